@@ -1,6 +1,9 @@
 package v1
 
 import (
+	"fmt"
+	"strings"
+
 	batchv1 "k8s.io/api/batch/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -36,6 +39,22 @@ type EnvironmentSpec struct {
 	TeamSettings      TeamSettings          `json:"teamSettings,omitempty" protobuf:"bytes,9,opt,name=teamSettings"`
 	PreviewGitSpec    PreviewGitSpec        `json:"previewGitInfo,omitempty" protobuf:"bytes,10,opt,name=previewGitInfo"`
 	WebHookEngine     WebHookEngineType     `json:"webHookEngine,omitempty" protobuf:"bytes,11,opt,name=webHookEngine"`
+	RemoteCluster     *EnvironmentCluster   `json:"remoteCluster,omitempty" protobuf:"bytes,12,opt,name=remoteCluster"`
+	// IstioInjection whether the pods of the namespace of the environment get Istio sidecars, overriding the
+	// IstioInjection team setting when set
+	IstioInjection *bool `json:"istioInjection,omitempty" protobuf:"bytes,13,opt,name=istioInjection"`
+}
+
+// EnvironmentCluster how to connect to the cluster an environment runs in when it is not the cluster of the team
+type EnvironmentCluster struct {
+	// URL the URL of the API server of the remote cluster
+	URL string `json:"url,omitempty" protobuf:"bytes,1,opt,name=url"`
+	// CAData the base64 encoded PEM certificate authority of the API server
+	CAData string `json:"caData,omitempty" protobuf:"bytes,2,opt,name=caData"`
+	// SecretName the Secret in the team namespace with either a 'kubeconfig' or a 'token' to connect with
+	SecretName string `json:"secretName,omitempty" protobuf:"bytes,3,opt,name=secretName"`
+	// Context the kubeconfig context used to connect to the remote cluster
+	Context string `json:"context,omitempty" protobuf:"bytes,4,opt,name=context"`
 }
 
 // EnvironmentStatus is the status for an Environment resource
@@ -63,6 +82,9 @@ const (
 	PromotionStrategyTypeAutomatic PromotionStrategyType = "Auto"
 	// PromotionStrategyTypeNever specifies that promotion is disabled for this environment
 	PromotionStrategyTypeNever PromotionStrategyType = "Never"
+	// PromotionStrategyTypeCanary specifies that promotion happens manually and is rolled out gradually as a Flagger
+	// canary which must pass its analysis for the promotion to succeed
+	PromotionStrategyTypeCanary PromotionStrategyType = "Canary"
 )
 
 // EnvironmentKindType is the kind of an environment
@@ -89,6 +111,9 @@ const (
 	PromotionEngineProw    PromotionEngineType = "Prow"
 )
 
+// DefaultPromotionApprovalComment the default comment on a promotion Pull Request which approves it
+const DefaultPromotionApprovalComment = "/approve promotion"
+
 // WebHookEngineType is the type of webhook processing implementation the team uses
 type WebHookEngineType string
 
@@ -113,6 +138,20 @@ var PromotionStrategyTypeValues = []string{
 	string(PromotionStrategyTypeAutomatic),
 	string(PromotionStrategyTypeManual),
 	string(PromotionStrategyTypeNever),
+	string(PromotionStrategyTypeCanary),
+}
+
+// ParsePromotionStrategyType returns the promotion strategy for the text ignoring case
+func ParsePromotionStrategyType(text string) (PromotionStrategyType, error) {
+	if text == "" {
+		return "", nil
+	}
+	for _, value := range PromotionStrategyTypeValues {
+		if strings.EqualFold(value, text) {
+			return PromotionStrategyType(value), nil
+		}
+	}
+	return "", fmt.Errorf("invalid promotion strategy %s. Valid values are: %s", text, strings.Join(PromotionStrategyTypeValues, ", "))
 }
 
 // EnvironmentRepositoryType is the repository type
@@ -150,6 +189,215 @@ type TeamSettings struct {
 	DockerRegistryOrg   string               `json:"dockerRegistryOrg,omitempty" protobuf:"bytes,16,opt,name=dockerRegistryOrg" command:"dockerregistryorg" commandUsage:"Docker registry organisation used for new projects in Jenkins X."`
 	GitPrivate          bool                 `json:"gitPrivate,omitempty" protobuf:"bytes,17,opt,name=gitPrivate" command:"gitprivate" commandUsage:"Are new repositories private by default"`
 	KubeProvider        string               `json:"kubeProvider,omitempty" protobuf:"bytes,18,opt,name=kubeProvider"`
+	StorageLocations    []StorageLocation    `json:"storageLocations,omitempty" protobuf:"bytes,19,opt,name=storageLocations"`
+	NamespaceScoped     bool                 `json:"namespaceScoped,omitempty" protobuf:"bytes,20,opt,name=namespaceScoped"`
+	SecretsLocation     SecretsLocation      `json:"secretsLocation,omitempty" protobuf:"bytes,21,opt,name=secretsLocation"`
+	PreviewTTLHours     int                  `json:"previewTTLHours,omitempty" protobuf:"bytes,22,opt,name=previewTTLHours" command:"previewttlhours" commandUsage:"Hours without activity after which preview environments are garbage collected. Use 0 to only collect previews of closed pull requests"`
+	PromotionApprovers  []PromotionApprovers `json:"promotionApprovers,omitempty" protobuf:"bytes,23,opt,name=promotionApprovers"`
+	// PromotionApprovalComment the comment on a promotion Pull Request which approves it
+	PromotionApprovalComment string `json:"promotionApprovalComment,omitempty" protobuf:"bytes,24,opt,name=promotionApprovalComment" command:"promotionapprovalcomment" commandUsage:"The comment an approver adds to a promotion Pull Request to approve it"`
+	// PreviewCommentTemplate the Go template of the comment which jx preview keeps up to date on the Pull Request
+	PreviewCommentTemplate string `json:"previewCommentTemplate,omitempty" protobuf:"bytes,25,opt,name=previewCommentTemplate" command:"previewcommenttemplate" commandUsage:"The Go template of the comment jx preview adds to the Pull Request"`
+	// Canary the analysis of the Flagger canaries of environments with the Canary promotion strategy
+	Canary CanarySettings `json:"canary,omitempty" protobuf:"bytes,26,opt,name=canary"`
+	// GitProtocol the protocol of the git remotes of new repositories, ssh or https. The provider APIs are always used
+	// over https
+	GitProtocol string `json:"gitProtocol,omitempty" protobuf:"bytes,27,opt,name=gitProtocol" command:"gitprotocol" commandUsage:"The protocol of the git remotes of new repositories: https or ssh"`
+	// GitSSHKeySecret the Secret containing the SSH deploy key which the pipelines clone and push with over ssh
+	GitSSHKeySecret string `json:"gitSshKeySecret,omitempty" protobuf:"bytes,28,opt,name=gitSshKeySecret" command:"gitsshkeysecret" commandUsage:"The Secret with the ssh-privatekey and known_hosts keys the pipelines use for ssh git remotes"`
+	// GitSigningKeySecret the Secret containing the GPG or SSH key which the pipelines sign their git commits and tags
+	// with, created by jx create gpg key
+	GitSigningKeySecret string `json:"gitSigningKeySecret,omitempty" protobuf:"bytes,29,opt,name=gitSigningKeySecret" command:"gitsigningkeysecret" commandUsage:"The Secret with the GPG or SSH key the pipelines sign their git commits and tags with"`
+	// DevPodImages the images the DevPods of the team can be created with in addition to the pod templates
+	DevPodImages []DevPodImage `json:"devPodImages,omitempty" protobuf:"bytes,30,opt,name=devPodImages"`
+	// DevPodIdleTimeoutMinutes the minutes without shell or sync activity after which jx gc devpods collects a DevPod
+	DevPodIdleTimeoutMinutes int `json:"devpodIdleTimeoutMinutes,omitempty" protobuf:"bytes,31,opt,name=devpodIdleTimeoutMinutes" command:"devpodidletimeoutminutes" commandUsage:"Minutes without activity after which DevPods are garbage collected. Use 0 to keep idle DevPods"`
+	// IstioInjection whether the namespaces of new staging, production and preview environments are labelled for
+	// Istio sidecar injection. Sidecars intercept the webhook callbacks of previews so it is disabled by default
+	IstioInjection bool `json:"istioInjection,omitempty" protobuf:"bytes,32,opt,name=istioInjection" command:"istioinjection" commandUsage:"Enable Istio sidecar injection in the namespaces of new environments and previews"`
+	// ImageScanFailOn the lowest severity of the vulnerabilities found by jx step scan image which fail the pipeline,
+	// defaults to critical
+	ImageScanFailOn string `json:"imageScanFailOn,omitempty" protobuf:"bytes,33,opt,name=imageScanFailOn" command:"imagescanfailon" commandUsage:"The lowest severity of the image vulnerabilities which fail the pipeline: unknown, low, medium, high or critical"`
+	// DockerRegistryInsecure whether the docker registry of the team is accessed without verifying its TLS
+	// certificate, such as when jx step scan image pulls the images of the pipelines
+	DockerRegistryInsecure bool `json:"dockerRegistryInsecure,omitempty" protobuf:"bytes,34,opt,name=dockerRegistryInsecure" command:"dockerregistryinsecure" commandUsage:"Access the docker registry of the team without verifying its TLS certificate"`
+}
+
+// DevPodImage an additional image which DevPods can be created with, configured via jx edit devpodimages
+type DevPodImage struct {
+	// Name the name of the image which is picked as the label of jx create devpod
+	Name string `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
+	// Image the image reference of the first container of the DevPod
+	Image string `json:"image,omitempty" protobuf:"bytes,2,opt,name=image"`
+	// PodTemplate the label of the pod template the DevPod is based on, a pod with a single container is used when it
+	// is empty
+	PodTemplate string `json:"podTemplate,omitempty" protobuf:"bytes,3,opt,name=podTemplate"`
+	// RequestCPU overrides the CPU request of the container
+	RequestCPU string `json:"requestCpu,omitempty" protobuf:"bytes,4,opt,name=requestCpu"`
+	// RequestMemory overrides the memory request of the container
+	RequestMemory string `json:"requestMemory,omitempty" protobuf:"bytes,5,opt,name=requestMemory"`
+	// Env the environment variables added to the container
+	Env map[string]string `json:"env,omitempty" protobuf:"bytes,6,rep,name=env"`
+}
+
+// CanarySettings the settings of the Flagger canary analysis of applications promoted to environments with the Canary
+// promotion strategy. Zero values use the defaults
+type CanarySettings struct {
+	// Interval the duration between the steps of the analysis such as 1m
+	Interval string `json:"interval,omitempty" protobuf:"bytes,1,opt,name=interval"`
+	// Threshold the number of failed checks after which the canary is rolled back
+	Threshold int `json:"threshold,omitempty" protobuf:"bytes,2,opt,name=threshold"`
+	// MaxWeight the maximum percentage of traffic routed to the canary
+	MaxWeight int `json:"maxWeight,omitempty" protobuf:"bytes,3,opt,name=maxWeight"`
+	// StepWeight the percentage of traffic added to the canary at each step
+	StepWeight int `json:"stepWeight,omitempty" protobuf:"bytes,4,opt,name=stepWeight"`
+	// RequestSuccessRate the minimum percentage of successful requests
+	RequestSuccessRate int `json:"requestSuccessRate,omitempty" protobuf:"bytes,5,opt,name=requestSuccessRate"`
+	// RequestDuration the maximum 99th percentile request duration in milliseconds
+	RequestDuration int `json:"requestDuration,omitempty" protobuf:"bytes,6,opt,name=requestDuration"`
+	// Port the container port of the application
+	Port int `json:"port,omitempty" protobuf:"bytes,7,opt,name=port"`
+}
+
+// PromotionApprovers the users who approve the promotion Pull Requests of an environment before they are merged
+type PromotionApprovers struct {
+	Environment string   `json:"environment,omitempty" protobuf:"bytes,1,opt,name=environment"`
+	Users       []string `json:"users,omitempty" protobuf:"bytes,2,opt,name=users"`
+}
+
+// SecretsLocation the external Vault which stores the secrets of the team
+type SecretsLocation struct {
+	// VaultURL the URL of the Vault, the secrets are stored in Kubernetes secrets when it is empty
+	VaultURL string `json:"vaultUrl,omitempty" protobuf:"bytes,1,opt,name=vaultUrl"`
+	// VaultAuthPath the mount path of the Kubernetes auth method of the Vault
+	VaultAuthPath string `json:"vaultAuthPath,omitempty" protobuf:"bytes,2,opt,name=vaultAuthPath"`
+	// VaultRole the role of the Kubernetes auth method which the build service account logs in with
+	VaultRole string `json:"vaultRole,omitempty" protobuf:"bytes,3,opt,name=vaultRole"`
+	// VaultSecretPrefix the path under which the secrets of the team are stored
+	VaultSecretPrefix string `json:"vaultSecretPrefix,omitempty" protobuf:"bytes,4,opt,name=vaultSecretPrefix"`
+}
+
+// StorageLocation the bucket used for the long term storage of a kind of content such as logs or reports
+type StorageLocation struct {
+	Classifier string `json:"classifier,omitempty" protobuf:"bytes,1,opt,name=classifier"`
+	BucketURL  string `json:"bucketUrl,omitempty" protobuf:"bytes,2,opt,name=bucketUrl"`
+	// Managed is true if jx created the bucket so that jx uninstall can delete it
+	Managed bool `json:"managed,omitempty" protobuf:"bytes,3,opt,name=managed"`
+}
+
+// StorageLocation returns the storage location for the given classifier or nil if there is none
+func (t *TeamSettings) StorageLocation(classifier string) *StorageLocation {
+	for i := range t.StorageLocations {
+		if t.StorageLocations[i].Classifier == classifier {
+			return &t.StorageLocations[i]
+		}
+	}
+	return nil
+}
+
+// SetStorageLocation sets the bucket URL of the storage location for the given classifier and whether jx created it
+func (t *TeamSettings) SetStorageLocation(classifier string, bucketURL string, managed bool) {
+	location := t.StorageLocation(classifier)
+	if location != nil {
+		location.BucketURL = bucketURL
+		location.Managed = managed
+		return
+	}
+	t.StorageLocations = append(t.StorageLocations, StorageLocation{Classifier: classifier, BucketURL: bucketURL, Managed: managed})
+}
+
+// DevPodImage returns the additional DevPod image with the given name or nil if there is none
+func (t *TeamSettings) DevPodImage(name string) *DevPodImage {
+	for i := range t.DevPodImages {
+		if t.DevPodImages[i].Name == name {
+			return &t.DevPodImages[i]
+		}
+	}
+	return nil
+}
+
+// SetDevPodImage adds the DevPod image or replaces the image with the same name
+func (t *TeamSettings) SetDevPodImage(image DevPodImage) {
+	existing := t.DevPodImage(image.Name)
+	if existing != nil {
+		*existing = image
+		return
+	}
+	t.DevPodImages = append(t.DevPodImages, image)
+}
+
+// RemoveDevPodImage removes the DevPod image with the given name returning false if there is none
+func (t *TeamSettings) RemoveDevPodImage(name string) bool {
+	answer := []DevPodImage{}
+	for _, image := range t.DevPodImages {
+		if image.Name != name {
+			answer = append(answer, image)
+		}
+	}
+	removed := len(answer) != len(t.DevPodImages)
+	t.DevPodImages = answer
+	return removed
+}
+
+// EnvironmentPromotionApprovers returns the users who approve promotions to the given environment or nil if promotions
+// to it do not need approval
+func (t *TeamSettings) EnvironmentPromotionApprovers(environment string) []string {
+	for _, approvers := range t.PromotionApprovers {
+		if approvers.Environment == environment {
+			return approvers.Users
+		}
+	}
+	return nil
+}
+
+// SetEnvironmentPromotionApprovers sets the users who approve promotions to the given environment, removing the
+// approvers of the environment if there are no users
+func (t *TeamSettings) SetEnvironmentPromotionApprovers(environment string, users []string) {
+	answer := []PromotionApprovers{}
+	for _, approvers := range t.PromotionApprovers {
+		if approvers.Environment != environment {
+			answer = append(answer, approvers)
+		}
+	}
+	if len(users) > 0 {
+		answer = append(answer, PromotionApprovers{Environment: environment, Users: users})
+	}
+	t.PromotionApprovers = answer
+}
+
+// GetPromotionApprovalComment returns the comment which approves a promotion Pull Request
+func (t *TeamSettings) GetPromotionApprovalComment() string {
+	if t.PromotionApprovalComment == "" {
+		return DefaultPromotionApprovalComment
+	}
+	return t.PromotionApprovalComment
+}
+
+// GetCanarySettings returns the canary analysis settings of the team with the defaults for any which are not set
+func (t *TeamSettings) GetCanarySettings() CanarySettings {
+	answer := t.Canary
+	if answer.Interval == "" {
+		answer.Interval = "1m"
+	}
+	if answer.Threshold <= 0 {
+		answer.Threshold = 5
+	}
+	if answer.MaxWeight <= 0 {
+		answer.MaxWeight = 50
+	}
+	if answer.StepWeight <= 0 {
+		answer.StepWeight = 10
+	}
+	if answer.RequestSuccessRate <= 0 {
+		answer.RequestSuccessRate = 99
+	}
+	if answer.RequestDuration <= 0 {
+		answer.RequestDuration = 500
+	}
+	if answer.Port <= 0 {
+		answer.Port = 8080
+	}
+	return answer
 }
 
 // QuickStartLocation
@@ -172,6 +420,8 @@ type PreviewGitSpec struct {
 	BuildStatusURL  string   `json:"buildStatusUrl,omitempty" protobuf:"bytes,7,opt,name=buildStatusUrl"`
 	ApplicationName string   `json:"appName,omitempty" protobuf:"bytes,8,opt,name=appName"`
 	ApplicationURL  string   `json:"applicationURL,omitempty" protobuf:"bytes,9,opt,name=applicationURL"`
+	// HelmValues the key=value overrides of the preview chart values which are reapplied on each deployment
+	HelmValues []string `json:"helmValues,omitempty" protobuf:"bytes,10,opt,name=helmValues"`
 }
 
 // UserSpec is the user details
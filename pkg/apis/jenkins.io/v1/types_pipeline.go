@@ -51,10 +51,12 @@ type PipelineActivitySpec struct {
 
 // PipelineActivityStep represents a step in a pipeline activity
 type PipelineActivityStep struct {
-	Kind    ActivityStepKindType `json:"kind,omitempty" protobuf:"bytes,1,opt,name=kind"`
-	Stage   *StageActivityStep   `json:"stage,omitempty" protobuf:"bytes,2,opt,name=stage"`
-	Promote *PromoteActivityStep `json:"promote,omitempty" protobuf:"bytes,3,opt,name=promote"`
-	Preview *PreviewActivityStep `json:"preview,omitempty" protobuf:"bytes,4,opt,name=preview"`
+	Kind     ActivityStepKindType  `json:"kind,omitempty" protobuf:"bytes,1,opt,name=kind"`
+	Stage    *StageActivityStep    `json:"stage,omitempty" protobuf:"bytes,2,opt,name=stage"`
+	Promote  *PromoteActivityStep  `json:"promote,omitempty" protobuf:"bytes,3,opt,name=promote"`
+	Preview  *PreviewActivityStep  `json:"preview,omitempty" protobuf:"bytes,4,opt,name=preview"`
+	Rollback *RollbackActivityStep `json:"rollback,omitempty" protobuf:"bytes,5,opt,name=rollback"`
+	Scan     *ScanActivityStep     `json:"scan,omitempty" protobuf:"bytes,6,opt,name=scan"`
 }
 
 // CoreActivityStep is a base step included in Stages of a pipeline or other kinds of step
@@ -92,6 +94,30 @@ type PromoteActivityStep struct {
 	ApplicationURL string                  `json:"applicationURL,omitempty" protobuf:"bytes,4,opt,name=environment"`
 }
 
+// RollbackActivityStep is the step of reverting an application in an environment to a previous version
+type RollbackActivityStep struct {
+	CoreActivityStep
+
+	Environment    string `json:"environment,omitempty" protobuf:"bytes,1,opt,name=environment"`
+	FromVersion    string `json:"fromVersion,omitempty" protobuf:"bytes,2,opt,name=fromVersion"`
+	ToVersion      string `json:"toVersion,omitempty" protobuf:"bytes,3,opt,name=toVersion"`
+	PullRequestURL string `json:"pullRequestURL,omitempty" protobuf:"bytes,4,opt,name=pullRequestURL"`
+	CommitSHA      string `json:"commitSHA,omitempty" protobuf:"bytes,5,opt,name=commitSHA"`
+}
+
+// ScanActivityStep is the step of scanning an image built by the pipeline for vulnerabilities
+type ScanActivityStep struct {
+	CoreActivityStep
+
+	Image string `json:"image,omitempty" protobuf:"bytes,1,opt,name=image"`
+	// FailOn the lowest severity of the vulnerabilities which fail the scan
+	FailOn string `json:"failOn,omitempty" protobuf:"bytes,2,opt,name=failOn"`
+	// Vulnerabilities the number of vulnerabilities found for each severity
+	Vulnerabilities map[string]int `json:"vulnerabilities,omitempty" protobuf:"bytes,3,rep,name=vulnerabilities"`
+	// ReportURL the URL of the full report in the long term storage of the logs
+	ReportURL string `json:"reportURL,omitempty" protobuf:"bytes,4,opt,name=reportURL"`
+}
+
 // GitStatus the status of a git commit in terms of CI/CD
 type GitStatus struct {
 	URL    string `json:"url,omitempty" protobuf:"bytes,1,opt,name=url"`
@@ -105,6 +131,9 @@ type PromotePullRequestStep struct {
 
 	PullRequestURL string `json:"pullRequestURL,omitempty" protobuf:"bytes,1,opt,name=pullRequestURL"`
 	MergeCommitSHA string `json:"mergeCommitSHA,omitempty" protobuf:"bytes,2,opt,name=mergeCommitSHA"`
+	// ApprovedBy the user who approved the promotion Pull Request of an environment which requires approval
+	ApprovedBy        string       `json:"approvedBy,omitempty" protobuf:"bytes,3,opt,name=approvedBy"`
+	ApprovedTimestamp *metav1.Time `json:"approvedTimestamp,omitempty" protobuf:"bytes,4,opt,name=approvedTimestamp"`
 }
 
 // PromoteUpdateStep is the step for updating a promotion after the Pull Request merges to master
@@ -141,6 +170,10 @@ const (
 	ActivityStepKindTypePreview ActivityStepKindType = "Preview"
 	// ActivityStepKindTypePromote a promote activity
 	ActivityStepKindTypePromote ActivityStepKindType = "Promote"
+	// ActivityStepKindTypeRollback a rollback activity
+	ActivityStepKindTypeRollback ActivityStepKindType = "Rollback"
+	// ActivityStepKindTypeScan a vulnerability scan of an image
+	ActivityStepKindTypeScan ActivityStepKindType = "Scan"
 )
 
 // ActivityStatusType is the status of an activity; usually succeeded or failed/error on completion
@@ -31,6 +31,22 @@ func (in *Attachment) DeepCopy() *Attachment {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanarySettings) DeepCopyInto(out *CanarySettings) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanarySettings.
+func (in *CanarySettings) DeepCopy() *CanarySettings {
+	if in == nil {
+		return nil
+	}
+	out := new(CanarySettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CodeCoverageAnalysis) DeepCopyInto(out *CodeCoverageAnalysis) {
 	*out = *in
@@ -131,6 +147,29 @@ func (in *CoreActivityStep) DeepCopy() *CoreActivityStep {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevPodImage) DeepCopyInto(out *DevPodImage) {
+	*out = *in
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevPodImage.
+func (in *DevPodImage) DeepCopy() *DevPodImage {
+	if in == nil {
+		return nil
+	}
+	out := new(DevPodImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Environment) DeepCopyInto(out *Environment) {
 	*out = *in
@@ -185,6 +224,22 @@ func (in *EnvironmentFilter) DeepCopy() *EnvironmentFilter {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvironmentCluster) DeepCopyInto(out *EnvironmentCluster) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentCluster.
+func (in *EnvironmentCluster) DeepCopy() *EnvironmentCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvironmentCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnvironmentList) DeepCopyInto(out *EnvironmentList) {
 	*out = *in
@@ -345,7 +400,17 @@ func (in *EnvironmentSpec) DeepCopyInto(out *EnvironmentSpec) {
 	*out = *in
 	out.Source = in.Source
 	in.TeamSettings.DeepCopyInto(&out.TeamSettings)
-	out.PreviewGitSpec = in.PreviewGitSpec
+	in.PreviewGitSpec.DeepCopyInto(&out.PreviewGitSpec)
+	if in.RemoteCluster != nil {
+		in, out := &in.RemoteCluster, &out.RemoteCluster
+		*out = new(EnvironmentCluster)
+		**out = **in
+	}
+	if in.IstioInjection != nil {
+		in, out := &in.IstioInjection, &out.IstioInjection
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -1021,6 +1086,16 @@ func (in *PipelineActivityStep) DeepCopyInto(out *PipelineActivityStep) {
 		*out = new(PreviewActivityStep)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Rollback != nil {
+		in, out := &in.Rollback, &out.Rollback
+		*out = new(RollbackActivityStep)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scan != nil {
+		in, out := &in.Scan, &out.Scan
+		*out = new(ScanActivityStep)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1055,6 +1130,11 @@ func (in *PreviewActivityStep) DeepCopy() *PreviewActivityStep {
 func (in *PreviewGitSpec) DeepCopyInto(out *PreviewGitSpec) {
 	*out = *in
 	out.User = in.User
+	if in.HelmValues != nil {
+		in, out := &in.HelmValues, &out.HelmValues
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -1099,6 +1179,10 @@ func (in *PromoteActivityStep) DeepCopy() *PromoteActivityStep {
 func (in *PromotePullRequestStep) DeepCopyInto(out *PromotePullRequestStep) {
 	*out = *in
 	in.CoreActivityStep.DeepCopyInto(&out.CoreActivityStep)
+	if in.ApprovedTimestamp != nil {
+		in, out := &in.ApprovedTimestamp, &out.ApprovedTimestamp
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
@@ -1150,6 +1234,27 @@ func (in *PromoteWorkflowStep) DeepCopy() *PromoteWorkflowStep {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionApprovers) DeepCopyInto(out *PromotionApprovers) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionApprovers.
+func (in *PromotionApprovers) DeepCopy() *PromotionApprovers {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionApprovers)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *QuickStartLocation) DeepCopyInto(out *QuickStartLocation) {
 	*out = *in
@@ -1290,6 +1395,63 @@ func (in *ReleaseStatus) DeepCopy() *ReleaseStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollbackActivityStep) DeepCopyInto(out *RollbackActivityStep) {
+	*out = *in
+	in.CoreActivityStep.DeepCopyInto(&out.CoreActivityStep)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollbackActivityStep.
+func (in *RollbackActivityStep) DeepCopy() *RollbackActivityStep {
+	if in == nil {
+		return nil
+	}
+	out := new(RollbackActivityStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScanActivityStep) DeepCopyInto(out *ScanActivityStep) {
+	*out = *in
+	in.CoreActivityStep.DeepCopyInto(&out.CoreActivityStep)
+	if in.Vulnerabilities != nil {
+		in, out := &in.Vulnerabilities, &out.Vulnerabilities
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScanActivityStep.
+func (in *ScanActivityStep) DeepCopy() *ScanActivityStep {
+	if in == nil {
+		return nil
+	}
+	out := new(ScanActivityStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretsLocation) DeepCopyInto(out *SecretsLocation) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretsLocation.
+func (in *SecretsLocation) DeepCopy() *SecretsLocation {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretsLocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StageActivityStep) DeepCopyInto(out *StageActivityStep) {
 	*out = *in
@@ -1359,6 +1521,22 @@ func (in *StaticProgramAnalysisCategory) DeepCopy() *StaticProgramAnalysisCatego
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageLocation) DeepCopyInto(out *StorageLocation) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageLocation.
+func (in *StorageLocation) DeepCopy() *StorageLocation {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageLocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Summaries) DeepCopyInto(out *Summaries) {
 	*out = *in
@@ -1455,6 +1633,27 @@ func (in *TeamSettings) DeepCopyInto(out *TeamSettings) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.StorageLocations != nil {
+		in, out := &in.StorageLocations, &out.StorageLocations
+		*out = make([]StorageLocation, len(*in))
+		copy(*out, *in)
+	}
+	out.SecretsLocation = in.SecretsLocation
+	if in.PromotionApprovers != nil {
+		in, out := &in.PromotionApprovers, &out.PromotionApprovers
+		*out = make([]PromotionApprovers, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.Canary = in.Canary
+	if in.DevPodImages != nil {
+		in, out := &in.DevPodImages, &out.DevPodImages
+		*out = make([]DevPodImage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
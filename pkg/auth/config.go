@@ -97,6 +97,42 @@ func urlsEqual(url1, url2 string) bool {
 	return url1 == url2 || strings.TrimSuffix(url1, "/") == strings.TrimSuffix(url2, "/")
 }
 
+// FindServerForGitURL returns the server which is not at the root of its host, such as https://example.com/gitea, that
+// the Git repository URL belongs to or nil if there is none. The path of such a server cannot be told apart from the
+// organisation of the repository by parsing the URL
+func (c *AuthConfig) FindServerForGitURL(gitURL string) *AuthServer {
+	repoURL, err := url.Parse(gitURL)
+	if err != nil || repoURL.Host == "" {
+		return nil
+	}
+	var answer *AuthServer
+	answerPath := ""
+	for _, s := range c.Servers {
+		serverURL, err := url.Parse(s.URL)
+		if err != nil || !sameGitHost(serverURL, repoURL) {
+			continue
+		}
+		serverPath := strings.Trim(serverURL.Path, "/")
+		if serverPath == "" || len(serverPath) <= len(answerPath) {
+			continue
+		}
+		if strings.HasPrefix(strings.TrimPrefix(repoURL.Path, "/"), serverPath+"/") {
+			answer = s
+			answerPath = serverPath
+		}
+	}
+	return answer
+}
+
+// sameGitHost returns true if the repository URL is on the host of the server URL. The port of ssh:// repository URLs
+// is the port of the ssh server rather than of the git server so it is ignored
+func sameGitHost(serverURL *url.URL, repoURL *url.URL) bool {
+	if repoURL.Scheme == "ssh" {
+		return serverURL.Hostname() == repoURL.Hostname()
+	}
+	return serverURL.Host == repoURL.Host
+}
+
 // GetServerByName returns the server for the given URL or null if its not found
 func (c *AuthConfig) GetServer(url string) *AuthServer {
 	for _, s := range c.Servers {
@@ -172,3 +172,27 @@ func TestDeleteServer2(t *testing.T) {
 	assert.Equal(t, url1, c.Servers[0].URL, "Failed to remove the right server from the configuration")
 	assert.Equal(t, url1, c.CurrentServer, "Server 1 should be current server")
 }
+
+func TestFindServerForGitURL(t *testing.T) {
+	t.Parallel()
+	c := &auth.AuthConfig{}
+	c.GetOrCreateServer("https://example.com")
+	c.GetOrCreateServer("https://example.com/gitea")
+	c.GetOrCreateServer("https://example.com/gitea/nested")
+
+	server := c.FindServerForGitURL("https://example.com/gitea/myorg/myrepo.git")
+	assert.NotNil(t, server, "No server found for a repository under the path of a server")
+	assert.Equal(t, "https://example.com/gitea", server.URL)
+
+	server = c.FindServerForGitURL("https://user@example.com/gitea/nested/myorg/myrepo")
+	assert.NotNil(t, server, "No server found for a repository under the path of a nested server")
+	assert.Equal(t, "https://example.com/gitea/nested", server.URL)
+
+	assert.Nil(t, c.FindServerForGitURL("https://example.com/myorg/myrepo"), "Servers at the root of their host should not be found")
+	assert.Nil(t, c.FindServerForGitURL("https://other.com/gitea/myorg/myrepo"), "Servers of other hosts should not be found")
+	assert.Nil(t, c.FindServerForGitURL("git@example.com:gitea/myorg/myrepo.git"), "git@ URLs should not be found")
+
+	server = c.FindServerForGitURL("ssh://git@example.com:2222/gitea/myorg/myrepo.git")
+	assert.NotNil(t, server, "No server found for an ssh repository on another port of the host of a server")
+	assert.Equal(t, "https://example.com/gitea", server.URL)
+}
@@ -10,6 +10,12 @@ type AuthServer struct {
 	Name  string
 	Kind  string
 
+	// APIURL the URL of the REST API if it is not derived from the server URL, e.g. when it is on a separate host
+	APIURL string `yaml:"apiUrl,omitempty"`
+
+	// OAuthClientID the client ID of the OAuth App used to login via the device flow rather than pasting an API token
+	OAuthClientID string `yaml:"oauthClientId,omitempty"`
+
 	CurrentUser string
 }
 
@@ -10,9 +10,23 @@ import (
 	"github.com/pkg/errors"
 )
 
-const EksctlVersion = "0.1.3"
+const EksctlVersion = "0.12.0"
 
-const HeptioAuthenticatorAwsVersion = "1.10.3"
+const AwsIamAuthenticatorVersion = "1.12.7"
+
+const KindVersion = "0.6.1"
+
+const K3dVersion = "3.0.0"
+
+const DoctlVersion = "1.36.0"
+
+const Helm3Version = "3.0.2"
+
+const IstioctlVersion = "1.7.3"
+
+const TrivyVersion = "0.18.3"
+
+const IBMCloudVersion = "1.2.3"
 
 func BinaryWithExtension(binary string) string {
 	if runtime.GOOS == "windows" {
@@ -59,6 +73,7 @@ func ShouldInstallBinary(binary string, expectedVersion string, versionExtractor
 }
 
 func DownloadFile(clientURL string, fullPath string) error {
+	clientURL = MirrorURL(clientURL)
 	log.Infof("Downloading %s to %s...\n", util.ColorInfo(clientURL), util.ColorInfo(fullPath))
 	err := util.DownloadFile(fullPath, clientURL)
 	if err != nil {
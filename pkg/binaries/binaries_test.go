@@ -33,3 +33,11 @@ func TestShouldInstall(t *testing.T) {
 	assert.True(t, shouldInstall)
 	assert.Nil(t, err)
 }
+
+func TestMirrorURLOf(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "https://nexus.example.com/binaries/github.com/kubernetes-sigs/kind/releases/download/v0.6.1/kind-linux-amd64",
+		MirrorURLOf("https://nexus.example.com/binaries", "https://github.com/kubernetes-sigs/kind/releases/download/v0.6.1/kind-linux-amd64"))
+	assert.Equal(t, "https://get.helm.sh/helm-v3.0.2-linux-amd64.tar.gz",
+		MirrorURLOf("", "https://get.helm.sh/helm-v3.0.2-linux-amd64.tar.gz"))
+}
@@ -0,0 +1,112 @@
+package binaries
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// ParseChecksum returns the SHA256 checksum of the given file name from the contents of a checksums file. Both the
+// sha256sum format of `<checksum>  <file name>` lines and files containing just the checksum of a single artifact
+// are supported
+func ParseChecksum(data []byte, fileName string) (string, error) {
+	lines := []string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 1 && len(lines) == 1 {
+			return strings.ToLower(fields[0]), nil
+		}
+		if len(fields) == 2 && path.Base(strings.TrimPrefix(fields[1], "*")) == fileName {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("no checksum found for %s", fileName)
+}
+
+// FileSHA256 returns the hex encoded SHA256 checksum of the given file
+func FileSHA256(fileName string) (string, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	_, err = io.Copy(hash, f)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// VerifyChecksum verifies the SHA256 checksum of the given file, deleting the file if it does not match
+func VerifyChecksum(fileName string, expected string) error {
+	actual, err := FileSHA256(fileName)
+	if err != nil {
+		return err
+	}
+	if actual != strings.ToLower(expected) {
+		err = os.Remove(fileName)
+		if err != nil {
+			log.Warnf("Failed to remove %s: %s\n", fileName, err)
+		}
+		return fmt.Errorf("checksum mismatch for %s: expected %s but got %s", fileName, expected, actual)
+	}
+	return nil
+}
+
+// DownloadChecksum downloads the checksums file from the given URL returning the checksum of the given file name
+func DownloadChecksum(checksumURL string, fileName string) (string, error) {
+	checksumURL = MirrorURL(checksumURL)
+	client, err := util.DownloadClient()
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Get(checksumURL)
+	if err != nil {
+		return "", fmt.Errorf("Unable to download checksums from %s due to: %v", checksumURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Unable to download checksums from %s due to status %s", checksumURL, resp.Status)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	checksum, err := ParseChecksum(data, fileName)
+	if err != nil {
+		return "", fmt.Errorf("%s in %s", err, checksumURL)
+	}
+	return checksum, nil
+}
+
+// VerifyDownload verifies the file downloaded from clientURL against the checksums published at checksumURL,
+// deleting the file if the checksums cannot be downloaded or do not match
+func VerifyDownload(clientURL string, checksumURL string, fullPath string) error {
+	checksum, err := DownloadChecksum(checksumURL, path.Base(clientURL))
+	if err != nil {
+		os.Remove(fullPath)
+		return err
+	}
+	err = VerifyChecksum(fullPath, checksum)
+	if err != nil {
+		return err
+	}
+	log.Infof("Verified the checksum of %s\n", util.ColorInfo(fullPath))
+	return nil
+}
@@ -0,0 +1,82 @@
+package binaries
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fixtureChecksum = "2a491e5215af1ff9137746ff51d60b8839b88aac53da1f04e490ec74022e67f6"
+
+func TestParseChecksum(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("test_data", "eksctl_checksums.txt"))
+	require.NoError(t, err)
+
+	checksum, err := ParseChecksum(data, "eksctl_linux_amd64.tar.gz")
+	assert.NoError(t, err)
+	assert.Equal(t, fixtureChecksum, checksum)
+
+	_, err = ParseChecksum(data, "eksctl_freebsd_amd64.tar.gz")
+	assert.Error(t, err)
+
+	data, err = ioutil.ReadFile(filepath.Join("test_data", "eksctl_linux_amd64.tar.gz.sha256"))
+	require.NoError(t, err)
+	checksum, err = ParseChecksum(data, "eksctl_linux_amd64.tar.gz")
+	assert.NoError(t, err)
+	assert.Equal(t, fixtureChecksum, checksum)
+
+	checksum, err = ParseChecksum([]byte("ABCDEF  *bin/kubectl\n"), "kubectl")
+	assert.NoError(t, err)
+	assert.Equal(t, "abcdef", checksum)
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	fileName := copyFixture(t, "eksctl_linux_amd64.tar.gz")
+	defer os.RemoveAll(filepath.Dir(fileName))
+
+	err := VerifyChecksum(fileName, fixtureChecksum)
+	assert.NoError(t, err)
+	assert.FileExists(t, fileName)
+
+	err = VerifyChecksum(fileName, "0000000000000000000000000000000000000000000000000000000000000000")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "checksum mismatch")
+	_, err = os.Stat(fileName)
+	assert.True(t, os.IsNotExist(err), "the file should be deleted on a checksum mismatch")
+}
+
+func TestVerifyDownload(t *testing.T) {
+	server := httptest.NewServer(http.FileServer(http.Dir("test_data")))
+	defer server.Close()
+	clientURL := server.URL + "/eksctl_linux_amd64.tar.gz"
+
+	fileName := copyFixture(t, "eksctl_linux_amd64.tar.gz")
+	defer os.RemoveAll(filepath.Dir(fileName))
+	err := VerifyDownload(clientURL, server.URL+"/eksctl_checksums.txt", fileName)
+	assert.NoError(t, err)
+	err = VerifyDownload(clientURL, clientURL+".sha256", fileName)
+	assert.NoError(t, err)
+	assert.FileExists(t, fileName)
+
+	err = VerifyDownload(clientURL, server.URL+"/missing_checksums.txt", fileName)
+	assert.Error(t, err)
+	_, err = os.Stat(fileName)
+	assert.True(t, os.IsNotExist(err), "the file should be deleted when the checksums cannot be downloaded")
+}
+
+func copyFixture(t *testing.T, name string) string {
+	dir, err := ioutil.TempDir("", "jx-checksums-")
+	require.NoError(t, err)
+	data, err := ioutil.ReadFile(filepath.Join("test_data", name))
+	require.NoError(t, err)
+	fileName := filepath.Join(dir, name)
+	err = ioutil.WriteFile(fileName, data, 0644)
+	require.NoError(t, err)
+	return fileName
+}
@@ -0,0 +1,40 @@
+package binaries
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+)
+
+var (
+	mirrorURL     string
+	mirrorURLLock sync.Mutex
+)
+
+// SetMirrorURL sets the base URL of the mirror which serves the binary downloads of an air-gapped network. An empty
+// URL downloads the binaries from their upstream hosts
+func SetMirrorURL(baseURL string) {
+	mirrorURLLock.Lock()
+	defer mirrorURLLock.Unlock()
+	mirrorURL = strings.TrimSuffix(baseURL, "/")
+}
+
+// MirrorURL returns the URL of the download in the mirror, which serves each file under the host and path of its
+// upstream URL, or the URL itself if there is no mirror
+func MirrorURL(downloadURL string) string {
+	mirrorURLLock.Lock()
+	defer mirrorURLLock.Unlock()
+	return MirrorURLOf(mirrorURL, downloadURL)
+}
+
+// MirrorURLOf returns the URL of the download in the mirror with the given base URL
+func MirrorURLOf(baseURL string, downloadURL string) string {
+	if baseURL == "" {
+		return downloadURL
+	}
+	u, err := url.Parse(downloadURL)
+	if err != nil || u.Host == "" {
+		return downloadURL
+	}
+	return baseURL + "/" + u.Host + u.Path
+}
@@ -0,0 +1,52 @@
+package binaries
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/blang/semver"
+)
+
+var (
+	versionRegex = regexp.MustCompile(`v?(\d+\.\d+\.\d+)`)
+
+	// MinimumVersions the oldest versions of the external binaries jx shells out to which support all the flags
+	// jx uses. Older versions are upgraded into ~/.jx/bin
+	MinimumVersions = map[string]semver.Version{
+		"eksctl":                semver.MustParse("0.11.0"),
+		"aws-iam-authenticator": semver.MustParse("0.3.0"),
+		// older versions of kind do not merge the kube config of the cluster into ~/.kube/config
+		"kind": semver.MustParse("0.6.0"),
+		// older versions of k3d do not support the `k3d cluster create` command nor agents
+		"k3d": semver.MustParse("3.0.0"),
+	}
+)
+
+// ParseVersion parses the first semantic version in the output of a `<binary> version` command
+func ParseVersion(output string) (semver.Version, error) {
+	m := versionRegex.FindStringSubmatch(output)
+	if m == nil {
+		return semver.Version{}, fmt.Errorf("no version found in %s", strings.TrimSpace(output))
+	}
+	return semver.Parse(m[1])
+}
+
+// BinaryVersion runs `<binary> version` returning the parsed version
+func BinaryVersion(binaryPath string) (semver.Version, error) {
+	data, err := exec.Command(binaryPath, "version").CombinedOutput()
+	if err != nil {
+		return semver.Version{}, fmt.Errorf("failed to run %s version: %s", binaryPath, err)
+	}
+	return ParseVersion(string(data))
+}
+
+// CheckMinimumVersion returns an error if the version of the binary is older than the minimum version jx requires
+func CheckMinimumVersion(binary string, version semver.Version) error {
+	minimum, ok := MinimumVersions[binary]
+	if ok && version.LT(minimum) {
+		return fmt.Errorf("%s %s is older than the minimum version %s required by jx, please upgrade it or run: jx upgrade binaries", binary, version, minimum)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package binaries
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVersion(t *testing.T) {
+	outputs := map[string]string{
+		"0.12.0\n": "0.12.0",
+		`[ℹ]  version.Info{BuiltAt:"", GitCommit:"", GitTag:"0.1.3"}`:              "0.1.3",
+		`{"Version":"v0.4.0","Commit":"c141eda34ad1b6b4d71056810951801348f8c367"}`: "0.4.0",
+	}
+	for output, expected := range outputs {
+		version, err := ParseVersion(output)
+		assert.NoError(t, err, output)
+		assert.Equal(t, expected, version.String(), output)
+	}
+
+	_, err := ParseVersion("unknown command \"version\"")
+	assert.Error(t, err)
+}
+
+func TestCheckMinimumVersion(t *testing.T) {
+	err := CheckMinimumVersion("eksctl", semver.MustParse("0.1.3"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), MinimumVersions["eksctl"].String())
+
+	assert.NoError(t, CheckMinimumVersion("eksctl", MinimumVersions["eksctl"]))
+	assert.NoError(t, CheckMinimumVersion("eksctl", semver.MustParse("1.0.0")))
+	assert.NoError(t, CheckMinimumVersion("kubectl", semver.MustParse("0.0.1")))
+}
+
+func TestBinaryVersion(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a shell script")
+	}
+	dir, err := ioutil.TempDir("", "jx-versions-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	binary := filepath.Join(dir, "eksctl")
+	err = ioutil.WriteFile(binary, []byte("#!/bin/sh\necho 0.10.2\n"), 0755)
+	require.NoError(t, err)
+
+	version, err := BinaryVersion(binary)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.10.2", version.String())
+	assert.Error(t, CheckMinimumVersion("eksctl", version))
+}
@@ -0,0 +1,195 @@
+package builds
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/cloud/buckets"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// StepContainerPrefix the prefix of the names of the containers of the steps of a build pod
+	StepContainerPrefix = "build-step-"
+
+	// ArchivedLogsFolder the folder of the archived build logs in the logs storage location
+	ArchivedLogsFolder = "jenkins-x/logs"
+
+	// the container which knative build adds to a pod to keep it running once the steps have completed
+	nopContainerName = "nop"
+)
+
+var stageHeaderRegex = regexp.MustCompile(`^==== Stage: (.+) ====$`)
+
+// PipelineContainer a container of a pod of a pipeline whose log is the log of a stage
+type PipelineContainer struct {
+	Stage string
+	Pod   *corev1.Pod
+	Name  string
+	Init  bool
+}
+
+// StageLog the log of a stage of an archived build log
+type StageLog struct {
+	Stage string
+	Log   string
+}
+
+// StageName returns the name of the stage of the container of a build pod, e.g. "Git Source" for the container
+// "build-step-git-source"
+func StageName(containerName string) string {
+	name := strings.Replace(strings.TrimPrefix(containerName, StepContainerPrefix), "-", " ", -1)
+	return strings.Title(name)
+}
+
+// StageHeader returns the line which precedes the log of a stage
+func StageHeader(stage string) string {
+	return fmt.Sprintf("==== Stage: %s ====", stage)
+}
+
+// MatchesStage returns true if the filter is empty or matches the name of the stage or of its container ignoring the
+// case, the difference between spaces and dashes and the step prefix of the container
+func MatchesStage(name string, filter string) bool {
+	if filter == "" {
+		return true
+	}
+	return normalizeStageName(name) == normalizeStageName(filter)
+}
+
+func normalizeStageName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.Replace(name, " ", "-", -1)
+	return strings.TrimPrefix(name, StepContainerPrefix)
+}
+
+// PipelineContainers returns the containers of the pods of a pipeline in the order in which they run: the pods in the
+// order of their creation then the init containers of each pod followed by its containers
+func PipelineContainers(pods []*corev1.Pod) []*PipelineContainer {
+	sorted := append([]*corev1.Pod{}, pods...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		t1 := sorted[i].CreationTimestamp
+		t2 := sorted[j].CreationTimestamp
+		if !t1.Equal(&t2) {
+			return t1.Before(&t2)
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	answer := []*PipelineContainer{}
+	for _, pod := range sorted {
+		for _, c := range pod.Spec.InitContainers {
+			answer = append(answer, &PipelineContainer{
+				Stage: StageName(c.Name),
+				Pod:   pod,
+				Name:  c.Name,
+				Init:  true,
+			})
+		}
+		for _, c := range pod.Spec.Containers {
+			if c.Name == nopContainerName {
+				continue
+			}
+			answer = append(answer, &PipelineContainer{
+				Stage: StageName(c.Name),
+				Pod:   pod,
+				Name:  c.Name,
+			})
+		}
+	}
+	return answer
+}
+
+// Status returns the status of the container or nil if the pod has no status for it yet
+func (c *PipelineContainer) Status() *corev1.ContainerStatus {
+	statuses := c.Pod.Status.ContainerStatuses
+	if c.Init {
+		statuses = c.Pod.Status.InitContainerStatuses
+	}
+	for i := range statuses {
+		if statuses[i].Name == c.Name {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
+
+// Key returns the key of the current run of the container so that the log of a restarted container is shown again
+func (c *PipelineContainer) Key() string {
+	restarts := int32(0)
+	status := c.Status()
+	if status != nil {
+		restarts = status.RestartCount
+	}
+	return fmt.Sprintf("%s/%s/%d", c.Pod.UID, c.Name, restarts)
+}
+
+// SplitStageLogs splits an archived build log into the logs of its stages using the stage headers. Any text before
+// the first header is returned as a log without a stage
+func SplitStageLogs(data string) []StageLog {
+	answer := []StageLog{}
+	current := StageLog{}
+	lines := []string{}
+	flush := func() {
+		current.Log = strings.Join(lines, "\n")
+		if current.Stage != "" || strings.TrimSpace(current.Log) != "" {
+			answer = append(answer, current)
+		}
+	}
+	for _, line := range strings.Split(data, "\n") {
+		matches := stageHeaderRegex.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if len(matches) > 1 {
+			flush()
+			current = StageLog{Stage: matches[1]}
+			lines = []string{}
+			continue
+		}
+		lines = append(lines, line)
+	}
+	flush()
+	return answer
+}
+
+// ArchivedLogPath returns the path of the archived build log of the pipeline in the logs storage location
+func ArchivedLogPath(activity *v1.PipelineActivity) string {
+	d := kube.CreatePipelineDetails(activity)
+	if d == nil || d.GitOwner == "" || d.GitRepository == "" || d.Build == "" {
+		return ""
+	}
+	return path.Join(ArchivedLogsFolder, d.GitOwner, d.GitRepository, d.BranchName, d.Build+".log")
+}
+
+// ArchivedImageScanReportPath returns the path of the vulnerability report of the image scan of the pipeline in the
+// logs storage location, next to its archived build log
+func ArchivedImageScanReportPath(activity *v1.PipelineActivity) string {
+	logPath := ArchivedLogPath(activity)
+	if logPath == "" {
+		return ""
+	}
+	return strings.TrimSuffix(logPath, ".log") + "-image-scan.json"
+}
+
+// ArchivedLogURLs returns the URLs of the archived build log of the pipeline: its build logs URL if it is a bucket URL
+// then its path in the bucket of the logs storage location
+func ArchivedLogURLs(activity *v1.PipelineActivity, logsBucketURL string) []string {
+	answer := []string{}
+	logsURL := activity.Spec.BuildLogsURL
+	if logsURL != "" {
+		_, _, key, err := buckets.ParseBucketURL(logsURL)
+		if err == nil && key != "" {
+			answer = append(answer, logsURL)
+		}
+	}
+	logPath := ArchivedLogPath(activity)
+	if logsBucketURL != "" && logPath != "" {
+		logsURL = strings.TrimSuffix(logsBucketURL, "/") + "/" + logPath
+		if util.StringArrayIndex(answer, logsURL) < 0 {
+			answer = append(answer, logsURL)
+		}
+	}
+	return answer
+}
@@ -0,0 +1,96 @@
+package builds
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestStageName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "Git Source", StageName("build-step-git-source"))
+	assert.Equal(t, "Build", StageName("build"))
+
+	assert.True(t, MatchesStage("Git Source", ""))
+	assert.True(t, MatchesStage("Git Source", "git source"))
+	assert.True(t, MatchesStage("Git Source", "git-source"))
+	assert.True(t, MatchesStage("build-step-git-source", "Git Source"))
+	assert.False(t, MatchesStage("Git Source", "git"))
+}
+
+func TestPipelineContainers(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	later := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "a-pod", CreationTimestamp: metav1.NewTime(now.Add(time.Minute))},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "build-step-release"}},
+			Containers:     []corev1.Container{{Name: "nop"}},
+		},
+	}
+	earlier := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "b-pod", CreationTimestamp: metav1.NewTime(now)},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "build-step-git-source"}, {Name: "build-step-build"}},
+			Containers:     []corev1.Container{{Name: "nop"}},
+		},
+		Status: corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{{Name: "build-step-build", RestartCount: 1}},
+		},
+	}
+	containers := PipelineContainers([]*corev1.Pod{later, earlier})
+	stages := []string{}
+	for _, c := range containers {
+		stages = append(stages, c.Stage)
+	}
+	assert.Equal(t, []string{"Git Source", "Build", "Release"}, stages)
+	assert.Nil(t, containers[0].Status())
+	assert.Equal(t, int32(1), containers[1].Status().RestartCount)
+	assert.NotEqual(t, containers[0].Key(), containers[1].Key())
+}
+
+func TestSplitStageLogs(t *testing.T) {
+	t.Parallel()
+	data := "preamble\n" + StageHeader("Git Source") + "\ncloning\n" + StageHeader("Build") + "\nbuilding\nbuilt\n"
+	assert.Equal(t, []StageLog{
+		{Log: "preamble"},
+		{Stage: "Git Source", Log: "cloning"},
+		{Stage: "Build", Log: "building\nbuilt\n"},
+	}, SplitStageLogs(data))
+
+	assert.Equal(t, []StageLog{{Log: "no headers"}}, SplitStageLogs("no headers"))
+}
+
+func TestArchivedLogURLs(t *testing.T) {
+	t.Parallel()
+	activity := &v1.PipelineActivity{
+		Spec: v1.PipelineActivitySpec{
+			Pipeline: "myorg/myrepo/master",
+			Build:    "3",
+		},
+	}
+	assert.Equal(t, []string{"gs://logs/jenkins-x/logs/myorg/myrepo/master/3.log"}, ArchivedLogURLs(activity, "gs://logs/"))
+	assert.Empty(t, ArchivedLogURLs(activity, ""))
+
+	activity.Spec.BuildLogsURL = "s3://archive/myrepo/3.log"
+	assert.Equal(t, []string{"s3://archive/myrepo/3.log", "gs://logs/jenkins-x/logs/myorg/myrepo/master/3.log"}, ArchivedLogURLs(activity, "gs://logs"))
+
+	activity.Spec.BuildLogsURL = "https://jenkins/job/myorg/job/myrepo/job/master/3/console"
+	assert.Equal(t, []string{"gs://logs/jenkins-x/logs/myorg/myrepo/master/3.log"}, ArchivedLogURLs(activity, "gs://logs"))
+}
+
+func TestArchivedImageScanReportPath(t *testing.T) {
+	t.Parallel()
+	activity := &v1.PipelineActivity{
+		Spec: v1.PipelineActivitySpec{
+			Pipeline: "myorg/myrepo/PR-12",
+			Build:    "3",
+		},
+	}
+	assert.Equal(t, "jenkins-x/logs/myorg/myrepo/PR-12/3-image-scan.json", ArchivedImageScanReportPath(activity))
+	assert.Equal(t, "", ArchivedImageScanReportPath(&v1.PipelineActivity{}))
+}
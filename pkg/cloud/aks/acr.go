@@ -0,0 +1,37 @@
+package aks
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	// ACRLoginServerSuffix the suffix of the login servers of Azure Container Registries
+	ACRLoginServerSuffix = ".azurecr.io"
+
+	minACRNameLength = 5
+	maxACRNameLength = 50
+)
+
+var disallowedACRNameCharacters = regexp.MustCompile("[^a-z0-9]")
+
+// ACRName returns the name of the Azure Container Registry created for the cluster. Registry names can only contain
+// 5 to 50 alphanumeric characters
+func ACRName(clusterName string) string {
+	name := disallowedACRNameCharacters.ReplaceAllString(strings.ToLower(clusterName), "")
+	if !strings.HasPrefix(name, "jx") {
+		name = "jx" + name
+	}
+	for len(name) < minACRNameLength {
+		name += "0"
+	}
+	if len(name) > maxACRNameLength {
+		name = name[:maxACRNameLength]
+	}
+	return name
+}
+
+// ACRLoginServer returns the login server of the Azure Container Registry which is used as the Docker registry
+func ACRLoginServer(name string) string {
+	return name + ACRLoginServerSuffix
+}
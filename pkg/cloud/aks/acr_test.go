@@ -0,0 +1,18 @@
+package aks_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/cloud/aks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestACRName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "jxmyfunnycluster", aks.ACRName("my-funny_Cluster"))
+	assert.Equal(t, "jxdev", aks.ACRName("jx-dev"))
+	assert.Equal(t, "jxa00", aks.ACRName("a"))
+	assert.Len(t, aks.ACRName(strings.Repeat("a", 60)), 50)
+	assert.Equal(t, "jxdev.azurecr.io", aks.ACRLoginServer("jxdev"))
+}
@@ -0,0 +1,98 @@
+package aks
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// ProbeBlobContainer checks the blob container of the storage account in $AZURE_STORAGE_ACCOUNT is writable by
+// uploading then deleting a blob with the given name
+func ProbeBlobContainer(container string, name string) error {
+	file, err := ioutil.TempFile("", "jx-probe-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	file.Close()
+
+	commands := [][]string{
+		{"storage", "blob", "upload", "--container-name", container, "--name", name, "--file", file.Name()},
+		{"storage", "blob", "delete", "--container-name", container, "--name", name},
+	}
+	for _, args := range commands {
+		cmd := util.Command{
+			Name: "az",
+			Args: args,
+		}
+		output, err := cmd.RunWithoutRetry()
+		if err != nil {
+			return fmt.Errorf("failed to run az %s: %s, %s", strings.Join(args, " "), output, err)
+		}
+	}
+	return nil
+}
+
+// DeleteBlobContainer deletes the blob container of the storage account in $AZURE_STORAGE_ACCOUNT with its blobs
+func DeleteBlobContainer(container string) error {
+	cmd := util.Command{
+		Name: "az",
+		Args: []string{"storage", "container", "delete", "--name", container},
+	}
+	output, err := cmd.RunWithoutRetry()
+	if err != nil {
+		return fmt.Errorf("failed to delete the blob container %s: %s, %s", container, output, err)
+	}
+	return nil
+}
+
+// ReadBlob returns the contents of the blob with the given name of the blob container of the storage account in
+// $AZURE_STORAGE_ACCOUNT
+func ReadBlob(container string, name string) ([]byte, error) {
+	file, err := ioutil.TempFile("", "jx-blob-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(file.Name())
+	file.Close()
+
+	args := []string{"storage", "blob", "download", "--container-name", container, "--name", name, "--file", file.Name()}
+	cmd := util.Command{
+		Name: "az",
+		Args: args,
+	}
+	output, err := cmd.RunWithoutRetry()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run az %s: %s, %s", strings.Join(args, " "), output, err)
+	}
+	return ioutil.ReadFile(file.Name())
+}
+
+// WriteBlob writes the data into the blob with the given name of the blob container of the storage account in
+// $AZURE_STORAGE_ACCOUNT
+func WriteBlob(container string, name string, data []byte) error {
+	file, err := ioutil.TempFile("", "jx-blob-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	_, err = file.Write(data)
+	file.Close()
+	if err != nil {
+		return err
+	}
+
+	args := []string{"storage", "blob", "upload", "--container-name", container, "--name", name, "--file", file.Name()}
+	cmd := util.Command{
+		Name: "az",
+		Args: args,
+	}
+	output, err := cmd.RunWithoutRetry()
+	if err != nil {
+		return fmt.Errorf("failed to run az %s: %s, %s", strings.Join(args, " "), output, err)
+	}
+	return nil
+}
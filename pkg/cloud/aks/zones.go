@@ -0,0 +1,90 @@
+package aks
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+const (
+	// AvailabilityZoneLocationsEnvVar the environment variable used to override the comma separated list of Azure
+	// locations which support availability zones
+	AvailabilityZoneLocationsEnvVar = "AKS_AVAILABILITY_ZONE_LOCATIONS"
+
+	// VMSetTypeScaleSets the VM set type of node pools backed by virtual machine scale sets
+	VMSetTypeScaleSets = "VirtualMachineScaleSets"
+
+	// VMSetTypeAvailabilitySet the VM set type of node pools backed by an availability set
+	VMSetTypeAvailabilitySet = "AvailabilitySet"
+)
+
+var (
+	// VMSetTypes the VM set types of AKS node pools
+	VMSetTypes = []string{VMSetTypeScaleSets, VMSetTypeAvailabilitySet}
+
+	// AvailabilityZones the availability zones of the Azure locations which support them
+	AvailabilityZones = []string{"1", "2", "3"}
+
+	// DefaultAvailabilityZoneLocations the Azure locations which support availability zones if not overridden via
+	// $AKS_AVAILABILITY_ZONE_LOCATIONS
+	DefaultAvailabilityZoneLocations = []string{
+		"centralus",
+		"eastus",
+		"eastus2",
+		"francecentral",
+		"japaneast",
+		"northeurope",
+		"southeastasia",
+		"uksouth",
+		"westeurope",
+		"westus2",
+	}
+)
+
+// AvailabilityZoneLocations returns the Azure locations which support availability zones
+func AvailabilityZoneLocations() []string {
+	value := os.Getenv(AvailabilityZoneLocationsEnvVar)
+	if value == "" {
+		return DefaultAvailabilityZoneLocations
+	}
+	answer := []string{}
+	for _, location := range strings.Split(value, ",") {
+		location = strings.TrimSpace(location)
+		if location != "" {
+			answer = append(answer, location)
+		}
+	}
+	return answer
+}
+
+// ParseZones parses a comma separated list of availability zones such as 1,2,3
+func ParseZones(zones string) ([]string, error) {
+	answer := []string{}
+	for _, zone := range strings.Split(zones, ",") {
+		zone = strings.TrimSpace(zone)
+		if zone == "" {
+			continue
+		}
+		if !util.Contains(AvailabilityZones, zone) {
+			return nil, fmt.Errorf("%s is not an availability zone, the zones are %s", zone, strings.Join(AvailabilityZones, ", "))
+		}
+		if !util.Contains(answer, zone) {
+			answer = append(answer, zone)
+		}
+	}
+	return answer, nil
+}
+
+// ValidateZones returns an error if availability zones are requested in a location which does not support them
+func ValidateZones(location string, zones []string) error {
+	if len(zones) == 0 {
+		return nil
+	}
+	locations := AvailabilityZoneLocations()
+	if !util.Contains(locations, location) {
+		return fmt.Errorf("location %s does not support availability zones, the locations which do are %s", location, strings.Join(locations, ", "))
+	}
+	return nil
+}
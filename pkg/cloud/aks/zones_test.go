@@ -0,0 +1,37 @@
+package aks_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/cloud/aks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseZones(t *testing.T) {
+	t.Parallel()
+	zones, err := aks.ParseZones("1, 2,3,1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "3"}, zones)
+
+	zones, err = aks.ParseZones("")
+	assert.NoError(t, err)
+	assert.Empty(t, zones)
+
+	_, err = aks.ParseZones("1,4")
+	assert.Error(t, err)
+}
+
+func TestValidateZones(t *testing.T) {
+	assert.NoError(t, aks.ValidateZones("westeurope", []string{"1", "2"}))
+	assert.NoError(t, aks.ValidateZones("westus", nil))
+
+	err := aks.ValidateZones("westus", []string{"1"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "does not support availability zones")
+
+	os.Setenv(aks.AvailabilityZoneLocationsEnvVar, "westus, eastus")
+	defer os.Unsetenv(aks.AvailabilityZoneLocationsEnvVar)
+	assert.NoError(t, aks.ValidateZones("westus", []string{"1"}))
+	assert.Error(t, aks.ValidateZones("westeurope", []string{"1"}))
+}
@@ -0,0 +1,52 @@
+package amazon
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+)
+
+// AssumeRoleDuration how long the temporary credentials of an assumed role are valid for
+var AssumeRoleDuration = time.Hour
+
+// assumedRoleCredentials the credentials of the role assumed via AssumeRole which are used by all AWS sessions
+var assumedRoleCredentials *credentials.Credentials
+
+// AssumeRole assumes the role using the current credentials so that all AWS sessions created afterwards use the
+// temporary credentials of the role. The credentials are refreshed before they expire
+func AssumeRole(profile string, region string, roleArn string, sessionName string) error {
+	sess, err := NewAwsSession(profile, region)
+	if err != nil {
+		return err
+	}
+	creds := stscreds.NewCredentials(sess, roleArn, func(provider *stscreds.AssumeRoleProvider) {
+		provider.RoleSessionName = sessionName
+		provider.Duration = AssumeRoleDuration
+		provider.ExpiryWindow = 5 * time.Minute
+	})
+	// fail fast if the role cannot be assumed
+	_, err = creds.Get()
+	if err != nil {
+		return err
+	}
+	assumedRoleCredentials = creds
+	return nil
+}
+
+// AssumedRoleEnv returns the environment variables containing the current temporary credentials of the assumed role
+// so that they can be passed to other AWS tools. Returns nil if no role has been assumed
+func AssumedRoleEnv() (map[string]string, error) {
+	if assumedRoleCredentials == nil {
+		return nil, nil
+	}
+	value, err := assumedRoleCredentials.Get()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		"AWS_ACCESS_KEY_ID":     value.AccessKeyID,
+		"AWS_SECRET_ACCESS_KEY": value.SecretAccessKey,
+		"AWS_SESSION_TOKEN":     value.SessionToken,
+	}, nil
+}
@@ -0,0 +1,153 @@
+package amazon
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// ClusterTagKey returns the tag key Kubernetes uses to mark the AWS resources it creates for a cluster
+func ClusterTagKey(clusterName string) string {
+	return "kubernetes.io/cluster/" + clusterName
+}
+
+// ParseEksctlClusterNames parses the output of `eksctl get cluster -o json` returning the cluster names
+func ParseEksctlClusterNames(data []byte) ([]string, error) {
+	clusters := []map[string]interface{}{}
+	err := json.Unmarshal(data, &clusters)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse the eksctl clusters due to %s", err)
+	}
+	answer := []string{}
+	for _, cluster := range clusters {
+		for _, key := range []string{"name", "Name"} {
+			if name, ok := cluster[key].(string); ok && name != "" {
+				answer = append(answer, name)
+				break
+			}
+		}
+		if metadata, ok := cluster["metadata"].(map[string]interface{}); ok {
+			if name, ok := metadata["name"].(string); ok && name != "" {
+				answer = append(answer, name)
+			}
+		}
+	}
+	return answer, nil
+}
+
+// DeleteClusterLoadBalancers deletes any load balancers created by Kubernetes for the cluster which were left behind
+// after the cluster was deleted, returning the names of the deleted load balancers
+func DeleteClusterLoadBalancers(profile string, region string, clusterName string) ([]string, error) {
+	sess, err := NewAwsSession(profile, region)
+	if err != nil {
+		return nil, err
+	}
+	tagKey := ClusterTagKey(clusterName)
+	deleted := []string{}
+
+	elbSvc := elb.New(sess)
+	loadBalancers, err := elbSvc.DescribeLoadBalancers(&elb.DescribeLoadBalancersInput{})
+	if err != nil {
+		return deleted, err
+	}
+	for _, loadBalancer := range loadBalancers.LoadBalancerDescriptions {
+		name := aws.StringValue(loadBalancer.LoadBalancerName)
+		tags, err := elbSvc.DescribeTags(&elb.DescribeTagsInput{LoadBalancerNames: []*string{loadBalancer.LoadBalancerName}})
+		if err != nil {
+			return deleted, err
+		}
+		if !hasELBTag(tags, tagKey) {
+			continue
+		}
+		log.Infof("Deleting load balancer %s\n", util.ColorInfo(name))
+		_, err = elbSvc.DeleteLoadBalancer(&elb.DeleteLoadBalancerInput{LoadBalancerName: loadBalancer.LoadBalancerName})
+		if err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, name)
+	}
+
+	elbv2Svc := elbv2.New(sess)
+	loadBalancersV2, err := elbv2Svc.DescribeLoadBalancers(&elbv2.DescribeLoadBalancersInput{})
+	if err != nil {
+		return deleted, err
+	}
+	for _, loadBalancer := range loadBalancersV2.LoadBalancers {
+		name := aws.StringValue(loadBalancer.LoadBalancerName)
+		tags, err := elbv2Svc.DescribeTags(&elbv2.DescribeTagsInput{ResourceArns: []*string{loadBalancer.LoadBalancerArn}})
+		if err != nil {
+			return deleted, err
+		}
+		if !hasELBv2Tag(tags, tagKey) {
+			continue
+		}
+		log.Infof("Deleting load balancer %s\n", util.ColorInfo(name))
+		_, err = elbv2Svc.DeleteLoadBalancer(&elbv2.DeleteLoadBalancerInput{LoadBalancerArn: loadBalancer.LoadBalancerArn})
+		if err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, name)
+	}
+	return deleted, nil
+}
+
+// DeleteClusterSecurityGroups deletes any security groups tagged with the cluster which were left behind after the
+// cluster was deleted, returning the IDs of the deleted security groups
+func DeleteClusterSecurityGroups(profile string, region string, clusterName string) ([]string, error) {
+	sess, err := NewAwsSession(profile, region)
+	if err != nil {
+		return nil, err
+	}
+	svc := ec2.New(sess)
+	groups, err := svc.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("tag-key"),
+				Values: aws.StringSlice([]string{ClusterTagKey(clusterName)}),
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	deleted := []string{}
+	for _, group := range groups.SecurityGroups {
+		id := aws.StringValue(group.GroupId)
+		log.Infof("Deleting security group %s\n", util.ColorInfo(id))
+		_, err = svc.DeleteSecurityGroup(&ec2.DeleteSecurityGroupInput{GroupId: group.GroupId})
+		if err != nil {
+			log.Warnf("Failed to delete security group %s: %s\n", id, err)
+			continue
+		}
+		deleted = append(deleted, id)
+	}
+	return deleted, nil
+}
+
+func hasELBTag(output *elb.DescribeTagsOutput, key string) bool {
+	for _, description := range output.TagDescriptions {
+		for _, tag := range description.Tags {
+			if aws.StringValue(tag.Key) == key {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasELBv2Tag(output *elbv2.DescribeTagsOutput, key string) bool {
+	for _, description := range output.TagDescriptions {
+		for _, tag := range description.Tags {
+			if aws.StringValue(tag.Key) == key {
+				return true
+			}
+		}
+	}
+	return false
+}
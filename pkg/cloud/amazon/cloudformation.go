@@ -0,0 +1,235 @@
+package amazon
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+)
+
+const (
+	// EksctlStacksMissing none of the eksctl stacks of the cluster exist
+	EksctlStacksMissing = "Missing"
+	// EksctlStacksComplete the control plane and nodegroup stacks were created
+	EksctlStacksComplete = "Complete"
+	// EksctlStacksInProgress some of the stacks are still being created, updated or deleted
+	EksctlStacksInProgress = "InProgress"
+	// EksctlStacksNodeGroupFailed the control plane stack was created but the nodegroup stacks are missing or failed
+	EksctlStacksNodeGroupFailed = "NodeGroupFailed"
+	// EksctlStacksRolledBack the control plane stack failed and needs to be deleted before the cluster can be created
+	EksctlStacksRolledBack = "RolledBack"
+)
+
+// EksctlClusterStackName returns the name of the CloudFormation stack of the EKS control plane created by eksctl
+func EksctlClusterStackName(clusterName string) string {
+	return EksctlStackPrefix(clusterName) + "cluster"
+}
+
+// EksctlStackPrefix returns the prefix of the names of the CloudFormation stacks eksctl creates for the cluster
+func EksctlStackPrefix(clusterName string) string {
+	return "eksctl-" + clusterName + "-"
+}
+
+// StackEventWatcher polls the events of the CloudFormation stacks whose names start with a prefix, returning only
+// the events which have not been seen before
+type StackEventWatcher struct {
+	StackPrefix string
+
+	svc     *cloudformation.CloudFormation
+	seen    map[string]bool
+	failed  []*cloudformation.StackEvent
+	lastKey map[string]string
+}
+
+// NewStackEventWatcher creates a watcher for the stacks with the given name prefix
+func NewStackEventWatcher(profile string, region string, stackPrefix string) (*StackEventWatcher, error) {
+	sess, err := NewAwsSession(profile, region)
+	if err != nil {
+		return nil, err
+	}
+	watcher := NewStackEventFilter(stackPrefix)
+	watcher.svc = cloudformation.New(sess)
+	return watcher, nil
+}
+
+// NewStackEventFilter creates a watcher which is not connected to AWS so that events can be added to it directly
+func NewStackEventFilter(stackPrefix string) *StackEventWatcher {
+	return &StackEventWatcher{
+		StackPrefix: stackPrefix,
+		seen:        map[string]bool{},
+		lastKey:     map[string]string{},
+	}
+}
+
+// Poll describes the events of the matching stacks returning the new events, oldest first
+func (w *StackEventWatcher) Poll() ([]*cloudformation.StackEvent, error) {
+	stackNames := []string{}
+	err := w.svc.DescribeStacksPages(&cloudformation.DescribeStacksInput{}, func(page *cloudformation.DescribeStacksOutput, lastPage bool) bool {
+		for _, stack := range page.Stacks {
+			name := aws.StringValue(stack.StackName)
+			if strings.HasPrefix(name, w.StackPrefix) {
+				stackNames = append(stackNames, name)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	events := []*cloudformation.StackEvent{}
+	for _, name := range stackNames {
+		output, err := w.svc.DescribeStackEvents(&cloudformation.DescribeStackEventsInput{StackName: aws.String(name)})
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, output.StackEvents...)
+	}
+	return w.AddEvents(events), nil
+}
+
+// AddEvents records the given events returning those not seen before, oldest first. Events which only repeat the
+// last status and reason reported for the same resource are collapsed
+func (w *StackEventWatcher) AddEvents(events []*cloudformation.StackEvent) []*cloudformation.StackEvent {
+	sorted := append([]*cloudformation.StackEvent{}, events...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return aws.TimeValue(sorted[i].Timestamp).Before(aws.TimeValue(sorted[j].Timestamp))
+	})
+	answer := []*cloudformation.StackEvent{}
+	for _, event := range sorted {
+		id := aws.StringValue(event.EventId)
+		if w.seen[id] {
+			continue
+		}
+		w.seen[id] = true
+
+		if aws.StringValue(event.ResourceStatus) == cloudformation.ResourceStatusCreateFailed {
+			w.failed = append(w.failed, event)
+		}
+		resource := aws.StringValue(event.StackName) + "/" + aws.StringValue(event.LogicalResourceId)
+		key := aws.StringValue(event.ResourceStatus) + "|" + aws.StringValue(event.ResourceStatusReason)
+		if w.lastKey[resource] == key {
+			continue
+		}
+		w.lastKey[resource] = key
+		answer = append(answer, event)
+	}
+	return answer
+}
+
+// FailedEvents returns up to the given number of the most recent CREATE_FAILED events
+func (w *StackEventWatcher) FailedEvents(count int) []*cloudformation.StackEvent {
+	if len(w.failed) <= count {
+		return w.failed
+	}
+	return w.failed[len(w.failed)-count:]
+}
+
+// FormatStackEvent returns a single line description of the event
+func FormatStackEvent(event *cloudformation.StackEvent) string {
+	text := fmt.Sprintf("%s %s %s", aws.StringValue(event.StackName), aws.StringValue(event.LogicalResourceId), aws.StringValue(event.ResourceStatus))
+	reason := aws.StringValue(event.ResourceStatusReason)
+	if reason != "" {
+		text += ": " + reason
+	}
+	return text
+}
+
+// EksctlStacks returns the CloudFormation stacks eksctl created for the cluster
+func EksctlStacks(profile string, region string, clusterName string) ([]*cloudformation.Stack, error) {
+	sess, err := NewAwsSession(profile, region)
+	if err != nil {
+		return nil, err
+	}
+	prefix := EksctlStackPrefix(clusterName)
+	answer := []*cloudformation.Stack{}
+	err = cloudformation.New(sess).DescribeStacksPages(&cloudformation.DescribeStacksInput{}, func(page *cloudformation.DescribeStacksOutput, lastPage bool) bool {
+		for _, stack := range page.Stacks {
+			if strings.HasPrefix(aws.StringValue(stack.StackName), prefix) {
+				answer = append(answer, stack)
+			}
+		}
+		return true
+	})
+	return answer, err
+}
+
+// EksctlStacksState returns the state of the cluster given the stacks eksctl created for it. If withNodeGroups is
+// false the cluster is complete without any nodegroup stacks, e.g. when its pods run on Fargate
+func EksctlStacksState(clusterName string, stacks []*cloudformation.Stack, withNodeGroups bool) string {
+	clusterStackName := EksctlClusterStackName(clusterName)
+	var clusterStack *cloudformation.Stack
+	nodeGroupStacks := []*cloudformation.Stack{}
+	for _, stack := range stacks {
+		if aws.StringValue(stack.StackName) == clusterStackName {
+			clusterStack = stack
+		} else {
+			nodeGroupStacks = append(nodeGroupStacks, stack)
+		}
+	}
+	if clusterStack == nil {
+		if len(nodeGroupStacks) == 0 {
+			return EksctlStacksMissing
+		}
+		return EksctlStacksRolledBack
+	}
+	switch stackStatusState(aws.StringValue(clusterStack.StackStatus)) {
+	case EksctlStacksInProgress:
+		return EksctlStacksInProgress
+	case EksctlStacksRolledBack:
+		return EksctlStacksRolledBack
+	}
+	if len(nodeGroupStacks) == 0 && withNodeGroups {
+		return EksctlStacksNodeGroupFailed
+	}
+	answer := EksctlStacksComplete
+	for _, stack := range nodeGroupStacks {
+		switch stackStatusState(aws.StringValue(stack.StackStatus)) {
+		case EksctlStacksInProgress:
+			return EksctlStacksInProgress
+		case EksctlStacksRolledBack:
+			answer = EksctlStacksNodeGroupFailed
+		}
+	}
+	return answer
+}
+
+// FailedEksctlNodeGroupStacks returns the names of the nodegroup stacks which failed and need to be deleted before
+// the nodegroup can be created again
+func FailedEksctlNodeGroupStacks(clusterName string, stacks []*cloudformation.Stack) []string {
+	answer := []string{}
+	for _, stack := range stacks {
+		name := aws.StringValue(stack.StackName)
+		if name != EksctlClusterStackName(clusterName) && stackStatusState(aws.StringValue(stack.StackStatus)) == EksctlStacksRolledBack {
+			answer = append(answer, name)
+		}
+	}
+	return answer
+}
+
+// DeleteStack deletes the CloudFormation stack waiting for the deletion to complete
+func DeleteStack(profile string, region string, stackName string) error {
+	sess, err := NewAwsSession(profile, region)
+	if err != nil {
+		return err
+	}
+	svc := cloudformation.New(sess)
+	_, err = svc.DeleteStack(&cloudformation.DeleteStackInput{StackName: aws.String(stackName)})
+	if err != nil {
+		return err
+	}
+	return svc.WaitUntilStackDeleteComplete(&cloudformation.DescribeStacksInput{StackName: aws.String(stackName)})
+}
+
+// stackStatusState maps the status of a stack to EksctlStacksComplete, EksctlStacksInProgress or EksctlStacksRolledBack
+func stackStatusState(status string) string {
+	switch {
+	case strings.HasSuffix(status, "_IN_PROGRESS"):
+		return EksctlStacksInProgress
+	case status == cloudformation.StackStatusCreateComplete || status == cloudformation.StackStatusUpdateComplete:
+		return EksctlStacksComplete
+	default:
+		return EksctlStacksRolledBack
+	}
+}
@@ -0,0 +1,84 @@
+package amazon_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/jenkins-x/jx/pkg/cloud/amazon"
+	"github.com/stretchr/testify/assert"
+)
+
+func stackEvent(id string, resource string, status string, reason string, seconds int) *cloudformation.StackEvent {
+	return &cloudformation.StackEvent{
+		EventId:              aws.String(id),
+		StackName:            aws.String("eksctl-foo-cluster"),
+		LogicalResourceId:    aws.String(resource),
+		ResourceStatus:       aws.String(status),
+		ResourceStatusReason: aws.String(reason),
+		Timestamp:            aws.Time(time.Unix(int64(seconds), 0)),
+	}
+}
+
+func TestStackEventWatcherAddEvents(t *testing.T) {
+	watcher := amazon.NewStackEventFilter(amazon.EksctlStackPrefix("foo"))
+	assert.Equal(t, "eksctl-foo-", watcher.StackPrefix)
+
+	// DescribeStackEvents returns the newest events first
+	events := watcher.AddEvents([]*cloudformation.StackEvent{
+		stackEvent("3", "VPC", "CREATE_IN_PROGRESS", "Resource creation Initiated", 3),
+		stackEvent("2", "VPC", "CREATE_IN_PROGRESS", "", 2),
+		stackEvent("1", "VPC", "CREATE_IN_PROGRESS", "", 1),
+	})
+	assert.Equal(t, []string{"1", "3"}, eventIDs(events), "duplicates should be collapsed")
+
+	events = watcher.AddEvents([]*cloudformation.StackEvent{
+		stackEvent("5", "NATIP", "CREATE_FAILED", "The maximum number of addresses has been reached.", 5),
+		stackEvent("4", "VPC", "CREATE_COMPLETE", "", 4),
+		stackEvent("3", "VPC", "CREATE_IN_PROGRESS", "Resource creation Initiated", 3),
+	})
+	assert.Equal(t, []string{"4", "5"}, eventIDs(events), "only new events should be returned")
+
+	failed := watcher.FailedEvents(5)
+	assert.Equal(t, []string{"5"}, eventIDs(failed))
+	assert.Equal(t, "eksctl-foo-cluster NATIP CREATE_FAILED: The maximum number of addresses has been reached.", amazon.FormatStackEvent(failed[0]))
+}
+
+func eventIDs(events []*cloudformation.StackEvent) []string {
+	answer := []string{}
+	for _, event := range events {
+		answer = append(answer, aws.StringValue(event.EventId))
+	}
+	return answer
+}
+
+func stack(name string, status string) *cloudformation.Stack {
+	return &cloudformation.Stack{
+		StackName:   aws.String(name),
+		StackStatus: aws.String(status),
+	}
+}
+
+func TestEksctlStacksState(t *testing.T) {
+	assert.Equal(t, amazon.EksctlStacksMissing, amazon.EksctlStacksState("foo", nil, true))
+	assert.Equal(t, amazon.EksctlStacksComplete, amazon.EksctlStacksState("foo", []*cloudformation.Stack{
+		stack("eksctl-foo-cluster", "CREATE_COMPLETE"),
+		stack("eksctl-foo-nodegroup-ng-1", "CREATE_COMPLETE"),
+	}, true))
+	assert.Equal(t, amazon.EksctlStacksInProgress, amazon.EksctlStacksState("foo", []*cloudformation.Stack{
+		stack("eksctl-foo-cluster", "CREATE_IN_PROGRESS"),
+	}, true))
+	assert.Equal(t, amazon.EksctlStacksRolledBack, amazon.EksctlStacksState("foo", []*cloudformation.Stack{
+		stack("eksctl-foo-cluster", "ROLLBACK_COMPLETE"),
+	}, true))
+
+	failedNodeGroup := []*cloudformation.Stack{
+		stack("eksctl-foo-cluster", "CREATE_COMPLETE"),
+		stack("eksctl-foo-nodegroup-ng-1", "ROLLBACK_COMPLETE"),
+	}
+	assert.Equal(t, amazon.EksctlStacksNodeGroupFailed, amazon.EksctlStacksState("foo", failedNodeGroup, true))
+	assert.Equal(t, []string{"eksctl-foo-nodegroup-ng-1"}, amazon.FailedEksctlNodeGroupStacks("foo", failedNodeGroup))
+	assert.Equal(t, amazon.EksctlStacksNodeGroupFailed, amazon.EksctlStacksState("foo", failedNodeGroup[:1], true))
+	assert.Equal(t, amazon.EksctlStacksComplete, amazon.EksctlStacksState("foo", failedNodeGroup[:1], false), "Fargate clusters have no nodegroups")
+}
@@ -3,9 +3,12 @@ package amazon
 import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"os"
 	"path"
+	"sort"
+	"strings"
 )
 
 const DefaultRegion = "us-west-2"
@@ -22,6 +25,9 @@ func NewAwsSession(profileOption string, regionOption string) (*session.Session,
 				&credentials.SharedCredentialsProvider{Filename: "", Profile: profileOption},
 			})
 	}
+	if assumedRoleCredentials != nil {
+		config.Credentials = assumedRoleCredentials
+	}
 
 	sessionOptions := session.Options{
 		SharedConfigState: session.SharedConfigEnable,
@@ -48,14 +54,38 @@ func NewAwsSessionWithoutOptions() (*session.Session, error) {
 	return NewAwsSession("", "")
 }
 
+// ResolveRegion returns the region option if specified, otherwise the region is resolved via ResolveRegionWithProfile
 func ResolveRegion(profileOption string, regionOption string) (string, error) {
-	session, err := NewAwsSession(profileOption, regionOption)
-	if err != nil {
-		return "", err
+	if regionOption != "" {
+		return regionOption, nil
 	}
-	return *session.Config.Region, nil
+	return ResolveRegionWithProfile(profileOption)
 }
 
 func ResolveRegionWithoutOptions() (string, error) {
 	return ResolveRegion("", "")
 }
+
+// Regions returns the sorted names of the regions of all the AWS partitions, including China and GovCloud (US)
+func Regions() []string {
+	answer := []string{}
+	for _, partition := range endpoints.DefaultPartitions() {
+		for name := range partition.Regions() {
+			answer = append(answer, name)
+		}
+	}
+	sort.Strings(answer)
+	return answer
+}
+
+// splitCommaSeparated splits the comma separated list removing any whitespace and empty values
+func splitCommaSeparated(text string) []string {
+	answer := []string{}
+	for _, value := range strings.Split(text, ",") {
+		value = strings.TrimSpace(value)
+		if value != "" {
+			answer = append(answer, value)
+		}
+	}
+	return answer
+}
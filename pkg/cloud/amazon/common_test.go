@@ -101,3 +101,9 @@ func TestReadingRegionFromEnvProfile(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, "qux", *session.Config.Region)
 }
+
+func TestRegions(t *testing.T) {
+	regions := amazon.Regions()
+	assert.Contains(t, regions, amazon.DefaultRegion)
+	assert.Contains(t, regions, "eu-west-1")
+}
@@ -39,7 +39,7 @@ func GetContainerRegistryHost() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return accountId + ".dkr.ecr." + region + ".amazonaws.com", nil
+	return accountId + ".dkr.ecr." + region + "." + RegionDNSSuffix(region), nil
 }
 
 // LazyCreateRegistry lazily creates the ECR registry if it does not already exist
@@ -0,0 +1,88 @@
+package amazon
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+const (
+	// DefaultEKSKubernetesVersion the Kubernetes version used by default when creating EKS clusters
+	DefaultEKSKubernetesVersion = "1.13"
+
+	// EKSKubernetesVersionsEnvVar the environment variable used to override the comma separated list
+	// of Kubernetes versions supported by EKS
+	EKSKubernetesVersionsEnvVar = "EKS_KUBERNETES_VERSIONS"
+
+	// EKSGPUAMIFamily the AMI family of the EKS optimized AMIs with GPU support
+	EKSGPUAMIFamily = "AmazonLinux2"
+
+	// MinEKSNodeVolumeSize the smallest node volume size in GiB the EKS AMIs boot reliably with
+	MinEKSNodeVolumeSize = 20
+)
+
+// EKSNodeVolumeTypes the EBS volume types supported for EKS node volumes
+var EKSNodeVolumeTypes = []string{"gp2", "io1", "sc1", "st1"}
+
+// DefaultEKSKubernetesVersions the Kubernetes versions supported by EKS if not overridden via $EKS_KUBERNETES_VERSIONS
+var DefaultEKSKubernetesVersions = []string{"1.12", "1.13", "1.14"}
+
+// EKSKubernetesVersions returns the Kubernetes versions supported by EKS in the given region
+func EKSKubernetesVersions(region string) []string {
+	value := os.Getenv(EKSKubernetesVersionsEnvVar)
+	if value != "" {
+		answer := []string{}
+		for _, v := range strings.Split(value, ",") {
+			v = strings.TrimSpace(v)
+			if v != "" {
+				answer = append(answer, v)
+			}
+		}
+		return answer
+	}
+	return DefaultEKSKubernetesVersions
+}
+
+// ValidateEKSKubernetesVersion returns an error if the given Kubernetes version is not supported by EKS in the region
+func ValidateEKSKubernetesVersion(region string, version string) error {
+	versions := EKSKubernetesVersions(region)
+	if util.Contains(versions, version) {
+		return nil
+	}
+	if len(versions) == 0 {
+		return fmt.Errorf("no Kubernetes versions are configured for EKS in %s", region)
+	}
+	return fmt.Errorf("EKS in %s supports %s–%s but version %s was requested", region, versions[0], versions[len(versions)-1], version)
+}
+
+// EKSNodeTypes the commonly used EC2 instance types for EKS nodes
+var EKSNodeTypes = []string{
+	"m5.large",
+	"m5.xlarge",
+	"m5.2xlarge",
+	"m4.large",
+	"m4.xlarge",
+	"c5.large",
+	"c5.xlarge",
+	"r5.large",
+	"r5.xlarge",
+	"t3.large",
+	"t3.xlarge",
+}
+
+// EKSGPUInstanceFamilies the EC2 instance families with NVIDIA GPUs
+var EKSGPUInstanceFamilies = []string{"p2", "p3", "g3", "g4"}
+
+// IsGPUInstanceType returns true if the EC2 instance type has NVIDIA GPUs
+func IsGPUInstanceType(instanceType string) bool {
+	family := strings.SplitN(strings.TrimSpace(instanceType), ".", 2)[0]
+	for _, gpuFamily := range EKSGPUInstanceFamilies {
+		// also match variants like g4dn and p3dn
+		if strings.HasPrefix(family, gpuFamily) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package amazon_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/cloud/amazon"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateEKSKubernetesVersion(t *testing.T) {
+	os.Setenv(amazon.EKSKubernetesVersionsEnvVar, "")
+	assert.Nil(t, amazon.ValidateEKSKubernetesVersion("eu-west-1", amazon.DefaultEKSKubernetesVersion))
+
+	err := amazon.ValidateEKSKubernetesVersion("eu-west-1", "1.9")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "EKS in eu-west-1 supports 1.12–1.14")
+}
+
+func TestEKSKubernetesVersionsFromEnv(t *testing.T) {
+	os.Setenv(amazon.EKSKubernetesVersionsEnvVar, "1.14, 1.15")
+	defer os.Setenv(amazon.EKSKubernetesVersionsEnvVar, "")
+	assert.Equal(t, []string{"1.14", "1.15"}, amazon.EKSKubernetesVersions("us-east-1"))
+	assert.Nil(t, amazon.ValidateEKSKubernetesVersion("us-east-1", "1.15"))
+}
+
+func TestIsGPUInstanceType(t *testing.T) {
+	for _, instanceType := range []string{"p2.xlarge", "p3.2xlarge", "p3dn.24xlarge", "g3s.xlarge", "g4dn.xlarge"} {
+		assert.True(t, amazon.IsGPUInstanceType(instanceType), "%s should be a GPU instance type", instanceType)
+	}
+	for _, instanceType := range []string{"m5.large", "c5.xlarge", "t3.large", ""} {
+		assert.False(t, amazon.IsGPUInstanceType(instanceType), "%s should not be a GPU instance type", instanceType)
+	}
+}
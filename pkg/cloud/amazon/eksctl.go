@@ -0,0 +1,205 @@
+package amazon
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/jenkins-x/jx/pkg/util"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// EksctlAPIVersion the eksctl API version of generated ClusterConfig files
+	EksctlAPIVersion = "eksctl.io/v1alpha5"
+
+	// EksctlClusterConfigKind the kind of eksctl ClusterConfig files
+	EksctlClusterConfigKind = "ClusterConfig"
+)
+
+var (
+	eksctlGitTagRegex  = regexp.MustCompile(`GitTag:"([^"]+)"`)
+	eksctlVersionRegex = regexp.MustCompile(`\d+\.\d+\.\d+`)
+
+	// MinEksctlManagedVersion the first eksctl version supporting managed nodegroups
+	MinEksctlManagedVersion = semver.MustParse("0.11.0")
+
+	// MinEksctlManagedSSHVersion the first eksctl version supporting SSH access to managed nodegroups
+	MinEksctlManagedSSHVersion = semver.MustParse("0.12.0")
+)
+
+// EksctlClusterConfig is the subset of the eksctl ClusterConfig YAML that jx needs to understand
+type EksctlClusterConfig struct {
+	APIVersion        string             `yaml:"apiVersion,omitempty"`
+	Kind              string             `yaml:"kind,omitempty"`
+	Metadata          EksctlClusterMeta  `yaml:"metadata"`
+	NodeGroups        []*EksctlNodeGroup `yaml:"nodeGroups,omitempty"`
+	AvailabilityZones []string           `yaml:"availabilityZones,omitempty"`
+	VPC               *EksctlClusterVPC  `yaml:"vpc,omitempty"`
+}
+
+// EksctlClusterVPC the existing VPC of an eksctl ClusterConfig
+type EksctlClusterVPC struct {
+	Subnets EksctlClusterSubnets `yaml:"subnets"`
+}
+
+// EksctlClusterSubnets the existing subnets of the VPC indexed by availability zone
+type EksctlClusterSubnets struct {
+	Private map[string]EksctlSubnet `yaml:"private,omitempty"`
+	Public  map[string]EksctlSubnet `yaml:"public,omitempty"`
+}
+
+// EksctlSubnet an existing subnet
+type EksctlSubnet struct {
+	ID string `yaml:"id"`
+}
+
+// EksctlClusterMeta the metadata of an eksctl ClusterConfig
+type EksctlClusterMeta struct {
+	Name    string            `yaml:"name"`
+	Region  string            `yaml:"region"`
+	Version string            `yaml:"version,omitempty"`
+	Tags    map[string]string `yaml:"tags,omitempty"`
+}
+
+// EksctlNodeGroup a nodegroup of an eksctl ClusterConfig
+type EksctlNodeGroup struct {
+	Name                  string                       `yaml:"name"`
+	InstanceType          string                       `yaml:"instanceType,omitempty"`
+	AMIFamily             string                       `yaml:"amiFamily,omitempty"`
+	Labels                map[string]string            `yaml:"labels,omitempty"`
+	Taints                map[string]string            `yaml:"taints,omitempty"`
+	PrivateNetworking     bool                         `yaml:"privateNetworking,omitempty"`
+	VolumeSize            *int                         `yaml:"volumeSize,omitempty"`
+	VolumeType            string                       `yaml:"volumeType,omitempty"`
+	VolumeEncrypted       *bool                        `yaml:"volumeEncrypted,omitempty"`
+	DesiredCapacity       *int                         `yaml:"desiredCapacity,omitempty"`
+	MinSize               *int                         `yaml:"minSize,omitempty"`
+	MaxSize               *int                         `yaml:"maxSize,omitempty"`
+	SSH                   *EksctlNodeGroupSSH          `yaml:"ssh,omitempty"`
+	IAM                   *EksctlNodeGroupIAM          `yaml:"iam,omitempty"`
+	InstancesDistribution *EksctlInstancesDistribution `yaml:"instancesDistribution,omitempty"`
+}
+
+// EksctlNodeGroupSSH the SSH access configuration of a nodegroup
+type EksctlNodeGroupSSH struct {
+	Allow         bool   `yaml:"allow"`
+	PublicKeyPath string `yaml:"publicKeyPath,omitempty"`
+	PublicKeyName string `yaml:"publicKeyName,omitempty"`
+}
+
+// EksctlNodeGroupIAM the IAM configuration of a nodegroup
+type EksctlNodeGroupIAM struct {
+	WithAddonPolicies EksctlNodeGroupIAMAddonPolicies `yaml:"withAddonPolicies"`
+}
+
+// EksctlNodeGroupIAMAddonPolicies the addon IAM policies attached to a nodegroup
+type EksctlNodeGroupIAMAddonPolicies struct {
+	ImageBuilder bool `yaml:"imageBuilder,omitempty"`
+}
+
+// EksctlInstancesDistribution the mixed instances policy of a nodegroup
+type EksctlInstancesDistribution struct {
+	InstanceTypes                       []string `yaml:"instanceTypes"`
+	MaxPrice                            *float64 `yaml:"maxPrice,omitempty"`
+	OnDemandBaseCapacity                *int     `yaml:"onDemandBaseCapacity,omitempty"`
+	OnDemandPercentageAboveBaseCapacity *int     `yaml:"onDemandPercentageAboveBaseCapacity,omitempty"`
+}
+
+// NewEksctlClusterConfig creates a new eksctl ClusterConfig for the given cluster name and region
+func NewEksctlClusterConfig(name string, region string) *EksctlClusterConfig {
+	return &EksctlClusterConfig{
+		APIVersion: EksctlAPIVersion,
+		Kind:       EksctlClusterConfigKind,
+		Metadata: EksctlClusterMeta{
+			Name:   name,
+			Region: region,
+		},
+	}
+}
+
+// UsesGPUInstances returns true if any of the nodegroups use GPU instance types
+func (c *EksctlClusterConfig) UsesGPUInstances() bool {
+	for _, nodeGroup := range c.NodeGroups {
+		if IsGPUInstanceType(nodeGroup.InstanceType) {
+			return true
+		}
+		if nodeGroup.InstancesDistribution != nil {
+			for _, instanceType := range nodeGroup.InstancesDistribution.InstanceTypes {
+				if IsGPUInstanceType(instanceType) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// NewEksctlClusterVPC creates the VPC configuration for the existing subnets using the given availability zone
+// of each subnet
+func NewEksctlClusterVPC(publicSubnets []string, privateSubnets []string, subnetZones map[string]string) *EksctlClusterVPC {
+	subnetsByZone := func(subnetIDs []string) map[string]EksctlSubnet {
+		if len(subnetIDs) == 0 {
+			return nil
+		}
+		answer := map[string]EksctlSubnet{}
+		for _, id := range subnetIDs {
+			answer[subnetZones[id]] = EksctlSubnet{ID: id}
+		}
+		return answer
+	}
+	return &EksctlClusterVPC{
+		Subnets: EksctlClusterSubnets{
+			Public:  subnetsByZone(publicSubnets),
+			Private: subnetsByZone(privateSubnets),
+		},
+	}
+}
+
+// LoadEksctlClusterConfig loads the eksctl ClusterConfig from the given file name
+func LoadEksctlClusterConfig(fileName string) (*EksctlClusterConfig, error) {
+	exists, err := util.FileExists(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("eksctl config file %s does not exist", fileName)
+	}
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load file %s due to %s", fileName, err)
+	}
+	config := &EksctlClusterConfig{}
+	err = yaml.Unmarshal(data, config)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal YAML file %s due to %s", fileName, err)
+	}
+	if config.Metadata.Name == "" {
+		return nil, fmt.Errorf("eksctl config file %s does not specify metadata.name", fileName)
+	}
+	return config, nil
+}
+
+// SaveEksctlClusterConfig saves the eksctl ClusterConfig to the given file name
+func SaveEksctlClusterConfig(config *EksctlClusterConfig, fileName string) error {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fileName, data, util.DefaultWritePermissions)
+}
+
+// ParseEksctlVersion parses the output of `eksctl version`, which is either just the version or
+// for older releases a version.Info struct containing the GitTag
+func ParseEksctlVersion(output string) (semver.Version, error) {
+	text := strings.TrimSpace(output)
+	if m := eksctlGitTagRegex.FindStringSubmatch(text); m != nil {
+		text = m[1]
+	}
+	if m := eksctlVersionRegex.FindString(text); m != "" {
+		text = m
+	}
+	return semver.ParseTolerant(text)
+}
@@ -0,0 +1,65 @@
+package amazon_test
+
+import (
+	"path"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/cloud/amazon"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadEksctlClusterConfig(t *testing.T) {
+	config, err := amazon.LoadEksctlClusterConfig(path.Join("test_data", "eksctl_cluster.yaml"))
+	assert.Nil(t, err)
+	assert.Equal(t, "my-cluster", config.Metadata.Name)
+	assert.Equal(t, "eu-west-1", config.Metadata.Region)
+	assert.Equal(t, 1, len(config.NodeGroups))
+	assert.Equal(t, "m5.xlarge", config.NodeGroups[0].InstanceType)
+	assert.Equal(t, 3, *config.NodeGroups[0].DesiredCapacity)
+}
+
+func TestLoadMissingEksctlClusterConfig(t *testing.T) {
+	_, err := amazon.LoadEksctlClusterConfig(path.Join("test_data", "does_not_exist.yaml"))
+	assert.NotNil(t, err)
+}
+
+func TestParseEksctlVersion(t *testing.T) {
+	version, err := amazon.ParseEksctlVersion("0.13.0\n")
+	assert.Nil(t, err)
+	assert.Equal(t, "0.13.0", version.String())
+
+	version, err = amazon.ParseEksctlVersion(`[ℹ]  version.Info{BuiltAt:"", GitCommit:"", GitTag:"0.1.3"}`)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.1.3", version.String())
+	assert.True(t, version.LT(amazon.MinEksctlManagedVersion))
+
+	_, err = amazon.ParseEksctlVersion("unknown")
+	assert.NotNil(t, err)
+}
+
+func TestParseEksctlClusterNames(t *testing.T) {
+	names, err := amazon.ParseEksctlClusterNames([]byte(`[{"name": "foo", "region": "us-west-2"}, {"Name": "bar"}, {"metadata": {"name": "baz"}}]`))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"foo", "bar", "baz"}, names)
+
+	names, err = amazon.ParseEksctlClusterNames([]byte(`[]`))
+	assert.Nil(t, err)
+	assert.Empty(t, names)
+}
+
+func TestNewEksctlClusterVPC(t *testing.T) {
+	subnetZones := map[string]string{
+		"subnet-0000000a": "us-west-2a",
+		"subnet-0000000b": "us-west-2b",
+		"subnet-1000000a": "us-west-2a",
+	}
+	vpc := amazon.NewEksctlClusterVPC(nil, []string{"subnet-0000000a", "subnet-0000000b"}, subnetZones)
+	assert.Nil(t, vpc.Subnets.Public)
+	assert.Equal(t, map[string]amazon.EksctlSubnet{
+		"us-west-2a": {ID: "subnet-0000000a"},
+		"us-west-2b": {ID: "subnet-0000000b"},
+	}, vpc.Subnets.Private)
+
+	vpc = amazon.NewEksctlClusterVPC([]string{"subnet-1000000a"}, nil, subnetZones)
+	assert.Equal(t, "subnet-1000000a", vpc.Subnets.Public["us-west-2a"].ID)
+}
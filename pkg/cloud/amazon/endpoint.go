@@ -0,0 +1,138 @@
+package amazon
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/eks"
+)
+
+const (
+	// EKSUpdateStatusSuccessful the status of an EKS cluster update which completed
+	EKSUpdateStatusSuccessful = "Successful"
+)
+
+// EKSUpdatePollInterval how often the status of an EKS cluster update is polled
+var EKSUpdatePollInterval = 15 * time.Second
+
+// DisableEKSPublicEndpoint restricts the API server endpoint of the EKS cluster to the VPC by enabling the private
+// endpoint and disabling the public one, waiting up to the timeout for the update to complete
+func DisableEKSPublicEndpoint(profile string, region string, clusterName string, timeout time.Duration) error {
+	sess, err := NewAwsSession(profile, region)
+	if err != nil {
+		return err
+	}
+	svc := eks.New(sess)
+	input := &updateClusterConfigInput{
+		Name: aws.String(clusterName),
+		ResourcesVpcConfig: &endpointAccessConfig{
+			EndpointPrivateAccess: aws.Bool(true),
+			EndpointPublicAccess:  aws.Bool(false),
+		},
+	}
+	output := &updateClusterConfigOutput{}
+	err = svc.NewRequest(&request.Operation{
+		Name:       "UpdateClusterConfig",
+		HTTPMethod: "POST",
+		HTTPPath:   "/clusters/{name}/update-config",
+	}, input, output).Send()
+	if err != nil {
+		return err
+	}
+	if output.Update == nil {
+		return nil
+	}
+	updateID := aws.StringValue(output.Update.ID)
+	deadline := time.Now().Add(timeout)
+	for {
+		describeOutput := &describeUpdateOutput{}
+		err = svc.NewRequest(&request.Operation{
+			Name:       "DescribeUpdate",
+			HTTPMethod: "GET",
+			HTTPPath:   "/clusters/{name}/updates/{updateId}",
+		}, &describeUpdateInput{Name: aws.String(clusterName), UpdateID: aws.String(updateID)}, describeOutput).Send()
+		if err != nil {
+			return err
+		}
+		status := ""
+		if describeOutput.Update != nil {
+			status = aws.StringValue(describeOutput.Update.Status)
+		}
+		switch status {
+		case EKSUpdateStatusSuccessful:
+			return nil
+		case "Failed", "Cancelled":
+			return fmt.Errorf("update %s of EKS cluster %s to disable the public endpoint is %s", updateID, clusterName, status)
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %s waiting for update %s of EKS cluster %s to disable the public endpoint", timeout, updateID, clusterName)
+		}
+		time.Sleep(EKSUpdatePollInterval)
+	}
+}
+
+// EndpointReachable returns true if a TCP connection can be made to the host of the API server endpoint URL within
+// the timeout. The private endpoint of an EKS cluster can only be reached from inside its VPC or connected networks
+func EndpointReachable(endpoint string, timeout time.Duration) bool {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "443")
+	}
+	conn, err := net.DialTimeout("tcp", host, timeout)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// updateClusterConfigInput the input of the EKS UpdateClusterConfig operation which is not part of the vendored
+// version of the AWS SDK
+type updateClusterConfigInput struct {
+	_ struct{} `type:"structure"`
+
+	Name               *string               `location:"uri" locationName:"name" type:"string" required:"true"`
+	ResourcesVpcConfig *endpointAccessConfig `locationName:"resourcesVpcConfig" type:"structure"`
+}
+
+type endpointAccessConfig struct {
+	_ struct{} `type:"structure"`
+
+	EndpointPrivateAccess *bool `locationName:"endpointPrivateAccess" type:"boolean"`
+	EndpointPublicAccess  *bool `locationName:"endpointPublicAccess" type:"boolean"`
+}
+
+type updateClusterConfigOutput struct {
+	_ struct{} `type:"structure"`
+
+	Update *clusterUpdate `locationName:"update" type:"structure"`
+}
+
+// describeUpdateInput the input of the EKS DescribeUpdate operation
+type describeUpdateInput struct {
+	_ struct{} `type:"structure"`
+
+	Name     *string `location:"uri" locationName:"name" type:"string" required:"true"`
+	UpdateID *string `location:"uri" locationName:"updateId" type:"string" required:"true"`
+}
+
+type describeUpdateOutput struct {
+	_ struct{} `type:"structure"`
+
+	Update *clusterUpdate `locationName:"update" type:"structure"`
+}
+
+type clusterUpdate struct {
+	_ struct{} `type:"structure"`
+
+	ID     *string `locationName:"id" type:"string"`
+	Status *string `locationName:"status" type:"string"`
+}
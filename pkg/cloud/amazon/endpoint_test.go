@@ -0,0 +1,28 @@
+package amazon_test
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/cloud/amazon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointReachable(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+	assert.True(t, amazon.EndpointReachable(server.URL, time.Second))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedURL := "https://" + listener.Addr().String()
+	listener.Close()
+	assert.False(t, amazon.EndpointReachable(closedURL, time.Second))
+
+	assert.False(t, amazon.EndpointReachable("", time.Second))
+}
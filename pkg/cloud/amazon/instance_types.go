@@ -0,0 +1,144 @@
+package amazon
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+var (
+	instanceTypeRegex = regexp.MustCompile(`^([a-z][a-z0-9-]*)\.([0-9]*xlarge|nano|micro|small|medium|large|metal)$`)
+
+	// KnownInstanceFamilies the EC2 instance families used to validate instance types when the instance types offered
+	// in a region cannot be described
+	KnownInstanceFamilies = []string{
+		"a1", "c1", "c3", "c4", "c5", "c5d", "c5n", "cc2", "d2", "f1", "g2", "g3", "g3s", "g4", "g4dn", "h1", "i2",
+		"i3", "i3en", "inf1", "m1", "m2", "m3", "m4", "m5", "m5a", "m5ad", "m5d", "m5dn", "m5n", "p2", "p3", "p3dn",
+		"r3", "r4", "r5", "r5a", "r5ad", "r5d", "r5dn", "r5n", "t1", "t2", "t3", "t3a", "x1", "x1e", "z1d",
+	}
+
+	instanceSizes = []string{"nano", "micro", "small", "medium", "large", "xlarge", "2xlarge", "4xlarge", "8xlarge",
+		"9xlarge", "12xlarge", "16xlarge", "18xlarge", "24xlarge", "32xlarge", "metal"}
+)
+
+// ValidateInstanceType returns an error if the EC2 instance type is not offered in the region. If the offerings
+// cannot be described, e.g. as there are no AWS credentials, the instance type is only checked against the known
+// instance families
+func ValidateInstanceType(profile string, region string, instanceType string) error {
+	offered, err := RegionInstanceTypes(profile, region)
+	if err != nil {
+		offered = nil
+	}
+	return ValidateOfferedInstanceType(region, instanceType, offered)
+}
+
+// ValidateInstanceTypeSyntax returns an error if the instance type is not of the form <family>.<size> using one of
+// the known instance families
+func ValidateInstanceTypeSyntax(instanceType string) error {
+	m := instanceTypeRegex.FindStringSubmatch(instanceType)
+	if m != nil && util.Contains(KnownInstanceFamilies, m[1]) {
+		return nil
+	}
+	return instanceTypeError(fmt.Sprintf("%s is not a valid EC2 instance type", instanceType), instanceType, knownInstanceTypes())
+}
+
+// ValidateOfferedInstanceType returns an error if the instance type is not one of the instance types offered in the
+// region. If no offered instance types are given the instance type is validated by ValidateInstanceTypeSyntax
+func ValidateOfferedInstanceType(region string, instanceType string, offered []string) error {
+	if len(offered) == 0 {
+		return ValidateInstanceTypeSyntax(instanceType)
+	}
+	if util.Contains(offered, instanceType) {
+		return nil
+	}
+	return instanceTypeError(fmt.Sprintf("instance type %s is not offered in %s", instanceType, region), instanceType, offered)
+}
+
+// RegionInstanceTypes returns the sorted EC2 instance types offered in the region
+func RegionInstanceTypes(profile string, region string) ([]string, error) {
+	sess, err := NewAwsSession(profile, region)
+	if err != nil {
+		return nil, err
+	}
+	svc := ec2.New(sess)
+	input := &describeInstanceTypeOfferingsInput{LocationType: aws.String("region")}
+	answer := []string{}
+	for {
+		output := &describeInstanceTypeOfferingsOutput{}
+		req := svc.NewRequest(&request.Operation{
+			Name:       "DescribeInstanceTypeOfferings",
+			HTTPMethod: "POST",
+			HTTPPath:   "/",
+		}, input, output)
+		err = req.Send()
+		if err != nil {
+			return nil, err
+		}
+		for _, offering := range output.InstanceTypeOfferings {
+			answer = append(answer, aws.StringValue(offering.InstanceType))
+		}
+		if aws.StringValue(output.NextToken) == "" {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+	sort.Strings(answer)
+	return answer, nil
+}
+
+func knownInstanceTypes() []string {
+	answer := []string{}
+	for _, family := range KnownInstanceFamilies {
+		for _, size := range instanceSizes {
+			answer = append(answer, family+"."+size)
+		}
+	}
+	return answer
+}
+
+func instanceTypeError(message string, instanceType string, candidates []string) error {
+	suggestions := util.SuggestionsFor(instanceType, candidates, 1)
+	if len(suggestions) == 0 {
+		suggestions = util.SuggestionsFor(instanceType, candidates, util.DefaultSuggestionsMinimumDistance)
+	}
+	switch {
+	case len(suggestions) == 1:
+		return fmt.Errorf("%s, did you mean %s?", message, suggestions[0])
+	case len(suggestions) > 1:
+		if len(suggestions) > 5 {
+			suggestions = suggestions[:5]
+		}
+		return fmt.Errorf("%s, did you mean one of %s?", message, strings.Join(suggestions, ", "))
+	}
+	return fmt.Errorf("%s", message)
+}
+
+// describeInstanceTypeOfferingsInput the input of the EC2 DescribeInstanceTypeOfferings operation which is not
+// part of the vendored version of the AWS SDK
+type describeInstanceTypeOfferingsInput struct {
+	_ struct{} `type:"structure"`
+
+	LocationType *string `type:"string"`
+	NextToken    *string `type:"string"`
+}
+
+// describeInstanceTypeOfferingsOutput the output of the EC2 DescribeInstanceTypeOfferings operation
+type describeInstanceTypeOfferingsOutput struct {
+	_ struct{} `type:"structure"`
+
+	InstanceTypeOfferings []*instanceTypeOffering `locationName:"instanceTypeOfferingSet" locationNameList:"item" type:"list"`
+	NextToken             *string                 `locationName:"nextToken" type:"string"`
+}
+
+type instanceTypeOffering struct {
+	_ struct{} `type:"structure"`
+
+	InstanceType *string `locationName:"instanceType" type:"string"`
+	Location     *string `locationName:"location" type:"string"`
+}
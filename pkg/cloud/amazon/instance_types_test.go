@@ -0,0 +1,36 @@
+package amazon_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/cloud/amazon"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateInstanceTypeSyntax(t *testing.T) {
+	for _, instanceType := range []string{"m5.large", "t3a.2xlarge", "p3dn.24xlarge", "i3.metal", "a1.medium"} {
+		assert.Nil(t, amazon.ValidateInstanceTypeSyntax(instanceType), "instance type %s should be valid", instanceType)
+	}
+
+	err := amazon.ValidateInstanceTypeSyntax("m5,large")
+	assert.EqualError(t, err, "m5,large is not a valid EC2 instance type, did you mean m5.large?")
+
+	for _, instanceType := range []string{"", "m5", "large", "zz9.large", "m5.huge", "M5.large"} {
+		assert.NotNil(t, amazon.ValidateInstanceTypeSyntax(instanceType), "instance type %q should be invalid", instanceType)
+	}
+}
+
+func TestValidateOfferedInstanceType(t *testing.T) {
+	offered := []string{"c5.large", "m5.large", "m5.xlarge", "t3.large"}
+	assert.Nil(t, amazon.ValidateOfferedInstanceType("us-west-1", "m5.xlarge", offered))
+
+	err := amazon.ValidateOfferedInstanceType("us-west-1", "a1.large", offered)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "instance type a1.large is not offered in us-west-1")
+
+	err = amazon.ValidateOfferedInstanceType("us-west-1", "m5.lrage", offered)
+	assert.EqualError(t, err, "instance type m5.lrage is not offered in us-west-1, did you mean m5.large?")
+
+	assert.Nil(t, amazon.ValidateOfferedInstanceType("us-west-1", "a1.large", nil), "the syntax should be checked if the offerings are unknown")
+	assert.NotNil(t, amazon.ValidateOfferedInstanceType("us-west-1", "m5,large", nil))
+}
@@ -0,0 +1,98 @@
+package amazon
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// DescribeEKSCluster returns the EKS cluster of the given name
+func DescribeEKSCluster(profile string, region string, clusterName string) (*eks.Cluster, error) {
+	sess, err := NewAwsSession(profile, region)
+	if err != nil {
+		return nil, err
+	}
+	output, err := eks.New(sess).DescribeCluster(&eks.DescribeClusterInput{Name: aws.String(clusterName)})
+	if err != nil {
+		return nil, err
+	}
+	return output.Cluster, nil
+}
+
+// UpdateKubeConfig adds the EKS cluster to the kube config file and makes it the current context in the same way as
+// `aws eks update-kubeconfig`, returning the name of the context. If a role ARN is given the user of the context
+// assumes the role
+func UpdateKubeConfig(profile string, region string, clusterName string, roleArn string) (string, error) {
+	cluster, err := DescribeEKSCluster(profile, region, clusterName)
+	if err != nil {
+		return "", err
+	}
+	status := aws.StringValue(cluster.Status)
+	if status != eks.ClusterStatusActive {
+		return "", fmt.Errorf("EKS cluster %s is %s rather than %s", clusterName, status, eks.ClusterStatusActive)
+	}
+	config, po, err := kube.LoadConfig()
+	if err != nil {
+		return "", err
+	}
+	contextName, err := AddEKSClusterToConfig(config, cluster, profile, roleArn)
+	if err != nil {
+		return "", err
+	}
+	return contextName, clientcmd.ModifyConfig(po, *config, false)
+}
+
+// AddEKSClusterToConfig adds the cluster, a user authenticating via aws-iam-authenticator and a context for them
+// to the kube config, making the context the current one. The entries are named after the ARN of the cluster. The
+// user assumes the role of the given ARN, if any, with the credentials of the profile
+func AddEKSClusterToConfig(config *api.Config, cluster *eks.Cluster, profile string, roleArn string) (string, error) {
+	name := aws.StringValue(cluster.Arn)
+	caData := []byte{}
+	if cluster.CertificateAuthority != nil {
+		data, err := base64.StdEncoding.DecodeString(aws.StringValue(cluster.CertificateAuthority.Data))
+		if err != nil {
+			return "", fmt.Errorf("failed to decode the certificate authority of EKS cluster %s: %s", name, err)
+		}
+		caData = data
+	}
+
+	exec := &api.ExecConfig{
+		APIVersion: "client.authentication.k8s.io/v1alpha1",
+		Command:    "aws-iam-authenticator",
+		Args:       []string{"token", "-i", aws.StringValue(cluster.Name)},
+	}
+	if roleArn != "" {
+		exec.Args = append(exec.Args, "-r", roleArn)
+	}
+	if profile != "" {
+		exec.Env = []api.ExecEnvVar{{Name: "AWS_PROFILE", Value: profile}}
+	}
+
+	if config.Clusters == nil {
+		config.Clusters = map[string]*api.Cluster{}
+	}
+	if config.AuthInfos == nil {
+		config.AuthInfos = map[string]*api.AuthInfo{}
+	}
+	if config.Contexts == nil {
+		config.Contexts = map[string]*api.Context{}
+	}
+	config.Clusters[name] = &api.Cluster{
+		Server:                   aws.StringValue(cluster.Endpoint),
+		CertificateAuthorityData: caData,
+	}
+	config.AuthInfos[name] = &api.AuthInfo{
+		Exec: exec,
+	}
+	config.Contexts[name] = &api.Context{
+		Cluster:  name,
+		AuthInfo: name,
+	}
+	config.CurrentContext = name
+	return name, nil
+}
@@ -0,0 +1,55 @@
+package amazon_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/jenkins-x/jx/pkg/cloud/amazon"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestAddEKSClusterToConfig(t *testing.T) {
+	arn := "arn:aws:eks:eu-west-1:123456789012:cluster/mycluster"
+	cluster := &eks.Cluster{
+		Arn:      aws.String(arn),
+		Name:     aws.String("mycluster"),
+		Endpoint: aws.String("https://ABCDEF.yl4.eu-west-1.eks.amazonaws.com"),
+		CertificateAuthority: &eks.Certificate{
+			Data: aws.String(base64.StdEncoding.EncodeToString([]byte("ca-data"))),
+		},
+	}
+	config := api.NewConfig()
+	config.CurrentContext = "minikube"
+
+	contextName, err := amazon.AddEKSClusterToConfig(config, cluster, "ci", "")
+	assert.Nil(t, err)
+	assert.Equal(t, arn, contextName)
+	assert.Equal(t, arn, config.CurrentContext)
+	assert.Equal(t, "https://ABCDEF.yl4.eu-west-1.eks.amazonaws.com", config.Clusters[arn].Server)
+	assert.Equal(t, []byte("ca-data"), config.Clusters[arn].CertificateAuthorityData)
+
+	exec := config.AuthInfos[arn].Exec
+	assert.Equal(t, "aws-iam-authenticator", exec.Command)
+	assert.Equal(t, []string{"token", "-i", "mycluster"}, exec.Args)
+	assert.Equal(t, []api.ExecEnvVar{{Name: "AWS_PROFILE", Value: "ci"}}, exec.Env)
+}
+
+func TestAddEKSClusterToConfigWithAssumedRole(t *testing.T) {
+	arn := "arn:aws:eks:eu-west-1:123456789012:cluster/mycluster"
+	cluster := &eks.Cluster{
+		Arn:      aws.String(arn),
+		Name:     aws.String("mycluster"),
+		Endpoint: aws.String("https://ABCDEF.yl4.eu-west-1.eks.amazonaws.com"),
+	}
+	config := api.NewConfig()
+
+	_, err := amazon.AddEKSClusterToConfig(config, cluster, "ci", "arn:aws:iam::123456789012:role/jx-installer")
+	assert.Nil(t, err)
+	exec := config.AuthInfos[arn].Exec
+	assert.Equal(t, []string{"token", "-i", "mycluster", "-r", "arn:aws:iam::123456789012:role/jx-installer"}, exec.Args,
+		"the user of the cluster should assume the role rather than use the profile")
+	assert.Equal(t, []api.ExecEnvVar{{Name: "AWS_PROFILE", Value: "ci"}}, exec.Env)
+}
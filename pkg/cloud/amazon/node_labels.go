@@ -0,0 +1,59 @@
+package amazon
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TaintEffects the effects a node taint can have
+var TaintEffects = []string{"NoSchedule", "PreferNoSchedule", "NoExecute"}
+
+// ParseNodeLabels parses a comma separated list of key=value node labels returning an error listing any malformed
+// entries
+func ParseNodeLabels(text string) (map[string]string, error) {
+	return parseKeyValues(text, "label")
+}
+
+// ParseNodeTaints parses a comma separated list of key=value:Effect node taints, where the value is optional,
+// returning the taints in the eksctl config format of a map of key to value:Effect
+func ParseNodeTaints(text string) (map[string]string, error) {
+	taints := map[string]string{}
+	invalid := []string{}
+	for _, token := range strings.Split(text, ",") {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+		idx := strings.LastIndex(token, ":")
+		if idx < 0 {
+			invalid = append(invalid, token)
+			continue
+		}
+		effect := token[idx+1:]
+		key := token[:idx]
+		value := ""
+		if parts := strings.SplitN(key, "=", 2); len(parts) == 2 {
+			key = parts[0]
+			value = parts[1]
+		}
+		if strings.TrimSpace(key) == "" {
+			invalid = append(invalid, token)
+			continue
+		}
+		validEffect := false
+		for _, e := range TaintEffects {
+			if effect == e {
+				validEffect = true
+				break
+			}
+		}
+		if !validEffect {
+			return nil, fmt.Errorf("invalid effect %s of taint %s, the effect must be one of %s", effect, token, strings.Join(TaintEffects, ", "))
+		}
+		taints[strings.TrimSpace(key)] = strings.TrimSpace(value) + ":" + effect
+	}
+	if len(invalid) > 0 {
+		return nil, fmt.Errorf("invalid taints %s, taints must be of the form key=value:Effect", strings.Join(invalid, ", "))
+	}
+	return taints, nil
+}
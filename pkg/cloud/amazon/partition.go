@@ -0,0 +1,61 @@
+package amazon
+
+import (
+	"strings"
+)
+
+const (
+	// PartitionAWS the standard commercial AWS partition
+	PartitionAWS = "aws"
+	// PartitionChina the AWS China partition of the cn-* regions
+	PartitionChina = "aws-cn"
+	// PartitionUSGov the AWS GovCloud (US) partition of the us-gov-* regions
+	PartitionUSGov = "aws-us-gov"
+)
+
+// RegionPartition returns the partition of the region based on its prefix
+func RegionPartition(region string) string {
+	switch {
+	case strings.HasPrefix(region, "cn-"):
+		return PartitionChina
+	case strings.HasPrefix(region, "us-gov-"):
+		return PartitionUSGov
+	default:
+		return PartitionAWS
+	}
+}
+
+// RegionDNSSuffix returns the DNS suffix of the endpoints of the region's partition
+func RegionDNSSuffix(region string) string {
+	if RegionPartition(region) == PartitionChina {
+		return "amazonaws.com.cn"
+	}
+	return "amazonaws.com"
+}
+
+// ConsoleURL returns the URL of the AWS console of the region's partition
+func ConsoleURL(region string) string {
+	switch RegionPartition(region) {
+	case PartitionChina:
+		return "https://console.amazonaws.cn"
+	case PartitionUSGov:
+		return "https://console.amazonaws-us-gov.com"
+	default:
+		return "https://console.aws.amazon.com"
+	}
+}
+
+// CloudFormationConsoleURL returns the URL of the CloudFormation console of the region
+func CloudFormationConsoleURL(region string) string {
+	url := ConsoleURL(region) + "/cloudformation/home"
+	if region != "" {
+		url += "?region=" + region
+	}
+	return url
+}
+
+// IsECRRegistry returns true if the docker registry host is an ECR registry in any partition
+func IsECRRegistry(dockerRegistry string) bool {
+	return strings.Index(dockerRegistry, ".ecr.") > 0 &&
+		(strings.HasSuffix(dockerRegistry, ".amazonaws.com") || strings.HasSuffix(dockerRegistry, ".amazonaws.com.cn"))
+}
@@ -0,0 +1,38 @@
+package amazon_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/cloud/amazon"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegionPartition(t *testing.T) {
+	assert.Equal(t, amazon.PartitionAWS, amazon.RegionPartition("eu-west-1"))
+	assert.Equal(t, amazon.PartitionAWS, amazon.RegionPartition(""))
+	assert.Equal(t, amazon.PartitionChina, amazon.RegionPartition("cn-north-1"))
+	assert.Equal(t, amazon.PartitionChina, amazon.RegionPartition("cn-northwest-1"))
+	assert.Equal(t, amazon.PartitionUSGov, amazon.RegionPartition("us-gov-west-1"))
+	assert.Equal(t, amazon.PartitionUSGov, amazon.RegionPartition("us-gov-east-1"))
+}
+
+func TestCloudFormationConsoleURL(t *testing.T) {
+	assert.Equal(t, "https://console.aws.amazon.com/cloudformation/home?region=eu-west-1", amazon.CloudFormationConsoleURL("eu-west-1"))
+	assert.Equal(t, "https://console.amazonaws.cn/cloudformation/home?region=cn-north-1", amazon.CloudFormationConsoleURL("cn-north-1"))
+	assert.Equal(t, "https://console.amazonaws-us-gov.com/cloudformation/home?region=us-gov-west-1", amazon.CloudFormationConsoleURL("us-gov-west-1"))
+}
+
+func TestIsECRRegistry(t *testing.T) {
+	assert.True(t, amazon.IsECRRegistry("123456789012.dkr.ecr.eu-west-1.amazonaws.com"))
+	assert.True(t, amazon.IsECRRegistry("123456789012.dkr.ecr.cn-north-1.amazonaws.com.cn"))
+	assert.True(t, amazon.IsECRRegistry("123456789012.dkr.ecr.us-gov-west-1.amazonaws.com"))
+	assert.False(t, amazon.IsECRRegistry("docker.io"))
+	assert.False(t, amazon.IsECRRegistry("s3.amazonaws.com"))
+}
+
+func TestRegionsIncludesAllPartitions(t *testing.T) {
+	regions := amazon.Regions()
+	assert.Contains(t, regions, "eu-west-1")
+	assert.Contains(t, regions, "cn-north-1")
+	assert.Contains(t, regions, "us-gov-west-1")
+}
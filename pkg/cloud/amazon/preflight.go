@@ -0,0 +1,183 @@
+package amazon
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+const (
+	// PreflightPassed the check passed
+	PreflightPassed = "PASS"
+	// PreflightFailed the check failed so creating the cluster will fail
+	PreflightFailed = "FAIL"
+	// PreflightWarning the check could not be completed
+	PreflightWarning = "WARN"
+
+	// DefaultVpcQuota the default number of VPCs per region of an AWS account, used if the quota of the account cannot
+	// be read from Service Quotas
+	DefaultVpcQuota = 5
+)
+
+// EKSRequiredActions the IAM actions eksctl needs to be able to create an EKS cluster
+var EKSRequiredActions = []string{
+	"eks:CreateCluster",
+	"eks:DescribeCluster",
+	"cloudformation:CreateStack",
+	"cloudformation:DescribeStacks",
+	"cloudformation:DescribeStackEvents",
+	"iam:CreateRole",
+	"iam:AttachRolePolicy",
+	"iam:PutRolePolicy",
+	"iam:PassRole",
+	"iam:CreateInstanceProfile",
+	"iam:AddRoleToInstanceProfile",
+	"ec2:CreateVpc",
+	"ec2:CreateSubnet",
+	"ec2:AllocateAddress",
+	"ec2:CreateNatGateway",
+	"ec2:CreateSecurityGroup",
+	"ec2:RunInstances",
+	"autoscaling:CreateAutoScalingGroup",
+	"autoscaling:CreateLaunchConfiguration",
+}
+
+// PreflightCheck the result of a check made before creating a cluster
+type PreflightCheck struct {
+	Name    string
+	Status  string
+	Message string
+}
+
+// Failed returns true if the check failed
+func (c *PreflightCheck) Failed() bool {
+	return c.Status == PreflightFailed
+}
+
+// EKSPreflightChecks verifies the credentials, IAM permissions and service quotas needed to create an EKS cluster. The
+// VPC quota is only checked if eksctl creates a VPC rather than using the subnets of an existing one
+func EKSPreflightChecks(profile string, region string, createsVpc bool) ([]*PreflightCheck, error) {
+	sess, err := NewAwsSession(profile, region)
+	if err != nil {
+		return nil, err
+	}
+	checks := []*PreflightCheck{}
+
+	identity, err := sts.New(sess).GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		checks = append(checks, &PreflightCheck{"Credentials", PreflightFailed, err.Error()})
+		return checks, nil
+	}
+	callerArn := aws.StringValue(identity.Arn)
+	checks = append(checks, &PreflightCheck{"Credentials", PreflightPassed, callerArn})
+	checks = append(checks, permissionsCheck(sess, callerArn))
+	checks = append(checks, elasticIPQuotaCheck(sess))
+	if createsVpc {
+		checks = append(checks, vpcQuotaCheck(sess))
+	}
+	return checks, nil
+}
+
+func permissionsCheck(sess *session.Session, callerArn string) *PreflightCheck {
+	name := "IAM permissions"
+	principalArn := IAMPrincipalARN(callerArn)
+	if strings.HasSuffix(principalArn, ":root") {
+		return &PreflightCheck{name, PreflightPassed, "the account root user has all permissions"}
+	}
+	input := &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String(principalArn),
+		ActionNames:     aws.StringSlice(EKSRequiredActions),
+	}
+	missing := []string{}
+	err := iam.New(sess).SimulatePrincipalPolicyPages(input, func(page *iam.SimulatePolicyResponse, lastPage bool) bool {
+		for _, result := range page.EvaluationResults {
+			if aws.StringValue(result.EvalDecision) != iam.PolicyEvaluationDecisionTypeAllowed {
+				missing = append(missing, aws.StringValue(result.EvalActionName))
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return &PreflightCheck{name, PreflightWarning, fmt.Sprintf("could not simulate the IAM policies of %s: %s", principalArn, err)}
+	}
+	if len(missing) > 0 {
+		return &PreflightCheck{name, PreflightFailed, "missing permissions: " + strings.Join(missing, ", ")}
+	}
+	return &PreflightCheck{name, PreflightPassed, fmt.Sprintf("%d actions allowed", len(EKSRequiredActions))}
+}
+
+func elasticIPQuotaCheck(sess *session.Session) *PreflightCheck {
+	name := "Elastic IP quota"
+	svc := ec2.New(sess)
+	attributes, err := svc.DescribeAccountAttributes(&ec2.DescribeAccountAttributesInput{
+		AttributeNames: aws.StringSlice([]string{"vpc-max-elastic-ips"}),
+	})
+	if err != nil {
+		return &PreflightCheck{name, PreflightWarning, err.Error()}
+	}
+	quota := -1
+	for _, attribute := range attributes.AccountAttributes {
+		for _, value := range attribute.AttributeValues {
+			quota, err = strconv.Atoi(aws.StringValue(value.AttributeValue))
+			if err != nil {
+				return &PreflightCheck{name, PreflightWarning, err.Error()}
+			}
+		}
+	}
+	if quota < 0 {
+		return &PreflightCheck{name, PreflightWarning, "could not find the Elastic IP quota of the account"}
+	}
+	addresses, err := svc.DescribeAddresses(&ec2.DescribeAddressesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("domain"),
+				Values: aws.StringSlice([]string{ec2.DomainTypeVpc}),
+			},
+		},
+	})
+	if err != nil {
+		return &PreflightCheck{name, PreflightWarning, err.Error()}
+	}
+	return quotaCheck(name, len(addresses.Addresses), quota)
+}
+
+func vpcQuotaCheck(sess *session.Session) *PreflightCheck {
+	name := "VPC quota"
+	vpcs, err := ec2.New(sess).DescribeVpcs(&ec2.DescribeVpcsInput{})
+	if err != nil {
+		return &PreflightCheck{name, PreflightWarning, err.Error()}
+	}
+	quota, err := GetServiceQuota(sess, VpcServiceCode, VpcsPerRegionQuotaCode)
+	if err != nil {
+		check := quotaCheck(name, len(vpcs.Vpcs), DefaultVpcQuota)
+		check.Message += fmt.Sprintf(" (default quota as the quota of the account could not be read: %s)", err)
+		return check
+	}
+	return quotaCheck(name, len(vpcs.Vpcs), quota)
+}
+
+func quotaCheck(name string, used int, quota int) *PreflightCheck {
+	message := fmt.Sprintf("%d of %d used", used, quota)
+	if used >= quota {
+		return &PreflightCheck{name, PreflightFailed, message}
+	}
+	return &PreflightCheck{name, PreflightPassed, message}
+}
+
+// IAMPrincipalARN converts the ARN returned by STS GetCallerIdentity into the ARN of the IAM user or role
+// which can be used to simulate its policies
+func IAMPrincipalARN(callerArn string) string {
+	// arn:aws:sts::123456789012:assumed-role/role-name/session-name
+	parts := strings.SplitN(callerArn, ":", 6)
+	if len(parts) < 6 || parts[2] != "sts" || !strings.HasPrefix(parts[5], "assumed-role/") {
+		return callerArn
+	}
+	names := strings.Split(parts[5], "/")
+	return strings.Join([]string{parts[0], parts[1], "iam", "", parts[4], "role/" + names[1]}, ":")
+}
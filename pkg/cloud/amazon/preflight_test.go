@@ -0,0 +1,17 @@
+package amazon_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/cloud/amazon"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIAMPrincipalARN(t *testing.T) {
+	assert.Equal(t, "arn:aws:iam::123456789012:role/admin",
+		amazon.IAMPrincipalARN("arn:aws:sts::123456789012:assumed-role/admin/jenkins"))
+	assert.Equal(t, "arn:aws:iam::123456789012:user/jenkins",
+		amazon.IAMPrincipalARN("arn:aws:iam::123456789012:user/jenkins"))
+	assert.Equal(t, "arn:aws:iam::123456789012:root",
+		amazon.IAMPrincipalARN("arn:aws:iam::123456789012:root"))
+}
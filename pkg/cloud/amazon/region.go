@@ -0,0 +1,90 @@
+package amazon
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/go-ini/ini"
+)
+
+var (
+	// EC2MetadataEndpoint the endpoint of the EC2 instance metadata service used to find the region
+	EC2MetadataEndpoint = "http://169.254.169.254/latest"
+
+	// EC2MetadataTimeout how long to wait for the EC2 instance metadata service which is only available on EC2
+	EC2MetadataTimeout = time.Second
+)
+
+// ResolveRegionWithProfile resolves the region using, in order: the AWS_REGION and AWS_DEFAULT_REGION environment
+// variables, the region of the profile in the AWS config file, the EC2 instance metadata and finally DefaultRegion.
+// If the profile is blank the AWS_PROFILE environment variable or the default profile is used
+func ResolveRegionWithProfile(profile string) (string, error) {
+	for _, name := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		region := os.Getenv(name)
+		if region != "" {
+			return region, nil
+		}
+	}
+	region, err := profileRegion(profile)
+	if err != nil {
+		return "", err
+	}
+	if region != "" {
+		return region, nil
+	}
+	region = ec2MetadataRegion()
+	if region != "" {
+		return region, nil
+	}
+	return DefaultRegion, nil
+}
+
+// profileRegion returns the region of the profile in the AWS config file or blank if there is none
+func profileRegion(profile string) (string, error) {
+	if profile == "" {
+		profile = os.Getenv("AWS_PROFILE")
+	}
+	sectionName := "default"
+	if profile != "" && profile != "default" {
+		sectionName = "profile " + profile
+	}
+	fileName := os.Getenv("AWS_CONFIG_FILE")
+	if fileName == "" {
+		fileName = filepath.Join(os.Getenv("HOME"), ".aws", "config")
+	}
+	if _, err := os.Stat(fileName); os.IsNotExist(err) {
+		return "", nil
+	}
+	config, err := ini.Load(fileName)
+	if err != nil {
+		return "", err
+	}
+	section, err := config.GetSection(sectionName)
+	if err != nil {
+		return "", nil
+	}
+	return section.Key("region").String(), nil
+}
+
+// ec2MetadataRegion returns the region of the EC2 instance or blank if not running on EC2
+func ec2MetadataRegion() string {
+	sess, err := session.NewSession()
+	if err != nil {
+		return ""
+	}
+	config := aws.NewConfig().
+		WithEndpoint(EC2MetadataEndpoint).
+		WithHTTPClient(&http.Client{Timeout: EC2MetadataTimeout}).
+		WithMaxRetries(0)
+	zone, err := ec2metadata.New(sess, config).GetMetadata("placement/availability-zone")
+	if err != nil || len(zone) < 2 {
+		return ""
+	}
+	// the region is the availability zone without its letter suffix
+	return zone[:len(zone)-1]
+}
@@ -0,0 +1,105 @@
+package amazon_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/cloud/amazon"
+	"github.com/stretchr/testify/assert"
+)
+
+func fakeAwsConfig(t *testing.T) func() {
+	dir, err := ioutil.TempDir("", "jx-aws-config-")
+	assert.Nil(t, err)
+	fileName := filepath.Join(dir, "config")
+	err = ioutil.WriteFile(fileName, []byte(`[default]
+region = default-region
+[profile foo]
+region = foo-region
+[profile noregion]
+output = json
+`), 0644)
+	assert.Nil(t, err)
+
+	oldConfigFile := os.Getenv("AWS_CONFIG_FILE")
+	os.Setenv("AWS_CONFIG_FILE", fileName)
+	configureEnv("", "", "")
+	return func() {
+		os.Setenv("AWS_CONFIG_FILE", oldConfigFile)
+		os.RemoveAll(dir)
+	}
+}
+
+func fakeEC2Metadata(t *testing.T, zone string) func() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/latest/meta-data/placement/availability-zone" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(zone))
+	}))
+	oldEndpoint := amazon.EC2MetadataEndpoint
+	amazon.EC2MetadataEndpoint = server.URL + "/latest"
+	return func() {
+		amazon.EC2MetadataEndpoint = oldEndpoint
+		server.Close()
+	}
+}
+
+func TestResolveRegionPrefersOptionAndEnv(t *testing.T) {
+	defer fakeAwsConfig(t)()
+	defer fakeEC2Metadata(t, "eu-central-1b")()
+
+	region, err := amazon.ResolveRegion("foo", "someRegion")
+	assert.Nil(t, err)
+	assert.Equal(t, "someRegion", region)
+
+	configureEnv("", "us-east-2", "")
+	region, err = amazon.ResolveRegion("foo", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "us-east-2", region)
+
+	configureEnv("us-east-1", "us-east-2", "")
+	region, err = amazon.ResolveRegion("foo", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "us-east-1", region)
+	configureEnv("", "", "")
+}
+
+func TestResolveRegionFromProfileConfig(t *testing.T) {
+	defer fakeAwsConfig(t)()
+	defer fakeEC2Metadata(t, "eu-central-1b")()
+
+	region, err := amazon.ResolveRegionWithProfile("foo")
+	assert.Nil(t, err)
+	assert.Equal(t, "foo-region", region, "the region of the profile option should be used rather than the default profile")
+
+	region, err = amazon.ResolveRegionWithProfile("")
+	assert.Nil(t, err)
+	assert.Equal(t, "default-region", region)
+
+	configureEnv("", "", "foo")
+	region, err = amazon.ResolveRegionWithProfile("")
+	assert.Nil(t, err)
+	assert.Equal(t, "foo-region", region)
+	configureEnv("", "", "")
+}
+
+func TestResolveRegionFromEC2Metadata(t *testing.T) {
+	defer fakeAwsConfig(t)()
+	defer fakeEC2Metadata(t, "eu-central-1b")()
+
+	region, err := amazon.ResolveRegionWithProfile("noregion")
+	assert.Nil(t, err)
+	assert.Equal(t, "eu-central-1", region)
+
+	// when not running on EC2 the default region is used
+	defer fakeEC2Metadata(t, "")()
+	region, err = amazon.ResolveRegionWithProfile("noregion")
+	assert.Nil(t, err)
+	assert.Equal(t, amazon.DefaultRegion, region)
+}
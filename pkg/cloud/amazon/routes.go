@@ -2,6 +2,7 @@ package amazon
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/route53"
@@ -19,45 +20,11 @@ func RegisterAwsCustomDomain(customDomain string, elbAddress string) error {
 	}
 	svc := route53.New(sess)
 
-	// find the hosted zone for the domain name
-	var hostedZoneId *string
-	listZonesInput := &route53.ListHostedZonesInput{}
-	err = svc.ListHostedZonesPages(listZonesInput, func(page *route53.ListHostedZonesOutput, hasNext bool) bool {
-		if page != nil {
-			for _, r := range page.HostedZones {
-				if r != nil && r.Name != nil && (*r.Name == customDomain || *r.Name == customDomain+".") {
-					hostedZoneId = r.Id
-					return false
-				}
-			}
-		}
-		return true
-	})
+	hostedZoneId, err := ensureHostedZone(svc, customDomain)
 	if err != nil {
 		return err
 	}
 
-	if hostedZoneId == nil {
-		// lets create the hosted zone!
-		callerRef := string(uuid.NewUUID())
-		createInput := &route53.CreateHostedZoneInput{
-			Name:            aws.String(customDomain),
-			CallerReference: aws.String(callerRef),
-		}
-		results, err := svc.CreateHostedZone(createInput)
-		if err != nil {
-			return err
-		}
-		if results.HostedZone == nil {
-			return fmt.Errorf("No HostedZone created for name %s!", customDomain)
-		}
-
-		hostedZoneId = results.HostedZone.Id
-		if hostedZoneId == nil {
-			return fmt.Errorf("No HostedZone ID created for name %s!", customDomain)
-		}
-	}
-
 	upsert := route53.ChangeActionUpsert
 	ttl := int64(300)
 	recordType := "CNAME"
@@ -92,3 +59,69 @@ func RegisterAwsCustomDomain(customDomain string, elbAddress string) error {
 	log.Infof("Updated HostZone ID %s successfully\n", info(*hostedZoneId))
 	return nil
 }
+
+// EnsureHostedZone returns the ID of the Route53 hosted zone of the domain, creating the zone if it does not exist
+// yet in which case the name servers of the domain need to be delegated to the zone
+func EnsureHostedZone(domain string) (string, error) {
+	sess, err := NewAwsSessionWithoutOptions()
+	if err != nil {
+		return "", err
+	}
+	hostedZoneId, err := ensureHostedZone(route53.New(sess), domain)
+	if err != nil {
+		return "", err
+	}
+	return *hostedZoneId, nil
+}
+
+func ensureHostedZone(svc *route53.Route53, domain string) (*string, error) {
+	// find the hosted zone for the domain name
+	var hostedZoneId *string
+	listZonesInput := &route53.ListHostedZonesInput{}
+	err := svc.ListHostedZonesPages(listZonesInput, func(page *route53.ListHostedZonesOutput, hasNext bool) bool {
+		if page != nil {
+			for _, r := range page.HostedZones {
+				if r != nil && r.Name != nil && (*r.Name == domain || *r.Name == domain+".") {
+					hostedZoneId = r.Id
+					return false
+				}
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if hostedZoneId != nil {
+		return hostedZoneId, nil
+	}
+
+	// lets create the hosted zone!
+	callerRef := string(uuid.NewUUID())
+	createInput := &route53.CreateHostedZoneInput{
+		Name:            aws.String(domain),
+		CallerReference: aws.String(callerRef),
+	}
+	results, err := svc.CreateHostedZone(createInput)
+	if err != nil {
+		return nil, err
+	}
+	if results.HostedZone == nil {
+		return nil, fmt.Errorf("No HostedZone created for name %s!", domain)
+	}
+
+	hostedZoneId = results.HostedZone.Id
+	if hostedZoneId == nil {
+		return nil, fmt.Errorf("No HostedZone ID created for name %s!", domain)
+	}
+	if results.DelegationSet != nil {
+		nameServers := []string{}
+		for _, ns := range results.DelegationSet.NameServers {
+			if ns != nil {
+				nameServers = append(nameServers, *ns)
+			}
+		}
+		log.Infof("Created HostedZone %s for %s, please delegate the domain to the name servers %s\n", util.ColorInfo(*hostedZoneId), util.ColorInfo(domain), util.ColorInfo(strings.Join(nameServers, ", ")))
+	}
+	return hostedZoneId, nil
+}
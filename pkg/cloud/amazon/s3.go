@@ -1,13 +1,16 @@
 package amazon
 
 import (
+	"bytes"
+	"io/ioutil"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3"
 )
 
-// CreateS3Bucket creates a new S3 bucket in the default region with the given bucket name
+// CreateS3Bucket creates a new S3 bucket in the default region with the given bucket name and tags
 // returning the location string
-func CreateS3Bucket(bucketName string, profile string, region string) (string, error) {
+func CreateS3Bucket(bucketName string, profile string, region string, tags map[string]string) (string, error) {
 	location := ""
 	sess, err := NewAwsSession(profile, region)
 	if err != nil {
@@ -25,5 +28,142 @@ func CreateS3Bucket(bucketName string, profile string, region string) (string, e
 	if result != nil && result.Location != nil {
 		location = *result.Location
 	}
+	if err != nil || len(tags) == 0 {
+		return location, err
+	}
+	_, err = svc.PutBucketTagging(&s3.PutBucketTaggingInput{
+		Bucket:  aws.String(bucketName),
+		Tagging: s3Tagging(tags),
+	})
 	return location, err
 }
+
+// ReadS3Object returns the contents of the object of the bucket with the given key
+func ReadS3Object(bucketName string, key string, profile string, region string) ([]byte, error) {
+	sess, err := NewAwsSession(profile, region)
+	if err != nil {
+		return nil, err
+	}
+	svc := s3.New(sess)
+	output, err := svc.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer output.Body.Close()
+	return ioutil.ReadAll(output.Body)
+}
+
+// WriteS3Object writes the data into the object of the bucket with the given key
+func WriteS3Object(bucketName string, key string, data []byte, profile string, region string) error {
+	sess, err := NewAwsSession(profile, region)
+	if err != nil {
+		return err
+	}
+	svc := s3.New(sess)
+	_, err = svc.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(data),
+	})
+	return err
+}
+
+// ProbeS3Bucket checks the bucket is writable by writing then deleting an object with the given key
+func ProbeS3Bucket(bucketName string, key string, profile string, region string) error {
+	sess, err := NewAwsSession(profile, region)
+	if err != nil {
+		return err
+	}
+	svc := s3.New(sess)
+	_, err = svc.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader([]byte("jx")),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = svc.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+// SecureS3Bucket enables the default server side encryption of the bucket and adds a lifecycle rule expiring its
+// objects after the given number of days
+func SecureS3Bucket(bucketName string, profile string, region string, expirationDays int64) error {
+	sess, err := NewAwsSession(profile, region)
+	if err != nil {
+		return err
+	}
+	svc := s3.New(sess)
+	_, err = svc.PutBucketEncryption(&s3.PutBucketEncryptionInput{
+		Bucket: aws.String(bucketName),
+		ServerSideEncryptionConfiguration: &s3.ServerSideEncryptionConfiguration{
+			Rules: []*s3.ServerSideEncryptionRule{
+				{
+					ApplyServerSideEncryptionByDefault: &s3.ServerSideEncryptionByDefault{
+						SSEAlgorithm: aws.String(s3.ServerSideEncryptionAes256),
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = svc.PutBucketLifecycleConfiguration(&s3.PutBucketLifecycleConfigurationInput{
+		Bucket: aws.String(bucketName),
+		LifecycleConfiguration: &s3.BucketLifecycleConfiguration{
+			Rules: []*s3.LifecycleRule{
+				{
+					ID:     aws.String("jx-expiration"),
+					Status: aws.String(s3.ExpirationStatusEnabled),
+					Filter: &s3.LifecycleRuleFilter{Prefix: aws.String("")},
+					Expiration: &s3.LifecycleExpiration{
+						Days: aws.Int64(expirationDays),
+					},
+					AbortIncompleteMultipartUpload: &s3.AbortIncompleteMultipartUpload{
+						DaysAfterInitiation: aws.Int64(1),
+					},
+				},
+			},
+		},
+	})
+	return err
+}
+
+// DeleteS3Bucket deletes the objects of the bucket then the bucket itself
+func DeleteS3Bucket(bucketName string, profile string, region string) error {
+	sess, err := NewAwsSession(profile, region)
+	if err != nil {
+		return err
+	}
+	svc := s3.New(sess)
+	var deleteErr error
+	err = svc.ListObjectsV2Pages(&s3.ListObjectsV2Input{Bucket: aws.String(bucketName)}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		objects := []*s3.ObjectIdentifier{}
+		for _, object := range page.Contents {
+			objects = append(objects, &s3.ObjectIdentifier{Key: object.Key})
+		}
+		if len(objects) > 0 {
+			_, deleteErr = svc.DeleteObjects(&s3.DeleteObjectsInput{
+				Bucket: aws.String(bucketName),
+				Delete: &s3.Delete{Objects: objects},
+			})
+		}
+		return deleteErr == nil
+	})
+	if err != nil {
+		return err
+	}
+	if deleteErr != nil {
+		return deleteErr
+	}
+	_, err = svc.DeleteBucket(&s3.DeleteBucketInput{Bucket: aws.String(bucketName)})
+	return err
+}
@@ -0,0 +1,84 @@
+package amazon
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/aws/aws-sdk-go/private/protocol/jsonrpc"
+)
+
+const (
+	// VpcServiceCode the Service Quotas code of Amazon VPC
+	VpcServiceCode = "vpc"
+	// VpcsPerRegionQuotaCode the Service Quotas code of the number of VPCs per region
+	VpcsPerRegionQuotaCode = "L-F678F1CE"
+)
+
+// the vendored AWS SDK predates Service Quotas so its GetServiceQuota operation is called via the JSON 1.1 protocol
+// of the SDK
+
+type getServiceQuotaInput struct {
+	ServiceCode *string `type:"string"`
+	QuotaCode   *string `type:"string"`
+}
+
+type getServiceQuotaOutput struct {
+	Quota *serviceQuota `type:"structure"`
+}
+
+type serviceQuota struct {
+	Value *float64 `type:"double"`
+}
+
+// newServiceQuotasClient returns a client of the Service Quotas API
+func newServiceQuotasClient(p client.ConfigProvider, cfgs ...*aws.Config) *client.Client {
+	c := p.ClientConfig("servicequotas", cfgs...)
+	svc := client.New(
+		*c.Config,
+		metadata.ClientInfo{
+			ServiceName:   "servicequotas",
+			ServiceID:     "Service Quotas",
+			SigningName:   c.SigningName,
+			SigningRegion: c.SigningRegion,
+			Endpoint:      c.Endpoint,
+			APIVersion:    "2019-06-24",
+			JSONVersion:   "1.1",
+			TargetPrefix:  "ServiceQuotasV20190624",
+		},
+		c.Handlers,
+	)
+	svc.Handlers.Sign.PushBackNamed(v4.SignRequestHandler)
+	svc.Handlers.Build.PushBackNamed(jsonrpc.BuildHandler)
+	svc.Handlers.Unmarshal.PushBackNamed(jsonrpc.UnmarshalHandler)
+	svc.Handlers.UnmarshalMeta.PushBackNamed(jsonrpc.UnmarshalMetaHandler)
+	svc.Handlers.UnmarshalError.PushBackNamed(jsonrpc.UnmarshalErrorHandler)
+	return svc
+}
+
+// GetServiceQuota returns the value of the quota of the AWS service which applies to the account in the region of the
+// session
+func GetServiceQuota(p client.ConfigProvider, serviceCode string, quotaCode string, cfgs ...*aws.Config) (int, error) {
+	svc := newServiceQuotasClient(p, cfgs...)
+	op := &request.Operation{
+		Name:       "GetServiceQuota",
+		HTTPMethod: "POST",
+		HTTPPath:   "/",
+	}
+	input := &getServiceQuotaInput{
+		ServiceCode: aws.String(serviceCode),
+		QuotaCode:   aws.String(quotaCode),
+	}
+	output := &getServiceQuotaOutput{}
+	err := svc.NewRequest(op, input, output).Send()
+	if err != nil {
+		return 0, err
+	}
+	if output.Quota == nil || output.Quota.Value == nil {
+		return 0, fmt.Errorf("no value of the quota %s of the service %s", quotaCode, serviceCode)
+	}
+	return int(aws.Float64Value(output.Quota.Value)), nil
+}
@@ -0,0 +1,58 @@
+package amazon_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/jenkins-x/jx/pkg/cloud/amazon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetServiceQuota(t *testing.T) {
+	t.Parallel()
+	var target string
+	input := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target = r.Header.Get("X-Amz-Target")
+		json.NewDecoder(r.Body).Decode(&input)
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		w.Write([]byte(`{"Quota": {"QuotaCode": "L-F678F1CE", "Value": 20.0}}`))
+	}))
+	defer server.Close()
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-west-2"),
+		Endpoint:    aws.String(server.URL),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+	})
+	require.NoError(t, err)
+
+	quota, err := amazon.GetServiceQuota(sess, amazon.VpcServiceCode, amazon.VpcsPerRegionQuotaCode)
+	require.NoError(t, err)
+	assert.Equal(t, 20, quota)
+	assert.Equal(t, "ServiceQuotasV20190624.GetServiceQuota", target)
+	assert.Equal(t, map[string]string{"ServiceCode": "vpc", "QuotaCode": "L-F678F1CE"}, input)
+}
+
+func TestGetServiceQuotaFailsWithoutAValue(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-west-2"),
+		Endpoint:    aws.String(server.URL),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+	})
+	require.NoError(t, err)
+
+	_, err = amazon.GetServiceQuota(sess, amazon.VpcServiceCode, amazon.VpcsPerRegionQuotaCode)
+	assert.Error(t, err)
+}
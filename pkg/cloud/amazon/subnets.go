@@ -0,0 +1,69 @@
+package amazon
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+var subnetIDRegex = regexp.MustCompile(`^subnet-([0-9a-f]{8}|[0-9a-f]{17})$`)
+
+// ParseSubnetIDs splits the comma separated list of subnet IDs removing any whitespace
+func ParseSubnetIDs(subnetIDs string) []string {
+	return splitCommaSeparated(subnetIDs)
+}
+
+// ValidateSubnetIDs returns an error if any of the subnet IDs is not a valid subnet ID
+func ValidateSubnetIDs(subnetIDs []string) error {
+	for _, id := range subnetIDs {
+		if !subnetIDRegex.MatchString(id) {
+			return fmt.Errorf("%s is not a valid subnet ID, subnet IDs look like subnet-0123456789abcdef0", id)
+		}
+	}
+	return nil
+}
+
+func isSubnetNotFound(err error) bool {
+	aerr, ok := err.(awserr.Error)
+	return ok && strings.HasPrefix(aerr.Code(), "InvalidSubnetID")
+}
+
+// SubnetAvailabilityZones returns the availability zone of each of the subnets, returning an error if any
+// of them does not exist or they are not all in the same VPC. Any awserr.Error returned means the subnets
+// could not be described, for example due to missing credentials
+func SubnetAvailabilityZones(profile string, region string, subnetIDs []string) (map[string]string, error) {
+	sess, err := NewAwsSession(profile, region)
+	if err != nil {
+		return nil, err
+	}
+	result, err := ec2.New(sess).DescribeSubnets(&ec2.DescribeSubnetsInput{
+		SubnetIds: aws.StringSlice(subnetIDs),
+	})
+	if err != nil {
+		if isSubnetNotFound(err) {
+			return nil, fmt.Errorf("%s", err.(awserr.Error).Message())
+		}
+		return nil, err
+	}
+	answer := map[string]string{}
+	vpcID := ""
+	for _, subnet := range result.Subnets {
+		id := aws.StringValue(subnet.SubnetId)
+		if vpcID == "" {
+			vpcID = aws.StringValue(subnet.VpcId)
+		} else if vpcID != aws.StringValue(subnet.VpcId) {
+			return nil, fmt.Errorf("subnet %s is in VPC %s but the other subnets are in VPC %s", id, aws.StringValue(subnet.VpcId), vpcID)
+		}
+		answer[id] = aws.StringValue(subnet.AvailabilityZone)
+	}
+	for _, id := range subnetIDs {
+		if _, ok := answer[id]; !ok {
+			return nil, fmt.Errorf("subnet %s does not exist in region %s", id, region)
+		}
+	}
+	return answer, nil
+}
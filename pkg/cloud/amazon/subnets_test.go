@@ -0,0 +1,17 @@
+package amazon_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/cloud/amazon"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSubnetIDs(t *testing.T) {
+	assert.Nil(t, amazon.ValidateSubnetIDs([]string{"subnet-0123abcd", "subnet-0123456789abcdef0"}))
+	assert.Nil(t, amazon.ValidateSubnetIDs(nil))
+
+	for _, id := range []string{"subnet-0123", "sg-0123abcd", "subnet-0123ABCD", "0123456789abcdef0", "subnet-0123456789abcdef0 "} {
+		assert.NotNil(t, amazon.ValidateSubnetIDs([]string{"subnet-0123abcd", id}), "subnet ID %q should be invalid", id)
+	}
+}
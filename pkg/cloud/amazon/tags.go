@@ -0,0 +1,63 @@
+package amazon
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// ParseTags parses a comma separated list of key=value tags returning an error listing any malformed entries
+func ParseTags(text string) (map[string]string, error) {
+	return parseKeyValues(text, "tag")
+}
+
+// parseKeyValues parses a comma separated list of key=value pairs returning an error listing any malformed entries
+// which refers to the pairs by the given noun, such as tag or label
+func parseKeyValues(text string, noun string) (map[string]string, error) {
+	answer := map[string]string{}
+	invalid := []string{}
+	for _, token := range strings.Split(text, ",") {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+		parts := strings.SplitN(token, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			invalid = append(invalid, token)
+			continue
+		}
+		answer[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	if len(invalid) > 0 {
+		return nil, fmt.Errorf("invalid %ss %s, %ss must be of the form key=value", noun, strings.Join(invalid, ", "), noun)
+	}
+	return answer, nil
+}
+
+// FormatTags formats the tags as a comma separated list of key=value pairs sorted by key
+func FormatTags(tags map[string]string) string {
+	keys := []string{}
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	answer := []string{}
+	for _, k := range keys {
+		answer = append(answer, k+"="+tags[k])
+	}
+	return strings.Join(answer, ",")
+}
+
+func s3Tagging(tags map[string]string) *s3.Tagging {
+	tagSet := []*s3.Tag{}
+	for k, v := range tags {
+		tagSet = append(tagSet, &s3.Tag{
+			Key:   aws.String(k),
+			Value: aws.String(v),
+		})
+	}
+	return &s3.Tagging{TagSet: tagSet}
+}
@@ -0,0 +1,52 @@
+package amazon_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/cloud/amazon"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTags(t *testing.T) {
+	tags, err := amazon.ParseTags("team=platform, cost-center=1234,empty=")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"team": "platform", "cost-center": "1234", "empty": ""}, tags)
+	assert.Equal(t, "cost-center=1234,empty=,team=platform", amazon.FormatTags(tags))
+
+	tags, err = amazon.ParseTags("")
+	assert.Nil(t, err)
+	assert.Empty(t, tags)
+
+	_, err = amazon.ParseTags("team=platform,oops,=nokey")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "oops, =nokey")
+}
+
+func TestParseNodeLabels(t *testing.T) {
+	labels, err := amazon.ParseNodeLabels("workload=builds, tier=ci")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"workload": "builds", "tier": "ci"}, labels)
+
+	_, err = amazon.ParseNodeLabels("workload")
+	assert.EqualError(t, err, "invalid labels workload, labels must be of the form key=value")
+
+	_, err = amazon.ParseNodeLabels("tags")
+	assert.EqualError(t, err, "invalid labels tags, labels must be of the form key=value", "the entries are not rewritten")
+}
+
+func TestParseNodeTaints(t *testing.T) {
+	taints, err := amazon.ParseNodeTaints("dedicated=builds:NoSchedule, gpu:PreferNoSchedule,spot=true:NoExecute")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"dedicated": "builds:NoSchedule", "gpu": ":PreferNoSchedule", "spot": "true:NoExecute"}, taints)
+
+	taints, err = amazon.ParseNodeTaints("")
+	assert.Nil(t, err)
+	assert.Empty(t, taints)
+
+	_, err = amazon.ParseNodeTaints("dedicated=builds:NoScheduel")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "NoSchedule, PreferNoSchedule, NoExecute")
+
+	_, err = amazon.ParseNodeTaints("dedicated=builds,=x:NoSchedule")
+	assert.EqualError(t, err, "invalid taints dedicated=builds, =x:NoSchedule, taints must be of the form key=value:Effect")
+}
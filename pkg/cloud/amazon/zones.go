@@ -1,13 +1,22 @@
 package amazon
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/jenkins-x/jx/pkg/util"
 )
 
 func AvailabilityZones() ([]string, error) {
+	return RegionAvailabilityZones("", "")
+}
+
+// RegionAvailabilityZones returns the availability zones of the given region
+func RegionAvailabilityZones(profile string, region string) ([]string, error) {
 	answer := []string{}
 
-	sess, err := NewAwsSessionWithoutOptions()
+	sess, err := NewAwsSession(profile, region)
 	if err != nil {
 		return answer, err
 	}
@@ -26,3 +35,23 @@ func AvailabilityZones() ([]string, error) {
 	}
 	return answer, nil
 }
+
+// ParseZones splits the comma separated list of zones removing any whitespace
+func ParseZones(zones string) []string {
+	return splitCommaSeparated(zones)
+}
+
+// ValidateZones returns an error if any of the zones is not an availability zone of the region.
+// If availableZones is not empty the zones must be one of them too
+func ValidateZones(region string, zones []string, availableZones []string) error {
+	for _, zone := range zones {
+		suffix := strings.TrimPrefix(zone, region)
+		if suffix == zone || len(suffix) != 1 || suffix[0] < 'a' || suffix[0] > 'z' {
+			return fmt.Errorf("availability zone %s is not in region %s", zone, region)
+		}
+		if len(availableZones) > 0 && !util.Contains(availableZones, zone) {
+			return fmt.Errorf("availability zone %s is not available in region %s, available zones are: %s", zone, region, strings.Join(availableZones, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package amazon_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/cloud/amazon"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseZones(t *testing.T) {
+	assert.Equal(t, []string{"us-west-2a", "us-west-2b"}, amazon.ParseZones(" us-west-2a , us-west-2b,"))
+	assert.Equal(t, []string{}, amazon.ParseZones(""))
+}
+
+func TestValidateZones(t *testing.T) {
+	assert.Nil(t, amazon.ValidateZones("us-west-2", []string{"us-west-2a", "us-west-2b"}, nil))
+
+	err := amazon.ValidateZones("us-west-2", []string{"us-west-2a", "us-east-1b"}, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "us-east-1b")
+
+	err = amazon.ValidateZones("us-west-2", []string{"us-west-21a"}, nil)
+	assert.NotNil(t, err)
+
+	err = amazon.ValidateZones("us-west-2", []string{"us-west-2d"}, []string{"us-west-2a", "us-west-2b", "us-west-2c"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "us-west-2d")
+}
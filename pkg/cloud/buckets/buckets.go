@@ -0,0 +1,134 @@
+package buckets
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/cloud/aks"
+	"github.com/jenkins-x/jx/pkg/cloud/amazon"
+	"github.com/jenkins-x/jx/pkg/cloud/gke"
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+const (
+	// SchemeGCS the URL scheme of Google Cloud Storage buckets
+	SchemeGCS = "gs"
+	// SchemeS3 the URL scheme of Amazon S3 buckets
+	SchemeS3 = "s3"
+	// SchemeAzureBlob the URL scheme of Azure blob storage containers
+	SchemeAzureBlob = "azblob"
+)
+
+// Schemes the supported bucket URL schemes
+var Schemes = []string{SchemeGCS, SchemeS3, SchemeAzureBlob}
+
+// ParseBucketURL splits a bucket URL such as gs://my-bucket/logs into its scheme, bucket name and key prefix
+func ParseBucketURL(bucketURL string) (string, string, string, error) {
+	u, err := url.Parse(bucketURL)
+	if err != nil {
+		return "", "", "", fmt.Errorf("invalid bucket URL %s: %s", bucketURL, err)
+	}
+	supported := false
+	for _, scheme := range Schemes {
+		if u.Scheme == scheme {
+			supported = true
+		}
+	}
+	if !supported {
+		return "", "", "", fmt.Errorf("unsupported bucket URL %s, the scheme must be one of %s://", bucketURL, strings.Join(Schemes, "://, "))
+	}
+	if u.Host == "" {
+		return "", "", "", fmt.Errorf("missing the bucket name in the URL %s", bucketURL)
+	}
+	return u.Scheme, u.Host, strings.Trim(u.Path, "/"), nil
+}
+
+// ProbeBucket checks the bucket is writable by writing then deleting a probe object under the prefix of the URL
+func ProbeBucket(bucketURL string, profile string, region string) error {
+	scheme, bucket, prefix, err := ParseBucketURL(bucketURL)
+	if err != nil {
+		return err
+	}
+	key := path.Join(prefix, ".jx-probe-"+string(uuid.NewUUID()))
+	switch scheme {
+	case SchemeGCS:
+		err = gke.ProbeBucket(bucket, key)
+	case SchemeS3:
+		err = amazon.ProbeS3Bucket(bucket, key, profile, region)
+	case SchemeAzureBlob:
+		err = aks.ProbeBlobContainer(bucket, key)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write to the bucket %s: %s", bucketURL, err)
+	}
+	return nil
+}
+
+// ReadObject returns the contents of the object of a URL such as gs://my-bucket/logs/build.log
+func ReadObject(objectURL string, profile string, region string) ([]byte, error) {
+	scheme, bucket, key, err := ParseBucketURL(objectURL)
+	if err != nil {
+		return nil, err
+	}
+	if key == "" {
+		return nil, fmt.Errorf("missing the object key in the URL %s", objectURL)
+	}
+	var data []byte
+	switch scheme {
+	case SchemeGCS:
+		data, err = gke.ReadObject(bucket, key)
+	case SchemeS3:
+		data, err = amazon.ReadS3Object(bucket, key, profile, region)
+	case SchemeAzureBlob:
+		data, err = aks.ReadBlob(bucket, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %s", objectURL, err)
+	}
+	return data, nil
+}
+
+// WriteObject writes the data into the object of a URL such as gs://my-bucket/logs/report.json
+func WriteObject(objectURL string, data []byte, profile string, region string) error {
+	scheme, bucket, key, err := ParseBucketURL(objectURL)
+	if err != nil {
+		return err
+	}
+	if key == "" {
+		return fmt.Errorf("missing the object key in the URL %s", objectURL)
+	}
+	switch scheme {
+	case SchemeGCS:
+		err = gke.WriteObject(bucket, key, data)
+	case SchemeS3:
+		err = amazon.WriteS3Object(bucket, key, data, profile, region)
+	case SchemeAzureBlob:
+		err = aks.WriteBlob(bucket, key, data)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %s", objectURL, err)
+	}
+	return nil
+}
+
+// DeleteBucket deletes the whole bucket of the URL including its objects
+func DeleteBucket(bucketURL string, profile string, region string) error {
+	scheme, bucket, prefix, err := ParseBucketURL(bucketURL)
+	if err != nil {
+		return err
+	}
+	if prefix != "" {
+		return fmt.Errorf("cannot delete the bucket %s as the URL has the path %s", bucketURL, prefix)
+	}
+	switch scheme {
+	case SchemeGCS:
+		return gke.DeleteBucket(bucket)
+	case SchemeS3:
+		return amazon.DeleteS3Bucket(bucket, profile, region)
+	case SchemeAzureBlob:
+		return aks.DeleteBlobContainer(bucket)
+	}
+	return nil
+}
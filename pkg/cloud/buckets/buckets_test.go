@@ -0,0 +1,36 @@
+package buckets_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/cloud/buckets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBucketURL(t *testing.T) {
+	t.Parallel()
+
+	testData := []struct {
+		url    string
+		scheme string
+		bucket string
+		prefix string
+	}{
+		{"gs://my-logs", "gs", "my-logs", ""},
+		{"s3://my-logs/jx/builds/", "s3", "my-logs", "jx/builds"},
+		{"azblob://reports", "azblob", "reports", ""},
+	}
+	for _, d := range testData {
+		scheme, bucket, prefix, err := buckets.ParseBucketURL(d.url)
+		if assert.NoError(t, err, "parsing %s", d.url) {
+			assert.Equal(t, d.scheme, scheme, "scheme of %s", d.url)
+			assert.Equal(t, d.bucket, bucket, "bucket of %s", d.url)
+			assert.Equal(t, d.prefix, prefix, "prefix of %s", d.url)
+		}
+	}
+
+	for _, u := range []string{"https://storage.googleapis.com/my-logs", "my-logs", "gs:///logs"} {
+		_, _, _, err := buckets.ParseBucketURL(u)
+		assert.Error(t, err, "parsing %s", u)
+	}
+}
@@ -0,0 +1,61 @@
+package doks
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+const (
+	// DefaultRegion the DigitalOcean region clusters are created in if none is specified
+	DefaultRegion = "nyc1"
+
+	// DefaultSize the droplet size slug of the nodes if none is specified
+	DefaultSize = "s-2vcpu-4gb"
+
+	// DefaultNodeCount the number of nodes of the node pool if none is specified
+	DefaultNodeCount = 3
+
+	// DefaultVersion the Kubernetes version slug doctl resolves to the latest supported version
+	DefaultVersion = "latest"
+
+	// AccessTokenEnvVar the environment variable of the DigitalOcean API token used by doctl
+	AccessTokenEnvVar = "DIGITALOCEAN_ACCESS_TOKEN"
+)
+
+// CreateClusterArgs returns the arguments of the `doctl` command which creates the cluster and saves its kube config
+// as the current context
+func CreateClusterArgs(clusterName string, region string, size string, nodeCount int, version string) []string {
+	return []string{"kubernetes", "cluster", "create", clusterName,
+		"--region", region,
+		"--size", size,
+		"--count", strconv.Itoa(nodeCount),
+		"--version", version,
+		"--wait",
+		"--update-kubeconfig",
+		"--set-current-context",
+	}
+}
+
+// ContextName returns the name of the kube context doctl saves for the cluster
+func ContextName(region string, clusterName string) string {
+	return fmt.Sprintf("do-%s-%s", region, clusterName)
+}
+
+// ParseClusterNames parses the output of `doctl kubernetes cluster list -o json` returning the cluster names
+func ParseClusterNames(data []byte) ([]string, error) {
+	clusters := []struct {
+		Name string `json:"name"`
+	}{}
+	err := json.Unmarshal(data, &clusters)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse the DigitalOcean Kubernetes clusters due to %s", err)
+	}
+	answer := []string{}
+	for _, cluster := range clusters {
+		if cluster.Name != "" {
+			answer = append(answer, cluster.Name)
+		}
+	}
+	return answer, nil
+}
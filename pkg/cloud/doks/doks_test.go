@@ -0,0 +1,27 @@
+package doks_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/cloud/doks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateClusterArgs(t *testing.T) {
+	t.Parallel()
+	args := doks.CreateClusterArgs("dev", "lon1", "s-4vcpu-8gb", 2, "1.16.2-do.0")
+	assert.Equal(t, "kubernetes cluster create dev --region lon1 --size s-4vcpu-8gb --count 2 --version 1.16.2-do.0 --wait --update-kubeconfig --set-current-context", strings.Join(args, " "))
+	assert.Equal(t, "do-lon1-dev", doks.ContextName("lon1", "dev"))
+}
+
+func TestParseClusterNames(t *testing.T) {
+	t.Parallel()
+	names, err := doks.ParseClusterNames([]byte(`[{"id":"1","name":"dev","region":"lon1"},{"id":"2","name":"staging","region":"nyc1"}]`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dev", "staging"}, names)
+
+	_, err = doks.ParseClusterNames([]byte(`not json`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,116 @@
+package externaldns
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// ReleaseName the name of the helm release of external-dns
+	ReleaseName = "external-dns"
+	// Chart the helm chart of external-dns
+	Chart = "stable/external-dns"
+	// Namespace the namespace external-dns is installed into
+	Namespace = "kube-system"
+
+	// HostnameAnnotation the annotation of a Service whose value is the hostname external-dns creates a record for
+	HostnameAnnotation = "external-dns.alpha.kubernetes.io/hostname"
+
+	// ProviderAWS manages the records in Route53
+	ProviderAWS = "aws"
+	// ProviderGoogle manages the records in Cloud DNS
+	ProviderGoogle = "google"
+	// ProviderAzure manages the records in Azure DNS
+	ProviderAzure = "azure"
+
+	// verifyHostPrefix the host looked up under the wildcard record of the domain to check it resolves
+	verifyHostPrefix = "jx-dns-check"
+)
+
+// Config the configuration of external-dns for a domain
+type Config struct {
+	// Provider the DNS provider such as aws, google or azure
+	Provider string
+	// Domain the domain whose records external-dns manages
+	Domain string
+	// OwnerID identifies the records created by this external-dns so that several clusters can share a zone
+	OwnerID string
+	// Region the AWS region of the cluster
+	Region string
+	// GoogleProject the GCP project of the Cloud DNS managed zone
+	GoogleProject string
+	// AzureResourceGroup the resource group of the Azure DNS zone
+	AzureResourceGroup string
+	// AzureTenantID the Azure tenant of the subscription
+	AzureTenantID string
+	// AzureSubscriptionID the Azure subscription of the DNS zone
+	AzureSubscriptionID string
+}
+
+// Validate checks the configuration has the values its provider needs
+func (c *Config) Validate() error {
+	if c.Domain == "" {
+		return fmt.Errorf("no domain specified for external-dns")
+	}
+	switch c.Provider {
+	case ProviderAWS:
+	case ProviderGoogle:
+		if c.GoogleProject == "" {
+			return fmt.Errorf("no GCP project specified for the Cloud DNS managed zone of %s", c.Domain)
+		}
+	case ProviderAzure:
+		if c.AzureResourceGroup == "" || c.AzureTenantID == "" || c.AzureSubscriptionID == "" {
+			return fmt.Errorf("the resource group, tenant and subscription of the Azure DNS zone of %s are required", c.Domain)
+		}
+	default:
+		return fmt.Errorf("unsupported external-dns provider %s", c.Provider)
+	}
+	return nil
+}
+
+// HelmValues returns the values to install the external-dns chart with for the configuration. The credentials come
+// from the cluster itself: the node or IRSA role on AWS, the node service account on GCP and the managed identity
+// of the nodes on Azure
+func HelmValues(c *Config) []string {
+	values := map[string]string{
+		"provider":         c.Provider,
+		"domainFilters[0]": c.Domain,
+		"policy":           "upsert-only",
+		"rbac.create":      "true",
+	}
+	if c.OwnerID != "" {
+		values["txtOwnerId"] = c.OwnerID
+	}
+	switch c.Provider {
+	case ProviderAWS:
+		values["aws.zoneType"] = "public"
+		if c.Region != "" {
+			values["aws.region"] = c.Region
+		}
+	case ProviderGoogle:
+		values["google.project"] = c.GoogleProject
+	case ProviderAzure:
+		values["azure.resourceGroup"] = c.AzureResourceGroup
+		values["azure.tenantId"] = c.AzureTenantID
+		values["azure.subscriptionId"] = c.AzureSubscriptionID
+		values["azure.useManagedIdentityExtension"] = "true"
+	}
+	answer := []string{}
+	for k, v := range values {
+		answer = append(answer, k+"="+v)
+	}
+	sort.Strings(answer)
+	return answer
+}
+
+// WildcardHostname returns the hostname to annotate the ingress controller Service with so that every host of the
+// domain points at it
+func WildcardHostname(domain string) string {
+	return "*." + strings.TrimSuffix(domain, ".")
+}
+
+// VerifyHostname returns a host of the domain which only resolves once the wildcard record exists
+func VerifyHostname(domain string) string {
+	return verifyHostPrefix + "." + strings.TrimSuffix(domain, ".")
+}
@@ -0,0 +1,49 @@
+package externaldns_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/cloud/externaldns"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHelmValues(t *testing.T) {
+	t.Parallel()
+	config := &externaldns.Config{
+		Provider: externaldns.ProviderAWS,
+		Domain:   "jx.example.com",
+		OwnerID:  "jx",
+		Region:   "eu-west-1",
+	}
+	assert.NoError(t, config.Validate())
+	assert.Equal(t, []string{
+		"aws.region=eu-west-1",
+		"aws.zoneType=public",
+		"domainFilters[0]=jx.example.com",
+		"policy=upsert-only",
+		"provider=aws",
+		"rbac.create=true",
+		"txtOwnerId=jx",
+	}, externaldns.HelmValues(config))
+
+	config = &externaldns.Config{
+		Provider:      externaldns.ProviderGoogle,
+		Domain:        "jx.example.com",
+		GoogleProject: "my-project",
+	}
+	assert.Contains(t, externaldns.HelmValues(config), "google.project=my-project")
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+	assert.Error(t, (&externaldns.Config{Provider: externaldns.ProviderAWS}).Validate())
+	assert.Error(t, (&externaldns.Config{Provider: externaldns.ProviderGoogle, Domain: "jx.example.com"}).Validate())
+	assert.Error(t, (&externaldns.Config{Provider: externaldns.ProviderAzure, Domain: "jx.example.com", AzureResourceGroup: "dns"}).Validate())
+	assert.Error(t, (&externaldns.Config{Provider: "digitalocean", Domain: "jx.example.com"}).Validate())
+}
+
+func TestHostnames(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "*.jx.example.com", externaldns.WildcardHostname("jx.example.com."))
+	assert.Equal(t, "jx-dns-check.jx.example.com", externaldns.VerifyHostname("jx.example.com"))
+}
@@ -0,0 +1,63 @@
+package gke
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// managedZone the fields of a Cloud DNS managed zone jx uses
+type managedZone struct {
+	Name        string   `json:"name"`
+	DNSName     string   `json:"dnsName"`
+	NameServers []string `json:"nameServers"`
+}
+
+// ManagedZoneName returns the name of the Cloud DNS managed zone jx creates for the domain
+func ManagedZoneName(domain string) string {
+	return strings.Replace(strings.TrimSuffix(domain, "."), ".", "-", -1)
+}
+
+// EnsureManagedZone creates a Cloud DNS managed zone for the domain unless the project already has one
+func EnsureManagedZone(projectId string, domain string) error {
+	dnsName := strings.TrimSuffix(domain, ".") + "."
+	cmd := util.Command{
+		Name: "gcloud",
+		Args: []string{"dns", "managed-zones", "list", "--project", projectId, "--filter", "dnsName=" + dnsName, "--format", "json"},
+	}
+	output, err := cmd.RunWithoutRetry()
+	if err != nil {
+		return err
+	}
+	zones := []managedZone{}
+	err = json.Unmarshal([]byte(output), &zones)
+	if err != nil {
+		return err
+	}
+	if len(zones) > 0 {
+		log.Infof("Using the Cloud DNS managed zone %s for %s\n", util.ColorInfo(zones[0].Name), util.ColorInfo(domain))
+		return nil
+	}
+
+	name := ManagedZoneName(domain)
+	cmd = util.Command{
+		Name: "gcloud",
+		Args: []string{"dns", "managed-zones", "create", name, "--project", projectId, "--dns-name", dnsName,
+			"--description", "Managed by Jenkins X", "--format", "json"},
+	}
+	output, err = cmd.RunWithoutRetry()
+	if err != nil {
+		return err
+	}
+	created := []managedZone{}
+	err = json.Unmarshal([]byte(output), &created)
+	if err == nil && len(created) > 0 {
+		log.Infof("Created the Cloud DNS managed zone %s for %s, please delegate the domain to the name servers %s\n",
+			util.ColorInfo(name), util.ColorInfo(domain), util.ColorInfo(strings.Join(created[0].NameServers, ", ")))
+	} else {
+		log.Infof("Created the Cloud DNS managed zone %s for %s, please delegate the domain to its name servers\n", util.ColorInfo(name), util.ColorInfo(domain))
+	}
+	return nil
+}
@@ -1,8 +1,10 @@
 package gke
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
@@ -66,6 +68,82 @@ func CreateBucket(projectId string, bucketName string, location string) error {
 	return nil
 }
 
+// ProbeBucket checks the bucket is writable by copying then removing an object with the given key
+func ProbeBucket(bucketName string, key string) error {
+	file, err := ioutil.TempFile("", "jx-probe-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	file.Close()
+
+	objectURL := fmt.Sprintf("gs://%s/%s", bucketName, key)
+	for _, args := range [][]string{{"cp", file.Name(), objectURL}, {"rm", objectURL}} {
+		cmd := util.Command{
+			Name: "gsutil",
+			Args: args,
+		}
+		output, err := cmd.RunWithoutRetry()
+		if err != nil {
+			return fmt.Errorf("failed to run gsutil %s: %s, %s", strings.Join(args, " "), output, err)
+		}
+	}
+	return nil
+}
+
+// ReadObject returns the contents of the object of the bucket with the given key
+func ReadObject(bucketName string, key string) ([]byte, error) {
+	var out bytes.Buffer
+	cmd := util.Command{
+		Name: "gsutil",
+		Args: []string{"cat", fmt.Sprintf("gs://%s/%s", bucketName, key)},
+		Out:  &out,
+	}
+	_, err := cmd.RunWithoutRetry()
+	if err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// WriteObject writes the data into the object of the bucket with the given key
+func WriteObject(bucketName string, key string, data []byte) error {
+	file, err := ioutil.TempFile("", "jx-object-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	_, err = file.Write(data)
+	file.Close()
+	if err != nil {
+		return err
+	}
+
+	args := []string{"cp", file.Name(), fmt.Sprintf("gs://%s/%s", bucketName, key)}
+	cmd := util.Command{
+		Name: "gsutil",
+		Args: args,
+	}
+	output, err := cmd.RunWithoutRetry()
+	if err != nil {
+		return fmt.Errorf("failed to run gsutil %s: %s, %s", strings.Join(args, " "), output, err)
+	}
+	return nil
+}
+
+// DeleteBucket deletes the objects of the bucket then the bucket itself
+func DeleteBucket(bucketName string) error {
+	cmd := util.Command{
+		Name: "gsutil",
+		Args: []string{"rm", "-r", fmt.Sprintf("gs://%s", bucketName)},
+	}
+	output, err := cmd.RunWithoutRetry()
+	if err != nil {
+		return fmt.Errorf("failed to delete the bucket %s: %s, %s", bucketName, output, err)
+	}
+	return nil
+}
+
 func GetRegionFromZone(zone string) string {
 	return zone[0 : len(zone)-2]
 }
@@ -14,3 +14,8 @@ func TestGetRegionFromZone(t *testing.T) {
 	r = GetRegionFromZone("uswest1-d")
 	assert.Equal(t, r, "uswest1")
 }
+
+func TestManagedZoneName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "jx-example-com", ManagedZoneName("jx.example.com."))
+}
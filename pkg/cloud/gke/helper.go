@@ -9,9 +9,28 @@ import (
 
 var PROJECT_LIST_HEADER = "PROJECT_ID"
 
+const (
+	// ReleaseChannelRegular the release channel which balances new features and stability
+	ReleaseChannelRegular = "regular"
+	// ReleaseChannelNone creates a cluster which is not on a release channel
+	ReleaseChannelNone = "none"
+)
+
+// ReleaseChannels the GKE release channels which upgrade clusters automatically
+var ReleaseChannels = []string{"rapid", ReleaseChannelRegular, "stable", ReleaseChannelNone}
+
 func GetGoogleZones(project string) ([]string, error) {
-	var zones []string
-	args := []string{"compute", "zones", "list"}
+	return getGoogleLocations("zones", project)
+}
+
+// GetGoogleRegions returns the compute regions of the project
+func GetGoogleRegions(project string) ([]string, error) {
+	return getGoogleLocations("regions", project)
+}
+
+func getGoogleLocations(kind string, project string) ([]string, error) {
+	var locations []string
+	args := []string{"compute", kind, "list"}
 
 	if "" != project {
 		args = append(args, "--project")
@@ -29,13 +48,13 @@ func GetGoogleZones(project string) ([]string, error) {
 	}
 
 	for _, item := range strings.Split(string(out), "\n") {
-		zone := strings.Split(item, " ")[0]
-		if strings.Contains(zone, "-") {
-			zones = append(zones, zone)
+		location := strings.Split(item, " ")[0]
+		if strings.Contains(location, "-") {
+			locations = append(locations, location)
 		}
-		sort.Strings(zones)
 	}
-	return zones, nil
+	sort.Strings(locations)
+	return locations, nil
 }
 
 func GetGoogleProjects() ([]string, error) {
@@ -0,0 +1,149 @@
+package gke
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+const (
+	// WorkloadIdentityAnnotation the annotation of a Kubernetes service account which names the Google service
+	// account it acts as via Workload Identity
+	WorkloadIdentityAnnotation = "iam.gke.io/gcp-service-account"
+
+	// WorkloadIdentityUserRole the role which allows a Kubernetes service account to act as a Google service account
+	WorkloadIdentityUserRole = "roles/iam.workloadIdentityUser"
+
+	// WorkloadIdentityRegistryRole the role granted to the Google service account used by the builds on the bucket in
+	// which GCR stores the images of the project. It allows the builds to push and pull images without being able to
+	// administer the other buckets of the project, as roles/storage.admin on the project would
+	WorkloadIdentityRegistryRole = "roles/storage.objectAdmin"
+
+	// DefaultContainerRegistry the GCR host used when no docker registry is specified
+	DefaultContainerRegistry = "gcr.io"
+
+	maxServiceAccountNameLength = 30
+)
+
+// WorkloadPool returns the Workload Identity pool of the project
+func WorkloadPool(projectId string) string {
+	return projectId + ".svc.id.goog"
+}
+
+// ServiceAccountEmail returns the email of the Google service account of the given name in the project
+func ServiceAccountEmail(serviceAccount string, projectId string) string {
+	return fmt.Sprintf("%s@%s.iam.gserviceaccount.com", serviceAccount, projectId)
+}
+
+// WorkloadIdentityMember returns the IAM member of a Kubernetes service account in the Workload Identity pool
+func WorkloadIdentityMember(projectId string, namespace string, kubeServiceAccount string) string {
+	return fmt.Sprintf("serviceAccount:%s[%s/%s]", WorkloadPool(projectId), namespace, kubeServiceAccount)
+}
+
+// WorkloadIdentityServiceAccountName returns the name of the Google service account the builds of the cluster act as,
+// which is truncated to the 30 characters allowed for service account names
+func WorkloadIdentityServiceAccountName(clusterName string) string {
+	name := "jx-" + strings.ToLower(clusterName)
+	if len(name) > maxServiceAccountNameLength {
+		name = name[:maxServiceAccountNameLength]
+	}
+	return strings.TrimRight(name, "-")
+}
+
+// ContainerRegistryBucket returns the Cloud Storage bucket in which GCR stores the images of the project pushed to
+// the given registry host, such as gcr.io or eu.gcr.io
+func ContainerRegistryBucket(registry string, projectId string) string {
+	host := strings.Split(registry, "/")[0]
+	if host == "" || host == DefaultContainerRegistry {
+		return fmt.Sprintf("artifacts.%s.appspot.com", projectId)
+	}
+	return fmt.Sprintf("%s.artifacts.%s.appspot.com", strings.TrimSuffix(host, "."+DefaultContainerRegistry), projectId)
+}
+
+// ValidateContainerRegistryBucket returns the GCR bucket of the registry or an error if it does not exist yet. GCR
+// creates the bucket on the first push to the registry which needs permissions the builds are not granted
+func ValidateContainerRegistryBucket(registry string, projectId string) (string, error) {
+	bucket := ContainerRegistryBucket(registry, projectId)
+	exists, err := BucketExists(projectId, bucket)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", fmt.Errorf("the GCR bucket %s does not exist yet, please push an image to %s/%s once with 'gcloud auth configure-docker' and 'docker push' then try again", bucket, strings.Split(registry, "/")[0], projectId)
+	}
+	return bucket, nil
+}
+
+// CreateWorkloadIdentityServiceAccount gets or creates the Google service account, grants it the
+// WorkloadIdentityRegistryRole on the GCR bucket of the registry and allows the Kubernetes service account in the
+// namespace to act as it, returning the email of the Google service account
+func CreateWorkloadIdentityServiceAccount(serviceAccount string, projectId string, registry string, namespace string, kubeServiceAccount string) (string, error) {
+	if projectId == "" {
+		return "", errors.New("cannot get/create a service account without a projectId")
+	}
+	email := ServiceAccountEmail(serviceAccount, projectId)
+	bucket, err := ValidateContainerRegistryBucket(registry, projectId)
+	if err != nil {
+		return "", err
+	}
+
+	cmd := util.Command{
+		Name: "gcloud",
+		Args: []string{"iam", "service-accounts", "list", "--filter", email, "--project", projectId},
+	}
+	output, err := cmd.RunWithoutRetry()
+	if err != nil {
+		return "", err
+	}
+	if output == "Listed 0 items." {
+		log.Infof("Creating service account %s\n", util.ColorInfo(serviceAccount))
+		cmd = util.Command{
+			Name: "gcloud",
+			Args: []string{"iam", "service-accounts", "create", serviceAccount, "--display-name", "Jenkins X builds", "--project", projectId},
+		}
+		_, err = cmd.RunWithoutRetry()
+		if err != nil {
+			return "", err
+		}
+	} else {
+		log.Infof("Service account %s exists\n", util.ColorInfo(serviceAccount))
+	}
+
+	log.Infof("Assigning role %s on bucket %s\n", WorkloadIdentityRegistryRole, util.ColorInfo(bucket))
+	cmd = util.Command{
+		Name: "gsutil",
+		Args: []string{"iam", "ch", "serviceAccount:" + email + ":" + WorkloadIdentityRegistryRole, "gs://" + bucket},
+	}
+	_, err = cmd.RunWithoutRetry()
+	if err != nil {
+		return "", err
+	}
+
+	member := WorkloadIdentityMember(projectId, namespace, kubeServiceAccount)
+	log.Infof("Allowing %s to act as service account %s\n", util.ColorInfo(member), util.ColorInfo(serviceAccount))
+	cmd = util.Command{
+		Name: "gcloud",
+		Args: []string{"iam", "service-accounts", "add-iam-policy-binding", email, "--member", member, "--role", WorkloadIdentityUserRole, "--project", projectId},
+	}
+	_, err = cmd.RunWithoutRetry()
+	if err != nil {
+		return "", err
+	}
+	return email, nil
+}
+
+// DeleteServiceAccount deletes the Google service account with the given email
+func DeleteServiceAccount(email string) error {
+	cmd := util.Command{
+		Name: "gcloud",
+		Args: []string{"iam", "service-accounts", "delete", email, "--quiet"},
+	}
+	output, err := cmd.RunWithoutRetry()
+	if err != nil {
+		return fmt.Errorf("failed to delete the service account %s: %s, %s", email, output, err)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package gke
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkloadIdentityMember(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "my-project.svc.id.goog", WorkloadPool("my-project"))
+	assert.Equal(t, "serviceAccount:my-project.svc.id.goog[jx/jenkins]", WorkloadIdentityMember("my-project", "jx", "jenkins"))
+	assert.Equal(t, "jx-dev@my-project.iam.gserviceaccount.com", ServiceAccountEmail("jx-dev", "my-project"))
+}
+
+func TestWorkloadIdentityServiceAccountName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "jx-dev", WorkloadIdentityServiceAccountName("Dev"))
+	name := WorkloadIdentityServiceAccountName("a-very-long-cluster-name-which-is-truncated")
+	assert.Equal(t, "jx-a-very-long-cluster-name-wh", name)
+	assert.Len(t, name, 30)
+	assert.Equal(t, "jx-a-cluster-name-ending-in-a", WorkloadIdentityServiceAccountName("a-cluster-name-ending-in-a-dash"))
+}
+
+func TestContainerRegistryBucket(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "artifacts.my-project.appspot.com", ContainerRegistryBucket("", "my-project"))
+	assert.Equal(t, "artifacts.my-project.appspot.com", ContainerRegistryBucket("gcr.io", "my-project"))
+	assert.Equal(t, "eu.artifacts.my-project.appspot.com", ContainerRegistryBucket("eu.gcr.io", "my-project"))
+	assert.Equal(t, "asia.artifacts.my-project.appspot.com", ContainerRegistryBucket("asia.gcr.io/my-project", "my-project"))
+}
@@ -0,0 +1,105 @@
+package iks
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// InfrastructureClassic the classic IBM Cloud infrastructure
+	InfrastructureClassic = "classic"
+	// InfrastructureVPCGen2 the generation 2 VPC infrastructure
+	InfrastructureVPCGen2 = "vpc-gen2"
+
+	// DefaultClassicZone the zone of classic clusters if none is specified
+	DefaultClassicZone = "dal10"
+	// DefaultVPCZone the zone of VPC clusters if none is specified
+	DefaultVPCZone = "us-south-1"
+	// DefaultClassicFlavor the worker flavor of classic clusters if none is specified
+	DefaultClassicFlavor = "b3c.4x16"
+	// DefaultVPCFlavor the worker flavor of VPC clusters if none is specified
+	DefaultVPCFlavor = "bx2.4x16"
+
+	// ContainerServicePlugin the ibmcloud CLI plugin which manages IBM Cloud Kubernetes Service clusters
+	ContainerServicePlugin = "container-service"
+
+	// WorkerStatusReady the status of a worker which is ready
+	WorkerStatusReady = "Ready"
+)
+
+var (
+	// Infrastructures the infrastructures an IKS cluster can be created on
+	Infrastructures = []string{InfrastructureClassic, InfrastructureVPCGen2}
+)
+
+// Worker the fields of `ibmcloud ks worker ls --output json` jx uses
+type Worker struct {
+	ID     string `json:"id"`
+	State  string `json:"state"`
+	Status string `json:"status"`
+}
+
+// CreateClusterArgs returns the arguments of the `ibmcloud` command which creates the cluster. The VPC and subnet are
+// only used on the VPC infrastructure
+func CreateClusterArgs(infrastructure string, clusterName string, zone string, flavor string, workers int, vpcID string, subnetID string) []string {
+	args := []string{"ks", "cluster", "create", infrastructure,
+		"--name", clusterName,
+		"--zone", zone,
+		"--flavor", flavor,
+		"--workers", strconv.Itoa(workers),
+	}
+	if infrastructure == InfrastructureVPCGen2 {
+		args = append(args, "--vpc-id", vpcID, "--subnet-id", subnetID)
+	}
+	return args
+}
+
+// ParseWorkers parses the output of `ibmcloud ks worker ls --output json`
+func ParseWorkers(data []byte) ([]Worker, error) {
+	workers := []Worker{}
+	err := json.Unmarshal(data, &workers)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse the IKS workers due to %s", err)
+	}
+	return workers, nil
+}
+
+// WorkersReady returns true if there are workers and all of them are ready
+func WorkersReady(workers []Worker) bool {
+	if len(workers) == 0 {
+		return false
+	}
+	for _, w := range workers {
+		if w.Status != WorkerStatusReady {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseIngressSubdomain parses the IBM provided ingress subdomain of the cluster from the output of
+// `ibmcloud ks cluster get --output json`, which older versions of the plugin return as ingressHostname
+func ParseIngressSubdomain(data []byte) (string, error) {
+	cluster := struct {
+		IngressHostname string `json:"ingressHostname"`
+		Ingress         struct {
+			Hostname string `json:"hostname"`
+		} `json:"ingress"`
+	}{}
+	err := json.Unmarshal(data, &cluster)
+	if err != nil {
+		return "", fmt.Errorf("Failed to parse the IKS cluster due to %s", err)
+	}
+	if cluster.Ingress.Hostname != "" {
+		return cluster.Ingress.Hostname, nil
+	}
+	return cluster.IngressHostname, nil
+}
+
+// ClusterNameFromContext returns the cluster name of a kube context created by `ibmcloud ks cluster config`, which
+// are of the form <cluster name>/<cluster id>
+func ClusterNameFromContext(context string) string {
+	return strings.SplitN(context, "/", 2)[0]
+}
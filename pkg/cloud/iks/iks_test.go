@@ -0,0 +1,42 @@
+package iks_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/cloud/iks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateClusterArgs(t *testing.T) {
+	t.Parallel()
+	args := iks.CreateClusterArgs(iks.InfrastructureClassic, "dev", "dal10", "b3c.4x16", 3, "vpc", "subnet")
+	assert.Equal(t, "ks cluster create classic --name dev --zone dal10 --flavor b3c.4x16 --workers 3", strings.Join(args, " "))
+
+	args = iks.CreateClusterArgs(iks.InfrastructureVPCGen2, "dev", "us-south-1", "bx2.4x16", 2, "vpc", "subnet")
+	assert.Equal(t, "ks cluster create vpc-gen2 --name dev --zone us-south-1 --flavor bx2.4x16 --workers 2 --vpc-id vpc --subnet-id subnet", strings.Join(args, " "))
+}
+
+func TestWorkersReady(t *testing.T) {
+	t.Parallel()
+	workers, err := iks.ParseWorkers([]byte(`[{"id":"w1","state":"normal","status":"Ready"},{"id":"w2","state":"provisioning","status":"Provisioning"}]`))
+	require.NoError(t, err)
+	assert.Len(t, workers, 2)
+	assert.False(t, iks.WorkersReady(workers))
+	assert.True(t, iks.WorkersReady(workers[:1]))
+	assert.False(t, iks.WorkersReady(nil))
+}
+
+func TestParseIngressSubdomain(t *testing.T) {
+	t.Parallel()
+	domain, err := iks.ParseIngressSubdomain([]byte(`{"name":"dev","ingressHostname":"dev.us-south.containers.appdomain.cloud"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "dev.us-south.containers.appdomain.cloud", domain)
+
+	domain, err = iks.ParseIngressSubdomain([]byte(`{"name":"dev","ingress":{"hostname":"dev-123.us-south.containers.appdomain.cloud"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "dev-123.us-south.containers.appdomain.cloud", domain)
+
+	assert.Equal(t, "dev", iks.ClusterNameFromContext("dev/bn2k1h3d0tgl6vi0g1pg"))
+}
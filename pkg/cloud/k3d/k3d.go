@@ -0,0 +1,59 @@
+package k3d
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultClusterName the name k3d gives a cluster if none is specified
+	DefaultClusterName = "k3s-default"
+
+	// Domain the domain of the ingresses of a k3d cluster, whose load balancer is reachable on localhost
+	Domain = "127.0.0.1.nip.io"
+
+	// IngressTraefik uses the traefik ingress controller k3s deploys by default
+	IngressTraefik = "traefik"
+
+	// IngressNginx disables traefik so that jx installs the nginx ingress controller
+	IngressNginx = "nginx"
+
+	// TraefikNamespace the namespace of the traefik ingress controller deployed by k3s
+	TraefikNamespace = "kube-system"
+
+	// TraefikService the name of the Service and Deployment of the traefik ingress controller deployed by k3s
+	TraefikService = "traefik"
+
+	contextPrefix = "k3d-"
+)
+
+var (
+	// Ingresses the ingress controllers a k3d cluster can be created with
+	Ingresses = []string{IngressTraefik, IngressNginx}
+)
+
+// CreateClusterArgs returns the arguments of the `k3d` command which creates the cluster with the given number of
+// agents. The HTTP and HTTPS ports of the cluster load balancer are published on localhost and traefik is only
+// deployed if it is the ingress controller
+func CreateClusterArgs(clusterName string, agents int, ingress string) []string {
+	args := []string{"cluster", "create", clusterName,
+		"--agents", strconv.Itoa(agents),
+		"--port", "80:80@loadbalancer",
+		"--port", "443:443@loadbalancer",
+		"--wait",
+	}
+	if ingress != IngressTraefik {
+		args = append(args, "--k3s-server-arg", "--no-deploy=traefik")
+	}
+	return args
+}
+
+// ContextName returns the name of the kube context k3d creates for the cluster
+func ContextName(clusterName string) string {
+	return contextPrefix + clusterName
+}
+
+// IsK3dContext returns true if the kube context is one created by k3d
+func IsK3dContext(context string) bool {
+	return strings.HasPrefix(context, contextPrefix)
+}
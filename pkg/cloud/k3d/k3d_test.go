@@ -0,0 +1,25 @@
+package k3d_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/cloud/k3d"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateClusterArgs(t *testing.T) {
+	t.Parallel()
+	args := k3d.CreateClusterArgs("dev", 2, k3d.IngressTraefik)
+	assert.Equal(t, "cluster create dev --agents 2 --port 80:80@loadbalancer --port 443:443@loadbalancer --wait", strings.Join(args, " "))
+
+	args = k3d.CreateClusterArgs("dev", 0, k3d.IngressNginx)
+	assert.Equal(t, "cluster create dev --agents 0 --port 80:80@loadbalancer --port 443:443@loadbalancer --wait --k3s-server-arg --no-deploy=traefik", strings.Join(args, " "))
+}
+
+func TestContextName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "k3d-dev", k3d.ContextName("dev"))
+	assert.True(t, k3d.IsK3dContext("k3d-dev"))
+	assert.False(t, k3d.IsK3dContext("kind-dev"))
+}
@@ -0,0 +1,108 @@
+package kind
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// DefaultClusterName the name kind gives a cluster if none is specified
+	DefaultClusterName = "kind"
+
+	// Domain the domain of the ingresses of a kind cluster, whose ingress controller is reachable on localhost
+	Domain = "127.0.0.1.nip.io"
+
+	// IngressReadyLabel the label of the node the ingress controller runs on, whose ports are mapped to localhost
+	IngressReadyLabel = "ingress-ready"
+
+	// ConfigAPIVersion the API version of the kind cluster config
+	ConfigAPIVersion = "kind.sigs.k8s.io/v1alpha3"
+
+	contextPrefix = "kind-"
+)
+
+// Config the subset of the kind Cluster config jx generates
+type Config struct {
+	Kind                 string   `yaml:"kind"`
+	APIVersion           string   `yaml:"apiVersion"`
+	Nodes                []Node   `yaml:"nodes"`
+	KubeadmConfigPatches []string `yaml:"kubeadmConfigPatches,omitempty"`
+}
+
+// Node a node of a kind cluster
+type Node struct {
+	Role              string        `yaml:"role"`
+	Image             string        `yaml:"image,omitempty"`
+	ExtraPortMappings []PortMapping `yaml:"extraPortMappings,omitempty"`
+}
+
+// PortMapping maps a port of the node container to a port on the host
+type PortMapping struct {
+	ContainerPort int `yaml:"containerPort"`
+	HostPort      int `yaml:"hostPort"`
+}
+
+// NewConfig creates the config of a kind cluster with the given number of workers. The HTTP and HTTPS ports of the
+// control plane node are mapped to localhost so that the ingress controller running on it is reachable there
+func NewConfig(workers int, image string) *Config {
+	config := &Config{
+		Kind:       "Cluster",
+		APIVersion: ConfigAPIVersion,
+		Nodes: []Node{
+			{
+				Role:  "control-plane",
+				Image: image,
+				ExtraPortMappings: []PortMapping{
+					{ContainerPort: 80, HostPort: 80},
+					{ContainerPort: 443, HostPort: 443},
+				},
+			},
+		},
+		// label the node whose ports are mapped so the ingress controller can be scheduled on it
+		KubeadmConfigPatches: []string{fmt.Sprintf(`apiVersion: kubeadm.k8s.io/v1beta2
+kind: InitConfiguration
+nodeRegistration:
+  kubeletExtraArgs:
+    node-labels: "%s=true"
+`, IngressReadyLabel)},
+	}
+	for i := 0; i < workers; i++ {
+		config.Nodes = append(config.Nodes, Node{Role: "worker", Image: image})
+	}
+	return config
+}
+
+// String returns the YAML of the config
+func (c *Config) String() (string, error) {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// ContextName returns the name of the kube context kind creates for the cluster
+func ContextName(clusterName string) string {
+	return contextPrefix + clusterName
+}
+
+// IsKindContext returns true if the kube context is one created by kind
+func IsKindContext(context string) bool {
+	return strings.HasPrefix(context, contextPrefix)
+}
+
+// IngressControllerValues returns the helm values of the nginx-ingress chart which run the controller on the node
+// whose ports are mapped to localhost, binding the controller to the HTTP and HTTPS ports of the node
+func IngressControllerValues() []string {
+	return []string{
+		"rbac.create=true",
+		"controller.hostPort.enabled=true",
+		"controller.service.type=NodePort",
+		"controller.nodeSelector." + IngressReadyLabel + "=true",
+		"controller.tolerations[0].key=node-role.kubernetes.io/master",
+		"controller.tolerations[0].operator=Equal",
+		"controller.tolerations[0].effect=NoSchedule",
+	}
+}
@@ -0,0 +1,42 @@
+package kind_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/cloud/kind"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestNewConfig(t *testing.T) {
+	t.Parallel()
+	config := kind.NewConfig(2, "")
+	text, err := config.String()
+	require.NoError(t, err)
+
+	parsed := &kind.Config{}
+	err = yaml.Unmarshal([]byte(text), parsed)
+	require.NoError(t, err)
+	assert.Equal(t, "Cluster", parsed.Kind)
+	assert.Equal(t, kind.ConfigAPIVersion, parsed.APIVersion)
+	require.Len(t, parsed.Nodes, 3)
+	assert.Equal(t, "control-plane", parsed.Nodes[0].Role)
+	assert.Equal(t, []kind.PortMapping{{ContainerPort: 80, HostPort: 80}, {ContainerPort: 443, HostPort: 443}}, parsed.Nodes[0].ExtraPortMappings)
+	assert.Equal(t, "worker", parsed.Nodes[2].Role)
+	assert.Empty(t, parsed.Nodes[2].ExtraPortMappings)
+	require.Len(t, parsed.KubeadmConfigPatches, 1)
+	assert.Contains(t, parsed.KubeadmConfigPatches[0], `node-labels: "ingress-ready=true"`)
+	assert.NotContains(t, text, "image:")
+
+	config = kind.NewConfig(0, "kindest/node:v1.15.6")
+	assert.Len(t, config.Nodes, 1)
+	assert.Equal(t, "kindest/node:v1.15.6", config.Nodes[0].Image)
+}
+
+func TestContextName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "kind-dev", kind.ContextName("dev"))
+	assert.True(t, kind.IsKindContext("kind-dev"))
+	assert.False(t, kind.IsKindContext("minikube"))
+}
@@ -0,0 +1,122 @@
+package minikube
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+const (
+	// DriverDocker runs the Minikube node as a Docker container
+	DriverDocker = "docker"
+	// DriverHyperkit the native hypervisor of macOS on Intel
+	DriverHyperkit = "hyperkit"
+	// DriverHyperV the native hypervisor of Windows
+	DriverHyperV = "hyperv"
+	// DriverKVM2 the native hypervisor of Linux, which needs /dev/kvm
+	DriverKVM2 = "kvm2"
+	// DriverVirtualBox the cross platform VirtualBox hypervisor
+	DriverVirtualBox = "virtualbox"
+	// DriverXhyve the deprecated macOS hypervisor
+	DriverXhyve = "xhyve"
+	// DriverNone runs Kubernetes directly on the Linux host
+	DriverNone = "none"
+
+	// LocalhostDomain the domain of the ingresses when the ingress controller is only reachable on localhost via
+	// `minikube tunnel`
+	LocalhostDomain = "127.0.0.1.nip.io"
+)
+
+var (
+	driverListRegex = regexp.MustCompile(`(?:VM )?[Dd]river is one of:?\s*\[?([^\]\n]*)`)
+	annotationRegex = regexp.MustCompile(`\([^)]*\)`)
+)
+
+// CandidateDrivers returns the drivers Minikube can use on the operating system and architecture, best first.
+// The hypervisors are preferred as they give the node a routable IP, falling back to the docker driver which also
+// works on Apple Silicon and on Linux without KVM
+func CandidateDrivers(goos string, goarch string) []string {
+	switch goos {
+	case "darwin":
+		if goarch != "amd64" {
+			return []string{DriverDocker}
+		}
+		return []string{DriverHyperkit, DriverDocker, DriverVirtualBox, DriverXhyve}
+	case "windows":
+		return []string{DriverHyperV, DriverDocker, DriverVirtualBox}
+	case "linux":
+		return []string{DriverKVM2, DriverDocker, DriverVirtualBox, DriverNone}
+	default:
+		return []string{DriverDocker, DriverVirtualBox}
+	}
+}
+
+// ParseSupportedDrivers parses the drivers listed in the description of the driver flag in the output of
+// `minikube start --help`, returning nil if they cannot be found
+func ParseSupportedDrivers(help string) []string {
+	m := driverListRegex.FindStringSubmatch(help)
+	if m == nil {
+		return nil
+	}
+	// remove annotations like (experimental) or (defaults to auto-detect)
+	list := annotationRegex.ReplaceAllString(m[1], "")
+	return strings.FieldsFunc(list, func(r rune) bool { return r == ',' || r == ' ' })
+}
+
+// ViableDrivers returns the candidate drivers, best first, which the installed Minikube supports and which are
+// available on the host. If supported is empty the drivers are not filtered by what Minikube supports
+func ViableDrivers(candidates []string, supported []string, available func(driver string) bool) []string {
+	answer := []string{}
+	for _, driver := range candidates {
+		if len(supported) > 0 && !util.Contains(supported, driver) {
+			continue
+		}
+		if available(driver) {
+			answer = append(answer, driver)
+		}
+	}
+	return answer
+}
+
+// Domain returns the domain of the ingresses of a Minikube cluster. The IP of the node is not reachable from the
+// host when the docker driver is used on macOS or Windows so the ingress controller is exposed on localhost via
+// `minikube tunnel` instead
+func Domain(ip string, driver string, goos string) string {
+	if NeedsTunnel(driver, goos) {
+		return LocalhostDomain
+	}
+	return ip + ".nip.io"
+}
+
+// NeedsTunnel returns true if the IP of the node is not reachable from the host so `minikube tunnel` is needed
+func NeedsTunnel(driver string, goos string) bool {
+	return driver == DriverDocker && goos != "linux"
+}
+
+// ParseProfileDriver parses the output of `minikube profile list -o json` returning the driver of the profile
+func ParseProfileDriver(data []byte, profile string) (string, error) {
+	profiles := struct {
+		Valid []struct {
+			Name   string
+			Config struct {
+				Driver   string
+				VMDriver string
+			}
+		} `json:"valid"`
+	}{}
+	err := json.Unmarshal(data, &profiles)
+	if err != nil {
+		return "", err
+	}
+	for _, p := range profiles.Valid {
+		if p.Name == profile {
+			if p.Config.Driver != "" {
+				return p.Config.Driver, nil
+			}
+			return p.Config.VMDriver, nil
+		}
+	}
+	return "", nil
+}
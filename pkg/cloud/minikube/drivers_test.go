@@ -0,0 +1,56 @@
+package minikube_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/cloud/minikube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSupportedDrivers(t *testing.T) {
+	t.Parallel()
+	help := `      --driver='': Driver is one of: virtualbox, parallels, vmwarefusion, hyperkit, vmware, docker, podman (experimental) (defaults to auto-detect)
+      --dry-run=false: dry-run mode.`
+	assert.Equal(t, []string{"virtualbox", "parallels", "vmwarefusion", "hyperkit", "vmware", "docker", "podman"}, minikube.ParseSupportedDrivers(help))
+
+	help = `      --vm-driver='virtualbox': VM driver is one of: [virtualbox parallels vmwarefusion kvm xhyve hyperv hyperkit kvm2 vmware none]`
+	assert.Equal(t, []string{"virtualbox", "parallels", "vmwarefusion", "kvm", "xhyve", "hyperv", "hyperkit", "kvm2", "vmware", "none"}, minikube.ParseSupportedDrivers(help))
+
+	assert.Nil(t, minikube.ParseSupportedDrivers("Usage: minikube start [flags]"))
+}
+
+func TestViableDrivers(t *testing.T) {
+	t.Parallel()
+	available := func(driver string) bool { return driver != minikube.DriverKVM2 }
+
+	// Linux without KVM falls back to docker
+	drivers := minikube.ViableDrivers(minikube.CandidateDrivers("linux", "amd64"), nil, available)
+	assert.Equal(t, []string{"docker", "virtualbox", "none"}, drivers)
+
+	// only drivers the installed Minikube supports
+	drivers = minikube.ViableDrivers(minikube.CandidateDrivers("linux", "amd64"), []string{"virtualbox", "kvm2", "none"}, available)
+	assert.Equal(t, []string{"virtualbox", "none"}, drivers)
+
+	// Apple Silicon can only use docker
+	assert.Equal(t, []string{"docker"}, minikube.CandidateDrivers("darwin", "arm64"))
+}
+
+func TestDomain(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "192.168.99.100.nip.io", minikube.Domain("192.168.99.100", minikube.DriverHyperkit, "darwin"))
+	assert.Equal(t, "192.168.49.2.nip.io", minikube.Domain("192.168.49.2", minikube.DriverDocker, "linux"))
+	assert.Equal(t, minikube.LocalhostDomain, minikube.Domain("192.168.49.2", minikube.DriverDocker, "darwin"))
+}
+
+func TestParseProfileDriver(t *testing.T) {
+	t.Parallel()
+	data := `{"invalid":[],"valid":[{"Name":"minikube","Status":"Running","Config":{"Name":"minikube","Driver":"docker"}}]}`
+	driver, err := minikube.ParseProfileDriver([]byte(data), "minikube")
+	require.NoError(t, err)
+	assert.Equal(t, "docker", driver)
+
+	driver, err = minikube.ParseProfileDriver([]byte(data), "other")
+	require.NoError(t, err)
+	assert.Equal(t, "", driver)
+}
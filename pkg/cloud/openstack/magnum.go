@@ -0,0 +1,77 @@
+package openstack
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+const (
+	// StatusCreateComplete the status of a Magnum cluster which has been created
+	StatusCreateComplete = "CREATE_COMPLETE"
+	// StatusCreateFailed the status of a Magnum cluster which failed to be created
+	StatusCreateFailed = "CREATE_FAILED"
+
+	// IngressOctavia exposes the ingress controller via a LoadBalancer service backed by an Octavia load balancer
+	IngressOctavia = "octavia"
+	// IngressNodePort exposes the ingress controller on the HTTP and HTTPS ports of the nodes, for clouds without
+	// Octavia
+	IngressNodePort = "nodeport"
+)
+
+var (
+	// Ingresses the strategies for exposing the ingress controller of a Magnum cluster
+	Ingresses = []string{IngressOctavia, IngressNodePort}
+)
+
+// Cluster the fields of `openstack coe cluster show -f json` jx uses
+type Cluster struct {
+	Name          string   `json:"name"`
+	Status        string   `json:"status"`
+	StatusReason  string   `json:"status_reason"`
+	NodeAddresses []string `json:"node_addresses"`
+}
+
+// CreateClusterArgs returns the arguments of the `openstack` command which creates the Magnum cluster from the
+// cluster template
+func CreateClusterArgs(clusterName string, template string, nodeCount int, masterCount int, keypair string) []string {
+	args := []string{"coe", "cluster", "create", clusterName,
+		"--cluster-template", template,
+		"--node-count", strconv.Itoa(nodeCount),
+		"--master-count", strconv.Itoa(masterCount),
+	}
+	if keypair != "" {
+		args = append(args, "--keypair", keypair)
+	}
+	return args
+}
+
+// ParseCluster parses the output of `openstack coe cluster show -f json`
+func ParseCluster(data []byte) (*Cluster, error) {
+	cluster := &Cluster{}
+	err := json.Unmarshal(data, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse the Magnum cluster due to %s", err)
+	}
+	return cluster, nil
+}
+
+// ContextName returns the name of the kube context of the Magnum cluster
+func ContextName(clusterName string) string {
+	return "openstack-" + clusterName
+}
+
+// IngressControllerValues returns the helm values of the nginx-ingress chart for the ingress strategy. With node
+// ports the controller runs on every node bound to the HTTP and HTTPS ports of the node
+func IngressControllerValues(ingress string) []string {
+	if ingress != IngressNodePort {
+		return nil
+	}
+	return []string{
+		"rbac.create=true",
+		"controller.kind=DaemonSet",
+		"controller.hostNetwork=true",
+		"controller.dnsPolicy=ClusterFirstWithHostNet",
+		"controller.service.type=ClusterIP",
+	}
+}
@@ -0,0 +1,36 @@
+package openstack_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/cloud/openstack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateClusterArgs(t *testing.T) {
+	t.Parallel()
+	args := openstack.CreateClusterArgs("dev", "k8s-template", 3, 1, "")
+	assert.Equal(t, "coe cluster create dev --cluster-template k8s-template --node-count 3 --master-count 1", strings.Join(args, " "))
+
+	args = openstack.CreateClusterArgs("dev", "k8s-template", 3, 3, "mykey")
+	assert.Equal(t, "coe cluster create dev --cluster-template k8s-template --node-count 3 --master-count 3 --keypair mykey", strings.Join(args, " "))
+}
+
+func TestParseCluster(t *testing.T) {
+	t.Parallel()
+	cluster, err := openstack.ParseCluster([]byte(`{"name": "dev", "status": "CREATE_COMPLETE", "status_reason": "Stack CREATE completed successfully", "node_addresses": ["10.0.0.5", "10.0.0.6"]}`))
+	require.NoError(t, err)
+	assert.Equal(t, openstack.StatusCreateComplete, cluster.Status)
+	assert.Equal(t, []string{"10.0.0.5", "10.0.0.6"}, cluster.NodeAddresses)
+
+	_, err = openstack.ParseCluster([]byte(`nope`))
+	assert.Error(t, err)
+}
+
+func TestIngressControllerValues(t *testing.T) {
+	t.Parallel()
+	assert.Empty(t, openstack.IngressControllerValues(openstack.IngressOctavia))
+	assert.Contains(t, openstack.IngressControllerValues(openstack.IngressNodePort), "controller.hostNetwork=true")
+}
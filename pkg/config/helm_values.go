@@ -13,14 +13,29 @@ type ExposeControllerConfig struct {
 	HTTP     string `yaml:"http"`
 	TLSAcme  string `yaml:"tlsacme"`
 	PathMode string `yaml:"pathMode"`
+	// TLSSecretName the secret of the certificate used by all the ingresses instead of one issued by cert-manager
+	TLSSecretName string `yaml:"tlsSecretName,omitempty"`
 }
 type ExposeController struct {
 	Config      ExposeControllerConfig `yaml:"config,omitempty"`
 	Annotations map[string]string      `yaml:"Annotations,omitempty"`
+	RBAC        RBACValuesConfig       `yaml:"rbac,omitempty"`
+}
+
+// RBACValuesConfig configures the RBAC resources a chart creates
+type RBACValuesConfig struct {
+	// Cluster creates ClusterRoles and ClusterRoleBindings rather than Roles and RoleBindings when not false
+	Cluster *bool `yaml:"cluster,omitempty"`
+}
+
+// ControllerValuesConfig configures the chart of a jx controller
+type ControllerValuesConfig struct {
+	RBAC RBACValuesConfig `yaml:"rbac,omitempty"`
 }
 
 type JenkinsValuesConfig struct {
 	Servers JenkinsServersValuesConfig `yaml:"Servers,omitempty"`
+	RBAC    RBACValuesConfig           `yaml:"rbac,omitempty"`
 }
 
 type ProwValuesConfig struct {
@@ -59,6 +74,10 @@ type HelmValuesConfig struct {
 	Jenkins          JenkinsValuesConfig                `yaml:"jenkins,omitempty"`
 	Prow             ProwValuesConfig                   `yaml:"prow,omitempty"`
 	PipelineSecrets  JenkinsPipelineSecretsValuesConfig `yaml:"PipelineSecrets,omitempty"`
+
+	ControllerBuild    ControllerValuesConfig `yaml:"controllerbuild,omitempty"`
+	ControllerTeam     ControllerValuesConfig `yaml:"controllerteam,omitempty"`
+	ControllerWorkflow ControllerValuesConfig `yaml:"controllerworkflow,omitempty"`
 }
 
 type HelmValuesConfigService struct {
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// DefaultDockerRegistry the registry of images which do not name one
+	DefaultDockerRegistry = "docker.io"
+)
+
+// OfflineConfig maps the upstream chart repositories, docker registries and binary download hosts which are
+// unreachable from an air-gapped network to their internal mirrors
+type OfflineConfig struct {
+	// ChartRepositories maps the URLs of upstream chart repositories to the URLs of their mirrors
+	ChartRepositories map[string]string `yaml:"chartRepositories,omitempty"`
+	// DockerRegistries maps upstream registry hosts, such as gcr.io or docker.io, to the image prefix of their mirror
+	DockerRegistries map[string]string `yaml:"dockerRegistries,omitempty"`
+	// BinaryDownloadURL is the base URL of the mirror of the binary downloads, which serves each file under the
+	// host and path of its upstream URL
+	BinaryDownloadURL string `yaml:"binaryDownloadURL,omitempty"`
+}
+
+// LoadOfflineConfig loads the offline configuration file
+func LoadOfflineConfig(fileName string) (*OfflineConfig, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load file %s due to %s", fileName, err)
+	}
+	config := &OfflineConfig{}
+	err = yaml.Unmarshal(data, config)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal YAML file %s due to %s", fileName, err)
+	}
+	return config, nil
+}
+
+// ChartRepositoryURL returns the URL of the mirror of the chart repository or the URL itself if it is not mirrored
+func (c *OfflineConfig) ChartRepositoryURL(url string) string {
+	if c == nil {
+		return url
+	}
+	mirror := c.ChartRepositories[strings.TrimSuffix(url, "/")]
+	if mirror == "" {
+		mirror = c.ChartRepositories[url]
+	}
+	if mirror == "" {
+		return url
+	}
+	return mirror
+}
+
+// ImageName returns the name of the image in the mirror of its registry or the image itself if it is not mirrored
+func (c *OfflineConfig) ImageName(image string) string {
+	if c == nil || image == "" {
+		return image
+	}
+	registry := DefaultDockerRegistry
+	name := image
+	paths := strings.SplitN(image, "/", 2)
+	if len(paths) == 2 && (strings.ContainsAny(paths[0], ".:") || paths[0] == "localhost") {
+		registry = paths[0]
+		name = paths[1]
+	}
+	prefix := c.DockerRegistries[registry]
+	if prefix == "" {
+		return image
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + name
+}
+
+// ImageValues returns the helm values which replace the images referenced by the given values with their mirrors,
+// using the image and image.repository conventions of charts, or an empty map if none of them are mirrored
+func (c *OfflineConfig) ImageValues(values map[string]interface{}) map[string]interface{} {
+	answer := map[string]interface{}{}
+	for k, v := range values {
+		switch value := v.(type) {
+		case string:
+			if isImageKey(k) {
+				mirror := c.ImageName(value)
+				if mirror != value {
+					answer[k] = mirror
+				}
+			}
+		case map[string]interface{}:
+			var child map[string]interface{}
+			if repository, ok := value["repository"].(string); ok && isImageKey(k) {
+				child = map[string]interface{}{}
+				mirror := c.ImageName(repository)
+				if mirror != repository {
+					child["repository"] = mirror
+				}
+			} else {
+				child = c.ImageValues(value)
+			}
+			if len(child) > 0 {
+				answer[k] = child
+			}
+		}
+	}
+	return answer
+}
+
+func isImageKey(key string) bool {
+	return strings.ToLower(key) == "image"
+}
@@ -0,0 +1,86 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfflineConfig(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-offline-config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, "offline.yaml")
+	err = ioutil.WriteFile(fileName, []byte(`chartRepositories:
+  https://chartmuseum.jenkins-x.io: https://nexus.example.com/repository/jenkins-x
+dockerRegistries:
+  gcr.io: registry.example.com/gcr
+  docker.io: registry.example.com/dockerhub/
+binaryDownloadURL: https://nexus.example.com/repository/binaries
+`), 0644)
+	require.NoError(t, err)
+
+	c, err := config.LoadOfflineConfig(fileName)
+	require.NoError(t, err)
+	assert.Equal(t, "https://nexus.example.com/repository/binaries", c.BinaryDownloadURL)
+
+	assert.Equal(t, "https://nexus.example.com/repository/jenkins-x", c.ChartRepositoryURL("https://chartmuseum.jenkins-x.io/"))
+	assert.Equal(t, "https://kubernetes-charts.storage.googleapis.com", c.ChartRepositoryURL("https://kubernetes-charts.storage.googleapis.com"))
+
+	assert.Equal(t, "registry.example.com/gcr/jenkinsxio/builder-go:0.1.2", c.ImageName("gcr.io/jenkinsxio/builder-go:0.1.2"))
+	assert.Equal(t, "registry.example.com/dockerhub/jenkinsxio/jenkinsx:0.0.3", c.ImageName("jenkinsxio/jenkinsx:0.0.3"))
+	assert.Equal(t, "registry.example.com/dockerhub/nginx", c.ImageName("nginx"))
+	assert.Equal(t, "quay.io/coreos/etcd:v3", c.ImageName("quay.io/coreos/etcd:v3"))
+
+	var nilConfig *config.OfflineConfig
+	assert.Equal(t, "nginx", nilConfig.ImageName("nginx"))
+	assert.Equal(t, "https://chartmuseum.jenkins-x.io", nilConfig.ChartRepositoryURL("https://chartmuseum.jenkins-x.io"))
+}
+
+func TestOfflineConfigImageValues(t *testing.T) {
+	t.Parallel()
+	c := &config.OfflineConfig{
+		DockerRegistries: map[string]string{"gcr.io": "registry.example.com/gcr"},
+	}
+	values := map[string]interface{}{
+		"jenkins": map[string]interface{}{
+			"Master": map[string]interface{}{
+				"Image":    "gcr.io/jenkinsxio/jenkinsx",
+				"ImageTag": "0.0.3",
+			},
+		},
+		"nexus": map[string]interface{}{
+			"image": map[string]interface{}{
+				"repository": "gcr.io/jenkinsxio/nexus",
+				"tag":        "0.1.0",
+			},
+		},
+		"chartmuseum": map[string]interface{}{
+			"image": map[string]interface{}{
+				"repository": "chartmuseum/chartmuseum",
+			},
+		},
+		"domain": "gcr.io/not-an-image",
+	}
+
+	expected := map[string]interface{}{
+		"jenkins": map[string]interface{}{
+			"Master": map[string]interface{}{
+				"Image": "registry.example.com/gcr/jenkinsxio/jenkinsx",
+			},
+		},
+		"nexus": map[string]interface{}{
+			"image": map[string]interface{}{
+				"repository": "registry.example.com/gcr/jenkinsxio/nexus",
+			},
+		},
+	}
+	assert.Equal(t, expected, c.ImageValues(values))
+}
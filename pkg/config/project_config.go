@@ -30,6 +30,15 @@ type ProjectConfig struct {
 	BuildPackGitURL     string                    `yaml:"buildPackGitURL,omitempty"`
 	BuildPackGitURef    string                    `yaml:"buildPackGitRef,omitempty"`
 	Workflow            string                    `yaml:"workflow,omitempty"`
+	Release             *ReleaseConfig            `yaml:"release,omitempty"`
+}
+
+// ReleaseConfig the configuration of the versions of the releases of the project
+type ReleaseConfig struct {
+	// SemVer the part of the semantic version bumped by a release which is one of major, minor, patch or auto
+	SemVer string `yaml:"semver,omitempty"`
+	// PreRelease the identifier of the pre-release versions such as rc
+	PreRelease string `yaml:"preRelease,omitempty"`
 }
 
 type PreviewEnvironmentConfig struct {
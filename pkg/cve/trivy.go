@@ -0,0 +1,122 @@
+package cve
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Severities the severities of the vulnerabilities found by Trivy from the lowest to the highest
+var Severities = []string{"UNKNOWN", "LOW", "MEDIUM", "HIGH", "CRITICAL"}
+
+// TrivyReport the JSON report of a Trivy image scan
+type TrivyReport struct {
+	Results []TrivyResult `json:"Results,omitempty"`
+}
+
+// TrivyResult the vulnerabilities of a target of a Trivy scan such as the OS packages or the dependencies of an
+// application in the image
+type TrivyResult struct {
+	Target          string               `json:"Target,omitempty"`
+	Type            string               `json:"Type,omitempty"`
+	Vulnerabilities []TrivyVulnerability `json:"Vulnerabilities,omitempty"`
+}
+
+// TrivyVulnerability a vulnerability of a package found by Trivy
+type TrivyVulnerability struct {
+	VulnerabilityID  string `json:"VulnerabilityID,omitempty"`
+	PkgName          string `json:"PkgName,omitempty"`
+	InstalledVersion string `json:"InstalledVersion,omitempty"`
+	FixedVersion     string `json:"FixedVersion,omitempty"`
+	Title            string `json:"Title,omitempty"`
+	Severity         string `json:"Severity,omitempty"`
+}
+
+// ParseTrivyReport parses the JSON report of Trivy which is either an object with the results or, for older versions
+// of Trivy, the array of results
+func ParseTrivyReport(data []byte) (*TrivyReport, error) {
+	answer := &TrivyReport{}
+	text := strings.TrimSpace(string(data))
+	var err error
+	if strings.HasPrefix(text, "[") {
+		err = json.Unmarshal(data, &answer.Results)
+	} else if text != "" && text != "null" {
+		err = json.Unmarshal(data, answer)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the Trivy report: %s", err)
+	}
+	return answer, nil
+}
+
+// ParseSeverity returns the upper case severity of the text or an error if it is not a Trivy severity
+func ParseSeverity(text string) (string, error) {
+	severity := strings.ToUpper(strings.TrimSpace(text))
+	if severityIndex(severity) < 0 {
+		return "", fmt.Errorf("invalid severity %s, it must be one of %s", text, strings.ToLower(strings.Join(Severities, ", ")))
+	}
+	return severity, nil
+}
+
+func severityIndex(severity string) int {
+	for i, s := range Severities {
+		if s == severity {
+			return i
+		}
+	}
+	return -1
+}
+
+// severity returns the upper case severity of the vulnerability treating severities Trivy does not document as
+// unknown
+func (v *TrivyVulnerability) severity() string {
+	severity := strings.ToUpper(v.Severity)
+	if severityIndex(severity) < 0 {
+		return "UNKNOWN"
+	}
+	return severity
+}
+
+// SeverityCounts returns the number of vulnerabilities for each severity
+func (r *TrivyReport) SeverityCounts() map[string]int {
+	answer := map[string]int{}
+	for _, result := range r.Results {
+		for _, v := range result.Vulnerabilities {
+			answer[v.severity()]++
+		}
+	}
+	return answer
+}
+
+// VulnerabilitiesAtOrAbove returns the vulnerabilities whose severity is the same as or higher than the given severity
+func (r *TrivyReport) VulnerabilitiesAtOrAbove(severity string) []TrivyVulnerability {
+	answer := []TrivyVulnerability{}
+	threshold := severityIndex(strings.ToUpper(severity))
+	if threshold < 0 {
+		return answer
+	}
+	for _, result := range r.Results {
+		for _, v := range result.Vulnerabilities {
+			if severityIndex(v.severity()) >= threshold {
+				answer = append(answer, v)
+			}
+		}
+	}
+	return answer
+}
+
+// SeveritySummary returns a summary of the number of vulnerabilities of each severity from the highest severity such
+// as "1 CRITICAL, 3 HIGH"
+func SeveritySummary(counts map[string]int) string {
+	parts := []string{}
+	for i := len(Severities) - 1; i >= 0; i-- {
+		count := counts[Severities[i]]
+		if count > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", count, Severities[i]))
+		}
+	}
+	if len(parts) == 0 {
+		return "no vulnerabilities"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,65 @@
+package cve_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/cve"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTrivyReport(t *testing.T) {
+	t.Parallel()
+	data, err := ioutil.ReadFile(filepath.Join("test_data", "trivy", "report.json"))
+	require.NoError(t, err)
+	report, err := cve.ParseTrivyReport(data)
+	require.NoError(t, err)
+
+	counts := report.SeverityCounts()
+	assert.Equal(t, map[string]int{"CRITICAL": 1, "HIGH": 1, "MEDIUM": 1}, counts)
+	assert.Equal(t, "1 CRITICAL, 1 HIGH, 1 MEDIUM", cve.SeveritySummary(counts))
+
+	ids := []string{}
+	for _, v := range report.VulnerabilitiesAtOrAbove("HIGH") {
+		ids = append(ids, v.VulnerabilityID)
+	}
+	assert.Equal(t, []string{"CVE-2019-14697", "CVE-2020-8203"}, ids)
+	assert.Len(t, report.VulnerabilitiesAtOrAbove("CRITICAL"), 1)
+	assert.Len(t, report.VulnerabilitiesAtOrAbove("UNKNOWN"), 3)
+}
+
+func TestParseTrivyReportOfOlderVersions(t *testing.T) {
+	t.Parallel()
+	report, err := cve.ParseTrivyReport([]byte(`[{"Target": "myapp:0.0.1 (debian 10.1)", "Vulnerabilities": [{"VulnerabilityID": "CVE-2019-5094", "Severity": "LOW"}]}]`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"LOW": 1}, report.SeverityCounts())
+
+	// older versions report nothing for images without vulnerabilities
+	report, err = cve.ParseTrivyReport([]byte("null"))
+	require.NoError(t, err)
+	assert.Equal(t, "no vulnerabilities", cve.SeveritySummary(report.SeverityCounts()))
+
+	_, err = cve.ParseTrivyReport([]byte("{"))
+	assert.Error(t, err)
+}
+
+func TestParseSeverity(t *testing.T) {
+	t.Parallel()
+	severity, err := cve.ParseSeverity(" critical")
+	require.NoError(t, err)
+	assert.Equal(t, "CRITICAL", severity)
+
+	_, err = cve.ParseSeverity("severe")
+	assert.Error(t, err)
+}
+
+func TestUnrecognisedSeveritiesAreUnknown(t *testing.T) {
+	t.Parallel()
+	report, err := cve.ParseTrivyReport([]byte(`{"Results": [{"Target": "myapp:0.0.1 (alpine 3.10.2)", "Vulnerabilities": [{"VulnerabilityID": "CVE-2019-1", "Severity": "NEGLIGIBLE"}, {"VulnerabilityID": "CVE-2019-2", "Severity": "low"}]}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"UNKNOWN": 1, "LOW": 1}, report.SeverityCounts())
+	assert.Len(t, report.VulnerabilitiesAtOrAbove("UNKNOWN"), 2, "the vulnerability of an unrecognised severity fails a scan on unknown")
+	assert.Len(t, report.VulnerabilitiesAtOrAbove("LOW"), 1)
+}
@@ -0,0 +1,493 @@
+package draft
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+const (
+	// RustPack the name of the pack built into jx for Rust projects built with Cargo
+	RustPack = "rust"
+	// DotNetPack the name of the pack built into jx for .NET Core projects
+	DotNetPack = "dotnet"
+
+	// BuiltinPacksDirName the name of the directory in the draft home the packs built into jx are written to
+	BuiltinPacksDirName = "builtin-packs"
+
+	exposecontrollerVersion = "2.3.63"
+)
+
+// builtinPacks the files of the packs built into jx by pack name then slash separated path in the pack
+var builtinPacks = map[string]map[string]string{
+	RustPack:   packFiles(rustDockerfile, rustDockerIgnore, jenkinsfile("jenkins-rust", "rust", `sh "cargo test"`), "A Helm chart for a Rust application"),
+	DotNetPack: packFiles(dotNetDockerfile, dotNetDockerIgnore, jenkinsfile("jenkins-jx-base", "jx-base", ""), "A Helm chart for a .NET Core application"),
+}
+
+// BuiltinPackNames returns the sorted names of the packs built into jx
+func BuiltinPackNames() []string {
+	answer := []string{}
+	for name := range builtinPacks {
+		answer = append(answer, name)
+	}
+	sort.Strings(answer)
+	return answer
+}
+
+// WriteBuiltinPacks writes the packs built into jx into a sub directory of the directory named after each pack,
+// replacing any previously written version of the packs
+func WriteBuiltinPacks(dir string) error {
+	for name, files := range builtinPacks {
+		packDir := filepath.Join(dir, name)
+		err := os.RemoveAll(packDir)
+		if err != nil {
+			return fmt.Errorf("failed to remove the old pack %s: %s", packDir, err)
+		}
+		for path, text := range files {
+			file := filepath.Join(packDir, filepath.FromSlash(path))
+			err = os.MkdirAll(filepath.Dir(file), util.DefaultWritePermissions)
+			if err != nil {
+				return fmt.Errorf("failed to create the directory of %s: %s", file, err)
+			}
+			err = ioutil.WriteFile(file, []byte(text), util.DefaultWritePermissions)
+			if err != nil {
+				return fmt.Errorf("failed to write %s: %s", file, err)
+			}
+		}
+	}
+	return nil
+}
+
+// DetectBuiltinPack returns the name of the pack built into jx which builds the project in the directory or an empty
+// string if the project is not a Rust or .NET Core project
+func DetectBuiltinPack(dir string) (string, error) {
+	exists, err := util.FileExists(filepath.Join(dir, "Cargo.toml"))
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return RustPack, nil
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	for _, f := range files {
+		ext := filepath.Ext(f.Name())
+		if !f.IsDir() && (ext == ".csproj" || ext == ".fsproj") {
+			return DotNetPack, nil
+		}
+	}
+	return "", nil
+}
+
+// packFiles returns the files of a pack with the Dockerfile and a chart and preview chart deploying the image on
+// port 8080
+func packFiles(dockerfile string, dockerIgnore string, jenkinsfile string, chartDescription string) map[string]string {
+	return map[string]string{
+		"Dockerfile":                       dockerfile,
+		".dockerignore":                    dockerIgnore,
+		"Jenkinsfile":                      jenkinsfile,
+		"skaffold.yaml":                    skaffoldYaml,
+		"charts/Chart.yaml":                fmt.Sprintf(chartYaml, chartDescription),
+		"charts/values.yaml":               chartValuesYaml,
+		"charts/.helmignore":               chartHelmIgnore,
+		"charts/templates/_helpers.tpl":    chartHelpersTpl,
+		"charts/templates/deployment.yaml": chartDeploymentYaml,
+		"charts/templates/service.yaml":    chartServiceYaml,
+		"charts/templates/NOTES.txt":       chartNotesTxt,
+		"preview/Chart.yaml":               previewChartYaml,
+		"preview/requirements.yaml":        fmt.Sprintf(previewRequirementsYaml, exposecontrollerVersion, exposecontrollerVersion),
+		"preview/values.yaml":              previewValuesYaml,
+		"preview/Makefile":                 previewMakefile,
+	}
+}
+
+// jenkinsfile returns the declarative pipeline of a pack which runs the test step then builds the image with skaffold
+// in the container of the pod template
+func jenkinsfile(label string, container string, testStep string) string {
+	testLines := ""
+	if testStep != "" {
+		testLines = "\n          " + testStep
+	}
+	return strings.Replace(strings.Replace(strings.Replace(jenkinsfileTemplate,
+		"$LABEL", label, -1),
+		"$CONTAINER", container, -1),
+		"$TEST_STEP", testLines, -1)
+}
+
+const rustDockerfile = `FROM rust:1.31 AS build
+WORKDIR /src
+COPY . .
+RUN cargo build --release && \
+    cp $(find target/release -maxdepth 1 -type f -perm -u+x | head -n 1) /app
+
+FROM debian:stretch-slim
+RUN apt-get update && apt-get install -y --no-install-recommends ca-certificates && rm -rf /var/lib/apt/lists/*
+COPY --from=build /app /usr/local/bin/app
+ENV PORT 8080
+EXPOSE 8080
+CMD ["/usr/local/bin/app"]
+`
+
+const rustDockerIgnore = `.git
+target
+`
+
+const dotNetDockerfile = `FROM microsoft/dotnet:2.2-sdk AS build
+WORKDIR /src
+COPY . .
+RUN dotnet restore
+RUN dotnet test
+RUN dotnet publish -c Release -o /app
+
+FROM microsoft/dotnet:2.2-aspnetcore-runtime
+WORKDIR /app
+COPY --from=build /app .
+ENV ASPNETCORE_URLS http://+:8080
+EXPOSE 8080
+ENTRYPOINT ["sh", "-c", "exec dotnet $(basename $(ls *.runtimeconfig.json | head -n 1) .runtimeconfig.json).dll"]
+`
+
+const dotNetDockerIgnore = `.git
+bin
+obj
+`
+
+const jenkinsfileTemplate = `pipeline {
+  agent {
+    label "$LABEL"
+  }
+  environment {
+    ORG = 'REPLACE_ME_ORG'
+    APP_NAME = 'REPLACE_ME_APP_NAME'
+    CHARTMUSEUM_CREDS = credentials('jenkins-x-chartmuseum')
+  }
+  stages {
+    stage('CI Build and push snapshot') {
+      when {
+        branch 'PR-*'
+      }
+      environment {
+        PREVIEW_VERSION = "0.0.0-SNAPSHOT-$BRANCH_NAME-$BUILD_NUMBER"
+        PREVIEW_NAMESPACE = "$APP_NAME-$BRANCH_NAME".toLowerCase()
+        HELM_RELEASE = "$PREVIEW_NAMESPACE".toLowerCase()
+      }
+      steps {
+        container('$CONTAINER') {
+          sh "jx step git status --state pending"$TEST_STEP
+          sh "export VERSION=$PREVIEW_VERSION && skaffold build -f skaffold.yaml"
+          sh "jx step post build --image $DOCKER_REGISTRY/$ORG/$APP_NAME:$PREVIEW_VERSION"
+          sh "jx step scan image --image $DOCKER_REGISTRY/$ORG/$APP_NAME:$PREVIEW_VERSION"
+          dir('./charts/preview') {
+            sh "make preview"
+            sh "jx preview --app $APP_NAME --dir ../.."
+          }
+        }
+      }
+    }
+    stage('Build Release') {
+      when {
+        branch 'master'
+      }
+      steps {
+        container('$CONTAINER') {
+          // ensure we're not on a detached head
+          sh "git checkout master"
+          sh "git config --global credential.helper store"
+          sh "jx step git credentials"
+          sh "jx step git status --state pending"
+
+          // so we can retrieve the version in later steps
+          sh "echo \$(jx-release-version) > VERSION"
+          sh "jx step tag --version \$(cat VERSION)"$TEST_STEP
+          sh "export VERSION=\$(cat VERSION) && skaffold build -f skaffold.yaml"
+          sh "jx step post build --image $DOCKER_REGISTRY/$ORG/$APP_NAME:\$(cat VERSION)"
+          sh "jx step scan image --image $DOCKER_REGISTRY/$ORG/$APP_NAME:\$(cat VERSION)"
+        }
+      }
+    }
+    stage('Promote to Environments') {
+      when {
+        branch 'master'
+      }
+      steps {
+        container('$CONTAINER') {
+          dir('./charts/REPLACE_ME_APP_NAME') {
+            sh "jx step changelog --version v\$(cat ../../VERSION)"
+
+            // release the helm chart
+            sh "jx step helm release"
+
+            // promote through all 'Auto' promotion Environments
+            sh "jx promote -b --all-auto --timeout 1h --version \$(cat ../../VERSION)"
+          }
+        }
+      }
+    }
+  }
+  post {
+    success {
+      container('$CONTAINER') {
+        sh "jx step git status --state success"
+      }
+    }
+    failure {
+      container('$CONTAINER') {
+        sh "jx step git status --state failure"
+      }
+    }
+    always {
+      cleanWs()
+    }
+  }
+}
+`
+
+const skaffoldYaml = `apiVersion: skaffold/v1alpha2
+kind: Config
+build:
+  tagPolicy:
+    envTemplate:
+      template: "{{.DOCKER_REGISTRY}}/REPLACE_ME_ORG/REPLACE_ME_APP_NAME:{{.VERSION}}"
+  artifacts:
+  - imageName: changeme
+    workspace: .
+    docker: {}
+  local: {}
+deploy:
+  kubectl: {}
+profiles:
+- name: dev
+  build:
+    tagPolicy:
+      envTemplate:
+        template: "{{.DOCKER_REGISTRY}}/REPLACE_ME_ORG/REPLACE_ME_APP_NAME:{{.DIGEST_HEX}}"
+    artifacts:
+    - docker: {}
+    local: {}
+  deploy:
+    helm:
+      releases:
+      - name: REPLACE_ME_APP_NAME
+        chartPath: charts/REPLACE_ME_APP_NAME
+        setValueTemplates:
+          image.repository: "{{.DOCKER_REGISTRY}}/REPLACE_ME_ORG/REPLACE_ME_APP_NAME"
+          image.tag: "{{.DIGEST_HEX}}"
+`
+
+const chartYaml = `apiVersion: v1
+description: %s
+icon: https://raw.githubusercontent.com/jenkins-x/jenkins-x-platform/d273e09/images/jx.png
+name: REPLACE_ME_APP_NAME
+version: 0.1.0-SNAPSHOT
+`
+
+const chartValuesYaml = `# Default values for the chart.
+# This is a YAML-formatted file.
+# Declare variables to be passed into your templates.
+replicaCount: 1
+image:
+  repository: draft
+  tag: dev
+  pullPolicy: IfNotPresent
+service:
+  name: REPLACE_ME_APP_NAME
+  type: ClusterIP
+  externalPort: 80
+  internalPort: 8080
+  annotations:
+    fabric8.io/expose: "true"
+resources:
+  limits:
+    cpu: 500m
+    memory: 256Mi
+  requests:
+    cpu: 100m
+    memory: 128Mi
+probePath: /
+livenessProbe:
+  initialDelaySeconds: 60
+  periodSeconds: 10
+  successThreshold: 1
+  timeoutSeconds: 1
+readinessProbe:
+  periodSeconds: 10
+  successThreshold: 1
+  timeoutSeconds: 1
+terminationGracePeriodSeconds: 10
+`
+
+const chartHelmIgnore = `# Patterns to ignore when building packages.
+.git/
+.gitignore
+*.swp
+*.bak
+*.tmp
+*~
+`
+
+const chartHelpersTpl = `{{/* vim: set filetype=mustache: */}}
+{{/*
+Expand the name of the chart.
+*/}}
+{{- define "name" -}}
+{{- default .Chart.Name .Values.nameOverride | trunc 63 | trimSuffix "-" -}}
+{{- end -}}
+
+{{/*
+Create a default fully qualified app name.
+We truncate at 63 chars because some Kubernetes name fields are limited to this (by the DNS naming spec).
+*/}}
+{{- define "fullname" -}}
+{{- $name := default .Chart.Name .Values.nameOverride -}}
+{{- printf "%s-%s" .Release.Name $name | trunc 63 | trimSuffix "-" -}}
+{{- end -}}
+`
+
+const chartDeploymentYaml = `apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  name: {{ template "fullname" . }}
+  labels:
+    draft: {{ default "draft-app" .Values.draft }}
+    chart: "{{ .Chart.Name }}-{{ .Chart.Version | replace "+" "_" }}"
+spec:
+  replicas: {{ .Values.replicaCount }}
+  template:
+    metadata:
+      labels:
+        draft: {{ default "draft-app" .Values.draft }}
+        app: {{ template "fullname" . }}
+    spec:
+      containers:
+      - name: {{ .Chart.Name }}
+        image: "{{ .Values.image.repository }}:{{ .Values.image.tag }}"
+        imagePullPolicy: {{ .Values.image.pullPolicy }}
+        env:
+        - name: PORT
+          value: "{{ .Values.service.internalPort }}"
+        ports:
+        - containerPort: {{ .Values.service.internalPort }}
+        livenessProbe:
+          httpGet:
+            path: {{ .Values.probePath }}
+            port: {{ .Values.service.internalPort }}
+          initialDelaySeconds: {{ .Values.livenessProbe.initialDelaySeconds }}
+          periodSeconds: {{ .Values.livenessProbe.periodSeconds }}
+          successThreshold: {{ .Values.livenessProbe.successThreshold }}
+          timeoutSeconds: {{ .Values.livenessProbe.timeoutSeconds }}
+        readinessProbe:
+          httpGet:
+            path: {{ .Values.probePath }}
+            port: {{ .Values.service.internalPort }}
+          periodSeconds: {{ .Values.readinessProbe.periodSeconds }}
+          successThreshold: {{ .Values.readinessProbe.successThreshold }}
+          timeoutSeconds: {{ .Values.readinessProbe.timeoutSeconds }}
+        resources:
+{{ toYaml .Values.resources | indent 12 }}
+      terminationGracePeriodSeconds: {{ .Values.terminationGracePeriodSeconds }}
+`
+
+const chartServiceYaml = `apiVersion: v1
+kind: Service
+metadata:
+{{- if .Values.service.name }}
+  name: {{ .Values.service.name }}
+{{- else }}
+  name: {{ template "fullname" . }}
+{{- end }}
+  labels:
+    chart: "{{ .Chart.Name }}-{{ .Chart.Version | replace "+" "_" }}"
+{{- if .Values.service.annotations }}
+  annotations:
+{{ toYaml .Values.service.annotations | indent 4 }}
+{{- end }}
+spec:
+  type: {{ .Values.service.type }}
+  ports:
+  - port: {{ .Values.service.externalPort }}
+    targetPort: {{ .Values.service.internalPort }}
+    protocol: TCP
+    name: http
+  selector:
+    app: {{ template "fullname" . }}
+`
+
+const chartNotesTxt = `
+Get the application URL by running these commands:
+
+kubectl get ingress {{ template "fullname" . }}
+`
+
+const previewChartYaml = `apiVersion: v1
+description: A Helm chart for Kubernetes
+icon: https://raw.githubusercontent.com/jenkins-x/jenkins-x-platform/d273e09/images/jx.png
+name: preview
+version: 0.1.0-SNAPSHOT
+`
+
+const previewRequirementsYaml = `
+dependencies:
+- alias: expose
+  name: exposecontroller
+  repository: https://chartmuseum.build.cd.jenkins-x.io
+  version: %s
+- alias: cleanup
+  name: exposecontroller
+  repository: https://chartmuseum.build.cd.jenkins-x.io
+  version: %s
+- alias: preview
+  name: REPLACE_ME_APP_NAME
+  repository: file://../REPLACE_ME_APP_NAME
+`
+
+const previewValuesYaml = `
+expose:
+  Annotations:
+    helm.sh/hook: post-install,post-upgrade
+    helm.sh/hook-delete-policy: hook-succeeded
+  config:
+    exposer: Ingress
+    http: true
+    tlsacme: false
+
+cleanup:
+  Args:
+    - --cleanup
+  Annotations:
+    helm.sh/hook: pre-delete
+    helm.sh/hook-delete-policy: hook-succeeded
+
+preview:
+  image:
+    repository:
+    tag:
+    pullPolicy: IfNotPresent
+`
+
+const previewMakefile = `OS := $(shell uname)
+
+preview:
+ifeq ($(OS),Darwin)
+	sed -i "" -e "s/version:.*/version: $(PREVIEW_VERSION)/" Chart.yaml
+	sed -i "" -e "s/version:.*/version: $(PREVIEW_VERSION)/" ../*/Chart.yaml
+	sed -i "" -e "s|repository: .*|repository: $(DOCKER_REGISTRY)\/REPLACE_ME_ORG\/REPLACE_ME_APP_NAME|" values.yaml
+	sed -i "" -e "s/tag: .*/tag: $(PREVIEW_VERSION)/" values.yaml
+else ifeq ($(OS),Linux)
+	sed -i -e "s/version:.*/version: $(PREVIEW_VERSION)/" Chart.yaml
+	sed -i -e "s/version:.*/version: $(PREVIEW_VERSION)/" ../*/Chart.yaml
+	sed -i -e "s|repository: .*|repository: $(DOCKER_REGISTRY)\/REPLACE_ME_ORG\/REPLACE_ME_APP_NAME|" values.yaml
+	sed -i -e "s/tag: .*/tag: $(PREVIEW_VERSION)/" values.yaml
+else
+	echo "platform $(OS) not supported to release from"
+	exit -1
+endif
+	echo "  version: $(PREVIEW_VERSION)" >> requirements.yaml
+	jx step helm build
+`
@@ -0,0 +1,70 @@
+package draft_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/draft-repo/pkg/draft/pack"
+	"github.com/jenkins-x/jx/pkg/draft"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteBuiltinPacks(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-builtin-packs")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, draft.WriteBuiltinPacks(dir))
+	assert.Equal(t, []string{draft.DotNetPack, draft.RustPack}, draft.BuiltinPackNames())
+
+	for _, name := range draft.BuiltinPackNames() {
+		p, err := pack.FromDir(filepath.Join(dir, name))
+		require.NoError(t, err, "loading pack %s", name)
+		assert.Len(t, p.Charts, 2, "charts of pack %s", name)
+		for _, file := range []string{"Dockerfile", "Jenkinsfile", "skaffold.yaml"} {
+			assert.Contains(t, p.Files, file, "files of pack %s", name)
+		}
+
+		appDir, err := ioutil.TempDir("", "test-builtin-pack-app")
+		require.NoError(t, err)
+		defer os.RemoveAll(appDir)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(appDir, "Dockerfile"), []byte("FROM mine\n"), 0644))
+		require.NoError(t, p.SaveDir(appDir))
+
+		data, err := ioutil.ReadFile(filepath.Join(appDir, "Dockerfile"))
+		require.NoError(t, err)
+		assert.Equal(t, "FROM mine\n", string(data), "the existing Dockerfile is kept by pack %s", name)
+		jenkinsfile, err := ioutil.ReadFile(filepath.Join(appDir, "Jenkinsfile"))
+		require.NoError(t, err)
+		for _, state := range []string{"pending", "success", "failure"} {
+			assert.Contains(t, string(jenkinsfile), "jx step git status --state "+state, "the Jenkinsfile of pack %s reports the build status", name)
+		}
+		assert.FileExists(t, filepath.Join(appDir, "charts", "REPLACE_ME_APP_NAME", "Chart.yaml"))
+		assert.FileExists(t, filepath.Join(appDir, "charts", "preview", "Makefile"))
+	}
+}
+
+func TestDetectBuiltinPack(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"Cargo.toml":    draft.RustPack,
+		"MyApp.csproj":  draft.DotNetPack,
+		"MyApp.fsproj":  draft.DotNetPack,
+		"package.json":  "",
+		"MyApp.vbproj2": "",
+	}
+	for file, expected := range tests {
+		dir, err := ioutil.TempDir("", "test-detect-builtin-pack")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, file), []byte{}, 0644))
+
+		actual, err := draft.DetectBuiltinPack(dir)
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual, "pack detected for %s", file)
+	}
+}
@@ -0,0 +1,52 @@
+package filesync
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// WriteTar writes a tar archive of the files with the given slash separated paths relative to the directory, copying
+// their contents to the optional progress writer too. Files which have been removed in the meantime are skipped
+func WriteTar(out io.Writer, dir string, files []string, progress io.Writer) error {
+	tw := tar.NewWriter(out)
+	for _, rel := range files {
+		err := writeTarFile(tw, dir, rel, progress)
+		if err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+func writeTarFile(tw *tar.Writer, dir string, rel string, progress io.Writer) error {
+	path := filepath.Join(dir, filepath.FromSlash(rel))
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = rel
+	err = tw.WriteHeader(header)
+	if err != nil {
+		return err
+	}
+	var reader io.Reader = f
+	if progress != nil {
+		reader = io.TeeReader(f, progress)
+	}
+	_, err = io.CopyN(tw, reader, header.Size)
+	return err
+}
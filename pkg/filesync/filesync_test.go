@@ -0,0 +1,203 @@
+package filesync_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/filesync"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t testing.TB, dir string, rel string, content string) {
+	path := filepath.Join(dir, filepath.FromSlash(rel))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+}
+
+// createProject creates a project with a node_modules tree of the given number of files
+func createProject(t testing.TB, modules int) string {
+	dir, err := ioutil.TempDir("", "jx-filesync-")
+	require.NoError(t, err)
+	writeFile(t, dir, "package.json", "{}")
+	writeFile(t, dir, "src/index.js", "console.log('hello')")
+	writeFile(t, dir, "src/gen/types.js", "// generated")
+	writeFile(t, dir, "dist/bundle.js", "bundle")
+	writeFile(t, dir, ".git/HEAD", "ref: refs/heads/master")
+	for i := 0; i < modules; i++ {
+		writeFile(t, dir, fmt.Sprintf("node_modules/module%d/lib/index.js", i), "module.exports = {}")
+	}
+	return dir
+}
+
+func TestIgnorer(t *testing.T) {
+	t.Parallel()
+	dir := createProject(t, 1)
+	defer os.RemoveAll(dir)
+
+	ignorer, err := filesync.NewIgnorer(dir, nil, false)
+	require.NoError(t, err)
+	assert.True(t, ignorer.Ignored("node_modules", true), "default patterns are used without an ignore file")
+	assert.True(t, ignorer.Ignored(".git/HEAD", false))
+	assert.False(t, ignorer.Ignored("src/index.js", false))
+
+	writeFile(t, dir, filesync.IgnoreFile, "# generated code\nsrc/gen/\n*.log\n")
+	writeFile(t, dir, filesync.GitIgnoreFile, "dist/\n")
+	ignorer, err = filesync.NewIgnorer(dir, []string{"node_modules"}, false)
+	require.NoError(t, err)
+	assert.True(t, ignorer.Ignored("src/gen", true))
+	assert.True(t, ignorer.Ignored("src/gen/types.js", false))
+	assert.True(t, ignorer.Ignored("logs/build.log", false))
+	assert.True(t, ignorer.Ignored("node_modules/module0/lib/index.js", false))
+	assert.True(t, ignorer.Ignored(".git", true), ".git is always ignored")
+	assert.False(t, ignorer.Ignored("dist/bundle.js", false), ".gitignore is only used on request")
+	assert.False(t, ignorer.Ignored("target", true), "the ignore file replaces the default patterns")
+
+	ignorer, err = filesync.NewIgnorer(dir, []string{"!src/gen/keep.js"}, true)
+	require.NoError(t, err)
+	assert.True(t, ignorer.Ignored("dist/bundle.js", false))
+	assert.False(t, ignorer.Ignored("src/gen/keep.js", false), "excludes take precedence")
+	assert.Contains(t, ignorer.StignoreContent(), "\nsrc/gen\n")
+}
+
+func TestFilesSkipsIgnoredTrees(t *testing.T) {
+	t.Parallel()
+	dir := createProject(t, 10)
+	defer os.RemoveAll(dir)
+
+	ignorer := filesync.NewIgnorerFromPatterns([]string{".git", "node_modules", "src/gen/"})
+	files, size, err := filesync.Files(dir, ignorer)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dist/bundle.js", "package.json", "src/index.js"}, files)
+	assert.Equal(t, int64(len("bundle")+len("{}")+len("console.log('hello')")), size)
+
+	var buffer bytes.Buffer
+	var output bytes.Buffer
+	progress := filesync.NewProgress(&output, size)
+	require.NoError(t, filesync.WriteTar(&buffer, dir, append(files, "deleted.js"), progress))
+	progress.Done()
+	reader := tar.NewReader(&buffer)
+	names := []string{}
+	for {
+		header, err := reader.Next()
+		if err != nil {
+			break
+		}
+		names = append(names, header.Name)
+	}
+	assert.Equal(t, files, names)
+	assert.Contains(t, output.String(), "Synchronised 28 B of 28 B (100%)")
+}
+
+func TestWatcher(t *testing.T) {
+	t.Parallel()
+	dir := createProject(t, 10)
+	defer os.RemoveAll(dir)
+
+	ignorer := filesync.NewIgnorerFromPatterns([]string{".git", "node_modules"})
+	watcher, err := filesync.NewWatcher(dir, ignorer, 50*time.Millisecond)
+	require.NoError(t, err)
+	defer watcher.Close()
+	assert.Equal(t, []string{".", "dist", "src", "src/gen"}, watcher.WatchedDirs(), "ignored directories should not be watched")
+
+	batches := make(chan *filesync.Changes, 10)
+	stop := make(chan struct{})
+	defer close(stop)
+	go watcher.Run(stop, func(changes *filesync.Changes) error {
+		batches <- changes
+		return nil
+	})
+
+	writeFile(t, dir, "node_modules/module0/lib/index.js", "changed")
+	writeFile(t, dir, "src/index.js", "changed")
+	writeFile(t, dir, "src/index.js", "changed again")
+	writeFile(t, dir, "lib/util.js", "new")
+	require.NoError(t, os.Remove(filepath.Join(dir, "dist/bundle.js")))
+
+	changes := &filesync.Changes{}
+	timeout := time.After(10 * time.Second)
+	for len(changes.Updated) < 2 || len(changes.Removed) < 1 {
+		select {
+		case batch := <-batches:
+			changes.Updated = append(changes.Updated, batch.Updated...)
+			changes.Removed = append(changes.Removed, batch.Removed...)
+		case <-timeout:
+			require.Fail(t, "timed out waiting for the changes", "%#v", changes)
+		}
+	}
+	assert.ElementsMatch(t, []string{"lib/util.js", "src/index.js"}, changes.Updated)
+	assert.Equal(t, []string{"dist/bundle.js"}, changes.Removed)
+	assert.Contains(t, watcher.WatchedDirs(), "lib")
+}
+
+func TestFormatBytes(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "512 B", filesync.FormatBytes(512))
+	assert.Equal(t, "1.5 KiB", filesync.FormatBytes(1536))
+	assert.Equal(t, "10.0 MiB", filesync.FormatBytes(10*1024*1024))
+}
+
+// benchmarkIncrementalSync measures recording and flushing a change in a project whose node_modules tree is ignored
+// or not. The cost of an incremental sync should not depend on the size of ignored trees
+func benchmarkIncrementalSync(b *testing.B, modules int, patterns []string) {
+	dir := createProject(b, modules)
+	defer os.RemoveAll(dir)
+	watcher, err := filesync.NewWatcher(dir, filesync.NewIgnorerFromPatterns(patterns), 0)
+	require.NoError(b, err)
+	defer watcher.Close()
+	changed := filepath.Join(dir, "src", "index.js")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		watcher.Add(changed)
+		changes, err := watcher.Flush()
+		if err != nil || len(changes.Updated) != 1 {
+			b.Fatalf("unexpected changes %#v: %v", changes, err)
+		}
+	}
+}
+
+func BenchmarkIncrementalSyncIgnoredSmallTree(b *testing.B) {
+	benchmarkIncrementalSync(b, 10, []string{".git", "node_modules"})
+}
+
+func BenchmarkIncrementalSyncIgnoredLargeTree(b *testing.B) {
+	benchmarkIncrementalSync(b, 1000, []string{".git", "node_modules"})
+}
+
+// BenchmarkFullScan the cost of scanning the whole project which incremental syncs avoid
+func BenchmarkFullScan(b *testing.B) {
+	dir := createProject(b, 1000)
+	defer os.RemoveAll(dir)
+	ignorer := filesync.NewIgnorerFromPatterns([]string{".git"})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, err := filesync.Files(dir, ignorer)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestMergeStignoreKeepsTheRulesOfTheUser(t *testing.T) {
+	t.Parallel()
+	ignorer := filesync.NewIgnorerFromPatterns([]string{".git", "node_modules/"})
+	generated := ignorer.StignoreContent()
+
+	assert.Equal(t, generated, ignorer.MergeStignore(""))
+
+	merged := ignorer.MergeStignore("// my rules\n*.log\n")
+	assert.Equal(t, "// my rules\n*.log\n\n"+generated, merged)
+
+	updated := filesync.NewIgnorerFromPatterns([]string{".git", "dist"})
+	remerged := updated.MergeStignore(merged)
+	assert.Equal(t, "// my rules\n*.log\n\n"+updated.StignoreContent(), remerged)
+	assert.NotContains(t, remerged, "node_modules")
+}
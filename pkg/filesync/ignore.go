@@ -0,0 +1,144 @@
+package filesync
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/src-d/go-git.v4/plumbing/format/gitignore"
+)
+
+const (
+	// IgnoreFile the file in the root of a synchronised directory containing the gitignore patterns of the files which
+	// are not synchronised
+	IgnoreFile = ".jxsyncignore"
+	// GitIgnoreFile the gitignore file of a git repository
+	GitIgnoreFile = ".gitignore"
+
+	stignoreBegin = "// BEGIN generated by jx sync from " + IgnoreFile + ", changes are overwritten"
+	stignoreEnd   = "// END generated by jx sync"
+)
+
+// DefaultIgnorePatterns the patterns which are ignored if the directory has no IgnoreFile
+var DefaultIgnorePatterns = []string{
+	".git",
+	".idea",
+	".settings",
+	".vscode",
+	"bin",
+	"build",
+	"target",
+	"node_modules",
+}
+
+// Ignorer decides which files of a directory are not synchronised
+type Ignorer struct {
+	patterns []string
+	matcher  gitignore.Matcher
+}
+
+// NewIgnorer creates the Ignorer of the directory from its IgnoreFile, or the DefaultIgnorePatterns if it has none, and
+// the exclude patterns which take precedence. The patterns of the .gitignore file are used too if useGitIgnore is true
+// or if the directory has no IgnoreFile
+func NewIgnorer(dir string, excludes []string, useGitIgnore bool) (*Ignorer, error) {
+	patterns := []string{".git"}
+	ignorePatterns, err := readPatterns(filepath.Join(dir, IgnoreFile))
+	if err != nil {
+		return nil, err
+	}
+	if ignorePatterns == nil || useGitIgnore {
+		gitIgnorePatterns, err := readPatterns(filepath.Join(dir, GitIgnoreFile))
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, gitIgnorePatterns...)
+	}
+	if ignorePatterns == nil {
+		patterns = append(patterns, DefaultIgnorePatterns...)
+	}
+	patterns = append(patterns, ignorePatterns...)
+	patterns = append(patterns, excludes...)
+	return NewIgnorerFromPatterns(patterns), nil
+}
+
+// NewIgnorerFromPatterns creates an Ignorer from gitignore patterns in the order of increasing precedence
+func NewIgnorerFromPatterns(patterns []string) *Ignorer {
+	parsed := []gitignore.Pattern{}
+	for _, p := range patterns {
+		parsed = append(parsed, gitignore.ParsePattern(p, nil))
+	}
+	return &Ignorer{
+		patterns: patterns,
+		matcher:  gitignore.NewMatcher(parsed),
+	}
+}
+
+// Patterns returns the gitignore patterns of the Ignorer
+func (i *Ignorer) Patterns() []string {
+	return i.patterns
+}
+
+// Ignored returns true if the file or directory with the slash separated path relative to the synchronised directory
+// is not synchronised
+func (i *Ignorer) Ignored(path string, isDir bool) bool {
+	if path == "" || path == "." {
+		return false
+	}
+	return i.matcher.Match(strings.Split(filepath.ToSlash(path), "/"), isDir)
+}
+
+// StignoreContent returns the patterns in the syntax of the .stignore file of ksync which does not support the
+// trailing slash of directory patterns, surrounded by the markers which let MergeStignore replace them
+func (i *Ignorer) StignoreContent() string {
+	lines := []string{stignoreBegin}
+	for _, p := range i.patterns {
+		lines = append(lines, strings.TrimSuffix(p, "/"))
+	}
+	lines = append(lines, stignoreEnd)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// MergeStignore returns the content of the existing .stignore file with the rules previously generated by jx sync
+// replaced by the StignoreContent, keeping the rules added by the user
+func (i *Ignorer) MergeStignore(existing string) string {
+	userLines := []string{}
+	generated := false
+	for _, line := range strings.Split(existing, "\n") {
+		switch strings.TrimRight(line, "\r") {
+		case stignoreBegin:
+			generated = true
+		case stignoreEnd:
+			generated = false
+		default:
+			if !generated {
+				userLines = append(userLines, line)
+			}
+		}
+	}
+	userContent := strings.TrimSpace(strings.Join(userLines, "\n"))
+	if userContent == "" {
+		return i.StignoreContent()
+	}
+	return userContent + "\n\n" + i.StignoreContent()
+}
+
+// readPatterns returns the patterns of the ignore file or nil if there is no such file
+func readPatterns(fileName string) ([]string, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	answer := []string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		answer = append(answer, line)
+	}
+	return answer, nil
+}
@@ -0,0 +1,65 @@
+package filesync
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// Progress writes the number of bytes synchronised so far to the output at most once per interval
+type Progress struct {
+	Out      io.Writer
+	Total    int64
+	Interval time.Duration
+
+	written int64
+	printed time.Time
+}
+
+// NewProgress creates the Progress of synchronising the given number of bytes
+func NewProgress(out io.Writer, total int64) *Progress {
+	return &Progress{
+		Out:      out,
+		Total:    total,
+		Interval: time.Second / 2,
+	}
+}
+
+// Write counts the written bytes
+func (p *Progress) Write(data []byte) (int, error) {
+	p.written += int64(len(data))
+	now := time.Now()
+	if now.Sub(p.printed) >= p.Interval {
+		p.printed = now
+		p.print("\r")
+	}
+	return len(data), nil
+}
+
+// Done prints the final number of synchronised bytes
+func (p *Progress) Done() {
+	p.print("\r")
+	fmt.Fprintln(p.Out)
+}
+
+func (p *Progress) print(prefix string) {
+	percent := 100
+	if p.Total > 0 && p.written < p.Total {
+		percent = int(p.written * 100 / p.Total)
+	}
+	fmt.Fprintf(p.Out, "%sSynchronised %s of %s (%d%%)", prefix, FormatBytes(p.written), FormatBytes(p.Total), percent)
+}
+
+// FormatBytes formats the number of bytes using binary units
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
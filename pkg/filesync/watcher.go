@@ -0,0 +1,220 @@
+package filesync
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/jenkins-x/jx/pkg/log"
+)
+
+// DefaultDebounce the time the Watcher waits for further changes before synchronising a batch of changes
+const DefaultDebounce = 300 * time.Millisecond
+
+// Changes the files which changed in a synchronised directory, as slash separated paths relative to the directory
+type Changes struct {
+	Updated []string
+	Removed []string
+}
+
+// Empty returns true if there are no changes
+func (c *Changes) Empty() bool {
+	return len(c.Updated) == 0 && len(c.Removed) == 0
+}
+
+// Files returns the files of the directory which are not ignored and their total size. Ignored directories are skipped
+// without reading their contents
+func Files(dir string, ignorer *Ignorer) ([]string, int64, error) {
+	files := []string{}
+	var size int64
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if ignorer.Ignored(rel, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Mode().IsRegular() {
+			files = append(files, filepath.ToSlash(rel))
+			size += info.Size()
+		}
+		return nil
+	})
+	return files, size, err
+}
+
+// Watcher watches the directories of a synchronised directory which are not ignored and reports the changed files in
+// batches. Ignored directories are neither watched nor scanned so that changes in them cost nothing
+type Watcher struct {
+	Dir      string
+	Ignorer  *Ignorer
+	Debounce time.Duration
+
+	watcher *fsnotify.Watcher
+	dirs    map[string]bool
+	pending map[string]bool
+}
+
+// NewWatcher creates a Watcher of the directory
+func NewWatcher(dir string, ignorer *Ignorer, debounce time.Duration) (*Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if debounce <= 0 {
+		debounce = DefaultDebounce
+	}
+	w := &Watcher{
+		Dir:      dir,
+		Ignorer:  ignorer,
+		Debounce: debounce,
+		watcher:  watcher,
+		dirs:     map[string]bool{},
+		pending:  map[string]bool{},
+	}
+	_, err = w.watchDir(dir)
+	if err != nil {
+		watcher.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// WatchedDirs returns the slash separated paths of the watched directories relative to the synchronised directory
+func (w *Watcher) WatchedDirs() []string {
+	answer := []string{}
+	for dir := range w.dirs {
+		answer = append(answer, dir)
+	}
+	sort.Strings(answer)
+	return answer
+}
+
+// Close stops watching the directory
+func (w *Watcher) Close() error {
+	return w.watcher.Close()
+}
+
+// Run calls the sync function with each batch of changes until the stop channel is closed or the sync function fails
+func (w *Watcher) Run(stop <-chan struct{}, sync func(changes *Changes) error) error {
+	var timer <-chan time.Time
+	for {
+		select {
+		case <-stop:
+			return nil
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return nil
+			}
+			if w.Add(event.Name) {
+				timer = time.After(w.Debounce)
+			}
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Warnf("Failed to watch %s: %s\n", w.Dir, err)
+		case <-timer:
+			timer = nil
+			changes, err := w.Flush()
+			if err != nil {
+				return err
+			}
+			if !changes.Empty() {
+				err = sync(changes)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// Add records a change of the file with the given path returning false if it is ignored
+func (w *Watcher) Add(path string) bool {
+	rel, err := filepath.Rel(w.Dir, path)
+	if err != nil || rel == "." {
+		return false
+	}
+	info, err := os.Lstat(path)
+	isDir := err == nil && info.IsDir()
+	if w.Ignorer.Ignored(rel, isDir) {
+		return false
+	}
+	w.pending[filepath.ToSlash(rel)] = true
+	return true
+}
+
+// Flush returns the changes recorded since the last flush, watching new directories and reporting their files as
+// updated
+func (w *Watcher) Flush() (*Changes, error) {
+	changes := &Changes{}
+	for rel := range w.pending {
+		path := filepath.Join(w.Dir, filepath.FromSlash(rel))
+		info, err := os.Lstat(path)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return nil, err
+			}
+			changes.Removed = append(changes.Removed, rel)
+			delete(w.dirs, rel)
+			continue
+		}
+		if info.IsDir() {
+			if w.dirs[rel] {
+				continue
+			}
+			files, err := w.watchDir(path)
+			if err != nil {
+				return nil, err
+			}
+			changes.Updated = append(changes.Updated, files...)
+		} else if info.Mode().IsRegular() {
+			changes.Updated = append(changes.Updated, rel)
+		}
+	}
+	w.pending = map[string]bool{}
+	sort.Strings(changes.Updated)
+	sort.Strings(changes.Removed)
+	return changes, nil
+}
+
+// watchDir watches the directory and its sub directories which are not ignored returning the files in them
+func (w *Watcher) watchDir(dir string) ([]string, error) {
+	files := []string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(w.Dir, path)
+		if err != nil {
+			return err
+		}
+		if w.Ignorer.Ignored(rel, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			err = w.watcher.Add(path)
+			if err != nil {
+				return err
+			}
+			w.dirs[filepath.ToSlash(rel)] = true
+		} else if info.Mode().IsRegular() {
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	return files, err
+}
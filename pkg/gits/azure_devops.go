@@ -0,0 +1,923 @@
+package gits
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/github"
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+const (
+	// AzureDevOpsURL the URL of the Azure DevOps service. The URL of a server is the URL of an organization such as
+	// https://dev.azure.com/myorg
+	AzureDevOpsURL = "https://dev.azure.com"
+
+	azureDevOpsSSHHost = "ssh.dev.azure.com"
+
+	azureDevOpsAPIVersion = "5.0"
+
+	azureDevOpsRefsPrefix = "refs/heads/"
+
+	azureDevOpsStatusGenre = "jenkins-x"
+)
+
+// azureDevOpsWebHookEvents the events of a repository which trigger its webhook. Azure DevOps creates one service hook
+// subscription for each event
+var azureDevOpsWebHookEvents = []string{
+	AzureDevOpsEventPush,
+	AzureDevOpsEventPullRequestCreated,
+	AzureDevOpsEventPullRequestUpdated,
+	AzureDevOpsEventPullRequestMerged,
+	AzureDevOpsEventPullRequestCommented,
+}
+
+// azureDevOpsStates maps the states of Git statuses to the states of Azure DevOps statuses
+var azureDevOpsStates = map[string]string{
+	"success": "succeeded",
+	"pending": "pending",
+	"error":   "error",
+	"failure": "failed",
+}
+
+// AzureDevOpsProvider implements the GitProvider interface for the Git repositories of Azure DevOps (Azure Repos).
+// The organisation of a repository is its Azure DevOps project
+type AzureDevOpsProvider struct {
+	Username string
+	// RestURL the base URL of the REST API of the Azure DevOps organization
+	RestURL string
+
+	Server auth.AuthServer
+	User   auth.UserAuth
+	Git    Gitter
+}
+
+type azureDevOpsList struct {
+	Count int             `json:"count"`
+	Value json.RawMessage `json:"value"`
+}
+
+type azureDevOpsProject struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type azureDevOpsRepository struct {
+	ID            string              `json:"id,omitempty"`
+	Name          string              `json:"name,omitempty"`
+	URL           string              `json:"url,omitempty"`
+	RemoteURL     string              `json:"remoteUrl,omitempty"`
+	SSHURL        string              `json:"sshUrl,omitempty"`
+	WebURL        string              `json:"webUrl,omitempty"`
+	DefaultBranch string              `json:"defaultBranch,omitempty"`
+	Project       *azureDevOpsProject `json:"project,omitempty"`
+}
+
+type azureDevOpsIdentity struct {
+	ID          string `json:"id,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	UniqueName  string `json:"uniqueName,omitempty"`
+	URL         string `json:"url,omitempty"`
+	ImageURL    string `json:"imageUrl,omitempty"`
+}
+
+type azureDevOpsCommitRef struct {
+	CommitID string `json:"commitId,omitempty"`
+}
+
+type azureDevOpsCompletionOptions struct {
+	MergeCommitMessage string `json:"mergeCommitMessage,omitempty"`
+	DeleteSourceBranch bool   `json:"deleteSourceBranch"`
+}
+
+type azureDevOpsPullRequest struct {
+	PullRequestID         int                           `json:"pullRequestId,omitempty"`
+	Repository            *azureDevOpsRepository        `json:"repository,omitempty"`
+	Status                string                        `json:"status,omitempty"`
+	CreatedBy             *azureDevOpsIdentity          `json:"createdBy,omitempty"`
+	ClosedDate            *time.Time                    `json:"closedDate,omitempty"`
+	Title                 string                        `json:"title,omitempty"`
+	Description           string                        `json:"description,omitempty"`
+	SourceRefName         string                        `json:"sourceRefName,omitempty"`
+	TargetRefName         string                        `json:"targetRefName,omitempty"`
+	MergeStatus           string                        `json:"mergeStatus,omitempty"`
+	LastMergeSourceCommit *azureDevOpsCommitRef         `json:"lastMergeSourceCommit,omitempty"`
+	LastMergeCommit       *azureDevOpsCommitRef         `json:"lastMergeCommit,omitempty"`
+	CompletionOptions     *azureDevOpsCompletionOptions `json:"completionOptions,omitempty"`
+	URL                   string                        `json:"url,omitempty"`
+}
+
+type azureDevOpsGitUserDate struct {
+	Name  string     `json:"name,omitempty"`
+	Email string     `json:"email,omitempty"`
+	Date  *time.Time `json:"date,omitempty"`
+}
+
+type azureDevOpsCommit struct {
+	CommitID  string                  `json:"commitId"`
+	Comment   string                  `json:"comment"`
+	Author    *azureDevOpsGitUserDate `json:"author,omitempty"`
+	Committer *azureDevOpsGitUserDate `json:"committer,omitempty"`
+	URL       string                  `json:"url,omitempty"`
+	RemoteURL string                  `json:"remoteUrl,omitempty"`
+}
+
+type azureDevOpsStatusContext struct {
+	Name  string `json:"name"`
+	Genre string `json:"genre,omitempty"`
+}
+
+type azureDevOpsStatus struct {
+	ID          int                      `json:"id,omitempty"`
+	State       string                   `json:"state"`
+	Description string                   `json:"description,omitempty"`
+	Context     azureDevOpsStatusContext `json:"context"`
+	TargetURL   string                   `json:"targetUrl,omitempty"`
+}
+
+type azureDevOpsComment struct {
+	ID              int                  `json:"id,omitempty"`
+	ParentCommentID int                  `json:"parentCommentId"`
+	Content         string               `json:"content"`
+	CommentType     string               `json:"commentType,omitempty"`
+	Author          *azureDevOpsIdentity `json:"author,omitempty"`
+	PublishedDate   *time.Time           `json:"publishedDate,omitempty"`
+	IsDeleted       bool                 `json:"isDeleted,omitempty"`
+}
+
+type azureDevOpsThread struct {
+	ID        int64                `json:"id,omitempty"`
+	Comments  []azureDevOpsComment `json:"comments"`
+	Status    string               `json:"status,omitempty"`
+	IsDeleted bool                 `json:"isDeleted,omitempty"`
+}
+
+type azureDevOpsSubscription struct {
+	ID               string            `json:"id,omitempty"`
+	PublisherID      string            `json:"publisherId"`
+	EventType        string            `json:"eventType"`
+	ResourceVersion  string            `json:"resourceVersion"`
+	ConsumerID       string            `json:"consumerId"`
+	ConsumerActionID string            `json:"consumerActionId"`
+	PublisherInputs  map[string]string `json:"publisherInputs"`
+	ConsumerInputs   map[string]string `json:"consumerInputs"`
+}
+
+// NewAzureDevOpsProvider creates a GitProvider for the Azure DevOps organization of the server
+func NewAzureDevOpsProvider(server *auth.AuthServer, user *auth.UserAuth, git Gitter) (GitProvider, error) {
+	provider := AzureDevOpsProvider{
+		Server:   *server,
+		User:     *user,
+		Username: user.Username,
+		RestURL:  strings.TrimSuffix(server.URL, "/"),
+		Git:      git,
+	}
+	return &provider, nil
+}
+
+// IsAzureDevOpsHost returns true if the host of a Git URL is an Azure DevOps host such as dev.azure.com/myorg or
+// myorg.visualstudio.com
+func IsAzureDevOpsHost(host string) bool {
+	host = strings.ToLower(host)
+	return host == "dev.azure.com" || strings.HasPrefix(host, "dev.azure.com/") || strings.HasPrefix(host, "ssh.dev.azure.com") ||
+		strings.HasSuffix(strings.Split(host, "/")[0], ".visualstudio.com")
+}
+
+// IsAzureDevOpsURL returns true if the URL is the URL of an Azure DevOps organization or one of its repositories
+func IsAzureDevOpsURL(text string) bool {
+	u, err := url.Parse(text)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	return IsAzureDevOpsHost(u.Host)
+}
+
+func (p *AzureDevOpsProvider) ListOrganisations() ([]GitOrganisation, error) {
+	answer := []GitOrganisation{}
+	projects := []azureDevOpsProject{}
+	err := p.list("_apis/projects", &projects)
+	if err != nil {
+		return answer, err
+	}
+	for _, project := range projects {
+		answer = append(answer, GitOrganisation{Login: project.Name})
+	}
+	return answer, nil
+}
+
+func (p *AzureDevOpsProvider) ListRepositories(org string) ([]*GitRepository, error) {
+	answer := []*GitRepository{}
+	path := "_apis/git/repositories"
+	if org != "" {
+		path = util.UrlJoin(url.PathEscape(org), path)
+	}
+	repos := []azureDevOpsRepository{}
+	err := p.list(path, &repos)
+	if err != nil {
+		return answer, err
+	}
+	for i := range repos {
+		answer = append(answer, toAzureDevOpsRepo(&repos[i]))
+	}
+	return answer, nil
+}
+
+func (p *AzureDevOpsProvider) CreateRepository(org string, name string, private bool) (*GitRepository, error) {
+	project := &azureDevOpsProject{}
+	err := p.request(http.MethodGet, util.UrlJoin("_apis/projects", url.PathEscape(org)), nil, project)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to find the project %s due to: %s", org, err)
+	}
+	body := &azureDevOpsRepository{
+		Name: name,
+		Project: &azureDevOpsProject{
+			ID: project.ID,
+		},
+	}
+	repo := &azureDevOpsRepository{}
+	err = p.request(http.MethodPost, p.reposPath(org), body, repo)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create repository %s/%s due to: %s", org, name, err)
+	}
+	return toAzureDevOpsRepo(repo), nil
+}
+
+func (p *AzureDevOpsProvider) GetRepository(org string, name string) (*GitRepository, error) {
+	repo, err := p.getRepository(org, name)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get repository %s/%s due to: %s", org, name, err)
+	}
+	return toAzureDevOpsRepo(repo), nil
+}
+
+func (p *AzureDevOpsProvider) getRepository(org string, name string) (*azureDevOpsRepository, error) {
+	repo := &azureDevOpsRepository{}
+	err := p.request(http.MethodGet, p.repoPath(org, name), nil, repo)
+	return repo, err
+}
+
+func (p *AzureDevOpsProvider) DeleteRepository(org string, name string) error {
+	repo, err := p.getRepository(org, name)
+	if err != nil {
+		return fmt.Errorf("Failed to delete repository %s/%s due to: %s", org, name, err)
+	}
+	err = p.request(http.MethodDelete, p.repoPath(org, repo.ID), nil, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to delete repository %s/%s due to: %s", org, name, err)
+	}
+	return nil
+}
+
+func (p *AzureDevOpsProvider) ForkRepository(originalOrg string, name string, destinationOrg string) (*GitRepository, error) {
+	return nil, fmt.Errorf("Forking repositories is not supported on Azure DevOps")
+}
+
+func (p *AzureDevOpsProvider) RenameRepository(org string, name string, newName string) (*GitRepository, error) {
+	repo, err := p.getRepository(org, name)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to rename repository %s/%s due to: %s", org, name, err)
+	}
+	answer := &azureDevOpsRepository{}
+	err = p.request(http.MethodPatch, p.repoPath(org, repo.ID), &azureDevOpsRepository{Name: newName}, answer)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to rename repository %s/%s to %s due to: %s", org, name, newName, err)
+	}
+	return toAzureDevOpsRepo(answer), nil
+}
+
+func (p *AzureDevOpsProvider) ValidateRepositoryName(org string, name string) error {
+	_, err := p.getRepository(org, name)
+	if err == nil {
+		return fmt.Errorf("Repository %s already exists", p.Git.RepoName(org, name))
+	}
+	if strings.Contains(err.Error(), "404") {
+		return nil
+	}
+	return err
+}
+
+func toAzureDevOpsRepo(repo *azureDevOpsRepository) *GitRepository {
+	return &GitRepository{
+		Name:             repo.Name,
+		AllowMergeCommit: true,
+		HTMLURL:          repo.WebURL,
+		CloneURL:         repo.RemoteURL,
+		SSHURL:           repo.SSHURL,
+	}
+}
+
+func (p *AzureDevOpsProvider) CreatePullRequest(data *GitPullRequestArguments) (*GitPullRequest, error) {
+	owner := data.GitRepositoryInfo.Organisation
+	repo := data.GitRepositoryInfo.Name
+	body := &azureDevOpsPullRequest{
+		Title:         data.Title,
+		Description:   data.Body,
+		SourceRefName: azureDevOpsRef(data.Head),
+		TargetRefName: azureDevOpsRef(data.Base),
+	}
+	pr := &azureDevOpsPullRequest{}
+	err := p.request(http.MethodPost, util.UrlJoin(p.repoPath(owner, repo), "pullrequests"), body, pr)
+	if err != nil {
+		return nil, err
+	}
+	return p.toPullRequest(owner, repo, pr), nil
+}
+
+func (p *AzureDevOpsProvider) UpdatePullRequestStatus(pr *GitPullRequest) error {
+	if pr.Number == nil {
+		return fmt.Errorf("Missing Number for GitPullRequest %#v", pr)
+	}
+	answer, err := p.getPullRequest(pr.Owner, pr.Repo, *pr.Number)
+	if err != nil {
+		return err
+	}
+	updatePullRequest(pr, p.toPullRequest(pr.Owner, pr.Repo, answer))
+	return nil
+}
+
+// updatePullRequest copies the status of the Pull Request from the latest version of it
+func updatePullRequest(pr *GitPullRequest, latest *GitPullRequest) {
+	pr.Author = latest.Author
+	pr.Mergeable = latest.Mergeable
+	pr.Merged = latest.Merged
+	pr.HeadRef = latest.HeadRef
+	pr.State = latest.State
+	pr.MergeCommitSHA = latest.MergeCommitSHA
+	pr.ClosedAt = latest.ClosedAt
+	pr.MergedAt = latest.MergedAt
+	pr.LastCommitSha = latest.LastCommitSha
+	pr.Title = latest.Title
+	pr.Body = latest.Body
+	if pr.URL == "" {
+		pr.URL = latest.URL
+	}
+}
+
+func (p *AzureDevOpsProvider) GetPullRequest(owner string, repo *GitRepositoryInfo, number int) (*GitPullRequest, error) {
+	pr, err := p.getPullRequest(owner, repo.Name, number)
+	if err != nil {
+		return nil, err
+	}
+	return p.toPullRequest(owner, repo.Name, pr), nil
+}
+
+func (p *AzureDevOpsProvider) getPullRequest(owner string, repo string, number int) (*azureDevOpsPullRequest, error) {
+	pr := &azureDevOpsPullRequest{}
+	err := p.request(http.MethodGet, p.pullRequestPath(owner, repo, number), nil, pr)
+	return pr, err
+}
+
+func (p *AzureDevOpsProvider) toPullRequest(owner string, repo string, pr *azureDevOpsPullRequest) *GitPullRequest {
+	number := pr.PullRequestID
+	merged := pr.Status == "completed"
+	mergeable := pr.MergeStatus == "succeeded"
+	state := azureDevOpsPullRequestState(pr.Status)
+	headRef := strings.TrimPrefix(pr.SourceRefName, azureDevOpsRefsPrefix)
+	answer := &GitPullRequest{
+		URL:       util.UrlJoin(p.repositoryWebURL(owner, repo), "pullrequest", strconv.Itoa(number)),
+		Owner:     owner,
+		Repo:      repo,
+		Number:    &number,
+		Mergeable: &mergeable,
+		Merged:    &merged,
+		HeadRef:   &headRef,
+		State:     &state,
+		ClosedAt:  pr.ClosedDate,
+		Title:     pr.Title,
+		Body:      pr.Description,
+	}
+	if merged {
+		answer.MergedAt = pr.ClosedDate
+	}
+	if pr.CreatedBy != nil {
+		answer.Author = toAzureDevOpsUser(pr.CreatedBy)
+	}
+	if pr.LastMergeSourceCommit != nil {
+		answer.LastCommitSha = pr.LastMergeSourceCommit.CommitID
+	}
+	if pr.LastMergeCommit != nil && merged {
+		answer.MergeCommitSHA = &pr.LastMergeCommit.CommitID
+	}
+	return answer
+}
+
+// azureDevOpsPullRequestState maps the status of an Azure DevOps Pull Request to the open or closed states of a Pull
+// Request
+func azureDevOpsPullRequestState(status string) string {
+	switch status {
+	case "completed", "abandoned":
+		return "closed"
+	default:
+		return "open"
+	}
+}
+
+func toAzureDevOpsUser(identity *azureDevOpsIdentity) *GitUser {
+	return &GitUser{
+		URL:       identity.URL,
+		Login:     identity.UniqueName,
+		Name:      identity.DisplayName,
+		AvatarURL: identity.ImageURL,
+	}
+}
+
+func (p *AzureDevOpsProvider) GetPullRequestCommits(owner string, repo *GitRepositoryInfo, number int) ([]*GitCommit, error) {
+	answer := []*GitCommit{}
+	commits := []azureDevOpsCommit{}
+	err := p.list(util.UrlJoin(p.pullRequestPath(owner, repo.Name, number), "commits"), &commits)
+	if err != nil {
+		return answer, err
+	}
+	for _, commit := range commits {
+		c := &GitCommit{
+			SHA:     commit.CommitID,
+			Message: commit.Comment,
+			URL:     commit.RemoteURL,
+		}
+		if commit.Author != nil {
+			c.Author = &GitUser{
+				Name:  commit.Author.Name,
+				Email: commit.Author.Email,
+			}
+		}
+		if commit.Committer != nil {
+			c.Committer = &GitUser{
+				Name:  commit.Committer.Name,
+				Email: commit.Committer.Email,
+			}
+		}
+		answer = append(answer, c)
+	}
+	return answer, nil
+}
+
+func (p *AzureDevOpsProvider) PullRequestLastCommitStatus(pr *GitPullRequest) (string, error) {
+	if pr.LastCommitSha == "" {
+		return "", fmt.Errorf("Pull Request %s has no last commit", pr.URL)
+	}
+	statuses, err := p.ListCommitStatus(pr.Owner, pr.Repo, pr.LastCommitSha)
+	if err != nil {
+		return "", err
+	}
+	for _, status := range statuses {
+		return status.State, nil
+	}
+	return "", fmt.Errorf("Could not find a status for the last commit of Pull Request %s", pr.URL)
+}
+
+func (p *AzureDevOpsProvider) ListCommitStatus(org string, repo string, sha string) ([]*GitRepoStatus, error) {
+	answer := []*GitRepoStatus{}
+	statuses := []azureDevOpsStatus{}
+	err := p.list(util.UrlJoin(p.repoPath(org, repo), "commits", sha, "statuses"), &statuses)
+	if err != nil {
+		return answer, err
+	}
+	for i := range statuses {
+		answer = append(answer, toAzureDevOpsRepoStatus(&statuses[i]))
+	}
+	return answer, nil
+}
+
+func (p *AzureDevOpsProvider) UpdateCommitStatus(org string, repo string, sha string, status *GitRepoStatus) (*GitRepoStatus, error) {
+	state, ok := azureDevOpsStates[status.State]
+	if !ok {
+		return nil, fmt.Errorf("Unknown commit status state %s", status.State)
+	}
+	body := &azureDevOpsStatus{
+		State:       state,
+		Description: status.Description,
+		TargetURL:   status.TargetURL,
+		Context: azureDevOpsStatusContext{
+			Name:  status.Context,
+			Genre: azureDevOpsStatusGenre,
+		},
+	}
+	answer := &azureDevOpsStatus{}
+	err := p.request(http.MethodPost, util.UrlJoin(p.repoPath(org, repo), "commits", sha, "statuses"), body, answer)
+	if err != nil {
+		return nil, err
+	}
+	return toAzureDevOpsRepoStatus(answer), nil
+}
+
+func toAzureDevOpsRepoStatus(status *azureDevOpsStatus) *GitRepoStatus {
+	answer := &GitRepoStatus{
+		ID:          strconv.Itoa(status.ID),
+		Context:     status.Context.Name,
+		URL:         status.TargetURL,
+		TargetURL:   status.TargetURL,
+		State:       status.State,
+		Description: status.Description,
+	}
+	for k, v := range azureDevOpsStates {
+		if v == status.State {
+			answer.State = k
+		}
+	}
+	return answer
+}
+
+func (p *AzureDevOpsProvider) MergePullRequest(pr *GitPullRequest, message string) error {
+	if pr.Number == nil {
+		return fmt.Errorf("Missing Number for GitPullRequest %#v", pr)
+	}
+	current, err := p.getPullRequest(pr.Owner, pr.Repo, *pr.Number)
+	if err != nil {
+		return err
+	}
+	body := &azureDevOpsPullRequest{
+		Status:                "completed",
+		LastMergeSourceCommit: current.LastMergeSourceCommit,
+		CompletionOptions: &azureDevOpsCompletionOptions{
+			MergeCommitMessage: message,
+		},
+	}
+	return p.request(http.MethodPatch, p.pullRequestPath(pr.Owner, pr.Repo, *pr.Number), body, nil)
+}
+
+// CreateWebHook creates the service hook subscriptions which post the push, Pull Request and comment events of the
+// repository to the URL of the webhook, replacing the subscriptions of an existing webhook with the same URL. The
+// secret of the webhook is sent as the password of basic authentication
+func (p *AzureDevOpsProvider) CreateWebHook(data *GitWebHookArguments) error {
+	repo, err := p.getRepository(data.Owner, data.Repo.Name)
+	if err != nil {
+		return err
+	}
+	err = p.deleteSubscriptions(repo, data.URL)
+	if err != nil {
+		return err
+	}
+	for _, event := range azureDevOpsWebHookEvents {
+		subscription := &azureDevOpsSubscription{
+			PublisherID:      "tfs",
+			EventType:        event,
+			ResourceVersion:  "1.0",
+			ConsumerID:       "webHooks",
+			ConsumerActionID: "httpRequest",
+			PublisherInputs: map[string]string{
+				"projectId":  repo.Project.ID,
+				"repository": repo.ID,
+			},
+			ConsumerInputs: map[string]string{
+				"url": data.URL,
+			},
+		}
+		if data.Secret != "" {
+			subscription.ConsumerInputs["basicAuthUsername"] = AzureDevOpsWebHookUsername
+			subscription.ConsumerInputs["basicAuthPassword"] = data.Secret
+		}
+		log.Infof("Creating the %s service hook of repository %s to %s\n", event, util.ColorInfo(data.Repo.Name), util.ColorInfo(data.URL))
+		err = p.request(http.MethodPost, "_apis/hooks/subscriptions", subscription, nil)
+		if err != nil {
+			return fmt.Errorf("Failed to create the %s service hook of repository %s due to: %s", event, data.Repo.Name, err)
+		}
+	}
+	return nil
+}
+
+// ListWebHooks returns the URLs which the service hook subscriptions of the repository post its events to
+func (p *AzureDevOpsProvider) ListWebHooks(owner string, repoName string) ([]*GitWebHookArguments, error) {
+	answer := []*GitWebHookArguments{}
+	repo, err := p.getRepository(owner, repoName)
+	if err != nil {
+		return answer, err
+	}
+	subscriptions, err := p.listSubscriptions(repo)
+	if err != nil {
+		return answer, err
+	}
+	urls := map[string]bool{}
+	for _, subscription := range subscriptions {
+		u := subscription.ConsumerInputs["url"]
+		if u == "" || urls[u] {
+			continue
+		}
+		urls[u] = true
+		answer = append(answer, &GitWebHookArguments{
+			Owner: owner,
+			Repo: &GitRepositoryInfo{
+				Name: repoName,
+			},
+			URL: u,
+		})
+	}
+	return answer, nil
+}
+
+// UpdateWebHook replaces the service hook subscriptions of the webhook as they cannot be updated in place
+func (p *AzureDevOpsProvider) UpdateWebHook(data *GitWebHookArguments) error {
+	return p.CreateWebHook(data)
+}
+
+func (p *AzureDevOpsProvider) DeleteWebHook(owner string, repoName string, webhookURL string) error {
+	repo, err := p.getRepository(owner, repoName)
+	if err != nil {
+		return err
+	}
+	return p.deleteSubscriptions(repo, webhookURL)
+}
+
+// listSubscriptions returns the service hook subscriptions of the repository
+func (p *AzureDevOpsProvider) listSubscriptions(repo *azureDevOpsRepository) ([]azureDevOpsSubscription, error) {
+	answer := []azureDevOpsSubscription{}
+	subscriptions := []azureDevOpsSubscription{}
+	err := p.list("_apis/hooks/subscriptions", &subscriptions)
+	if err != nil {
+		return answer, err
+	}
+	for _, subscription := range subscriptions {
+		if subscription.PublisherInputs["repository"] == repo.ID {
+			answer = append(answer, subscription)
+		}
+	}
+	return answer, nil
+}
+
+// deleteSubscriptions deletes the service hook subscriptions of the repository which post to the URL
+func (p *AzureDevOpsProvider) deleteSubscriptions(repo *azureDevOpsRepository, webhookURL string) error {
+	subscriptions, err := p.listSubscriptions(repo)
+	if err != nil {
+		return err
+	}
+	for _, subscription := range subscriptions {
+		if subscription.ConsumerInputs["url"] != webhookURL {
+			continue
+		}
+		err = p.request(http.MethodDelete, util.UrlJoin("_apis/hooks/subscriptions", subscription.ID), nil, nil)
+		if err != nil {
+			return fmt.Errorf("Failed to delete the service hook %s of repository %s due to: %s", subscription.ID, repo.Name, err)
+		}
+	}
+	return nil
+}
+
+func (p *AzureDevOpsProvider) IsGitHub() bool {
+	return false
+}
+
+func (p *AzureDevOpsProvider) IsGitea() bool {
+	return false
+}
+
+func (p *AzureDevOpsProvider) IsBitbucketCloud() bool {
+	return false
+}
+
+func (p *AzureDevOpsProvider) IsBitbucketServer() bool {
+	return false
+}
+
+func (p *AzureDevOpsProvider) IsGerrit() bool {
+	return false
+}
+
+func (p *AzureDevOpsProvider) Kind() string {
+	return KindAzureDevOps
+}
+
+func (p *AzureDevOpsProvider) GetIssue(org string, name string, number int) (*GitIssue, error) {
+	log.Warn("Azure DevOps work items are not supported as issues\n")
+	return nil, nil
+}
+
+func (p *AzureDevOpsProvider) IssueURL(org string, name string, number int, isPull bool) string {
+	if isPull {
+		return util.UrlJoin(p.repositoryWebURL(org, name), "pullrequest", strconv.Itoa(number))
+	}
+	return util.UrlJoin(p.Server.URL, url.PathEscape(org), "_workitems/edit", strconv.Itoa(number))
+}
+
+func (p *AzureDevOpsProvider) SearchIssues(org string, name string, query string) ([]*GitIssue, error) {
+	log.Warn("Azure DevOps work items are not supported as issues\n")
+	return []*GitIssue{}, nil
+}
+
+func (p *AzureDevOpsProvider) SearchIssuesClosedSince(org string, name string, t time.Time) ([]*GitIssue, error) {
+	issues, err := p.SearchIssues(org, name, "")
+	if err != nil {
+		return issues, err
+	}
+	return FilterIssuesClosedSince(issues, t), nil
+}
+
+func (p *AzureDevOpsProvider) CreateIssue(owner string, repo string, issue *GitIssue) (*GitIssue, error) {
+	return nil, fmt.Errorf("Creating issues is not supported on Azure DevOps")
+}
+
+func (p *AzureDevOpsProvider) HasIssues() bool {
+	return false
+}
+
+func (p *AzureDevOpsProvider) AddPRComment(pr *GitPullRequest, comment string) error {
+	if pr.Number == nil {
+		return fmt.Errorf("Missing Number for GitPullRequest %#v", pr)
+	}
+	return p.CreateIssueComment(pr.Owner, pr.Repo, *pr.Number, comment)
+}
+
+// ListPRComments returns the first comment of each thread of the Pull Request. The ID of a comment is the ID of its
+// thread
+func (p *AzureDevOpsProvider) ListPRComments(pr *GitPullRequest) ([]*GitPRComment, error) {
+	answer := []*GitPRComment{}
+	if pr.Number == nil {
+		return answer, fmt.Errorf("Missing Number for GitPullRequest %#v", pr)
+	}
+	threads := []azureDevOpsThread{}
+	err := p.list(util.UrlJoin(p.pullRequestPath(pr.Owner, pr.Repo, *pr.Number), "threads"), &threads)
+	if err != nil {
+		return answer, err
+	}
+	for _, thread := range threads {
+		if thread.IsDeleted || len(thread.Comments) == 0 {
+			continue
+		}
+		comment := thread.Comments[0]
+		c := &GitPRComment{
+			ID:        thread.ID,
+			Body:      comment.Content,
+			CreatedAt: comment.PublishedDate,
+		}
+		if comment.Author != nil {
+			c.User = *toAzureDevOpsUser(comment.Author)
+		}
+		answer = append(answer, c)
+	}
+	return answer, nil
+}
+
+func (p *AzureDevOpsProvider) EditPRComment(pr *GitPullRequest, comment *GitPRComment, body string) error {
+	if pr.Number == nil {
+		return fmt.Errorf("Missing Number for GitPullRequest %#v", pr)
+	}
+	path := util.UrlJoin(p.pullRequestPath(pr.Owner, pr.Repo, *pr.Number), "threads", strconv.FormatInt(comment.ID, 10), "comments", "1")
+	return p.request(http.MethodPatch, path, &azureDevOpsComment{Content: body}, nil)
+}
+
+// CreateIssueComment adds a comment thread to the Pull Request with the number as Azure DevOps has no issues
+func (p *AzureDevOpsProvider) CreateIssueComment(owner string, repo string, number int, comment string) error {
+	thread := &azureDevOpsThread{
+		Comments: []azureDevOpsComment{
+			{
+				Content:     comment,
+				CommentType: "text",
+			},
+		},
+		Status: "active",
+	}
+	return p.request(http.MethodPost, util.UrlJoin(p.pullRequestPath(owner, repo, number), "threads"), thread, nil)
+}
+
+func (p *AzureDevOpsProvider) UpdateRelease(owner string, repo string, tag string, releaseInfo *GitRelease) error {
+	log.Warn("Azure DevOps doesn't support releases of Git repositories\n")
+	return nil
+}
+
+func (p *AzureDevOpsProvider) ListReleases(org string, name string) ([]*GitRelease, error) {
+	answer := []*GitRelease{}
+	log.Warn("Azure DevOps doesn't support releases of Git repositories\n")
+	return answer, nil
+}
+
+func (p *AzureDevOpsProvider) JenkinsWebHookPath(gitURL string, secret string) string {
+	return "/git/notifyCommit?url=" + url.QueryEscape(gitURL)
+}
+
+func (p *AzureDevOpsProvider) Label() string {
+	return p.Server.Label()
+}
+
+func (p *AzureDevOpsProvider) ServerURL() string {
+	return p.Server.URL
+}
+
+func (p *AzureDevOpsProvider) BranchArchiveURL(org string, name string, branch string) string {
+	return util.UrlJoin(p.RestURL, p.repoPath(org, name), "items") + "?path=/&$format=zip&download=true&versionDescriptor.version=" +
+		url.QueryEscape(branch) + "&api-version=" + azureDevOpsAPIVersion
+}
+
+func (p *AzureDevOpsProvider) CurrentUsername() string {
+	return p.Username
+}
+
+func (p *AzureDevOpsProvider) UserAuth() auth.UserAuth {
+	return p.User
+}
+
+func (p *AzureDevOpsProvider) UserInfo(username string) *GitUser {
+	return &GitUser{
+		Login: username,
+	}
+}
+
+func (p *AzureDevOpsProvider) AddCollaborator(user string, organisation string, repo string) error {
+	log.Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for Azure DevOps. Please add user: %v as a member of the project %s.\n", user, organisation)
+	return nil
+}
+
+func (p *AzureDevOpsProvider) ListInvitations() ([]*github.RepositoryInvitation, *github.Response, error) {
+	log.Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for Azure DevOps.\n")
+	return []*github.RepositoryInvitation{}, &github.Response{}, nil
+}
+
+func (p *AzureDevOpsProvider) AcceptInvitation(ID int64) (*github.Response, error) {
+	log.Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for Azure DevOps.\n")
+	return &github.Response{}, nil
+}
+
+// AzureDevOpsAccessTokenURL returns the URL of the page of an Azure DevOps organization which creates personal access
+// tokens
+func AzureDevOpsAccessTokenURL(url string) string {
+	return util.UrlJoin(url, "_usersSettings/tokens")
+}
+
+// repositoryWebURL returns the URL of the web page of the repository
+func (p *AzureDevOpsProvider) repositoryWebURL(org string, name string) string {
+	return util.UrlJoin(p.Server.URL, url.PathEscape(org), "_git", url.PathEscape(name))
+}
+
+// reposPath returns the path of the repositories of the project relative to the REST URL
+func (p *AzureDevOpsProvider) reposPath(org string) string {
+	return util.UrlJoin(url.PathEscape(org), "_apis/git/repositories")
+}
+
+// repoPath returns the path of the repository with the name or ID relative to the REST URL
+func (p *AzureDevOpsProvider) repoPath(org string, name string) string {
+	return util.UrlJoin(p.reposPath(org), url.PathEscape(name))
+}
+
+// pullRequestPath returns the path of the Pull Request relative to the REST URL
+func (p *AzureDevOpsProvider) pullRequestPath(org string, name string, number int) string {
+	return util.UrlJoin(p.repoPath(org, name), "pullrequests", strconv.Itoa(number))
+}
+
+// azureDevOpsRef returns the full name of the ref of the branch
+func azureDevOpsRef(branch string) string {
+	if strings.HasPrefix(branch, "refs/") {
+		return branch
+	}
+	return azureDevOpsRefsPrefix + branch
+}
+
+// list performs a GET of a list resource and decodes its values into the result
+func (p *AzureDevOpsProvider) list(path string, result interface{}) error {
+	page := &azureDevOpsList{}
+	err := p.request(http.MethodGet, path, nil, page)
+	if err != nil {
+		return err
+	}
+	if len(page.Value) == 0 {
+		return nil
+	}
+	return json.Unmarshal(page.Value, result)
+}
+
+// request performs a request against the REST API authenticating with the personal access token of the user
+func (p *AzureDevOpsProvider) request(method string, path string, body interface{}, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	u := util.UrlJoin(p.RestURL, path)
+	separator := "?"
+	if strings.Contains(u, "?") {
+		separator = "&"
+	}
+	u += separator + "api-version=" + azureDevOpsAPIVersion
+	req, err := http.NewRequest(method, u, reader)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(p.Username, p.User.ApiToken)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s failed with status %s: %s", method, u, resp.Status, string(data))
+	}
+	if result == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, result)
+}
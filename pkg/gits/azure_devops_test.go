@@ -0,0 +1,357 @@
+package gits_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/github"
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	azureDevOpsProject = "test-project"
+	azureDevOpsRepo    = "test-repo"
+	azureDevOpsSHA     = "d6f24ee03d76a2caf0a4e1975fb43e8f61759b9c"
+)
+
+type AzureDevOpsProviderTestSuite struct {
+	suite.Suite
+	mux      *http.ServeMux
+	server   *httptest.Server
+	provider *gits.AzureDevOpsProvider
+}
+
+var azureDevOpsRouter = util.Router{
+	"/_apis/projects": util.MethodMap{
+		"GET": "projects.json",
+	},
+	"/_apis/projects/test-project": util.MethodMap{
+		"GET": "project.json",
+	},
+	"/test-project/_apis/git/repositories": util.MethodMap{
+		"GET":  "repos.json",
+		"POST": "repo.json",
+	},
+	"/test-project/_apis/git/repositories/test-repo": util.MethodMap{
+		"GET": "repo.json",
+	},
+	"/test-project/_apis/git/repositories/5febef5a-833d-4e14-b9c0-14cb638f91e6": util.MethodMap{
+		"PATCH":  "repo.json",
+		"DELETE": "empty.json",
+	},
+	"/test-project/_apis/git/repositories/test-repo/pullrequests": util.MethodMap{
+		"POST": "pr.json",
+	},
+	"/test-project/_apis/git/repositories/test-repo/pullrequests/1": util.MethodMap{
+		"GET":   "pr.json",
+		"PATCH": "pr.json",
+	},
+	"/test-project/_apis/git/repositories/test-repo/pullrequests/1/commits": util.MethodMap{
+		"GET": "pr-commits.json",
+	},
+	"/test-project/_apis/git/repositories/test-repo/pullrequests/1/threads": util.MethodMap{
+		"GET":  "threads.json",
+		"POST": "empty.json",
+	},
+	"/test-project/_apis/git/repositories/test-repo/pullrequests/1/threads/7/comments/1": util.MethodMap{
+		"PATCH": "empty.json",
+	},
+	"/test-project/_apis/git/repositories/test-repo/commits/d6f24ee03d76a2caf0a4e1975fb43e8f61759b9c/statuses": util.MethodMap{
+		"GET":  "statuses.json",
+		"POST": "status.json",
+	},
+	"/_apis/hooks/subscriptions": util.MethodMap{
+		"GET":  "subscriptions.json",
+		"POST": "empty.json",
+	},
+	"/_apis/hooks/subscriptions/4f9da0ed-3a3a-4d4f-9a1d-8a1a0c3b9f37": util.MethodMap{
+		"DELETE": "empty.json",
+	},
+}
+
+func (suite *AzureDevOpsProviderTestSuite) SetupSuite() {
+	suite.mux = http.NewServeMux()
+
+	for path, methodMap := range azureDevOpsRouter {
+		suite.mux.HandleFunc(path, util.GetMockAPIResponseFromFile("test_data/azure_devops", methodMap))
+	}
+
+	suite.server = httptest.NewServer(suite.mux)
+	suite.Require().NotNil(suite.server)
+
+	as := auth.AuthServer{
+		URL:         suite.server.URL,
+		Name:        "Test Azure DevOps",
+		Kind:        gits.KindAzureDevOps,
+		CurrentUser: userName,
+	}
+	ua := auth.UserAuth{
+		Username: userName,
+		ApiToken: "0123456789abdef",
+	}
+
+	gitProvider, err := gits.NewAzureDevOpsProvider(&as, &ua, nil)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(gitProvider)
+
+	var ok bool
+	suite.provider, ok = gitProvider.(*gits.AzureDevOpsProvider)
+	suite.Require().True(ok)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TearDownSuite() {
+	suite.server.Close()
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestListOrganisations() {
+	orgs, err := suite.provider.ListOrganisations()
+
+	suite.Require().NoError(err)
+	suite.Require().Len(orgs, 2)
+	suite.Equal(azureDevOpsProject, orgs[0].Login)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestListRepositories() {
+	repos, err := suite.provider.ListRepositories(azureDevOpsProject)
+
+	suite.Require().NoError(err)
+	suite.Require().Len(repos, 2)
+	suite.Equal(azureDevOpsRepo, repos[0].Name)
+	suite.Equal("https://test-org@dev.azure.com/test-org/test-project/_git/test-repo", repos[0].CloneURL)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestCreateRepository() {
+	repo, err := suite.provider.CreateRepository(azureDevOpsProject, azureDevOpsRepo, true)
+
+	suite.Require().NoError(err)
+	suite.Equal(azureDevOpsRepo, repo.Name)
+	suite.Equal("https://dev.azure.com/test-org/test-project/_git/test-repo", repo.HTMLURL)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestDeleteRepository() {
+	err := suite.provider.DeleteRepository(azureDevOpsProject, azureDevOpsRepo)
+
+	suite.Require().NoError(err)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestPullRequests() {
+	pr, err := suite.provider.CreatePullRequest(&gits.GitPullRequestArguments{
+		Title: "Add a feature",
+		Head:  "feature",
+		Base:  "master",
+		GitRepositoryInfo: &gits.GitRepositoryInfo{
+			Organisation: azureDevOpsProject,
+			Name:         azureDevOpsRepo,
+		},
+	})
+	suite.Require().NoError(err)
+	suite.Equal(1, *pr.Number)
+	suite.Equal(suite.server.URL+"/test-project/_git/test-repo/pullrequest/1", pr.URL)
+	suite.Equal("feature", *pr.HeadRef)
+	suite.Equal("open", *pr.State)
+	suite.Equal(azureDevOpsSHA, pr.LastCommitSha)
+	suite.True(*pr.Mergeable)
+	suite.False(*pr.Merged)
+
+	err = suite.provider.UpdatePullRequestStatus(pr)
+	suite.Require().NoError(err)
+
+	commits, err := suite.provider.GetPullRequestCommits(azureDevOpsProject, &gits.GitRepositoryInfo{Name: azureDevOpsRepo}, 1)
+	suite.Require().NoError(err)
+	suite.Require().Len(commits, 1)
+	suite.Equal(azureDevOpsSHA, commits[0].SHA)
+	suite.Equal("test-user@example.com", commits[0].Author.Email)
+
+	status, err := suite.provider.PullRequestLastCommitStatus(pr)
+	suite.Require().NoError(err)
+	suite.Equal("success", status)
+
+	err = suite.provider.MergePullRequest(pr, "Merged")
+	suite.Require().NoError(err)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestPullRequestComments() {
+	number := 1
+	pr := &gits.GitPullRequest{
+		Owner:  azureDevOpsProject,
+		Repo:   azureDevOpsRepo,
+		Number: &number,
+	}
+	comments, err := suite.provider.ListPRComments(pr)
+	suite.Require().NoError(err)
+	suite.Require().Len(comments, 1)
+	suite.Equal(int64(7), comments[0].ID)
+	suite.Equal("Looks good", comments[0].Body)
+	suite.Equal("test-user@example.com", comments[0].User.Login)
+
+	err = suite.provider.EditPRComment(pr, comments[0], "Looks great")
+	suite.Require().NoError(err)
+
+	err = suite.provider.AddPRComment(pr, "Thanks")
+	suite.Require().NoError(err)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestUpdateCommitStatus() {
+	status, err := suite.provider.UpdateCommitStatus(azureDevOpsProject, azureDevOpsRepo, azureDevOpsSHA, &gits.GitRepoStatus{
+		State:       "pending",
+		Context:     "continuous-integration/jenkins-x",
+		Description: "The build is running",
+		TargetURL:   "https://jenkins.example.com/job/test-repo/2",
+	})
+	suite.Require().NoError(err)
+	suite.Equal("pending", status.State)
+	suite.Equal("2", status.ID)
+
+	_, err = suite.provider.UpdateCommitStatus(azureDevOpsProject, azureDevOpsRepo, azureDevOpsSHA, &gits.GitRepoStatus{
+		State: "unknown",
+	})
+	suite.Error(err)
+}
+
+func (suite *AzureDevOpsProviderTestSuite) TestWebHooks() {
+	hooks, err := suite.provider.ListWebHooks(azureDevOpsProject, azureDevOpsRepo)
+	suite.Require().NoError(err)
+	suite.Require().Len(hooks, 1)
+	suite.Equal("http://hook.example.com/hook", hooks[0].URL)
+
+	err = suite.provider.CreateWebHook(&gits.GitWebHookArguments{
+		Owner:  azureDevOpsProject,
+		Repo:   &gits.GitRepositoryInfo{Name: azureDevOpsRepo},
+		URL:    "http://hook.example.com/hook",
+		Secret: "secret",
+	})
+	suite.Require().NoError(err)
+
+	err = suite.provider.DeleteWebHook(azureDevOpsProject, azureDevOpsRepo, "http://hook.example.com/hook")
+	suite.Require().NoError(err)
+}
+
+func TestAzureDevOpsProviderTestSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping AzureDevOpsProviderTestSuite in short mode")
+	} else {
+		suite.Run(t, new(AzureDevOpsProviderTestSuite))
+	}
+}
+
+func loadAzureDevOpsEvent(t *testing.T, name string) []byte {
+	data, err := ioutil.ReadFile(filepath.Join("test_data", "azure_devops", name))
+	require.NoError(t, err)
+	return data
+}
+
+func TestTranslateAzureDevOpsPushWebHook(t *testing.T) {
+	t.Parallel()
+	hook, err := gits.TranslateAzureDevOpsWebHook(loadAzureDevOpsEvent(t, "push-event.json"))
+	require.NoError(t, err)
+	require.NotNil(t, hook)
+	assert.Equal(t, "push", hook.Event)
+	assert.Equal(t, "03c164c2-8912-4d5e-8009-3707d5f83734", hook.Delivery)
+
+	event := &github.PushEvent{}
+	require.NoError(t, json.Unmarshal(hook.Payload, event))
+	assert.Equal(t, "refs/heads/master", event.GetRef())
+	assert.Equal(t, "aad331d8d3b131fa9ae03cf5e53965b51942618a", event.GetBefore())
+	assert.Equal(t, "33b55f7cb7e7e245323987634f960cf4a6e6bc74", event.GetAfter())
+	assert.False(t, event.GetCreated())
+	assert.Equal(t, "test-project/test-repo", event.GetRepo().GetFullName())
+	assert.Equal(t, "test-project", event.GetRepo().GetOwner().GetLogin())
+	assert.Equal(t, "master", event.GetRepo().GetDefaultBranch())
+	require.Len(t, event.Commits, 1)
+	assert.Equal(t, "Fixed bug in web.config file", event.GetHeadCommit().GetMessage())
+	assert.Equal(t, "test-user@example.com", event.GetSender().GetLogin())
+}
+
+func TestTranslateAzureDevOpsPullRequestWebHook(t *testing.T) {
+	t.Parallel()
+	hook, err := gits.TranslateAzureDevOpsWebHook(loadAzureDevOpsEvent(t, "pr-event.json"))
+	require.NoError(t, err)
+	require.NotNil(t, hook)
+	assert.Equal(t, "pull_request", hook.Event)
+
+	event := &github.PullRequestEvent{}
+	require.NoError(t, json.Unmarshal(hook.Payload, event))
+	assert.Equal(t, "closed", event.GetAction())
+	assert.Equal(t, 1, event.GetNumber())
+	pr := event.GetPullRequest()
+	assert.True(t, pr.GetMerged())
+	assert.Equal(t, "feature", pr.GetHead().GetRef())
+	assert.Equal(t, azureDevOpsSHA, pr.GetHead().GetSHA())
+	assert.Equal(t, "master", pr.GetBase().GetRef())
+	assert.Equal(t, "b60280bc6e62e2f880f1b63c1e24987664d3bda3", pr.GetMergeCommitSHA())
+	assert.Equal(t, "https://dev.azure.com/test-org/test-project/_git/test-repo/pullrequest/1", pr.GetHTMLURL())
+	assert.Equal(t, "test-user@example.com", pr.GetUser().GetLogin())
+}
+
+func TestTranslateAzureDevOpsCommentWebHook(t *testing.T) {
+	t.Parallel()
+	hook, err := gits.TranslateAzureDevOpsWebHook(loadAzureDevOpsEvent(t, "comment-event.json"))
+	require.NoError(t, err)
+	require.NotNil(t, hook)
+	assert.Equal(t, "issue_comment", hook.Event)
+
+	event := &github.IssueCommentEvent{}
+	require.NoError(t, json.Unmarshal(hook.Payload, event))
+	assert.Equal(t, "created", event.GetAction())
+	assert.Equal(t, 1, event.GetIssue().GetNumber())
+	assert.True(t, event.GetIssue().IsPullRequest())
+	assert.Equal(t, "open", event.GetIssue().GetState())
+	assert.Equal(t, "/approve", event.GetComment().GetBody())
+	assert.Equal(t, "approver@example.com", event.GetComment().GetUser().GetLogin())
+}
+
+func TestTranslateAzureDevOpsUnknownWebHook(t *testing.T) {
+	t.Parallel()
+	hook, err := gits.TranslateAzureDevOpsWebHook([]byte(`{"eventType": "build.complete"}`))
+	require.NoError(t, err)
+	assert.Nil(t, hook)
+
+	_, err = gits.TranslateAzureDevOpsWebHook([]byte(`not json`))
+	assert.Error(t, err)
+}
+
+func TestAzureDevOpsWebHookProxy(t *testing.T) {
+	t.Parallel()
+	var headers http.Header
+	var payload []byte
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		payload, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer target.Close()
+	proxy := httptest.NewServer(&gits.AzureDevOpsWebHookProxy{
+		TargetURL: target.URL,
+		Secret:    "secret",
+	})
+	defer proxy.Close()
+
+	post := func(password string) *http.Response {
+		req, err := http.NewRequest(http.MethodPost, proxy.URL, strings.NewReader(string(loadAzureDevOpsEvent(t, "push-event.json"))))
+		require.NoError(t, err)
+		req.SetBasicAuth(gits.AzureDevOpsWebHookUsername, password)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+
+	resp := post("wrong")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Nil(t, headers)
+
+	resp = post("secret")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NotNil(t, headers)
+	assert.Equal(t, "push", headers.Get(gits.GitHubEventHeader))
+	assert.Equal(t, gits.GitHubWebHookSignature(payload, "secret"), headers.Get(gits.GitHubSignatureHeader))
+}
@@ -0,0 +1,412 @@
+package gits
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/github"
+	"github.com/jenkins-x/jx/pkg/log"
+)
+
+const (
+	// AzureDevOpsEventPush the service hook event of a push to a repository
+	AzureDevOpsEventPush = "git.push"
+
+	// AzureDevOpsEventPullRequestCreated the service hook event of a new Pull Request
+	AzureDevOpsEventPullRequestCreated = "git.pullrequest.created"
+
+	// AzureDevOpsEventPullRequestUpdated the service hook event of a change to a Pull Request such as a push to its
+	// source branch or abandoning it
+	AzureDevOpsEventPullRequestUpdated = "git.pullrequest.updated"
+
+	// AzureDevOpsEventPullRequestMerged the service hook event of a merge of a Pull Request
+	AzureDevOpsEventPullRequestMerged = "git.pullrequest.merged"
+
+	// AzureDevOpsEventPullRequestCommented the service hook event of a comment on a Pull Request
+	AzureDevOpsEventPullRequestCommented = "ms.vss-code.git-pullrequest-comment-event"
+
+	// AzureDevOpsWebHookUsername the basic authentication user of the service hooks created for webhooks. The password
+	// is the webhook secret
+	AzureDevOpsWebHookUsername = "jenkins-x"
+
+	// GitHubEventHeader the header of the name of the event of a GitHub webhook
+	GitHubEventHeader = "X-GitHub-Event"
+
+	// GitHubDeliveryHeader the header of the unique ID of the delivery of a GitHub webhook
+	GitHubDeliveryHeader = "X-GitHub-Delivery"
+
+	// GitHubSignatureHeader the header of the HMAC signature of the payload of a GitHub webhook
+	GitHubSignatureHeader = "X-Hub-Signature"
+)
+
+// AzureDevOpsWebHookEvent the payload Azure DevOps posts for a service hook subscription
+type AzureDevOpsWebHookEvent struct {
+	ID          string          `json:"id"`
+	EventType   string          `json:"eventType"`
+	PublisherID string          `json:"publisherId"`
+	Resource    json.RawMessage `json:"resource"`
+	CreatedDate *time.Time      `json:"createdDate,omitempty"`
+}
+
+type azureDevOpsRefUpdate struct {
+	Name        string `json:"name"`
+	OldObjectID string `json:"oldObjectId"`
+	NewObjectID string `json:"newObjectId"`
+}
+
+type azureDevOpsPush struct {
+	PushID     int64                  `json:"pushId"`
+	Commits    []azureDevOpsCommit    `json:"commits"`
+	RefUpdates []azureDevOpsRefUpdate `json:"refUpdates"`
+	Repository *azureDevOpsRepository `json:"repository"`
+	PushedBy   *azureDevOpsIdentity   `json:"pushedBy"`
+}
+
+type azureDevOpsPullRequestComment struct {
+	Comment     *azureDevOpsComment     `json:"comment"`
+	PullRequest *azureDevOpsPullRequest `json:"pullRequest"`
+}
+
+// GitHubWebHook a webhook event in the format GitHub posts it
+type GitHubWebHook struct {
+	// Event the name of the event such as push or pull_request
+	Event string
+	// Delivery the unique ID of the delivery
+	Delivery string
+	// Payload the JSON payload of the event
+	Payload []byte
+}
+
+// TranslateAzureDevOpsWebHook translates the payload of an Azure DevOps service hook into the GitHub webhook of the
+// same event so that it can be handled by tools which only understand GitHub webhooks such as the Prow hook. Nil is
+// returned for events which have no GitHub equivalent
+func TranslateAzureDevOpsWebHook(payload []byte) (*GitHubWebHook, error) {
+	event := &AzureDevOpsWebHookEvent{}
+	err := json.Unmarshal(payload, event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the Azure DevOps service hook event: %s", err)
+	}
+	var name string
+	var answer interface{}
+	switch event.EventType {
+	case AzureDevOpsEventPush:
+		push := &azureDevOpsPush{}
+		err = json.Unmarshal(event.Resource, push)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the resource of the %s event: %s", event.EventType, err)
+		}
+		name = "push"
+		answer = toGitHubPushEvent(push)
+	case AzureDevOpsEventPullRequestCreated, AzureDevOpsEventPullRequestUpdated, AzureDevOpsEventPullRequestMerged:
+		pr := &azureDevOpsPullRequest{}
+		err = json.Unmarshal(event.Resource, pr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the resource of the %s event: %s", event.EventType, err)
+		}
+		name = "pull_request"
+		answer = toGitHubPullRequestEvent(event.EventType, pr)
+	case AzureDevOpsEventPullRequestCommented:
+		resource := &azureDevOpsPullRequestComment{}
+		err = json.Unmarshal(event.Resource, resource)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the resource of the %s event: %s", event.EventType, err)
+		}
+		if resource.Comment == nil || resource.PullRequest == nil {
+			return nil, fmt.Errorf("the %s event has no comment or Pull Request", event.EventType)
+		}
+		name = "issue_comment"
+		answer = toGitHubIssueCommentEvent(resource)
+	default:
+		return nil, nil
+	}
+	data, err := json.Marshal(answer)
+	if err != nil {
+		return nil, err
+	}
+	return &GitHubWebHook{
+		Event:    name,
+		Delivery: event.ID,
+		Payload:  data,
+	}, nil
+}
+
+// GitHubWebHookSignature returns the value of the X-Hub-Signature header GitHub signs the payload of a webhook with
+func GitHubWebHookSignature(payload []byte, secret string) string {
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write(payload)
+	return "sha1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func toGitHubPushEvent(push *azureDevOpsPush) *github.PushEvent {
+	answer := &github.PushEvent{
+		PushID: github.Int64(push.PushID),
+		Repo:   toGitHubPushEventRepository(push.Repository),
+	}
+	if len(push.RefUpdates) > 0 {
+		update := push.RefUpdates[0]
+		answer.Ref = github.String(update.Name)
+		answer.Before = github.String(update.OldObjectID)
+		answer.After = github.String(update.NewObjectID)
+		answer.Head = github.String(update.NewObjectID)
+		answer.Created = github.Bool(isZeroObjectID(update.OldObjectID))
+		answer.Deleted = github.Bool(isZeroObjectID(update.NewObjectID))
+	}
+	for _, commit := range push.Commits {
+		c := github.PushEventCommit{
+			ID:       github.String(commit.CommitID),
+			SHA:      github.String(commit.CommitID),
+			Message:  github.String(commit.Comment),
+			URL:      github.String(commit.RemoteURL),
+			Distinct: github.Bool(true),
+		}
+		if commit.Author != nil {
+			c.Author = &github.CommitAuthor{
+				Name:  github.String(commit.Author.Name),
+				Email: github.String(commit.Author.Email),
+			}
+		}
+		if commit.Committer != nil {
+			c.Committer = &github.CommitAuthor{
+				Name:  github.String(commit.Committer.Name),
+				Email: github.String(commit.Committer.Email),
+			}
+		}
+		answer.Commits = append(answer.Commits, c)
+	}
+	answer.Size = github.Int(len(answer.Commits))
+	if len(answer.Commits) > 0 {
+		// Azure DevOps lists the most recent commit first
+		answer.HeadCommit = &answer.Commits[0]
+	}
+	if push.PushedBy != nil {
+		answer.Pusher = toGitHubIdentity(push.PushedBy)
+		answer.Sender = toGitHubIdentity(push.PushedBy)
+	}
+	return answer
+}
+
+func toGitHubPullRequestEvent(eventType string, pr *azureDevOpsPullRequest) *github.PullRequestEvent {
+	action := "synchronize"
+	switch {
+	case eventType == AzureDevOpsEventPullRequestCreated:
+		action = "opened"
+	case pr.Status == "completed" || pr.Status == "abandoned":
+		action = "closed"
+	}
+	answer := &github.PullRequestEvent{
+		Action:      github.String(action),
+		Number:      github.Int(pr.PullRequestID),
+		PullRequest: toGitHubPullRequest(pr),
+		Repo:        toGitHubRepository(pr.Repository),
+	}
+	if pr.CreatedBy != nil {
+		answer.Sender = toGitHubIdentity(pr.CreatedBy)
+	}
+	return answer
+}
+
+func toGitHubIssueCommentEvent(resource *azureDevOpsPullRequestComment) *github.IssueCommentEvent {
+	pr := resource.PullRequest
+	comment := resource.Comment
+	state := azureDevOpsPullRequestState(pr.Status)
+	htmlURL := azureDevOpsPullRequestWebURL(pr)
+	answer := &github.IssueCommentEvent{
+		Action: github.String("created"),
+		Issue: &github.Issue{
+			Number:  github.Int(pr.PullRequestID),
+			Title:   github.String(pr.Title),
+			Body:    github.String(pr.Description),
+			State:   github.String(state),
+			HTMLURL: github.String(htmlURL),
+			PullRequestLinks: &github.PullRequestLinks{
+				URL:     github.String(pr.URL),
+				HTMLURL: github.String(htmlURL),
+			},
+		},
+		Comment: &github.IssueComment{
+			ID:        github.Int64(int64(comment.ID)),
+			Body:      github.String(comment.Content),
+			CreatedAt: comment.PublishedDate,
+		},
+		Repo: toGitHubRepository(pr.Repository),
+	}
+	if pr.CreatedBy != nil {
+		answer.Issue.User = toGitHubIdentity(pr.CreatedBy)
+	}
+	if comment.Author != nil {
+		answer.Comment.User = toGitHubIdentity(comment.Author)
+		answer.Sender = toGitHubIdentity(comment.Author)
+	}
+	return answer
+}
+
+func toGitHubPullRequest(pr *azureDevOpsPullRequest) *github.PullRequest {
+	state := azureDevOpsPullRequestState(pr.Status)
+	repo := toGitHubRepository(pr.Repository)
+	answer := &github.PullRequest{
+		Number:    github.Int(pr.PullRequestID),
+		State:     github.String(state),
+		Title:     github.String(pr.Title),
+		Body:      github.String(pr.Description),
+		URL:       github.String(pr.URL),
+		HTMLURL:   github.String(azureDevOpsPullRequestWebURL(pr)),
+		Merged:    github.Bool(pr.Status == "completed"),
+		Mergeable: github.Bool(pr.MergeStatus == "succeeded"),
+		ClosedAt:  pr.ClosedDate,
+		Head: &github.PullRequestBranch{
+			Ref:  github.String(strings.TrimPrefix(pr.SourceRefName, azureDevOpsRefsPrefix)),
+			Repo: repo,
+		},
+		Base: &github.PullRequestBranch{
+			Ref:  github.String(strings.TrimPrefix(pr.TargetRefName, azureDevOpsRefsPrefix)),
+			Repo: repo,
+		},
+	}
+	if pr.Status == "completed" {
+		answer.MergedAt = pr.ClosedDate
+	}
+	if pr.LastMergeSourceCommit != nil {
+		answer.Head.SHA = github.String(pr.LastMergeSourceCommit.CommitID)
+	}
+	if pr.LastMergeCommit != nil {
+		answer.MergeCommitSHA = github.String(pr.LastMergeCommit.CommitID)
+	}
+	if pr.CreatedBy != nil {
+		answer.User = toGitHubIdentity(pr.CreatedBy)
+	}
+	return answer
+}
+
+// toGitHubRepository returns the GitHub repository of an Azure DevOps repository whose owner is its project
+func toGitHubRepository(repo *azureDevOpsRepository) *github.Repository {
+	if repo == nil {
+		return nil
+	}
+	owner := ""
+	if repo.Project != nil {
+		owner = repo.Project.Name
+	}
+	answer := &github.Repository{
+		Name:     github.String(repo.Name),
+		FullName: github.String(owner + "/" + repo.Name),
+		Owner: &github.User{
+			Login: github.String(owner),
+		},
+		URL:      github.String(repo.URL),
+		HTMLURL:  github.String(repo.WebURL),
+		CloneURL: github.String(repo.RemoteURL),
+		SSHURL:   github.String(repo.SSHURL),
+	}
+	if repo.DefaultBranch != "" {
+		answer.DefaultBranch = github.String(strings.TrimPrefix(repo.DefaultBranch, azureDevOpsRefsPrefix))
+	}
+	return answer
+}
+
+func toGitHubPushEventRepository(repo *azureDevOpsRepository) *github.PushEventRepository {
+	r := toGitHubRepository(repo)
+	if r == nil {
+		return nil
+	}
+	return &github.PushEventRepository{
+		Name:          r.Name,
+		FullName:      r.FullName,
+		Owner:         r.Owner,
+		URL:           r.URL,
+		HTMLURL:       r.HTMLURL,
+		CloneURL:      r.CloneURL,
+		SSHURL:        r.SSHURL,
+		DefaultBranch: r.DefaultBranch,
+	}
+}
+
+func toGitHubIdentity(identity *azureDevOpsIdentity) *github.User {
+	return &github.User{
+		Login:     github.String(identity.UniqueName),
+		Name:      github.String(identity.DisplayName),
+		URL:       github.String(identity.URL),
+		AvatarURL: github.String(identity.ImageURL),
+	}
+}
+
+// azureDevOpsPullRequestWebURL returns the URL of the web page of the Pull Request
+func azureDevOpsPullRequestWebURL(pr *azureDevOpsPullRequest) string {
+	if pr.Repository == nil || pr.Repository.WebURL == "" {
+		return pr.URL
+	}
+	return fmt.Sprintf("%s/pullrequest/%d", strings.TrimSuffix(pr.Repository.WebURL, "/"), pr.PullRequestID)
+}
+
+// isZeroObjectID returns true if the object ID is the ID of a missing ref
+func isZeroObjectID(id string) bool {
+	return strings.Trim(id, "0") == ""
+}
+
+// AzureDevOpsWebHookProxy receives the service hook events of Azure DevOps and posts them to a GitHub webhook endpoint
+// such as the Prow hook, signed with the webhook secret
+type AzureDevOpsWebHookProxy struct {
+	// TargetURL the URL the translated GitHub webhooks are posted to
+	TargetURL string
+	// Secret the webhook secret which authenticates the service hooks and signs the translated webhooks
+	Secret string
+	// Client the HTTP client which posts the translated webhooks
+	Client *http.Client
+}
+
+// ServeHTTP implements http.Handler
+func (p *AzureDevOpsWebHookProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	if p.Secret != "" {
+		_, password, ok := r.BasicAuth()
+		if !ok || !hmac.Equal([]byte(password), []byte(p.Secret)) {
+			http.Error(w, "invalid webhook credentials", http.StatusUnauthorized)
+			return
+		}
+	}
+	payload, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	hook, err := TranslateAzureDevOpsWebHook(payload)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if hook == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	req, err := http.NewRequest(http.MethodPost, p.TargetURL, bytes.NewReader(hook.Payload))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(GitHubEventHeader, hook.Event)
+	req.Header.Set(GitHubDeliveryHeader, hook.Delivery)
+	req.Header.Set(GitHubSignatureHeader, GitHubWebHookSignature(hook.Payload, p.Secret))
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Warnf("Failed to post the %s webhook to %s: %s\n", hook.Event, p.TargetURL, err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	w.WriteHeader(resp.StatusCode)
+}
@@ -639,6 +639,39 @@ func (b *BitbucketCloudProvider) ListCommitStatus(org string, repo string, sha s
 	return statuses, nil
 }
 
+func (b *BitbucketCloudProvider) UpdateCommitStatus(org string, repo string, sha string, status *GitRepoStatus) (*GitRepoStatus, error) {
+	state, err := BitbucketServerBuildState(status.State)
+	if err != nil {
+		return nil, err
+	}
+	key := status.Context
+	if key == "" {
+		key = "jenkins-x"
+	}
+	options := map[string]interface{}{
+		"body": bitbucket.Commitstatus{
+			Type_:       "build",
+			Key:         key,
+			Name:        status.Context,
+			State:       state,
+			Url:         status.TargetURL,
+			Description: status.Description,
+		},
+	}
+	result, _, err := b.Client.RepositoriesApi.RepositoriesUsernameRepoSlugCommitNodeStatusesBuildPost(b.Context, org, repo, sha, options)
+	if err != nil {
+		return nil, err
+	}
+	return &GitRepoStatus{
+		ID:          result.Key,
+		Context:     result.Name,
+		URL:         result.Url,
+		State:       stateMap[result.State],
+		TargetURL:   result.Url,
+		Description: result.Description,
+	}, nil
+}
+
 func (b *BitbucketCloudProvider) MergePullRequest(pr *GitPullRequest, message string) error {
 
 	options := map[string]interface{}{
@@ -690,6 +723,43 @@ func (b *BitbucketCloudProvider) CreateWebHook(data *GitWebHookArguments) error
 	return nil
 }
 
+func (b *BitbucketCloudProvider) ListWebHooks(owner string, repo string) ([]*GitWebHookArguments, error) {
+	answer := []*GitWebHookArguments{}
+	hooks, _, err := b.Client.RepositoriesApi.RepositoriesUsernameRepoSlugHooksGet(b.Context, owner, repo)
+	if err != nil {
+		return answer, err
+	}
+	for _, hook := range hooks.Values {
+		answer = append(answer, &GitWebHookArguments{
+			Owner: owner,
+			Repo:  &GitRepositoryInfo{Organisation: owner, Name: repo},
+			URL:   hook.Url,
+		})
+	}
+	return answer, nil
+}
+
+func (b *BitbucketCloudProvider) UpdateWebHook(data *GitWebHookArguments) error {
+	log.Warn("Updating webhooks on Bitbucket Cloud is not supported at this moment")
+	return nil
+}
+
+func (b *BitbucketCloudProvider) DeleteWebHook(owner string, repo string, webhookURL string) error {
+	hooks, _, err := b.Client.RepositoriesApi.RepositoriesUsernameRepoSlugHooksGet(b.Context, owner, repo)
+	if err != nil {
+		return err
+	}
+	for _, hook := range hooks.Values {
+		if hook.Url == webhookURL {
+			_, err = b.Client.RepositoriesApi.RepositoriesUsernameRepoSlugHooksUidDelete(b.Context, owner, repo, hook.Uuid)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func BitbucketIssueToGitIssue(bIssue bitbucket.Issue) *GitIssue {
 	id := int(bIssue.Id)
 	ownerAndRepo := strings.Split(bIssue.Repository.FullName, "/")
@@ -820,6 +890,14 @@ func (b *BitbucketCloudProvider) AddPRComment(pr *GitPullRequest, comment string
 	return nil
 }
 
+func (b *BitbucketCloudProvider) ListPRComments(pr *GitPullRequest) ([]*GitPRComment, error) {
+	return nil, fmt.Errorf("Bitbucket Cloud doesn't support listing PR comments via the REST API")
+}
+
+func (b *BitbucketCloudProvider) EditPRComment(pr *GitPullRequest, comment *GitPRComment, body string) error {
+	return fmt.Errorf("Bitbucket Cloud doesn't support editing PR comments via the REST API")
+}
+
 func (b *BitbucketCloudProvider) CreateIssueComment(owner string, repo string, number int, comment string) error {
 	log.Warn("Bitbucket Cloud doesn't support adding issue comments viea the REST API")
 	return nil
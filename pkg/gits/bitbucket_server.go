@@ -1,9 +1,13 @@
 package gits
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -22,6 +26,8 @@ type BitbucketServerProvider struct {
 	Client   *bitbucket.APIClient
 	Username string
 	Context  context.Context
+	// RestURL the base URL of the REST API of the server
+	RestURL string
 
 	Server auth.AuthServer
 	User   auth.UserAuth
@@ -63,6 +69,50 @@ type reposPage struct {
 	Values        []bitbucket.Repository `json:"values"`
 }
 
+type webHook struct {
+	ID            int64             `json:"id,omitempty"`
+	Name          string            `json:"name"`
+	URL           string            `json:"url"`
+	Active        bool              `json:"active"`
+	Events        []string          `json:"events"`
+	Configuration map[string]string `json:"configuration,omitempty"`
+}
+
+type webHooksPage struct {
+	Size          int       `json:"size"`
+	Limit         int       `json:"limit"`
+	Start         int       `json:"start"`
+	NextPageStart int       `json:"nextPageStart"`
+	IsLastPage    bool      `json:"isLastPage"`
+	Values        []webHook `json:"values"`
+}
+
+type buildStatus struct {
+	State       string `json:"state"`
+	Key         string `json:"key"`
+	Name        string `json:"name,omitempty"`
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// bitbucketServerWebHookName the name of the webhooks jx registers on repositories
+const bitbucketServerWebHookName = "Jenkins X Web Hook"
+
+// bitbucketServerWebHookEvents the events of a repository which trigger the webhooks jx registers
+var bitbucketServerWebHookEvents = []string{"repo:refs_changed", "repo:modified", "repo:forked", "repo:comment:added", "repo:comment:edited", "repo:comment:deleted", "pr:opened", "pr:reviewer:approved", "pr:reviewer:unapproved", "pr:reviewer:needs_work", "pr:merged", "pr:declined", "pr:deleted", "pr:comment:added", "pr:comment:edited", "pr:comment:deleted"}
+
+// bitbucketServerBuildStates maps the states of commit statuses to the states of Bitbucket Server build statuses
+var bitbucketServerBuildStates = map[string]string{
+	"pending":     "INPROGRESS",
+	"in-progress": "INPROGRESS",
+	"inprogress":  "INPROGRESS",
+	"success":     "SUCCESSFUL",
+	"successful":  "SUCCESSFUL",
+	"error":       "FAILED",
+	"failure":     "FAILED",
+	"failed":      "FAILED",
+}
+
 type pullrequestEndpointBranch struct {
 	Name string `json:"name,omitempty"`
 }
@@ -79,7 +129,8 @@ func NewBitbucketServerProvider(server *auth.AuthServer, user *auth.UserAuth, gi
 		Git:      git,
 	}
 
-	cfg := bitbucket.NewConfiguration(server.URL + "/rest")
+	provider.RestURL = server.URL + "/rest"
+	cfg := bitbucket.NewConfiguration(provider.RestURL)
 	provider.Client = bitbucket.NewAPIClient(apiKeyAuthContext, cfg)
 
 	return &provider, nil
@@ -663,29 +714,197 @@ func (b *BitbucketServerProvider) MergePullRequest(pr *GitPullRequest, message s
 }
 
 func (b *BitbucketServerProvider) CreateWebHook(data *GitWebHookArguments) error {
-	projectKey, repo := parseBitBucketServerURL(data.Repo.URL)
+	projectKey, repo := webHookRepository(data)
+	hooks, err := b.ListWebHooks(projectKey, repo)
+	if err != nil {
+		log.Warnf("Failed to query the webhooks of %s/%s: %s\n", projectKey, repo, err)
+	}
+	for _, hook := range hooks {
+		if hook.URL == data.URL {
+			log.Infof("Updating the webhook registered for %s\n", data.URL)
+			hook.Repo = data.Repo
+			hook.Secret = data.Secret
+			return b.UpdateWebHook(hook)
+		}
+	}
 
-	var options = map[string]interface{}{
-		"url":    data.URL,
-		"name":   "Jenkins X Web Hook",
-		"active": true,
-		"events": []string{"repo:refs_changed", "repo:modified", "repo:forked", "repo:comment:added", "repo:comment:edited", "repo:comment:deleted", "pr:opened", "pr:reviewer:approved", "pr:reviewer:unapproved", "pr:reviewer:needs_work", "pr:merged", "pr:declined", "pr:deleted", "pr:comment:added", "pr:comment:edited", "pr:comment:deleted"},
+	requestBody, err := json.Marshal(newBitbucketServerWebHook(data))
+	if err != nil {
+		return err
+	}
+
+	_, err = b.Client.DefaultApi.CreateWebhook(projectKey, repo, requestBody, []string{"application/json"})
+
+	return err
+}
+
+func (b *BitbucketServerProvider) ListWebHooks(owner string, repo string) ([]*GitWebHookArguments, error) {
+	answer := []*GitWebHookArguments{}
+	start := 0
+	for {
+		var page webHooksPage
+		path := fmt.Sprintf("api/1.0/projects/%s/repos/%s/webhooks?start=%d", owner, repo, start)
+		err := b.request(http.MethodGet, path, nil, &page)
+		if err != nil {
+			return answer, err
+		}
+		for _, hook := range page.Values {
+			answer = append(answer, &GitWebHookArguments{
+				ID:     hook.ID,
+				Owner:  owner,
+				Repo:   &GitRepositoryInfo{Organisation: owner, Name: repo},
+				URL:    hook.URL,
+				Secret: hook.Configuration["secret"],
+			})
+		}
+		if page.IsLastPage || len(page.Values) == 0 {
+			break
+		}
+		start = page.NextPageStart
+	}
+	return answer, nil
+}
+
+func (b *BitbucketServerProvider) UpdateWebHook(data *GitWebHookArguments) error {
+	if data.ID == 0 {
+		return fmt.Errorf("missing the ID of the webhook for %s", data.URL)
+	}
+	projectKey, repo := webHookRepository(data)
+	path := fmt.Sprintf("api/1.0/projects/%s/repos/%s/webhooks/%d", projectKey, repo, data.ID)
+	return b.request(http.MethodPut, path, newBitbucketServerWebHook(data), nil)
+}
+
+func (b *BitbucketServerProvider) DeleteWebHook(owner string, repo string, webhookURL string) error {
+	hooks, err := b.ListWebHooks(owner, repo)
+	if err != nil {
+		return err
 	}
+	for _, hook := range hooks {
+		if hook.URL == webhookURL {
+			path := fmt.Sprintf("api/1.0/projects/%s/repos/%s/webhooks/%d", owner, repo, hook.ID)
+			err = b.request(http.MethodDelete, path, nil, nil)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
 
+// webHookRepository returns the project key and repository slug of the repository of the webhook
+func webHookRepository(data *GitWebHookArguments) (string, string) {
+	if data.Repo.URL != "" {
+		return parseBitBucketServerURL(data.Repo.URL)
+	}
+	owner := data.Owner
+	if owner == "" {
+		owner = data.Repo.Organisation
+	}
+	return owner, data.Repo.Name
+}
+
+func newBitbucketServerWebHook(data *GitWebHookArguments) *webHook {
+	hook := &webHook{
+		Name:   bitbucketServerWebHookName,
+		URL:    data.URL,
+		Active: true,
+		Events: bitbucketServerWebHookEvents,
+	}
 	if data.Secret != "" {
-		options["configuration"] = map[string]interface{}{
+		hook.Configuration = map[string]string{
 			"secret": data.Secret,
 		}
 	}
+	return hook
+}
 
-	requestBody, err := json.Marshal(options)
+// UpdateCommitStatus sets the build status of the commit. The state of the status is mapped to one of the Bitbucket
+// Server states INPROGRESS, SUCCESSFUL or FAILED
+func (b *BitbucketServerProvider) UpdateCommitStatus(org string, repo string, sha string, status *GitRepoStatus) (*GitRepoStatus, error) {
+	state, err := BitbucketServerBuildState(status.State)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	key := status.Context
+	if key == "" {
+		key = status.ID
+	}
+	if key == "" {
+		key = "jenkins-x"
+	}
+	targetURL := status.TargetURL
+	if targetURL == "" {
+		targetURL = status.URL
+	}
+	if targetURL == "" {
+		return nil, fmt.Errorf("missing the URL of the build status %s of commit %s", key, sha)
 	}
+	body := &buildStatus{
+		State:       state,
+		Key:         key,
+		Name:        status.Context,
+		URL:         targetURL,
+		Description: status.Description,
+	}
+	err = b.request(http.MethodPost, "build-status/1.0/commits/"+sha, body, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &GitRepoStatus{
+		ID:          key,
+		Context:     status.Context,
+		URL:         targetURL,
+		State:       state,
+		TargetURL:   targetURL,
+		Description: status.Description,
+	}, nil
+}
 
-	_, err = b.Client.DefaultApi.CreateWebhook(projectKey, repo, requestBody, []string{"application/json"})
+// BitbucketServerBuildState returns the Bitbucket Server build status state of the state of a commit status such as
+// pending, success, error or failure
+func BitbucketServerBuildState(state string) (string, error) {
+	answer, ok := bitbucketServerBuildStates[strings.ToLower(state)]
+	if !ok {
+		return "", fmt.Errorf("unsupported commit status state %s for Bitbucket Server", state)
+	}
+	return answer, nil
+}
 
-	return err
+// request sends the JSON of the body to the path of the REST API of the server and decodes the JSON of the response
+// into the result unless it is nil
+func (b *BitbucketServerProvider) request(method string, path string, body interface{}, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	u := util.UrlJoin(b.RestURL, path)
+	req, err := http.NewRequest(method, u, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+b.User.ApiToken)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s failed with status %s: %s", method, u, resp.Status, string(data))
+	}
+	if result == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, result)
 }
 
 func (b *BitbucketServerProvider) SearchIssues(org string, name string, query string) ([]*GitIssue, error) {
@@ -735,6 +954,14 @@ func (b *BitbucketServerProvider) AddPRComment(pr *GitPullRequest, comment strin
 	return nil
 }
 
+func (b *BitbucketServerProvider) ListPRComments(pr *GitPullRequest) ([]*GitPRComment, error) {
+	return nil, fmt.Errorf("Bitbucket Server doesn't support listing PR comments via the REST API")
+}
+
+func (b *BitbucketServerProvider) EditPRComment(pr *GitPullRequest, comment *GitPRComment, body string) error {
+	return fmt.Errorf("Bitbucket Server doesn't support editing PR comments via the REST API")
+}
+
 func (b *BitbucketServerProvider) CreateIssueComment(owner string, repo string, number int, comment string) error {
 	log.Warn("Bitbucket Server doesn't support adding issue comments via the REST API")
 	return nil
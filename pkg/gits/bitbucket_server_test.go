@@ -10,6 +10,7 @@ import (
 	"github.com/jenkins-x/jx/pkg/auth"
 	"github.com/jenkins-x/jx/pkg/gits"
 	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -56,13 +57,19 @@ var bitbucketServerRouter = util.Router{
 		"POST": "pr-merge-success.json",
 	},
 	"/rest/api/1.0/projects/TEST-ORG/repos/test-repo/webhooks": util.MethodMap{
+		"GET":  "webhooks.json",
 		"POST": "webhook.json",
 	},
+	"/rest/api/1.0/projects/TEST-ORG/repos/test-repo/webhooks/14": util.MethodMap{
+		"PUT":    "webhook.json",
+		"DELETE": "repos.test-repo.nil.json",
+	},
 	"/rest/api/1.0/users/test-user": util.MethodMap{
 		"GET": "user.json",
 	},
 	"/rest/build-status/1.0/commits/d6f24ee03d76a2caf0a4e1975fb43e8f61759b9c": util.MethodMap{
-		"GET": "build-statuses.json",
+		"GET":  "build-statuses.json",
+		"POST": "repos.test-repo.nil.json",
 	},
 }
 
@@ -103,6 +110,7 @@ func (suite *BitbucketServerProviderTestSuite) SetupSuite() {
 
 	apiKeyAuthContext := context.WithValue(ctx, bitbucket.ContextAccessToken, ua.ApiToken)
 	suite.provider.Client = bitbucket.NewAPIClient(apiKeyAuthContext, cfg)
+	suite.provider.RestURL = suite.server.URL + "/rest"
 }
 
 func (suite *BitbucketServerProviderTestSuite) TestGetRepository() {
@@ -291,6 +299,53 @@ func (suite *BitbucketServerProviderTestSuite) TestCreateWebHook() {
 	suite.Require().Nil(err)
 }
 
+func (suite *BitbucketServerProviderTestSuite) TestListWebHooks() {
+	hooks, err := suite.provider.ListWebHooks("TEST-ORG", "test-repo")
+
+	suite.Require().Nil(err)
+	suite.Require().Len(hooks, 1)
+	suite.Require().Equal(int64(14), hooks[0].ID)
+	suite.Require().Equal("https://my-jenkins.example.com/github-webhook/", hooks[0].URL)
+	suite.Require().Equal("oldSecret", hooks[0].Secret)
+}
+
+func (suite *BitbucketServerProviderTestSuite) TestUpdateWebHook() {
+	data := &gits.GitWebHookArguments{
+		ID:     14,
+		Owner:  "TEST-ORG",
+		Repo:   &gits.GitRepositoryInfo{Organisation: "TEST-ORG", Name: "test-repo"},
+		URL:    "https://my-jenkins.example.com/github-webhook/",
+		Secret: "someSecret",
+	}
+	err := suite.provider.UpdateWebHook(data)
+
+	suite.Require().Nil(err)
+}
+
+func (suite *BitbucketServerProviderTestSuite) TestDeleteWebHook() {
+	err := suite.provider.DeleteWebHook("TEST-ORG", "test-repo", "https://my-jenkins.example.com/github-webhook/")
+
+	suite.Require().Nil(err)
+}
+
+func (suite *BitbucketServerProviderTestSuite) TestUpdateCommitStatus() {
+	status, err := suite.provider.UpdateCommitStatus("TEST-ORG", "test-repo", "d6f24ee03d76a2caf0a4e1975fb43e8f61759b9c", &gits.GitRepoStatus{
+		Context:     "continuous-integration/jenkins-x",
+		State:       "pending",
+		TargetURL:   "https://jenkins.example.com/job/test-repo/1",
+		Description: "Build started",
+	})
+
+	suite.Require().Nil(err)
+	suite.Require().Equal("INPROGRESS", status.State)
+	suite.Require().Equal("continuous-integration/jenkins-x", status.ID)
+
+	_, err = suite.provider.UpdateCommitStatus("TEST-ORG", "test-repo", "d6f24ee03d76a2caf0a4e1975fb43e8f61759b9c", &gits.GitRepoStatus{
+		State: "success",
+	})
+	suite.Require().NotNil(err, "a build status needs a URL")
+}
+
 func (suite *BitbucketServerProviderTestSuite) TestUserInfo() {
 
 	userInfo := suite.provider.UserInfo("test-user")
@@ -321,6 +376,23 @@ func (suite *BitbucketServerProviderTestSuite) TestAcceptInvitations() {
 	suite.Require().Nil(err)
 }
 
+func TestBitbucketServerBuildState(t *testing.T) {
+	t.Parallel()
+	for state, expected := range map[string]string{
+		"pending":    "INPROGRESS",
+		"success":    "SUCCESSFUL",
+		"error":      "FAILED",
+		"failure":    "FAILED",
+		"SUCCESSFUL": "SUCCESSFUL",
+	} {
+		actual, err := gits.BitbucketServerBuildState(state)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "state %s", state)
+	}
+	_, err := gits.BitbucketServerBuildState("unknown")
+	assert.Error(t, err)
+}
+
 func TestBitbucketServerProviderTestSuite(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping TestBitbucketServerProviderTestSuite in short mode")
@@ -0,0 +1,170 @@
+package gits
+
+import (
+	"bytes"
+	"sort"
+	"text/template"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	otherChangesTitle  = "Other Changes"
+	otherChangesLegend = "These commits did not use [Conventional Commits](https://conventionalcommits.org/) formatted messages:"
+)
+
+// Changelog the commits, issues and pull requests of a release grouped into the sections of its changelog. It is the
+// data of the go templates which customize the changelog
+type Changelog struct {
+	Release *v1.ReleaseSpec
+	// Conventional is true if any commit follows the Conventional Commits notation, otherwise the commits are a
+	// single section without a title
+	Conventional bool
+	// Sections the sections of the commits: the breaking changes first, then each type of commit and the other changes
+	Sections     []*ChangelogSection
+	Issues       []*ChangelogEntry
+	PullRequests []*ChangelogEntry
+}
+
+// ChangelogSection the commits of a section of the changelog such as the features, fixes or breaking changes
+type ChangelogSection struct {
+	Title   string
+	Legend  string
+	Commits []*ChangelogEntry
+}
+
+// ChangelogEntry a commit, issue or pull request of the changelog
+type ChangelogEntry struct {
+	// Description the markdown description of the entry along with its author and linked issues
+	Description string
+	Commit      *v1.CommitSummary
+	Info        *CommitInfo
+	Issue       *v1.IssueSummary
+}
+
+// NewChangelog groups the commits of the release by their Conventional Commits type
+// see: https://conventionalcommits.org/
+func NewChangelog(releaseSpec *v1.ReleaseSpec, gitInfo *GitRepositoryInfo) *Changelog {
+	answer := &Changelog{
+		Release: releaseSpec,
+	}
+	issueMap := map[string]*v1.IssueSummary{}
+	for i := range releaseSpec.Issues {
+		issue := &releaseSpec.Issues[i]
+		issueMap[issue.ID] = issue
+	}
+
+	sectionMap := map[*CommitGroup]*ChangelogSection{}
+	orders := map[*ChangelogSection]int{}
+	for i := range releaseSpec.Commits {
+		cs := &releaseSpec.Commits[i]
+		if cs.Message == "" {
+			continue
+		}
+		ci := ParseCommit(cs.Message)
+		group := ci.Group()
+		section := sectionMap[group]
+		if section == nil {
+			section = &ChangelogSection{
+				Title: group.Title,
+			}
+			sectionMap[group] = section
+			orders[section] = group.Order
+			answer.Sections = append(answer.Sections, section)
+		}
+		if group.Title != "" {
+			answer.Conventional = true
+		}
+		section.Commits = appendChangelogEntry(section.Commits, &ChangelogEntry{
+			Description: describeCommit(gitInfo, cs, ci, issueMap),
+			Commit:      cs,
+			Info:        ci,
+		})
+	}
+	sort.SliceStable(answer.Sections, func(i, j int) bool {
+		return orders[answer.Sections[i]] < orders[answer.Sections[j]]
+	})
+	for _, section := range answer.Sections {
+		if section.Title == "" && answer.Conventional {
+			section.Title = otherChangesTitle
+			section.Legend = otherChangesLegend
+		}
+	}
+
+	for i := range releaseSpec.Issues {
+		issue := &releaseSpec.Issues[i]
+		answer.Issues = appendChangelogEntry(answer.Issues, &ChangelogEntry{
+			Description: describeIssue(gitInfo, issue),
+			Issue:       issue,
+		})
+	}
+	for i := range releaseSpec.PullRequests {
+		pr := &releaseSpec.PullRequests[i]
+		answer.PullRequests = appendChangelogEntry(answer.PullRequests, &ChangelogEntry{
+			Description: describeIssue(gitInfo, pr),
+			Issue:       pr,
+		})
+	}
+	return answer
+}
+
+// appendChangelogEntry appends the entry unless it has the same description as the previous entry
+func appendChangelogEntry(entries []*ChangelogEntry, entry *ChangelogEntry) []*ChangelogEntry {
+	if len(entries) > 0 && entries[len(entries)-1].Description == entry.Description {
+		return entries
+	}
+	return append(entries, entry)
+}
+
+// Markdown returns the default markdown of the changelog
+func (c *Changelog) Markdown() string {
+	if len(c.Sections) == 0 && len(c.Issues) == 0 && len(c.PullRequests) == 0 {
+		return ""
+	}
+	var buffer bytes.Buffer
+	buffer.WriteString("## Changes\n")
+
+	for _, section := range c.Sections {
+		buffer.WriteString("\n")
+		if section.Title != "" {
+			buffer.WriteString("### " + section.Title + "\n\n")
+		}
+		if section.Legend != "" {
+			buffer.WriteString(section.Legend + "\n\n")
+		}
+		for _, entry := range section.Commits {
+			buffer.WriteString("* " + entry.Description + "\n")
+			if entry.Info != nil && entry.Info.BreakingChange != "" {
+				buffer.WriteString("  * " + entry.Info.BreakingChange + "\n")
+			}
+		}
+	}
+	writeChangelogEntries(&buffer, "Issues", c.Issues)
+	writeChangelogEntries(&buffer, "Pull Requests", c.PullRequests)
+	return buffer.String()
+}
+
+func writeChangelogEntries(buffer *bytes.Buffer, title string, entries []*ChangelogEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	buffer.WriteString("\n### " + title + "\n\n")
+	for _, entry := range entries {
+		buffer.WriteString("* " + entry.Description + "\n")
+	}
+}
+
+// RenderTemplate renders the changelog with the go template: https://golang.org/pkg/text/template/
+func (c *Changelog) RenderTemplate(templateText string) (string, error) {
+	tmpl, err := template.New("changelog").Parse(templateText)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse the changelog template")
+	}
+	var buffer bytes.Buffer
+	err = tmpl.Execute(&buffer, c)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to render the changelog template")
+	}
+	return buffer.String(), nil
+}
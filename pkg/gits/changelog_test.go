@@ -0,0 +1,112 @@
+package gits_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangelogSections(t *testing.T) {
+	t.Parallel()
+	author := &v1.UserDetails{Login: "jstrachan"}
+	releaseSpec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{Message: "fix(ui): a fix\nfixes #123", Author: author, IssueIDs: []string{"123"}},
+			{Message: "feat(api)!: a new API", Author: author},
+			{Message: "feat: a feature", Author: author},
+			{Message: "ci: a pipeline", Author: author},
+			{Message: "chore: move the config\n\nBREAKING CHANGE: the config moved", Author: author},
+			{Message: "wip: not a type of the convention", Author: author},
+			{Message: "Merge branch 'master'", Author: author},
+		},
+		Issues: []v1.IssueSummary{
+			{ID: "123", URL: "https://github.com/jstrachan/foo/issues/123", Title: "a bug"},
+		},
+	}
+	gitInfo := &gits.GitRepositoryInfo{
+		Host:         "github.com",
+		Organisation: "jstrachan",
+		Name:         "foo",
+	}
+	changelog := gits.NewChangelog(releaseSpec, gitInfo)
+	assert.True(t, changelog.Conventional)
+
+	titles := []string{}
+	for _, section := range changelog.Sections {
+		titles = append(titles, section.Title)
+	}
+	assert.Equal(t, []string{"Breaking Changes", "New Features", "Bug Fixes", "Continuous Integration", "Other Changes"}, titles)
+
+	expectedMarkdown := `## Changes
+
+### Breaking Changes
+
+* api: a new API ([jstrachan](https://github.com/jstrachan))
+* move the config ([jstrachan](https://github.com/jstrachan))
+  * the config moved
+
+### New Features
+
+* a feature ([jstrachan](https://github.com/jstrachan))
+
+### Bug Fixes
+
+* ui: a fix ([jstrachan](https://github.com/jstrachan)) [#123](https://github.com/jstrachan/foo/issues/123) 
+
+### Continuous Integration
+
+* a pipeline ([jstrachan](https://github.com/jstrachan))
+
+### Other Changes
+
+These commits did not use [Conventional Commits](https://conventionalcommits.org/) formatted messages:
+
+* not a type of the convention ([jstrachan](https://github.com/jstrachan))
+* Merge branch 'master' ([jstrachan](https://github.com/jstrachan))
+
+### Issues
+
+* [#123](https://github.com/jstrachan/foo/issues/123) a bug
+`
+	assert.Equal(t, expectedMarkdown, changelog.Markdown())
+}
+
+func TestChangelogFlatListWithoutConventionalCommits(t *testing.T) {
+	t.Parallel()
+	releaseSpec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{Message: "some commit 1"},
+			{Message: "Merge branch 'master': some commit 2"},
+		},
+	}
+	changelog := gits.NewChangelog(releaseSpec, &gits.GitRepositoryInfo{Host: "github.com"})
+	assert.False(t, changelog.Conventional)
+	require.Len(t, changelog.Sections, 1)
+	assert.Equal(t, "", changelog.Sections[0].Title)
+	assert.Equal(t, "## Changes\n\n* some commit 1\n* Merge branch 'master': some commit 2\n", changelog.Markdown())
+}
+
+func TestChangelogRenderTemplate(t *testing.T) {
+	t.Parallel()
+	releaseSpec := &v1.ReleaseSpec{
+		Version: "1.2.0",
+		Commits: []v1.CommitSummary{
+			{Message: "feat(ui): a feature"},
+			{Message: "fix: a fix"},
+		},
+	}
+	changelog := gits.NewChangelog(releaseSpec, &gits.GitRepositoryInfo{Host: "github.com"})
+	markdown, err := changelog.RenderTemplate(`# {{ .Release.Version }}
+{{ range .Sections }}
+## {{ .Title }}
+{{ range .Commits }}- {{ .Info.Message }}{{ with .Info.Feature }} [{{ . }}]{{ end }}
+{{ end }}{{ end }}`)
+	require.NoError(t, err)
+	assert.Equal(t, "# 1.2.0\n\n## New Features\n- a feature [ui]\n\n## Bug Fixes\n- a fix\n", markdown)
+
+	_, err = changelog.RenderTemplate("{{ .Unknown }}")
+	assert.Error(t, err)
+}
@@ -1,9 +1,9 @@
 package gits
 
 import (
-	"bytes"
 	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx/pkg/util"
+	"regexp"
 	"strconv"
 	"strings"
 )
@@ -12,7 +12,11 @@ type CommitInfo struct {
 	Kind    string
 	Feature string
 	Message string
-	group   *CommitGroup
+	// Breaking is true if the commit is marked with a '!' after its type or has a BREAKING CHANGE footer
+	Breaking bool
+	// BreakingChange the description of the BREAKING CHANGE footer of the commit if it has one
+	BreakingChange string
+	group          *CommitGroup
 }
 
 type CommitGroup struct {
@@ -35,10 +39,17 @@ var (
 		"revert":   createCommitGroup("Reverts"),
 		"style":    createCommitGroup("Styles"),
 		"chore":    createCommitGroup("Chores"),
+		"build":    createCommitGroup("Builds"),
+		"ci":       createCommitGroup("Continuous Integration"),
 		"":         createCommitGroup(""),
 	}
 
+	// BreakingChangesGroup the group of the commits with breaking changes which comes before all the other groups
+	BreakingChangesGroup = &CommitGroup{Title: "Breaking Changes"}
+
 	unknownKindOrder = groupCounter + 1
+
+	conventionalCommitRegex = regexp.MustCompile(`^([a-zA-Z]+)(\([^)]*\))?(!)?:`)
 )
 
 func createCommitGroup(title string) *CommitGroup {
@@ -59,20 +70,23 @@ func ConventionalCommitTypeToTitle(kind string) *CommitGroup {
 	return answer
 }
 
-// ParseCommit parses a conventional commit
+// ParseCommit parses a conventional commit such as 'feat(scope): subject' along with the older 'feat:(scope) subject'
+// notation. The kind of a commit which does not follow the convention is empty
 // see: https://conventionalcommits.org/
 func ParseCommit(message string) *CommitInfo {
 	answer := &CommitInfo{
 		Message: message,
 	}
 
-	idx := strings.Index(message, ":")
-	if idx > 0 {
-		answer.Kind = message[0:idx]
-
-		rest := strings.TrimSpace(message[idx+1:])
-		if strings.HasPrefix(rest, "(") {
-			idx = strings.Index(rest, ")")
+	rest := message
+	matches := conventionalCommitRegex.FindStringSubmatch(message)
+	if matches != nil {
+		answer.Kind = matches[1]
+		answer.Feature = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(matches[2], "("), ")"))
+		answer.Breaking = matches[3] == "!"
+		rest = strings.TrimSpace(message[len(matches[0]):])
+		if matches[2] == "" && strings.HasPrefix(rest, "(") {
+			idx := strings.Index(rest, ")")
 			if idx > 0 {
 				answer.Feature = strings.TrimSpace(rest[1:idx])
 				rest = strings.TrimSpace(rest[idx+1:])
@@ -80,12 +94,64 @@ func ParseCommit(message string) *CommitInfo {
 		}
 		answer.Message = rest
 	}
+	breaking, description := parseBreakingChange(rest)
+	if breaking {
+		answer.Breaking = true
+		answer.BreakingChange = description
+	}
+	return answer
+}
+
+// parseBreakingChange returns true and the description of the BREAKING CHANGE footer if the body of the commit
+// message after its subject line has one
+func parseBreakingChange(message string) (bool, string) {
+	lines := strings.Split(message, "\n")
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		for _, prefix := range []string{"BREAKING CHANGE:", "BREAKING-CHANGE:"} {
+			if strings.HasPrefix(line, prefix) {
+				description := []string{strings.TrimSpace(strings.TrimPrefix(line, prefix))}
+				for _, next := range lines[i+1:] {
+					next = strings.TrimSpace(next)
+					if next == "" {
+						break
+					}
+					description = append(description, next)
+				}
+				return true, strings.TrimSpace(strings.Join(description, " "))
+			}
+		}
+	}
+	return false, ""
+}
+
+// ConventionalCommitsBump returns the part of the semantic version bumped by the commit messages following the
+// conventional commits specification: major for a breaking change, minor for a feature and patch otherwise
+func ConventionalCommitsBump(messages []string) string {
+	answer := util.SemVerPatch
+	for _, message := range messages {
+		info := ParseCommit(strings.TrimSpace(message))
+		if info.Breaking {
+			return util.SemVerMajor
+		}
+		if strings.ToLower(info.Kind) == "feat" {
+			answer = util.SemVerMinor
+		}
+	}
 	return answer
 }
 
 func (c *CommitInfo) Group() *CommitGroup {
 	if c.group == nil {
-		c.group = ConventionalCommitTitles[strings.ToLower(c.Kind)]
+		if c.Breaking {
+			c.group = BreakingChangesGroup
+		} else {
+			c.group = ConventionalCommitTitles[strings.ToLower(c.Kind)]
+		}
+		if c.group == nil {
+			// types which are not part of the convention are other changes
+			c.group = ConventionalCommitTitles[""]
+		}
 	}
 	return c.group
 }
@@ -98,107 +164,9 @@ func (c *CommitInfo) Order() int {
 	return c.Group().Order
 }
 
-type GroupAndCommitInfos struct {
-	group   *CommitGroup
-	commits []string
-}
-
 // GenerateMarkdown generates the markdown document for the commits
 func GenerateMarkdown(releaseSpec *v1.ReleaseSpec, gitInfo *GitRepositoryInfo) (string, error) {
-	commitInfos := []*CommitInfo{}
-
-	groupAndCommits := map[int]*GroupAndCommitInfos{}
-
-	issues := releaseSpec.Issues
-	issueMap := map[string]*v1.IssueSummary{}
-	for _, issue := range issues {
-		copy := issue
-		issueMap[copy.ID] = &copy
-	}
-
-	for _, cs := range releaseSpec.Commits {
-		message := cs.Message
-		if message != "" {
-			ci := ParseCommit(message)
-
-			description := "* " + describeCommit(gitInfo, &cs, ci, issueMap) + "\n"
-			group := ci.Group()
-			if group != nil {
-				gac := groupAndCommits[group.Order]
-				if gac == nil {
-					gac = &GroupAndCommitInfos{
-						group:   group,
-						commits: []string{},
-					}
-					groupAndCommits[group.Order] = gac
-				}
-				gac.commits = append(gac.commits, description)
-			}
-			commitInfos = append(commitInfos, ci)
-		}
-	}
-
-	prs := releaseSpec.PullRequests
-
-	var buffer bytes.Buffer
-	if len(commitInfos) == 0 && len(issues) == 0 && len(prs) == 0 {
-		return "", nil
-	}
-
-	buffer.WriteString("## Changes\n")
-
-	hasTitle := false
-	for i := 0; i <= unknownKindOrder; i++ {
-		gac := groupAndCommits[i]
-		if gac != nil && len(gac.commits) > 0 {
-			group := gac.group
-			if group != nil {
-				legend := ""
-				buffer.WriteString("\n")
-				if group.Title == "" && hasTitle {
-					group.Title = "Other Changes"
-					legend = "These commits did not use [Conventional Commits](https://conventionalcommits.org/) formatted messages:\n\n"
-				}
-				if group.Title != "" {
-					hasTitle = true
-					buffer.WriteString("### " + group.Title + "\n\n" + legend)
-				}
-			}
-			previous := ""
-			for _, msg := range gac.commits {
-				if msg != previous {
-					buffer.WriteString(msg)
-					previous = msg
-				}
-			}
-		}
-	}
-
-	if len(issues) > 0 {
-		buffer.WriteString("\n### Issues\n\n")
-
-		previous := ""
-		for _, issue := range issues {
-			msg := describeIssue(gitInfo, &issue)
-			if msg != previous {
-				buffer.WriteString("* " + msg + "\n")
-				previous = msg
-			}
-		}
-	}
-	if len(prs) > 0 {
-		buffer.WriteString("\n### Pull Requests\n\n")
-
-		previous := ""
-		for _, pr := range prs {
-			msg := describeIssue(gitInfo, &pr)
-			if msg != previous {
-				buffer.WriteString("* " + msg + "\n")
-				previous = msg
-			}
-		}
-	}
-	return buffer.String(), nil
+	return NewChangelog(releaseSpec, gitInfo).Markdown(), nil
 }
 
 func describeIssue(info *GitRepositoryInfo, issue *v1.IssueSummary) string {
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/util"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -22,10 +23,25 @@ func TestParseCommits(t *testing.T) {
 		Message: "wine is good too",
 	})
 	assertParseCommit(t, "feat(beer): wine is good too", &gits.CommitInfo{
-		Kind:    "feat(beer)",
-		Feature: "",
+		Kind:    "feat",
+		Feature: "beer",
 		Message: "wine is good too",
 	})
+	assertParseCommit(t, "refactor(api)!: drop the old API", &gits.CommitInfo{
+		Kind:     "refactor",
+		Feature:  "api",
+		Message:  "drop the old API",
+		Breaking: true,
+	})
+	assertParseCommit(t, "feat: move the config\n\nBREAKING CHANGE: the config\nmoved to .jx\n\nfixes #1", &gits.CommitInfo{
+		Kind:           "feat",
+		Message:        "move the config\n\nBREAKING CHANGE: the config\nmoved to .jx\n\nfixes #1",
+		Breaking:       true,
+		BreakingChange: "the config moved to .jx",
+	})
+	assertParseCommit(t, "Merge branch 'master': conflicts", &gits.CommitInfo{
+		Message: "Merge branch 'master': conflicts",
+	})
 }
 
 func assertParseCommit(t *testing.T, input string, expected *gits.CommitInfo) {
@@ -36,3 +52,12 @@ func assertParseCommit(t *testing.T, input string, expected *gits.CommitInfo) {
 	assert.Equal(t, expected.Message, info.Message, "Message for Commit %s", info)
 	assert.Equal(t, expected, info, "CommitInfo for Commit %s", info)
 }
+
+func TestConventionalCommitsBump(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, util.SemVerPatch, gits.ConventionalCommitsBump(nil))
+	assert.Equal(t, util.SemVerPatch, gits.ConventionalCommitsBump([]string{"fix: a bug", "chore(deps): upgrade", "no prefix"}))
+	assert.Equal(t, util.SemVerMinor, gits.ConventionalCommitsBump([]string{"fix: a bug", "feat(ui): a feature"}))
+	assert.Equal(t, util.SemVerMajor, gits.ConventionalCommitsBump([]string{"feat: a feature", "refactor!: drop the old API"}))
+	assert.Equal(t, util.SemVerMajor, gits.ConventionalCommitsBump([]string{"feat: a feature\n\nBREAKING CHANGE: the config moved"}))
+}
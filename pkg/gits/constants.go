@@ -1,6 +1,7 @@
 package gits
 
 const (
+	KindAzureDevOps     = "azuredevops"
 	KindBitBucketCloud  = "bitbucketcloud"
 	KindBitBucketServer = "bitbucketserver"
 	KindGitea           = "gitea"
@@ -9,8 +10,16 @@ const (
 	KindUnknown         = "unknown"
 
 	BitbucketCloudURL = "https://bitbucket.org"
+
+	// GitProtocolHTTPS the protocol of https:// git remotes which authenticate with the API token of the user
+	GitProtocolHTTPS = "https"
+	// GitProtocolSSH the protocol of ssh:// git remotes which authenticate with an SSH key
+	GitProtocolSSH = "ssh"
 )
 
 var (
-	KindGits = []string{KindBitBucketCloud, KindBitBucketServer, KindGitea, KindGitHub, KindGitlab}
+	KindGits = []string{KindAzureDevOps, KindBitBucketCloud, KindBitBucketServer, KindGitea, KindGitHub, KindGitlab}
+
+	// GitProtocols the protocols of the git remotes jx can create
+	GitProtocols = []string{GitProtocolHTTPS, GitProtocolSSH}
 )
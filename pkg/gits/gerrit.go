@@ -76,6 +76,10 @@ func (p *GerritProvider) ListCommitStatus(org string, repo string, sha string) (
 	return nil, nil
 }
 
+func (p *GerritProvider) UpdateCommitStatus(org string, repo string, sha string, status *GitRepoStatus) (*GitRepoStatus, error) {
+	return nil, nil
+}
+
 func (p *GerritProvider) MergePullRequest(pr *GitPullRequest, message string) error {
 	return nil
 }
@@ -84,6 +88,18 @@ func (p *GerritProvider) CreateWebHook(data *GitWebHookArguments) error {
 	return nil
 }
 
+func (p *GerritProvider) ListWebHooks(owner string, repo string) ([]*GitWebHookArguments, error) {
+	return nil, nil
+}
+
+func (p *GerritProvider) UpdateWebHook(data *GitWebHookArguments) error {
+	return nil
+}
+
+func (p *GerritProvider) DeleteWebHook(owner string, repo string, webhookURL string) error {
+	return nil
+}
+
 func (p *GerritProvider) IsGitHub() bool {
 	return false
 }
@@ -136,6 +152,14 @@ func (p *GerritProvider) AddPRComment(pr *GitPullRequest, comment string) error
 	return nil
 }
 
+func (p *GerritProvider) ListPRComments(pr *GitPullRequest) ([]*GitPRComment, error) {
+	return nil, nil
+}
+
+func (p *GerritProvider) EditPRComment(pr *GitPullRequest, comment *GitPRComment, body string) error {
+	return nil
+}
+
 func (p *GerritProvider) CreateIssueComment(owner string, repo string, number int, comment string) error {
 	return nil
 }
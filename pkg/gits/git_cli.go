@@ -273,8 +273,8 @@ func (g *GitCLI) gitCmdWithOutput(dir string, args ...string) (string, error) {
 // CreatePushURL creates the Git repository URL with the username and password encoded for HTTPS based URLs
 func (g *GitCLI) CreatePushURL(cloneURL string, userAuth *auth.UserAuth) (string, error) {
 	u, err := url.Parse(cloneURL)
-	if err != nil {
-		// already a git/ssh url?
+	if err != nil || isSSHScheme(u.Scheme) {
+		// already a git/ssh url which authenticates with an SSH key?
 		return cloneURL, nil
 	}
 	if userAuth.Username != "" || userAuth.ApiToken != "" {
@@ -519,6 +519,70 @@ func (g *GitCLI) CreateTag(dir string, tag string, msg string) error {
 	return g.gitCmd("", "tag", "-fa", tag, "-m", msg)
 }
 
+// CreateSignedTag creates a tag signed with the signing key of the git configuration with the given name and message
+// in the repository at the given directory
+func (g *GitCLI) CreateSignedTag(dir string, tag string, msg string) error {
+	return g.gitCmd(dir, "tag", "-fs", tag, "-m", msg)
+}
+
+// ReleaseTags returns the tags of the current commit along with the tags of the release commits made on top of it
+// before tagging, whose messages start with "release ", in the repository at the given directory
+func (g *GitCLI) ReleaseTags(dir string) ([]string, error) {
+	answer := []string{}
+	head, err := g.gitCmdWithOutput(dir, "rev-parse", "HEAD")
+	if err != nil {
+		return answer, err
+	}
+	text, err := g.gitCmdWithOutput(dir, "tag", "--contains", head)
+	if err != nil {
+		return answer, err
+	}
+	for _, tag := range strings.Split(text, "\n") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		sha, err := g.gitCmdWithOutput(dir, "rev-parse", tag+"^{commit}")
+		if err != nil {
+			return answer, err
+		}
+		if sha == head {
+			answer = append(answer, tag)
+			continue
+		}
+		parent, err := g.gitCmdWithOutput(dir, "rev-parse", sha+"^")
+		if err != nil || parent != head {
+			continue
+		}
+		subject, err := g.gitCmdWithOutput(dir, "log", "-1", "--format=%s", sha)
+		if err == nil && strings.HasPrefix(subject, "release ") {
+			answer = append(answer, tag)
+		}
+	}
+	return answer, nil
+}
+
+// GetCommitMessagesSince returns the messages of the commits of the current branch since the given reference, or of
+// all its commits if the reference is empty, in the repository at the given directory
+func (g *GitCLI) GetCommitMessagesSince(dir string, ref string) ([]string, error) {
+	answer := []string{}
+	args := []string{"log", "--format=%B%x1e"}
+	if ref != "" {
+		args = append(args, ref+"..HEAD")
+	}
+	text, err := g.gitCmdWithOutput(dir, args...)
+	if err != nil {
+		return answer, err
+	}
+	for _, message := range strings.Split(text, "\x1e") {
+		message = strings.TrimSpace(message)
+		if message != "" {
+			answer = append(answer, message)
+		}
+	}
+	return answer, nil
+}
+
 // PrintCreateRepositoryGenerateAccessToken prints the access token URL of a Git repository
 func (g *GitCLI) PrintCreateRepositoryGenerateAccessToken(server *auth.AuthServer, username string, o io.Writer) {
 	tokenUrl := ProviderAccessTokenURL(server.Kind, server.URL, username)
@@ -594,6 +658,20 @@ func (g *GitCLI) CreateBranch(dir string, branch string) error {
 	return g.gitCmd(dir, "branch", branch)
 }
 
+// FileRevisions returns the SHAs of the commits which changed the file on the current branch, newest first
+func (g *GitCLI) FileRevisions(dir string, fileName string) ([]string, error) {
+	out, err := g.gitCmdWithOutput(dir, "log", "--format=%H", "--", fileName)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(out), nil
+}
+
+// ShowFileAtRevision returns the contents of the file, relative to the root of the repository, at the revision
+func (g *GitCLI) ShowFileAtRevision(dir string, revision string, fileName string) (string, error) {
+	return g.gitCmdWithOutput(dir, "show", revision+":"+filepath.ToSlash(fileName))
+}
+
 // Diff runs git diff
 func (g *GitCLI) Diff(dir string) (string, error) {
 	return g.gitCmdWithOutput(dir, "diff")
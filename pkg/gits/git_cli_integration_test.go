@@ -0,0 +1,51 @@
+// +build integration
+
+package gits_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReleaseTagsAndCommitMessages(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-git-release-tags")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	git := gits.NewGitCLI()
+	require.NoError(t, git.Init(dir))
+	require.NoError(t, git.SetUsername(dir, "test"))
+	require.NoError(t, git.SetEmail(dir, "test@example.com"))
+	gitCmd := func(args ...string) string {
+		out, err := (&util.Command{Dir: dir, Name: "git", Args: args}).RunWithoutRetry()
+		require.NoError(t, err)
+		return out
+	}
+	gitCmd("commit", "--allow-empty", "-m", "feat: first")
+	gitCmd("tag", "v1.0.0")
+	gitCmd("commit", "--allow-empty", "-m", "fix: second")
+	gitCmd("commit", "--allow-empty", "-m", "feat!: third\n\nwith a body")
+
+	tags, err := git.ReleaseTags(dir)
+	require.NoError(t, err)
+	assert.Empty(t, tags)
+
+	messages, err := git.GetCommitMessagesSince(dir, "v1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"feat!: third\n\nwith a body", "fix: second"}, messages)
+
+	// the release commit made on top of the current commit by jx step tag
+	head := gitCmd("rev-parse", "HEAD")
+	gitCmd("commit", "--allow-empty", "-m", "release 1.1.0")
+	gitCmd("tag", "v1.1.0")
+	gitCmd("checkout", "-q", head)
+
+	tags, err = git.ReleaseTags(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v1.1.0"}, tags)
+}
@@ -21,6 +21,13 @@ type GitRemote struct {
 type GitTag struct {
 	Name    string
 	Message string
+	Signed  bool
+}
+
+// GitFileRevision the contents of a file at a revision
+type GitFileRevision struct {
+	SHA     string
+	Content string
 }
 
 type GitFake struct {
@@ -36,7 +43,9 @@ type GitFake struct {
 	Commits        []GitCommit
 	Changes        bool
 	GitTags        []GitTag
+	HeadTags       []string
 	Revision       string
+	FileHistory    map[string][]GitFileRevision
 }
 
 func (g *GitFake) FindGitConfigDir(dir string) (string, string, error) {
@@ -131,7 +140,7 @@ func (g *GitFake) PushTag(dir string, tag string) error {
 
 func (g *GitFake) CreatePushURL(cloneURL string, userAuth *auth.UserAuth) (string, error) {
 	u, err := url.Parse(cloneURL)
-	if err != nil {
+	if err != nil || isSSHScheme(u.Scheme) {
 		return cloneURL, nil
 	}
 	if userAuth.Username != "" || userAuth.ApiToken != "" {
@@ -393,6 +402,28 @@ func (g *GitFake) CreateTag(dir string, tag string, msg string) error {
 	return nil
 }
 
+func (g *GitFake) CreateSignedTag(dir string, tag string, msg string) error {
+	t := GitTag{
+		Name:    tag,
+		Message: msg,
+		Signed:  true,
+	}
+	g.GitTags = append(g.GitTags, t)
+	return nil
+}
+
+func (g *GitFake) ReleaseTags(dir string) ([]string, error) {
+	return g.HeadTags, nil
+}
+
+func (g *GitFake) GetCommitMessagesSince(dir string, ref string) ([]string, error) {
+	messages := []string{}
+	for _, commit := range g.Commits {
+		messages = append(messages, commit.Message)
+	}
+	return messages, nil
+}
+
 func (g *GitFake) GetRevisionBeforeDate(dir string, t time.Time) (string, error) {
 	return g.Revision, nil
 }
@@ -401,6 +432,23 @@ func (g *GitFake) GetRevisionBeforeDateText(dir string, dateText string) (string
 	return g.Revision, nil
 }
 
+func (g *GitFake) FileRevisions(dir string, fileName string) ([]string, error) {
+	answer := []string{}
+	for _, revision := range g.FileHistory[fileName] {
+		answer = append(answer, revision.SHA)
+	}
+	return answer, nil
+}
+
+func (g *GitFake) ShowFileAtRevision(dir string, revision string, fileName string) (string, error) {
+	for _, r := range g.FileHistory[fileName] {
+		if r.SHA == revision {
+			return r.Content, nil
+		}
+	}
+	return "", fmt.Errorf("no file %s at revision %s", fileName, revision)
+}
+
 func (g *GitFake) Diff(dir string) (string, error) {
 	return "", nil
 }
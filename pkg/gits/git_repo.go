@@ -26,6 +26,8 @@ type GitRepositoryOptions struct {
 	ApiToken  string
 	Owner     string
 	Private   bool
+	// Protocol the protocol of the git remotes of new repositories, ssh or https. Defaults to https
+	Protocol string
 }
 
 // GetRepository returns the repository if it already exists
@@ -110,12 +112,18 @@ func PickNewOrExistingGitRepository(batchMode bool, authConfigSvc auth.AuthConfi
 			return nil
 		}
 
-		// TODO could we guess this based on the users ~/.git for github?
-		defaultUserName := ""
-		err = config.EditUserAuth(server.Label(), userAuth, defaultUserName, true, batchMode, f, in, out, errOut)
+		loggedIn, err := LoginWithGitHubDeviceFlow(server, userAuth, batchMode, out)
 		if err != nil {
 			return nil, err
 		}
+		if !loggedIn {
+			// TODO could we guess this based on the users ~/.git for github?
+			defaultUserName := ""
+			err = config.EditUserAuth(server.Label(), userAuth, defaultUserName, true, batchMode, f, in, out, errOut)
+			if err != nil {
+				return nil, err
+			}
+		}
 
 		// TODO lets verify the auth works
 
@@ -28,23 +28,106 @@ func (i *GitRepositoryInfo) IsGitHub() bool {
 	return GitHubHost == i.Host || strings.HasSuffix(i.URL, "https://github.com")
 }
 
+// IsAzureDevOps returns true if the repository is hosted on Azure DevOps
+func (i *GitRepositoryInfo) IsAzureDevOps() bool {
+	return IsAzureDevOpsHost(i.Host)
+}
+
 // PullRequestURL returns the URL of a pull request of the given name/number
 func (i *GitRepositoryInfo) PullRequestURL(prName string) string {
+	if i.IsAzureDevOps() {
+		return util.UrlJoin(i.HttpsURL(), "pullrequest", prName)
+	}
 	return util.UrlJoin("https://"+i.Host, i.Organisation, i.Name, "pull", prName)
 }
 
 // HttpCloneURL returns the HTTPS git URL this repository
 func (i *GitRepositoryInfo) HttpCloneURL() string {
+	if i.IsAzureDevOps() {
+		return i.HttpsURL()
+	}
 	return i.HttpsURL() + ".git"
 }
 
+// SSHCloneURL returns the ssh:// git URL of this repository
+func (i *GitRepositoryInfo) SSHCloneURL() string {
+	host := i.hostName()
+	if i.IsAzureDevOps() {
+		// the organization of Azure DevOps repositories is part of their host
+		org := ""
+		parts := strings.SplitN(host, "/", 2)
+		if len(parts) == 2 {
+			org = parts[1]
+		}
+		return "ssh://git@" + util.UrlJoin(azureDevOpsSSHHost, "v3", org, i.Organisation, i.Name)
+	}
+	host = strings.SplitN(host, "/", 2)[0]
+	return "ssh://git@" + util.UrlJoin(host, i.Organisation, i.Name) + ".git"
+}
+
+// CloneURLForProtocol returns the git URL of this repository for the given protocol, an ssh:// URL for ssh and a
+// https:// URL otherwise
+func (i *GitRepositoryInfo) CloneURLForProtocol(protocol string) string {
+	if protocol == GitProtocolSSH {
+		return i.SSHCloneURL()
+	}
+	return i.HttpCloneURL()
+}
+
+// IsSSH returns true if the repository URL is an ssh://, git@ or git:// URL rather than an http(s) URL
+func (i *GitRepositoryInfo) IsSSH() bool {
+	return isSSHScheme(i.Scheme)
+}
+
+// hostName returns the host of the repository without any scheme
+func (i *GitRepositoryInfo) hostName() string {
+	host := i.Host
+	idx := strings.Index(host, "://")
+	if idx >= 0 {
+		host = host[idx+3:]
+	}
+	return host
+}
+
+// GitCloneURLForProtocol converts the given git URL to the given protocol, ssh or https, returning the URL unchanged
+// if it already uses the protocol or no protocol is given
+func GitCloneURLForProtocol(cloneURL string, protocol string) (string, error) {
+	if protocol == "" {
+		return cloneURL, nil
+	}
+	if util.StringArrayIndex(GitProtocols, protocol) < 0 {
+		return "", util.InvalidOption("git-protocol", protocol, GitProtocols)
+	}
+	info, err := ParseGitURL(cloneURL)
+	if err != nil {
+		return "", err
+	}
+	if info.IsSSH() == (protocol == GitProtocolSSH) {
+		return cloneURL, nil
+	}
+	return info.CloneURLForProtocol(protocol), nil
+}
+
+func isSSHScheme(scheme string) bool {
+	return scheme == "ssh" || scheme == "git" || scheme == "git+ssh"
+}
+
+// repositoryPath returns the path of the repository relative to its host. Azure DevOps repositories are under the
+// _git path of their project
+func (i *GitRepositoryInfo) repositoryPath() string {
+	if i.IsAzureDevOps() {
+		return util.UrlJoin(i.Organisation, "_git", i.Name)
+	}
+	return util.UrlJoin(i.Organisation, i.Name)
+}
+
 // HttpURL returns the URL to browse this repository in a web browser
 func (i *GitRepositoryInfo) HttpURL() string {
 	host := i.Host
 	if !strings.Contains(host, ":/") {
 		host = "http://" + host
 	}
-	return util.UrlJoin(host, i.Organisation, i.Name)
+	return util.UrlJoin(host, i.repositoryPath())
 }
 
 // HttpsURL returns the URL to browse this repository in a web browser
@@ -53,13 +136,17 @@ func (i *GitRepositoryInfo) HttpsURL() string {
 	if !strings.Contains(host, ":/") {
 		host = "https://" + host
 	}
-	return util.UrlJoin(host, i.Organisation, i.Name)
+	return util.UrlJoin(host, i.repositoryPath())
 }
 
 // HostURL returns the URL to the host
 func (i *GitRepositoryInfo) HostURL() string {
 	answer := i.Host
 	if !strings.Contains(answer, ":/") {
+		if i.IsSSH() {
+			// the provider APIs are always on https even for repositories cloned with ssh
+			return "https://" + answer
+		}
 		// lets find the scheme from the URL
 		u := i.URL
 		if u != "" {
@@ -83,7 +170,7 @@ func (i *GitRepositoryInfo) HostURL() string {
 
 func (i *GitRepositoryInfo) HostURLWithoutUser() string {
 	u := i.URL
-	if u != "" {
+	if u != "" && !i.IsSSH() {
 		u2, err := url.Parse(u)
 		if err == nil {
 			u2.User = nil
@@ -102,7 +189,18 @@ func (i *GitRepositoryInfo) HostURLWithoutUser() string {
 // PipelinePath returns the pipeline path for the master branch which can be used to query
 // pipeline logs in `jx get build logs myPipelinePath`
 func (i *GitRepositoryInfo) PipelinePath() string {
-	return i.Organisation + "/" + i.Name + "/master"
+	return i.FlatOrganisation() + "/" + i.Name + "/master"
+}
+
+// FlatOrganisation returns the organisation as a single path segment which can be used for the Jenkins folder and
+// the names of Kubernetes resources
+func (i *GitRepositoryInfo) FlatOrganisation() string {
+	return FlattenOrganisation(i.Organisation)
+}
+
+// FlattenOrganisation replaces the '/' separating the groups of a nested namespace, such as a GitLab subgroup, with '-'
+func FlattenOrganisation(org string) string {
+	return strings.Replace(org, "/", "-", -1)
 }
 
 // ParseGitURL attempts to parse the given text as a URL or git URL-like string to determine
@@ -114,6 +212,14 @@ func ParseGitURL(text string) (*GitRepositoryInfo, error) {
 	u, err := url.Parse(text)
 	if err == nil && u != nil {
 		answer.Host = u.Host
+		if isSSHScheme(u.Scheme) {
+			// the port of the ssh server is not the port of the web server of the git provider
+			answer.Host = u.Hostname()
+			if answer.Host == azureDevOpsSSHHost {
+				answer.Scheme = u.Scheme
+				return parseAzureDevOpsSSHPath(strings.Trim(u.Path, "/"), &answer)
+			}
+		}
 
 		// lets default to github
 		if answer.Host == "" {
@@ -135,25 +241,62 @@ func ParseGitURL(text string) (*GitRepositoryInfo, error) {
 		t = strings.TrimSuffix(t, ".git")
 
 		arr := util.RegexpSplit(t, ":|/")
+		if len(arr) > 1 && arr[0] == azureDevOpsSSHHost {
+			answer.Scheme = "git"
+			return parseAzureDevOpsSSHPath(strings.Join(arr[1:], "/"), &answer)
+		}
 		if len(arr) >= 3 {
 			answer.Scheme = "git"
 			answer.Host = arr[0]
-			answer.Organisation = arr[1]
-			answer.Name = arr[2]
+			answer.Organisation = strings.Join(arr[1:len(arr)-1], "/")
+			answer.Project = answer.Organisation
+			answer.Name = arr[len(arr)-1]
 			return &answer, nil
 		}
 	}
 	return nil, fmt.Errorf("Could not parse Git URL %s", text)
 }
 
+// parseAzureDevOpsSSHPath parses the path of the SSH URLs of Azure DevOps repositories which are of the form
+// git@ssh.dev.azure.com:v3/organization/project/repository
+func parseAzureDevOpsSSHPath(path string, info *GitRepositoryInfo) (*GitRepositoryInfo, error) {
+	arr := strings.Split(strings.TrimSuffix(path, ".git"), "/")
+	if len(arr) != 4 || arr[0] != "v3" {
+		return nil, fmt.Errorf("Could not parse Azure DevOps SSH URL %s", info.URL)
+	}
+	info.Host = "dev.azure.com/" + arr[1]
+	info.Organisation = arr[2]
+	info.Project = info.Organisation
+	info.Name = arr[3]
+	return info, nil
+}
+
 func parsePath(path string, info *GitRepositoryInfo) (*GitRepositoryInfo, error) {
 	trimPath := strings.TrimSuffix(path, "/")
 	trimPath = strings.TrimSuffix(trimPath, ".git")
+	trimPath = strings.TrimPrefix(trimPath, "/")
 	arr := strings.Split(trimPath, "/")
 	arrayLength := len(arr)
+	if arrayLength >= 3 && arr[arrayLength-2] == "_git" {
+		// Azure DevOps repositories are under the _git path of their project. The organization in the path of
+		// dev.azure.com URLs is part of the host as each organization is a separate server
+		if arrayLength > 3 {
+			info.Host = info.Host + "/" + strings.Join(arr[0:arrayLength-3], "/")
+		}
+		info.Organisation = arr[arrayLength-3]
+		info.Project = info.Organisation
+		info.Name = arr[arrayLength-1]
+		return info, nil
+	}
 	if arrayLength >= 2 {
-		info.Organisation = arr[arrayLength-2]
-		info.Project = arr[arrayLength-2]
+		// the namespace of a GitLab project in a subgroup is the whole path before its name whereas
+		// GitHub and Bitbucket Server clone URLs only have one organisation or project segment
+		start := 0
+		if info.Host == GitHubHost || arr[0] == "scm" {
+			start = arrayLength - 2
+		}
+		info.Organisation = strings.Join(arr[start:arrayLength-1], "/")
+		info.Project = info.Organisation
 		info.Name = arr[arrayLength-1]
 
 		return info, nil
@@ -176,6 +319,9 @@ func SaasGitKind(gitServiceUrl string) string {
 	case BitbucketCloudURL:
 		return KindBitBucketCloud
 	default:
+		if IsAzureDevOpsURL(gitServiceUrl) {
+			return KindAzureDevOps
+		}
 		return ""
 	}
 }
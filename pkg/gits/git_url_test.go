@@ -50,6 +50,36 @@ func TestParseGitURL(t *testing.T) {
 		{
 			"http://test-user@auth.example.com/scm/bar/foo.git", "auth.example.com", "bar", "foo",
 		},
+		{
+			"https://gitlab.com/group/subgroup/project", "gitlab.com", "group/subgroup", "project",
+		},
+		{
+			"https://gitlab.com/group/subgroup/nested/project.git", "gitlab.com", "group/subgroup/nested", "project",
+		},
+		{
+			"git@gitlab.com:group/subgroup/project.git", "gitlab.com", "group/subgroup", "project",
+		},
+		{
+			"https://gitlab.example.com/group/subgroup/nested/project.git", "gitlab.example.com", "group/subgroup/nested", "project",
+		},
+		{
+			"https://myorg@dev.azure.com/myorg/myproject/_git/myrepo", "dev.azure.com/myorg", "myproject", "myrepo",
+		},
+		{
+			"https://myorg.visualstudio.com/myproject/_git/myrepo", "myorg.visualstudio.com", "myproject", "myrepo",
+		},
+		{
+			"git@ssh.dev.azure.com:v3/myorg/myproject/myrepo", "dev.azure.com/myorg", "myproject", "myrepo",
+		},
+		{
+			"ssh://git@ssh.dev.azure.com/v3/myorg/myproject/myrepo", "dev.azure.com/myorg", "myproject", "myrepo",
+		},
+		{
+			"ssh://git@github.com/bar/foo.git", "github.com", "bar", "foo",
+		},
+		{
+			"ssh://git@bitbucket.example.com:7999/proj/foo.git", "bitbucket.example.com", "proj", "foo",
+		},
 	}
 	for _, data := range testCases {
 		info, err := gits.ParseGitURL(data.url)
@@ -60,3 +90,67 @@ func TestParseGitURL(t *testing.T) {
 		assert.Equal(t, data.name, info.Name, "Name does not match for input %s", data.url)
 	}
 }
+
+func TestAzureDevOpsURLs(t *testing.T) {
+	t.Parallel()
+	info, err := gits.ParseGitURL("https://myorg@dev.azure.com/myorg/myproject/_git/myrepo")
+	assert.NoError(t, err)
+	assert.True(t, info.IsAzureDevOps())
+	assert.Equal(t, "https://dev.azure.com/myorg", info.HostURL())
+	assert.Equal(t, "https://dev.azure.com/myorg/myproject/_git/myrepo", info.HttpsURL())
+	assert.Equal(t, "https://dev.azure.com/myorg/myproject/_git/myrepo", info.HttpCloneURL())
+	assert.Equal(t, "https://dev.azure.com/myorg/myproject/_git/myrepo/pullrequest/3", info.PullRequestURL("3"))
+	assert.Equal(t, gits.KindAzureDevOps, gits.SaasGitKind(info.HostURL()))
+}
+
+func TestFlatOrganisation(t *testing.T) {
+	t.Parallel()
+	info, err := gits.ParseGitURL("https://gitlab.com/group/subgroup/nested/project.git")
+	assert.NoError(t, err)
+	assert.Equal(t, "group-subgroup-nested", info.FlatOrganisation())
+	assert.Equal(t, "group-subgroup-nested/project/master", info.PipelinePath())
+	assert.Equal(t, "https://gitlab.com/group/subgroup/nested/project", info.HttpsURL())
+	assert.Equal(t, "jenkins-x", gits.FlattenOrganisation("jenkins-x"))
+}
+
+func TestSSHCloneURLs(t *testing.T) {
+	t.Parallel()
+	info, err := gits.ParseGitURL("https://github.com/bar/foo.git")
+	assert.NoError(t, err)
+	assert.Equal(t, "ssh://git@github.com/bar/foo.git", info.SSHCloneURL())
+	assert.Equal(t, "https://github.com/bar/foo.git", info.CloneURLForProtocol(gits.GitProtocolHTTPS))
+	assert.Equal(t, "ssh://git@github.com/bar/foo.git", info.CloneURLForProtocol(gits.GitProtocolSSH))
+
+	info, err = gits.ParseGitURL("ssh://git@github.com/bar/foo.git")
+	assert.NoError(t, err)
+	assert.True(t, info.IsSSH())
+	assert.Equal(t, "https://github.com", info.HostURL())
+	assert.Equal(t, "https://github.com/bar/foo", info.HttpsURL())
+
+	info, err = gits.ParseGitURL("https://myorg@dev.azure.com/myorg/myproject/_git/myrepo")
+	assert.NoError(t, err)
+	assert.Equal(t, "ssh://git@ssh.dev.azure.com/v3/myorg/myproject/myrepo", info.SSHCloneURL())
+
+	info, err = gits.ParseGitURL("ssh://git@ssh.dev.azure.com/v3/myorg/myproject/myrepo")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://dev.azure.com/myorg", info.HostURL())
+	assert.Equal(t, "https://dev.azure.com/myorg/myproject/_git/myrepo", info.HttpCloneURL())
+}
+
+func TestGitCloneURLForProtocol(t *testing.T) {
+	t.Parallel()
+	u, err := gits.GitCloneURLForProtocol("https://github.com/bar/foo.git", gits.GitProtocolSSH)
+	assert.NoError(t, err)
+	assert.Equal(t, "ssh://git@github.com/bar/foo.git", u)
+
+	u, err = gits.GitCloneURLForProtocol("git@github.com:bar/foo.git", gits.GitProtocolHTTPS)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://github.com/bar/foo.git", u)
+
+	u, err = gits.GitCloneURLForProtocol("https://github.com/bar/foo.git", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://github.com/bar/foo.git", u)
+
+	_, err = gits.GitCloneURLForProtocol("https://github.com/bar/foo.git", "ftp")
+	assert.Error(t, err)
+}
@@ -1,7 +1,13 @@
 package gits
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -13,6 +19,9 @@ import (
 	"github.com/jenkins-x/jx/pkg/util"
 )
 
+// giteaWebHookEvents the events of a repository which trigger its webhooks
+var giteaWebHookEvents = []string{"create", "push", "pull_request", "issue_comment"}
+
 type GiteaProvider struct {
 	Username string
 	Client   *gitea.Client
@@ -67,7 +76,7 @@ func (p *GiteaProvider) ListRepositories(org string) ([]*GitRepository, error) {
 		}
 		return answer, nil
 	}
-	repos, err := p.Client.ListOrgRepos(org)
+	repos, err := p.Client.ListOrgRepos(p.owner(org))
 	if err != nil {
 		return answer, err
 	}
@@ -78,22 +87,19 @@ func (p *GiteaProvider) ListRepositories(org string) ([]*GitRepository, error) {
 }
 
 func (p *GiteaProvider) ListReleases(org string, name string) ([]*GitRelease, error) {
-	owner := org
-	if owner == "" {
-		owner = p.Username
-	}
+	owner := p.owner(org)
 	answer := []*GitRelease{}
 	repos, err := p.Client.ListReleases(owner, name)
 	if err != nil {
 		return answer, err
 	}
 	for _, repo := range repos {
-		answer = append(answer, toGiteaRelease(org, name, repo))
+		answer = append(answer, p.toGiteaRelease(owner, name, repo))
 	}
 	return answer, nil
 }
 
-func toGiteaRelease(org string, name string, release *gitea.Release) *GitRelease {
+func (p *GiteaProvider) toGiteaRelease(org string, name string, release *gitea.Release) *GitRelease {
 	totalDownloadCount := 0
 	for _, asset := range release.Attachments {
 		totalDownloadCount = totalDownloadCount + int(asset.DownloadCount)
@@ -103,17 +109,29 @@ func toGiteaRelease(org string, name string, release *gitea.Release) *GitRelease
 		TagName:       release.TagName,
 		Body:          release.Note,
 		URL:           release.URL,
-		HTMLURL:       release.URL,
+		HTMLURL:       p.releaseURL(org, name, release.TagName),
 		DownloadCount: totalDownloadCount,
 	}
 }
 
+// releaseURL returns the URL of the web page of the release of the tag
+func (p *GiteaProvider) releaseURL(org string, name string, tag string) string {
+	return util.UrlJoin(p.ServerURL(), org, name, "releases/tag", tag)
+}
+
 func (p *GiteaProvider) CreateRepository(org string, name string, private bool) (*GitRepository, error) {
 	options := gitea.CreateRepoOption{
 		Name:    name,
 		Private: private,
 	}
-	repo, err := p.Client.CreateRepo(options)
+	owner := p.owner(org)
+	var repo *gitea.Repository
+	var err error
+	if owner == p.Username {
+		repo, err = p.Client.CreateRepo(options)
+	} else {
+		repo, err = p.Client.CreateOrgRepo(owner, options)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("Failed to create repository %s/%s due to: %s", org, name, err)
 	}
@@ -121,7 +139,7 @@ func (p *GiteaProvider) CreateRepository(org string, name string, private bool)
 }
 
 func (p *GiteaProvider) GetRepository(org string, name string) (*GitRepository, error) {
-	repo, err := p.Client.GetRepo(org, name)
+	repo, err := p.Client.GetRepo(p.owner(org), name)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to get repository %s/%s due to: %s", org, name, err)
 	}
@@ -129,10 +147,7 @@ func (p *GiteaProvider) GetRepository(org string, name string) (*GitRepository,
 }
 
 func (p *GiteaProvider) DeleteRepository(org string, name string) error {
-	owner := org
-	if owner == "" {
-		owner = p.Username
-	}
+	owner := p.owner(org)
 	err := p.Client.DeleteRepo(owner, name)
 	if err != nil {
 		return fmt.Errorf("Failed to delete repository %s/%s due to: %s", owner, name, err)
@@ -152,10 +167,12 @@ func toGiteaRepo(name string, repo *gitea.Repository) *GitRepository {
 }
 
 func (p *GiteaProvider) ForkRepository(originalOrg string, name string, destinationOrg string) (*GitRepository, error) {
-	repoConfig := gitea.CreateForkOption{
-		Organization: &destinationOrg,
+	repoConfig := gitea.CreateForkOption{}
+	if destinationOrg != "" {
+		destinationOrg = p.owner(destinationOrg)
+		repoConfig.Organization = &destinationOrg
 	}
-	repo, err := p.Client.CreateFork(originalOrg, name, repoConfig)
+	repo, err := p.Client.CreateFork(p.owner(originalOrg), name, repoConfig)
 	if err != nil {
 		msg := ""
 		if destinationOrg != "" {
@@ -188,17 +205,16 @@ func (p *GiteaProvider) ForkRepository(originalOrg string, name string, destinat
 	return toGiteaRepo(name, repo), nil
 }
 
+// CreateWebHook creates the webhook of the repository, updating an existing webhook with the same URL so that its
+// secret and events are up to date
 func (p *GiteaProvider) CreateWebHook(data *GitWebHookArguments) error {
-	owner := data.Owner
-	if owner == "" {
-		owner = p.Username
-	}
+	owner := p.owner(data.Owner)
 	repo := data.Repo.Name
 	if repo == "" {
 		return fmt.Errorf("Missing property Repo")
 	}
 	webhookUrl := data.URL
-	if repo == "" {
+	if webhookUrl == "" {
 		return fmt.Errorf("Missing property URL")
 	}
 	hooks, err := p.Client.ListRepoHooks(owner, repo)
@@ -208,24 +224,23 @@ func (p *GiteaProvider) CreateWebHook(data *GitWebHookArguments) error {
 	for _, hook := range hooks {
 		s := hook.Config["url"]
 		if s == webhookUrl {
-			log.Warnf("Already has a webhook registered for %s\n", webhookUrl)
-			return nil
+			log.Infof("Updating the existing Gitea webhook for %s/%s for url %s\n", owner, repo, webhookUrl)
+			return p.UpdateWebHook(&GitWebHookArguments{
+				ID:     hook.ID,
+				Owner:  owner,
+				Repo:   data.Repo,
+				URL:    webhookUrl,
+				Secret: data.Secret,
+			})
 		}
 	}
-	config := map[string]string{
-		"url":          webhookUrl,
-		"content_type": "json",
-	}
-	if data.Secret != "" {
-		config["secret"] = data.Secret
-	}
 	hook := gitea.CreateHookOption{
 		Type:   "gitea",
-		Config: config,
-		Events: []string{"create", "push", "pull_request"},
+		Config: giteaWebHookConfig(data),
+		Events: giteaWebHookEvents,
 		Active: true,
 	}
-	log.Infof("Creating GitHub webhook for %s/%s for url %s\n", owner, repo, webhookUrl)
+	log.Infof("Creating Gitea webhook for %s/%s for url %s\n", owner, repo, webhookUrl)
 	_, err = p.Client.CreateRepoHook(owner, repo, hook)
 	if err != nil {
 		return fmt.Errorf("Failed to create webhook for %s/%s with %#v due to: %s", owner, repo, hook, err)
@@ -233,8 +248,66 @@ func (p *GiteaProvider) CreateWebHook(data *GitWebHookArguments) error {
 	return err
 }
 
+func (p *GiteaProvider) ListWebHooks(owner string, repo string) ([]*GitWebHookArguments, error) {
+	owner = p.owner(owner)
+	answer := []*GitWebHookArguments{}
+	hooks, err := p.Client.ListRepoHooks(owner, repo)
+	if err != nil {
+		return answer, err
+	}
+	for _, hook := range hooks {
+		answer = append(answer, &GitWebHookArguments{
+			ID:    hook.ID,
+			Owner: owner,
+			Repo:  &GitRepositoryInfo{Organisation: owner, Name: repo},
+			URL:   hook.Config["url"],
+		})
+	}
+	return answer, nil
+}
+
+func (p *GiteaProvider) UpdateWebHook(data *GitWebHookArguments) error {
+	owner := p.owner(data.Owner)
+	active := true
+	hook := gitea.EditHookOption{
+		Config: giteaWebHookConfig(data),
+		Events: giteaWebHookEvents,
+		Active: &active,
+	}
+	return p.Client.EditRepoHook(owner, data.Repo.Name, data.ID, hook)
+}
+
+// giteaWebHookConfig returns the configuration of the webhook which posts JSON payloads signed with its secret
+func giteaWebHookConfig(data *GitWebHookArguments) map[string]string {
+	config := map[string]string{
+		"url":          data.URL,
+		"content_type": "json",
+	}
+	if data.Secret != "" {
+		config["secret"] = data.Secret
+	}
+	return config
+}
+
+func (p *GiteaProvider) DeleteWebHook(owner string, repo string, webhookURL string) error {
+	owner = p.owner(owner)
+	hooks, err := p.Client.ListRepoHooks(owner, repo)
+	if err != nil {
+		return err
+	}
+	for _, hook := range hooks {
+		if hook.Config["url"] == webhookURL {
+			err = p.Client.DeleteRepoHook(owner, repo, hook.ID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (p *GiteaProvider) CreatePullRequest(data *GitPullRequestArguments) (*GitPullRequest, error) {
-	owner := data.GitRepositoryInfo.Organisation
+	owner := p.owner(data.GitRepositoryInfo.Organisation)
 	repo := data.GitRepositoryInfo.Name
 	title := data.Title
 	body := data.Body
@@ -261,8 +334,8 @@ func (p *GiteaProvider) CreatePullRequest(data *GitPullRequestArguments) (*GitPu
 	answer := &GitPullRequest{
 		URL:    pr.HTMLURL,
 		Number: &id,
-		Owner:  data.GitRepositoryInfo.Organisation,
-		Repo:   data.GitRepositoryInfo.Name,
+		Owner:  owner,
+		Repo:   repo,
 	}
 	if pr.Head != nil {
 		answer.LastCommitSha = pr.Head.Sha
@@ -275,12 +348,16 @@ func (p *GiteaProvider) UpdatePullRequestStatus(pr *GitPullRequest) error {
 		return fmt.Errorf("Missing Number for GitPullRequest %#v", pr)
 	}
 	n := *pr.Number
+	pr.Owner = p.owner(pr.Owner)
 	result, err := p.Client.GetPullRequest(pr.Owner, pr.Repo, int64(n))
 	if err != nil {
 		return fmt.Errorf("Could not find pull request for %s/%s #%d: %s", pr.Owner, pr.Repo, n, err)
 	}
-	pr.Author = &GitUser{
-		Login: result.Poster.UserName,
+	if result.Poster != nil {
+		pr.Author = toGiteaUser(result.Poster)
+	}
+	if pr.URL == "" {
+		pr.URL = result.HTMLURL
 	}
 	merged := result.HasMerged
 	pr.Merged = &merged
@@ -291,20 +368,17 @@ func (p *GiteaProvider) UpdatePullRequestStatus(pr *GitPullRequest) error {
 	pr.Body = result.Body
 	stateText := string(result.State)
 	pr.State = &stateText
+	pr.ClosedAt = result.Closed
+	if result.DiffURL != "" {
+		pr.DiffURL = &result.DiffURL
+	}
 	head := result.Head
 	if head != nil {
 		pr.LastCommitSha = head.Sha
+		pr.HeadRef = &head.Ref
 	} else {
 		pr.LastCommitSha = ""
 	}
-	/*
-		TODO
-
-		pr.ClosedAt = result.Closed
-		pr.StatusesURL = result.StatusesURL
-		pr.IssueURL = result.IssueURL
-		pr.DiffURL = result.DiffURL
-	*/
 	return nil
 }
 
@@ -315,13 +389,17 @@ func (p *GiteaProvider) GetPullRequest(owner string, repo *GitRepositoryInfo, nu
 		Number: &number,
 	}
 	err := p.UpdatePullRequestStatus(pr)
-
-	existing := p.UserInfo(pr.Author.Login)
-	if existing != nil && existing.Email != "" {
-		pr.Author = existing
+	if err != nil {
+		return pr, err
 	}
 
-	return pr, err
+	if pr.Author != nil && pr.Author.Email == "" {
+		existing := p.UserInfo(pr.Author.Login)
+		if existing != nil && existing.Email != "" {
+			pr.Author = existing
+		}
+	}
+	return pr, nil
 }
 
 func (p *GiteaProvider) GetPullRequestCommits(owner string, repository *GitRepositoryInfo, number int) ([]*GitCommit, error) {
@@ -336,6 +414,7 @@ func (p *GiteaProvider) GetPullRequestCommits(owner string, repository *GitRepos
 }
 
 func (p *GiteaProvider) GetIssue(org string, name string, number int) (*GitIssue, error) {
+	org = p.owner(org)
 	i, err := p.Client.GetIssue(org, name, int64(number))
 	if err != nil {
 		if strings.Contains(err.Error(), "404") {
@@ -353,10 +432,9 @@ func (p *GiteaProvider) IssueURL(org string, name string, number int, isPull boo
 	}
 	path := "issues"
 	if isPull {
-		path = "pull"
+		path = "pulls"
 	}
-	url := util.UrlJoin(serverPrefix, org, name, path, strconv.Itoa(number))
-	return url
+	return util.UrlJoin(serverPrefix, p.owner(org), name, path, strconv.Itoa(number))
 }
 
 func (p *GiteaProvider) SearchIssues(org string, name string, filter string) ([]*GitIssue, error) {
@@ -376,6 +454,7 @@ func (p *GiteaProvider) SearchIssuesClosedSince(org string, name string, t time.
 
 func (p *GiteaProvider) searchIssuesWithOptions(org string, name string, opts gitea.ListIssueOption) ([]*GitIssue, error) {
 	opts.Page = 0
+	org = p.owner(org)
 	answer := []*GitIssue{}
 	issues, err := p.Client.ListRepoIssues(org, name, opts)
 	if err != nil {
@@ -405,7 +484,7 @@ func (p *GiteaProvider) fromGiteaIssue(org string, name string, i *gitea.Issue)
 	if assignee != nil {
 		assignees = append(assignees, *toGiteaUser(assignee))
 	}
-	number := int(i.ID)
+	number := int(i.Index)
 	return &GitIssue{
 		Number:        &number,
 		URL:           p.IssueURL(org, name, number, false),
@@ -427,6 +506,7 @@ func (p *GiteaProvider) CreateIssue(owner string, repo string, issue *GitIssue)
 		Title: issue.Title,
 		Body:  issue.Body,
 	}
+	owner = p.owner(owner)
 	i, err := p.Client.CreateIssue(owner, repo, config)
 	if err != nil {
 		return nil, err
@@ -456,7 +536,12 @@ func (p *GiteaProvider) MergePullRequest(pr *GitPullRequest, message string) err
 		return fmt.Errorf("Missing Number for GitPullRequest %#v", pr)
 	}
 	n := *pr.Number
-	return p.Client.MergePullRequest(pr.Owner, pr.Repo, int64(n))
+	body := map[string]string{
+		"Do":                "merge",
+		"MergeMessageField": message,
+	}
+	path := fmt.Sprintf("repos/%s/%s/pulls/%d/merge", p.owner(pr.Owner), pr.Repo, n)
+	return p.request(http.MethodPost, path, body, nil)
 }
 
 func (p *GiteaProvider) PullRequestLastCommitStatus(pr *GitPullRequest) (string, error) {
@@ -464,7 +549,7 @@ func (p *GiteaProvider) PullRequestLastCommitStatus(pr *GitPullRequest) (string,
 	if ref == "" {
 		return "", fmt.Errorf("Missing String for LastCommitSha %#v", pr)
 	}
-	results, err := p.Client.ListStatuses(pr.Owner, pr.Repo, ref, gitea.ListStatusesOption{})
+	results, err := p.Client.ListStatuses(p.owner(pr.Owner), pr.Repo, ref, gitea.ListStatusesOption{})
 	if err != nil {
 		return "", err
 	}
@@ -485,7 +570,43 @@ func (p *GiteaProvider) AddPRComment(pr *GitPullRequest, comment string) error {
 	prComment := gitea.CreateIssueCommentOption{
 		Body: asText(&comment),
 	}
-	_, err := p.Client.CreateIssueComment(pr.Owner, pr.Repo, int64(n), prComment)
+	_, err := p.Client.CreateIssueComment(p.owner(pr.Owner), pr.Repo, int64(n), prComment)
+	return err
+}
+
+func (p *GiteaProvider) ListPRComments(pr *GitPullRequest) ([]*GitPRComment, error) {
+	answer := []*GitPRComment{}
+	if pr.Number == nil {
+		return answer, fmt.Errorf("Missing Number for GitPullRequest %#v", pr)
+	}
+	comments, err := p.Client.ListIssueComments(p.owner(pr.Owner), pr.Repo, int64(*pr.Number))
+	if err != nil {
+		return answer, err
+	}
+	for _, comment := range comments {
+		created := comment.Created
+		prComment := &GitPRComment{
+			ID:        comment.ID,
+			Body:      comment.Body,
+			CreatedAt: &created,
+		}
+		if comment.Poster != nil {
+			prComment.User = GitUser{
+				Login: comment.Poster.UserName,
+				Name:  comment.Poster.FullName,
+				Email: comment.Poster.Email,
+			}
+		}
+		answer = append(answer, prComment)
+	}
+	return answer, nil
+}
+
+func (p *GiteaProvider) EditPRComment(pr *GitPullRequest, comment *GitPRComment, body string) error {
+	if pr.Number == nil {
+		return fmt.Errorf("Missing Number for GitPullRequest %#v", pr)
+	}
+	_, err := p.Client.EditIssueComment(p.owner(pr.Owner), pr.Repo, int64(*pr.Number), comment.ID, gitea.EditIssueCommentOption{Body: body})
 	return err
 }
 
@@ -493,7 +614,7 @@ func (p *GiteaProvider) CreateIssueComment(owner string, repo string, number int
 	issueComment := gitea.CreateIssueCommentOption{
 		Body: comment,
 	}
-	_, err := p.Client.CreateIssueComment(owner, repo, int64(number), issueComment)
+	_, err := p.Client.CreateIssueComment(p.owner(owner), repo, int64(number), issueComment)
 	if err != nil {
 		return err
 	}
@@ -502,7 +623,7 @@ func (p *GiteaProvider) CreateIssueComment(owner string, repo string, number int
 
 func (p *GiteaProvider) ListCommitStatus(org string, repo string, sha string) ([]*GitRepoStatus, error) {
 	answer := []*GitRepoStatus{}
-	results, err := p.Client.ListStatuses(org, repo, sha, gitea.ListStatusesOption{})
+	results, err := p.Client.ListStatuses(p.owner(org), repo, sha, gitea.ListStatusesOption{})
 	if err != nil {
 		return answer, fmt.Errorf("Could not find a status for repository %s/%s with ref %s", org, repo, sha)
 	}
@@ -520,12 +641,33 @@ func (p *GiteaProvider) ListCommitStatus(org string, repo string, sha string) ([
 	return answer, nil
 }
 
+func (p *GiteaProvider) UpdateCommitStatus(org string, repo string, sha string, status *GitRepoStatus) (*GitRepoStatus, error) {
+	opts := gitea.CreateStatusOption{
+		State:       gitea.StatusState(status.State),
+		TargetURL:   status.TargetURL,
+		Description: status.Description,
+		Context:     status.Context,
+	}
+	result, err := p.Client.CreateStatus(p.owner(org), repo, sha, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &GitRepoStatus{
+		ID:          fmt.Sprintf("%d", result.ID),
+		Context:     result.Context,
+		URL:         result.URL,
+		TargetURL:   result.TargetURL,
+		State:       string(result.State),
+		Description: result.Description,
+	}, nil
+}
+
 func (p *GiteaProvider) RenameRepository(org string, name string, newName string) (*GitRepository, error) {
 	return nil, fmt.Errorf("Rename of repositories is not supported for Gitea")
 }
 
 func (p *GiteaProvider) ValidateRepositoryName(org string, name string) error {
-	_, err := p.Client.GetRepo(org, name)
+	_, err := p.Client.GetRepo(p.owner(org), name)
 	if err == nil {
 		return fmt.Errorf("Repository %s already exists", p.Git.RepoName(org, name))
 	}
@@ -535,21 +677,26 @@ func (p *GiteaProvider) ValidateRepositoryName(org string, name string) error {
 	return err
 }
 
+// UpdateRelease creates the release of the tag or fills in the missing details of an existing release
 func (p *GiteaProvider) UpdateRelease(owner string, repo string, tag string, releaseInfo *GitRelease) error {
-	var release *gitea.Release
+	owner = p.owner(owner)
 	releases, err := p.Client.ListReleases(owner, repo)
-	found := false
+	if err != nil {
+		return fmt.Errorf("Failed to list the releases of %s/%s due to: %s", owner, repo, err)
+	}
+	var release *gitea.Release
 	for _, rel := range releases {
-		if rel.TagName == tag {
+		// sometimes we prepend a v for example when using gh-release
+		if rel.TagName == tag || (!strings.HasPrefix(tag, "v") && rel.TagName == "v"+tag) {
 			release = rel
-			found = true
 			break
 		}
 	}
 	flag := false
 
 	// lets populate the release
-	if !found {
+	if release == nil {
+		log.Warnf("No release found for %s/%s and tag %s so creating a new release\n", owner, repo, tag)
 		createRelease := gitea.CreateReleaseOption{
 			TagName:      releaseInfo.TagName,
 			Title:        releaseInfo.Name,
@@ -557,34 +704,42 @@ func (p *GiteaProvider) UpdateRelease(owner string, repo string, tag string, rel
 			IsDraft:      flag,
 			IsPrerelease: flag,
 		}
-		_, err = p.Client.CreateRelease(owner, repo, createRelease)
-		return err
-	} else {
-		editRelease := gitea.EditReleaseOption{
-			TagName:      release.TagName,
-			Title:        release.Title,
-			Note:         release.Note,
-			IsDraft:      &flag,
-			IsPrerelease: &flag,
-		}
-		if editRelease.Title == "" && releaseInfo.Name != "" {
-			editRelease.Title = releaseInfo.Name
+		if createRelease.TagName == "" {
+			createRelease.TagName = tag
 		}
-		if editRelease.TagName == "" && releaseInfo.TagName != "" {
-			editRelease.TagName = releaseInfo.TagName
-		}
-		if editRelease.Note == "" && releaseInfo.Body != "" {
-			editRelease.Note = releaseInfo.Body
-		}
-		r2, err := p.Client.EditRelease(owner, repo, release.ID, editRelease)
+		r, err := p.Client.CreateRelease(owner, repo, createRelease)
 		if err != nil {
 			return err
 		}
-		if r2 != nil {
-			releaseInfo.URL = r2.URL
-		}
+		releaseInfo.URL = r.URL
+		releaseInfo.HTMLURL = p.releaseURL(owner, repo, r.TagName)
+		return nil
 	}
-	return err
+	editRelease := gitea.EditReleaseOption{
+		TagName:      release.TagName,
+		Title:        release.Title,
+		Note:         release.Note,
+		IsDraft:      &flag,
+		IsPrerelease: &flag,
+	}
+	if editRelease.Title == "" && releaseInfo.Name != "" {
+		editRelease.Title = releaseInfo.Name
+	}
+	if editRelease.TagName == "" && releaseInfo.TagName != "" {
+		editRelease.TagName = releaseInfo.TagName
+	}
+	if editRelease.Note == "" && releaseInfo.Body != "" {
+		editRelease.Note = releaseInfo.Body
+	}
+	r2, err := p.Client.EditRelease(owner, repo, release.ID, editRelease)
+	if err != nil {
+		return err
+	}
+	if r2 != nil {
+		releaseInfo.URL = r2.URL
+		releaseInfo.HTMLURL = p.releaseURL(owner, repo, r2.TagName)
+	}
+	return nil
 }
 
 func (p *GiteaProvider) HasIssues() bool {
@@ -632,7 +787,7 @@ func (p *GiteaProvider) ServerURL() string {
 }
 
 func (p *GiteaProvider) BranchArchiveURL(org string, name string, branch string) string {
-	return util.UrlJoin(p.ServerURL(), org, name, "archive", branch+".zip")
+	return util.UrlJoin(p.ServerURL(), p.owner(org), name, "archive", branch+".zip")
 }
 
 func (p *GiteaProvider) UserAuth() auth.UserAuth {
@@ -674,3 +829,56 @@ func (p *GiteaProvider) AcceptInvitation(ID int64) (*github.Response, error) {
 	log.Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for Gitea.\n")
 	return &github.Response{}, nil
 }
+
+// owner returns the owner of a repository, defaulting to the current user. The path of a Gitea server which is not at
+// the root of its host is part of the organisation parsed from the URLs of its repositories, so it is removed
+func (p *GiteaProvider) owner(org string) string {
+	if org == "" {
+		return p.Username
+	}
+	u, err := url.Parse(p.Server.URL)
+	if err == nil {
+		prefix := strings.Trim(u.Path, "/")
+		if prefix != "" {
+			org = strings.TrimPrefix(org, prefix+"/")
+		}
+	}
+	return org
+}
+
+// request performs a request against the REST API authenticating with the API token of the user. It is used for the
+// operations the Gitea SDK does not support
+func (p *GiteaProvider) request(method string, path string, body interface{}, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	u := util.UrlJoin(p.Server.URL, "api/v1", path)
+	req, err := http.NewRequest(method, u, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "token "+p.User.ApiToken)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s failed with status %s: %s", method, u, resp.Status, string(data))
+	}
+	if result == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, result)
+}
@@ -0,0 +1,176 @@
+package gits_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/suite"
+)
+
+// giteaOrg the organisation parsed from the URLs of the repositories of a Gitea server at the path /gitea
+const giteaOrg = "gitea/test-org"
+
+type GiteaProviderTestSuite struct {
+	suite.Suite
+	mux       *http.ServeMux
+	server    *httptest.Server
+	provider  *gits.GiteaProvider
+	mergeBody map[string]string
+}
+
+var giteaRouter = util.Router{
+	"/gitea/api/v1/org/test-org/repos": util.MethodMap{
+		"POST": "repo.json",
+	},
+	"/gitea/api/v1/repos/test-org/test-repo/pulls/1": util.MethodMap{
+		"GET": "pr.json",
+	},
+	"/gitea/api/v1/repos/test-org/test-repo/hooks": util.MethodMap{
+		"GET":  "hooks.json",
+		"POST": "hook.json",
+	},
+	"/gitea/api/v1/repos/test-org/test-repo/hooks/3": util.MethodMap{
+		"PATCH": "hook.json",
+	},
+	"/gitea/api/v1/repos/test-org/test-repo/releases": util.MethodMap{
+		"GET":  "releases.json",
+		"POST": "release.json",
+	},
+	"/gitea/api/v1/repos/test-org/test-repo/releases/5": util.MethodMap{
+		"PATCH": "release-5.json",
+	},
+}
+
+func (suite *GiteaProviderTestSuite) SetupSuite() {
+	suite.mux = http.NewServeMux()
+
+	for path, methodMap := range giteaRouter {
+		suite.mux.HandleFunc(path, util.GetMockAPIResponseFromFile("test_data/gitea", methodMap))
+	}
+	suite.mux.HandleFunc("/gitea/api/v1/repos/test-org/test-repo/pulls/1/merge", func(w http.ResponseWriter, r *http.Request) {
+		data, err := ioutil.ReadAll(r.Body)
+		suite.Require().NoError(err)
+		suite.mergeBody = map[string]string{}
+		suite.Require().NoError(json.Unmarshal(data, &suite.mergeBody))
+	})
+
+	suite.server = httptest.NewServer(suite.mux)
+	suite.Require().NotNil(suite.server)
+
+	as := auth.AuthServer{
+		URL:         suite.server.URL + "/gitea",
+		Name:        "Test Gitea",
+		Kind:        gits.KindGitea,
+		CurrentUser: userName,
+	}
+	ua := auth.UserAuth{
+		Username: userName,
+		ApiToken: "0123456789abdef",
+	}
+
+	gitProvider, err := gits.NewGiteaProvider(&as, &ua, nil)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(gitProvider)
+
+	var ok bool
+	suite.provider, ok = gitProvider.(*gits.GiteaProvider)
+	suite.Require().True(ok)
+}
+
+func (suite *GiteaProviderTestSuite) TearDownSuite() {
+	suite.server.Close()
+}
+
+func (suite *GiteaProviderTestSuite) TestCreateRepository() {
+	repo, err := suite.provider.CreateRepository(giteaOrg, "test-repo", false)
+
+	suite.Require().NoError(err)
+	suite.Equal("test-repo", repo.Name)
+	suite.Equal("https://example.com/gitea/test-org/test-repo.git", repo.CloneURL)
+}
+
+func (suite *GiteaProviderTestSuite) TestGetPullRequest() {
+	pr, err := suite.provider.GetPullRequest(giteaOrg, &gits.GitRepositoryInfo{Name: "test-repo"}, 1)
+
+	suite.Require().NoError(err)
+	suite.Equal("test-org", pr.Owner)
+	suite.Equal("https://example.com/gitea/test-org/test-repo/pulls/1", pr.URL)
+	suite.Equal("test-user@example.com", pr.Author.Email)
+	suite.True(*pr.Merged)
+	suite.True(pr.IsClosed())
+	suite.Equal("promote-test-app-1.0.1", *pr.HeadRef)
+	suite.Equal("d6f24ee03d76a2caf0a4e1975fb43e8f61759b9c", pr.LastCommitSha)
+	suite.Equal("b60280bc6e62e2f880f1b63c1e24987664d3bda3", *pr.MergeCommitSHA)
+}
+
+func (suite *GiteaProviderTestSuite) TestMergePullRequest() {
+	number := 1
+	pr := &gits.GitPullRequest{
+		Owner:  giteaOrg,
+		Repo:   "test-repo",
+		Number: &number,
+	}
+	err := suite.provider.MergePullRequest(pr, "Merged by jx")
+
+	suite.Require().NoError(err)
+	suite.Equal("merge", suite.mergeBody["Do"])
+	suite.Equal("Merged by jx", suite.mergeBody["MergeMessageField"])
+}
+
+func (suite *GiteaProviderTestSuite) TestIssueURL() {
+	url := suite.provider.IssueURL(giteaOrg, "test-repo", 1, true)
+
+	suite.Equal(suite.server.URL+"/gitea/test-org/test-repo/pulls/1", url)
+}
+
+func (suite *GiteaProviderTestSuite) TestCreateWebHook() {
+	err := suite.provider.CreateWebHook(&gits.GitWebHookArguments{
+		Owner:  giteaOrg,
+		Repo:   &gits.GitRepositoryInfo{Name: "test-repo"},
+		URL:    "http://hook.example.com/hook",
+		Secret: "secret",
+	})
+	suite.Require().NoError(err)
+
+	err = suite.provider.CreateWebHook(&gits.GitWebHookArguments{
+		Owner:  giteaOrg,
+		Repo:   &gits.GitRepositoryInfo{Name: "test-repo"},
+		URL:    "http://other.example.com/hook",
+		Secret: "secret",
+	})
+	suite.Require().NoError(err)
+}
+
+func (suite *GiteaProviderTestSuite) TestUpdateRelease() {
+	release := &gits.GitRelease{
+		Name:    "v1.0.1",
+		TagName: "v1.0.1",
+		Body:    "The changelog",
+	}
+	err := suite.provider.UpdateRelease(giteaOrg, "test-repo", "v1.0.1", release)
+	suite.Require().NoError(err)
+	suite.Equal(suite.server.URL+"/gitea/test-org/test-repo/releases/tag/v1.0.1", release.HTMLURL)
+
+	release = &gits.GitRelease{
+		Name:    "1.0.0",
+		TagName: "1.0.0",
+		Body:    "The changelog",
+	}
+	err = suite.provider.UpdateRelease(giteaOrg, "test-repo", "1.0.0", release)
+	suite.Require().NoError(err)
+	suite.Equal(suite.server.URL+"/gitea/test-org/test-repo/releases/tag/v1.0.0", release.HTMLURL)
+}
+
+func TestGiteaProviderTestSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping GiteaProviderTestSuite in short mode")
+	} else {
+		suite.Run(t, new(GiteaProviderTestSuite))
+	}
+}
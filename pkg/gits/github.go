@@ -12,6 +12,7 @@ import (
 	"github.com/jenkins-x/jx/pkg/auth"
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
 	"golang.org/x/oauth2"
 )
 
@@ -44,18 +45,45 @@ func NewGitHubProvider(server *auth.AuthServer, user *auth.UserAuth, git Gitter)
 		&oauth2.Token{AccessToken: user.ApiToken},
 	)
 	tc := oauth2.NewClient(ctx, ts)
+	tc.Transport = NewGitHubRateLimitTransport(tc.Transport)
 
 	var err error
-	u := server.URL
-	if IsGitHubServerURL(u) {
+	if server.APIURL == "" && IsGitHubServerURL(server.URL) {
 		provider.Client = github.NewClient(tc)
 	} else {
-		u = GitHubEnterpriseApiEndpointURL(u)
-		provider.Client, err = github.NewEnterpriseClient(u, u, tc)
+		u := GitHubServerAPIURL(server)
+		provider.Client, err = github.NewEnterpriseClient(u, GitHubEnterpriseUploadURL(u), tc)
 	}
 	return &provider, err
 }
 
+// GitHubServerAPIURL returns the API URL of the given GitHub server, which is either configured explicitly for
+// servers which host their API separately or derived from the server URL
+func GitHubServerAPIURL(server *auth.AuthServer) string {
+	if server.APIURL != "" {
+		return server.APIURL
+	}
+	return GitHubEnterpriseApiEndpointURL(server.URL)
+}
+
+// GitHubEnterpriseUploadURL returns the URL used to upload release assets for the given GitHub Enterprise API URL
+func GitHubEnterpriseUploadURL(apiURL string) string {
+	u := strings.TrimSuffix(apiURL, "/")
+	if strings.HasSuffix(u, "/api/v3") {
+		return strings.TrimSuffix(u, "/v3") + "/uploads/"
+	}
+	return u + "/"
+}
+
+// GitHubRawContentURL returns the URL of the raw content of a file in a GitHub repository. On github.com the raw
+// content is served from its own host whereas GitHub Enterprise serves it under the /raw path of the server
+func GitHubRawContentURL(serverURL string, org string, name string, ref string, path string) string {
+	if IsGitHubServerURL(serverURL) {
+		return util.UrlJoin("https://raw.githubusercontent.com", org, name, ref, path)
+	}
+	return util.UrlJoin(serverURL, "raw", org, name, ref, path)
+}
+
 func GitHubEnterpriseApiEndpointURL(u string) string {
 	if IsGitHubServerURL(u) {
 		return u
@@ -70,11 +98,16 @@ func GitHubEnterpriseApiEndpointURL(u string) string {
 // GetEnterpriseApiURL returns the github enterprise API URL or blank if this
 // provider is for the https://github.com service
 func (p *GitHubProvider) GetEnterpriseApiURL() string {
-	u := p.Server.URL
-	if IsGitHubServerURL(u) {
+	if p.Server.APIURL == "" && IsGitHubServerURL(p.Server.URL) {
 		return ""
 	}
-	return GitHubEnterpriseApiEndpointURL(u)
+	return GitHubServerAPIURL(&p.Server)
+}
+
+// RateLimits returns the current GitHub API rate limits of the user
+func (p *GitHubProvider) RateLimits() (*github.RateLimits, error) {
+	limits, _, err := p.Client.RateLimits(p.Context)
+	return limits, err
 }
 
 func IsGitHubServerURL(u string) bool {
@@ -336,6 +369,153 @@ func (p *GitHubProvider) CreateWebHook(data *GitWebHookArguments) error {
 	return err
 }
 
+func (p *GitHubProvider) ListWebHooks(owner string, repo string) ([]*GitWebHookArguments, error) {
+	if owner == "" {
+		owner = p.Username
+	}
+	answer := []*GitWebHookArguments{}
+	hooks, err := p.listHooks(owner, repo)
+	if err != nil {
+		return answer, err
+	}
+	for _, hook := range hooks {
+		s, _ := hook.Config["url"].(string)
+		answer = append(answer, &GitWebHookArguments{
+			ID:    hook.GetID(),
+			Owner: owner,
+			Repo:  &GitRepositoryInfo{Organisation: owner, Name: repo},
+			URL:   s,
+		})
+	}
+	return answer, nil
+}
+
+func (p *GitHubProvider) UpdateWebHook(data *GitWebHookArguments) error {
+	owner := data.Owner
+	if owner == "" {
+		owner = p.Username
+	}
+	config := map[string]interface{}{
+		"url":          data.URL,
+		"content_type": "json",
+	}
+	if data.Secret != "" {
+		config["secret"] = data.Secret
+	}
+	hook := &github.Hook{
+		Config: config,
+		Events: []string{"*"},
+	}
+	_, _, err := p.Client.Repositories.EditHook(p.Context, owner, data.Repo.Name, data.ID, hook)
+	return err
+}
+
+func (p *GitHubProvider) DeleteWebHook(owner string, repo string, webhookURL string) error {
+	if owner == "" {
+		owner = p.Username
+	}
+	hooks, err := p.listHooks(owner, repo)
+	if err != nil {
+		return err
+	}
+	for _, hook := range hooks {
+		s, _ := hook.Config["url"].(string)
+		if s == webhookURL {
+			_, err = p.Client.Repositories.DeleteHook(p.Context, owner, repo, hook.GetID())
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// gitHubHookDelivery a delivery of an event to a webhook
+type gitHubHookDelivery struct {
+	ID         int64  `json:"id"`
+	Event      string `json:"event"`
+	Status     string `json:"status"`
+	StatusCode int    `json:"status_code"`
+}
+
+// WebHookDeliveryTimeout how long to wait for the delivery of a ping event to a webhook
+var WebHookDeliveryTimeout = 30 * time.Second
+
+// PingWebHook sends a ping event to the webhook with the given ID and waits for its delivery, returning an error if
+// the receiver of the webhook did not accept it, e.g. as its secret differs
+func (p *GitHubProvider) PingWebHook(owner string, repo string, id int64) error {
+	if owner == "" {
+		owner = p.Username
+	}
+	previous, err := p.listHookDeliveries(owner, repo, id)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list the deliveries of the webhook %d", id)
+	}
+	seen := map[int64]bool{}
+	for _, d := range previous {
+		seen[d.ID] = true
+	}
+	_, err = p.Client.Repositories.PingHook(p.Context, owner, repo, id)
+	if err != nil {
+		return err
+	}
+	var delivery *gitHubHookDelivery
+	deadline := time.Now().Add(WebHookDeliveryTimeout)
+	for delivery == nil {
+		deliveries, err := p.listHookDeliveries(owner, repo, id)
+		if err != nil {
+			return errors.Wrapf(err, "failed to list the deliveries of the webhook %d", id)
+		}
+		for _, d := range deliveries {
+			if d.Event == "ping" && !seen[d.ID] {
+				delivery = d
+				break
+			}
+		}
+		if delivery == nil {
+			if time.Now().After(deadline) {
+				return fmt.Errorf("the ping event was not delivered to the webhook %d within %s", id, WebHookDeliveryTimeout)
+			}
+			time.Sleep(2 * time.Second)
+		}
+	}
+	if delivery.StatusCode < 200 || delivery.StatusCode >= 300 {
+		return fmt.Errorf("the ping event was rejected by the webhook %d with status %d %s", id, delivery.StatusCode, delivery.Status)
+	}
+	return nil
+}
+
+// listHookDeliveries returns the most recent deliveries of events to the webhook with the given ID
+func (p *GitHubProvider) listHookDeliveries(owner string, repo string, id int64) ([]*gitHubHookDelivery, error) {
+	req, err := p.Client.NewRequest("GET", fmt.Sprintf("repos/%s/%s/hooks/%d/deliveries", owner, repo, id), nil)
+	if err != nil {
+		return nil, err
+	}
+	deliveries := []*gitHubHookDelivery{}
+	_, err = p.Client.Do(p.Context, req, &deliveries)
+	return deliveries, err
+}
+
+func (p *GitHubProvider) listHooks(owner string, repo string) ([]*github.Hook, error) {
+	answer := []*github.Hook{}
+	options := &github.ListOptions{
+		Page:    0,
+		PerPage: pageSize,
+	}
+	for {
+		hooks, _, err := p.Client.Repositories.ListHooks(p.Context, owner, repo, options)
+		if err != nil {
+			return answer, err
+		}
+		answer = append(answer, hooks...)
+		if len(hooks) < pageSize || len(hooks) == 0 {
+			break
+		}
+		options.Page += 1
+	}
+	return answer, nil
+}
+
 func (p *GitHubProvider) CreatePullRequest(data *GitPullRequestArguments) (*GitPullRequest, error) {
 	owner := data.GitRepositoryInfo.Organisation
 	repo := data.GitRepositoryInfo.Name
@@ -543,6 +723,50 @@ func (p *GitHubProvider) AddPRComment(pr *GitPullRequest, comment string) error
 	return nil
 }
 
+func (p *GitHubProvider) ListPRComments(pr *GitPullRequest) ([]*GitPRComment, error) {
+	answer := []*GitPRComment{}
+	if pr.Number == nil {
+		return answer, fmt.Errorf("Missing Number for GitPullRequest %#v", pr)
+	}
+	options := &github.IssueListCommentsOptions{
+		ListOptions: github.ListOptions{
+			PerPage: pageSize,
+		},
+	}
+	for {
+		comments, resp, err := p.Client.Issues.ListComments(p.Context, pr.Owner, pr.Repo, *pr.Number, options)
+		if err != nil {
+			return answer, err
+		}
+		for _, comment := range comments {
+			prComment := &GitPRComment{
+				ID:        comment.GetID(),
+				Body:      asText(comment.Body),
+				CreatedAt: comment.CreatedAt,
+			}
+			if comment.User != nil {
+				prComment.User = GitUser{
+					Login: asText(comment.User.Login),
+					Name:  asText(comment.User.Name),
+				}
+			}
+			answer = append(answer, prComment)
+		}
+		if resp == nil || resp.NextPage == 0 {
+			return answer, nil
+		}
+		options.Page = resp.NextPage
+	}
+}
+
+func (p *GitHubProvider) EditPRComment(pr *GitPullRequest, comment *GitPRComment, body string) error {
+	prComment := &github.IssueComment{
+		Body: &body,
+	}
+	_, _, err := p.Client.Issues.EditComment(p.Context, pr.Owner, pr.Repo, comment.ID, prComment)
+	return err
+}
+
 func (p *GitHubProvider) CreateIssueComment(owner string, repo string, number int, comment string) error {
 	issueComment := &github.IssueComment{
 		Body: &comment,
@@ -591,6 +815,27 @@ func (p *GitHubProvider) ListCommitStatus(org string, repo string, sha string) (
 	return answer, nil
 }
 
+func (p *GitHubProvider) UpdateCommitStatus(org string, repo string, sha string, status *GitRepoStatus) (*GitRepoStatus, error) {
+	repoStatus := &github.RepoStatus{
+		State:       github.String(status.State),
+		TargetURL:   github.String(status.TargetURL),
+		Description: github.String(status.Description),
+		Context:     github.String(status.Context),
+	}
+	result, _, err := p.Client.Repositories.CreateStatus(p.Context, org, repo, sha, repoStatus)
+	if err != nil {
+		return nil, err
+	}
+	return &GitRepoStatus{
+		ID:          fmt.Sprintf("%d", result.GetID()),
+		Context:     result.GetContext(),
+		URL:         result.GetURL(),
+		TargetURL:   result.GetTargetURL(),
+		State:       result.GetState(),
+		Description: result.GetDescription(),
+	}, nil
+}
+
 func notNullInt64(n *int64) int64 {
 	if n != nil {
 		return *n
@@ -647,7 +892,7 @@ func (p *GitHubProvider) UpdateRelease(owner string, repo string, tag string, re
 		vtag := "v" + tag
 
 		rel2, r2, err2 := p.Client.Repositories.GetReleaseByTag(p.Context, owner, repo, vtag)
-		if r2.StatusCode != 404 {
+		if r2 != nil && r2.StatusCode != 404 {
 			rel = rel2
 			r = r2
 			err = err2
@@ -670,19 +915,25 @@ func (p *GitHubProvider) UpdateRelease(owner string, repo string, tag string, re
 	}
 	if r != nil && r.StatusCode == 404 {
 		log.Warnf("No release found for %s/%s and tag %s so creating a new release\n", owner, repo, tag)
-		_, _, err = p.Client.Repositories.CreateRelease(p.Context, owner, repo, release)
-		return err
+		created, _, err := p.Client.Repositories.CreateRelease(p.Context, owner, repo, release)
+		if err != nil {
+			return err
+		}
+		releaseInfo.URL = asText(created.URL)
+		releaseInfo.HTMLURL = asText(created.HTMLURL)
+		return nil
 	}
 	id := release.ID
 	if id == nil {
 		return fmt.Errorf("The release for %s/%s tag %s has no ID!", owner, repo, tag)
 	}
 	r2, _, err := p.Client.Repositories.EditRelease(p.Context, owner, repo, *id, release)
-	if r != nil {
-		releaseInfo.URL = asText(r2.URL)
-		releaseInfo.HTMLURL = asText(r2.HTMLURL)
+	if err != nil {
+		return err
 	}
-	return err
+	releaseInfo.URL = asText(r2.URL)
+	releaseInfo.HTMLURL = asText(r2.HTMLURL)
+	return nil
 }
 
 func (p *GitHubProvider) GetIssue(org string, name string, number int) (*GitIssue, error) {
@@ -867,7 +1118,7 @@ func GitHubAccessTokenURL(url string) string {
 	if strings.Index(url, "://") < 0 {
 		url = "https://" + url
 	}
-	return util.UrlJoin(url, "/settings/tokens/new?scopes=repo,read:user,read:org,user:email,write:repo_hook,delete_repo")
+	return util.UrlJoin(url, "/settings/tokens/new?scopes="+strings.Join(GitHubTokenScopes, ","))
 }
 
 func (p *GitHubProvider) Label() string {
@@ -879,7 +1130,12 @@ func (p *GitHubProvider) ServerURL() string {
 }
 
 func (p *GitHubProvider) BranchArchiveURL(org string, name string, branch string) string {
-	return util.UrlJoin(p.ServerURL(), org, name, "archive", branch+".zip")
+	u := p.GetEnterpriseApiURL()
+	if u != "" {
+		// the archive links of the GitHub Enterprise web UI do not accept API tokens so use the API instead
+		return util.UrlJoin(u, "repos", org, name, "zipball", branch)
+	}
+	return util.UrlJoin(GitHubURL, org, name, "archive", branch+".zip")
 }
 
 func (p *GitHubProvider) CurrentUsername() string {
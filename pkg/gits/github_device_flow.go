@@ -0,0 +1,286 @@
+package gits
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/github"
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+const (
+	// GitHubOAuthClientIDEnvVar the environment variable of the client ID of the OAuth App used for the device flow
+	GitHubOAuthClientIDEnvVar = "JX_GITHUB_OAUTH_CLIENT_ID"
+
+	gitHubDeviceGrantType     = "urn:ietf:params:oauth:grant-type:device_code"
+	gitHubOAuthScopesHeader   = "X-OAuth-Scopes"
+	gitHubDefaultPollInterval = 5 * time.Second
+)
+
+// GitHubTokenScopes the scopes of the API tokens jx needs on GitHub
+var GitHubTokenScopes = []string{"repo", "read:user", "read:org", "user:email", "write:repo_hook", "delete_repo"}
+
+// gitHubImpliedScopes the scopes which include other scopes
+var gitHubImpliedScopes = map[string][]string{
+	"read:user":       {"user"},
+	"user:email":      {"user"},
+	"read:org":        {"write:org", "admin:org"},
+	"write:repo_hook": {"admin:repo_hook"},
+	"read:repo_hook":  {"write:repo_hook", "admin:repo_hook"},
+}
+
+// ErrGitHubDeviceFlowNotSupported is returned when the GitHub server or OAuth App does not support the device flow
+var ErrGitHubDeviceFlowNotSupported = errors.New("the GitHub server does not support the OAuth device flow")
+
+// GitHubDeviceCode the code the user enters at the verification URL to authorize the device flow
+type GitHubDeviceCode struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURI string `json:"verification_uri"`
+	ExpiresIn       int    `json:"expires_in"`
+	Interval        int    `json:"interval"`
+}
+
+type gitHubDeviceTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	Scope            string `json:"scope"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+	Interval         int    `json:"interval"`
+}
+
+// GitHubDeviceFlow obtains an API token for a user of a GitHub server via the OAuth device flow in which the user
+// authorizes jx in their browser instead of creating and pasting a personal access token
+type GitHubDeviceFlow struct {
+	ServerURL  string
+	ClientID   string
+	HTTPClient *http.Client
+
+	// Sleep and Now can be replaced in tests
+	Sleep func(d time.Duration)
+	Now   func() time.Time
+}
+
+// NewGitHubDeviceFlow creates the device flow for the GitHub server using the given OAuth App
+func NewGitHubDeviceFlow(serverURL string, clientID string) *GitHubDeviceFlow {
+	return &GitHubDeviceFlow{
+		ServerURL:  serverURL,
+		ClientID:   clientID,
+		HTTPClient: http.DefaultClient,
+		Sleep:      time.Sleep,
+		Now:        time.Now,
+	}
+}
+
+// RequestCode requests the code the user has to enter to authorize a token with the given scopes
+func (f *GitHubDeviceFlow) RequestCode(scopes []string) (*GitHubDeviceCode, error) {
+	code := &GitHubDeviceCode{}
+	status, err := f.post("login/device/code", url.Values{
+		"client_id": {f.ClientID},
+		"scope":     {strings.Join(scopes, " ")},
+	}, code)
+	if err != nil {
+		return nil, err
+	}
+	if status >= http.StatusInternalServerError {
+		return nil, fmt.Errorf("failed to request a device code from %s: status %d", f.ServerURL, status)
+	}
+	if status != http.StatusOK || code.DeviceCode == "" {
+		// older GitHub Enterprise servers and OAuth Apps without the device flow enabled reject the request
+		return nil, ErrGitHubDeviceFlowNotSupported
+	}
+	return code, nil
+}
+
+// PollAccessToken waits until the user authorized the code and returns the API token
+func (f *GitHubDeviceFlow) PollAccessToken(code *GitHubDeviceCode) (string, error) {
+	interval := time.Duration(code.Interval) * time.Second
+	if interval <= 0 {
+		interval = gitHubDefaultPollInterval
+	}
+	expires := f.Now().Add(time.Duration(code.ExpiresIn) * time.Second)
+	for {
+		f.Sleep(interval)
+		if code.ExpiresIn > 0 && f.Now().After(expires) {
+			return "", fmt.Errorf("the code %s expired before it was authorized", code.UserCode)
+		}
+		resp := &gitHubDeviceTokenResponse{}
+		_, err := f.post("login/oauth/access_token", url.Values{
+			"client_id":   {f.ClientID},
+			"device_code": {code.DeviceCode},
+			"grant_type":  {gitHubDeviceGrantType},
+		}, resp)
+		if err != nil {
+			return "", err
+		}
+		switch resp.Error {
+		case "":
+			if resp.AccessToken == "" {
+				return "", fmt.Errorf("no access token was returned for the code %s", code.UserCode)
+			}
+			return resp.AccessToken, nil
+		case "authorization_pending":
+		case "slow_down":
+			if resp.Interval > 0 {
+				interval = time.Duration(resp.Interval) * time.Second
+			} else {
+				interval += 5 * time.Second
+			}
+		case "expired_token":
+			return "", fmt.Errorf("the code %s expired before it was authorized", code.UserCode)
+		case "access_denied":
+			return "", fmt.Errorf("the authorization of the code %s was denied", code.UserCode)
+		case "device_flow_disabled", "unsupported_grant_type", "incorrect_client_credentials":
+			return "", ErrGitHubDeviceFlowNotSupported
+		default:
+			return "", fmt.Errorf("failed to get an access token: %s %s", resp.Error, resp.ErrorDescription)
+		}
+	}
+}
+
+func (f *GitHubDeviceFlow) post(path string, values url.Values, result interface{}) (int, error) {
+	u := util.UrlJoin(f.ServerURL, path)
+	req, err := http.NewRequest(http.MethodPost, u, strings.NewReader(values.Encode()))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	resp, err := f.HTTPClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, nil
+	}
+	err = json.NewDecoder(resp.Body).Decode(result)
+	if err != nil {
+		return resp.StatusCode, fmt.Errorf("failed to parse the response of %s: %s", u, err)
+	}
+	return resp.StatusCode, nil
+}
+
+// GitHubOAuthClientID returns the client ID of the OAuth App used for the device flow of the server
+func GitHubOAuthClientID(server *auth.AuthServer) string {
+	clientID := os.Getenv(GitHubOAuthClientIDEnvVar)
+	if clientID != "" {
+		return clientID
+	}
+	return server.OAuthClientID
+}
+
+// IsGitHubServer returns true if the server is github.com or a GitHub Enterprise server
+func IsGitHubServer(server *auth.AuthServer) bool {
+	return server.Kind == KindGitHub || (server.Kind == "" && IsGitHubServerURL(server.URL))
+}
+
+// LoginWithGitHubDeviceFlow logs the user into the GitHub server via the OAuth device flow, setting the user name and
+// API token of the user auth. It returns false without an error if the device flow cannot be used so that the caller
+// can fall back to asking for a personal access token
+func LoginWithGitHubDeviceFlow(server *auth.AuthServer, userAuth *auth.UserAuth, batchMode bool, out io.Writer) (bool, error) {
+	if batchMode || !IsGitHubServer(server) {
+		return false, nil
+	}
+	clientID := GitHubOAuthClientID(server)
+	if clientID == "" {
+		return false, nil
+	}
+	flow := NewGitHubDeviceFlow(server.URL, clientID)
+	code, err := flow.RequestCode(GitHubTokenScopes)
+	if err == ErrGitHubDeviceFlowNotSupported {
+		log.Warnf("%s does not support the OAuth device flow, please create an API token instead\n", server.Label())
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	fmt.Fprintf(out, "To authorize jx on %s open %s and enter the code %s\n", server.Label(), util.ColorInfo(code.VerificationURI), util.ColorInfo(code.UserCode))
+	fmt.Fprintf(out, "Waiting for the authorization...\n")
+	token, err := flow.PollAccessToken(code)
+	if err == ErrGitHubDeviceFlowNotSupported {
+		log.Warnf("The OAuth device flow is not enabled for the OAuth App %s, please create an API token instead\n", clientID)
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	login, err := ValidateGitHubToken(server, token)
+	if err != nil {
+		return false, err
+	}
+	userAuth.Username = login
+	userAuth.ApiToken = token
+	return true, nil
+}
+
+// ValidateGitHubToken checks that the API token has the scopes jx needs and can access the webhooks of the
+// repositories of its user, returning the login of the user
+func ValidateGitHubToken(server *auth.AuthServer, token string) (string, error) {
+	gitProvider, err := NewGitHubProvider(server, &auth.UserAuth{ApiToken: token}, nil)
+	if err != nil {
+		return "", err
+	}
+	provider := gitProvider.(*GitHubProvider)
+	user, resp, err := provider.Client.Users.Get(provider.Context, "")
+	if err != nil {
+		return "", fmt.Errorf("the API token is not valid for %s: %s", server.Label(), err)
+	}
+	// fine-grained tokens have no scopes header so only the test call below can check them
+	if scopes, ok := resp.Header[http.CanonicalHeaderKey(gitHubOAuthScopesHeader)]; ok {
+		missing := MissingGitHubScopes(strings.Join(scopes, ","), GitHubTokenScopes)
+		if len(missing) > 0 {
+			return "", fmt.Errorf("the API token is missing the scopes: %s", strings.Join(missing, ", "))
+		}
+	}
+	repos, _, err := provider.Client.Repositories.List(provider.Context, "", &github.RepositoryListOptions{
+		Affiliation: "owner",
+		ListOptions: github.ListOptions{PerPage: 1},
+	})
+	if err != nil {
+		return "", fmt.Errorf("the API token cannot list the repositories of %s: %s", user.GetLogin(), err)
+	}
+	if len(repos) > 0 {
+		repo := repos[0]
+		_, _, err = provider.Client.Repositories.ListHooks(provider.Context, repo.GetOwner().GetLogin(), repo.GetName(), nil)
+		if err != nil {
+			return "", fmt.Errorf("the API token cannot access the webhooks of %s: %s", repo.GetFullName(), err)
+		}
+	}
+	return user.GetLogin(), nil
+}
+
+// MissingGitHubScopes returns the required scopes which are neither in the comma separated list of granted scopes
+// nor included in any of the granted scopes
+func MissingGitHubScopes(granted string, required []string) []string {
+	grantedScopes := map[string]bool{}
+	for _, scope := range strings.Split(granted, ",") {
+		grantedScopes[strings.TrimSpace(scope)] = true
+	}
+	missing := []string{}
+	for _, scope := range required {
+		if grantedScopes[scope] {
+			continue
+		}
+		found := false
+		for _, implying := range gitHubImpliedScopes[scope] {
+			if grantedScopes[implying] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
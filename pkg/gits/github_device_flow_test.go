@@ -0,0 +1,183 @@
+package gits_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// deviceFlowServer replies to the device code request and then with the given token responses in turn
+func deviceFlowServer(t *testing.T, tokenResponses []map[string]interface{}) *httptest.Server {
+	count := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login/device/code", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "test-client", r.FormValue("client_id"))
+		assert.Equal(t, "repo read:user", r.FormValue("scope"))
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"device_code":      "device-123",
+			"user_code":        "ABCD-1234",
+			"verification_uri": "https://github.com/login/device",
+			"expires_in":       900,
+			"interval":         5,
+		})
+	})
+	mux.HandleFunc("/login/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "device-123", r.FormValue("device_code"))
+		assert.Equal(t, "urn:ietf:params:oauth:grant-type:device_code", r.FormValue("grant_type"))
+		require.True(t, count < len(tokenResponses), "too many token requests")
+		json.NewEncoder(w).Encode(tokenResponses[count])
+		count++
+	})
+	return httptest.NewServer(mux)
+}
+
+func newTestDeviceFlow(serverURL string, sleeps *[]time.Duration) *gits.GitHubDeviceFlow {
+	now := time.Unix(1500000000, 0)
+	flow := gits.NewGitHubDeviceFlow(serverURL, "test-client")
+	flow.Now = func() time.Time {
+		return now
+	}
+	flow.Sleep = func(d time.Duration) {
+		*sleeps = append(*sleeps, d)
+		now = now.Add(d)
+	}
+	return flow
+}
+
+func TestGitHubDeviceFlow(t *testing.T) {
+	t.Parallel()
+	server := deviceFlowServer(t, []map[string]interface{}{
+		{"error": "authorization_pending"},
+		{"error": "slow_down", "interval": 10},
+		{"access_token": "gho_token", "scope": "repo,read:user"},
+	})
+	defer server.Close()
+
+	sleeps := []time.Duration{}
+	flow := newTestDeviceFlow(server.URL, &sleeps)
+	code, err := flow.RequestCode([]string{"repo", "read:user"})
+	require.NoError(t, err)
+	assert.Equal(t, "ABCD-1234", code.UserCode)
+	assert.Equal(t, "https://github.com/login/device", code.VerificationURI)
+
+	token, err := flow.PollAccessToken(code)
+	require.NoError(t, err)
+	assert.Equal(t, "gho_token", token)
+	assert.Equal(t, []time.Duration{5 * time.Second, 5 * time.Second, 10 * time.Second}, sleeps)
+}
+
+func TestGitHubDeviceFlowDenied(t *testing.T) {
+	t.Parallel()
+	server := deviceFlowServer(t, []map[string]interface{}{
+		{"error": "authorization_pending"},
+		{"error": "access_denied"},
+	})
+	defer server.Close()
+
+	sleeps := []time.Duration{}
+	flow := newTestDeviceFlow(server.URL, &sleeps)
+	code, err := flow.RequestCode([]string{"repo", "read:user"})
+	require.NoError(t, err)
+
+	_, err = flow.PollAccessToken(code)
+	assert.EqualError(t, err, "the authorization of the code ABCD-1234 was denied")
+}
+
+func TestGitHubDeviceFlowExpires(t *testing.T) {
+	t.Parallel()
+	server := deviceFlowServer(t, []map[string]interface{}{})
+	defer server.Close()
+
+	sleeps := []time.Duration{}
+	flow := newTestDeviceFlow(server.URL, &sleeps)
+	code, err := flow.RequestCode([]string{"repo", "read:user"})
+	require.NoError(t, err)
+	code.ExpiresIn = 3
+
+	_, err = flow.PollAccessToken(code)
+	assert.EqualError(t, err, "the code ABCD-1234 expired before it was authorized")
+}
+
+func TestGitHubDeviceFlowNotSupported(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	sleeps := []time.Duration{}
+	_, err := newTestDeviceFlow(server.URL, &sleeps).RequestCode(gits.GitHubTokenScopes)
+	assert.Equal(t, gits.ErrGitHubDeviceFlowNotSupported, err)
+}
+
+func TestLoginWithGitHubDeviceFlowFallsBack(t *testing.T) {
+	t.Parallel()
+	userAuth := &auth.UserAuth{}
+	server := &auth.AuthServer{URL: "https://github.com", Kind: gits.KindGitHub}
+
+	loggedIn, err := gits.LoginWithGitHubDeviceFlow(server, userAuth, false, nil)
+	assert.NoError(t, err)
+	assert.False(t, loggedIn, "the device flow needs an OAuth App")
+
+	server.OAuthClientID = "test-client"
+	loggedIn, err = gits.LoginWithGitHubDeviceFlow(server, userAuth, true, nil)
+	assert.NoError(t, err)
+	assert.False(t, loggedIn, "the device flow needs a user in batch mode")
+
+	loggedIn, err = gits.LoginWithGitHubDeviceFlow(&auth.AuthServer{URL: "https://gitlab.com", Kind: gits.KindGitlab, OAuthClientID: "test-client"}, userAuth, false, nil)
+	assert.NoError(t, err)
+	assert.False(t, loggedIn, "the device flow is only supported on GitHub")
+	assert.True(t, userAuth.IsInvalid())
+}
+
+func TestMissingGitHubScopes(t *testing.T) {
+	t.Parallel()
+	assert.Empty(t, gits.MissingGitHubScopes("repo, read:user, read:org, user:email, write:repo_hook, delete_repo", gits.GitHubTokenScopes))
+	assert.Empty(t, gits.MissingGitHubScopes("admin:org, admin:repo_hook, delete_repo, repo, user", gits.GitHubTokenScopes))
+	assert.Equal(t, []string{"write:repo_hook", "delete_repo"}, gits.MissingGitHubScopes("repo, user, read:org", gits.GitHubTokenScopes))
+}
+
+func TestValidateGitHubToken(t *testing.T) {
+	t.Parallel()
+	scopes := "repo, user, read:org, admin:repo_hook, delete_repo"
+	hooksStatus := http.StatusOK
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/user", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-OAuth-Scopes", scopes)
+		w.Write([]byte(`{"login": "test-user"}`))
+	})
+	mux.HandleFunc("/api/v3/user/repos", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "owner", r.URL.Query().Get("affiliation"))
+		w.Write([]byte(`[{"name": "test-repo", "full_name": "test-user/test-repo", "owner": {"login": "test-user"}}]`))
+	})
+	mux.HandleFunc("/api/v3/repos/test-user/test-repo/hooks", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(hooksStatus)
+		if hooksStatus == http.StatusOK {
+			w.Write([]byte(`[]`))
+		} else {
+			w.Write([]byte(`{"message": "Not Found"}`))
+		}
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+	server := &auth.AuthServer{URL: testServer.URL, Kind: gits.KindGitHub}
+
+	login, err := gits.ValidateGitHubToken(server, "gho_token")
+	require.NoError(t, err)
+	assert.Equal(t, "test-user", login)
+
+	hooksStatus = http.StatusNotFound
+	_, err = gits.ValidateGitHubToken(server, "gho_token")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot access the webhooks of test-user/test-repo")
+
+	scopes = "repo, user"
+	_, err = gits.ValidateGitHubToken(server, "gho_token")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing the scopes: read:org, write:repo_hook, delete_repo")
+}
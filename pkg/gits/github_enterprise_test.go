@@ -0,0 +1,160 @@
+package gits_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type GitHubEnterpriseProviderTestSuite struct {
+	suite.Suite
+	mux      *http.ServeMux
+	server   *httptest.Server
+	provider *gits.GitHubProvider
+}
+
+var gitHubEnterpriseRouter = util.Router{
+	"/api/v3/repos/test-org/test-repo": util.MethodMap{
+		"GET": "repo.json",
+	},
+	"/api/v3/repos/test-org/test-repo/releases/tags/v1.0.0": util.MethodMap{
+		"GET": "release.json",
+	},
+	"/api/v3/repos/test-org/test-repo/releases/1": util.MethodMap{
+		"PATCH": "release.json",
+	},
+	"/api/v3/repos/test-org/test-repo/releases": util.MethodMap{
+		"POST": "created-release.json",
+	},
+}
+
+func (suite *GitHubEnterpriseProviderTestSuite) SetupSuite() {
+	suite.mux = http.NewServeMux()
+
+	for path, methodMap := range gitHubEnterpriseRouter {
+		suite.mux.HandleFunc(path, util.GetMockAPIResponseFromFile("test_data/github_enterprise", methodMap))
+	}
+	suite.mux.HandleFunc("/api/v3/repos/sso-org/test-repo", func(w http.ResponseWriter, r *http.Request) {
+		data, err := util.LoadBytes("test_data/github_enterprise", "sso-required.json")
+		suite.Require().NoError(err)
+		w.Header().Set("X-GitHub-SSO", "required; url="+suite.server.URL+"/orgs/sso-org/sso?authorization_request=ABC123")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write(data)
+	})
+
+	suite.server = httptest.NewServer(suite.mux)
+	suite.Require().NotNil(suite.server)
+
+	suite.provider = suite.createProvider(&auth.AuthServer{
+		URL:  suite.server.URL,
+		Name: "Test GitHub Enterprise",
+		Kind: gits.KindGitHub,
+	})
+}
+
+func (suite *GitHubEnterpriseProviderTestSuite) createProvider(server *auth.AuthServer) *gits.GitHubProvider {
+	ua := auth.UserAuth{
+		Username: userName,
+		ApiToken: "0123456789abdef",
+	}
+	gitProvider, err := gits.NewGitHubProvider(server, &ua, nil)
+	suite.Require().NoError(err)
+	provider, ok := gitProvider.(*gits.GitHubProvider)
+	suite.Require().True(ok)
+	return provider
+}
+
+func (suite *GitHubEnterpriseProviderTestSuite) TearDownSuite() {
+	suite.server.Close()
+}
+
+func (suite *GitHubEnterpriseProviderTestSuite) TestEnterpriseURLs() {
+	suite.Equal(suite.server.URL+"/api/v3/", suite.provider.Client.BaseURL.String())
+	suite.Equal(suite.server.URL+"/api/uploads/", suite.provider.Client.UploadURL.String())
+	suite.Equal(suite.server.URL+"/api/v3/", suite.provider.GetEnterpriseApiURL())
+	suite.Equal(suite.server.URL+"/api/v3/repos/test-org/test-repo/zipball/master", suite.provider.BranchArchiveURL("test-org", "test-repo", "master"))
+	suite.Equal(suite.server.URL+"/raw/test-org/test-repo/v1.0.0/charts/test-repo/values.yaml", gits.GitHubRawContentURL(suite.provider.ServerURL(), "test-org", "test-repo", "v1.0.0", "charts/test-repo/values.yaml"))
+}
+
+func (suite *GitHubEnterpriseProviderTestSuite) TestGetRepository() {
+	repo, err := suite.provider.GetRepository("test-org", "test-repo")
+
+	suite.Require().NoError(err)
+	suite.Equal("test-repo", repo.Name)
+	suite.Equal("https://git.corp.example.com/test-org/test-repo.git", repo.CloneURL)
+}
+
+func (suite *GitHubEnterpriseProviderTestSuite) TestSplitHostAPIURL() {
+	provider := suite.createProvider(&auth.AuthServer{
+		URL:    "https://git.corp.example.com",
+		APIURL: suite.server.URL + "/api/v3",
+		Kind:   gits.KindGitHub,
+	})
+	suite.Equal(suite.server.URL+"/api/uploads/", provider.Client.UploadURL.String())
+	suite.Equal(suite.server.URL+"/api/v3/repos/test-org/test-repo/zipball/master", provider.BranchArchiveURL("test-org", "test-repo", "master"))
+	suite.Equal("https://git.corp.example.com/raw/test-org/test-repo/master/README.md", gits.GitHubRawContentURL(provider.ServerURL(), "test-org", "test-repo", "master", "README.md"))
+
+	repo, err := provider.GetRepository("test-org", "test-repo")
+	suite.Require().NoError(err)
+	suite.Equal("test-repo", repo.Name)
+}
+
+func (suite *GitHubEnterpriseProviderTestSuite) TestUpdateExistingRelease() {
+	release := &gits.GitRelease{
+		Name:    "1.0.0",
+		TagName: "1.0.0",
+		Body:    "The changelog",
+	}
+	err := suite.provider.UpdateRelease("test-org", "test-repo", "1.0.0", release)
+
+	suite.Require().NoError(err)
+	suite.Equal("https://git.corp.example.com/test-org/test-repo/releases/tag/v1.0.0", release.HTMLURL)
+	suite.Equal("https://git.corp.example.com/api/v3/repos/test-org/test-repo/releases/1", release.URL)
+}
+
+func (suite *GitHubEnterpriseProviderTestSuite) TestCreateRelease() {
+	release := &gits.GitRelease{
+		Name:    "1.0.1",
+		TagName: "1.0.1",
+		Body:    "The changelog",
+	}
+	err := suite.provider.UpdateRelease("test-org", "test-repo", "1.0.1", release)
+
+	suite.Require().NoError(err)
+	suite.Equal("https://git.corp.example.com/test-org/test-repo/releases/tag/1.0.1", release.HTMLURL)
+}
+
+func (suite *GitHubEnterpriseProviderTestSuite) TestTokenNotAuthorizedForSSO() {
+	_, err := suite.provider.GetRepository("sso-org", "test-repo")
+
+	suite.Require().Error(err)
+	suite.Contains(err.Error(), "not authorized for the SAML single sign-on")
+	suite.Contains(err.Error(), suite.server.URL+"/orgs/sso-org/sso?authorization_request=ABC123")
+}
+
+func TestGitHubEnterpriseProviderTestSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping GitHubEnterpriseProviderTestSuite in short mode")
+	} else {
+		suite.Run(t, new(GitHubEnterpriseProviderTestSuite))
+	}
+}
+
+func TestGitHubURLs(t *testing.T) {
+	t.Parallel()
+	gitProvider, err := gits.NewGitHubProvider(&auth.AuthServer{URL: "https://github.com", Kind: gits.KindGitHub}, &auth.UserAuth{Username: userName}, nil)
+	assert.NoError(t, err)
+	provider := gitProvider.(*gits.GitHubProvider)
+
+	assert.Equal(t, "", provider.GetEnterpriseApiURL())
+	assert.Equal(t, "https://uploads.github.com/", provider.Client.UploadURL.String())
+	assert.Equal(t, "https://github.com/test-org/test-repo/archive/master.zip", provider.BranchArchiveURL("test-org", "test-repo", "master"))
+	assert.Equal(t, "https://raw.githubusercontent.com/test-org/test-repo/master/README.md", gits.GitHubRawContentURL(provider.ServerURL(), "test-org", "test-repo", "master", "README.md"))
+	assert.Equal(t, "https://git.corp.example.com/api/uploads/", gits.GitHubEnterpriseUploadURL("https://git.corp.example.com/api/v3/"))
+}
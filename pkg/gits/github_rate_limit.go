@@ -0,0 +1,261 @@
+package gits
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"golang.org/x/time/rate"
+)
+
+const (
+	gitHubRateLimitRemainingHeader = "X-RateLimit-Remaining"
+	gitHubRateLimitResetHeader     = "X-RateLimit-Reset"
+	retryAfterHeader               = "Retry-After"
+	gitHubSSOHeader                = "X-GitHub-SSO"
+
+	// DefaultGitHubMaxRetries the default number of times a rate limited GitHub API request is retried
+	DefaultGitHubMaxRetries = 5
+
+	gitHubInitialBackoff = time.Second
+	gitHubMaxBackoff     = 2 * time.Minute
+)
+
+var (
+	gitAPILimiter     *rate.Limiter
+	gitAPILimiterLock sync.RWMutex
+)
+
+// SetGitAPIQPS caps the number of requests per second made to the GitHub API. A value of zero or less removes the cap
+func SetGitAPIQPS(qps float64) {
+	gitAPILimiterLock.Lock()
+	defer gitAPILimiterLock.Unlock()
+	if qps <= 0 {
+		gitAPILimiter = nil
+		return
+	}
+	gitAPILimiter = rate.NewLimiter(rate.Limit(qps), 1)
+}
+
+func waitForGitAPILimiter(ctx context.Context) error {
+	gitAPILimiterLock.RLock()
+	limiter := gitAPILimiter
+	gitAPILimiterLock.RUnlock()
+	if limiter == nil {
+		return nil
+	}
+	return limiter.Wait(ctx)
+}
+
+// GitHubRateLimitTransport retries the GitHub API requests which are rejected by the primary or secondary rate limits.
+// When the primary rate limit is exhausted it waits until the limit resets, secondary (abuse) rate limits are retried
+// after the Retry-After delay or with an exponential backoff. Requests rejected because the token is not authorized for
+// the single sign-on of an organisation fail with a GitHubSSOAuthorizationError
+type GitHubRateLimitTransport struct {
+	Base       http.RoundTripper
+	MaxRetries int
+
+	// Sleep and Now can be replaced in tests
+	Sleep func(d time.Duration)
+	Now   func() time.Time
+}
+
+// NewGitHubRateLimitTransport creates a rate limit aware transport which sends its requests via the given transport
+func NewGitHubRateLimitTransport(base http.RoundTripper) *GitHubRateLimitTransport {
+	return &GitHubRateLimitTransport{
+		Base:       base,
+		MaxRetries: DefaultGitHubMaxRetries,
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *GitHubRateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	backoff := gitHubInitialBackoff
+	for attempt := 0; ; attempt++ {
+		err := waitForGitAPILimiter(req.Context())
+		if err != nil {
+			return nil, err
+		}
+		resp, err := t.base().RoundTrip(req)
+		if err != nil {
+			return resp, err
+		}
+		ssoURL := gitHubSSOAuthorizationURL(resp)
+		if ssoURL != "" {
+			resp.Body.Close()
+			return nil, &GitHubSSOAuthorizationError{AuthorizationURL: ssoURL}
+		}
+		if attempt >= t.MaxRetries {
+			return resp, nil
+		}
+		delay, exhausted := t.retryDelay(resp)
+		if delay < 0 {
+			if resp.Header.Get(gitHubRateLimitRemainingHeader) == "0" {
+				// the go-github client fails any further requests until the reset once it sees an exhausted
+				// limit, so hide the reset to let the next request through to wait for the reset here instead
+				resp.Header.Del(gitHubRateLimitResetHeader)
+			}
+			return resp, nil
+		}
+		if req.Body != nil && req.GetBody == nil {
+			// the request body cannot be sent again
+			return resp, nil
+		}
+		if delay == 0 {
+			delay = backoff + time.Duration(rand.Int63n(int64(backoff)))
+			backoff *= 2
+			if backoff > gitHubMaxBackoff {
+				backoff = gitHubMaxBackoff
+			}
+		}
+		if exhausted {
+			log.Warnf("The GitHub API rate limit is exhausted, waiting %s until it resets\n", util.ColorInfo(delay))
+		} else {
+			log.Warnf("The GitHub API secondary rate limit was hit, retrying in %s\n", util.ColorInfo(delay))
+		}
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+		t.sleep(delay)
+
+		req, err = rewindRequest(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryDelay returns how long to wait before retrying the request of the given response, a negative duration if the
+// request should not be retried or zero to use the exponential backoff. It also returns whether the primary rate
+// limit is exhausted
+func (t *GitHubRateLimitTransport) retryDelay(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return -1, false
+	}
+	if resp.Header.Get(gitHubRateLimitRemainingHeader) == "0" {
+		delay := t.resetDelay(resp)
+		if delay < time.Second {
+			delay = time.Second
+		}
+		return delay, true
+	}
+	retryAfter := resp.Header.Get(retryAfterHeader)
+	if retryAfter != "" {
+		seconds, err := strconv.Atoi(retryAfter)
+		if err == nil && seconds >= 0 {
+			return time.Duration(seconds)*time.Second + time.Second, false
+		}
+		return 0, false
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || isGitHubSecondaryRateLimit(resp) {
+		return 0, false
+	}
+	return -1, false
+}
+
+// GitHubSSOAuthorizationError is returned when the API token has not been authorized for the SAML single sign-on of
+// the organisation being accessed
+type GitHubSSOAuthorizationError struct {
+	AuthorizationURL string
+}
+
+func (e *GitHubSSOAuthorizationError) Error() string {
+	return fmt.Sprintf("the API token is not authorized for the SAML single sign-on of this organisation, please authorize it at %s", e.AuthorizationURL)
+}
+
+// gitHubSSOAuthorizationURL returns the URL to authorize the API token at if the response was rejected because the
+// token is not authorized for the single sign-on of an organisation
+func gitHubSSOAuthorizationURL(resp *http.Response) string {
+	if resp.StatusCode != http.StatusForbidden {
+		return ""
+	}
+	value := resp.Header.Get(gitHubSSOHeader)
+	if !strings.HasPrefix(value, "required") {
+		return ""
+	}
+	for _, part := range strings.Split(value, ";") {
+		part = strings.TrimSpace(part)
+		if strings.HasPrefix(part, "url=") {
+			return strings.TrimPrefix(part, "url=")
+		}
+	}
+	return ""
+}
+
+// resetDelay returns how long to wait until the exhausted primary rate limit of the response resets or zero if the
+// limit is not exhausted
+func (t *GitHubRateLimitTransport) resetDelay(resp *http.Response) time.Duration {
+	if resp.Header.Get(gitHubRateLimitRemainingHeader) != "0" {
+		return 0
+	}
+	reset, err := strconv.ParseInt(resp.Header.Get(gitHubRateLimitResetHeader), 10, 64)
+	if err != nil {
+		return 0
+	}
+	delay := time.Unix(reset, 0).Sub(t.now()) + time.Second
+	if delay < 0 {
+		return 0
+	}
+	return delay
+}
+
+// isGitHubSecondaryRateLimit returns true if the body of the 403 response is the GitHub abuse detection message rather
+// than a permission error. The body is restored so that it can still be read by the caller
+func isGitHubSecondaryRateLimit(resp *http.Response) bool {
+	if resp.Body == nil {
+		return false
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+	if err != nil {
+		return false
+	}
+	text := strings.ToLower(string(data))
+	return strings.Contains(text, "abuse") || strings.Contains(text, "secondary rate limit")
+}
+
+// rewindRequest returns a copy of the request with a fresh body so that it can be sent again
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	if req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	answer := req.WithContext(req.Context())
+	answer.Body = body
+	return answer, nil
+}
+
+func (t *GitHubRateLimitTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+func (t *GitHubRateLimitTransport) sleep(d time.Duration) {
+	if t.Sleep != nil {
+		t.Sleep(d)
+		return
+	}
+	time.Sleep(d)
+}
+
+func (t *GitHubRateLimitTransport) now() time.Time {
+	if t.Now != nil {
+		return t.Now()
+	}
+	return time.Now()
+}
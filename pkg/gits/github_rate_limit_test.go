@@ -0,0 +1,164 @@
+package gits_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type rateLimitedResponse struct {
+	status  int
+	headers map[string]string
+	body    string
+}
+
+// rateLimitedServer replies with the given responses in turn and then with 200 OK, recording the request bodies
+func rateLimitedServer(responses []rateLimitedResponse, bodies *[]string) *httptest.Server {
+	count := 0
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		*bodies = append(*bodies, string(data))
+		if count >= len(responses) {
+			w.Write([]byte("{}"))
+			return
+		}
+		resp := responses[count]
+		count++
+		for k, v := range resp.headers {
+			w.Header().Set(k, v)
+		}
+		w.WriteHeader(resp.status)
+		w.Write([]byte(resp.body))
+	}))
+}
+
+func newTestRateLimitTransport(now time.Time, sleeps *[]time.Duration) *gits.GitHubRateLimitTransport {
+	transport := gits.NewGitHubRateLimitTransport(http.DefaultTransport)
+	transport.Now = func() time.Time {
+		return now
+	}
+	transport.Sleep = func(d time.Duration) {
+		*sleeps = append(*sleeps, d)
+	}
+	return transport
+}
+
+func TestGitHubRateLimitTransportWaitsForReset(t *testing.T) {
+	t.Parallel()
+	now := time.Unix(1500000000, 0)
+	bodies := []string{}
+	server := rateLimitedServer([]rateLimitedResponse{
+		{
+			status: http.StatusForbidden,
+			headers: map[string]string{
+				"X-RateLimit-Remaining": "0",
+				"X-RateLimit-Reset":     fmt.Sprintf("%d", now.Add(30*time.Second).Unix()),
+			},
+			body: `{"message": "API rate limit exceeded"}`,
+		},
+	}, &bodies)
+	defer server.Close()
+
+	sleeps := []time.Duration{}
+	client := &http.Client{Transport: newTestRateLimitTransport(now, &sleeps)}
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{"name": "test"}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []time.Duration{31 * time.Second}, sleeps)
+	assert.Equal(t, []string{`{"name": "test"}`, `{"name": "test"}`}, bodies)
+}
+
+func TestGitHubRateLimitTransportRetriesSecondaryRateLimits(t *testing.T) {
+	t.Parallel()
+	bodies := []string{}
+	server := rateLimitedServer([]rateLimitedResponse{
+		{
+			status:  http.StatusForbidden,
+			headers: map[string]string{"Retry-After": "60"},
+			body:    `{"message": "You have triggered an abuse detection mechanism."}`,
+		},
+		{
+			status: http.StatusForbidden,
+			body:   `{"message": "You have exceeded a secondary rate limit."}`,
+		},
+		{
+			status: http.StatusForbidden,
+			body:   `{"message": "You have exceeded a secondary rate limit."}`,
+		},
+	}, &bodies)
+	defer server.Close()
+
+	sleeps := []time.Duration{}
+	client := &http.Client{Transport: newTestRateLimitTransport(time.Now(), &sleeps)}
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, bodies, 4)
+	require.Len(t, sleeps, 3)
+	assert.Equal(t, 61*time.Second, sleeps[0])
+	assert.True(t, sleeps[1] >= time.Second && sleeps[1] < 2*time.Second, "first backoff %s", sleeps[1])
+	assert.True(t, sleeps[2] >= 2*time.Second && sleeps[2] < 4*time.Second, "second backoff %s", sleeps[2])
+}
+
+func TestGitHubRateLimitTransportDoesNotRetryPermissionErrors(t *testing.T) {
+	t.Parallel()
+	bodies := []string{}
+	server := rateLimitedServer([]rateLimitedResponse{
+		{
+			status: http.StatusForbidden,
+			body:   `{"message": "Must have admin rights to Repository."}`,
+		},
+	}, &bodies)
+	defer server.Close()
+
+	sleeps := []time.Duration{}
+	client := &http.Client{Transport: newTestRateLimitTransport(time.Now(), &sleeps)}
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, `{"message": "Must have admin rights to Repository."}`, string(data))
+	assert.Empty(t, sleeps)
+	assert.Len(t, bodies, 1)
+}
+
+func TestGitHubRateLimitTransportGivesUpAfterMaxRetries(t *testing.T) {
+	t.Parallel()
+	responses := []rateLimitedResponse{}
+	for i := 0; i < 10; i++ {
+		responses = append(responses, rateLimitedResponse{
+			status:  http.StatusTooManyRequests,
+			headers: map[string]string{"Retry-After": "1"},
+		})
+	}
+	bodies := []string{}
+	server := rateLimitedServer(responses, &bodies)
+	defer server.Close()
+
+	sleeps := []time.Duration{}
+	transport := newTestRateLimitTransport(time.Now(), &sleeps)
+	transport.MaxRetries = 2
+	client := &http.Client{Transport: transport}
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Len(t, sleeps, 2)
+	assert.Len(t, bodies, 3)
+}
@@ -0,0 +1,54 @@
+package gits_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pingServer serves the deliveries of a webhook adding a ping delivery with the given status code when it is pinged
+func pingServer(statusCode int) *httptest.Server {
+	deliveries := `[{"id": 1, "event": "ping", "status": "OK", "status_code": 200}]`
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/test-org/test-repo/hooks/7/pings", func(w http.ResponseWriter, r *http.Request) {
+		deliveries = fmt.Sprintf(`[{"id": 2, "event": "ping", "status": "%s", "status_code": %d}, {"id": 1, "event": "ping", "status": "OK", "status_code": 200}]`,
+			http.StatusText(statusCode), statusCode)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/api/v3/repos/test-org/test-repo/hooks/7/deliveries", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(deliveries))
+	})
+	return httptest.NewServer(mux)
+}
+
+func newPingProvider(t *testing.T, server *httptest.Server) *gits.GitHubProvider {
+	provider, err := gits.NewGitHubProvider(&auth.AuthServer{URL: server.URL, Kind: gits.KindGitHub},
+		&auth.UserAuth{Username: userName, ApiToken: "0123456789abdef"}, nil)
+	require.NoError(t, err)
+	return provider.(*gits.GitHubProvider)
+}
+
+func TestGitHubPingWebHookChecksTheDelivery(t *testing.T) {
+	t.Parallel()
+	server := pingServer(http.StatusOK)
+	defer server.Close()
+
+	err := newPingProvider(t, server).PingWebHook("test-org", "test-repo", 7)
+	assert.NoError(t, err)
+}
+
+func TestGitHubPingWebHookFailsIfTheDeliveryIsRejected(t *testing.T) {
+	t.Parallel()
+	server := pingServer(http.StatusUnauthorized)
+	defer server.Close()
+
+	err := newPingProvider(t, server).PingWebHook("test-org", "test-repo", 7)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+}
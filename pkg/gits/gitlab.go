@@ -103,6 +103,13 @@ func (g *GitlabProvider) CreateRepository(org string, name string, private bool)
 		Name:       &name,
 		Visibility: &visibility,
 	}
+	if org != "" && org != g.Username {
+		group, _, err := g.Client.Groups.GetGroup(org)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find the group %s due to: %s", org, err)
+		}
+		p.NamespaceID = &group.ID
+	}
 
 	project, _, err := g.Client.Projects.CreateProject(p)
 	if err != nil {
@@ -144,13 +151,19 @@ func (g *GitlabProvider) ListOrganisations() ([]GitOrganisation, error) {
 }
 
 func (g *GitlabProvider) projectId(org, username, name string) (string, error) {
+	// lets look up the project by its full path first as the path includes the subgroups of nested namespaces
+	project, _, err := g.Client.Projects.GetProject(owner(org, username) + "/" + name)
+	if err == nil && project != nil {
+		return strconv.Itoa(project.ID), nil
+	}
+
 	repos, _, err := getRepositories(g.Client, username, org)
 	if err != nil {
 		return "", err
 	}
 
 	for _, repo := range repos {
-		if repo.Name == name {
+		if repo.Name == name || repo.Path == name {
 			return strconv.Itoa(repo.ID), nil
 		}
 	}
@@ -367,6 +380,28 @@ func (g *GitlabProvider) ListCommitStatus(org string, repo string, sha string) (
 	return statuses, nil
 }
 
+func (g *GitlabProvider) UpdateCommitStatus(org string, repo string, sha string, status *GitRepoStatus) (*GitRepoStatus, error) {
+	pid, err := g.projectId(org, g.Username, repo)
+	if err != nil {
+		return nil, err
+	}
+	state := status.State
+	if state == "error" || state == "failure" {
+		state = string(gitlab.Failed)
+	}
+	opt := &gitlab.SetCommitStatusOptions{
+		State:       gitlab.BuildStateValue(state),
+		Context:     &status.Context,
+		TargetURL:   &status.TargetURL,
+		Description: &status.Description,
+	}
+	result, _, err := g.Client.Commits.SetCommitStatus(pid, sha, opt)
+	if err != nil {
+		return nil, err
+	}
+	return fromCommitStatus(result), nil
+}
+
 func fromCommitStatus(status *gitlab.CommitStatus) *GitRepoStatus {
 	return &GitRepoStatus{
 		ID:          string(status.ID),
@@ -391,10 +426,11 @@ func (g *GitlabProvider) MergePullRequest(pr *GitPullRequest, message string) er
 func (g *GitlabProvider) CreateWebHook(data *GitWebHookArguments) error {
 	pid, err := g.projectId(data.Owner, g.Username, data.Repo.Name)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	owner := owner(g.Username, data.Owner)
+	// the Jenkins folder of a project in a subgroup is named after the flattened namespace
+	owner := FlattenOrganisation(owner(data.Owner, g.Username))
 	webhookURL := util.UrlJoin(data.URL, owner, data.Repo.Name)
 	opt := &gitlab.AddProjectHookOptions{
 		URL:   &webhookURL,
@@ -405,6 +441,60 @@ func (g *GitlabProvider) CreateWebHook(data *GitWebHookArguments) error {
 	return err
 }
 
+func (g *GitlabProvider) ListWebHooks(owner string, repo string) ([]*GitWebHookArguments, error) {
+	answer := []*GitWebHookArguments{}
+	pid, err := g.projectId(owner, g.Username, repo)
+	if err != nil {
+		return answer, err
+	}
+	hooks, _, err := g.Client.Projects.ListProjectHooks(pid, nil)
+	if err != nil {
+		return answer, err
+	}
+	for _, hook := range hooks {
+		answer = append(answer, &GitWebHookArguments{
+			ID:    int64(hook.ID),
+			Owner: owner,
+			Repo:  &GitRepositoryInfo{Organisation: owner, Name: repo},
+			URL:   hook.URL,
+		})
+	}
+	return answer, nil
+}
+
+func (g *GitlabProvider) UpdateWebHook(data *GitWebHookArguments) error {
+	pid, err := g.projectId(data.Owner, g.Username, data.Repo.Name)
+	if err != nil {
+		return err
+	}
+	opt := &gitlab.EditProjectHookOptions{
+		URL:   &data.URL,
+		Token: &data.Secret,
+	}
+	_, _, err = g.Client.Projects.EditProjectHook(pid, int(data.ID), opt)
+	return err
+}
+
+func (g *GitlabProvider) DeleteWebHook(owner string, repo string, webhookURL string) error {
+	pid, err := g.projectId(owner, g.Username, repo)
+	if err != nil {
+		return err
+	}
+	hooks, _, err := g.Client.Projects.ListProjectHooks(pid, nil)
+	if err != nil {
+		return err
+	}
+	for _, hook := range hooks {
+		if hook.URL == webhookURL {
+			_, err = g.Client.Projects.DeleteProjectHook(pid, hook.ID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (g *GitlabProvider) SearchIssues(org, repo, query string) ([]*GitIssue, error) {
 	opt := &gitlab.ListProjectIssuesOptions{Search: &query}
 	return g.searchIssuesWithOptions(org, repo, opt)
@@ -516,6 +606,50 @@ func (g *GitlabProvider) AddPRComment(pr *GitPullRequest, comment string) error
 	return err
 }
 
+func (g *GitlabProvider) ListPRComments(pr *GitPullRequest) ([]*GitPRComment, error) {
+	answer := []*GitPRComment{}
+	if pr.Number == nil {
+		return answer, fmt.Errorf("Missing Number for GitPullRequest %#v", pr)
+	}
+	pid, err := g.projectId(pr.Owner, g.Username, pr.Repo)
+	if err != nil {
+		return answer, err
+	}
+	notes, _, err := g.Client.Notes.ListMergeRequestNotes(pid, *pr.Number, &gitlab.ListMergeRequestNotesOptions{})
+	if err != nil {
+		return answer, err
+	}
+	for _, note := range notes {
+		if note.System {
+			continue
+		}
+		answer = append(answer, &GitPRComment{
+			ID: int64(note.ID),
+			User: GitUser{
+				Login: note.Author.Username,
+				Name:  note.Author.Name,
+				Email: note.Author.Email,
+			},
+			Body:      note.Body,
+			CreatedAt: note.CreatedAt,
+		})
+	}
+	return answer, nil
+}
+
+func (g *GitlabProvider) EditPRComment(pr *GitPullRequest, comment *GitPRComment, body string) error {
+	if pr.Number == nil {
+		return fmt.Errorf("Missing Number for GitPullRequest %#v", pr)
+	}
+	pid, err := g.projectId(pr.Owner, g.Username, pr.Repo)
+	if err != nil {
+		return err
+	}
+	opt := &gitlab.UpdateMergeRequestNoteOptions{Body: &body}
+	_, _, err = g.Client.Notes.UpdateMergeRequestNote(pid, *pr.Number, int(comment.ID), opt)
+	return err
+}
+
 func (g *GitlabProvider) CreateIssueComment(owner string, repo string, number int, comment string) error {
 	opt := &gitlab.CreateIssueNoteOptions{Body: &comment}
 
@@ -1,6 +1,8 @@
 package gits_test
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"fmt"
@@ -20,6 +22,8 @@ const (
 	gitlabOrgName     = "testorg"
 	gitlabProjectName = "test-project"
 	gitlabProjectID   = "5690870"
+
+	gitlabSubgroupProjectID = "7001"
 )
 
 type GitlabProviderSuite struct {
@@ -27,6 +31,7 @@ type GitlabProviderSuite struct {
 	mux      *http.ServeMux
 	server   *httptest.Server
 	provider *gits.GitlabProvider
+	hookURL  string
 }
 
 func (suite *GitlabProviderSuite) SetupSuite() {
@@ -93,10 +98,45 @@ func configureGitlabMock(suite *GitlabProviderSuite, mux *http.ServeMux) {
 		w.Write(src)
 	})
 
+	// the namespace of a project in a subgroup must be URL encoded as a single path segment
+	subgroupProject := func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.RequestURI, "/projects/group%2Fsubgroup%2F") {
+			http.NotFound(w, r)
+			return
+		}
+		src, err := ioutil.ReadFile("test_data/gitlab/subgroup-project.json")
+
+		suite.Require().Nil(err)
+		w.Write(src)
+	}
+	mux.HandleFunc("/api/v4/projects/group/subgroup/project", subgroupProject)
+	mux.HandleFunc("/api/v4/projects/group/subgroup/nested/project", subgroupProject)
+
+	mux.HandleFunc("/api/v4/groups/group/subgroup/projects", func(w http.ResponseWriter, r *http.Request) {
+		suite.Require().Contains(r.RequestURI, "/groups/group%2Fsubgroup/projects")
+		src, err := ioutil.ReadFile("test_data/gitlab/subgroup-project.json")
+
+		suite.Require().Nil(err)
+		w.Write([]byte("[" + string(src) + "]"))
+	})
+
+	mux.HandleFunc(fmt.Sprintf("/api/v4/projects/%s/hooks", gitlabSubgroupProjectID), func(w http.ResponseWriter, r *http.Request) {
+		hook := &gitlab.ProjectHook{}
+		err := json.NewDecoder(r.Body).Decode(hook)
+
+		suite.Require().Nil(err)
+		suite.hookURL = hook.URL
+		hook.ID = 1
+		json.NewEncoder(w).Encode(hook)
+	})
+
 	gitlabRouter := util.Router{
 		fmt.Sprintf("/api/v4/projects/%s", gitlabProjectID): util.MethodMap{
 			"GET": "project.json",
 		},
+		fmt.Sprintf("/api/v4/projects/%s", gitlabSubgroupProjectID): util.MethodMap{
+			"GET": "subgroup-project.json",
+		},
 	}
 	for path, methodMap := range gitlabRouter {
 		mux.HandleFunc(path, util.GetMockAPIResponseFromFile("test_data/gitlab", methodMap))
@@ -154,6 +194,38 @@ func (suite *GitlabProviderSuite) TestGetRepository() {
 	suite.Require().Equal(gitlabProjectName, repo.Name)
 }
 
+func (suite *GitlabProviderSuite) TestGetRepositoryInSubgroup() {
+	for _, org := range []string{"group/subgroup", "group/subgroup/nested"} {
+		repo, err := suite.provider.GetRepository(org, "project")
+
+		suite.Require().Nil(err, "getting the project of %s", org)
+		suite.Require().Equal("project", repo.Name)
+		suite.Require().Equal("https://gitlab.example.com/group/subgroup/nested/project.git", repo.CloneURL)
+	}
+}
+
+func (suite *GitlabProviderSuite) TestListRepositoriesInSubgroup() {
+	repositories, err := suite.provider.ListRepositories("group/subgroup")
+
+	suite.Require().Nil(err)
+	suite.Require().Len(repositories, 1)
+	suite.Require().Equal("project", repositories[0].Name)
+}
+
+func (suite *GitlabProviderSuite) TestCreateWebHookInSubgroup() {
+	gitInfo, err := gits.ParseGitURL("https://gitlab.example.com/group/subgroup/nested/project.git")
+	suite.Require().Nil(err)
+
+	err = suite.provider.CreateWebHook(&gits.GitWebHookArguments{
+		Owner: gitInfo.Organisation,
+		Repo:  gitInfo,
+		URL:   "http://jenkins.example.com/project",
+	})
+
+	suite.Require().Nil(err)
+	suite.Require().Equal("http://jenkins.example.com/project/group-subgroup-nested/project", suite.hookURL)
+}
+
 func (suite *GitlabProviderSuite) TestAddCollaborator() {
 	err := suite.provider.AddCollaborator("derek", orgName, "repo")
 	suite.Require().Nil(err)
@@ -52,10 +52,18 @@ type GitProvider interface {
 
 	ListCommitStatus(org string, repo string, sha string) ([]*GitRepoStatus, error)
 
+	UpdateCommitStatus(org string, repo string, sha string, status *GitRepoStatus) (*GitRepoStatus, error)
+
 	MergePullRequest(pr *GitPullRequest, message string) error
 
 	CreateWebHook(data *GitWebHookArguments) error
 
+	ListWebHooks(owner string, repo string) ([]*GitWebHookArguments, error)
+
+	UpdateWebHook(data *GitWebHookArguments) error
+
+	DeleteWebHook(owner string, repo string, webhookURL string) error
+
 	IsGitHub() bool
 
 	IsGitea() bool
@@ -82,6 +90,10 @@ type GitProvider interface {
 
 	AddPRComment(pr *GitPullRequest, comment string) error
 
+	ListPRComments(pr *GitPullRequest) ([]*GitPRComment, error)
+
+	EditPRComment(pr *GitPullRequest, comment *GitPRComment, body string) error
+
 	CreateIssueComment(owner string, repo string, number int, comment string) error
 
 	UpdateRelease(owner string, repo string, tag string, releaseInfo *GitRelease) error
@@ -202,6 +214,12 @@ type Gitter interface {
 	FetchTags(dir string) error
 	Tags(dir string) ([]string, error)
 	CreateTag(dir string, tag string, msg string) error
+	CreateSignedTag(dir string, tag string, msg string) error
+	ReleaseTags(dir string) ([]string, error)
+	GetCommitMessagesSince(dir string, ref string) ([]string, error)
+
+	FileRevisions(dir string, fileName string) ([]string, error)
+	ShowFileAtRevision(dir string, revision string, fileName string) (string, error)
 
 	GetRevisionBeforeDate(dir string, t time.Time) (string, error)
 	GetRevisionBeforeDateText(dir string, dateText string) (string, error)
@@ -201,6 +201,36 @@ func (mock *MockGitProvider) DeleteRepository(_param0 string, _param1 string) er
 	return ret0
 }
 
+func (mock *MockGitProvider) DeleteWebHook(_param0 string, _param1 string, _param2 string) error {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockGitProvider().")
+	}
+	params := []pegomock.Param{_param0, _param1, _param2}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("DeleteWebHook", params, []reflect.Type{reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(error)
+		}
+	}
+	return ret0
+}
+
+func (mock *MockGitProvider) EditPRComment(_param0 *gits.GitPullRequest, _param1 *gits.GitPRComment, _param2 string) error {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockGitProvider().")
+	}
+	params := []pegomock.Param{_param0, _param1, _param2}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("EditPRComment", params, []reflect.Type{reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(error)
+		}
+	}
+	return ret0
+}
+
 func (mock *MockGitProvider) ForkRepository(_param0 string, _param1 string, _param2 string) (*gits.GitRepository, error) {
 	if mock == nil {
 		panic("mock must not be nil. Use myMock := NewMockGitProvider().")
@@ -507,6 +537,25 @@ func (mock *MockGitProvider) ListOrganisations() ([]gits.GitOrganisation, error)
 	return ret0, ret1
 }
 
+func (mock *MockGitProvider) ListPRComments(_param0 *gits.GitPullRequest) ([]*gits.GitPRComment, error) {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockGitProvider().")
+	}
+	params := []pegomock.Param{_param0}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("ListPRComments", params, []reflect.Type{reflect.TypeOf((*[]*gits.GitPRComment)(nil)).Elem(), reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 []*gits.GitPRComment
+	var ret1 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].([]*gits.GitPRComment)
+		}
+		if result[1] != nil {
+			ret1 = result[1].(error)
+		}
+	}
+	return ret0, ret1
+}
+
 func (mock *MockGitProvider) ListReleases(_param0 string, _param1 string) ([]*gits.GitRelease, error) {
 	if mock == nil {
 		panic("mock must not be nil. Use myMock := NewMockGitProvider().")
@@ -545,6 +594,25 @@ func (mock *MockGitProvider) ListRepositories(_param0 string) ([]*gits.GitReposi
 	return ret0, ret1
 }
 
+func (mock *MockGitProvider) ListWebHooks(_param0 string, _param1 string) ([]*gits.GitWebHookArguments, error) {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockGitProvider().")
+	}
+	params := []pegomock.Param{_param0, _param1}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("ListWebHooks", params, []reflect.Type{reflect.TypeOf((*[]*gits.GitWebHookArguments)(nil)).Elem(), reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 []*gits.GitWebHookArguments
+	var ret1 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].([]*gits.GitWebHookArguments)
+		}
+		if result[1] != nil {
+			ret1 = result[1].(error)
+		}
+	}
+	return ret0, ret1
+}
+
 func (mock *MockGitProvider) MergePullRequest(_param0 *gits.GitPullRequest, _param1 string) error {
 	if mock == nil {
 		panic("mock must not be nil. Use myMock := NewMockGitProvider().")
@@ -651,6 +719,25 @@ func (mock *MockGitProvider) ServerURL() string {
 	return ret0
 }
 
+func (mock *MockGitProvider) UpdateCommitStatus(_param0 string, _param1 string, _param2 string, _param3 *gits.GitRepoStatus) (*gits.GitRepoStatus, error) {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockGitProvider().")
+	}
+	params := []pegomock.Param{_param0, _param1, _param2, _param3}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("UpdateCommitStatus", params, []reflect.Type{reflect.TypeOf((**gits.GitRepoStatus)(nil)).Elem(), reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 *gits.GitRepoStatus
+	var ret1 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(*gits.GitRepoStatus)
+		}
+		if result[1] != nil {
+			ret1 = result[1].(error)
+		}
+	}
+	return ret0, ret1
+}
+
 func (mock *MockGitProvider) UpdatePullRequestStatus(_param0 *gits.GitPullRequest) error {
 	if mock == nil {
 		panic("mock must not be nil. Use myMock := NewMockGitProvider().")
@@ -681,6 +768,21 @@ func (mock *MockGitProvider) UpdateRelease(_param0 string, _param1 string, _para
 	return ret0
 }
 
+func (mock *MockGitProvider) UpdateWebHook(_param0 *gits.GitWebHookArguments) error {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockGitProvider().")
+	}
+	params := []pegomock.Param{_param0}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("UpdateWebHook", params, []reflect.Type{reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(error)
+		}
+	}
+	return ret0
+}
+
 func (mock *MockGitProvider) UserAuth() auth.UserAuth {
 	if mock == nil {
 		panic("mock must not be nil. Use myMock := NewMockGitProvider().")
@@ -1083,6 +1185,76 @@ func (c *GitProvider_DeleteRepository_OngoingVerification) GetAllCapturedArgumen
 	return
 }
 
+func (verifier *VerifierGitProvider) DeleteWebHook(_param0 string, _param1 string, _param2 string) *GitProvider_DeleteWebHook_OngoingVerification {
+	params := []pegomock.Param{_param0, _param1, _param2}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "DeleteWebHook", params)
+	return &GitProvider_DeleteWebHook_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type GitProvider_DeleteWebHook_OngoingVerification struct {
+	mock              *MockGitProvider
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *GitProvider_DeleteWebHook_OngoingVerification) GetCapturedArguments() (string, string, string) {
+	_param0, _param1, _param2 := c.GetAllCapturedArguments()
+	return _param0[len(_param0)-1], _param1[len(_param1)-1], _param2[len(_param2)-1]
+}
+
+func (c *GitProvider_DeleteWebHook_OngoingVerification) GetAllCapturedArguments() (_param0 []string, _param1 []string, _param2 []string) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]string, len(params[0]))
+		for u, param := range params[0] {
+			_param0[u] = param.(string)
+		}
+		_param1 = make([]string, len(params[1]))
+		for u, param := range params[1] {
+			_param1[u] = param.(string)
+		}
+		_param2 = make([]string, len(params[2]))
+		for u, param := range params[2] {
+			_param2[u] = param.(string)
+		}
+	}
+	return
+}
+
+func (verifier *VerifierGitProvider) EditPRComment(_param0 *gits.GitPullRequest, _param1 *gits.GitPRComment, _param2 string) *GitProvider_EditPRComment_OngoingVerification {
+	params := []pegomock.Param{_param0, _param1, _param2}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "EditPRComment", params)
+	return &GitProvider_EditPRComment_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type GitProvider_EditPRComment_OngoingVerification struct {
+	mock              *MockGitProvider
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *GitProvider_EditPRComment_OngoingVerification) GetCapturedArguments() (*gits.GitPullRequest, *gits.GitPRComment, string) {
+	_param0, _param1, _param2 := c.GetAllCapturedArguments()
+	return _param0[len(_param0)-1], _param1[len(_param1)-1], _param2[len(_param2)-1]
+}
+
+func (c *GitProvider_EditPRComment_OngoingVerification) GetAllCapturedArguments() (_param0 []*gits.GitPullRequest, _param1 []*gits.GitPRComment, _param2 []string) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]*gits.GitPullRequest, len(params[0]))
+		for u, param := range params[0] {
+			_param0[u] = param.(*gits.GitPullRequest)
+		}
+		_param1 = make([]*gits.GitPRComment, len(params[1]))
+		for u, param := range params[1] {
+			_param1[u] = param.(*gits.GitPRComment)
+		}
+		_param2 = make([]string, len(params[2]))
+		for u, param := range params[2] {
+			_param2[u] = param.(string)
+		}
+	}
+	return
+}
+
 func (verifier *VerifierGitProvider) ForkRepository(_param0 string, _param1 string, _param2 string) *GitProvider_ForkRepository_OngoingVerification {
 	params := []pegomock.Param{_param0, _param1, _param2}
 	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "ForkRepository", params)
@@ -1529,6 +1701,33 @@ func (c *GitProvider_ListOrganisations_OngoingVerification) GetCapturedArguments
 func (c *GitProvider_ListOrganisations_OngoingVerification) GetAllCapturedArguments() {
 }
 
+func (verifier *VerifierGitProvider) ListPRComments(_param0 *gits.GitPullRequest) *GitProvider_ListPRComments_OngoingVerification {
+	params := []pegomock.Param{_param0}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "ListPRComments", params)
+	return &GitProvider_ListPRComments_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type GitProvider_ListPRComments_OngoingVerification struct {
+	mock              *MockGitProvider
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *GitProvider_ListPRComments_OngoingVerification) GetCapturedArguments() *gits.GitPullRequest {
+	_param0 := c.GetAllCapturedArguments()
+	return _param0[len(_param0)-1]
+}
+
+func (c *GitProvider_ListPRComments_OngoingVerification) GetAllCapturedArguments() (_param0 []*gits.GitPullRequest) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]*gits.GitPullRequest, len(params[0]))
+		for u, param := range params[0] {
+			_param0[u] = param.(*gits.GitPullRequest)
+		}
+	}
+	return
+}
+
 func (verifier *VerifierGitProvider) ListReleases(_param0 string, _param1 string) *GitProvider_ListReleases_OngoingVerification {
 	params := []pegomock.Param{_param0, _param1}
 	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "ListReleases", params)
@@ -1587,6 +1786,37 @@ func (c *GitProvider_ListRepositories_OngoingVerification) GetAllCapturedArgumen
 	return
 }
 
+func (verifier *VerifierGitProvider) ListWebHooks(_param0 string, _param1 string) *GitProvider_ListWebHooks_OngoingVerification {
+	params := []pegomock.Param{_param0, _param1}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "ListWebHooks", params)
+	return &GitProvider_ListWebHooks_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type GitProvider_ListWebHooks_OngoingVerification struct {
+	mock              *MockGitProvider
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *GitProvider_ListWebHooks_OngoingVerification) GetCapturedArguments() (string, string) {
+	_param0, _param1 := c.GetAllCapturedArguments()
+	return _param0[len(_param0)-1], _param1[len(_param1)-1]
+}
+
+func (c *GitProvider_ListWebHooks_OngoingVerification) GetAllCapturedArguments() (_param0 []string, _param1 []string) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]string, len(params[0]))
+		for u, param := range params[0] {
+			_param0[u] = param.(string)
+		}
+		_param1 = make([]string, len(params[1]))
+		for u, param := range params[1] {
+			_param1[u] = param.(string)
+		}
+	}
+	return
+}
+
 func (verifier *VerifierGitProvider) MergePullRequest(_param0 *gits.GitPullRequest, _param1 string) *GitProvider_MergePullRequest_OngoingVerification {
 	params := []pegomock.Param{_param0, _param1}
 	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "MergePullRequest", params)
@@ -1767,6 +1997,45 @@ func (c *GitProvider_ServerURL_OngoingVerification) GetCapturedArguments() {
 func (c *GitProvider_ServerURL_OngoingVerification) GetAllCapturedArguments() {
 }
 
+func (verifier *VerifierGitProvider) UpdateCommitStatus(_param0 string, _param1 string, _param2 string, _param3 *gits.GitRepoStatus) *GitProvider_UpdateCommitStatus_OngoingVerification {
+	params := []pegomock.Param{_param0, _param1, _param2, _param3}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "UpdateCommitStatus", params)
+	return &GitProvider_UpdateCommitStatus_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type GitProvider_UpdateCommitStatus_OngoingVerification struct {
+	mock              *MockGitProvider
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *GitProvider_UpdateCommitStatus_OngoingVerification) GetCapturedArguments() (string, string, string, *gits.GitRepoStatus) {
+	_param0, _param1, _param2, _param3 := c.GetAllCapturedArguments()
+	return _param0[len(_param0)-1], _param1[len(_param1)-1], _param2[len(_param2)-1], _param3[len(_param3)-1]
+}
+
+func (c *GitProvider_UpdateCommitStatus_OngoingVerification) GetAllCapturedArguments() (_param0 []string, _param1 []string, _param2 []string, _param3 []*gits.GitRepoStatus) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]string, len(params[0]))
+		for u, param := range params[0] {
+			_param0[u] = param.(string)
+		}
+		_param1 = make([]string, len(params[1]))
+		for u, param := range params[1] {
+			_param1[u] = param.(string)
+		}
+		_param2 = make([]string, len(params[2]))
+		for u, param := range params[2] {
+			_param2[u] = param.(string)
+		}
+		_param3 = make([]*gits.GitRepoStatus, len(params[3]))
+		for u, param := range params[3] {
+			_param3[u] = param.(*gits.GitRepoStatus)
+		}
+	}
+	return
+}
+
 func (verifier *VerifierGitProvider) UpdatePullRequestStatus(_param0 *gits.GitPullRequest) *GitProvider_UpdatePullRequestStatus_OngoingVerification {
 	params := []pegomock.Param{_param0}
 	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "UpdatePullRequestStatus", params)
@@ -1833,6 +2102,33 @@ func (c *GitProvider_UpdateRelease_OngoingVerification) GetAllCapturedArguments(
 	return
 }
 
+func (verifier *VerifierGitProvider) UpdateWebHook(_param0 *gits.GitWebHookArguments) *GitProvider_UpdateWebHook_OngoingVerification {
+	params := []pegomock.Param{_param0}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "UpdateWebHook", params)
+	return &GitProvider_UpdateWebHook_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type GitProvider_UpdateWebHook_OngoingVerification struct {
+	mock              *MockGitProvider
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *GitProvider_UpdateWebHook_OngoingVerification) GetCapturedArguments() *gits.GitWebHookArguments {
+	_param0 := c.GetAllCapturedArguments()
+	return _param0[len(_param0)-1]
+}
+
+func (c *GitProvider_UpdateWebHook_OngoingVerification) GetAllCapturedArguments() (_param0 []*gits.GitWebHookArguments) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]*gits.GitWebHookArguments, len(params[0]))
+		for u, param := range params[0] {
+			_param0[u] = param.(*gits.GitWebHookArguments)
+		}
+	}
+	return
+}
+
 func (verifier *VerifierGitProvider) UserAuth() *GitProvider_UserAuth_OngoingVerification {
 	params := []pegomock.Param{}
 	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "UserAuth", params)
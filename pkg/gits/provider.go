@@ -79,6 +79,14 @@ type GitIssue struct {
 	Assignees     []GitUser
 }
 
+// GitPRComment a comment on a Pull Request
+type GitPRComment struct {
+	ID        int64
+	User      GitUser
+	Body      string
+	CreatedAt *time.Time
+}
+
 type GitUser struct {
 	URL       string
 	Login     string
@@ -127,6 +135,7 @@ type GitPullRequestArguments struct {
 }
 
 type GitWebHookArguments struct {
+	ID     int64
 	Owner  string
 	Repo   *GitRepositoryInfo
 	URL    string
@@ -140,6 +149,8 @@ func (pr *GitPullRequest) IsClosed() bool {
 
 func CreateProvider(server *auth.AuthServer, user *auth.UserAuth, git Gitter) (GitProvider, error) {
 	switch server.Kind {
+	case KindAzureDevOps:
+		return NewAzureDevOpsProvider(server, user, git)
 	case KindBitBucketCloud:
 		return NewBitbucketCloudProvider(server, user, git)
 	case KindBitBucketServer:
@@ -171,6 +182,8 @@ func GetHost(gitProvider GitProvider) (string, error) {
 
 func ProviderAccessTokenURL(kind string, url string, username string) string {
 	switch kind {
+	case KindAzureDevOps:
+		return AzureDevOpsAccessTokenURL(url)
 	case KindBitBucketCloud:
 		// TODO pass in the username
 		return BitBucketCloudAccessTokenURL(url, username)
@@ -291,7 +304,7 @@ func (s *GitRepoStatus) IsFailed() bool {
 
 func (i *GitRepositoryInfo) PickOrCreateProvider(authConfigSvc auth.AuthConfigService, message string, batchMode bool, gitKind string, git Gitter, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) (GitProvider, error) {
 	config := authConfigSvc.Config()
-	hostUrl := i.HostURLWithoutUser()
+	hostUrl := i.ServerURL(config)
 	server := config.GetOrCreateServer(hostUrl)
 	if server.Kind == "" {
 		server.Kind = gitKind
@@ -313,8 +326,18 @@ func (i *GitRepositoryInfo) CreateProviderForUser(server *auth.AuthServer, user
 	return CreateProvider(server, user, git)
 }
 
+// ServerURL returns the URL of the Git server of the repository, including the path of a server in the auth
+// configuration which is not at the root of its host
+func (i *GitRepositoryInfo) ServerURL(config *auth.AuthConfig) string {
+	server := config.FindServerForGitURL(i.URL)
+	if server != nil {
+		return server.URL
+	}
+	return i.HostURLWithoutUser()
+}
+
 func (i *GitRepositoryInfo) CreateProvider(authConfigSvc auth.AuthConfigService, gitKind string, git Gitter, batchMode bool, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) (GitProvider, error) {
-	hostUrl := i.HostURLWithoutUser()
+	hostUrl := i.ServerURL(authConfigSvc.Config())
 	return CreateProviderForURL(authConfigSvc, gitKind, hostUrl, git, batchMode, in, out, errOut)
 }
 
@@ -361,12 +384,18 @@ func createUserForServer(batchMode bool, authConfigSvc auth.AuthConfigService, s
 		return nil
 	}
 
-	// TODO could we guess this based on the users ~/.git for github?
-	defaultUserName := ""
-	err := authConfigSvc.Config().EditUserAuth(server.Label(), userAuth, defaultUserName, false, batchMode, f, in, out, errOut)
+	loggedIn, err := LoginWithGitHubDeviceFlow(server, userAuth, batchMode, out)
 	if err != nil {
 		return userAuth, err
 	}
+	if !loggedIn {
+		// TODO could we guess this based on the users ~/.git for github?
+		defaultUserName := ""
+		err = authConfigSvc.Config().EditUserAuth(server.Label(), userAuth, defaultUserName, false, batchMode, f, in, out, errOut)
+		if err != nil {
+			return userAuth, err
+		}
+	}
 
 	// TODO lets verify the auth works
 
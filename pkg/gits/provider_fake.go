@@ -24,6 +24,7 @@ const (
 	BitbucketCloud
 	BitbucketServer
 	Gerrit
+	AzureDevOps
 )
 
 type CommitStatus string
@@ -44,6 +45,7 @@ type FakePullRequest struct {
 	PullRequest *GitPullRequest
 	Commits     []*FakeCommit
 	Comment     string
+	Comments    []*GitPRComment
 }
 
 type FakeIssue struct {
@@ -60,6 +62,7 @@ type FakeRepository struct {
 	issueCount         int
 	Releases           map[string]*GitRelease
 	PullRequestCounter int
+	WebHooks           []*GitWebHookArguments
 }
 
 type FakeProvider struct {
@@ -305,6 +308,20 @@ func (f *FakeProvider) ListCommitStatus(org string, repoName string, sha string)
 	return answer, nil
 }
 
+func (f *FakeProvider) UpdateCommitStatus(org string, repoName string, sha string, status *GitRepoStatus) (*GitRepoStatus, error) {
+	repo := f.findRepository(org, repoName)
+	if repo == nil {
+		return nil, fmt.Errorf("repository with name '%s' not found", repoName)
+	}
+	for _, commit := range repo.Commits {
+		if commit.Commit.SHA == sha {
+			commit.Status = CommitStatus(status.State)
+			return status, nil
+		}
+	}
+	return nil, fmt.Errorf("commit with sha '%s' not found", sha)
+}
+
 func (f *FakeProvider) MergePullRequest(pr *GitPullRequest, message string) error {
 	owner := pr.Owner
 	repos, ok := f.Repositories[owner]
@@ -327,6 +344,62 @@ func (f *FakeProvider) MergePullRequest(pr *GitPullRequest, message string) erro
 }
 
 func (f *FakeProvider) CreateWebHook(data *GitWebHookArguments) error {
+	if data.Repo == nil {
+		return nil
+	}
+	repo := f.findRepository(data.Owner, data.Repo.Name)
+	if repo != nil {
+		if data.ID == 0 {
+			data.ID = int64(len(repo.WebHooks) + 1)
+		}
+		repo.WebHooks = append(repo.WebHooks, data)
+	}
+	return nil
+}
+
+func (f *FakeProvider) ListWebHooks(owner string, repo string) ([]*GitWebHookArguments, error) {
+	fakeRepo := f.findRepository(owner, repo)
+	if fakeRepo == nil {
+		return nil, fmt.Errorf("repository with name '%s' not found", repo)
+	}
+	return fakeRepo.WebHooks, nil
+}
+
+func (f *FakeProvider) UpdateWebHook(data *GitWebHookArguments) error {
+	fakeRepo := f.findRepository(data.Owner, data.Repo.Name)
+	if fakeRepo == nil {
+		return fmt.Errorf("repository with name '%s' not found", data.Repo.Name)
+	}
+	for i, webHook := range fakeRepo.WebHooks {
+		if webHook.ID == data.ID {
+			fakeRepo.WebHooks[i] = data
+			return nil
+		}
+	}
+	return fmt.Errorf("webhook with id '%d' not found", data.ID)
+}
+
+func (f *FakeProvider) DeleteWebHook(owner string, repo string, webhookURL string) error {
+	fakeRepo := f.findRepository(owner, repo)
+	if fakeRepo == nil {
+		return fmt.Errorf("repository with name '%s' not found", repo)
+	}
+	webHooks := []*GitWebHookArguments{}
+	for _, webHook := range fakeRepo.WebHooks {
+		if webHook.URL != webhookURL {
+			webHooks = append(webHooks, webHook)
+		}
+	}
+	fakeRepo.WebHooks = webHooks
+	return nil
+}
+
+func (f *FakeProvider) findRepository(owner string, name string) *FakeRepository {
+	for _, repo := range f.Repositories[owner] {
+		if repo.GitRepo != nil && repo.GitRepo.Name == name {
+			return repo
+		}
+	}
 	return nil
 }
 
@@ -362,6 +435,8 @@ func (f *FakeProvider) Kind() string {
 		return KindBitBucketCloud
 	case BitbucketServer:
 		return KindBitBucketServer
+	case AzureDevOps:
+		return KindAzureDevOps
 	default:
 		return KindUnknown
 	}
@@ -470,12 +545,53 @@ func (f *FakeProvider) AddPRComment(pr *GitPullRequest, comment string) error {
 				return fmt.Errorf("pull request with id '%d' not found", number)
 			}
 			pr.Comment = comment
+			now := time.Now()
+			pr.Comments = append(pr.Comments, &GitPRComment{
+				ID:        int64(len(pr.Comments) + 1),
+				User:      GitUser{Login: f.User.Username},
+				Body:      comment,
+				CreatedAt: &now,
+			})
 			return nil
 		}
 	}
 	return fmt.Errorf("repository with name '%s' not found", repoName)
 }
 
+func (f *FakeProvider) ListPRComments(pr *GitPullRequest) ([]*GitPRComment, error) {
+	owner := pr.Owner
+	repos, ok := f.Repositories[owner]
+	if !ok {
+		return nil, fmt.Errorf("no repositories found for '%s'", owner)
+	}
+	repoName := pr.Repo
+	number := *pr.Number
+	for _, r := range repos {
+		if r.GitRepo.Name == repoName {
+			pr, ok := r.PullRequests[number]
+			if !ok {
+				return nil, fmt.Errorf("pull request with id '%d' not found", number)
+			}
+			return pr.Comments, nil
+		}
+	}
+	return nil, fmt.Errorf("repository with name '%s' not found", repoName)
+}
+
+func (f *FakeProvider) EditPRComment(pr *GitPullRequest, comment *GitPRComment, body string) error {
+	comments, err := f.ListPRComments(pr)
+	if err != nil {
+		return err
+	}
+	for _, c := range comments {
+		if c.ID == comment.ID {
+			c.Body = body
+			return nil
+		}
+	}
+	return fmt.Errorf("comment with id '%d' not found", comment.ID)
+}
+
 func (f *FakeProvider) CreateIssueComment(owner string, repoName string, number int, comment string) error {
 	repos, ok := f.Repositories[owner]
 	if !ok {
@@ -0,0 +1,167 @@
+package gits
+
+import (
+	"net/url"
+)
+
+const (
+	// WebHookStatusOK the repository has exactly the expected webhook
+	WebHookStatusOK = "ok"
+	// WebHookStatusMissing the repository has no webhook calling this installation
+	WebHookStatusMissing = "missing"
+	// WebHookStatusStale the webhooks of the repository call a previous URL of this installation
+	WebHookStatusStale = "stale"
+	// WebHookStatusDuplicate the repository has the expected webhook more than once
+	WebHookStatusDuplicate = "duplicate"
+	// WebHookStatusSecret the webhook of the repository has a different secret
+	WebHookStatusSecret = "secret mismatch"
+
+	// WebHookSecretOK the secret of the webhook matches
+	WebHookSecretOK = "ok"
+	// WebHookSecretMismatch the secret of the webhook differs
+	WebHookSecretMismatch = "mismatch"
+	// WebHookSecretUnknown the git provider does not reveal the secret of its webhooks
+	WebHookSecretUnknown = "unknown"
+)
+
+// WebHookPinger is implemented by the git providers which can send a ping event to a webhook
+type WebHookPinger interface {
+	PingWebHook(owner string, repo string, id int64) error
+}
+
+// WebHookDrift the differences between the webhooks of a repository and the webhook it should have
+type WebHookDrift struct {
+	Status string
+	Secret string
+	// Hook the webhook with the expected URL if there is one
+	Hook *GitWebHookArguments
+	// Duplicates the number of additional webhooks with the expected URL
+	Duplicates int
+	// Stale the webhooks with the path of the expected URL on another host
+	Stale []*GitWebHookArguments
+}
+
+// Drifted returns true if the webhooks of the repository need to be repaired
+func (d *WebHookDrift) Drifted() bool {
+	return d.Status != WebHookStatusOK
+}
+
+// StaleURLs returns the distinct URLs of the stale webhooks
+func (d *WebHookDrift) StaleURLs() []string {
+	answer := []string{}
+	for _, hook := range d.Stale {
+		found := false
+		for _, u := range answer {
+			if u == hook.URL {
+				found = true
+				break
+			}
+		}
+		if !found {
+			answer = append(answer, hook.URL)
+		}
+	}
+	return answer
+}
+
+// DetectWebHookDrift compares the webhooks of a repository with the expected webhook. Webhooks with the path of the
+// expected URL on other hosts are considered to be left over from previous installations, e.g. before the ingress
+// domain changed. The secret of the webhooks is only compared if the git provider reveals it
+func DetectWebHookDrift(hooks []*GitWebHookArguments, expected *GitWebHookArguments) *WebHookDrift {
+	drift := &WebHookDrift{
+		Status: WebHookStatusOK,
+		Secret: WebHookSecretUnknown,
+	}
+	expectedPath := webHookPath(expected.URL)
+	for _, hook := range hooks {
+		if hook.URL == expected.URL {
+			if drift.Hook == nil {
+				drift.Hook = hook
+			} else {
+				drift.Duplicates++
+			}
+		} else if expectedPath != "" && webHookPath(hook.URL) == expectedPath {
+			drift.Stale = append(drift.Stale, hook)
+		}
+	}
+	if drift.Hook != nil && drift.Hook.Secret != "" {
+		if drift.Hook.Secret == expected.Secret {
+			drift.Secret = WebHookSecretOK
+		} else {
+			drift.Secret = WebHookSecretMismatch
+		}
+	}
+	switch {
+	case drift.Hook == nil && len(drift.Stale) == 0:
+		drift.Status = WebHookStatusMissing
+	case drift.Hook == nil || len(drift.Stale) > 0:
+		drift.Status = WebHookStatusStale
+	case drift.Duplicates > 0:
+		drift.Status = WebHookStatusDuplicate
+	case drift.Secret == WebHookSecretMismatch:
+		drift.Status = WebHookStatusSecret
+	}
+	return drift
+}
+
+// RepairWebHooks makes the repository have exactly the expected webhook: a stale webhook is pointed at the expected
+// URL, the other stale and duplicate webhooks are deleted and a missing webhook is created
+func RepairWebHooks(provider GitProvider, drift *WebHookDrift, expected *GitWebHookArguments) error {
+	owner := expected.Owner
+	repo := expected.Repo.Name
+	staleURLs := drift.StaleURLs()
+	if drift.Hook == nil && len(drift.Stale) > 0 {
+		hook := *expected
+		hook.ID = drift.Stale[0].ID
+		err := provider.UpdateWebHook(&hook)
+		if err != nil {
+			return err
+		}
+		// the other stale webhooks with the URL of the updated one are deleted below
+		if countWebHooksWithURL(drift.Stale, staleURLs[0]) == 1 {
+			staleURLs = staleURLs[1:]
+		}
+	}
+	for _, u := range staleURLs {
+		err := provider.DeleteWebHook(owner, repo, u)
+		if err != nil {
+			return err
+		}
+	}
+	if drift.Hook == nil && len(drift.Stale) == 0 || drift.Duplicates > 0 {
+		if drift.Duplicates > 0 {
+			// webhooks can only be deleted by URL so the duplicates are replaced with a single new webhook
+			err := provider.DeleteWebHook(owner, repo, expected.URL)
+			if err != nil {
+				return err
+			}
+		}
+		hook := *expected
+		hook.ID = 0
+		return provider.CreateWebHook(&hook)
+	}
+	if drift.Hook != nil && drift.Secret == WebHookSecretMismatch {
+		hook := *expected
+		hook.ID = drift.Hook.ID
+		return provider.UpdateWebHook(&hook)
+	}
+	return nil
+}
+
+func countWebHooksWithURL(hooks []*GitWebHookArguments, u string) int {
+	count := 0
+	for _, hook := range hooks {
+		if hook.URL == u {
+			count++
+		}
+	}
+	return count
+}
+
+func webHookPath(webHookURL string) string {
+	u, err := url.Parse(webHookURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return u.Path
+}
@@ -0,0 +1,109 @@
+package gits_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testHookURL  = "http://hook.jx.new.example.com/hook"
+	testOldURL   = "http://hook.jx.old.example.com/hook"
+	testOtherURL = "https://ci.example.com/github-webhook/"
+)
+
+func newWebHookTestProvider(hooks ...*gits.GitWebHookArguments) (*gits.FakeProvider, *gits.FakeRepository, *gits.GitWebHookArguments) {
+	repo := gits.NewFakeRepository("myorg", "myrepo")
+	info := &gits.GitRepositoryInfo{Organisation: "myorg", Name: "myrepo"}
+	for _, hook := range hooks {
+		hook.Owner = "myorg"
+		hook.Repo = info
+	}
+	repo.WebHooks = hooks
+	expected := &gits.GitWebHookArguments{
+		Owner:  "myorg",
+		Repo:   info,
+		URL:    testHookURL,
+		Secret: "s3cr3t",
+	}
+	return gits.NewFakeProvider(repo), repo, expected
+}
+
+func webHookURLs(repo *gits.FakeRepository) []string {
+	answer := []string{}
+	for _, hook := range repo.WebHooks {
+		answer = append(answer, hook.URL)
+	}
+	return answer
+}
+
+func repairWebHooks(t *testing.T, provider *gits.FakeProvider, expected *gits.GitWebHookArguments, status string) {
+	hooks, err := provider.ListWebHooks("myorg", "myrepo")
+	require.NoError(t, err)
+	drift := gits.DetectWebHookDrift(hooks, expected)
+	assert.Equal(t, status, drift.Status)
+	require.NoError(t, gits.RepairWebHooks(provider, drift, expected))
+
+	hooks, err = provider.ListWebHooks("myorg", "myrepo")
+	require.NoError(t, err)
+	assert.False(t, gits.DetectWebHookDrift(hooks, expected).Drifted(), "the webhooks should have been repaired")
+}
+
+func TestWebHookDriftOK(t *testing.T) {
+	t.Parallel()
+	provider, _, expected := newWebHookTestProvider(
+		&gits.GitWebHookArguments{ID: 1, URL: testHookURL},
+		&gits.GitWebHookArguments{ID: 2, URL: testOtherURL},
+	)
+	hooks, err := provider.ListWebHooks("myorg", "myrepo")
+	require.NoError(t, err)
+	drift := gits.DetectWebHookDrift(hooks, expected)
+	assert.Equal(t, gits.WebHookStatusOK, drift.Status)
+	assert.Equal(t, gits.WebHookSecretUnknown, drift.Secret)
+	assert.False(t, drift.Drifted())
+}
+
+func TestRepairMissingWebHook(t *testing.T) {
+	t.Parallel()
+	provider, repo, expected := newWebHookTestProvider(&gits.GitWebHookArguments{ID: 1, URL: testOtherURL})
+	repairWebHooks(t, provider, expected, gits.WebHookStatusMissing)
+	assert.Equal(t, []string{testOtherURL, testHookURL}, webHookURLs(repo))
+}
+
+func TestRepairStaleWebHooks(t *testing.T) {
+	t.Parallel()
+	provider, repo, expected := newWebHookTestProvider(
+		&gits.GitWebHookArguments{ID: 1, URL: testOldURL},
+		&gits.GitWebHookArguments{ID: 2, URL: testOtherURL},
+		&gits.GitWebHookArguments{ID: 3, URL: "http://hook.jx.older.example.com/hook"},
+	)
+	repairWebHooks(t, provider, expected, gits.WebHookStatusStale)
+	assert.Equal(t, []string{testHookURL, testOtherURL}, webHookURLs(repo))
+	assert.Equal(t, int64(1), repo.WebHooks[0].ID, "the stale webhook should have been updated")
+}
+
+func TestRepairDuplicateWebHooks(t *testing.T) {
+	t.Parallel()
+	provider, repo, expected := newWebHookTestProvider(
+		&gits.GitWebHookArguments{ID: 1, URL: testHookURL},
+		&gits.GitWebHookArguments{ID: 2, URL: testOtherURL},
+		&gits.GitWebHookArguments{ID: 3, URL: testHookURL},
+	)
+	repairWebHooks(t, provider, expected, gits.WebHookStatusDuplicate)
+	assert.Equal(t, []string{testOtherURL, testHookURL}, webHookURLs(repo))
+}
+
+func TestRepairWebHookSecretMismatch(t *testing.T) {
+	t.Parallel()
+	provider, repo, expected := newWebHookTestProvider(&gits.GitWebHookArguments{ID: 7, URL: testHookURL, Secret: "old"})
+	hooks, err := provider.ListWebHooks("myorg", "myrepo")
+	require.NoError(t, err)
+	assert.Equal(t, gits.WebHookSecretMismatch, gits.DetectWebHookDrift(hooks, expected).Secret)
+
+	repairWebHooks(t, provider, expected, gits.WebHookStatusSecret)
+	require.Len(t, repo.WebHooks, 1)
+	assert.Equal(t, int64(7), repo.WebHooks[0].ID)
+	assert.Equal(t, "s3cr3t", repo.WebHooks[0].Secret)
+}
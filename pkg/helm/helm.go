@@ -6,6 +6,11 @@ type Version int
 const (
 	V2 Version = 2
 	V3         = 3
+
+	// Helm3Binary the name of the helm 3 CLI binary installed alongside helm 2
+	Helm3Binary = "helm3"
+	// StableRepoURL the URL of the stable chart repository
+	StableRepoURL = "https://kubernetes-charts.storage.googleapis.com"
 )
 
 type ChartSummary struct {
@@ -19,6 +19,8 @@ type HelmCLI struct {
 	CWD        string
 	Runner     *util.Command
 	Debug      bool
+	// StableRepoURL replaces the URL of the stable repository, such as with a mirror in an air-gapped network
+	StableRepoURL string
 }
 
 // NewHelmCLI creates a new HelmCLI instance configured to use the provided helm CLI in
@@ -69,6 +71,27 @@ func (h *HelmCLI) HelmBinary() string {
 // SetHelmBinary configure a new helm CLI
 func (h *HelmCLI) SetHelmBinary(binary string) {
 	h.Binary = binary
+	h.BinVersion = BinaryVersion(binary)
+}
+
+// BinaryVersion returns the helm version of the given helm CLI binary
+func BinaryVersion(binary string) Version {
+	if filepath.Base(binary) == Helm3Binary {
+		return V3
+	}
+	return V2
+}
+
+func (h *HelmCLI) isHelm3() bool {
+	return h.BinVersion == V3
+}
+
+// timeoutArg returns the timeout in seconds in the format of the helm CLI version
+func (h *HelmCLI) timeoutArg(timeout int) string {
+	if h.isHelm3() {
+		return strconv.Itoa(timeout) + "s"
+	}
+	return strconv.Itoa(timeout)
 }
 
 func (h *HelmCLI) runHelm(args ...string) error {
@@ -88,6 +111,9 @@ func (h *HelmCLI) runHelmWithOutput(args ...string) (string, error) {
 
 // Init executes the helm init command according with the given flags
 func (h *HelmCLI) Init(clientOnly bool, serviceAccount string, tillerNamespace string, upgrade bool) error {
+	if h.isHelm3() {
+		return h.initHelm3()
+	}
 	args := []string{}
 	args = append(args, "init")
 	if clientOnly {
@@ -102,6 +128,9 @@ func (h *HelmCLI) Init(clientOnly bool, serviceAccount string, tillerNamespace s
 	if upgrade {
 		args = append(args, "--upgrade", "--wait", "--force-upgrade")
 	}
+	if h.StableRepoURL != "" {
+		args = append(args, "--stable-repo-url", h.StableRepoURL)
+	}
 
 	if h.Debug {
 		log.Infof("Initialising Helm '%s'\n", util.ColorInfo(strings.Join(args, " ")))
@@ -110,9 +139,49 @@ func (h *HelmCLI) Init(clientOnly bool, serviceAccount string, tillerNamespace s
 	return h.runHelm(args...)
 }
 
-// AddRepo adds a new helm repo with the given name and URL
-func (h *HelmCLI) AddRepo(repo string, URL string) error {
-	return h.runHelm("repo", "add", repo, URL)
+// initHelm3 adds the stable repository which helm 3 no longer adds on its own as there is no init command
+func (h *HelmCLI) initHelm3() error {
+	stableRepoURL := StableRepoURL
+	if h.StableRepoURL != "" {
+		stableRepoURL = h.StableRepoURL
+	}
+	missing, err := h.IsRepoMissing(stableRepoURL)
+	if err != nil {
+		return err
+	}
+	if !missing {
+		return nil
+	}
+	if h.Debug {
+		log.Infof("Adding the helm repository %s\n", util.ColorInfo(stableRepoURL))
+	}
+	return h.AddRepo("stable", stableRepoURL, "", "")
+}
+
+// AddRepo adds a new helm repo with the given name and URL and the optional credentials of a private repo. Helm 3
+// reads the password from stdin so that it is not visible in the process list. The password is never part of the
+// returned error
+func (h *HelmCLI) AddRepo(repo string, URL string, username string, password string) error {
+	args := []string{"repo", "add", repo, URL}
+	if username != "" {
+		args = append(args, "--username", username)
+	}
+	if password != "" {
+		if h.isHelm3() {
+			args = append(args, "--password-stdin")
+			h.Runner.In = strings.NewReader(password)
+			defer func() {
+				h.Runner.In = nil
+			}()
+		} else {
+			args = append(args, "--password", password)
+		}
+	}
+	err := h.runHelm(args...)
+	if err != nil && password != "" {
+		return errors.New(strings.Replace(err.Error(), password, "****", -1))
+	}
+	return err
 }
 
 // RemoveRepo removes the given repo from helm
@@ -124,6 +193,9 @@ func (h *HelmCLI) RemoveRepo(repo string) error {
 func (h *HelmCLI) ListRepos() (map[string]string, error) {
 	output, err := h.runHelmWithOutput("repo", "list")
 	if err != nil {
+		if h.isHelm3() && strings.Contains(output+err.Error(), "no repositories") {
+			return map[string]string{}, nil
+		}
 		return nil, errors.Wrap(err, "failed to list repositories")
 	}
 	repos := map[string]string{}
@@ -143,7 +215,7 @@ func (h *HelmCLI) ListRepos() (map[string]string, error) {
 // SearchCharts searches for all the charts matching the given filter
 func (h *HelmCLI) SearchCharts(filter string) ([]ChartSummary, error) {
 	answer := []ChartSummary{}
-	output, err := h.runHelmWithOutput("search", filter)
+	output, err := h.runHelmWithOutput(h.searchArgs(filter)...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to search charts")
 	}
@@ -171,6 +243,14 @@ func (h *HelmCLI) SearchCharts(filter string) ([]ChartSummary, error) {
 	return answer, nil
 }
 
+// searchArgs returns the arguments to search the repositories as helm 3 can also search the hub
+func (h *HelmCLI) searchArgs(filter string) []string {
+	if h.isHelm3() {
+		return []string{"search", "repo", filter}
+	}
+	return []string{"search", filter}
+}
+
 // IsRepoMissing checks if the repository with the given URL is missing from helm
 func (h *HelmCLI) IsRepoMissing(URL string) (bool, error) {
 	repos, err := h.ListRepos()
@@ -226,9 +306,13 @@ func (h *HelmCLI) BuildDependency() error {
 func (h *HelmCLI) InstallChart(chart string, releaseName string, ns string, version *string, timeout *int,
 	values []string, valueFiles []string) error {
 	args := []string{}
-	args = append(args, "install", "--wait", "--name", releaseName, "--namespace", ns, chart)
+	if h.isHelm3() {
+		args = append(args, "install", releaseName, chart, "--wait", "--namespace", ns)
+	} else {
+		args = append(args, "install", "--wait", "--name", releaseName, "--namespace", ns, chart)
+	}
 	if timeout != nil {
-		args = append(args, "--timeout", strconv.Itoa(*timeout))
+		args = append(args, "--timeout", h.timeoutArg(*timeout))
 	}
 	if version != nil {
 		args = append(args, "--version", *version)
@@ -251,6 +335,9 @@ func (h *HelmCLI) InstallChart(chart string, releaseName string, ns string, vers
 func (h *HelmCLI) Template(chart string, releaseName string, ns string, outDir string, upgrade bool,
 	values []string, valueFiles []string) error {
 	args := []string{"template", "--name", releaseName, "--namespace", ns, chart, "--output-dir", outDir, "--debug"}
+	if h.isHelm3() {
+		args = []string{"template", releaseName, chart, "--namespace", ns, "--output-dir", outDir, "--debug"}
+	}
 	if upgrade {
 		args = append(args, "--is-upgrade")
 	}
@@ -287,7 +374,7 @@ func (h *HelmCLI) UpgradeChart(chart string, releaseName string, ns string, vers
 		args = append(args, "--force")
 	}
 	if timeout != nil {
-		args = append(args, "--timeout", strconv.Itoa(*timeout))
+		args = append(args, "--timeout", h.timeoutArg(*timeout))
 	}
 	if version != nil {
 		args = append(args, "--version", *version)
@@ -309,6 +396,10 @@ func (h *HelmCLI) UpgradeChart(chart string, releaseName string, ns string, vers
 
 // DeleteRelease removes the given release
 func (h *HelmCLI) DeleteRelease(ns string, releaseName string, purge bool) error {
+	if h.isHelm3() {
+		// helm 3 always purges the release history
+		return h.runHelm("uninstall", releaseName, "--namespace", ns)
+	}
 	args := []string{}
 	args = append(args, "delete")
 	if purge {
@@ -320,12 +411,16 @@ func (h *HelmCLI) DeleteRelease(ns string, releaseName string, purge bool) error
 
 // ListCharts execute the helm list command and returns its output
 func (h *HelmCLI) ListCharts() (string, error) {
+	if h.isHelm3() {
+		// helm 3 releases are namespaced so list the releases of all namespaces like helm 2 does
+		return h.runHelmWithOutput("list", "--all-namespaces")
+	}
 	return h.runHelmWithOutput("list")
 }
 
 // SearchChartVersions search all version of the given chart
 func (h *HelmCLI) SearchChartVersions(chart string) ([]string, error) {
-	output, err := h.runHelmWithOutput("search", chart, "--versions")
+	output, err := h.runHelmWithOutput(append(h.searchArgs(chart), "--versions")...)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to search chart '%s'", chart)
 	}
@@ -379,6 +474,9 @@ func (h *HelmCLI) FindChart() (string, error) {
 
 // StatusRelease returns the output of the helm status command for a given release
 func (h *HelmCLI) StatusRelease(ns string, releaseName string) error {
+	if h.isHelm3() {
+		return h.runHelm("status", releaseName, "--namespace", ns)
+	}
 	return h.runHelm("status", releaseName)
 }
 
@@ -390,17 +488,47 @@ func (h *HelmCLI) StatusReleases(ns string) (map[string]string, error) {
 	}
 	lines := strings.Split(output, "\n")
 	statusMap := map[string]string{}
+	// helm 3 lists the namespace before the revision
+	statusField := 3
+	if h.isHelm3() {
+		statusField = 4
+	}
 	for _, line := range lines[1:] {
 		fields := strings.Split(line, "\t")
-		if len(fields) > 3 {
+		if len(fields) > statusField {
 			release := strings.TrimSpace(fields[0])
-			status := strings.TrimSpace(fields[3])
+			status := strings.ToUpper(strings.TrimSpace(fields[statusField]))
 			statusMap[release] = status
 		}
 	}
 	return statusMap, nil
 }
 
+// GetReleaseValues returns the YAML of all the computed values of the given release
+func (h *HelmCLI) GetReleaseValues(ns string, releaseName string) (string, error) {
+	if h.isHelm3() {
+		return h.runHelmWithOutput("get", "values", releaseName, "--all", "--output", "yaml", "--namespace", ns)
+	}
+	return h.runHelmWithOutput("get", "values", "--all", releaseName)
+}
+
+// GetReleaseUserValues returns the YAML of the values supplied by the user when the given release was installed or
+// upgraded without the default values of its chart
+func (h *HelmCLI) GetReleaseUserValues(ns string, releaseName string) (string, error) {
+	if h.isHelm3() {
+		return h.runHelmWithOutput("get", "values", releaseName, "--output", "yaml", "--namespace", ns)
+	}
+	return h.runHelmWithOutput("get", "values", releaseName)
+}
+
+// GetReleaseManifest returns the manifests rendered by the given release
+func (h *HelmCLI) GetReleaseManifest(ns string, releaseName string) (string, error) {
+	if h.isHelm3() {
+		return h.runHelmWithOutput("get", "manifest", releaseName, "--namespace", ns)
+	}
+	return h.runHelmWithOutput("get", "manifest", releaseName)
+}
+
 // Lint lints the helm chart from the current working directory and returns the warnings in the output
 func (h *HelmCLI) Lint() (string, error) {
 	return h.runHelmWithOutput("lint")
@@ -419,7 +547,7 @@ func (h *HelmCLI) Version(tls bool) (string, error) {
 // Version executes the helm version command and returns its output
 func (h *HelmCLI) VersionWithArgs(tls bool, extraArgs ...string) (string, error) {
 	args := []string{"version", "--short"}
-	if tls {
+	if tls && !h.isHelm3() {
 		args = append(args, "--tls")
 	}
 	args = append(args, extraArgs...)
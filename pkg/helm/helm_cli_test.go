@@ -104,9 +104,46 @@ func TestAddRepo(t *testing.T) {
 	expectedArgs := fmt.Sprintf("repo add %s %s", repo, repoURL)
 	helm, err := createHelm(expectedArgs)
 	assert.NoError(t, err, "should create helm without any error")
-	err = helm.AddRepo(repo, repoURL)
+	err = helm.AddRepo(repo, repoURL, "", "")
 	assert.NoError(t, err, "should add helm repo without any error")
 }
+
+func TestAddRepoWithCredentials(t *testing.T) {
+	setup("")
+	expectedArgs := fmt.Sprintf("repo add %s %s --username jenkins --password secret", repo, repoURL)
+	helm, err := createHelm(expectedArgs)
+	assert.NoError(t, err, "should create helm without any error")
+	err = helm.AddRepo(repo, repoURL, "jenkins", "secret")
+	assert.NoError(t, err, "should add helm repo without any error")
+}
+func TestAddRepoWithCredentialsHelm3(t *testing.T) {
+	var r *util.Command
+	var stdin string
+	monkey.PatchInstanceMethod(reflect.TypeOf(r), "RunWithoutRetry", func(c *util.Command) (string, error) {
+		data, err := ioutil.ReadAll(c.In)
+		stdin = string(data)
+		return "", err
+	})
+	cli := createHelm3()
+	err := cli.AddRepo(repo, repoURL, "jenkins", "secret")
+	assert.NoError(t, err, "should add helm repo without any error")
+	assert.Equal(t, "repo add test-repo http://test-repo --username jenkins --password-stdin", strings.Join(cli.Runner.Args, " "))
+	assert.Equal(t, "secret", stdin, "the password should be passed on stdin")
+	assert.Nil(t, cli.Runner.In)
+}
+
+func TestAddRepoWithCredentialsRedactsThePassword(t *testing.T) {
+	var r *util.Command
+	monkey.PatchInstanceMethod(reflect.TypeOf(r), "RunWithoutRetry", func(c *util.Command) (string, error) {
+		return "", fmt.Errorf("failed to run '%s %s'", c.Name, strings.Join(c.Args, " "))
+	})
+	cli := helm.NewHelmCLI(binary, helm.V2, cwd, true)
+	err := cli.AddRepo(repo, repoURL, "jenkins", "secret")
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret")
+	assert.Contains(t, err.Error(), "--password ****")
+}
+
 func TestRemoveRepo(t *testing.T) {
 	setup("")
 	expectedArgs := fmt.Sprintf("repo remove %s", repo)
@@ -245,6 +282,35 @@ func TestStatusReleases(t *testing.T) {
 	}
 }
 
+func TestGetReleaseValues(t *testing.T) {
+	setup("foo: bar")
+	expectedArgs := fmt.Sprintf("get values --all %s", releaseName)
+	helm, err := createHelm(expectedArgs)
+	assert.NoError(t, err, "should create helm without any error")
+	values, err := helm.GetReleaseValues(namespace, releaseName)
+	assert.NoError(t, err, "should get the values of a helm chart release without any error")
+	assert.Equal(t, "foo: bar", values)
+}
+
+func TestGetReleaseUserValues(t *testing.T) {
+	setup("foo: bar")
+	expectedArgs := fmt.Sprintf("get values %s", releaseName)
+	helm, err := createHelm(expectedArgs)
+	assert.NoError(t, err, "should create helm without any error")
+	values, err := helm.GetReleaseUserValues(namespace, releaseName)
+	assert.NoError(t, err, "should get the user supplied values of a helm chart release without any error")
+	assert.Equal(t, "foo: bar", values)
+}
+
+func TestGetReleaseManifest(t *testing.T) {
+	setup("")
+	expectedArgs := fmt.Sprintf("get manifest %s", releaseName)
+	helm, err := createHelm(expectedArgs)
+	assert.NoError(t, err, "should create helm without any error")
+	_, err = helm.GetReleaseManifest(namespace, releaseName)
+	assert.NoError(t, err, "should get the manifest of a helm chart release without any error")
+}
+
 func TestLint(t *testing.T) {
 	expectedArgs := "lint"
 	expectedOutput := "test"
@@ -302,3 +368,101 @@ func TestPackage(t *testing.T) {
 	err = helm.PackageChart()
 	assert.NoError(t, err, "should package chart without any error")
 }
+
+const listReleasesHelm3Output = "NAME     \tNAMESPACE\tREVISION\tUPDATED                             \tSTATUS  \tCHART                      \tAPP VERSION\n" +
+	"jenkins-x\tjx       \t1       \t2019-12-10 10:12:21.36 +0000 UTC\tdeployed\tjenkins-x-platform-2.0.1655\t           \n" +
+	"jxing    \tkube-system\t1     \t2019-12-10 10:10:02.12 +0000 UTC\tfailed  \tnginx-ingress-1.26.2      \t0.26.1     \n"
+
+func createHelm3() *helm.HelmCLI {
+	return helm.NewHelmCLI(helm.Helm3Binary, helm.V3, cwd, true)
+}
+
+func TestBinaryVersion(t *testing.T) {
+	assert.Equal(t, helm.V2, helm.BinaryVersion("helm"))
+	assert.Equal(t, helm.Version(helm.V3), helm.BinaryVersion("helm3"))
+	assert.Equal(t, helm.Version(helm.V3), helm.BinaryVersion("/home/jx/.jx/bin/helm3"))
+
+	cli := helm.NewHelmCLI(binary, helm.V2, cwd, true)
+	cli.SetHelmBinary(helm.Helm3Binary)
+	assert.Equal(t, helm.Version(helm.V3), cli.BinVersion)
+}
+
+func TestInitHelm3(t *testing.T) {
+	setup("NAME     \tURL\njenkins-x\thttps://chartmuseum.build.cd.jenkins-x.io\n")
+	cli := createHelm3()
+	err := cli.Init(false, serviceAccount, namespace, true)
+	assert.NoError(t, err, "should init helm3 without any error")
+	assert.Equal(t, "repo add stable "+helm.StableRepoURL, strings.Join(cli.Runner.Args, " "))
+}
+
+func TestInitHelm3StableRepoMirror(t *testing.T) {
+	setup("NAME     \tURL\njenkins-x\thttps://chartmuseum.build.cd.jenkins-x.io\n")
+	cli := createHelm3()
+	cli.StableRepoURL = "https://nexus.example.com/repository/stable"
+	err := cli.Init(false, "", "", false)
+	assert.NoError(t, err, "should init helm3 without any error")
+	assert.Equal(t, "repo add stable https://nexus.example.com/repository/stable", strings.Join(cli.Runner.Args, " "))
+}
+
+func TestInstallChartHelm3(t *testing.T) {
+	setup("")
+	timeout := 600
+	cli := createHelm3()
+	err := cli.InstallChart(chart, releaseName, namespace, nil, &timeout, []string{"test"}, nil)
+	assert.NoError(t, err, "should install the chart without any error")
+	expectedArgs := fmt.Sprintf("install %s %s --wait --namespace %s --timeout 600s --set test", releaseName, chart, namespace)
+	assert.Equal(t, expectedArgs, strings.Join(cli.Runner.Args, " "))
+}
+
+func TestUpgradeChartHelm3(t *testing.T) {
+	setup("")
+	version := "0.0.1"
+	timeout := 600
+	cli := createHelm3()
+	err := cli.UpgradeChart(chart, releaseName, namespace, &version, true, &timeout, false, true, nil, nil)
+	assert.NoError(t, err, "should upgrade the chart without any error")
+	expectedArgs := fmt.Sprintf("upgrade --namespace %s --install --wait --timeout 600s --version %s %s %s", namespace, version, releaseName, chart)
+	assert.Equal(t, expectedArgs, strings.Join(cli.Runner.Args, " "))
+}
+
+func TestDeleteReleaseHelm3(t *testing.T) {
+	setup("")
+	cli := createHelm3()
+	err := cli.DeleteRelease(namespace, releaseName, true)
+	assert.NoError(t, err, "should delete helm chart release without any error")
+	assert.Equal(t, fmt.Sprintf("uninstall %s --namespace %s", releaseName, namespace), strings.Join(cli.Runner.Args, " "))
+}
+
+func TestStatusReleasesHelm3(t *testing.T) {
+	setup(listReleasesHelm3Output)
+	cli := createHelm3()
+	statusMap, err := cli.StatusReleases(namespace)
+	assert.NoError(t, err, "should list the release statuses without any error")
+	assert.Equal(t, "list --all-namespaces", strings.Join(cli.Runner.Args, " "))
+	assert.Equal(t, map[string]string{"jenkins-x": "DEPLOYED", "jxing": "FAILED"}, statusMap)
+}
+
+func TestGetReleaseValuesHelm3(t *testing.T) {
+	setup("")
+	cli := createHelm3()
+	_, err := cli.GetReleaseValues(namespace, releaseName)
+	assert.NoError(t, err, "should get the values of a helm chart release without any error")
+	assert.Equal(t, fmt.Sprintf("get values %s --all --output yaml --namespace %s", releaseName, namespace), strings.Join(cli.Runner.Args, " "))
+}
+
+func TestGetReleaseUserValuesHelm3(t *testing.T) {
+	setup("")
+	cli := createHelm3()
+	_, err := cli.GetReleaseUserValues(namespace, releaseName)
+	assert.NoError(t, err, "should get the user supplied values of a helm chart release without any error")
+	assert.Equal(t, fmt.Sprintf("get values %s --output yaml --namespace %s", releaseName, namespace), strings.Join(cli.Runner.Args, " "))
+}
+
+func TestSearchChartVersionsHelm3(t *testing.T) {
+	setup(searchVersionOutput)
+	cli := createHelm3()
+	versions, err := cli.SearchChartVersions(chart)
+	assert.NoError(t, err, "should search chart versions without any error")
+	assert.Equal(t, fmt.Sprintf("search repo %s --versions", chart), strings.Join(cli.Runner.Args, " "))
+	assert.Equal(t, []string{"0.0.1481", "0.0.1480", "0.0.1479"}, versions)
+}
@@ -18,6 +18,9 @@ import (
 const (
 	RequirementsFileName = "requirements.yaml"
 
+	// ValuesFileName the default values file of a chart
+	ValuesFileName = "values.yaml"
+
 	DefaultHelmRepositoryURL = "http://jenkins-x-chartmuseum:8080"
 
 	defaultEnvironmentChartDir = "env"
@@ -87,9 +87,9 @@ func (h *HelmTemplate) Init(clientOnly bool, serviceAccount string, tillerNamesp
 	return h.Client.Init(true, serviceAccount, tillerNamespace, upgrade)
 }
 
-// AddRepo adds a new helm repo with the given name and URL
-func (h *HelmTemplate) AddRepo(repo string, URL string) error {
-	return h.Client.AddRepo(repo, URL)
+// AddRepo adds a new helm repo with the given name and URL and the optional credentials of a private repo
+func (h *HelmTemplate) AddRepo(repo string, URL string, username string, password string) error {
+	return h.Client.AddRepo(repo, URL, username, password)
 }
 
 // RemoveRepo removes the given repo from helm
@@ -365,6 +365,21 @@ func (h *HelmTemplate) StatusRelease(ns string, releaseName string) error {
 	return nil
 }
 
+// GetReleaseValues returns the YAML of all the computed values of the given release
+func (h *HelmTemplate) GetReleaseValues(ns string, releaseName string) (string, error) {
+	return "", fmt.Errorf("the values of the release %s are not stored when using helm template", releaseName)
+}
+
+// GetReleaseUserValues returns the YAML of the values supplied by the user for the given release
+func (h *HelmTemplate) GetReleaseUserValues(ns string, releaseName string) (string, error) {
+	return "", fmt.Errorf("the values of the release %s are not stored when using helm template", releaseName)
+}
+
+// GetReleaseManifest returns the manifests rendered by the given release
+func (h *HelmTemplate) GetReleaseManifest(ns string, releaseName string) (string, error) {
+	return "", fmt.Errorf("the manifests of the release %s are not stored when using helm template", releaseName)
+}
+
 // StatusReleases returns the status of all installed releases
 func (h *HelmTemplate) StatusReleases(ns string) (map[string]string, error) {
 	statusMap := map[string]string{}
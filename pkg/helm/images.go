@@ -0,0 +1,22 @@
+package helm
+
+import (
+	"regexp"
+	"sort"
+)
+
+var imageRegex = regexp.MustCompile(`(?m)^[\s-]*image:\s*["']?([^"'\s]+)["']?\s*$`)
+
+// ImagesOfManifests returns the sorted, unique images referenced by the rendered kubernetes manifests of a chart
+func ImagesOfManifests(manifests string) []string {
+	images := map[string]bool{}
+	for _, match := range imageRegex.FindAllStringSubmatch(manifests, -1) {
+		images[match[1]] = true
+	}
+	answer := []string{}
+	for image := range images {
+		answer = append(answer, image)
+	}
+	sort.Strings(answer)
+	return answer
+}
@@ -0,0 +1,38 @@
+package helm_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImagesOfManifests(t *testing.T) {
+	t.Parallel()
+	manifests := `---
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  template:
+    spec:
+      initContainers:
+      - image: "busybox:1.30"
+      containers:
+      - name: chartmuseum
+        image: chartmuseum/chartmuseum:v0.7.1
+      - name: controller
+        image: 'gcr.io/jenkinsxio/builder-go:0.1.2'
+---
+kind: Deployment
+spec:
+  template:
+    spec:
+      containers:
+      - image: chartmuseum/chartmuseum:v0.7.1
+        env:
+        - name: image
+          value: not-an-image
+`
+	expected := []string{"busybox:1.30", "chartmuseum/chartmuseum:v0.7.1", "gcr.io/jenkinsxio/builder-go:0.1.2"}
+	assert.Equal(t, expected, helm.ImagesOfManifests(manifests))
+}
@@ -7,7 +7,7 @@ type Helmer interface {
 	HelmBinary() string
 	SetHelmBinary(binary string)
 	Init(clientOnly bool, serviceAccount string, tillerNamespace string, upgrade bool) error
-	AddRepo(repo string, URL string) error
+	AddRepo(repo string, URL string, username string, password string) error
 	RemoveRepo(repo string) error
 	ListRepos() (map[string]string, error)
 	UpdateRepo() error
@@ -25,6 +25,9 @@ type Helmer interface {
 	PackageChart() error
 	StatusRelease(ns string, releaseName string) error
 	StatusReleases(ns string) (map[string]string, error)
+	GetReleaseValues(ns string, releaseName string) (string, error)
+	GetReleaseUserValues(ns string, releaseName string) (string, error)
+	GetReleaseManifest(ns string, releaseName string) (string, error)
 	Lint() (string, error)
 	Version(tls bool) (string, error)
 	SearchCharts(filter string) ([]ChartSummary, error)
@@ -0,0 +1,62 @@
+package helm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const sourceCommentPrefix = "# Source: "
+
+// SortManifests sorts the YAML documents of the manifests by their source template so that the manifests stored
+// in a release can be compared with the ones generated by helm template
+func SortManifests(manifests string) string {
+	docs := []string{}
+	for _, doc := range strings.Split("\n"+manifests, "\n---") {
+		doc = strings.TrimSpace(doc)
+		if doc != "" {
+			docs = append(docs, doc)
+		}
+	}
+	sort.SliceStable(docs, func(i, j int) bool {
+		return manifestSource(docs[i]) < manifestSource(docs[j])
+	})
+	answer := ""
+	for _, doc := range docs {
+		answer += "---\n" + doc + "\n"
+	}
+	return answer
+}
+
+// LoadManifestsDir loads all the YAML files generated by helm template into the given directory
+func LoadManifestsDir(dir string) (string, error) {
+	answer := ""
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".yaml") {
+			return nil
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read the manifest %s", path)
+		}
+		answer += "\n---\n" + string(data)
+		return nil
+	})
+	return answer, err
+}
+
+func manifestSource(doc string) string {
+	for _, line := range strings.Split(doc, "\n") {
+		if strings.HasPrefix(line, sourceCommentPrefix) {
+			return strings.TrimPrefix(line, sourceCommentPrefix)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,35 @@
+package helm_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortManifests(t *testing.T) {
+	t.Parallel()
+	manifests := `
+---
+# Source: jenkins-x-platform/charts/nexus/templates/pvc.yaml
+kind: PersistentVolumeClaim
+---
+# Source: jenkins-x-platform/charts/chartmuseum/templates/service.yaml
+kind: Service
+
+---
+# Source: jenkins-x-platform/charts/chartmuseum/templates/deployment.yaml
+kind: Deployment
+`
+	expected := `---
+# Source: jenkins-x-platform/charts/chartmuseum/templates/deployment.yaml
+kind: Deployment
+---
+# Source: jenkins-x-platform/charts/chartmuseum/templates/service.yaml
+kind: Service
+---
+# Source: jenkins-x-platform/charts/nexus/templates/pvc.yaml
+kind: PersistentVolumeClaim
+`
+	assert.Equal(t, expected, helm.SortManifests(manifests))
+}
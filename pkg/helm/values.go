@@ -0,0 +1,188 @@
+package helm
+
+import (
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// SecretURLPrefix the prefix of the values which reference the key of a Secret, such as secret://my-secret/password,
+// so that secret values are never committed to the git repository of an environment
+const SecretURLPrefix = "secret://"
+
+// secretKeyRegex matches the names of the values which contain credentials
+var secretKeyRegex = regexp.MustCompile(`(?i)(password|passwd|secret|token|credentials|apikey|api_key|privatekey|private_key)`)
+
+// LoadValuesFile loads the helm values of the YAML file
+func LoadValuesFile(fileName string) (map[string]interface{}, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the values file %s", fileName)
+	}
+	values := map[string]interface{}{}
+	err = yaml.Unmarshal(data, &values)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the values file %s", fileName)
+	}
+	return values, nil
+}
+
+// MergeValues deep merges the overrides into the values, the same way helm merges values files, and returns the
+// sorted paths of the values which the overrides replaced with a different value
+func MergeValues(values map[string]interface{}, overrides map[string]interface{}) []string {
+	answer := mergeValues(values, overrides, "")
+	sort.Strings(answer)
+	return answer
+}
+
+func mergeValues(values map[string]interface{}, overrides map[string]interface{}, prefix string) []string {
+	answer := []string{}
+	for k, v := range overrides {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		existing, ok := values[k]
+		if ok {
+			existingMap, existingIsMap := existing.(map[string]interface{})
+			overrideMap, overrideIsMap := v.(map[string]interface{})
+			if existingIsMap && overrideIsMap {
+				answer = append(answer, mergeValues(existingMap, overrideMap, path)...)
+				continue
+			}
+			if !reflect.DeepEqual(existing, v) {
+				answer = append(answer, path)
+			}
+		}
+		values[k] = v
+	}
+	return answer
+}
+
+// SaveValuesFile saves the helm values to the YAML file
+func SaveValuesFile(fileName string, values map[string]interface{}) error {
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal the values file %s", fileName)
+	}
+	err = ioutil.WriteFile(fileName, data, util.DefaultWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save the values file %s", fileName)
+	}
+	return nil
+}
+
+// ParseSetValues parses values in the syntax of the --set option of helm, such as a.b=c,d=2, into nested values
+func ParseSetValues(sets []string) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	for _, set := range sets {
+		for _, expression := range strings.Split(set, ",") {
+			idx := strings.Index(expression, "=")
+			if idx <= 0 {
+				return nil, fmt.Errorf("invalid value %s, expected key=value", expression)
+			}
+			path := strings.Split(expression[:idx], ".")
+			m := values
+			for _, key := range path[:len(path)-1] {
+				child, ok := m[key].(map[string]interface{})
+				if !ok {
+					child = map[string]interface{}{}
+					m[key] = child
+				}
+				m = child
+			}
+			m[path[len(path)-1]] = parseSetValue(expression[idx+1:])
+		}
+	}
+	return values, nil
+}
+
+// parseSetValue converts the value to a bool or an int like helm does
+func parseSetValue(value string) interface{} {
+	switch value {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	i, err := strconv.ParseInt(value, 10, 64)
+	if err == nil {
+		return i
+	}
+	return value
+}
+
+// IsSecretKey returns true if the name of the value suggests it contains credentials
+func IsSecretKey(key string) bool {
+	return secretKeyRegex.MatchString(key)
+}
+
+// SecretURL returns the value referencing the key of the Secret
+func SecretURL(secretName string, key string) string {
+	return SecretURLPrefix + secretName + "/" + key
+}
+
+// MoveSecretValues replaces the plain text values containing credentials with secret URLs referencing the keys of the
+// Secret with the given name and returns the data of the Secret indexed by the paths of the values
+func MoveSecretValues(values map[string]interface{}, secretName string) map[string]string {
+	data := map[string]string{}
+	moveSecretValues(values, secretName, "", data)
+	return data
+}
+
+func moveSecretValues(values map[string]interface{}, secretName string, prefix string, data map[string]string) {
+	for k, v := range values {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		switch value := v.(type) {
+		case map[string]interface{}:
+			moveSecretValues(value, secretName, path, data)
+		case string:
+			if IsSecretKey(k) && value != "" && !strings.HasPrefix(value, SecretURLPrefix) {
+				data[path] = value
+				values[k] = SecretURL(secretName, path)
+			}
+		}
+	}
+}
+
+// ResolveSecretURLs replaces the secret URLs in the values with the keys of the Secrets returned by the lookup function
+// returning true if any values were replaced
+func ResolveSecretURLs(values map[string]interface{}, lookup func(secretName string, key string) (string, error)) (bool, error) {
+	answer := false
+	for k, v := range values {
+		switch value := v.(type) {
+		case map[string]interface{}:
+			resolved, err := ResolveSecretURLs(value, lookup)
+			if err != nil {
+				return answer, err
+			}
+			answer = answer || resolved
+		case string:
+			if strings.HasPrefix(value, SecretURLPrefix) {
+				ref := strings.TrimPrefix(value, SecretURLPrefix)
+				idx := strings.Index(ref, "/")
+				if idx <= 0 {
+					return answer, fmt.Errorf("invalid secret URL %s, expected %s<secret>/<key>", value, SecretURLPrefix)
+				}
+				secret, err := lookup(ref[:idx], ref[idx+1:])
+				if err != nil {
+					return answer, errors.Wrapf(err, "failed to resolve the secret URL %s", value)
+				}
+				values[k] = secret
+				answer = true
+			}
+		}
+	}
+	return answer, nil
+}
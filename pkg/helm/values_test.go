@@ -0,0 +1,116 @@
+package helm_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadValuesFile(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-values")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, "values.yaml")
+	err = ioutil.WriteFile(fileName, []byte("nexus:\n  resources:\n    limits:\n      memory: 2Gi\n"), 0644)
+	require.NoError(t, err)
+
+	values, err := helm.LoadValuesFile(fileName)
+	require.NoError(t, err)
+	nexus := values["nexus"].(map[string]interface{})
+	limits := nexus["resources"].(map[string]interface{})["limits"].(map[string]interface{})
+	assert.Equal(t, "2Gi", limits["memory"])
+
+	_, err = helm.LoadValuesFile(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestMergeValues(t *testing.T) {
+	t.Parallel()
+	values := map[string]interface{}{
+		"expose": map[string]interface{}{
+			"config": map[string]interface{}{
+				"domain":  "1.2.3.4.nip.io",
+				"exposer": "Ingress",
+			},
+		},
+		"nexus": map[string]interface{}{
+			"enabled": true,
+		},
+	}
+	overrides := map[string]interface{}{
+		"expose": map[string]interface{}{
+			"config": map[string]interface{}{
+				"domain":  "jx.example.com",
+				"exposer": "Ingress",
+			},
+		},
+		"nginx": map[string]interface{}{
+			"replicaCount": 3,
+		},
+		"nexus": "disabled",
+	}
+
+	overridden := helm.MergeValues(values, overrides)
+	assert.Equal(t, []string{"expose.config.domain", "nexus"}, overridden)
+
+	config := values["expose"].(map[string]interface{})["config"].(map[string]interface{})
+	assert.Equal(t, "jx.example.com", config["domain"])
+	assert.Equal(t, "Ingress", config["exposer"])
+	assert.Equal(t, "disabled", values["nexus"])
+	assert.Equal(t, 3, values["nginx"].(map[string]interface{})["replicaCount"])
+}
+
+func TestParseSetValues(t *testing.T) {
+	t.Parallel()
+	values, err := helm.ParseSetValues([]string{"image.tag=1.2.3,replicaCount=2", "ingress.enabled=true"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"image":        map[string]interface{}{"tag": "1.2.3"},
+		"replicaCount": int64(2),
+		"ingress":      map[string]interface{}{"enabled": true},
+	}, values)
+
+	_, err = helm.ParseSetValues([]string{"image.tag"})
+	assert.Error(t, err)
+}
+
+func TestMoveAndResolveSecretValues(t *testing.T) {
+	t.Parallel()
+	values := map[string]interface{}{
+		"postgresql": map[string]interface{}{
+			"postgresUser":     "app",
+			"postgresPassword": "s3cr3t",
+		},
+		"apiToken": "secret://existing/token",
+		"replicas": 1,
+	}
+	data := helm.MoveSecretValues(values, "myapp-values")
+	assert.Equal(t, map[string]string{"postgresql.postgresPassword": "s3cr3t"}, data)
+	postgresql := values["postgresql"].(map[string]interface{})
+	assert.Equal(t, "secret://myapp-values/postgresql.postgresPassword", postgresql["postgresPassword"])
+	assert.Equal(t, "app", postgresql["postgresUser"])
+	assert.Equal(t, "secret://existing/token", values["apiToken"], "secret URLs should be kept")
+
+	secrets := map[string]string{
+		"myapp-values/postgresql.postgresPassword": "s3cr3t",
+		"existing/token": "abc",
+	}
+	resolved, err := helm.ResolveSecretURLs(values, func(name string, key string) (string, error) {
+		return secrets[name+"/"+key], nil
+	})
+	require.NoError(t, err)
+	assert.True(t, resolved)
+	assert.Equal(t, "s3cr3t", postgresql["postgresPassword"])
+	assert.Equal(t, "abc", values["apiToken"])
+
+	resolved, err = helm.ResolveSecretURLs(map[string]interface{}{"replicas": 1}, nil)
+	require.NoError(t, err)
+	assert.False(t, resolved)
+}
@@ -0,0 +1,249 @@
+package jenkins
+
+import (
+	"fmt"
+	"strings"
+)
+
+// jenkinsfileBlock a statement of a Jenkinsfile or a block with a head such as stage('Build') and the lines of its body
+// along with the indexes of its first and last lines and of the line of its first body element in the parsed lines
+type jenkinsfileBlock struct {
+	head      string
+	body      []string
+	block     bool
+	start     int
+	end       int
+	bodyStart int
+}
+
+// declarativePipeline the lines of a declarative Jenkinsfile with the blocks of its pipeline and of its stages
+type declarativePipeline struct {
+	lines       []string
+	pipeline    *jenkinsfileBlock
+	blocks      []*jenkinsfileBlock
+	stages      *jenkinsfileBlock
+	stageBlocks []*jenkinsfileBlock
+}
+
+// parseDeclarativePipeline parses the pipeline block of a declarative Jenkinsfile and its stages
+func parseDeclarativePipeline(text string) (*declarativePipeline, error) {
+	lines := strings.Split(text, "\n")
+	blocks, err := parseJenkinsfileBlocks(lines)
+	if err != nil {
+		return nil, err
+	}
+	pipeline := findJenkinsfileBlock(blocks, "pipeline")
+	if pipeline == nil || !pipeline.block {
+		return nil, fmt.Errorf("it is not a declarative pipeline")
+	}
+	pipelineBlocks, err := parseJenkinsfileBlocks(pipeline.body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the pipeline: %v", err)
+	}
+	stages := findJenkinsfileBlock(pipelineBlocks, "stages")
+	if stages == nil || !stages.block {
+		return nil, fmt.Errorf("it has no stages")
+	}
+	stageBlocks, err := parseJenkinsfileBlocks(stages.body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the stages: %v", err)
+	}
+	return &declarativePipeline{
+		lines:       lines,
+		pipeline:    pipeline,
+		blocks:      pipelineBlocks,
+		stages:      stages,
+		stageBlocks: stageBlocks,
+	}, nil
+}
+
+// find returns the block of the pipeline with the keyword
+func (p *declarativePipeline) find(keyword string) *jenkinsfileBlock {
+	return findJenkinsfileBlock(p.blocks, keyword)
+}
+
+// stageNames returns the names of the stages of the pipeline
+func (p *declarativePipeline) stageNames() []string {
+	answer := []string{}
+	for _, stage := range p.stageBlocks {
+		if stage.isStage() {
+			answer = append(answer, stage.stageName())
+		}
+	}
+	return answer
+}
+
+func (b *jenkinsfileBlock) isStage() bool {
+	return b.block && b.keyword() == "stage"
+}
+
+// stageName returns the name of a stage block such as Build for stage('Build')
+func (b *jenkinsfileBlock) stageName() string {
+	name := strings.TrimSpace(strings.TrimPrefix(b.head, "stage"))
+	name = strings.TrimSuffix(strings.TrimPrefix(name, "("), ")")
+	return strings.Trim(name, `'"`)
+}
+
+func (b *jenkinsfileBlock) keyword() string {
+	head := b.head
+	i := strings.IndexAny(head, " (")
+	if i > 0 {
+		head = head[0:i]
+	}
+	return head
+}
+
+func (b *jenkinsfileBlock) lines() []string {
+	if !b.block {
+		return []string{b.head}
+	}
+	answer := []string{b.head + " {"}
+	answer = append(answer, b.body...)
+	return append(answer, "}")
+}
+
+func findJenkinsfileBlock(blocks []*jenkinsfileBlock, keyword string) *jenkinsfileBlock {
+	for _, b := range blocks {
+		if b.keyword() == keyword {
+			return b
+		}
+	}
+	return nil
+}
+
+// parseJenkinsfileBlocks splits the lines into the statements and blocks at the top level of the lines
+func parseJenkinsfileBlocks(lines []string) ([]*jenkinsfileBlock, error) {
+	answer := []*jenkinsfileBlock{}
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+		open := braceIndex(line, '{')
+		if open < 0 {
+			answer = append(answer, &jenkinsfileBlock{head: line, start: i, end: i, bodyStart: i})
+			continue
+		}
+		head := strings.TrimSpace(line[0:open])
+		rest := line[open+1:]
+		depth := 1 + braceDepth(rest)
+		if depth <= 0 {
+			end := strings.LastIndex(rest, "}")
+			body := strings.TrimSpace(rest[0:end])
+			answer = append(answer, &jenkinsfileBlock{head: head, body: []string{body}, block: true, start: i, end: i, bodyStart: i})
+			continue
+		}
+		start := i
+		bodyStart := i + 1
+		body := []string{}
+		if strings.TrimSpace(rest) != "" {
+			body = append(body, strings.TrimSpace(rest))
+			bodyStart = i
+		}
+		for i++; i < len(lines); i++ {
+			depth += braceDepth(lines[i])
+			if depth <= 0 {
+				closing := strings.TrimSpace(lines[i])
+				if closing != "}" {
+					body = append(body, strings.TrimSpace(closing[0:strings.LastIndex(closing, "}")]))
+				}
+				break
+			}
+			body = append(body, lines[i])
+		}
+		if depth > 0 {
+			return nil, fmt.Errorf("the block %s is not closed", head)
+		}
+		answer = append(answer, &jenkinsfileBlock{head: head, body: body, block: true, start: start, end: i, bodyStart: bodyStart})
+	}
+	return answer, nil
+}
+
+// braceIndex returns the index of the first brace outside of a string or comment in the line
+func braceIndex(line string, brace rune) int {
+	answer := -1
+	scanJenkinsfileLine(line, func(i int, c rune) bool {
+		if c == brace {
+			answer = i
+			return false
+		}
+		return true
+	})
+	return answer
+}
+
+// braceDepth returns how many more braces the line opens than it closes outside of strings and comments
+func braceDepth(line string) int {
+	depth := 0
+	scanJenkinsfileLine(line, func(i int, c rune) bool {
+		if c == '{' {
+			depth++
+		} else if c == '}' {
+			depth--
+		}
+		return true
+	})
+	return depth
+}
+
+// scanJenkinsfileLine invokes the callback for each character of the line which is not in a string or comment until
+// it returns false
+func scanJenkinsfileLine(line string, callback func(i int, c rune) bool) {
+	var quote rune
+	escaped := false
+	for i, c := range line {
+		if quote != 0 {
+			if escaped {
+				escaped = false
+			} else if c == '\\' {
+				escaped = true
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		if c == '"' || c == '\'' {
+			quote = c
+			continue
+		}
+		if c == '/' && strings.HasPrefix(line[i:], "//") {
+			return
+		}
+		if !callback(i, c) {
+			return
+		}
+	}
+}
+
+// indentJenkinsfile indents the trimmed lines by two spaces for each level of nesting
+func indentJenkinsfile(lines []string) string {
+	return strings.Join(indentJenkinsfileLines(lines, "", "  "), "\n") + "\n"
+}
+
+// indentJenkinsfileLines trims the lines then indents them with the prefix and the unit for each level of nesting
+func indentJenkinsfileLines(lines []string, prefix string, unit string) []string {
+	answer := []string{}
+	depth := 0
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		change := braceDepth(line)
+		indent := depth
+		if strings.HasPrefix(line, "}") {
+			indent--
+		}
+		if indent < 0 {
+			indent = 0
+		}
+		if line != "" {
+			line = prefix + strings.Repeat(unit, indent) + line
+		}
+		answer = append(answer, line)
+		depth += change
+	}
+	return answer
+}
+
+// leadingWhitespace returns the whitespace the line is indented with
+func leadingWhitespace(line string) string {
+	return line[0 : len(line)-len(strings.TrimLeft(line, " \t"))]
+}
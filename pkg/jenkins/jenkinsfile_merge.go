@@ -0,0 +1,191 @@
+package jenkins
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// releaseStepRegex matches the steps of a pack Jenkinsfile which release and promote the application
+var releaseStepRegex = regexp.MustCompile(`\bjx (step tag|step helm release|promote)\b`)
+
+// promoteStepRegex matches the step which promotes the application to the environments
+var promoteStepRegex = regexp.MustCompile(`\bjx promote\b`)
+
+// jenkinsfileInsertion the lines to insert into a Jenkinsfile before the line at the index, replacing the given number
+// of lines
+type jenkinsfileInsertion struct {
+	index   int
+	replace int
+	lines   []string
+}
+
+// AddReleaseStages returns the Jenkinsfile with the stages of the pack Jenkinsfile which release and promote the
+// application appended if the Jenkinsfile does not promote the application yet. The rest of the Jenkinsfile is left
+// as it is so the appended stages define the environment variables of the pack which the Jenkinsfile does not
+func AddReleaseStages(jenkinsfile string, packJenkinsfile string) (string, error) {
+	return mergeJenkinsfile(jenkinsfile, packJenkinsfile, false)
+}
+
+// MergeJenkinsfile returns the Jenkinsfile with the environment variables of the pack Jenkinsfile added to its
+// environment and the stages of the pack which release and promote the application appended if the Jenkinsfile does
+// not promote the application yet, keeping the build stages of the Jenkinsfile
+func MergeJenkinsfile(jenkinsfile string, packJenkinsfile string) (string, error) {
+	return mergeJenkinsfile(jenkinsfile, packJenkinsfile, true)
+}
+
+func mergeJenkinsfile(jenkinsfile string, packJenkinsfile string, mergeEnvironment bool) (string, error) {
+	p, err := parseDeclarativePipeline(jenkinsfile)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse the Jenkinsfile: %v", err)
+	}
+	pack, err := parseDeclarativePipeline(packJenkinsfile)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse the Jenkinsfile of the pack: %v", err)
+	}
+
+	envLines := []string{}
+	if packEnvironment := pack.find("environment"); packEnvironment != nil {
+		names := p.environmentNames()
+		for _, line := range packEnvironment.body {
+			name := environmentName(line)
+			if name != "" && util.StringArrayIndex(names, name) < 0 {
+				envLines = append(envLines, strings.TrimSpace(line))
+			}
+		}
+	}
+
+	stages := []*jenkinsfileBlock{}
+	if !promoteStepRegex.MatchString(jenkinsfile) {
+		names := p.stageNames()
+		for _, stage := range pack.stageBlocks {
+			if stage.isStage() && util.StringArrayIndex(names, stage.stageName()) < 0 && releaseStepRegex.MatchString(strings.Join(stage.body, "\n")) {
+				stages = append(stages, stage)
+			}
+		}
+	}
+	if len(stages) == 0 && (!mergeEnvironment || len(envLines) == 0) {
+		return jenkinsfile, nil
+	}
+
+	pipelineIndent := leadingWhitespace(p.lines[p.line(p.stages.start)])
+	unit := "  "
+	if len(p.stageBlocks) > 0 {
+		stageIndent := leadingWhitespace(p.lines[p.stageLine(p.stageBlocks[0].start)])
+		if strings.HasPrefix(stageIndent, pipelineIndent) && len(stageIndent) > len(pipelineIndent) {
+			unit = strings.TrimPrefix(stageIndent, pipelineIndent)
+		}
+	}
+
+	insertions := []jenkinsfileInsertion{}
+	if len(stages) > 0 {
+		lines := []string{}
+		for _, stage := range stages {
+			lines = append(lines, stage.head+" {")
+			if !mergeEnvironment && len(envLines) > 0 {
+				lines = append(lines, environmentLines(stage, envLines)...)
+			} else {
+				lines = append(lines, stage.body...)
+			}
+			lines = append(lines, "}")
+		}
+		insertions = append(insertions, jenkinsfileInsertion{
+			index: p.line(p.stages.end),
+			lines: indentJenkinsfileLines(lines, pipelineIndent+unit, unit),
+		})
+	}
+	if mergeEnvironment && len(envLines) > 0 {
+		environment := p.find("environment")
+		if environment == nil {
+			index := p.pipeline.bodyStart
+			if agent := p.find("agent"); agent != nil {
+				index = p.line(agent.end) + 1
+			}
+			lines := append(append([]string{"environment {"}, envLines...), "}")
+			insertions = append(insertions, jenkinsfileInsertion{
+				index: index,
+				lines: indentJenkinsfileLines(lines, pipelineIndent, unit),
+			})
+		} else if environment.start == environment.end {
+			lines := append(append([]string{"environment {"}, environment.body...), envLines...)
+			lines = append(lines, "}")
+			insertions = append(insertions, jenkinsfileInsertion{
+				index:   p.line(environment.start),
+				replace: 1,
+				lines:   indentJenkinsfileLines(lines, pipelineIndent, unit),
+			})
+		} else {
+			insertions = append(insertions, jenkinsfileInsertion{
+				index: p.line(environment.end),
+				lines: indentJenkinsfileLines(envLines, pipelineIndent+unit, unit),
+			})
+		}
+	}
+
+	sort.Slice(insertions, func(i, j int) bool {
+		return insertions[i].index > insertions[j].index
+	})
+	lines := p.lines
+	for _, insertion := range insertions {
+		answer := append([]string{}, lines[0:insertion.index]...)
+		answer = append(answer, insertion.lines...)
+		lines = append(answer, lines[insertion.index+insertion.replace:]...)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// line returns the index in the Jenkinsfile of the line at the index in the body of the pipeline
+func (p *declarativePipeline) line(index int) int {
+	return p.pipeline.bodyStart + index
+}
+
+// stageLine returns the index in the Jenkinsfile of the line at the index in the body of the stages
+func (p *declarativePipeline) stageLine(index int) int {
+	return p.line(p.stages.bodyStart + index)
+}
+
+// environmentNames returns the names of the environment variables of the pipeline
+func (p *declarativePipeline) environmentNames() []string {
+	answer := []string{}
+	if environment := p.find("environment"); environment != nil {
+		for _, line := range environment.body {
+			if name := environmentName(line); name != "" {
+				answer = append(answer, name)
+			}
+		}
+	}
+	return answer
+}
+
+// environmentName returns the name of the environment variable defined by a line of an environment block
+func environmentName(line string) string {
+	i := strings.Index(line, "=")
+	if i < 0 {
+		return ""
+	}
+	return strings.TrimSpace(line[0:i])
+}
+
+// environmentLines returns the body of the stage with the environment variables added to its environment
+func environmentLines(stage *jenkinsfileBlock, envLines []string) []string {
+	parts, err := parseJenkinsfileBlocks(stage.body)
+	if err != nil {
+		return stage.body
+	}
+	environment := findJenkinsfileBlock(parts, "environment")
+	answer := []string{"environment {"}
+	answer = append(answer, envLines...)
+	if environment != nil {
+		answer = append(answer, environment.body...)
+	}
+	answer = append(answer, "}")
+	for _, part := range parts {
+		if part != environment {
+			answer = append(answer, part.lines()...)
+		}
+	}
+	return answer
+}
@@ -0,0 +1,81 @@
+package jenkins_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/jenkins"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadMergeJenkinsfiles(t *testing.T, expectedFile string) (string, string, string) {
+	dir := filepath.Join("test_data", "merge_jenkinsfile")
+	answer := []string{}
+	for _, name := range []string{"Jenkinsfile", "pack.Jenkinsfile", expectedFile} {
+		data, err := ioutil.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		answer = append(answer, string(data))
+	}
+	return answer[0], answer[1], answer[2]
+}
+
+func TestAddReleaseStages(t *testing.T) {
+	t.Parallel()
+	jenkinsfile, packJenkinsfile, expected := loadMergeJenkinsfiles(t, "expected.keep.Jenkinsfile")
+
+	actual, err := jenkins.AddReleaseStages(jenkinsfile, packJenkinsfile)
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	again, err := jenkins.AddReleaseStages(actual, packJenkinsfile)
+	require.NoError(t, err)
+	assert.Equal(t, actual, again, "the stages are only added if the Jenkinsfile does not promote")
+}
+
+func TestMergeJenkinsfile(t *testing.T) {
+	t.Parallel()
+	jenkinsfile, packJenkinsfile, expected := loadMergeJenkinsfiles(t, "expected.merge.Jenkinsfile")
+
+	actual, err := jenkins.MergeJenkinsfile(jenkinsfile, packJenkinsfile)
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	again, err := jenkins.MergeJenkinsfile(actual, packJenkinsfile)
+	require.NoError(t, err)
+	assert.Equal(t, actual, again)
+}
+
+func TestMergeJenkinsfileAddsEnvironmentBlock(t *testing.T) {
+	t.Parallel()
+	_, packJenkinsfile, _ := loadMergeJenkinsfiles(t, "expected.merge.Jenkinsfile")
+	jenkinsfile := `pipeline {
+  agent any
+  stages {
+    stage('Build') {
+      steps {
+        sh "make"
+      }
+    }
+  }
+}
+`
+	actual, err := jenkins.MergeJenkinsfile(jenkinsfile, packJenkinsfile)
+	require.NoError(t, err)
+	assert.Contains(t, actual, `  agent any
+  environment {
+    ORG = 'REPLACE_ME_ORG'
+    APP_NAME = 'REPLACE_ME_APP_NAME'
+    CHARTMUSEUM_CREDS = credentials('jenkins-x-chartmuseum')
+  }
+  stages {`)
+	assert.Contains(t, actual, "    stage('Promote to Environments') {")
+}
+
+func TestMergeJenkinsfileFailsForScriptedPipelines(t *testing.T) {
+	t.Parallel()
+	_, packJenkinsfile, _ := loadMergeJenkinsfiles(t, "expected.merge.Jenkinsfile")
+	_, err := jenkins.MergeJenkinsfile("node {\n  sh 'make'\n}\n", packJenkinsfile)
+	assert.Error(t, err)
+}
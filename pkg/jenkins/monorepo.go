@@ -0,0 +1,122 @@
+package jenkins
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MonorepoApp an application in a directory of a monorepo and the declarative Jenkinsfile generated for it
+type MonorepoApp struct {
+	// Dir the slash separated path of the application relative to the root of the monorepo
+	Dir string
+	// Name the name of the application which its release and chart are named after
+	Name string
+	// Jenkinsfile the declarative Jenkinsfile which builds the application from the root of its directory
+	Jenkinsfile string
+}
+
+var gitStepRegex = regexp.MustCompile(`^git\s*[('"]`)
+
+// MonorepoJenkinsfile combines the declarative Jenkinsfiles of the applications of a monorepo into a single
+// Jenkinsfile. Each application gets its own stages which run in the directory of the application with the agent and
+// environment of its Jenkinsfile, and only when the files of its directory changed
+func MonorepoJenkinsfile(apps []MonorepoApp) (string, error) {
+	lines := []string{
+		"// the stages of each application of the monorepo only run when the files in its directory change",
+		"pipeline {",
+		"agent none",
+		"stages {",
+	}
+	for _, app := range apps {
+		stages, err := monorepoAppStages(app)
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, stages...)
+	}
+	lines = append(lines, "}", "}")
+	return indentJenkinsfile(lines), nil
+}
+
+// monorepoAppStages returns the stages of the Jenkinsfile of the application rewritten to run in its directory
+func monorepoAppStages(app MonorepoApp) ([]string, error) {
+	p, err := parseDeclarativePipeline(app.Jenkinsfile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the Jenkinsfile of %s: %v", app.Dir, err)
+	}
+	agent := p.find("agent")
+	environment := p.find("environment")
+	post := p.find("post")
+
+	answer := []string{}
+	for _, stage := range p.stageBlocks {
+		if !stage.isStage() {
+			continue
+		}
+		name := stage.stageName()
+		parts, err := parseJenkinsfileBlocks(stage.body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the stage %s of the Jenkinsfile of %s: %v", name, app.Dir, err)
+		}
+
+		answer = append(answer, fmt.Sprintf("stage('%s: %s') {", app.Name, name))
+		if findJenkinsfileBlock(parts, "agent") == nil && agent != nil {
+			answer = append(answer, agent.lines()...)
+		}
+		envLines := []string{}
+		if environment != nil {
+			envLines = append(envLines, environment.body...)
+		}
+		if stageEnvironment := findJenkinsfileBlock(parts, "environment"); stageEnvironment != nil {
+			envLines = append(envLines, stageEnvironment.body...)
+		}
+		if len(envLines) > 0 {
+			answer = append(answer, "environment {")
+			answer = append(answer, envLines...)
+			answer = append(answer, "}")
+		}
+		answer = append(answer,
+			"when {",
+			"beforeAgent true",
+			"anyOf {",
+			fmt.Sprintf(`changeset "%s/**"`, app.Dir),
+			"expression { currentBuild.previousBuild == null }",
+			"}")
+		if when := findJenkinsfileBlock(parts, "when"); when != nil {
+			answer = append(answer, when.body...)
+		}
+		answer = append(answer, "}")
+
+		for _, part := range parts {
+			switch part.keyword() {
+			case "agent", "environment", "when":
+			case "steps":
+				answer = append(answer, "steps {", fmt.Sprintf("dir('%s') {", app.Dir))
+				answer = append(answer, rewriteGitSteps(part.body)...)
+				answer = append(answer, "}", "}")
+			default:
+				answer = append(answer, part.lines()...)
+			}
+		}
+		if findJenkinsfileBlock(parts, "post") == nil && post != nil {
+			answer = append(answer, post.lines()...)
+		}
+		answer = append(answer, "}")
+	}
+	return answer, nil
+}
+
+// rewriteGitSteps makes the git steps which check out the repository do so in the root of the workspace rather than
+// in the directory of the application
+func rewriteGitSteps(lines []string) []string {
+	answer := []string{}
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if gitStepRegex.MatchString(trimmed) {
+			line = "dir(env.WORKSPACE) { " + trimmed + " }"
+		}
+		answer = append(answer, line)
+	}
+	return answer
+}
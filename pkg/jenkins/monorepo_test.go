@@ -0,0 +1,42 @@
+package jenkins_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/jenkins"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonorepoJenkinsfile(t *testing.T) {
+	t.Parallel()
+	dir := filepath.Join("test_data", "monorepo")
+	data, err := ioutil.ReadFile(filepath.Join(dir, "Jenkinsfile"))
+	require.NoError(t, err)
+	expected, err := ioutil.ReadFile(filepath.Join(dir, "expected.Jenkinsfile"))
+	require.NoError(t, err)
+
+	actual, err := jenkins.MonorepoJenkinsfile([]jenkins.MonorepoApp{
+		{
+			Dir:         "services/foo",
+			Name:        "myrepo-foo",
+			Jenkinsfile: string(data),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), actual)
+}
+
+func TestMonorepoJenkinsfileFailsForScriptedPipelines(t *testing.T) {
+	t.Parallel()
+	_, err := jenkins.MonorepoJenkinsfile([]jenkins.MonorepoApp{
+		{
+			Dir:         "services/bar",
+			Name:        "myrepo-bar",
+			Jenkinsfile: "node {\n  sh 'make'\n}\n",
+		},
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,350 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	optionRepository = "repository"
+	optionPassword   = "password"
+)
+
+var (
+	addAppLong = templates.LongDesc(`
+		Adds an app, which is a helm chart, to the development environment
+
+		If the development environment uses GitOps the chart and its values are added to the requirements.yaml and
+		values.yaml files of its Git repository via a Pull Request. Otherwise the chart is installed via helm.
+
+		The credentials of a private chart repository are stored in a Secret which is used when the chart is installed.
+		Values which contain credentials, such as passwords and tokens, are never committed in plain text: they are moved
+		to a Secret and replaced with secret://<secret>/<key> URLs which are resolved when the chart is installed.
+
+		In interactive mode the changes to the Git repository are shown before the Pull Request is created.
+`)
+
+	addAppExample = templates.Examples(`
+		# adds the latest version of a chart from the default chart repository
+		jx add app jx-app-jacoco
+
+		# adds a chart from a private chart repository with custom values
+		jx add app mychart --version 1.2.3 --repository https://charts.acme.com --username admin --password secret \
+			--values myvalues.yaml --set replicaCount=2
+	`)
+)
+
+// AddAppOptions the options for the add app command
+type AddAppOptions struct {
+	AddOptions
+
+	Version     string
+	Alias       string
+	Repository  string
+	Username    string
+	Password    string
+	ValueFiles  []string
+	SetValues   []string
+	Namespace   string
+	ReleaseName string
+	HelmUpdate  bool
+
+	// allow git to be configured externally before a PR is created
+	ConfigureGitCallback ConfigureGitFolderFn
+}
+
+// NewCmdAddApp creates a command object for the "add app" command
+func NewCmdAddApp(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &AddAppOptions{
+		AddOptions: AddOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "app [chart]",
+		Short:   "Adds an app to the development environment",
+		Long:    addAppLong,
+		Example: addAppExample,
+		Aliases: []string{"apps"},
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.Version, "version", "", "", "The version of the chart. Defaults to the latest version")
+	cmd.Flags().StringVarP(&options.Alias, "alias", "", "", "The alias of the chart in the requirements of the environment")
+	cmd.Flags().StringVarP(&options.Repository, optionRepository, "", DEFAULT_CHARTMUSEUM_URL, "The URL of the chart repository")
+	cmd.Flags().StringVarP(&options.Username, optionUsername, "", "", "The username of a private chart repository")
+	cmd.Flags().StringVarP(&options.Password, optionPassword, "", "", "The password of a private chart repository")
+	cmd.Flags().StringArrayVarP(&options.ValueFiles, "values", "f", []string{}, "The values files of the chart, later files take precedence")
+	cmd.Flags().StringArrayVarP(&options.SetValues, "set", "s", []string{}, "The values of the chart which take precedence over the values files (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The namespace to install the chart into if the development environment does not use GitOps. Defaults to the namespace of the development environment")
+	cmd.Flags().StringVarP(&options.ReleaseName, optionRelease, "r", "", "The release name if the development environment does not use GitOps. Defaults to the alias or name of the chart")
+	cmd.Flags().BoolVarP(&options.HelmUpdate, "helm-update", "", true, "Should we run helm update first to ensure we use the latest version")
+	options.addCommonFlags(cmd)
+	return cmd
+}
+
+// Run implements this command
+func (o *AddAppOptions) Run() error {
+	if len(o.Args) == 0 {
+		return o.Cmd.Help()
+	}
+	if len(o.Args) > 1 {
+		return fmt.Errorf("expected a single chart but got %s", strings.Join(o.Args, ", "))
+	}
+	chart := o.Args[0]
+	if o.Repository == "" {
+		return util.MissingOption(optionRepository)
+	}
+	if o.Password != "" && o.Username == "" {
+		return util.MissingOption(optionUsername)
+	}
+	values, err := o.appValues()
+	if err != nil {
+		return err
+	}
+
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	jxClient, _, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	env, err := kube.GetEnvironment(jxClient, ns, kube.LabelValueDevEnvironment)
+	if err != nil {
+		return err
+	}
+
+	if o.Username != "" {
+		_, err = kube.CreateOrUpdateChartRepositorySecret(kubeClient, ns, &kube.ChartRepositoryCredentials{
+			URL:      o.Repository,
+			Username: o.Username,
+			Password: o.Password,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to store the credentials of chart repository %s", o.Repository)
+		}
+		log.Infof("Stored the credentials of chart repository %s in Secret %s\n", util.ColorInfo(o.Repository), util.ColorInfo(kube.ChartRepositorySecretName(o.Repository)))
+	}
+
+	if env.Spec.Source.URL == "" {
+		return o.installApp(kubeClient, ns, chart, env)
+	}
+	return o.createAppPullRequest(kubeClient, ns, env, chart, values)
+}
+
+// appName returns the name of the app in the environment
+func (o *AddAppOptions) appName(chart string) string {
+	if o.Alias != "" {
+		return o.Alias
+	}
+	return chart
+}
+
+// appValues merges the values files and the set values of the app
+func (o *AddAppOptions) appValues() (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	for _, f := range o.ValueFiles {
+		fileValues, err := helm.LoadValuesFile(f)
+		if err != nil {
+			return nil, err
+		}
+		helm.MergeValues(values, fileValues)
+	}
+	setValues, err := helm.ParseSetValues(o.SetValues)
+	if err != nil {
+		return nil, util.InvalidOptionError("set", strings.Join(o.SetValues, ","), err)
+	}
+	helm.MergeValues(values, setValues)
+	return values, nil
+}
+
+// addAppRepository adds the chart repository to helm returning its name
+func (o *AddAppOptions) addAppRepository() (string, error) {
+	return o.addAppChartRepository(o.Repository, o.Username, o.Password)
+}
+
+// installApp installs the chart via helm into an environment which does not use GitOps
+func (o *AddAppOptions) installApp(kubeClient kubernetes.Interface, devNs string, chart string, env *v1.Environment) error {
+	repoName, err := o.addAppRepository()
+	if err != nil {
+		return err
+	}
+	version, err := o.appVersion(repoName, chart)
+	if err != nil {
+		return err
+	}
+	ns := o.Namespace
+	if ns == "" {
+		ns = env.Spec.Namespace
+	}
+	releaseName := o.ReleaseName
+	if releaseName == "" {
+		releaseName = o.appName(chart)
+	}
+	// the repositories have already been updated when looking up the latest version
+	helmUpdate := o.HelmUpdate && o.Version != ""
+	log.Infof("Installing chart %s version %s as release %s into namespace %s\n", util.ColorInfo(chart), util.ColorInfo(version), util.ColorInfo(releaseName), util.ColorInfo(ns))
+	err = o.installChartOptions(InstallChartOptions{
+		ReleaseName: releaseName,
+		Chart:       repoName + "/" + chart,
+		Version:     version,
+		Ns:          ns,
+		HelmUpdate:  helmUpdate,
+		SetValues:   o.SetValues,
+		ValueFiles:  o.ValueFiles,
+	})
+	if err != nil {
+		return err
+	}
+	// record the app so that it can be listed and upgraded later on
+	return kube.SaveApp(kubeClient, devNs, &kube.App{
+		Name:        o.appName(chart),
+		Chart:       chart,
+		Repository:  o.Repository,
+		Version:     version,
+		ReleaseName: releaseName,
+		Namespace:   ns,
+	})
+}
+
+// appVersion returns the version of the chart to add which defaults to its latest version
+func (o *AddAppOptions) appVersion(repoName string, chart string) (string, error) {
+	if o.Version != "" {
+		return o.Version, nil
+	}
+	if o.HelmUpdate {
+		err := o.Helm().UpdateRepo()
+		if err != nil {
+			return "", errors.Wrap(err, "failed to update the helm repositories")
+		}
+	}
+	version, err := o.latestChartVersion(repoName, chart)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to find the latest version of chart %s in repository %s, please specify the --version", chart, o.Repository)
+	}
+	return version, nil
+}
+
+// createAppPullRequest adds the chart and its values to the source code of the GitOps environment via a Pull Request
+func (o *AddAppOptions) createAppPullRequest(kubeClient kubernetes.Interface, ns string, env *v1.Environment, chart string, values map[string]interface{}) error {
+	repoName, err := o.addAppRepository()
+	if err != nil {
+		return err
+	}
+	version, err := o.appVersion(repoName, chart)
+	if err != nil {
+		return err
+	}
+
+	app := o.appName(chart)
+	secretName := kube.ToValidName(app + "-values")
+	secretValues := helm.MoveSecretValues(values, secretName)
+
+	modifyRequirementsFn := func(requirements *helm.Requirements) error {
+		requirements.SetAppVersion(chart, version, o.Repository, o.Alias)
+		return nil
+	}
+	modifyChartFn := func(chartDir string) error {
+		if len(values) == 0 {
+			return nil
+		}
+		return addAppValues(filepath.Join(chartDir, helm.ValuesFileName), app, values)
+	}
+	var confirmFn ConfirmEnvironmentChangesFn
+	if !o.BatchMode {
+		confirmFn = o.confirmEnvironmentChanges
+	}
+	branchName := "add-app-" + app + "-" + version
+	title := fmt.Sprintf("Add %s %s", app, version)
+	message := fmt.Sprintf("Add app %s version %s from %s", app, version, o.Repository)
+	info, err := o.createConfirmedEnvironmentPullRequest(env, modifyRequirementsFn, modifyChartFn, confirmFn, branchName, title, message, nil, o.ConfigureGitCallback)
+	if err != nil || info == nil || len(secretValues) == 0 {
+		return err
+	}
+	// the secret values are only stored once the Pull Request which refers to them has been created
+	err = createOrUpdateAppValuesSecret(kubeClient, ns, secretName, secretValues)
+	if err != nil {
+		return errors.Wrapf(err, "failed to store the secret values of app %s", app)
+	}
+	log.Infof("Stored the values %s in Secret %s\n", util.ColorInfo(strings.Join(util.SortedMapKeys(secretValues), ", ")), util.ColorInfo(secretName))
+	return nil
+}
+
+// addAppValues merges the values of the app into the values file of an environment
+func addAppValues(valuesFile string, app string, values map[string]interface{}) error {
+	envValues := map[string]interface{}{}
+	exists, err := util.FileExists(valuesFile)
+	if err != nil {
+		return err
+	}
+	if exists {
+		envValues, err = helm.LoadValuesFile(valuesFile)
+		if err != nil {
+			return err
+		}
+	}
+	helm.MergeValues(envValues, map[string]interface{}{app: values})
+	return helm.SaveValuesFile(valuesFile, envValues)
+}
+
+// createOrUpdateAppValuesSecret stores the secret values of an app in the Secret with the given name
+func createOrUpdateAppValuesSecret(kubeClient kubernetes.Interface, ns string, name string, values map[string]string) error {
+	secrets := kubeClient.CoreV1().Secrets(ns)
+	secret, err := secrets.Get(name, metav1.GetOptions{})
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return err
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					kube.LabelKind: kube.ValueKindAppValues,
+				},
+			},
+			Data: map[string][]byte{},
+		}
+		for k, v := range values {
+			secret.Data[k] = []byte(v)
+		}
+		_, err = secrets.Create(secret)
+		return err
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	for k, v := range values {
+		secret.Data[k] = []byte(v)
+	}
+	_, err = secrets.Update(secret)
+	return err
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAddAppValues(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-add-app")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	valuesFile := filepath.Join(dir, "myvalues.yaml")
+	require.NoError(t, ioutil.WriteFile(valuesFile, []byte("replicaCount: 1\ndatabase:\n  user: app\n  password: s3cr3t\n"), 0644))
+	o := &AddAppOptions{
+		ValueFiles: []string{valuesFile},
+		SetValues:  []string{"replicaCount=2"},
+	}
+	values, err := o.appValues()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), values["replicaCount"], "set values should take precedence over values files")
+
+	secretValues := helm.MoveSecretValues(values, "myapp-values")
+	client := fake.NewSimpleClientset()
+	require.NoError(t, createOrUpdateAppValuesSecret(client, "jx", "myapp-values", secretValues))
+	secret, err := client.CoreV1().Secrets("jx").Get("myapp-values", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", string(secret.Data["database.password"]))
+	assert.Equal(t, kube.ValueKindAppValues, secret.Labels[kube.LabelKind])
+
+	envValuesFile := filepath.Join(dir, helm.ValuesFileName)
+	require.NoError(t, ioutil.WriteFile(envValuesFile, []byte("expose:\n  config:\n    exposer: Ingress\n"), 0644))
+	require.NoError(t, addAppValues(envValuesFile, "myapp", values))
+	data, err := ioutil.ReadFile(envValuesFile)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "s3cr3t", "credentials should never be committed")
+	envValues, err := helm.LoadValuesFile(envValuesFile)
+	require.NoError(t, err)
+	assert.Contains(t, envValues, "expose")
+	app := envValues["myapp"].(map[string]interface{})
+	assert.Equal(t, "secret://myapp-values/database.password", app["database"].(map[string]interface{})["password"])
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+)
+
+// ApproveOptions is the start of the data required to perform the operation.  As new fields are added, add them here instead of
+// referencing the cmd.Flags()
+type ApproveOptions struct {
+	CommonOptions
+}
+
+var (
+	approve_long = templates.LongDesc(`
+		Approves a change which is waiting for the approval of a user
+
+`)
+
+	approve_example = templates.Examples(`
+		jx approve promotion --env production --app myapp
+	`)
+)
+
+// NewCmdApprove creates a command object for the generic "approve" action
+func NewCmdApprove(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &ApproveOptions{
+		CommonOptions: CommonOptions{
+			Factory: f,
+			In:      in,
+			Out:     out,
+			Err:     errOut,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "approve TYPE [flags]",
+		Short:   "Approves a change waiting for approval",
+		Long:    approve_long,
+		Example: approve_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(NewCmdApprovePromotion(f, in, out, errOut))
+	return cmd
+}
+
+// Run implements this command
+func (o *ApproveOptions) Run() error {
+	return o.Cmd.Help()
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ApprovePromotionOptions the options for the approve promotion command
+type ApprovePromotionOptions struct {
+	CommonOptions
+
+	Environment string
+	Application string
+}
+
+var (
+	approvePromotionLong = templates.LongDesc(`
+		Approves the pending promotion of an application to an environment which requires approval by merging its
+		Pull Request.
+
+		The users who approve promotions to an environment are configured in the team settings via
+		'jx edit approvers'. An approver can also approve a promotion by commenting '/approve promotion', or the
+		comment configured via 'jx edit promotionapprovalcomment', on the Pull Request.
+
+`)
+
+	approvePromotionExample = templates.Examples(`
+		# approve the pending promotion of myapp to production
+		jx approve promotion --env production --app myapp
+	`)
+)
+
+// NewCmdApprovePromotion creates the command
+func NewCmdApprovePromotion(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &ApprovePromotionOptions{
+		CommonOptions: CommonOptions{
+			Factory: f,
+			In:      in,
+			Out:     out,
+			Err:     errOut,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "promotion",
+		Short:   "Approves the pending promotion of an application to an environment",
+		Long:    approvePromotionLong,
+		Example: approvePromotionExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&options.Environment, optionEnvironment, "e", "", "The environment the application is being promoted to")
+	cmd.Flags().StringVarP(&options.Application, optionApplication, "a", "", "The application being promoted")
+	options.addCommonFlags(cmd)
+	return cmd
+}
+
+// Run implements this command
+func (o *ApprovePromotionOptions) Run() error {
+	if o.Environment == "" {
+		return util.MissingOption(optionEnvironment)
+	}
+	if o.Application == "" {
+		return util.MissingOption(optionApplication)
+	}
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	env, err := kube.GetEnvironment(jxClient, ns, o.Environment)
+	if err != nil {
+		return err
+	}
+	if env.Spec.Source.URL == "" {
+		return fmt.Errorf("the environment %s has no Git repository", env.Name)
+	}
+	approvers, _, err := o.promotionApprovers(env.Name)
+	if err != nil {
+		return err
+	}
+	if len(approvers) == 0 {
+		return fmt.Errorf("promotions to the environment %s do not require approval", env.Name)
+	}
+
+	activities := jxClient.JenkinsV1().PipelineActivities(ns)
+	activityList, err := activities.List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	activity, step := findPendingPromotion(activityList.Items, o.Application, env.Name)
+	if activity == nil {
+		return fmt.Errorf("there is no promotion of %s to the environment %s waiting for approval", o.Application, env.Name)
+	}
+	prURL := step.PullRequest.PullRequestURL
+	prNumber, err := pullRequestNumberFromURL(prURL)
+	if err != nil {
+		return err
+	}
+
+	gitInfo, err := gits.ParseGitURL(env.Spec.Source.URL)
+	if err != nil {
+		return err
+	}
+	gitProvider, err := o.gitProviderForURL(env.Spec.Source.URL, "environment repository")
+	if err != nil {
+		return err
+	}
+	pr, err := gitProvider.GetPullRequest(gitInfo.Organisation, gitInfo, prNumber)
+	if err != nil {
+		return err
+	}
+	if pr.Merged != nil && *pr.Merged {
+		return fmt.Errorf("the promotion Pull Request %s is already merged", prURL)
+	}
+	if pr.IsClosed() {
+		return fmt.Errorf("the promotion Pull Request %s is closed", prURL)
+	}
+
+	user := gitProvider.CurrentUsername()
+	if util.StringArrayIndex(approvers, user) < 0 {
+		return fmt.Errorf("the user %s is not one of the approvers of promotions to the environment %s: %s", user, env.Name, strings.Join(approvers, ", "))
+	}
+	err = gitProvider.MergePullRequest(pr, fmt.Sprintf("jx approve promotion merged promotion PR approved by %s", user))
+	if err != nil {
+		return fmt.Errorf("failed to merge the promotion Pull Request %s: %s", prURL, err)
+	}
+	log.Infof("Approved the promotion of %s to %s by merging Pull Request %s\n", util.ColorInfo(o.Application), util.ColorInfo(env.Name), util.ColorInfo(prURL))
+
+	step.PullRequest.ApprovedBy = user
+	step.PullRequest.ApprovedTimestamp = &metav1.Time{Time: time.Now()}
+	_, err = activities.Update(activity)
+	if err != nil {
+		log.Warnf("Failed to update PipelineActivity %s: %s\n", activity.Name, err)
+	}
+	return nil
+}
+
+// findPendingPromotion returns the most recent PipelineActivity with a promotion of the application to the environment
+// whose Pull Request has not completed yet
+func findPendingPromotion(activities []v1.PipelineActivity, app string, envName string) (*v1.PipelineActivity, *v1.PromoteActivityStep) {
+	var answer *v1.PipelineActivity
+	var answerStep *v1.PromoteActivityStep
+	for i := range activities {
+		activity := &activities[i]
+		if activity.RepositoryName() != app {
+			continue
+		}
+		for _, s := range activity.Spec.Steps {
+			promote := s.Promote
+			if promote == nil || promote.Environment != envName || promote.PullRequest == nil {
+				continue
+			}
+			if promote.PullRequest.PullRequestURL == "" || promote.PullRequest.CompletedTimestamp != nil {
+				continue
+			}
+			if answer == nil || answer.CreationTimestamp.Before(&activity.CreationTimestamp) {
+				answer = activity
+				answerStep = promote
+			}
+		}
+	}
+	return answer, answerStep
+}
+
+// pullRequestNumberFromURL returns the number of the Pull Request at the end of its URL
+func pullRequestNumberFromURL(prURL string) (int, error) {
+	paths := strings.Split(strings.TrimSuffix(prURL, "/"), "/")
+	number, err := strconv.Atoi(paths[len(paths)-1])
+	if err != nil {
+		return 0, fmt.Errorf("failed to find the Pull Request number in URL %s", prURL)
+	}
+	return number, nil
+}
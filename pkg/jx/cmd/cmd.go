@@ -4,6 +4,7 @@ import (
 	"io"
 	"strings"
 
+	"github.com/jenkins-x/jx/pkg/gits"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/version"
 	"github.com/spf13/cobra"
@@ -34,11 +35,18 @@ func NewJXCommand(f Factory, in terminal.FileReader, out terminal.FileWriter, er
 		*/
 	}
 
+	gitAPIQPS := 0.0
+	cmds.PersistentFlags().Float64VarP(&gitAPIQPS, "git-api-qps", "", 0, "The maximum number of requests per second sent to the GitHub API, e.g. for scripted bulk operations. Zero for no limit")
+	cmds.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		gits.SetGitAPIQPS(gitAPIQPS)
+	}
+
 	createCommands := NewCmdCreate(f, in, out, err)
 	deleteCommands := NewCmdDelete(f, in, out, err)
 	getCommands := NewCmdGet(f, in, out, err)
 	editCommands := NewCmdEdit(f, in, out, err)
 	updateCommands := NewCmdUpdate(f, in, out, err)
+	addCommands := NewCmdAdd(f, in, out, err)
 
 	installCommands := []*cobra.Command{
 		NewCmdInstall(f, in, out, err),
@@ -70,6 +78,7 @@ func NewJXCommand(f Factory, in terminal.FileReader, out terminal.FileWriter, er
 	environmentsCommands := []*cobra.Command{
 		NewCmdPreview(f, in, out, err),
 		NewCmdPromote(f, in, out, err),
+		NewCmdApprove(f, in, out, err),
 	}
 	environmentsCommands = append(environmentsCommands, findCommands("environment", createCommands, deleteCommands, editCommands, getCommands)...)
 
@@ -132,6 +141,7 @@ func NewJXCommand(f Factory, in terminal.FileReader, out terminal.FileWriter, er
 				getCommands,
 				editCommands,
 				createCommands,
+				addCommands,
 				updateCommands,
 				deleteCommands,
 				NewCmdStart(f, in, out, err),
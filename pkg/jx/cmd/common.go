@@ -56,11 +56,15 @@ type CommonOptions struct {
 	LogLevel               string
 	Headless               bool
 	NoBrew                 bool
+	SkipChecksumVerify     bool
+	DownloadCAFile         string
 	InstallDependencies    bool
 	SkipAuthSecretsMerge   bool
 	ServiceAccount         string
 	Username               string
 	ExternalJenkinsBaseURL string
+	// CommandTimeout the maximum duration of the commands run via the runCommand helpers, zero for no timeout
+	CommandTimeout time.Duration
 
 	// common cached clients
 	KubeClientCached    kubernetes.Interface
@@ -71,6 +75,7 @@ type CommonOptions struct {
 	jenkinsClient       gojenkins.JenkinsClient
 	GitClient           gits.Gitter
 	helm                helm.Helmer
+	offlineConfig       *config.OfflineConfig
 
 	Prow
 }
@@ -119,9 +124,15 @@ func (options *CommonOptions) addCommonFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&options.LogLevel, "log-level", "", logrus.InfoLevel.String(), "Logging level. Possible values - panic, fatal, error, warning, info, debug.")
 	cmd.Flags().BoolVarP(&options.Headless, "headless", "", false, "Enable headless operation if using browser automation")
 	cmd.Flags().BoolVarP(&options.NoBrew, "no-brew", "", false, "Disables the use of brew on MacOS to install or upgrade command line dependencies")
+	cmd.Flags().BoolVarP(&options.SkipChecksumVerify, "skip-checksum-verify", "", false, "Skips verifying the SHA256 checksums of downloaded command line dependencies, e.g. for mirrors which do not host the checksum files")
+	cmd.Flags().StringVarP(&options.DownloadCAFile, "download-ca-file", "", "", "A PEM bundle of additional CA certificates trusted when downloading command line dependencies. Defaults to $"+util.DownloadCAFileEnvVar)
 	cmd.Flags().BoolVarP(&options.InstallDependencies, "install-dependencies", "", false, "Should any required dependencies be installed automatically")
 	cmd.Flags().BoolVarP(&options.SkipAuthSecretsMerge, "skip-auth-secrets-merge", "", false, "Skips merging a local git auth yaml file with any pipeline secrets that are found")
+	cmd.Flags().DurationVarP(&options.CommandTimeout, "command-timeout", "", 0, "The maximum duration of each command line tool run by jx, such as 30m, after which it is stopped. Zero for no timeout")
 	options.Cmd = cmd
+	cmd.PreRun = func(cmd *cobra.Command, args []string) {
+		util.SetDownloadCAFile(options.DownloadCAFile)
+	}
 }
 
 func (o *CommonOptions) CreateApiExtensionsClient() (apiextensionsclientset.Interface, error) {
@@ -254,6 +265,12 @@ func (o *CommonOptions) Helm() helm.Helmer {
 		if err != nil {
 			helmBinary = defaultHelmBin
 		}
+		helmVersion := helm.BinaryVersion(helmBinary)
+		if helmVersion == helm.V3 {
+			// helm 3 has no tiller so neither a local tiller nor the template mode are needed
+			noTiller = false
+			helmTemplate = false
+		}
 		featureFlag := "none"
 		if helmTemplate {
 			featureFlag = "template-mode"
@@ -261,7 +278,10 @@ func (o *CommonOptions) Helm() helm.Helmer {
 			featureFlag = "no-tiller-server"
 		}
 		log.Infof("Using helmBinary %s with feature flag: %s\n", util.ColorInfo(helmBinary), util.ColorInfo(featureFlag))
-		helmCLI := helm.NewHelmCLI(helmBinary, helm.V2, "", o.Verbose)
+		helmCLI := helm.NewHelmCLI(helmBinary, helmVersion, "", o.Verbose)
+		if o.offlineConfig != nil {
+			helmCLI.StableRepoURL = o.offlineConfig.ChartRepositoryURL(helm.StableRepoURL)
+		}
 		o.helm = helmCLI
 		if helmTemplate {
 			kubeClient, _, _ := o.KubeClient()
@@ -707,6 +727,14 @@ func (o *CommonOptions) expose(devNamespace, targetNamespace, password string) e
 		return fmt.Errorf("failed to copy certmanager resources from %s to %s namespace: %v", devNamespace, targetNamespace, err)
 	}
 
+	// the ingresses of the namespace need their own copy of a certificate brought by the user
+	if ic.TLSSecretName != "" && targetNamespace != devNamespace {
+		err = kube.CopyTLSSecret(o.KubeClientCached, devNamespace, targetNamespace, ic.TLSSecretName)
+		if err != nil {
+			return fmt.Errorf("failed to copy the TLS secret %s from %s to %s namespace: %v", ic.TLSSecretName, devNamespace, targetNamespace, err)
+		}
+	}
+
 	return o.runExposecontroller(devNamespace, targetNamespace, ic)
 }
 
@@ -723,6 +751,12 @@ func (o *CommonOptions) runExposecontroller(devNamespace, targetNamespace string
 	if !ic.TLS && ic.Issuer != "" {
 		exValues = append(exValues, "config.http=true")
 	}
+	if ic.TLSSecretName != "" {
+		exValues = append(exValues, "config.tlsSecretName="+ic.TLSSecretName)
+	}
+	if ic.IngressClass != "" {
+		exValues = append(exValues, "Annotations.kubernetes\\.io/ingress\\.class="+ic.IngressClass)
+	}
 
 	if len(services) > 0 {
 		serviceCfg := "config.extravalues='services: ["
@@ -756,6 +790,17 @@ func (o *CommonOptions) runExposecontroller(devNamespace, targetNamespace string
 
 }
 
+// basicAuthIngressAnnotations returns the exposecontroller ingress annotations which protect an ingress with the basic
+// auth secret using the annotation scheme of the ingress controller of the team
+func (o *CommonOptions) basicAuthIngressAnnotations(devNamespace string) string {
+	ingressKind := kube.IngressKindNginx
+	ic, err := kube.GetIngressConfig(o.KubeClientCached, devNamespace)
+	if err == nil && ic.IngressKind != "" {
+		ingressKind = ic.IngressKind
+	}
+	return kube.BasicAuthIngressAnnotations(ingressKind, kube.SecretBasicAuth)
+}
+
 // CleanExposecontrollerReources cleans expose controller resources
 func (o *CommonOptions) CleanExposecontrollerReources(ns string) {
 
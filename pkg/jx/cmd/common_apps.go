@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+)
+
+// AppVersion the current and the latest available versions of an app of the development environment
+type AppVersion struct {
+	Name          string `json:"name"`
+	Chart         string `json:"chart"`
+	Repository    string `json:"repository"`
+	Version       string `json:"version"`
+	LatestVersion string `json:"latestVersion,omitempty"`
+	ReleaseName   string `json:"releaseName,omitempty"`
+	Namespace     string `json:"namespace,omitempty"`
+
+	// repoName the name of the helm repository of the chart once it has been added
+	repoName string
+}
+
+// AppVersionList the apps output by 'jx get apps --versions -o json|yaml'
+type AppVersionList struct {
+	Items []*AppVersion `json:"items"`
+}
+
+// devEnvironmentApps returns the apps of the development environment which are the requirements of its Git repository
+// if it uses GitOps or the apps installed with 'jx add app' otherwise. The directory of the clone of the Git
+// repository is returned too, it is empty if the environment does not use GitOps
+func (o *CommonOptions) devEnvironmentApps(kubeClient kubernetes.Interface, ns string, env *v1.Environment) ([]*AppVersion, string, error) {
+	answer := []*AppVersion{}
+	if env.Spec.Source.URL == "" {
+		apps, err := kube.GetApps(kubeClient, ns)
+		if err != nil {
+			return nil, "", errors.Wrap(err, "failed to load the apps of the development environment")
+		}
+		for _, app := range apps {
+			answer = append(answer, &AppVersion{
+				Name:        app.Name,
+				Chart:       app.Chart,
+				Repository:  app.Repository,
+				Version:     app.Version,
+				ReleaseName: app.ReleaseName,
+				Namespace:   app.Namespace,
+			})
+		}
+		return answer, "", nil
+	}
+
+	dir, _, _, err := o.cloneEnvironmentRepository(env, nil)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to clone the Git repository of environment %s", env.Name)
+	}
+	requirementsFile, err := helm.FindRequirementsFileName(dir)
+	if err != nil {
+		return nil, "", err
+	}
+	requirements, err := helm.LoadRequirementsFile(requirementsFile)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to load %s", requirementsFile)
+	}
+	for _, dep := range requirements.Dependencies {
+		if dep == nil {
+			continue
+		}
+		name := dep.Alias
+		if name == "" {
+			name = dep.Name
+		}
+		answer = append(answer, &AppVersion{
+			Name:       name,
+			Chart:      dep.Name,
+			Repository: dep.Repository,
+			Version:    dep.Version,
+		})
+	}
+	return answer, dir, nil
+}
+
+// addAppChartRepository adds the chart repository to helm using the given credentials, or the stored credentials of
+// the repository if there are none, returning the name of the helm repository
+func (o *CommonOptions) addAppChartRepository(repoURL string, username string, password string) (string, error) {
+	if username == "" {
+		if c := o.chartRepositoryCredentials()[strings.TrimSuffix(repoURL, "/")]; c != nil {
+			username = c.Username
+			password = c.Password
+		}
+	}
+	repoName := kube.ToValidName(strings.TrimPrefix(kube.ChartRepositorySecretName(repoURL), "chart-repo-"))
+	err := o.addHelmRepoWithCredentialsIfMissing(repoURL, repoName, username, password)
+	if err != nil {
+		return "", err
+	}
+	repos, err := o.Helm().ListRepos()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list the helm repositories")
+	}
+	for name, u := range repos {
+		if strings.TrimSuffix(u, "/") == strings.TrimSuffix(repoURL, "/") {
+			return name, nil
+		}
+	}
+	return repoName, nil
+}
+
+// latestChartVersion returns the latest version of the chart in the helm repository
+func (o *CommonOptions) latestChartVersion(repoName string, chart string) (string, error) {
+	versions, err := o.Helm().SearchChartVersions(repoName + "/" + chart)
+	if err != nil {
+		return "", err
+	}
+	if len(versions) == 0 {
+		return "", fmt.Errorf("chart %s was not found in helm repository %s", chart, repoName)
+	}
+	return versions[0], nil
+}
+
+// findLatestAppVersions adds the chart repositories of the apps to helm and looks up the latest versions of the apps.
+// Apps whose latest version cannot be found are reported and left without a latest version
+func (o *CommonOptions) findLatestAppVersions(apps []*AppVersion, helmUpdate bool) error {
+	repoNames := map[string]string{}
+	for _, app := range apps {
+		if app.Repository == "" {
+			continue
+		}
+		repoName, ok := repoNames[app.Repository]
+		if !ok {
+			var err error
+			repoName, err = o.addAppChartRepository(app.Repository, "", "")
+			if err != nil {
+				return err
+			}
+			repoNames[app.Repository] = repoName
+		}
+		app.repoName = repoName
+	}
+	if helmUpdate && len(repoNames) > 0 {
+		err := o.Helm().UpdateRepo()
+		if err != nil {
+			return errors.Wrap(err, "failed to update the helm repositories")
+		}
+	}
+	for _, app := range apps {
+		if app.repoName == "" {
+			log.Warnf("App %s has no chart repository\n", app.Name)
+			continue
+		}
+		version, err := o.latestChartVersion(app.repoName, app.Chart)
+		if err != nil {
+			log.Warnf("Failed to find the latest version of app %s: %s\n", app.Name, err)
+			continue
+		}
+		app.LatestVersion = version
+	}
+	return nil
+}
+
+// diffAppValues prints the changes of the values computed from the values files between the current and the target
+// version of the chart of the app
+func (o *CommonOptions) diffAppValues(app *AppVersion, version string, valueFiles []string) error {
+	tmpDir, err := ioutil.TempDir("", "upgrade-app-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	currentValues, err := o.appChartValues(app, app.Version, filepath.Join(tmpDir, "current"), valueFiles)
+	if err != nil {
+		return err
+	}
+	targetValues, err := o.appChartValues(app, version, filepath.Join(tmpDir, "target"), valueFiles)
+	if err != nil {
+		return err
+	}
+	diff, err := util.UnifiedDiff(currentValues, targetValues, fmt.Sprintf("values.yaml (%s)", app.Version), fmt.Sprintf("values.yaml (%s)", version))
+	if err != nil {
+		return err
+	}
+	if diff == "" {
+		log.Infof("There are no changes to the values of app %s\n", util.ColorInfo(app.Name))
+		return nil
+	}
+	fmt.Fprintln(o.Out, util.ColorDiff(diff))
+	return nil
+}
+
+// appChartValues fetches the version of the chart of the app into the directory and computes its values merged with
+// the values files
+func (o *CommonOptions) appChartValues(app *AppVersion, version string, dir string, valueFiles []string) (string, error) {
+	chart := app.repoName + "/" + app.Chart
+	err := o.RunCommand(o.Helm().HelmBinary(), "fetch", chart, "--version", version, "--untar", "--untardir", dir)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to fetch version %s of the chart %s", version, chart)
+	}
+	return targetPlatformValues(filepath.Join(dir, app.Chart), nil, valueFiles)
+}
+
+// envAppValues returns the values of the app in the values file of the chart of a GitOps environment
+func envAppValues(dir string, app string) (map[string]interface{}, error) {
+	requirementsFile, err := helm.FindRequirementsFileName(dir)
+	if err != nil {
+		return nil, err
+	}
+	valuesFile := filepath.Join(filepath.Dir(requirementsFile), helm.ValuesFileName)
+	exists, err := util.FileExists(valuesFile)
+	if err != nil || !exists {
+		return map[string]interface{}{}, err
+	}
+	envValues, err := helm.LoadValuesFile(valuesFile)
+	if err != nil {
+		return nil, err
+	}
+	values, ok := envValues[app].(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{}, nil
+	}
+	return values, nil
+}
+
+// confirmEnvironmentChanges shows the changes to the source code of the environment and asks to confirm them
+func (o *CommonOptions) confirmEnvironmentChanges(dir string) (bool, error) {
+	diff, err := o.Git().Diff(dir)
+	if err != nil {
+		return false, err
+	}
+	log.Infof("The following changes will be made to the development environment:\n\n%s\n\n", diff)
+	return util.Confirm("Do you want to create a Pull Request with these changes?", true, "The changes are applied once the Pull Request is merged", o.In, o.Out, o.Err), nil
+}
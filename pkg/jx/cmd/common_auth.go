@@ -122,5 +122,13 @@ func (o *CommonOptions) LoadPipelineSecrets(kind, serviceKind string) (*corev1.S
 	opts := metav1.ListOptions{
 		LabelSelector: selector,
 	}
-	return kubeClient.CoreV1().Secrets(ns).List(opts)
+	secrets, err := kubeClient.CoreV1().Secrets(ns).List(opts)
+	if err != nil {
+		return secrets, err
+	}
+	err = o.loadSecretDataFromVault(secrets.Items)
+	if err != nil {
+		return nil, err
+	}
+	return secrets, nil
 }
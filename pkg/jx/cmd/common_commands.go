@@ -1,12 +1,17 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
@@ -14,6 +19,103 @@ import (
 
 // TODO Refactor to use util.Run or util.RunWithoutRetry?
 
+// commandStopGracePeriod is how long a command has to stop after jx is interrupted before it is killed
+var commandStopGracePeriod = 10 * time.Second
+
+// commandContext returns the context of the commands run by the helpers below, which has a deadline if a
+// CommandTimeout is configured
+func (o *CommonOptions) commandContext() (context.Context, context.CancelFunc) {
+	if o.CommandTimeout > 0 {
+		return context.WithTimeout(context.Background(), o.CommandTimeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+func (o *CommonOptions) runCommand(e *exec.Cmd) error {
+	ctx, cancel := o.commandContext()
+	defer cancel()
+	return runCommandWithContext(ctx, e)
+}
+
+// runCommandWithContext runs the command until it completes or the context is done, in which case the command is
+// killed. The proxy environment variables are passed to the command explicitly. Non interactive commands run in their
+// own process group so that SIGINT and SIGTERM received by jx are passed on to the command and any processes it
+// started, rather than leaving them orphaned
+func runCommandWithContext(ctx context.Context, e *exec.Cmd) error {
+	if e.Stdin == nil {
+		setProcessGroup(e)
+	}
+	if e.Env == nil {
+		e.Env = os.Environ()
+	}
+	e.Env = append(e.Env, util.ProxyEnv()...)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	start := time.Now()
+	err := e.Start()
+	if err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- e.Wait()
+	}()
+
+	commandLine := strings.Join(e.Args, " ")
+	select {
+	case err = <-done:
+		return err
+	case sig := <-signals:
+		stopCommand(e, sig, done, commandStopGracePeriod)
+		return fmt.Errorf("command '%s' was stopped by %s", commandLine, sig)
+	case <-ctx.Done():
+		killProcessGroup(e)
+		<-done
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("command '%s' timed out after %s", commandLine, time.Since(start).Round(time.Second))
+		}
+		return fmt.Errorf("command '%s' was cancelled", commandLine)
+	}
+}
+
+// stopCommand passes the signal received by jx on to the command, waiting for it to stop and killing it if it is still
+// running after the grace period. Interactive commands share the process group of the terminal so they have already
+// received a SIGINT from it
+func stopCommand(e *exec.Cmd, sig os.Signal, done <-chan error, gracePeriod time.Duration) {
+	if e.Stdin == nil || sig != os.Interrupt {
+		signalProcessGroup(e, sig)
+	}
+	select {
+	case <-done:
+	case <-time.After(gracePeriod):
+		log.Warnf("Killing command '%s' as it did not stop within %s\n", strings.Join(e.Args, " "), gracePeriod)
+		killProcessGroup(e)
+		<-done
+	}
+}
+
+func (o *CommonOptions) runCommandVerboseWithContext(ctx context.Context, name string, args ...string) error {
+	e := exec.Command(name, args...)
+	e.Stdout = o.Out
+	e.Stderr = o.Err
+	os.Setenv("PATH", util.PathWithBinary())
+	err := runCommandWithContext(ctx, e)
+	if err != nil {
+		log.Errorf("Error: Command failed  %s %s\n", name, strings.Join(args, " "))
+	}
+	return err
+}
+
+func (o *CommonOptions) runCommandQuietlyWithContext(ctx context.Context, name string, args ...string) error {
+	e := exec.Command(name, args...)
+	e.Stdout = ioutil.Discard
+	e.Stderr = ioutil.Discard
+	os.Setenv("PATH", util.PathWithBinary())
+	return runCommandWithContext(ctx, e)
+}
+
 func (o *CommonOptions) runCommandFromDir(dir, name string, args ...string) error {
 	e := exec.Command(name, args...)
 	if dir != "" {
@@ -22,7 +124,7 @@ func (o *CommonOptions) runCommandFromDir(dir, name string, args ...string) erro
 	e.Stdout = o.Out
 	e.Stderr = o.Err
 	os.Setenv("PATH", util.PathWithBinary())
-	err := e.Run()
+	err := o.runCommand(e)
 	if err != nil {
 		log.Errorf("Error: Command failed  %s %s\n", name, strings.Join(args, " "))
 	}
@@ -37,7 +139,7 @@ func (o *CommonOptions) RunCommand(name string, args ...string) error {
 		e.Stderr = o.Err
 	}
 	os.Setenv("PATH", util.PathWithBinary())
-	err := e.Run()
+	err := o.runCommand(e)
 	if err != nil {
 		log.Errorf("Error: Command failed  %s %s\n", name, strings.Join(args, " "))
 	}
@@ -49,7 +151,7 @@ func (o *CommonOptions) runCommandVerbose(name string, args ...string) error {
 	e.Stdout = o.Out
 	e.Stderr = o.Err
 	os.Setenv("PATH", util.PathWithBinary())
-	err := e.Run()
+	err := o.runCommand(e)
 	if err != nil {
 		log.Errorf("Error: Command failed  %s %s\n", name, strings.Join(args, " "))
 	}
@@ -76,7 +178,7 @@ func (o *CommonOptions) runCommandVerboseAt(dir string, name string, args ...str
 	e.Stdout = o.Out
 	e.Stderr = o.Err
 	os.Setenv("PATH", util.PathWithBinary())
-	err := e.Run()
+	err := o.runCommand(e)
 	if err != nil {
 		log.Errorf("Error: Command failed  %s %s\n", name, strings.Join(args, " "))
 	}
@@ -88,7 +190,7 @@ func (o *CommonOptions) runCommandQuietly(name string, args ...string) error {
 	e.Stdout = ioutil.Discard
 	e.Stderr = ioutil.Discard
 	os.Setenv("PATH", util.PathWithBinary())
-	return e.Run()
+	return o.runCommand(e)
 }
 
 func (o *CommonOptions) runCommandInteractive(interactive bool, name string, args ...string) error {
@@ -99,7 +201,7 @@ func (o *CommonOptions) runCommandInteractive(interactive bool, name string, arg
 		e.Stdin = os.Stdin
 	}
 	os.Setenv("PATH", util.PathWithBinary())
-	err := e.Run()
+	err := o.runCommand(e)
 	if err != nil {
 		log.Errorf("Error: Command failed  %s %s\n", name, strings.Join(args, " "))
 	}
@@ -117,7 +219,7 @@ func (o *CommonOptions) runCommandInteractiveInDir(interactive bool, dir string,
 		e.Dir = dir
 	}
 	os.Setenv("PATH", util.PathWithBinary())
-	err := e.Run()
+	err := o.runCommand(e)
 	if err != nil {
 		log.Errorf("Error: Command failed  %s %s\n", name, strings.Join(args, " "))
 	}
@@ -131,8 +233,11 @@ func (o *CommonOptions) getCommandOutput(dir string, name string, args ...string
 	if dir != "" {
 		e.Dir = dir
 	}
-	data, err := e.CombinedOutput()
-	text := string(data)
+	var output bytes.Buffer
+	e.Stdout = &output
+	e.Stderr = &output
+	err := o.runCommand(e)
+	text := output.String()
 	text = strings.TrimSpace(text)
 	if err != nil {
 		return "", fmt.Errorf("Command failed '%s %s': %s %s\n", name, strings.Join(args, " "), text, err)
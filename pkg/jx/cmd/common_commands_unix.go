@@ -0,0 +1,29 @@
+// +build !windows
+
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup starts the command in a new process group
+func setProcessGroup(e *exec.Cmd) {
+	e.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// signalProcessGroup sends the signal to the process group of the command if it has one, otherwise to the command
+func signalProcessGroup(e *exec.Cmd, sig os.Signal) {
+	s, ok := sig.(syscall.Signal)
+	if ok && e.SysProcAttr != nil && e.SysProcAttr.Setpgid {
+		syscall.Kill(-e.Process.Pid, s)
+		return
+	}
+	e.Process.Signal(sig)
+}
+
+// killProcessGroup kills the command and any processes in its process group
+func killProcessGroup(e *exec.Cmd) {
+	signalProcessGroup(e, syscall.SIGKILL)
+}
@@ -0,0 +1,68 @@
+// +build !windows
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunCommandWithContextTimesOut(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	// the background sleep keeps the output open so the command only returns quickly if its process group is killed
+	err := runCommandWithContext(ctx, exec.Command("sh", "-c", "sleep 10 & sleep 10"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "command 'sh -c sleep 10 & sleep 10' timed out after")
+	assert.True(t, time.Since(start) < 5*time.Second, "the command should be killed when the deadline passes")
+}
+
+func TestRunCommandWithContext(t *testing.T) {
+	o := &CommonOptions{CommandTimeout: 10 * time.Second}
+	output, err := o.getCommandOutput("", "sh", "-c", "echo hello")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", output)
+
+	_, err = o.getCommandOutput("", "sh", "-c", "exit 3")
+	assert.Error(t, err)
+}
+
+func TestStopCommandKillsACommandIgnoringTheSignal(t *testing.T) {
+	e := exec.Command("sh", "-c", "trap '' TERM; sleep 10")
+	setProcessGroup(e)
+	assert.NoError(t, e.Start())
+	done := make(chan error, 1)
+	go func() {
+		done <- e.Wait()
+	}()
+	// give the shell time to install its trap
+	time.Sleep(200 * time.Millisecond)
+
+	start := time.Now()
+	stopCommand(e, syscall.SIGTERM, done, 200*time.Millisecond)
+	assert.True(t, time.Since(start) < 5*time.Second, "the command should be killed after the grace period")
+}
+
+func TestStopCommandDoesNotInterruptAnInteractiveCommandAgain(t *testing.T) {
+	e := exec.Command("sh", "-c", "trap 'exit 3' INT; sleep 2 & wait")
+	e.Stdin = strings.NewReader("")
+	assert.NoError(t, e.Start())
+	done := make(chan error, 1)
+	go func() {
+		done <- e.Wait()
+	}()
+	time.Sleep(200 * time.Millisecond)
+
+	stopCommand(e, os.Interrupt, done, 200*time.Millisecond)
+	status := e.ProcessState.Sys().(syscall.WaitStatus)
+	assert.True(t, status.Signaled(), "the terminal has already sent SIGINT to interactive commands so they are only killed")
+}
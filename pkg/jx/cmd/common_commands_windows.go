@@ -0,0 +1,22 @@
+// +build windows
+
+package cmd
+
+import (
+	"os"
+	"os/exec"
+)
+
+// setProcessGroup does nothing on windows where the command is killed directly
+func setProcessGroup(e *exec.Cmd) {
+}
+
+// signalProcessGroup kills the command as signals cannot be sent to processes on windows
+func signalProcessGroup(e *exec.Cmd, sig os.Signal) {
+	e.Process.Kill()
+}
+
+// killProcessGroup kills the command
+func killProcessGroup(e *exec.Cmd) {
+	e.Process.Kill()
+}
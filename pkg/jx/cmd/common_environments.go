@@ -14,94 +14,38 @@ import (
 	"github.com/jenkins-x/jx/pkg/util"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/kubernetes"
 )
 
 // ModifyRequirementsFn callback for modifying requirements
 type ModifyRequirementsFn func(requirements *helm.Requirements) error
 
+// ModifyChartFn callback for modifying the files of the chart of an environment in the directory
+type ModifyChartFn func(chartDir string) error
+
 // ConfigureGitFolderFn callback to optionally configure git before its used for creating commits and PRs
 type ConfigureGitFolderFn func(dir string, gitInfo *gits.GitRepositoryInfo, gitAdapter gits.Gitter) error
 
-type CreateEnvPullRequestFn func(env *v1.Environment, modifyRequirementsFn ModifyRequirementsFn, branchNameText string, title string, message string, pullRequestInfo *ReleasePullRequestInfo) (*ReleasePullRequestInfo, error)
+// ConfirmEnvironmentChangesFn callback to confirm the changes to the source code of an environment in the directory
+// before they are committed
+type ConfirmEnvironmentChangesFn func(dir string) (bool, error)
 
-func (o *CommonOptions) createEnvironmentPullRequest(env *v1.Environment, modifyRequirementsFn ModifyRequirementsFn, branchNameText string, title string, message string, pullRequestInfo *ReleasePullRequestInfo, configGitFn ConfigureGitFolderFn) (*ReleasePullRequestInfo, error) {
-	var answer *ReleasePullRequestInfo
-	source := &env.Spec.Source
-	gitURL := source.URL
-	if gitURL == "" {
-		return answer, fmt.Errorf("No source git URL")
-	}
-	gitInfo, err := gits.ParseGitURL(gitURL)
-	if err != nil {
-		return answer, err
-	}
+type CreateEnvPullRequestFn func(env *v1.Environment, modifyRequirementsFn ModifyRequirementsFn, branchNameText string, title string, message string, pullRequestInfo *ReleasePullRequestInfo) (*ReleasePullRequestInfo, error)
 
-	environmentsDir, err := util.EnvironmentsDir()
-	if err != nil {
-		return answer, err
-	}
-	dir := filepath.Join(environmentsDir, gitInfo.Organisation, gitInfo.Name)
+func (o *CommonOptions) createEnvironmentPullRequest(env *v1.Environment, modifyRequirementsFn ModifyRequirementsFn, modifyChartFn ModifyChartFn, branchNameText string, title string, message string, pullRequestInfo *ReleasePullRequestInfo, configGitFn ConfigureGitFolderFn) (*ReleasePullRequestInfo, error) {
+	return o.createConfirmedEnvironmentPullRequest(env, modifyRequirementsFn, modifyChartFn, nil, branchNameText, title, message, pullRequestInfo, configGitFn)
+}
 
-	// now lets clone the fork and push it...
-	exists, err := util.FileExists(dir)
+// createConfirmedEnvironmentPullRequest creates a Pull Request on the environment like createEnvironmentPullRequest if
+// the optional confirm callback accepts the changes. No Pull Request is created if the changes are rejected
+func (o *CommonOptions) createConfirmedEnvironmentPullRequest(env *v1.Environment, modifyRequirementsFn ModifyRequirementsFn, modifyChartFn ModifyChartFn, confirmFn ConfirmEnvironmentChangesFn, branchNameText string, title string, message string, pullRequestInfo *ReleasePullRequestInfo, configGitFn ConfigureGitFolderFn) (*ReleasePullRequestInfo, error) {
+	var answer *ReleasePullRequestInfo
+	dir, gitInfo, base, err := o.cloneEnvironmentRepository(env, configGitFn)
 	if err != nil {
 		return answer, err
 	}
 
 	branchName := o.Git().ConvertToValidBranchName(branchNameText)
-	base := source.Ref
-	if base == "" {
-		base = "master"
-	}
-
-	if exists {
-		if configGitFn != nil {
-			err = configGitFn(dir, gitInfo, o.Git())
-			if err != nil {
-				return answer, err
-			}
-		}
-		// lets check the git remote URL is setup correctly
-		err = o.Git().SetRemoteURL(dir, "origin", gitURL)
-		if err != nil {
-			return answer, err
-		}
-		err = o.Git().Stash(dir)
-		if err != nil {
-			return answer, err
-		}
-		err = o.Git().Checkout(dir, base)
-		if err != nil {
-			return answer, err
-		}
-		err = o.Git().Pull(dir)
-		if err != nil {
-			return answer, err
-		}
-	} else {
-		err := os.MkdirAll(dir, DefaultWritePermissions)
-		if err != nil {
-			return answer, fmt.Errorf("Failed to create directory %s due to %s", dir, err)
-		}
-		err = o.Git().Clone(gitURL, dir)
-		if err != nil {
-			return answer, err
-		}
-		if configGitFn != nil {
-			err = configGitFn(dir, gitInfo, o.Git())
-			if err != nil {
-				return answer, err
-			}
-		}
-		if base != "master" {
-			err = o.Git().Checkout(dir, base)
-			if err != nil {
-				return answer, err
-			}
-		}
-
-		// TODO lets fork if required???
-	}
 	branchNames, err := o.Git().RemoteBranchNames(dir, "remotes/origin/")
 	if err != nil {
 		return answer, fmt.Errorf("Failed to load remote branch names: %s", err)
@@ -130,8 +74,32 @@ func (o *CommonOptions) createEnvironmentPullRequest(env *v1.Environment, modify
 	}
 
 	err = modifyRequirementsFn(requirements)
+	if err != nil {
+		return answer, err
+	}
 
 	err = helm.SaveRequirementsFile(requirementsFile, requirements)
+	if err != nil {
+		return answer, err
+	}
+
+	if modifyChartFn != nil {
+		err = modifyChartFn(filepath.Dir(requirementsFile))
+		if err != nil {
+			return answer, err
+		}
+	}
+
+	if confirmFn != nil {
+		confirmed, err := confirmFn(dir)
+		if err != nil {
+			return answer, err
+		}
+		if !confirmed {
+			log.Warnf("Discarded the changes to the source code of Environment %s\n", env.Name)
+			return answer, o.Git().Stash(dir)
+		}
+	}
 
 	err = o.Git().Add(dir, "*", "*/*")
 	if err != nil {
@@ -161,17 +129,7 @@ func (o *CommonOptions) createEnvironmentPullRequest(env *v1.Environment, modify
 		return answer, err
 	}
 
-	authConfigSvc, err := o.CreateGitAuthConfigService()
-	if err != nil {
-		return answer, err
-	}
-
-	gitKind, err := o.GitServerKind(gitInfo)
-	if err != nil {
-		return answer, err
-	}
-
-	provider, err := gitInfo.PickOrCreateProvider(authConfigSvc, "user name to submit the Pull Request", o.BatchMode, gitKind, o.Git(), o.In, o.Out, o.Err)
+	provider, err := o.environmentGitProvider(gitInfo, "user name to submit the Pull Request")
 	if err != nil {
 		return answer, err
 	}
@@ -196,6 +154,117 @@ func (o *CommonOptions) createEnvironmentPullRequest(env *v1.Environment, modify
 	}, nil
 }
 
+// cloneEnvironmentRepository clones the Git repository of the environment or pulls the latest changes of its base
+// branch into an existing clone returning the directory, the repository and the base branch
+func (o *CommonOptions) cloneEnvironmentRepository(env *v1.Environment, configGitFn ConfigureGitFolderFn) (string, *gits.GitRepositoryInfo, string, error) {
+	source := &env.Spec.Source
+	gitURL := source.URL
+	if gitURL == "" {
+		return "", nil, "", fmt.Errorf("No source git URL")
+	}
+	gitInfo, err := gits.ParseGitURL(gitURL)
+	if err != nil {
+		return "", nil, "", err
+	}
+
+	environmentsDir, err := util.EnvironmentsDir()
+	if err != nil {
+		return "", nil, "", err
+	}
+	dir := filepath.Join(environmentsDir, gitInfo.Organisation, gitInfo.Name)
+
+	// now lets clone the fork and push it...
+	exists, err := util.FileExists(dir)
+	if err != nil {
+		return "", nil, "", err
+	}
+
+	base := source.Ref
+	if base == "" {
+		base = "master"
+	}
+
+	if exists {
+		if configGitFn != nil {
+			err = configGitFn(dir, gitInfo, o.Git())
+			if err != nil {
+				return "", nil, "", err
+			}
+		}
+		// lets check the git remote URL is setup correctly
+		err = o.Git().SetRemoteURL(dir, "origin", gitURL)
+		if err != nil {
+			return "", nil, "", err
+		}
+		err = o.Git().Stash(dir)
+		if err != nil {
+			return "", nil, "", err
+		}
+		err = o.Git().Checkout(dir, base)
+		if err != nil {
+			return "", nil, "", err
+		}
+		err = o.Git().Pull(dir)
+		if err != nil {
+			return "", nil, "", err
+		}
+	} else {
+		err := os.MkdirAll(dir, DefaultWritePermissions)
+		if err != nil {
+			return "", nil, "", fmt.Errorf("Failed to create directory %s due to %s", dir, err)
+		}
+		err = o.Git().Clone(gitURL, dir)
+		if err != nil {
+			return "", nil, "", err
+		}
+		if configGitFn != nil {
+			err = configGitFn(dir, gitInfo, o.Git())
+			if err != nil {
+				return "", nil, "", err
+			}
+		}
+		if base != "master" {
+			err = o.Git().Checkout(dir, base)
+			if err != nil {
+				return "", nil, "", err
+			}
+		}
+
+		// TODO lets fork if required???
+	}
+	return dir, gitInfo, base, nil
+}
+
+// environmentGitProvider returns the Git provider for the repository of an environment
+func (o *CommonOptions) environmentGitProvider(gitInfo *gits.GitRepositoryInfo, message string) (gits.GitProvider, error) {
+	authConfigSvc, err := o.CreateGitAuthConfigService()
+	if err != nil {
+		return nil, err
+	}
+	gitKind, err := o.GitServerKind(gitInfo)
+	if err != nil {
+		return nil, err
+	}
+	return gitInfo.PickOrCreateProvider(authConfigSvc, message, o.BatchMode, gitKind, o.Git(), o.In, o.Out, o.Err)
+}
+
+// EnvironmentKubeClient returns the kube client for the cluster the environment runs in which is the current cluster
+// unless the environment has a remote cluster
+func (o *CommonOptions) EnvironmentKubeClient(env *v1.Environment) (kubernetes.Interface, error) {
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return nil, err
+	}
+	if !kube.IsRemoteEnvironment(env) {
+		return kubeClient, nil
+	}
+	client, err := kube.RemoteClusterClient(kubeClient, ns, env.Spec.RemoteCluster)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to the remote cluster of environment %s", env.Name)
+	}
+	return client, nil
+}
+
 func (o *CommonOptions) registerEnvironmentCRD() error {
 	apisClient, err := o.Factory.CreateApiExtensionsClient()
 	if err != nil {
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/cloud/amazon"
+	"github.com/jenkins-x/jx/pkg/cloud/externaldns"
+	"github.com/jenkins-x/jx/pkg/cloud/gke"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+)
+
+// externalDNSProviders the DNS providers of external-dns for the kubernetes providers it supports
+var externalDNSProviders = map[string]string{
+	AWS: externaldns.ProviderAWS,
+	EKS: externaldns.ProviderAWS,
+	GKE: externaldns.ProviderGoogle,
+	AKS: externaldns.ProviderAzure,
+}
+
+// azureAccount the fields of 'az account show' used to configure external-dns
+type azureAccount struct {
+	ID       string `json:"id"`
+	TenantID string `json:"tenantId"`
+}
+
+// validateExternalDNS checks external-dns can manage the records of the domain on the kubernetes provider
+func validateExternalDNS(provider string, domain string) error {
+	if _, ok := externalDNSProviders[provider]; !ok {
+		supported := util.SortedMapKeys(externalDNSProviders)
+		return util.InvalidOptionf("external-dns", "true", "external-dns is only supported on the providers %s", strings.Join(supported, ", "))
+	}
+	if domain == "" {
+		return util.MissingOption("domain")
+	}
+	return nil
+}
+
+// installExternalDNS creates or validates the DNS zone of the domain, installs external-dns for the kubernetes
+// provider and annotates the ingress controller service so that a wildcard record of the domain points at it
+func (o *CommonOptions) installExternalDNS(client kubernetes.Interface, provider string, domain string, ingressNamespace string, ingressService string) error {
+	config, err := o.externalDNSConfig(provider, domain)
+	if err != nil {
+		return errors.Wrapf(err, "failed to configure external-dns for the domain %s", domain)
+	}
+	err = config.Validate()
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Installing %s for the domain %s using the %s provider\n", util.ColorInfo(externaldns.ReleaseName), util.ColorInfo(domain), util.ColorInfo(config.Provider))
+	err = o.installChart(externaldns.ReleaseName, externaldns.Chart, "", externaldns.Namespace, true, externaldns.HelmValues(config))
+	if err != nil {
+		return errors.Wrap(err, "failed to install external-dns")
+	}
+
+	annotations := map[string]string{externaldns.HostnameAnnotation: externaldns.WildcardHostname(domain)}
+	err = kube.AnnotateService(client, ingressNamespace, ingressService, annotations)
+	if err != nil {
+		return errors.Wrapf(err, "failed to annotate the ingress controller service %s/%s", ingressNamespace, ingressService)
+	}
+	log.Infof("Annotated the ingress controller service %s so external-dns creates the record %s\n", util.ColorInfo(ingressService), util.ColorInfo(externaldns.WildcardHostname(domain)))
+	return nil
+}
+
+func (o *CommonOptions) externalDNSConfig(provider string, domain string) (*externaldns.Config, error) {
+	config := &externaldns.Config{
+		Provider: externalDNSProviders[provider],
+		Domain:   domain,
+	}
+	currentContext, err := o.getCommandOutput("", "kubectl", "config", "current-context")
+	if err == nil {
+		config.OwnerID = kube.ToValidName(currentContext)
+	}
+
+	switch config.Provider {
+	case externaldns.ProviderAWS:
+		config.Region, err = amazon.ResolveRegionWithoutOptions()
+		if err != nil {
+			return nil, err
+		}
+		_, err = amazon.EnsureHostedZone(domain)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create or find the Route53 hosted zone of %s", domain)
+		}
+		log.Infof("Note: the node or IRSA role of external-dns needs the %s and %s permissions on the hosted zone\n", util.ColorInfo("route53:ChangeResourceRecordSets"), util.ColorInfo("route53:ListResourceRecordSets"))
+
+	case externaldns.ProviderGoogle:
+		config.GoogleProject, err = o.getCommandOutput("", "gcloud", "config", "get-value", "project")
+		if err != nil {
+			return nil, err
+		}
+		err = gke.EnsureManagedZone(config.GoogleProject, domain)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create or find the Cloud DNS managed zone of %s", domain)
+		}
+		log.Infof("Note: the nodes need the %s scope for external-dns to manage the records\n", util.ColorInfo("https://www.googleapis.com/auth/ndev.clouddns.readwrite"))
+
+	case externaldns.ProviderAzure:
+		output, err := o.getCommandOutput("", "az", "account", "show", "-o", "json")
+		if err != nil {
+			return nil, err
+		}
+		account := azureAccount{}
+		err = json.Unmarshal([]byte(output), &account)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse the Azure account")
+		}
+		config.AzureTenantID = account.TenantID
+		config.AzureSubscriptionID = account.ID
+		config.AzureResourceGroup, err = o.ensureAzureDNSZone(domain, currentContext)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create or find the Azure DNS zone of %s", domain)
+		}
+	}
+	return config, nil
+}
+
+// ensureAzureDNSZone returns the resource group of the Azure DNS zone of the domain, creating the zone in the
+// resource group of the AKS cluster if there is none
+func (o *CommonOptions) ensureAzureDNSZone(domain string, clusterName string) (string, error) {
+	query := fmt.Sprintf("[?name=='%s'].resourceGroup", domain)
+	resourceGroup, err := o.getCommandOutput("", "az", "network", "dns", "zone", "list", "--query", query, "-o", "tsv")
+	if err != nil {
+		return "", err
+	}
+	if resourceGroup != "" {
+		return strings.Split(resourceGroup, "\n")[0], nil
+	}
+
+	query = fmt.Sprintf("[?name=='%s'].resourceGroup", clusterName)
+	resourceGroup, err = o.getCommandOutput("", "az", "aks", "list", "--query", query, "-o", "tsv")
+	if err != nil {
+		return "", err
+	}
+	if resourceGroup == "" {
+		return "", fmt.Errorf("could not find the resource group of the AKS cluster %s to create the DNS zone of %s in", clusterName, domain)
+	}
+	resourceGroup = strings.Split(resourceGroup, "\n")[0]
+	err = o.RunCommand("az", "network", "dns", "zone", "create", "--resource-group", resourceGroup, "--name", domain)
+	if err != nil {
+		return "", err
+	}
+	log.Infof("Created the Azure DNS zone %s in resource group %s, please delegate the domain to its name servers\n", util.ColorInfo(domain), util.ColorInfo(resourceGroup))
+	return resourceGroup, nil
+}
+
+// waitForDomainToResolve waits until a host of the domain resolves via the wildcard record external-dns creates
+func (o *CommonOptions) waitForDomainToResolve(domain string, timeout time.Duration) error {
+	host := externaldns.VerifyHostname(domain)
+	log.Infof("Waiting for %s to resolve so the certificates of the domain can be issued...\n", util.ColorInfo(host))
+	err := o.retryQuietlyUntilTimeout(timeout, 10*time.Second, func() error {
+		_, err := net.LookupHost(host)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("the domain %s does not resolve, check it is delegated to the name servers of its DNS zone or use --skip-dns-verify: %s", domain, err)
+	}
+	log.Infof("The domain %s resolves\n", util.ColorInfo(domain))
+	return nil
+}
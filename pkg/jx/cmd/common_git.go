@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"io/ioutil"
+	"strings"
 	"time"
 
 	"github.com/jenkins-x/jx/pkg/auth"
@@ -95,6 +96,9 @@ func (o *CommonOptions) updatePipelineGitCredentialsSecret(server *auth.AuthServ
 		kube.AnnotationURL:                    server.URL,
 		kube.AnnotationName:                   serverName,
 	}
+	if server.APIURL != "" {
+		annotations[kube.AnnotationAPIURL] = server.APIURL
+	}
 	if err != nil {
 		// lets create a new secret
 		create = true
@@ -110,6 +114,15 @@ func (o *CommonOptions) updatePipelineGitCredentialsSecret(server *auth.AuthServ
 	} else {
 		secret.Annotations = kube.MergeMaps(secret.Annotations, annotations)
 		secret.Labels = kube.MergeMaps(secret.Labels, labels)
+		loaded := []v1.Secret{*secret}
+		err = o.loadSecretDataFromVault(loaded)
+		if err != nil {
+			return name, err
+		}
+		secret = &loaded[0]
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
 	}
 	if userAuth.Username != "" {
 		secret.Data["username"] = []byte(userAuth.Username)
@@ -117,8 +130,14 @@ func (o *CommonOptions) updatePipelineGitCredentialsSecret(server *auth.AuthServ
 	if userAuth.ApiToken != "" {
 		secret.Data["password"] = []byte(userAuth.ApiToken)
 	}
+	err = o.storeSecretDataInVault(secret)
+	if err != nil {
+		return name, err
+	}
 	if create {
 		_, err = secrets.Create(secret)
+	} else {
+		_, err = secrets.Update(secret)
 	}
 	if err != nil {
 		return name, fmt.Errorf("Failed to %s secret %s due to %s", operation, secret.Name, err)
@@ -231,10 +250,40 @@ func addGitRepoOptionsArguments(cmd *cobra.Command, repositoryOptions *gits.GitR
 	cmd.Flags().StringVarP(&repositoryOptions.Username, "git-username", "", "", "The Git username to use for creating new Git repositories")
 	cmd.Flags().StringVarP(&repositoryOptions.ApiToken, "git-api-token", "", "", "The Git API token to use for creating new Git repositories")
 	cmd.Flags().BoolVarP(&repositoryOptions.Private, "git-private", "", false, "Create new Git repositories as private")
+	cmd.Flags().StringVarP(&repositoryOptions.Protocol, "git-protocol", "", "", fmt.Sprintf("The protocol of the git remotes of new Git repositories: %s. Defaults to the team setting or https", strings.Join(gits.GitProtocols, ", ")))
+}
+
+// defaultGitProtocol defaults the git protocol of the repository options to the team setting and validates it
+func (o *CommonOptions) defaultGitProtocol(repositoryOptions *gits.GitRepositoryOptions) error {
+	if repositoryOptions.Protocol == "" {
+		teamSettings, err := o.TeamSettings()
+		if err != nil {
+			return err
+		}
+		repositoryOptions.Protocol = teamSettings.GitProtocol
+	}
+	if repositoryOptions.Protocol == "" {
+		repositoryOptions.Protocol = gits.GitProtocolHTTPS
+	}
+	if util.StringArrayIndex(gits.GitProtocols, repositoryOptions.Protocol) < 0 {
+		return util.InvalidOption("git-protocol", repositoryOptions.Protocol, gits.GitProtocols)
+	}
+	return nil
 }
 
 func (o *CommonOptions) GitServerKind(gitInfo *gits.GitRepositoryInfo) (string, error) {
-	return o.GitServerHostURLKind(gitInfo.HostURL())
+	hostURL := gitInfo.HostURL()
+	authConfigSvc, err := o.CreateGitAuthConfigService()
+	if err == nil {
+		server := authConfigSvc.Config().FindServerForGitURL(gitInfo.URL)
+		if server != nil {
+			if server.Kind != "" {
+				return server.Kind, nil
+			}
+			hostURL = server.URL
+		}
+	}
+	return o.GitServerHostURLKind(hostURL)
 }
 
 func (o *CommonOptions) GitServerHostURLKind(hostURL string) (string, error) {
@@ -63,7 +63,7 @@ func (o *CommonOptions) registerLocalHelmRepo(repoName, ns string) error {
 			return errors.Wrapf(err, "failed to remove the repository '%s'", repoName)
 		}
 	}
-	return o.Helm().AddRepo(repoName, helmUrl)
+	return o.Helm().AddRepo(repoName, helmUrl, "", "")
 }
 
 // addHelmRepoIfMissing adds the given helm repo if its not already added
@@ -72,6 +72,13 @@ func (o *CommonOptions) addHelmRepoIfMissing(helmUrl string, repoName string) er
 }
 
 func (o *CommonOptions) addHelmBinaryRepoIfMissing(helmUrl string, repoName string) error {
+	return o.addHelmRepoWithCredentialsIfMissing(helmUrl, repoName, "", "")
+}
+
+// addHelmRepoWithCredentialsIfMissing adds the given helm repo with the optional credentials of a private repo if its
+// not already added
+func (o *CommonOptions) addHelmRepoWithCredentialsIfMissing(helmUrl string, repoName string, username string, password string) error {
+	helmUrl = o.offlineConfig.ChartRepositoryURL(helmUrl)
 	missing, err := o.Helm().IsRepoMissing(helmUrl)
 	if err != nil {
 		return errors.Wrapf(err, "failed to check if the repository with URL '%s' is missing", helmUrl)
@@ -79,7 +86,7 @@ func (o *CommonOptions) addHelmBinaryRepoIfMissing(helmUrl string, repoName stri
 	if missing {
 		log.Infof("Adding missing Helm repo: %s %s\n", util.ColorInfo(repoName), util.ColorInfo(helmUrl))
 		err = o.retry(6, 10*time.Second, func() (err error) {
-			err = o.Helm().AddRepo(repoName, helmUrl)
+			err = o.Helm().AddRepo(repoName, helmUrl, username, password)
 			if err == nil {
 				log.Infof("Successfully added Helm repository %s.\n", repoName)
 			}
@@ -216,12 +223,22 @@ func (o *CommonOptions) addChartRepos(dir string, helmBinary string, chartRepos
 			return errors.Wrap(err, "failed to load the Helm requirements file")
 		}
 		if requirements != nil {
+			var credentials map[string]*kube.ChartRepositoryCredentials
 			for _, dep := range requirements.Dependencies {
 				repo := dep.Repository
 				if repo != "" && !util.StringMapHasValue(installedChartRepos, repo) && repo != defaultChartRepo && !strings.HasPrefix(repo, "file:") {
 					repoCounter++
+					if credentials == nil {
+						credentials = o.chartRepositoryCredentials()
+					}
+					username := ""
+					password := ""
+					if c := credentials[strings.TrimSuffix(repo, "/")]; c != nil {
+						username = c.Username
+						password = c.Password
+					}
 					// TODO we could provide some mechanism to customise the names of repos somehow?
-					err = o.addHelmBinaryRepoIfMissing(repo, "repo"+strconv.Itoa(repoCounter))
+					err = o.addHelmRepoWithCredentialsIfMissing(repo, "repo"+strconv.Itoa(repoCounter), username, password)
 					if err != nil {
 						return errors.Wrapf(err, "failed to add Helm repository '%s'", repo)
 					}
@@ -236,6 +253,21 @@ func (o *CommonOptions) getInstalledChartRepos(helmBinary string) (map[string]st
 	return o.Helm().ListRepos()
 }
 
+// chartRepositoryCredentials returns the credentials of the private chart repositories of the team or none if they
+// cannot be loaded
+func (o *CommonOptions) chartRepositoryCredentials() map[string]*kube.ChartRepositoryCredentials {
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err == nil {
+		var answer map[string]*kube.ChartRepositoryCredentials
+		answer, err = kube.GetChartRepositoryCredentials(kubeClient, ns)
+		if err == nil {
+			return answer
+		}
+	}
+	log.Warnf("Failed to load the credentials of the private chart repositories: %s\n", err)
+	return map[string]*kube.ChartRepositoryCredentials{}
+}
+
 func (o *CommonOptions) helmInit(dir string) error {
 	o.Helm().SetCWD(dir)
 	_, err := o.Helm().Version(false)
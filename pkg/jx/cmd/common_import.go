@@ -134,7 +134,7 @@ func (o *CommonOptions) ImportProject(gitURL string, dir string, jenkinsfile str
 			log.Infof("Created credential %s for host %s user %s\n", util.ColorInfo(credentials), util.ColorInfo(u), util.ColorInfo(user.Username))
 		}
 	}
-	org := gitInfo.Organisation
+	org := gitInfo.FlatOrganisation()
 	err = o.retry(10, time.Second*10, func() error {
 		folder, err := jenk.GetJob(org)
 		if err != nil {
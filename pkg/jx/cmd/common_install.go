@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
 
@@ -22,6 +23,7 @@ import (
 	"github.com/alexflint/go-filemutex"
 	"github.com/blang/semver"
 	jenkinsv1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/cloud/iks"
 	"github.com/jenkins-x/jx/pkg/gits"
 	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/log"
@@ -59,6 +61,18 @@ jenkinsLocationConfiguration.save()
 `
 )
 
+const (
+	// maxParallelDependencyInstalls the maximum number of dependencies downloaded at the same time
+	maxParallelDependencyInstalls = 4
+)
+
+var (
+	// sequentialDependencies the dependencies installed via package managers or interactive installers
+	sequentialDependencies = []string{"az", "gcloud", "oci", "aws", "openstack", "ibmcloud", "virtualbox", "kvm", "kvm2", "hyperkit", "xhyve", "hyperv"}
+
+	binaryInstallLock sync.Mutex
+)
+
 type Prow struct {
 	Version     string
 	Chart       string
@@ -77,62 +91,154 @@ func (o *CommonOptions) doInstallMissingDependencies(install []string) error {
 		}
 	}
 
+	downloads := []string{}
+	sequential := []string{}
 	for _, i := range install {
-		var err error
-		switch i {
-		case "az":
-			err = o.installAzureCli()
-		case "kubectl":
-			err = o.installKubectl()
-		case "gcloud":
-			err = o.installGcloud()
-		case "helm":
-			err = o.installHelm()
-		case "tiller":
-			err = o.installTiller()
-		case "helm3":
-			err = o.installHelm3()
-		case "hyperkit":
-			err = o.installHyperkit()
-		case "kops":
-			err = o.installKops()
-		case "kvm":
-			err = o.installKvm()
-		case "kvm2":
-			err = o.installKvm2()
-		case "ksync":
-			_, err = o.installKSync()
-		case "minikube":
-			err = o.installMinikube()
-		case "minishift":
-			err = o.installMinishift()
-		case "oc":
-			err = o.installOc()
-		case "virtualbox":
-			err = o.installVirtualBox()
-		case "xhyve":
-			err = o.installXhyve()
-		case "hyperv":
-			err = o.installhyperv()
-		case "terraform":
-			err = o.installTerraform()
-		case "oci":
-			err = o.installOciCli()
-		case "aws":
-			err = o.installAws()
-		case "eksctl":
-			err = o.installEksCtl(false)
-		case "heptio-authenticator-aws":
-			err = o.installHeptioAuthenticatorAws(false)
-		case "kustomize":
-			err = o.installKustomize()
-		default:
-			return fmt.Errorf("unknown dependency to install %s\n", i)
+		if i == "brew" {
+			continue
 		}
+		if o.installsInParallel(i) {
+			downloads = append(downloads, i)
+		} else {
+			sequential = append(sequential, i)
+		}
+	}
+
+	errs := []error{}
+	err := installInParallel(downloads, maxParallelDependencyInstalls, o.installDependency)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for _, i := range sequential {
+		err := o.installDependency(i)
 		if err != nil {
-			return fmt.Errorf("error installing %s: %v\n", i, err)
+			errs = append(errs, err)
 		}
 	}
+	return util.CombineErrors(errs...)
+}
+
+// installsInParallel returns true if the dependency is installed by downloading its binary so that it can be
+// installed at the same time as other dependencies. Package managers and installers which prompt the user are
+// always run one at a time
+func (o *CommonOptions) installsInParallel(dependency string) bool {
+	if runtime.GOOS == "darwin" && !o.NoBrew {
+		return false
+	}
+	for _, d := range sequentialDependencies {
+		if d == dependency {
+			return false
+		}
+	}
+	return true
+}
+
+// installInParallel runs the install function for each of the dependencies using at most maxWorkers at a time.
+// A failed install does not stop the others, the errors of all the failed installs are returned together
+func installInParallel(dependencies []string, maxWorkers int, install func(string) error) error {
+	if maxWorkers < 1 {
+		maxWorkers = 1
+	}
+	work := make(chan string)
+	results := make(chan error, len(dependencies))
+	var wg sync.WaitGroup
+	for w := 0; w < maxWorkers && w < len(dependencies); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for dependency := range work {
+				log.Infof("Installing %s\n", util.ColorInfo(dependency))
+				err := install(dependency)
+				if err == nil {
+					log.Infof("Installed %s\n", util.ColorInfo(dependency))
+				}
+				results <- err
+			}
+		}()
+	}
+	for _, dependency := range dependencies {
+		work <- dependency
+	}
+	close(work)
+	wg.Wait()
+	close(results)
+
+	errs := []error{}
+	for err := range results {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return util.CombineErrors(errs...)
+}
+
+func (o *CommonOptions) installDependency(i string) error {
+	var err error
+	switch i {
+	case "az":
+		err = o.installAzureCli()
+	case "kubectl":
+		err = o.installKubectl()
+	case "gcloud":
+		err = o.installGcloud()
+	case "helm":
+		err = o.installHelm()
+	case "tiller":
+		err = o.installTiller()
+	case "helm3":
+		err = o.installHelm3()
+	case "hyperkit":
+		err = o.installHyperkit()
+	case "kops":
+		err = o.installKops()
+	case "kvm":
+		err = o.installKvm()
+	case "kvm2":
+		err = o.installKvm2()
+	case "ksync":
+		_, err = o.installKSync()
+	case "minikube":
+		err = o.installMinikube()
+	case "minishift":
+		err = o.installMinishift()
+	case "oc":
+		err = o.installOc()
+	case "virtualbox":
+		err = o.installVirtualBox()
+	case "xhyve":
+		err = o.installXhyve()
+	case "hyperv":
+		err = o.installhyperv()
+	case "terraform":
+		err = o.installTerraform()
+	case "oci":
+		err = o.installOciCli()
+	case "aws":
+		err = o.installAws()
+	case "eksctl":
+		err = o.installEksCtl(false)
+	case "aws-iam-authenticator":
+		err = o.installAwsIamAuthenticator(false)
+	case "kustomize":
+		err = o.installKustomize()
+	case "kind":
+		err = o.installKind()
+	case "k3d":
+		err = o.installK3d()
+	case "doctl":
+		err = o.installDoctl()
+	case "istioctl":
+		_, err = o.installIstioctl(binaries.IstioctlVersion)
+	case "openstack":
+		err = o.installOpenStackCli()
+	case "ibmcloud":
+		err = o.installIBMCloudCli()
+	default:
+		return fmt.Errorf("unknown dependency to install %s", i)
+	}
+	if err != nil {
+		return fmt.Errorf("error installing %s: %v", i, err)
+	}
 	return nil
 }
 
@@ -157,7 +263,88 @@ func binaryShouldBeInstalled(d string) string {
 		}
 		return d
 	}
-	return ""
+	return outdatedBinaryShouldBeUpgraded(d)
+}
+
+// outdatedBinaryShouldBeUpgraded returns the binary if the version found on the PATH is older than the minimum
+// version jx requires and there is no newer copy in ~/.jx/bin. If there is then ~/.jx/bin is put first on the PATH
+// so that the newer copy is used instead of the system one
+func outdatedBinaryShouldBeUpgraded(d string) string {
+	minimum, ok := binaries.MinimumVersions[d]
+	if !ok {
+		return ""
+	}
+	path, err := binaries.LookupForBinary(d)
+	if err != nil {
+		return ""
+	}
+	version, err := binaries.BinaryVersion(path)
+	if err != nil {
+		log.Warnf("Could not find the version of %s so assuming it is supported: %s\n", path, err)
+		return ""
+	}
+	if version.GE(minimum) {
+		return ""
+	}
+	binDir, err := util.JXBinLocation()
+	if err != nil {
+		return d
+	}
+	jxPath := filepath.Join(binDir, binaries.BinaryWithExtension(d))
+	if jxPath != path {
+		jxVersion, err := binaries.BinaryVersion(jxPath)
+		if err == nil && jxVersion.GE(minimum) {
+			useJXBinLocationFirst(binDir)
+			return ""
+		}
+	}
+	log.Warnf("%s %s at %s is older than the minimum version %s required by jx so a newer version will be installed into %s\n",
+		d, version, path, minimum, binDir)
+	return d
+}
+
+// useJXBinLocationFirst puts the jx bin directory first on the PATH of this process so that binaries upgraded into
+// it are used instead of older versions installed elsewhere
+func useJXBinLocationFirst(binDir string) {
+	path := os.Getenv("PATH")
+	if !strings.HasPrefix(path, binDir+string(os.PathListSeparator)) {
+		os.Setenv("PATH", binDir+string(os.PathListSeparator)+path)
+	}
+}
+
+// checkMinimumVersions returns an error if any of the binaries on the PATH are older than the minimum versions
+// jx requires
+func checkMinimumVersions(deps []string) error {
+	errs := []error{}
+	for _, d := range deps {
+		if _, ok := binaries.MinimumVersions[d]; !ok {
+			continue
+		}
+		path, err := binaries.LookupForBinary(d)
+		if err != nil {
+			continue
+		}
+		version, err := binaries.BinaryVersion(path)
+		if err != nil {
+			continue
+		}
+		err = binaries.CheckMinimumVersion(d, version)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return util.CombineErrors(errs...)
+}
+
+// awsIamAuthenticatorShouldBeInstalled returns aws-iam-authenticator if it needs to be installed. An existing
+// installation of its deprecated predecessor heptio-authenticator-aws is accepted so that it is not downloaded again
+func awsIamAuthenticatorShouldBeInstalled() string {
+	d := binaryShouldBeInstalled("aws-iam-authenticator")
+	if d != "" && binaryShouldBeInstalled("heptio-authenticator-aws") == "" {
+		log.Warnf("%s is deprecated, please replace it with %s\n", util.ColorInfo("heptio-authenticator-aws"), util.ColorInfo("aws-iam-authenticator"))
+		return ""
+	}
+	return d
 }
 
 func (o *CommonOptions) installBrew() error {
@@ -222,12 +409,48 @@ type InstallOrUpdateBinaryOptions struct {
 	Binary              string
 	GitHubOrganization  string
 	DownloadUrlTemplate string
+	ChecksumUrlTemplate string
 	Version             string
 	SkipPathScan        bool
 	VersionExtractor    binaries.VersionExtractor
 	Archived            bool
 }
 
+// downloadURLs returns the download URL of the binary for the operating system and architecture along with the URL
+// of its checksums, which is empty if the binary has none
+func (options InstallOrUpdateBinaryOptions) downloadURLs(goos string, goarch string) (string, string, error) {
+	templateValues := map[string]string{"version": options.Version, "os": goos, "OS": strings.Title(goos), "arch": goarch, "extension": archiveExtension(goos)}
+	urlTemplate, err := template.New(options.Binary).Parse(options.DownloadUrlTemplate)
+	if err != nil {
+		return "", "", err
+	}
+	clientUrlBuffer := bytes.NewBufferString("")
+	err = urlTemplate.Execute(clientUrlBuffer, templateValues)
+	if err != nil {
+		return "", "", err
+	}
+	if options.ChecksumUrlTemplate == "" {
+		return clientUrlBuffer.String(), "", nil
+	}
+	checksumTemplate, err := template.New(options.Binary + "-checksum").Parse(options.ChecksumUrlTemplate)
+	if err != nil {
+		return "", "", err
+	}
+	checksumUrlBuffer := bytes.NewBufferString("")
+	err = checksumTemplate.Execute(checksumUrlBuffer, templateValues)
+	if err != nil {
+		return "", "", err
+	}
+	return clientUrlBuffer.String(), checksumUrlBuffer.String(), nil
+}
+
+func archiveExtension(goos string) string {
+	if goos == "windows" {
+		return "zip"
+	}
+	return "tar.gz"
+}
+
 func (o *CommonOptions) installOrUpdateBinary(options InstallOrUpdateBinaryOptions) error {
 	shouldInstallBinary, err := binaries.ShouldInstallBinary(options.Binary, options.Version, options.VersionExtractor)
 	if err != nil {
@@ -254,16 +477,15 @@ func (o *CommonOptions) installOrUpdateBinary(options InstallOrUpdateBinaryOptio
 		}
 	}
 
-	urlTemplate, err := template.New(options.Binary).Parse(options.DownloadUrlTemplate)
-	if err != nil {
-		return err
-	}
 	binDir, err := util.JXBinLocation()
 	if err != nil {
 		return err
 	}
 	fileName := options.Binary
-	if !options.SkipPathScan {
+	upgrade := outdatedBinaryShouldBeUpgraded(options.Binary) != ""
+	if upgrade {
+		fileName = binaries.BinaryWithExtension(options.Binary)
+	} else if !options.SkipPathScan {
 		installFilename, flag, err := o.shouldInstallBinary(binDir, options.Binary)
 		fileName = installFilename
 		if err != nil || !flag {
@@ -276,21 +498,31 @@ func (o *CommonOptions) installOrUpdateBinary(options InstallOrUpdateBinaryOptio
 			return err
 		}
 	}
-	extension := "tar.gz"
-	if runtime.GOOS == "windows" {
-		extension = "zip"
+	clientURL, checksumURL, err := options.downloadURLs(runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		return err
 	}
-	clientUrlBuffer := bytes.NewBufferString("")
-	urlTemplate.Execute(clientUrlBuffer, map[string]string{"version": options.Version, "os": runtime.GOOS, "arch": runtime.GOARCH, "extension": extension})
+	extension := archiveExtension(runtime.GOOS)
 	fullPath := filepath.Join(binDir, fileName)
 	tarFile := fullPath
 	if options.Archived {
 		tarFile = tarFile + "." + extension
 	}
-	err = binaries.DownloadFile(clientUrlBuffer.String(), tarFile)
+	err = binaries.DownloadFile(clientURL, tarFile)
 	if err != nil {
 		return err
 	}
+	if checksumURL != "" {
+		err = o.verifyChecksum(clientURL, checksumURL, tarFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	// binaries are downloaded in parallel so place them and record their versions one at a time
+	binaryInstallLock.Lock()
+	defer binaryInstallLock.Unlock()
+
 	if options.Archived {
 		if extension == "zip" {
 			zipDir := filepath.Join(binDir, options.Binary+"-tmp-"+uuid.NewUUID().String())
@@ -327,6 +559,15 @@ func (o *CommonOptions) installOrUpdateBinary(options InstallOrUpdateBinaryOptio
 		}
 	}
 
+	// reload the versions as other binaries may have been installed since they were read
+	binariesVersions = map[string]string{}
+	if _, err := os.Stat(binariesConfiguration); err == nil {
+		binariesBytes, err := ioutil.ReadFile(binariesConfiguration)
+		if err != nil {
+			return err
+		}
+		yaml.Unmarshal(binariesBytes, &binariesVersions)
+	}
 	binariesVersions[options.Binary] = options.Version
 	binariesBytes, err := yaml.Marshal(binariesVersions)
 	if err != nil {
@@ -337,7 +578,26 @@ func (o *CommonOptions) installOrUpdateBinary(options InstallOrUpdateBinaryOptio
 		return err
 	}
 
-	return os.Chmod(fullPath, 0755)
+	err = os.Chmod(fullPath, 0755)
+	if err != nil {
+		return err
+	}
+	if upgrade {
+		useJXBinLocationFirst(binDir)
+		log.Infof("Upgraded %s to %s in %s, please put %s before any other versions of %s on your PATH\n",
+			util.ColorInfo(options.Binary), util.ColorInfo(options.Version), binDir, binDir, options.Binary)
+	}
+	return nil
+}
+
+// verifyChecksum verifies the file downloaded from clientURL against the SHA256 checksums published at checksumURL
+// unless checksum verification has been disabled
+func (o *CommonOptions) verifyChecksum(clientURL string, checksumURL string, fileName string) error {
+	if o.SkipChecksumVerify {
+		log.Warnf("Skipping the checksum verification of %s\n", fileName)
+		return nil
+	}
+	return binaries.VerifyDownload(clientURL, checksumURL, fileName)
 }
 
 func (o *CommonOptions) installBrewIfRequired() error {
@@ -381,6 +641,12 @@ func (o *CommonOptions) installKubectl() error {
 	if err != nil {
 		return err
 	}
+	err = o.verifyChecksum(clientURL, clientURL+".sha256", tmpFile)
+	if err != nil {
+		return err
+	}
+	binaryInstallLock.Lock()
+	defer binaryInstallLock.Unlock()
 	err = util.RenameFile(tmpFile, fullPath)
 	if err != nil {
 		return err
@@ -413,6 +679,8 @@ func (o *CommonOptions) installKustomize() error {
 	if err != nil {
 		return err
 	}
+	binaryInstallLock.Lock()
+	defer binaryInstallLock.Unlock()
 	err = util.RenameFile(tmpFile, fullPath)
 	if err != nil {
 		return err
@@ -464,6 +732,8 @@ func (o *CommonOptions) installOc() error {
 	if err != nil {
 		return err
 	}
+	binaryInstallLock.Lock()
+	defer binaryInstallLock.Unlock()
 
 	if extension == ".zip" {
 		zipDir := filepath.Join(binDir, "oc-tmp-"+uuid.NewUUID().String())
@@ -667,6 +937,12 @@ func (o *CommonOptions) installHelm() error {
 	if err != nil {
 		return err
 	}
+	err = o.verifyChecksum(clientURL, clientURL+".sha256", tarFile)
+	if err != nil {
+		return err
+	}
+	binaryInstallLock.Lock()
+	defer binaryInstallLock.Unlock()
 	err = util.UnTargz(tarFile, binDir, []string{binary, fileName})
 	if err != nil {
 		return err
@@ -708,6 +984,12 @@ func (o *CommonOptions) installTiller() error {
 	if err != nil {
 		return err
 	}
+	err = o.verifyChecksum(clientURL, clientURL+".sha256", tarFile)
+	if err != nil {
+		return err
+	}
+	binaryInstallLock.Lock()
+	defer binaryInstallLock.Unlock()
 	err = util.UnTargz(tarFile, binDir, []string{binary, fileName, "helm"})
 	if err != nil {
 		return err
@@ -827,31 +1109,26 @@ func (o *CommonOptions) installHelm3() error {
 	if err != nil || !flag {
 		return err
 	}
-	/*
-	   latestVersion, err := util.GetLatestVersionFromGitHub("kubernetes", "helm")
-	   	if err != nil {
-	   		return err
-	   	}
-	*/
-	/*
-		latestVersion := "3"
-		clientURL := fmt.Sprintf("https://storage.googleapis.com/kubernetes-helm/helm-dev-v%s-%s-%s.tar.gz", latestVersion, runtime.GOOS, runtime.GOARCH)
-	*/
-	// let use our patched version
-	latestVersion := "untagged-93375777c6644a452a64"
-	clientURL := fmt.Sprintf("https://github.com/jstrachan/helm/releases/download/%v/helm-%s-%s.tar.gz", latestVersion, runtime.GOOS, runtime.GOARCH)
+	clientURL := fmt.Sprintf("https://get.helm.sh/helm-v%s-%s-%s.tar.gz", binaries.Helm3Version, runtime.GOOS, runtime.GOARCH)
 
 	tmpDir := filepath.Join(binDir, "helm3.tmp")
 	err = os.MkdirAll(tmpDir, DefaultWritePermissions)
 	if err != nil {
 		return err
 	}
-	fullPath := filepath.Join(binDir, binary)
+	fullPath := filepath.Join(binDir, fileName)
 	tarFile := filepath.Join(tmpDir, fileName+".tgz")
 	err = binaries.DownloadFile(clientURL, tarFile)
 	if err != nil {
 		return err
 	}
+	err = o.verifyChecksum(clientURL, clientURL+".sha256", tarFile)
+	if err != nil {
+		return err
+	}
+	binaryInstallLock.Lock()
+	defer binaryInstallLock.Unlock()
+	// the helm 3 binary is also called helm so rename it to helm3 to keep it apart from helm 2
 	err = util.UnTargz(tarFile, tmpDir, []string{"helm", "helm"})
 	if err != nil {
 		return err
@@ -1010,6 +1287,12 @@ func (o *CommonOptions) installTerraform() error {
 	if err != nil {
 		return err
 	}
+	err = o.verifyChecksum(clientURL, fmt.Sprintf("https://releases.hashicorp.com/terraform/%s/terraform_%s_SHA256SUMS", latestVersion, latestVersion), zipFile)
+	if err != nil {
+		return err
+	}
+	binaryInstallLock.Lock()
+	defer binaryInstallLock.Unlock()
 	err = util.Unzip(zipFile, binDir)
 	if err != nil {
 		return err
@@ -1046,6 +1329,12 @@ func (o *CommonOptions) installKops() error {
 	if err != nil {
 		return err
 	}
+	err = o.verifyChecksum(clientURL, clientURL+".sha256", tmpFile)
+	if err != nil {
+		return err
+	}
+	binaryInstallLock.Lock()
+	defer binaryInstallLock.Unlock()
 	err = util.RenameFile(tmpFile, fullPath)
 	if err != nil {
 		return err
@@ -1077,6 +1366,8 @@ func (o *CommonOptions) installKSync() (bool, error) {
 	if err != nil {
 		return false, err
 	}
+	binaryInstallLock.Lock()
+	defer binaryInstallLock.Unlock()
 	err = util.RenameFile(tmpFile, fullPath)
 	if err != nil {
 		return false, err
@@ -1166,6 +1457,12 @@ func (o *CommonOptions) installMinikube() error {
 	if err != nil {
 		return err
 	}
+	err = o.verifyChecksum(clientURL, clientURL+".sha256", tmpFile)
+	if err != nil {
+		return err
+	}
+	binaryInstallLock.Lock()
+	defer binaryInstallLock.Unlock()
 	err = util.RenameFile(tmpFile, fullPath)
 	if err != nil {
 		return err
@@ -1198,6 +1495,8 @@ func (o *CommonOptions) installMinishift() error {
 	if err != nil {
 		return err
 	}
+	binaryInstallLock.Lock()
+	defer binaryInstallLock.Unlock()
 	err = util.UnTargz(tarFile, binDir, []string{binary, fileName})
 	if err != nil {
 		return err
@@ -1240,6 +1539,42 @@ func (o *CommonOptions) installOciCli() error {
 	return os.Remove(filePath)
 }
 
+func (o *CommonOptions) installOpenStackCli() error {
+	log.Info("Installing OpenStack CLI with the Magnum plugin...\n")
+	return o.runCommandVerbose("pip", "install", "python-openstackclient", "python-magnumclient")
+}
+
+func (o *CommonOptions) installIBMCloudCli() error {
+	err := o.installOrUpdateBinary(ibmcloudBinaryOptions(binaries.IBMCloudVersion))
+	if err != nil {
+		return err
+	}
+	return o.installIBMCloudPlugin(iks.ContainerServicePlugin)
+}
+
+func ibmcloudBinaryOptions(version string) InstallOrUpdateBinaryOptions {
+	return InstallOrUpdateBinaryOptions{
+		Binary:              "ibmcloud",
+		GitHubOrganization:  "IBM-Cloud",
+		DownloadUrlTemplate: "https://download.clis.cloud.ibm.com/ibm-cloud-cli/{{.version}}/binaries/IBM_Cloud_CLI_{{.version}}_{{if eq .os \"darwin\"}}macos{{else}}{{.os}}_{{.arch}}{{end}}.{{if eq .os \"windows\"}}zip{{else}}tgz{{end}}",
+		ChecksumUrlTemplate: "https://download.clis.cloud.ibm.com/ibm-cloud-cli/{{.version}}/binaries/IBM_Cloud_CLI_{{.version}}_checksums.txt",
+		Version:             version,
+		SkipPathScan:        false,
+		VersionExtractor:    nil,
+		Archived:            true,
+	}
+}
+
+// installIBMCloudPlugin installs the ibmcloud CLI plugin if it is not already installed
+func (o *CommonOptions) installIBMCloudPlugin(plugin string) error {
+	_, err := o.getCommandOutput("", "ibmcloud", "plugin", "show", plugin)
+	if err == nil {
+		return nil
+	}
+	log.Infof("Installing the ibmcloud %s plugin...\n", plugin)
+	return o.runCommandVerbose("ibmcloud", "plugin", "install", plugin, "-f")
+}
+
 func (o *CommonOptions) installAws() error {
 	// TODO
 	return nil
@@ -1250,30 +1585,141 @@ func (o *CommonOptions) installEksCtl(skipPathScan bool) error {
 }
 
 func (o *CommonOptions) installEksCtlWithVersion(version string, skipPathScan bool) error {
-	return o.installOrUpdateBinary(InstallOrUpdateBinaryOptions{
+	return o.installOrUpdateBinary(eksctlBinaryOptions(version, skipPathScan))
+}
+
+func eksctlBinaryOptions(version string, skipPathScan bool) InstallOrUpdateBinaryOptions {
+	return InstallOrUpdateBinaryOptions{
 		Binary:              "eksctl",
 		GitHubOrganization:  "weaveworks",
-		DownloadUrlTemplate: "https://github.com/weaveworks/eksctl/releases/download/{{.version}}/eksctl_{{.os}}_{{.arch}}.{{.extension}}",
+		DownloadUrlTemplate: "https://github.com/weaveworks/eksctl/releases/download/{{.version}}/eksctl_{{.OS}}_{{.arch}}.{{.extension}}",
+		ChecksumUrlTemplate: "https://github.com/weaveworks/eksctl/releases/download/{{.version}}/eksctl_checksums.txt",
 		Version:             version,
 		SkipPathScan:        skipPathScan,
 		VersionExtractor:    nil,
 		Archived:            true,
-	})
+	}
 }
 
-func (o *CommonOptions) installHeptioAuthenticatorAws(skipPathScan bool) error {
-	return o.installHeptioAuthenticatorAwsWithVersion(binaries.HeptioAuthenticatorAwsVersion, skipPathScan)
+func (o *CommonOptions) installAwsIamAuthenticator(skipPathScan bool) error {
+	return o.installAwsIamAuthenticatorWithVersion(binaries.AwsIamAuthenticatorVersion, skipPathScan)
 }
 
-func (o *CommonOptions) installHeptioAuthenticatorAwsWithVersion(version string, skipPathScan bool) error {
-	return o.installOrUpdateBinary(InstallOrUpdateBinaryOptions{
-		Binary:              "heptio-authenticator-aws",
+func (o *CommonOptions) installAwsIamAuthenticatorWithVersion(version string, skipPathScan bool) error {
+	return o.installOrUpdateBinary(awsIamAuthenticatorBinaryOptions(version, skipPathScan))
+}
+
+func awsIamAuthenticatorBinaryOptions(version string, skipPathScan bool) InstallOrUpdateBinaryOptions {
+	return InstallOrUpdateBinaryOptions{
+		Binary:              "aws-iam-authenticator",
 		GitHubOrganization:  "",
-		DownloadUrlTemplate: "https://amazon-eks.s3-us-west-2.amazonaws.com/{{.version}}/2018-06-05/bin/{{.os}}/{{.arch}}/heptio-authenticator-aws",
+		DownloadUrlTemplate: "https://amazon-eks.s3-us-west-2.amazonaws.com/{{.version}}/2019-03-27/bin/{{.os}}/{{.arch}}/aws-iam-authenticator{{if eq .os \"windows\"}}.exe{{end}}",
+		ChecksumUrlTemplate: "https://amazon-eks.s3-us-west-2.amazonaws.com/{{.version}}/2019-03-27/bin/{{.os}}/{{.arch}}/aws-iam-authenticator{{if eq .os \"windows\"}}.exe{{end}}.sha256",
 		Version:             version,
 		SkipPathScan:        skipPathScan,
 		VersionExtractor:    nil,
-	})
+	}
+}
+
+func (o *CommonOptions) installKind() error {
+	return o.installOrUpdateBinary(kindBinaryOptions())
+}
+
+func kindBinaryOptions() InstallOrUpdateBinaryOptions {
+	return InstallOrUpdateBinaryOptions{
+		Binary:              "kind",
+		GitHubOrganization:  "kubernetes-sigs",
+		DownloadUrlTemplate: "https://github.com/kubernetes-sigs/kind/releases/download/v{{.version}}/kind-{{.os}}-{{.arch}}",
+		Version:             binaries.KindVersion,
+		SkipPathScan:        false,
+		VersionExtractor:    nil,
+	}
+}
+
+func (o *CommonOptions) installK3d() error {
+	return o.installOrUpdateBinary(k3dBinaryOptions())
+}
+
+func k3dBinaryOptions() InstallOrUpdateBinaryOptions {
+	return InstallOrUpdateBinaryOptions{
+		Binary:              "k3d",
+		GitHubOrganization:  "rancher",
+		DownloadUrlTemplate: "https://github.com/rancher/k3d/releases/download/v{{.version}}/k3d-{{.os}}-{{.arch}}",
+		Version:             binaries.K3dVersion,
+		SkipPathScan:        false,
+		VersionExtractor:    nil,
+	}
+}
+
+func (o *CommonOptions) installDoctl() error {
+	return o.installOrUpdateBinary(doctlBinaryOptions())
+}
+
+func doctlBinaryOptions() InstallOrUpdateBinaryOptions {
+	return InstallOrUpdateBinaryOptions{
+		Binary:              "doctl",
+		GitHubOrganization:  "digitalocean",
+		DownloadUrlTemplate: "https://github.com/digitalocean/doctl/releases/download/v{{.version}}/doctl-{{.version}}-{{.os}}-{{.arch}}.{{.extension}}",
+		ChecksumUrlTemplate: "https://github.com/digitalocean/doctl/releases/download/v{{.version}}/doctl-{{.version}}-checksums.sha256",
+		Version:             binaries.DoctlVersion,
+		SkipPathScan:        false,
+		VersionExtractor:    nil,
+		Archived:            true,
+	}
+}
+
+// installIstioctl installs the given version of istioctl into the jx bin directory returning its path. The Istio
+// control plane is installed and upgraded by the istioctl of the same version so any istioctl on the PATH is not used
+func (o *CommonOptions) installIstioctl(version string) (string, error) {
+	binDir, err := util.JXBinLocation()
+	if err != nil {
+		return "", err
+	}
+	err = o.installOrUpdateBinary(istioctlBinaryOptions(version))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(binDir, binaries.BinaryWithExtension("istioctl")), nil
+}
+
+func istioctlBinaryOptions(version string) InstallOrUpdateBinaryOptions {
+	return InstallOrUpdateBinaryOptions{
+		Binary:              "istioctl",
+		GitHubOrganization:  "istio",
+		DownloadUrlTemplate: "https://github.com/istio/istio/releases/download/{{.version}}/istioctl-{{.version}}-{{if eq .os \"darwin\"}}osx{{else if eq .os \"windows\"}}win{{else}}{{.os}}-{{.arch}}{{end}}.{{.extension}}",
+		ChecksumUrlTemplate: "https://github.com/istio/istio/releases/download/{{.version}}/istioctl-{{.version}}-{{if eq .os \"darwin\"}}osx{{else if eq .os \"windows\"}}win{{else}}{{.os}}-{{.arch}}{{end}}.{{.extension}}.sha256",
+		Version:             version,
+		SkipPathScan:        true,
+		VersionExtractor:    nil,
+		Archived:            true,
+	}
+}
+
+// installTrivy installs the pinned version of trivy into the jx bin directory returning its path so that the format of
+// its reports is the one jx parses
+func (o *CommonOptions) installTrivy() (string, error) {
+	binDir, err := util.JXBinLocation()
+	if err != nil {
+		return "", err
+	}
+	err = o.installOrUpdateBinary(trivyBinaryOptions(binaries.TrivyVersion))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(binDir, binaries.BinaryWithExtension("trivy")), nil
+}
+
+func trivyBinaryOptions(version string) InstallOrUpdateBinaryOptions {
+	return InstallOrUpdateBinaryOptions{
+		Binary:              "trivy",
+		GitHubOrganization:  "aquasecurity",
+		DownloadUrlTemplate: "https://github.com/aquasecurity/trivy/releases/download/v{{.version}}/trivy_{{.version}}_{{if eq .os \"darwin\"}}macOS{{else}}{{.OS}}{{end}}-{{if eq .arch \"arm64\"}}ARM64{{else}}64bit{{end}}.tar.gz",
+		ChecksumUrlTemplate: "https://github.com/aquasecurity/trivy/releases/download/v{{.version}}/trivy_{{.version}}_checksums.txt",
+		Version:             version,
+		SkipPathScan:        true,
+		VersionExtractor:    nil,
+		Archived:            true,
+	}
 }
 
 func (o *CommonOptions) GetCloudProvider(p string) (string, error) {
@@ -1353,7 +1799,11 @@ func (o *CommonOptions) installMissingDependencies(providerSpecificDeps []string
 		survey.AskOne(prompt, &install, nil, surveyOpts)
 	}
 
-	return o.doInstallMissingDependencies(install)
+	err := o.doInstallMissingDependencies(install)
+	if err != nil {
+		return err
+	}
+	return checkMinimumVersions(deps)
 }
 
 // installRequirements installs any requirements for the given provider kind
@@ -1370,6 +1820,14 @@ func (o *CommonOptions) installRequirements(cloudProvider string, extraDependenc
 		deps = o.addRequiredBinary("oci", deps)
 	case MINIKUBE:
 		deps = o.addRequiredBinary("minikube", deps)
+	case KIND:
+		deps = o.addRequiredBinary("kind", deps)
+	case K3S:
+		deps = o.addRequiredBinary("k3d", deps)
+	case DOKS:
+		deps = o.addRequiredBinary("doctl", deps)
+	case OPENSTACK:
+		deps = o.addRequiredBinary("openstack", deps)
 	}
 
 	for _, dep := range extraDependencies {
@@ -1591,11 +2049,20 @@ func (o *CommonOptions) createWebhookProw(gitURL string, gitProvider gits.GitPro
 	if err != nil {
 		return err
 	}
-	baseURL, err := kube.GetServiceURLFromName(o.KubeClientCached, "hook", ns)
-	if err != nil {
-		return err
+	webhookUrl := ""
+	if gitProvider.Kind() == gits.KindAzureDevOps {
+		// Prow only understands GitHub webhooks so the service hooks go to the translating controller
+		webhookUrl, err = o.ensureAzureDevOpsWebHook(o.KubeClientCached, ns)
+		if err != nil {
+			return err
+		}
+	} else {
+		baseURL, err := kube.GetServiceURLFromName(o.KubeClientCached, "hook", ns)
+		if err != nil {
+			return err
+		}
+		webhookUrl = util.UrlJoin(baseURL, "hook")
 	}
-	webhookUrl := util.UrlJoin(baseURL, "hook")
 
 	hmacToken, err := o.KubeClientCached.CoreV1().Secrets(ns).Get("hmac-token", metav1.GetOptions{})
 	if err != nil {
@@ -1,10 +1,20 @@
 package cmd
 
 import (
-	"github.com/pborman/uuid"
-	"github.com/stretchr/testify/assert"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/binaries"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestInstallEksctl(t *testing.T) {
@@ -19,3 +29,130 @@ func TestInstallEksctl(t *testing.T) {
 	err = (&CommonOptions{}).installEksCtl(false)
 	assert.FileExists(t, os.Getenv("JX_HOME")+"/bin/eksctl")
 }
+
+func TestAwsIamAuthenticatorAcceptsHeptioAuthenticator(t *testing.T) {
+	binDir, err := ioutil.TempDir("", "jx-bin-")
+	assert.Nil(t, err)
+	defer os.RemoveAll(binDir)
+
+	oldPath := os.Getenv("PATH")
+	err = os.Setenv("PATH", binDir)
+	assert.Nil(t, err)
+	defer os.Setenv("PATH", oldPath)
+
+	defer os.Unsetenv("JX_HOME")
+	err = os.Setenv("JX_HOME", "/tmp/"+uuid.New())
+	assert.Nil(t, err)
+
+	assert.Equal(t, "aws-iam-authenticator", awsIamAuthenticatorShouldBeInstalled())
+
+	err = ioutil.WriteFile(filepath.Join(binDir, "heptio-authenticator-aws"), []byte("#!/bin/sh\n"), 0755)
+	assert.Nil(t, err)
+	assert.Equal(t, "", awsIamAuthenticatorShouldBeInstalled())
+	assert.NotContains(t, eksClusterDependencies(false), "aws-iam-authenticator")
+	assert.Equal(t, []string{"aws-iam-authenticator"}, eksClusterDependencies(true), "the kube config of an existing cluster uses aws-iam-authenticator")
+}
+
+func TestInstallInParallel(t *testing.T) {
+	var lock sync.Mutex
+	active := 0
+	maxActive := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		active++
+		if active > maxActive {
+			maxActive = active
+		}
+		lock.Unlock()
+		time.Sleep(50 * time.Millisecond)
+		lock.Lock()
+		active--
+		lock.Unlock()
+
+		if strings.HasPrefix(r.URL.Path, "/broken") {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			assert.Nil(t, err)
+			conn.Close()
+			return
+		}
+		fmt.Fprint(w, r.URL.Path)
+	}))
+	defer server.Close()
+
+	binDir, err := ioutil.TempDir("", "jx-bin-")
+	assert.Nil(t, err)
+	defer os.RemoveAll(binDir)
+
+	dependencies := []string{"kubectl", "broken-helm", "eksctl", "kops", "broken-oc", "minikube", "terraform", "ksync"}
+	err = installInParallel(dependencies, 4, func(dependency string) error {
+		return binaries.DownloadFile(server.URL+"/"+dependency, filepath.Join(binDir, dependency))
+	})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "broken-helm")
+	assert.Contains(t, err.Error(), "broken-oc")
+
+	for _, dependency := range dependencies {
+		if strings.HasPrefix(dependency, "broken") {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(binDir, dependency))
+		assert.Nil(t, err)
+		assert.Equal(t, "/"+dependency, string(data))
+	}
+	assert.True(t, maxActive > 1, "downloads should run concurrently")
+	assert.True(t, maxActive <= 4, "at most 4 downloads should run at once but was %d", maxActive)
+}
+
+func TestIstioctlDownloadURLs(t *testing.T) {
+	t.Parallel()
+	options := istioctlBinaryOptions("1.7.3")
+
+	clientURL, checksumURL, err := options.downloadURLs("linux", "amd64")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://github.com/istio/istio/releases/download/1.7.3/istioctl-1.7.3-linux-amd64.tar.gz", clientURL)
+	assert.Equal(t, clientURL+".sha256", checksumURL)
+
+	clientURL, _, err = options.downloadURLs("darwin", "amd64")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://github.com/istio/istio/releases/download/1.7.3/istioctl-1.7.3-osx.tar.gz", clientURL)
+
+	clientURL, _, err = options.downloadURLs("windows", "amd64")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://github.com/istio/istio/releases/download/1.7.3/istioctl-1.7.3-win.zip", clientURL)
+}
+
+func TestTrivyDownloadURLs(t *testing.T) {
+	t.Parallel()
+	options := trivyBinaryOptions("0.18.3")
+
+	clientURL, checksumURL, err := options.downloadURLs("linux", "amd64")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://github.com/aquasecurity/trivy/releases/download/v0.18.3/trivy_0.18.3_Linux-64bit.tar.gz", clientURL)
+	assert.Equal(t, "https://github.com/aquasecurity/trivy/releases/download/v0.18.3/trivy_0.18.3_checksums.txt", checksumURL)
+
+	clientURL, _, err = options.downloadURLs("darwin", "amd64")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://github.com/aquasecurity/trivy/releases/download/v0.18.3/trivy_0.18.3_macOS-64bit.tar.gz", clientURL)
+
+	clientURL, _, err = options.downloadURLs("linux", "arm64")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://github.com/aquasecurity/trivy/releases/download/v0.18.3/trivy_0.18.3_Linux-ARM64.tar.gz", clientURL)
+}
+
+func TestIBMCloudDownloadURLs(t *testing.T) {
+	t.Parallel()
+	options := ibmcloudBinaryOptions("1.2.3")
+
+	clientURL, checksumURL, err := options.downloadURLs("linux", "amd64")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://download.clis.cloud.ibm.com/ibm-cloud-cli/1.2.3/binaries/IBM_Cloud_CLI_1.2.3_linux_amd64.tgz", clientURL)
+	assert.Equal(t, "https://download.clis.cloud.ibm.com/ibm-cloud-cli/1.2.3/binaries/IBM_Cloud_CLI_1.2.3_checksums.txt", checksumURL)
+
+	clientURL, _, err = options.downloadURLs("darwin", "amd64")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://download.clis.cloud.ibm.com/ibm-cloud-cli/1.2.3/binaries/IBM_Cloud_CLI_1.2.3_macos.tgz", clientURL)
+
+	clientURL, _, err = options.downloadURLs("windows", "amd64")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://download.clis.cloud.ibm.com/ibm-cloud-cli/1.2.3/binaries/IBM_Cloud_CLI_1.2.3_windows_amd64.zip", clientURL)
+}
@@ -70,3 +70,62 @@ func (o *CommonOptions) ensureClusterRoleBinding(clusterRoleBindingName string,
 	}
 	return nil
 }
+
+func (o *CommonOptions) ensureRole(ns string, roleName string, rules []rbacv1.PolicyRule) error {
+	client, _, err := o.KubeClient()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.RbacV1().Roles(ns).Get(roleName, meta_v1.GetOptions{})
+	if err != nil {
+		role := &rbacv1.Role{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      roleName,
+				Namespace: ns,
+			},
+			Rules: rules,
+		}
+		_, err = client.RbacV1().Roles(ns).Create(role)
+		if err != nil {
+			return fmt.Errorf("Failed to create Role %s in namespace %s: %s", roleName, ns, err)
+		}
+		log.Infof("Created Role %s in namespace %s\n", util.ColorInfo(roleName), util.ColorInfo(ns))
+	}
+	return nil
+}
+
+func (o *CommonOptions) ensureRoleBinding(ns string, roleBindingName string, role string, serviceAccountNamespace string, serviceAccountName string) error {
+	client, _, err := o.KubeClient()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.RbacV1().RoleBindings(ns).Get(roleBindingName, meta_v1.GetOptions{})
+	if err != nil {
+		roleBinding := &rbacv1.RoleBinding{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:      roleBindingName,
+				Namespace: ns,
+			},
+			Subjects: []rbacv1.Subject{
+				{
+					Kind:      "ServiceAccount",
+					Name:      serviceAccountName,
+					Namespace: serviceAccountNamespace,
+				},
+			},
+			RoleRef: rbacv1.RoleRef{
+				Kind:     "Role",
+				Name:     role,
+				APIGroup: "rbac.authorization.k8s.io",
+			},
+		}
+		_, err = client.RbacV1().RoleBindings(ns).Create(roleBinding)
+		if err != nil {
+			return fmt.Errorf("Failed to create RoleBinding %s in namespace %s: %s", roleBindingName, ns, err)
+		}
+		log.Infof("Created RoleBinding %s in namespace %s\n", util.ColorInfo(roleBindingName), util.ColorInfo(ns))
+	}
+	return nil
+}
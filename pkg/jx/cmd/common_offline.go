@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/binaries"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// OfflineValuesFile the values file which replaces the images of the platform chart with their mirrors
+const OfflineValuesFile = "offlineValues.yaml"
+
+// loadOfflineConfig loads the mirrors of an air-gapped network which the charts, images and binaries are used from
+func (o *CommonOptions) loadOfflineConfig(fileName string) error {
+	if fileName == "" || o.offlineConfig != nil {
+		return nil
+	}
+	offlineConfig, err := config.LoadOfflineConfig(fileName)
+	if err != nil {
+		return errors.Wrap(err, "failed to load the offline configuration")
+	}
+	o.offlineConfig = offlineConfig
+	binaries.SetMirrorURL(offlineConfig.BinaryDownloadURL)
+	log.Infof("Using the mirrors of the offline configuration %s\n", util.ColorInfo(fileName))
+	return nil
+}
+
+// writeOfflineImageValues writes a values file which replaces the images referenced by the merged values files with
+// their mirrors, returning an empty file name if there is no offline configuration or no image is mirrored
+func (o *CommonOptions) writeOfflineImageValues(valueFiles []string, dir string) (string, error) {
+	if o.offlineConfig == nil {
+		return "", nil
+	}
+	values := map[string]interface{}{}
+	for _, valueFile := range valueFiles {
+		exists, err := util.FileExists(valueFile)
+		if err != nil || !exists {
+			continue
+		}
+		fileValues, err := helm.LoadValuesFile(valueFile)
+		if err != nil {
+			return "", err
+		}
+		helm.MergeValues(values, fileValues)
+	}
+	imageValues := o.offlineConfig.ImageValues(values)
+	if len(imageValues) == 0 {
+		return "", nil
+	}
+	data, err := yaml.Marshal(imageValues)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal the mirrored image values")
+	}
+	fileName := filepath.Join(dir, OfflineValuesFile)
+	err = ioutil.WriteFile(fileName, data, DefaultWritePermissions)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to write the mirrored image values")
+	}
+	log.Infof("Using the mirrored images in %s\n", util.ColorInfo(fileName))
+	return fileName, nil
+}
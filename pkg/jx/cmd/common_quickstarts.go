@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/quickstarts"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// defaultQuickstartCacheTTL how long the repositories of a quickstart location are cached for by default
+	defaultQuickstartCacheTTL = time.Hour
+)
+
+// addQuickstartLocationFlags adds the flags for the extra locations to load quickstarts from
+func addQuickstartLocationFlags(cmd *cobra.Command, gitHubOrganisations *[]string, quickstartLocations *[]string, cacheTTL *time.Duration) {
+	cmd.Flags().StringArrayVarP(gitHubOrganisations, "organisations", "g", []string{}, "The GitHub organisations to query for quickstarts")
+	cmd.Flags().StringArrayVarP(quickstartLocations, "quickstart-location", "", []string{}, "An extra location of quickstarts of the form owner, owner/repository or a Git server URL of either, which wins over the team locations for quickstarts of the same name")
+	cmd.Flags().DurationVarP(cacheTTL, "cache-ttl", "", defaultQuickstartCacheTTL, "How long the repositories of each quickstart location are cached in ~/.jx/quickstarts for. Use 0 to always list them from the Git server")
+}
+
+// addQuickstartFilterFlags adds the flags to filter quickstarts by their metadata
+func addQuickstartFilterFlags(cmd *cobra.Command, filter *quickstarts.QuickstartFilter) {
+	cmd.Flags().StringArrayVarP(&filter.Tags, "tag", "t", []string{}, "The tags on the quickstarts to filter")
+	cmd.Flags().StringVarP(&filter.Owner, "owner", "", "", "The owner to filter on")
+	cmd.Flags().StringVarP(&filter.Language, "language", "l", "", "The language to filter on")
+	cmd.Flags().StringVarP(&filter.Framework, "framework", "", "", "The framework to filter on")
+	cmd.Flags().StringVarP(&filter.Text, "filter", "f", "", "The text filter")
+}
+
+// loadQuickstarts loads the quickstarts of the team locations, unless ignoreTeam is true, followed by the GitHub
+// organisations and the quickstart locations given on the command line so that a quickstart of the same name from a
+// later, more specific, location wins
+func (o *CommonOptions) loadQuickstarts(ignoreTeam bool, gitHubOrganisations []string, quickstartLocations []string, cacheTTL time.Duration) (*quickstarts.QuickstartModel, error) {
+	authConfigSvc, err := o.CreateGitAuthConfigService()
+	if err != nil {
+		return nil, err
+	}
+	config := authConfigSvc.Config()
+
+	var locations []v1.QuickStartLocation
+	if !ignoreTeam {
+		jxClient, ns, err := o.JXClientAndDevNamespace()
+		if err != nil {
+			return nil, err
+		}
+		err = o.registerEnvironmentCRD()
+		if err != nil {
+			return nil, err
+		}
+
+		locations, err = kube.GetQuickstartLocations(jxClient, ns)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// lets add any extra github organisations if they are not already configured
+	for _, org := range gitHubOrganisations {
+		found := false
+		for _, loc := range locations {
+			if loc.GitURL == gits.GitHubURL && loc.Owner == org {
+				found = true
+				break
+			}
+		}
+		if !found {
+			locations = append(locations, v1.QuickStartLocation{
+				GitURL:   gits.GitHubURL,
+				GitKind:  gits.KindGitHub,
+				Owner:    org,
+				Includes: []string{"*"},
+				Excludes: []string{"WIP-*"},
+			})
+		}
+	}
+
+	for _, text := range quickstartLocations {
+		location, err := parseQuickstartLocation(text)
+		if err != nil {
+			return nil, err
+		}
+		locations = addQuickstartLocation(locations, location)
+	}
+
+	model, err := o.LoadQuickstartsFromLocations(config, locations, cacheTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load quickstarts: %s", err)
+	}
+	return model, nil
+}
+
+// LoadQuickstartsFromLocations loads the quickstarts of the locations in order using the Git credentials configured for
+// their Git servers, caching the repositories of each location for the ttl
+func (o *CommonOptions) LoadQuickstartsFromLocations(config *auth.AuthConfig, locations []v1.QuickStartLocation, cacheTTL time.Duration) (*quickstarts.QuickstartModel, error) {
+	model := quickstarts.NewQuickstartModel()
+	cacheDir := ""
+	if cacheTTL > 0 {
+		var err error
+		cacheDir, err = util.QuickstartsDir()
+		if err != nil {
+			return model, err
+		}
+	}
+
+	for _, location := range locations {
+		kind := location.GitKind
+		if kind == "" {
+			server := config.GetServer(location.GitURL)
+			if server != nil {
+				kind = server.Kind
+			}
+		}
+		if kind == "" {
+			kind = gits.KindGitHub
+		}
+		gitProvider, err := o.gitProviderForGitServerURL(location.GitURL, kind)
+		if err != nil {
+			return model, err
+		}
+		o.Debugf("Searching for repositories in Git server %s owner %s includes %s excludes %s as user %s \n", gitProvider.ServerURL(), location.Owner, strings.Join(location.Includes, ", "), strings.Join(location.Excludes, ", "), gitProvider.CurrentUsername())
+		err = model.LoadCachedGitQuickstarts(gitProvider, location.Owner, location.Includes, location.Excludes, cacheDir, cacheTTL)
+		if err != nil {
+			o.Debugf("Quickstart load error: %s\n", err.Error())
+		}
+	}
+	return model, nil
+}
+
+// addQuickstartLocation appends the location replacing any location of the same Git server and owner
+func addQuickstartLocation(locations []v1.QuickStartLocation, location v1.QuickStartLocation) []v1.QuickStartLocation {
+	answer := []v1.QuickStartLocation{}
+	for _, loc := range locations {
+		if loc.GitURL != location.GitURL || loc.Owner != location.Owner {
+			answer = append(answer, loc)
+		}
+	}
+	return append(answer, location)
+}
+
+// parseQuickstartLocation parses a quickstart location of the form owner, owner/repository or the URL of an owner or
+// a repository on a Git server. The repository can be a wildcard pattern
+func parseQuickstartLocation(text string) (v1.QuickStartLocation, error) {
+	location := v1.QuickStartLocation{
+		GitURL:   gits.GitHubURL,
+		Includes: []string{"*"},
+		Excludes: []string{"WIP-*"},
+	}
+	path := text
+	if strings.Contains(text, "://") {
+		u, err := url.Parse(text)
+		if err != nil || u.Host == "" {
+			return location, fmt.Errorf("invalid quickstart location %s", text)
+		}
+		location.GitURL = u.Scheme + "://" + u.Host
+		path = u.Path
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if path == "" {
+		return location, fmt.Errorf("no owner in the quickstart location %s", text)
+	}
+	location.GitKind = gits.SaasGitKind(location.GitURL)
+	location.Owner = path
+	idx := strings.LastIndex(path, "/")
+	if idx > 0 {
+		location.Owner = path[0:idx]
+		location.Includes = []string{path[idx+1:]}
+	}
+	return location, nil
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuickstartLocation(t *testing.T) {
+	t.Parallel()
+	tests := map[string]v1.QuickStartLocation{
+		"myorg": {
+			GitURL: gits.GitHubURL, GitKind: gits.KindGitHub, Owner: "myorg", Includes: []string{"*"}, Excludes: []string{"WIP-*"},
+		},
+		"myorg/node-*": {
+			GitURL: gits.GitHubURL, GitKind: gits.KindGitHub, Owner: "myorg", Includes: []string{"node-*"}, Excludes: []string{"WIP-*"},
+		},
+		"https://gitlab.com/mygroup/sub/quickstart.git": {
+			GitURL: "https://gitlab.com", GitKind: gits.KindGitlab, Owner: "mygroup/sub", Includes: []string{"quickstart"}, Excludes: []string{"WIP-*"},
+		},
+		"https://git.example.com/myorg/": {
+			GitURL: "https://git.example.com", Owner: "myorg", Includes: []string{"*"}, Excludes: []string{"WIP-*"},
+		},
+	}
+	for text, expected := range tests {
+		actual, err := parseQuickstartLocation(text)
+		require.NoError(t, err, "parsing %s", text)
+		assert.Equal(t, expected, actual, "parsing %s", text)
+	}
+
+	for _, text := range []string{"", "/", "https://github.com/"} {
+		_, err := parseQuickstartLocation(text)
+		assert.Error(t, err, "parsing %q", text)
+	}
+}
+
+func TestAddQuickstartLocation(t *testing.T) {
+	t.Parallel()
+	defaults := v1.QuickStartLocation{GitURL: gits.GitHubURL, Owner: "jenkins-x-quickstarts", Includes: []string{"*"}}
+	team := v1.QuickStartLocation{GitURL: gits.GitHubURL, Owner: "myorg", Includes: []string{"*"}}
+	mine := v1.QuickStartLocation{GitURL: gits.GitHubURL, Owner: "myorg", Includes: []string{"node-*"}}
+
+	locations := addQuickstartLocation([]v1.QuickStartLocation{team, defaults}, mine)
+	assert.Equal(t, []v1.QuickStartLocation{defaults, mine}, locations)
+}
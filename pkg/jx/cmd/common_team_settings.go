@@ -10,6 +10,7 @@ import (
 	"os/user"
 	"reflect"
 	"strconv"
+	"time"
 
 	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx/pkg/kube"
@@ -95,6 +96,26 @@ func (o *CommonOptions) TeamHelmBin() (string, bool, bool, error) {
 	return helmBin, teamSettings.NoTiller, teamSettings.HelmTemplate, nil
 }
 
+// TeamPreviewTTL returns how long a preview environment can go without activity before it is garbage collected or
+// zero if previews are only collected when their pull request is closed
+func (o *CommonOptions) TeamPreviewTTL() (time.Duration, error) {
+	teamSettings, err := o.TeamSettings()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(teamSettings.PreviewTTLHours) * time.Hour, nil
+}
+
+// TeamDevPodIdleTimeout returns how long a DevPod can go without activity before it is garbage collected or zero if
+// idle DevPods are kept
+func (o *CommonOptions) TeamDevPodIdleTimeout() (time.Duration, error) {
+	teamSettings, err := o.TeamSettings()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(teamSettings.DevPodIdleTimeoutMinutes) * time.Minute, nil
+}
+
 // ModifyDevEnvironment modifies the development environment settings
 func (o *CommonOptions) ModifyDevEnvironment(callback func(env *v1.Environment) error) error {
 	apisClient, err := o.CreateApiExtensionsClient()
@@ -346,6 +367,9 @@ func addTeamSettingsCommandsFromTags(baseCmd *cobra.Command, in terminal.FileRea
 					} else if structField.Type.String() == "bool" {
 						value, err = strconv.ParseBool(args[0])
 						CheckErr(err)
+					} else if structField.Type.String() == "int" {
+						value, err = strconv.Atoi(args[0])
+						CheckErr(err)
 					}
 				} else if !options.BatchMode {
 					var err error
@@ -353,6 +377,12 @@ func addTeamSettingsCommandsFromTags(baseCmd *cobra.Command, in terminal.FileRea
 						value, err = util.PickValue(commandUsage+":", field.String(), true, in, out, errOut)
 					} else if structField.Type.String() == "bool" {
 						value = util.Confirm(commandUsage+":", field.Bool(), "", in, out, errOut)
+					} else if structField.Type.String() == "int" {
+						var text string
+						text, err = util.PickValue(commandUsage+":", strconv.Itoa(int(field.Int())), true, in, out, errOut)
+						if err == nil {
+							value, err = strconv.Atoi(text)
+						}
 					}
 					CheckErr(err)
 				} else {
@@ -367,6 +397,8 @@ func addTeamSettingsCommandsFromTags(baseCmd *cobra.Command, in terminal.FileRea
 						valueField.SetString(value.(string))
 					case bool:
 						valueField.SetBool(value.(bool))
+					case int:
+						valueField.SetInt(int64(value.(int)))
 					}
 					log.Infof("Setting the team %s to: %s\n", util.ColorInfo(command), util.ColorInfo(value))
 					return nil
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/vault"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SystemVaultClient returns a client of the external Vault which stores the secrets of the team under its secret
+// prefix, logging in as the build service account. It returns nil if the team stores its secrets in Kubernetes secrets
+func (o *CommonOptions) SystemVaultClient() (*vault.Client, error) {
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return nil, err
+	}
+	env, err := kube.GetEnvironment(jxClient, ns, kube.LabelValueDevEnvironment)
+	if err != nil {
+		// there is no dev environment yet so there are no team settings
+		return nil, nil
+	}
+	location := env.Spec.TeamSettings.SecretsLocation
+	if location.VaultURL == "" {
+		return nil, nil
+	}
+	client, ns, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return nil, err
+	}
+	sa, err := client.CoreV1().ServiceAccounts(ns).Get(jxBuilderServiceAccount, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the service account %s", jxBuilderServiceAccount)
+	}
+	for _, ref := range sa.Secrets {
+		secret, err := client.CoreV1().Secrets(ns).Get(ref.Name, metav1.GetOptions{})
+		if err != nil || secret.Type != corev1.SecretTypeServiceAccountToken {
+			continue
+		}
+		jwt := string(secret.Data[corev1.ServiceAccountTokenKey])
+		return vault.NewClient(location.VaultURL, location.VaultAuthPath, location.VaultRole, location.VaultSecretPrefix, jwt), nil
+	}
+	return nil, fmt.Errorf("no token secret found for the service account %s in namespace %s", jxBuilderServiceAccount, ns)
+}
+
+// storeSecretDataInVault moves the data of the secret into the external Vault of the team, if there is one, leaving
+// the secret as a reference to its key in the Vault
+func (o *CommonOptions) storeSecretDataInVault(secret *corev1.Secret) error {
+	client, err := o.SystemVaultClient()
+	if err != nil || client == nil {
+		return err
+	}
+	data := map[string]interface{}{}
+	for k, v := range secret.Data {
+		data[k] = string(v)
+	}
+	err = client.Write(secret.Name, data)
+	if err != nil {
+		return errors.Wrapf(err, "failed to store the secret %s in the Vault", secret.Name)
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[kube.AnnotationVaultSecret] = secret.Name
+	secret.Data = map[string][]byte{}
+	return nil
+}
+
+// loadSecretDataFromVault fills in the data of the secrets which are stored in the external Vault of the team
+func (o *CommonOptions) loadSecretDataFromVault(secrets []corev1.Secret) error {
+	var client *vault.Client
+	for i := range secrets {
+		secret := &secrets[i]
+		key := secret.Annotations[kube.AnnotationVaultSecret]
+		if key == "" {
+			continue
+		}
+		if client == nil {
+			var err error
+			client, err = o.SystemVaultClient()
+			if err != nil {
+				return err
+			}
+			if client == nil {
+				return fmt.Errorf("the secret %s is stored in the Vault but the team has no secrets location", secret.Name)
+			}
+		}
+		data, err := client.Read(key)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read the secret %s from the Vault", key)
+		}
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		for k, v := range data {
+			if text, ok := v.(string); ok {
+				secret.Data[k] = []byte(text)
+			}
+		}
+	}
+	return nil
+}
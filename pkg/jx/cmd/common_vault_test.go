@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	jxfake "github.com/jenkins-x/jx/pkg/client/clientset/versioned/fake"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSecretDataIsStoredInTheExternalVault(t *testing.T) {
+	t.Parallel()
+	stored := map[string]map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/auth/kubernetes/login" {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"auth": map[string]interface{}{"client_token": "token", "lease_duration": 3600, "renewable": true},
+			})
+			return
+		}
+		key := strings.TrimPrefix(r.URL.Path, "/v1/")
+		if r.Method == http.MethodPut {
+			data := map[string]interface{}{}
+			json.NewDecoder(r.Body).Decode(&data)
+			stored[key] = data
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": stored[key]})
+	}))
+	defer server.Close()
+
+	devEnv := kube.NewPermanentEnvironment("dev")
+	devEnv.Spec.Namespace = "jx"
+	devEnv.Spec.TeamSettings.SecretsLocation = v1.SecretsLocation{
+		VaultURL:          server.URL,
+		VaultAuthPath:     "kubernetes",
+		VaultRole:         "jenkins-x",
+		VaultSecretPrefix: "secret/jx",
+	}
+	o := &CommonOptions{
+		KubeClientCached: fake.NewSimpleClientset(
+			&corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{Name: jxBuilderServiceAccount, Namespace: "jx"},
+				Secrets:    []corev1.ObjectReference{{Name: "jenkins-x-token"}},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "jenkins-x-token", Namespace: "jx"},
+				Type:       corev1.SecretTypeServiceAccountToken,
+				Data:       map[string][]byte{corev1.ServiceAccountTokenKey: []byte("my-jwt")},
+			}),
+		jxClient:         jxfake.NewSimpleClientset(devEnv),
+		devNamespace:     "jx",
+		currentNamespace: "jx",
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "jx-pipeline-git-github-github"},
+		Data: map[string][]byte{
+			"username": []byte("jenkins-x-bot"),
+			"password": []byte("my-token"),
+		},
+	}
+	err := o.storeSecretDataInVault(secret)
+	require.NoError(t, err)
+	assert.Empty(t, secret.Data, "the data should only be stored in the Vault")
+	assert.Equal(t, "jx-pipeline-git-github-github", secret.Annotations[kube.AnnotationVaultSecret])
+	assert.Equal(t, map[string]interface{}{"username": "jenkins-x-bot", "password": "my-token"}, stored["secret/jx/jx-pipeline-git-github-github"])
+
+	secrets := []corev1.Secret{*secret, {ObjectMeta: metav1.ObjectMeta{Name: "kubernetes-secret"}, Data: map[string][]byte{"password": []byte("other")}}}
+	err = o.loadSecretDataFromVault(secrets)
+	require.NoError(t, err)
+	assert.Equal(t, "jenkins-x-bot", string(secrets[0].Data["username"]))
+	assert.Equal(t, "my-token", string(secrets[0].Data["password"]))
+	assert.Equal(t, "other", string(secrets[1].Data["password"]))
+}
+
+func TestSecretDataStaysInKubernetesWithoutASecretsLocation(t *testing.T) {
+	t.Parallel()
+	devEnv := kube.NewPermanentEnvironment("dev")
+	devEnv.Spec.Namespace = "jx"
+	o := &CommonOptions{
+		KubeClientCached: fake.NewSimpleClientset(),
+		jxClient:         jxfake.NewSimpleClientset(devEnv),
+		devNamespace:     "jx",
+		currentNamespace: "jx",
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-secret"},
+		Data:       map[string][]byte{"password": []byte("my-token")},
+	}
+	err := o.storeSecretDataInVault(secret)
+	require.NoError(t, err)
+	assert.Equal(t, "my-token", string(secret.Data["password"]))
+	assert.Empty(t, secret.Annotations)
+}
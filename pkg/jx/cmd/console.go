@@ -3,6 +3,8 @@ package cmd
 import (
 	"fmt"
 	"io"
+	"os"
+	"runtime"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
@@ -104,13 +106,36 @@ func (o *ConsoleOptions) Open(name string, label string) error {
 	if name == "jenkins" {
 		fullURL = o.urlForMode(url)
 	}
-	fmt.Fprintf(o.Out, "%s: %s\n", label, util.ColorInfo(fullURL))
-	if !o.OnlyViewURL {
-		browser.OpenURL(fullURL)
-	}
+	o.openURL(label, fullURL)
 	return nil
 }
 
+// openURL displays the URL and opens it in a browser unless only the URL is wanted or there is no browser to open it,
+// e.g. in an SSH session
+func (o *ConsoleOptions) openURL(label string, url string) {
+	fmt.Fprintf(o.Out, "%s: %s\n", label, util.ColorInfo(url))
+	if o.OnlyViewURL {
+		return
+	}
+	if !hasBrowser() {
+		log.Infof("There is no browser to open the URL so open it yourself\n")
+		return
+	}
+	err := browser.OpenURL(url)
+	if err != nil {
+		log.Warnf("Could not open a browser: %s\n", err)
+	}
+}
+
+// hasBrowser returns false on Linux and other unix systems without a display such as SSH sessions
+func hasBrowser() bool {
+	switch runtime.GOOS {
+	case "darwin", "windows":
+		return true
+	}
+	return os.Getenv("DISPLAY") != "" || os.Getenv("WAYLAND_DISPLAY") != ""
+}
+
 func (o *ConsoleOptions) urlForMode(url string) string {
 	if o.ClassicMode {
 		return url
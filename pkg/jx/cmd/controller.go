@@ -2,16 +2,25 @@ package cmd
 
 import (
 	"io"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/metrics"
 )
 
 // ControllerOptions contains the CLI options
 type ControllerOptions struct {
 	CommonOptions
+
+	MetricsPort int
 }
 
 var (
@@ -27,7 +36,7 @@ var (
 // NewCmdController creates the edit command
 func NewCmdController(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
 	options := &ControllerOptions{
-		CommonOptions{
+		CommonOptions: CommonOptions{
 			Factory: f,
 			In:      in,
 			Out:     out,
@@ -48,6 +57,7 @@ func NewCmdController(f Factory, in terminal.FileReader, out terminal.FileWriter
 		},
 	}
 
+	cmd.AddCommand(NewCmdControllerAzureDevOps(f, in, out, errOut))
 	cmd.AddCommand(NewCmdControllerBackup(f, in, out, errOut))
 	cmd.AddCommand(NewCmdControllerBuild(f, in, out, errOut))
 	cmd.AddCommand(NewCmdControllerRole(f, in, out, errOut))
@@ -60,3 +70,39 @@ func NewCmdController(f Factory, in terminal.FileReader, out terminal.FileWriter
 func (o *ControllerOptions) Run() error {
 	return o.Cmd.Help()
 }
+
+func (o *ControllerOptions) addMetricsFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVarP(&o.MetricsPort, "metrics-port", "", metrics.DefaultPort, "The port to serve the Prometheus metrics on. Use 0 to disable the metrics")
+}
+
+// startPipelineMetrics serves the metrics of the pipelines and of the preview environments of the namespace unless
+// they are disabled in which case nil is returned. The metrics are labelled with the name of the controller as the
+// build and workflow controllers may both record the same pipelines
+func (o *ControllerOptions) startPipelineMetrics(jxClient versioned.Interface, ns string, controller string) (*metrics.PipelineMetrics, error) {
+	if o.MetricsPort <= 0 {
+		return nil, nil
+	}
+	pipelineMetrics, err := metrics.NewPipelineMetrics(prometheus.DefaultRegisterer, controller)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		for {
+			envs, err := jxClient.JenkinsV1().Environments(ns).List(metav1.ListOptions{})
+			if err != nil {
+				log.Warnf("Failed to count the preview environments: %s\n", err)
+			} else {
+				count := 0
+				for i := range envs.Items {
+					if kube.IsPreviewEnvironment(&envs.Items[i]) {
+						count++
+					}
+				}
+				pipelineMetrics.SetPreviewEnvironments(count)
+			}
+			time.Sleep(time.Minute)
+		}
+	}()
+	metrics.Serve(o.MetricsPort)
+	return pipelineMetrics, nil
+}
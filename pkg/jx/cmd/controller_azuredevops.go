@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/jenkins-x/jx/pkg/version"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	azureDevOpsHookURL       = "http://hook/hook"
+	azureDevOpsHMACSecret    = "hmac-token"
+	azureDevOpsHMACSecretKey = "hmac"
+
+	// azureDevOpsWebHookService the exposed service running this controller which the service hooks of the Azure
+	// DevOps repositories are registered at
+	azureDevOpsWebHookService = "azuredevops-hook"
+	azureDevOpsWebHookPort    = 8080
+)
+
+var (
+	controllerAzureDevOpsLong = templates.LongDesc(`
+		Runs the controller which receives the service hook events of Azure DevOps repositories and posts them as
+		GitHub webhooks to the Prow hook, as Prow only understands the events of GitHub.
+
+		The controller is deployed as the azuredevops-hook service of the team when the webhooks of Azure DevOps
+		repositories are created, which registers the service hooks at the URL this controller is exposed on. The
+		service hooks authenticate with the Prow HMAC token which also signs the translated webhooks.
+`)
+
+	controllerAzureDevOpsExample = templates.Examples(`
+		# translate the Azure DevOps service hooks for the Prow hook
+		jx controller azuredevops --port 8080
+	`)
+)
+
+// ControllerAzureDevOpsOptions the options for the controller azuredevops command
+type ControllerAzureDevOpsOptions struct {
+	ControllerOptions
+
+	Port    int
+	HookURL string
+	Secret  string
+}
+
+// NewCmdControllerAzureDevOps creates the command
+func NewCmdControllerAzureDevOps(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &ControllerAzureDevOpsOptions{
+		ControllerOptions: ControllerOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "azuredevops",
+		Short:   "Runs the controller which translates Azure DevOps service hooks into GitHub webhooks for Prow",
+		Long:    controllerAzureDevOpsLong,
+		Example: controllerAzureDevOpsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().IntVarP(&options.Port, "port", "p", azureDevOpsWebHookPort, "The port to receive the service hooks on")
+	cmd.Flags().StringVarP(&options.HookURL, "hook-url", "", azureDevOpsHookURL, "The URL of the Prow hook to post the translated webhooks to")
+	cmd.Flags().StringVarP(&options.Secret, "secret", "s", "", "The webhook secret. Defaults to the Prow HMAC token")
+	options.addCommonFlags(cmd)
+	return cmd
+}
+
+// Run implements this command
+func (o *ControllerAzureDevOpsOptions) Run() error {
+	if o.Secret == "" {
+		kubeClient, ns, err := o.KubeClientAndDevNamespace()
+		if err != nil {
+			return err
+		}
+		secret, err := kubeClient.CoreV1().Secrets(ns).Get(azureDevOpsHMACSecret, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to find the Prow HMAC token in Secret %s in namespace %s", azureDevOpsHMACSecret, ns)
+		}
+		o.Secret = string(secret.Data[azureDevOpsHMACSecretKey])
+	}
+	proxy := &gits.AzureDevOpsWebHookProxy{
+		TargetURL: o.HookURL,
+		Secret:    o.Secret,
+	}
+	log.Infof("Translating Azure DevOps service hooks on port %s for %s\n", util.ColorInfo(o.Port), util.ColorInfo(o.HookURL))
+	return http.ListenAndServe(fmt.Sprintf(":%d", o.Port), proxy)
+}
+
+// ensureAzureDevOpsWebHook deploys this controller into the dev namespace if it is not running yet, exposes it and
+// returns the URL the service hooks of the Azure DevOps repositories are registered at
+func (o *CommonOptions) ensureAzureDevOpsWebHook(kubeClient kubernetes.Interface, ns string) (string, error) {
+	labels := map[string]string{"app": azureDevOpsWebHookService}
+	deployments := kubeClient.AppsV1().Deployments(ns)
+	_, err := deployments.Get(azureDevOpsWebHookService, metav1.GetOptions{})
+	if err != nil {
+		replicas := int32(1)
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   azureDevOpsWebHookService,
+				Labels: labels,
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec: corev1.PodSpec{
+						ServiceAccountName: jxBuilderServiceAccount,
+						Containers: []corev1.Container{
+							{
+								Name:  azureDevOpsWebHookService,
+								Image: o.offlineConfig.ImageName("jenkinsxio/jx:" + version.GetVersion()),
+								Args:  []string{"controller", "azuredevops", "--port", strconv.Itoa(azureDevOpsWebHookPort)},
+								Ports: []corev1.ContainerPort{{ContainerPort: azureDevOpsWebHookPort}},
+							},
+						},
+					},
+				},
+			},
+		}
+		_, err = deployments.Create(deployment)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to create the Deployment %s in namespace %s", azureDevOpsWebHookService, ns)
+		}
+		log.Infof("Deployed %s to translate the service hooks of Azure DevOps for Prow\n", util.ColorInfo(azureDevOpsWebHookService))
+	}
+
+	services := kubeClient.CoreV1().Services(ns)
+	_, err = services.Get(azureDevOpsWebHookService, metav1.GetOptions{})
+	if err != nil {
+		service := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        azureDevOpsWebHookService,
+				Labels:      labels,
+				Annotations: map[string]string{kube.ExposeAnnotation: "true"},
+			},
+			Spec: corev1.ServiceSpec{
+				Selector: labels,
+				Ports: []corev1.ServicePort{
+					{
+						Name:       "http",
+						Port:       80,
+						TargetPort: intstr.FromInt(azureDevOpsWebHookPort),
+					},
+				},
+			},
+		}
+		_, err = services.Create(service)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to create the Service %s in namespace %s", azureDevOpsWebHookService, ns)
+		}
+	}
+
+	hookURL, err := kube.GetServiceURLFromName(kubeClient, azureDevOpsWebHookService, ns)
+	if err != nil {
+		return "", err
+	}
+	if hookURL == "" {
+		ic, err := kube.GetIngressConfig(kubeClient, ns)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to get the ingress config of namespace %s", ns)
+		}
+		err = o.runExposecontroller(ns, ns, ic, azureDevOpsWebHookService)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to expose the Service %s", azureDevOpsWebHookService)
+		}
+		hookURL, err = kube.GetServiceURLFromName(kubeClient, azureDevOpsWebHookService, ns)
+		if err != nil {
+			return "", err
+		}
+		if hookURL == "" {
+			return "", fmt.Errorf("the Service %s in namespace %s has not been exposed", azureDevOpsWebHookService, ns)
+		}
+	}
+	return hookURL, nil
+}
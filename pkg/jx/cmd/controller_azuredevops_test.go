@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAzureDevOpsWebHookIsRegisteredAtTheTranslatingController(t *testing.T) {
+	t.Parallel()
+	kubeClient := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "jx"}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{
+			Name:        "hook",
+			Namespace:   "jx",
+			Annotations: map[string]string{kube.ExposeURLAnnotation: "http://hook.jx.example.com"},
+		}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{
+			Name:        azureDevOpsWebHookService,
+			Namespace:   "jx",
+			Annotations: map[string]string{kube.ExposeURLAnnotation: "http://azuredevops-hook.jx.example.com"},
+		}},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "hmac-token", Namespace: "jx"},
+			Data:       map[string][]byte{"hmac": []byte("my-hmac")},
+		})
+	o := &CommonOptions{
+		KubeClientCached: kubeClient,
+		currentNamespace: "jx",
+	}
+	repo := &gits.FakeRepository{GitRepo: &gits.GitRepository{Name: "myrepo"}}
+	provider := &gits.FakeProvider{
+		Type:         gits.AzureDevOps,
+		Repositories: map[string][]*gits.FakeRepository{"myproject": {repo}},
+	}
+
+	err := o.createWebhookProw("https://dev.azure.com/myorg/myproject/_git/myrepo", provider)
+	require.NoError(t, err)
+	require.Len(t, repo.WebHooks, 1)
+	assert.Equal(t, "http://azuredevops-hook.jx.example.com", repo.WebHooks[0].URL)
+	assert.Equal(t, "my-hmac", repo.WebHooks[0].Secret)
+
+	deployment, err := kubeClient.AppsV1().Deployments("jx").Get(azureDevOpsWebHookService, metav1.GetOptions{})
+	require.NoError(t, err, "the translating controller should be deployed")
+	assert.Equal(t, []string{"controller", "azuredevops", "--port", "8080"}, deployment.Spec.Template.Spec.Containers[0].Args)
+}
+
+func TestGitHubWebHookIsRegisteredAtTheProwHook(t *testing.T) {
+	t.Parallel()
+	kubeClient := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "jx"}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{
+			Name:        "hook",
+			Namespace:   "jx",
+			Annotations: map[string]string{kube.ExposeURLAnnotation: "http://hook.jx.example.com"},
+		}},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "hmac-token", Namespace: "jx"},
+			Data:       map[string][]byte{"hmac": []byte("my-hmac")},
+		})
+	o := &CommonOptions{
+		KubeClientCached: kubeClient,
+		currentNamespace: "jx",
+	}
+	repo := &gits.FakeRepository{GitRepo: &gits.GitRepository{Name: "myrepo"}}
+	provider := &gits.FakeProvider{
+		Type:         gits.GitHub,
+		Repositories: map[string][]*gits.FakeRepository{"myorg": {repo}},
+	}
+
+	err := o.createWebhookProw("https://github.com/myorg/myrepo", provider)
+	require.NoError(t, err)
+	require.Len(t, repo.WebHooks, 1)
+	assert.Equal(t, "http://hook.jx.example.com/hook", repo.WebHooks[0].URL)
+
+	_, err = kubeClient.AppsV1().Deployments("jx").Get(azureDevOpsWebHookService, metav1.GetOptions{})
+	assert.Error(t, err, "the translating controller should only be deployed for Azure DevOps")
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
 	"github.com/jenkins-x/jx/pkg/gits"
 	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/metrics"
 	"github.com/jenkins-x/jx/pkg/util"
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
@@ -28,6 +29,8 @@ type ControllerBuildOptions struct {
 	ControllerOptions
 
 	Namespace string
+
+	metrics *metrics.PipelineMetrics
 }
 
 // NewCmdControllerBuild creates a command object for the generic "get" action, which
@@ -57,6 +60,7 @@ func NewCmdControllerBuild(f Factory, in terminal.FileReader, out terminal.FileW
 	}
 
 	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The namespace to watch or defaults to the current namespace")
+	options.addMetricsFlags(cmd)
 	return cmd
 }
 
@@ -85,6 +89,11 @@ func (o *ControllerBuildOptions) Run() error {
 	if ns == "" {
 		ns = devNs
 	}
+	o.metrics, err = o.startPipelineMetrics(jxClient, ns, "build")
+	if err != nil {
+		return err
+	}
+
 	pod := &corev1.Pod{}
 	log.Infof("Watching for Knative build pods in namespace %s\n", util.ColorInfo(ns))
 	listWatch := cache.NewListWatchFromClient(client.CoreV1().RESTClient(), "pods", ns, fields.Everything())
@@ -141,6 +150,8 @@ func (o *ControllerBuildOptions) onPod(obj interface{}, jxClient versioned.Inter
 						_, err := activities.Update(a)
 						if err != nil {
 							log.Warnf("Failed to update PipelineActivities%s: %s\n", a.Name, err)
+						} else {
+							o.metrics.OnPipelineActivity(a)
 						}
 					}
 				}
@@ -209,8 +220,7 @@ func (o *ControllerBuildOptions) updatePipelineActivity(activity *v1.PipelineAct
 	copy := *activity
 	// TODO update the steps based on the Knative build pod's init containers
 	for _, c := range pod.Status.InitContainerStatuses {
-		name := strings.Replace(strings.TrimPrefix(c.Name, "build-step-"), "-", " ", -1)
-		title := strings.Title(name)
+		title := builds.StageName(c.Name)
 		_, stage, _ := kube.GetOrCreateStage(activity, title)
 
 		running := c.State.Running
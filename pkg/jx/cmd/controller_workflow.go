@@ -14,6 +14,7 @@ import (
 	"github.com/jenkins-x/jx/pkg/gits"
 	"github.com/jenkins-x/jx/pkg/helm"
 	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/metrics"
 	"github.com/jenkins-x/jx/pkg/util"
 	"github.com/jenkins-x/jx/pkg/workflow"
 	"github.com/pkg/errors"
@@ -46,6 +47,7 @@ type ControllerWorkflowOptions struct {
 	PullRequestPollDuration *time.Duration
 	workflowMap             map[string]*v1.Workflow
 	pipelineMap             map[string]*v1.PipelineActivity
+	metrics                 *metrics.PipelineMetrics
 }
 
 // NewCmdControllerWorkflow creates a command object for the generic "get" action, which
@@ -80,6 +82,7 @@ func NewCmdControllerWorkflow(f Factory, in terminal.FileReader, out terminal.Fi
 	cmd.Flags().StringVarP(&options.LocalHelmRepoName, "helm-repo-name", "r", kube.LocalHelmRepoName, "The name of the helm repository that contains the app")
 	cmd.Flags().BoolVarP(&options.NoWatch, "no-watch", "", false, "Disable watch so just performs any delta processes on pending workflows")
 	cmd.Flags().StringVarP(&options.PullRequestPollTime, optionPullRequestPollTime, "", "20s", "Poll time when waiting for a Pull Request to merge")
+	options.addMetricsFlags(cmd)
 	return cmd
 }
 
@@ -119,6 +122,11 @@ func (o *ControllerWorkflowOptions) Run() error {
 		return o.updatePipelinesWithoutWatching(jxClient, ns)
 	}
 
+	o.metrics, err = o.startPipelineMetrics(jxClient, ns, "workflow")
+	if err != nil {
+		return err
+	}
+
 	log.Infof("Watching for PipelineActivity resources in namespace %s\n", util.ColorInfo(ns))
 	workflow := &v1.Workflow{}
 	activity := &v1.PipelineActivity{}
@@ -157,6 +165,9 @@ func (o *ControllerWorkflowOptions) Run() error {
 				o.onActivityObj(newObj, jxClient, ns)
 			},
 			DeleteFunc: func(obj interface{}) {
+				if activity, ok := obj.(*v1.PipelineActivity); ok {
+					o.metrics.OnPipelineActivityDeleted(activity)
+				}
 			},
 		},
 	)
@@ -248,6 +259,7 @@ func (o *ControllerWorkflowOptions) onActivityObj(obj interface{}, jxClient vers
 				pipeline = activity
 			}
 		}
+		o.metrics.OnPipelineActivity(pipeline)
 		o.onActivity(pipeline, jxClient, ns)
 	}
 }
@@ -79,6 +79,7 @@ func NewCmdCreate(f Factory, in terminal.FileReader, out terminal.FileWriter, er
 	cmd.AddCommand(NewCmdCreateEtcHosts(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateGkeServiceAccount(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateGit(f, in, out, errOut))
+	cmd.AddCommand(NewCmdCreateGpgKey(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateIssue(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateJenkins(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateJHipster(f, in, out, errOut))
@@ -109,6 +110,8 @@ func (o *CreateProjectOptions) ImportCreatedProject(outDir string) error {
 	importOptions := &o.ImportOptions
 	importOptions.Dir = outDir
 	importOptions.DisableDotGitSearch = true
+	// the project has just been generated so a dry run generates the files of the import in it
+	importOptions.dryRunInPlace = true
 	return importOptions.Run()
 }
 
@@ -52,12 +52,14 @@ func NewCmdCreateAddon(f Factory, in terminal.FileReader, out terminal.FileWrite
 	cmd.AddCommand(NewCmdCreateAddonAmbassador(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateAddonAnchore(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateAddonCloudBees(f, in, out, errOut))
+	cmd.AddCommand(NewCmdCreateAddonFlagger(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateAddonGitea(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateAddonIstio(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateAddonKnativeBuild(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateAddonKubeless(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateAddonOwasp(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateAddonPipelineEvents(f, in, out, errOut))
+	cmd.AddCommand(NewCmdCreateAddonPrometheus(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateAddonProw(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateAddonSSO(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateAddonVault(f, in, out, errOut))
@@ -113,6 +115,11 @@ func (o *CreateAddonOptions) ExposeAddon(addon string) error {
 	if !ok {
 		return nil
 	}
+	return o.exposeAddonService(service)
+}
+
+// exposeAddonService annotates the service of an addon so that exposecontroller creates an ingress for it
+func (o *CreateAddonOptions) exposeAddonService(service string) error {
 	svc, err := o.KubeClientCached.CoreV1().Services(o.Namespace).Get(service, meta_v1.GetOptions{})
 	if err != nil {
 		return errors.Wrapf(err, "getting the addon service: %s", service)
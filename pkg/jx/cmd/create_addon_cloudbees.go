@@ -256,7 +256,7 @@ To register to get your username/password to to: %s
 			annotationsUpdated = true
 		}
 		if svc.Annotations[kube.AnnotationIngress] == "" {
-			svc.Annotations[kube.AnnotationIngress] = "nginx.ingress.kubernetes.io/auth-type: basic\nnginx.ingress.kubernetes.io/auth-secret: jx-basic-auth"
+			svc.Annotations[kube.AnnotationIngress] = o.basicAuthIngressAnnotations(devNamespace)
 			annotationsUpdated = true
 		}
 		if annotationsUpdated {
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+var (
+	createAddonFlaggerLong = templates.LongDesc(`
+		Creates the Flagger addon which rolls out the applications promoted to environments with the Canary promotion
+		strategy gradually, analysing the metrics of Istio at each step
+`)
+
+	createAddonFlaggerExample = templates.Examples(`
+		# Create the Flagger addon
+		jx create addon flagger
+
+		# Create the Flagger addon in a custom namespace
+		jx create addon flagger -n mynamespace
+	`)
+)
+
+// CreateAddonFlaggerOptions the options for the create addon flagger command
+type CreateAddonFlaggerOptions struct {
+	CreateAddonOptions
+
+	Chart string
+}
+
+// NewCmdCreateAddonFlagger creates a command object for the "create" command
+func NewCmdCreateAddonFlagger(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &CreateAddonFlaggerOptions{
+		CreateAddonOptions: CreateAddonOptions{
+			CreateOptions: CreateOptions{
+				CommonOptions: CommonOptions{
+					Factory: f,
+					In:      in,
+					Out:     out,
+					Err:     errOut,
+				},
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "flagger",
+		Short:   "Create a Flagger addon",
+		Long:    createAddonFlaggerLong,
+		Example: createAddonFlaggerExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addCommonFlags(cmd)
+	options.addFlags(cmd, flaggerNamespace, flaggerReleaseName, "")
+
+	cmd.Flags().StringVarP(&options.Chart, optionChart, "c", kube.ChartFlagger, "The name of the chart to use")
+	return cmd
+}
+
+// Run implements the command
+func (o *CreateAddonFlaggerOptions) Run() error {
+	if o.ReleaseName == "" {
+		return util.MissingOption(optionRelease)
+	}
+	if o.Chart == "" {
+		return util.MissingOption(optionChart)
+	}
+	err := o.addHelmRepoIfMissing(flaggerRepoURL, flaggerRepoName)
+	if err != nil {
+		return err
+	}
+
+	err = o.ensureHelm()
+	if err != nil {
+		return errors.Wrap(err, "failed to ensure that helm is present")
+	}
+
+	values := strings.Split(o.SetValues, ",")
+	err = o.installChart(o.ReleaseName, o.Chart, o.Version, o.Namespace, true, values)
+	if err != nil {
+		return err
+	}
+	return nil
+}
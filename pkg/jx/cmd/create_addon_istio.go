@@ -3,12 +3,10 @@ package cmd
 import (
 	"fmt"
 	"io"
-	"io/ioutil"
-	"path/filepath"
 	"strings"
 
+	"github.com/jenkins-x/jx/pkg/binaries"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
-	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
 	"github.com/pkg/errors"
@@ -19,22 +17,33 @@ import (
 const (
 	defaultIstioNamespace   = "istio-system"
 	defaultIstioReleaseName = "istio"
-	defaultIstioPassword    = "istio"
-	defaultIstioConfigDir   = "/istio_service_dir"
-	defaultIstioVersion     = ""
+	defaultIstioProfile     = "default"
+
+	optionIstioInjection = "istio-injection"
 )
 
 var (
 	createAddonIstioLong = templates.LongDesc(`
 		Creates the istio addon for service mesh on Kubernetes
+
+		Istio is installed with the istioctl of the requested version which is downloaded into ~/.jx/bin.
+
+		The pods of the staging, production and preview environments only get Istio sidecars when their namespaces are
+		labelled for injection which is controlled with the istioinjection team setting and 'jx edit environment --istio-injection'
 `)
 
 	createAddonIstioExample = templates.Examples(`
-		# Create the istio addon 
+		# Create the istio addon
 		jx create addon istio
 
 		# Create the istio addon in a custom namespace
 		jx create addon istio -n mynamespace
+
+		# Create the istio addon with the demo profile of a given version
+		jx create addon istio --profile demo --version 1.7.3
+
+		# Inject sidecars into the namespaces of new environments and previews
+		jx edit istioinjection true
 	`)
 )
 
@@ -42,11 +51,13 @@ var (
 type CreateAddonIstioOptions struct {
 	CreateAddonOptions
 
+	Profile string
+
+	// the flags of the former helm chart based install which are no longer used
 	Chart             string
 	Password          string
 	ConfigDir         string
 	NoInjectorWebhook bool
-	Dir               string
 }
 
 // NewCmdCreateAddonIstio creates a command object for the "create" command
@@ -79,76 +90,71 @@ func NewCmdCreateAddonIstio(f Factory, in terminal.FileReader, out terminal.File
 	}
 
 	options.addCommonFlags(cmd)
-	options.addFlags(cmd, defaultIstioNamespace, defaultIstioReleaseName, defaultIstioVersion)
+	options.addIstioFlags(cmd)
 
-	cmd.Flags().StringVarP(&options.Password, "password", "p", defaultIstioPassword, "The default password to use for Istio")
-	cmd.Flags().StringVarP(&options.ConfigDir, "config-dir", "d", defaultIstioConfigDir, "The config directory to use")
+	cmd.Flags().StringVarP(&options.Profile, "profile", "", defaultIstioProfile, "The istioctl installation profile such as default, demo or minimal")
+	cmd.Flags().StringVarP(&options.Password, "password", "p", "", "The default password to use for Istio")
+	cmd.Flags().StringVarP(&options.ConfigDir, "config-dir", "d", "", "The config directory to use")
 	cmd.Flags().StringVarP(&options.Chart, optionChart, "c", "", "The name of the chart to use")
 	cmd.Flags().BoolVarP(&options.NoInjectorWebhook, "no-injector-webhook", "", false, "Disables the injector webhook")
+	cmd.Flags().MarkDeprecated("password", "Istio is installed with istioctl")
+	cmd.Flags().MarkDeprecated("config-dir", "Istio is installed with istioctl")
+	cmd.Flags().MarkDeprecated(optionChart, "Istio is installed with istioctl")
+	cmd.Flags().MarkDeprecated("no-injector-webhook", "sidecars are only injected into the namespaces labelled for injection, see 'jx edit environment --istio-injection'")
 	return cmd
 }
 
+// addIstioFlags adds the flags shared by the commands which install and upgrade Istio
+func (o *CreateAddonOptions) addIstioFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.Namespace, "namespace", "n", defaultIstioNamespace, "The Namespace of the Istio control plane")
+	cmd.Flags().StringVarP(&o.ReleaseName, optionRelease, "r", defaultIstioReleaseName, "The release name of Istio if it was installed with helm by an older version of jx")
+	cmd.Flags().StringVarP(&o.SetValues, "set", "s", "", "The istioctl set values (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	cmd.Flags().StringVarP(&o.Version, "version", "v", binaries.IstioctlVersion, "The version of Istio to install")
+}
+
 // Run implements the command
 func (o *CreateAddonIstioOptions) Run() error {
-	if o.Chart == "" {
-		// lets git clone the source to find the istio charts as they are not published anywhere yet
-		dir, err := o.getIstioChartsFromGitHub()
-		if err != nil {
-			return err
-		}
-		chartDir := filepath.Join(dir, kube.ChartIstio)
-		exists, err := util.FileExists(chartDir)
-		if !exists {
-			return fmt.Errorf("Could not find folder %s inside istio clone at %s", kube.ChartIstio, dir)
-		}
-		o.Dir = dir
-		o.Chart = kube.ChartIstio
-	}
-	if o.ReleaseName == "" {
-		return util.MissingOption(optionRelease)
+	if o.Namespace == "" {
+		return util.MissingOption("namespace")
 	}
-	if o.Chart == "" {
-		return util.MissingOption(optionChart)
+	if o.Version == "" {
+		return util.MissingOption("version")
 	}
-	err := o.ensureHelm()
-	if err != nil {
-		return errors.Wrap(err, "failed to ensure that Helm is present")
-	}
-	_, _, err = o.KubeClient()
+	legacy, err := o.isIstioHelmRelease()
 	if err != nil {
 		return err
 	}
-
-	devNamespace, _, err := kube.GetDevNamespace(o.KubeClientCached, o.currentNamespace)
+	if legacy {
+		return fmt.Errorf("Istio was installed with the helm release %s, use 'jx upgrade addon istio' to install Istio %s with istioctl", o.ReleaseName, o.Version)
+	}
+	istioctl, err := o.installIstioctl(o.Version)
 	if err != nil {
-		return fmt.Errorf("cannot find a dev team namespace to get existing exposecontroller config from. %v", err)
+		return errors.Wrapf(err, "failed to install istioctl %s", o.Version)
 	}
-
-	log.Infof("found dev namespace %s\n", devNamespace)
-
-	values := []string{}
-	if o.NoInjectorWebhook {
-		values = append(values, "sidecarInjectorWebhook.enabled=false")
+	profile := o.Profile
+	if profile == "" {
+		profile = defaultIstioProfile
 	}
-	setValues := strings.Split(o.SetValues, ",")
-	values = append(values, setValues...)
-	err = o.installChartAt(o.Dir, o.ReleaseName, o.Chart, o.Version, o.Namespace, true, values)
+	args := append([]string{"install", "--skip-confirmation", "--set", "profile=" + profile}, istioSetArgs(o.Namespace, o.SetValues)...)
+	log.Infof("Installing Istio %s into namespace %s\n", util.ColorInfo(o.Version), util.ColorInfo(o.Namespace))
+	err = o.runCommandVerbose(istioctl, args...)
 	if err != nil {
-		return fmt.Errorf("istio deployment failed: %v", err)
+		return errors.Wrap(err, "istio deployment failed")
 	}
+	log.Infof("Installed Istio %s. Sidecars are injected into the namespaces with the %s label, see %s\n",
+		util.ColorInfo(o.Version), util.ColorInfo("istio-injection=enabled"), util.ColorInfo("jx edit environment --istio-injection"))
 	return nil
 }
 
-func (o *CreateAddonIstioOptions) getIstioChartsFromGitHub() (string, error) {
-	answer, err := ioutil.TempDir("", "istio")
-	if err != nil {
-		return answer, err
-	}
-	gitRepo := "https://github.com/istio/istio.git"
-	log.Infof("Cloning %s to %s\n", util.ColorInfo(gitRepo), util.ColorInfo(answer))
-	err = o.Git().Clone(gitRepo, answer)
-	if err != nil {
-		return answer, err
+// istioSetArgs returns the istioctl arguments which install the control plane into the namespace along with the
+// comma separated set values
+func istioSetArgs(ns string, setValues string) []string {
+	args := []string{"--set", "values.global.istioNamespace=" + ns}
+	for _, value := range strings.Split(setValues, ",") {
+		value = strings.TrimSpace(value)
+		if value != "" {
+			args = append(args, "--set", value)
+		}
 	}
-	return answer, nil
+	return args
 }
@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIstioSetArgs(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, []string{"--set", "values.global.istioNamespace=istio-system"}, istioSetArgs("istio-system", ""))
+	assert.Equal(t, []string{
+		"--set", "values.global.istioNamespace=mesh",
+		"--set", "values.gateways.istio-ingressgateway.type=NodePort",
+		"--set", "meshConfig.accessLogFile=/dev/stdout",
+	}, istioSetArgs("mesh", "values.gateways.istio-ingressgateway.type=NodePort, meshConfig.accessLogFile=/dev/stdout"))
+}
@@ -128,13 +128,13 @@ func (o *CreateAddonPipelineEventsOptions) Run() error {
 	}
 
 	// annotate the kibana and elasticsearch services so exposecontroller can create an ingress rule
-	err = o.addExposecontrollerAnnotations(kibanaServiceName)
+	err = o.addExposecontrollerAnnotations(devNamespace, kibanaServiceName)
 	if err != nil {
 		return err
 	}
 
 	esServiceName := kube.AddonServices[defaultPEName]
-	err = o.addExposecontrollerAnnotations(esServiceName)
+	err = o.addExposecontrollerAnnotations(devNamespace, esServiceName)
 	if err != nil {
 		return err
 	}
@@ -193,7 +193,7 @@ func (o *CreateAddonPipelineEventsOptions) Run() error {
 	log.Successf("kibana is available and running %s\n", kIng)
 	return nil
 }
-func (o *CreateAddonPipelineEventsOptions) addExposecontrollerAnnotations(serviceName string) error {
+func (o *CreateAddonPipelineEventsOptions) addExposecontrollerAnnotations(devNamespace string, serviceName string) error {
 
 	svc, err := o.KubeClientCached.CoreV1().Services(o.Namespace).Get(serviceName, meta_v1.GetOptions{})
 	if err != nil {
@@ -209,7 +209,7 @@ func (o *CreateAddonPipelineEventsOptions) addExposecontrollerAnnotations(servic
 		annotationsUpdated = true
 	}
 	if svc.Annotations[kube.AnnotationIngress] == "" {
-		svc.Annotations[kube.AnnotationIngress] = "nginx.ingress.kubernetes.io/auth-type: basic\nnginx.ingress.kubernetes.io/auth-secret: jx-basic-auth"
+		svc.Annotations[kube.AnnotationIngress] = o.basicAuthIngressAnnotations(devNamespace)
 		annotationsUpdated = true
 	}
 	if annotationsUpdated {
@@ -0,0 +1,313 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/metrics"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+const (
+	prometheusRepoName           = "prometheus-community"
+	prometheusRepoURL            = "https://prometheus-community.github.io/helm-charts"
+	defaultPrometheusNamespace   = "monitoring"
+	defaultPrometheusReleaseName = "prometheus"
+
+	// the kube-prometheus-stack chart names the Grafana service after the release
+	prometheusGrafanaServiceSuffix = "-grafana"
+)
+
+var (
+	createAddonPrometheusLong = templates.LongDesc(`
+		Creates the Prometheus addon which installs Prometheus and Grafana with settings sized for Jenkins X
+
+		Prometheus scrapes the metrics of the pods of the development environment which have the
+		prometheus.io/scrape annotation, such as the build and workflow controllers which export the metrics of the
+		pipelines and the preview environments. Grafana comes with dashboards of these metrics and is exposed
+		via exposecontroller.
+`)
+
+	createAddonPrometheusExample = templates.Examples(`
+		# Create the Prometheus addon
+		jx create addon prometheus
+
+		# Create the Prometheus addon in a custom namespace with a given Grafana admin password
+		jx create addon prometheus -n mynamespace --password mypassword
+	`)
+)
+
+// CreateAddonPrometheusOptions the options for the create addon prometheus command
+type CreateAddonPrometheusOptions struct {
+	CreateAddonOptions
+
+	Chart    string
+	Password string
+}
+
+// NewCmdCreateAddonPrometheus creates a command object for the "create" command
+func NewCmdCreateAddonPrometheus(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &CreateAddonPrometheusOptions{
+		CreateAddonOptions: CreateAddonOptions{
+			CreateOptions: CreateOptions{
+				CommonOptions: CommonOptions{
+					Factory: f,
+					In:      in,
+					Out:     out,
+					Err:     errOut,
+				},
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "prometheus",
+		Short:   "Create a Prometheus addon with Grafana dashboards of the pipelines",
+		Long:    createAddonPrometheusLong,
+		Example: createAddonPrometheusExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addCommonFlags(cmd)
+	options.addFlags(cmd, defaultPrometheusNamespace, defaultPrometheusReleaseName, "")
+
+	cmd.Flags().StringVarP(&options.Chart, optionChart, "c", kube.ChartKubePrometheusStack, "The name of the chart to use")
+	cmd.Flags().StringVarP(&options.Password, "password", "p", "", "The password of the Grafana admin user. Defaults to a generated password")
+	return cmd
+}
+
+// Run implements the command
+func (o *CreateAddonPrometheusOptions) Run() error {
+	if o.ReleaseName == "" {
+		return util.MissingOption(optionRelease)
+	}
+	if o.Chart == "" {
+		return util.MissingOption(optionChart)
+	}
+	err := o.addHelmRepoIfMissing(prometheusRepoURL, prometheusRepoName)
+	if err != nil {
+		return err
+	}
+	err = o.ensureHelm()
+	if err != nil {
+		return errors.Wrap(err, "failed to ensure that helm is present")
+	}
+	kubeClient, _, err := o.KubeClient()
+	if err != nil {
+		return err
+	}
+	devNamespace, _, err := kube.GetDevNamespace(kubeClient, o.currentNamespace)
+	if err != nil {
+		return errors.Wrap(err, "retrieving the dev namespace")
+	}
+
+	password := o.Password
+	if password == "" {
+		password, err = util.RandStringBytesMaskImprSrc(16)
+		if err != nil {
+			return errors.Wrap(err, "failed to generate the Grafana admin password")
+		}
+	}
+	tmpDir, err := ioutil.TempDir("", "prometheus-addon-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	valuesFile := filepath.Join(tmpDir, helm.ValuesFileName)
+	err = helm.SaveValuesFile(valuesFile, prometheusAddonValues(devNamespace, password))
+	if err != nil {
+		return err
+	}
+
+	err = o.installChartOptions(InstallChartOptions{
+		ReleaseName: o.ReleaseName,
+		Chart:       o.Chart,
+		Version:     o.Version,
+		Ns:          o.Namespace,
+		HelmUpdate:  o.HelmUpdate,
+		SetValues:   strings.Split(o.SetValues, ","),
+		ValueFiles:  []string{valuesFile},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = createOrUpdateGrafanaDashboards(kubeClient, o.Namespace)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the Grafana dashboards")
+	}
+
+	grafanaService := o.ReleaseName + prometheusGrafanaServiceSuffix
+	err = o.exposeAddonService(grafanaService)
+	if err != nil {
+		return err
+	}
+	url, err := kube.GetServiceURLFromName(kubeClient, grafanaService, o.Namespace)
+	if err != nil {
+		log.Warnf("Failed to find the URL of Grafana: %s\n", err)
+	} else {
+		log.Infof("Grafana is available at %s\n", util.ColorInfo(url))
+	}
+	if o.Password == "" {
+		log.Infof("The Grafana admin user is %s with the password %s\n", util.ColorInfo("admin"), util.ColorInfo(password))
+	}
+	return nil
+}
+
+// prometheusAddonValues returns the values of the kube-prometheus-stack chart sized for Jenkins X which scrape the
+// annotated pods of the development environment
+func prometheusAddonValues(devNamespace string, password string) map[string]interface{} {
+	return map[string]interface{}{
+		"alertmanager": map[string]interface{}{
+			"enabled": false,
+		},
+		// the control plane components are not reachable on most managed clusters
+		"kubeEtcd": map[string]interface{}{
+			"enabled": false,
+		},
+		"kubeControllerManager": map[string]interface{}{
+			"enabled": false,
+		},
+		"kubeScheduler": map[string]interface{}{
+			"enabled": false,
+		},
+		"prometheus": map[string]interface{}{
+			"prometheusSpec": map[string]interface{}{
+				"retention": "7d",
+				"resources": map[string]interface{}{
+					"requests": map[string]interface{}{
+						"cpu":    "200m",
+						"memory": "512Mi",
+					},
+					"limits": map[string]interface{}{
+						"memory": "1Gi",
+					},
+				},
+				"serviceMonitorSelectorNilUsesHelmValues": false,
+				"additionalScrapeConfigs": []interface{}{
+					map[string]interface{}{
+						"job_name": "jenkins-x",
+						"kubernetes_sd_configs": []interface{}{
+							map[string]interface{}{
+								"role": "pod",
+								"namespaces": map[string]interface{}{
+									"names": []interface{}{devNamespace},
+								},
+							},
+						},
+						"relabel_configs": []interface{}{
+							map[string]interface{}{
+								"source_labels": []interface{}{"__meta_kubernetes_pod_annotation_prometheus_io_scrape"},
+								"action":        "keep",
+								"regex":         "true",
+							},
+							map[string]interface{}{
+								"source_labels": []interface{}{"__address__", "__meta_kubernetes_pod_annotation_prometheus_io_port"},
+								"action":        "replace",
+								"regex":         `([^:]+)(?::\d+)?;(\d+)`,
+								"replacement":   "$1:$2",
+								"target_label":  "__address__",
+							},
+							map[string]interface{}{
+								"source_labels": []interface{}{"__meta_kubernetes_pod_name"},
+								"target_label":  "pod",
+							},
+						},
+					},
+				},
+			},
+		},
+		"grafana": map[string]interface{}{
+			"adminPassword": password,
+			"resources": map[string]interface{}{
+				"requests": map[string]interface{}{
+					"cpu":    "100m",
+					"memory": "128Mi",
+				},
+				"limits": map[string]interface{}{
+					"memory": "256Mi",
+				},
+			},
+			"sidecar": map[string]interface{}{
+				"dashboards": map[string]interface{}{
+					"enabled": true,
+					"label":   metrics.DashboardLabel,
+				},
+			},
+		},
+	}
+}
+
+// createOrUpdateGrafanaDashboards creates the ConfigMaps of the dashboards of the pipeline metrics which the Grafana
+// sidecar loads
+func createOrUpdateGrafanaDashboards(kubeClient kubernetes.Interface, ns string) error {
+	dashboards, err := metrics.Dashboards()
+	if err != nil {
+		return err
+	}
+	configMaps := kubeClient.CoreV1().ConfigMaps(ns)
+	for _, name := range util.SortedMapKeys(dashboards) {
+		data := map[string]string{
+			name + ".json": dashboards[name],
+		}
+		cm, err := configMaps.Get(name, metav1.GetOptions{})
+		if err != nil {
+			if !k8serrors.IsNotFound(err) {
+				return err
+			}
+			cm = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: name,
+					Labels: map[string]string{
+						metrics.DashboardLabel: "1",
+					},
+				},
+				Data: data,
+			}
+			_, err = configMaps.Create(cm)
+		} else {
+			cm.Data = data
+			_, err = configMaps.Update(cm)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to save the ConfigMap of dashboard %s", name)
+		}
+	}
+	return nil
+}
+
+// deleteGrafanaDashboards deletes the ConfigMaps of the dashboards of the pipeline metrics
+func deleteGrafanaDashboards(kubeClient kubernetes.Interface, ns string) error {
+	dashboards, err := metrics.Dashboards()
+	if err != nil {
+		return err
+	}
+	for _, name := range util.SortedMapKeys(dashboards) {
+		err = kubeClient.CoreV1().ConfigMaps(ns).Delete(name, &metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete the ConfigMap of dashboard %s: %s", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPrometheusAddonValues(t *testing.T) {
+	t.Parallel()
+	values := prometheusAddonValues("jx", "s3cr3t")
+
+	grafana := values["grafana"].(map[string]interface{})
+	assert.Equal(t, "s3cr3t", grafana["adminPassword"])
+	sidecar := grafana["sidecar"].(map[string]interface{})["dashboards"].(map[string]interface{})
+	assert.Equal(t, metrics.DashboardLabel, sidecar["label"])
+
+	spec := values["prometheus"].(map[string]interface{})["prometheusSpec"].(map[string]interface{})
+	scrapeConfigs := spec["additionalScrapeConfigs"].([]interface{})
+	require.Len(t, scrapeConfigs, 1)
+	sdConfigs := scrapeConfigs[0].(map[string]interface{})["kubernetes_sd_configs"].([]interface{})
+	namespaces := sdConfigs[0].(map[string]interface{})["namespaces"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"jx"}, namespaces["names"], "only the pods of the dev environment are scraped")
+}
+
+func TestGrafanaDashboardConfigMaps(t *testing.T) {
+	t.Parallel()
+	kubeClient := fake.NewSimpleClientset()
+	ns := defaultPrometheusNamespace
+
+	require.NoError(t, createOrUpdateGrafanaDashboards(kubeClient, ns))
+	// updating the existing dashboards
+	require.NoError(t, createOrUpdateGrafanaDashboards(kubeClient, ns))
+
+	list, err := kubeClient.CoreV1().ConfigMaps(ns).List(metav1.ListOptions{LabelSelector: metrics.DashboardLabel})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 2)
+	for _, cm := range list.Items {
+		assert.Contains(t, cm.Data, cm.Name+".json")
+	}
+
+	require.NoError(t, deleteGrafanaDashboards(kubeClient, ns))
+	require.NoError(t, deleteGrafanaDashboards(kubeClient, ns), "deleting missing dashboards should not fail")
+	list, err = kubeClient.CoreV1().ConfigMaps(ns).List(metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, list.Items)
+}
@@ -28,10 +28,14 @@ const (
 	GKE        = "gke"
 	OKE        = "oke"
 	EKS        = "eks"
+	DOKS       = "doks"
+	OPENSTACK  = "openstack"
 	AKS        = "aks"
 	AWS        = "aws"
 	PKS        = "pks"
 	MINIKUBE   = "minikube"
+	KIND       = "kind"
+	K3S        = "k3s"
 	MINISHIFT  = "minishift"
 	KUBERNETES = "kubernetes"
 	OPENSHIFT  = "openshift"
@@ -44,7 +48,7 @@ const (
 	optionClusterName       = "cluster-name"
 )
 
-var KUBERNETES_PROVIDERS = []string{MINIKUBE, GKE, OKE, AKS, AWS, EKS, KUBERNETES, IBM, OPENSHIFT, MINISHIFT, JX_INFRA, PKS}
+var KUBERNETES_PROVIDERS = []string{MINIKUBE, KIND, K3S, GKE, OKE, AKS, AWS, EKS, DOKS, OPENSTACK, KUBERNETES, IBM, OPENSHIFT, MINISHIFT, JX_INFRA, PKS}
 
 const (
 	stableKubeCtlVersionURL = "https://storage.googleapis.com/kubernetes-release/release/stable.txt"
@@ -54,8 +58,13 @@ const (
     * aks (Azure Container Service - https://docs.microsoft.com/en-us/azure/aks)
     * aws (Amazon Web Services via kops - https://github.com/aws-samples/aws-workshop-for-kubernetes/blob/master/readme.adoc)
     * eks (Amazon Web Services Elastic Container Service for Kubernetes - https://docs.aws.amazon.com/eks/latest/userguide/getting-started.html)
+    * doks (DigitalOcean Kubernetes - https://www.digitalocean.com/products/kubernetes)
+    * ibm (IBM Cloud Kubernetes Service - https://cloud.ibm.com/docs/containers)
     * gke (Google Container Engine - https://cloud.google.com/kubernetes-engine)
+    * openstack (OpenStack Magnum - https://docs.openstack.org/magnum/latest)
     * oke (Oracle Cloud Infrastructure Container Engine for Kubernetes - https://docs.cloud.oracle.com/iaas/Content/ContEng/Concepts/contengoverview.htm)
+    * kind (Kubernetes cluster running in Docker containers on your laptop - https://kind.sigs.k8s.io)
+    * k3s (lightweight Kubernetes cluster running in Docker containers on your laptop via k3d - https://k3d.io)
     * kubernetes for custom installations of Kubernetes
     * minikube (single-node Kubernetes cluster inside a VM on your laptop)
 	* minishift (single-node OpenShift cluster inside a VM on your laptop)
@@ -81,8 +90,13 @@ var (
 		- draft (CLI that makes it easy to build applications that run on Kubernetes)
 		- minikube (single-node Kubernetes cluster inside a VM on your laptop )
 		- minishift (single-node OpenShift cluster inside a VM on your laptop)
+		- kind (Kubernetes cluster running in Docker containers on your laptop)
+		- k3d (lightweight k3s Kubernetes cluster running in Docker containers on your laptop)
 		- virtualisation drivers (to run Minikube in a VM)
 		- gcloud (Google Cloud CLI)
+		- doctl (DigitalOcean CLI)
+		- openstack (OpenStack CLI with the Magnum plugin)
+		- ibmcloud (IBM Cloud CLI with the container-service plugin)
 		- oci (Oracle Cloud Infrastructure CLI)
 		- az (Azure CLI)
 
@@ -125,11 +139,16 @@ func NewCmdCreateCluster(f Factory, in terminal.FileReader, out terminal.FileWri
 
 	cmd.AddCommand(NewCmdCreateClusterAKS(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateClusterAWS(f, in, out, errOut))
+	cmd.AddCommand(NewCmdCreateClusterDOKS(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateClusterEKS(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateClusterGKE(f, in, out, errOut))
+	cmd.AddCommand(NewCmdCreateClusterIKS(f, in, out, errOut))
+	cmd.AddCommand(NewCmdCreateClusterK3s(f, in, out, errOut))
+	cmd.AddCommand(NewCmdCreateClusterKind(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateClusterMinikube(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateClusterMinishift(f, in, out, errOut))
 	cmd.AddCommand(NewCmdCreateClusterOKE(f, in, out, errOut))
+	cmd.AddCommand(NewCmdCreateClusterOpenStack(f, in, out, errOut))
 
 	return cmd
 }
@@ -175,6 +194,26 @@ func (o *CreateClusterOptions) Run() error {
 	return o.Cmd.Help()
 }
 
+// installRequirements installs the command line tools of the cloud provider, downloading them from the mirror of the
+// --offline-config if there is one
+func (o *CreateClusterOptions) installRequirements(cloudProvider string, extraDependencies ...string) error {
+	err := o.loadOfflineConfig(o.InstallOptions.Flags.OfflineConfig)
+	if err != nil {
+		return err
+	}
+	return o.CommonOptions.installRequirements(cloudProvider, extraDependencies...)
+}
+
+// installMissingDependencies installs the given command line tools, downloading them from the mirror of the
+// --offline-config if there is one
+func (o *CreateClusterOptions) installMissingDependencies(deps []string) error {
+	err := o.loadOfflineConfig(o.InstallOptions.Flags.OfflineConfig)
+	if err != nil {
+		return err
+	}
+	return o.CommonOptions.installMissingDependencies(deps)
+}
+
 func (o *CreateClusterOptions) addCreateClusterFlags(cmd *cobra.Command) {
 	o.InstallOptions.addInstallFlags(cmd, true)
 	cmd.Flags().BoolVarP(&o.SkipInstallation, "skip-installation", "", false, "Provision cluster only, don't install Jenkins X into it")
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"strings"
@@ -9,6 +10,8 @@ import (
 	"github.com/jenkins-x/jx/pkg/cloud/aks"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
@@ -19,6 +22,10 @@ type CreateClusterAKSOptions struct {
 	CreateClusterOptions
 
 	Flags CreateClusterAKSFlags
+
+	// zones and vmSetType are parsed from the --zones and --vm-set-type flags
+	zones     []string
+	vmSetType string
 }
 
 type CreateClusterAKSFlags struct {
@@ -51,6 +58,11 @@ type CreateClusterAKSFlags struct {
 	SkipProviderRegistration  bool
 	SkipResourceGroupCreation bool
 	Tags                      string
+	Zones                     string
+	VMSetType                 string
+	ManagedIdentity           bool
+	AttachACR                 bool
+	ACRName                   string
 }
 
 var (
@@ -75,6 +87,15 @@ var (
 
 		jx create cluster aks
 
+		# to spread the nodes across availability zones
+		jx create cluster aks --location westeurope --zones 1,2,3
+
+		# to use a managed identity rather than a service principal and pull images from a registry named after the cluster
+		jx create cluster aks --managed-identity --attach-acr
+
+		# to attach an existing Azure Container Registry
+		jx create cluster aks --attach-acr --acr-name myregistry
+
 `)
 )
 
@@ -128,17 +149,33 @@ func NewCmdCreateClusterAKS(f Factory, in terminal.FileReader, out terminal.File
 	cmd.Flags().BoolVarP(&options.Flags.SkipProviderRegistration, "skip-provider-registration", "", false, "Skip provider registration")
 	cmd.Flags().BoolVarP(&options.Flags.SkipResourceGroupCreation, "skip-resource-group-creation", "", false, "Skip resource group creation")
 	cmd.Flags().StringVarP(&options.Flags.Tags, "tags", "", "", "Space-separated tags in 'key[=value]' format. Use '' to clear existing tags.")
+	cmd.Flags().StringVarP(&options.Flags.Zones, "zones", "", "", "Comma separated list of the availability zones to spread the nodes across, such as 1,2,3. Requires a location which supports availability zones")
+	cmd.Flags().StringVarP(&options.Flags.VMSetType, "vm-set-type", "", "", "The VM set type of the nodes, one of: "+strings.Join(aks.VMSetTypes, ", ")+". Defaults to "+aks.VMSetTypeScaleSets+" if --zones is specified")
+	cmd.Flags().BoolVarP(&options.Flags.ManagedIdentity, "managed-identity", "", false, "Use a managed identity for the cluster rather than creating a service principal whose client secret expires")
+	cmd.Flags().BoolVarP(&options.Flags.AttachACR, "attach-acr", "", false, "Attaches an Azure Container Registry to the cluster and uses it as the Docker registry, which is created if it does not exist")
+	cmd.Flags().StringVarP(&options.Flags.ACRName, "acr-name", "", "", "The name of the Azure Container Registry to attach. Implies --attach-acr. Defaults to a registry named after the cluster")
 	return cmd
 }
 
 func (o *CreateClusterAKSOptions) Run() error {
+	if len(o.Args) > 0 {
+		return fmt.Errorf("unexpected arguments %s, use --acr-name to give the name of the Azure Container Registry to attach", strings.Join(o.Args, " "))
+	}
+	if o.Flags.ManagedIdentity && (o.Flags.ServicePrincipal != "" || o.Flags.ClientSecret != "") {
+		return util.InvalidOptionf("managed-identity", "", "--managed-identity cannot be used with --service-principal or --client-secret")
+	}
+
+	err := o.validateZonesFlags()
+	if err != nil {
+		return err
+	}
 
 	var deps []string
 	d := binaryShouldBeInstalled("az")
 	if d != "" {
 		deps = append(deps, d)
 	}
-	err := o.installMissingDependencies(deps)
+	err = o.installMissingDependencies(deps)
 	if err != nil {
 		log.Errorf("%v\nPlease fix the error or install manually then try again", err)
 		os.Exit(-1)
@@ -183,6 +220,11 @@ func (o *CreateClusterAKSOptions) createClusterAKS() error {
 		}
 	}
 
+	err := aks.ValidateZones(location, o.zones)
+	if err != nil {
+		return util.InvalidOptionError("zones", o.Flags.Zones, err)
+	}
+
 	nodeVMSize := o.Flags.NodeVMSize
 	if nodeVMSize == "" {
 		prompts := &survey.Select{
@@ -214,7 +256,6 @@ func (o *CreateClusterAKSOptions) createClusterAKS() error {
 	userName := o.Flags.UserName
 	password := o.Flags.Password
 
-	var err error
 	if !o.Flags.SkipLogin {
 		//First login
 
@@ -269,6 +310,14 @@ func (o *CreateClusterAKSOptions) createClusterAKS() error {
 			return err
 		}
 	}
+	acrName := ""
+	if o.Flags.AttachACR || o.Flags.ACRName != "" {
+		acrName, err = o.getOrCreateACR(o.Flags.ACRName, clusterName, resourceName, location)
+		if err != nil {
+			return err
+		}
+	}
+
 	createCluster := []string{"aks", "create", "-g", resourceName, "-n", clusterName}
 
 	if o.Flags.KubeVersion != "" {
@@ -353,6 +402,24 @@ func (o *CreateClusterAKSOptions) createClusterAKS() error {
 		createCluster = append(createCluster, "--tags", o.Flags.Tags)
 	}
 
+	if o.Flags.ManagedIdentity {
+		createCluster = append(createCluster, "--enable-managed-identity")
+	}
+
+	if acrName != "" {
+		createCluster = append(createCluster, "--attach-acr", acrName)
+	}
+
+	if o.vmSetType != "" {
+		createCluster = append(createCluster, "--vm-set-type", o.vmSetType)
+	}
+
+	if len(o.zones) > 0 {
+		// nodes in availability zones can only be load balanced by the standard load balancer
+		createCluster = append(createCluster, "--load-balancer-sku", "standard", "--zones")
+		createCluster = append(createCluster, o.zones...)
+	}
+
 	log.Infof("Creating cluster named %s in resource group %s...\n", clusterName, resourceName)
 	err = o.RunCommand("az", createCluster...)
 	if err != nil {
@@ -368,6 +435,56 @@ func (o *CreateClusterAKSOptions) createClusterAKS() error {
 		return err
 	}
 
+	if acrName != "" && o.InstallOptions.Flags.DockerRegistry == "" {
+		// the nodes pull from the attached registry with the identity of the cluster so no docker-registry secret is needed
+		o.InstallOptions.Flags.DockerRegistry = aks.ACRLoginServer(acrName)
+		log.Infof("Using the Azure Container Registry %s as the Docker registry\n", util.ColorInfo(o.InstallOptions.Flags.DockerRegistry))
+	}
+
 	log.Info("Initialising cluster ...\n")
 	return o.initAndInstall(AKS)
 }
+
+// getOrCreateACR returns the name of the Azure Container Registry to attach to the cluster, which is named after the
+// cluster if no name is given, creating it in the resource group if it does not exist
+func (o *CreateClusterAKSOptions) getOrCreateACR(name string, clusterName string, resourceGroup string, location string) (string, error) {
+	if name == "" {
+		name = aks.ACRName(clusterName)
+	}
+	_, err := o.getCommandOutput("", "az", "acr", "show", "--name", name, "--query", "loginServer", "--output", "tsv")
+	if err == nil {
+		log.Infof("Attaching the existing Azure Container Registry %s\n", util.ColorInfo(name))
+		return name, nil
+	}
+	log.Infof("Creating the Azure Container Registry %s in resource group %s\n", util.ColorInfo(name), util.ColorInfo(resourceGroup))
+	err = o.RunCommand("az", "acr", "create", "--name", name, "--resource-group", resourceGroup, "--location", location, "--sku", "Basic")
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create the Azure Container Registry %s", name)
+	}
+	return name, nil
+}
+
+// validateZonesFlags parses the availability zones and the VM set type, which defaults to scale sets when
+// availability zones are requested as the nodes of an availability set cannot be spread across zones
+func (o *CreateClusterAKSOptions) validateZonesFlags() error {
+	zones, err := aks.ParseZones(o.Flags.Zones)
+	if err != nil {
+		return util.InvalidOptionError("zones", o.Flags.Zones, err)
+	}
+	vmSetType := o.Flags.VMSetType
+	if vmSetType != "" && !util.Contains(aks.VMSetTypes, vmSetType) {
+		return util.InvalidOption("vm-set-type", vmSetType, aks.VMSetTypes)
+	}
+	if len(zones) > 0 {
+		if vmSetType == aks.VMSetTypeAvailabilitySet {
+			return util.InvalidOptionf("vm-set-type", vmSetType, "--zones requires the VM set type %s", aks.VMSetTypeScaleSets)
+		}
+		if vmSetType == "" {
+			log.Infof("Using VM set type %s as it is required to spread the nodes across availability zones %s\n", util.ColorInfo(aks.VMSetTypeScaleSets), util.ColorInfo(strings.Join(zones, ", ")))
+			vmSetType = aks.VMSetTypeScaleSets
+		}
+	}
+	o.zones = zones
+	o.vmSetType = vmSetType
+	return nil
+}
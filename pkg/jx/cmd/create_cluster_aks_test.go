@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/cloud/aks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateClusterAKSZonesFlags(t *testing.T) {
+	t.Parallel()
+	o := &CreateClusterAKSOptions{}
+	assert.Nil(t, o.validateZonesFlags())
+	assert.Empty(t, o.zones)
+	assert.Equal(t, "", o.vmSetType)
+
+	o.Flags.Zones = "1,2,3"
+	assert.Nil(t, o.validateZonesFlags())
+	assert.Equal(t, []string{"1", "2", "3"}, o.zones)
+	assert.Equal(t, aks.VMSetTypeScaleSets, o.vmSetType, "scale sets are required for availability zones")
+
+	o.Flags.VMSetType = aks.VMSetTypeAvailabilitySet
+	assert.NotNil(t, o.validateZonesFlags())
+
+	o.Flags.VMSetType = "Spot"
+	assert.NotNil(t, o.validateZonesFlags())
+
+	o.Flags.Zones = "4"
+	o.Flags.VMSetType = ""
+	assert.NotNil(t, o.validateZonesFlags())
+}
+
+func TestCreateClusterAKSAttachACRFlags(t *testing.T) {
+	t.Parallel()
+	cmd := NewCmdCreateClusterAKS(nil, nil, nil, nil)
+	require.NoError(t, cmd.ParseFlags([]string{"--attach-acr", "--acr-name", "myregistry"}))
+	attach, err := cmd.Flags().GetBool("attach-acr")
+	require.NoError(t, err)
+	assert.True(t, attach)
+	name, err := cmd.Flags().GetString("acr-name")
+	require.NoError(t, err)
+	assert.Equal(t, "myregistry", name)
+
+	o := &CreateClusterAKSOptions{}
+	o.Args = []string{"myregistry"}
+	o.Flags.AttachACR = true
+	err = o.Run()
+	require.Error(t, err, "a registry name after --attach-acr should not be ignored")
+	assert.Contains(t, err.Error(), "--acr-name")
+}
@@ -44,6 +44,7 @@ type CreateClusterAWSFlags struct {
 	NodeSize               string
 	MasterSize             string
 	State                  string
+	Tags                   string
 }
 
 var (
@@ -103,6 +104,7 @@ func NewCmdCreateClusterAWS(f Factory, in terminal.FileReader, out terminal.File
 	cmd.Flags().StringVarP(&options.Flags.NodeSize, "node-size", "", "", "The size of a node in the kops created cluster.")
 	cmd.Flags().StringVarP(&options.Flags.MasterSize, "master-size", "", "", "The size of a master in the kops created cluster.")
 	cmd.Flags().StringVarP(&options.Flags.State, "state", "", "", "The S3 bucket used to store the state of the cluster.")
+	cmd.Flags().StringVarP(&options.Flags.Tags, "tags", "", "", "Comma separated list of key=value tags applied to the instances, the S3 buckets and other AWS resources created for the cluster")
 	return cmd
 }
 
@@ -121,6 +123,10 @@ func (o *CreateClusterAWSOptions) Run() error {
 	}
 
 	flags := &o.Flags
+	tags, err := amazon.ParseTags(flags.Tags)
+	if err != nil {
+		return util.InvalidOptionError("tags", flags.Tags, err)
+	}
 
 	if flags.NodeCount == "" {
 		prompt := &survey.Input{
@@ -143,6 +149,25 @@ func (o *CreateClusterAWSOptions) Run() error {
 		}
 	*/
 
+	if flags.NodeSize != "" || flags.MasterSize != "" {
+		region, err := amazon.ResolveRegion(flags.Profile, flags.Region)
+		if err != nil {
+			return err
+		}
+		if flags.NodeSize != "" {
+			err = amazon.ValidateInstanceType(flags.Profile, region, flags.NodeSize)
+			if err != nil {
+				return util.InvalidOptionError("node-size", flags.NodeSize, err)
+			}
+		}
+		if flags.MasterSize != "" {
+			err = amazon.ValidateInstanceType(flags.Profile, region, flags.MasterSize)
+			if err != nil {
+				return util.InvalidOptionError("master-size", flags.MasterSize, err)
+			}
+		}
+	}
+
 	zones := flags.Zones
 	if zones == "" {
 		zones = os.Getenv("AWS_AVAILABILITY_ZONES")
@@ -193,7 +218,7 @@ func (o *CreateClusterAWSOptions) Run() error {
 			bucketName := "kops-state-" + accountId + "-" + string(uuid.NewUUID())
 			log.Infof("Creating S3 bucket %s to store kops state\n", util.ColorInfo(bucketName))
 
-			location, err := amazon.CreateS3Bucket(bucketName, o.Flags.Profile, o.Flags.Region)
+			location, err := amazon.CreateS3Bucket(bucketName, o.Flags.Profile, o.Flags.Region, tags)
 			if err != nil {
 				return err
 			}
@@ -241,6 +266,9 @@ func (o *CreateClusterAWSOptions) Run() error {
 		auth = "AlwaysAllow"
 	}
 	args = append(args, "--authorization", auth, "--zones", zones, "--yes")
+	if len(tags) > 0 {
+		args = append(args, "--cloud-labels", amazon.FormatTags(tags))
+	}
 
 	if flags.TerraformDirectory != "" {
 		args = append(args, "--out", flags.TerraformDirectory, "--target=terraform")
@@ -290,6 +318,9 @@ func (o *CreateClusterAWSOptions) Run() error {
 	log.Blank()
 
 	log.Info("Initialising cluster ...\n")
+	o.InstallOptions.CloudResourceTags = tags
+	o.InstallOptions.AWSProfile = flags.Profile
+	o.InstallOptions.AWSRegion = flags.Region
 	return o.initAndInstall(AWS)
 }
 
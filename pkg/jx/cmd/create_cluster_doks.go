@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Pallinder/go-randomdata"
+	"github.com/jenkins-x/jx/pkg/cloud/doks"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// CreateClusterDOKSOptions the flags for running create cluster doks
+type CreateClusterDOKSOptions struct {
+	CreateClusterOptions
+
+	Flags CreateClusterDOKSFlags
+}
+
+// CreateClusterDOKSFlags the flags for creating a DigitalOcean Kubernetes cluster
+type CreateClusterDOKSFlags struct {
+	ClusterName       string
+	Region            string
+	Size              string
+	NodeCount         int
+	KubernetesVersion string
+}
+
+var (
+	createClusterDOKSLong = templates.LongDesc(`
+		This command creates a new Kubernetes cluster on DigitalOcean, installing required local dependencies and
+		provisions the Jenkins X platform
+
+		DigitalOcean Kubernetes (DOKS) is a managed Kubernetes service. The cluster is created via doctl which
+		must be authenticated via 'doctl auth init' or the ` + doks.AccessTokenEnvVar + ` environment variable.
+
+`)
+
+	createClusterDOKSExample = templates.Examples(`
+
+		jx create cluster doks
+
+		# create a cluster of 5 nodes in London
+		jx create cluster doks --region lon1 --size s-4vcpu-8gb --node-count 5
+
+`)
+)
+
+// NewCmdCreateClusterDOKS creates the command
+func NewCmdCreateClusterDOKS(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := CreateClusterDOKSOptions{
+		CreateClusterOptions: createCreateClusterOptions(f, in, out, errOut, DOKS),
+	}
+	cmd := &cobra.Command{
+		Use:     "doks",
+		Short:   "Create a new Kubernetes cluster on DigitalOcean: Runs on DigitalOcean Kubernetes",
+		Long:    createClusterDOKSLong,
+		Example: createClusterDOKSExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addCreateClusterFlags(cmd)
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.Flags.ClusterName, optionClusterName, "n", "", "The name of the cluster. Defaults to a generated name")
+	cmd.Flags().StringVarP(&options.Flags.Region, "region", "r", doks.DefaultRegion, "The DigitalOcean region of the cluster")
+	cmd.Flags().StringVarP(&options.Flags.Size, "size", "", doks.DefaultSize, "The droplet size slug of the nodes")
+	cmd.Flags().IntVarP(&options.Flags.NodeCount, "node-count", "", doks.DefaultNodeCount, "The number of nodes")
+	cmd.Flags().StringVarP(&options.Flags.KubernetesVersion, optionKubernetesVersion, "", doks.DefaultVersion, "The Kubernetes version slug of the cluster, see 'doctl kubernetes options versions'")
+	return cmd
+}
+
+// Run implements this command
+func (o *CreateClusterDOKSOptions) Run() error {
+	if o.Flags.NodeCount < 1 {
+		return util.InvalidOptionf("node-count", strconv.Itoa(o.Flags.NodeCount), "a cluster needs at least one node")
+	}
+	err := o.installRequirements(DOKS)
+	if err != nil {
+		log.Errorf("error installing missing dependencies %v, please fix or install manually then try again", err)
+		os.Exit(-1)
+	}
+
+	err = o.createClusterDOKS()
+	if err != nil {
+		log.Errorf("error creating cluster %v", err)
+		os.Exit(-1)
+	}
+	return nil
+}
+
+func (o *CreateClusterDOKSOptions) createClusterDOKS() error {
+	_, err := o.getCommandOutput("", "doctl", "account", "get")
+	if err != nil {
+		return fmt.Errorf("doctl is not authenticated, please run %s or set the %s environment variable: %s",
+			util.ColorInfo("doctl auth init"), doks.AccessTokenEnvVar, err)
+	}
+
+	if o.Flags.ClusterName == "" {
+		o.Flags.ClusterName = strings.ToLower(randomdata.SillyName())
+		log.Infof("No cluster name provided so using a generated one: %s\n", o.Flags.ClusterName)
+	}
+
+	args := doks.CreateClusterArgs(o.Flags.ClusterName, o.Flags.Region, o.Flags.Size, o.Flags.NodeCount, o.Flags.KubernetesVersion)
+	log.Infof("Creating DigitalOcean Kubernetes cluster %s - this can take a few minutes...\n", util.ColorInfo(o.Flags.ClusterName))
+	log.Infof("Running command: %s\n", util.ColorInfo("doctl "+strings.Join(args, " ")))
+	err = o.runCommandVerbose("doctl", args...)
+	if err != nil {
+		return err
+	}
+	log.Infof("DigitalOcean Kubernetes cluster %s created, using kube context %s\n",
+		util.ColorInfo(o.Flags.ClusterName), util.ColorInfo(doks.ContextName(o.Flags.Region, o.Flags.ClusterName)))
+
+	log.Info("Initialising cluster ...\n")
+	return o.initAndInstall(DOKS)
+}
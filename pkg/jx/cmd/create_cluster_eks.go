@@ -1,19 +1,42 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"github.com/Pallinder/go-randomdata"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/jenkins-x/jx/pkg/cloud/amazon"
+	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
 	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/pkg/errors"
+	logger "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
-	logger "github.com/sirupsen/logrus"
+)
+
+const (
+	optionVpcPublicSubnets  = "vpc-public-subnets"
+	optionVpcPrivateSubnets = "vpc-private-subnets"
+	optionNodeVolumeSize    = "node-volume-size"
+	optionNodeVolumeType    = "node-volume-type"
+
+	eksStackEventsPollInterval = 15 * time.Second
+	eksStackFailedEventsCount  = 5
+
+	// eksEndpointDialTimeout how long to wait when checking whether the private endpoint of a cluster can be reached
+	eksEndpointDialTimeout = 5 * time.Second
 )
 
 // CreateClusterEKSOptions contains the CLI flags
@@ -21,6 +44,13 @@ type CreateClusterEKSOptions struct {
 	CreateClusterOptions
 
 	Flags CreateClusterEKSFlags
+
+	// gpuNodes is true if the cluster is created with GPU nodes
+	gpuNodes bool
+
+	// nodeLabels and nodeTaints are parsed from the --node-labels and --node-taints flags
+	nodeLabels map[string]string
+	nodeTaints map[string]string
 }
 
 type CreateClusterEKSFlags struct {
@@ -35,6 +65,33 @@ type CreateClusterEKSFlags struct {
 	SshPublicKey        string
 	Verbose             int
 	AWSOperationTimeout time.Duration
+	EksctlTimeout       time.Duration
+	EksctlConfig        string
+	KubernetesVersion   string
+	Spot                bool
+	InstanceTypes       string
+	SpotMaxPrice        float64
+	DryRun              bool
+	SkipPreflight       bool
+	Tags                string
+	Managed             bool
+	VpcPublicSubnets    string
+	VpcPrivateSubnets   string
+	SkipGPUPlugin       bool
+	NodeVolumeSize      int
+	NodeVolumeType      string
+	EncryptedVolumes    bool
+	Retry               bool
+	AssumeRoleArn       string
+	RoleSessionName     string
+	NoSSHAccess         bool
+	SkipClusterCreate   bool
+	Fargate             bool
+	FargateNamespaces   string
+	NodeLabels          string
+	NodeTaints          string
+	PrivateNetworking   bool
+	PrivateEndpoint     bool
 }
 
 var (
@@ -52,6 +109,54 @@ var (
 
 		# to specify the zones
 		jx create cluster eks --zones us-west-2a,us-west-2b,us-west-2c
+
+		# to use a specific Kubernetes version
+		jx create cluster eks --kubernetes-version 1.13
+
+		# to create the nodes as spot instances using a mix of instance types
+		jx create cluster eks --spot --instance-types m5.large,m5a.large,m4.large --nodes-min 2 --nodes-max 5
+
+		# to create an EKS managed nodegroup
+		jx create cluster eks --managed
+
+		# to use the existing subnets of a VPC
+		jx create cluster eks --vpc-private-subnets subnet-0ff156e0c4a6d300c,subnet-0426fb4a607393184 --vpc-public-subnets subnet-0153e560b3129a696,subnet-009fa0199ec203c37
+
+		# to create the nodes with larger encrypted volumes for building images
+		jx create cluster eks --node-volume-size 100 --encrypted-volumes
+
+		# to create GPU nodes with the NVIDIA device plugin installed
+		jx create cluster eks --node-type p3.2xlarge
+
+		# to complete or recreate a cluster whose previous creation failed without prompting
+		jx create cluster eks --cluster-name mycluster --retry
+
+		# to create the cluster in another account by assuming a role there
+		jx create cluster eks --assume-role-arn arn:aws:iam::123456789012:role/jx-cluster-creator
+
+		# to create the nodes without SSH access
+		jx create cluster eks --no-ssh-access
+
+		# to install Jenkins X on an existing EKS cluster without creating one
+		jx create cluster eks --skip-cluster-create --cluster-name mycluster --region eu-west-1
+
+		# to run the pods of the preview and staging environments on Fargate
+		jx create cluster eks --fargate --fargate-namespaces jx-staging,jx-preview
+
+		# to label the nodes and only schedule pods which tolerate the taint on them
+		jx create cluster eks --node-labels workload=builds --node-taints dedicated=builds:NoSchedule
+
+		# to create the nodes without public IPs and only allow access to the API server from inside the VPC
+		jx create cluster eks --private-networking --private-endpoint
+
+		# to tag the CloudFormation stacks and other AWS resources created for the cluster
+		jx create cluster eks --tags team=platform,cost-center=1234
+
+		# to see the eksctl command and any generated eksctl config without creating the cluster
+		jx create cluster eks --dry-run
+
+		# to create the cluster from an eksctl ClusterConfig file
+		jx create cluster eks --eksctl-config cluster.yaml
 `)
 )
 
@@ -83,10 +188,37 @@ func NewCmdCreateClusterEKS(f Factory, in terminal.FileReader, out terminal.File
 	cmd.Flags().IntVarP(&options.Flags.NodesMax, "nodes-max", "", -1, "maximum number of nodes")
 	cmd.Flags().IntVarP(&options.Flags.Verbose, "eksctl-log-level", "", -1, "set log level, use 0 to silence, 4 for debugging and 5 for debugging with AWS debug logging (default 3)")
 	cmd.Flags().DurationVarP(&options.Flags.AWSOperationTimeout, "aws-api-timeout", "", 20*time.Minute, "Duration of AWS API timeout")
+	cmd.Flags().DurationVarP(&options.Flags.EksctlTimeout, "eksctl-timeout", "", time.Hour, "The maximum duration of each eksctl command, such as creating the cluster, after which it is stopped. Zero for no timeout")
 	cmd.Flags().StringVarP(&options.Flags.Region, "region", "r", "", "The region to use. Default: us-west-2")
 	cmd.Flags().StringVarP(&options.Flags.Zones, optionZones, "z", "", "Availability Zones. Auto-select if not specified. If provided, this overrides the $EKS_AVAILABILITY_ZONES environment variable")
 	cmd.Flags().StringVarP(&options.Flags.Profile, "profile", "p", "", "AWS profile to use. If provided, this overrides the AWS_PROFILE environment variable")
-	cmd.Flags().StringVarP(&options.Flags.SshPublicKey, "ssh-public-key", "", "", "SSH public key to use for nodes (import from local path, or use existing EC2 key pair) (default \"~/.ssh/id_rsa.pub\")")
+	cmd.Flags().StringVarP(&options.Flags.SshPublicKey, "ssh-public-key", "", "", "SSH public key to use for nodes (import from local path, or use existing EC2 key pair) (default \"~/.ssh/id_rsa.pub\"). If there is no default key a key pair is generated in ~/.jx/ssh/<cluster name>")
+	cmd.Flags().BoolVarP(&options.Flags.NoSSHAccess, "no-ssh-access", "", false, "Disable SSH access to the nodes")
+	cmd.Flags().StringVarP(&options.Flags.KubernetesVersion, optionKubernetesVersion, "v", "", "The Kubernetes version of the EKS control plane. Defaults to the eksctl default")
+	cmd.Flags().BoolVarP(&options.Flags.Spot, "spot", "", false, "Create the nodes as spot instances")
+	cmd.Flags().StringVarP(&options.Flags.InstanceTypes, "instance-types", "", "", "Comma separated list of instance types used for the mixed instances policy of the nodes")
+	cmd.Flags().Float64VarP(&options.Flags.SpotMaxPrice, "spot-max-price", "", 0, "The maximum hourly price to pay for spot instances. Defaults to the on-demand price")
+	cmd.Flags().BoolVarP(&options.Flags.DryRun, "dry-run", "", false, "Print the eksctl command and any generated eksctl config file without creating the cluster")
+	cmd.Flags().BoolVarP(&options.Flags.Managed, "managed", "", false, "Create an EKS managed nodegroup")
+	cmd.Flags().StringVarP(&options.Flags.VpcPublicSubnets, optionVpcPublicSubnets, "", "", "Comma separated list of the IDs of existing public subnets to use. The availability zones are implied by the subnets")
+	cmd.Flags().StringVarP(&options.Flags.VpcPrivateSubnets, optionVpcPrivateSubnets, "", "", "Comma separated list of the IDs of existing private subnets to use. The availability zones are implied by the subnets")
+	cmd.Flags().StringVarP(&options.Flags.Tags, "tags", "", "", "Comma separated list of key=value tags applied to the CloudFormation stacks and other AWS resources created for the cluster")
+	cmd.Flags().IntVarP(&options.Flags.NodeVolumeSize, optionNodeVolumeSize, "", 0, fmt.Sprintf("The size of the node volumes in GiB. Must be at least %d. Defaults to the eksctl default", amazon.MinEKSNodeVolumeSize))
+	cmd.Flags().StringVarP(&options.Flags.NodeVolumeType, optionNodeVolumeType, "", "", "The type of the node volumes, one of: "+strings.Join(amazon.EKSNodeVolumeTypes, ", ")+". Defaults to the eksctl default")
+	cmd.Flags().BoolVarP(&options.Flags.EncryptedVolumes, "encrypted-volumes", "", false, "Encrypt the EBS volumes of the nodes")
+	cmd.Flags().StringVarP(&options.Flags.NodeLabels, "node-labels", "", "", "Comma separated list of key=value labels added to the nodes")
+	cmd.Flags().StringVarP(&options.Flags.NodeTaints, "node-taints", "", "", "Comma separated list of key=value:Effect taints added to the nodes, where the effect is one of "+strings.Join(amazon.TaintEffects, ", "))
+	cmd.Flags().BoolVarP(&options.Flags.PrivateNetworking, "private-networking", "", false, "Create the nodes in private subnets without public IPs")
+	cmd.Flags().BoolVarP(&options.Flags.PrivateEndpoint, "private-endpoint", "", false, "Disable the public endpoint of the API server once the cluster is created so that it can only be reached from inside the VPC")
+	cmd.Flags().BoolVarP(&options.Flags.SkipGPUPlugin, "skip-gpu-plugin", "", false, "Do not install the NVIDIA device plugin when the nodes have GPUs")
+	cmd.Flags().BoolVarP(&options.Flags.Retry, "retry", "", false, "If a previous attempt to create the cluster failed, create its nodegroup or delete the failed stacks and create it again without prompting")
+	cmd.Flags().StringVarP(&options.Flags.AssumeRoleArn, "assume-role-arn", "", "", "The ARN of a role to assume with the current credentials which is then used to create the cluster")
+	cmd.Flags().StringVarP(&options.Flags.RoleSessionName, "role-session-name", "", "jx-create-cluster", "The session name used when assuming the role of --assume-role-arn")
+	cmd.Flags().BoolVarP(&options.Flags.SkipClusterCreate, "skip-cluster-create", "", false, "Install Jenkins X on the existing EKS cluster of --cluster-name rather than creating a new cluster")
+	cmd.Flags().BoolVarP(&options.Flags.Fargate, "fargate", "", false, "Run the pods on Fargate rather than creating a nodegroup")
+	cmd.Flags().StringVarP(&options.Flags.FargateNamespaces, "fargate-namespaces", "", "", "Comma separated list of namespaces to create Fargate profiles for in addition to the Jenkins X namespace and the namespaces of its default environments. Requires --fargate")
+	cmd.Flags().BoolVarP(&options.Flags.SkipPreflight, "skip-preflight", "", false, "Skip checking the AWS credentials, permissions and quotas before creating the cluster")
+	cmd.Flags().StringVarP(&options.Flags.EksctlConfig, "eksctl-config", "", "", "Path to an eksctl ClusterConfig YAML file used to create the cluster. Cannot be combined with the cluster, region, zone, node and ssh flags")
 	return cmd
 }
 
@@ -94,50 +226,319 @@ func NewCmdCreateClusterEKS(f Factory, in terminal.FileReader, out terminal.File
 func (o *CreateClusterEKSOptions) Run() error {
 	log.ConfigureLog(o.LogLevel)
 
-	var deps []string
-	d := binaryShouldBeInstalled("eksctl")
-	if d != "" {
-		deps = append(deps, d)
+	flags := &o.Flags
+	if flags.EksctlConfig != "" {
+		if flags.SkipClusterCreate {
+			return util.InvalidOptionf("skip-cluster-create", "", "--skip-cluster-create cannot be used with --eksctl-config")
+		}
+		err := o.validateEksctlConfigFlags()
+		if err != nil {
+			return err
+		}
 	}
-	d = binaryShouldBeInstalled("heptio-authenticator-aws")
-	if d != "" {
-		deps = append(deps, d)
+	err := o.validateSpotFlags()
+	if err != nil {
+		return err
+	}
+	err = o.validateNodeVolumeFlags()
+	if err != nil {
+		return err
 	}
-	logger.Debugf("Dependencies to be installed: %s", strings.Join(deps,", "))
-	err := o.installMissingDependencies(deps)
+	err = o.validateNodeLabelsAndTaints()
 	if err != nil {
-		logger.Errorf("%v\nPlease fix the error or install manually then try again", err)
-		os.Exit(-1)
+		return err
+	}
+	err = o.validateFargateFlags()
+	if err != nil {
+		return err
+	}
+	tags, err := amazon.ParseTags(flags.Tags)
+	if err != nil {
+		return util.InvalidOptionError("tags", flags.Tags, err)
 	}
 
+	if !flags.DryRun {
+		deps := eksClusterDependencies(flags.SkipClusterCreate)
+		logger.Debugf("Dependencies to be installed: %s", strings.Join(deps, ", "))
+		err = o.installMissingDependencies(deps)
+		if err != nil {
+			logger.Errorf("%v\nPlease fix the error or install manually then try again", err)
+			os.Exit(-1)
+		}
+	}
+
+	if flags.AssumeRoleArn != "" && !flags.DryRun {
+		logger.Infof("Assuming role %s", util.ColorInfo(flags.AssumeRoleArn))
+		err = amazon.AssumeRole(flags.Profile, flags.Region, flags.AssumeRoleArn, flags.RoleSessionName)
+		if err != nil {
+			return util.InvalidOptionError("assume-role-arn", flags.AssumeRoleArn, err)
+		}
+	}
+
+	if flags.SkipClusterCreate {
+		return o.installOnExistingCluster(tags)
+	}
+
+	if flags.EksctlConfig == "" {
+		if !o.BatchMode {
+			err = o.promptForMissingFlags()
+			if err != nil {
+				return err
+			}
+		}
+		err = o.resolveSSHAccess()
+		if err != nil {
+			return err
+		}
+	}
+
+	args, config, err := o.createEksctlArgs()
+	if err != nil {
+		return err
+	}
+	if flags.EksctlConfig == "" && !flags.Fargate {
+		err = o.validateInstanceTypes()
+		if err != nil {
+			return err
+		}
+	}
+	configFileName := ""
+	if config != nil {
+		configFile, err := ioutil.TempFile("", "jx-eksctl-config-")
+		if err != nil {
+			return err
+		}
+		configFile.Close()
+		configFileName = configFile.Name()
+		defer os.Remove(configFileName)
+		err = amazon.SaveEksctlClusterConfig(config, configFileName)
+		if err != nil {
+			return err
+		}
+		args = append([]string{"create", "cluster", "-f", configFileName}, args...)
+	}
+
+	if flags.DryRun {
+		fmt.Fprintf(o.Out, "eksctl %s\n", strings.Join(args, " "))
+		if configFileName != "" {
+			data, err := ioutil.ReadFile(configFileName)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(o.Out, "\n# %s\n%s", configFileName, string(data))
+		}
+		if flags.PrivateEndpoint {
+			fmt.Fprintf(o.Out, "aws eks update-cluster-config --name %s --region %s --resources-vpc-config endpointPublicAccess=false,endpointPrivateAccess=true\n", flags.ClusterName, flags.Region)
+		}
+		return nil
+	}
+
+	if !flags.SkipPreflight {
+		err = o.runPreflightChecks()
+		if err != nil {
+			return err
+		}
+	}
+
+	err = o.createOrResumeCluster(args)
+	if err != nil {
+		return err
+	}
+
+	if flags.Fargate {
+		err = o.createFargateProfiles()
+		if err != nil {
+			return err
+		}
+	}
+
+	if o.gpuNodes && !flags.SkipGPUPlugin {
+		err = o.installNvidiaDevicePlugin()
+		if err != nil {
+			return err
+		}
+	}
+
+	if flags.PrivateEndpoint {
+		err = o.disablePublicEndpoint()
+		if err != nil {
+			return err
+		}
+	}
+
+	err = exportAssumedRoleCredentials()
+	if err != nil {
+		return err
+	}
+	if flags.PrivateEndpoint {
+		err = o.checkPrivateEndpointReachable()
+		if err != nil {
+			return err
+		}
+	}
+	logger.Infof("Initialising cluster %s ...\n", util.ColorInfo(flags.ClusterName))
+	o.InstallOptions.CloudResourceTags = tags
+	o.InstallOptions.AWSProfile = flags.Profile
+	o.InstallOptions.AWSRegion = flags.Region
+	return o.initAndInstall(EKS)
+}
+
+// createEksctlArgs resolves the region, zones and other defaults and returns the arguments used to invoke eksctl.
+// If the nodes can only be described by an eksctl config file the generated config is returned too and the arguments
+// only contain the options to pass in addition to the config file
+func (o *CreateClusterEKSOptions) createEksctlArgs() ([]string, *amazon.EksctlClusterConfig, error) {
 	flags := &o.Flags
+	if flags.EksctlConfig != "" {
+		config, err := amazon.LoadEksctlClusterConfig(flags.EksctlConfig)
+		if err != nil {
+			return nil, nil, err
+		}
+		flags.ClusterName = config.Metadata.Name
+		flags.Region = config.Metadata.Region
+		o.gpuNodes = config.UsesGPUInstances()
+		args := []string{"create", "cluster", "-f", flags.EksctlConfig}
+		return o.appendCommonEksctlArgs(args), nil, nil
+	}
+
+	if flags.Managed {
+		err := o.validateManagedFlags()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	publicSubnets := amazon.ParseSubnetIDs(flags.VpcPublicSubnets)
+	privateSubnets := amazon.ParseSubnetIDs(flags.VpcPrivateSubnets)
+	usesSubnets := len(publicSubnets) > 0 || len(privateSubnets) > 0
+	if usesSubnets && flags.Zones != "" {
+		return nil, nil, util.InvalidOptionf(optionZones, flags.Zones, "--%s cannot be used with --%s or --%s as the availability zones are implied by the subnets", optionZones, optionVpcPublicSubnets, optionVpcPrivateSubnets)
+	}
+	if flags.PrivateNetworking && len(publicSubnets) > 0 && len(privateSubnets) == 0 {
+		return nil, nil, util.InvalidOptionf("private-networking", "", "--private-networking requires --%s as the nodes are created in the private subnets", optionVpcPrivateSubnets)
+	}
+	for option, subnets := range map[string][]string{optionVpcPublicSubnets: publicSubnets, optionVpcPrivateSubnets: privateSubnets} {
+		err := amazon.ValidateSubnetIDs(subnets)
+		if err != nil {
+			return nil, nil, util.InvalidOptionError(option, strings.Join(subnets, ","), err)
+		}
+	}
 
 	zones := flags.Zones
-	if zones == "" {
+	if zones == "" && !usesSubnets {
 		zones = os.Getenv("EKS_AVAILABILITY_ZONES")
 	}
 
-	args := []string{"create", "cluster", "--full-ecr-access"}
-	if flags.ClusterName != "" {
-		args = append(args, "--name", flags.ClusterName)
-	}
+	// the name is always passed to eksctl so that its stacks can be watched and resumed and the cluster configured
+	o.generateClusterNameIfMissing()
+	args := []string{"create", "cluster", "--full-ecr-access", "--name", flags.ClusterName}
 
-	region, err := amazon.ResolveRegion("", flags.Region)
+	region, err := amazon.ResolveRegion(flags.Profile, flags.Region)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
+	flags.Region = region
 	args = append(args, "--region", region)
 
+	zoneList := amazon.ParseZones(zones)
+	if len(zoneList) > 0 {
+		availableZones, err := amazon.RegionAvailabilityZones(flags.Profile, region)
+		if err != nil {
+			logger.Debugf("Could not find the availability zones of region %s: %s", region, err)
+		}
+		err = amazon.ValidateZones(region, zoneList, availableZones)
+		if err != nil {
+			return nil, nil, util.InvalidOptionError(optionZones, zones, err)
+		}
+		zones = strings.Join(zoneList, ",")
+	}
+
+	var subnetZones map[string]string
+	if usesSubnets {
+		subnetZones, err = amazon.SubnetAvailabilityZones(flags.Profile, region, append(append([]string{}, publicSubnets...), privateSubnets...))
+		if err != nil {
+			if _, ok := err.(awserr.Error); !ok {
+				return nil, nil, fmt.Errorf("invalid --%s or --%s: %s", optionVpcPublicSubnets, optionVpcPrivateSubnets, err)
+			}
+			logger.Debugf("Could not describe the subnets in region %s: %s", region, err)
+			subnetZones = nil
+		}
+	}
+
+	kubernetesVersion := flags.KubernetesVersion
+	if kubernetesVersion == "" && !o.BatchMode {
+		surveyOpts := survey.WithStdio(o.In, o.Out, o.Err)
+		prompt := &survey.Select{
+			Message: "Kubernetes version:",
+			Options: amazon.EKSKubernetesVersions(region),
+			Default: amazon.DefaultEKSKubernetesVersion,
+			Help:    "The Kubernetes version of the EKS control plane",
+		}
+		err = survey.AskOne(prompt, &kubernetesVersion, nil, surveyOpts)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if kubernetesVersion != "" {
+		err = amazon.ValidateEKSKubernetesVersion(region, kubernetesVersion)
+		if err != nil {
+			return nil, nil, util.InvalidOptionError(optionKubernetesVersion, kubernetesVersion, err)
+		}
+		args = append(args, "--version", kubernetesVersion)
+	}
+
+	o.gpuNodes = o.usesGPUInstances()
+	if o.requiresEksctlConfig() {
+		config := o.createEksctlClusterConfig(region, zones, kubernetesVersion)
+		if usesSubnets {
+			if subnetZones == nil {
+				return nil, nil, fmt.Errorf("could not find the availability zones of the subnets which are required to generate the eksctl config, please check your AWS credentials")
+			}
+			config.VPC = amazon.NewEksctlClusterVPC(publicSubnets, privateSubnets, subnetZones)
+		}
+		return o.appendCommonEksctlArgs(nil), config, nil
+	}
+
 	if zones != "" {
 		args = append(args, "--zones", zones)
 	}
-	if flags.Profile != "" {
-		args = append(args, "--profile", flags.Profile)
+	if len(publicSubnets) > 0 {
+		args = append(args, "--"+optionVpcPublicSubnets, strings.Join(publicSubnets, ","))
+	}
+	if len(privateSubnets) > 0 {
+		args = append(args, "--"+optionVpcPrivateSubnets, strings.Join(privateSubnets, ","))
+	}
+	if tags := o.tags(); len(tags) > 0 {
+		args = append(args, "--tags", amazon.FormatTags(tags))
+	}
+	if flags.Fargate {
+		args = append(args, "--fargate")
+		return o.appendCommonEksctlArgs(args), nil, nil
 	}
 	if flags.SshPublicKey != "" {
 		args = append(args, "--ssh-public-key", flags.SshPublicKey)
 	}
+	if flags.NoSSHAccess {
+		args = append(args, "--ssh-access=false")
+	}
 	args = append(args, "--node-type", flags.NodeType)
+	if o.gpuNodes {
+		args = append(args, "--node-ami-family", amazon.EKSGPUAMIFamily)
+	}
+	if flags.NodeVolumeSize > 0 {
+		args = append(args, "--"+optionNodeVolumeSize, strconv.Itoa(flags.NodeVolumeSize))
+	}
+	if flags.NodeVolumeType != "" {
+		args = append(args, "--"+optionNodeVolumeType, flags.NodeVolumeType)
+	}
+	if len(o.nodeLabels) > 0 {
+		args = append(args, "--node-labels", amazon.FormatTags(o.nodeLabels))
+	}
+	if flags.PrivateNetworking {
+		args = append(args, "--node-private-networking")
+	}
+	if flags.Managed {
+		args = append(args, "--managed")
+	}
 	if flags.NodeCount >= 0 {
 		args = append(args, "--nodes", strconv.Itoa(flags.NodeCount))
 	}
@@ -147,28 +548,826 @@ func (o *CreateClusterEKSOptions) Run() error {
 	if flags.NodesMax >= 0 {
 		args = append(args, "--nodes-max", strconv.Itoa(flags.NodesMax))
 	}
+	return o.appendCommonEksctlArgs(args), nil, nil
+}
+
+// generateClusterNameIfMissing generates a cluster name if none was specified
+func (o *CreateClusterEKSOptions) generateClusterNameIfMissing() {
+	flags := &o.Flags
+	if flags.ClusterName == "" {
+		flags.ClusterName = strings.ToLower(randomdata.SillyName())
+		logger.Infof("No cluster name provided so using a generated one: %s", flags.ClusterName)
+	}
+}
+
+// promptForMissingFlags asks for the cluster name, region and nodes if they were not specified on the command line
+func (o *CreateClusterEKSOptions) promptForMissingFlags() error {
+	flags := &o.Flags
+	var err error
+	if flags.ClusterName == "" {
+		flags.ClusterName, err = util.PickValue("Cluster name:", strings.ToLower(randomdata.SillyName()), true, o.In, o.Out, o.Err)
+		if err != nil {
+			return err
+		}
+	}
+
+	if flags.Region == "" {
+		defaultRegion, err := amazon.ResolveRegion(flags.Profile, "")
+		if err != nil {
+			return err
+		}
+		flags.Region, err = util.PickNameWithDefault(amazon.Regions(), "AWS region:", defaultRegion, o.In, o.Out, o.Err)
+		if err != nil {
+			return err
+		}
+	}
+
+	if flags.Fargate {
+		return nil
+	}
+
+	if flags.InstanceTypes == "" && !o.flagChanged("node-type") {
+		other := "other"
+		nodeType, err := util.PickNameWithDefault(append(amazon.EKSNodeTypes, other), "Node instance type:", flags.NodeType, o.In, o.Out, o.Err)
+		if err != nil {
+			return err
+		}
+		if nodeType == other {
+			nodeType, err = util.PickValue("Node instance type:", flags.NodeType, true, o.In, o.Out, o.Err)
+			if err != nil {
+				return err
+			}
+		}
+		flags.NodeType = nodeType
+	}
+
+	if flags.NodesMin < 0 {
+		defaultValue := 3
+		if flags.NodeCount >= 0 {
+			defaultValue = flags.NodeCount
+		}
+		flags.NodesMin, err = o.pickNodeCount("Minimum number of nodes:", defaultValue)
+		if err != nil {
+			return err
+		}
+	}
+	if flags.NodesMax < 0 {
+		defaultValue := 5
+		if flags.NodesMin > defaultValue {
+			defaultValue = flags.NodesMin
+		}
+		flags.NodesMax, err = o.pickNodeCount("Maximum number of nodes:", defaultValue)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveSSHAccess generates an SSH key pair for the nodes if no SSH public key was specified and the default one
+// does not exist, unless SSH access is disabled
+func (o *CreateClusterEKSOptions) resolveSSHAccess() error {
+	flags := &o.Flags
+	if flags.NoSSHAccess {
+		if flags.SshPublicKey != "" {
+			return util.InvalidOptionf("no-ssh-access", "", "--no-ssh-access cannot be used with --ssh-public-key")
+		}
+		return nil
+	}
+	// managed nodegroups do not allow SSH access by default and Fargate has no nodes so neither need a key
+	if flags.SshPublicKey != "" || flags.Managed || flags.Fargate {
+		return nil
+	}
+	defaultKey := util.DefaultSSHPublicKey()
+	exists, err := util.FileExists(defaultKey)
+	if err != nil || exists {
+		return err
+	}
+
+	if !o.BatchMode {
+		generate := "Generate a new SSH key pair"
+		answer, err := util.PickNameWithDefault([]string{generate, "Disable SSH access to the nodes"},
+			fmt.Sprintf("There is no SSH public key at %s. How should the nodes be accessed?", defaultKey), generate, o.In, o.Out, o.Err)
+		if err != nil {
+			return err
+		}
+		if answer != generate {
+			flags.NoSSHAccess = true
+			return nil
+		}
+	}
+	o.generateClusterNameIfMissing()
+	dir, err := util.SSHKeysDir(flags.ClusterName)
+	if err != nil {
+		return err
+	}
+	if flags.DryRun {
+		flags.SshPublicKey = filepath.Join(dir, util.SSHPublicKeyFileName)
+		return nil
+	}
+	flags.SshPublicKey, err = util.GenerateSSHKeyPair(dir, flags.ClusterName)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate the SSH key pair of the nodes")
+	}
+	logger.Infof("There is no SSH public key at %s so generated the SSH key pair %s", defaultKey, util.ColorInfo(flags.SshPublicKey))
+	return nil
+}
+
+// installOnExistingCluster points the kube config at an existing EKS cluster and installs Jenkins X on it
+func (o *CreateClusterEKSOptions) installOnExistingCluster(tags map[string]string) error {
+	flags := &o.Flags
+	if flags.ClusterName == "" {
+		if o.BatchMode {
+			return util.MissingOption(optionClusterName)
+		}
+		name, err := util.PickValue("Name of the existing EKS cluster:", "", true, o.In, o.Out, o.Err)
+		if err != nil {
+			return err
+		}
+		flags.ClusterName = name
+	}
+	region, err := amazon.ResolveRegion(flags.Profile, flags.Region)
+	if err != nil {
+		return err
+	}
+	flags.Region = region
+
+	if flags.DryRun {
+		fmt.Fprintf(o.Out, "aws eks update-kubeconfig --name %s --region %s\n", flags.ClusterName, region)
+		return nil
+	}
+	err = exportAssumedRoleCredentials()
+	if err != nil {
+		return err
+	}
+	contextName, err := amazon.UpdateKubeConfig(flags.Profile, region, flags.ClusterName, flags.AssumeRoleArn)
+	if err != nil {
+		return errors.Wrapf(err, "failed to find the EKS cluster %s in region %s", flags.ClusterName, region)
+	}
+	logger.Infof("Switched to the kube context %s of EKS cluster %s", util.ColorInfo(contextName), util.ColorInfo(flags.ClusterName))
+
+	if flags.Fargate {
+		err = o.createFargateProfiles()
+		if err != nil {
+			return err
+		}
+	} else {
+		client, _, err := o.KubeClient()
+		if err != nil {
+			return err
+		}
+		ready, total, err := kube.ReadyNodeCount(client)
+		if err != nil {
+			return errors.Wrapf(err, "failed to list the nodes of EKS cluster %s", flags.ClusterName)
+		}
+		if ready == 0 {
+			return fmt.Errorf("none of the %d nodes of EKS cluster %s are Ready, please add a nodegroup to the cluster or use --fargate and try again", total, flags.ClusterName)
+		}
+		logger.Infof("%d of %d nodes are Ready", ready, total)
+	}
+
+	logger.Infof("Initialising cluster %s ...\n", util.ColorInfo(flags.ClusterName))
+	o.InstallOptions.CloudResourceTags = tags
+	o.InstallOptions.AWSProfile = flags.Profile
+	o.InstallOptions.AWSRegion = flags.Region
+	return o.initAndInstall(EKS)
+}
+
+func (o *CreateClusterEKSOptions) pickNodeCount(message string, defaultValue int) (int, error) {
+	value, err := util.PickValue(message, strconv.Itoa(defaultValue), true, o.In, o.Out, o.Err)
+	if err != nil {
+		return 0, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || count < 0 {
+		return 0, fmt.Errorf("%s is not a valid number of nodes", value)
+	}
+	return count, nil
+}
+
+// tags returns the tags to apply to the AWS resources, the flag is validated at the start of Run
+func (o *CreateClusterEKSOptions) tags() map[string]string {
+	tags, _ := amazon.ParseTags(o.Flags.Tags)
+	return tags
+}
+
+// flagChanged returns true if the flag was specified on the command line
+func (o *CreateClusterEKSOptions) flagChanged(name string) bool {
+	return o.Cmd != nil && o.Cmd.Flags().Changed(name)
+}
+
+// runPreflightChecks checks the AWS credentials, permissions and quotas before any CloudFormation stacks are created
+func (o *CreateClusterEKSOptions) runPreflightChecks() error {
+	logger.Infof("Checking AWS credentials, permissions and quotas in region %s ...", util.ColorInfo(o.Flags.Region))
+	createsVpc := o.Flags.VpcPublicSubnets == "" && o.Flags.VpcPrivateSubnets == ""
+	checks, err := amazon.EKSPreflightChecks(o.Flags.Profile, o.Flags.Region, createsVpc)
+	if err != nil {
+		return err
+	}
+	table := o.CreateTable()
+	table.AddRow("CHECK", "STATUS", "DETAILS")
+	failed := []string{}
+	for _, check := range checks {
+		status := util.ColorInfo(check.Status)
+		if check.Failed() {
+			status = util.ColorError(check.Status)
+			failed = append(failed, check.Name)
+		} else if check.Status == amazon.PreflightWarning {
+			status = util.ColorWarning(check.Status)
+		}
+		table.AddRow(check.Name, status, check.Message)
+	}
+	table.Render()
+	if len(failed) > 0 {
+		return fmt.Errorf("pre-flight checks failed: %s\nPlease fix them or use --skip-preflight to create the cluster anyway", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// appendCommonEksctlArgs appends the eksctl arguments which are valid whether or not a config file is used
+func (o *CreateClusterEKSOptions) appendCommonEksctlArgs(args []string) []string {
+	flags := &o.Flags
+	// the credentials of an assumed role are passed to eksctl via the environment
+	if flags.Profile != "" && flags.AssumeRoleArn == "" {
+		args = append(args, "--profile", flags.Profile)
+	}
 	if flags.Verbose >= 0 {
 		args = append(args, "--verbose", strconv.Itoa(flags.Verbose))
 	}
-	args = append(args, "--aws-api-timeout", flags.AWSOperationTimeout.String())
+	return append(args, "--aws-api-timeout", flags.AWSOperationTimeout.String())
+}
+
+// runEksctl runs eksctl with the given arguments to create the cluster
+func (o *CreateClusterEKSOptions) runEksctl(args []string) error {
+	if o.Flags.NodeVolumeSize > 0 {
+		logger.Infof("Creating EKS cluster with %dGiB node volumes - this can take a while so please be patient...", o.Flags.NodeVolumeSize)
+	} else {
+		logger.Info("Creating EKS cluster - this can take a while so please be patient...")
+	}
+	logger.Infof("You can watch progress in the CloudFormation console: %s", util.ColorInfo(amazon.CloudFormationConsoleURL(o.Flags.Region)))
 
-	logger.Info("Creating EKS cluster - this can take a while so please be patient...")
-	logger.Infof("You can watch progress in the CloudFormation console: %s", util.ColorInfo("https://console.aws.amazon.com/cloudformation/"))
+	err := exportAssumedRoleCredentials()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := o.eksctlContext()
+	defer cancel()
 
 	logger.Debugf("Running command: %s", util.ColorInfo("eksctl "+strings.Join(args, " ")))
 	if logger.GetLevel() == logger.DebugLevel {
-		err = o.runCommandVerbose("eksctl", args...)
+		err := o.runCommandVerboseWithContext(ctx, "eksctl", args...)
 		if err != nil {
 			return err
 		}
 		log.Blank()
-	} else {
-		err = o.runCommandQuietly("eksctl", args...)
+		return nil
+	}
+
+	watcher, err := amazon.NewStackEventWatcher(o.Flags.Profile, o.Flags.Region, amazon.EksctlStackPrefix(o.Flags.ClusterName))
+	if err != nil {
+		logger.Debugf("Not watching the CloudFormation events: %s", err)
+		return o.runCommandQuietlyWithContext(ctx, "eksctl", args...)
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- o.runCommandQuietlyWithContext(ctx, "eksctl", args...)
+	}()
+	ticker := time.NewTicker(eksStackEventsPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-done:
+			logStackEvents(watcher)
+			if err != nil {
+				return stackEventsError(watcher, err)
+			}
+			return nil
+		case <-ticker.C:
+			logStackEvents(watcher)
+		}
+	}
+}
+
+// eksctlContext returns the context of an eksctl command, which is killed if it runs for longer than the eksctl
+// timeout. The AWS API timeout only limits the single AWS calls of eksctl
+func (o *CreateClusterEKSOptions) eksctlContext() (context.Context, context.CancelFunc) {
+	if o.Flags.EksctlTimeout > 0 {
+		return context.WithTimeout(context.Background(), o.Flags.EksctlTimeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// createOrResumeCluster runs eksctl to create the cluster. If eksctl fails, or a previous attempt failed, the state of
+// the eksctl stacks is inspected so that the cluster creation can be completed rather than failing again
+func (o *CreateClusterEKSOptions) createOrResumeCluster(args []string) error {
+	resumed, err := o.resumeCluster(args)
+	if err != nil || resumed {
+		return err
+	}
+	err = o.runEksctl(args)
+	if err == nil {
+		return nil
+	}
+	logger.Warnf("Failed to create the EKS cluster: %s", err)
+	resumed, resumeErr := o.resumeCluster(args)
+	if resumeErr != nil {
+		return resumeErr
+	}
+	if !resumed {
+		return err
+	}
+	return nil
+}
+
+// resumeCluster completes the creation of the cluster from the state of its eksctl stacks returning false if there
+// are no stacks to resume from
+func (o *CreateClusterEKSOptions) resumeCluster(args []string) (bool, error) {
+	flags := &o.Flags
+	if flags.ClusterName == "" {
+		return false, nil
+	}
+	stacks, err := amazon.EksctlStacks(flags.Profile, flags.Region, flags.ClusterName)
+	if err != nil {
+		logger.Debugf("Could not find the eksctl stacks of cluster %s: %s", flags.ClusterName, err)
+		return false, nil
+	}
+	name := util.ColorInfo(flags.ClusterName)
+	switch amazon.EksctlStacksState(flags.ClusterName, stacks, !flags.Fargate) {
+	case amazon.EksctlStacksMissing:
+		return false, nil
+
+	case amazon.EksctlStacksComplete:
+		logger.Infof("The EKS cluster %s already exists", name)
+		return true, nil
+
+	case amazon.EksctlStacksInProgress:
+		return true, fmt.Errorf("the CloudFormation stacks of cluster %s are still in progress, please wait for them to finish and try again", flags.ClusterName)
+
+	case amazon.EksctlStacksNodeGroupFailed:
+		if !o.confirmResume(fmt.Sprintf("The control plane of cluster %s exists but its nodegroup failed. Create the nodegroup?", flags.ClusterName)) {
+			return true, fmt.Errorf("the nodegroup of cluster %s failed, use --retry to create it", flags.ClusterName)
+		}
+		for _, stackName := range amazon.FailedEksctlNodeGroupStacks(flags.ClusterName, stacks) {
+			logger.Infof("Deleting the failed nodegroup stack %s", util.ColorInfo(stackName))
+			err = amazon.DeleteStack(flags.Profile, flags.Region, stackName)
+			if err != nil {
+				return true, err
+			}
+		}
+		logger.Infof("Creating the nodegroup of cluster %s", name)
+		return true, o.runEksctl(eksctlNodeGroupArgs(args))
+
+	default:
+		if !o.confirmResume(fmt.Sprintf("The CloudFormation stacks of cluster %s failed. Delete them and create the cluster again?", flags.ClusterName)) {
+			return true, fmt.Errorf("the CloudFormation stacks of cluster %s failed, use --retry to delete them and create the cluster again", flags.ClusterName)
+		}
+		// delete the nodegroup stacks before the control plane stack they depend on
+		clusterStackName := amazon.EksctlClusterStackName(flags.ClusterName)
+		stackNames := []string{}
+		for _, stack := range stacks {
+			stackName := aws.StringValue(stack.StackName)
+			if stackName != clusterStackName {
+				stackNames = append(stackNames, stackName)
+			}
+		}
+		stackNames = append(stackNames, clusterStackName)
+		for _, stackName := range stackNames {
+			logger.Infof("Deleting the failed stack %s", util.ColorInfo(stackName))
+			err = amazon.DeleteStack(flags.Profile, flags.Region, stackName)
+			if err != nil {
+				return true, err
+			}
+		}
+		return true, o.runEksctl(args)
+	}
+}
+
+// confirmResume returns true if the failed cluster creation should be resumed
+func (o *CreateClusterEKSOptions) confirmResume(message string) bool {
+	if o.Flags.Retry {
+		return true
+	}
+	if o.BatchMode {
+		return false
+	}
+	return util.Confirm(message, true, "", o.In, o.Out, o.Err)
+}
+
+// eksctlNodeGroupArgs converts the arguments used to create the cluster into the arguments used to create its nodegroup
+func eksctlNodeGroupArgs(args []string) []string {
+	answer := []string{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "cluster":
+			if i == 1 {
+				arg = "nodegroup"
+			}
+		case "--name":
+			arg = "--cluster"
+		case "--zones", "--" + optionVpcPublicSubnets, "--" + optionVpcPrivateSubnets:
+			// the nodegroup uses the zones and subnets of the cluster
+			i++
+			continue
+		}
+		answer = append(answer, arg)
+	}
+	return answer
+}
+
+// exportAssumedRoleCredentials sets the current credentials of any assumed role in the environment of the commands jx
+// runs, refreshing them first if they are about to expire
+func exportAssumedRoleCredentials() error {
+	env, err := amazon.AssumedRoleEnv()
+	if err != nil {
+		return errors.Wrap(err, "failed to refresh the credentials of the assumed role")
+	}
+	for name, value := range env {
+		err = os.Setenv(name, value)
 		if err != nil {
 			return err
 		}
 	}
+	return nil
+}
 
-	logger.Info("Initialising cluster ...\n")
-	return o.initAndInstall(EKS)
+// logStackEvents logs any new events of the eksctl CloudFormation stacks
+func logStackEvents(watcher *amazon.StackEventWatcher) {
+	events, err := watcher.Poll()
+	if err != nil {
+		logger.Debugf("Failed to describe the CloudFormation events: %s", err)
+		return
+	}
+	for _, event := range events {
+		logger.Info(amazon.FormatStackEvent(event))
+	}
+}
+
+// stackEventsError adds the last CREATE_FAILED CloudFormation events to the error returned by eksctl
+func stackEventsError(watcher *amazon.StackEventWatcher, err error) error {
+	failed := watcher.FailedEvents(eksStackFailedEventsCount)
+	if len(failed) == 0 {
+		return err
+	}
+	reasons := []string{}
+	for _, event := range failed {
+		reasons = append(reasons, amazon.FormatStackEvent(event))
+	}
+	return fmt.Errorf("%s\nCloudFormation reported:\n  %s", err, strings.Join(reasons, "\n  "))
+}
+
+// validateManagedFlags returns an error if managed nodegroups cannot be created with the other flags or
+// the installed version of eksctl
+func (o *CreateClusterEKSOptions) validateManagedFlags() error {
+	if o.usesMixedInstances() {
+		return util.InvalidOptionf("managed", "", "managed nodegroups cannot be used with --spot or --instance-types")
+	}
+	if o.Flags.EncryptedVolumes {
+		return util.InvalidOptionf("managed", "", "managed nodegroups cannot be used with --encrypted-volumes")
+	}
+	output, err := o.getCommandOutput("", "eksctl", "version")
+	if err != nil {
+		logger.Warnf("Could not find the eksctl version so assuming it supports managed nodegroups: %s", err)
+		return nil
+	}
+	version, err := amazon.ParseEksctlVersion(output)
+	if err != nil {
+		logger.Warnf("Could not parse the eksctl version %s so assuming it supports managed nodegroups: %s", output, err)
+		return nil
+	}
+	if version.LT(amazon.MinEksctlManagedVersion) {
+		return util.InvalidOptionf("managed", "", "eksctl %s does not support managed nodegroups, please upgrade eksctl to %s or later", version, amazon.MinEksctlManagedVersion)
+	}
+	if o.Flags.SshPublicKey != "" && version.LT(amazon.MinEksctlManagedSSHVersion) {
+		return util.InvalidOptionf("managed", "", "eksctl %s does not support --ssh-public-key with managed nodegroups, please upgrade eksctl to %s or later", version, amazon.MinEksctlManagedSSHVersion)
+	}
+	return nil
+}
+
+// requiresEksctlConfig returns true if the nodes can only be configured via an eksctl config file
+func (o *CreateClusterEKSOptions) requiresEksctlConfig() bool {
+	return o.usesMixedInstances() || o.Flags.EncryptedVolumes || o.Flags.NodeTaints != ""
+}
+
+// usesMixedInstances returns true if the nodes need a mixed instances policy which can only be configured via an eksctl config file
+func (o *CreateClusterEKSOptions) usesMixedInstances() bool {
+	return o.Flags.Spot || o.Flags.InstanceTypes != ""
+}
+
+// validateFargateFlags returns an error if the Fargate flags are combined with flags which configure nodes
+func (o *CreateClusterEKSOptions) validateFargateFlags() error {
+	flags := &o.Flags
+	if flags.FargateNamespaces != "" && !flags.Fargate {
+		return util.InvalidOptionf("fargate-namespaces", flags.FargateNamespaces, "--fargate-namespaces can only be used with --fargate")
+	}
+	if flags.Fargate && o.requiresEksctlConfig() {
+		return util.InvalidOptionf("fargate", "", "--fargate cannot be used with --spot, --instance-types, --encrypted-volumes or --node-taints as Fargate has no nodes")
+	}
+	if flags.Fargate && (flags.NodeLabels != "" || flags.PrivateNetworking) {
+		return util.InvalidOptionf("fargate", "", "--fargate cannot be used with --node-labels or --private-networking as Fargate has no nodes")
+	}
+	if flags.Fargate {
+		nodeFlags := []struct {
+			name string
+			used bool
+		}{
+			{"node-type", o.flagChanged("node-type")},
+			{optionNodes, flags.NodeCount >= 0},
+			{"nodes-min", flags.NodesMin >= 0},
+			{"nodes-max", flags.NodesMax >= 0},
+			{"managed", flags.Managed},
+			{"ssh-public-key", flags.SshPublicKey != ""},
+			{optionNodeVolumeSize, flags.NodeVolumeSize > 0},
+			{optionNodeVolumeType, flags.NodeVolumeType != ""},
+			{"spot-max-price", flags.SpotMaxPrice > 0},
+		}
+		for _, flag := range nodeFlags {
+			if flag.used {
+				return util.InvalidOptionf("fargate", "", "--fargate cannot be used with --%s as Fargate has no nodes", flag.name)
+			}
+		}
+	}
+	return nil
+}
+
+// validateNodeLabelsAndTaints parses the node labels and taints returning an error if any of them are invalid
+func (o *CreateClusterEKSOptions) validateNodeLabelsAndTaints() error {
+	flags := &o.Flags
+	var err error
+	o.nodeLabels, err = amazon.ParseNodeLabels(flags.NodeLabels)
+	if err != nil {
+		return util.InvalidOptionError("node-labels", flags.NodeLabels, err)
+	}
+	o.nodeTaints, err = amazon.ParseNodeTaints(flags.NodeTaints)
+	if err != nil {
+		return util.InvalidOptionError("node-taints", flags.NodeTaints, err)
+	}
+	if len(o.nodeTaints) > 0 && flags.Managed {
+		return util.InvalidOptionf("managed", "", "managed nodegroups cannot be used with --node-taints")
+	}
+	return nil
+}
+
+// fargateNamespaces returns the namespaces to create Fargate profiles for which always includes the namespace
+// Jenkins X is installed into and the namespaces of its default environments so that their pods can be scheduled
+// without any nodes
+func (o *CreateClusterEKSOptions) fargateNamespaces() []string {
+	namespaces := []string{}
+	installNamespace := o.InstallOptions.Flags.Namespace
+	if installNamespace == "" {
+		installNamespace = kube.DefaultNamespace
+	}
+	namespaces = append(namespaces, installNamespace)
+	if !o.InstallOptions.Flags.NoDefaultEnvironments {
+		namespaces = append(namespaces, installNamespace+"-staging", installNamespace+"-production")
+	}
+	for _, ns := range strings.Split(o.Flags.FargateNamespaces, ",") {
+		ns = strings.TrimSpace(ns)
+		if ns != "" && !util.Contains(namespaces, ns) {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
+
+// createFargateProfiles creates a Fargate profile for each of the Fargate namespaces
+func (o *CreateClusterEKSOptions) createFargateProfiles() error {
+	flags := &o.Flags
+	logger.Warn("Builds which need Docker in Docker or privileged containers will not work on Fargate")
+	for _, ns := range o.fargateNamespaces() {
+		args := []string{"create", "fargateprofile", "--cluster", flags.ClusterName, "--region", flags.Region, "--namespace", ns, "--name", "fp-" + ns}
+		if flags.Profile != "" && flags.AssumeRoleArn == "" {
+			args = append(args, "--profile", flags.Profile)
+		}
+		logger.Infof("Creating the Fargate profile for namespace %s", util.ColorInfo(ns))
+		logger.Debugf("Running command: %s", util.ColorInfo("eksctl "+strings.Join(args, " ")))
+		ctx, cancel := o.eksctlContext()
+		err := o.runCommandQuietlyWithContext(ctx, "eksctl", args...)
+		cancel()
+		if err != nil {
+			return errors.Wrapf(err, "failed to create the Fargate profile for namespace %s", ns)
+		}
+	}
+	return nil
+}
+
+// disablePublicEndpoint restricts the API server endpoint of the new cluster to its VPC
+func (o *CreateClusterEKSOptions) disablePublicEndpoint() error {
+	flags := &o.Flags
+	logger.Infof("Disabling the public endpoint of EKS cluster %s so that the API server can only be reached from inside its VPC ...", util.ColorInfo(flags.ClusterName))
+	err := amazon.DisableEKSPublicEndpoint(flags.Profile, flags.Region, flags.ClusterName, flags.AWSOperationTimeout)
+	if err != nil {
+		return errors.Wrapf(err, "failed to disable the public endpoint of EKS cluster %s", flags.ClusterName)
+	}
+	return nil
+}
+
+// checkPrivateEndpointReachable returns an error explaining how to install Jenkins X from inside the VPC if the
+// private endpoint of the cluster cannot be reached, rather than letting the install time out
+func (o *CreateClusterEKSOptions) checkPrivateEndpointReachable() error {
+	flags := &o.Flags
+	cluster, err := amazon.DescribeEKSCluster(flags.Profile, flags.Region, flags.ClusterName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to describe EKS cluster %s", flags.ClusterName)
+	}
+	endpoint := aws.StringValue(cluster.Endpoint)
+	if amazon.EndpointReachable(endpoint, eksEndpointDialTimeout) {
+		logger.Debugf("The private endpoint %s of EKS cluster %s is reachable", endpoint, flags.ClusterName)
+		return nil
+	}
+	vpc := "of the cluster"
+	if cluster.ResourcesVpcConfig != nil && cluster.ResourcesVpcConfig.VpcId != nil {
+		vpc = aws.StringValue(cluster.ResourcesVpcConfig.VpcId)
+	}
+	return fmt.Errorf("EKS cluster %s was created but its private endpoint %s cannot be reached from here.\n"+
+		"To install Jenkins X run the following from a machine inside VPC %s, e.g. an EC2 instance or a host connected via VPN or Direct Connect:\n\n"+
+		"  jx create cluster eks --skip-cluster-create --cluster-name %s --region %s\n",
+		flags.ClusterName, endpoint, vpc, flags.ClusterName, flags.Region)
+}
+
+// validateInstanceTypes returns an error if the node instance types are not offered in the region. If the offered
+// instance types cannot be described only the syntax of the instance types is checked
+func (o *CreateClusterEKSOptions) validateInstanceTypes() error {
+	flags := &o.Flags
+	offered, err := amazon.RegionInstanceTypes(flags.Profile, flags.Region)
+	if err != nil {
+		logger.Debugf("Could not describe the instance types offered in region %s: %s", flags.Region, err)
+	}
+	err = amazon.ValidateOfferedInstanceType(flags.Region, flags.NodeType, offered)
+	if err != nil {
+		return util.InvalidOptionError("node-type", flags.NodeType, err)
+	}
+	for _, instanceType := range strings.Split(flags.InstanceTypes, ",") {
+		instanceType = strings.TrimSpace(instanceType)
+		if instanceType == "" {
+			continue
+		}
+		err = amazon.ValidateOfferedInstanceType(flags.Region, instanceType, offered)
+		if err != nil {
+			return util.InvalidOptionError("instance-types", flags.InstanceTypes, err)
+		}
+	}
+	return nil
+}
+
+// validateNodeVolumeFlags returns an error if the node volume size or type is not supported
+func (o *CreateClusterEKSOptions) validateNodeVolumeFlags() error {
+	flags := &o.Flags
+	if flags.NodeVolumeSize != 0 && flags.NodeVolumeSize < amazon.MinEKSNodeVolumeSize {
+		return util.InvalidOptionf(optionNodeVolumeSize, strconv.Itoa(flags.NodeVolumeSize), "the EKS AMIs need node volumes of at least %dGiB", amazon.MinEKSNodeVolumeSize)
+	}
+	if flags.NodeVolumeType != "" && !util.Contains(amazon.EKSNodeVolumeTypes, flags.NodeVolumeType) {
+		return util.InvalidOption(optionNodeVolumeType, flags.NodeVolumeType, amazon.EKSNodeVolumeTypes)
+	}
+	return nil
+}
+
+// usesGPUInstances returns true if the node type or any of the mixed instance types have GPUs
+func (o *CreateClusterEKSOptions) usesGPUInstances() bool {
+	instanceTypes := append([]string{o.Flags.NodeType}, strings.Split(o.Flags.InstanceTypes, ",")...)
+	for _, instanceType := range instanceTypes {
+		if amazon.IsGPUInstanceType(instanceType) {
+			return true
+		}
+	}
+	return false
+}
+
+// installNvidiaDevicePlugin installs the NVIDIA device plugin so that the GPUs of the nodes can be scheduled
+func (o *CreateClusterEKSOptions) installNvidiaDevicePlugin() error {
+	client, _, err := o.KubeClient()
+	if err != nil {
+		return err
+	}
+	logger.Infof("Installing the NVIDIA device plugin %s so that the GPUs of the nodes can be used", util.ColorInfo(kube.NvidiaDevicePluginImage))
+	err = kube.ApplyNvidiaDevicePlugin(client)
+	if err != nil {
+		return errors.Wrap(err, "failed to install the NVIDIA device plugin, use --skip-gpu-plugin if you install it yourself")
+	}
+	return nil
+}
+
+// validateSpotFlags returns an error if the spot instance flags are inconsistent
+func (o *CreateClusterEKSOptions) validateSpotFlags() error {
+	flags := &o.Flags
+	if flags.SpotMaxPrice != 0 && !flags.Spot {
+		return util.InvalidOptionf("spot-max-price", strconv.FormatFloat(flags.SpotMaxPrice, 'f', -1, 64), "--spot-max-price can only be used with --spot")
+	}
+	if flags.Spot && flags.NodeCount == 1 && (flags.NodesMin < 0 || flags.NodesMax < 0) {
+		return util.InvalidOptionf("spot", "", "a single spot node makes the Jenkins X install unreliable, please specify --nodes-min and --nodes-max or use more nodes")
+	}
+	return nil
+}
+
+// createEksctlClusterConfig creates the eksctl ClusterConfig equivalent of the flags
+func (o *CreateClusterEKSOptions) createEksctlClusterConfig(region string, zones string, kubernetesVersion string) *amazon.EksctlClusterConfig {
+	flags := &o.Flags
+	config := amazon.NewEksctlClusterConfig(flags.ClusterName, region)
+	config.Metadata.Version = kubernetesVersion
+	if tags := o.tags(); len(tags) > 0 {
+		config.Metadata.Tags = tags
+	}
+	if zones != "" {
+		config.AvailabilityZones = amazon.ParseZones(zones)
+	}
+
+	nodeGroup := &amazon.EksctlNodeGroup{
+		Name: "ng-1",
+		IAM: &amazon.EksctlNodeGroupIAM{
+			WithAddonPolicies: amazon.EksctlNodeGroupIAMAddonPolicies{
+				ImageBuilder: true,
+			},
+		},
+	}
+	if flags.NodeCount >= 0 {
+		nodeGroup.DesiredCapacity = &flags.NodeCount
+	}
+	if flags.NodesMin >= 0 {
+		nodeGroup.MinSize = &flags.NodesMin
+	}
+	if flags.NodesMax >= 0 {
+		nodeGroup.MaxSize = &flags.NodesMax
+	}
+	if flags.SshPublicKey != "" {
+		nodeGroup.SSH = &amazon.EksctlNodeGroupSSH{
+			Allow:         true,
+			PublicKeyPath: flags.SshPublicKey,
+		}
+	} else if flags.NoSSHAccess {
+		nodeGroup.SSH = &amazon.EksctlNodeGroupSSH{
+			Allow: false,
+		}
+	}
+
+	if len(o.nodeLabels) > 0 {
+		nodeGroup.Labels = o.nodeLabels
+	}
+	if len(o.nodeTaints) > 0 {
+		nodeGroup.Taints = o.nodeTaints
+	}
+	nodeGroup.PrivateNetworking = flags.PrivateNetworking
+	if flags.NodeVolumeSize > 0 {
+		nodeGroup.VolumeSize = &flags.NodeVolumeSize
+	}
+	nodeGroup.VolumeType = flags.NodeVolumeType
+	if flags.EncryptedVolumes {
+		nodeGroup.VolumeEncrypted = &flags.EncryptedVolumes
+	}
+
+	if o.usesMixedInstances() {
+		instanceTypes := []string{flags.NodeType}
+		if flags.InstanceTypes != "" {
+			instanceTypes = strings.Split(flags.InstanceTypes, ",")
+		}
+		distribution := &amazon.EksctlInstancesDistribution{
+			InstanceTypes: instanceTypes,
+		}
+		if flags.Spot {
+			onDemand := 0
+			distribution.OnDemandBaseCapacity = &onDemand
+			distribution.OnDemandPercentageAboveBaseCapacity = &onDemand
+			if flags.SpotMaxPrice > 0 {
+				distribution.MaxPrice = &flags.SpotMaxPrice
+			}
+		}
+		nodeGroup.InstancesDistribution = distribution
+	} else {
+		nodeGroup.InstanceType = flags.NodeType
+	}
+	if o.usesGPUInstances() {
+		nodeGroup.AMIFamily = amazon.EKSGPUAMIFamily
+	}
+	config.NodeGroups = []*amazon.EksctlNodeGroup{nodeGroup}
+	return config
+}
+
+// validateEksctlConfigFlags returns an error if any flags which are defined by the eksctl config file were also specified
+func (o *CreateClusterEKSOptions) validateEksctlConfigFlags() error {
+	for _, name := range []string{optionClusterName, "region", optionZones, optionKubernetesVersion, "node-type", optionNodes, "nodes-min", "nodes-max", "ssh-public-key", "no-ssh-access", "spot", "instance-types", "spot-max-price", "tags", "managed", optionVpcPublicSubnets, optionVpcPrivateSubnets, optionNodeVolumeSize, optionNodeVolumeType, "encrypted-volumes", "node-labels", "node-taints", "private-networking"} {
+		if o.flagChanged(name) {
+			return util.InvalidOptionf("eksctl-config", o.Flags.EksctlConfig, "--%s cannot be used with --eksctl-config, please specify it in the eksctl config file instead", name)
+		}
+	}
+	return nil
+}
+
+// eksClusterDependencies returns the binaries which need to be installed to create the EKS cluster or, when the
+// cluster creation is skipped, to install onto the existing cluster. The kube config of an existing cluster is
+// written by jx for aws-iam-authenticator so heptio-authenticator-aws is not accepted in its place
+func eksClusterDependencies(skipClusterCreate bool) []string {
+	deps := []string{}
+	if skipClusterCreate {
+		if d := binaryShouldBeInstalled("aws-iam-authenticator"); d != "" {
+			deps = append(deps, d)
+		}
+		return deps
+	}
+	if d := binaryShouldBeInstalled("eksctl"); d != "" {
+		deps = append(deps, d)
+	}
+	if d := awsIamAuthenticatorShouldBeInstalled(); d != "" {
+		deps = append(deps, d)
+	}
+	return deps
 }
@@ -0,0 +1,326 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCreateClusterEKSOptions() *CreateClusterEKSOptions {
+	return &CreateClusterEKSOptions{
+		Flags: CreateClusterEKSFlags{
+			ClusterName: "mycluster",
+			NodeType:    "m5.large",
+			NodeCount:   -1,
+			NodesMin:    -1,
+			NodesMax:    -1,
+			Verbose:     -1,
+		},
+	}
+}
+
+func TestCreateEksctlClusterConfigForSpotInstances(t *testing.T) {
+	t.Parallel()
+	o := newTestCreateClusterEKSOptions()
+	o.Flags.Spot = true
+	o.Flags.InstanceTypes = "m5.large,m4.large"
+	o.Flags.SpotMaxPrice = 0.05
+	o.Flags.NodesMin = 2
+	o.Flags.NodesMax = 5
+
+	config := o.createEksctlClusterConfig("eu-west-1", "eu-west-1a,eu-west-1b", "1.13")
+	assert.Equal(t, "mycluster", config.Metadata.Name)
+	assert.Equal(t, "eu-west-1", config.Metadata.Region)
+	assert.Equal(t, "1.13", config.Metadata.Version)
+	assert.Equal(t, []string{"eu-west-1a", "eu-west-1b"}, config.AvailabilityZones)
+	assert.Equal(t, 1, len(config.NodeGroups))
+
+	nodeGroup := config.NodeGroups[0]
+	assert.Nil(t, nodeGroup.DesiredCapacity)
+	assert.Equal(t, 2, *nodeGroup.MinSize)
+	assert.Equal(t, 5, *nodeGroup.MaxSize)
+	assert.True(t, nodeGroup.IAM.WithAddonPolicies.ImageBuilder)
+	assert.Equal(t, []string{"m5.large", "m4.large"}, nodeGroup.InstancesDistribution.InstanceTypes)
+	assert.Equal(t, 0, *nodeGroup.InstancesDistribution.OnDemandPercentageAboveBaseCapacity)
+	assert.Equal(t, 0.05, *nodeGroup.InstancesDistribution.MaxPrice)
+}
+
+func TestValidateSpotFlags(t *testing.T) {
+	t.Parallel()
+	o := newTestCreateClusterEKSOptions()
+	o.Flags.Spot = true
+	o.Flags.NodeCount = 1
+	assert.NotNil(t, o.validateSpotFlags())
+
+	o.Flags.NodesMin = 1
+	o.Flags.NodesMax = 3
+	assert.Nil(t, o.validateSpotFlags())
+
+	o = newTestCreateClusterEKSOptions()
+	o.Flags.SpotMaxPrice = 0.1
+	assert.NotNil(t, o.validateSpotFlags())
+}
+
+func TestCreateEksctlArgs(t *testing.T) {
+	t.Parallel()
+	o := newTestCreateClusterEKSOptions()
+	o.BatchMode = true
+	o.Flags.Region = "eu-west-1"
+	o.Flags.Zones = "eu-west-1a,eu-west-1b"
+	o.Flags.NodeCount = 3
+
+	args, config, err := o.createEksctlArgs()
+	assert.Nil(t, err)
+	assert.Nil(t, config)
+	assert.Equal(t, []string{"create", "cluster", "--full-ecr-access", "--name", "mycluster", "--region", "eu-west-1",
+		"--zones", "eu-west-1a,eu-west-1b", "--node-type", "m5.large", "--nodes", "3", "--aws-api-timeout", "0s"}, args)
+
+	o.Flags.Spot = true
+	args, config, err = o.createEksctlArgs()
+	assert.Nil(t, err)
+	assert.NotNil(t, config)
+	assert.Equal(t, []string{"--aws-api-timeout", "0s"}, args)
+}
+
+func TestCreateEksctlArgsGeneratesTheClusterName(t *testing.T) {
+	t.Parallel()
+	o := newTestCreateClusterEKSOptions()
+	o.BatchMode = true
+	o.Flags.ClusterName = ""
+	o.Flags.Region = "eu-west-1"
+
+	args, config, err := o.createEksctlArgs()
+	require.NoError(t, err)
+	assert.Nil(t, config)
+	require.NotEmpty(t, o.Flags.ClusterName)
+	assert.Equal(t, []string{"create", "cluster", "--full-ecr-access", "--name", o.Flags.ClusterName}, args[:5])
+}
+
+func TestCreateEksctlArgsRejectsInvalidSubnets(t *testing.T) {
+	t.Parallel()
+	o := newTestCreateClusterEKSOptions()
+	o.BatchMode = true
+	o.Flags.Region = "eu-west-1"
+	o.Flags.Zones = "eu-west-1a,eu-west-1b"
+	o.Flags.VpcPrivateSubnets = "subnet-0123abcd,subnet-4567abcd"
+	_, _, err := o.createEksctlArgs()
+	assert.NotNil(t, err, "zones cannot be used with subnets")
+
+	o.Flags.Zones = ""
+	o.Flags.VpcPublicSubnets = "subnet-0123abcd,subnet-typo"
+	_, _, err = o.createEksctlArgs()
+	assert.NotNil(t, err, "invalid subnet IDs should be rejected")
+}
+
+func TestCreateEksctlArgsForGPUNodes(t *testing.T) {
+	t.Parallel()
+	o := newTestCreateClusterEKSOptions()
+	o.BatchMode = true
+	o.Flags.Region = "eu-west-1"
+	o.Flags.NodeType = "p3.2xlarge"
+
+	args, _, err := o.createEksctlArgs()
+	assert.Nil(t, err)
+	assert.True(t, o.gpuNodes)
+	assert.Contains(t, strings.Join(args, " "), "--node-type p3.2xlarge --node-ami-family AmazonLinux2")
+
+	o.Flags.Spot = true
+	o.Flags.NodeType = "m5.large"
+	o.Flags.InstanceTypes = "m5.large,g4dn.xlarge"
+	_, config, err := o.createEksctlArgs()
+	assert.Nil(t, err)
+	assert.True(t, o.gpuNodes)
+	assert.Equal(t, "AmazonLinux2", config.NodeGroups[0].AMIFamily)
+}
+
+func TestNodeVolumeFlags(t *testing.T) {
+	t.Parallel()
+	o := newTestCreateClusterEKSOptions()
+	o.Flags.NodeVolumeSize = 10
+	assert.NotNil(t, o.validateNodeVolumeFlags(), "volumes smaller than 20GiB should be rejected")
+
+	o.Flags.NodeVolumeSize = 100
+	o.Flags.NodeVolumeType = "gp3"
+	assert.NotNil(t, o.validateNodeVolumeFlags())
+
+	o.Flags.NodeVolumeType = "io1"
+	assert.Nil(t, o.validateNodeVolumeFlags())
+
+	o.BatchMode = true
+	o.Flags.Region = "eu-west-1"
+	args, config, err := o.createEksctlArgs()
+	assert.Nil(t, err)
+	assert.Nil(t, config)
+	assert.Contains(t, strings.Join(args, " "), "--node-volume-size 100 --node-volume-type io1")
+
+	o.Flags.EncryptedVolumes = true
+	_, config, err = o.createEksctlArgs()
+	assert.Nil(t, err)
+	nodeGroup := config.NodeGroups[0]
+	assert.Equal(t, "m5.large", nodeGroup.InstanceType)
+	assert.Nil(t, nodeGroup.InstancesDistribution)
+	assert.Equal(t, 100, *nodeGroup.VolumeSize)
+	assert.Equal(t, "io1", nodeGroup.VolumeType)
+	assert.True(t, *nodeGroup.VolumeEncrypted)
+}
+
+func TestEksctlNodeGroupArgs(t *testing.T) {
+	t.Parallel()
+	args := []string{"create", "cluster", "--full-ecr-access", "--name", "mycluster", "--region", "eu-west-1",
+		"--zones", "eu-west-1a,eu-west-1b", "--node-type", "m5.large", "--nodes", "3"}
+	assert.Equal(t, []string{"create", "nodegroup", "--full-ecr-access", "--cluster", "mycluster", "--region", "eu-west-1",
+		"--node-type", "m5.large", "--nodes", "3"}, eksctlNodeGroupArgs(args))
+
+	args = []string{"create", "cluster", "-f", "cluster.yaml", "--profile", "foo"}
+	assert.Equal(t, []string{"create", "nodegroup", "-f", "cluster.yaml", "--profile", "foo"}, eksctlNodeGroupArgs(args))
+}
+
+func TestAppendCommonEksctlArgsWithAssumedRole(t *testing.T) {
+	t.Parallel()
+	o := newTestCreateClusterEKSOptions()
+	o.Flags.Profile = "ci"
+	assert.Equal(t, []string{"--profile", "ci", "--aws-api-timeout", "0s"}, o.appendCommonEksctlArgs(nil))
+
+	o.Flags.AssumeRoleArn = "arn:aws:iam::123456789012:role/jx"
+	assert.Equal(t, []string{"--aws-api-timeout", "0s"}, o.appendCommonEksctlArgs(nil), "the assumed role credentials should be used rather than the profile")
+}
+
+func TestNoSSHAccess(t *testing.T) {
+	t.Parallel()
+	o := newTestCreateClusterEKSOptions()
+	o.BatchMode = true
+	o.Flags.Region = "eu-west-1"
+	o.Flags.NoSSHAccess = true
+	o.Flags.SshPublicKey = "~/.ssh/id_rsa.pub"
+	assert.NotNil(t, o.resolveSSHAccess(), "--no-ssh-access cannot be used with --ssh-public-key")
+
+	o.Flags.SshPublicKey = ""
+	assert.Nil(t, o.resolveSSHAccess())
+	args, _, err := o.createEksctlArgs()
+	assert.Nil(t, err)
+	assert.Contains(t, args, "--ssh-access=false")
+}
+
+func TestFargate(t *testing.T) {
+	t.Parallel()
+	o := newTestCreateClusterEKSOptions()
+	o.BatchMode = true
+	o.Flags.Region = "eu-west-1"
+	o.Flags.FargateNamespaces = "jx-staging"
+	assert.NotNil(t, o.validateFargateFlags(), "--fargate-namespaces requires --fargate")
+
+	o.Flags.Fargate = true
+	assert.Nil(t, o.validateFargateFlags())
+	args, config, err := o.createEksctlArgs()
+	assert.Nil(t, err)
+	assert.Nil(t, config)
+	assert.Contains(t, args, "--fargate")
+	assert.NotContains(t, args, "--node-type")
+
+	o.InstallOptions.Flags.Namespace = "jx"
+	o.Flags.FargateNamespaces = "jx-staging, jx-preview,jx"
+	assert.Equal(t, []string{"jx", "jx-staging", "jx-production", "jx-preview"}, o.fargateNamespaces())
+
+	o.InstallOptions.Flags.NoDefaultEnvironments = true
+	assert.Equal(t, []string{"jx", "jx-staging", "jx-preview"}, o.fargateNamespaces())
+
+	o.Flags.NodeCount = 3
+	assert.NotNil(t, o.validateFargateFlags(), "--fargate cannot be used with --nodes")
+
+	o.Flags.NodeCount = -1
+	o.Flags.Managed = true
+	assert.NotNil(t, o.validateFargateFlags(), "--fargate cannot be used with --managed")
+
+	o.Flags.Managed = false
+	o.Flags.Spot = true
+	assert.NotNil(t, o.validateFargateFlags(), "--fargate cannot be used with spot nodes")
+}
+
+func TestNodeLabelsAndTaints(t *testing.T) {
+	t.Parallel()
+	o := newTestCreateClusterEKSOptions()
+	o.BatchMode = true
+	o.Flags.Region = "eu-west-1"
+	o.Flags.NodeLabels = "workload=builds"
+	assert.Nil(t, o.validateNodeLabelsAndTaints())
+
+	args, config, err := o.createEksctlArgs()
+	assert.Nil(t, err)
+	assert.Nil(t, config)
+	assert.Contains(t, args, "--node-labels")
+	assert.Contains(t, args, "workload=builds")
+
+	o.Flags.NodeTaints = "dedicated=builds:NoSchedule"
+	assert.Nil(t, o.validateNodeLabelsAndTaints())
+	_, config, err = o.createEksctlArgs()
+	assert.Nil(t, err)
+	assert.NotNil(t, config, "taints can only be set in the eksctl config")
+	assert.Equal(t, map[string]string{"workload": "builds"}, config.NodeGroups[0].Labels)
+	assert.Equal(t, map[string]string{"dedicated": "builds:NoSchedule"}, config.NodeGroups[0].Taints)
+
+	o.Flags.NodeTaints = "dedicated=builds:Never"
+	assert.NotNil(t, o.validateNodeLabelsAndTaints())
+
+	o.Flags.NodeTaints = "dedicated=builds:NoSchedule"
+	o.Flags.Managed = true
+	assert.NotNil(t, o.validateNodeLabelsAndTaints(), "managed nodegroups do not support taints")
+}
+
+func TestPrivateNetworking(t *testing.T) {
+	t.Parallel()
+	o := newTestCreateClusterEKSOptions()
+	o.BatchMode = true
+	o.Flags.Region = "eu-west-1"
+	o.Flags.PrivateNetworking = true
+	args, config, err := o.createEksctlArgs()
+	assert.Nil(t, err)
+	assert.Nil(t, config)
+	assert.Contains(t, args, "--node-private-networking")
+
+	o.Flags.EncryptedVolumes = true
+	_, config, err = o.createEksctlArgs()
+	assert.Nil(t, err)
+	assert.NotNil(t, config)
+	assert.True(t, config.NodeGroups[0].PrivateNetworking)
+
+	o.Flags.EncryptedVolumes = false
+	o.Flags.VpcPublicSubnets = "subnet-0153e560b3129a696"
+	_, _, err = o.createEksctlArgs()
+	assert.NotNil(t, err, "private networking needs private subnets")
+
+	o.Flags.VpcPublicSubnets = ""
+	o.Flags.Fargate = true
+	assert.NotNil(t, o.validateFargateFlags())
+}
+
+func TestEksctlContextUsesTheEksctlTimeout(t *testing.T) {
+	t.Parallel()
+	o := newTestCreateClusterEKSOptions()
+	o.Flags.AWSOperationTimeout = time.Millisecond
+	ctx, cancel := o.eksctlContext()
+	defer cancel()
+	_, hasDeadline := ctx.Deadline()
+	assert.False(t, hasDeadline, "the AWS API timeout should not limit the duration of eksctl")
+
+	o.Flags.EksctlTimeout = time.Hour
+	ctx, cancel = o.eksctlContext()
+	defer cancel()
+	deadline, hasDeadline := ctx.Deadline()
+	assert.True(t, hasDeadline)
+	assert.True(t, time.Until(deadline) > 59*time.Minute)
+}
+
+func TestCommandTimeoutFlag(t *testing.T) {
+	t.Parallel()
+	cmd := NewCmdCreateClusterEKS(nil, nil, nil, nil)
+	require.NoError(t, cmd.ParseFlags([]string{"--command-timeout", "30m", "--aws-api-timeout", "5m"}))
+	timeout, err := cmd.Flags().GetDuration("command-timeout")
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Minute, timeout)
+	eksctlTimeout, err := cmd.Flags().GetDuration("eksctl-timeout")
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, eksctlTimeout)
+}
@@ -12,6 +12,8 @@ import (
 
 	"regexp"
 
+	"strconv"
+
 	"github.com/Pallinder/go-randomdata"
 	"github.com/jenkins-x/jx/pkg/cloud/gke"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
@@ -30,26 +32,33 @@ type CreateClusterGKEOptions struct {
 }
 
 type CreateClusterGKEFlags struct {
-	AutoUpgrade     bool
-	ClusterName     string
-	ClusterIpv4Cidr string
-	ClusterVersion  string
-	DiskSize        string
-	ImageType       string
-	MachineType     string
-	MinNumOfNodes   string
-	MaxNumOfNodes   string
-	Network         string
-	ProjectId       string
-	SkipLogin       bool
-	SubNetwork      string
-	Zone            string
-	Namespace       string
-	Labels          string
+	AutoUpgrade      bool
+	ClusterName      string
+	ClusterIpv4Cidr  string
+	ClusterVersion   string
+	DiskSize         string
+	ImageType        string
+	MachineType      string
+	MinNumOfNodes    string
+	MaxNumOfNodes    string
+	Network          string
+	Preemptible      bool
+	ProjectId        string
+	Region           string
+	ReleaseChannel   string
+	SkipLogin        bool
+	SubNetwork       string
+	Zone             string
+	Namespace        string
+	Labels           string
+	WorkloadIdentity bool
 }
 
 const CLUSTER_LIST_HEADER = "PROJECT_ID"
 
+// gkeRegionalZoneCount the number of zones gcloud creates the nodes of a regional cluster in by default
+const gkeRegionalZoneCount = 3
+
 var (
 	createClusterGKELong = templates.LongDesc(`
 		This command creates a new Kubernetes cluster on GKE, installing required local dependencies and provisions the
@@ -70,6 +79,18 @@ var (
 
 		jx create cluster gke
 
+		# to create a cheaper dev cluster with preemptible nodes which autoscales between 1 and 3 nodes per zone
+		jx create cluster gke --preemptible --min-num-nodes 1 --max-num-nodes 3
+
+		# to create a cluster on the regular release channel rather than pinning the Kubernetes version
+		jx create cluster gke --release-channel regular
+
+		# to create a regional cluster whose control plane and nodes are spread across the zones of the region
+		jx create cluster gke --region europe-west1
+
+		# to push images to GCR as a Google service account via Workload Identity rather than with node scopes or keys
+		jx create cluster gke --workload-identity
+
 `)
 	disallowedLabelCharacters = regexp.MustCompile("[^a-z0-9-]")
 )
@@ -98,17 +119,21 @@ func NewCmdCreateClusterGKE(f Factory, in terminal.FileReader, out terminal.File
 
 	cmd.Flags().StringVarP(&options.Flags.ClusterName, optionClusterName, "n", "", "The name of this cluster, default is a random generated name")
 	cmd.Flags().StringVarP(&options.Flags.ClusterIpv4Cidr, "cluster-ipv4-cidr", "", "", "The IP address range for the pods in this cluster in CIDR notation (e.g. 10.0.0.0/14)")
-	cmd.Flags().StringVarP(&options.Flags.ClusterVersion, optionKubernetesVersion, "v", "", "The Kubernetes version to use for the master and nodes. Defaults to server-specified")
+	cmd.Flags().StringVarP(&options.Flags.ClusterVersion, optionKubernetesVersion, "v", "", "The Kubernetes version to use for the master and nodes. Defaults to server-specified. Cannot be used with --release-channel")
+	cmd.Flags().StringVarP(&options.Flags.ReleaseChannel, "release-channel", "", "", "The release channel which upgrades the cluster automatically, one of: "+strings.Join(gke.ReleaseChannels, ", ")+". Use none to create a cluster which is not on a release channel")
 	cmd.Flags().StringVarP(&options.Flags.DiskSize, "disk-size", "d", "", "Size in GB for node VM boot disks. Defaults to 100GB")
 	cmd.Flags().BoolVarP(&options.Flags.AutoUpgrade, "enable-autoupgrade", "", false, "Sets autoupgrade feature for a cluster's default node-pool(s)")
 	cmd.Flags().StringVarP(&options.Flags.MachineType, "machine-type", "m", "", "The type of machine to use for nodes")
 	cmd.Flags().StringVarP(&options.Flags.MinNumOfNodes, "min-num-nodes", "", "", "The minimum number of nodes to be created in each of the cluster's zones")
 	cmd.Flags().StringVarP(&options.Flags.MaxNumOfNodes, "max-num-nodes", "", "", "The maximum number of nodes to be created in each of the cluster's zones")
+	cmd.Flags().BoolVarP(&options.Flags.Preemptible, "preemptible", "", false, "Use preemptible VMs for the nodes, which are cheaper but can be stopped at any time")
 	cmd.Flags().StringVarP(&options.Flags.Network, "network", "", "", "The Compute Engine Network that the cluster will connect to")
 	cmd.Flags().StringVarP(&options.Flags.ProjectId, "project-id", "p", "", "Google Project ID to create cluster in")
 	cmd.Flags().StringVarP(&options.Flags.SubNetwork, "subnetwork", "", "", "The Google Compute Engine subnetwork to which the cluster is connected")
-	cmd.Flags().StringVarP(&options.Flags.Zone, "zone", "z", "", "The compute zone (e.g. us-central1-a) for the cluster")
+	cmd.Flags().StringVarP(&options.Flags.Zone, "zone", "z", "", "The compute zone (e.g. us-central1-a) for a zonal cluster")
+	cmd.Flags().StringVarP(&options.Flags.Region, "region", "", "", "The compute region (e.g. us-central1) for a regional cluster, which has a control plane and nodes in each of the zones of the region. Cannot be used with --zone")
 	cmd.Flags().BoolVarP(&options.Flags.SkipLogin, "skip-login", "", false, "Skip Google auth if already logged in via gcloud auth")
+	cmd.Flags().BoolVarP(&options.Flags.WorkloadIdentity, "workload-identity", "", false, "Enable Workload Identity and push images to GCR as a Google service account created for the builds rather than using node scopes or service account keys")
 	cmd.Flags().StringVarP(&options.Flags.Labels, "labels", "", "", "The labels to add to the cluster being created such as 'foo=bar,whatnot=123'. Label names must begin with a lowercase character ([a-z]), end with a lowercase alphanumeric ([a-z0-9]) with dashes (-), and lowercase alphanumeric ([a-z0-9]) between.")
 
 	cmd.AddCommand(NewCmdCreateClusterGKETerraform(f, in, out, errOut))
@@ -117,7 +142,12 @@ func NewCmdCreateClusterGKE(f Factory, in terminal.FileReader, out terminal.File
 }
 
 func (o *CreateClusterGKEOptions) Run() error {
-	err := o.installRequirements(GKE)
+	err := o.validateFlags()
+	if err != nil {
+		return err
+	}
+
+	err = o.installRequirements(GKE)
 	if err != nil {
 		return err
 	}
@@ -154,6 +184,14 @@ func (o *CreateClusterGKEOptions) createClusterGKE() error {
 		return err
 	}
 
+	if o.Flags.WorkloadIdentity {
+		// check the bucket the builds are granted access to before spending time creating the cluster
+		_, err = gke.ValidateContainerRegistryBucket(o.workloadIdentityRegistry(), projectId)
+		if err != nil {
+			return err
+		}
+	}
+
 	// lets ensure we've got container and compute enabled
 	glcoudArgs := []string{"services", "enable", "container", "compute"}
 	log.Infof("Let's ensure we have container and compute enabled on your project via: %s\n", util.ColorInfo("gcloud "+strings.Join(glcoudArgs, " ")))
@@ -168,23 +206,9 @@ func (o *CreateClusterGKEOptions) createClusterGKE() error {
 		log.Infof("No cluster name provided so using a generated one: %s\n", o.Flags.ClusterName)
 	}
 
-	zone := o.Flags.Zone
-	if zone == "" {
-		availableZones, err := gke.GetGoogleZones(projectId)
-		if err != nil {
-			return err
-		}
-		prompts := &survey.Select{
-			Message:  "Google Cloud Zone:",
-			Options:  availableZones,
-			PageSize: 10,
-			Help:     "The compute zone (e.g. us-central1-a) for the cluster",
-		}
-
-		err = survey.AskOne(prompts, &zone, nil, surveyOpts)
-		if err != nil {
-			return err
-		}
+	locationFlag, location, err := o.pickLocation(projectId)
+	if err != nil {
+		return err
 	}
 
 	machineType := o.Flags.MachineType
@@ -204,7 +228,9 @@ func (o *CreateClusterGKEOptions) createClusterGKE() error {
 	}
 
 	minNumOfNodes := o.Flags.MinNumOfNodes
-	if minNumOfNodes == "" {
+	if minNumOfNodes == "" && o.BatchMode {
+		minNumOfNodes = "3"
+	} else if minNumOfNodes == "" {
 		prompt := &survey.Input{
 			Message: "Minimum number of Nodes",
 			Default: "3",
@@ -215,7 +241,9 @@ func (o *CreateClusterGKEOptions) createClusterGKE() error {
 	}
 
 	maxNumOfNodes := o.Flags.MaxNumOfNodes
-	if maxNumOfNodes == "" {
+	if maxNumOfNodes == "" && o.BatchMode {
+		maxNumOfNodes = "5"
+	} else if maxNumOfNodes == "" {
 		prompt := &survey.Input{
 			Message: "Maximum number of Nodes",
 			Default: "5",
@@ -225,15 +253,63 @@ func (o *CreateClusterGKEOptions) createClusterGKE() error {
 		survey.AskOne(prompt, &maxNumOfNodes, nil, surveyOpts)
 	}
 
-	// mandatory flags are machine type, num-nodes, zone,
+	err = validateGKENumOfNodes(minNumOfNodes, maxNumOfNodes)
+	if err != nil {
+		return err
+	}
+	if locationFlag == "--region" {
+		min, _ := strconv.Atoi(minNumOfNodes)
+		max, _ := strconv.Atoi(maxNumOfNodes)
+		log.Infof("The number of nodes is per zone so with the default of %d zones regional cluster %s in %s will have %s nodes in total\n",
+			gkeRegionalZoneCount, o.Flags.ClusterName, util.ColorInfo(location), util.ColorInfo(fmt.Sprintf("%d-%d", min*gkeRegionalZoneCount, max*gkeRegionalZoneCount)))
+	}
+
+	preemptible := o.Flags.Preemptible
+	if !preemptible && !o.BatchMode && !(o.Cmd != nil && o.Cmd.Flags().Changed("preemptible")) {
+		prompt := &survey.Confirm{
+			Message: "Use preemptible VMs?",
+			Default: false,
+			Help:    "Preemptible VMs are much cheaper but can be stopped at any time and last at most 24 hours, so are best suited to dev clusters",
+		}
+
+		err = survey.AskOne(prompt, &preemptible, nil, surveyOpts)
+		if err != nil {
+			return err
+		}
+	}
+
+	releaseChannel := o.Flags.ReleaseChannel
+	if releaseChannel == "" && o.Flags.ClusterVersion == "" && !o.BatchMode {
+		prompt := &survey.Select{
+			Message: "Release channel:",
+			Options: gke.ReleaseChannels,
+			Default: gke.ReleaseChannelRegular,
+			Help:    "The cluster is upgraded automatically to the versions of the release channel. Choose none to use the default Kubernetes version of GKE without automatic upgrades",
+		}
+
+		err = survey.AskOne(prompt, &releaseChannel, nil, surveyOpts)
+		if err != nil {
+			return err
+		}
+	}
+
+	// mandatory flags are machine type, num-nodes, zone or region,
 	args := []string{"container", "clusters", "create",
-		o.Flags.ClusterName, "--zone", zone,
+		o.Flags.ClusterName, locationFlag, location,
 		"--num-nodes", minNumOfNodes,
 		"--machine-type", machineType,
 		"--enable-autoscaling",
 		"--min-nodes", minNumOfNodes,
 		"--max-nodes", maxNumOfNodes}
 
+	if preemptible {
+		args = append(args, "--preemptible")
+	}
+
+	if o.Flags.WorkloadIdentity {
+		args = append(args, "--workload-pool="+gke.WorkloadPool(projectId))
+	}
+
 	if o.Flags.DiskSize != "" {
 		args = append(args, "--disk-size", o.Flags.DiskSize)
 	}
@@ -246,6 +322,10 @@ func (o *CreateClusterGKEOptions) createClusterGKE() error {
 		args = append(args, "--cluster-version", o.Flags.ClusterVersion)
 	}
 
+	if releaseChannel != "" && releaseChannel != gke.ReleaseChannelNone {
+		args = append(args, "--release-channel", releaseChannel)
+	}
+
 	if o.Flags.AutoUpgrade {
 		args = append(args, "--enable-autoupgrade", "true")
 	}
@@ -284,6 +364,22 @@ func (o *CreateClusterGKEOptions) createClusterGKE() error {
 		return err
 	}
 
+	masterVersion, err := o.getCommandOutput("", "gcloud", "container", "clusters", "describe", o.Flags.ClusterName, locationFlag, location, "--project", projectId, "--format", "value(currentMasterVersion)")
+	if err != nil {
+		log.Warnf("Could not find the master version of cluster %s: %s\n", o.Flags.ClusterName, err)
+	} else if releaseChannel != "" && releaseChannel != gke.ReleaseChannelNone {
+		log.Infof("Created cluster %s with master version %s on the %s release channel\n", util.ColorInfo(o.Flags.ClusterName), util.ColorInfo(masterVersion), util.ColorInfo(releaseChannel))
+	} else {
+		log.Infof("Created cluster %s with master version %s\n", util.ColorInfo(o.Flags.ClusterName), util.ColorInfo(masterVersion))
+	}
+
+	if o.Flags.WorkloadIdentity {
+		err = o.createWorkloadIdentityServiceAccount(projectId)
+		if err != nil {
+			return err
+		}
+	}
+
 	log.Info("Initialising cluster ...\n")
 	o.InstallOptions.Flags.DefaultEnvironmentPrefix = o.Flags.ClusterName
 	err = o.initAndInstall(GKE)
@@ -291,7 +387,7 @@ func (o *CreateClusterGKEOptions) createClusterGKE() error {
 		return err
 	}
 
-	err = o.RunCommand("gcloud", "container", "clusters", "get-credentials", o.Flags.ClusterName, "--zone", zone, "--project", projectId)
+	err = o.RunCommand("gcloud", "container", "clusters", "get-credentials", o.Flags.ClusterName, locationFlag, location, "--project", projectId)
 	if err != nil {
 		return err
 	}
@@ -321,6 +417,122 @@ func (o *CreateClusterGKEOptions) createClusterGKE() error {
 	return nil
 }
 
+// validateFlags returns an error if the flags are invalid before any gcloud commands are run
+func (o *CreateClusterGKEOptions) validateFlags() error {
+	if o.Flags.Zone != "" && o.Flags.Region != "" {
+		return util.InvalidOptionf("region", o.Flags.Region, "--region cannot be used with --zone, use --region for a regional cluster or --zone for a zonal cluster")
+	}
+	if o.Flags.ReleaseChannel != "" {
+		if !util.Contains(gke.ReleaseChannels, o.Flags.ReleaseChannel) {
+			return util.InvalidOption("release-channel", o.Flags.ReleaseChannel, gke.ReleaseChannels)
+		}
+		if o.Flags.ClusterVersion != "" && o.Flags.ReleaseChannel != gke.ReleaseChannelNone {
+			return util.InvalidOptionf("release-channel", o.Flags.ReleaseChannel, "--release-channel cannot be used with --%s as the release channel picks the version", optionKubernetesVersion)
+		}
+	}
+	return validateGKENumOfNodes(o.Flags.MinNumOfNodes, o.Flags.MaxNumOfNodes)
+}
+
+// pickLocation returns the gcloud location flag and the zone of a zonal cluster or the region of a regional cluster,
+// asking whether the cluster is zonal or regional if neither was specified
+func (o *CreateClusterGKEOptions) pickLocation(projectId string) (string, string, error) {
+	if o.Flags.Region != "" {
+		return "--region", o.Flags.Region, nil
+	}
+	if o.Flags.Zone != "" {
+		return "--zone", o.Flags.Zone, nil
+	}
+	surveyOpts := survey.WithStdio(o.In, o.Out, o.Err)
+	regional := false
+	if !o.BatchMode {
+		locationType := ""
+		prompt := &survey.Select{
+			Message: "Zonal or regional cluster?",
+			Options: []string{"zonal", "regional"},
+			Default: "zonal",
+			Help:    "A zonal cluster has a single control plane and nodes in one zone. A regional cluster has a control plane and nodes in each of the zones of a region so it survives a zone outage",
+		}
+		err := survey.AskOne(prompt, &locationType, nil, surveyOpts)
+		if err != nil {
+			return "", "", err
+		}
+		regional = locationType == "regional"
+	}
+
+	locationFlag := "--zone"
+	message := "Google Cloud Zone:"
+	help := "The compute zone (e.g. us-central1-a) for the cluster"
+	locations, err := gke.GetGoogleZones(projectId)
+	if regional {
+		locationFlag = "--region"
+		message = "Google Cloud Region:"
+		help = "The compute region (e.g. us-central1) for the cluster"
+		locations, err = gke.GetGoogleRegions(projectId)
+	}
+	if err != nil {
+		return "", "", err
+	}
+	location := ""
+	prompt := &survey.Select{
+		Message:  message,
+		Options:  locations,
+		PageSize: 10,
+		Help:     help,
+	}
+	err = survey.AskOne(prompt, &location, nil, surveyOpts)
+	if err != nil {
+		return "", "", err
+	}
+	return locationFlag, location, nil
+}
+
+// createWorkloadIdentityServiceAccount creates the Google service account which the build service account acts as
+// via Workload Identity so that the install annotates the build service account rather than creating a docker config
+func (o *CreateClusterGKEOptions) createWorkloadIdentityServiceAccount(projectId string) error {
+	ns := o.InstallOptions.Flags.Namespace
+	if ns == "" {
+		ns = "jx"
+	}
+	serviceAccount := gke.WorkloadIdentityServiceAccountName(o.Flags.ClusterName)
+	email, err := gke.CreateWorkloadIdentityServiceAccount(serviceAccount, projectId, o.workloadIdentityRegistry(), ns, jxBuilderServiceAccount)
+	if err != nil {
+		return fmt.Errorf("failed to create the Workload Identity service account %s: %s", serviceAccount, err)
+	}
+	o.InstallOptions.GKEWorkloadIdentityServiceAccount = email
+	return nil
+}
+
+// workloadIdentityRegistry returns the GCR host the builds push to as the Workload Identity service account
+func (o *CreateClusterGKEOptions) workloadIdentityRegistry() string {
+	if o.InstallOptions.Flags.DockerRegistry != "" {
+		return o.InstallOptions.Flags.DockerRegistry
+	}
+	return gke.DefaultContainerRegistry
+}
+
+// validateGKENumOfNodes returns an error if the minimum or maximum number of nodes is not a number or the maximum
+// is less than the minimum. Empty values are not validated as they are prompted for
+func validateGKENumOfNodes(minNumOfNodes string, maxNumOfNodes string) error {
+	min, max := -1, -1
+	var err error
+	if minNumOfNodes != "" {
+		min, err = strconv.Atoi(minNumOfNodes)
+		if err != nil || min < 0 {
+			return util.InvalidOptionf("min-num-nodes", minNumOfNodes, "the minimum number of nodes must be a number of 0 or more")
+		}
+	}
+	if maxNumOfNodes != "" {
+		max, err = strconv.Atoi(maxNumOfNodes)
+		if err != nil || max < 1 {
+			return util.InvalidOptionf("max-num-nodes", maxNumOfNodes, "the maximum number of nodes must be a number greater than 0")
+		}
+	}
+	if min >= 0 && max >= 0 && max < min {
+		return util.InvalidOptionf("max-num-nodes", maxNumOfNodes, "the maximum number of nodes cannot be less than the minimum number of nodes %d", min)
+	}
+	return nil
+}
+
 func sanitizeLabel(username string) string {
 	sanitized := strings.ToLower(username)
 	return disallowedLabelCharacters.ReplaceAllString(sanitized, "-")
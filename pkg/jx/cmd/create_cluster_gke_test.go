@@ -24,3 +24,54 @@ func Test_sanitizeLabel(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateGKENumOfNodes(t *testing.T) {
+	t.Parallel()
+	assert.Nil(t, validateGKENumOfNodes("", ""))
+	assert.Nil(t, validateGKENumOfNodes("1", "3"))
+	assert.Nil(t, validateGKENumOfNodes("3", "3"))
+	assert.Nil(t, validateGKENumOfNodes("0", ""))
+	assert.Nil(t, validateGKENumOfNodes("", "5"))
+	assert.NotNil(t, validateGKENumOfNodes("5", "3"), "max < min")
+	assert.NotNil(t, validateGKENumOfNodes("three", "5"))
+	assert.NotNil(t, validateGKENumOfNodes("-1", "5"))
+	assert.NotNil(t, validateGKENumOfNodes("", "0"))
+}
+
+func TestCreateClusterGKEValidateFlags(t *testing.T) {
+	t.Parallel()
+	o := &CreateClusterGKEOptions{}
+	o.Flags.Region = "europe-west1"
+	assert.Nil(t, o.validateFlags())
+
+	flag, location, err := o.pickLocation("my-project")
+	assert.Nil(t, err)
+	assert.Equal(t, "--region", flag)
+	assert.Equal(t, "europe-west1", location)
+
+	o.Flags.Zone = "europe-west1-b"
+	assert.NotNil(t, o.validateFlags(), "--region and --zone are mutually exclusive")
+
+	o.Flags.Region = ""
+	flag, location, err = o.pickLocation("my-project")
+	assert.Nil(t, err)
+	assert.Equal(t, "--zone", flag)
+	assert.Equal(t, "europe-west1-b", location)
+}
+
+func TestCreateClusterGKEReleaseChannel(t *testing.T) {
+	t.Parallel()
+	o := &CreateClusterGKEOptions{}
+	o.Flags.ReleaseChannel = "regular"
+	assert.Nil(t, o.validateFlags())
+
+	o.Flags.ReleaseChannel = "beta"
+	assert.NotNil(t, o.validateFlags())
+
+	o.Flags.ReleaseChannel = "stable"
+	o.Flags.ClusterVersion = "1.13.7-gke.8"
+	assert.NotNil(t, o.validateFlags(), "--release-channel and --kubernetes-version are mutually exclusive")
+
+	o.Flags.ReleaseChannel = "none"
+	assert.Nil(t, o.validateFlags())
+}
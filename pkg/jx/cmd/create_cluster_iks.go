@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Pallinder/go-randomdata"
+	"github.com/jenkins-x/jx/pkg/cloud/iks"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// CreateClusterIKSOptions the flags for running create cluster iks
+type CreateClusterIKSOptions struct {
+	CreateClusterOptions
+
+	Flags CreateClusterIKSFlags
+}
+
+// CreateClusterIKSFlags the flags for creating an IBM Cloud Kubernetes Service cluster
+type CreateClusterIKSFlags struct {
+	ClusterName    string
+	Infrastructure string
+	Zone           string
+	Flavor         string
+	Workers        int
+	VPCID          string
+	SubnetID       string
+	Timeout        string
+}
+
+const (
+	iksWorkerPollInterval = 30 * time.Second
+)
+
+var (
+	createClusterIKSLong = templates.LongDesc(`
+		This command creates a new Kubernetes cluster on IBM Cloud Kubernetes Service (IKS), installing required
+		local dependencies and provisions the Jenkins X platform
+
+		Clusters can be created on the classic infrastructure or on generation 2 VPCs, which need the '--vpc-id'
+		and '--subnet-id' of the VPC. The ibmcloud CLI must be logged in via 'ibmcloud login'.
+
+		The ingress subdomain IBM provides for the cluster is used as the domain of Jenkins X, exposed via the
+		application load balancer of the cluster.
+
+`)
+
+	createClusterIKSExample = templates.Examples(`
+
+		jx create cluster iks
+
+		# create a cluster in a generation 2 VPC
+		jx create cluster iks --infrastructure vpc-gen2 --zone us-south-1 --vpc-id myvpc --subnet-id mysubnet
+
+`)
+)
+
+// NewCmdCreateClusterIKS creates the command
+func NewCmdCreateClusterIKS(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := CreateClusterIKSOptions{
+		CreateClusterOptions: createCreateClusterOptions(f, in, out, errOut, IBM),
+	}
+	cmd := &cobra.Command{
+		Use:     "iks",
+		Short:   "Create a new Kubernetes cluster on IBM Cloud Kubernetes Service",
+		Long:    createClusterIKSLong,
+		Example: createClusterIKSExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addCreateClusterFlags(cmd)
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.Flags.ClusterName, optionClusterName, "n", "", "The name of the cluster. Defaults to a generated name")
+	cmd.Flags().StringVarP(&options.Flags.Infrastructure, "infrastructure", "", iks.InfrastructureClassic, "The infrastructure of the cluster. Valid values: "+strings.Join(iks.Infrastructures, ", "))
+	cmd.Flags().StringVarP(&options.Flags.Zone, "zone", "z", "", fmt.Sprintf("The zone of the cluster. Defaults to %s for classic and %s for VPC clusters", iks.DefaultClassicZone, iks.DefaultVPCZone))
+	cmd.Flags().StringVarP(&options.Flags.Flavor, "flavor", "", "", fmt.Sprintf("The machine flavor of the workers. Defaults to %s for classic and %s for VPC clusters", iks.DefaultClassicFlavor, iks.DefaultVPCFlavor))
+	cmd.Flags().IntVarP(&options.Flags.Workers, "workers", "", 3, "The number of workers")
+	cmd.Flags().StringVarP(&options.Flags.VPCID, "vpc-id", "", "", "The ID of the VPC of a vpc-gen2 cluster")
+	cmd.Flags().StringVarP(&options.Flags.SubnetID, "subnet-id", "", "", "The ID of the VPC subnet of a vpc-gen2 cluster")
+	cmd.Flags().StringVarP(&options.Flags.Timeout, "cluster-timeout", "", "90m", "How long to wait for the workers to be ready")
+	return cmd
+}
+
+// Run implements this command
+func (o *CreateClusterIKSOptions) Run() error {
+	err := o.validateFlags()
+	if err != nil {
+		return err
+	}
+	err = o.installRequirements(IBM, "ibmcloud")
+	if err != nil {
+		log.Errorf("error installing missing dependencies %v, please fix or install manually then try again", err)
+		os.Exit(-1)
+	}
+	err = o.installIBMCloudPlugin(iks.ContainerServicePlugin)
+	if err != nil {
+		return err
+	}
+
+	err = o.createClusterIKS()
+	if err != nil {
+		log.Errorf("error creating cluster %v", err)
+		os.Exit(-1)
+	}
+	return nil
+}
+
+func (o *CreateClusterIKSOptions) validateFlags() error {
+	flags := &o.Flags
+	if util.StringArrayIndex(iks.Infrastructures, flags.Infrastructure) < 0 {
+		return util.InvalidOption("infrastructure", flags.Infrastructure, iks.Infrastructures)
+	}
+	if flags.Workers < 1 {
+		return util.InvalidOptionf("workers", strconv.Itoa(flags.Workers), "a cluster needs at least one worker")
+	}
+	if flags.Infrastructure == iks.InfrastructureVPCGen2 {
+		if flags.VPCID == "" {
+			return util.MissingOption("vpc-id")
+		}
+		if flags.SubnetID == "" {
+			return util.MissingOption("subnet-id")
+		}
+		if flags.Zone == "" {
+			flags.Zone = iks.DefaultVPCZone
+		}
+		if flags.Flavor == "" {
+			flags.Flavor = iks.DefaultVPCFlavor
+		}
+	} else {
+		if flags.VPCID != "" || flags.SubnetID != "" {
+			return util.InvalidOptionf("infrastructure", flags.Infrastructure, "--vpc-id and --subnet-id can only be used with --infrastructure %s", iks.InfrastructureVPCGen2)
+		}
+		if flags.Zone == "" {
+			flags.Zone = iks.DefaultClassicZone
+		}
+		if flags.Flavor == "" {
+			flags.Flavor = iks.DefaultClassicFlavor
+		}
+	}
+	_, err := time.ParseDuration(flags.Timeout)
+	if err != nil {
+		return util.InvalidOptionf("cluster-timeout", flags.Timeout, "it is not a duration such as 90m: %s", err)
+	}
+	return nil
+}
+
+func (o *CreateClusterIKSOptions) createClusterIKS() error {
+	flags := &o.Flags
+	if flags.ClusterName == "" {
+		flags.ClusterName = strings.ToLower(randomdata.SillyName())
+		log.Infof("No cluster name provided so using a generated one: %s\n", flags.ClusterName)
+	}
+
+	args := iks.CreateClusterArgs(flags.Infrastructure, flags.ClusterName, flags.Zone, flags.Flavor, flags.Workers, flags.VPCID, flags.SubnetID)
+	log.Infof("Creating IKS cluster %s - this can take a while so please be patient...\n", util.ColorInfo(flags.ClusterName))
+	log.Infof("Running command: %s\n", util.ColorInfo("ibmcloud "+strings.Join(args, " ")))
+	err := o.runCommandVerbose("ibmcloud", args...)
+	if err != nil {
+		return err
+	}
+
+	err = o.waitForIKSWorkers()
+	if err != nil {
+		return err
+	}
+	log.Infof("IKS cluster %s created\n", util.ColorInfo(flags.ClusterName))
+
+	err = o.RunCommand("ibmcloud", "ks", "cluster", "config", "--cluster", flags.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	if o.InstallOptions.Flags.Domain == "" {
+		domain, err := o.iksIngressSubdomain(flags.ClusterName)
+		if err != nil {
+			return err
+		}
+		o.InstallOptions.Flags.Domain = domain
+	}
+	// the application load balancer of the cluster routes the ingress subdomain so no ingress controller is needed
+	o.InstallOptions.InitOptions.Flags.SkipIngress = true
+
+	log.Info("Initialising cluster ...\n")
+	return o.initAndInstall(IBM)
+}
+
+// waitForIKSWorkers polls the workers of the cluster until they are all ready or the timeout expires
+func (o *CreateClusterIKSOptions) waitForIKSWorkers() error {
+	timeout, err := time.ParseDuration(o.Flags.Timeout)
+	if err != nil {
+		return err
+	}
+	clusterName := o.Flags.ClusterName
+	deadline := time.Now().Add(timeout)
+	for {
+		output, err := o.getCommandOutput("", "ibmcloud", "ks", "worker", "ls", "--cluster", clusterName, "--output", "json")
+		if err != nil {
+			return err
+		}
+		workers, err := iks.ParseWorkers([]byte(output))
+		if err != nil {
+			return err
+		}
+		if iks.WorkersReady(workers) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %s waiting for the workers of IKS cluster %s to be ready", timeout, clusterName)
+		}
+		ready := 0
+		for _, w := range workers {
+			if w.Status == iks.WorkerStatusReady {
+				ready++
+			}
+		}
+		log.Infof("%d of %d workers of IKS cluster %s are ready\n", ready, len(workers), clusterName)
+		time.Sleep(iksWorkerPollInterval)
+	}
+}
+
+// iksIngressSubdomainForInstall returns the ingress subdomain of the IKS cluster of the kube context, installing the
+// ibmcloud CLI and its container service plugin only now as no other step of the install needs them
+func (o *CommonOptions) iksIngressSubdomainForInstall(kubeContext string) (string, error) {
+	err := o.installRequirements(IBM, "ibmcloud")
+	if err != nil {
+		return "", err
+	}
+	err = o.installIBMCloudPlugin(iks.ContainerServicePlugin)
+	if err != nil {
+		return "", err
+	}
+	return o.iksIngressSubdomain(iks.ClusterNameFromContext(kubeContext))
+}
+
+// iksIngressSubdomain returns the ingress subdomain IBM provides for the IKS cluster
+func (o *CommonOptions) iksIngressSubdomain(clusterName string) (string, error) {
+	output, err := o.getCommandOutput("", "ibmcloud", "ks", "cluster", "get", "--cluster", clusterName, "--output", "json")
+	if err != nil {
+		return "", err
+	}
+	domain, err := iks.ParseIngressSubdomain([]byte(output))
+	if err != nil {
+		return "", err
+	}
+	if domain == "" {
+		return "", fmt.Errorf("IKS cluster %s has no ingress subdomain, please specify the domain via --domain", clusterName)
+	}
+	log.Infof("Using the ingress subdomain %s of IKS cluster %s\n", util.ColorInfo(domain), util.ColorInfo(clusterName))
+	return domain, nil
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/cloud/k3d"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// CreateClusterK3sOptions the flags for running create cluster k3s
+type CreateClusterK3sOptions struct {
+	CreateClusterOptions
+
+	Flags CreateClusterK3sFlags
+}
+
+// CreateClusterK3sFlags the flags for creating a k3s cluster with k3d
+type CreateClusterK3sFlags struct {
+	ClusterName string
+	Agents      int
+	Ingress     string
+}
+
+var (
+	createClusterK3sLong = templates.LongDesc(`
+		This command creates a new Kubernetes cluster, installing required local dependencies and provisions the
+		Jenkins X platform
+
+		k3d runs the lightweight k3s Kubernetes distribution in Docker containers, so it only needs Docker rather
+		than a VM and starts in seconds. The HTTP and HTTPS ports of the cluster are published on localhost so the
+		Jenkins X applications are available on ` + k3d.Domain + `
+
+		By default the traefik ingress controller deployed by k3s is used. Use '--ingress nginx' to disable traefik
+		and install the nginx ingress controller instead.
+
+`)
+
+	createClusterK3sExample = templates.Examples(`
+
+		jx create cluster k3s
+
+		# create a k3s cluster with two agent nodes using the nginx ingress controller
+		jx create cluster k3s --agents 2 --ingress nginx
+
+`)
+)
+
+// NewCmdCreateClusterK3s creates the command
+func NewCmdCreateClusterK3s(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := CreateClusterK3sOptions{
+		CreateClusterOptions: createCreateClusterOptions(f, in, out, errOut, K3S),
+	}
+	cmd := &cobra.Command{
+		Use:     "k3s",
+		Short:   "Create a new Kubernetes cluster with k3d: Runs k3s locally in Docker",
+		Long:    createClusterK3sLong,
+		Example: createClusterK3sExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addCreateClusterFlags(cmd)
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.Flags.ClusterName, optionClusterName, "n", k3d.DefaultClusterName, "The name of the k3s cluster")
+	cmd.Flags().IntVarP(&options.Flags.Agents, "agents", "", 0, "The number of agent nodes in addition to the server node")
+	cmd.Flags().StringVarP(&options.Flags.Ingress, "ingress", "", k3d.IngressTraefik, "The ingress controller to use. Valid values: "+strings.Join(k3d.Ingresses, ", "))
+	return cmd
+}
+
+// Run implements this command
+func (o *CreateClusterK3sOptions) Run() error {
+	err := o.validateFlags()
+	if err != nil {
+		return err
+	}
+	err = o.installRequirements(K3S)
+	if err != nil {
+		log.Errorf("error installing missing dependencies %v, please fix or install manually then try again", err)
+		os.Exit(-1)
+	}
+
+	err = o.createClusterK3s()
+	if err != nil {
+		log.Errorf("error creating cluster %v", err)
+		os.Exit(-1)
+	}
+	return nil
+}
+
+func (o *CreateClusterK3sOptions) validateFlags() error {
+	if o.Flags.Agents < 0 {
+		return util.InvalidOptionf("agents", strconv.Itoa(o.Flags.Agents), "the number of agent nodes cannot be negative")
+	}
+	if util.StringArrayIndex(k3d.Ingresses, o.Flags.Ingress) < 0 {
+		return util.InvalidOption("ingress", o.Flags.Ingress, k3d.Ingresses)
+	}
+	return nil
+}
+
+func (o *CreateClusterK3sOptions) createClusterK3s() error {
+	clusterName := o.Flags.ClusterName
+	if clusterName == "" {
+		clusterName = k3d.DefaultClusterName
+	}
+
+	args := k3d.CreateClusterArgs(clusterName, o.Flags.Agents, o.Flags.Ingress)
+	log.Infof("Creating k3s cluster %s...\n", util.ColorInfo(clusterName))
+	log.Infof("Running command: %s\n", util.ColorInfo("k3d "+strings.Join(args, " ")))
+	err := o.runCommandVerbose("k3d", args...)
+	if err != nil {
+		return err
+	}
+	log.Infof("k3s cluster %s created\n", util.ColorInfo(clusterName))
+
+	err = o.RunCommand("kubectl", "config", "use-context", k3d.ContextName(clusterName))
+	if err != nil {
+		return err
+	}
+
+	if o.Flags.Ingress == k3d.IngressTraefik {
+		// k3s deploys traefik itself so there is no ingress controller for jx to install
+		log.Infof("Using the %s ingress controller deployed by k3s\n", util.ColorInfo(k3d.IngressTraefik))
+		initFlags := &o.InstallOptions.InitOptions.Flags
+		initFlags.SkipIngress = true
+		initFlags.IngressNamespace = k3d.TraefikNamespace
+		initFlags.IngressService = k3d.TraefikService
+		initFlags.IngressDeployment = k3d.TraefikService
+	}
+
+	// the load balancer of the cluster is published on localhost
+	if o.InstallOptions.Flags.Domain == "" {
+		o.InstallOptions.Flags.Domain = k3d.Domain
+	}
+
+	log.Info("Initialising cluster ...\n")
+	return o.initAndInstall(K3S)
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/cloud/kind"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// CreateClusterKindOptions the flags for running create cluster kind
+type CreateClusterKindOptions struct {
+	CreateClusterOptions
+
+	Flags CreateClusterKindFlags
+}
+
+// CreateClusterKindFlags the flags for creating a kind cluster
+type CreateClusterKindFlags struct {
+	ClusterName string
+	Workers     int
+	NodeImage   string
+	Wait        string
+}
+
+var (
+	createClusterKindLong = templates.LongDesc(`
+		This command creates a new Kubernetes cluster, installing required local dependencies and provisions the
+		Jenkins X platform
+
+		kind runs a local Kubernetes cluster using Docker containers as the nodes, so it only needs Docker rather
+		than a VM. The HTTP and HTTPS ports of the ingress controller are mapped to localhost so the Jenkins X
+		applications are available on ` + kind.Domain + `
+
+`)
+
+	createClusterKindExample = templates.Examples(`
+
+		jx create cluster kind
+
+		# create a kind cluster with two worker nodes
+		jx create cluster kind --workers 2
+
+`)
+)
+
+// NewCmdCreateClusterKind creates the command
+func NewCmdCreateClusterKind(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := CreateClusterKindOptions{
+		CreateClusterOptions: createCreateClusterOptions(f, in, out, errOut, KIND),
+	}
+	cmd := &cobra.Command{
+		Use:     "kind",
+		Short:   "Create a new Kubernetes cluster with kind: Runs locally in Docker",
+		Long:    createClusterKindLong,
+		Example: createClusterKindExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addCreateClusterFlags(cmd)
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.Flags.ClusterName, optionClusterName, "n", kind.DefaultClusterName, "The name of the kind cluster")
+	cmd.Flags().IntVarP(&options.Flags.Workers, "workers", "", 0, "The number of worker nodes in addition to the control plane node")
+	cmd.Flags().StringVarP(&options.Flags.NodeImage, "image", "", "", "The node image used for the nodes of the cluster, which determines the Kubernetes version")
+	cmd.Flags().StringVarP(&options.Flags.Wait, "wait", "", "5m", "How long to wait for the control plane to be ready")
+	return cmd
+}
+
+// Run implements this command
+func (o *CreateClusterKindOptions) Run() error {
+	if o.Flags.Workers < 0 {
+		return util.InvalidOptionf("workers", strconv.Itoa(o.Flags.Workers), "the number of worker nodes cannot be negative")
+	}
+	err := o.installRequirements(KIND)
+	if err != nil {
+		log.Errorf("error installing missing dependencies %v, please fix or install manually then try again", err)
+		os.Exit(-1)
+	}
+
+	err = o.createClusterKind()
+	if err != nil {
+		log.Errorf("error creating cluster %v", err)
+		os.Exit(-1)
+	}
+	return nil
+}
+
+func (o *CreateClusterKindOptions) createClusterKind() error {
+	clusterName := o.Flags.ClusterName
+	if clusterName == "" {
+		clusterName = kind.DefaultClusterName
+	}
+
+	output, err := o.getCommandOutput("", "kind", "get", "clusters")
+	if err != nil {
+		return err
+	}
+	for _, name := range strings.Fields(output) {
+		if name == clusterName {
+			return util.InvalidOptionf(optionClusterName, clusterName,
+				"a kind cluster called %s already exists, perhaps use `jx install` or delete it via `jx delete cluster kind -n %s`", clusterName, clusterName)
+		}
+	}
+
+	config, err := kind.NewConfig(o.Flags.Workers, o.Flags.NodeImage).String()
+	if err != nil {
+		return err
+	}
+	configFile, err := ioutil.TempFile("", "jx-kind-config-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(configFile.Name())
+	_, err = configFile.WriteString(config)
+	configFile.Close()
+	if err != nil {
+		return errors.Wrapf(err, "writing the kind config to %s", configFile.Name())
+	}
+
+	args := []string{"create", "cluster", "--name", clusterName, "--config", configFile.Name()}
+	if o.Flags.Wait != "" {
+		args = append(args, "--wait", o.Flags.Wait)
+	}
+	log.Infof("Creating kind cluster %s...\n", util.ColorInfo(clusterName))
+	log.Infof("Running command: %s\n", util.ColorInfo("kind "+strings.Join(args, " ")))
+	err = o.runCommandVerbose("kind", args...)
+	if err != nil {
+		return err
+	}
+	log.Infof("kind cluster %s created\n", util.ColorInfo(clusterName))
+
+	err = o.RunCommand("kubectl", "config", "use-context", kind.ContextName(clusterName))
+	if err != nil {
+		return err
+	}
+
+	// the ingress controller is reachable on localhost via the port mappings of the kind config
+	if o.InstallOptions.Flags.Domain == "" {
+		o.InstallOptions.Flags.Domain = kind.Domain
+	}
+
+	log.Info("Initialising cluster ...\n")
+	return o.initAndInstall(KIND)
+}
@@ -12,6 +12,7 @@ import (
 
 	"fmt"
 
+	"github.com/jenkins-x/jx/pkg/cloud/minikube"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
@@ -80,7 +81,7 @@ func NewCmdCreateClusterMinikube(f Factory, in terminal.FileReader, out terminal
 	cmd.Flags().StringVarP(&options.Flags.Memory, "memory", "m", "", fmt.Sprintf("Amount of RAM allocated to the Minikube VM in MB. Defaults to %s MB.", MinikubeDefaultMemory))
 	cmd.Flags().StringVarP(&options.Flags.CPU, "cpu", "c", "", fmt.Sprintf("Number of CPUs allocated to the Minikube VM. Defaults to %s.", MinikubeDefaultCpu))
 	cmd.Flags().StringVarP(&options.Flags.DiskSize, "disk-size", "s", "", fmt.Sprintf("Total amount of storage allocated to the Minikube VM. Defaults to %s", MinikubeDefaultDiskSize))
-	cmd.Flags().StringVarP(&options.Flags.Driver, "vm-driver", "d", "", "VM driver is one of: [hyperkit hyperv kvm kvm2 virtualbox vmwarefusion xhyve]")
+	cmd.Flags().StringVarP(&options.Flags.Driver, "vm-driver", "d", "", "VM driver is one of: [docker hyperkit hyperv kvm kvm2 virtualbox vmwarefusion xhyve none]. Defaults to the best driver available on this machine")
 	cmd.Flags().StringVarP(&options.Flags.HyperVVirtualSwitch, "hyperv-virtual-switch", "v", "", "Additional options for using HyperV with Minikube")
 	cmd.Flags().StringVarP(&options.Flags.ClusterVersion, optionKubernetesVersion, "", "", "Kubernetes version")
 
@@ -114,13 +115,41 @@ func (o *CreateClusterMinikubeOptions) Run() error {
 	return nil
 }
 
-func (o *CreateClusterMinikubeOptions) defaultMacVMDriver() string {
-	_, err := o.getCommandOutput("", "hyperkit", "-v")
+// viableMinikubeDrivers returns the drivers, best first, which the installed Minikube supports and which are available
+// on this machine. If none are available all the candidate drivers are returned so that one can be installed
+func (o *CreateClusterMinikubeOptions) viableMinikubeDrivers() []string {
+	candidates := minikube.CandidateDrivers(runtime.GOOS, runtime.GOARCH)
+	help, err := o.getCommandOutput("", "minikube", "start", "--help")
 	if err != nil {
-		log.Warnf("Could not find hyperkit on your PATH. If you install Docker for Mac then we could use hyperkit.\nSee: https://docs.docker.com/docker-for-mac/install/\n")
-		return "xhyve"
+		log.Warnf("Could not determine the drivers supported by Minikube: %s\n", err)
 	}
-	return "hyperkit"
+	drivers := minikube.ViableDrivers(candidates, minikube.ParseSupportedDrivers(help), o.minikubeDriverAvailable)
+	if len(drivers) == 0 {
+		log.Warnf("Could not find an available Minikube driver, the selected driver will be installed if possible\n")
+		return candidates
+	}
+	return drivers
+}
+
+func (o *CreateClusterMinikubeOptions) minikubeDriverAvailable(driver string) bool {
+	var err error
+	switch driver {
+	case minikube.DriverDocker:
+		// the docker daemon must be running as well as the CLI being installed
+		_, err = o.getCommandOutput("", "docker", "info")
+	case minikube.DriverHyperkit:
+		_, err = o.getCommandOutput("", "hyperkit", "-v")
+		if err != nil {
+			log.Warnf("Could not find hyperkit on your PATH. If you install Docker for Mac then we could use hyperkit.\nSee: https://docs.docker.com/docker-for-mac/install/\n")
+		}
+	case minikube.DriverKVM2:
+		_, err = os.Stat("/dev/kvm")
+	case minikube.DriverVirtualBox:
+		_, err = o.getCommandOutput("", "VBoxManage", "--version")
+	case minikube.DriverXhyve:
+		_, err = o.getCommandOutput("", "xhyve", "-v")
+	}
+	return err == nil
 }
 
 func (o *CreateClusterMinikubeOptions) isExistingMinikubeRunning() bool {
@@ -170,48 +199,18 @@ func (o *CreateClusterMinikubeOptions) createClusterMinikube() error {
 	showPromptIfOptionNotSet(&disksize, prompt, o.In, o.Out, o.Err)
 
 	vmDriverValue := o.Flags.Driver
-
-	defaultDriver := ""
-	if len(vmDriverValue) == 0 {
-		switch runtime.GOOS {
-		case "darwin":
-			defaultDriver = o.defaultMacVMDriver()
-		case "windows":
-			defaultDriver = "hyperv"
-		case "linux":
-			defaultDriver = "kvm"
-		default:
-			defaultDriver = "virtualbox"
+	if vmDriverValue == "" {
+		drivers := o.viableMinikubeDrivers()
+		prompts := &survey.Select{
+			Message: "Select driver:",
+			Options: drivers,
+			Default: drivers[0],
+			Help:    "VM driver, defaults to the best driver available on this machine",
 		}
+		showPromptIfOptionNotSet(&vmDriverValue, prompts, o.In, o.Out, o.Err)
 	}
 
-	// only add drivers that are appropriate for this OS
-	drivers := []string{defaultDriver}
-	if defaultDriver == "kvm" {
-		drivers = append(drivers, "kvm2")
-	}
-	if defaultDriver != "virtualbox" {
-		drivers = append(drivers, "virtualbox")
-	}
-	if runtime.GOOS == "darwin" {
-		if util.StringArrayIndex(drivers, "xhyve") < 0 {
-			drivers = append(drivers, "xhyve")
-		}
-	}
-	if runtime.GOOS == "linux" {
-		drivers = append(drivers, "none")
-	}
-
-	prompts := &survey.Select{
-		Message: "Select driver:",
-		Options: drivers,
-		Default: defaultDriver,
-		Help:    "VM driver, defaults to recommended native virtualisation",
-	}
-
-	showPromptIfOptionNotSet(&vmDriverValue, prompts, o.In, o.Out, o.Err)
-
-	if vmDriverValue != "none" {
+	if vmDriverValue != minikube.DriverNone && vmDriverValue != minikube.DriverDocker {
 		err := o.doInstallMissingDependencies([]string{vmDriverValue})
 		if err != nil {
 			log.Errorf("error installing missing dependencies %v, please fix or install manually then try again", err)
@@ -253,7 +252,10 @@ func (o *CreateClusterMinikubeOptions) createClusterMinikube() error {
 	if err != nil {
 		return err
 	}
-	o.InstallOptions.Flags.Domain = ip + ".nip.io"
+	o.InstallOptions.Flags.Domain = minikube.Domain(ip, vmDriverValue, runtime.GOOS)
+	if minikube.NeedsTunnel(vmDriverValue, runtime.GOOS) {
+		log.Warnf("The Minikube IP is not reachable with the %s driver, please run %s in another terminal to expose the ingress controller on localhost\n", vmDriverValue, util.ColorInfo("minikube tunnel"))
+	}
 
 	log.Info("Initialising cluster ...\n")
 	err = o.initAndInstall(MINIKUBE)
@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Pallinder/go-randomdata"
+	"github.com/jenkins-x/jx/pkg/cloud/openstack"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// CreateClusterOpenStackOptions the flags for running create cluster openstack
+type CreateClusterOpenStackOptions struct {
+	CreateClusterOptions
+
+	Flags CreateClusterOpenStackFlags
+}
+
+// CreateClusterOpenStackFlags the flags for creating an OpenStack Magnum cluster
+type CreateClusterOpenStackFlags struct {
+	ClusterName string
+	Template    string
+	NodeCount   int
+	MasterCount int
+	Keypair     string
+	Ingress     string
+	Timeout     string
+}
+
+const (
+	openStackClusterPollInterval = 30 * time.Second
+)
+
+var (
+	createClusterOpenStackLong = templates.LongDesc(`
+		This command creates a new Kubernetes cluster on OpenStack Magnum, installing required local dependencies
+		and provisions the Jenkins X platform
+
+		The cluster is created from a Magnum cluster template via the openstack CLI, which must be configured for
+		your cloud via the OS_* environment variables or a clouds.yaml file.
+
+		The ingress controller is exposed via an Octavia load balancer by default. On clouds without Octavia use
+		'--ingress nodeport' to expose it on the HTTP and HTTPS ports of the nodes instead.
+
+`)
+
+	createClusterOpenStackExample = templates.Examples(`
+
+		jx create cluster openstack --template k8s-template
+
+		# create a cluster with 3 masters and 5 nodes exposing the ingress controller on the nodes
+		jx create cluster openstack --template k8s-template --master-count 3 --node-count 5 --ingress nodeport
+
+`)
+)
+
+// NewCmdCreateClusterOpenStack creates the command
+func NewCmdCreateClusterOpenStack(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := CreateClusterOpenStackOptions{
+		CreateClusterOptions: createCreateClusterOptions(f, in, out, errOut, OPENSTACK),
+	}
+	cmd := &cobra.Command{
+		Use:     "openstack",
+		Short:   "Create a new Kubernetes cluster on OpenStack Magnum",
+		Long:    createClusterOpenStackLong,
+		Example: createClusterOpenStackExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addCreateClusterFlags(cmd)
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.Flags.ClusterName, optionClusterName, "n", "", "The name of the cluster. Defaults to a generated name")
+	cmd.Flags().StringVarP(&options.Flags.Template, "template", "", "", "The Magnum cluster template to create the cluster from")
+	cmd.Flags().IntVarP(&options.Flags.NodeCount, "node-count", "", 3, "The number of worker nodes")
+	cmd.Flags().IntVarP(&options.Flags.MasterCount, "master-count", "", 1, "The number of master nodes")
+	cmd.Flags().StringVarP(&options.Flags.Keypair, "keypair", "", "", "The name of the SSH keypair of the nodes. Defaults to the keypair of the cluster template")
+	cmd.Flags().StringVarP(&options.Flags.Ingress, "ingress", "", openstack.IngressOctavia, "How the ingress controller is exposed. Valid values: "+strings.Join(openstack.Ingresses, ", "))
+	cmd.Flags().StringVarP(&options.Flags.Timeout, "cluster-timeout", "", "60m", "How long to wait for the cluster to be created")
+	return cmd
+}
+
+// Run implements this command
+func (o *CreateClusterOpenStackOptions) Run() error {
+	err := o.validateFlags()
+	if err != nil {
+		return err
+	}
+	err = o.installRequirements(OPENSTACK)
+	if err != nil {
+		log.Errorf("error installing missing dependencies %v, please fix or install manually then try again", err)
+		os.Exit(-1)
+	}
+
+	err = o.createClusterOpenStack()
+	if err != nil {
+		log.Errorf("error creating cluster %v", err)
+		os.Exit(-1)
+	}
+	return nil
+}
+
+func (o *CreateClusterOpenStackOptions) validateFlags() error {
+	flags := &o.Flags
+	if flags.Template == "" {
+		return util.MissingOption("template")
+	}
+	if flags.NodeCount < 1 {
+		return util.InvalidOptionf("node-count", strconv.Itoa(flags.NodeCount), "a cluster needs at least one node")
+	}
+	if flags.MasterCount < 1 {
+		return util.InvalidOptionf("master-count", strconv.Itoa(flags.MasterCount), "a cluster needs at least one master")
+	}
+	if util.StringArrayIndex(openstack.Ingresses, flags.Ingress) < 0 {
+		return util.InvalidOption("ingress", flags.Ingress, openstack.Ingresses)
+	}
+	_, err := time.ParseDuration(flags.Timeout)
+	if err != nil {
+		return util.InvalidOptionf("cluster-timeout", flags.Timeout, "it is not a duration such as 60m: %s", err)
+	}
+	return nil
+}
+
+func (o *CreateClusterOpenStackOptions) createClusterOpenStack() error {
+	flags := &o.Flags
+	if flags.ClusterName == "" {
+		flags.ClusterName = strings.ToLower(randomdata.SillyName())
+		log.Infof("No cluster name provided so using a generated one: %s\n", flags.ClusterName)
+	}
+
+	args := openstack.CreateClusterArgs(flags.ClusterName, flags.Template, flags.NodeCount, flags.MasterCount, flags.Keypair)
+	log.Infof("Creating OpenStack Magnum cluster %s - this can take a while so please be patient...\n", util.ColorInfo(flags.ClusterName))
+	log.Infof("Running command: %s\n", util.ColorInfo("openstack "+strings.Join(args, " ")))
+	err := o.runCommandVerbose("openstack", args...)
+	if err != nil {
+		return err
+	}
+
+	cluster, err := o.waitForOpenStackCluster()
+	if err != nil {
+		return err
+	}
+	log.Infof("OpenStack Magnum cluster %s created\n", util.ColorInfo(flags.ClusterName))
+
+	err = o.mergeOpenStackKubeConfig()
+	if err != nil {
+		return err
+	}
+
+	if flags.Ingress == openstack.IngressNodePort {
+		if len(cluster.NodeAddresses) == 0 {
+			return fmt.Errorf("the Magnum cluster %s has no node addresses to expose the ingress controller on", flags.ClusterName)
+		}
+		initOpts := &o.InstallOptions.InitOptions
+		initOpts.ingressControllerValues = openstack.IngressControllerValues(flags.Ingress)
+		if initOpts.Flags.ExternalIP == "" {
+			initOpts.Flags.ExternalIP = cluster.NodeAddresses[0]
+		}
+		log.Infof("Exposing the ingress controller on the nodes using the external IP %s\n", util.ColorInfo(initOpts.Flags.ExternalIP))
+	}
+
+	log.Info("Initialising cluster ...\n")
+	return o.initAndInstall(OPENSTACK)
+}
+
+// waitForOpenStackCluster polls the status of the Magnum cluster until it is created, failing if the creation fails
+// or does not complete within the timeout
+func (o *CreateClusterOpenStackOptions) waitForOpenStackCluster() (*openstack.Cluster, error) {
+	timeout, err := time.ParseDuration(o.Flags.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	clusterName := o.Flags.ClusterName
+	deadline := time.Now().Add(timeout)
+	for {
+		output, err := o.getCommandOutput("", "openstack", "coe", "cluster", "show", clusterName, "-f", "json")
+		if err != nil {
+			return nil, err
+		}
+		cluster, err := openstack.ParseCluster([]byte(output))
+		if err != nil {
+			return nil, err
+		}
+		switch cluster.Status {
+		case openstack.StatusCreateComplete:
+			return cluster, nil
+		case openstack.StatusCreateFailed:
+			return nil, fmt.Errorf("failed to create the Magnum cluster %s: %s", clusterName, cluster.StatusReason)
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out after %s waiting for the Magnum cluster %s to be created, its status is %s", timeout, clusterName, cluster.Status)
+		}
+		log.Infof("Magnum cluster %s is %s\n", clusterName, cluster.Status)
+		time.Sleep(openStackClusterPollInterval)
+	}
+}
+
+// mergeOpenStackKubeConfig saves the kube config of the Magnum cluster into the default kube config as the current
+// context
+func (o *CreateClusterOpenStackOptions) mergeOpenStackKubeConfig() error {
+	dir, err := ioutil.TempDir("", "jx-openstack-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	err = o.RunCommand("openstack", "coe", "cluster", "config", o.Flags.ClusterName, "--dir", dir, "--force")
+	if err != nil {
+		return err
+	}
+	contextName := openstack.ContextName(o.Flags.ClusterName)
+	err = kube.MergeConfigFile(filepath.Join(dir, "config"), contextName)
+	if err != nil {
+		return err
+	}
+	log.Infof("Switched to kube context %s\n", util.ColorInfo(contextName))
+	return nil
+}
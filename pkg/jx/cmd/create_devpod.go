@@ -32,6 +32,9 @@ import (
 const (
 	optionLabel      = "label"
 	optionRequestCpu = "request-cpu"
+	optionCPU        = "cpu"
+	optionMemory     = "memory"
+	optionGPU        = "gpu"
 	devPodGoPath     = "/workspace"
 )
 
@@ -49,6 +52,12 @@ var (
 
 		# creates a new Maven DevPod 
 		jx create devpod -l maven
+
+		# creates a new DevPod running a custom image based on the maven pod template
+		jx create devpod --image myregistry/mysdk:1.2.3 -l maven
+
+		# creates a new DevPod with more CPU and memory and a GPU, which become the defaults of your next DevPods
+		jx create devpod -l python --cpu 4 --memory 16Gi --gpu 1
 	`)
 )
 
@@ -64,9 +73,13 @@ type CreateDevPodOptions struct {
 	CreateOptions
 
 	Label          string
+	Image          string
 	Suffix         string
 	WorkingDir     string
 	RequestCpu     string
+	CPU            string
+	Memory         string
+	GPU            int
 	Dir            string
 	Reuse          bool
 	Sync           bool
@@ -121,10 +134,15 @@ func NewCmdCreateDevPod(f Factory, in terminal.FileReader, out terminal.FileWrit
 		},
 	}
 
-	cmd.Flags().StringVarP(&options.Label, optionLabel, "l", "", "The label of the pod template to use")
+	cmd.Flags().StringVarP(&options.Label, optionLabel, "l", "", "The label of the pod template or the name of the DevPod image of the team to use")
+	cmd.Flags().StringVarP(&options.Image, optionImage, "i", "", "The image of the DevPod. The pod template given by --label is used as the base of the DevPod if specified")
 	cmd.Flags().StringVarP(&options.Suffix, "suffix", "s", "", "The suffix to append the pod name")
 	cmd.Flags().StringVarP(&options.WorkingDir, "working-dir", "w", "", "The working directory of the DevPod")
-	cmd.Flags().StringVarP(&options.RequestCpu, optionRequestCpu, "c", "1", "The request CPU of the DevPod")
+	cmd.Flags().StringVarP(&options.RequestCpu, optionRequestCpu, "", "", "The request CPU of the DevPod")
+	cmd.Flags().MarkDeprecated(optionRequestCpu, "use --"+optionCPU+" instead")
+	cmd.Flags().StringVarP(&options.CPU, optionCPU, "c", "", "The CPU request of the DevPod. Defaults to your last --cpu or the pod template")
+	cmd.Flags().StringVarP(&options.Memory, optionMemory, "", "", "The memory request of the DevPod. Defaults to your last --memory or the pod template")
+	cmd.Flags().IntVarP(&options.GPU, optionGPU, "", 0, "The number of NVIDIA GPUs of the DevPod. Defaults to your last --gpu or the pod template")
 	cmd.Flags().BoolVarP(&options.Reuse, "reuse", "", true, "Reuse an existing DevPod if a suitable one exists. The DevPod will be selected based on the label (or current working directory)")
 	cmd.Flags().BoolVarP(&options.Sync, "sync", "", false, "Also synchronise the local file system into the DevPod")
 	cmd.Flags().IntSliceVarP(&options.Ports, "ports", "p", []int{}, "Container ports exposed by the DevPod")
@@ -153,6 +171,26 @@ func (o *CreateDevPodOptions) Run() error {
 		return errors.New("Cannot specify --import-url && --sync")
 	}
 
+	// the explicit resources replace those of the pod template and become the defaults of the user
+	explicitResources := &kube.DevPodPreferences{}
+	if o.flagChanged(optionRequestCpu) {
+		explicitResources.CPU = o.RequestCpu
+	}
+	if o.flagChanged(optionCPU) {
+		explicitResources.CPU = o.CPU
+	}
+	if o.flagChanged(optionMemory) {
+		explicitResources.Memory = o.Memory
+	}
+	if o.flagChanged(optionGPU) {
+		explicitResources.GPU = o.GPU
+	}
+	hasExplicitResources := o.flagChanged(optionRequestCpu) || o.flagChanged(optionCPU) || o.flagChanged(optionMemory) || o.flagChanged(optionGPU)
+	err := validateDevPodResources(explicitResources)
+	if err != nil {
+		return err
+	}
+
 	client, curNs, err := o.KubeClient()
 	if err != nil {
 		return err
@@ -180,21 +218,41 @@ func (o *CreateDevPodOptions) Run() error {
 		return fmt.Errorf("Failed to find ConfigMap %s in namespace %s: %s", kube.ConfigMapJenkinsPodTemplates, ns, err)
 	}
 	podTemplates := cm.Data
-	labels := util.SortedMapKeys(podTemplates)
 
+	// the DevPod images of the team are picked like pod templates and replace pod templates with the same label
+	var devPodImage *v1.DevPodImage
 	label := o.Label
-	if label == "" {
-		label = o.guessDevPodLabel(dir, labels)
-	}
-	if label == "" {
-		label, err = util.PickName(labels, "Pick which kind of DevPod you wish to create: ", o.In, o.Out, o.Err)
+	if o.Image != "" {
+		devPodImage = &v1.DevPodImage{
+			Name:        DevPodImageName(o.Image),
+			Image:       o.Image,
+			PodTemplate: o.Label,
+		}
+		label = devPodImage.Name
+	} else {
+		teamSettings, err := o.TeamSettings()
 		if err != nil {
 			return err
 		}
-	}
-	yml := podTemplates[label]
-	if yml == "" {
-		return util.InvalidOption(optionLabel, label, labels)
+		labels := util.SortedMapKeys(podTemplates)
+		for _, image := range teamSettings.DevPodImages {
+			if podTemplates[image.Name] == "" {
+				labels = append(labels, image.Name)
+			}
+		}
+		if label == "" {
+			label = o.guessDevPodLabel(dir, labels)
+		}
+		if label == "" {
+			label, err = util.PickName(labels, "Pick which kind of DevPod you wish to create: ", o.In, o.Out, o.Err)
+			if err != nil {
+				return err
+			}
+		}
+		devPodImage = teamSettings.DevPodImage(label)
+		if devPodImage == nil && podTemplates[label] == "" {
+			return util.InvalidOption(optionLabel, label, labels)
+		}
 	}
 
 	editEnv, err := o.getOrCreateEditEnvironment()
@@ -202,10 +260,19 @@ func (o *CreateDevPodOptions) Run() error {
 		return err
 	}
 
-	pod := &corev1.Pod{}
-	err = yaml.Unmarshal([]byte(yml), pod)
-	if err != nil {
-		return fmt.Errorf("Failed to parse Pod Template YAML: %s\n%s", err, yml)
+	var pod *corev1.Pod
+	yml := podTemplates[label]
+	if devPodImage != nil {
+		pod, yml, err = CreateDevPodImagePod(podTemplates, devPodImage)
+		if err != nil {
+			return err
+		}
+	} else {
+		pod = &corev1.Pod{}
+		err = yaml.Unmarshal([]byte(yml), pod)
+		if err != nil {
+			return fmt.Errorf("Failed to parse Pod Template YAML: %s\n%s", err, yml)
+		}
 	}
 	if pod.Labels == nil {
 		pod.Labels = map[string]string{}
@@ -338,12 +405,35 @@ func (o *CreateDevPodOptions) Run() error {
 
 	}
 
-	if o.RequestCpu != "" {
-		q, err := resource.ParseQuantity(o.RequestCpu)
+	preferences, err := kube.GetDevPodPreferences(client, ns, userName)
+	if err != nil {
+		return err
+	}
+	err = SetDevPodResources(pod, preferences, false)
+	if err != nil {
+		return err
+	}
+	err = SetDevPodResources(pod, explicitResources, true)
+	if err != nil {
+		return err
+	}
+	if o.flagChanged(optionGPU) && o.GPU == 0 {
+		RemoveDevPodGPU(pod)
+	}
+	if hasExplicitResources {
+		if o.flagChanged(optionRequestCpu) || o.flagChanged(optionCPU) {
+			preferences.CPU = explicitResources.CPU
+		}
+		if o.flagChanged(optionMemory) {
+			preferences.Memory = explicitResources.Memory
+		}
+		if o.flagChanged(optionGPU) {
+			preferences.GPU = explicitResources.GPU
+		}
+		err = kube.SaveDevPodPreferences(client, ns, userName, preferences)
 		if err != nil {
-			return util.InvalidOptionError(optionRequestCpu, o.RequestCpu, err)
+			log.Warnf("Failed to save your DevPod preferences: %s\n", err)
 		}
-		container1.Resources.Requests[corev1.ResourceCPU] = q
 	}
 
 	workingDir := o.WorkingDir
@@ -421,6 +511,7 @@ func (o *CreateDevPodOptions) Run() error {
 	podResources := client.CoreV1().Pods(ns)
 
 	create := true
+	resumed := false
 	if o.Reuse {
 		matchLabels := map[string]string{
 			kube.LabelPodTemplate:    label,
@@ -437,7 +528,7 @@ func (o *CreateDevPodOptions) Run() error {
 		if err != nil {
 			return err
 		}
-		for _, p := range podsList.Items {
+		matches := func(p *corev1.Pod) bool {
 			ann := p.Annotations
 			if ann == nil {
 				ann = map[string]string{}
@@ -447,19 +538,68 @@ func (o *CreateDevPodOptions) Run() error {
 			if !o.Sync {
 				matchDir = ""
 			}
-			if p.DeletionTimestamp == nil && ann[kube.AnnotationLocalDir] == matchDir {
+			if len(p.Spec.Containers) == 0 || p.Spec.Containers[0].Image != container1.Image {
+				return false
+			}
+			// lets not reuse a DevPod with other resources than the ones asked for
+			if hasExplicitResources {
+				resources := p.Spec.Containers[0].Resources
+				if !sameResources(resources.Requests, container1.Resources.Requests) || !sameResources(resources.Limits, container1.Resources.Limits) {
+					return false
+				}
+			}
+			return p.DeletionTimestamp == nil && ann[kube.AnnotationLocalDir] == matchDir
+		}
+		for _, p := range podsList.Items {
+			if matches(&p) {
 				create = false
 				pod = &p
 				name = pod.Name
 				log.Infof("Reusing pod %s - waiting for it to be ready...\n", util.ColorInfo(pod.Name))
+				err = kube.UpdateDevPodLastActivity(client, ns, name, time.Now())
+				if err != nil {
+					log.Warnf("Failed to record the activity of DevPod %s: %s\n", name, err)
+				}
 				break
 			}
 		}
+
+		// lets recreate a DevPod suspended by jx gc devpods
+		if create {
+			suspended, err := kube.GetSuspendedDevPods(client, ns, userName, label)
+			if err != nil {
+				return err
+			}
+			for i := range suspended {
+				cm := &suspended[i]
+				p, err := kube.SuspendedDevPod(cm)
+				if err != nil {
+					log.Warnf("%s\n", err)
+					continue
+				}
+				if matches(p) {
+					log.Infof("Resuming pod %s - waiting for it to be ready...\n", util.ColorInfo(p.Name))
+					pod, err = kube.ResumeDevPod(client, ns, cm, time.Now())
+					if err != nil {
+						return err
+					}
+					create = false
+					resumed = true
+					name = pod.Name
+					err = kube.WaitForPodNameToBeReady(client, ns, name, time.Hour)
+					if err != nil {
+						return err
+					}
+					break
+				}
+			}
+		}
 	}
 
 	theiaServiceName := name + "-theia"
 	if create {
 		log.Infof("Creating a DevPod of label: %s\n", util.ColorInfo(label))
+		kube.SetDevPodLastActivity(pod, time.Now())
 		_, err = podResources.Create(pod)
 		if err != nil {
 			if o.Verbose {
@@ -498,7 +638,16 @@ func (o *CreateDevPodOptions) Run() error {
 					},
 				},
 			}
-			_, err = client.CoreV1().PersistentVolumeClaims(curNs).Create(&pvc)
+			// the workspace of a DevPod collected by jx gc devpods is kept so lets use it again
+			pvcs := client.CoreV1().PersistentVolumeClaims(curNs)
+			existing, err := pvcs.Get(workspaceClaimName, metav1.GetOptions{})
+			if err == nil {
+				log.Infof("Using the existing workspace %s\n", util.ColorInfo(workspaceClaimName))
+				existing.OwnerReferences = pvc.OwnerReferences
+				_, err = pvcs.Update(existing)
+			} else {
+				_, err = pvcs.Create(&pvc)
+			}
 
 			if err != nil {
 				return err
@@ -645,37 +794,20 @@ func (o *CreateDevPodOptions) Run() error {
 	}
 
 	var rshExec []string
-	if create {
+	if create || resumed {
 		//  Let install bash-completion to make life better
-		log.Infof("Installing Bash Completion into DevPod\n")
-		rshExec = append(rshExec, "yum install -q -y bash-completion bash-completion-extra", "mkdir -p ~/.jx", "jx completion bash > ~/.jx/bash", "echo \"source ~/.jx/bash\" >> ~/.bashrc")
+		if devPodImage == nil {
+			log.Infof("Installing Bash Completion into DevPod\n")
+			rshExec = append(rshExec, "yum install -q -y bash-completion bash-completion-extra", "mkdir -p ~/.jx", "jx completion bash > ~/.jx/bash", "echo \"source ~/.jx/bash\" >> ~/.bashrc")
+		}
 
 		// Only add git secrets to the Theia container when sync flag is missing (otherwise Theia container won't exist)
 		if !o.Sync {
 			// Add Git Secrets to Theia container
-			secrets, err := o.LoadPipelineSecrets(kube.ValueKindGit, "")
+			theiaRshExec, err := o.devPodGitCredentialsExec()
 			if err != nil {
 				return err
 			}
-			gitCredentials := o.GitCredentials.CreateGitCredentialsFromSecrets(secrets)
-			theiaRshExec := []string{
-				fmt.Sprintf("echo \"%s\" >> ~/.git-credentials", string(gitCredentials)),
-				"git config --global credential.helper store",
-			}
-
-			// Configure remote username and email for git
-			username, _ := o.Git().Username("")
-			email, _ := o.Git().Email("")
-
-			if username != "" {
-				theiaRshExec = append(theiaRshExec, fmt.Sprintf("git config --global user.name \"%s\"", username))
-			}
-			if email != "" {
-				theiaRshExec = append(theiaRshExec, fmt.Sprintf("git config --global user.email \"%s\"", email))
-			}
-
-			// remove annoying warning
-			theiaRshExec = append(theiaRshExec, " git config --global push.default simple")
 
 			options := &RshOptions{
 				CommonOptions: o.CommonOptions,
@@ -697,7 +829,16 @@ func (o *CreateDevPodOptions) Run() error {
 		// Try to clone the right Git repo into the DevPod
 
 		// First configure git credentials
-		rshExec = append(rshExec, "jx step git credentials", "git config --global credential.helper store")
+		if devPodImage == nil {
+			rshExec = append(rshExec, "jx step git credentials", "git config --global credential.helper store")
+		} else if create || resumed {
+			// custom images may not contain jx so the credentials are written directly
+			gitCredentialsExec, err := o.devPodGitCredentialsExec()
+			if err != nil {
+				return err
+			}
+			rshExec = append(rshExec, gitCredentialsExec...)
+		}
 
 		// We only honor --import if --sync is not specified
 		if o.Import {
@@ -741,6 +882,111 @@ func (o *CreateDevPodOptions) Run() error {
 	return options.Run()
 }
 
+// devPodGitCredentialsExec returns the commands which store the git credentials of the pipelines and configure the
+// git user in a container of the DevPod
+func (o *CreateDevPodOptions) devPodGitCredentialsExec() ([]string, error) {
+	secrets, err := o.LoadPipelineSecrets(kube.ValueKindGit, "")
+	if err != nil {
+		return nil, err
+	}
+	gitCredentials := o.GitCredentials.CreateGitCredentialsFromSecrets(secrets)
+	answer := []string{
+		fmt.Sprintf("echo \"%s\" >> ~/.git-credentials", string(gitCredentials)),
+		"git config --global credential.helper store",
+	}
+
+	// Configure remote username and email for git
+	username, _ := o.Git().Username("")
+	email, _ := o.Git().Email("")
+
+	if username != "" {
+		answer = append(answer, fmt.Sprintf("git config --global user.name \"%s\"", username))
+	}
+	if email != "" {
+		answer = append(answer, fmt.Sprintf("git config --global user.email \"%s\"", email))
+	}
+
+	// remove annoying warning
+	answer = append(answer, " git config --global push.default simple")
+	return answer, nil
+}
+
+// CreateDevPodImagePod creates the pod of a DevPod running a custom image, based on the pod template of the image or
+// on a pod with a single container if the image has none. It returns the YAML of the pod template
+func CreateDevPodImagePod(podTemplates map[string]string, image *v1.DevPodImage) (*corev1.Pod, string, error) {
+	pod := &corev1.Pod{}
+	yml := ""
+	if image.PodTemplate != "" {
+		yml = podTemplates[image.PodTemplate]
+		if yml == "" {
+			return nil, yml, util.InvalidOption(optionLabel, image.PodTemplate, util.SortedMapKeys(podTemplates))
+		}
+		err := yaml.Unmarshal([]byte(yml), pod)
+		if err != nil {
+			return nil, yml, fmt.Errorf("Failed to parse Pod Template YAML: %s\n%s", err, yml)
+		}
+	}
+	if len(pod.Spec.Containers) == 0 {
+		// keep the container running so that we can shell into it
+		pod.Spec.Containers = []corev1.Container{
+			{
+				Name:    "devpod",
+				Command: []string{"/bin/sh", "-c", "cat"},
+				TTY:     true,
+			},
+		}
+	}
+	container := &pod.Spec.Containers[0]
+	container.Image = image.Image
+	if container.Resources.Requests == nil {
+		container.Resources.Requests = corev1.ResourceList{}
+	}
+	if image.RequestCPU != "" {
+		q, err := resource.ParseQuantity(image.RequestCPU)
+		if err != nil {
+			return nil, yml, fmt.Errorf("invalid CPU request %s of DevPod image %s: %s", image.RequestCPU, image.Name, err)
+		}
+		container.Resources.Requests[corev1.ResourceCPU] = q
+	}
+	if image.RequestMemory != "" {
+		q, err := resource.ParseQuantity(image.RequestMemory)
+		if err != nil {
+			return nil, yml, fmt.Errorf("invalid memory request %s of DevPod image %s: %s", image.RequestMemory, image.Name, err)
+		}
+		container.Resources.Requests[corev1.ResourceMemory] = q
+	}
+	for _, name := range util.SortedMapKeys(image.Env) {
+		found := false
+		for i := range container.Env {
+			if container.Env[i].Name == name {
+				container.Env[i] = corev1.EnvVar{Name: name, Value: image.Env[name]}
+				found = true
+				break
+			}
+		}
+		if !found {
+			container.Env = append(container.Env, corev1.EnvVar{Name: name, Value: image.Env[name]})
+		}
+	}
+	return pod, yml, nil
+}
+
+// DevPodImageName returns the name of a DevPod running the image, i.e. the last path element of the image reference
+// without the tag or digest
+func DevPodImageName(image string) string {
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		name = name[:i]
+	}
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, ":"); i >= 0 {
+		name = name[:i]
+	}
+	return kube.ToValidName(name)
+}
+
 func (o *CreateDevPodOptions) getOrCreateEditEnvironment() (*v1.Environment, error) {
 	var env *v1.Environment
 	apisClient, err := o.Factory.CreateApiExtensionsClient()
@@ -880,9 +1126,125 @@ func ownerRef(pod *corev1.Pod) metav1.OwnerReference {
 	controller := true
 	return metav1.OwnerReference{
 		APIVersion: "v1",
-		Kind:       "Service",
+		Kind:       "Pod",
 		Name:       pod.Name,
 		UID:        pod.UID,
 		Controller: &controller,
 	}
 }
+
+// flagChanged returns true if the flag was specified on the command line
+func (o *CreateDevPodOptions) flagChanged(name string) bool {
+	return o.Cmd != nil && o.Cmd.Flags().Changed(name)
+}
+
+// validateDevPodResources validates the resources given on the command line
+func validateDevPodResources(resources *kube.DevPodPreferences) error {
+	if resources.CPU != "" {
+		_, err := resource.ParseQuantity(resources.CPU)
+		if err != nil {
+			return util.InvalidOptionError(optionCPU, resources.CPU, err)
+		}
+	}
+	if resources.Memory != "" {
+		_, err := resource.ParseQuantity(resources.Memory)
+		if err != nil {
+			return util.InvalidOptionError(optionMemory, resources.Memory, err)
+		}
+	}
+	if resources.GPU < 0 {
+		return util.InvalidOptionf(optionGPU, strconv.Itoa(resources.GPU), "the number of GPUs cannot be negative")
+	}
+	return nil
+}
+
+// SetDevPodResources sets the CPU and memory requests and the GPU limit of the first container of the DevPod, raising
+// its limits to the requests. The resources defined by the pod template are only replaced if override is true. GPUs
+// also make the DevPod tolerate the taint of the GPU nodes
+func SetDevPodResources(pod *corev1.Pod, resources *kube.DevPodPreferences, override bool) error {
+	if len(pod.Spec.Containers) == 0 {
+		return nil
+	}
+	container := &pod.Spec.Containers[0]
+	requests := map[corev1.ResourceName]string{
+		corev1.ResourceCPU:    resources.CPU,
+		corev1.ResourceMemory: resources.Memory,
+	}
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		value := requests[name]
+		if value == "" {
+			continue
+		}
+		if _, defined := container.Resources.Requests[name]; defined && !override {
+			continue
+		}
+		q, err := resource.ParseQuantity(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s request %s: %s", name, value, err)
+		}
+		if container.Resources.Requests == nil {
+			container.Resources.Requests = corev1.ResourceList{}
+		}
+		container.Resources.Requests[name] = q
+		if limit, ok := container.Resources.Limits[name]; ok && limit.Cmp(q) < 0 {
+			container.Resources.Limits[name] = q
+		}
+	}
+
+	gpuResource := corev1.ResourceName(kube.NvidiaGPUResource)
+	if resources.GPU <= 0 {
+		return nil
+	}
+	if _, defined := container.Resources.Limits[gpuResource]; defined && !override {
+		return nil
+	}
+	if container.Resources.Limits == nil {
+		container.Resources.Limits = corev1.ResourceList{}
+	}
+	container.Resources.Limits[gpuResource] = *resource.NewQuantity(int64(resources.GPU), resource.DecimalSI)
+	delete(container.Resources.Requests, gpuResource)
+	for _, t := range pod.Spec.Tolerations {
+		if t.Key == kube.NvidiaGPUResource {
+			return nil
+		}
+	}
+	pod.Spec.Tolerations = append(pod.Spec.Tolerations, corev1.Toleration{
+		Key:      kube.NvidiaGPUResource,
+		Operator: corev1.TolerationOpExists,
+		Effect:   corev1.TaintEffectNoSchedule,
+	})
+	return nil
+}
+
+// RemoveDevPodGPU removes the GPU limit of the first container of the DevPod and its toleration of the taint of the
+// GPU nodes, whether they come from the pod template or the saved preferences
+func RemoveDevPodGPU(pod *corev1.Pod) {
+	if len(pod.Spec.Containers) == 0 {
+		return
+	}
+	gpuResource := corev1.ResourceName(kube.NvidiaGPUResource)
+	container := &pod.Spec.Containers[0]
+	delete(container.Resources.Limits, gpuResource)
+	delete(container.Resources.Requests, gpuResource)
+	tolerations := []corev1.Toleration{}
+	for _, t := range pod.Spec.Tolerations {
+		if t.Key != kube.NvidiaGPUResource {
+			tolerations = append(tolerations, t)
+		}
+	}
+	pod.Spec.Tolerations = tolerations
+}
+
+// sameResources returns true if the resource lists contain the same quantities
+func sameResources(a corev1.ResourceList, b corev1.ResourceList) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, q := range a {
+		other, ok := b[name]
+		if !ok || q.Cmp(other) != 0 {
+			return false
+		}
+	}
+	return true
+}
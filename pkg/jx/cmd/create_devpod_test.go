@@ -4,8 +4,13 @@ import (
 	"path"
 	"testing"
 
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 func TestFindDevPodLabel(t *testing.T) {
@@ -26,3 +31,127 @@ func TestFindDevPodLabel(t *testing.T) {
 		}
 	}
 }
+
+func TestDevPodImageName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "mysdk", cmd.DevPodImageName("myregistry.example.com:5000/team/mysdk:1.2.3"))
+	assert.Equal(t, "mysdk", cmd.DevPodImageName("team/mysdk@sha256:abcdef"))
+	assert.Equal(t, "ubuntu", cmd.DevPodImageName("ubuntu"))
+}
+
+func TestCreateDevPodImagePod(t *testing.T) {
+	t.Parallel()
+	podTemplates := map[string]string{
+		"maven": `
+metadata:
+  labels:
+    jenkins.io/kind: build-pod
+spec:
+  containers:
+  - name: maven
+    image: jenkinsxio/builder-maven:0.1.1
+    command: ["/bin/sh", "-c", "cat"]
+    tty: true
+    env:
+    - name: MAVEN_OPTS
+      value: -Xmx512m
+  - name: jnlp
+    image: jenkinsci/jnlp-slave:3.14-1
+`,
+	}
+
+	pod, yml, err := cmd.CreateDevPodImagePod(podTemplates, &v1.DevPodImage{
+		Name:          "sdk",
+		Image:         "myregistry/mysdk:1.2.3",
+		PodTemplate:   "maven",
+		RequestMemory: "2Gi",
+		Env:           map[string]string{"MAVEN_OPTS": "-Xmx1g", "SDK_HOME": "/opt/sdk"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, podTemplates["maven"], yml)
+	require.Len(t, pod.Spec.Containers, 2)
+	container := pod.Spec.Containers[0]
+	assert.Equal(t, "myregistry/mysdk:1.2.3", container.Image)
+	assert.Equal(t, []string{"/bin/sh", "-c", "cat"}, container.Command)
+	assert.Equal(t, "2Gi", container.Resources.Requests.Memory().String())
+	assert.Equal(t, []corev1.EnvVar{{Name: "MAVEN_OPTS", Value: "-Xmx1g"}, {Name: "SDK_HOME", Value: "/opt/sdk"}}, container.Env)
+	assert.Equal(t, "jenkinsci/jnlp-slave:3.14-1", pod.Spec.Containers[1].Image)
+
+	pod, _, err = cmd.CreateDevPodImagePod(podTemplates, &v1.DevPodImage{Name: "ubuntu", Image: "ubuntu:18.04"})
+	require.NoError(t, err)
+	require.Len(t, pod.Spec.Containers, 1)
+	assert.Equal(t, "ubuntu:18.04", pod.Spec.Containers[0].Image)
+	assert.True(t, pod.Spec.Containers[0].TTY)
+	assert.NotNil(t, pod.Spec.Containers[0].Resources.Requests)
+
+	_, _, err = cmd.CreateDevPodImagePod(podTemplates, &v1.DevPodImage{Name: "sdk", Image: "mysdk", PodTemplate: "gradle"})
+	assert.Error(t, err)
+}
+
+func TestSetDevPodResources(t *testing.T) {
+	t.Parallel()
+	newPod := func() *corev1.Pod {
+		return &corev1.Pod{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name: "maven",
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU: resource.MustParse("400m"),
+							},
+							Limits: corev1.ResourceList{
+								corev1.ResourceCPU: resource.MustParse("1"),
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	preferences := &kube.DevPodPreferences{CPU: "2", Memory: "4Gi", GPU: 1}
+
+	pod := newPod()
+	require.NoError(t, cmd.SetDevPodResources(pod, preferences, false))
+	resources := pod.Spec.Containers[0].Resources
+	assert.Equal(t, "400m", resources.Requests.Cpu().String(), "the pod template CPU request should be kept")
+	assert.Equal(t, "4Gi", resources.Requests.Memory().String())
+	assert.Equal(t, "1", resources.Limits.Cpu().String())
+	gpus := resources.Limits[corev1.ResourceName(kube.NvidiaGPUResource)]
+	assert.Equal(t, "1", gpus.String())
+	require.Len(t, pod.Spec.Tolerations, 1)
+	assert.Equal(t, kube.NvidiaGPUResource, pod.Spec.Tolerations[0].Key)
+
+	require.NoError(t, cmd.SetDevPodResources(pod, &kube.DevPodPreferences{CPU: "2", GPU: 2}, true))
+	resources = pod.Spec.Containers[0].Resources
+	assert.Equal(t, "2", resources.Requests.Cpu().String(), "explicit resources replace the pod template")
+	assert.Equal(t, "2", resources.Limits.Cpu().String(), "the limit should be raised to the request")
+	assert.Equal(t, "4Gi", resources.Requests.Memory().String())
+	gpus = resources.Limits[corev1.ResourceName(kube.NvidiaGPUResource)]
+	assert.Equal(t, "2", gpus.String())
+	assert.Len(t, pod.Spec.Tolerations, 1)
+
+	pod = newPod()
+	require.NoError(t, cmd.SetDevPodResources(pod, &kube.DevPodPreferences{}, true))
+	assert.Equal(t, newPod(), pod, "no resources should leave the pod unchanged")
+}
+
+func TestExplicitZeroGPUsRemoveTheSavedGPU(t *testing.T) {
+	t.Parallel()
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "maven"}},
+			Tolerations: []corev1.Toleration{
+				{Key: "dedicated", Operator: corev1.TolerationOpExists},
+			},
+		},
+	}
+	require.NoError(t, cmd.SetDevPodResources(pod, &kube.DevPodPreferences{GPU: 1}, false))
+	require.NoError(t, cmd.SetDevPodResources(pod, &kube.DevPodPreferences{GPU: 0}, true))
+	require.Contains(t, pod.Spec.Containers[0].Resources.Limits, corev1.ResourceName(kube.NvidiaGPUResource))
+
+	cmd.RemoveDevPodGPU(pod)
+	assert.NotContains(t, pod.Spec.Containers[0].Resources.Limits, corev1.ResourceName(kube.NvidiaGPUResource))
+	assert.Equal(t, []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}}, pod.Spec.Tolerations,
+		"only the toleration of the GPU nodes should be removed")
+}
@@ -2,6 +2,10 @@ package cmd
 
 import (
 	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
@@ -17,6 +21,8 @@ import (
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/prow"
 	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
 )
 
 var (
@@ -38,6 +44,12 @@ var (
 
 		# Creates a new Environment passing in the required data on the command line
 		jx create env -n prod -l Production --no-gitops --namespace my-prod
+
+		# Creates a new Environment using an existing Git repository and its 'release' branch as the source
+		jx create env -n prod -l Production --namespace jx-production --git-url https://github.com/acme/gitops-production.git --git-ref release
+
+		# Creates a new Environment running in another cluster using the token in the 'prod-cluster' secret
+		jx create env -n prod -l Production --namespace jx-production --remote-cluster-url https://prod.example.com --remote-cluster-secret prod-cluster
 	`)
 )
 
@@ -55,6 +67,8 @@ type CreateEnvOptions struct {
 	GitRepositoryOptions   gits.GitRepositoryOptions
 	Prefix                 string
 	BranchPattern          string
+	RemoteCluster          v1.EnvironmentCluster
+	Scaffold               bool
 }
 
 // NewCmdCreateEnv creates a command object for the "create" command
@@ -92,12 +106,18 @@ func NewCmdCreateEnv(f Factory, in terminal.FileReader, out terminal.FileWriter,
 	cmd.Flags().StringVarP(&options.Options.Spec.Label, "label", "l", "", "The Environment label which is a descriptive string like 'Production' or 'Staging'")
 	cmd.Flags().StringVarP(&options.Options.Spec.Namespace, kube.OptionNamespace, "s", "", "The Kubernetes namespace for the Environment")
 	cmd.Flags().StringVarP(&options.Options.Spec.Cluster, "cluster", "c", "", "The Kubernetes cluster for the Environment. If blank and a namespace is specified assumes the current cluster")
-	cmd.Flags().StringVarP(&options.Options.Spec.Source.URL, "git-url", "g", "", "The Git clone URL for the source code for GitOps based Environments")
-	cmd.Flags().StringVarP(&options.Options.Spec.Source.Ref, "git-ref", "r", "", "The Git repo reference for the source code for GitOps based Environments")
+	cmd.Flags().StringVarP(&options.Options.Spec.Source.URL, "git-url", "g", "", "The Git clone URL of an existing repository to use as the source code for GitOps based Environments instead of creating a new repository")
+	cmd.Flags().StringVarP(&options.Options.Spec.Source.Ref, "git-ref", "r", "", "The Git branch of the source code for GitOps based Environments which promotion Pull Requests are merged into")
+	cmd.Flags().BoolVarP(&options.Scaffold, "scaffold", "", false, "Adds any missing files of the Environment layout to the existing Git repository of --git-url without asking")
 	cmd.Flags().Int32VarP(&options.Options.Spec.Order, "order", "o", 100, "The order weighting of the Environment so that they can be sorted by this order before name")
+	cmd.Flags().StringVarP(&options.RemoteCluster.URL, "remote-cluster-url", "", "", "The API server URL of the remote cluster the Environment runs in if it is not the current cluster")
+	cmd.Flags().StringVarP(&options.RemoteCluster.CAData, "remote-cluster-ca", "", "", "The base64 encoded PEM certificate authority of the API server of the remote cluster")
+	cmd.Flags().StringVarP(&options.RemoteCluster.SecretName, "remote-cluster-secret", "", "", "The Secret in the team namespace with a '"+kube.SecretDataKubeConfig+"' or a '"+kube.SecretDataToken+"' to connect to the remote cluster with")
+	cmd.Flags().StringVarP(&options.RemoteCluster.Context, "remote-cluster-context", "", "", "The kubeconfig context used to connect to the remote cluster")
 	cmd.Flags().StringVarP(&options.Prefix, "prefix", "", "jx", "Environment repo prefix, your Git repo will be of the form 'environment-$prefix-$envName'")
 
-	cmd.Flags().StringVarP(&options.PromotionStrategy, "promotion", "p", "", "The promotion strategy")
+	cmd.Flags().StringVarP(&options.PromotionStrategy, "promotion", "p", "", "The promotion strategy. Possible values: "+strings.Join(v1.PromotionStrategyTypeValues, ", "))
+	cmd.Flags().StringVarP(&options.PromotionStrategy, "promotion-strategy", "", "", "The promotion strategy. Possible values: "+strings.Join(v1.PromotionStrategyTypeValues, ", "))
 	cmd.Flags().StringVarP(&options.ForkEnvironmentGitRepo, "fork-git-repo", "f", kube.DefaultEnvironmentGitRepoURL, "The Git repository used as the fork when creating new Environment Git repos")
 	cmd.Flags().StringVarP(&options.EnvJobCredentials, "env-job-credentials", "", "", "The Jenkins credentials used by the GitOps Job for this environment")
 	cmd.Flags().StringVarP(&options.BranchPattern, "branches", "", "", "The branch pattern for branches to trigger CI/CD pipelines on the environment Git repository")
@@ -164,12 +184,38 @@ func (o *CreateEnvOptions) Run() error {
 	}
 
 	env := v1.Environment{}
-	o.Options.Spec.PromotionStrategy = v1.PromotionStrategyType(o.PromotionStrategy)
+	o.Options.Spec.PromotionStrategy, err = v1.ParsePromotionStrategyType(o.PromotionStrategy)
+	if err != nil {
+		return err
+	}
+	err = o.defaultGitProtocol(&o.GitRepositoryOptions)
+	if err != nil {
+		return err
+	}
 	gitProvider, err := kube.CreateEnvironmentSurvey(o.BatchMode, authConfigSvc, devEnv, &env, &o.Options, o.ForkEnvironmentGitRepo, ns,
 		jxClient, kubeClient, envDir, &o.GitRepositoryOptions, o.HelmValuesConfig, o.Prefix, o.Git(), o.In, o.Out, o.Err)
 	if err != nil {
 		return err
 	}
+	if o.Options.Spec.Source.URL != "" {
+		err = o.validateEnvironmentRepository(&env)
+		if err != nil {
+			return err
+		}
+	}
+	var remoteClient kubernetes.Interface
+	if o.RemoteCluster != (v1.EnvironmentCluster{}) {
+		env.Spec.RemoteCluster = &o.RemoteCluster
+		remoteClient, err = o.EnvironmentKubeClient(&env)
+		if err != nil {
+			return err
+		}
+		err = kube.ValidateRemoteCluster(remoteClient)
+		if err != nil {
+			return errors.Wrapf(err, "failed to validate the remote cluster of environment %s", env.Name)
+		}
+		log.Infof("Connected to the remote cluster of environment %s\n", util.ColorInfo(env.Name))
+	}
 	_, err = jxClient.JenkinsV1().Environments(ns).Create(&env)
 	if err != nil {
 		return err
@@ -181,14 +227,28 @@ func (o *CreateEnvOptions) Run() error {
 	if err != nil {
 		return err
 	}
+	if remoteClient != nil && env.Spec.Namespace != "" {
+		labels := map[string]string{
+			kube.LabelTeam:        ns,
+			kube.LabelEnvironment: env.Name,
+		}
+		err = kube.AddIstioInjectionLabel(remoteClient, jxClient, &env, ns, labels)
+		if err != nil {
+			return err
+		}
+		err = kube.EnsureNamespaceCreated(remoteClient, env.Spec.Namespace, labels, map[string]string{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to create the namespace %s in the remote cluster", env.Spec.Namespace)
+		}
+	}
 	gitURL := env.Spec.Source.URL
 	gitInfo, err := gits.ParseGitURL(gitURL)
 	if err != nil {
 		return err
 	}
 	if o.Prow {
-		repo := fmt.Sprintf("%s/environment-%s-%s", gitInfo.Organisation, o.Prefix, o.Options.Name)
-		err = prow.AddEnvironment(o.KubeClientCached, []string{repo}, devEnv.Spec.Namespace, env.Spec.Namespace)
+		repo := fmt.Sprintf("%s/%s", gitInfo.Organisation, gitInfo.Name)
+		err = prow.AddEnvironment(o.KubeClientCached, []string{repo}, devEnv.Spec.Namespace, env.Spec.Namespace, devEnv.Spec.TeamSettings.GitSSHKeySecret)
 		if err != nil {
 			return fmt.Errorf("failed to add repo %s to Prow config in namespace %s: %v", repo, env.Spec.Namespace, err)
 		}
@@ -237,8 +297,104 @@ func (o *CreateEnvOptions) Run() error {
 			// register the webhook
 			return o.createWebhookProw(gitURL, gitProvider)
 		}
-		return o.ImportProject(gitURL, envDir, jenkins.DefaultJenkinsfile, o.BranchPattern, o.EnvJobCredentials, false, gitProvider, authConfigSvc, true, o.BatchMode)
+		branchPattern, err := o.environmentBranchPattern(&env, gitProvider)
+		if err != nil {
+			return err
+		}
+		return o.ImportProject(gitURL, envDir, jenkins.DefaultJenkinsfile, branchPattern, o.EnvJobCredentials, false, gitProvider, authConfigSvc, true, o.BatchMode)
+	}
+
+	return nil
+}
+
+// validateEnvironmentRepository checks that the existing Git repository of the environment has the layout of an
+// environment, adding any missing files from the environment template repository if the user agrees
+func (o *CreateEnvOptions) validateEnvironmentRepository(env *v1.Environment) error {
+	gitURL := env.Spec.Source.URL
+	dir, _, base, err := o.cloneEnvironmentRepository(env, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to clone the Git repository %s", gitURL)
+	}
+	missing, err := kube.MissingEnvironmentRepositoryFiles(dir)
+	if err != nil {
+		return err
+	}
+	if len(missing) == 0 {
+		log.Infof("Using the existing Git repository %s on branch %s\n", util.ColorInfo(gitURL), util.ColorInfo(base))
+		return nil
+	}
+	missingText := strings.Join(missing, ", ")
+	if !o.Scaffold {
+		if o.BatchMode {
+			return fmt.Errorf("the Git repository %s is missing %s of the environment layout. Use --scaffold to add them", gitURL, missingText)
+		}
+		message := fmt.Sprintf("The Git repository %s is missing %s. Would you like to add them?", gitURL, missingText)
+		if !util.Confirm(message, true, "The files are copied from the environment template repository and pushed to the branch "+base, o.In, o.Out, o.Err) {
+			return fmt.Errorf("the Git repository %s is missing %s of the environment layout", gitURL, missingText)
+		}
 	}
 
+	templateDir, err := ioutil.TempDir("", "jx-environment-template-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(templateDir)
+	err = o.Git().Clone(o.ForkEnvironmentGitRepo, templateDir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to clone the environment template repository %s", o.ForkEnvironmentGitRepo)
+	}
+	err = kube.ScaffoldEnvironmentRepository(dir, templateDir, missing)
+	if err != nil {
+		return err
+	}
+	err = o.Git().Add(dir, "*")
+	if err != nil {
+		return err
+	}
+	err = o.Git().CommitDir(dir, "Add the environment layout")
+	if err != nil {
+		return err
+	}
+	if util.StringArrayIndex(missing, kube.EnvironmentPipelineFiles[0]) >= 0 {
+		err = kube.ModifyNamespace(o.Out, dir, env, o.Git())
+		if err != nil {
+			return err
+		}
+	}
+	err = o.Git().Push(dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to push the environment layout to %s", gitURL)
+	}
+	log.Infof("Added %s to the Git repository %s on branch %s\n", util.ColorInfo(missingText), util.ColorInfo(gitURL), util.ColorInfo(base))
 	return nil
 }
+
+// environmentBranchPattern returns the branch pattern of the pipeline of the environment which must build the branch of
+// its source if it is not master
+func (o *CreateEnvOptions) environmentBranchPattern(env *v1.Environment, gitProvider gits.GitProvider) (string, error) {
+	ref := env.Spec.Source.Ref
+	if o.BranchPattern != "" || ref == "" || ref == "master" {
+		return o.BranchPattern, nil
+	}
+	patterns, err := o.TeamBranchPatterns()
+	if err != nil {
+		return "", err
+	}
+	branchPattern := patterns.DefaultBranchPattern
+	if branchPattern == "" {
+		branchPattern = jenkins.BranchPattern(gitProvider.Kind())
+	}
+	return addBranchToPattern(branchPattern, ref), nil
+}
+
+// addBranchToPattern returns the branch pattern extended to match the branch if it does not already
+func addBranchToPattern(pattern string, branch string) string {
+	if pattern == "" {
+		return regexp.QuoteMeta(branch)
+	}
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err == nil && re.MatchString(branch) {
+		return pattern
+	}
+	return pattern + "|" + regexp.QuoteMeta(branch)
+}
@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddBranchToPattern(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "master|PR-.*|feature.*", addBranchToPattern("master|PR-.*|feature.*", "feature-release"))
+	assert.Equal(t, "master|PR-.*|feature.*|release", addBranchToPattern("master|PR-.*|feature.*", "release"))
+	assert.Equal(t, "master|release/1\\.0", addBranchToPattern("master", "release/1.0"))
+	assert.Equal(t, "release", addBranchToPattern("", "release"))
+}
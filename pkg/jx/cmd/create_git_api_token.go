@@ -30,6 +30,12 @@ const (
 var (
 	create_git_token_long = templates.LongDesc(`
 		Creates a new API Token for a user on a Git Server
+
+		On GitHub servers with an OAuth App configured via --oauth-client-id or $` + gits.GitHubOAuthClientIDEnvVar + `
+		the token is created with the OAuth device flow: jx displays a code and a URL at which the user authorizes jx in
+		their browser. Otherwise or with --paste the user is asked to create a token and paste it.
+
+		The scopes of GitHub tokens are validated before the token is stored.
 `)
 
 	create_git_token_example = templates.Examples(`
@@ -41,6 +47,9 @@ var (
  		# using browser automation to login to the Git server
 		# with the username and password to find the API Token
 		jx create git token -n local -p somePassword someUserName	
+
+		# Login to GitHub in the browser via the OAuth device flow of an OAuth App
+		jx create git token --oauth-client-id 0123456789abcdef0123
 	`)
 )
 
@@ -48,11 +57,13 @@ var (
 type CreateGitTokenOptions struct {
 	CreateOptions
 
-	ServerFlags ServerFlags
-	Username    string
-	Password    string
-	ApiToken    string
-	Timeout     string
+	ServerFlags   ServerFlags
+	Username      string
+	Password      string
+	ApiToken      string
+	Timeout       string
+	Paste         bool
+	OAuthClientID string
 }
 
 // NewCmdCreateGitToken creates a command
@@ -86,6 +97,8 @@ func NewCmdCreateGitToken(f Factory, in terminal.FileReader, out terminal.FileWr
 	cmd.Flags().StringVarP(&options.ApiToken, "api-token", "t", "", "The API Token for the user")
 	cmd.Flags().StringVarP(&options.Password, "password", "p", "", "The User password to try automatically create a new API Token")
 	cmd.Flags().StringVarP(&options.Timeout, "timeout", "", "", "The timeout if using browser automation to generate the API token (by passing username and password)")
+	cmd.Flags().BoolVarP(&options.Paste, "paste", "", false, "Asks for an API token to paste instead of using the OAuth device flow")
+	cmd.Flags().StringVarP(&options.OAuthClientID, "oauth-client-id", "", "", "The client ID of the GitHub OAuth App with the device flow enabled which is used to login, it is saved for the server")
 
 	return cmd
 }
@@ -114,7 +127,24 @@ func (o *CreateGitTokenOptions) Run() error {
 		return err
 	}
 
-	// TODO add the API thingy...
+	if o.OAuthClientID != "" {
+		server.OAuthClientID = o.OAuthClientID
+	}
+	if o.ApiToken == "" && o.Password == "" && !o.Paste {
+		deviceAuth := &auth.UserAuth{}
+		loggedIn, err := gits.LoginWithGitHubDeviceFlow(server, deviceAuth, o.BatchMode, o.Out)
+		if err != nil {
+			return err
+		}
+		if loggedIn {
+			if o.Username != "" && o.Username != deviceAuth.Username {
+				return fmt.Errorf("the browser was logged into %s as %s rather than %s", server.Label(), deviceAuth.Username, o.Username)
+			}
+			o.Username = deviceAuth.Username
+			o.ApiToken = deviceAuth.ApiToken
+		}
+	}
+
 	if o.Username == "" {
 		return fmt.Errorf("No Username specified")
 	}
@@ -149,6 +179,13 @@ func (o *CreateGitTokenOptions) Run() error {
 		}
 	}
 
+	if gits.IsGitHubServer(server) {
+		_, err = gits.ValidateGitHubToken(server, userAuth.ApiToken)
+		if err != nil {
+			return err
+		}
+	}
+
 	config.CurrentServer = server.URL
 	err = authConfigSvc.SaveConfig()
 	if err != nil {
@@ -23,6 +23,9 @@ var (
 		# Add a new Git server with a name
 		jx create git server bitbucket http://bitbucket.org -n MyBitBucket 
 
+		# Add a GitHub Enterprise server which serves its API from a separate host
+		jx create git server github https://git.corp.example.com --git-api-url https://api.git.corp.example.com
+
 		For more documentation see: [https://jenkins-x.io/developing/git/](https://jenkins-x.io/developing/git/)
 
 	`)
@@ -36,7 +39,8 @@ var (
 type CreateGitServerOptions struct {
 	CreateOptions
 
-	Name string
+	Name   string
+	APIURL string
 }
 
 // NewCmdCreateGitServer creates a command object for the "create" command
@@ -68,6 +72,7 @@ func NewCmdCreateGitServer(f Factory, in terminal.FileReader, out terminal.FileW
 	}
 
 	cmd.Flags().StringVarP(&options.Name, "name", "n", "", "The name for the Git server being created")
+	cmd.Flags().StringVarP(&options.APIURL, "git-api-url", "", "", "The URL of the API of the Git server if it is not derived from the server URL, e.g. for GitHub Enterprise servers which serve their API from a separate host")
 	return cmd
 }
 
@@ -105,7 +110,10 @@ func (o *CreateGitServerOptions) Run() error {
 		return err
 	}
 	config := authConfigSvc.Config()
-	config.GetOrCreateServerName(gitUrl, name, kind)
+	server := config.GetOrCreateServerName(gitUrl, name, kind)
+	if o.APIURL != "" {
+		server.APIURL = o.APIURL
+	}
 	config.CurrentServer = gitUrl
 	err = authConfigSvc.SaveConfig()
 	if err != nil {
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+const (
+	defaultGitSigningUserName = "jenkins-x-bot"
+	defaultGitSigningEmail    = "jenkins-x@googlegroups.com"
+)
+
+var (
+	createGpgKeyLong = templates.LongDesc(`
+		Generates the key the pipelines sign their git commits and tags with, stores it in a Secret and configures the
+		team to use it.
+
+		The key is either a GPG key or an SSH key which needs git 2.34 or later. The public key is printed so that it
+		can be added to the account of the pipeline bot on the git provider which then shows the commits as verified.
+		The pipelines sign their commits and tags after running 'jx step git credentials --sign'.
+`)
+
+	createGpgKeyExample = templates.Examples(`
+		# Create a GPG signing key for the pipeline bot
+		jx create gpg key --email bot@example.com
+
+		# Create an SSH signing key instead
+		jx create gpg key --format ssh --email bot@example.com
+	`)
+)
+
+// CreateGpgKeyOptions the options for the create gpg key command
+type CreateGpgKeyOptions struct {
+	CreateOptions
+
+	SecretName string
+	Format     string
+	UserName   string
+	Email      string
+}
+
+// NewCmdCreateGpgKey creates a command object for the "create gpg key" command
+func NewCmdCreateGpgKey(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &CreateGpgKeyOptions{
+		CreateOptions: CreateOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "gpg key",
+		Short:   "Creates the key the pipelines sign their git commits and tags with",
+		Long:    createGpgKeyLong,
+		Example: createGpgKeyExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.SecretName, "name", "n", kube.SecretGitSigningKey, "The name of the Secret the key is stored in")
+	cmd.Flags().StringVarP(&options.Format, "format", "f", kube.GitSigningFormatGPG, fmt.Sprintf("The format of the key, one of: %s", strings.Join(kube.GitSigningFormats, ", ")))
+	cmd.Flags().StringVarP(&options.UserName, "user-name", "u", defaultGitSigningUserName, "The name of the git user of the pipelines")
+	cmd.Flags().StringVarP(&options.Email, "email", "e", "", "The email address of the git user of the pipelines which must be a verified email address of the bot account")
+	options.addCommonFlags(cmd)
+	return cmd
+}
+
+// Run implements the command
+func (o *CreateGpgKeyOptions) Run() error {
+	if util.StringArrayIndex(kube.GitSigningFormats, o.Format) < 0 {
+		return util.InvalidOption("format", o.Format, kube.GitSigningFormats)
+	}
+	if o.Email == "" {
+		if o.BatchMode {
+			o.Email = defaultGitSigningEmail
+		} else {
+			email, err := util.PickValue("Email address of the pipeline git user:", defaultGitSigningEmail, true, o.In, o.Out, o.Err)
+			if err != nil {
+				return err
+			}
+			o.Email = email
+		}
+	}
+
+	tmpDir, err := ioutil.TempDir("", "jx-git-signing-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	var key *kube.GitSigningKey
+	if o.Format == kube.GitSigningFormatSSH {
+		key, err = o.generateSSHSigningKey(tmpDir)
+	} else {
+		key, err = o.generateGPGSigningKey(tmpDir)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to generate the %s signing key", o.Format)
+	}
+
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	_, err = kube.CreateOrUpdateGitSigningKeySecret(kubeClient, ns, o.SecretName, key)
+	if err != nil {
+		return errors.Wrapf(err, "failed to store the signing key in Secret %s in namespace %s", o.SecretName, ns)
+	}
+	err = o.ModifyDevEnvironment(func(env *v1.Environment) error {
+		env.Spec.TeamSettings.GitSigningKeySecret = o.SecretName
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to save the signing key Secret in the team settings")
+	}
+	log.Infof("Stored the %s signing key of %s in Secret %s\n", o.Format, util.ColorInfo(o.Email), util.ColorInfo(o.SecretName))
+
+	if o.Format == kube.GitSigningFormatSSH {
+		log.Infof("\nPlease add this public key as an SSH signing key of the pipeline bot account, e.g. at https://github.com/settings/keys\n\n")
+	} else {
+		log.Infof("\nPlease add this public key %s as a GPG key of the pipeline bot account, e.g. at https://github.com/settings/keys\n\n", util.ColorInfo(key.KeyID))
+	}
+	fmt.Fprintln(o.Out, strings.TrimSpace(string(key.PublicKey)))
+	log.Infof("\nThe commits are only shown as verified if %s is a verified email address of the bot account\n", util.ColorInfo(o.Email))
+	return nil
+}
+
+// generateGPGSigningKey generates a GPG signing key without a passphrase in a keyring in the given directory
+func (o *CreateGpgKeyOptions) generateGPGSigningKey(dir string) (*kube.GitSigningKey, error) {
+	homeDir := filepath.Join(dir, "gnupg")
+	err := os.MkdirAll(homeDir, 0700)
+	if err != nil {
+		return nil, err
+	}
+	gpg := func(args ...string) (string, error) {
+		return o.getCommandOutput("", "gpg", append([]string{"--homedir", homeDir, "--batch"}, args...)...)
+	}
+	userID := fmt.Sprintf("%s <%s>", o.UserName, o.Email)
+	_, err = gpg("--pinentry-mode", "loopback", "--passphrase", "", "--quick-generate-key", userID, "rsa4096", "sign", "never")
+	if err != nil {
+		return nil, err
+	}
+	output, err := gpg("--with-colons", "--list-secret-keys", o.Email)
+	if err != nil {
+		return nil, err
+	}
+	keyID := GPGFingerprint(output)
+	if keyID == "" {
+		return nil, fmt.Errorf("no fingerprint found for the generated GPG key of %s", userID)
+	}
+	privateKeyFile := filepath.Join(dir, "private-key.asc")
+	_, err = gpg("--pinentry-mode", "loopback", "--passphrase", "", "--armor", "--output", privateKeyFile, "--export-secret-keys", keyID)
+	if err != nil {
+		return nil, err
+	}
+	publicKeyFile := filepath.Join(dir, "public-key.asc")
+	_, err = gpg("--armor", "--output", publicKeyFile, "--export", keyID)
+	if err != nil {
+		return nil, err
+	}
+	return loadGitSigningKey(kube.GitSigningFormatGPG, keyID, privateKeyFile, publicKeyFile)
+}
+
+// generateSSHSigningKey generates an ed25519 SSH signing key without a passphrase in the given directory
+func (o *CreateGpgKeyOptions) generateSSHSigningKey(dir string) (*kube.GitSigningKey, error) {
+	privateKeyFile := filepath.Join(dir, "id_ed25519")
+	_, err := o.getCommandOutput("", "ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", o.Email, "-f", privateKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	return loadGitSigningKey(kube.GitSigningFormatSSH, "", privateKeyFile, privateKeyFile+".pub")
+}
+
+func loadGitSigningKey(format string, keyID string, privateKeyFile string, publicKeyFile string) (*kube.GitSigningKey, error) {
+	privateKey, err := ioutil.ReadFile(privateKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := ioutil.ReadFile(publicKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	return &kube.GitSigningKey{
+		Format:     format,
+		KeyID:      keyID,
+		PrivateKey: privateKey,
+		PublicKey:  publicKey,
+	}, nil
+}
+
+// GPGFingerprint returns the fingerprint of the first key of the output of gpg --with-colons --list-keys
+func GPGFingerprint(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(strings.TrimSpace(line), ":")
+		if len(fields) > 9 && fields[0] == "fpr" {
+			return fields[9]
+		}
+	}
+	return ""
+}
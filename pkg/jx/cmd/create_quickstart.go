@@ -8,16 +8,14 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
-	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx/pkg/gits"
-	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/quickstarts"
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
 
-	"github.com/jenkins-x/jx/pkg/auth"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/util"
 )
@@ -47,6 +45,12 @@ var (
 		jx create quickstart
 
 		jx create quickstart -f http
+
+		# Create a new project from a Go quickstart using the http framework
+		jx create quickstart --language go --framework http
+
+		# Create a new project from the quickstarts of the team and of another organisation
+		jx create quickstart --quickstart-location myorg --quickstart-location https://gitlab.com/mygroup/quickstart-*
 	`)
 )
 
@@ -55,6 +59,8 @@ type CreateQuickstartOptions struct {
 	CreateProjectOptions
 
 	GitHubOrganisations []string
+	QuickstartLocations []string
+	CacheTTL            time.Duration
 	Filter              quickstarts.QuickstartFilter
 	GitProvider         gits.GitProvider
 	GitHost             string
@@ -91,76 +97,19 @@ func NewCmdCreateQuickstart(f Factory, in terminal.FileReader, out terminal.File
 	}
 	options.addCreateAppFlags(cmd)
 
-	cmd.Flags().StringArrayVarP(&options.GitHubOrganisations, "organisations", "g", []string{}, "The GitHub organisations to query for quickstarts")
-	cmd.Flags().StringArrayVarP(&options.Filter.Tags, "tag", "t", []string{}, "The tags on the quickstarts to filter")
-	cmd.Flags().StringVarP(&options.Filter.Owner, "owner", "", "", "The owner to filter on")
-	cmd.Flags().StringVarP(&options.Filter.Language, "language", "l", "", "The language to filter on")
-	cmd.Flags().StringVarP(&options.Filter.Framework, "framework", "", "", "The framework to filter on")
+	addQuickstartLocationFlags(cmd, &options.GitHubOrganisations, &options.QuickstartLocations, &options.CacheTTL)
+	addQuickstartFilterFlags(cmd, &options.Filter)
 	cmd.Flags().StringVarP(&options.GitHost, "git-host", "", "", "The Git server host if not using GitHub when pushing created project")
-	cmd.Flags().StringVarP(&options.Filter.Text, "filter", "f", "", "The text filter")
 	cmd.Flags().StringVarP(&options.Filter.ProjectName, "project-name", "p", "", "The project name (for use with -b batch mode)")
 	return cmd
 }
 
 // Run implements the generic Create command
 func (o *CreateQuickstartOptions) Run() error {
-	authConfigSvc, err := o.CreateGitAuthConfigService()
+	model, err := o.loadQuickstarts(o.IgnoreTeam, o.GitHubOrganisations, o.QuickstartLocations, o.CacheTTL)
 	if err != nil {
 		return err
 	}
-	config := authConfigSvc.Config()
-
-	var locations []v1.QuickStartLocation
-	if !o.IgnoreTeam {
-		jxClient, ns, err := o.JXClientAndDevNamespace()
-		if err != nil {
-			return err
-		}
-		err = o.registerEnvironmentCRD()
-		if err != nil {
-			return err
-		}
-
-		locations, err = kube.GetQuickstartLocations(jxClient, ns)
-		if err != nil {
-			return err
-		}
-	}
-
-	// lets add any extra github organisations if they are not already configured
-	for _, org := range o.GitHubOrganisations {
-		found := false
-		for _, loc := range locations {
-			if loc.GitURL == gits.GitHubURL && loc.Owner == org {
-				found = true
-				break
-			}
-		}
-		if !found {
-			locations = append(locations, v1.QuickStartLocation{
-				GitURL:   gits.GitHubURL,
-				GitKind:  gits.KindGitHub,
-				Owner:    org,
-				Includes: []string{"*"},
-				Excludes: []string{"WIP-*"},
-			})
-		}
-	}
-
-	gitMap := map[string]map[string]v1.QuickStartLocation{}
-	for _, loc := range locations {
-		m := gitMap[loc.GitURL]
-		if m == nil {
-			m = map[string]v1.QuickStartLocation{}
-			gitMap[loc.GitURL] = m
-		}
-		m[loc.Owner] = loc
-	}
-
-	model, err := o.LoadQuickstartsFromMap(config, gitMap)
-	if err != nil {
-		return fmt.Errorf("failed to load quickstarts: %s", err)
-	}
 	q, err := model.CreateSurvey(&o.Filter, o.BatchMode, o.In, o.Out, o.Err)
 	if err != nil {
 		return err
@@ -225,15 +174,37 @@ func (o *CreateQuickstartOptions) Run() error {
 func (o *CreateQuickstartOptions) createQuickstart(f *quickstarts.QuickstartForm, dir string) (string, error) {
 	q := f.Quickstart
 	answer := filepath.Join(dir, f.Name)
+	tmpDir, err := o.downloadQuickstart(q, dir)
+	if err != nil {
+		if q.GitURL == "" {
+			return answer, err
+		}
+		log.Warnf("Could not download the archive of quickstart %s so cloning it instead: %s\n", q.ID, err)
+		tmpDir, err = o.cloneQuickstart(q)
+		if err != nil {
+			return answer, err
+		}
+	}
+	err = util.RenameDir(tmpDir, answer, false)
+	if err != nil {
+		return answer, fmt.Errorf("failed to rename temp dir %s to %s: %s", tmpDir, answer, err)
+	}
+	log.Infof("Generated quickstart at %s\n", answer)
+	return answer, nil
+}
+
+// downloadQuickstart downloads and unzips the archive of the quickstart using the credentials of its Git provider
+// returning the directory containing the source
+func (o *CreateQuickstartOptions) downloadQuickstart(q *quickstarts.Quickstart, dir string) (string, error) {
 	u := q.DownloadZipURL
 	if u == "" {
-		return answer, fmt.Errorf("quickstart %s does not have a download zip URL", q.ID)
+		return "", fmt.Errorf("quickstart %s does not have a download zip URL", q.ID)
 	}
 	client := http.Client{}
 
 	req, err := http.NewRequest(http.MethodGet, u, strings.NewReader(""))
 	if err != nil {
-		return answer, err
+		return "", err
 	}
 	userAuth := q.GitProvider.UserAuth()
 	token := userAuth.ApiToken
@@ -244,40 +215,60 @@ func (o *CreateQuickstartOptions) createQuickstart(f *quickstarts.QuickstartForm
 	}
 	res, err := client.Do(req)
 	if err != nil {
-		return answer, err
+		return "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download %s: %s", u, res.Status)
 	}
 	body, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return answer, err
+		return "", err
 	}
 
 	zipFile := filepath.Join(dir, "source.zip")
 	err = ioutil.WriteFile(zipFile, body, util.DefaultWritePermissions)
 	if err != nil {
-		return answer, fmt.Errorf("failed to download file %s due to %s", zipFile, err)
+		return "", fmt.Errorf("failed to download file %s due to %s", zipFile, err)
 	}
+	defer os.Remove(zipFile)
 	tmpDir, err := ioutil.TempDir("", "jx-source-")
 	if err != nil {
-		return answer, fmt.Errorf("failed to create temporary directory: %s", err)
+		return "", fmt.Errorf("failed to create temporary directory: %s", err)
 	}
 	err = util.Unzip(zipFile, tmpDir)
 	if err != nil {
-		return answer, fmt.Errorf("failed to unzip new project file %s due to %s", zipFile, err)
+		return "", fmt.Errorf("failed to unzip new project file %s due to %s", zipFile, err)
 	}
-	err = os.Remove(zipFile)
+	tmpDir, err = findFirstDirectory(tmpDir)
 	if err != nil {
-		return answer, err
+		return "", fmt.Errorf("failed to find a directory inside the source download: %s", err)
 	}
-	tmpDir, err = findFirstDirectory(tmpDir)
+	return tmpDir, nil
+}
+
+// cloneQuickstart clones the quickstart using the credentials of its Git provider for Git servers which do not provide
+// archives of private repositories, returning the directory containing the source without the .git directory
+func (o *CreateQuickstartOptions) cloneQuickstart(q *quickstarts.Quickstart) (string, error) {
+	userAuth := q.GitProvider.UserAuth()
+	cloneURL, err := o.Git().CreatePushURL(q.GitURL, &userAuth)
 	if err != nil {
-		return answer, fmt.Errorf("failed to find a directory inside the source download: %s", err)
+		return "", err
 	}
-	err = util.RenameDir(tmpDir, answer, false)
+	tmpDir, err := ioutil.TempDir("", "jx-source-")
 	if err != nil {
-		return answer, fmt.Errorf("failed to rename temp dir %s to %s: %s", tmpDir, answer, err)
+		return "", fmt.Errorf("failed to create temporary directory: %s", err)
 	}
-	log.Infof("Generated quickstart at %s\n", answer)
-	return answer, nil
+	err = o.Git().Clone(cloneURL, tmpDir)
+	if err != nil {
+		// the error would include the credentials in the clone URL
+		return "", fmt.Errorf("failed to clone quickstart %s from %s", q.ID, q.GitURL)
+	}
+	err = os.RemoveAll(filepath.Join(tmpDir, ".git"))
+	if err != nil {
+		return "", err
+	}
+	return tmpDir, nil
 }
 
 func findFirstDirectory(dir string) (string, error) {
@@ -292,27 +283,3 @@ func findFirstDirectory(dir string) (string, error) {
 	}
 	return "", fmt.Errorf("no child directory found in %s", dir)
 }
-
-// LoadQuickstartsFromMap Load all quickstarts
-func (o *CreateQuickstartOptions) LoadQuickstartsFromMap(config *auth.AuthConfig, gitMap map[string]map[string]v1.QuickStartLocation) (*quickstarts.QuickstartModel, error) {
-	model := quickstarts.NewQuickstartModel()
-
-	for gitURL, m := range gitMap {
-		for _, location := range m {
-			kind := location.GitKind
-			if kind == "" {
-				kind = gits.KindGitHub
-			}
-			gitProvider, err := o.gitProviderForGitServerURL(gitURL, kind)
-			if err != nil {
-				return model, err
-			}
-			o.Debugf("Searching for repositories in Git server %s owner %s includes %s excludes %s as user %s \n", gitProvider.ServerURL(), location.Owner, strings.Join(location.Includes, ", "), strings.Join(location.Excludes, ", "), gitProvider.CurrentUsername())
-			err = model.LoadGithubQuickstarts(gitProvider, location.Owner, location.Includes, location.Excludes)
-			if err != nil {
-				o.Debugf("Quickstart load error: %s\n", err.Error())
-			}
-		}
-	}
-	return model, nil
-}
@@ -56,6 +56,7 @@ func NewCmdDelete(f Factory, in terminal.FileReader, out terminal.FileWriter, er
 	cmd.AddCommand(NewCmdDeleteApp(f, in, out, errOut))
 	cmd.AddCommand(NewCmdDeleteBranch(f, in, out, errOut))
 	cmd.AddCommand(NewCmdDeleteChat(f, in, out, errOut))
+	cmd.AddCommand(NewCmdDeleteCluster(f, in, out, errOut))
 	cmd.AddCommand(NewCmdDeleteContext(f, in, out, errOut))
 	cmd.AddCommand(NewCmdDeleteDevPod(f, in, out, errOut))
 	cmd.AddCommand(NewCmdDeleteEnv(f, in, out, errOut))
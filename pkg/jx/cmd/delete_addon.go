@@ -46,6 +46,7 @@ func NewCmdDeleteAddon(f Factory, in terminal.FileReader, out terminal.FileWrite
 
 	cmd.AddCommand(NewCmdDeleteAddonCloudBees(f, in, out, errOut))
 	cmd.AddCommand(NewCmdDeleteAddonGitea(f, in, out, errOut))
+	cmd.AddCommand(NewCmdDeleteAddonPrometheus(f, in, out, errOut))
 	cmd.AddCommand(NewCmdDeleteAddonSSO(f, in, out, errOut))
 	options.addFlags(cmd)
 	return cmd
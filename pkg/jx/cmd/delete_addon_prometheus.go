@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	deleteAddonPrometheusLong = templates.LongDesc(`
+		Deletes the Prometheus addon together with its Grafana dashboards and ingress
+`)
+
+	deleteAddonPrometheusExample = templates.Examples(`
+		# Deletes the Prometheus addon
+		jx delete addon prometheus
+	`)
+)
+
+// DeleteAddonPrometheusOptions the options for the delete addon prometheus command
+type DeleteAddonPrometheusOptions struct {
+	DeleteAddonOptions
+
+	ReleaseName string
+	Namespace   string
+}
+
+// NewCmdDeleteAddonPrometheus defines the command
+func NewCmdDeleteAddonPrometheus(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &DeleteAddonPrometheusOptions{
+		DeleteAddonOptions: DeleteAddonOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "prometheus",
+		Short:   "Deletes the Prometheus addon",
+		Long:    deleteAddonPrometheusLong,
+		Example: deleteAddonPrometheusExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&options.ReleaseName, optionRelease, "r", defaultPrometheusReleaseName, "The chart release name")
+	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", defaultPrometheusNamespace, "The namespace the addon is installed in")
+	options.addFlags(cmd)
+	return cmd
+}
+
+// Run implements the command
+func (o *DeleteAddonPrometheusOptions) Run() error {
+	if o.ReleaseName == "" {
+		return util.MissingOption(optionRelease)
+	}
+	if o.Namespace == "" {
+		return util.MissingOption("namespace")
+	}
+	kubeClient, _, err := o.KubeClient()
+	if err != nil {
+		return err
+	}
+	err = o.Helm().DeleteRelease(o.Namespace, o.ReleaseName, o.Purge)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete the release %s", o.ReleaseName)
+	}
+	err = deleteGrafanaDashboards(kubeClient, o.Namespace)
+	if err != nil {
+		return err
+	}
+	ingress := o.ReleaseName + prometheusGrafanaServiceSuffix
+	err = kubeClient.ExtensionsV1beta1().Ingresses(o.Namespace).Delete(ingress, &metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete the ingress %s", ingress)
+	}
+	log.Infof("Deleted the Prometheus addon %s from namespace %s\n", util.ColorInfo(o.ReleaseName), util.ColorInfo(o.Namespace))
+	return nil
+}
@@ -230,7 +230,10 @@ func (o *DeleteAppOptions) deleteAppFromEnvironment(env *v1.Environment, appName
 		requirements.RemoveApp(appName)
 		return nil
 	}
-	info, err := o.createEnvironmentPullRequest(env, modifyRequirementsFn, branchName, title, message, nil, o.ConfigureGitCallback)
+	modifyChartFn := func(chartDir string) error {
+		return removeCanaryTemplate(chartDir, appName)
+	}
+	info, err := o.createEnvironmentPullRequest(env, modifyRequirementsFn, modifyChartFn, branchName, title, message, nil, o.ConfigureGitCallback)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// DeleteClusterOptions are the flags for delete cluster commands
+type DeleteClusterOptions struct {
+	CommonOptions
+}
+
+// NewCmdDeleteCluster creates a command object for the "delete cluster" action which
+// deletes a Kubernetes cluster created by jx
+func NewCmdDeleteCluster(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &DeleteClusterOptions{
+		CommonOptions{
+			Factory: f,
+			In:      in,
+			Out:     out,
+			Err:     errOut,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "cluster [kubernetes provider]",
+		Short: "Deletes a Kubernetes cluster",
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(NewCmdDeleteClusterDOKS(f, in, out, errOut))
+	cmd.AddCommand(NewCmdDeleteClusterEKS(f, in, out, errOut))
+	cmd.AddCommand(NewCmdDeleteClusterKind(f, in, out, errOut))
+	return cmd
+}
+
+// Run implements this command
+func (o *DeleteClusterOptions) Run() error {
+	return o.Cmd.Help()
+}
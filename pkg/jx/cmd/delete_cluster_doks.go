@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jenkins-x/jx/pkg/cloud/doks"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// DeleteClusterDOKSOptions contains the CLI flags
+type DeleteClusterDOKSOptions struct {
+	CommonOptions
+
+	Flags DeleteClusterDOKSFlags
+}
+
+// DeleteClusterDOKSFlags the flags for deleting a DigitalOcean Kubernetes cluster
+type DeleteClusterDOKSFlags struct {
+	ClusterName string
+	Confirm     bool
+}
+
+var (
+	deleteClusterDOKSLong = templates.LongDesc(`
+		This command deletes a DigitalOcean Kubernetes cluster created via 'jx create cluster doks' along with its
+		node droplets and removes it from the local kube config.
+`)
+
+	deleteClusterDOKSExample = templates.Examples(`
+		# pick the DigitalOcean Kubernetes cluster to delete
+		jx delete cluster doks
+
+		# delete a specific cluster without prompting
+		jx delete cluster doks --cluster-name mycluster -b -y
+`)
+)
+
+// NewCmdDeleteClusterDOKS creates the command
+func NewCmdDeleteClusterDOKS(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := DeleteClusterDOKSOptions{
+		CommonOptions: CommonOptions{
+			Factory: f,
+			In:      in,
+			Out:     out,
+			Err:     errOut,
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "doks",
+		Short:   "Deletes a DigitalOcean Kubernetes cluster",
+		Long:    deleteClusterDOKSLong,
+		Example: deleteClusterDOKSExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.Flags.ClusterName, optionClusterName, "n", "", "The name of the cluster to delete")
+	cmd.Flags().BoolVarP(&options.Flags.Confirm, "yes", "y", false, "Confirms we should delete the cluster")
+	return cmd
+}
+
+// Run implements this command
+func (o *DeleteClusterDOKSOptions) Run() error {
+	flags := &o.Flags
+	if o.BatchMode && !flags.Confirm {
+		return fmt.Errorf("In batch mode you must specify the '-y' flag to confirm")
+	}
+
+	err := o.installRequirements(DOKS)
+	if err != nil {
+		return err
+	}
+
+	clusterName := flags.ClusterName
+	if clusterName == "" && len(o.Args) > 0 {
+		clusterName = o.Args[0]
+	}
+	if clusterName == "" {
+		if o.BatchMode {
+			return util.MissingOption(optionClusterName)
+		}
+		output, err := o.getCommandOutput("", "doctl", "kubernetes", "cluster", "list", "-o", "json")
+		if err != nil {
+			return err
+		}
+		names, err := doks.ParseClusterNames([]byte(output))
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			return fmt.Errorf("There are no DigitalOcean Kubernetes clusters")
+		}
+		clusterName, err = util.PickName(names, "Pick the DigitalOcean Kubernetes cluster to delete:", o.In, o.Out, o.Err)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !flags.Confirm {
+		log.Warnf("You are about to delete the DigitalOcean Kubernetes cluster %s. This operation CANNOT be undone!\n", clusterName)
+		confirm := false
+		prompt := &survey.Confirm{
+			Message: "Are you sure you want to delete the cluster?",
+			Default: false,
+		}
+		err = survey.AskOne(prompt, &confirm, nil, survey.WithStdio(o.In, o.Out, o.Err))
+		if err != nil {
+			return err
+		}
+		if !confirm {
+			return nil
+		}
+	}
+
+	log.Infof("Deleting DigitalOcean Kubernetes cluster %s\n", util.ColorInfo(clusterName))
+	err = o.runCommandVerbose("doctl", "kubernetes", "cluster", "delete", clusterName, "--force")
+	if err != nil {
+		return err
+	}
+	log.Infof("Deleted DigitalOcean Kubernetes cluster %s\n", util.ColorInfo(clusterName))
+	return nil
+}
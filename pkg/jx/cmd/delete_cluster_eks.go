@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/cloud/amazon"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// DeleteClusterEKSOptions contains the CLI flags
+type DeleteClusterEKSOptions struct {
+	CommonOptions
+
+	Flags DeleteClusterEKSFlags
+}
+
+// DeleteClusterEKSFlags the flags for deleting an EKS cluster
+type DeleteClusterEKSFlags struct {
+	ClusterName string
+	Region      string
+	Profile     string
+	Confirm     bool
+}
+
+var (
+	deleteClusterEKSLong = templates.LongDesc(`
+		This command deletes an EKS cluster created via 'jx create cluster eks' along with the CloudFormation stacks
+		created by eksctl and any load balancers and security groups Kubernetes left behind for the cluster.
+`)
+
+	deleteClusterEKSExample = templates.Examples(`
+		# pick the EKS cluster to delete
+		jx delete cluster eks
+
+		# delete a specific EKS cluster without prompting
+		jx delete cluster eks --cluster-name mycluster --region eu-west-1 -b -y
+`)
+)
+
+// NewCmdDeleteClusterEKS creates the command
+func NewCmdDeleteClusterEKS(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := DeleteClusterEKSOptions{
+		CommonOptions: CommonOptions{
+			Factory: f,
+			In:      in,
+			Out:     out,
+			Err:     errOut,
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "eks",
+		Short:   "Deletes an EKS cluster and the AWS resources created for it",
+		Long:    deleteClusterEKSLong,
+		Example: deleteClusterEKSExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.Flags.ClusterName, optionClusterName, "n", "", "The name of the cluster to delete")
+	cmd.Flags().StringVarP(&options.Flags.Region, "region", "r", "", "The region to use. Default: "+amazon.DefaultRegion)
+	cmd.Flags().StringVarP(&options.Flags.Profile, "profile", "p", "", "AWS profile to use. If provided, this overrides the AWS_PROFILE environment variable")
+	cmd.Flags().BoolVarP(&options.Flags.Confirm, "yes", "y", false, "Confirms we should delete the cluster")
+	return cmd
+}
+
+// Run implements this command
+func (o *DeleteClusterEKSOptions) Run() error {
+	flags := &o.Flags
+	if o.BatchMode && !flags.Confirm {
+		return fmt.Errorf("In batch mode you must specify the '-y' flag to confirm")
+	}
+
+	var deps []string
+	d := binaryShouldBeInstalled("eksctl")
+	if d != "" {
+		deps = append(deps, d)
+	}
+	d = awsIamAuthenticatorShouldBeInstalled()
+	if d != "" {
+		deps = append(deps, d)
+	}
+	err := o.installMissingDependencies(deps)
+	if err != nil {
+		log.Errorf("%v\nPlease fix the error or install manually then try again", err)
+		os.Exit(-1)
+	}
+
+	region, err := amazon.ResolveRegion(flags.Profile, flags.Region)
+	if err != nil {
+		return err
+	}
+
+	clusterName := flags.ClusterName
+	if clusterName == "" && len(o.Args) > 0 {
+		clusterName = o.Args[0]
+	}
+	if clusterName == "" {
+		if o.BatchMode {
+			return util.MissingOption(optionClusterName)
+		}
+		args := o.appendProfileArg([]string{"get", "cluster", "--region", region, "-o", "json"})
+		output, err := o.getCommandOutput("", "eksctl", args...)
+		if err != nil {
+			return err
+		}
+		names, err := amazon.ParseEksctlClusterNames([]byte(output))
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			return fmt.Errorf("There are no EKS clusters in region %s", region)
+		}
+		clusterName, err = util.PickName(names, "Pick the EKS cluster to delete:", o.In, o.Out, o.Err)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !flags.Confirm {
+		log.Warnf("You are about to delete the EKS cluster %s in region %s. This operation CANNOT be undone!\n", clusterName, region)
+		confirm := false
+		prompt := &survey.Confirm{
+			Message: "Are you sure you want to delete the cluster?",
+			Default: false,
+		}
+		err = survey.AskOne(prompt, &confirm, nil, survey.WithStdio(o.In, o.Out, o.Err))
+		if err != nil {
+			return err
+		}
+		if !confirm {
+			return nil
+		}
+	}
+
+	args := o.appendProfileArg([]string{"delete", "cluster", "--name", clusterName, "--region", region, "--wait"})
+	log.Infof("Deleting EKS cluster %s - this can take a while so please be patient...\n", util.ColorInfo(clusterName))
+	log.Infof("Running command: %s\n", util.ColorInfo("eksctl "+strings.Join(args, " ")))
+	err = o.runCommandVerbose("eksctl", args...)
+	if err != nil {
+		return err
+	}
+
+	loadBalancers, err := amazon.DeleteClusterLoadBalancers(flags.Profile, region, clusterName)
+	if err != nil {
+		return err
+	}
+	securityGroups, err := amazon.DeleteClusterSecurityGroups(flags.Profile, region, clusterName)
+	if err != nil {
+		return err
+	}
+	log.Infof("Deleted EKS cluster %s along with %d leftover load balancers and %d security groups\n",
+		util.ColorInfo(clusterName), len(loadBalancers), len(securityGroups))
+	return nil
+}
+
+func (o *DeleteClusterEKSOptions) appendProfileArg(args []string) []string {
+	if o.Flags.Profile != "" {
+		args = append(args, "--profile", o.Flags.Profile)
+	}
+	return args
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jenkins-x/jx/pkg/cloud/kind"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// DeleteClusterKindOptions contains the CLI flags
+type DeleteClusterKindOptions struct {
+	CommonOptions
+
+	Flags DeleteClusterKindFlags
+}
+
+// DeleteClusterKindFlags the flags for deleting a kind cluster
+type DeleteClusterKindFlags struct {
+	ClusterName string
+	Confirm     bool
+}
+
+var (
+	deleteClusterKindLong = templates.LongDesc(`
+		This command deletes a kind cluster created via 'jx create cluster kind', removing the Docker containers
+		of its nodes.
+`)
+
+	deleteClusterKindExample = templates.Examples(`
+		# delete the default kind cluster
+		jx delete cluster kind
+
+		# delete a specific kind cluster without prompting
+		jx delete cluster kind --cluster-name mycluster -b -y
+`)
+)
+
+// NewCmdDeleteClusterKind creates the command
+func NewCmdDeleteClusterKind(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := DeleteClusterKindOptions{
+		CommonOptions: CommonOptions{
+			Factory: f,
+			In:      in,
+			Out:     out,
+			Err:     errOut,
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "kind",
+		Short:   "Deletes a kind cluster and the Docker containers of its nodes",
+		Long:    deleteClusterKindLong,
+		Example: deleteClusterKindExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addCommonFlags(cmd)
+
+	cmd.Flags().StringVarP(&options.Flags.ClusterName, optionClusterName, "n", kind.DefaultClusterName, "The name of the cluster to delete")
+	cmd.Flags().BoolVarP(&options.Flags.Confirm, "yes", "y", false, "Confirms we should delete the cluster")
+	return cmd
+}
+
+// Run implements this command
+func (o *DeleteClusterKindOptions) Run() error {
+	flags := &o.Flags
+	if o.BatchMode && !flags.Confirm {
+		return fmt.Errorf("In batch mode you must specify the '-y' flag to confirm")
+	}
+
+	err := o.installRequirements(KIND)
+	if err != nil {
+		return err
+	}
+
+	clusterName := flags.ClusterName
+	if len(o.Args) > 0 {
+		clusterName = o.Args[0]
+	}
+	if clusterName == "" {
+		clusterName = kind.DefaultClusterName
+	}
+
+	if !flags.Confirm {
+		log.Warnf("You are about to delete the kind cluster %s. This operation CANNOT be undone!\n", clusterName)
+		confirm := false
+		prompt := &survey.Confirm{
+			Message: "Are you sure you want to delete the cluster?",
+			Default: false,
+		}
+		err = survey.AskOne(prompt, &confirm, nil, survey.WithStdio(o.In, o.Out, o.Err))
+		if err != nil {
+			return err
+		}
+		if !confirm {
+			return nil
+		}
+	}
+
+	log.Infof("Deleting kind cluster %s\n", util.ColorInfo(clusterName))
+	err = o.runCommandVerbose("kind", "delete", "cluster", "--name", clusterName)
+	if err != nil {
+		return err
+	}
+	log.Infof("Deleted kind cluster %s\n", util.ColorInfo(clusterName))
+	return nil
+}
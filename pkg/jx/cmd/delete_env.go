@@ -128,6 +128,19 @@ func (o *DeleteEnvOptions) deleteEnviroment(jxClient versioned.Interface, ns str
 		return fmt.Errorf("No namespace for environment %s", name)
 	}
 	kind := env.Spec.Kind
+	if envNs == ns && !kind.IsPermanent() {
+		// previews of a namespace scoped install are releases in the team namespace
+		releaseName := kube.ToValidName(ns + "-" + name)
+		log.Infof("Deleting the release %s rather than the team namespace %s\n", util.ColorInfo(releaseName), util.ColorInfo(ns))
+		return o.Helm().DeleteRelease(ns, releaseName, true)
+	}
+	if !kind.IsPermanent() {
+		// the release of a preview is named after its namespace and is not removed with the namespace
+		err = o.Helm().DeleteRelease(envNs, envNs, true)
+		if err != nil {
+			log.Warnf("Failed to delete the release %s of environment %s: %s\n", envNs, name, err)
+		}
+	}
 	if o.DeleteNamespace || !kind.IsPermanent() {
 		return o.KubeClientCached.CoreV1().Namespaces().Delete(envNs, &metav1.DeleteOptions{})
 	}
@@ -53,7 +53,10 @@ func NewCmdEdit(f Factory, in terminal.FileReader, out terminal.FileWriter, errO
 
 	cmd.AddCommand(NewCmdCreateBranchPattern(f, in, out, errOut))
 	cmd.AddCommand(NewCmdEditAddon(f, in, out, errOut))
+	cmd.AddCommand(NewCmdEditApprovers(f, in, out, errOut))
+	cmd.AddCommand(NewCmdEditCanary(f, in, out, errOut))
 	cmd.AddCommand(NewCmdEditBuildpack(f, in, out, errOut))
+	cmd.AddCommand(NewCmdEditDevPodImages(f, in, out, errOut))
 	cmd.AddCommand(NewCmdEditConfig(f, in, out, errOut))
 	cmd.AddCommand(NewCmdEditEnv(f, in, out, errOut))
 	cmd.AddCommand(NewCmdEditHelmBin(f, in, out, errOut))
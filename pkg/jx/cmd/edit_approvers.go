@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"io"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+var (
+	editApproversLong = templates.LongDesc(`
+		Configures the users who approve promotions to an environment
+
+		Promotion Pull Requests of an environment with approvers are only merged once one of the approvers comments
+		'/approve promotion' on the Pull Request or runs 'jx approve promotion'.
+`)
+
+	editApproversExample = templates.Examples(`
+		# require approval by alice or bob for promotions to production
+		jx edit approvers --env production --user alice --user bob
+
+		# merge promotions to production automatically again
+		jx edit approvers --env production
+
+	`)
+)
+
+// EditApproversOptions the options for the edit approvers command
+type EditApproversOptions struct {
+	CreateOptions
+
+	Environment string
+	Users       []string
+}
+
+// NewCmdEditApprovers creates a command object for the "edit approvers" command
+func NewCmdEditApprovers(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &EditApproversOptions{
+		CreateOptions: CreateOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "approvers",
+		Short:   "Configures the users who approve promotions to an environment",
+		Long:    editApproversLong,
+		Example: editApproversExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.Environment, optionEnvironment, "e", "", "The environment to configure the approvers of")
+	cmd.Flags().StringArrayVarP(&options.Users, "user", "u", []string{}, "The git user names of the approvers. Promotions are merged automatically if there are none")
+	options.addCommonFlags(cmd)
+	return cmd
+}
+
+// Run implements the command
+func (o *EditApproversOptions) Run() error {
+	if o.Environment == "" {
+		return util.MissingOption(optionEnvironment)
+	}
+
+	callback := func(env *v1.Environment) error {
+		env.Spec.TeamSettings.SetEnvironmentPromotionApprovers(o.Environment, o.Users)
+		if len(o.Users) == 0 {
+			log.Infof("Promotions to environment %s are merged automatically\n", util.ColorInfo(o.Environment))
+		} else {
+			log.Infof("Promotions to environment %s are approved by: %s\n", util.ColorInfo(o.Environment), util.ColorInfo(strings.Join(o.Users, ", ")))
+		}
+		return nil
+	}
+	return o.ModifyDevEnvironment(callback)
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+var (
+	editCanaryLong = templates.LongDesc(`
+		Configures the analysis of the Flagger canaries of the environments with the Canary promotion strategy
+
+		Only the settings which are specified are changed. Settings which were never configured use their defaults.
+`)
+
+	editCanaryExample = templates.Examples(`
+		# route 20% more traffic to the canary every 2 minutes up to 60%
+		jx edit canary --interval 2m --step-weight 20 --max-weight 60
+
+		# roll back canaries after 3 checks where less than 95% of requests succeed
+		jx edit canary --success-rate 95 --threshold 3
+	`)
+)
+
+// EditCanaryOptions the options for the edit canary command
+type EditCanaryOptions struct {
+	CreateOptions
+
+	Settings v1.CanarySettings
+}
+
+// NewCmdEditCanary creates a command object for the "edit canary" command
+func NewCmdEditCanary(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &EditCanaryOptions{
+		CreateOptions: CreateOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "canary",
+		Short:   "Configures the analysis of the canaries of environments with the Canary promotion strategy",
+		Long:    editCanaryLong,
+		Example: editCanaryExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	settings := &options.Settings
+	cmd.Flags().StringVarP(&settings.Interval, "interval", "i", "", "The duration between the steps of the analysis such as 1m")
+	cmd.Flags().IntVarP(&settings.Threshold, "threshold", "t", 0, "The number of failed checks after which the canary is rolled back")
+	cmd.Flags().IntVarP(&settings.MaxWeight, "max-weight", "", 0, "The maximum percentage of traffic routed to the canary")
+	cmd.Flags().IntVarP(&settings.StepWeight, "step-weight", "", 0, "The percentage of traffic added to the canary at each step")
+	cmd.Flags().IntVarP(&settings.RequestSuccessRate, "success-rate", "", 0, "The minimum percentage of successful requests")
+	cmd.Flags().IntVarP(&settings.RequestDuration, "request-duration", "", 0, "The maximum 99th percentile request duration in milliseconds")
+	cmd.Flags().IntVarP(&settings.Port, "port", "p", 0, "The container port of the applications")
+	options.addCommonFlags(cmd)
+	return cmd
+}
+
+// Run implements the command
+func (o *EditCanaryOptions) Run() error {
+	callback := func(env *v1.Environment) error {
+		canary := &env.Spec.TeamSettings.Canary
+		mergeCanarySettings(canary, &o.Settings)
+		s := env.Spec.TeamSettings.GetCanarySettings()
+		log.Infof("Canaries route %s%% more traffic every %s up to %s%% and roll back after %s failed checks\n",
+			util.ColorInfo(s.StepWeight), util.ColorInfo(s.Interval), util.ColorInfo(s.MaxWeight), util.ColorInfo(s.Threshold))
+		return nil
+	}
+	return o.ModifyDevEnvironment(callback)
+}
+
+// mergeCanarySettings changes the settings which are specified in the changes
+func mergeCanarySettings(settings *v1.CanarySettings, changes *v1.CanarySettings) {
+	if changes.Interval != "" {
+		settings.Interval = changes.Interval
+	}
+	if changes.Threshold > 0 {
+		settings.Threshold = changes.Threshold
+	}
+	if changes.MaxWeight > 0 {
+		settings.MaxWeight = changes.MaxWeight
+	}
+	if changes.StepWeight > 0 {
+		settings.StepWeight = changes.StepWeight
+	}
+	if changes.RequestSuccessRate > 0 {
+		settings.RequestSuccessRate = changes.RequestSuccessRate
+	}
+	if changes.RequestDuration > 0 {
+		settings.RequestDuration = changes.RequestDuration
+	}
+	if changes.Port > 0 {
+		settings.Port = changes.Port
+	}
+}
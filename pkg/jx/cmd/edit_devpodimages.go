@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+var (
+	editDevPodImagesLong = templates.LongDesc(`
+		Configures the images the DevPods of the team can be created with in addition to the pod templates
+
+		The images are offered by 'jx create devpod' next to the pod templates and can be picked with --label. A DevPod
+		image is based on a pod template if --pod-template is specified and on a pod with a single container otherwise.
+		The workspace volume, Theia, the exposed ports and the git credentials are added to it like to any DevPod.
+`)
+
+	editDevPodImagesExample = templates.Examples(`
+		# add a DevPod image with the SDK of the team based on the maven pod template
+		jx edit devpodimages --name sdk --image myregistry/mysdk:1.2.3 --pod-template maven --env SDK_HOME=/opt/sdk
+
+		# remove the DevPod image
+		jx edit devpodimages --name sdk --delete
+
+		# list the DevPod images
+		jx edit devpodimages
+	`)
+)
+
+// EditDevPodImagesOptions the options for the edit devpodimages command
+type EditDevPodImagesOptions struct {
+	CreateOptions
+
+	Name          string
+	Image         string
+	PodTemplate   string
+	RequestCPU    string
+	RequestMemory string
+	Env           []string
+	Delete        bool
+}
+
+// NewCmdEditDevPodImages creates a command object for the "edit devpodimages" command
+func NewCmdEditDevPodImages(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &EditDevPodImagesOptions{
+		CreateOptions: CreateOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "devpodimages",
+		Short:   "Configures the images the DevPods of the team can be created with",
+		Aliases: []string{"devpodimage"},
+		Long:    editDevPodImagesLong,
+		Example: editDevPodImagesExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.Name, "name", "n", "", "The name the DevPod image is picked with as the label of 'jx create devpod'")
+	cmd.Flags().StringVarP(&options.Image, optionImage, "i", "", "The image reference of the DevPod image")
+	cmd.Flags().StringVarP(&options.PodTemplate, "pod-template", "t", "", "The label of the pod template the DevPod image is based on")
+	cmd.Flags().StringVarP(&options.RequestCPU, optionRequestCpu, "c", "", "The CPU request of the container")
+	cmd.Flags().StringVarP(&options.RequestMemory, "request-memory", "m", "", "The memory request of the container")
+	cmd.Flags().StringArrayVarP(&options.Env, "env", "e", []string{}, "The environment variables of the container of the form NAME=VALUE")
+	cmd.Flags().BoolVarP(&options.Delete, "delete", "d", false, "Removes the DevPod image")
+	options.addCommonFlags(cmd)
+	return cmd
+}
+
+// Run implements the command
+func (o *EditDevPodImagesOptions) Run() error {
+	if o.Name == "" {
+		if o.Image != "" || o.Delete {
+			return util.MissingOption("name")
+		}
+		return o.listDevPodImages()
+	}
+	if o.Name != kube.ToValidName(o.Name) {
+		return fmt.Errorf("invalid name %s, it should be a valid label value such as %s", o.Name, kube.ToValidName(o.Name))
+	}
+	if o.Delete {
+		callback := func(env *v1.Environment) error {
+			if !env.Spec.TeamSettings.RemoveDevPodImage(o.Name) {
+				return fmt.Errorf("there is no DevPod image %s", o.Name)
+			}
+			log.Infof("Removed the DevPod image %s\n", util.ColorInfo(o.Name))
+			return nil
+		}
+		return o.ModifyDevEnvironment(callback)
+	}
+	if o.Image == "" {
+		return util.MissingOption(optionImage)
+	}
+	image := v1.DevPodImage{
+		Name:          o.Name,
+		Image:         o.Image,
+		PodTemplate:   o.PodTemplate,
+		RequestCPU:    o.RequestCPU,
+		RequestMemory: o.RequestMemory,
+	}
+	for _, quantity := range []string{o.RequestCPU, o.RequestMemory} {
+		if quantity != "" {
+			_, err := resource.ParseQuantity(quantity)
+			if err != nil {
+				return fmt.Errorf("invalid resource request %s: %s", quantity, err)
+			}
+		}
+	}
+	for _, env := range o.Env {
+		values := strings.SplitN(env, "=", 2)
+		if len(values) != 2 || values[0] == "" {
+			return fmt.Errorf("invalid environment variable %s, it should be of the form NAME=VALUE", env)
+		}
+		if image.Env == nil {
+			image.Env = map[string]string{}
+		}
+		image.Env[values[0]] = values[1]
+	}
+
+	callback := func(env *v1.Environment) error {
+		env.Spec.TeamSettings.SetDevPodImage(image)
+		log.Infof("DevPods created with %s run the image %s\n", util.ColorInfo("jx create devpod -l "+o.Name), util.ColorInfo(o.Image))
+		return nil
+	}
+	return o.ModifyDevEnvironment(callback)
+}
+
+func (o *EditDevPodImagesOptions) listDevPodImages() error {
+	teamSettings, err := o.TeamSettings()
+	if err != nil {
+		return err
+	}
+	table := o.CreateTable()
+	table.AddRow("NAME", "IMAGE", "POD TEMPLATE")
+	for _, image := range teamSettings.DevPodImages {
+		table.AddRow(image.Name, image.Image, image.PodTemplate)
+	}
+	table.Render()
+	return nil
+}
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"fmt"
 	"io"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
@@ -29,6 +31,12 @@ var (
 
 		# Edit the prod Environment in batch mode (so not interactive)
 		jx edit env -b -n prod -l Production --no-gitops --namespace my-prod
+
+		# Roll out promotions to the production Environment as Flagger canaries
+		jx edit env production --promotion-strategy canary
+
+		# Inject Istio sidecars into the pods of the staging Environment
+		jx edit env staging --istio-injection=true
 	`)
 )
 
@@ -39,12 +47,14 @@ type EditEnvOptions struct {
 	Options                v1.Environment
 	HelmValuesConfig       config.HelmValuesConfig
 	PromotionStrategy      string
+	Promotion              string
 	NoGitOps               bool
 	ForkEnvironmentGitRepo string
 	EnvJobCredentials      string
 	GitRepositoryOptions   gits.GitRepositoryOptions
 	Prefix                 string
 	BranchPattern          string
+	IstioInjection         bool
 }
 
 // NewCmdEditEnv creates a command object for the "create" command
@@ -87,12 +97,15 @@ func NewCmdEditEnv(f Factory, in terminal.FileReader, out terminal.FileWriter, e
 	cmd.Flags().Int32VarP(&options.Options.Spec.Order, "order", "o", 100, "The order weighting of the Environment so that they can be sorted by this order before name")
 	cmd.Flags().StringVarP(&options.Prefix, "prefix", "", "jx", "Environment repo prefix, your Git repo will be of the form 'environment-$prefix-$envName'")
 
-	cmd.Flags().StringVarP(&options.PromotionStrategy, "promotion", "p", "", "The promotion strategy")
+	cmd.Flags().StringVarP(&options.Promotion, "promotion", "p", "", "The promotion strategy")
+	cmd.Flags().MarkDeprecated("promotion", "use --promotion-strategy instead")
+	cmd.Flags().StringVarP(&options.PromotionStrategy, "promotion-strategy", "", "", "The promotion strategy. Possible values: "+strings.Join(v1.PromotionStrategyTypeValues, ", "))
 	cmd.Flags().StringVarP(&options.ForkEnvironmentGitRepo, "fork-git-repo", "f", kube.DefaultEnvironmentGitRepoURL, "The Git repository used as the fork when creating new Environment Git repos")
 	cmd.Flags().StringVarP(&options.EnvJobCredentials, "env-job-credentials", "", "", "The Jenkins credentials used by the GitOps Job for this environment")
 	cmd.Flags().StringVarP(&options.BranchPattern, "branches", "", "", "The branch pattern for branches to trigger CI/CD pipelines on the environment Git repository")
 
 	cmd.Flags().BoolVarP(&options.NoGitOps, "no-gitops", "x", false, "Disables the use of GitOps on the environment so that promotion is implemented by directly modifying the resources via Helm instead of using a Git repository")
+	cmd.Flags().BoolVarP(&options.IstioInjection, optionIstioInjection, "", false, "Enables or disables the Istio sidecar injection of the pods of the Environment's namespace instead of using the istioinjection team setting")
 
 	addGitRepoOptionsArguments(cmd, &options.GitRepositoryOptions)
 	options.HelmValuesConfig.AddExposeControllerValues(cmd, false)
@@ -155,12 +168,25 @@ func (o *EditEnvOptions) Run() error {
 	if err != nil {
 		return err
 	}
-	o.Options.Spec.PromotionStrategy = v1.PromotionStrategyType(o.PromotionStrategy)
+	if o.PromotionStrategy == "" {
+		o.PromotionStrategy = o.Promotion
+	}
+	o.Options.Spec.PromotionStrategy, err = v1.ParsePromotionStrategyType(o.PromotionStrategy)
+	if err != nil {
+		return err
+	}
 	gitProvider, err := kube.CreateEnvironmentSurvey(o.BatchMode, authConfigSvc, devEnv, env, &o.Options, o.ForkEnvironmentGitRepo,
 		ns, jxClient, kubeClient, envDir, &o.GitRepositoryOptions, o.HelmValuesConfig, o.Prefix, o.Git(), o.In, o.Out, o.Err)
 	if err != nil {
 		return err
 	}
+	istioInjection := o.Cmd != nil && o.Cmd.Flags().Changed(optionIstioInjection)
+	if istioInjection {
+		if env.Spec.Kind == v1.EnvironmentKindTypeDevelopment {
+			return fmt.Errorf("the Istio sidecar injection of the development environment cannot be changed")
+		}
+		env.Spec.IstioInjection = &o.IstioInjection
+	}
 	_, err = jxClient.JenkinsV1().Environments(ns).Update(env)
 	if err != nil {
 		return err
@@ -171,6 +197,14 @@ func (o *EditEnvOptions) Run() error {
 	if err != nil {
 		return err
 	}
+	if istioInjection {
+		err = o.labelRemoteEnvironmentNamespace(env, ns)
+		if err != nil {
+			return err
+		}
+		log.Infof("Set the Istio sidecar injection of namespace %s to %s. Restart its pods to add or remove their sidecars\n",
+			util.ColorInfo(env.Spec.Namespace), util.ColorInfo(kube.IstioInjectionLabelValue(o.IstioInjection)))
+	}
 	gitURL := env.Spec.Source.URL
 	if gitURL != "" {
 		if gitProvider == nil {
@@ -184,3 +218,28 @@ func (o *EditEnvOptions) Run() error {
 	}
 	return nil
 }
+
+// labelRemoteEnvironmentNamespace labels the namespace of an environment in a remote cluster with its Istio sidecar
+// injection setting as only the namespaces in the cluster of the team are set up by EnsureEnvironmentNamespaceSetup
+func (o *EditEnvOptions) labelRemoteEnvironmentNamespace(env *v1.Environment, devNs string) error {
+	if !kube.IsRemoteEnvironment(env) || env.Spec.Namespace == "" {
+		return nil
+	}
+	remoteClient, err := o.EnvironmentKubeClient(env)
+	if err != nil {
+		return err
+	}
+	jxClient, _, err := o.JXClient()
+	if err != nil {
+		return err
+	}
+	labels := map[string]string{
+		kube.LabelTeam:        devNs,
+		kube.LabelEnvironment: env.Name,
+	}
+	err = kube.AddIstioInjectionLabel(remoteClient, jxClient, env, devNs, labels)
+	if err != nil {
+		return err
+	}
+	return kube.EnsureNamespaceCreated(remoteClient, env.Spec.Namespace, labels, map[string]string{})
+}
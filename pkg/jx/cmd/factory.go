@@ -245,6 +245,10 @@ func (f *factory) AuthMergePipelineSecrets(config *auth.AuthConfig, secrets *cor
 					if name != "" {
 						server.Name = name
 					}
+					apiURL := annotations[kube.AnnotationAPIURL]
+					if apiURL != "" {
+						server.APIURL = apiURL
+					}
 					if data != nil {
 						username := data[kube.SecretDataUsername]
 						pwd := data[kube.SecretDataPassword]
@@ -21,6 +21,7 @@ const (
 	valid_gc_resources = `Valid resource types include:
 
     * activities
+	* devpods
 	* helm
 	* previews
 	* releases
@@ -38,6 +39,7 @@ var (
 	gc_example = templates.Examples(`
 		jx gc previews
 		jx gc activities
+		jx gc devpods
 		jx gc helm
 		jx gc gke
 		jx gc previews
@@ -72,6 +74,7 @@ func NewCmdGC(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut
 	}
 
 	cmd.AddCommand(NewCmdGCActivities(f, in, out, errOut))
+	cmd.AddCommand(NewCmdGCDevPods(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGCPreviews(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGCGKE(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGCHelm(f, in, out, errOut))
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// GCDevPodsOptions the options for the gc devpods command
+type GCDevPodsOptions struct {
+	CommonOptions
+
+	IdleTimeoutMinutes int
+	Suspend            bool
+}
+
+var (
+	GCDevPodsLong = templates.LongDesc(`
+		Garbage collect the DevPods which have had no activity for longer than the idle timeout of the team, which is
+		configured via 'jx edit devpodidletimeoutminutes'.
+
+		The activity of a DevPod is recorded by 'jx rsh', 'jx sync' and 'jx create devpod'. A DevPod is also kept if a
+		shell in it has had input within the idle timeout.
+
+		The workspace of DevPods created with --persist is kept so that nothing is lost. With --suspend the DevPods are
+		stored so that 'jx create devpod' recreates them with their workspace, otherwise a new DevPod created with the same
+		name and --persist uses the workspace again.
+`)
+
+	GCDevPodsExample = templates.Examples(`
+		jx gc devpods
+
+		# suspend the DevPods which have been idle for 2 hours
+		jx gc devpods --idle-timeout 120 --suspend
+`)
+)
+
+// NewCmdGCDevPods creates a command object for the "gc devpods" command
+func NewCmdGCDevPods(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &GCDevPodsOptions{
+		CommonOptions: CommonOptions{
+			Factory: f,
+			In:      in,
+			Out:     out,
+			Err:     errOut,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "devpods",
+		Short:   "garbage collection for idle DevPods",
+		Aliases: []string{"devpod"},
+		Long:    GCDevPodsLong,
+		Example: GCDevPodsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().IntVarP(&options.IdleTimeoutMinutes, "idle-timeout", "t", 0, "The minutes without activity after which DevPods are collected. Defaults to the idle timeout of the team")
+	cmd.Flags().BoolVarP(&options.Suspend, "suspend", "", false, "Suspend the idle DevPods so that 'jx create devpod' recreates them instead of deleting them")
+	options.addCommonFlags(cmd)
+	return cmd
+}
+
+// Run implements this command
+func (o *GCDevPodsOptions) Run() error {
+	client, curNs, err := o.KubeClient()
+	if err != nil {
+		return err
+	}
+	ns, _, err := kube.GetDevNamespace(client, curNs)
+	if err != nil {
+		return err
+	}
+	timeout := time.Duration(o.IdleTimeoutMinutes) * time.Minute
+	if timeout <= 0 {
+		timeout, err = o.TeamDevPodIdleTimeout()
+		if err != nil {
+			return err
+		}
+	}
+	if timeout <= 0 {
+		if o.Verbose {
+			log.Infof("Idle DevPods are kept as the team has no DevPod idle timeout\n")
+		}
+		return nil
+	}
+
+	pods, err := kube.GetDevPods(client, ns)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil || !kube.IsDevPodIdle(pod, timeout, now) {
+			continue
+		}
+		if o.hasShellActivity(ns, pod, timeout) {
+			err = kube.UpdateDevPodLastActivity(client, ns, pod.Name, now)
+			if err != nil {
+				log.Warnf("Failed to record the activity of DevPod %s: %s\n", pod.Name, err)
+			}
+			continue
+		}
+		if !hasDevPodWorkspace(pod) {
+			log.Warnf("DevPod %s was not created with --persist so its workspace is lost\n", pod.Name)
+		}
+		if o.Suspend {
+			log.Infof("Suspending DevPod %s of %s as it has had no activity for over %s\n", util.ColorInfo(pod.Name), util.ColorInfo(pod.Labels[kube.LabelDevPodUsername]), timeout)
+			_, err = kube.SuspendDevPod(client, ns, pod)
+		} else {
+			log.Infof("Deleting DevPod %s of %s as it has had no activity for over %s\n", util.ColorInfo(pod.Name), util.ColorInfo(pod.Labels[kube.LabelDevPodUsername]), timeout)
+			err = kube.DeleteDevPod(client, ns, pod)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to collect DevPod %s: %s", pod.Name, err)
+		}
+	}
+	return nil
+}
+
+// hasShellActivity returns true if a terminal of the first container of the DevPod has had input within the timeout
+func (o *GCDevPodsOptions) hasShellActivity(ns string, pod *corev1.Pod, timeout time.Duration) bool {
+	if len(pod.Spec.Containers) == 0 {
+		return false
+	}
+	probe := fmt.Sprintf("find /dev/pts -name '[0-9]*' -mmin -%d", int(timeout.Minutes()))
+	output, err := o.getCommandOutput("", "kubectl", "exec", "-n", ns, pod.Name, "-c", pod.Spec.Containers[0].Name, "--", "/bin/sh", "-c", probe)
+	if err != nil {
+		log.Warnf("Failed to check the shell activity of DevPod %s: %s\n", pod.Name, err)
+		return false
+	}
+	return strings.TrimSpace(output) != ""
+}
+
+// hasDevPodWorkspace returns true if the DevPod has a persistent workspace
+func hasDevPodWorkspace(pod *corev1.Pod) bool {
+	for _, v := range pod.Spec.Volumes {
+		if v.PersistentVolumeClaim != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// devPodIdleAndWarning returns how long the DevPod has been idle and a warning if it is collected soon
+func devPodIdleAndWarning(pod *corev1.Pod, timeout time.Duration, now time.Time) (string, string) {
+	idle := now.Sub(kube.DevPodLastActivity(pod)).Round(time.Minute)
+	expiry, ok := kube.DevPodIdleExpiry(pod, timeout)
+	if !ok {
+		return idle.String(), ""
+	}
+	remaining := expiry.Sub(now).Round(time.Minute)
+	warning := timeout / 4
+	if warning > 30*time.Minute {
+		warning = 30 * time.Minute
+	}
+	if remaining <= 0 {
+		return idle.String(), "idle, collected soon"
+	}
+	if remaining <= warning {
+		return idle.String(), fmt.Sprintf("collected in %s", remaining)
+	}
+	return idle.String(), ""
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDevPodIdleAndWarning(t *testing.T) {
+	t.Parallel()
+	created := time.Date(2018, 11, 2, 17, 0, 0, 0, time.UTC)
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "alice-maven",
+			CreationTimestamp: metav1.NewTime(created),
+		},
+	}
+	kube.SetDevPodLastActivity(pod, created.Add(time.Hour))
+
+	idle, warning := devPodIdleAndWarning(pod, 4*time.Hour, created.Add(2*time.Hour))
+	assert.Equal(t, "1h0m0s", idle)
+	assert.Equal(t, "", warning)
+
+	_, warning = devPodIdleAndWarning(pod, 4*time.Hour, created.Add(4*time.Hour+40*time.Minute))
+	assert.Equal(t, "collected in 20m0s", warning)
+
+	_, warning = devPodIdleAndWarning(pod, 4*time.Hour, created.Add(6*time.Hour))
+	assert.Equal(t, "idle, collected soon", warning)
+
+	_, warning = devPodIdleAndWarning(pod, 0, created.Add(100*time.Hour))
+	assert.Equal(t, "", warning)
+}
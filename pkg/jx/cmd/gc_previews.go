@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
@@ -15,7 +16,9 @@ import (
 	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx/pkg/gits"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
 )
 
 // GetOptions is the start of the data required to perform the operation.  As new fields are added, add them here instead of
@@ -32,6 +35,10 @@ var (
 		Garbage collect Jenkins X preview environments.  If a pull request is merged or closed the associated preview
 		environment will be deleted.
 
+		If the team has a preview time to live configured via 'jx edit previewttlhours' then preview environments which
+		have not been deployed for longer than that are deleted too. Previews created with 'jx preview --keep' are never
+		deleted.
+
 `)
 
 	GCPreviewsExample = templates.Examples(`
@@ -88,51 +95,93 @@ func (o *GCPreviewsOptions) Run() error {
 		return nil
 	}
 
-	for _, e := range envs.Items {
-		if e.Spec.Kind == v1.EnvironmentKindTypePreview {
-			gitInfo, err := gits.ParseGitURL(e.Spec.Source.URL)
-			if err != nil {
-				return err
-			}
-			// we need pull request info to include
-			authConfigSvc, err := o.CreateGitAuthConfigService()
-			if err != nil {
-				return err
-			}
+	ttl, err := o.TeamPreviewTTL()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
 
-			gitKind, err := o.GitServerKind(gitInfo)
-			if err != nil {
-				return err
+	for i := range envs.Items {
+		e := &envs.Items[i]
+		if e.Spec.Kind != v1.EnvironmentKindTypePreview {
+			continue
+		}
+		if kube.IsPreviewKept(e) {
+			if o.Verbose {
+				log.Infof("Keeping preview environment %s as it is pinned\n", util.ColorInfo(e.Name))
 			}
+			continue
+		}
+		gitInfo, err := gits.ParseGitURL(e.Spec.Source.URL)
+		if err != nil {
+			return err
+		}
+		// we need pull request info to include
+		authConfigSvc, err := o.CreateGitAuthConfigService()
+		if err != nil {
+			return err
+		}
 
-			gitProvider, err := gitInfo.CreateProvider(authConfigSvc, gitKind, o.Git(), o.BatchMode, o.In, o.Out, o.Err)
-			if err != nil {
-				return err
-			}
-			prNum, err := strconv.Atoi(e.Spec.PreviewGitSpec.Name)
-			if err != nil {
-				log.Warn("Unable to convert PR " + e.Spec.PreviewGitSpec.Name + " to a number" + "\n")
-			}
-			pullRequest, err := gitProvider.GetPullRequest(gitInfo.Organisation, gitInfo, prNum)
-			if err != nil {
-				return err
-			}
+		gitKind, err := o.GitServerKind(gitInfo)
+		if err != nil {
+			return err
+		}
 
-			lowerState := strings.ToLower(*pullRequest.State)
-
-			if strings.HasPrefix(lowerState, "clos") || strings.HasPrefix(lowerState, "merged") || strings.HasPrefix(lowerState, "superseded") || strings.HasPrefix(lowerState, "declined") {
-				// lets delete the preview environment
-				deleteOpts := DeleteEnvOptions{
-					DeleteNamespace: true,
-					CommonOptions:   o.CommonOptions,
-				}
-				deleteOpts.CommonOptions.Args = []string{e.Name}
-				err = deleteOpts.Run()
-				if err != nil {
-					return fmt.Errorf("failed to delete preview environment %s: %v\n", e.Name, err)
-				}
-			}
+		gitProvider, err := gitInfo.CreateProvider(authConfigSvc, gitKind, o.Git(), o.BatchMode, o.In, o.Out, o.Err)
+		if err != nil {
+			return err
+		}
+		prNum, err := strconv.Atoi(e.Spec.PreviewGitSpec.Name)
+		if err != nil {
+			log.Warn("Unable to convert PR " + e.Spec.PreviewGitSpec.Name + " to a number" + "\n")
+		}
+		pullRequest, err := gitProvider.GetPullRequest(gitInfo.Organisation, gitInfo, prNum)
+		if err != nil {
+			return err
+		}
+
+		state := ""
+		if pullRequest.State != nil {
+			state = *pullRequest.State
+		}
+		reason := previewGCReason(e, state, ttl, now)
+		if reason == "" {
+			continue
+		}
+
+		// lets delete the preview environment
+		log.Infof("Deleting preview environment %s as %s\n", util.ColorInfo(e.Name), reason)
+		deleteOpts := DeleteEnvOptions{
+			DeleteNamespace: true,
+			CommonOptions:   o.CommonOptions,
+		}
+		deleteOpts.CommonOptions.Args = []string{e.Name}
+		err = deleteOpts.Run()
+		if err != nil {
+			return fmt.Errorf("failed to delete preview environment %s: %v\n", e.Name, err)
+		}
+
+		comment := fmt.Sprintf(":broom: The preview environment %s has been removed as %s.", e.Name, reason)
+		err = gitProvider.AddPRComment(pullRequest, comment)
+		if err != nil {
+			log.Warnf("Failed to comment on pull request %s that preview environment %s was removed: %s\n", pullRequest.URL, e.Name, err)
 		}
 	}
 	return nil
 }
+
+// previewGCReason returns why the preview environment should be garbage collected given the state of its pull request
+// and the time to live of previews without activity or an empty string if it should be kept
+func previewGCReason(env *v1.Environment, prState string, ttl time.Duration, now time.Time) string {
+	if kube.IsPreviewKept(env) {
+		return ""
+	}
+	lowerState := strings.ToLower(prState)
+	if strings.HasPrefix(lowerState, "clos") || strings.HasPrefix(lowerState, "merged") || strings.HasPrefix(lowerState, "superseded") || strings.HasPrefix(lowerState, "declined") {
+		return fmt.Sprintf("its pull request is %s", lowerState)
+	}
+	if kube.IsPreviewExpired(env, ttl, now) {
+		return fmt.Sprintf("it has had no activity for over %s", ttl)
+	}
+	return ""
+}
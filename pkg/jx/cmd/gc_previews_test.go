@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPreviewGCReason(t *testing.T) {
+	t.Parallel()
+	created := time.Date(2018, 11, 1, 9, 0, 0, 0, time.UTC)
+	ttl := 72 * time.Hour
+	env := &v1.Environment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "acme-pr-1",
+			CreationTimestamp: metav1.NewTime(created),
+		},
+	}
+
+	assert.Equal(t, "", previewGCReason(env, "open", ttl, created.Add(time.Hour)))
+	assert.Equal(t, "its pull request is merged", previewGCReason(env, "MERGED", ttl, created.Add(time.Hour)))
+	assert.Equal(t, "its pull request is closed", previewGCReason(env, "closed", 0, created.Add(time.Hour)))
+	assert.Equal(t, "it has had no activity for over 72h0m0s", previewGCReason(env, "open", ttl, created.Add(73*time.Hour)))
+	assert.Equal(t, "", previewGCReason(env, "open", 0, created.Add(73*time.Hour)))
+
+	env.Annotations = map[string]string{kube.AnnotationPreviewKeep: "true"}
+	assert.Equal(t, "", previewGCReason(env, "closed", ttl, created.Add(73*time.Hour)))
+}
+
+func TestPreviewAgeAndExpiry(t *testing.T) {
+	t.Parallel()
+	created := time.Date(2018, 11, 1, 9, 0, 0, 0, time.UTC)
+	env := &v1.Environment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "acme-pr-1",
+			CreationTimestamp: metav1.NewTime(created),
+		},
+	}
+
+	age, expires := previewAgeAndExpiry(env, 24*time.Hour, created.Add(90*time.Minute))
+	assert.Equal(t, "1h30m0s", age)
+	assert.Equal(t, "22h30m0s", expires)
+
+	_, expires = previewAgeAndExpiry(env, 24*time.Hour, created.Add(25*time.Hour))
+	assert.Equal(t, "expired", expires)
+
+	_, expires = previewAgeAndExpiry(env, 0, created.Add(25*time.Hour))
+	assert.Equal(t, "never", expires)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
@@ -13,6 +14,16 @@ import (
 	"github.com/jenkins-x/jx/pkg/util"
 )
 
+const (
+	optionOutput = "output"
+
+	outputFormatTable = "table"
+	outputFormatJSON  = "json"
+	outputFormatYAML  = "yaml"
+)
+
+var outputFormats = []string{outputFormatTable, outputFormatJSON, outputFormatYAML}
+
 // GetOptions is the start of the data required to perform the operation.  As new fields are added, add them here instead of
 // referencing the cmd.Flags()
 type GetOptions struct {
@@ -86,7 +97,9 @@ func NewCmdGet(f Factory, in terminal.FileReader, out terminal.FileWriter, errOu
 	cmd.AddCommand(NewCmdGetPostPreviewJob(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGetPreview(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGetQuickstartLocation(f, in, out, errOut))
+	cmd.AddCommand(NewCmdGetQuickstarts(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGetRelease(f, in, out, errOut))
+	cmd.AddCommand(NewCmdGetStorage(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGetTeam(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGetTeamRole(f, in, out, errOut))
 	cmd.AddCommand(NewCmdGetToken(f, in, out, errOut))
@@ -110,20 +123,32 @@ func outputEmptyListWarning(out io.Writer) error {
 
 func (o *GetOptions) addGetFlags(cmd *cobra.Command) {
 	o.Cmd = cmd
-	cmd.Flags().StringVarP(&o.Output, "output", "o", "", "The output format such as 'yaml'")
+	cmd.Flags().StringVarP(&o.Output, optionOutput, "o", "", "The output format which is one of: "+strings.Join(outputFormats, ", ")+". Defaults to "+outputFormatTable)
+}
+
+// structuredOutput returns true if the result is rendered as JSON or YAML rather than as a table
+func (o *GetOptions) structuredOutput() (bool, error) {
+	switch o.Output {
+	case "", outputFormatTable:
+		return false, nil
+	case outputFormatJSON, outputFormatYAML:
+		return true, nil
+	default:
+		return false, util.InvalidOption(optionOutput, o.Output, outputFormats)
+	}
 }
 
 // renderResult renders the result in a given output format
 func (o *GetOptions) renderResult(value interface{}, format string) error {
 	switch format {
-	case "json":
-		data, err := json.Marshal(value)
+	case outputFormatJSON:
+		data, err := json.MarshalIndent(value, "", "  ")
 		if err != nil {
 			return err
 		}
-		_, e := o.Out.Write(data)
+		_, e := o.Out.Write(append(data, '\n'))
 		return e
-	case "yaml":
+	case outputFormatYAML:
 		data, err := yaml.Marshal(value)
 		if err != nil {
 			return err
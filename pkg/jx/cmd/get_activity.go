@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"fmt"
 	"io"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ghodss/yaml"
@@ -26,16 +28,22 @@ const (
 
 // GetActivityOptions containers the CLI options
 type GetActivityOptions struct {
-	CommonOptions
+	GetOptions
 
 	Filter      string
 	BuildNumber string
+	Env         string
+	Since       time.Duration
 	Watch       bool
+	Compact     bool
 }
 
 var (
 	get_activity_long = templates.LongDesc(`
-		Display the current activities for one or more projects.
+		Display the current activities for one or more projects with the latest first.
+
+		With --watch the activities are shown as they change. With --compact one line is shown per activity, which
+		for a watch is redrawn as the running activities progress.
 `)
 
 	get_activity_example = templates.Examples(`
@@ -47,17 +55,28 @@ var (
 
 		# Watch the activities for application 'foo'
 		jx get act -f foo -w
+
+		# List the activities for application 'foo' as YAML
+		jx get act -f foo -o yaml
+
+		# Watch the running activities of the master branch of application 'foo' started in the last hour
+		jx get act -f foo/master --since 1h -w --compact
+
+		# List the activities which promoted to the production environment
+		jx get act --env production
 	`)
 )
 
 // NewCmdGetActivity creates the new command for: jx get version
 func NewCmdGetActivity(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
 	options := &GetActivityOptions{
-		CommonOptions: CommonOptions{
-			Factory: f,
-			In:      in,
-			Out:     out,
-			Err:     errOut,
+		GetOptions: GetOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
 		},
 	}
 	cmd := &cobra.Command{
@@ -73,14 +92,28 @@ func NewCmdGetActivity(f Factory, in terminal.FileReader, out terminal.FileWrite
 			CheckErr(err)
 		},
 	}
-	cmd.Flags().StringVarP(&options.Filter, "filter", "f", "", "Text to filter the pipeline names")
+	cmd.Flags().StringVarP(&options.Filter, "filter", "f", "", "Text to filter the pipeline names such as repo/branch")
 	cmd.Flags().StringVarP(&options.BuildNumber, "build", "b", "", "The build number to filter on")
+	cmd.Flags().StringVarP(&options.Env, "env", "e", "", "Only shows the activities which promote to or preview in the given environment")
+	cmd.Flags().DurationVarP(&options.Since, "since", "", 0, "Only shows the activities started within the given duration such as 1h")
 	cmd.Flags().BoolVarP(&options.Watch, "watch", "w", false, "Whether to watch the activities for changes")
+	cmd.Flags().BoolVarP(&options.Compact, "compact", "", false, "Shows one line per activity with its repository, branch, build, current step and elapsed time")
+	options.addGetFlags(cmd)
 	return cmd
 }
 
 // Run implements this command
 func (o *GetActivityOptions) Run() error {
+	structured, err := o.structuredOutput()
+	if err != nil {
+		return err
+	}
+	if structured && o.Watch {
+		return fmt.Errorf("cannot watch activities with --%s %s", optionOutput, o.Output)
+	}
+	if structured && o.Compact {
+		return fmt.Errorf("cannot use --compact with --%s %s", optionOutput, o.Output)
+	}
 	f := o.Factory
 	client, currentNs, err := f.CreateJXClient()
 	if err != nil {
@@ -122,6 +155,18 @@ func (o *GetActivityOptions) Run() error {
 	if err != nil {
 		return err
 	}
+	kube.SortActivitiesByStartTime(list.Items)
+	if structured {
+		list.Items = o.filterActivities(list.Items)
+		return o.renderResult(list, o.Output)
+	}
+	if o.Compact {
+		now := time.Now()
+		for _, activity := range o.filterActivities(list.Items) {
+			fmt.Fprintln(o.Out, compactActivityLine(&activity, now))
+		}
+		return nil
+	}
 	for _, activity := range list.Items {
 		o.addTableRow(&table, &activity)
 	}
@@ -158,7 +203,56 @@ func (o *GetActivityOptions) addTableRow(table *tbl.Table, activity *v1.Pipeline
 }
 
 func (o *GetActivityOptions) WatchActivities(table *tbl.Table, jxClient versioned.Interface, ns string) error {
+	if o.Compact {
+		return o.watchActivitiesCompact(jxClient, ns)
+	}
 	yamlSpecMap := map[string]string{}
+	o.runActivityInformer(jxClient, ns, cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			o.onActivity(table, obj, yamlSpecMap)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			o.onActivity(table, newObj, yamlSpecMap)
+		},
+		DeleteFunc: func(obj interface{}) {
+		},
+	})
+
+	// Wait forever
+	select {}
+}
+
+// watchActivitiesCompact watches the activities redrawing one line per running activity every second
+func (o *GetActivityOptions) watchActivitiesCompact(jxClient versioned.Interface, ns string) error {
+	dashboard := newActivityDashboard(o.Out)
+	onActivity := func(obj interface{}) {
+		activity, ok := obj.(*v1.PipelineActivity)
+		if ok && o.matches(activity) {
+			dashboard.onActivity(activity)
+		}
+	}
+	o.runActivityInformer(jxClient, ns, cache.ResourceEventHandlerFuncs{
+		AddFunc: onActivity,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			onActivity(newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			activity, ok := obj.(*v1.PipelineActivity)
+			if ok {
+				dashboard.onActivityDeleted(activity)
+			}
+		},
+	})
+
+	// redraw the elapsed times forever
+	for range time.Tick(time.Second) {
+		dashboard.refresh()
+	}
+	return nil
+}
+
+// runActivityInformer starts an informer of the activities of the namespace
+func (o *GetActivityOptions) runActivityInformer(jxClient versioned.Interface, ns string, handler cache.ResourceEventHandlerFuncs) {
 	activity := &v1.PipelineActivity{}
 	listWatch := cache.NewListWatchFromClient(jxClient.JenkinsV1().RESTClient(), "pipelineactivities", ns, fields.Everything())
 	kube.SortListWatchByName(listWatch)
@@ -166,23 +260,11 @@ func (o *GetActivityOptions) WatchActivities(table *tbl.Table, jxClient versione
 		listWatch,
 		activity,
 		time.Minute*10,
-		cache.ResourceEventHandlerFuncs{
-			AddFunc: func(obj interface{}) {
-				o.onActivity(table, obj, yamlSpecMap)
-			},
-			UpdateFunc: func(oldObj, newObj interface{}) {
-				o.onActivity(table, newObj, yamlSpecMap)
-			},
-			DeleteFunc: func(obj interface{}) {
-			},
-		},
+		handler,
 	)
 
 	stop := make(chan struct{})
 	go controller.Run(stop)
-
-	// Wait forever
-	select {}
 }
 
 func (o *GetActivityOptions) onActivity(table *tbl.Table, obj interface{}, yamlSpecMap map[string]string) {
@@ -212,12 +294,18 @@ func (o *CommonOptions) addStepRow(table *tbl.Table, parent *v1.PipelineActivity
 	stage := parent.Stage
 	preview := parent.Preview
 	promote := parent.Promote
+	rollback := parent.Rollback
+	scan := parent.Scan
 	if stage != nil {
 		addStageRow(table, stage, indent)
 	} else if preview != nil {
 		addPreviewRow(table, preview, indent)
 	} else if promote != nil {
 		addPromoteRow(table, promote, indent)
+	} else if rollback != nil {
+		addRollbackRow(table, rollback, indent)
+	} else if scan != nil {
+		addScanRow(table, scan, indent)
 	} else {
 		log.Warnf("Unknown step kind %#v\n", parent)
 	}
@@ -268,6 +356,25 @@ func addPromoteRow(table *tbl.Table, parent *v1.PromoteActivityStep, indent stri
 	}
 }
 
+func addRollbackRow(table *tbl.Table, parent *v1.RollbackActivityStep, indent string) {
+	description := "from " + util.ColorInfo(parent.FromVersion) + " to " + util.ColorInfo(parent.ToVersion)
+	if parent.PullRequestURL != "" {
+		description += " PullRequest: " + util.ColorInfo(parent.PullRequestURL)
+	}
+	if parent.CommitSHA != "" {
+		description += " SHA: " + util.ColorInfo(parent.CommitSHA)
+	}
+	addStepRowItem(table, &parent.CoreActivityStep, indent, "Rollback: "+parent.Environment, description)
+}
+
+func addScanRow(table *tbl.Table, parent *v1.ScanActivityStep, indent string) {
+	description := ""
+	if parent.ReportURL != "" {
+		description = "Report: " + util.ColorInfo(parent.ReportURL)
+	}
+	addStepRowItem(table, &parent.CoreActivityStep, indent, "Scan: "+parent.Image, description)
+}
+
 func addStepRowItem(table *tbl.Table, step *v1.CoreActivityStep, indent string, name string, description string) {
 	text := step.Description
 	if description != "" {
@@ -357,6 +464,17 @@ func timeToString(t *metav1.Time) string {
 	return durationString(t, now)
 }
 
+// filterActivities returns the activities which match the filter and build number
+func (o *GetActivityOptions) filterActivities(activities []v1.PipelineActivity) []v1.PipelineActivity {
+	answer := []v1.PipelineActivity{}
+	for i := range activities {
+		if o.matches(&activities[i]) {
+			answer = append(answer, activities[i])
+		}
+	}
+	return answer
+}
+
 func (o *GetActivityOptions) matches(activity *v1.PipelineActivity) bool {
 	answer := true
 	filter := o.Filter
@@ -367,5 +485,152 @@ func (o *GetActivityOptions) matches(activity *v1.PipelineActivity) bool {
 	if answer && build != "" {
 		answer = activity.Spec.Build == build
 	}
+	if answer && o.Env != "" {
+		answer = activityUsesEnvironment(activity, o.Env)
+	}
+	if answer && o.Since > 0 {
+		answer = time.Since(kube.ActivityStartTime(activity)) <= o.Since
+	}
 	return answer
 }
+
+// activityUsesEnvironment returns true if the activity promotes to, previews in or rolls back the environment
+func activityUsesEnvironment(activity *v1.PipelineActivity, env string) bool {
+	for _, step := range activity.Spec.Steps {
+		if (step.Promote != nil && step.Promote.Environment == env) ||
+			(step.Preview != nil && step.Preview.Environment == env) ||
+			(step.Rollback != nil && step.Rollback.Environment == env) {
+			return true
+		}
+	}
+	return false
+}
+
+// activityDashboard shows one line per running activity which is redrawn in place as the activities change. The line
+// of an activity which completes is left above the running activities
+type activityDashboard struct {
+	out     io.Writer
+	now     func() time.Time
+	lock    sync.Mutex
+	running map[string]*v1.PipelineActivity
+	lines   int
+}
+
+func newActivityDashboard(out io.Writer) *activityDashboard {
+	return &activityDashboard{
+		out:     out,
+		now:     time.Now,
+		running: map[string]*v1.PipelineActivity{},
+	}
+}
+
+func (d *activityDashboard) onActivity(activity *v1.PipelineActivity) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.clear()
+	name := activity.Name
+	if activity.Spec.Status.IsTerminated() {
+		// only the activities which complete while watching are shown
+		if d.running[name] != nil {
+			delete(d.running, name)
+			fmt.Fprintln(d.out, compactActivityLine(activity, d.now()))
+		}
+	} else {
+		d.running[name] = activity
+	}
+	d.draw()
+}
+
+func (d *activityDashboard) onActivityDeleted(activity *v1.PipelineActivity) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.clear()
+	delete(d.running, activity.Name)
+	d.draw()
+}
+
+// refresh redraws the lines of the running activities to update their elapsed times
+func (d *activityDashboard) refresh() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.clear()
+	d.draw()
+}
+
+// clear erases the lines of the running activities
+func (d *activityDashboard) clear() {
+	for ; d.lines > 0; d.lines-- {
+		// move the cursor up a line and erase it
+		fmt.Fprint(d.out, "\033[1A\033[2K")
+	}
+}
+
+func (d *activityDashboard) draw() {
+	activities := []v1.PipelineActivity{}
+	for _, activity := range d.running {
+		activities = append(activities, *activity)
+	}
+	kube.SortActivitiesByStartTime(activities)
+	now := d.now()
+	for i := range activities {
+		fmt.Fprintln(d.out, compactActivityLine(&activities[i], now))
+	}
+	d.lines = len(activities)
+}
+
+// compactActivityLine returns the line of an activity with its repository, branch, build number, current step, elapsed
+// time and status
+func compactActivityLine(activity *v1.PipelineActivity, now time.Time) string {
+	repository := activity.Spec.Pipeline
+	branch := ""
+	d := kube.CreatePipelineDetails(activity)
+	if d.GitOwner != "" && d.GitRepository != "" {
+		repository = d.GitOwner + "/" + d.GitRepository
+		branch = d.BranchName
+	}
+	end := now
+	completed := activity.Spec.CompletedTimestamp
+	if activity.Spec.Status.IsTerminated() && completed != nil {
+		end = completed.Time
+	}
+	elapsed := end.Sub(kube.ActivityStartTime(activity)).Round(time.Second)
+	return fmt.Sprintf("%-40s %-20s %-6s %-30s %8s %s", repository, branch, "#"+activity.Spec.Build,
+		currentStepName(activity), elapsed.String(), statusString(activity.Spec.Status))
+}
+
+// currentStepName returns the name of the last running step of the activity or of its last started step if none are
+// running
+func currentStepName(activity *v1.PipelineActivity) string {
+	running := ""
+	started := ""
+	onStep := func(step *v1.CoreActivityStep, name string) {
+		if step.Status == v1.ActivityStatusTypeRunning {
+			running = name
+		} else if step.StartedTimestamp != nil {
+			started = name
+		}
+	}
+	for _, step := range activity.Spec.Steps {
+		if stage := step.Stage; stage != nil {
+			onStep(&stage.CoreActivityStep, stage.Name)
+			for i := range stage.Steps {
+				onStep(&stage.Steps[i], stage.Name+" / "+stage.Steps[i].Name)
+			}
+		} else if preview := step.Preview; preview != nil {
+			onStep(&preview.CoreActivityStep, "Preview")
+		} else if promote := step.Promote; promote != nil {
+			onStep(&promote.CoreActivityStep, "Promote: "+promote.Environment)
+		} else if rollback := step.Rollback; rollback != nil {
+			onStep(&rollback.CoreActivityStep, "Rollback: "+rollback.Environment)
+		} else if scan := step.Scan; scan != nil {
+			onStep(&scan.CoreActivityStep, "Scan: "+scan.Image)
+		}
+	}
+	if running != "" {
+		return running
+	}
+	return started
+}
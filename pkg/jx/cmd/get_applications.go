@@ -16,17 +16,19 @@ import (
 	"github.com/jenkins-x/jx/pkg/util"
 	"k8s.io/api/apps/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 // GetApplicationsOptions containers the CLI options
 type GetApplicationsOptions struct {
-	CommonOptions
+	GetOptions
 
 	Namespace   string
 	Environment string
 	HideUrl     bool
 	HidePod     bool
 	Previews    bool
+	Versions    bool
 }
 
 var (
@@ -52,17 +54,25 @@ var (
 
 		# List applications just showing the versions (hiding urls and pod counts)
 		jx get apps -u -p
+
+		# List the applications in the Production environment as JSON
+		jx get apps -e production -o json
+
+		# List the apps added to the development environment with their current and latest available versions
+		jx get apps --versions
 	`)
 )
 
 // NewCmdGetApplications creates the new command for: jx get version
 func NewCmdGetApplications(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
 	options := &GetApplicationsOptions{
-		CommonOptions: CommonOptions{
-			Factory: f,
-			In:      in,
-			Out:     out,
-			Err:     errOut,
+		GetOptions: GetOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
 		},
 	}
 	cmd := &cobra.Command{
@@ -83,16 +93,50 @@ func NewCmdGetApplications(f Factory, in terminal.FileReader, out terminal.FileW
 	cmd.Flags().BoolVarP(&options.Previews, "preview", "w", false, "Show preview environments only")
 	cmd.Flags().StringVarP(&options.Environment, "env", "e", "", "Filter applications in the given environment")
 	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "Filter applications in the given namespace")
+	cmd.Flags().BoolVarP(&options.Versions, "versions", "", false, "Show the current and latest available versions of the apps added to the development environment")
+	options.addGetFlags(cmd)
 	return cmd
 }
 
+// ApplicationInfo an application and the environments it is deployed in
+type ApplicationInfo struct {
+	Name         string                       `json:"name"`
+	Environments []ApplicationEnvironmentInfo `json:"environments"`
+}
+
+// ApplicationEnvironmentInfo the deployment of an application in an environment. The pod counts and URL are omitted
+// when they are hidden with --pod and --url
+type ApplicationEnvironmentInfo struct {
+	Environment   string `json:"environment"`
+	Namespace     string `json:"namespace"`
+	Version       string `json:"version,omitempty"`
+	Replicas      *int32 `json:"replicas,omitempty"`
+	ReadyReplicas *int32 `json:"readyReplicas,omitempty"`
+	URL           string `json:"url,omitempty"`
+	Canary        string `json:"canary,omitempty"`
+}
+
+// ApplicationInfoList the applications output by 'jx get applications -o json|yaml'
+type ApplicationInfoList struct {
+	Items []ApplicationInfo `json:"items"`
+}
+
 type EnvApps struct {
 	Environment v1.Environment
 	Apps        map[string]v1beta1.Deployment
+	Canaries    map[string]string
+	KubeClient  kubernetes.Interface
 }
 
 // Run implements this command
 func (o *GetApplicationsOptions) Run() error {
+	structured, err := o.structuredOutput()
+	if err != nil {
+		return err
+	}
+	if o.Versions {
+		return o.appVersions(structured)
+	}
 	f := o.Factory
 	client, currentNs, err := f.CreateJXClient()
 	if err != nil {
@@ -133,15 +177,27 @@ func (o *GetApplicationsOptions) Run() error {
 			namespaces = append(namespaces, ens)
 			if ens != "" && env.Name != kube.LabelValueDevEnvironment {
 				envNames = append(envNames, env.Name)
-				m, err := kube.GetDeployments(kubeClient, ens)
+				envKubeClient, err := o.EnvironmentKubeClient(&env)
+				if err != nil {
+					log.Warnf("Could not list the applications in environment %s: %s\n", env.Name, err)
+					continue
+				}
+				m, err := kube.GetDeployments(envKubeClient, ens)
 				if err == nil {
 					envApp := EnvApps{
 						Environment: env,
 						Apps:        map[string]v1beta1.Deployment{},
+						Canaries:    map[string]string{},
+						KubeClient:  envKubeClient,
 					}
 					envApps = append(envApps, envApp)
+					isCanary := kube.IsCanaryEnvironment(&env)
 					for k, d := range m {
 						appName := kube.GetAppName(k, ens)
+						if isCanary && strings.HasSuffix(appName, kube.CanaryPrimarySuffix) {
+							// the primary Deployment Flagger creates for the canary of the application
+							continue
+						}
 						if env.Spec.Kind == v1.EnvironmentKindTypeEdit {
 							if appName == kube.DeploymentExposecontrollerService || env.Spec.PreviewGitSpec.User.Username != u.Username {
 								continue
@@ -154,17 +210,27 @@ func (o *GetApplicationsOptions) Run() error {
 						if util.StringArrayIndex(apps, appName) < 0 {
 							apps = append(apps, appName)
 						}
+						if isCanary {
+							canary, err := kube.GetCanary(envKubeClient, ens, appName)
+							if err != nil {
+								log.Warnf("Could not find the canary of %s in environment %s: %s\n", appName, env.Name, err)
+							}
+							envApp.Canaries[appName] = kube.CanaryProgress(canary)
+						}
 					}
 				}
 			}
 		}
 	}
 	util.ReverseStrings(namespaces)
+	sort.Strings(apps)
+	if structured {
+		return o.renderResult(o.applicationInfos(apps, envApps), o.Output)
+	}
 	if len(apps) == 0 {
 		log.Infof("No applications found in environments %s\n", strings.Join(envNames, ", "))
 		return nil
 	}
-	sort.Strings(apps)
 
 	table := o.CreateTable()
 	title := "APPLICATION"
@@ -180,6 +246,9 @@ func (o *GetApplicationsOptions) Run() error {
 		if ea.Environment.Spec.Kind != v1.EnvironmentKindTypePreview {
 			titles = append(titles, strings.ToUpper(envName))
 		}
+		if kube.IsCanaryEnvironment(&ea.Environment) {
+			titles = append(titles, "CANARY")
+		}
 		if !o.HidePod {
 			titles = append(titles, "PODS")
 		}
@@ -198,6 +267,9 @@ func (o *GetApplicationsOptions) Run() error {
 			if ea.Environment.Spec.Kind != v1.EnvironmentKindTypePreview {
 				row = append(row, version)
 			}
+			if kube.IsCanaryEnvironment(&ea.Environment) {
+				row = append(row, ea.Canaries[appName])
+			}
 			if !o.HidePod {
 				pods := ""
 				replicas := ""
@@ -209,24 +281,7 @@ func (o *GetApplicationsOptions) Run() error {
 				row = append(row, pods)
 			}
 			if !o.HideUrl {
-				url, _ := kube.FindServiceURL(kubeClient, d.Namespace, appName)
-				if url == "" {
-					url, _ = kube.FindServiceURL(kubeClient, d.Namespace, d.Name)
-				}
-				if url == "" {
-					// handle helm3
-					chart := d.Labels["chart"]
-					if chart != "" {
-						idx := strings.LastIndex(chart, "-")
-						if idx > 0 {
-							svcName := chart[0:idx]
-							if svcName != appName && svcName != d.Name {
-								url, _ = kube.FindServiceURL(kubeClient, d.Namespace, svcName)
-							}
-						}
-					}
-				}
-				row = append(row, url)
+				row = append(row, applicationURL(ea.KubeClient, appName, &d))
 			}
 		}
 		table.AddRow(row...)
@@ -234,3 +289,104 @@ func (o *GetApplicationsOptions) Run() error {
 	table.Render()
 	return nil
 }
+
+// appVersions lists the apps of the development environment with their current and latest available versions
+func (o *GetApplicationsOptions) appVersions(structured bool) error {
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	jxClient, _, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	env, err := kube.GetEnvironment(jxClient, ns, kube.LabelValueDevEnvironment)
+	if err != nil {
+		return err
+	}
+	apps, _, err := o.devEnvironmentApps(kubeClient, ns, env)
+	if err != nil {
+		return err
+	}
+	err = o.findLatestAppVersions(apps, true)
+	if err != nil {
+		return err
+	}
+	if structured {
+		return o.renderResult(AppVersionList{Items: apps}, o.Output)
+	}
+	if len(apps) == 0 {
+		log.Infof("No apps found in the development environment\n")
+		return nil
+	}
+
+	table := o.CreateTable()
+	table.AddRow("APP", "CHART", "VERSION", "LATEST", "REPOSITORY")
+	for _, app := range apps {
+		latest := app.LatestVersion
+		if latest != "" && latest != app.Version {
+			latest = util.ColorWarning(latest)
+		}
+		table.AddRow(app.Name, app.Chart, app.Version, latest, app.Repository)
+	}
+	table.Render()
+	return nil
+}
+
+// applicationInfos returns the applications and the environments they are deployed in
+func (o *GetApplicationsOptions) applicationInfos(apps []string, envApps []EnvApps) ApplicationInfoList {
+	answer := ApplicationInfoList{
+		Items: []ApplicationInfo{},
+	}
+	for _, appName := range apps {
+		info := ApplicationInfo{
+			Name:         appName,
+			Environments: []ApplicationEnvironmentInfo{},
+		}
+		for _, ea := range envApps {
+			d, ok := ea.Apps[appName]
+			if !ok {
+				continue
+			}
+			envInfo := ApplicationEnvironmentInfo{
+				Environment: ea.Environment.Name,
+				Namespace:   ea.Environment.Spec.Namespace,
+				Version:     kube.GetVersion(&d.ObjectMeta),
+				Canary:      ea.Canaries[appName],
+			}
+			if !o.HidePod {
+				ready := d.Status.ReadyReplicas
+				envInfo.ReadyReplicas = &ready
+				envInfo.Replicas = d.Spec.Replicas
+			}
+			if !o.HideUrl && ea.KubeClient != nil {
+				envInfo.URL = applicationURL(ea.KubeClient, appName, &d)
+			}
+			info.Environments = append(info.Environments, envInfo)
+		}
+		answer.Items = append(answer.Items, info)
+	}
+	return answer
+}
+
+// applicationURL returns the URL of the service of the application deployment if it has one
+func applicationURL(kubeClient kubernetes.Interface, appName string, d *v1beta1.Deployment) string {
+	url, _ := kube.FindServiceURL(kubeClient, d.Namespace, appName)
+	if url == "" {
+		url, _ = kube.FindServiceURL(kubeClient, d.Namespace, d.Name)
+	}
+	if url == "" {
+		// handle helm3
+		chart := d.Labels["chart"]
+		if chart != "" {
+			idx := strings.LastIndex(chart, "-")
+			if idx > 0 {
+				svcName := chart[0:idx]
+				if svcName != appName && svcName != d.Name {
+					url, _ = kube.FindServiceURL(kubeClient, d.Namespace, svcName)
+				}
+			}
+		}
+	}
+	return url
+}
@@ -6,11 +6,13 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jenkins-x/golang-jenkins"
 	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx/pkg/builds"
 	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/cloud/buckets"
 	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
@@ -21,6 +23,7 @@ import (
 	"github.com/jenkins-x/jx/pkg/util"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -28,20 +31,37 @@ import (
 type GetBuildLogsOptions struct {
 	GetOptions
 
-	Tail   bool
-	Filter string
-	Build  int
+	Tail      bool
+	Filter    string
+	Build     int
+	Stage     string
+	Container string
+
+	// the functions which read the logs which tests replace
+	streamLogs      func(ns string, pod string, container string, follow bool, out io.Writer) error
+	readArchivedLog func(url string) ([]byte, error)
+	pollPeriod      time.Duration
 }
 
 var (
 	get_build_log_long = templates.LongDesc(`
-		Display the Git server URLs.
+		Display the log of a pipeline build.
 
+		With the prow webhook engine the logs of the stages of the pipeline are shown in order from the containers of
+		its pods. The logs of the later stages are followed as they run until the pipeline completes unless --tail=false. The
+		logs of stages whose pods have been deleted are read from the archived build log in the logs storage location
+		of the team.
 `)
 
 	get_build_log_example = templates.Examples(`
-		# List all registered Git server URLs
-		jx get git
+		# Follow the log of the last build of a pipeline until it completes
+		jx get build logs myorg/myrepo/master
+
+		# Display the current log of the last build of a pipeline without following it
+		jx get build logs myorg/myrepo/master --tail=false
+
+		# Display the log of a stage of a given build
+		jx get build logs myorg/myrepo/master --build 3 --stage "Git Source"
 	`)
 )
 
@@ -75,6 +95,8 @@ func NewCmdGetBuildLogs(f Factory, in terminal.FileReader, out terminal.FileWrit
 	cmd.Flags().BoolVarP(&options.Tail, "tail", "t", true, "Tails the build log to the current terminal")
 	cmd.Flags().StringVarP(&options.Filter, "filter", "f", "", "Filters all the available jobs by those that contain the given text")
 	cmd.Flags().IntVarP(&options.Build, "build", "b", 0, "The build number to view")
+	cmd.Flags().StringVarP(&options.Stage, "stage", "", "", "Only shows the log of the stage with the given name")
+	cmd.Flags().StringVarP(&options.Container, "container", "", "", "Only shows the log of the container with the given name")
 
 	return cmd
 }
@@ -93,7 +115,22 @@ func (o *GetBuildLogsOptions) Run() error {
 	devEnv, err := kube.GetEnrichedDevEnvironment(kubeClient, jxClient, ns)
 	webhookEngine := devEnv.Spec.WebHookEngine
 	if webhookEngine == v1.WebHookEngineProw {
-		return o.getProwBuildLog(kubeClient, jxClient, ns)
+		logsBucketURL := ""
+		location := devEnv.Spec.TeamSettings.StorageLocation(StorageClassifierLogs)
+		if location != nil {
+			logsBucketURL = location.BucketURL
+		}
+		if o.streamLogs == nil {
+			o.streamLogs = func(ns string, pod string, container string, follow bool, out io.Writer) error {
+				return kube.StreamContainerLogs(kubeClient, ns, pod, container, follow, out)
+			}
+		}
+		if o.readArchivedLog == nil {
+			o.readArchivedLog = func(url string) ([]byte, error) {
+				return buckets.ReadObject(url, "", "")
+			}
+		}
+		return o.getProwBuildLog(kubeClient, jxClient, ns, logsBucketURL)
 	}
 	jobMap, err := o.getJobMap(o.Filter)
 	if err != nil {
@@ -146,7 +183,7 @@ func (o *GetBuildLogsOptions) Run() error {
 	return o.tailBuild(name, &last)
 }
 
-func (o *GetBuildLogsOptions) getProwBuildLog(kubeClient kubernetes.Interface, jxClient versioned.Interface, ns string) error {
+func (o *GetBuildLogsOptions) getProwBuildLog(kubeClient kubernetes.Interface, jxClient versioned.Interface, ns string, logsBucketURL string) error {
 	activities := jxClient.JenkinsV1().PipelineActivities(ns)
 	pipelineList, err := activities.List(metav1.ListOptions{})
 	if err != nil {
@@ -227,31 +264,159 @@ func (o *GetBuildLogsOptions) getProwBuildLog(kubeClient kubernetes.Interface, j
 	}
 	log.Infof("Getting the log of pipeline %s build %s\n", util.ColorInfo(name), util.ColorInfo("#"+strconv.Itoa(buildNumber)))
 
-	pods, err := builds.GetBuildPods(kubeClient, ns)
-	if err != nil {
-		return err
+	return o.streamPipelineLogs(kubeClient, jxClient, ns, build, logsBucketURL)
+}
+
+// streamPipelineLogs shows the logs of the containers of the pods of the pipeline in the order in which they run,
+// following them until the pipeline terminates if required, then the archived logs of the stages whose pods are gone
+func (o *GetBuildLogsOptions) streamPipelineLogs(kubeClient kubernetes.Interface, jxClient versioned.Interface, ns string, activity *v1.PipelineActivity, logsBucketURL string) error {
+	pollPeriod := o.pollPeriod
+	if pollPeriod == 0 {
+		pollPeriod = 2 * time.Second
 	}
+	activities := jxClient.JenkinsV1().PipelineActivities(ns)
+	shownContainers := map[string]bool{}
+	shownStages := map[string]bool{}
+	waiting := false
+	for {
+		latest, err := activities.Get(activity.Name, metav1.GetOptions{})
+		if err == nil {
+			activity = latest
+		} else if !apierrors.IsNotFound(err) {
+			return err
+		}
+		terminated := activity.Spec.Status.IsTerminated()
 
-	for _, pod := range pods {
-		log.Infof("found pod %s\n", pod.Name)
-		initContainers := pod.Spec.InitContainers
-		if len(initContainers) > 0 {
-			lastInitC := initContainers[len(initContainers)-1]
-			params := BuildParams{}
-			params.DefaultValuesFromEnvVars(lastInitC.Env)
+		pods, err := pipelinePods(kubeClient, ns, activity)
+		if err != nil {
+			return err
+		}
+		progressed := false
+		for _, c := range builds.PipelineContainers(pods) {
+			if !o.matchesContainer(c) {
+				continue
+			}
+			status := c.Status()
+			if status == nil || status.State.Waiting != nil {
+				if c.Init {
+					// the later init containers of the pod have not started either
+					break
+				}
+				continue
+			}
+			key := c.Key()
+			if shownContainers[key] {
+				continue
+			}
+			follow := o.Tail && status.State.Running != nil
+			fmt.Fprintln(o.Out, builds.StageHeader(c.Stage))
+			err = o.streamLogs(ns, c.Pod.Name, c.Name, follow, o.Out)
+			if err != nil {
+				log.Warnf("%s\n", err)
+			}
+			shownContainers[key] = true
+			shownStages[c.Stage] = true
+			progressed = true
+			if follow {
+				// the statuses of the later containers have changed while following this one
+				break
+			}
+		}
+		if !o.Tail || terminated {
+			break
+		}
+		if !progressed {
+			if len(pods) == 0 && !waiting {
+				log.Infof("Waiting for the pods of pipeline %s build %s\n", util.ColorInfo(activity.Spec.Pipeline), util.ColorInfo("#"+activity.Spec.Build))
+				waiting = true
+			}
+			time.Sleep(pollPeriod)
+		}
+	}
+	return o.showArchivedLogs(activity, logsBucketURL, shownStages)
+}
 
-			if params.MatchesPipeline(build) {
-				return o.getPodLog(ns, pod, lastInitC)
+// showArchivedLogs shows the logs of the terminated stages of the pipeline which were not shown from the pods
+func (o *GetBuildLogsOptions) showArchivedLogs(activity *v1.PipelineActivity, logsBucketURL string, shownStages map[string]bool) error {
+	missing := []string{}
+	for _, step := range activity.Spec.Steps {
+		stage := step.Stage
+		if stage != nil && !shownStages[stage.Name] && stage.Status.IsTerminated() && o.matchesStage(stage.Name) {
+			missing = append(missing, stage.Name)
+		}
+	}
+	if len(missing) == 0 && len(shownStages) > 0 {
+		return nil
+	}
+	name := activity.Spec.Pipeline
+	build := "#" + activity.Spec.Build
+	for _, url := range builds.ArchivedLogURLs(activity, logsBucketURL) {
+		data, err := o.readArchivedLog(url)
+		if err != nil {
+			if o.Verbose {
+				log.Warnf("%s\n", err)
+			}
+			continue
+		}
+		log.Infof("Showing the archived log %s\n", util.ColorInfo(url))
+		for _, stageLog := range builds.SplitStageLogs(string(data)) {
+			if stageLog.Stage == "" {
+				// an archived log without stage headers cannot be filtered
+				if len(shownStages) == 0 && o.Stage == "" && o.Container == "" {
+					fmt.Fprintln(o.Out, stageLog.Log)
+				}
+				continue
+			}
+			if shownStages[stageLog.Stage] || !o.matchesStage(stageLog.Stage) {
+				continue
 			}
+			fmt.Fprintln(o.Out, builds.StageHeader(stageLog.Stage))
+			fmt.Fprintln(o.Out, stageLog.Log)
 		}
+		return nil
+	}
+	if len(missing) > 0 {
+		log.Warnf("The pods of the stages %s of pipeline %s build %s are gone and no archived log was found\n",
+			util.ColorInfo(strings.Join(missing, ", ")), util.ColorInfo(name), util.ColorInfo(build))
+	} else if len(shownStages) == 0 {
+		log.Warnf("No pod is available for pipeline %s build %s\n", util.ColorInfo(name), util.ColorInfo(build))
 	}
-	log.Warnf("No pod is available for pipeline %s build %s\n", util.ColorInfo(name), util.ColorInfo("#"+strconv.Itoa(buildNumber)))
 	return nil
 }
 
-func (o *GetBuildLogsOptions) getPodLog(ns string, pod *corev1.Pod, container corev1.Container) error {
-	log.Infof("Getting the pod log for pod %s and init container %s\n", pod.Name, container.Name)
-	return o.tailLogs(ns, pod.Name, container.Name)
+// matchesContainer returns true if the container matches the --stage and --container filters
+func (o *GetBuildLogsOptions) matchesContainer(c *builds.PipelineContainer) bool {
+	return builds.MatchesStage(c.Stage, o.Stage) && builds.MatchesStage(c.Name, o.Container)
+}
+
+// matchesStage returns true if the stage of an activity or an archived log matches the --stage and --container
+// filters, the stages being named after their containers
+func (o *GetBuildLogsOptions) matchesStage(stage string) bool {
+	return builds.MatchesStage(stage, o.Stage) && builds.MatchesStage(stage, o.Container)
+}
+
+// pipelinePods returns the build pods of the pipeline, there being more than one if a stage runs in another pod or
+// the pod of the pipeline was recreated
+func pipelinePods(kubeClient kubernetes.Interface, ns string, activity *v1.PipelineActivity) ([]*corev1.Pod, error) {
+	pods, err := builds.GetBuildPods(kubeClient, ns)
+	if err != nil {
+		return nil, err
+	}
+	answer := []*corev1.Pod{}
+	for _, pod := range pods {
+		containers := pod.Spec.InitContainers
+		if len(containers) == 0 {
+			containers = pod.Spec.Containers
+		}
+		if len(containers) > 0 {
+			params := BuildParams{}
+			params.DefaultValuesFromEnvVars(containers[len(containers)-1].Env)
+			if params.MatchesPipeline(activity) {
+				answer = append(answer, pod)
+			}
+		}
+	}
+	return answer, nil
 }
 
 type BuildParams struct {
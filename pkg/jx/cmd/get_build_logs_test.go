@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/builds"
+	jxfake "github.com/jenkins-x/jx/pkg/client/clientset/versioned/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetProwBuildLogStitchesStages(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	activity := buildLogsTestActivity(v1.ActivityStatusTypeSucceeded, "Git Source", "Build", "Promote")
+	pod1 := buildLogsTestPod("pod1", now, "build-step-git-source", "build-step-build")
+	pod2 := buildLogsTestPod("pod2", now.Add(time.Minute), "build-step-build")
+	setBuildLogsTestStatus(pod1, "build-step-git-source", terminatedState())
+	setBuildLogsTestStatus(pod1, "build-step-build", terminatedState())
+	setBuildLogsTestStatus(pod2, "build-step-build", terminatedState())
+
+	kubeClient := fake.NewSimpleClientset(pod2, pod1)
+	jxClient := jxfake.NewSimpleClientset(activity)
+	out := buildLogsTestOutput(t)
+	defer os.Remove(out.Name())
+	archived := builds.StageHeader("Git Source") + "\narchived clone\n" + builds.StageHeader("Promote") + "\narchived promote\n"
+	o := buildLogsTestOptions(out, archived)
+
+	err := o.getProwBuildLog(kubeClient, jxClient, "jx", "gs://logs")
+	require.NoError(t, err)
+	assert.Equal(t, builds.StageHeader("Git Source")+"\nlog of pod1/build-step-git-source\n"+
+		builds.StageHeader("Build")+"\nlog of pod1/build-step-build\n"+
+		builds.StageHeader("Build")+"\nlog of pod2/build-step-build\n"+
+		builds.StageHeader("Promote")+"\narchived promote\n\n", readBuildLogsTestOutput(t, out))
+
+	o.Stage = "build"
+	err = o.getProwBuildLog(kubeClient, jxClient, "jx", "gs://logs")
+	require.NoError(t, err)
+	assert.Equal(t, builds.StageHeader("Build")+"\nlog of pod1/build-step-build\n"+
+		builds.StageHeader("Build")+"\nlog of pod2/build-step-build\n", readBuildLogsTestOutput(t, out))
+
+	o.Stage = ""
+	o.Container = "build-step-git-source"
+	err = o.getProwBuildLog(kubeClient, jxClient, "jx", "gs://logs")
+	require.NoError(t, err)
+	assert.Equal(t, builds.StageHeader("Git Source")+"\nlog of pod1/build-step-git-source\n", readBuildLogsTestOutput(t, out))
+}
+
+func TestGetProwBuildLogFollowsUntilTerminated(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	activity := buildLogsTestActivity(v1.ActivityStatusTypeRunning, "Git Source", "Build")
+	pod := buildLogsTestPod("pod1", now, "build-step-git-source", "build-step-build")
+	setBuildLogsTestStatus(pod, "build-step-git-source", corev1.ContainerState{Running: &corev1.ContainerStateRunning{}})
+	setBuildLogsTestStatus(pod, "build-step-build", corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{}})
+
+	kubeClient := fake.NewSimpleClientset(pod)
+	jxClient := jxfake.NewSimpleClientset(activity)
+	out := buildLogsTestOutput(t)
+	defer os.Remove(out.Name())
+	o := buildLogsTestOptions(out, "")
+	o.Tail = true
+	streamLogs := o.streamLogs
+	o.streamLogs = func(ns string, podName string, container string, follow bool, out io.Writer) error {
+		if container == "build-step-git-source" {
+			assert.True(t, follow, "the running container should be followed")
+			// the stage completes while it is followed and the pipeline completes along with the next stage
+			setBuildLogsTestStatus(pod, "build-step-git-source", terminatedState())
+			setBuildLogsTestStatus(pod, "build-step-build", terminatedState())
+			_, err := kubeClient.CoreV1().Pods(ns).Update(pod)
+			require.NoError(t, err)
+			activity.Spec.Status = v1.ActivityStatusTypeSucceeded
+			_, err = jxClient.JenkinsV1().PipelineActivities(ns).Update(activity)
+			require.NoError(t, err)
+		}
+		return streamLogs(ns, podName, container, follow, out)
+	}
+
+	err := o.getProwBuildLog(kubeClient, jxClient, "jx", "")
+	require.NoError(t, err)
+	assert.Equal(t, builds.StageHeader("Git Source")+"\nlog of pod1/build-step-git-source\n"+
+		builds.StageHeader("Build")+"\nlog of pod1/build-step-build\n", readBuildLogsTestOutput(t, out))
+}
+
+func buildLogsTestOutput(t *testing.T) *os.File {
+	file, err := ioutil.TempFile("", "test-get-build-logs")
+	require.NoError(t, err)
+	return file
+}
+
+// readBuildLogsTestOutput returns the output written to the file so far and truncates it
+func readBuildLogsTestOutput(t *testing.T, file *os.File) string {
+	data, err := ioutil.ReadFile(file.Name())
+	require.NoError(t, err)
+	require.NoError(t, file.Truncate(0))
+	_, err = file.Seek(0, 0)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestGetBuildLogsFlags(t *testing.T) {
+	t.Parallel()
+	cmd := NewCmdGetBuildLogs(nil, os.Stdin, os.Stdout, os.Stderr)
+	assert.Equal(t, "filter", cmd.Flags().ShorthandLookup("f").Name, "-f should filter the jobs")
+	assert.Equal(t, "true", cmd.Flags().Lookup("tail").DefValue, "the logs should be followed by default")
+}
+
+func buildLogsTestOptions(out *os.File, archived string) *GetBuildLogsOptions {
+	o := &GetBuildLogsOptions{
+		pollPeriod: time.Millisecond,
+		streamLogs: func(ns string, pod string, container string, follow bool, out io.Writer) error {
+			_, err := fmt.Fprintf(out, "log of %s/%s\n", pod, container)
+			return err
+		},
+		readArchivedLog: func(url string) ([]byte, error) {
+			if url != "gs://logs/jenkins-x/logs/myorg/myrepo/master/1.log" {
+				return nil, fmt.Errorf("no object %s", url)
+			}
+			return []byte(archived), nil
+		},
+	}
+	o.Out = out
+	o.Args = []string{"myorg/myrepo/master"}
+	return o
+}
+
+func buildLogsTestActivity(status v1.ActivityStatusType, stages ...string) *v1.PipelineActivity {
+	activity := &v1.PipelineActivity{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myorg-myrepo-master-1",
+			Namespace: "jx",
+		},
+		Spec: v1.PipelineActivitySpec{
+			Pipeline: "myorg/myrepo/master",
+			Build:    "1",
+			Status:   status,
+		},
+	}
+	for _, stage := range stages {
+		activity.Spec.Steps = append(activity.Spec.Steps, v1.PipelineActivityStep{
+			Kind: v1.ActivityStepKindTypeStage,
+			Stage: &v1.StageActivityStep{
+				CoreActivityStep: v1.CoreActivityStep{
+					Name:   stage,
+					Status: v1.ActivityStatusTypeSucceeded,
+				},
+			},
+		})
+	}
+	return activity
+}
+
+func buildLogsTestPod(name string, created time.Time, containers ...string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "jx",
+			UID:               types.UID(name),
+			CreationTimestamp: metav1.NewTime(created),
+			Labels: map[string]string{
+				builds.LabelBuildName: "myorg-myrepo-master-1",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "nop"}},
+		},
+	}
+	for _, c := range containers {
+		pod.Spec.InitContainers = append(pod.Spec.InitContainers, corev1.Container{
+			Name: c,
+			Env: []corev1.EnvVar{
+				{Name: "REPO_OWNER", Value: "myorg"},
+				{Name: "REPO_NAME", Value: "myrepo"},
+				{Name: "BRANCH_NAME", Value: "master"},
+				{Name: "BUILD_NUMBER", Value: "1"},
+			},
+		})
+	}
+	return pod
+}
+
+func setBuildLogsTestStatus(pod *corev1.Pod, container string, state corev1.ContainerState) {
+	for i := range pod.Status.InitContainerStatuses {
+		if pod.Status.InitContainerStatuses[i].Name == container {
+			pod.Status.InitContainerStatuses[i].State = state
+			return
+		}
+	}
+	pod.Status.InitContainerStatuses = append(pod.Status.InitContainerStatuses, corev1.ContainerStatus{
+		Name:  container,
+		State: state,
+	})
+}
+
+func terminatedState() corev1.ContainerState {
+	return corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}}
+}
@@ -79,22 +79,36 @@ func (o *GetDevPodOptions) Run() error {
 	}
 
 	names, m, err := kube.GetDevPodNames(client, ns, u.Username)
+	if err != nil {
+		return err
+	}
+
+	timeout, err := o.TeamDevPodIdleTimeout()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
 
 	table := o.CreateTable()
-	table.AddRow("NAME", "POD TEMPLATE", "AGE", "STATUS")
+	table.AddRow("NAME", "POD TEMPLATE", "IMAGE", "AGE", "IDLE", "STATUS", "WARNING")
 
 	for _, k := range names {
 		pod := m[k]
 		if pod != nil {
 			podTemplate := ""
+			image := ""
 			status := kube.PodStatus(pod)
 			labels := pod.Labels
-			d := time.Now().Sub(pod.CreationTimestamp.Time).Round(time.Second)
+			d := now.Sub(pod.CreationTimestamp.Time).Round(time.Second)
 			age := d.String()
+			idle, warning := devPodIdleAndWarning(pod, timeout, now)
 			if labels != nil {
 				podTemplate = labels[kube.LabelPodTemplate]
 			}
-			table.AddRow(k, podTemplate, age, status)
+			if len(pod.Spec.Containers) > 0 {
+				image = pod.Spec.Containers[0].Image
+			}
+			table.AddRow(k, podTemplate, image, age, idle, status, warning)
 		}
 	}
 
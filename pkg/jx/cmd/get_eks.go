@@ -77,7 +77,7 @@ func (o *GetEksOptions) Run() error {
 		if d != "" {
 			deps = append(deps, d)
 		}
-		d = binaryShouldBeInstalled("heptio-authenticator-aws")
+		d = awsIamAuthenticatorShouldBeInstalled()
 		if d != "" {
 			deps = append(deps, d)
 		}
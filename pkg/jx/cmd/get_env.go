@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
@@ -72,11 +73,11 @@ func NewCmdGetEnv(f Factory, in terminal.FileReader, out terminal.FileWriter, er
 
 // Run implements this command
 func (o *GetEnvOptions) Run() error {
-	client, ns, err := o.JXClientAndDevNamespace()
+	structured, err := o.structuredOutput()
 	if err != nil {
 		return err
 	}
-	kubeClient, _, err := o.KubeClient()
+	client, ns, err := o.JXClientAndDevNamespace()
 	if err != nil {
 		return err
 	}
@@ -101,6 +102,10 @@ func (o *GetEnvOptions) Run() error {
 			return util.InvalidArg(e, envNames)
 		}
 
+		if structured {
+			return o.renderResult(env, o.Output)
+		}
+
 		// lets output one environment
 		spec := &env.Spec
 
@@ -112,7 +117,11 @@ func (o *GetEnvOptions) Run() error {
 
 		ens := env.Spec.Namespace
 		if ens != "" {
-			deps, err := kubeClient.AppsV1beta1().Deployments(ens).List(metav1.ListOptions{})
+			envKubeClient, err := o.EnvironmentKubeClient(env)
+			if err != nil {
+				return err
+			}
+			deps, err := envKubeClient.AppsV1beta1().Deployments(ens).List(metav1.ListOptions{})
 			if err != nil {
 				return fmt.Errorf("Could not find deployments in namespace %s: %s", ens, err)
 			}
@@ -133,7 +142,7 @@ func (o *GetEnvOptions) Run() error {
 		if err != nil {
 			return err
 		}
-		if len(envs.Items) == 0 {
+		if len(envs.Items) == 0 && !structured {
 			log.Infof("No environments found.\nTo create an environment use: jx create env\n")
 			return nil
 		}
@@ -141,13 +150,21 @@ func (o *GetEnvOptions) Run() error {
 		environments := o.filterEnvironments(envs.Items)
 		kube.SortEnvironments(environments)
 
-		if o.Output != "" {
+		if structured {
 			envs.Items = environments
 			return o.renderResult(envs, o.Output)
 		}
+		var previewTTL time.Duration
+		if o.PreviewOnly {
+			previewTTL, err = o.TeamPreviewTTL()
+			if err != nil {
+				return err
+			}
+		}
+		now := time.Now()
 		table := o.CreateTable()
 		if o.PreviewOnly {
-			table.AddRow("PULL REQUEST", "NAMESPACE", "APPLICATION")
+			table.AddRow("PULL REQUEST", "NAMESPACE", "APPLICATION", "AGE", "EXPIRES")
 		} else {
 			table.AddRow("NAME", "LABEL", "KIND", "PROMOTE", "NAMESPACE", "ORDER", "CLUSTER", "SOURCE", "REF", "PR")
 		}
@@ -155,7 +172,8 @@ func (o *GetEnvOptions) Run() error {
 		for _, env := range environments {
 			spec := &env.Spec
 			if o.PreviewOnly {
-				table.AddRow(spec.PullRequestURL, spec.Namespace, util.ColorInfo(spec.PreviewGitSpec.ApplicationURL))
+				age, expires := previewAgeAndExpiry(&env, previewTTL, now)
+				table.AddRow(spec.PullRequestURL, spec.Namespace, util.ColorInfo(spec.PreviewGitSpec.ApplicationURL), age, expires)
 			} else {
 				table.AddRow(env.Name, spec.Label, kindString(spec), string(spec.PromotionStrategy), spec.Namespace, util.Int32ToA(spec.Order), spec.Cluster, spec.Source.URL, spec.Source.Ref, spec.PullRequestURL)
 			}
@@ -165,6 +183,20 @@ func (o *GetEnvOptions) Run() error {
 	return nil
 }
 
+// previewAgeAndExpiry returns how long ago the preview environment was created and how long it has left before it is
+// garbage collected for the given time to live
+func previewAgeAndExpiry(env *v1.Environment, ttl time.Duration, now time.Time) (string, string) {
+	age := now.Sub(env.CreationTimestamp.Time).Round(time.Minute).String()
+	expiry, ok := kube.PreviewExpiry(env, ttl)
+	if !ok {
+		return age, "never"
+	}
+	if !now.Before(expiry) {
+		return age, "expired"
+	}
+	return age, expiry.Sub(now).Round(time.Minute).String()
+}
+
 func kindString(spec *v1.EnvironmentSpec) string {
 	answer := string(spec.Kind)
 	if answer == "" {
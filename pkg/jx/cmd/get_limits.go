@@ -11,7 +11,11 @@ import (
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"net/http"
 
+	"github.com/google/go-github/github"
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/gits"
 	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/pkg/errors"
 	"strconv"
 	"time"
 )
@@ -35,6 +39,8 @@ type Rate struct {
 // GetAddonOptions the command line options
 type GetLimitsOptions struct {
 	GetOptions
+
+	GitHub bool
 }
 
 var (
@@ -46,6 +52,9 @@ var (
 	get_limits_example = templates.Examples(`
 		# List all git users with limits
 		jx get limits
+
+		# List the remaining quota of each GitHub API resource, including GitHub Enterprise servers
+		jx get limits --github
 	`)
 )
 
@@ -75,6 +84,7 @@ func NewCmdGetLimits(f Factory, in terminal.FileReader, out terminal.FileWriter,
 			CheckErr(err)
 		},
 	}
+	cmd.Flags().BoolVarP(&options.GitHub, "github", "", false, "Lists the remaining quota of each GitHub API rate limit of the GitHub servers")
 
 	return cmd
 }
@@ -86,6 +96,9 @@ func (o *GetLimitsOptions) Run() error {
 		return err
 	}
 	config := authConfigSvc.Config()
+	if o.GitHub {
+		return o.showGitHubLimits(config)
+	}
 
 	table := o.CreateTable()
 	table.AddRow("Name", "URL", "Username", "Limit", "Remaining", "Reset")
@@ -160,3 +173,40 @@ func (o *GetLimitsOptions) GetLimits(server string, username string, apitoken st
 
 	return limits, nil
 }
+
+// showGitHubLimits lists the core and search rate limits of the users of all the GitHub servers
+func (o *GetLimitsOptions) showGitHubLimits(config *auth.AuthConfig) error {
+	table := o.CreateTable()
+	table.AddRow("Name", "URL", "Username", "Resource", "Limit", "Remaining", "Reset")
+
+	for _, s := range config.Servers {
+		if s.Kind != "" && s.Kind != gits.KindGitHub {
+			continue
+		}
+		for _, u := range s.Users {
+			provider, err := gits.NewGitHubProvider(s, u, o.Git())
+			if err != nil {
+				return err
+			}
+			limits, err := provider.(*gits.GitHubProvider).RateLimits()
+			if err != nil {
+				return errors.Wrapf(err, "failed to get the rate limits of user %s on %s", u.Username, s.URL)
+			}
+			addGitHubRateRow(table.AddRow, s, u, "core", limits.Core)
+			addGitHubRateRow(table.AddRow, s, u, "search", limits.Search)
+		}
+	}
+	table.Render()
+	return nil
+}
+
+func addGitHubRateRow(addRow func(...string), s *auth.AuthServer, u *auth.UserAuth, resource string, rate *github.Rate) {
+	if rate == nil {
+		return
+	}
+	resetLabel := ""
+	if !rate.Reset.IsZero() {
+		resetLabel = time.Until(rate.Reset.Time).Round(time.Second).String()
+	}
+	addRow(s.Name, s.URL, u.Username, resource, strconv.Itoa(rate.Limit), strconv.Itoa(rate.Remaining), resetLabel)
+}
@@ -11,9 +11,22 @@ import (
 
 	"github.com/jenkins-x/golang-jenkins"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
-	"github.com/jenkins-x/jx/pkg/table"
 )
 
+// PipelineInfo the last build of a pipeline
+type PipelineInfo struct {
+	Name      string `json:"name"`
+	URL       string `json:"url,omitempty"`
+	LastBuild string `json:"lastBuild,omitempty"`
+	Status    string `json:"status"`
+	Duration  string `json:"duration,omitempty"`
+}
+
+// PipelineInfoList the pipelines output by 'jx get pipelines -o json|yaml'
+type PipelineInfoList struct {
+	Items []PipelineInfo `json:"items"`
+}
+
 // GetPipelineOptions is the start of the data required to perform the operation.  As new fields are added, add them here instead of
 // referencing the cmd.Flags()
 type GetPipelineOptions struct {
@@ -29,6 +42,9 @@ var (
 	get_pipeline_example = templates.Examples(`
 		# List all pipelines
 		jx get pipeline
+
+		# List all pipelines as JSON
+		jx get pipeline -o json
 	`)
 )
 
@@ -65,6 +81,10 @@ func NewCmdGetPipeline(f Factory, in terminal.FileReader, out terminal.FileWrite
 
 // Run implements this command
 func (o *GetPipelineOptions) Run() error {
+	structured, err := o.structuredOutput()
+	if err != nil {
+		return err
+	}
 	jenkins, err := o.JenkinsClient()
 	if err != nil {
 		return err
@@ -73,57 +93,74 @@ func (o *GetPipelineOptions) Run() error {
 	if err != nil {
 		return err
 	}
-	if len(jobs) == 0 {
+	if len(jobs) == 0 && !structured {
 		return outputEmptyListWarning(o.Out)
 	}
 
-	if o.Output != "" {
-		return o.renderResult(jobs, o.Output)
-	}
-
-	table := o.CreateTable()
-	table.AddRow("Name", "URL", "LAST_BUILD", "STATUS", "DURATION")
-
+	pipelines := []PipelineInfo{}
 	for _, j := range jobs {
 		job, err := jenkins.GetJob(j.Name)
 		if err != nil {
 			return err
 		}
-		o.dump(jenkins, job.Name, &table)
+		pipelines, err = o.collectPipelines(jenkins, job.Name, pipelines)
+		if err != nil {
+			return err
+		}
+	}
+
+	if structured {
+		return o.renderResult(PipelineInfoList{Items: pipelines}, o.Output)
+	}
+
+	table := o.CreateTable()
+	table.AddRow("Name", "URL", "LAST_BUILD", "STATUS", "DURATION")
+	for _, p := range pipelines {
+		lastBuild := ""
+		if p.LastBuild != "" {
+			lastBuild = "#" + p.LastBuild
+		}
+		table.AddRow(p.Name, p.URL, lastBuild, p.Status, p.Duration)
 	}
 	table.Render()
 	return nil
 }
 
-func (o *GetPipelineOptions) dump(jenkins gojenkins.JenkinsClient, name string, table *table.Table) error {
+// collectPipelines appends the pipelines of the job, or of its child jobs, which match the filter
+func (o *GetPipelineOptions) collectPipelines(jenkins gojenkins.JenkinsClient, name string, pipelines []PipelineInfo) ([]PipelineInfo, error) {
 	job, err := jenkins.GetJob(name)
 	if err != nil {
-		return err
+		return pipelines, err
 	}
 
 	if job.Jobs != nil {
 		for _, child := range job.Jobs {
-			o.dump(jenkins, job.FullName+"/"+child.Name, table)
+			pipelines, _ = o.collectPipelines(jenkins, job.FullName+"/"+child.Name, pipelines)
 		}
-	} else {
-		last, err := jenkins.GetLastBuild(job)
-		if err != nil {
-			if jenkins.IsErrNotFound(err) {
-				if o.matchesFilter(&job) {
-					table.AddRow(job.FullName, job.Url, "", "Never Built", "")
-				}
-			}
-			return nil
-		}
-		if o.matchesFilter(&job) {
-			if last.Building {
-				table.AddRow(job.FullName, job.Url, "#"+last.Id, "Building", time.Duration(last.EstimatedDuration).String()+"(est.)")
-			} else {
-				table.AddRow(job.FullName, job.Url, "#"+last.Id, last.Result, time.Duration(last.Duration).String())
-			}
+		return pipelines, nil
+	}
+	if !o.matchesFilter(&job) {
+		return pipelines, nil
+	}
+	last, err := jenkins.GetLastBuild(job)
+	if err != nil {
+		if jenkins.IsErrNotFound(err) {
+			pipelines = append(pipelines, PipelineInfo{Name: job.FullName, URL: job.Url, Status: "Never Built"})
 		}
+		return pipelines, nil
 	}
-	return nil
+	info := PipelineInfo{
+		Name:      job.FullName,
+		URL:       job.Url,
+		LastBuild: last.Id,
+		Status:    last.Result,
+		Duration:  time.Duration(last.Duration).String(),
+	}
+	if last.Building {
+		info.Status = "Building"
+		info.Duration = time.Duration(last.EstimatedDuration).String() + "(est.)"
+	}
+	return append(pipelines, info), nil
 }
 
 func (o *GetPipelineOptions) matchesFilter(job *gojenkins.Job) bool {
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/quickstarts"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// GetQuickstartsOptions contains the command line options
+type GetQuickstartsOptions struct {
+	GetOptions
+
+	GitHubOrganisations []string
+	QuickstartLocations []string
+	CacheTTL            time.Duration
+	Filter              quickstarts.QuickstartFilter
+	IgnoreTeam          bool
+}
+
+var (
+	getQuickstartsLong = templates.LongDesc(`
+		Display the quickstarts available to create a new project from with 'jx create quickstart'.
+
+		For more documentation see: [https://jenkins-x.io/developing/create-quickstart/](https://jenkins-x.io/developing/create-quickstart/)
+
+`)
+
+	getQuickstartsExample = templates.Examples(`
+		# List all the quickstarts
+		jx get quickstarts
+
+		# List the Go quickstarts using the http framework
+		jx get quickstarts --language go --framework http
+
+		# List the quickstarts of the team and of another organisation as YAML
+		jx get qs --quickstart-location myorg -o yaml
+	`)
+)
+
+// NewCmdGetQuickstarts creates the command
+func NewCmdGetQuickstarts(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &GetQuickstartsOptions{
+		GetOptions: GetOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "quickstarts",
+		Short:   "Display the available quickstarts",
+		Aliases: []string{"quickstart", "qs"},
+		Long:    getQuickstartsLong,
+		Example: getQuickstartsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	addQuickstartLocationFlags(cmd, &options.GitHubOrganisations, &options.QuickstartLocations, &options.CacheTTL)
+	cmd.Flags().BoolVarP(&options.IgnoreTeam, "ignore-team", "", false, "Ignores the quickstart locations of the team")
+	addQuickstartFilterFlags(cmd, &options.Filter)
+	options.addGetFlags(cmd)
+	return cmd
+}
+
+// Run implements this command
+func (o *GetQuickstartsOptions) Run() error {
+	structured, err := o.structuredOutput()
+	if err != nil {
+		return err
+	}
+	model, err := o.loadQuickstarts(o.IgnoreTeam, o.GitHubOrganisations, o.QuickstartLocations, o.CacheTTL)
+	if err != nil {
+		return err
+	}
+	list := model.SortedQuickstarts(&o.Filter)
+	if structured {
+		return o.renderResult(list, o.Output)
+	}
+	if len(list) == 0 {
+		return outputEmptyListWarning(o.Out)
+	}
+
+	table := o.CreateTable()
+	table.AddRow("NAME", "OWNER", "LANGUAGE", "FRAMEWORK", "TAGS")
+	for _, q := range list {
+		table.AddRow(q.SurveyName(), q.Owner, q.Language, q.Framework, strings.Join(q.Tags, ", "))
+	}
+	table.Render()
+	return nil
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// GetStorageOptions containers the CLI options
+type GetStorageOptions struct {
+	GetOptions
+}
+
+var (
+	getStorageLong = templates.LongDesc(`
+		Display the buckets the current Team uses for the long term storage of logs, reports and the artifact repository.
+
+`)
+
+	getStorageExample = templates.Examples(`
+		# List the storage locations of the team
+		jx get storage
+	`)
+)
+
+// NewCmdGetStorage creates the new command for: jx get storage
+func NewCmdGetStorage(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &GetStorageOptions{
+		GetOptions: GetOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "storage",
+		Short:   "Display the storage locations of the team",
+		Aliases: []string{"bucket", "buckets"},
+		Long:    getStorageLong,
+		Example: getStorageExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	options.addGetFlags(cmd)
+	return cmd
+}
+
+// Run implements this command
+func (o *GetStorageOptions) Run() error {
+	teamSettings, err := o.TeamSettings()
+	if err != nil {
+		return err
+	}
+	if len(teamSettings.StorageLocations) == 0 {
+		return outputEmptyListWarning(o.Out)
+	}
+
+	table := o.CreateTable()
+	table.AddRow("CLASSIFIER", "BUCKET")
+	for _, location := range teamSettings.StorageLocations {
+		table.AddRow(location.Classifier, location.BucketURL)
+	}
+	table.Render()
+	return nil
+}
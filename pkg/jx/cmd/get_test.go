@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/api/apps/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetStructuredOutput(t *testing.T) {
+	t.Parallel()
+	for _, format := range []string{"", "table"} {
+		o := &GetOptions{Output: format}
+		structured, err := o.structuredOutput()
+		require.NoError(t, err)
+		assert.False(t, structured, "format %s", format)
+	}
+	for _, format := range []string{"json", "yaml"} {
+		o := &GetOptions{Output: format}
+		structured, err := o.structuredOutput()
+		require.NoError(t, err)
+		assert.True(t, structured, "format %s", format)
+	}
+	o := &GetOptions{Output: "xml"}
+	_, err := o.structuredOutput()
+	assert.Error(t, err)
+}
+
+func TestGetFilterActivities(t *testing.T) {
+	t.Parallel()
+	activities := []v1.PipelineActivity{
+		{ObjectMeta: metav1.ObjectMeta{Name: "acme-foo-master-1"}, Spec: v1.PipelineActivitySpec{Pipeline: "acme/foo/master", Build: "1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "acme-foo-master-2"}, Spec: v1.PipelineActivitySpec{Pipeline: "acme/foo/master", Build: "2"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "acme-bar-master-1"}, Spec: v1.PipelineActivitySpec{Pipeline: "acme/bar/master", Build: "1"}},
+	}
+
+	o := &GetActivityOptions{Filter: "foo"}
+	assert.Len(t, o.filterActivities(activities), 2)
+
+	o = &GetActivityOptions{Filter: "foo", BuildNumber: "2"}
+	filtered := o.filterActivities(activities)
+	require.Len(t, filtered, 1)
+	assert.Equal(t, "acme-foo-master-2", filtered[0].Name)
+}
+
+func TestGetFilterActivitiesByEnvAndSince(t *testing.T) {
+	t.Parallel()
+	recently := metav1.NewTime(time.Now().Add(-10 * time.Minute))
+	yesterday := metav1.NewTime(time.Now().Add(-24 * time.Hour))
+	activities := []v1.PipelineActivity{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "acme-foo-master-1"},
+			Spec: v1.PipelineActivitySpec{
+				Pipeline:         "acme/foo/master",
+				Build:            "1",
+				StartedTimestamp: &yesterday,
+				Steps: []v1.PipelineActivityStep{
+					{Promote: &v1.PromoteActivityStep{Environment: "production"}},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "acme-foo-master-2"},
+			Spec: v1.PipelineActivitySpec{
+				Pipeline:         "acme/foo/master",
+				Build:            "2",
+				StartedTimestamp: &recently,
+				Steps: []v1.PipelineActivityStep{
+					{Promote: &v1.PromoteActivityStep{Environment: "staging"}},
+				},
+			},
+		},
+	}
+
+	o := &GetActivityOptions{Env: "production"}
+	filtered := o.filterActivities(activities)
+	require.Len(t, filtered, 1)
+	assert.Equal(t, "acme-foo-master-1", filtered[0].Name)
+
+	o = &GetActivityOptions{Filter: "foo/master", Since: time.Hour}
+	filtered = o.filterActivities(activities)
+	require.Len(t, filtered, 1)
+	assert.Equal(t, "acme-foo-master-2", filtered[0].Name)
+}
+
+func TestGetActivitiesCompactDashboard(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	started := metav1.NewTime(now.Add(-90 * time.Second))
+	activity := &v1.PipelineActivity{
+		ObjectMeta: metav1.ObjectMeta{Name: "acme-foo-master-3"},
+		Spec: v1.PipelineActivitySpec{
+			Pipeline:         "acme/foo/master",
+			Build:            "3",
+			Status:           v1.ActivityStatusTypeRunning,
+			StartedTimestamp: &started,
+			Steps: []v1.PipelineActivityStep{
+				{
+					Kind: v1.ActivityStepKindTypeStage,
+					Stage: &v1.StageActivityStep{
+						CoreActivityStep: v1.CoreActivityStep{Name: "Git Source", Status: v1.ActivityStatusTypeSucceeded, StartedTimestamp: &started},
+					},
+				},
+				{
+					Kind: v1.ActivityStepKindTypeStage,
+					Stage: &v1.StageActivityStep{
+						CoreActivityStep: v1.CoreActivityStep{Name: "Build", Status: v1.ActivityStatusTypeRunning},
+					},
+				},
+			},
+		},
+	}
+	line := compactActivityLine(activity, now)
+	fields := strings.Fields(line)
+	assert.Equal(t, []string{"acme/foo", "master", "#3", "Build", "1m30s"}, fields[:5])
+
+	out := &bytes.Buffer{}
+	dashboard := newActivityDashboard(out)
+	dashboard.now = func() time.Time {
+		return now
+	}
+	dashboard.onActivity(activity)
+	assert.Equal(t, line+"\n", out.String())
+
+	out.Reset()
+	completed := *activity
+	completed.Spec.Status = v1.ActivityStatusTypeSucceeded
+	dashboard.onActivity(&completed)
+	assert.Equal(t, "\033[1A\033[2K"+compactActivityLine(&completed, now)+"\n", out.String(), "the running line should be replaced by the completed one")
+	assert.Empty(t, dashboard.running)
+
+	out.Reset()
+	dashboard.refresh()
+	assert.Empty(t, out.String(), "the completed line should be left alone")
+}
+
+func TestGetApplicationsRenderJSON(t *testing.T) {
+	t.Parallel()
+	file, err := ioutil.TempFile("", "test-get-apps")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+
+	replicas := int32(2)
+	o := &GetApplicationsOptions{
+		GetOptions: GetOptions{
+			CommonOptions: CommonOptions{Out: file},
+			Output:        "json",
+		},
+		HideUrl: true,
+	}
+	envApps := []EnvApps{
+		{
+			Environment: v1.Environment{
+				ObjectMeta: metav1.ObjectMeta{Name: "staging"},
+				Spec:       v1.EnvironmentSpec{Namespace: "jx-staging"},
+			},
+			Apps: map[string]v1beta1.Deployment{
+				"foo": {
+					ObjectMeta: metav1.ObjectMeta{Name: "jx-staging-foo", Labels: map[string]string{"version": "1.0.1"}},
+					Spec:       v1beta1.DeploymentSpec{Replicas: &replicas},
+					Status:     v1beta1.DeploymentStatus{ReadyReplicas: 1},
+				},
+			},
+		},
+		{
+			Environment: v1.Environment{
+				ObjectMeta: metav1.ObjectMeta{Name: "production"},
+				Spec:       v1.EnvironmentSpec{Namespace: "jx-production"},
+			},
+			Apps: map[string]v1beta1.Deployment{},
+		},
+	}
+	err = o.renderResult(o.applicationInfos([]string{"foo"}, envApps), o.Output)
+	require.NoError(t, err)
+
+	data, err := ioutil.ReadFile(file.Name())
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "\x1b[")
+
+	list := ApplicationInfoList{}
+	err = json.Unmarshal(data, &list)
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	app := list.Items[0]
+	assert.Equal(t, "foo", app.Name)
+	require.Len(t, app.Environments, 1, "the application is only in staging")
+	env := app.Environments[0]
+	assert.Equal(t, "staging", env.Environment)
+	assert.Equal(t, "jx-staging", env.Namespace)
+	assert.Equal(t, "1.0.1", env.Version)
+	assert.Equal(t, int32(2), *env.Replicas)
+	assert.Equal(t, int32(1), *env.ReadyReplicas)
+	assert.Equal(t, "", env.URL)
+}
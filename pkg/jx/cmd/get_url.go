@@ -8,25 +8,37 @@ import (
 
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/util"
 )
 
 // GetURLOptions the command line options
 type GetURLOptions struct {
 	GetOptions
 
-	Namespace   string
-	Environment string
+	Namespace       string
+	Environment     string
+	AllEnvironments bool
+}
+
+// URLList the URLs of the exposed services
+type URLList struct {
+	Items []kube.ExposedURL `json:"items"`
 }
 
 var (
 	get_url_long = templates.LongDesc(`
 		Display one or more URLs from the running services.
 
+		The URLs are those of the services exposed by the exposecontroller or by an ingress along with whether they use TLS.
+
 `)
 
 	get_url_example = templates.Examples(`
 		# List all URLs in this namespace
 		jx get url
+
+		# List all URLs in the namespaces of all the environments as JSON
+		jx get urls --all-environments -o json
 	`)
 )
 
@@ -58,6 +70,8 @@ func NewCmdGetURL(f Factory, in terminal.FileReader, out terminal.FileWriter, er
 		},
 	}
 	options.addGetUrlFlags(cmd)
+	options.addGetFlags(cmd)
+	cmd.Flags().BoolVarP(&options.AllEnvironments, "all-environments", "", false, "Lists the URLs in the namespaces of all the environments")
 	return cmd
 }
 
@@ -68,28 +82,79 @@ func (o *GetURLOptions) addGetUrlFlags(cmd *cobra.Command) {
 
 // Run implements this command
 func (o *GetURLOptions) Run() error {
-	client, ns, err := o.KubeClient()
+	structured, err := o.structuredOutput()
 	if err != nil {
 		return err
 	}
-	if o.Namespace != "" {
-		ns = o.Namespace
-	} else if o.Environment != "" {
-		ns, err = o.findEnvironmentNamespace(o.Environment)
-		if err != nil {
-			return err
-		}
-	}
-	urls, err := kube.FindServiceURLs(client, ns)
+	urls, err := o.findURLs()
 	if err != nil {
 		return err
 	}
+	if structured {
+		return o.renderResult(URLList{Items: urls}, o.Output)
+	}
 	table := o.CreateTable()
-	table.AddRow("Name", "URL")
-
+	if o.AllEnvironments {
+		table.AddRow("ENVIRONMENT", "NAMESPACE", "NAME", "URL", "TLS")
+	} else {
+		table.AddRow("Name", "URL", "TLS")
+	}
 	for _, url := range urls {
-		table.AddRow(url.Name, url.URL)
+		tls := util.ColorWarning("no")
+		if url.TLS {
+			tls = util.ColorInfo("yes")
+		}
+		if o.AllEnvironments {
+			table.AddRow(url.Environment, url.Namespace, url.Name, url.URL, tls)
+		} else {
+			table.AddRow(url.Name, url.URL, tls)
+		}
 	}
 	table.Render()
 	return nil
 }
+
+// findURLs returns the URLs of the namespace of the options or of the namespaces of all the environments
+func (o *GetURLOptions) findURLs() ([]kube.ExposedURL, error) {
+	client, ns, err := o.KubeClient()
+	if err != nil {
+		return nil, err
+	}
+	if !o.AllEnvironments {
+		if o.Namespace != "" {
+			ns = o.Namespace
+		} else if o.Environment != "" {
+			ns, err = o.findEnvironmentNamespace(o.Environment)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return kube.FindExposedURLs(client, ns)
+	}
+	jxClient, devNs, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return nil, err
+	}
+	envMap, envNames, err := kube.GetEnvironments(jxClient, devNs)
+	if err != nil {
+		return nil, err
+	}
+	answer := []kube.ExposedURL{}
+	namespaces := map[string]bool{}
+	for _, name := range envNames {
+		envNs := envMap[name].Spec.Namespace
+		if envNs == "" || namespaces[envNs] {
+			continue
+		}
+		namespaces[envNs] = true
+		urls, err := kube.FindExposedURLs(client, envNs)
+		if err != nil {
+			return nil, err
+		}
+		for _, url := range urls {
+			url.Environment = name
+			answer = append(answer, url)
+		}
+	}
+	return answer, nil
+}
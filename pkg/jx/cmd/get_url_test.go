@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	jxfake "github.com/jenkins-x/jx/pkg/client/clientset/versioned/fake"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetURLAllEnvironmentsAsJSON(t *testing.T) {
+	t.Parallel()
+	environment := func(name string, ns string) *v1.Environment {
+		return &v1.Environment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "jx"},
+			Spec:       v1.EnvironmentSpec{Namespace: ns},
+		}
+	}
+	service := func(name string, ns string, url string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   ns,
+				Annotations: map[string]string{kube.ExposeURLAnnotation: url},
+			},
+		}
+	}
+	out, err := ioutil.TempFile("", "test-get-url")
+	require.NoError(t, err)
+	defer os.Remove(out.Name())
+
+	o := &GetURLOptions{
+		GetOptions: GetOptions{
+			CommonOptions: CommonOptions{
+				Out: out,
+				KubeClientCached: fake.NewSimpleClientset(
+					service("jenkins", "jx", "http://jenkins.jx.example.com"),
+					service("myapp", "jx-staging", "https://myapp.jx-staging.example.com"),
+					service("myapp", "jx-production", "https://myapp.example.com")),
+				jxClient: jxfake.NewSimpleClientset(
+					environment("dev", "jx"),
+					environment("production", "jx-production"),
+					environment("staging", "jx-staging")),
+				devNamespace:     "jx",
+				currentNamespace: "jx",
+			},
+			Output: outputFormatJSON,
+		},
+		AllEnvironments: true,
+	}
+	err = o.Run()
+	require.NoError(t, err)
+
+	data, err := ioutil.ReadFile(out.Name())
+	require.NoError(t, err)
+	list := URLList{}
+	err = json.Unmarshal(data, &list)
+	require.NoError(t, err)
+	assert.Equal(t, []kube.ExposedURL{
+		{Environment: "dev", Namespace: "jx", Name: "jenkins", URL: "http://jenkins.jx.example.com"},
+		{Environment: "production", Namespace: "jx-production", Name: "myapp", URL: "https://myapp.example.com", TLS: true},
+		{Environment: "staging", Namespace: "jx-staging", Name: "myapp", URL: "https://myapp.jx-staging.example.com", TLS: true},
+	}, list.Items)
+}
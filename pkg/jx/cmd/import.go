@@ -6,7 +6,9 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/cenkalti/backoff"
 	"github.com/jenkins-x/jx/pkg/cloud/amazon"
@@ -51,6 +53,16 @@ const (
 	// JenkinsfileBackupSuffix the suffix used by Jenkins for backups
 	JenkinsfileBackupSuffix = ".backup"
 
+	// JenkinsfileOriginalSuffix the suffix an existing Jenkinsfile is renamed with when it is replaced by the Jenkinsfile of the pack
+	JenkinsfileOriginalSuffix = ".original"
+
+	// ExistingJenkinsfileKeep keeps an existing Jenkinsfile only appending the release and promote stages of the pack if it does not promote
+	ExistingJenkinsfileKeep = "keep"
+	// ExistingJenkinsfileRename renames an existing Jenkinsfile and uses the Jenkinsfile of the pack
+	ExistingJenkinsfileRename = "rename"
+	// ExistingJenkinsfileMerge merges the environment variables and the release and promote stages of the pack into an existing Jenkinsfile
+	ExistingJenkinsfileMerge = "merge"
+
 	minimumMavenDeployVersion = "2.8.2"
 
 	defaultGitIgnoreFile = `
@@ -104,6 +116,22 @@ type ImportOptions struct {
 	DisableMaven          bool
 	PipelineUserName      string
 	PipelineServer        string
+
+	AppDirs             []string
+	DiscoverApps        bool
+	ExistingJenkinsfile string
+
+	AllRepos        bool
+	ExcludeRepos    []string
+	ContinueOnError bool
+	Concurrency     int
+
+	dryRunBaseDir string
+	// dryRunInPlace generates the files of a dry run in the directory itself rather than in a copy, which is used for
+	// the projects the create commands have just generated
+	dryRunInPlace          bool
+	skipJenkinsXRepository bool
+	importLock             *sync.Mutex
 }
 
 var (
@@ -137,6 +165,21 @@ var (
 
         # Import all repositories from a GitHub organisation which contain the text foo
 		jx import --github --org myname --all --filter foo 
+
+		# Import the repositories of a GitHub organisation matching a glob concurrently, skipping the ones already imported
+		jx import --org myname --all-repos --filter 'svc-*' --exclude '*-docs' --batch-mode
+
+		# Import the applications in the services/foo and services/bar directories of a monorepo
+		jx import --app-dir services/foo --app-dir services/bar
+
+		# Import every directory of a monorepo containing a Dockerfile, pom.xml or package.json as an application
+		jx import --discover-apps
+
+		# Import a project keeping its Jenkinsfile but adding the release and promote stages of the pack
+		jx import --existing-jenkinsfile keep
+
+		# Preview the files and the remote actions of the import without changing anything
+		jx import --dry-run
 		`)
 )
 
@@ -165,7 +208,13 @@ func NewCmdImport(f Factory, in terminal.FileReader, out terminal.FileWriter, er
 	cmd.Flags().StringVarP(&options.RepoURL, "url", "u", "", "The git clone URL to clone into the current directory and then import")
 	cmd.Flags().BoolVarP(&options.GitHub, "github", "", false, "If you wish to pick the repositories from GitHub to import")
 	cmd.Flags().BoolVarP(&options.SelectAll, "all", "", false, "If selecting projects to import from a Git provider this defaults to selecting them all")
-	cmd.Flags().StringVarP(&options.SelectFilter, "filter", "", "", "If selecting projects to import from a Git provider this filters the list of repositories")
+	cmd.Flags().StringVarP(&options.SelectFilter, "filter", "", "", "If selecting projects to import from a Git provider this filters the list of repositories. With --all-repos it can be a glob pattern")
+	cmd.Flags().BoolVarP(&options.AllRepos, "all-repos", "", false, "Imports the repositories of the organisation given by --org concurrently, skipping those already imported")
+	cmd.Flags().StringArrayVarP(&options.ExcludeRepos, "exclude", "", nil, "A glob pattern of the repositories to not import with --all-repos, can be repeated")
+	cmd.Flags().BoolVarP(&options.ContinueOnError, "continue-on-error", "", false, "Continues importing the remaining repositories with --all-repos if one of them fails to import")
+	cmd.Flags().IntVarP(&options.Concurrency, "concurrency", "", defaultImportConcurrency, "The maximum number of repositories imported at the same time with --all-repos")
+	cmd.Flags().StringArrayVarP(&options.AppDirs, "app-dir", "", nil, "The directory of an application of a monorepo to generate a Dockerfile, chart and pipeline stages for, can be repeated")
+	cmd.Flags().BoolVarP(&options.DiscoverApps, "discover-apps", "", false, "Imports each directory of a monorepo containing a Dockerfile, pom.xml or package.json as an application")
 
 	options.addImportFlags(cmd, false)
 
@@ -183,7 +232,11 @@ func (options *ImportOptions) addImportFlags(cmd *cobra.Command, createProject b
 	cmd.Flags().StringVarP(&options.Repository, "name", "", notCreateProject("n"), "Specify the Git repository name to import the project into (if it is not already in one)")
 	cmd.Flags().StringVarP(&options.Credentials, "credentials", notCreateProject("c"), "", "The Jenkins credentials name used by the job")
 	cmd.Flags().StringVarP(&options.Jenkinsfile, "jenkinsfile", notCreateProject("j"), "", "The name of the Jenkinsfile to use. If not specified then 'Jenkinsfile' will be used")
-	cmd.Flags().BoolVarP(&options.DryRun, "dry-run", "", false, "Performs local changes to the repo but skips the import into Jenkins X")
+	dryRunUsage := "Generates the files in a temporary copy of the repository and prints them with the remote actions which would be performed without modifying the repository, the Git remote or the cluster"
+	if createProject {
+		dryRunUsage = "Generates the files of the import in the generated project and prints the remote actions which would be performed without modifying the Git remote or the cluster"
+	}
+	cmd.Flags().BoolVarP(&options.DryRun, "dry-run", "", false, dryRunUsage)
 	cmd.Flags().BoolVarP(&options.DisableDraft, "no-draft", "", false, "Disable Draft from trying to default a Dockerfile and Helm Chart")
 	cmd.Flags().BoolVarP(&options.DisableJenkinsfileCheck, "no-jenkinsfile", "", false, "Disable defaulting a Jenkinsfile if its missing")
 	cmd.Flags().StringVarP(&options.ImportGitCommitMessage, "import-commit-message", "", "", "Should we override the Jenkinsfile in the project?")
@@ -191,6 +244,7 @@ func (options *ImportOptions) addImportFlags(cmd *cobra.Command, createProject b
 	cmd.Flags().BoolVarP(&options.ListDraftPacks, "list-packs", "", false, "list available draft packs")
 	cmd.Flags().StringVarP(&options.DraftPack, "pack", "", "", "The name of the pack to use")
 	cmd.Flags().StringVarP(&options.DockerRegistryOrg, "docker-registry-org", "", "", "The name of the docker registry organisation to use. If not specified then the Git provider organisation will be used")
+	cmd.Flags().StringVarP(&options.ExistingJenkinsfile, "existing-jenkinsfile", "", "", fmt.Sprintf("How to handle an existing Jenkinsfile: %s. If not specified you are asked in interactive mode", strings.Join(existingJenkinsfileModes, ", ")))
 	cmd.Flags().StringVarP(&options.ExternalJenkinsBaseURL, "external-jenkins-url", "", "", "The jenkins url that an external git provider needs to use")
 
 	options.addCommonFlags(cmd)
@@ -214,6 +268,10 @@ func (options *ImportOptions) Run() error {
 
 	options.Factory.SetBatch(options.BatchMode)
 
+	if options.ExistingJenkinsfile != "" && util.StringArrayIndex(existingJenkinsfileModes, options.ExistingJenkinsfile) < 0 {
+		return util.InvalidOption("existing-jenkinsfile", options.ExistingJenkinsfile, existingJenkinsfileModes)
+	}
+
 	var err error
 	isProw := false
 	if !options.DryRun {
@@ -287,14 +345,20 @@ func (options *ImportOptions) Run() error {
 			}
 		}
 		if userAuth.IsInvalid() {
-			f := func(username string) error {
-				options.Git().PrintCreateRepositoryGenerateAccessToken(server, username, options.Out)
-				return nil
-			}
-			err = config.EditUserAuth(server.Label(), userAuth, userAuth.Username, true, options.BatchMode, f, options.In, options.Out, options.Err)
+			loggedIn, err := gits.LoginWithGitHubDeviceFlow(server, userAuth, options.BatchMode, options.Out)
 			if err != nil {
 				return err
 			}
+			if !loggedIn {
+				f := func(username string) error {
+					options.Git().PrintCreateRepositoryGenerateAccessToken(server, username, options.Out)
+					return nil
+				}
+				err = config.EditUserAuth(server.Label(), userAuth, userAuth.Username, true, options.BatchMode, f, options.In, options.Out, options.Err)
+				if err != nil {
+					return err
+				}
+			}
 
 			// TODO lets verify the auth works?
 			if userAuth.IsInvalid() {
@@ -313,6 +377,9 @@ func (options *ImportOptions) Run() error {
 			return err
 		}
 	}
+	if options.AllRepos {
+		return options.importAllRepositories(isProw)
+	}
 	if options.GitHub {
 		return options.ImportProjectsFromGitHub()
 	}
@@ -333,14 +400,36 @@ func (options *ImportOptions) Run() error {
 	shouldClone := checkForJenkinsfile || !options.DisableDraft
 
 	if options.RepoURL != "" {
+		if options.DryRun {
+			err = options.createDryRunDir()
+			if err != nil {
+				return err
+			}
+		}
 		if shouldClone {
 			// lets make sure there's a .git at the end for GitHub URLs
 			err = options.CloneRepository()
 			if err != nil {
 				return err
 			}
+			err = options.checkJenkinsXRepository()
+			if err != nil {
+				return err
+			}
+			if options.DryRun {
+				err = options.startDryRun()
+				if err != nil {
+					return err
+				}
+			}
 		}
 	} else {
+		if options.DryRun {
+			err = options.startDryRun()
+			if err != nil {
+				return err
+			}
+		}
 		err = options.DiscoverGit()
 		if err != nil {
 			return err
@@ -373,52 +462,64 @@ func (options *ImportOptions) Run() error {
 	}
 	options.AppName = kube.ToValidName(strings.ToLower(options.AppName))
 
-	if !options.DisableDraft {
-		err = options.DraftCreate()
+	if options.DiscoverApps || len(options.AppDirs) > 0 {
+		err = options.resolveMonorepoAppDirs()
 		if err != nil {
 			return err
 		}
-
-	}
-	err = options.fixDockerIgnoreFile()
-	if err != nil {
-		return err
-	}
-
-	err = options.fixMaven()
-	if err != nil {
-		return err
 	}
 
-	if options.RepoURL == "" {
-		if !options.DryRun {
-			err = options.CreateNewRemoteRepository()
-			if err != nil {
-				return err
-			}
+	if len(options.AppDirs) > 0 {
+		err = options.importMonorepoApps()
+		if err != nil {
+			return err
 		}
 	} else {
-		if shouldClone {
-			err = options.Git().Push(options.Dir)
+		if !options.DisableDraft {
+			err = options.withImportLock(options.DraftCreate)
 			if err != nil {
 				return err
 			}
+
+		}
+		err = options.fixDockerIgnoreFile()
+		if err != nil {
+			return err
+		}
+
+		err = options.fixMaven()
+		if err != nil {
+			return err
 		}
 	}
 
 	if options.DryRun {
-		log.Infoln("dry-run so skipping import to Jenkins X")
-		return nil
+		return options.printDryRun(shouldClone)
 	}
 
-	if !isProw {
-		err = options.checkChartmuseumCredentialExists()
+	if options.RepoURL == "" {
+		err = options.CreateNewRemoteRepository()
 		if err != nil {
 			return err
 		}
+	} else {
+		if shouldClone {
+			err = options.Git().Push(options.Dir)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
-	return options.doImport()
+	return options.withImportLock(func() error {
+		if !isProw {
+			err := options.checkChartmuseumCredentialExists()
+			if err != nil {
+				return err
+			}
+		}
+		return options.doImport()
+	})
 }
 
 // ImportProjectsFromGitHub import projects from github
@@ -466,6 +567,11 @@ func (options *ImportOptions) DraftCreate() error {
 	if err != nil {
 		return err
 	}
+	builtinPacksDir := filepath.Join(draftDir, jxdraft.BuiltinPacksDirName)
+	err = jxdraft.WriteBuiltinPacks(builtinPacksDir)
+	if err != nil {
+		return err
+	}
 
 	// TODO this is a workaround of this draft issue:
 	// https://github.com/Azure/draft/issues/476
@@ -494,13 +600,12 @@ func (options *ImportOptions) DraftCreate() error {
 
 	if len(customDraftPack) > 0 {
 		log.Info("trying to use draft pack: " + customDraftPack + "\n")
-		lpack = filepath.Join(packsDir, customDraftPack)
-		f, err := util.FileExists(lpack)
+		lpack, err = findDraftPack(packsDir, builtinPacksDir, customDraftPack)
 		if err != nil {
 			log.Error(err.Error())
 			return err
 		}
-		if f == false {
+		if lpack == "" {
 			log.Error("Could not find pack: " + customDraftPack + " going to try detect which pack to use")
 			lpack = ""
 		}
@@ -536,12 +641,24 @@ func (options *ImportOptions) DraftCreate() error {
 			lpack = filepath.Join(packsDir, "jenkins")
 		} else if exists, err := util.FileExists(packagerConfigName); err == nil && exists {
 			lpack = filepath.Join(packsDir, "cwp")
+		} else if builtinPack, err := jxdraft.DetectBuiltinPack(dir); err == nil && builtinPack != "" {
+			lpack, err = findDraftPack(packsDir, builtinPacksDir, builtinPack)
+			if err != nil {
+				return err
+			}
 		} else {
 			// pack detection time
 			lpack, err = jxdraft.DoPackDetection(draftHome, options.Out, dir)
 
 			if err != nil {
-				return err
+				if options.BatchMode {
+					return err
+				}
+				log.Warnf("%s\n", err)
+				lpack, err = options.pickDraftPack(packsDir, builtinPacksDir)
+				if err != nil {
+					return err
+				}
 			}
 		}
 	}
@@ -561,7 +678,26 @@ func (options *ImportOptions) DraftCreate() error {
 		}
 	}
 	jenkinsfileBackup := ""
-	if jenkinsfileExists && options.InitialisedGit && !options.DisableJenkinsfileCheck {
+	existingJenkinsfileMode := ""
+	existingJenkinsfile := ""
+	if jenkinsfileExists && !options.DisableJenkinsfileCheck {
+		existingJenkinsfileMode, err = options.pickExistingJenkinsfileMode(jenkinsfile)
+		if err != nil {
+			return err
+		}
+	}
+	if existingJenkinsfileMode != "" {
+		// lets remove the existing Jenkinsfile so that the pack generates its Jenkinsfile
+		data, err := ioutil.ReadFile(jenkinsfile)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load %s", jenkinsfile)
+		}
+		existingJenkinsfile = string(data)
+		err = os.Remove(jenkinsfile)
+		if err != nil {
+			return err
+		}
+	} else if jenkinsfileExists && options.InitialisedGit && !options.DisableJenkinsfileCheck {
 		// lets copy the old Jenkinsfile in case we overwrite it
 		jenkinsfileBackup = jenkinsfile + JenkinsfileBackupSuffix
 		err = util.RenameFile(jenkinsfile, jenkinsfileBackup)
@@ -650,6 +786,13 @@ func (options *ImportOptions) DraftCreate() error {
 		return err
 	}
 
+	if existingJenkinsfileMode != "" {
+		err = options.applyExistingJenkinsfile(jenkinsfile, existingJenkinsfile, existingJenkinsfileMode)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Create prow owners file
 	err = options.CreateProwOwnersFile()
 	if err != nil {
@@ -727,6 +870,10 @@ func (options *ImportOptions) CreateNewRemoteRepository() error {
 	_, defaultRepoName := filepath.Split(dir)
 
 	options.GitRepositoryOptions.Owner = options.getOrganisation()
+	err = options.defaultGitProtocol(&options.GitRepositoryOptions)
+	if err != nil {
+		return err
+	}
 
 	details, err := gits.PickNewGitRepository(options.BatchMode, authConfigSvc, defaultRepoName, &options.GitRepositoryOptions,
 		options.GitServer, options.GitUserAuth, options.Git(), options.In, options.Out, options.Err)
@@ -740,7 +887,11 @@ func (options *ImportOptions) CreateNewRemoteRepository() error {
 	options.GitProvider = details.GitProvider
 
 	options.RepoURL = repo.CloneURL
-	pushGitURL, err := options.Git().CreatePushURL(repo.CloneURL, details.User)
+	cloneURL, err := gits.GitCloneURLForProtocol(repo.CloneURL, options.GitRepositoryOptions.Protocol)
+	if err != nil {
+		return err
+	}
+	pushGitURL, err := options.Git().CreatePushURL(cloneURL, details.User)
 	if err != nil {
 		return err
 	}
@@ -863,7 +1014,7 @@ func (options *ImportOptions) DiscoverGit() error {
 	}
 
 	// lets prompt the user to initialise the Git repository
-	if !options.BatchMode {
+	if !options.BatchMode && !options.DryRun {
 		log.Infof("The directory %s is not yet using git\n", util.ColorInfo(dir))
 		flag := false
 		prompt := &survey.Confirm{
@@ -904,7 +1055,7 @@ func (options *ImportOptions) DiscoverGit() error {
 
 	message := options.ImportGitCommitMessage
 	if message == "" {
-		if options.BatchMode {
+		if options.BatchMode || options.DryRun {
 			message = "Initial import"
 		} else {
 			messagePrompt := &survey.Input{
@@ -999,9 +1150,11 @@ func (options *ImportOptions) doImport() error {
 		jenkinsfile = jenkins.DefaultJenkinsfile
 	}
 
-	err = options.ensureDockerRepositoryExists()
-	if err != nil {
-		return err
+	for _, appName := range options.appNames() {
+		err = options.ensureDockerRepositoryExists(appName)
+		if err != nil {
+			return err
+		}
 	}
 
 	isProw, err := options.isProw()
@@ -1026,7 +1179,11 @@ func (options *ImportOptions) addProwConfig(gitURL string) error {
 		return err
 	}
 	repo := gitInfo.Organisation + "/" + gitInfo.Name
-	err = prow.AddApplication(options.KubeClientCached, []string{repo}, options.currentNamespace, options.DraftPack)
+	settings, err := options.TeamSettings()
+	if err != nil {
+		return err
+	}
+	err = prow.AddApplication(options.KubeClientCached, []string{repo}, options.currentNamespace, options.DraftPack, settings.GitSSHKeySecret)
 	if err != nil {
 		return err
 	}
@@ -1039,9 +1196,8 @@ func (options *ImportOptions) addProwConfig(gitURL string) error {
 }
 
 // ensureDockerRepositoryExists for some kinds of container registry we need to pre-initialise its use such as for ECR
-func (options *ImportOptions) ensureDockerRepositoryExists() error {
+func (options *ImportOptions) ensureDockerRepositoryExists(appName string) error {
 	orgName := options.getOrganisationOrCurrentUser()
-	appName := options.AppName
 	if orgName == "" {
 		log.Warnf("Missing organisation name!\n")
 		return nil
@@ -1066,7 +1222,7 @@ func (options *ImportOptions) ensureDockerRepositoryExists() error {
 	if cm.Data != nil {
 		dockerRegistry := cm.Data["docker.registry"]
 		if dockerRegistry != "" {
-			if strings.HasSuffix(dockerRegistry, ".amazonaws.com") && strings.Index(dockerRegistry, ".ecr.") > 0 {
+			if amazon.IsECRRegistry(dockerRegistry) {
 				return amazon.LazyCreateRegistry(orgName, appName)
 			}
 		}
@@ -1413,7 +1569,13 @@ func (o *ImportOptions) allDraftPacks() ([]string, error) {
 		return nil, err
 	}
 
-	files, err := ioutil.ReadDir(dir)
+	return draftPackNames(dir)
+}
+
+// draftPackNames returns the sorted names of the build packs of the team in the packs directory and the packs built
+// into jx
+func draftPackNames(packsDir string) ([]string, error) {
+	files, err := ioutil.ReadDir(packsDir)
 	if err != nil {
 		return nil, err
 	}
@@ -1423,6 +1585,40 @@ func (o *ImportOptions) allDraftPacks() ([]string, error) {
 			result = append(result, f.Name())
 		}
 	}
-	return result, err
+	for _, name := range jxdraft.BuiltinPackNames() {
+		if util.StringArrayIndex(result, name) < 0 {
+			result = append(result, name)
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// findDraftPack returns the directory of the named pack preferring the build packs of the team over the packs built
+// into jx or an empty string if there is no such pack
+func findDraftPack(packsDir string, builtinPacksDir string, name string) (string, error) {
+	for _, dir := range []string{packsDir, builtinPacksDir} {
+		packDir := filepath.Join(dir, name)
+		exists, err := util.FileExists(packDir)
+		if err != nil {
+			return "", err
+		}
+		if exists {
+			return packDir, nil
+		}
+	}
+	return "", nil
+}
 
+// pickDraftPack lets the user pick the pack to use when the pack could not be detected
+func (o *ImportOptions) pickDraftPack(packsDir string, builtinPacksDir string) (string, error) {
+	names, err := draftPackNames(packsDir)
+	if err != nil {
+		return "", err
+	}
+	name, err := util.PickName(names, "Pick the pack to build the project with:", o.In, o.Out, o.Err)
+	if err != nil {
+		return "", err
+	}
+	return findDraftPack(packsDir, builtinPacksDir, name)
 }
@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/jenkins"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	importFileAdded    = "added"
+	importFileModified = "modified"
+	importFileDeleted  = "deleted"
+
+	importDryRunDirPrefix = "jx-import-dry-run-"
+)
+
+// importFileChange a file of the repository which the import would add, modify or delete
+type importFileChange struct {
+	Path   string
+	Status string
+	Diff   string
+}
+
+// createDryRunDir creates the temporary directory a dry run clones the repository into
+func (options *ImportOptions) createDryRunDir() error {
+	dir, err := ioutil.TempDir("", importDryRunDirPrefix)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the dry run directory")
+	}
+	options.Dir = dir
+	return nil
+}
+
+// startDryRun copies the repository into a temporary directory where the files are generated so that the repository
+// being imported is left untouched
+func (options *ImportOptions) startDryRun() error {
+	if options.dryRunInPlace {
+		return nil
+	}
+	dir := options.Dir
+	if !options.DisableDotGitSearch {
+		root, _, err := options.Git().FindGitConfigDir(dir)
+		if err != nil {
+			return err
+		}
+		if root != "" {
+			dir = root
+		}
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	tmpDir, err := ioutil.TempDir("", importDryRunDirPrefix)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the dry run directory")
+	}
+	previewDir := filepath.Join(tmpDir, filepath.Base(dir))
+	err = util.CopyDir(dir, previewDir, true)
+	if err != nil {
+		return errors.Wrapf(err, "failed to copy %s to %s", dir, previewDir)
+	}
+	log.Infof("dry-run so generating the files in a copy of %s at %s\n", util.ColorInfo(dir), util.ColorInfo(previewDir))
+	options.dryRunBaseDir = dir
+	options.Dir = previewDir
+	return nil
+}
+
+// printDryRun prints the detected settings, the files which would be added or modified and the actions which would
+// be performed on the Git provider and the cluster
+func (options *ImportOptions) printDryRun(pushChanges bool) error {
+	pack := options.DraftPack
+	if pack == "" {
+		pack = "none"
+	}
+	log.Infof("\nDetected settings:\n")
+	log.Infof("  Draft pack:          %s\n", util.ColorInfo(pack))
+	log.Infof("  Applications:        %s\n", util.ColorInfo(strings.Join(options.appNames(), ", ")))
+	log.Infof("  Organisation:        %s\n", util.ColorInfo(options.getOrganisationOrCurrentUser()))
+	log.Infof("  Docker registry org: %s\n", util.ColorInfo(options.getDockerRegistryOrg()))
+
+	if options.dryRunBaseDir != "" {
+		changes, err := importFileChanges(options.dryRunBaseDir, options.Dir)
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			log.Infof("\nNo files would be added or modified\n")
+		} else {
+			log.Infof("\nFiles which would be added or modified:\n")
+			log.Info(formatImportFileTree(changes))
+			for _, change := range changes {
+				if change.Diff != "" {
+					log.Infof("\n%s", util.ColorDiff(change.Diff))
+				}
+			}
+		}
+	}
+
+	log.Infof("\nRemote actions which would be performed:\n")
+	for i, action := range options.dryRunRemoteActions(pushChanges) {
+		log.Infof("  %d. %s\n", i+1, action)
+	}
+	log.Infof("\ndry-run so the Git repository, its remote and the cluster were not modified, the generated files are in %s\n", util.ColorInfo(options.Dir))
+	return nil
+}
+
+// dryRunRemoteActions describes the actions the import would perform on the Git provider and the cluster
+func (options *ImportOptions) dryRunRemoteActions(pushChanges bool) []string {
+	actions := []string{}
+	repo := options.RepoURL
+	if repo == "" {
+		server := options.GitRepositoryOptions.ServerURL
+		if options.GitServer != nil {
+			server = options.GitServer.URL
+		}
+		dir := options.dryRunBaseDir
+		if dir == "" {
+			dir = options.Dir
+		}
+		repo = options.getOrganisationOrCurrentUser() + "/" + filepath.Base(dir)
+		actions = append(actions,
+			fmt.Sprintf("create the Git repository %s on %s", util.ColorInfo(repo), util.ColorInfo(server)),
+			fmt.Sprintf("add the remote origin and push the master branch to %s", util.ColorInfo(repo)))
+	} else if pushChanges {
+		actions = append(actions, fmt.Sprintf("push the generated commits to %s", util.ColorInfo(repo)))
+	}
+	actions = append(actions, fmt.Sprintf("create the Docker repositories %s if the Docker registry is ECR",
+		util.ColorInfo(strings.Join(options.appNames(), ", "))))
+	actions = append(actions, fmt.Sprintf("register a webhook on %s which triggers the pipelines", util.ColorInfo(repo)))
+	jenkinsfile := options.Jenkinsfile
+	if jenkinsfile == "" {
+		jenkinsfile = jenkins.DefaultJenkinsfile
+	}
+	actions = append(actions, fmt.Sprintf("create the pipeline of %s using the %s as a Jenkins job or Prow configuration depending on the team",
+		util.ColorInfo(repo), util.ColorInfo(jenkinsfile)))
+	return actions
+}
+
+// importFileChanges compares the files of the directory with the base directory ignoring the .git directory,
+// returning the changes sorted by path
+func importFileChanges(baseDir string, dir string) ([]importFileChange, error) {
+	baseFiles, err := importFiles(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	files, err := importFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	answer := []importFileChange{}
+	for _, path := range files {
+		data, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+		if err != nil {
+			return nil, err
+		}
+		change := importFileChange{Path: path, Status: importFileAdded}
+		fromName := "/dev/null"
+		var baseData []byte
+		if util.StringArrayIndex(baseFiles, path) >= 0 {
+			baseData, err = ioutil.ReadFile(filepath.Join(baseDir, filepath.FromSlash(path)))
+			if err != nil {
+				return nil, err
+			}
+			if bytes.Equal(data, baseData) {
+				continue
+			}
+			change.Status = importFileModified
+			fromName = "a/" + path
+		}
+		if bytes.IndexByte(data, 0) < 0 && bytes.IndexByte(baseData, 0) < 0 {
+			change.Diff, err = util.UnifiedDiff(string(baseData), string(data), fromName, "b/"+path)
+			if err != nil {
+				return nil, err
+			}
+		}
+		answer = append(answer, change)
+	}
+	for _, path := range baseFiles {
+		if util.StringArrayIndex(files, path) < 0 {
+			answer = append(answer, importFileChange{Path: path, Status: importFileDeleted})
+		}
+	}
+	sort.Slice(answer, func(i, j int) bool {
+		return answer[i].Path < answer[j].Path
+	})
+	return answer, nil
+}
+
+// importFiles returns the slash separated paths of the files in the directory outside of the .git directory
+func importFiles(dir string) ([]string, error) {
+	answer := []string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		answer = append(answer, filepath.ToSlash(rel))
+		return nil
+	})
+	return answer, err
+}
+
+// formatImportFileTree renders the changes sorted by path as a tree of directories and files
+func formatImportFileTree(changes []importFileChange) string {
+	var buffer strings.Builder
+	printedDirs := map[string]bool{}
+	for _, change := range changes {
+		parts := strings.Split(change.Path, "/")
+		for i := 0; i < len(parts)-1; i++ {
+			dir := strings.Join(parts[0:i+1], "/")
+			if !printedDirs[dir] {
+				printedDirs[dir] = true
+				buffer.WriteString(fmt.Sprintf("  %s%s/\n", strings.Repeat("  ", i), parts[i]))
+			}
+		}
+		depth := len(parts) - 1
+		buffer.WriteString(fmt.Sprintf("  %s%s (%s)\n", strings.Repeat("  ", depth), parts[depth], change.Status))
+	}
+	return buffer.String()
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportFileChanges(t *testing.T) {
+	t.Parallel()
+	baseDir, err := ioutil.TempDir("", "test-import-dry-run-base")
+	require.NoError(t, err)
+	defer os.RemoveAll(baseDir)
+	dir, err := ioutil.TempDir("", "test-import-dry-run")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeTestFile := func(dir string, path string, text string) {
+		file := filepath.Join(dir, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), DefaultWritePermissions))
+		require.NoError(t, ioutil.WriteFile(file, []byte(text), DefaultWritePermissions))
+	}
+	writeTestFile(baseDir, "README.md", "hello\n")
+	writeTestFile(baseDir, "Jenkinsfile", "old\n")
+	writeTestFile(baseDir, "OLD.txt", "removed\n")
+	writeTestFile(baseDir, ".git/config", "base\n")
+	writeTestFile(dir, "README.md", "hello\n")
+	writeTestFile(dir, "Jenkinsfile", "new\n")
+	writeTestFile(dir, "Dockerfile", "FROM scratch\n")
+	writeTestFile(dir, "charts/myapp/Chart.yaml", "name: myapp\n")
+	writeTestFile(dir, ".git/config", "changed\n")
+
+	changes, err := importFileChanges(baseDir, dir)
+	require.NoError(t, err)
+	require.Len(t, changes, 4)
+
+	assert.Equal(t, "Dockerfile", changes[0].Path)
+	assert.Equal(t, importFileAdded, changes[0].Status)
+	assert.Contains(t, changes[0].Diff, "--- /dev/null")
+	assert.Contains(t, changes[0].Diff, "+FROM scratch")
+
+	assert.Equal(t, "Jenkinsfile", changes[1].Path)
+	assert.Equal(t, importFileModified, changes[1].Status)
+	assert.Contains(t, changes[1].Diff, "-old")
+	assert.Contains(t, changes[1].Diff, "+new")
+
+	assert.Equal(t, "OLD.txt", changes[2].Path)
+	assert.Equal(t, importFileDeleted, changes[2].Status)
+
+	assert.Equal(t, "charts/myapp/Chart.yaml", changes[3].Path)
+	assert.Equal(t, importFileAdded, changes[3].Status)
+}
+
+func TestFormatImportFileTree(t *testing.T) {
+	t.Parallel()
+	changes := []importFileChange{
+		{Path: "Dockerfile", Status: importFileAdded},
+		{Path: "charts/myapp/Chart.yaml", Status: importFileAdded},
+		{Path: "charts/myapp/values.yaml", Status: importFileAdded},
+		{Path: "charts/preview/Chart.yaml", Status: importFileAdded},
+		{Path: "pom.xml", Status: importFileModified},
+	}
+	expected := `  Dockerfile (added)
+  charts/
+    myapp/
+      Chart.yaml (added)
+      values.yaml (added)
+    preview/
+      Chart.yaml (added)
+  pom.xml (modified)
+`
+	assert.Equal(t, expected, formatImportFileTree(changes))
+}
+
+func TestStartDryRunOfCreatedProjectGeneratesInPlace(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-import-dry-run-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	o := &ImportOptions{DisableDotGitSearch: true}
+	o.Dir = dir
+	err = o.startDryRun()
+	require.NoError(t, err)
+	defer os.RemoveAll(filepath.Dir(o.Dir))
+	assert.NotEqual(t, dir, o.Dir, "an existing repository should be copied")
+	assert.Equal(t, dir, o.dryRunBaseDir)
+
+	o = &ImportOptions{DisableDotGitSearch: true, dryRunInPlace: true}
+	o.Dir = dir
+	err = o.startDryRun()
+	require.NoError(t, err)
+	assert.Equal(t, dir, o.Dir, "a created project should be generated in place")
+	assert.Equal(t, "", o.dryRunBaseDir)
+}
@@ -83,6 +83,8 @@ func assertImport(t *testing.T, testDir string, testcase string, withRename bool
 	err := o.Run()
 	assert.NoError(t, err, "Failed with %s", err)
 	if err == nil {
+		// the dry run generates the files in a copy of the directory
+		testDir = o.Dir
 		defaultJenkinsfile := filepath.Join(testDir, jenkins.DefaultJenkinsfile)
 		jenkinsfile := defaultJenkinsfile
 		if o.Jenkinsfile != "" && o.Jenkinsfile != jenkins.DefaultJenkinsfile {
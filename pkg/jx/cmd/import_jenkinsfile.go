@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/jenkins-x/jx/pkg/jenkins"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// existingJenkinsfileModes the ways of handling an existing Jenkinsfile when importing a project
+var existingJenkinsfileModes = []string{ExistingJenkinsfileKeep, ExistingJenkinsfileRename, ExistingJenkinsfileMerge}
+
+// pickExistingJenkinsfileMode returns how to handle the existing Jenkinsfile asking the user in interactive mode if
+// the --existing-jenkinsfile flag is not specified. An empty string is returned in batch mode without the flag
+func (options *ImportOptions) pickExistingJenkinsfileMode(jenkinsfile string) (string, error) {
+	if options.ExistingJenkinsfile != "" || options.BatchMode {
+		return options.ExistingJenkinsfile, nil
+	}
+	log.Infof("The project already has the Jenkinsfile %s\n", util.ColorInfo(jenkinsfile))
+	return util.PickNameWithDefault(existingJenkinsfileModes, "How do you want to handle the existing Jenkinsfile:",
+		ExistingJenkinsfileMerge, options.In, options.Out, options.Err)
+}
+
+// applyExistingJenkinsfile combines the existing Jenkinsfile with the Jenkinsfile generated by the pack in its place
+// then writes the result, showing the changes to the existing Jenkinsfile for confirmation in interactive mode
+func (options *ImportOptions) applyExistingJenkinsfile(jenkinsfile string, existing string, mode string) error {
+	exists, err := util.FileExists(jenkinsfile)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		log.Warnf("The pack did not generate a Jenkinsfile so keeping the existing %s\n", jenkinsfile)
+		return ioutil.WriteFile(jenkinsfile, []byte(existing), DefaultWritePermissions)
+	}
+	data, err := ioutil.ReadFile(jenkinsfile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load %s", jenkinsfile)
+	}
+	packJenkinsfile := string(data)
+
+	text := existing
+	switch mode {
+	case ExistingJenkinsfileRename:
+		originalFile := jenkinsfile + JenkinsfileOriginalSuffix
+		err = ioutil.WriteFile(originalFile, []byte(existing), DefaultWritePermissions)
+		if err != nil {
+			return errors.Wrapf(err, "failed to write %s", originalFile)
+		}
+		log.Infof("Moved the existing Jenkinsfile to %s and used the Jenkinsfile of the pack\n", util.ColorInfo(originalFile))
+		return nil
+	case ExistingJenkinsfileKeep:
+		text, err = jenkins.AddReleaseStages(existing, packJenkinsfile)
+	default:
+		text, err = jenkins.MergeJenkinsfile(existing, packJenkinsfile)
+	}
+	if err != nil {
+		log.Warnf("Keeping the existing %s unchanged as it could not be combined with the Jenkinsfile of the pack: %s\n", jenkinsfile, err)
+		text = existing
+	} else if text != existing && !options.BatchMode {
+		name := filepath.Base(jenkinsfile)
+		diff, err := util.UnifiedDiff(existing, text, "a/"+name, "b/"+name)
+		if err != nil {
+			return err
+		}
+		log.Infof("The changes to the existing %s:\n\n%s\n", util.ColorInfo(jenkinsfile), util.ColorDiff(diff))
+		if !util.Confirm("Do you want to commit the changes to the Jenkinsfile?", true,
+			"If not the existing Jenkinsfile is kept unchanged", options.In, options.Out, options.Err) {
+			text = existing
+		}
+	}
+	if text == existing {
+		log.Infof("Kept the existing %s unchanged\n", util.ColorInfo(jenkinsfile))
+	} else {
+		log.Infof("Combined the existing %s with the Jenkinsfile of the pack\n", util.ColorInfo(jenkinsfile))
+	}
+	return ioutil.WriteFile(jenkinsfile, []byte(text), DefaultWritePermissions)
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testExistingJenkinsfile = `pipeline {
+  agent any
+  stages {
+    stage('Build') {
+      steps {
+        sh "make"
+      }
+    }
+  }
+}
+`
+	testPackJenkinsfile = `pipeline {
+  agent any
+  environment {
+    APP_NAME = 'myapp'
+  }
+  stages {
+    stage('Promote to Environments') {
+      steps {
+        sh "jx promote -b --all-auto"
+      }
+    }
+  }
+}
+`
+)
+
+func TestApplyExistingJenkinsfile(t *testing.T) {
+	t.Parallel()
+	for _, mode := range existingJenkinsfileModes {
+		dir, err := ioutil.TempDir("", "test-existing-jenkinsfile")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		jenkinsfile := filepath.Join(dir, "Jenkinsfile")
+		require.NoError(t, ioutil.WriteFile(jenkinsfile, []byte(testPackJenkinsfile), DefaultWritePermissions))
+
+		options := &ImportOptions{}
+		options.BatchMode = true
+		require.NoError(t, options.applyExistingJenkinsfile(jenkinsfile, testExistingJenkinsfile, mode), "mode %s", mode)
+
+		switch mode {
+		case ExistingJenkinsfileRename:
+			data, err := ioutil.ReadFile(jenkinsfile + JenkinsfileOriginalSuffix)
+			require.NoError(t, err)
+			assert.Equal(t, testExistingJenkinsfile, string(data))
+			data, err = ioutil.ReadFile(jenkinsfile)
+			require.NoError(t, err)
+			assert.Equal(t, testPackJenkinsfile, string(data))
+		case ExistingJenkinsfileKeep:
+			tests.AssertFileContains(t, jenkinsfile, `sh "make"`)
+			tests.AssertFileContains(t, jenkinsfile, "stage('Promote to Environments') {\n      environment {\n        APP_NAME = 'myapp'")
+			tests.AssertFileDoesNotExist(t, jenkinsfile+JenkinsfileOriginalSuffix)
+		case ExistingJenkinsfileMerge:
+			tests.AssertFileContains(t, jenkinsfile, `sh "make"`)
+			tests.AssertFileContains(t, jenkinsfile, "  agent any\n  environment {\n    APP_NAME = 'myapp'\n  }")
+			tests.AssertFileContains(t, jenkinsfile, "stage('Promote to Environments')")
+		}
+	}
+}
+
+func TestApplyExistingJenkinsfileKeepsScriptedPipelines(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-existing-jenkinsfile")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	jenkinsfile := filepath.Join(dir, "Jenkinsfile")
+	require.NoError(t, ioutil.WriteFile(jenkinsfile, []byte(testPackJenkinsfile), DefaultWritePermissions))
+	scripted := "node {\n  sh 'make'\n}\n"
+
+	options := &ImportOptions{}
+	options.BatchMode = true
+	require.NoError(t, options.applyExistingJenkinsfile(jenkinsfile, scripted, ExistingJenkinsfileMerge))
+
+	data, err := ioutil.ReadFile(jenkinsfile)
+	require.NoError(t, err)
+	assert.Equal(t, scripted, string(data))
+}
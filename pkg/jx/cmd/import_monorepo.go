@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/jenkins"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// monorepoAppFiles the files whose presence in a directory makes it an application when discovering the applications
+// of a monorepo
+var monorepoAppFiles = []string{"Dockerfile", "pom.xml", "package.json"}
+
+// monorepoSkipDirs the directories which are not searched when discovering the applications of a monorepo
+var monorepoSkipDirs = []string{"charts", "node_modules", "target", "vendor"}
+
+// resolveMonorepoAppDirs validates the --app-dir flags or discovers the application directories of the monorepo,
+// storing them as slash separated paths relative to the root of the repository
+func (options *ImportOptions) resolveMonorepoAppDirs() error {
+	if options.DiscoverApps {
+		dirs, err := discoverMonorepoAppDirs(options.Dir)
+		if err != nil {
+			return err
+		}
+		if len(dirs) == 0 {
+			return fmt.Errorf("no directories containing a %s were found in %s", strings.Join(monorepoAppFiles, ", "), options.Dir)
+		}
+		options.AppDirs = append(options.AppDirs, dirs...)
+	}
+	answer := []string{}
+	for _, appDir := range options.AppDirs {
+		dir := filepath.ToSlash(filepath.Clean(appDir))
+		if dir == "." || dir == ".." || strings.HasPrefix(dir, "../") || filepath.IsAbs(appDir) {
+			return util.InvalidOptionf("app-dir", appDir, "the directory must be inside the repository %s", options.Dir)
+		}
+		exists, err := util.FileExists(filepath.Join(options.Dir, dir))
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return util.InvalidOptionf("app-dir", appDir, "the directory does not exist in %s", options.Dir)
+		}
+		if util.StringArrayIndex(answer, dir) < 0 {
+			answer = append(answer, dir)
+		}
+	}
+	options.AppDirs = answer
+	log.Infof("Importing the applications in the directories %s\n", util.ColorInfo(strings.Join(options.AppDirs, ", ")))
+	return nil
+}
+
+// discoverMonorepoAppDirs returns the directories below the root directory containing an application without
+// searching inside the applications
+func discoverMonorepoAppDirs(rootDir string) ([]string, error) {
+	answer := []string{}
+	err := filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() || path == rootDir {
+			return nil
+		}
+		name := info.Name()
+		if strings.HasPrefix(name, ".") || util.StringArrayIndex(monorepoSkipDirs, name) >= 0 {
+			return filepath.SkipDir
+		}
+		for _, file := range monorepoAppFiles {
+			exists, err := util.FileExists(filepath.Join(path, file))
+			if err != nil {
+				return err
+			}
+			if exists {
+				rel, err := filepath.Rel(rootDir, path)
+				if err != nil {
+					return err
+				}
+				answer = append(answer, filepath.ToSlash(rel))
+				return filepath.SkipDir
+			}
+		}
+		return nil
+	})
+	return answer, err
+}
+
+// monorepoAppName returns the name of the application in the directory of the monorepo which its release and chart
+// are named after
+func (options *ImportOptions) monorepoAppName(appDir string) string {
+	return kube.ToValidName(strings.ToLower(options.AppName + "-" + filepath.Base(appDir)))
+}
+
+// appNames returns the names of the applications being imported
+func (options *ImportOptions) appNames() []string {
+	if len(options.AppDirs) == 0 {
+		return []string{options.AppName}
+	}
+	answer := []string{}
+	for _, appDir := range options.AppDirs {
+		answer = append(answer, options.monorepoAppName(appDir))
+	}
+	return answer
+}
+
+// importMonorepoApps generates the Dockerfile, chart and Jenkinsfile of each application directory of the monorepo
+// then combines the Jenkinsfiles into a single Jenkinsfile whose stages only build the applications which changed
+func (options *ImportOptions) importMonorepoApps() error {
+	apps := []jenkins.MonorepoApp{}
+	for _, appDir := range options.AppDirs {
+		appOptions := &ImportOptions{
+			CommonOptions:           options.CommonOptions,
+			Dir:                     filepath.Join(options.Dir, filepath.FromSlash(appDir)),
+			AppName:                 options.monorepoAppName(appDir),
+			Organisation:            options.Organisation,
+			DryRun:                  options.DryRun,
+			DisableJenkinsfileCheck: options.DisableJenkinsfileCheck,
+			DraftPack:               options.DraftPack,
+			DockerRegistryOrg:       options.DockerRegistryOrg,
+			InitialisedGit:          options.InitialisedGit,
+			GitProvider:             options.GitProvider,
+			PostDraftPackCallback:   options.PostDraftPackCallback,
+			DisableMaven:            options.DisableMaven,
+			ExistingJenkinsfile:     options.ExistingJenkinsfile,
+		}
+		log.Infof("Importing the application %s in the directory %s\n", util.ColorInfo(appOptions.AppName), util.ColorInfo(appDir))
+		if !options.DisableDraft {
+			err := appOptions.DraftCreate()
+			if err != nil {
+				return errors.Wrapf(err, "failed to create the application in the directory %s", appDir)
+			}
+		}
+		err := appOptions.fixDockerIgnoreFile()
+		if err != nil {
+			return err
+		}
+		err = appOptions.fixMaven()
+		if err != nil {
+			return err
+		}
+
+		jenkinsfile := filepath.Join(appOptions.Dir, jenkins.DefaultJenkinsfile)
+		exists, err := util.FileExists(jenkinsfile)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			log.Warnf("No %s was generated for the application in the directory %s so it is not built\n", jenkins.DefaultJenkinsfile, appDir)
+			continue
+		}
+		data, err := ioutil.ReadFile(jenkinsfile)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load %s", jenkinsfile)
+		}
+		apps = append(apps, jenkins.MonorepoApp{
+			Dir:         appDir,
+			Name:        appOptions.AppName,
+			Jenkinsfile: string(data),
+		})
+		err = os.Remove(jenkinsfile)
+		if err != nil {
+			return err
+		}
+	}
+	if len(apps) == 0 {
+		return nil
+	}
+	return options.writeMonorepoJenkinsfile(apps)
+}
+
+// writeMonorepoJenkinsfile writes the Jenkinsfile combining the stages of the applications into the root of the
+// repository, keeping a backup of an existing Jenkinsfile
+func (options *ImportOptions) writeMonorepoJenkinsfile(apps []jenkins.MonorepoApp) error {
+	text, err := jenkins.MonorepoJenkinsfile(apps)
+	if err != nil {
+		return err
+	}
+	name := options.Jenkinsfile
+	if name == "" {
+		name = jenkins.DefaultJenkinsfile
+	}
+	jenkinsfile := filepath.Join(options.Dir, name)
+	exists, err := util.FileExists(jenkinsfile)
+	if err != nil {
+		return err
+	}
+	if exists {
+		backup := jenkinsfile + JenkinsfileBackupSuffix
+		err = util.RenameFile(jenkinsfile, backup)
+		if err != nil {
+			return fmt.Errorf("Failed to rename old Jenkinsfile: %s", err)
+		}
+		log.Warnf("Moved the existing %s to %s\n", jenkinsfile, backup)
+	}
+	err = ioutil.WriteFile(jenkinsfile, []byte(text), DefaultWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to write %s", jenkinsfile)
+	}
+	log.Infof("Generated the %s building the applications %s\n", util.ColorInfo(jenkinsfile), util.ColorInfo(strings.Join(options.appNames(), ", ")))
+
+	err = options.Git().Add(options.Dir, "*")
+	if err != nil {
+		return err
+	}
+	return options.Git().CommitIfChanges(options.Dir, "Generate the monorepo Jenkinsfile")
+}
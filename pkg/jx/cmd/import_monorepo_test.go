@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createMonorepo(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "test-monorepo-")
+	require.NoError(t, err)
+	files := []string{
+		"services/foo/Dockerfile",
+		"services/foo/nested/package.json",
+		"services/bar/pom.xml",
+		"web/node_modules/lib/package.json",
+		"web/package.json",
+		"docs/README.md",
+		".github/Dockerfile",
+	}
+	for _, file := range files {
+		path := filepath.Join(dir, filepath.FromSlash(file))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), DefaultWritePermissions))
+		require.NoError(t, ioutil.WriteFile(path, []byte{}, DefaultWritePermissions))
+	}
+	return dir
+}
+
+func TestDiscoverMonorepoAppDirs(t *testing.T) {
+	t.Parallel()
+	dir := createMonorepo(t)
+	defer os.RemoveAll(dir)
+
+	dirs, err := discoverMonorepoAppDirs(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"services/bar", "services/foo", "web"}, dirs)
+}
+
+func TestResolveMonorepoAppDirs(t *testing.T) {
+	t.Parallel()
+	dir := createMonorepo(t)
+	defer os.RemoveAll(dir)
+
+	options := &ImportOptions{
+		Dir:     dir,
+		AppName: "myrepo",
+		AppDirs: []string{"./services/foo/", "services/foo", "web"},
+	}
+	err := options.resolveMonorepoAppDirs()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"services/foo", "web"}, options.AppDirs)
+	assert.Equal(t, []string{"myrepo-foo", "myrepo-web"}, options.appNames())
+
+	for _, appDir := range []string{"..", "docs/missing", "."} {
+		options.AppDirs = []string{appDir}
+		err = options.resolveMonorepoAppDirs()
+		assert.Error(t, err, "app dir %s", appDir)
+	}
+}
@@ -0,0 +1,275 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/prow"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	importRepositorySucceeded = "succeeded"
+	importRepositoryFailed    = "failed"
+	importRepositorySkipped   = "skipped"
+
+	defaultImportConcurrency = 4
+)
+
+// importRepositoryResult the outcome of importing one repository of an organisation
+type importRepositoryResult struct {
+	Repository string
+	Status     string
+	Reason     string
+}
+
+// importSkippedError is returned when a repository does not need to be imported
+type importSkippedError struct {
+	reason string
+}
+
+func (e *importSkippedError) Error() string {
+	return e.reason
+}
+
+// importAllRepositories imports the repositories of the organisation which match the filter and are not excluded,
+// letting the user select them in interactive mode, using a bounded number of concurrent imports
+func (options *ImportOptions) importAllRepositories(isProw bool) error {
+	org := options.Organisation
+	if org == "" {
+		return util.MissingOption("org")
+	}
+	if options.Concurrency < 1 {
+		return fmt.Errorf("the concurrency must be at least 1 but was %d", options.Concurrency)
+	}
+	if options.Dir == "" {
+		dir, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		options.Dir = dir
+	}
+
+	repos, err := options.GitProvider.ListRepositories(org)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list the repositories of %s", org)
+	}
+	repoMap := map[string]*gits.GitRepository{}
+	names := []string{}
+	for _, repo := range repos {
+		if repo.Name != "" && matchesImportRepository(repo.Name, options.SelectFilter, options.ExcludeRepos) {
+			repoMap[repo.Name] = repo
+			names = append(names, repo.Name)
+		}
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("no repositories of %s match the filter", org)
+	}
+	sort.Strings(names)
+	if !options.BatchMode {
+		names, err = util.SelectNames(names, "Which repositories do you want to import:", true, options.In, options.Out, options.Err)
+		if err != nil {
+			return err
+		}
+	}
+	log.Infof("Importing %d repositories of %s\n", len(names), util.ColorInfo(org))
+
+	results := make([]importRepositoryResult, len(names))
+	jobs := make(chan int)
+	lock := &sync.Mutex{}
+	failed := false
+	hasFailed := func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return failed
+	}
+	importLock := &sync.Mutex{}
+	var wg sync.WaitGroup
+	for w := 0; w < options.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result := options.importRepository(repoMap[names[i]], isProw, importLock)
+				if result.Status == importRepositoryFailed {
+					lock.Lock()
+					failed = true
+					lock.Unlock()
+				}
+				results[i] = result
+			}
+		}()
+	}
+	for i, name := range names {
+		if !options.ContinueOnError && hasFailed() {
+			results[i] = importRepositoryResult{
+				Repository: name,
+				Status:     importRepositorySkipped,
+				Reason:     "an earlier import failed, use --continue-on-error to import the remaining repositories",
+			}
+			continue
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return options.printImportRepositoryResults(results)
+}
+
+// importRepository imports a repository of the organisation unless it is already imported
+func (options *ImportOptions) importRepository(repo *gits.GitRepository, isProw bool, importLock *sync.Mutex) importRepositoryResult {
+	result := importRepositoryResult{
+		Repository: repo.Name,
+		Status:     importRepositorySucceeded,
+	}
+	if !options.DryRun {
+		registered, err := options.isRepositoryRegistered(repo.Name, isProw)
+		if err != nil {
+			result.Status = importRepositoryFailed
+			result.Reason = fmt.Sprintf("failed to check if the repository is registered: %s", err)
+			return result
+		}
+		if registered {
+			result.Status = importRepositorySkipped
+			result.Reason = "already registered"
+			return result
+		}
+	}
+
+	o := &ImportOptions{
+		CommonOptions:           options.CommonOptions,
+		Dir:                     options.Dir,
+		RepoURL:                 repo.CloneURL,
+		Organisation:            options.Organisation,
+		Repository:              repo.Name,
+		Credentials:             options.Credentials,
+		DryRun:                  options.DryRun,
+		DisableDraft:            options.DisableDraft,
+		DisableJenkinsfileCheck: options.DisableJenkinsfileCheck,
+		Jenkinsfile:             options.Jenkinsfile,
+		BranchPattern:           options.BranchPattern,
+		DraftPack:               options.DraftPack,
+		DockerRegistryOrg:       options.DockerRegistryOrg,
+		ExistingJenkinsfile:     options.ExistingJenkinsfile,
+		Jenkins:                 options.Jenkins,
+		GitServer:               options.GitServer,
+		GitUserAuth:             options.GitUserAuth,
+		GitProvider:             options.GitProvider,
+		DisableMaven:            options.DisableMaven,
+		skipJenkinsXRepository:  true,
+		importLock:              importLock,
+	}
+	o.BatchMode = true
+	log.Infof("Importing repository %s\n", util.ColorInfo(repo.Name))
+	err := o.Run()
+	if err != nil {
+		if skipped, ok := err.(*importSkippedError); ok {
+			result.Status = importRepositorySkipped
+			result.Reason = skipped.reason
+		} else {
+			result.Status = importRepositoryFailed
+			result.Reason = err.Error()
+		}
+	}
+	return result
+}
+
+// isRepositoryRegistered returns true if the repository of the organisation already has a Jenkins job or prow jobs
+func (options *ImportOptions) isRepositoryRegistered(name string, isProw bool) (bool, error) {
+	if isProw {
+		return prow.IsRepositoryRegistered(options.KubeClientCached, options.currentNamespace, options.Organisation+"/"+name)
+	}
+	if options.Jenkins == nil {
+		return false, nil
+	}
+	_, err := options.Jenkins.GetJobByPath(gits.FlattenOrganisation(options.Organisation), name)
+	return err == nil, nil
+}
+
+// checkJenkinsXRepository returns an importSkippedError when importing the repositories of an organisation and the
+// cloned repository already contains a jenkins-x.yml, removing the clone
+func (options *ImportOptions) checkJenkinsXRepository() error {
+	if !options.skipJenkinsXRepository {
+		return nil
+	}
+	exists, err := util.FileExists(filepath.Join(options.Dir, config.ProjectConfigFileName))
+	if err != nil || !exists {
+		return err
+	}
+	err = os.RemoveAll(options.Dir)
+	if err != nil {
+		log.Warnf("Failed to remove the clone %s: %s\n", options.Dir, err)
+	}
+	return &importSkippedError{reason: fmt.Sprintf("already contains a %s", config.ProjectConfigFileName)}
+}
+
+// withImportLock invokes the function holding the lock shared by the concurrent imports of an organisation so that
+// they do not update the build packs, Jenkins or the prow configuration at the same time
+func (options *ImportOptions) withImportLock(fn func() error) error {
+	if options.importLock != nil {
+		options.importLock.Lock()
+		defer options.importLock.Unlock()
+	}
+	return fn()
+}
+
+// printImportRepositoryResults prints the outcome of importing each repository returning an error if any failed
+func (options *ImportOptions) printImportRepositoryResults(results []importRepositoryResult) error {
+	counts := map[string]int{}
+	table := options.CreateTable()
+	table.AddRow("REPOSITORY", "STATUS", "REASON")
+	for _, result := range results {
+		counts[result.Status]++
+		status := result.Status
+		switch status {
+		case importRepositorySucceeded:
+			status = util.ColorInfo(status)
+		case importRepositoryFailed:
+			status = util.ColorError(status)
+		default:
+			status = util.ColorWarning(status)
+		}
+		table.AddRow(result.Repository, status, result.Reason)
+	}
+	log.Blank()
+	table.Render()
+	log.Blank()
+	log.Infof("%d succeeded, %d failed and %d skipped\n", counts[importRepositorySucceeded],
+		counts[importRepositoryFailed], counts[importRepositorySkipped])
+	if counts[importRepositoryFailed] > 0 {
+		return fmt.Errorf("failed to import %d of the %d repositories", counts[importRepositoryFailed], len(results))
+	}
+	return nil
+}
+
+// matchesImportRepository returns true if the repository name matches the filter and none of the exclude patterns.
+// The filter and exclude patterns are glob patterns though a filter without glob characters matches any name
+// containing it
+func matchesImportRepository(name string, filter string, excludes []string) bool {
+	if filter != "" {
+		if strings.ContainsAny(filter, "*?[") {
+			matched, err := path.Match(filter, name)
+			if err != nil || !matched {
+				return false
+			}
+		} else if !strings.Contains(name, filter) {
+			return false
+		}
+	}
+	for _, exclude := range excludes {
+		if matched, err := path.Match(exclude, name); err == nil && matched {
+			return false
+		}
+	}
+	return true
+}
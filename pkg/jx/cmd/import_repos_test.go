@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchesImportRepository(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		filter   string
+		excludes []string
+		expected bool
+	}{
+		{"svc-orders", "", nil, true},
+		{"svc-orders", "orders", nil, true},
+		{"svc-orders", "payments", nil, false},
+		{"svc-orders", "svc-*", nil, true},
+		{"web-orders", "svc-*", nil, false},
+		{"svc-orders", "svc-?rders", nil, true},
+		{"svc-docs", "svc-*", []string{"*-docs"}, false},
+		{"svc-orders", "svc-*", []string{"*-docs", "legacy"}, true},
+		{"legacy", "", []string{"*-docs", "legacy"}, false},
+	}
+	for _, test := range tests {
+		actual := matchesImportRepository(test.name, test.filter, test.excludes)
+		assert.Equal(t, test.expected, actual, "repository %s with filter %q and excludes %v", test.name, test.filter, test.excludes)
+	}
+}
+
+func TestCheckJenkinsXRepository(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-import-repos")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	o := &ImportOptions{Dir: dir}
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, config.ProjectConfigFileName), []byte{}, DefaultWritePermissions))
+	assert.NoError(t, o.checkJenkinsXRepository(), "only checked when importing the repositories of an organisation")
+
+	o.skipJenkinsXRepository = true
+	err = o.checkJenkinsXRepository()
+	require.Error(t, err)
+	_, skipped := err.(*importSkippedError)
+	assert.True(t, skipped, "the repository is skipped")
+	_, err = os.Stat(dir)
+	assert.True(t, os.IsNotExist(err), "the clone is removed")
+
+	require.NoError(t, os.MkdirAll(dir, DefaultWritePermissions))
+	assert.NoError(t, o.checkJenkinsXRepository())
+}
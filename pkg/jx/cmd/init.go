@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/jenkins-x/jx/pkg/cloud/amazon"
+	"github.com/jenkins-x/jx/pkg/cloud/kind"
 	"github.com/jenkins-x/jx/pkg/helm"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/kube"
@@ -33,6 +34,11 @@ type InitOptions struct {
 	CommonOptions
 	Client clientset.Clientset
 	Flags  InitFlags
+
+	// ingressControllerValues the helm values of the ingress controller chart chosen by the create cluster command
+	ingressControllerValues []string
+	// externalDNS whether external-dns manages the records of the domain so none are registered by init
+	externalDNS bool
 }
 
 type InitFlags struct {
@@ -56,9 +62,13 @@ type InitFlags struct {
 	RemoteTiller               bool
 	GlobalTiller               bool
 	SkipIngress                bool
+	SkipIngressInstall         bool
+	IngressClass               string
+	IngressKind                string
 	SkipTiller                 bool
 	OnPremise                  bool
 	Http                       bool
+	NamespaceScoped            bool
 }
 
 const (
@@ -134,6 +144,9 @@ func (options *InitOptions) addInitFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVarP(&options.Flags.RemoteTiller, "remote-tiller", "", true, "If enabled and we are using tiller for helm then run tiller remotely in the kubernetes cluster. Otherwise we run the tiller process locally.")
 	cmd.Flags().BoolVarP(&options.Flags.NoTiller, "no-tiller", "", false, "Whether to disable the use of tiller with helm. If disabled we use 'helm template' to generate the YAML from helm charts then we use 'kubectl apply' to install it to avoid using tiller completely.")
 	cmd.Flags().BoolVarP(&options.Flags.SkipIngress, "skip-ingress", "", false, "Don't install an ingress controller")
+	cmd.Flags().BoolVarP(&options.Flags.SkipIngressInstall, "skip-ingress-install", "", false, "Reuse the existing ingress controller of the --ingress-namespace and --ingress-service flags rather than installing one")
+	cmd.Flags().StringVarP(&options.Flags.IngressClass, "ingress-class", "", "", "The ingress class of the existing ingress controller which exposecontroller annotates the ingresses with")
+	cmd.Flags().StringVarP(&options.Flags.IngressKind, "ingress-kind", "", kube.IngressKindNginx, fmt.Sprintf("The kind of the ingress controller whose annotations are used. One of: %s", strings.Join(kube.IngressKinds, ", ")))
 	cmd.Flags().BoolVarP(&options.Flags.SkipTiller, "skip-tiller", "", false, "Don't install a Helm Tiller service")
 	cmd.Flags().BoolVarP(&options.Flags.Helm3, "helm3", "", false, "Use helm3 to install Jenkins X which does not use Tiller")
 	cmd.Flags().BoolVarP(&options.Flags.OnPremise, "on-premise", "", false, "If installing on an on premise cluster then lets default the 'external-ip' to be the Kubernetes master IP address")
@@ -156,9 +169,21 @@ func (o *InitOptions) Run() error {
 		return err
 	}
 
-	err = o.enableClusterAdminRole()
-	if err != nil {
-		return err
+	if o.Flags.NamespaceScoped {
+		log.Warnf("Not binding the %s cluster role to the current user as the install is namespace scoped\n", o.Flags.UserClusterRole)
+		if o.Flags.GlobalTiller {
+			log.Warnf("Using a tiller in the team namespace rather than a global tiller as the install is namespace scoped\n")
+			o.Flags.GlobalTiller = false
+		}
+		if !o.Flags.SkipIngress && !o.Flags.SkipIngressInstall {
+			log.Warnf("Not installing an ingress controller as it needs cluster roles, ask a cluster administrator to install one in namespace %s\n", o.ingressNamespace())
+			o.Flags.SkipIngress = true
+		}
+	} else {
+		err = o.enableClusterAdminRole()
+		if err != nil {
+			return err
+		}
 	}
 
 	// helm init, this has been seen to fail intermittently on public clouds, so lets retry a couple of times
@@ -391,7 +416,7 @@ func (o *InitOptions) initHelm() error {
 		}
 	}
 
-	err = o.Helm().AddRepo("jenkins-x", DEFAULT_CHARTMUSEUM_URL)
+	err = o.Helm().AddRepo("jenkins-x", o.offlineConfig.ChartRepositoryURL(DEFAULT_CHARTMUSEUM_URL), "", "")
 	if err != nil {
 		return err
 	}
@@ -444,6 +469,10 @@ func (o *InitOptions) initIngress() error {
 
 	ingressNamespace := o.Flags.IngressNamespace
 
+	if o.Flags.SkipIngressInstall {
+		return o.useExistingIngress(client, ingressNamespace)
+	}
+
 	err = kube.EnsureNamespaceCreated(client, ingressNamespace, map[string]string{"jenkins.io/kind": "ingress"}, nil)
 	if err != nil {
 		return fmt.Errorf("Failed to ensure the ingress namespace %s is created: %s\nIs this an RBAC issue on your cluster?", ingressNamespace, err)
@@ -513,6 +542,13 @@ func (o *InitOptions) initIngress() error {
 		}
 
 		values := []string{"rbac.create=true" /*,"rbac.serviceAccountName="+ingressServiceAccount*/}
+		if o.Flags.Provider == KIND {
+			// run the controller on the node whose ports kind maps to localhost
+			values = kind.IngressControllerValues()
+		}
+		if len(o.ingressControllerValues) > 0 {
+			values = o.ingressControllerValues
+		}
 		valuesFiles := []string{}
 		valuesFiles, err = helm.AppendMyValues(valuesFiles)
 		if err != nil {
@@ -576,54 +612,93 @@ controller:
 		log.Info("existing ingress controller found, no need to install a new one\n")
 	}
 
-	if o.Flags.Provider != MINIKUBE && o.Flags.Provider != MINISHIFT && o.Flags.Provider != OPENSHIFT {
+	err = o.initIngressDomain(client, ingressNamespace)
+	if err != nil {
+		return err
+	}
 
-		log.Infof("Waiting for external loadbalancer to be created and update the nginx-ingress-controller service in %s namespace\n", ingressNamespace)
+	log.Success("nginx ingress controller installed and configured")
 
-		if o.Flags.Provider == OKE {
-			log.Infof("Note: this loadbalancer will fail to be provisioned if you have insufficient quotas, this can happen easily on a OCI free account\n")
-		}
+	return nil
+}
 
-		if o.Flags.Provider == GKE {
-			log.Infof("Note: this loadbalancer will fail to be provisioned if you have insufficient quotas, this can happen easily on a GKE free account. To view quotas run: %s\n", util.ColorInfo("gcloud compute project-info describe"))
-		}
+// useExistingIngress checks the ingress controller Service of a custom ingress controller exists then finds the
+// domain from its external address without installing an ingress controller
+func (o *InitOptions) useExistingIngress(client kubernetes.Interface, ingressNamespace string) error {
+	if util.StringArrayIndex(kube.IngressKinds, o.Flags.IngressKind) < 0 {
+		return util.InvalidOption("ingress-kind", o.Flags.IngressKind, kube.IngressKinds)
+	}
+	_, err := client.CoreV1().Services(ingressNamespace).Get(o.Flags.IngressService, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find the ingress controller Service %s in namespace %s", o.Flags.IngressService, ingressNamespace)
+	}
+	log.Infof("Using the existing %s ingress controller Service %s in namespace %s\n", o.Flags.IngressKind,
+		util.ColorInfo(o.Flags.IngressService), util.ColorInfo(ingressNamespace))
+	err = o.initIngressDomain(client, ingressNamespace)
+	if err != nil {
+		return err
+	}
+	log.Success("existing ingress controller configured")
+	return nil
+}
 
-		externalIP := o.Flags.ExternalIP
-		if externalIP == "" && o.Flags.OnPremise {
-			// lets find the Kubernetes master IP
-			config, err := o.Factory.CreateKubeConfig()
+// initIngressDomain waits for the external address of the ingress controller Service then finds the domain from it
+func (o *InitOptions) initIngressDomain(client kubernetes.Interface, ingressNamespace string) error {
+	if o.Flags.Provider == MINIKUBE || o.Flags.Provider == MINISHIFT || o.Flags.Provider == OPENSHIFT || o.Flags.Provider == KIND || o.Flags.Provider == K3S {
+		return nil
+	}
+
+	log.Infof("Waiting for external loadbalancer to be created and update the %s service in %s namespace\n", o.Flags.IngressService, ingressNamespace)
+
+	if o.Flags.Provider == OKE {
+		log.Infof("Note: this loadbalancer will fail to be provisioned if you have insufficient quotas, this can happen easily on a OCI free account\n")
+	}
+
+	if o.Flags.Provider == DOKS {
+		log.Infof("Note: DigitalOcean provisions a load balancer whose IP address is used as the external IP of the ingress controller, which can take a few minutes\n")
+	}
+
+	if o.Flags.Provider == GKE {
+		log.Infof("Note: this loadbalancer will fail to be provisioned if you have insufficient quotas, this can happen easily on a GKE free account. To view quotas run: %s\n", util.ColorInfo("gcloud compute project-info describe"))
+	}
+
+	var err error
+	externalIP := o.Flags.ExternalIP
+	if externalIP == "" && o.Flags.OnPremise {
+		// lets find the Kubernetes master IP
+		config, err := o.Factory.CreateKubeConfig()
+		if err != nil {
+			return err
+		}
+		host := config.Host
+		if host == "" {
+			log.Warnf("No API server host is defined in the local kube config!\n")
+		} else {
+			externalIP, err = util.UrlHostNameWithoutPort(host)
 			if err != nil {
-				return err
-			}
-			host := config.Host
-			if host == "" {
-				log.Warnf("No API server host is defined in the local kube config!\n")
-			} else {
-				externalIP, err = util.UrlHostNameWithoutPort(host)
-				if err != nil {
-					return fmt.Errorf("Could not parse Kubernetes master URI: %s as got: %s\nTry specifying the external IP address directly via: --external-ip", host, err)
-				}
+				return fmt.Errorf("Could not parse Kubernetes master URI: %s as got: %s\nTry specifying the external IP address directly via: --external-ip", host, err)
 			}
 		}
+	}
 
-		if externalIP == "" {
-			err = kube.WaitForExternalIP(client, o.Flags.IngressService, ingressNamespace, 10*time.Minute)
-			if err != nil {
-				return err
-			}
-			log.Infof("External loadbalancer created\n")
-		} else {
-			log.Infof("Using external IP: %s\n", util.ColorInfo(externalIP))
+	if externalIP == "" {
+		err = kube.WaitForExternalIP(client, o.Flags.IngressService, ingressNamespace, 10*time.Minute)
+		if err != nil {
+			return err
 		}
+		log.Infof("External loadbalancer created\n")
+	} else {
+		log.Infof("Using external IP: %s\n", util.ColorInfo(externalIP))
+	}
 
+	if o.externalDNS && o.Flags.Domain != "" {
+		log.Infof("The records of the domain %s are created by external-dns\n", util.ColorInfo(o.Flags.Domain))
+	} else {
 		o.Flags.Domain, err = o.GetDomain(client, o.Flags.Domain, o.Flags.Provider, ingressNamespace, o.Flags.IngressService, externalIP)
 		if err != nil {
 			return err
 		}
 	}
-
-	log.Success("nginx ingress controller installed and configured")
-
 	return nil
 }
 
@@ -702,6 +777,9 @@ func (o *CommonOptions) GetDomain(client kubernetes.Interface, domain string, pr
 				return "", err
 			}
 			address = ip
+		} else if provider == KIND || provider == K3S {
+			// kind and k3d map the ports of the ingress controller to localhost
+			address = "127.0.0.1"
 		} else {
 			info := util.ColorInfo
 			log.Infof("Waiting to find the external host name of the ingress controller Service in namespace %s with name %s\n", info(ingressNamespace), info(ingressService))
@@ -7,15 +7,23 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Pallinder/go-randomdata"
+	"github.com/ghodss/yaml"
 	"github.com/jenkins-x/jx/pkg/addon"
 	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
 	"github.com/jenkins-x/jx/pkg/cloud/amazon"
+	"github.com/jenkins-x/jx/pkg/cloud/gke"
+	"github.com/jenkins-x/jx/pkg/cloud/k3d"
+	"github.com/jenkins-x/jx/pkg/cloud/kind"
+	"github.com/jenkins-x/jx/pkg/cloud/minikube"
 	"github.com/jenkins-x/jx/pkg/config"
 	"github.com/jenkins-x/jx/pkg/gits"
 	"github.com/jenkins-x/jx/pkg/helm"
@@ -23,6 +31,7 @@ import (
 	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/jenkins-x/jx/pkg/vault"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1"
@@ -45,6 +54,22 @@ type InstallOptions struct {
 
 	InitOptions InitOptions
 	Flags       InstallFlags
+
+	// CloudResourceTags are applied to any cloud resources created by the install
+	CloudResourceTags map[string]string
+
+	// AWSProfile and AWSRegion are used to create the AWS resources of the install, such as the logs bucket, when
+	// installing onto a cluster created by jx create cluster aws or eks
+	AWSProfile string
+	AWSRegion  string
+
+	// GKEWorkloadIdentityServiceAccount is the email of the Google service account the builds act as via Workload
+	// Identity, in which case they authenticate to GCR without a docker config secret
+	GKEWorkloadIdentityServiceAccount string
+
+	installState *kube.InstallState
+	// resumingInstall is true when a previous install was interrupted before completing
+	resumingInstall bool
 }
 
 // InstallFlags flags for the install command
@@ -68,6 +93,26 @@ type InstallFlags struct {
 	Version                  string
 	Prow                     bool
 	DisableSetKubeContext    bool
+	Force                    bool
+	ExternalDNS              bool
+	SkipDNSVerify            bool
+	DNSVerifyTimeout         time.Duration
+	TLSCertFile              string
+	TLSKeyFile               string
+	ForceRestart             bool
+	ValuesFiles              []string
+	OfflineConfig            string
+	DryRun                   bool
+	DryRunDir                string
+	LogsBucket               string
+	ReportsBucket            string
+	RepositoryBucket         string
+	NamespaceScoped          bool
+	ExternalVaultURL         string
+	VaultAuthPath            string
+	VaultRole                string
+	VaultSecretPrefix        string
+	VaultProbeImage          string
 }
 
 // Secrets struct for secrets
@@ -84,10 +129,33 @@ const (
 	GitSecretsFile        = "gitSecrets.yaml"
 	AdminSecretsFile      = "adminSecrets.yaml"
 	ExtraValuesFile       = "extraValues.yaml"
+	UserValuesFile        = "userValues.yaml"
 	JXInstallConfig       = "jx-install-config"
 	CloudEnvValuesFile    = "myvalues.yaml"
 	CloudEnvSecretsFile   = "secrets.yaml"
 	defaultInstallTimeout = "6000"
+
+	// jxBuilderServiceAccount the Kubernetes service account the build pods run as
+	jxBuilderServiceAccount = "jenkins"
+)
+
+// the steps of the install which are recorded in the jx-install-state ConfigMap as they complete
+const (
+	installStepIngress        = "install-ingress"
+	installStepExternalDNS    = "install-external-dns"
+	installStepTLS            = "configure-tls"
+	installStepIngressProbe   = "probe-ingress"
+	installStepProw           = "install-prow"
+	installStepPlatform       = "install-platform"
+	installStepAddons         = "install-addons"
+	installStepJenkinsToken   = "jenkins-api-token"
+	installStepEnvironments   = "create-environments"
+	installStepLocalHelmRepo  = "register-local-helm-repo"
+	installStepStorage        = "configure-storage"
+	installStepExternalVault  = "configure-external-vault"
+	installStateProvider      = "provider"
+	installStateDomain        = "domain"
+	installStateTLSSecretName = "tlsSecretName"
 )
 
 var (
@@ -116,6 +184,18 @@ var (
 
 		# If you know the cloud provider you can pass this as a CLI argument. E.g. for AWS
 		jx install --provider=aws
+
+		# Review the manifests, values and environments the install would create without changing the cluster
+		jx install --dry-run --dry-run-dir jx-install-review
+
+		# Reuse an existing traefik ingress controller rather than installing nginx
+		jx install --skip-ingress-install --ingress-kind traefik --ingress-class traefik --ingress-namespace traefik --ingress-service traefik
+
+		# Store the secrets of the team in an existing Vault rather than in Kubernetes secrets
+		jx install --external-vault-url https://vault.example.com:8200 --vault-secret-prefix secret/teams/jx
+
+		# Install into a namespace you administer after a cluster administrator ran 'jx step install crds'
+		jx install --namespace-scoped --namespace myteam
 `)
 )
 
@@ -141,6 +221,8 @@ func NewCmdInstall(f Factory, in terminal.FileReader, out terminal.FileWriter, e
 
 	options.addCommonFlags(cmd)
 	options.addInstallFlags(cmd, false)
+	cmd.Flags().BoolVarP(&options.Flags.DryRun, "dry-run", "", false, "Renders the manifests, values and environment repositories the install would create into the --dry-run-dir without changing the cluster")
+	cmd.Flags().StringVarP(&options.Flags.DryRunDir, "dry-run-dir", "", "jx-install-dry-run", "The directory the --dry-run writes the rendered install into")
 
 	cmd.Flags().StringVarP(&options.Flags.Provider, "provider", "", "", "Cloud service providing the Kubernetes cluster.  Supported providers: "+KubernetesProviderOptions())
 	return cmd
@@ -218,6 +300,24 @@ func (options *InstallOptions) addInstallFlags(cmd *cobra.Command, includesInit
 	cmd.Flags().StringVarP(&flags.ExposeControllerPathMode, "exposecontroller-pathmode", "", "", "The ExposeController path mode for how services should be exposed as URLs. Defaults to using subnets. Use a value of `path` to use relative paths within the domain host such as when using AWS ELB host names")
 	cmd.Flags().StringVarP(&flags.Version, "version", "", "", "The specific platform version to install")
 	cmd.Flags().BoolVarP(&flags.Prow, "prow", "", false, "Enable prow")
+	cmd.Flags().BoolVarP(&flags.Force, "force", "", false, "Install even if the cluster capability checks of the kubernetes provider fail")
+	cmd.Flags().BoolVarP(&flags.ExternalDNS, "external-dns", "", false, "Installs external-dns to create the wildcard DNS record of the --domain in Route53, Cloud DNS or Azure DNS")
+	cmd.Flags().BoolVarP(&flags.SkipDNSVerify, "skip-dns-verify", "", false, "Skips waiting for the domain to resolve when using --external-dns")
+	cmd.Flags().StringVarP(&flags.TLSCertFile, "tls-cert-file", "", "", "The PEM encoded wildcard TLS certificate of the domain to use for all ingresses instead of cert-manager")
+	cmd.Flags().StringVarP(&flags.TLSKeyFile, "tls-key-file", "", "", "The PEM encoded private key of the --tls-cert-file")
+	cmd.Flags().DurationVarP(&flags.DNSVerifyTimeout, "dns-verify-timeout", "", 15*time.Minute, "How long to wait for the domain to resolve when using --external-dns")
+	cmd.Flags().StringArrayVarP(&flags.ValuesFiles, "values", "", nil, "A helm values file to merge on top of the values jx generates for the platform chart, can be repeated with the last file winning")
+	cmd.Flags().StringVarP(&flags.OfflineConfig, "offline-config", "", "", "A YAML file mapping the upstream chart repositories, docker registries and binary downloads to the mirrors of an air-gapped network, see 'jx step mirror'")
+	cmd.Flags().BoolVarP(&flags.ForceRestart, "force-restart", "", false, "Runs all the install steps again rather than skipping those a previous install completed")
+	cmd.Flags().StringVarP(&flags.LogsBucket, "logs-bucket", "", "", "The URL of an existing bucket to store the build logs in such as gs://name, s3://name or azblob://name, otherwise one is created on AWS")
+	cmd.Flags().StringVarP(&flags.ReportsBucket, "reports-bucket", "", "", "The URL of an existing bucket to store the test and coverage reports in such as gs://name, s3://name or azblob://name")
+	cmd.Flags().BoolVarP(&flags.NamespaceScoped, "namespace-scoped", "", false, "Only creates namespaced resources for clusters where you are not a cluster administrator, which needs the CRDs registered first via 'jx step install crds'")
+	cmd.Flags().StringVarP(&flags.ExternalVaultURL, "external-vault-url", "", "", "The URL of an existing Vault to store the secrets of the team in, which the build service account logs in to with the Kubernetes auth method")
+	cmd.Flags().StringVarP(&flags.VaultAuthPath, "vault-auth-path", "", vault.DefaultAuthPath, "The mount path of the Kubernetes auth method of the --external-vault-url")
+	cmd.Flags().StringVarP(&flags.VaultRole, "vault-role", "", defaultVaultRole, "The role of the Kubernetes auth method of the --external-vault-url which the build service account logs in with")
+	cmd.Flags().StringVarP(&flags.VaultSecretPrefix, "vault-secret-prefix", "", vault.DefaultSecretPrefix, "The path in the --external-vault-url under which the secrets of the team are stored")
+	cmd.Flags().StringVarP(&flags.VaultProbeImage, "vault-probe-image", "", defaultVaultProbeImage, "The image with curl of the job which checks the build service account can log in to the --external-vault-url")
+	cmd.Flags().StringVarP(&flags.RepositoryBucket, "repository-bucket", "", "", "The URL of an existing bucket to store the artifact repository in such as gs://name, s3://name or azblob://name")
 
 	addGitRepoOptionsArguments(cmd, &options.GitRepositoryOptions)
 	options.HelmValuesConfig.AddExposeControllerValues(cmd, true)
@@ -232,13 +332,22 @@ func (flags *InstallFlags) addCloudEnvOptions(cmd *cobra.Command) {
 
 // Run implements this command
 func (options *InstallOptions) Run() error {
+	err := options.loadOfflineConfig(options.Flags.OfflineConfig)
+	if err != nil {
+		return err
+	}
+	err = options.InitOptions.loadOfflineConfig(options.Flags.OfflineConfig)
+	if err != nil {
+		return err
+	}
+
 	if options.Flags.Provider == EKS {
 		var deps []string
 		d := binaryShouldBeInstalled("eksctl")
 		if d != "" {
 			deps = append(deps, d)
 		}
-		d = binaryShouldBeInstalled("heptio-authenticator-aws")
+		d = awsIamAuthenticatorShouldBeInstalled()
 		if d != "" {
 			deps = append(deps, d)
 		}
@@ -255,8 +364,21 @@ func (options *InstallOptions) Run() error {
 	}
 	options.KubeClientCached = client
 
+	if options.Flags.DryRun {
+		return options.dryRun(client, originalNs)
+	}
+
 	initOpts := &options.InitOptions
 	helmBinary := initOpts.HelmBinary()
+	if initOpts.Flags.Helm3 {
+		// helm 3 talks to the cluster directly so skip tiller entirely, neither running it locally nor in the cluster
+		if initOpts.Flags.NoTiller {
+			log.Warnf("Ignoring --no-tiller as helm3 does not use tiller\n")
+		}
+		initOpts.Flags.NoTiller = false
+		initOpts.Flags.RemoteTiller = true
+		initOpts.Flags.SkipTiller = true
+	}
 
 	// configure the helm binary
 	options.Helm().SetHelmBinary(helmBinary)
@@ -346,6 +468,11 @@ func (options *InstallOptions) Run() error {
 		return fmt.Errorf("Failed to ensure the namespace %s is created: %s\nIs this an RBAC issue on your cluster?", ns, err)
 	}
 
+	err = options.loadInstallState(client, ns)
+	if err != nil {
+		return err
+	}
+
 	if !options.Flags.DisableSetKubeContext {
 		err = options.RunCommand("kubectl", "config", "set-context", context, "--namespace", ns)
 		if err != nil {
@@ -357,6 +484,48 @@ func (options *InstallOptions) Run() error {
 	if err != nil {
 		return errors.Wrapf(err, "failed to get the cloud provider '%s'", options.Flags.Provider)
 	}
+	err = options.installState.SetValue(installStateProvider, options.Flags.Provider)
+	if err != nil {
+		return err
+	}
+
+	if options.Flags.ExternalDNS {
+		err = validateExternalDNS(options.Flags.Provider, initOpts.Flags.Domain)
+		if err != nil {
+			return err
+		}
+		initOpts.externalDNS = true
+	}
+
+	err = options.validateStorageBuckets()
+	if err != nil {
+		return err
+	}
+
+	if options.Flags.ExternalVaultURL != "" {
+		err = options.validateExternalVault()
+		if err != nil {
+			return err
+		}
+	}
+
+	if options.Flags.NamespaceScoped {
+		err = options.prepareNamespaceScopedInstall()
+		if err != nil {
+			return err
+		}
+	}
+
+	if options.Flags.Provider == KUBERNETES {
+		if options.Flags.NamespaceScoped {
+			log.Warnf("Skipping the cluster capability checks as they need cluster roles\n")
+		} else {
+			err = options.checkClusterCapabilities(client)
+			if err != nil {
+				return err
+			}
+		}
+	}
 
 	initOpts.Flags.Provider = options.Flags.Provider
 	initOpts.Flags.Namespace = options.Flags.Namespace
@@ -365,9 +534,12 @@ func (options *InstallOptions) Run() error {
 	if exposeController != nil {
 		initOpts.Flags.Http = exposeController.Config.HTTP == "true"
 	}
+	initOpts.Flags.NamespaceScoped = options.Flags.NamespaceScoped
 	initOpts.BatchMode = options.BatchMode
 
-	if options.Flags.Provider == AKS {
+	if options.Flags.Provider == AKS && options.Flags.NamespaceScoped {
+		log.Warnf("Not creating the cluster-admin role as the install is namespace scoped\n")
+	} else if options.Flags.Provider == AKS {
 		/**
 		 * create a cluster admin role
 		 */
@@ -387,7 +559,9 @@ func (options *InstallOptions) Run() error {
 		}
 	}
 	isAwsProvider := options.Flags.Provider == AWS || options.Flags.Provider == EKS
-	if isAwsProvider {
+	if isAwsProvider && options.Flags.NamespaceScoped {
+		log.Warnf("Not checking the default storage class as the install is namespace scoped\n")
+	} else if isAwsProvider {
 		err = options.ensureDefaultStorageClass(client, "gp2", "kubernetes.io/aws-ebs", "gp2")
 		if err != nil {
 			return err
@@ -402,7 +576,44 @@ func (options *InstallOptions) Run() error {
 		if err != nil {
 			return errors.Wrap(err, "failed to get the IP from Minikube")
 		}
-		options.Flags.Domain = ip + ".nip.io"
+		driver := ""
+		profiles, err := options.getCommandOutput("", "minikube", "profile", "list", "-o", "json")
+		if err == nil {
+			driver, _ = minikube.ParseProfileDriver([]byte(profiles), "minikube")
+		}
+		options.Flags.Domain = minikube.Domain(ip, driver, runtime.GOOS)
+		if minikube.NeedsTunnel(driver, runtime.GOOS) {
+			log.Warnf("The Minikube IP is not reachable with the %s driver, please run %s in another terminal to expose the ingress controller on localhost\n", driver, util.ColorInfo("minikube tunnel"))
+		}
+	}
+
+	if options.Flags.Provider == IBM && options.Flags.Domain == "" && currentContext != "" {
+		// the application load balancer of the cluster routes the ingress subdomain IBM provides
+		domain, err := options.iksIngressSubdomainForInstall(currentContext)
+		if err != nil {
+			log.Warnf("Failed to get the ingress subdomain of the IKS cluster so the domain is detected from the ingress controller instead: %s\n", err)
+		} else {
+			options.Flags.Domain = domain
+			initOpts.Flags.SkipIngress = true
+		}
+	}
+
+	if kind.IsKindContext(currentContext) {
+		if options.Flags.Provider == "" {
+			options.Flags.Provider = KIND
+		}
+		if options.Flags.Domain == "" {
+			options.Flags.Domain = kind.Domain
+		}
+	}
+
+	if k3d.IsK3dContext(currentContext) {
+		if options.Flags.Provider == "" {
+			options.Flags.Provider = K3S
+		}
+		if options.Flags.Domain == "" {
+			options.Flags.Domain = k3d.Domain
+		}
 	}
 
 	if initOpts.Flags.Domain == "" && options.Flags.Domain != "" {
@@ -427,6 +638,13 @@ func (options *InstallOptions) Run() error {
 		if isOpenShiftProvider(options.Flags.Provider) {
 			ecConfig.Exposer = "Route"
 		}
+		if ingressClass := options.InitOptions.Flags.IngressClass; ingressClass != "" {
+			if exposeController.Annotations == nil {
+				exposeController.Annotations = map[string]string{}
+			}
+			exposeController.Annotations[kube.AnnotationIngressClass] = ingressClass
+			log.Success("set exposeController ingress class " + ingressClass + "\n")
+		}
 	}
 
 	callback := func(env *v1.Environment) error {
@@ -440,6 +658,12 @@ func (options *InstallOptions) Run() error {
 	if err != nil {
 		return err
 	}
+	if options.Flags.NamespaceScoped {
+		err = options.enableNamespaceScopedTeamSettings()
+		if err != nil {
+			return err
+		}
+	}
 	if initOpts.Flags.NoTiller {
 		callback := func(env *v1.Environment) error {
 			env.Spec.TeamSettings.HelmTemplate = true
@@ -461,10 +685,25 @@ func (options *InstallOptions) Run() error {
 		initOpts.helm = options.helm
 	}
 
+	ingressInstalled := options.installState.IsStepCompleted(installStepIngress)
+	if ingressInstalled {
+		log.Infof("Skipping the install step %s as it has already completed, use --force-restart to run it again\n", util.ColorInfo(installStepIngress))
+		initOpts.Flags.SkipIngress = true
+	}
 	err = initOpts.Run()
 	if err != nil {
 		return errors.Wrap(err, "failed to initialize the jx")
 	}
+	if !ingressInstalled {
+		err = options.installState.SetValue(installStateDomain, initOpts.Flags.Domain)
+		if err != nil {
+			return err
+		}
+		err = options.installState.CompleteStep(installStepIngress)
+		if err != nil {
+			return err
+		}
+	}
 
 	if isOpenShiftProvider(options.Flags.Provider) {
 		err = options.enableOpenShiftSCC(ns)
@@ -478,6 +717,15 @@ func (options *InstallOptions) Run() error {
 		options.Flags.Domain = initOpts.Flags.Domain
 	}
 
+	if options.Flags.ExternalDNS {
+		err = options.runInstallStep(installStepExternalDNS, func() error {
+			return options.installExternalDNS(client, options.Flags.Provider, initOpts.Flags.Domain, initOpts.Flags.IngressNamespace, initOpts.Flags.IngressService)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	// get secrets to use in helm install
 	secrets, err := options.getGitSecrets()
 	if err != nil {
@@ -499,6 +747,28 @@ func (options *InstallOptions) Run() error {
 		helmConfig.ExposeController.Config.Domain = options.InitOptions.Flags.Domain
 	}
 	domain := helmConfig.ExposeController.Config.Domain
+	err = options.runInstallStep(installStepTLS, func() error {
+		err := options.configureTLSCertificate(client, ns, domain)
+		if err != nil {
+			return err
+		}
+		return options.installState.SetValue(installStateTLSSecretName, helmConfig.ExposeController.Config.TLSSecretName)
+	})
+	if err != nil {
+		return err
+	}
+	if tlsSecretName := options.installState.Value(installStateTLSSecretName); tlsSecretName != "" {
+		helmConfig.ExposeController.Config.TLSSecretName = tlsSecretName
+		helmConfig.ExposeController.Config.TLSAcme = "false"
+	}
+	if domain != "" && initOpts.Flags.SkipIngressInstall && initOpts.Flags.IngressClass != "" {
+		err = options.runInstallStep(installStepIngressProbe, func() error {
+			return options.probeExistingIngress(client, ns, domain)
+		})
+		if err != nil {
+			return err
+		}
+	}
 	if domain != "" && addon.IsAddonEnabled("gitea") {
 		helmConfig.Jenkins.Servers.GetOrCreateFirstGitea().Url = "http://gitea-gitea." + ns + "." + domain
 	}
@@ -511,7 +781,9 @@ func (options *InstallOptions) Run() error {
 			helmConfig.Jenkins.Servers.Global.EnvVars = map[string]string{}
 		}
 		helmConfig.Jenkins.Servers.Global.EnvVars["DOCKER_REGISTRY"] = dockerRegistry
-		if isOpenShiftProvider(options.Flags.Provider) && dockerRegistry == "docker-registry.default.svc:5000" {
+		if options.GKEWorkloadIdentityServiceAccount != "" {
+			options.configureWorkloadIdentityDockerConfig(helmConfig, dockerRegistry)
+		} else if isOpenShiftProvider(options.Flags.Provider) && dockerRegistry == "docker-registry.default.svc:5000" {
 			options.enableOpenShiftRegistryPermissions(ns, helmConfig, dockerRegistry)
 		}
 	}
@@ -524,6 +796,10 @@ func (options *InstallOptions) Run() error {
 		os.Setenv("TILLER_NAMESPACE", initOpts.Flags.TillerNamespace)
 	}
 
+	if options.Flags.NamespaceScoped {
+		options.configureNamespaceScopedValues(helmConfig)
+	}
+
 	// lets add any GitHub Enterprise servers
 	gitAuthCfg, err := options.CreateGitAuthConfigService()
 	if err != nil {
@@ -584,6 +860,20 @@ func (options *InstallOptions) Run() error {
 	data[AdminSecretsFile] = []byte(adminSecrets)
 	data[GitSecretsFile] = []byte(secrets)
 
+	userValuesFileName := ""
+	if len(options.Flags.ValuesFiles) > 0 {
+		userValues, err := options.mergeUserValues(config)
+		if err != nil {
+			return err
+		}
+		userValuesFileName = filepath.Join(dir, UserValuesFile)
+		err = ioutil.WriteFile(userValuesFileName, userValues, 0644)
+		if err != nil {
+			return errors.Wrap(err, "failed to write the user values file")
+		}
+		data[UserValuesFile] = userValues
+	}
+
 	jxSecrets := &core_v1.Secret{
 		Data: data,
 		ObjectMeta: metav1.ObjectMeta{
@@ -640,11 +930,21 @@ func (options *InstallOptions) Run() error {
 	if err != nil {
 		return errors.Wrap(err, "failed to append the myvalues.yaml file")
 	}
+	if userValuesFileName != "" {
+		valueFiles = append(valueFiles, userValuesFileName)
+	}
+	offlineValuesFileName, err := options.writeOfflineImageValues(valueFiles, dir)
+	if err != nil {
+		return err
+	}
+	if offlineValuesFileName != "" {
+		valueFiles = append(valueFiles, offlineValuesFileName)
+	}
 
 	options.currentNamespace = ns
 	if options.Flags.Prow {
 		// install prow into the new env
-		err = options.installProw()
+		err = options.runInstallStep(installStepProw, options.installProw)
 		if err != nil {
 			return fmt.Errorf("failed to install prow: %v", err)
 		}
@@ -660,13 +960,42 @@ func (options *InstallOptions) Run() error {
 
 	log.Infof("Installing jx into namespace %s\n", util.ColorInfo(ns))
 
-	if !options.Flags.InstallOnly {
-		err = options.Helm().UpgradeChart(jxChart, jxRelName, ns, &version, true, &timeoutInt, false, false, nil, valueFiles)
-	} else {
-		err = options.Helm().InstallChart(jxChart, jxRelName, ns, &version, &timeoutInt, nil, valueFiles)
-	}
+	err = options.runInstallStep(installStepPlatform, func() error {
+		var err error
+		if !options.Flags.InstallOnly {
+			err = options.Helm().UpgradeChart(jxChart, jxRelName, ns, &version, true, &timeoutInt, false, false, nil, valueFiles)
+		} else {
+			err = options.Helm().InstallChart(jxChart, jxRelName, ns, &version, &timeoutInt, nil, valueFiles)
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to install/upgrade the jenkins-x platform chart")
+		}
+
+		if options.GKEWorkloadIdentityServiceAccount != "" {
+			err = options.annotateWorkloadIdentityServiceAccount(ns)
+			if err != nil {
+				return errors.Wrap(err, "failed to configure Workload Identity")
+			}
+		}
+		if options.Flags.NamespaceScoped {
+			err = options.ensureNamespacedBuildRole(ns)
+			if err != nil {
+				return errors.Wrap(err, "failed to create the role of the build service account")
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		return errors.Wrap(err, "failed to install/upgrade the jenkins-x platform chart")
+		return err
+	}
+
+	if options.Flags.ExternalVaultURL != "" {
+		err = options.runInstallStep(installStepExternalVault, func() error {
+			return options.configureExternalVault(ns)
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to configure the external Vault")
+		}
 	}
 
 	if options.Flags.CleanupTempFiles {
@@ -679,6 +1008,20 @@ func (options *InstallOptions) Run() error {
 		if err != nil {
 			return errors.Wrap(err, "failed to cleanup the config file")
 		}
+
+		if userValuesFileName != "" {
+			err = os.Remove(userValuesFileName)
+			if err != nil {
+				return errors.Wrap(err, "failed to cleanup the user values file")
+			}
+		}
+
+		if offlineValuesFileName != "" {
+			err = os.Remove(offlineValuesFileName)
+			if err != nil {
+				return errors.Wrap(err, "failed to cleanup the offline values file")
+			}
+		}
 	}
 
 	tls, err := strconv.ParseBool(exposeController.Config.TLSAcme)
@@ -686,9 +1029,12 @@ func (options *InstallOptions) Run() error {
 		return fmt.Errorf("failed to parse TLS exposecontroller boolean %v", err)
 	}
 	ic := kube.IngressConfig{
-		Domain:  domain,
-		TLS:     tls,
-		Exposer: exposeController.Config.Exposer,
+		Domain:        domain,
+		TLS:           tls,
+		Exposer:       exposeController.Config.Exposer,
+		TLSSecretName: exposeController.Config.TLSSecretName,
+		IngressClass:  options.InitOptions.Flags.IngressClass,
+		IngressKind:   options.InitOptions.Flags.IngressKind,
 	}
 	// save ingress config details to a configmap
 	_, err = kube.SaveAsConfigMap(options.KubeClientCached, kube.IngressConfigConfigmap, ns, ic)
@@ -775,32 +1121,52 @@ func (options *InstallOptions) Run() error {
 		return errors.Wrap(err, "failed to load the addons configuration")
 	}
 
-	for _, ac := range addonConfig.Addons {
-		if ac.Enabled {
-			err = options.installAddon(ac.Name)
-			if err != nil {
-				return fmt.Errorf("failed to install addon %s: %s", ac.Name, err)
+	err = options.runInstallStep(installStepAddons, func() error {
+		for _, ac := range addonConfig.Addons {
+			if ac.Enabled {
+				if ac.Name == CertManagerDeployment && helmConfig.ExposeController.Config.TLSSecretName != "" {
+					log.Infof("Skipping the %s addon as the TLS secret %s is used\n", util.ColorInfo(ac.Name), util.ColorInfo(helmConfig.ExposeController.Config.TLSSecretName))
+					continue
+				}
+				if ac.Name == kube.DefaultVaultOperatorReleaseName && options.Flags.ExternalVaultURL != "" {
+					log.Infof("Skipping the %s addon as the Vault %s is used\n", util.ColorInfo(ac.Name), util.ColorInfo(options.Flags.ExternalVaultURL))
+					continue
+				}
+				err := options.installAddon(ac.Name)
+				if err != nil {
+					return fmt.Errorf("failed to install addon %s: %s", ac.Name, err)
+				}
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	options.logAdminPassword()
 
-	log.Info("Getting Jenkins API Token\n")
-	err = options.retry(3, 2*time.Second, func() (err error) {
-		options.CreateJenkinsUserOptions.CommonOptions = options.CommonOptions
-		options.CreateJenkinsUserOptions.Password = options.AdminSecretsService.Flags.DefaultAdminPassword
-		options.CreateJenkinsUserOptions.UseBrowser = true
-		if options.BatchMode {
-			options.CreateJenkinsUserOptions.BatchMode = true
-			options.CreateJenkinsUserOptions.Headless = true
-			log.Info("Attempting to find the Jenkins API Token with the browser in headless mode...")
-		}
-		err = options.CreateJenkinsUserOptions.Run()
-		return
+	err = options.runInstallStep(installStepJenkinsToken, func() error {
+		log.Info("Getting Jenkins API Token\n")
+		err := options.retry(3, 2*time.Second, func() (err error) {
+			options.CreateJenkinsUserOptions.CommonOptions = options.CommonOptions
+			options.CreateJenkinsUserOptions.Password = options.AdminSecretsService.Flags.DefaultAdminPassword
+			options.CreateJenkinsUserOptions.UseBrowser = true
+			if options.BatchMode {
+				options.CreateJenkinsUserOptions.BatchMode = true
+				options.CreateJenkinsUserOptions.Headless = true
+				log.Info("Attempting to find the Jenkins API Token with the browser in headless mode...")
+			}
+			err = options.CreateJenkinsUserOptions.Run()
+			return
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to get the Jenkins API token")
+		}
+		return nil
 	})
 	if err != nil {
-		return errors.Wrap(err, "failed to get the Jenkins API token")
+		return err
 	}
 
 	jxClient, _, err := options.JXClient()
@@ -814,56 +1180,46 @@ func (options *InstallOptions) Run() error {
 	}
 
 	if !options.Flags.NoDefaultEnvironments {
-		// lets only recreate the environments if its the first time we run this
-		_, envNames, err := kube.GetEnvironments(jxClient, ns)
-		if err != nil || len(envNames) <= 1 {
-
-			if options.Flags.DefaultEnvironmentPrefix == "" {
-				options.Flags.DefaultEnvironmentPrefix = strings.ToLower(randomdata.SillyName())
-			}
-
-			log.Info("Creating default staging and production environments\n")
-			// Common CreateEnv Options
-			options.CreateEnvOptions.GitRepositoryOptions = options.GitRepositoryOptions
-			options.CreateEnvOptions.GitRepositoryOptions.Owner = options.Flags.EnvironmentGitOwner
-			options.CreateEnvOptions.Prefix = options.Flags.DefaultEnvironmentPrefix
-			options.CreateEnvOptions.Prow = options.Flags.Prow
-			if options.BatchMode {
-				options.CreateEnvOptions.BatchMode = options.BatchMode
-			}
-
-			options.CreateEnvOptions.Options.Name = "staging"
-			options.CreateEnvOptions.Options.Spec.Label = "Staging"
-			options.CreateEnvOptions.Options.Spec.Order = 100
-			err = options.CreateEnvOptions.Run()
-			if err != nil {
-				return errors.Wrapf(err, "failed to create staging environment in namespace %s", options.devNamespace)
-			}
-			options.CreateEnvOptions.Options.Name = "production"
-			options.CreateEnvOptions.Options.Spec.Label = "Production"
-			options.CreateEnvOptions.Options.Spec.Order = 200
-			options.CreateEnvOptions.Options.Spec.PromotionStrategy = v1.PromotionStrategyTypeManual
-			options.CreateEnvOptions.PromotionStrategy = string(v1.PromotionStrategyTypeManual)
-
-			err = options.CreateEnvOptions.Run()
-			if err != nil {
-				return errors.Wrapf(err, "failed to create the production environment in namespace %s", options.devNamespace)
-			}
+		err = options.runInstallStep(installStepEnvironments, func() error {
+			return options.createDefaultEnvironments(jxClient, ns)
+		})
+		if err != nil {
+			return err
 		}
 	}
 
+	err = options.runInstallStep(installStepStorage, options.configureStorage)
+	if err != nil {
+		return errors.Wrap(err, "failed to configure the long term storage")
+	}
+
 	err = options.saveChartmuseumAuthConfig()
 	if err != nil {
 		return errors.Wrap(err, "failed to save the auth config for Chartmuseum")
 	}
 
 	if options.Flags.RegisterLocalHelmRepo {
-		err = options.registerLocalHelmRepo(options.Flags.LocalHelmRepoName, ns)
+		err = options.runInstallStep(installStepLocalHelmRepo, func() error {
+			return options.registerLocalHelmRepo(options.Flags.LocalHelmRepoName, ns)
+		})
 		if err != nil {
 			return errors.Wrapf(err, "failed to register the local helm repo '%s'", options.Flags.LocalHelmRepoName)
 		}
 	}
 
+	if options.Flags.ExternalDNS && !options.Flags.SkipDNSVerify {
+		err = options.waitForDomainToResolve(initOpts.Flags.Domain, options.Flags.DNSVerifyTimeout)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the install completed so a later install runs all the steps again rather than resuming this one
+	err = options.installState.Reset()
+	if err != nil {
+		return errors.Wrap(err, "failed to remove the install state")
+	}
+
 	log.Success("\nJenkins X installation completed successfully\n")
 
 	options.logAdminPassword()
@@ -905,6 +1261,36 @@ func (options *InstallOptions) enableOpenShiftSCC(ns string) error {
 	return options.RunCommand("oc", "adm", "policy", "add-scc-to-user", "anyuid", "system:serviceaccount:"+ns+":default")
 }
 
+// configureWorkloadIdentityDockerConfig replaces the docker config pipeline secret with one which holds no credentials
+// as the builds get the token of GKEWorkloadIdentityServiceAccount from the metadata server via the gcloud credential
+// helper. The secret is still created as the build pods mount it
+func (options *InstallOptions) configureWorkloadIdentityDockerConfig(helmConfig *config.HelmValuesConfig, dockerRegistry string) {
+	host := strings.Split(dockerRegistry, "/")[0]
+	helmConfig.PipelineSecrets.DockerConfig = `{"auths": {}, "credHelpers": {"` + host + `": "gcr"}}`
+	log.Infof("The builds authenticate to %s as service account %s via Workload Identity so the docker config secret holds no credentials\n",
+		util.ColorInfo(dockerRegistry), util.ColorInfo(options.GKEWorkloadIdentityServiceAccount))
+}
+
+// annotateWorkloadIdentityServiceAccount annotates the service account of the builds so that they act as the Google
+// service account of GKEWorkloadIdentityServiceAccount
+func (options *InstallOptions) annotateWorkloadIdentityServiceAccount(ns string) error {
+	serviceAccounts := options.KubeClientCached.CoreV1().ServiceAccounts(ns)
+	sa, err := serviceAccounts.Get(jxBuilderServiceAccount, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find the service account %s in namespace %s", jxBuilderServiceAccount, ns)
+	}
+	if sa.Annotations == nil {
+		sa.Annotations = map[string]string{}
+	}
+	sa.Annotations[gke.WorkloadIdentityAnnotation] = options.GKEWorkloadIdentityServiceAccount
+	_, err = serviceAccounts.Update(sa)
+	if err != nil {
+		return err
+	}
+	log.Infof("Annotated service account %s so that the builds act as %s\n", util.ColorInfo(jxBuilderServiceAccount), util.ColorInfo(options.GKEWorkloadIdentityServiceAccount))
+	return nil
+}
+
 func (options *InstallOptions) enableOpenShiftRegistryPermissions(ns string, helmConfig *config.HelmValuesConfig, dockerRegistry string) error {
 	log.Infof("Enabling permissions for OpenShift registry in namespace %s\n", ns)
 	// Open the registry so any authenticated user can pull images from the jx namespace
@@ -1170,17 +1556,23 @@ func (options *InstallOptions) getGitUser(message string) (*auth.UserAuth, error
 		return userAuth, err
 	}
 	if userAuth.IsInvalid() {
-		f := func(username string) error {
-			options.Git().PrintCreateRepositoryGenerateAccessToken(server, username, options.Out)
-			return nil
-		}
-
-		// TODO could we guess this based on the users ~/.git for github?
-		defaultUserName := ""
-		err = config.EditUserAuth(server.Label(), userAuth, defaultUserName, false, options.BatchMode, f, options.In, options.Out, options.Err)
+		loggedIn, err := gits.LoginWithGitHubDeviceFlow(server, userAuth, options.BatchMode, options.Out)
 		if err != nil {
 			return userAuth, err
 		}
+		if !loggedIn {
+			f := func(username string) error {
+				options.Git().PrintCreateRepositoryGenerateAccessToken(server, username, options.Out)
+				return nil
+			}
+
+			// TODO could we guess this based on the users ~/.git for github?
+			defaultUserName := ""
+			err = config.EditUserAuth(server.Label(), userAuth, defaultUserName, false, options.BatchMode, f, options.In, options.Out, options.Err)
+			if err != nil {
+				return userAuth, err
+			}
+		}
 
 		// TODO lets verify the auth works
 
@@ -1197,11 +1589,16 @@ func (options *InstallOptions) getGitUser(message string) (*auth.UserAuth, error
 			teamSettings.PipelineUsername = userAuth.Username
 			teamSettings.Organisation = options.Owner
 			teamSettings.GitPrivate = options.GitRepositoryOptions.Private
+			if options.GitRepositoryOptions.Protocol != "" {
+				teamSettings.GitProtocol = options.GitRepositoryOptions.Protocol
+			}
 			return nil
 		}
-		err = options.ModifyDevEnvironment(callback)
-		if err != nil {
-			return userAuth, fmt.Errorf("failed to save team settings %s", err)
+		if !options.Flags.DryRun {
+			err = options.ModifyDevEnvironment(callback)
+			if err != nil {
+				return userAuth, fmt.Errorf("failed to save team settings %s", err)
+			}
 		}
 	}
 	// TODO This API should be refactored/rethought as mixing OO and functional styles is error prone. If choosing an OO style, mutations should be carried out on the object data and then that data should be introspected as the source of truth in the operation. Alternatively, remove object state and pass values in a functional style.
@@ -1210,6 +1607,173 @@ func (options *InstallOptions) getGitUser(message string) (*auth.UserAuth, error
 	return userAuth, nil
 }
 
+// mergeUserValues merges the --values files in order, warning about any value which replaces one jx generated,
+// and returns the merged values so they are applied after the generated values
+func (options *InstallOptions) mergeUserValues(generatedValues string) ([]byte, error) {
+	userValues := map[string]interface{}{}
+	for _, fileName := range options.Flags.ValuesFiles {
+		values, err := helm.LoadValuesFile(fileName)
+		if err != nil {
+			return nil, err
+		}
+		helm.MergeValues(userValues, values)
+		log.Infof("Using the values file %s\n", util.ColorInfo(fileName))
+	}
+
+	generated := map[string]interface{}{}
+	err := yaml.Unmarshal([]byte(generatedValues), &generated)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse the generated helm values")
+	}
+	for _, path := range helm.MergeValues(generated, userValues) {
+		log.Warnf("The value %s from --values overrides the value generated by jx install\n", util.ColorWarning(path))
+	}
+
+	data, err := yaml.Marshal(userValues)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal the user values")
+	}
+	return data, nil
+}
+
+// loadInstallState loads the steps a previous run of the install completed in the namespace, or removes them
+// when all the steps should run again
+func (options *InstallOptions) loadInstallState(client kubernetes.Interface, ns string) error {
+	state, err := kube.LoadInstallState(client, ns)
+	if err != nil {
+		return err
+	}
+	options.installState = state
+	if options.Flags.ForceRestart {
+		return state.Reset()
+	}
+	steps := state.CompletedSteps()
+	if len(steps) == 0 {
+		return nil
+	}
+	options.resumingInstall = true
+	sort.Strings(steps)
+	log.Infof("Resuming the install which has already completed the steps: %s\n", util.ColorInfo(strings.Join(steps, ", ")))
+	if options.Flags.Provider == "" {
+		options.Flags.Provider = state.Value(installStateProvider)
+	}
+	if options.InitOptions.Flags.Domain == "" {
+		options.InitOptions.Flags.Domain = state.Value(installStateDomain)
+	}
+	return nil
+}
+
+// runInstallStep runs the named install step unless a previous run of the install has completed it
+func (options *InstallOptions) runInstallStep(name string, fn func() error) error {
+	if options.installState.IsStepCompleted(name) {
+		log.Infof("Skipping the install step %s as it has already completed, use --force-restart to run it again\n", util.ColorInfo(name))
+		return nil
+	}
+	err := fn()
+	if err != nil {
+		return err
+	}
+	return options.installState.CompleteStep(name)
+}
+
+// createDefaultEnvironments creates the staging and production environments, along with their git repositories and
+// webhooks, on the first install into the namespace. When resuming an interrupted install only the missing ones are
+// created
+func (options *InstallOptions) createDefaultEnvironments(jxClient versioned.Interface, ns string) error {
+	envMap, envNames, err := kube.GetEnvironments(jxClient, ns)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load the environments in namespace %s", ns)
+	}
+	// lets only create the environments if its the first time we run this
+	if len(envNames) > 1 && !options.resumingInstall {
+		return nil
+	}
+
+	if options.Flags.DefaultEnvironmentPrefix == "" {
+		options.Flags.DefaultEnvironmentPrefix = strings.ToLower(randomdata.SillyName())
+	}
+
+	log.Info("Creating default staging and production environments\n")
+	// Common CreateEnv Options
+	options.CreateEnvOptions.GitRepositoryOptions = options.GitRepositoryOptions
+	options.CreateEnvOptions.GitRepositoryOptions.Owner = options.Flags.EnvironmentGitOwner
+	options.CreateEnvOptions.Prefix = options.Flags.DefaultEnvironmentPrefix
+	options.CreateEnvOptions.Prow = options.Flags.Prow
+	if options.BatchMode {
+		options.CreateEnvOptions.BatchMode = options.BatchMode
+	}
+
+	if envMap["staging"] != nil {
+		log.Infof("The environment %s already exists\n", util.ColorInfo("staging"))
+	} else {
+		options.CreateEnvOptions.Options.Name = "staging"
+		options.CreateEnvOptions.Options.Spec.Label = "Staging"
+		options.CreateEnvOptions.Options.Spec.Order = 100
+		err = options.CreateEnvOptions.Run()
+		if err != nil {
+			return errors.Wrapf(err, "failed to create staging environment in namespace %s", options.devNamespace)
+		}
+	}
+
+	if envMap["production"] != nil {
+		log.Infof("The environment %s already exists\n", util.ColorInfo("production"))
+	} else {
+		options.CreateEnvOptions.Options.Name = "production"
+		options.CreateEnvOptions.Options.Spec.Label = "Production"
+		options.CreateEnvOptions.Options.Spec.Order = 200
+		options.CreateEnvOptions.Options.Spec.PromotionStrategy = v1.PromotionStrategyTypeManual
+		options.CreateEnvOptions.PromotionStrategy = string(v1.PromotionStrategyTypeManual)
+
+		err = options.CreateEnvOptions.Run()
+		if err != nil {
+			return errors.Wrapf(err, "failed to create the production environment in namespace %s", options.devNamespace)
+		}
+	}
+	return nil
+}
+
+// configureTLSCertificate creates a TLS secret from the certificate and key files brought by the user, prompting
+// for them when automatic TLS is not enabled, and configures exposecontroller to use it for all the ingresses
+func (options *InstallOptions) configureTLSCertificate(client kubernetes.Interface, ns string, domain string) error {
+	flags := &options.Flags
+	ecConfig := &options.CreateEnvOptions.HelmValuesConfig.ExposeController.Config
+	if flags.TLSCertFile == "" && flags.TLSKeyFile == "" {
+		if options.BatchMode || ecConfig.TLSAcme == "true" || domain == "" || strings.HasSuffix(domain, "nip.io") {
+			return nil
+		}
+		if !util.Confirm("Would you like to use your own TLS certificate for the domain "+domain+"?", false,
+			"Uses a wildcard TLS certificate, such as one issued by your corporate CA, for all ingresses instead of cert-manager", options.In, options.Out, options.Err) {
+			return nil
+		}
+		var err error
+		flags.TLSCertFile, err = util.PickValue("TLS certificate file:", "", true, options.In, options.Out, options.Err)
+		if err != nil {
+			return err
+		}
+		flags.TLSKeyFile, err = util.PickValue("TLS private key file:", "", true, options.In, options.Out, options.Err)
+		if err != nil {
+			return err
+		}
+	}
+	if flags.TLSCertFile == "" {
+		return util.MissingOption("tls-cert-file")
+	}
+	if flags.TLSKeyFile == "" {
+		return util.MissingOption("tls-key-file")
+	}
+	if ecConfig.TLSAcme == "true" {
+		return util.InvalidOptionf("tls-acme", ecConfig.TLSAcme, "automatic TLS cannot be used with --tls-cert-file")
+	}
+
+	_, err := options.createTLSSecret(client, ns, domain, flags.TLSCertFile, flags.TLSKeyFile)
+	if err != nil {
+		return err
+	}
+	ecConfig.TLSSecretName = kube.SecretIngressTLS
+	ecConfig.TLSAcme = "false"
+	return nil
+}
+
 func (options *InstallOptions) installAddon(name string) error {
 	log.Infof("Installing addon %s\n", util.ColorInfo(name))
 
@@ -1238,7 +1802,7 @@ func (options *InstallOptions) addGitServersToJenkinsConfig(helmConfig *config.H
 			if !gits.IsGitHubServerURL(u) {
 				sc := config.JenkinsGithubServersValuesConfig{
 					Name: server.Name,
-					Url:  gits.GitHubEnterpriseApiEndpointURL(u),
+					Url:  gits.GitHubServerAPIURL(server),
 				}
 				helmConfig.Jenkins.Servers.GHE = append(helmConfig.Jenkins.Servers.GHE, sc)
 			}
@@ -1247,6 +1811,37 @@ func (options *InstallOptions) addGitServersToJenkinsConfig(helmConfig *config.H
 	return nil
 }
 
+// checkClusterCapabilities verifies the cluster has the capabilities Jenkins X relies on, as clusters of the generic
+// kubernetes provider can be set up in many ways, failing unless --force is specified if the cluster lacks any
+func (options *InstallOptions) checkClusterCapabilities(client kubernetes.Interface) error {
+	log.Info("Checking the capabilities of the cluster\n")
+	checks := kube.CheckClusterCapabilities(client)
+	table := options.CreateTable()
+	table.AddRow("CHECK", "RESULT", "MESSAGE")
+	for _, check := range checks {
+		result := string(check.Result)
+		switch check.Result {
+		case kube.CapabilityPass:
+			result = util.ColorInfo(result)
+		case kube.CapabilityWarn:
+			result = util.ColorWarning(result)
+		case kube.CapabilityFail:
+			result = util.ColorError(result)
+		}
+		table.AddRow(check.Name, result, check.Message)
+	}
+	table.Render()
+
+	if kube.CapabilityChecksFailed(checks) {
+		if options.Flags.Force {
+			log.Warn("The cluster lacks capabilities Jenkins X relies on but continuing as --force was specified\n")
+			return nil
+		}
+		return fmt.Errorf("the cluster lacks capabilities Jenkins X relies on, please fix the failed checks or use --force to install anyway")
+	}
+	return nil
+}
+
 func (options *InstallOptions) ensureDefaultStorageClass(client kubernetes.Interface, name string, provisioner string, typeName string) error {
 	storageClassInterface := client.StorageV1().StorageClasses()
 	storageClasses, err := storageClassInterface.List(metav1.ListOptions{})
@@ -0,0 +1,350 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Pallinder/go-randomdata"
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/addon"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// DryRunValuesFile the merged values of the platform chart written by jx install --dry-run
+	DryRunValuesFile = "values.yaml"
+	// DryRunPlanFile the summary of the resources written by jx install --dry-run
+	DryRunPlanFile = "plan.txt"
+
+	dryRunDomainPlaceholder = "EXTERNAL-IP.nip.io"
+)
+
+// dryRun resolves the install configuration then writes the rendered platform chart, its values, the default
+// environment repositories and a plan of the resources to create into the dry run directory without modifying the cluster
+func (options *InstallOptions) dryRun(client kubernetes.Interface, originalNs string) error {
+	initOpts := &options.InitOptions
+	dir, err := filepath.Abs(options.Flags.DryRunDir)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(dir, DefaultWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create the dry run dir %s", dir)
+	}
+
+	ns := options.Flags.Namespace
+	if ns == "" {
+		ns = originalNs
+	}
+	options.Flags.Provider, err = options.GetCloudProvider(options.Flags.Provider)
+	if err != nil {
+		return err
+	}
+
+	helmConfig := &options.CreateEnvOptions.HelmValuesConfig
+	domain := initOpts.Flags.Domain
+	if domain == "" {
+		domain = options.Flags.Domain
+	}
+	if domain == "" {
+		domain = helmConfig.ExposeController.Config.Domain
+	}
+	if domain == "" {
+		domain = options.dryRunDomain(client, ns)
+	}
+	helmConfig.ExposeController.Config.Domain = domain
+	if helmConfig.ExposeController.Config.PathMode == "" {
+		helmConfig.ExposeController.Config.PathMode = options.Flags.ExposeControllerPathMode
+	}
+	if options.Flags.ExternalDNS {
+		err = validateExternalDNS(options.Flags.Provider, domain)
+		if err != nil {
+			return err
+		}
+	}
+
+	secrets, err := options.getGitSecrets()
+	if err != nil {
+		return errors.Wrap(err, "failed to read the git secrets from configuration")
+	}
+	err = options.AdminSecretsService.NewAdminSecretsConfig()
+	if err != nil {
+		return errors.Wrap(err, "failed to create the admin secret config service")
+	}
+	adminSecrets, err := options.AdminSecretsService.Secrets.String()
+	if err != nil {
+		return errors.Wrap(err, "failed to read the admin secrets")
+	}
+
+	dockerRegistry, err := options.dockerRegistryValue()
+	if err != nil {
+		return err
+	}
+	if dockerRegistry != "" {
+		if helmConfig.Jenkins.Servers.Global.EnvVars == nil {
+			helmConfig.Jenkins.Servers.Global.EnvVars = map[string]string{}
+		}
+		helmConfig.Jenkins.Servers.Global.EnvVars["DOCKER_REGISTRY"] = dockerRegistry
+	}
+	gitAuthCfg, err := options.CreateGitAuthConfigService()
+	if err != nil {
+		return errors.Wrap(err, "failed to create the git auth config service")
+	}
+	err = options.addGitServersToJenkinsConfig(helmConfig, gitAuthCfg)
+	if err != nil {
+		return errors.Wrap(err, "failed to add the Git servers to Jenkins config")
+	}
+	config, err := helmConfig.String()
+	if err != nil {
+		return errors.Wrap(err, "failed to get the helm config")
+	}
+
+	wrkDir, err := options.cloneJXCloudEnvironmentsRepo()
+	if err != nil {
+		return errors.Wrap(err, "failed to clone the jx cloud environments repo")
+	}
+	makefileDir := filepath.Join(wrkDir, fmt.Sprintf("env-%s", strings.ToLower(options.Flags.Provider)))
+	version := options.Flags.Version
+	if version == "" {
+		version, err = LoadVersionFromCloudEnvironmentsDir(wrkDir)
+		if err != nil {
+			return errors.Wrap(err, "failed to load version from cloud environments dir")
+		}
+	}
+
+	tmpDir, err := ioutil.TempDir("", "jx-install-dry-run-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	valueFiles := []string{filepath.Join(makefileDir, CloudEnvValuesFile), filepath.Join(makefileDir, CloudEnvSecretsFile)}
+	generated := map[string]string{GitSecretsFile: secrets, AdminSecretsFile: adminSecrets, ExtraValuesFile: config}
+	for _, name := range []string{GitSecretsFile, AdminSecretsFile, ExtraValuesFile} {
+		fileName := filepath.Join(tmpDir, name)
+		err = ioutil.WriteFile(fileName, []byte(generated[name]), 0600)
+		if err != nil {
+			return errors.Wrapf(err, "failed to write %s", fileName)
+		}
+		valueFiles = append(valueFiles, fileName)
+	}
+	valueFiles, err = helm.AppendMyValues(valueFiles)
+	if err != nil {
+		return errors.Wrap(err, "failed to append the myvalues.yaml file")
+	}
+	if len(options.Flags.ValuesFiles) > 0 {
+		userValues, err := options.mergeUserValues(config)
+		if err != nil {
+			return err
+		}
+		fileName := filepath.Join(tmpDir, UserValuesFile)
+		err = ioutil.WriteFile(fileName, userValues, 0600)
+		if err != nil {
+			return errors.Wrap(err, "failed to write the user values file")
+		}
+		valueFiles = append(valueFiles, fileName)
+	}
+	offlineValuesFileName, err := options.writeOfflineImageValues(valueFiles, tmpDir)
+	if err != nil {
+		return err
+	}
+	if offlineValuesFileName != "" {
+		valueFiles = append(valueFiles, offlineValuesFileName)
+	}
+
+	values := map[string]interface{}{}
+	for _, valueFile := range valueFiles {
+		exists, err := util.FileExists(valueFile)
+		if err != nil || !exists {
+			continue
+		}
+		fileValues, err := helm.LoadValuesFile(valueFile)
+		if err != nil {
+			return err
+		}
+		helm.MergeValues(values, fileValues)
+	}
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the platform values")
+	}
+	valuesFileName := filepath.Join(dir, DryRunValuesFile)
+	err = ioutil.WriteFile(valuesFileName, data, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "failed to write %s", valuesFileName)
+	}
+	log.Warnf("The values file %s contains secrets so please do not share it\n", valuesFileName)
+
+	manifestsDir := filepath.Join(dir, "manifests")
+	err = options.renderPlatformChart(version, ns, tmpDir, manifestsDir, valuesFileName)
+	if err != nil {
+		return err
+	}
+
+	var environments []string
+	if !options.Flags.NoDefaultEnvironments {
+		environments, err = options.cloneDryRunEnvironments(filepath.Join(dir, "environments"))
+		if err != nil {
+			return err
+		}
+	}
+
+	plan := options.dryRunPlan(client, ns, domain, version, environments)
+	planFileName := filepath.Join(dir, DryRunPlanFile)
+	err = ioutil.WriteFile(planFileName, []byte(plan), DefaultWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to write %s", planFileName)
+	}
+	log.Infof("\n%s\n", plan)
+	log.Infof("Rendered the install of Jenkins X into %s without modifying the cluster\n", util.ColorInfo(dir))
+	return nil
+}
+
+// dryRunDomain returns the domain of an existing install or the nip.io domain of the ingress controller service
+// without installing the ingress controller
+func (options *InstallOptions) dryRunDomain(client kubernetes.Interface, ns string) string {
+	domain, err := kube.GetCurrentDomain(client, ns)
+	if err == nil && domain != "" {
+		return domain
+	}
+	initOpts := &options.InitOptions
+	svc, err := client.CoreV1().Services(initOpts.Flags.IngressNamespace).Get(initOpts.Flags.IngressService, metav1.GetOptions{})
+	if err == nil {
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			if ingress.IP != "" {
+				return ingress.IP + ".nip.io"
+			}
+		}
+	}
+	log.Warnf("Could not find the external IP of the ingress controller so using the domain %s; use --domain to specify it\n", dryRunDomainPlaceholder)
+	return dryRunDomainPlaceholder
+}
+
+// renderPlatformChart fetches the platform chart and renders it with helm template into the manifests dir
+func (options *InstallOptions) renderPlatformChart(version string, ns string, tmpDir string, manifestsDir string, valuesFileName string) error {
+	helmBinary := options.InitOptions.HelmBinary()
+	cli := helm.NewHelmCLI(helmBinary, helm.BinaryVersion(helmBinary), tmpDir, options.Verbose)
+	chartRepoURL := options.offlineConfig.ChartRepositoryURL(DEFAULT_CHARTMUSEUM_URL)
+	missing, err := cli.IsRepoMissing(chartRepoURL)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check the helm repository %s", chartRepoURL)
+	}
+	if missing {
+		err = cli.AddRepo("jenkins-x", chartRepoURL, "", "")
+		if err != nil {
+			return errors.Wrapf(err, "failed to add the helm repository %s", chartRepoURL)
+		}
+	}
+	err = cli.UpdateRepo()
+	if err != nil {
+		return errors.Wrap(err, "failed to update the helm repo")
+	}
+	err = options.RunCommand(helmBinary, "fetch", "jenkins-x/jenkins-x-platform", "--version", version, "--destination", tmpDir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to fetch version %s of the jenkins-x platform chart", version)
+	}
+	err = os.MkdirAll(manifestsDir, DefaultWritePermissions)
+	if err != nil {
+		return err
+	}
+	chartFile := filepath.Join(tmpDir, fmt.Sprintf("jenkins-x-platform-%s.tgz", version))
+	err = cli.Template(chartFile, "jenkins-x", ns, manifestsDir, false, nil, []string{valuesFileName})
+	if err != nil {
+		return errors.Wrap(err, "failed to render the jenkins-x platform chart")
+	}
+	log.Infof("Rendered the manifests of the jenkins-x platform chart %s into %s\n", util.ColorInfo(version), util.ColorInfo(manifestsDir))
+	return nil
+}
+
+// cloneDryRunEnvironments clones the environment template into the repositories of the default environments,
+// returning their names
+func (options *InstallOptions) cloneDryRunEnvironments(dir string) ([]string, error) {
+	if options.Flags.DefaultEnvironmentPrefix == "" {
+		options.Flags.DefaultEnvironmentPrefix = strings.ToLower(randomdata.SillyName())
+	}
+	gitURL := options.CreateEnvOptions.ForkEnvironmentGitRepo
+	if gitURL == "" {
+		gitURL = kube.DefaultEnvironmentGitRepoURL
+	}
+	names := []string{}
+	for _, env := range []string{"staging", "production"} {
+		name := fmt.Sprintf("environment-%s-%s", options.Flags.DefaultEnvironmentPrefix, env)
+		envDir := filepath.Join(dir, name)
+		err := options.Git().Clone(gitURL, envDir)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to clone the environment template %s", gitURL)
+		}
+		names = append(names, name)
+	}
+	log.Infof("Cloned the default environment repositories into %s\n", util.ColorInfo(dir))
+	return names, nil
+}
+
+// dryRunPlan describes the resources the install would create
+func (options *InstallOptions) dryRunPlan(client kubernetes.Interface, ns string, domain string, version string, environments []string) string {
+	initOpts := &options.InitOptions
+	helmConfig := &options.CreateEnvOptions.HelmValuesConfig
+	var buffer bytes.Buffer
+	line := func(format string, a ...interface{}) {
+		buffer.WriteString(fmt.Sprintf(format, a...) + "\n")
+	}
+	line("Jenkins X install plan for provider %s", options.Flags.Provider)
+	line("")
+	line("namespace:          %s", ns)
+	line("domain:             %s", domain)
+	count, err := kube.DeploymentPodCount(client, initOpts.Flags.IngressDeployment, initOpts.Flags.IngressNamespace)
+	if initOpts.Flags.SkipIngressInstall {
+		line("ingress controller: reuse the %s service %s/%s", initOpts.Flags.IngressKind, initOpts.Flags.IngressNamespace, initOpts.Flags.IngressService)
+	} else if err == nil && count > 0 {
+		line("ingress controller: %s/%s already exists", initOpts.Flags.IngressNamespace, initOpts.Flags.IngressDeployment)
+	} else if !initOpts.Flags.SkipIngress {
+		line("ingress controller: install %s/%s", initOpts.Flags.IngressNamespace, initOpts.Flags.IngressDeployment)
+	}
+	if options.Flags.ExternalDNS {
+		line("external-dns:       install using %s for the zone %s", externalDNSProviders[options.Flags.Provider], domain)
+	}
+	if options.Flags.TLSCertFile != "" {
+		line("TLS secret:         create from %s", options.Flags.TLSCertFile)
+	}
+	line("platform chart:     install jenkins-x/jenkins-x-platform %s as release jenkins-x", version)
+	if options.Flags.Prow {
+		line("prow:               install")
+	}
+	addonConfig, err := addon.LoadAddonsConfig()
+	if err == nil {
+		for _, ac := range addonConfig.Addons {
+			if !ac.Enabled {
+				continue
+			}
+			if ac.Name == CertManagerDeployment && (helmConfig.ExposeController.Config.TLSSecretName != "" || options.Flags.TLSCertFile != "") {
+				continue
+			}
+			line("addon:              install %s", ac.Name)
+		}
+	}
+	storageBuckets := options.storageBuckets()
+	for _, classifier := range []string{StorageClassifierLogs, StorageClassifierReports, StorageClassifierRepository} {
+		if storageBuckets[classifier] != "" {
+			line("storage:            store the %s in %s", classifier, storageBuckets[classifier])
+		} else if classifier == StorageClassifierLogs && (options.Flags.Provider == AWS || options.Flags.Provider == EKS) {
+			line("storage:            create an encrypted S3 bucket for the logs expiring after %d days", logsBucketExpirationDays)
+		}
+	}
+	for _, name := range environments {
+		line("environment:        create the git repository %s with a webhook", name)
+	}
+	if options.GKEWorkloadIdentityServiceAccount != "" {
+		line("service account:    annotate the builds to use %s", options.GKEWorkloadIdentityServiceAccount)
+	}
+	return buffer.String()
+}
@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	extv1beta1 "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newDryRunInstallOptions(provider string) *InstallOptions {
+	options := &InstallOptions{}
+	options.Flags.Provider = provider
+	options.InitOptions.Flags.IngressNamespace = "kube-system"
+	options.InitOptions.Flags.IngressDeployment = "jxing-nginx-ingress-controller"
+	options.InitOptions.Flags.IngressService = "jxing-nginx-ingress-controller"
+	options.InitOptions.Flags.IngressKind = "LoadBalancer"
+	options.CreateEnvOptions.HelmValuesConfig = config.HelmValuesConfig{ExposeController: &config.ExposeController{}}
+	return options
+}
+
+func existingIngressController() []runtime.Object {
+	labels := map[string]string{"app": "nginx-ingress"}
+	return []runtime.Object{
+		&extv1beta1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "jxing-nginx-ingress-controller", Namespace: "kube-system"},
+			Spec:       extv1beta1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: labels}},
+		},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "jxing-nginx-ingress-controller-1", Namespace: "kube-system", Labels: labels}},
+	}
+}
+
+func TestDryRunPlan(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		provider     string
+		objects      []runtime.Object
+		configure    func(options *InstallOptions)
+		environments []string
+		expected     []string
+		unexpected   []string
+	}{
+		{
+			name:     "installs the ingress controller",
+			provider: GKE,
+			expected: []string{
+				"Jenkins X install plan for provider gke",
+				"namespace:          jx",
+				"domain:             jx.example.com",
+				"ingress controller: install kube-system/jxing-nginx-ingress-controller",
+				"platform chart:     install jenkins-x/jenkins-x-platform 1.2.3 as release jenkins-x",
+			},
+			unexpected: []string{"external-dns:", "TLS secret:", "prow:", "storage:", "environment:", "service account:"},
+		},
+		{
+			name:       "keeps an existing ingress controller",
+			provider:   GKE,
+			objects:    existingIngressController(),
+			expected:   []string{"ingress controller: kube-system/jxing-nginx-ingress-controller already exists"},
+			unexpected: []string{"ingress controller: install"},
+		},
+		{
+			name:     "reuses the ingress service",
+			provider: GKE,
+			configure: func(options *InstallOptions) {
+				options.InitOptions.Flags.SkipIngressInstall = true
+			},
+			expected:   []string{"ingress controller: reuse the LoadBalancer service kube-system/jxing-nginx-ingress-controller"},
+			unexpected: []string{"ingress controller: install"},
+		},
+		{
+			name:     "skips the ingress controller",
+			provider: GKE,
+			configure: func(options *InstallOptions) {
+				options.InitOptions.Flags.SkipIngress = true
+			},
+			unexpected: []string{"ingress controller:"},
+		},
+		{
+			name:     "installs external-dns for the provider",
+			provider: AKS,
+			configure: func(options *InstallOptions) {
+				options.Flags.ExternalDNS = true
+			},
+			expected: []string{"external-dns:       install using azure for the zone jx.example.com"},
+		},
+		{
+			name:     "creates the TLS secret",
+			provider: GKE,
+			configure: func(options *InstallOptions) {
+				options.Flags.TLSCertFile = "tls.crt"
+			},
+			expected: []string{"TLS secret:         create from tls.crt"},
+		},
+		{
+			name:     "installs prow",
+			provider: GKE,
+			configure: func(options *InstallOptions) {
+				options.Flags.Prow = true
+			},
+			expected: []string{"prow:               install"},
+		},
+		{
+			name:     "stores into the specified buckets",
+			provider: GKE,
+			configure: func(options *InstallOptions) {
+				options.Flags.LogsBucket = "gs://my-logs"
+				options.Flags.ReportsBucket = "gs://my-reports"
+			},
+			expected: []string{
+				"storage:            store the logs in gs://my-logs",
+				"storage:            store the reports in gs://my-reports",
+			},
+			unexpected: []string{"storage:            store the repository"},
+		},
+		{
+			name:     "creates the logs bucket on AWS",
+			provider: EKS,
+			expected: []string{"storage:            create an encrypted S3 bucket for the logs expiring after 90 days"},
+		},
+		{
+			name:     "keeps the specified logs bucket on AWS",
+			provider: AWS,
+			configure: func(options *InstallOptions) {
+				options.Flags.LogsBucket = "s3://my-logs"
+			},
+			expected:   []string{"storage:            store the logs in s3://my-logs"},
+			unexpected: []string{"create an encrypted S3 bucket"},
+		},
+		{
+			name:         "creates the environment repositories",
+			provider:     GKE,
+			environments: []string{"environment-silly-staging", "environment-silly-production"},
+			expected: []string{
+				"environment:        create the git repository environment-silly-staging with a webhook",
+				"environment:        create the git repository environment-silly-production with a webhook",
+			},
+		},
+		{
+			name:     "annotates the builds with the workload identity service account",
+			provider: GKE,
+			configure: func(options *InstallOptions) {
+				options.GKEWorkloadIdentityServiceAccount = "jx-builds@myproject.iam.gserviceaccount.com"
+			},
+			expected: []string{"service account:    annotate the builds to use jx-builds@myproject.iam.gserviceaccount.com"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := newDryRunInstallOptions(tt.provider)
+			if tt.configure != nil {
+				tt.configure(options)
+			}
+			client := fake.NewSimpleClientset(tt.objects...)
+
+			plan := options.dryRunPlan(client, "jx", "jx.example.com", "1.2.3", tt.environments)
+
+			lines := strings.Split(plan, "\n")
+			for _, expected := range tt.expected {
+				assert.Contains(t, lines, expected)
+			}
+			for _, unexpected := range tt.unexpected {
+				assert.NotContains(t, plan, unexpected)
+			}
+		})
+	}
+}
+
+func TestDryRunDomain(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		objects  []runtime.Object
+		expected string
+	}{
+		{
+			name: "uses the domain of the existing install",
+			objects: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "ingress-config", Namespace: "jx"},
+					Data:       map[string]string{"domain": "jx.example.com"},
+				},
+			},
+			expected: "jx.example.com",
+		},
+		{
+			name: "uses the external IP of the ingress controller",
+			objects: []runtime.Object{
+				&corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{Name: "jxing-nginx-ingress-controller", Namespace: "kube-system"},
+					Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{
+						Ingress: []corev1.LoadBalancerIngress{{IP: "1.2.3.4"}},
+					}},
+				},
+			},
+			expected: "1.2.3.4.nip.io",
+		},
+		{
+			name:     "uses a placeholder without an external IP",
+			expected: dryRunDomainPlaceholder,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := newDryRunInstallOptions(GKE)
+			client := fake.NewSimpleClientset(tt.objects...)
+
+			assert.Equal(t, tt.expected, options.dryRunDomain(client, "jx"))
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// ingressProbeName the name of the temporary ingress which checks an existing ingress controller admits ingresses
+	ingressProbeName = "jx-ingress-probe"
+)
+
+// probeExistingIngress creates a temporary ingress with the ingress class of the existing ingress controller to check
+// that the controller admits the ingresses exposecontroller creates
+func (options *InstallOptions) probeExistingIngress(client kubernetes.Interface, ns string, domain string) error {
+	ingressClass := options.InitOptions.Flags.IngressClass
+	host := ingressProbeName + "." + domain
+	log.Infof("Checking the ingress controller with the class %s admits the ingress of %s\n", util.ColorInfo(ingressClass), util.ColorInfo(host))
+	err := kube.ProbeIngress(client, ns, ingressProbeName, host, ingressClass, "jenkins", 8080, 2*time.Minute)
+	if err != nil {
+		log.Warnf("The ingresses of Jenkins X may not be reachable: %s\n", err)
+		return nil
+	}
+	log.Infof("The ingress controller with the class %s admitted the probe ingress\n", util.ColorInfo(ingressClass))
+	return nil
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/jenkins-x/jx/pkg/vault"
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// defaultVaultRole the default role of the Kubernetes auth method the build service account logs in with
+	defaultVaultRole = "jenkins-x"
+
+	// defaultVaultProbeImage the image of the job which checks the build service account can log in to the Vault
+	defaultVaultProbeImage = "curlimages/curl:7.72.0"
+
+	vaultProbeJob = "jx-vault-probe"
+)
+
+// validateExternalVault checks the --external-vault-url flag is a URL
+func (options *InstallOptions) validateExternalVault() error {
+	u, err := url.Parse(options.Flags.ExternalVaultURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return util.InvalidOptionf("external-vault-url", options.Flags.ExternalVaultURL, "the URL of the Vault such as https://vault.example.com:8200")
+	}
+	return nil
+}
+
+// configureExternalVault checks the build service account can log in to the external Vault from inside the cluster
+// then stores the Vault as the secrets location of the team
+func (options *InstallOptions) configureExternalVault(ns string) error {
+	flags := options.Flags
+	log.Infof("Checking the service account %s can log in to the Vault %s with the role %s\n", util.ColorInfo(jxBuilderServiceAccount),
+		util.ColorInfo(flags.ExternalVaultURL), util.ColorInfo(flags.VaultRole))
+	err := options.probeVaultLogin(ns)
+	if err != nil {
+		return err
+	}
+
+	callback := func(env *v1.Environment) error {
+		env.Spec.TeamSettings.SecretsLocation = v1.SecretsLocation{
+			VaultURL:          flags.ExternalVaultURL,
+			VaultAuthPath:     flags.VaultAuthPath,
+			VaultRole:         flags.VaultRole,
+			VaultSecretPrefix: flags.VaultSecretPrefix,
+		}
+		log.Infof("Storing the secrets location %s in the TeamSettings\n", util.ColorInfo(flags.ExternalVaultURL+" "+flags.VaultSecretPrefix))
+		return nil
+	}
+	return options.ModifyDevEnvironment(callback)
+}
+
+// probeVaultLogin runs a job as the build service account which logs in to the Vault with its service account token
+func (options *InstallOptions) probeVaultLogin(ns string) error {
+	client, _, err := options.KubeClient()
+	if err != nil {
+		return err
+	}
+	jobs := client.BatchV1().Jobs(ns)
+	deletePropagation := metav1.DeletePropagationForeground
+	deleteOptions := &metav1.DeleteOptions{PropagationPolicy: &deletePropagation}
+	jobs.Delete(vaultProbeJob, deleteOptions)
+
+	backoffLimit := int32(0)
+	flags := options.Flags
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: vaultProbeJob,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					ServiceAccountName: jxBuilderServiceAccount,
+					RestartPolicy:      corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:    "vault-login",
+							Image:   options.offlineConfig.ImageName(flags.VaultProbeImage),
+							Command: []string{"sh", "-c", vault.LoginScript(flags.ExternalVaultURL, flags.VaultAuthPath, flags.VaultRole)},
+						},
+					},
+				},
+			},
+		},
+	}
+	_, err = jobs.Create(job)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create the Vault probe job %s", vaultProbeJob)
+	}
+	defer jobs.Delete(vaultProbeJob, deleteOptions)
+
+	err = kube.WaitForJobToTerminate(client, ns, vaultProbeJob, 5*time.Minute)
+	if err != nil {
+		return err
+	}
+	job, err = jobs.Get(vaultProbeJob, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if !kube.IsJobSucceeded(job) {
+		return fmt.Errorf("the service account %s in namespace %s failed to log in to the Vault %s using the auth path %s and the role %s, check the role binds the service account and the Vault is reachable from the cluster",
+			jxBuilderServiceAccount, ns, flags.ExternalVaultURL, flags.VaultAuthPath, flags.VaultRole)
+	}
+	log.Infof("The service account %s logged in to the Vault\n", util.ColorInfo(jxBuilderServiceAccount))
+	return nil
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/pkg/errors"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+const (
+	// jxBuilderRole the role of the build service account of a namespace scoped install
+	jxBuilderRole = "jenkins-x-builder"
+)
+
+// prepareNamespaceScopedInstall checks the CRDs a cluster administrator registered with 'jx step install crds' and
+// turns off the features which need cluster roles
+func (options *InstallOptions) prepareNamespaceScopedInstall() error {
+	apisClient, err := options.CreateApiExtensionsClient()
+	if err != nil {
+		return errors.Wrap(err, "failed to create the API extensions client")
+	}
+	err = kube.RegisterAllCRDs(apisClient)
+	if err != nil {
+		return errors.Wrap(err, "the Jenkins X CRDs are not registered")
+	}
+	if options.Flags.ExternalDNS {
+		log.Warnf("Not installing external-dns as it needs cluster roles, please create the DNS records of the domain yourself\n")
+		options.Flags.ExternalDNS = false
+	}
+	if options.Flags.Prow {
+		log.Warnf("Prow may need cluster roles which a namespace scoped install cannot create, ask a cluster administrator if its install fails\n")
+	}
+	if options.Flags.NoDefaultEnvironments {
+		return nil
+	}
+	log.Warnf("The namespaces of the default environments must already exist as a namespace scoped install cannot create them\n")
+	return nil
+}
+
+// configureNamespaceScopedValues turns off the ClusterRoles and ClusterRoleBindings of the charts of the platform so
+// that they only create Roles and RoleBindings in the team namespace
+func (options *InstallOptions) configureNamespaceScopedValues(helmConfig *config.HelmValuesConfig) {
+	cluster := false
+	if helmConfig.ExposeController == nil {
+		helmConfig.ExposeController = &config.ExposeController{}
+	}
+	helmConfig.ExposeController.RBAC.Cluster = &cluster
+	helmConfig.Jenkins.RBAC.Cluster = &cluster
+	helmConfig.ControllerBuild.RBAC.Cluster = &cluster
+	helmConfig.ControllerTeam.RBAC.Cluster = &cluster
+	helmConfig.ControllerWorkflow.RBAC.Cluster = &cluster
+}
+
+// ensureNamespacedBuildRole binds the build service account to a role in the team namespace rather than a cluster role
+func (options *InstallOptions) ensureNamespacedBuildRole(ns string) error {
+	rules := []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{"", "apps", "extensions", "batch"},
+			Resources: []string{"*"},
+			Verbs:     []string{"*"},
+		},
+		{
+			APIGroups: []string{"jenkins.io"},
+			Resources: []string{"*"},
+			Verbs:     []string{"*"},
+		},
+	}
+	err := options.ensureRole(ns, jxBuilderRole, rules)
+	if err != nil {
+		return err
+	}
+	return options.ensureRoleBinding(ns, jxBuilderRole, jxBuilderRole, ns, jxBuilderServiceAccount)
+}
+
+// enableNamespaceScopedTeamSettings records in the team settings that the install is namespace scoped so that the
+// commands which would otherwise need cluster roles degrade
+func (options *InstallOptions) enableNamespaceScopedTeamSettings() error {
+	callback := func(env *v1.Environment) error {
+		env.Spec.TeamSettings.NamespaceScoped = true
+		log.Info("Enabling the namespace scoped mode in the TeamSettings\n")
+		return nil
+	}
+	return options.ModifyDevEnvironment(callback)
+}
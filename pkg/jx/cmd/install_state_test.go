@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"testing"
+
+	jxfake "github.com/jenkins-x/jx/pkg/client/clientset/versioned/fake"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestInstallResumesOnlyAnInterruptedInstall(t *testing.T) {
+	t.Parallel()
+	client := fake.NewSimpleClientset()
+	options := &InstallOptions{}
+	require.NoError(t, options.loadInstallState(client, "jx"))
+	assert.False(t, options.resumingInstall)
+
+	require.NoError(t, options.installState.CompleteStep(installStepIngress))
+	require.NoError(t, options.installState.SetValue(installStateDomain, "jx.example.com"))
+
+	interrupted := &InstallOptions{}
+	require.NoError(t, interrupted.loadInstallState(client, "jx"))
+	assert.True(t, interrupted.resumingInstall)
+	assert.Equal(t, "jx.example.com", interrupted.InitOptions.Flags.Domain)
+
+	// a successful install removes the state
+	require.NoError(t, interrupted.installState.Reset())
+	_, err := client.CoreV1().ConfigMaps("jx").Get(kube.ConfigMapNameJXInstallState, metav1.GetOptions{})
+	assert.Error(t, err)
+
+	rerun := &InstallOptions{}
+	require.NoError(t, rerun.loadInstallState(client, "jx"))
+	assert.False(t, rerun.resumingInstall)
+	assert.Equal(t, "", rerun.InitOptions.Flags.Domain)
+}
+
+func TestDefaultEnvironmentsAreOnlyCreatedOnTheFirstInstall(t *testing.T) {
+	t.Parallel()
+	devEnv := kube.NewPermanentEnvironment("dev")
+	devEnv.Spec.Namespace = "jx"
+	qaEnv := kube.NewPermanentEnvironment("qa")
+	jxClient := jxfake.NewSimpleClientset(devEnv, qaEnv)
+	options := &InstallOptions{}
+
+	err := options.createDefaultEnvironments(jxClient, "jx")
+	require.NoError(t, err)
+
+	_, names, err := kube.GetEnvironments(jxClient, "jx")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dev", "qa"}, names)
+}
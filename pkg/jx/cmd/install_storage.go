@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/cloud/amazon"
+	"github.com/jenkins-x/jx/pkg/cloud/buckets"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+const (
+	// StorageClassifierLogs the storage location of the build logs
+	StorageClassifierLogs = "logs"
+	// StorageClassifierReports the storage location of the test and code coverage reports
+	StorageClassifierReports = "reports"
+	// StorageClassifierRepository the storage location of the artifact repository
+	StorageClassifierRepository = "repository"
+
+	// logsBucketExpirationDays the days after which the objects of the logs bucket created on AWS expire
+	logsBucketExpirationDays = 90
+)
+
+// storageBuckets returns the bucket URLs specified on the command line indexed by their storage classifier
+func (options *InstallOptions) storageBuckets() map[string]string {
+	answer := map[string]string{}
+	for classifier, bucketURL := range map[string]string{
+		StorageClassifierLogs:       options.Flags.LogsBucket,
+		StorageClassifierReports:    options.Flags.ReportsBucket,
+		StorageClassifierRepository: options.Flags.RepositoryBucket,
+	} {
+		if bucketURL != "" {
+			answer[classifier] = bucketURL
+		}
+	}
+	return answer
+}
+
+// validateStorageBuckets checks the specified buckets are writable before anything is installed
+func (options *InstallOptions) validateStorageBuckets() error {
+	for classifier, bucketURL := range options.storageBuckets() {
+		log.Infof("Checking the %s bucket %s is writable\n", classifier, util.ColorInfo(bucketURL))
+		err := buckets.ProbeBucket(bucketURL, options.AWSProfile, options.AWSRegion)
+		if err != nil {
+			return util.InvalidOptionf(fmt.Sprintf("%s-bucket", classifier), bucketURL, "%s", err)
+		}
+	}
+	return nil
+}
+
+// configureStorage creates the logs bucket on AWS if none was specified then records the storage locations in the
+// team settings
+func (options *InstallOptions) configureStorage() error {
+	locations := options.storageBuckets()
+	createdBucketURL := ""
+	if locations[StorageClassifierLogs] == "" && (options.Flags.Provider == AWS || options.Flags.Provider == EKS) {
+		teamSettings, err := options.TeamSettings()
+		if err != nil {
+			return err
+		}
+		location := teamSettings.StorageLocation(StorageClassifierLogs)
+		if location != nil && location.BucketURL != "" {
+			log.Infof("Using the existing logs bucket %s\n", util.ColorInfo(location.BucketURL))
+		} else {
+			createdBucketURL, err = options.createLogsS3Bucket()
+			if err != nil {
+				return err
+			}
+			locations[StorageClassifierLogs] = createdBucketURL
+		}
+	}
+	if len(locations) == 0 {
+		return nil
+	}
+	callback := func(env *v1.Environment) error {
+		for classifier, bucketURL := range locations {
+			env.Spec.TeamSettings.SetStorageLocation(classifier, bucketURL, bucketURL == createdBucketURL)
+		}
+		return nil
+	}
+	err := options.ModifyDevEnvironment(callback)
+	if err != nil {
+		return errors.Wrap(err, "failed to record the storage locations in the team settings")
+	}
+	for classifier, bucketURL := range locations {
+		log.Infof("The %s are stored in %s\n", classifier, util.ColorInfo(bucketURL))
+	}
+	return nil
+}
+
+// createLogsS3Bucket creates an encrypted S3 bucket for the build logs whose objects expire, returning its URL
+func (options *InstallOptions) createLogsS3Bucket() (string, error) {
+	accountID, region, err := amazon.GetAccountIDAndRegion(options.AWSProfile, options.AWSRegion)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to find the AWS account")
+	}
+	bucketName := "jx-logs-" + accountID + "-" + string(uuid.NewUUID())
+	log.Infof("Creating S3 bucket %s to store the build logs\n", util.ColorInfo(bucketName))
+	_, err = amazon.CreateS3Bucket(bucketName, options.AWSProfile, region, options.CloudResourceTags)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create the S3 bucket %s", bucketName)
+	}
+	err = amazon.SecureS3Bucket(bucketName, options.AWSProfile, region, logsBucketExpirationDays)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to configure the encryption and lifecycle of the S3 bucket %s", bucketName)
+	}
+	return buckets.SchemeS3 + "://" + bucketName, nil
+}
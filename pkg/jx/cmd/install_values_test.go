@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkloadIdentityDockerConfigHoldsNoCredentials(t *testing.T) {
+	t.Parallel()
+	options := &InstallOptions{
+		GKEWorkloadIdentityServiceAccount: "jx-builds@myproject.iam.gserviceaccount.com",
+	}
+	helmConfig := &config.HelmValuesConfig{}
+	helmConfig.PipelineSecrets.DockerConfig = `{"auths": {"gcr.io": {"auth": "c2VjcmV0"}}}`
+
+	options.configureWorkloadIdentityDockerConfig(helmConfig, "eu.gcr.io")
+
+	dockerConfig := map[string]interface{}{}
+	err := yaml.Unmarshal([]byte(helmConfig.PipelineSecrets.DockerConfig), &dockerConfig)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"auths":       map[string]interface{}{},
+		"credHelpers": map[string]interface{}{"eu.gcr.io": "gcr"},
+	}, dockerConfig)
+}
+
+func TestNamespaceScopedValuesTurnOffClusterRBAC(t *testing.T) {
+	t.Parallel()
+	options := &InstallOptions{}
+	helmConfig := &config.HelmValuesConfig{
+		ExposeController: &config.ExposeController{Config: config.ExposeControllerConfig{Domain: "jx.example.com"}},
+	}
+
+	options.configureNamespaceScopedValues(helmConfig)
+
+	text, err := helmConfig.String()
+	require.NoError(t, err)
+	values := map[string]interface{}{}
+	err = yaml.Unmarshal([]byte(text), &values)
+	require.NoError(t, err)
+	rbac := map[string]interface{}{"rbac": map[string]interface{}{"cluster": false}}
+	for _, chart := range []string{"controllerbuild", "controllerteam", "controllerworkflow"} {
+		assert.Equal(t, rbac, values[chart], "the values of %s", chart)
+	}
+	assert.Equal(t, rbac["rbac"], values["expose"].(map[string]interface{})["rbac"])
+	assert.Equal(t, rbac["rbac"], values["jenkins"].(map[string]interface{})["rbac"])
+	assert.Equal(t, "jx.example.com", values["expose"].(map[string]interface{})["config"].(map[string]interface{})["domain"])
+}
+
+func TestClusterRBACValuesAreOmittedByDefault(t *testing.T) {
+	t.Parallel()
+	helmConfig := &config.HelmValuesConfig{ExposeController: &config.ExposeController{}}
+
+	text, err := helmConfig.String()
+	require.NoError(t, err)
+	assert.NotContains(t, text, "rbac")
+	assert.NotContains(t, text, "controllerbuild")
+}
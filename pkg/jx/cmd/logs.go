@@ -3,8 +3,10 @@ package cmd
 import (
 	"fmt"
 	"io"
+	"os"
 	"time"
 
+	"github.com/fatih/color"
 	"github.com/jenkins-x/jx/pkg/builds"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/kube"
@@ -29,12 +31,25 @@ type LogsOptions struct {
 	Label           string
 	EditEnvironment bool
 	KNativeBuild    bool
+	AllContainers   bool
+	Previous        bool
+	Since           time.Duration
+
+	// streamLogs opens the log of a container, defaults to the log of the API server
+	streamLogs func(ns string, pod string, options *corev1.PodLogOptions) (io.ReadCloser, error)
+	noFollow   bool
 }
 
+// the colors of the prefixes of the lines of the logs of the different pods and containers
+var logPrefixColors = []color.Attribute{color.FgCyan, color.FgGreen, color.FgMagenta, color.FgYellow, color.FgBlue, color.FgHiCyan, color.FgHiGreen, color.FgHiMagenta}
+
 var (
 	logs_long = templates.LongDesc(`
 		Tails the logs of the newest pod for a Deployment.
 
+		The logs of all the pods of the Deployment or matching the label selector are interleaved when any of the
+		--label, --all-containers, --previous or --since options are used. Each line is prefixed with its pod and
+		container and the logs of new pods, such as those of a rolling deploy, are followed as they start.
 `)
 
 	logs_example = templates.Examples(`
@@ -46,6 +61,15 @@ var (
 
 		# Tails the log of the latest Knative build pod
 		jx logs -k
+
+		# Tails the logs of all the containers of all the pods of deployment myapp in the staging environment
+		jx logs myapp --all-containers -e staging
+
+		# Shows the log of the crashed instances of the pods of deployment myapp before tailing their logs
+		jx logs myapp --previous
+
+		# Tails the logs of the last 10 minutes of the pods with a label
+		jx logs -l app=myapp --since 10m | grep ERROR
 `)
 )
 
@@ -76,9 +100,12 @@ func NewCmdLogs(f Factory, in terminal.FileReader, out terminal.FileWriter, errO
 	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "the namespace to look for the Deployment. Defaults to the current namespace")
 	cmd.Flags().StringVarP(&options.Environment, "env", "e", "", "the Environment to look for the Deployment. Defaults to the current environment")
 	cmd.Flags().StringVarP(&options.Filter, "filter", "f", "", "Filters the available deployments if no deployment argument is provided")
-	cmd.Flags().StringVarP(&options.Label, "label", "l", "", "The label to filter the pods if no deployment argument is provided")
+	cmd.Flags().StringVarP(&options.Label, "label", "l", "", "The label selector of the pods whose logs are shown if no deployment argument is provided")
 	cmd.Flags().BoolVarP(&options.KNativeBuild, "knative-build", "k", false, "View the logs of the latest Knative build pod")
 	cmd.Flags().BoolVarP(&options.EditEnvironment, "edit", "d", false, "Use my Edit Environment to look for the Deployment pods")
+	cmd.Flags().BoolVarP(&options.AllContainers, "all-containers", "", false, "Show the logs of all the init containers and containers of the pods")
+	cmd.Flags().BoolVarP(&options.Previous, "previous", "p", false, "Show the logs of the previous instances of the restarted containers before their current logs")
+	cmd.Flags().DurationVarP(&options.Since, "since", "", 0, "Only show the lines logged within this duration such as 10m or 1h")
 	return cmd
 }
 
@@ -117,7 +144,8 @@ func (o *LogsOptions) Run() error {
 	if err != nil {
 		return fmt.Errorf("Could not find deployments in namespace %s with filter %s: %s", ns, o.Filter, err)
 	}
-	if len(names) == 0 {
+	// the pods of a label selector need not belong to a Deployment
+	if len(names) == 0 && (o.Label == "" || len(args) > 0) {
 		if o.Filter == "" {
 			return fmt.Errorf("There are no Deployments")
 		} else {
@@ -140,6 +168,19 @@ func (o *LogsOptions) Run() error {
 		}
 	}
 
+	if !o.KNativeBuild && o.interleaveLogs() {
+		selector := o.Label
+		if name != "" {
+			selector, err = deploymentSelector(client, ns, name, names)
+		} else {
+			_, err = labels.Parse(selector)
+		}
+		if err != nil {
+			return err
+		}
+		return o.streamPodLogs(client, ns, selector)
+	}
+
 	for {
 		pod := ""
 		if o.KNativeBuild {
@@ -175,6 +216,61 @@ func (o *LogsOptions) Run() error {
 	}
 }
 
+// interleaveLogs returns true if the logs of all the matching pods are interleaved rather than tailing the log of the
+// newest pod
+func (o *LogsOptions) interleaveLogs() bool {
+	return o.Label != "" || o.AllContainers || o.Previous || o.Since > 0
+}
+
+// deploymentSelector returns the label selector of the pods of the Deployment
+func deploymentSelector(c kubernetes.Interface, ns string, name string, names []string) (string, error) {
+	deployment, err := c.AppsV1beta1().Deployments(ns).Get(name, metav1.GetOptions{})
+	if err != nil || deployment == nil {
+		return "", util.InvalidArg(name, names)
+	}
+	if deployment.Spec.Selector == nil {
+		return "", fmt.Errorf("No selector defined on Deployment %s in namespace %s", name, ns)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return "", err
+	}
+	return selector.String(), nil
+}
+
+// streamPodLogs interleaves the logs of the pods matching the selector, prefixing each line with its pod and container
+func (o *LogsOptions) streamPodLogs(client kubernetes.Interface, ns string, selector string) error {
+	var out io.Writer = os.Stdout
+	if o.Out != nil {
+		out = o.Out
+	}
+	colors := map[string]*color.Color{}
+	streamer := &kube.PodLogStreamer{
+		Client: client,
+		Options: kube.PodLogsOptions{
+			Namespace:     ns,
+			Selector:      selector,
+			Container:     o.Container,
+			AllContainers: o.AllContainers,
+			Previous:      o.Previous,
+			Since:         o.Since,
+			Follow:        !o.noFollow,
+		},
+		Out:        out,
+		OpenStream: o.streamLogs,
+		Prefix: func(pod string, container string) string {
+			name := pod + "/" + container
+			c := colors[name]
+			if c == nil {
+				c = color.New(logPrefixColors[len(colors)%len(logPrefixColors)])
+				colors[name] = c
+			}
+			return c.Sprint(name) + " "
+		},
+	}
+	return streamer.Run(nil)
+}
+
 func parseSelector(selectorText string) (map[string]string, error) {
 	selector, err := metav1.ParseToLabelSelector(selectorText)
 	if err != nil {
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1beta1 "k8s.io/api/apps/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestLogsStreamPodLogsOfDeployment(t *testing.T) {
+	t.Parallel()
+	deployment := &appsv1beta1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "myapp", Namespace: "jx-staging"},
+		Spec: appsv1beta1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "app", Operator: metav1.LabelSelectorOpIn, Values: []string{"myapp"}},
+				},
+			},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "myapp-1", Namespace: "jx-staging", Labels: map[string]string{"app": "myapp"}},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "app"}, {Name: "sidecar"}},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "app", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}}},
+				{Name: "sidecar", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+			},
+		},
+	}
+	client := fake.NewSimpleClientset(deployment, pod)
+
+	selector, err := deploymentSelector(client, "jx-staging", "myapp", []string{"myapp"})
+	require.NoError(t, err)
+	assert.Equal(t, "app in (myapp)", selector)
+
+	out, err := ioutil.TempFile("", "test-logs")
+	require.NoError(t, err)
+	defer os.Remove(out.Name())
+	o := &LogsOptions{
+		CommonOptions: CommonOptions{Out: out},
+		Container:     "sidecar",
+		Since:         time.Minute,
+		noFollow:      true,
+		streamLogs: func(ns string, pod string, options *corev1.PodLogOptions) (io.ReadCloser, error) {
+			return ioutil.NopCloser(strings.NewReader("log of " + pod + "/" + options.Container + "\n")), nil
+		},
+	}
+	assert.True(t, o.interleaveLogs())
+	err = o.streamPodLogs(client, "jx-staging", selector)
+	require.NoError(t, err)
+
+	data, err := ioutil.ReadFile(out.Name())
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "myapp-1/sidecar")
+	assert.True(t, strings.HasSuffix(string(data), " log of myapp-1/sidecar\n"), "unexpected output %q", string(data))
+}
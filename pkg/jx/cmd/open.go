@@ -1,12 +1,17 @@
 package cmd
 
 import (
+	"fmt"
 	"io"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
 
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
 )
 
 type OpenOptions struct {
@@ -17,7 +22,11 @@ var (
 	open_long = templates.LongDesc(`
 		Opens a named service in the browser.
 
-		You can use the '--url' argument to just display the URL without opening it`)
+		The name can be part of the name of the service, or its letters in order, in which case you pick the service to
+		open if several services match.
+
+		You can use the '--url' argument to just display the URL without opening it. The URL is also displayed when
+		there is no browser to open it, e.g. in an SSH session`)
 
 	open_example = templates.Examples(`
 		# Open the Nexus console in a browser
@@ -26,6 +35,9 @@ var (
 		# Print the Nexus console URL but do not open a browser
 		jx open jenkins-x-sonatype-nexus -u
 
+		# Open the service whose name contains myapp in the production environment
+		jx open myapp --env production
+
 		# List all the service URLs
 		jx open`)
 )
@@ -66,6 +78,116 @@ func (o *OpenOptions) Run() error {
 	if len(o.Args) == 0 {
 		return o.GetURLOptions.Run()
 	}
-	name := o.Args[0]
-	return o.ConsoleOptions.Open(name, name)
+	url, err := o.findURL(o.Args[0])
+	if err != nil {
+		return err
+	}
+	o.openURL(url.Name, o.serviceURL(url))
+	return nil
+}
+
+// serviceURL returns the URL to open for the service, which is the Blue Ocean UI of Jenkins unless --classic is used
+func (o *OpenOptions) serviceURL(url *kube.ExposedURL) string {
+	if url.Name == kube.ServiceJenkins {
+		return o.urlForMode(url.URL)
+	}
+	return url.URL
+}
+
+// findURL returns the URL of the service whose name matches the given name, letting the user pick the service if
+// several services match
+func (o *OpenOptions) findURL(name string) (*kube.ExposedURL, error) {
+	client, ns, err := o.KubeClient()
+	if err != nil {
+		return nil, err
+	}
+	namespaces := []string{}
+	if o.Namespace != "" {
+		namespaces = append(namespaces, o.Namespace)
+	} else if o.Environment != "" {
+		envNs, err := o.findEnvironmentNamespace(o.Environment)
+		if err != nil {
+			return nil, err
+		}
+		namespaces = append(namespaces, envNs)
+	} else {
+		devNs, _, err := kube.GetDevNamespace(client, ns)
+		if err != nil {
+			return nil, err
+		}
+		namespaces = append(namespaces, ns)
+		if devNs != ns {
+			namespaces = append(namespaces, devNs)
+		}
+	}
+	urls := []kube.ExposedURL{}
+	for _, namespace := range namespaces {
+		nsURLs, err := kube.FindExposedURLs(client, namespace)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, nsURLs...)
+	}
+	matches := matchURLs(urls, name)
+	switch len(matches) {
+	case 0:
+		log.Infof("If the app %s is running in a different environment you could try: %s\n", util.ColorInfo(name), util.ColorInfo("jx get urls --all-environments"))
+		return nil, fmt.Errorf("Could not find URL for service %s in namespace %s", name, strings.Join(namespaces, ", "))
+	case 1:
+		return &matches[0], nil
+	}
+	labels := []string{}
+	for _, url := range matches {
+		labels = append(labels, fmt.Sprintf("%s %s", url.Name, url.URL))
+	}
+	if o.BatchMode {
+		return nil, fmt.Errorf("%s matches several services: %s", name, strings.Join(labels, ", "))
+	}
+	label, err := util.PickName(labels, "Pick service to open: ", o.In, o.Out, o.Err)
+	if err != nil {
+		return nil, err
+	}
+	idx := util.StringArrayIndex(labels, label)
+	if idx < 0 {
+		return nil, fmt.Errorf("no service was picked")
+	}
+	return &matches[idx], nil
+}
+
+// matchURLs returns the URL of the service with the given name if there is one, otherwise the URLs of the services
+// whose names contain the name ignoring the case or, failing that, contain its letters in order
+func matchURLs(urls []kube.ExposedURL, name string) []kube.ExposedURL {
+	for _, url := range urls {
+		if url.Name == name {
+			return []kube.ExposedURL{url}
+		}
+	}
+	lower := strings.ToLower(name)
+	answer := []kube.ExposedURL{}
+	for _, url := range urls {
+		if strings.Contains(strings.ToLower(url.Name), lower) {
+			answer = append(answer, url)
+		}
+	}
+	if len(answer) > 0 {
+		return answer
+	}
+	for _, url := range urls {
+		if containsInOrder(strings.ToLower(url.Name), lower) {
+			answer = append(answer, url)
+		}
+	}
+	return answer
+}
+
+// containsInOrder returns true if the text contains all the characters of the letters in the same order
+func containsInOrder(text string, letters string) bool {
+	for _, r := range letters {
+		idx := strings.IndexRune(text, r)
+		if idx < 0 {
+			return false
+		}
+		text = text[idx+len(string(r)):]
+	}
+	return true
 }
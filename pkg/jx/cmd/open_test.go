@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchURLs(t *testing.T) {
+	t.Parallel()
+	urls := []kube.ExposedURL{
+		{Name: "jenkins"},
+		{Name: "jenkins-x-chartmuseum"},
+		{Name: "jenkins-x-docker-registry"},
+		{Name: "jenkins-x-monocular-ui"},
+	}
+	names := func(matches []kube.ExposedURL) []string {
+		answer := []string{}
+		for _, url := range matches {
+			answer = append(answer, url.Name)
+		}
+		return answer
+	}
+
+	assert.Equal(t, []string{"jenkins"}, names(matchURLs(urls, "jenkins")), "an exact name should only match its service")
+	assert.Equal(t, []string{"jenkins-x-chartmuseum"}, names(matchURLs(urls, "Chart")))
+	assert.Equal(t, []string{"jenkins-x-chartmuseum", "jenkins-x-docker-registry", "jenkins-x-monocular-ui"}, names(matchURLs(urls, "jenkins-")))
+	assert.Equal(t, []string{"jenkins-x-docker-registry"}, names(matchURLs(urls, "dreg")))
+	assert.Equal(t, []string{"jenkins-x-chartmuseum", "jenkins-x-docker-registry", "jenkins-x-monocular-ui"}, names(matchURLs(urls, "jx")))
+	assert.Empty(t, matchURLs(urls, "nexus"))
+}
+
+func TestOpenJenkinsUsesTheConsoleMode(t *testing.T) {
+	t.Parallel()
+	jenkins := &kube.ExposedURL{Name: kube.ServiceJenkins, URL: "http://jenkins.jx.example.com"}
+	nexus := &kube.ExposedURL{Name: "nexus", URL: "http://nexus.jx.example.com"}
+
+	o := &OpenOptions{}
+	assert.Equal(t, "http://jenkins.jx.example.com/blue", o.serviceURL(jenkins))
+	assert.Equal(t, "http://nexus.jx.example.com", o.serviceURL(nexus))
+
+	o.ClassicMode = true
+	assert.Equal(t, "http://jenkins.jx.example.com", o.serviceURL(jenkins))
+}
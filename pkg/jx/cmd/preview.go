@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -8,6 +9,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
@@ -17,6 +19,7 @@ import (
 	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
 	batchv1 "k8s.io/api/batch/v1"
@@ -31,11 +34,29 @@ var (
 
 		For more documentation on Preview Environments see: [https://jenkins-x.io/about/features/#preview-environments](https://jenkins-x.io/about/features/#preview-environments)
 
+		The values of the preview chart can be overridden for a single Pull Request by adding a 'values.pr.yaml' file
+		next to the preview chart on the Pull Request branch, or with '--set key=value' which is stored on the
+		Environment and reapplied every time the Pull Request is deployed. When the same value is set in several places
+		'--set' wins over 'values.pr.yaml' which wins over the values of the preview chart.
+
+		A single comment on the Pull Request is kept up to date with the preview URL, version and image of the last
+		deployment. Its body can be customised with a Go template via '--comment-template' or for the whole team via
+		'jx edit previewcommenttemplate'.
+
 `)
 
 	previewExample = templates.Examples(`
 		# Create or updates the Preview Environment for the Pull Request
 		jx preview
+
+		# Create or updates the Preview Environment and pin it so that it is never garbage collected
+		jx preview --keep
+
+		# Create or updates the Preview Environment overriding a value of the preview chart for this Pull Request
+		jx preview --set preview.resources.requests.memory=128Mi
+
+		# Create or updates the Preview Environment with a custom Pull Request comment
+		jx preview --comment-template ':rocket: {{ .Name }} version {{ .Version }} is at {{ .URL }}'
 	`)
 )
 
@@ -48,10 +69,37 @@ const (
 	DOCKER_REGISTRY_ORG                    = "DOCKER_REGISTRY_ORG"
 	PREVIEW_VERSION                        = "PREVIEW_VERSION"
 
+	// PreviewValuesPRFile the optional values file on a Pull Request branch which overrides the preview chart values
+	PreviewValuesPRFile = "values.pr.yaml"
+
+	// PreviewCommentMarker the hidden marker of the Pull Request comment which jx preview keeps up to date
+	PreviewCommentMarker = "jx-preview"
+
+	// DefaultPreviewCommentTemplate the default Go template of the Pull Request comment of jx preview
+	DefaultPreviewCommentTemplate = `:star: PR built and available in a preview environment **{{ .Name }}**{{ if .URL }} [here]({{ .URL }}){{ end }}
+
+| Version | Image | Last deployed |
+| --- | --- | --- |
+| {{ .Version }} | {{ .Image }}:{{ .ImageTag }} | {{ .Deployed }} |`
+
 	optionPostPreviewJobTimeout  = "post-preview-job-timeout"
 	optionPostPreviewJobPollTime = "post-preview-poll-time"
 )
 
+const previewCommentTimeFormat = "2006-01-02 15:04:05 MST"
+
+// PreviewCommentData the values available to the template of the Pull Request comment of a preview
+type PreviewCommentData struct {
+	Name           string
+	Namespace      string
+	URL            string
+	PullRequestURL string
+	Version        string
+	Image          string
+	ImageTag       string
+	Deployed       string
+}
+
 // PreviewOptions the options for viewing running PRs
 type PreviewOptions struct {
 	PromoteOptions
@@ -68,6 +116,9 @@ type PreviewOptions struct {
 	Dir                    string
 	PostPreviewJobTimeout  string
 	PostPreviewJobPollTime string
+	Keep                   bool
+	SetValues              []string
+	CommentTemplate        string
 
 	PullRequestName string
 	GitConfDir      string
@@ -132,6 +183,9 @@ func (options *PreviewOptions) addPreviewOptions(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&options.SourceRef, "source-ref", "", "", "The source code git ref (branch/sha)")
 	cmd.Flags().StringVarP(&options.PostPreviewJobTimeout, optionPostPreviewJobTimeout, "", "2h", "The duration before we consider the post preview Jobs failed")
 	cmd.Flags().StringVarP(&options.PostPreviewJobPollTime, optionPostPreviewJobPollTime, "", "10s", "The amount of time between polls for the post preview Job status")
+	cmd.Flags().BoolVarP(&options.Keep, "keep", "", false, "Pins the Preview Environment so that it is never garbage collected by 'jx gc previews'")
+	cmd.Flags().StringArrayVarP(&options.SetValues, "set", "", []string{}, "Overrides a value of the preview chart using key=value which is stored on the Environment and reapplied on subsequent deployments")
+	cmd.Flags().StringVarP(&options.CommentTemplate, "comment-template", "", "", "The Go template of the comment on the Pull Request. Defaults to the team setting or the built in template")
 }
 
 // Run implements the command
@@ -321,7 +375,7 @@ func (o *PreviewOptions) Run() error {
 			update = true
 		}
 
-		gitSpec := spec.PreviewGitSpec
+		gitSpec := &spec.PreviewGitSpec
 		if gitSpec.BuildStatus != buildStatus {
 			gitSpec.BuildStatus = buildStatus
 			update = true
@@ -357,6 +411,16 @@ func (o *PreviewOptions) Run() error {
 				update = true
 			}
 		}
+		gitSpec.HelmValues, err = mergePreviewHelmValues(gitSpec.HelmValues, o.SetValues)
+		if err != nil {
+			return err
+		}
+		// every deployment of the preview counts as activity for garbage collection
+		kube.SetPreviewLastActivity(env, time.Now())
+		if o.Keep {
+			env.Annotations[kube.AnnotationPreviewKeep] = "true"
+		}
+		update = true
 
 		if update {
 			env, err = environmentsResource.Update(env)
@@ -374,6 +438,10 @@ func (o *PreviewOptions) Run() error {
 			BuildStatus:     buildStatus,
 			BuildStatusURL:  buildStatusUrl,
 		}
+		previewGitSpec.HelmValues, err = mergePreviewHelmValues(nil, o.SetValues)
+		if err != nil {
+			return err
+		}
 		if pullRequest != nil {
 			previewGitSpec.Title = pullRequest.Title
 			previewGitSpec.Description = pullRequest.Body
@@ -400,6 +468,10 @@ func (o *PreviewOptions) Run() error {
 				PreviewGitSpec: previewGitSpec,
 			},
 		}
+		kube.SetPreviewLastActivity(env, time.Now())
+		if o.Keep {
+			env.Annotations[kube.AnnotationPreviewKeep] = "true"
+		}
 		_, err = environmentsResource.Create(env)
 		if err != nil {
 			return fmt.Errorf("Failed to create environment in namespace %s due to: %s", ns, err)
@@ -462,7 +534,15 @@ func (o *PreviewOptions) Run() error {
 		return err
 	}
 
-	err = o.Helm().UpgradeChart(".", o.ReleaseName, o.Namespace, nil, true, nil, true, true, nil, []string{configFileName})
+	valueFiles, err := previewHelmValueFiles(dir, configFileName)
+	if err != nil {
+		return err
+	}
+	setValues := env.Spec.PreviewGitSpec.HelmValues
+	if len(setValues) > 0 {
+		log.Infof("Overriding the preview chart values with %s\n", util.ColorInfo(strings.Join(setValues, ", ")))
+	}
+	err = o.Helm().UpgradeChart(".", o.ReleaseName, o.Namespace, nil, true, nil, true, true, setValues, valueFiles)
 	if err != nil {
 		return err
 	}
@@ -481,9 +561,22 @@ func (o *PreviewOptions) Run() error {
 		log.Warnf("Could not find the service URL in namespace %s for names %s\n", o.Namespace, strings.Join(appNames, ", "))
 	}
 
-	comment := fmt.Sprintf(":star: PR built and available in a preview environment **%s**", o.Name)
-	if url != "" {
-		comment += fmt.Sprintf(" [here](%s) ", url)
+	version := o.Version
+	if version == "" {
+		version = tag
+	}
+	comment, err := o.previewComment(&PreviewCommentData{
+		Name:           o.Name,
+		Namespace:      o.Namespace,
+		URL:            url,
+		PullRequestURL: o.PullRequestURL,
+		Version:        version,
+		Image:          repository,
+		ImageTag:       tag,
+		Deployed:       time.Now().UTC().Format(previewCommentTimeFormat),
+	})
+	if err != nil {
+		return err
 	}
 
 	pipeline := o.getJobName()
@@ -546,6 +639,7 @@ func (o *PreviewOptions) Run() error {
 			Repository: o.GitInfo.Name,
 			Comment:    comment,
 			PR:         o.PullRequestName,
+			Marker:     PreviewCommentMarker,
 		},
 		StepPROptions: StepPROptions{
 			StepOptions: StepOptions{
@@ -754,7 +848,17 @@ func (o *PreviewOptions) defaultValues(ns string, warnMissingName bool) error {
 		return fmt.Errorf("No name could be defaulted for the Preview Environment. Please supply one!")
 	}
 	if o.Namespace == "" {
-		o.Namespace = ns + "-" + o.Name
+		teamSettings, err := o.TeamSettings()
+		if err == nil && teamSettings.NamespaceScoped {
+			// a namespace scoped install cannot create namespaces so the preview is a release in the team namespace
+			log.Warnf("Deploying the preview %s into the team namespace %s as the install is namespace scoped\n", o.Name, ns)
+			o.Namespace = ns
+			if o.ReleaseName == "" {
+				o.ReleaseName = kube.ToValidName(ns + "-" + o.Name)
+			}
+		} else {
+			o.Namespace = ns + "-" + o.Name
+		}
 	}
 	o.Namespace = kube.ToValidName(o.Namespace)
 	if o.Label == "" {
@@ -825,3 +929,79 @@ func getImageTag() (string, error) {
 
 	return tag, nil
 }
+
+// previewComment renders the comment on the Pull Request using the template of the command line, the team or the
+// default template
+func (o *PreviewOptions) previewComment(data *PreviewCommentData) (string, error) {
+	commentTemplate := o.CommentTemplate
+	if commentTemplate == "" {
+		teamSettings, err := o.TeamSettings()
+		if err != nil {
+			return "", err
+		}
+		commentTemplate = teamSettings.PreviewCommentTemplate
+	}
+	return renderPreviewComment(commentTemplate, data)
+}
+
+// renderPreviewComment renders the Pull Request comment of a preview using the given Go template or the default
+// template if it is empty
+func renderPreviewComment(commentTemplate string, data *PreviewCommentData) (string, error) {
+	if commentTemplate == "" {
+		commentTemplate = DefaultPreviewCommentTemplate
+	}
+	tmpl, err := template.New("comment").Parse(commentTemplate)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse the preview comment template")
+	}
+	var buffer bytes.Buffer
+	err = tmpl.Execute(&buffer, data)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to render the preview comment template")
+	}
+	return buffer.String(), nil
+}
+
+// previewHelmValueFiles returns the values files of the preview chart in the given directory in increasing order of
+// precedence: the generated values followed by the values file of the Pull Request if there is one
+func previewHelmValueFiles(dir string, generatedValuesFile string) ([]string, error) {
+	answer := []string{generatedValuesFile}
+	prValuesFile := filepath.Join(dir, PreviewValuesPRFile)
+	exists, err := util.FileExists(prValuesFile)
+	if err != nil {
+		return answer, err
+	}
+	if exists {
+		log.Infof("Overriding the preview chart values with %s\n", util.ColorInfo(PreviewValuesPRFile))
+		answer = append(answer, prValuesFile)
+	}
+	return answer, nil
+}
+
+// mergePreviewHelmValues merges the key=value overrides into the existing ones, replacing the value of any key which
+// is already overridden and otherwise appending it so that the order in which helm applies them is kept
+func mergePreviewHelmValues(existing []string, overrides []string) ([]string, error) {
+	answer := append([]string{}, existing...)
+	for _, override := range overrides {
+		idx := strings.Index(override, "=")
+		if idx <= 0 {
+			return answer, fmt.Errorf("invalid --set value %s which should be of the form key=value", override)
+		}
+		prefix := override[0 : idx+1]
+		found := false
+		for i, value := range answer {
+			if strings.HasPrefix(value, prefix) {
+				answer[i] = override
+				found = true
+				break
+			}
+		}
+		if !found {
+			answer = append(answer, override)
+		}
+	}
+	if len(answer) == 0 {
+		return nil, nil
+	}
+	return answer, nil
+}
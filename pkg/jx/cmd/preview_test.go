@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergePreviewHelmValues(t *testing.T) {
+	t.Parallel()
+	values, err := mergePreviewHelmValues(nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, values)
+
+	values, err = mergePreviewHelmValues(nil, []string{"featureFlag=true", "downstream.url=http://stub"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"featureFlag=true", "downstream.url=http://stub"}, values)
+
+	// overrides from a later push replace the stored value of the same key in place and append new keys
+	values, err = mergePreviewHelmValues(values, []string{"downstream.url=http://other", "replicaCount=1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"featureFlag=true", "downstream.url=http://other", "replicaCount=1"}, values)
+
+	// a key which is a prefix of another key is a different value
+	values, err = mergePreviewHelmValues(values, []string{"feature=x"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"featureFlag=true", "downstream.url=http://other", "replicaCount=1", "feature=x"}, values)
+
+	_, err = mergePreviewHelmValues(values, []string{"=true"})
+	assert.Error(t, err)
+	_, err = mergePreviewHelmValues(values, []string{"featureFlag"})
+	assert.Error(t, err)
+}
+
+func TestPreviewHelmValueFiles(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-preview-values")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	generated := filepath.Join(dir, ExtraValuesFile)
+	files, err := previewHelmValueFiles(dir, generated)
+	require.NoError(t, err)
+	assert.Equal(t, []string{generated}, files)
+
+	// the values file of the Pull Request comes last so that helm lets it win over the generated values
+	prValues := filepath.Join(dir, PreviewValuesPRFile)
+	err = ioutil.WriteFile(prValues, []byte("featureFlag: true\n"), 0644)
+	require.NoError(t, err)
+	files, err = previewHelmValueFiles(dir, generated)
+	require.NoError(t, err)
+	assert.Equal(t, []string{generated, prValues}, files)
+}
+
+func TestRenderPreviewComment(t *testing.T) {
+	t.Parallel()
+	data := &PreviewCommentData{
+		Name:     "acme-myapp-pr-3",
+		URL:      "http://myapp.jx-acme-myapp-pr-3.example.com",
+		Version:  "0.0.0-SNAPSHOT-PR-3-2",
+		Image:    "10.0.0.1:5000/acme/myapp",
+		ImageTag: "0.0.0-SNAPSHOT-PR-3-2",
+		Deployed: "2018-11-01 09:00:00 UTC",
+	}
+
+	comment, err := renderPreviewComment("", data)
+	require.NoError(t, err)
+	assert.Equal(t, `:star: PR built and available in a preview environment **acme-myapp-pr-3** [here](http://myapp.jx-acme-myapp-pr-3.example.com)
+
+| Version | Image | Last deployed |
+| --- | --- | --- |
+| 0.0.0-SNAPSHOT-PR-3-2 | 10.0.0.1:5000/acme/myapp:0.0.0-SNAPSHOT-PR-3-2 | 2018-11-01 09:00:00 UTC |`, comment)
+
+	comment, err = renderPreviewComment("{{ .Name }} {{ .Version }} is at {{ .URL }}", data)
+	require.NoError(t, err)
+	assert.Equal(t, "acme-myapp-pr-3 0.0.0-SNAPSHOT-PR-3-2 is at http://myapp.jx-acme-myapp-pr-3.example.com", comment)
+
+	_, err = renderPreviewComment("{{ .Name ", data)
+	assert.Error(t, err)
+	_, err = renderPreviewComment("{{ .Unknown }}", data)
+	assert.Error(t, err)
+}
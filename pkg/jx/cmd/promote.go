@@ -60,6 +60,8 @@ type PromoteOptions struct {
 	PullRequestPollTime string
 	Filter              string
 	Alias               string
+	Rollback            bool
+	ToVersion           string
 
 	// allow git to be configured externally before a PR is created
 	ConfigureGitCallback ConfigureGitFolderFn
@@ -103,9 +105,15 @@ var (
         # discovering the application name from the source code
 		jx promote --version 1.2.3 --env staging
 
+		# Promote a version of the current application to staging and, once it is running there, to production
+		jx promote --version 1.2.3 --env staging,production
+
 		# Promote a version of the myapp application to production
 		jx promote myapp --version 1.2.3 --env production
 
+		# Roll back the myapp application in production to the version it had before the current one
+		jx promote --rollback --env production --app myapp
+
 		# To search for all the available charts for a given name use -f.
 		# e.g. to find a redis chart to install
 		jx promote -f redis
@@ -144,8 +152,10 @@ func NewCmdPromote(f Factory, in terminal.FileReader, out terminal.FileWriter, e
 	options.addCommonFlags(cmd)
 
 	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The Namespace to promote to")
-	cmd.Flags().StringVarP(&options.Environment, optionEnvironment, "e", "", "The Environment to promote to")
+	cmd.Flags().StringVarP(&options.Environment, optionEnvironment, "e", "", "The Environment to promote to. Use a comma separated list to promote to several environments in order")
 	cmd.Flags().BoolVarP(&options.AllAutomatic, "all-auto", "", false, "Promote to all automatic environments in order")
+	cmd.Flags().BoolVarP(&options.Rollback, optionRollback, "", false, "Rolls back the application in the environment to the version it had before the current one")
+	cmd.Flags().StringVarP(&options.ToVersion, optionToVersion, "", "", "The previous version of the application to roll back to. Defaults to the version before the current one")
 
 	options.addPromoteOptions(cmd)
 	return cmd
@@ -166,7 +176,7 @@ func (options *PromoteOptions) addPromoteOptions(cmd *cobra.Command) {
 	cmd.Flags().BoolVarP(&options.NoHelmUpdate, "no-helm-update", "", false, "Allows the 'helm repo update' command if you are sure your local helm cache is up to date with the version you wish to promote")
 	cmd.Flags().BoolVarP(&options.NoMergePullRequest, "no-merge", "", false, "Disables automatic merge of promote Pull Requests")
 	cmd.Flags().BoolVarP(&options.NoPoll, "no-poll", "", false, "Disables polling for Pull Request or Pipeline status")
-	cmd.Flags().BoolVarP(&options.NoWaitAfterMerge, "no-wait", "", false, "Disables waiting for completing promotion after the Pull request is merged")
+	cmd.Flags().BoolVarP(&options.NoWaitAfterMerge, "no-wait", "", false, "Disables waiting for completing promotion after the Pull request is merged. When promoting to several environments the Pull Requests of all of them are created without waiting for the previous environment")
 	cmd.Flags().BoolVarP(&options.IgnoreLocalFiles, "ignore-local-file", "", false, "Ignores the local file system when deducing the Git repository")
 }
 
@@ -197,7 +207,7 @@ func (o *PromoteOptions) Run() error {
 		return err
 	}
 
-	if o.Environment == "" && !o.BatchMode {
+	if o.Environment == "" && !o.AllAutomatic && !o.BatchMode {
 		names := []string{}
 		m, allEnvNames, err := kube.GetOrderedEnvironments(jxClient, ns)
 		if err != nil {
@@ -230,10 +240,6 @@ func (o *PromoteOptions) Run() error {
 		o.TimeoutDuration = &duration
 	}
 
-	targetNS, env, err := o.GetTargetNamespace(o.Namespace, o.Environment)
-	if err != nil {
-		return err
-	}
 	apisClient, err := o.Factory.CreateApiExtensionsClient()
 	if err != nil {
 		return err
@@ -257,14 +263,31 @@ func (o *PromoteOptions) Run() error {
 
 	o.Activities = jxClient.JenkinsV1().PipelineActivities(ns)
 
+	envNames := parseEnvironmentNames(o.Environment)
+	if o.Rollback && (o.AllAutomatic || len(envNames) > 1) {
+		return fmt.Errorf("the --%s option can only be used with a single --%s", optionRollback, optionEnvironment)
+	}
+	if !o.Rollback && o.ToVersion != "" {
+		return fmt.Errorf("the --%s option can only be used with --%s", optionToVersion, optionRollback)
+	}
+	if o.AllAutomatic {
+		return o.PromoteAllAutomatic()
+	}
+	if len(envNames) > 1 {
+		return o.PromoteEnvironments(envNames)
+	}
+
+	targetNS, env, err := o.GetTargetNamespace(o.Namespace, o.Environment)
+	if err != nil {
+		return err
+	}
 	releaseName := o.ReleaseName
 	if releaseName == "" {
 		releaseName = targetNS + "-" + app
 		o.ReleaseName = releaseName
 	}
-
-	if o.AllAutomatic {
-		return o.PromoteAllAutomatic()
+	if o.Rollback {
+		return o.RollbackEnvironment(env)
 	}
 	if env == nil {
 		if o.Environment == "" {
@@ -317,25 +340,18 @@ func (o *PromoteOptions) PromoteAllAutomatic() error {
 	}
 	kube.SortEnvironments(environments)
 
-	for _, env := range environments {
+	autoEnvs := []*v1.Environment{}
+	for i := range environments {
+		env := &environments[i]
 		kind := env.Spec.Kind
 		if env.Spec.PromotionStrategy == v1.PromotionStrategyTypeAutomatic && kind.IsPermanent() {
-			ns := env.Spec.Namespace
-			if ns == "" {
+			if env.Spec.Namespace == "" {
 				return fmt.Errorf("No namespace for environment %s", env.Name)
 			}
-			releaseInfo, err := o.Promote(ns, &env, false)
-			if err != nil {
-				return err
-			}
-			o.ReleaseInfo = releaseInfo
-			err = o.WaitForPromotion(ns, &env, releaseInfo)
-			if err != nil {
-				return err
-			}
+			autoEnvs = append(autoEnvs, env)
 		}
 	}
-	return nil
+	return o.promoteEnvironments(autoEnvs)
 }
 
 func (o *PromoteOptions) Promote(targetNS string, env *v1.Environment, warnIfAuto bool) (*ReleaseInfo, error) {
@@ -388,6 +404,12 @@ func (o *PromoteOptions) Promote(targetNS string, env *v1.Environment, warnIfAut
 	if env != nil {
 		source := &env.Spec.Source
 		if source.URL != "" && env.Spec.Kind.IsPermanent() {
+			if kube.IsCanaryEnvironment(env) {
+				err := o.ensureFlagger(env)
+				if err != nil {
+					return releaseInfo, err
+				}
+			}
 			err := o.PromoteViaPullRequest(env, releaseInfo)
 			if err == nil {
 				startPromotePR := func(a *v1.PipelineActivity, s *v1.PipelineActivityStep, ps *v1.PromoteActivityStep, p *v1.PromotePullRequestStep) error {
@@ -411,7 +433,11 @@ func (o *PromoteOptions) Promote(targetNS string, env *v1.Environment, warnIfAut
 			return releaseInfo, err
 		}
 	}
-	err := o.verifyHelmConfigured()
+	err := validatePromotionCluster(env)
+	if err != nil {
+		return releaseInfo, err
+	}
+	err = o.verifyHelmConfigured()
 	if err != nil {
 		return releaseInfo, err
 	}
@@ -447,6 +473,16 @@ func (o *PromoteOptions) Promote(targetNS string, env *v1.Environment, warnIfAut
 	return releaseInfo, err
 }
 
+// validatePromotionCluster returns an error if the environment is in a remote cluster but does not use GitOps as helm
+// can only upgrade the charts of the cluster of the team
+func validatePromotionCluster(env *v1.Environment) error {
+	if kube.IsRemoteEnvironment(env) && env.Spec.Source.URL == "" {
+		return fmt.Errorf("cannot promote to Environment %s in the remote cluster %s as it has no source repository, environments in remote clusters must use GitOps",
+			env.Name, env.Spec.RemoteCluster.URL)
+	}
+	return nil
+}
+
 func (o *PromoteOptions) PromoteViaPullRequest(env *v1.Environment, releaseInfo *ReleaseInfo) error {
 	version := o.Version
 	versionName := version
@@ -476,7 +512,7 @@ func (o *PromoteOptions) PromoteViaPullRequest(env *v1.Environment, releaseInfo
 		releaseInfo.PullRequestInfo = info
 		return err
 	} else {
-		info, err := o.createEnvironmentPullRequest(env, modifyRequirementsFn, branchNameText, title, message, releaseInfo.PullRequestInfo, o.ConfigureGitCallback)
+		info, err := o.createEnvironmentPullRequest(env, modifyRequirementsFn, o.canaryChartFn(env, app), branchNameText, title, message, releaseInfo.PullRequestInfo, o.ConfigureGitCallback)
 		releaseInfo.PullRequestInfo = info
 		return err
 	}
@@ -516,13 +552,31 @@ func (o *PromoteOptions) GetTargetNamespace(ns string, env string) (string, *v1.
 		if targetNS == "" {
 			return "", nil, fmt.Errorf("Environment %s does not have a namspace associated with it!", env)
 		}
+		err = validatePromotionCluster(envResource)
+		if err != nil {
+			return "", nil, err
+		}
 	} else if ns != "" {
 		targetNS = ns
 	}
 
+	// the namespace of an environment in a remote cluster is created in that cluster
+	envKubeClient := kubeClient
+	if envResource != nil {
+		envKubeClient, err = o.EnvironmentKubeClient(envResource)
+		if err != nil {
+			return "", nil, err
+		}
+	}
 	labels := map[string]string{}
 	annotations := map[string]string{}
-	err = kube.EnsureNamespaceCreated(kubeClient, targetNS, labels, annotations)
+	if envResource != nil {
+		err = kube.AddIstioInjectionLabel(envKubeClient, jxClient, envResource, team, labels)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	err = kube.EnsureNamespaceCreated(envKubeClient, targetNS, labels, annotations)
 	if err != nil {
 		return "", nil, err
 	}
@@ -563,10 +617,15 @@ func (o *PromoteOptions) waitForGitOpsPullRequest(ns string, env *v1.Environment
 	logHasMergeSha := false
 	logMergeStatusError := false
 	logNoMergeStatuses := false
+	logWaitingForApproval := false
 	urlStatusMap := map[string]string{}
 	urlStatusTargetURLMap := map[string]string{}
 
 	if pullRequestInfo != nil {
+		approvers, approvalComment, err := o.promotionApprovers(env.Name)
+		if err != nil {
+			return err
+		}
 		for {
 			pr := pullRequestInfo.PullRequest
 			gitProvider := pullRequestInfo.GitProvider
@@ -659,6 +718,10 @@ func (o *PromoteOptions) waitForGitOpsPullRequest(ns string, env *v1.Environment
 								}
 								if succeeded {
 									log.Infoln("Merge status checks all passed so the promotion worked!")
+									err = o.completeCanaryPromotion(env, pr, end, promoteKey)
+									if err != nil {
+										return err
+									}
 									err = o.commentOnIssues(ns, env, promoteKey)
 									if err == nil {
 										err = promoteKey.OnPromoteUpdate(o.Activities, kube.CompletePromotionUpdate)
@@ -683,8 +746,23 @@ func (o *PromoteOptions) waitForGitOpsPullRequest(ns string, env *v1.Environment
 						log.Infoln("The build for the Pull Request last commit is currently in progress.")
 					} else {
 						if status == "success" {
-							if !o.NoMergePullRequest {
-								err = gitProvider.MergePullRequest(pr, "jx promote automatically merged promotion PR")
+							mergeMessage := "jx promote automatically merged promotion PR"
+							approved := true
+							if len(approvers) > 0 {
+								approval := o.findPullRequestPromotionApproval(gitProvider, pr, approvalComment, approvers)
+								approved = approval != nil
+								if approved {
+									mergeMessage = fmt.Sprintf("jx promote merged promotion PR approved by %s", approval.User.Login)
+									promoteKey.OnPromotePullRequest(o.Activities, approvePromotionPullRequest(approval.User.Login, approvalTime(approval)))
+								} else if !logWaitingForApproval {
+									logWaitingForApproval = true
+									log.Infof("Pull Request %s is waiting for one of %s to comment %s or run %s\n", util.ColorInfo(pr.URL),
+										util.ColorInfo(strings.Join(approvers, ", ")), util.ColorInfo(approvalComment),
+										util.ColorInfo(fmt.Sprintf("jx approve promotion --env %s --app %s", env.Name, o.Application)))
+								}
+							}
+							if !o.NoMergePullRequest && approved {
+								err = gitProvider.MergePullRequest(pr, mergeMessage)
 								if err != nil {
 									if !logMergeFailure {
 										logMergeFailure = true
@@ -1025,7 +1103,7 @@ func (o *PromoteOptions) commentOnIssues(targetNS string, environment *v1.Enviro
 	if err != nil {
 		return err
 	}
-	kubeClient, _, err := o.KubeClient()
+	kubeClient, err := o.EnvironmentKubeClient(environment)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// promotionApprovers returns the users who must approve promotions to the environment, which is empty if promotions
+// are merged automatically, along with the comment which approves a promotion Pull Request
+func (o *CommonOptions) promotionApprovers(envName string) ([]string, string, error) {
+	teamSettings, err := o.TeamSettings()
+	if err != nil {
+		return nil, "", err
+	}
+	return teamSettings.EnvironmentPromotionApprovers(envName), teamSettings.GetPromotionApprovalComment(), nil
+}
+
+// findPullRequestPromotionApproval returns the approval comment on the promotion Pull Request from one of the approvers
+// or nil if it has not been approved yet
+func (o *PromoteOptions) findPullRequestPromotionApproval(gitProvider gits.GitProvider, pr *gits.GitPullRequest, approvalComment string, approvers []string) *gits.GitPRComment {
+	comments, err := gitProvider.ListPRComments(pr)
+	if err != nil {
+		log.Warnf("Failed to query the comments of Pull Request %s due to %s\n", pr.URL, err)
+		return nil
+	}
+	return findPromotionApproval(comments, approvalComment, approvers)
+}
+
+// findPromotionApproval returns the first comment from one of the approvers which contains the approval comment on a
+// line of its own or nil if there is none
+func findPromotionApproval(comments []*gits.GitPRComment, approvalComment string, approvers []string) *gits.GitPRComment {
+	for _, comment := range comments {
+		if comment == nil || util.StringArrayIndex(approvers, comment.User.Login) < 0 {
+			continue
+		}
+		for _, line := range strings.Split(comment.Body, "\n") {
+			if strings.TrimSpace(line) == approvalComment {
+				return comment
+			}
+		}
+	}
+	return nil
+}
+
+// approvalTime returns the time the promotion was approved by the comment
+func approvalTime(comment *gits.GitPRComment) time.Time {
+	if comment.CreatedAt != nil {
+		return *comment.CreatedAt
+	}
+	return time.Now()
+}
+
+// approvePromotionPullRequest records who approved the promotion Pull Request and when
+func approvePromotionPullRequest(approver string, approved time.Time) kube.PromotePullRequestFn {
+	return func(a *v1.PipelineActivity, s *v1.PipelineActivityStep, ps *v1.PromoteActivityStep, p *v1.PromotePullRequestStep) error {
+		p.ApprovedBy = approver
+		p.ApprovedTimestamp = &metav1.Time{Time: approved}
+		return nil
+	}
+}
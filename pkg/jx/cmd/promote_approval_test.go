@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFindPromotionApproval(t *testing.T) {
+	t.Parallel()
+	approvers := []string{"alice", "bob"}
+	comment := v1.DefaultPromotionApprovalComment
+	comments := []*gits.GitPRComment{
+		{User: gits.GitUser{Login: "mallory"}, Body: comment},
+		{User: gits.GitUser{Login: "alice"}, Body: "looks good but not yet"},
+		{User: gits.GitUser{Login: "bob"}, Body: "thanks!\n  " + comment + "  \n"},
+	}
+
+	approval := findPromotionApproval(comments, comment, approvers)
+	require.NotNil(t, approval)
+	assert.Equal(t, "bob", approval.User.Login)
+
+	assert.Nil(t, findPromotionApproval(comments[:2], comment, approvers))
+	assert.Nil(t, findPromotionApproval(comments, "/lgtm", approvers))
+}
+
+func TestFindPullRequestPromotionApproval(t *testing.T) {
+	t.Parallel()
+	gitProvider := gits.NewFakeProvider(gits.NewFakeRepository("acme", "environment-production"))
+	pr, err := gitProvider.CreatePullRequest(&gits.GitPullRequestArguments{
+		GitRepositoryInfo: &gits.GitRepositoryInfo{Organisation: "acme", Name: "environment-production"},
+		Title:             "chore: promote myapp to version 1.0.1",
+	})
+	require.NoError(t, err)
+
+	o := &PromoteOptions{}
+	approvers := []string{"alice"}
+	comment := v1.DefaultPromotionApprovalComment
+	gitProvider.User.Username = "mallory"
+	require.NoError(t, gitProvider.AddPRComment(pr, comment))
+	assert.Nil(t, o.findPullRequestPromotionApproval(gitProvider, pr, comment, approvers))
+
+	gitProvider.User.Username = "alice"
+	require.NoError(t, gitProvider.AddPRComment(pr, comment))
+	approval := o.findPullRequestPromotionApproval(gitProvider, pr, comment, approvers)
+	require.NotNil(t, approval)
+	assert.Equal(t, "alice", approval.User.Login)
+	assert.NotNil(t, approval.CreatedAt)
+}
+
+func TestTeamSettingsPromotionApprovers(t *testing.T) {
+	t.Parallel()
+	settings := &v1.TeamSettings{}
+	assert.Nil(t, settings.EnvironmentPromotionApprovers("production"))
+	assert.Equal(t, v1.DefaultPromotionApprovalComment, settings.GetPromotionApprovalComment())
+
+	settings.SetEnvironmentPromotionApprovers("production", []string{"alice"})
+	settings.SetEnvironmentPromotionApprovers("staging", []string{"bob"})
+	settings.SetEnvironmentPromotionApprovers("production", []string{"alice", "carol"})
+	assert.Equal(t, []string{"alice", "carol"}, settings.EnvironmentPromotionApprovers("production"))
+	assert.Equal(t, []string{"bob"}, settings.EnvironmentPromotionApprovers("staging"))
+
+	settings.SetEnvironmentPromotionApprovers("staging", nil)
+	assert.Nil(t, settings.EnvironmentPromotionApprovers("staging"))
+	assert.Len(t, settings.PromotionApprovers, 1)
+}
+
+func TestFindPendingPromotion(t *testing.T) {
+	t.Parallel()
+	now := metav1.NewTime(time.Now())
+	activity := func(name string, created time.Time, env string, completed *metav1.Time) v1.PipelineActivity {
+		return v1.PipelineActivity{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(created)},
+			Spec: v1.PipelineActivitySpec{
+				Pipeline: "acme/myapp/master",
+				Steps: []v1.PipelineActivityStep{
+					{
+						Promote: &v1.PromoteActivityStep{
+							Environment: env,
+							PullRequest: &v1.PromotePullRequestStep{
+								CoreActivityStep: v1.CoreActivityStep{CompletedTimestamp: completed},
+								PullRequestURL:   "https://github.com/acme/environment-production/pull/" + name,
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	start := time.Date(2018, 11, 1, 9, 0, 0, 0, time.UTC)
+	activities := []v1.PipelineActivity{
+		activity("1", start, "production", &now),
+		activity("2", start.Add(time.Hour), "production", nil),
+		activity("3", start.Add(2*time.Hour), "staging", nil),
+	}
+
+	a, step := findPendingPromotion(activities, "myapp", "production")
+	require.NotNil(t, a)
+	assert.Equal(t, "2", a.Name)
+	assert.Equal(t, "production", step.Environment)
+
+	a, _ = findPendingPromotion(activities, "other", "production")
+	assert.Nil(t, a)
+
+	number, err := pullRequestNumberFromURL(step.PullRequest.PullRequestURL)
+	require.NoError(t, err)
+	assert.Equal(t, 2, number)
+	_, err = pullRequestNumberFromURL("https://github.com/acme/environment-production")
+	assert.Error(t, err)
+}
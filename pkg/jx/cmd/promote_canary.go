@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	flaggerRepoName    = "flagger"
+	flaggerRepoURL     = "https://flagger.app"
+	flaggerReleaseName = "flagger"
+	flaggerNamespace   = "istio-system"
+)
+
+// canaryChartFn returns the function which adds the canary of the application to the templates of the chart of a
+// canary environment, or removes a canary left behind if the environment no longer uses canaries
+func (o *CommonOptions) canaryChartFn(env *v1.Environment, app string) ModifyChartFn {
+	return func(chartDir string) error {
+		if !kube.IsCanaryEnvironment(env) {
+			return removeCanaryTemplate(chartDir, app)
+		}
+		teamSettings, err := o.TeamSettings()
+		if err != nil {
+			return err
+		}
+		return writeCanaryTemplate(chartDir, app, teamSettings.GetCanarySettings())
+	}
+}
+
+// writeCanaryTemplate writes the canary of the application into the templates of the chart in the directory
+func writeCanaryTemplate(chartDir string, app string, settings v1.CanarySettings) error {
+	data, err := kube.CanaryYAML(app, settings)
+	if err != nil {
+		return errors.Wrapf(err, "failed to generate the canary of %s", app)
+	}
+	dir := filepath.Join(chartDir, "templates")
+	err = os.MkdirAll(dir, util.DefaultWritePermissions)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, kube.CanaryFileName(app)), data, util.DefaultWritePermissions)
+}
+
+// removeCanaryTemplate removes the canary of the application from the templates of the chart in the directory if it
+// exists
+func removeCanaryTemplate(chartDir string, app string) error {
+	fileName := filepath.Join(chartDir, "templates", kube.CanaryFileName(app))
+	exists, err := util.FileExists(fileName)
+	if err != nil || !exists {
+		return err
+	}
+	return os.Remove(fileName)
+}
+
+// ensureFlagger installs the Flagger addon which rolls out the canaries of the environment if it is not installed yet
+func (o *PromoteOptions) ensureFlagger(env *v1.Environment) error {
+	if kube.IsRemoteEnvironment(env) {
+		log.Warnf("Cannot check that Flagger is installed in the remote cluster of the environment %s\n", env.Name)
+		return nil
+	}
+	apisClient, err := o.CreateApiExtensionsClient()
+	if err != nil {
+		return err
+	}
+	_, err = apisClient.ApiextensionsV1beta1().CustomResourceDefinitions().Get(kube.CanaryCRDName, metav1.GetOptions{})
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to check if the CustomResourceDefinition %s exists", kube.CanaryCRDName)
+	}
+	_, err = apisClient.ApiextensionsV1beta1().CustomResourceDefinitions().Get(kube.IstioVirtualServiceCRDName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("Istio is not installed in the cluster so Flagger cannot roll out the canaries of the environment %s, install it with %s", env.Name, util.ColorInfo("jx create addon istio"))
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to check if the CustomResourceDefinition %s exists", kube.IstioVirtualServiceCRDName)
+	}
+
+	log.Infof("Installing Flagger to roll out the canaries of the environment %s\n", util.ColorInfo(env.Name))
+	err = o.addHelmRepoIfMissing(flaggerRepoURL, flaggerRepoName)
+	if err != nil {
+		return err
+	}
+	err = o.ensureHelm()
+	if err != nil {
+		return errors.Wrap(err, "failed to ensure that helm is present")
+	}
+	return o.installChart(flaggerReleaseName, kube.ChartFlagger, "", flaggerNamespace, true, nil)
+}
+
+// waitForCanary waits for the analysis of the canary of the application promoted by the merged Pull Request to finish,
+// returning an error if it failed or did not finish in time
+func (o *PromoteOptions) waitForCanary(env *v1.Environment, pr *gits.GitPullRequest, end time.Time) error {
+	kubeClient, err := o.EnvironmentKubeClient(env)
+	if err != nil {
+		return err
+	}
+	since := time.Now()
+	if pr.MergedAt != nil {
+		since = *pr.MergedAt
+	}
+	app := o.Application
+	ns := env.Spec.Namespace
+	log.Infof("Waiting for the canary analysis of %s in the environment %s\n", util.ColorInfo(app), util.ColorInfo(env.Name))
+	progress := ""
+	for {
+		canary, err := kube.GetCanary(kubeClient, ns, app)
+		if err != nil {
+			log.Warnf("Failed to query the canary %s in namespace %s: %s\n", app, ns, err)
+		} else {
+			done, err := kube.CanaryResult(canary, since)
+			if done {
+				if err == nil {
+					log.Infof("The canary analysis of %s passed\n", util.ColorInfo(app))
+				}
+				return err
+			}
+			p := kube.CanaryProgress(canary)
+			if p != "" && p != progress {
+				progress = p
+				log.Infof("Canary %s: %s\n", util.ColorInfo(app), util.ColorInfo(progress))
+			}
+		}
+		if time.Now().After(end) {
+			return fmt.Errorf("Timed out waiting for the canary analysis of %s in the environment %s", app, env.Name)
+		}
+		time.Sleep(*o.PullRequestPollDuration)
+	}
+}
+
+// completeCanaryPromotion waits for the canary analysis of a canary environment, rolling the environment back and
+// failing the promotion if it does not pass
+func (o *PromoteOptions) completeCanaryPromotion(env *v1.Environment, pr *gits.GitPullRequest, end time.Time, promoteKey *kube.PromoteStepActivityKey) error {
+	if !kube.IsCanaryEnvironment(env) {
+		return nil
+	}
+	err := o.waitForCanary(env, pr, end)
+	if err == nil {
+		return nil
+	}
+	log.Warnf("%s\n", err)
+	rollbackErr := o.RollbackEnvironment(env)
+	if rollbackErr != nil {
+		log.Warnf("Failed to roll back %s in the environment %s: %s\n", o.Application, env.Name, rollbackErr)
+	}
+	promoteKey.OnPromoteUpdate(o.Activities, kube.FailedPromotionUpdate)
+	return err
+}
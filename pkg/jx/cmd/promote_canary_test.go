@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCanaryTemplate(t *testing.T) {
+	t.Parallel()
+	chartDir, err := ioutil.TempDir("", "test-canary-template")
+	require.NoError(t, err)
+	defer os.RemoveAll(chartDir)
+
+	fileName := filepath.Join(chartDir, "templates", "myapp-canary.yaml")
+	settings := v1.TeamSettings{}
+	err = writeCanaryTemplate(chartDir, "myapp", settings.GetCanarySettings())
+	require.NoError(t, err)
+	assert.FileExists(t, fileName)
+
+	err = removeCanaryTemplate(chartDir, "myapp")
+	require.NoError(t, err)
+	exists, err := util.FileExists(fileName)
+	require.NoError(t, err)
+	assert.False(t, exists, "the canary template should be removed")
+
+	err = removeCanaryTemplate(chartDir, "myapp")
+	assert.NoError(t, err, "removing a missing canary template")
+}
+
+func TestMergeCanarySettings(t *testing.T) {
+	t.Parallel()
+	settings := v1.CanarySettings{
+		Interval:  "2m",
+		MaxWeight: 60,
+	}
+	mergeCanarySettings(&settings, &v1.CanarySettings{
+		StepWeight: 20,
+		MaxWeight:  80,
+	})
+	assert.Equal(t, v1.CanarySettings{
+		Interval:   "2m",
+		MaxWeight:  80,
+		StepWeight: 20,
+	}, settings)
+}
+
+func TestFlaggerIsNotInstalledWithoutIstio(t *testing.T) {
+	t.Parallel()
+	o := &PromoteOptions{
+		CommonOptions: CommonOptions{apiExtensionsClient: apiextensionsfake.NewSimpleClientset()},
+	}
+
+	err := o.ensureFlagger(kube.NewPermanentEnvironment("production"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Istio is not installed")
+}
+
+func TestInstalledFlaggerIsUsed(t *testing.T) {
+	t.Parallel()
+	o := &PromoteOptions{
+		CommonOptions: CommonOptions{apiExtensionsClient: apiextensionsfake.NewSimpleClientset(
+			&apiextensionsv1beta1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: kube.CanaryCRDName}},
+		)},
+	}
+
+	err := o.ensureFlagger(kube.NewPermanentEnvironment("production"))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+const (
+	promoteEnvironmentSucceeded  = "succeeded"
+	promoteEnvironmentFailed     = "failed"
+	promoteEnvironmentPending    = "pending"
+	promoteEnvironmentNotStarted = "not started"
+)
+
+// promoteEnvironmentResult the outcome of promoting to one of several environments
+type promoteEnvironmentResult struct {
+	Environment string
+	Status      string
+	Reason      string
+	PullRequest string
+}
+
+// parseEnvironmentNames splits the comma separated list of environment names
+func parseEnvironmentNames(text string) []string {
+	answer := []string{}
+	for _, name := range strings.Split(text, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			answer = append(answer, name)
+		}
+	}
+	return answer
+}
+
+// PromoteEnvironments promotes to the named environments in order, validating all of them before promoting to any
+func (o *PromoteOptions) PromoteEnvironments(names []string) error {
+	envs := []*v1.Environment{}
+	for i, name := range names {
+		if util.StringArrayIndex(names[0:i], name) >= 0 {
+			return fmt.Errorf("the environment %s is specified more than once in --%s", name, optionEnvironment)
+		}
+		_, env, err := o.GetTargetNamespace("", name)
+		if err != nil {
+			return err
+		}
+		if !env.Spec.Kind.IsPermanent() {
+			return fmt.Errorf("cannot promote to the environment %s as it is not a permanent environment", name)
+		}
+		envs = append(envs, env)
+	}
+	return o.promoteEnvironments(envs)
+}
+
+// promoteEnvironments promotes to the environments in order waiting for the promotion to each environment to succeed
+// before promoting to the next one, stopping at the first failure. If the --no-wait option is used the promotions to
+// all of the environments are started before waiting for any of them
+func (o *PromoteOptions) promoteEnvironments(envs []*v1.Environment) error {
+	if len(envs) == 0 {
+		log.Warnf("No environments to promote to\n")
+		return nil
+	}
+	names := []string{}
+	for _, env := range envs {
+		names = append(names, env.Name)
+	}
+	log.Infof("Promoting to the environments %s\n", util.ColorInfo(strings.Join(names, ", ")))
+
+	fanOut := o.NoWaitAfterMerge
+	results := make([]promoteEnvironmentResult, len(envs))
+	releaseInfos := make([]*ReleaseInfo, len(envs))
+	releaseName := o.ReleaseName
+	stopped := false
+	for i, env := range envs {
+		results[i] = promoteEnvironmentResult{
+			Environment: env.Name,
+			Status:      promoteEnvironmentNotStarted,
+		}
+		if stopped {
+			results[i].Reason = "a previous environment failed"
+			continue
+		}
+		o.ReleaseName = releaseName
+		releaseInfo, err := o.Promote(env.Spec.Namespace, env, false)
+		releaseInfos[i] = releaseInfo
+		results[i].PullRequest = releaseInfoPullRequestURL(releaseInfo)
+		if err == nil && !fanOut {
+			err = o.waitForEnvironmentPromotion(env, releaseInfo)
+		}
+		if err != nil {
+			results[i].Status = promoteEnvironmentFailed
+			results[i].Reason = err.Error()
+			stopped = !fanOut
+			continue
+		}
+		o.ReleaseInfo = releaseInfo
+		results[i].Status = promoteEnvironmentSucceeded
+		if fanOut && !o.NoPoll && releaseInfo != nil && releaseInfo.PullRequestInfo != nil {
+			results[i].Status = promoteEnvironmentPending
+		}
+	}
+
+	if fanOut {
+		for i, env := range envs {
+			if results[i].Status != promoteEnvironmentPending {
+				continue
+			}
+			err := o.waitForEnvironmentPromotion(env, releaseInfos[i])
+			if err != nil {
+				results[i].Status = promoteEnvironmentFailed
+				results[i].Reason = err.Error()
+			} else {
+				results[i].Status = promoteEnvironmentSucceeded
+			}
+		}
+	}
+	return o.printPromoteEnvironmentResults(results)
+}
+
+// waitForEnvironmentPromotion waits for the promotion to the environment to succeed unless polling is disabled
+func (o *PromoteOptions) waitForEnvironmentPromotion(env *v1.Environment, releaseInfo *ReleaseInfo) error {
+	if o.NoPoll || releaseInfo == nil {
+		return nil
+	}
+	return o.WaitForPromotion(env.Spec.Namespace, env, releaseInfo)
+}
+
+// releaseInfoPullRequestURL returns the URL of the Pull Request of the promotion if there is one
+func releaseInfoPullRequestURL(releaseInfo *ReleaseInfo) string {
+	if releaseInfo == nil || releaseInfo.PullRequestInfo == nil || releaseInfo.PullRequestInfo.PullRequest == nil {
+		return ""
+	}
+	return releaseInfo.PullRequestInfo.PullRequest.URL
+}
+
+// printPromoteEnvironmentResults prints the outcome of promoting to each environment returning an error describing the
+// first environment which failed
+func (o *PromoteOptions) printPromoteEnvironmentResults(results []promoteEnvironmentResult) error {
+	table := o.CreateTable()
+	table.AddRow("ENVIRONMENT", "STATUS", "PULL REQUEST", "REASON")
+	var failed *promoteEnvironmentResult
+	for i, result := range results {
+		status := result.Status
+		switch status {
+		case promoteEnvironmentSucceeded:
+			status = util.ColorInfo(status)
+		case promoteEnvironmentFailed:
+			status = util.ColorError(status)
+			if failed == nil {
+				failed = &results[i]
+			}
+		default:
+			status = util.ColorWarning(status)
+		}
+		table.AddRow(result.Environment, status, result.PullRequest, result.Reason)
+	}
+	log.Blank()
+	table.Render()
+	log.Blank()
+	if failed != nil {
+		return fmt.Errorf("promotion stopped at environment %s: %s", failed.Environment, failed.Reason)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEnvironmentNames(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, []string{}, parseEnvironmentNames(""))
+	assert.Equal(t, []string{"staging"}, parseEnvironmentNames("staging"))
+	assert.Equal(t, []string{"staging", "production"}, parseEnvironmentNames("staging, production,"))
+}
+
+func TestPrintPromoteEnvironmentResults(t *testing.T) {
+	t.Parallel()
+	o := &PromoteOptions{
+		CommonOptions: CommonOptions{
+			Factory: NewFactory(),
+			Out:     os.Stdout,
+		},
+	}
+
+	err := o.printPromoteEnvironmentResults([]promoteEnvironmentResult{
+		{Environment: "staging", Status: promoteEnvironmentSucceeded, PullRequest: "https://github.com/acme/env-staging/pull/1"},
+		{Environment: "production", Status: promoteEnvironmentSucceeded},
+	})
+	require.NoError(t, err)
+
+	err = o.printPromoteEnvironmentResults([]promoteEnvironmentResult{
+		{Environment: "staging", Status: promoteEnvironmentSucceeded},
+		{Environment: "uat", Status: promoteEnvironmentFailed, Reason: "Pull request is closed"},
+		{Environment: "production", Status: promoteEnvironmentNotStarted, Reason: "a previous environment failed"},
+	})
+	require.Error(t, err)
+	assert.Equal(t, "promotion stopped at environment uat: Pull request is closed", err.Error())
+}
+
+func TestValidatePromotionCluster(t *testing.T) {
+	t.Parallel()
+	env := kube.NewPermanentEnvironment("production")
+	assert.NoError(t, validatePromotionCluster(env))
+	assert.NoError(t, validatePromotionCluster(nil))
+
+	env.Spec.RemoteCluster = &v1.EnvironmentCluster{URL: "https://prod.example.com"}
+	assert.Error(t, validatePromotionCluster(env), "a remote environment without GitOps should not be promoted with helm")
+
+	env.Spec.Source.URL = "https://github.com/myorg/environment-production.git"
+	assert.NoError(t, validatePromotionCluster(env))
+}
@@ -0,0 +1,337 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	optionRollback  = "rollback"
+	optionToVersion = "to-version"
+)
+
+// appVersionRevision the version of an application in the requirements of an environment at a revision
+type appVersionRevision struct {
+	SHA     string
+	Version string
+}
+
+// RollbackEnvironment reverts the application in the environment to the version it had before the current one, or to
+// the --to-version, by changing the requirements of the environment. Environments which promote automatically are
+// changed with a direct commit and the others with a Pull Request
+func (o *PromoteOptions) RollbackEnvironment(env *v1.Environment) error {
+	if env == nil {
+		return util.MissingOption(optionEnvironment)
+	}
+	if env.Spec.Source.URL == "" {
+		return fmt.Errorf("cannot roll back the environment %s as it has no Git repository", env.Name)
+	}
+	app := o.Application
+	dir, gitInfo, _, err := o.cloneEnvironmentRepository(env, o.ConfigureGitCallback)
+	if err != nil {
+		return err
+	}
+	requirementsFile, err := helm.FindRequirementsFileName(dir)
+	if err != nil {
+		return err
+	}
+	fileName, err := filepath.Rel(dir, requirementsFile)
+	if err != nil {
+		return err
+	}
+	history, err := o.loadAppVersionHistory(dir, fileName, app)
+	if err != nil {
+		return err
+	}
+	from, to, err := findRollbackVersion(history, app, env.Name, o.ToVersion)
+	if err != nil {
+		return err
+	}
+	log.Infof("Rolling back %s in environment %s from version %s to %s\n", util.ColorInfo(app), util.ColorInfo(env.Name), util.ColorInfo(from), util.ColorInfo(to))
+
+	promoteKey := o.createPromoteKey(env)
+	startRollback := func(a *v1.PipelineActivity, s *v1.PipelineActivityStep, r *v1.RollbackActivityStep) error {
+		kube.StartRollback(a, s, r)
+		r.FromVersion = from
+		r.ToVersion = to
+		return nil
+	}
+	err = promoteKey.OnRollback(o.Activities, startRollback)
+	if err != nil {
+		log.Warnf("Failed to update PipelineActivity: %s\n", err)
+	}
+
+	err = o.rollbackRequirements(env, dir, gitInfo, requirementsFile, fileName, from, to, promoteKey)
+	if err != nil {
+		promoteKey.OnRollback(o.Activities, kube.FailedRollback)
+		return err
+	}
+	err = promoteKey.OnRollback(o.Activities, kube.CompleteRollback)
+	if err != nil {
+		log.Warnf("Failed to update PipelineActivity: %s\n", err)
+	}
+	return nil
+}
+
+// rollbackRequirements changes the version of the application in the requirements of the environment and waits for
+// the pipeline of the environment to succeed
+func (o *PromoteOptions) rollbackRequirements(env *v1.Environment, dir string, gitInfo *gits.GitRepositoryInfo, requirementsFile string, fileName string, from string, to string, promoteKey *kube.PromoteStepActivityKey) error {
+	app := o.Application
+	message := fmt.Sprintf("Roll back %s from version %s to %s", app, from, to)
+	modifyRequirementsFn := func(requirements *helm.Requirements) error {
+		for _, dep := range requirements.Dependencies {
+			if dep != nil && dep.Name == app {
+				dep.Version = to
+				return nil
+			}
+		}
+		return fmt.Errorf("the application %s is not in the requirements of the environment %s", app, env.Name)
+	}
+
+	if env.Spec.PromotionStrategy == v1.PromotionStrategyTypeAutomatic {
+		requirements, err := helm.LoadRequirementsFile(requirementsFile)
+		if err != nil {
+			return err
+		}
+		err = modifyRequirementsFn(requirements)
+		if err != nil {
+			return err
+		}
+		err = helm.SaveRequirementsFile(requirementsFile, requirements)
+		if err != nil {
+			return err
+		}
+		err = o.Git().Add(dir, fileName)
+		if err != nil {
+			return err
+		}
+		err = o.Git().CommitDir(dir, message)
+		if err != nil {
+			return err
+		}
+		err = o.Git().Push(dir)
+		if err != nil {
+			return err
+		}
+		revisions, err := o.Git().FileRevisions(dir, fileName)
+		if err != nil {
+			return err
+		}
+		if len(revisions) == 0 {
+			return fmt.Errorf("no commit found for %s in the environment %s", fileName, env.Name)
+		}
+		sha := revisions[0]
+		log.Infof("Pushed commit %s to the environment %s\n", util.ColorInfo(sha), util.ColorInfo(env.Name))
+		o.updateRollback(promoteKey, func(r *v1.RollbackActivityStep) {
+			r.CommitSHA = sha
+		})
+		if !o.shouldWaitForRollback() {
+			return nil
+		}
+		provider, err := o.environmentGitProvider(gitInfo, "user name to query the commit statuses")
+		if err != nil {
+			return err
+		}
+		return o.waitForRollbackCommitStatuses(provider, gitInfo.Organisation, gitInfo.Name, sha)
+	}
+
+	branchNameText := "rollback-" + app + "-" + to
+	title := app + " rollback to " + to
+	var info *ReleasePullRequestInfo
+	var err error
+	if o.FakePullRequests != nil {
+		info, err = o.FakePullRequests(env, modifyRequirementsFn, branchNameText, title, message, nil)
+	} else {
+		info, err = o.createEnvironmentPullRequest(env, modifyRequirementsFn, nil, branchNameText, title, message, nil, o.ConfigureGitCallback)
+	}
+	if err != nil {
+		return err
+	}
+	if info == nil || info.PullRequest == nil {
+		return fmt.Errorf("no Pull Request was created to roll back %s in the environment %s", app, env.Name)
+	}
+	pr := info.PullRequest
+	o.updateRollback(promoteKey, func(r *v1.RollbackActivityStep) {
+		r.PullRequestURL = pr.URL
+	})
+	if !o.shouldWaitForRollback() {
+		return nil
+	}
+	sha, err := o.waitForRollbackPullRequest(info.GitProvider, pr)
+	if err != nil {
+		return err
+	}
+	o.updateRollback(promoteKey, func(r *v1.RollbackActivityStep) {
+		r.CommitSHA = sha
+	})
+	return o.waitForRollbackCommitStatuses(info.GitProvider, pr.Owner, pr.Repo, sha)
+}
+
+// loadAppVersionHistory returns the version of the application in the requirements file at each revision which
+// changed it, newest first. The version is empty for revisions where the application is not in the requirements
+func (o *PromoteOptions) loadAppVersionHistory(dir string, fileName string, app string) ([]appVersionRevision, error) {
+	revisions, err := o.Git().FileRevisions(dir, fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load the history of %s", fileName)
+	}
+	answer := []appVersionRevision{}
+	for _, sha := range revisions {
+		text, err := o.Git().ShowFileAtRevision(dir, sha, fileName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load %s at revision %s", fileName, sha)
+		}
+		requirements, err := helm.LoadRequirements([]byte(text))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s at revision %s", fileName, sha)
+		}
+		version := ""
+		for _, dep := range requirements.Dependencies {
+			if dep != nil && dep.Name == app {
+				version = dep.Version
+				break
+			}
+		}
+		answer = append(answer, appVersionRevision{
+			SHA:     sha,
+			Version: version,
+		})
+	}
+	return answer, nil
+}
+
+// findRollbackVersion returns the current version of the application and the version to roll back to from the
+// history of the environment, newest first. The version to roll back to is the toVersion if specified or the
+// version before the current one. It never looks past the revision where the application first appeared
+func findRollbackVersion(history []appVersionRevision, app string, envName string, toVersion string) (string, string, error) {
+	if len(history) == 0 || history[0].Version == "" {
+		return "", "", fmt.Errorf("the application %s is not in the environment %s", app, envName)
+	}
+	from := history[0].Version
+	if toVersion == from {
+		return "", "", fmt.Errorf("the application %s is already at version %s in the environment %s", app, from, envName)
+	}
+	for _, revision := range history[1:] {
+		version := revision.Version
+		if version == "" {
+			break
+		}
+		if version == from {
+			continue
+		}
+		if toVersion == "" || version == toVersion {
+			return from, version, nil
+		}
+	}
+	if toVersion != "" {
+		return "", "", fmt.Errorf("the application %s has not been at version %s in the environment %s since it first appeared there", app, toVersion, envName)
+	}
+	return "", "", fmt.Errorf("cannot roll back the application %s past its first version %s in the environment %s", app, from, envName)
+}
+
+// updateRollback updates the Rollback step on the PipelineActivity logging any failure
+func (o *PromoteOptions) updateRollback(promoteKey *kube.PromoteStepActivityKey, fn func(r *v1.RollbackActivityStep)) {
+	err := promoteKey.OnRollback(o.Activities, func(a *v1.PipelineActivity, s *v1.PipelineActivityStep, r *v1.RollbackActivityStep) error {
+		fn(r)
+		return nil
+	})
+	if err != nil {
+		log.Warnf("Failed to update PipelineActivity: %s\n", err)
+	}
+}
+
+// shouldWaitForRollback returns true if the rollback should wait for the pipeline of the environment
+func (o *PromoteOptions) shouldWaitForRollback() bool {
+	if o.NoPoll {
+		return false
+	}
+	if o.TimeoutDuration == nil || o.PullRequestPollDuration == nil {
+		log.Infof("No --%s or --%s option specified on the 'jx promote' command so not waiting for the rollback to succeed\n", optionTimeout, optionPullRequestPollTime)
+		return false
+	}
+	return true
+}
+
+// waitForRollbackPullRequest waits for the Pull Request to merge, merging it once its last commit succeeds unless
+// merging is disabled, returning the merge SHA
+func (o *PromoteOptions) waitForRollbackPullRequest(provider gits.GitProvider, pr *gits.GitPullRequest) (string, error) {
+	end := time.Now().Add(*o.TimeoutDuration)
+	logMergeFailure := false
+	for {
+		err := provider.UpdatePullRequestStatus(pr)
+		if err != nil {
+			log.Warnf("Failed to query the Pull Request status for %s %s\n", pr.URL, err)
+		} else if pr.Merged != nil && *pr.Merged {
+			if pr.MergeCommitSHA != nil {
+				log.Infof("Pull Request %s is merged at sha %s\n", util.ColorInfo(pr.URL), util.ColorInfo(*pr.MergeCommitSHA))
+				return *pr.MergeCommitSHA, nil
+			}
+		} else if pr.IsClosed() {
+			return "", fmt.Errorf("the rollback failed as Pull Request %s is closed without merging", pr.URL)
+		} else if !o.NoMergePullRequest {
+			status, err := provider.PullRequestLastCommitStatus(pr)
+			if err != nil {
+				log.Warnf("Failed to query the Pull Request last commit status for %s ref %s %s\n", pr.URL, pr.LastCommitSha, err)
+			} else if status == gitStatusSuccess {
+				err = provider.MergePullRequest(pr, "jx promote automatically merged rollback PR")
+				if err != nil && !logMergeFailure {
+					logMergeFailure = true
+					log.Warnf("Failed to merge the Pull Request %s due to %s\n", pr.URL, err)
+				}
+			} else if status == "error" || status == "failure" {
+				return "", fmt.Errorf("Pull request %s last commit has status %s for ref %s", pr.URL, status, pr.LastCommitSha)
+			}
+		}
+		if time.Now().After(end) {
+			return "", fmt.Errorf("Timed out waiting for pull request %s to merge. Waited %s", pr.URL, o.TimeoutDuration.String())
+		}
+		time.Sleep(*o.PullRequestPollDuration)
+	}
+}
+
+// waitForRollbackCommitStatuses waits for the statuses of the commit on the environment repository to succeed
+func (o *PromoteOptions) waitForRollbackCommitStatuses(provider gits.GitProvider, owner string, repo string, sha string) error {
+	end := time.Now().Add(*o.TimeoutDuration)
+	logStatusError := false
+	for {
+		statuses, err := provider.ListCommitStatus(owner, repo, sha)
+		if err != nil {
+			if !logStatusError {
+				logStatusError = true
+				log.Warnf("Failed to query the status of repo %s/%s sha %s due to: %s\n", owner, repo, sha, err)
+			}
+		} else if len(statuses) > 0 {
+			states := map[string]string{}
+			for _, status := range statuses {
+				if status.IsFailed() {
+					return fmt.Errorf("the environment pipeline failed with status: %s URL: %s description: %s", status.State, status.TargetURL, status.Description)
+				}
+				if states[status.URL] == "" {
+					states[status.URL] = status.State
+				}
+			}
+			succeeded := true
+			for _, state := range states {
+				if state != gitStatusSuccess {
+					succeeded = false
+				}
+			}
+			if succeeded {
+				log.Infof("The pipeline of %s/%s succeeded for sha %s\n", owner, repo, util.ColorInfo(sha))
+				return nil
+			}
+		}
+		if time.Now().After(end) {
+			return fmt.Errorf("Timed out waiting for the environment pipeline of %s/%s sha %s. Waited %s", owner, repo, sha, o.TimeoutDuration.String())
+		}
+		time.Sleep(*o.PullRequestPollDuration)
+	}
+}
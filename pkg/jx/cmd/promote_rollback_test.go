@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindRollbackVersion(t *testing.T) {
+	t.Parallel()
+	history := []appVersionRevision{
+		{SHA: "e", Version: "1.0.3"},
+		{SHA: "d", Version: "1.0.3"},
+		{SHA: "c", Version: "1.0.2"},
+		{SHA: "b", Version: "1.0.1"},
+		{SHA: "a", Version: ""},
+		{SHA: "0", Version: "0.9.0"},
+	}
+
+	from, to, err := findRollbackVersion(history, "myapp", "production", "")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.3", from)
+	assert.Equal(t, "1.0.2", to)
+
+	from, to, err = findRollbackVersion(history, "myapp", "production", "1.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.3", from)
+	assert.Equal(t, "1.0.1", to)
+
+	_, _, err = findRollbackVersion(history, "myapp", "production", "0.9.0")
+	assert.Error(t, err, "cannot roll back past the first appearance of the app")
+
+	_, _, err = findRollbackVersion(history, "myapp", "production", "1.0.3")
+	assert.Error(t, err, "already at the version")
+
+	_, _, err = findRollbackVersion(history[3:], "myapp", "production", "")
+	assert.Error(t, err, "no version before the first one")
+
+	_, _, err = findRollbackVersion(history[4:], "myapp", "production", "")
+	assert.Error(t, err, "the app is not in the environment")
+}
+
+func TestLoadAppVersionHistory(t *testing.T) {
+	t.Parallel()
+	fileName := "env/requirements.yaml"
+	o := &PromoteOptions{
+		CommonOptions: CommonOptions{
+			GitClient: &gits.GitFake{
+				FileHistory: map[string][]gits.GitFileRevision{
+					fileName: {
+						{SHA: "c", Content: "dependencies:\n- name: myapp\n  version: 1.0.2\n- name: other\n  version: 2.0.0\n"},
+						{SHA: "b", Content: "dependencies:\n- name: myapp\n  version: 1.0.1\n"},
+						{SHA: "a", Content: "dependencies:\n- name: other\n  version: 1.0.0\n"},
+					},
+				},
+			},
+		},
+	}
+
+	history, err := o.loadAppVersionHistory("", fileName, "myapp")
+	require.NoError(t, err)
+	assert.Equal(t, []appVersionRevision{
+		{SHA: "c", Version: "1.0.2"},
+		{SHA: "b", Version: "1.0.1"},
+		{SHA: "a", Version: ""},
+	}, history)
+}
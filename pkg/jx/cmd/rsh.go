@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/pkg/errors"
@@ -16,6 +17,7 @@ import (
 	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/util"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 const (
@@ -232,9 +234,21 @@ func (o *RshOptions) Run() error {
 	if o.Verbose {
 		log.Infof("Running command: kubectl %s\n", strings.Join(a, " "))
 	}
+	if o.DevPod {
+		o.recordDevPodActivity(client, ns, name)
+		defer o.recordDevPodActivity(client, ns, name)
+	}
 	return o.runCommandInteractive(true, "kubectl", a...)
 }
 
+// recordDevPodActivity records the activity in the DevPod so that it is not garbage collected while it is in use
+func (o *RshOptions) recordDevPodActivity(client kubernetes.Interface, ns string, name string) {
+	err := kube.UpdateDevPodLastActivity(client, ns, name, time.Now())
+	if err != nil {
+		log.Warnf("Failed to record the activity of DevPod %s: %s\n", name, err)
+	}
+}
+
 func (o *RshOptions) detectBash(ns string, podName string, container string) (string, error) {
 	fileName := "/tmp/pod_" + podName + "_shells"
 	args := []string{"cp", ns + "/" + podName + ":" + ShellsFile, fileName}
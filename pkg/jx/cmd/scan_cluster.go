@@ -24,8 +24,6 @@ const (
 	kubeHunterContainerName = "jx-kube-hunter"
 	kubeHunterNamespace     = "jx-kube-hunter"
 	kubeHunterJobName       = "jx-kube-hunter-job"
-
-	outputFormatYAML = "yaml"
 )
 
 // ScanClusterOptions the options for 'scan cluster' command
@@ -1,32 +1,88 @@
 package cmd
 
 import (
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"io"
+	"net/http"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/prow"
+	"github.com/jenkins-x/jx/pkg/util"
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
+const (
+	// StatusCheckPass the status of a check which passed
+	StatusCheckPass = "pass"
+	// StatusCheckWarn the status of a check which passed with a warning
+	StatusCheckWarn = "warn"
+	// StatusCheckFail the status of a check which failed
+	StatusCheckFail = "fail"
+
+	defaultStatusCheckTimeout = 10 * time.Second
+
+	// certificates which expire within this period are reported with a warning
+	certificateExpiryWarningPeriod = 14 * 24 * time.Hour
+)
+
+// prowDeployments the deployments of Prow which receive the webhooks and run the pipelines
+var prowDeployments = []string{prow.Hook, "plank"}
+
 type StatusOptions struct {
 	CommonOptions
 	node string
+
+	Output  string
+	Timeout time.Duration
 }
 
+// StatusCheck the result of the health check of a component of Jenkins X
+type StatusCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// StatusReport the results of the health checks of Jenkins X
+type StatusReport struct {
+	Cluster string        `json:"cluster,omitempty"`
+	Status  string        `json:"status"`
+	Checks  []StatusCheck `json:"checks"`
+}
+
+// statusCheckFunc checks a component returning its status and the reason
+type statusCheckFunc func() (string, string)
+
 var (
 	StatusLong = templates.LongDesc(`
-		Gets the current status of the Kubernetes cluster
+		Gets the current status of the Kubernetes cluster and checks the health of the components of Jenkins X
+
+		Each check passes, warns or fails with a reason. The command fails if any check fails so that it can be used
+		for monitoring. The checks run in parallel and a check which does not complete within the timeout fails.
 
 `)
 
 	StatusExample = templates.Examples(`
 		# displays the current status of the Kubernetes cluster
 		jx status
+
+		# reports the status as JSON for monitoring
+		jx status -o json
 `)
 )
 
@@ -55,10 +111,20 @@ func NewCmdStatus(f Factory, in terminal.FileReader, out terminal.FileWriter, er
 	}
 
 	cmd.Flags().StringVarP(&options.node, "node", "n", "", "the named node to get ")
+	cmd.Flags().StringVarP(&options.Output, optionOutput, "o", "", "The output format which is one of: "+strings.Join(outputFormats, ", ")+". Defaults to "+outputFormatTable)
+	cmd.Flags().DurationVarP(&options.Timeout, "timeout", "", defaultStatusCheckTimeout, "The timeout of each check")
 	return cmd
 }
 
 func (o *StatusOptions) Run() error {
+	getOptions := &GetOptions{
+		CommonOptions: o.CommonOptions,
+		Output:        o.Output,
+	}
+	structured, err := getOptions.structuredOutput()
+	if err != nil {
+		return err
+	}
 
 	client, namespace, err := o.KubeClient()
 	if err != nil {
@@ -92,29 +158,300 @@ func (o *StatusOptions) Run() error {
 		return fmt.Errorf("no deployments found in namespace %s", namespace)
 	}
 
-	for _, d := range deployList.Items {
-		err = kube.WaitForDeploymentToBeReady(client, d.Name, namespace, 5*time.Second)
+	jxClient, _, err := o.JXClient()
+	if err != nil {
+		return err
+	}
+	report := o.checkStatus(client, jxClient, namespace, clusterStatus, deployList.Items)
+
+	if structured {
+		err = getOptions.renderResult(report, o.Output)
 		if err != nil {
-			log.Warnf("%s: jx deployment %s not ready in namespace %s", clusterStatus.Info(), d.Name, namespace)
 			return err
 		}
+	} else {
+		table := o.CreateTable()
+		table.AddRow("CHECK", "STATUS", "REASON")
+		for _, check := range report.Checks {
+			table.AddRow(check.Name, statusCheckString(check.Status), check.Reason)
+		}
+		table.Render()
+	}
+
+	switch report.Status {
+	case StatusCheckFail:
+		failed := []string{}
+		for _, check := range report.Checks {
+			if check.Status == StatusCheckFail {
+				failed = append(failed, check.Name)
+			}
+		}
+		return fmt.Errorf("Jenkins X checks failed for %s: %s", clusterStatus.Info(), strings.Join(failed, ", "))
+	case StatusCheckWarn:
+		if !structured {
+			log.Warnf("Jenkins X checks passed with warnings for %s\n", clusterStatus.Info())
+		}
+	default:
+		if !structured {
+			log.Successf("Jenkins X checks passed for %s\n", clusterStatus.Info())
+		}
+	}
+	return nil
+}
+
+// checkStatus runs the checks of the components of Jenkins X in parallel
+func (o *StatusOptions) checkStatus(client kubernetes.Interface, jxClient versioned.Interface, ns string, clusterStatus kube.ClusterStatus, deployments []appsv1.Deployment) *StatusReport {
+	timeout := o.Timeout
+	if timeout <= 0 {
+		timeout = defaultStatusCheckTimeout
+	}
+	deploymentMap := map[string]*appsv1.Deployment{}
+	for i := range deployments {
+		deploymentMap[deployments[i].Name] = &deployments[i]
+	}
+	prowEnabled := isProwStatus(client, jxClient, ns)
+	webhookURL := func() (string, error) {
+		if prowEnabled {
+			baseURL, err := kube.GetServiceURLFromName(client, prow.Hook, ns)
+			if err != nil || baseURL == "" {
+				return "", fmt.Errorf("the %s service has no URL", prow.Hook)
+			}
+			return util.UrlJoin(baseURL, "hook"), nil
+		}
+		baseURL, err := kube.GetServiceURLFromName(client, kube.ServiceJenkins, ns)
+		if err != nil || baseURL == "" {
+			return "", fmt.Errorf("the %s service has no URL", kube.ServiceJenkins)
+		}
+		return baseURL, nil
+	}
+
+	names := []string{
+		"Cluster",
+		"Deployments",
+		"Pipeline engine",
+		"Controllers",
+		"Chart repository",
+		"Docker registry",
+		"Webhook endpoint",
+		"TLS certificate",
+	}
+	checks := []statusCheckFunc{
+		func() (string, string) {
+			resources := clusterStatus.CheckResource()
+			if resources != "" {
+				return StatusCheckWarn, "need more " + resources
+			}
+			return StatusCheckPass, clusterStatus.Info()
+		},
+		func() (string, string) {
+			notReady := []string{}
+			for _, d := range deployments {
+				if !isDeploymentReady(&d) {
+					notReady = append(notReady, d.Name)
+				}
+			}
+			if len(notReady) > 0 {
+				return StatusCheckFail, "not ready: " + strings.Join(notReady, ", ")
+			}
+			return StatusCheckPass, fmt.Sprintf("%d deployments are ready", len(deployments))
+		},
+		func() (string, string) {
+			if prowEnabled {
+				return checkStatusDeployments(deploymentMap, prowDeployments, "Prow")
+			}
+			return checkStatusDeployments(deploymentMap, []string{kube.ServiceJenkins}, "Jenkins")
+		},
+		func() (string, string) {
+			if prowEnabled && deploymentMap[kube.DeploymentControllerBuild] == nil {
+				return StatusCheckFail, fmt.Sprintf("the %s deployment is missing so the pipeline activities are not updated", kube.DeploymentControllerBuild)
+			}
+			return checkOptionalStatusDeployments(deploymentMap, kube.DeploymentControllerBuild, kube.DeploymentControllerWorkflow)
+		},
+		func() (string, string) {
+			return checkOptionalStatusDeployments(deploymentMap, kube.DeploymentChartMuseum, kube.DeploymentNexus)
+		},
+		func() (string, string) {
+			return checkDockerRegistryStatus(client, ns, deploymentMap)
+		},
+		func() (string, string) {
+			url, err := webhookURL()
+			if err != nil {
+				return StatusCheckFail, err.Error()
+			}
+			return checkURLStatus(url, timeout)
+		},
+		func() (string, string) {
+			return checkTLSStatus(client, ns)
+		},
+	}
+
+	report := &StatusReport{
+		Cluster: clusterStatus.Info(),
+		Status:  StatusCheckPass,
+		Checks:  make([]StatusCheck, len(checks)),
+	}
+	var wg sync.WaitGroup
+	for i := range checks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			report.Checks[i] = runStatusCheck(names[i], timeout, checks[i])
+		}(i)
+	}
+	wg.Wait()
+	for _, check := range report.Checks {
+		if check.Status == StatusCheckFail || (check.Status == StatusCheckWarn && report.Status == StatusCheckPass) {
+			report.Status = check.Status
+		}
+	}
+	return report
+}
+
+// runStatusCheck runs the check failing it if it does not complete within the timeout
+func runStatusCheck(name string, timeout time.Duration, check statusCheckFunc) StatusCheck {
+	result := make(chan StatusCheck, 1)
+	go func() {
+		status, reason := check()
+		result <- StatusCheck{Name: name, Status: status, Reason: reason}
+	}()
+	select {
+	case answer := <-result:
+		return answer
+	case <-time.After(timeout):
+		return StatusCheck{Name: name, Status: StatusCheckFail, Reason: fmt.Sprintf("timed out after %s", timeout)}
+	}
+}
+
+// isProwStatus returns true if the webhook engine of the development environment is Prow
+func isProwStatus(client kubernetes.Interface, jxClient versioned.Interface, ns string) bool {
+	devEnv, err := jxClient.JenkinsV1().Environments(ns).Get(kube.LabelValueDevEnvironment, metav1.GetOptions{})
+	if err == nil && devEnv.Spec.WebHookEngine != v1.WebHookEngineNone {
+		return devEnv.Spec.WebHookEngine == v1.WebHookEngineProw
+	}
+	prowEnabled, err := kube.IsProwEnabled(client, ns)
+	return err == nil && prowEnabled
+}
+
+func isDeploymentReady(d *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	return d.Status.ReadyReplicas >= replicas
+}
+
+// checkStatusDeployments fails unless all the deployments of the component exist and are ready
+func checkStatusDeployments(deploymentMap map[string]*appsv1.Deployment, names []string, component string) (string, string) {
+	for _, name := range names {
+		d := deploymentMap[name]
+		if d == nil {
+			return StatusCheckFail, fmt.Sprintf("%s is not installed, the %s deployment is missing", component, name)
+		}
+		if !isDeploymentReady(d) {
+			return StatusCheckFail, fmt.Sprintf("the %s deployment of %s is not ready", name, component)
+		}
+	}
+	return StatusCheckPass, component + " is ready"
+}
+
+// checkOptionalStatusDeployments fails if any of the deployments which exist are not ready and warns if none exist
+func checkOptionalStatusDeployments(deploymentMap map[string]*appsv1.Deployment, names ...string) (string, string) {
+	ready := []string{}
+	for _, name := range names {
+		d := deploymentMap[name]
+		if d == nil {
+			continue
+		}
+		if !isDeploymentReady(d) {
+			return StatusCheckFail, fmt.Sprintf("the %s deployment is not ready", name)
+		}
+		ready = append(ready, name)
+	}
+	if len(ready) == 0 {
+		return StatusCheckWarn, "none of " + strings.Join(names, ", ") + " are installed"
+	}
+	return StatusCheckPass, strings.Join(ready, ", ") + " ready"
+}
+
+// checkDockerRegistryStatus checks the in-cluster Docker registry if there is one otherwise that an external registry
+// is configured
+func checkDockerRegistryStatus(client kubernetes.Interface, ns string, deploymentMap map[string]*appsv1.Deployment) (string, string) {
+	if deploymentMap[kube.DeploymentDockerRegistry] != nil {
+		return checkStatusDeployments(deploymentMap, []string{kube.DeploymentDockerRegistry}, "the Docker registry")
+	}
+	cm, err := client.CoreV1().ConfigMaps(ns).Get(kube.ConfigMapJenkinsDockerRegistry, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return StatusCheckWarn, fmt.Sprintf("no Docker registry is configured in the ConfigMap %s", kube.ConfigMapJenkinsDockerRegistry)
+		}
+		return StatusCheckFail, err.Error()
+	}
+	registry := cm.Data["docker.registry"]
+	if registry == "" {
+		return StatusCheckWarn, fmt.Sprintf("no docker.registry is configured in the ConfigMap %s", kube.ConfigMapJenkinsDockerRegistry)
+	}
+	return StatusCheckPass, "uses the external registry " + registry
+}
+
+// checkURLStatus checks the URL is reachable, any response other than a server error being good enough as the
+// endpoints of webhooks only accept POST requests
+func checkURLStatus(url string, timeout time.Duration) (string, string) {
+	httpClient := &http.Client{Timeout: timeout}
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return StatusCheckFail, fmt.Sprintf("%s is not reachable: %s", url, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return StatusCheckFail, fmt.Sprintf("%s returned %s", url, resp.Status)
 	}
-	resourceStr := clusterStatus.CheckResource()
+	return StatusCheckPass, url + " is reachable"
+}
 
-	jenkinsURL, err := o.findServiceInNamespace("jenkins", namespace)
+// checkTLSStatus checks the expiry of the certificate of the ingress TLS secret
+func checkTLSStatus(client kubernetes.Interface, ns string) (string, string) {
+	ic, err := kube.GetIngressConfig(client, ns)
+	if err != nil || !ic.TLS {
+		return StatusCheckWarn, "TLS is not enabled"
+	}
+	secretName := ic.TLSSecretName
+	if secretName == "" {
+		secretName = kube.SecretIngressTLS
+	}
+	secret, err := client.CoreV1().Secrets(ns).Get(secretName, metav1.GetOptions{})
 	if err != nil {
-		if resourceStr != "" {
-			log.Warnf("%s Jenkins not found and %s\n", clusterStatus.Info(), resourceStr)
-		} else {
-			log.Warnf("%s Jenkins not found\n", clusterStatus.Info())
+		if apierrors.IsNotFound(err) && ic.TLSSecretName == "" {
+			// cert-manager creates the secret of each ingress and renews their certificates
+			return StatusCheckPass, "the certificates are issued by cert-manager with the issuer " + ic.Issuer
 		}
-		return err
+		return StatusCheckFail, fmt.Sprintf("failed to get the TLS secret %s: %s", secretName, err)
 	}
-	if resourceStr != "" {
-		log.Warnf("Jenkins X installed for %s. Jenkins is running at %s. Need more %s\n", clusterStatus.Info(), jenkinsURL, resourceStr)
-	} else {
-		log.Successf("Jenkins X checks passed for %s. Jenkins is running at %s\n", clusterStatus.Info(), jenkinsURL)
+	block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+	if block == nil {
+		return StatusCheckFail, fmt.Sprintf("the TLS secret %s has no PEM encoded certificate", secretName)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return StatusCheckFail, fmt.Sprintf("failed to parse the certificate of the TLS secret %s: %s", secretName, err)
+	}
+	expiry := cert.NotAfter.Format(time.RFC3339)
+	remaining := time.Until(cert.NotAfter)
+	if remaining <= 0 {
+		return StatusCheckFail, fmt.Sprintf("the certificate of the TLS secret %s expired on %s", secretName, expiry)
+	}
+	if remaining < certificateExpiryWarningPeriod {
+		return StatusCheckWarn, fmt.Sprintf("the certificate of the TLS secret %s expires on %s", secretName, expiry)
 	}
+	return StatusCheckPass, fmt.Sprintf("the certificate of the TLS secret %s expires on %s", secretName, expiry)
+}
 
-	return nil
+func statusCheckString(status string) string {
+	switch status {
+	case StatusCheckFail:
+		return util.ColorError(status)
+	case StatusCheckWarn:
+		return util.ColorWarning(status)
+	default:
+		return util.ColorInfo(status)
+	}
 }
@@ -1,14 +1,30 @@
 package cmd_test
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
+	versiond_mocks "github.com/jenkins-x/jx/pkg/client/clientset/versioned/fake"
 	"github.com/jenkins-x/jx/pkg/jx/cmd"
 	cmd_mocks "github.com/jenkins-x/jx/pkg/jx/cmd/mocks"
+	cmd_mock_matchers "github.com/jenkins-x/jx/pkg/jx/cmd/mocks/matchers"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/table"
 
 	. "github.com/petergtz/pegomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -83,8 +99,14 @@ func TestStatusRun(t *testing.T) {
 		},
 	}
 
+	// the webhook endpoint of Jenkins must be reachable
+	jenkinsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer jenkinsServer.Close()
+
 	annotations := make(map[string]string)
-	annotations["fabric8.io/exposeUrl"] = "http://jenkins.testorama.com"
+	annotations["fabric8.io/exposeUrl"] = jenkinsServer.URL
 
 	// Create a fake Jenkins service
 	service := &v1.Service{
@@ -101,6 +123,8 @@ func TestStatusRun(t *testing.T) {
 	kubernetesInterface := kube_mocks.NewSimpleClientset(node, deployment, service)
 	// Override CreateClient to return mock Kubernetes interface
 	When(factory.CreateClient()).ThenReturn(kubernetesInterface, "jx-testing", nil)
+	When(factory.CreateJXClient()).ThenReturn(versiond_mocks.NewSimpleClientset(), "jx-testing", nil)
+	When(factory.CreateTable(cmd_mock_matchers.AnyIoWriter())).ThenReturn(table.CreateTable(os.Stdout))
 
 	// Setup options
 	options := &cmd.StatusOptions{
@@ -116,3 +140,96 @@ func TestStatusRun(t *testing.T) {
 	assert.NoError(t, err, "Should not error")
 
 }
+
+func TestStatusRunReportsFailuresAsJSON(t *testing.T) {
+	t.Parallel()
+
+	node := &v1.Node{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name: "test-node-1",
+		},
+		Status: v1.NodeStatus{
+			Capacity: v1.ResourceList{
+				v1.ResourceName(v1.ResourceCPU):    resource.MustParse("10"),
+				v1.ResourceName(v1.ResourceMemory): resource.MustParse("10G"),
+			},
+		},
+	}
+	replicaCount := int32(1)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "jenkins",
+			Namespace: "jx-testing",
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicaCount,
+		},
+	}
+	ingressConfig := &v1.ConfigMap{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      kube.IngressConfigConfigmap,
+			Namespace: "jx-testing",
+		},
+		Data: map[string]string{
+			kube.TLS:           "true",
+			kube.TLSSecretName: "my-tls",
+		},
+	}
+	tlsSecret := &v1.Secret{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "my-tls",
+			Namespace: "jx-testing",
+		},
+		Data: map[string][]byte{
+			v1.TLSCertKey: generateStatusTestCertificate(t, time.Now().Add(-time.Hour)),
+		},
+	}
+
+	factory := cmd_mocks.NewMockFactory()
+	When(factory.CreateClient()).ThenReturn(kube_mocks.NewSimpleClientset(node, deployment, ingressConfig, tlsSecret), "jx-testing", nil)
+	When(factory.CreateJXClient()).ThenReturn(versiond_mocks.NewSimpleClientset(), "jx-testing", nil)
+
+	out, err := ioutil.TempFile("", "test-status")
+	require.NoError(t, err)
+	defer os.Remove(out.Name())
+	options := &cmd.StatusOptions{
+		CommonOptions: cmd.CommonOptions{
+			Factory: factory,
+			Out:     out,
+			Err:     os.Stderr,
+		},
+		Output: "json",
+	}
+
+	err = options.Run()
+	assert.Error(t, err, "the command should fail when a check fails")
+
+	data, err := ioutil.ReadFile(out.Name())
+	require.NoError(t, err)
+	report := &cmd.StatusReport{}
+	require.NoError(t, json.Unmarshal(data, report))
+	assert.Equal(t, cmd.StatusCheckFail, report.Status)
+	checks := map[string]cmd.StatusCheck{}
+	for _, check := range report.Checks {
+		checks[check.Name] = check
+	}
+	assert.Equal(t, cmd.StatusCheckFail, checks["Deployments"].Status)
+	assert.Equal(t, "not ready: jenkins", checks["Deployments"].Reason)
+	assert.Equal(t, cmd.StatusCheckFail, checks["Webhook endpoint"].Status)
+	assert.Equal(t, cmd.StatusCheckFail, checks["TLS certificate"].Status)
+	assert.Contains(t, checks["TLS certificate"].Reason, "expired")
+}
+
+func generateStatusTestCertificate(t *testing.T, notAfter time.Time) []byte {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "jenkins.jx.example.com"},
+		NotBefore:    notAfter.Add(-48 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
 	"gopkg.in/src-d/go-git.v4/plumbing/object"
@@ -48,6 +49,8 @@ type StepChangelogOptions struct {
 	Footer              string
 	FooterFile          string
 	OutputMarkdownFile  string
+	Template            string
+	ChangelogFile       string
 	OverwriteCRD        bool
 	GenerateCRD         bool
 	GenerateReleaseYaml bool
@@ -68,6 +71,9 @@ type StepChangelogState struct {
 const (
 	ReleaseName = `{{ .Chart.Name }}-{{ .Chart.Version | replace "+" "_" }}`
 
+	// defaultChangelogTemplate the go template of the changelog of a repository which is used if there is no --template
+	defaultChangelogTemplate = ".jx/changelog.tmpl"
+
 	SpecName    = `{{ .Chart.Name }}`
 	SpecVersion = `{{ .Chart.Version }}`
 
@@ -109,7 +115,9 @@ e.g. define environment variables GIT_USERNAME and GIT_API_TOKEN
 
 		If you have just created a git tag this command will try default to the changes between the last tag and the previous one. You can always specify the exact Git references (tag/sha) directly via '--previous-rev' and '--rev'
 
-		The changelog is generated by parsing the git commits. It will also detect any text like 'fixes #123' to link to issue fixes. You can also use Conventional Commits notation: https://conventionalcommits.org/ to get a nicer formatted changelog. e.g. using commits like 'fix:(my feature) this my fix' or 'feat:(cheese) something'
+		The changelog is generated by parsing the git commits. It will also detect any text like 'fixes #123' to link to issue fixes. You can also use Conventional Commits notation: https://conventionalcommits.org/ to get a nicer formatted changelog. e.g. using commits like 'fix(my feature): this my fix' or 'feat(cheese)!: something' which are grouped into sections such as the breaking changes, features, fixes and other changes. If none of the commits use the notation the changelog is a flat list of the commits
+
+		You can fully customize the markdown with a go template on the grouped commits: https://golang.org/pkg/text/template/ via the '--template' option which defaults to the '.jx/changelog.tmpl' file of the repository if it exists
 
 		This command also generates a Release Custom Resource Definition you can include in your helm chart to give metadata about the changelog of the application along with metadata about the release (git tag, url, commits, issues fixed etc). Including this metadata in a helm charts means we can do things like automatically comment on issues when they hit Staging or Production; or give detailed descriptions of what things have changed when using GitOps to update versions in an environment by referencing the fixed issues in the Pull Request.
 
@@ -129,6 +137,9 @@ e.g. define environment variables GIT_USERNAME and GIT_API_TOKEN
 		# specify the version and a header template
 		jx step changelog --header-file docs/dev/changelog-header.md --version 1.2.3
 
+		# render the changelog with a go template and add it to the CHANGELOG.md file of the repository
+		jx step changelog --version 1.2.3 --template docs/changelog.tmpl --changelog-file CHANGELOG.md
+
 `)
 
 	GitHubIssueRegex = regexp.MustCompile(`(\#\d+)`)
@@ -171,6 +182,8 @@ func NewCmdStepChangelog(f Factory, in terminal.FileReader, out terminal.FileWri
 	cmd.Flags().StringVarP(&options.Build, "build", "", "", "The Build number which is used to update the PipelineActivity. If not specified its defaulted from  the '$BUILD_NUMBER' environment variable")
 	cmd.Flags().StringVarP(&options.Dir, "dir", "", "", "The directory of the Git repository. Defaults to the current working directory")
 	cmd.Flags().StringVarP(&options.OutputMarkdownFile, "output-markdown", "", "", "The file to generate for the changelog output if not updating a Git provider release")
+	cmd.Flags().StringVarP(&options.Template, "template", "", "", "The go template file which renders the markdown of the changelog from the commits grouped into sections. Defaults to "+defaultChangelogTemplate+" if it exists in the repository")
+	cmd.Flags().StringVarP(&options.ChangelogFile, "changelog-file", "", "", "The file of the repository, such as CHANGELOG.md, which the changelog of the version is added to then committed and pushed")
 	cmd.Flags().BoolVarP(&options.OverwriteCRD, "overwrite", "o", false, "overwrites the Release CRD YAML file if it exists")
 	cmd.Flags().BoolVarP(&options.GenerateCRD, "crd", "c", false, "Generate the CRD in the chart")
 	cmd.Flags().BoolVarP(&options.GenerateReleaseYaml, "generate-yaml", "y", true, "Generate the Release YAML in the local helm chart")
@@ -346,7 +359,7 @@ func (o *StepChangelogOptions) Run() error {
 	}
 
 	// lets try to update the release
-	markdown, err := gits.GenerateMarkdown(&release.Spec, gitInfo)
+	markdown, err := o.generateMarkdown(dir, &release.Spec, gitInfo)
 	if err != nil {
 		return err
 	}
@@ -359,6 +372,12 @@ func (o *StepChangelogOptions) Run() error {
 		return err
 	}
 	markdown = header + markdown + footer
+	if o.ChangelogFile != "" {
+		err = o.updateChangelogFile(dir, markdown)
+		if err != nil {
+			return err
+		}
+	}
 	if version != "" && o.UpdateRelease && foundGitProvider {
 		releaseInfo := &gits.GitRelease{
 			Name:    version,
@@ -697,3 +716,83 @@ func (o *StepChangelogOptions) getTemplateResult(releaseSpec *v1.ReleaseSpec, te
 	writer.Flush()
 	return buffer.String(), err
 }
+
+// generateMarkdown renders the changelog with the template of the --template option or the default template of the
+// repository if it exists, otherwise it returns the default markdown
+func (o *StepChangelogOptions) generateMarkdown(dir string, releaseSpec *v1.ReleaseSpec, gitInfo *gits.GitRepositoryInfo) (string, error) {
+	changelog := gits.NewChangelog(releaseSpec, gitInfo)
+	templateFile := o.Template
+	if templateFile == "" {
+		templateFile = filepath.Join(dir, defaultChangelogTemplate)
+		exists, err := util.FileExists(templateFile)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return changelog.Markdown(), nil
+		}
+	}
+	data, err := ioutil.ReadFile(templateFile)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to load the changelog template %s", templateFile)
+	}
+	log.Infof("Generating the changelog with the template %s\n", util.ColorInfo(templateFile))
+	return changelog.RenderTemplate(string(data))
+}
+
+// updateChangelogFile adds the changelog of the version to the top of the changelog file of the repository then
+// commits and pushes it
+func (o *StepChangelogOptions) updateChangelogFile(dir string, markdown string) error {
+	if o.Version == "" {
+		log.Warnf("Cannot update the changelog file %s as there is no --version\n", o.ChangelogFile)
+		return nil
+	}
+	if strings.TrimSpace(markdown) == "" {
+		log.Infof("No changes to add to the changelog file %s\n", o.ChangelogFile)
+		return nil
+	}
+	fileName := o.ChangelogFile
+	if !filepath.IsAbs(fileName) {
+		fileName = filepath.Join(dir, fileName)
+	}
+	heading := "# " + o.Version + "\n"
+	existing := ""
+	exists, err := util.FileExists(fileName)
+	if err != nil {
+		return err
+	}
+	if exists {
+		data, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load the changelog file %s", fileName)
+		}
+		existing = string(data)
+		if strings.HasPrefix(existing, heading) || strings.Contains(existing, "\n"+heading) {
+			log.Infof("The changelog file %s already contains version %s\n", util.ColorInfo(o.ChangelogFile), util.ColorInfo(o.Version))
+			return nil
+		}
+	}
+	text := heading + "\n" + strings.TrimSpace(markdown) + "\n"
+	if strings.TrimSpace(existing) != "" {
+		text += "\n" + existing
+	}
+	err = ioutil.WriteFile(fileName, []byte(text), DefaultWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save the changelog file %s", fileName)
+	}
+	err = o.Git().Add(dir, fileName)
+	if err != nil {
+		return err
+	}
+	// skip CI so that pushing the changelog does not trigger another release
+	err = o.Git().CommitDir(dir, fmt.Sprintf("chore: add version %s to %s [skip ci]", o.Version, o.ChangelogFile))
+	if err != nil {
+		return err
+	}
+	err = o.Git().Push(dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to push the changelog file %s", o.ChangelogFile)
+	}
+	log.Infof("Added version %s to the changelog file %s\n", util.ColorInfo(o.Version), util.ColorInfo(o.ChangelogFile))
+	return nil
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepChangelogGenerateMarkdownWithRepositoryTemplate(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-step-changelog-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	releaseSpec := &v1.ReleaseSpec{
+		Commits: []v1.CommitSummary{
+			{Message: "feat(ui): a feature"},
+			{Message: "fix: a fix"},
+		},
+	}
+	gitInfo := &gits.GitRepositoryInfo{Host: "github.com", Organisation: "jstrachan", Name: "foo"}
+	o := &StepChangelogOptions{}
+
+	markdown, err := o.generateMarkdown(dir, releaseSpec, gitInfo)
+	require.NoError(t, err)
+	assert.Equal(t, "## Changes\n\n### New Features\n\n* ui: a feature\n\n### Bug Fixes\n\n* a fix\n", markdown)
+
+	templateFile := filepath.Join(dir, defaultChangelogTemplate)
+	require.NoError(t, os.MkdirAll(filepath.Dir(templateFile), DefaultWritePermissions))
+	tmpl := "{{ range .Sections }}{{ .Title }}: {{ len .Commits }}\n{{ end }}"
+	require.NoError(t, ioutil.WriteFile(templateFile, []byte(tmpl), DefaultWritePermissions))
+	markdown, err = o.generateMarkdown(dir, releaseSpec, gitInfo)
+	require.NoError(t, err)
+	assert.Equal(t, "New Features: 1\nBug Fixes: 1\n", markdown)
+
+	o.Template = filepath.Join(dir, "does-not-exist.tmpl")
+	_, err = o.generateMarkdown(dir, releaseSpec, gitInfo)
+	assert.Error(t, err)
+}
+
+func TestStepChangelogUpdateChangelogFile(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-step-changelog-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	fakeGit := &gits.GitFake{}
+	o := &StepChangelogOptions{
+		Version:       "1.1.0",
+		ChangelogFile: "CHANGELOG.md",
+	}
+	o.GitClient = fakeGit
+	fileName := filepath.Join(dir, "CHANGELOG.md")
+
+	require.NoError(t, o.updateChangelogFile(dir, "## Changes\n\n* first\n"))
+	o.Version = "1.2.0"
+	require.NoError(t, o.updateChangelogFile(dir, "## Changes\n\n* second\n"))
+	// running the step again for the same version does not add it twice
+	require.NoError(t, o.updateChangelogFile(dir, "## Changes\n\n* second\n"))
+
+	data, err := ioutil.ReadFile(fileName)
+	require.NoError(t, err)
+	assert.Equal(t, "# 1.2.0\n\n## Changes\n\n* second\n\n# 1.1.0\n\n## Changes\n\n* first\n", string(data))
+
+	commits := fakeGit.Commits
+	require.Len(t, commits, 2)
+	assert.Equal(t, "chore: add version 1.2.0 to CHANGELOG.md [skip ci]", commits[1].Message)
+}
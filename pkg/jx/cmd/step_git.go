@@ -44,6 +44,7 @@ func NewCmdStepGit(f Factory, in terminal.FileReader, out terminal.FileWriter, e
 		},
 	}
 	cmd.AddCommand(NewCmdStepGitCredentials(f, in, out, errOut))
+	cmd.AddCommand(NewCmdStepGitStatus(f, in, out, errOut))
 	return cmd
 }
 
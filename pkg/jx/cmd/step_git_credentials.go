@@ -9,10 +9,12 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/jenkins-x/jx/pkg/gits"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
 	corev1 "k8s.io/api/core/v1"
@@ -20,6 +22,9 @@ import (
 
 const (
 	optionOutputFile = "output"
+
+	gitSSHSigningKeyFile = "ssh-signing-key"
+	gitGPGProgramFile    = "gpg"
 )
 
 // StepGitCredentialsOptions contains the command line flags
@@ -27,6 +32,7 @@ type StepGitCredentialsOptions struct {
 	StepOptions
 
 	OutputFile string
+	Sign       bool
 }
 
 var (
@@ -42,6 +48,9 @@ var (
 		# generate the Git credentials to a output file
 		jx step git credentials -o /tmp/mycreds
 
+		# generate the Git credentials and sign the git commits and tags with the signing key of the team
+		jx step git credentials --sign
+
 `)
 )
 
@@ -70,6 +79,7 @@ func NewCmdStepGitCredentials(f Factory, in terminal.FileReader, out terminal.Fi
 		},
 	}
 	cmd.Flags().StringVarP(&options.OutputFile, optionOutputFile, "o", "", "The output file name")
+	cmd.Flags().BoolVarP(&options.Sign, "sign", "", false, "Configures git to sign the commits and tags with the GPG or SSH key of the team created by 'jx create gpg key'")
 	return cmd
 }
 
@@ -99,7 +109,139 @@ func (o *StepGitCredentialsOptions) Run() error {
 	if err != nil {
 		return err
 	}
-	return o.createGitCredentialsFile(outFile, secrets)
+	err = o.createGitCredentialsFile(outFile, secrets)
+	if err != nil {
+		return err
+	}
+	settings, err := o.TeamSettings()
+	if err != nil {
+		return err
+	}
+	if settings.GitProtocol == gits.GitProtocolSSH {
+		err = o.configureGitSSHRemotes(secrets)
+		if err != nil {
+			return err
+		}
+	}
+	if o.Sign {
+		return o.configureGitSigning(settings.GitSigningKeySecret)
+	}
+	return nil
+}
+
+// configureGitSigning installs the signing key of the given Secret and configures git to sign all commits and tags
+// with it
+func (o *StepGitCredentialsOptions) configureGitSigning(secretName string) error {
+	if secretName == "" {
+		return fmt.Errorf("no git signing key is configured for the team, please create one via: jx create gpg key")
+	}
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	key, err := kube.GetGitSigningKey(kubeClient, ns, secretName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load the git signing key from Secret %s in namespace %s", secretName, ns)
+	}
+	configDir, err := util.ConfigDir()
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(configDir, "git-signing")
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		return err
+	}
+	err = o.installGitSigningKey(key, dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to install the git signing key of Secret %s", secretName)
+	}
+	for _, setting := range GitSigningConfig(key, dir) {
+		err = o.RunCommand("git", "config", "--global", setting[0], setting[1])
+		if err != nil {
+			return errors.Wrapf(err, "failed to set the git configuration %s", setting[0])
+		}
+	}
+	log.Infof("Signing git commits and tags with the %s key of Secret %s\n", key.Format, util.ColorInfo(secretName))
+	return nil
+}
+
+// installGitSigningKey writes the signing key to the given directory. GPG keys are imported into their own keyring as
+// the release GPG keyring of the pipelines is mounted read only
+func (o *StepGitCredentialsOptions) installGitSigningKey(key *kube.GitSigningKey, dir string) error {
+	if key.Format == kube.GitSigningFormatSSH {
+		err := ioutil.WriteFile(filepath.Join(dir, gitSSHSigningKeyFile), key.PrivateKey, 0600)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(filepath.Join(dir, gitSSHSigningKeyFile+".pub"), key.PublicKey, 0644)
+	}
+	homeDir := filepath.Join(dir, "gnupg")
+	err := os.MkdirAll(homeDir, 0700)
+	if err != nil {
+		return err
+	}
+	keyFile := filepath.Join(dir, "private-key.asc")
+	err = ioutil.WriteFile(keyFile, key.PrivateKey, 0600)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(keyFile)
+	err = o.RunCommand("gpg", "--homedir", homeDir, "--batch", "--import", keyFile)
+	if err != nil {
+		return err
+	}
+	script := fmt.Sprintf("#!/bin/sh\nexec gpg --homedir %s \"$@\"\n", homeDir)
+	return ioutil.WriteFile(filepath.Join(dir, gitGPGProgramFile), []byte(script), 0700)
+}
+
+// GitSigningConfig returns the git configuration settings which sign commits and tags with the key installed in the
+// given directory
+func GitSigningConfig(key *kube.GitSigningKey, dir string) [][]string {
+	answer := [][]string{}
+	if key.Format == kube.GitSigningFormatSSH {
+		answer = append(answer,
+			[]string{"gpg.format", "ssh"},
+			[]string{"user.signingkey", filepath.Join(dir, gitSSHSigningKeyFile)})
+	} else {
+		answer = append(answer,
+			[]string{"gpg.format", "openpgp"},
+			[]string{"gpg.program", filepath.Join(dir, gitGPGProgramFile)},
+			[]string{"user.signingkey", key.KeyID})
+	}
+	return append(answer,
+		[]string{"commit.gpgsign", "true"},
+		[]string{"tag.gpgsign", "true"})
+}
+
+// configureGitSSHRemotes makes git rewrite the https URLs of the git servers of the pipeline secrets to ssh URLs so
+// that the pipelines clone and push over ssh with the deploy key of the team
+func (o *StepGitCredentialsOptions) configureGitSSHRemotes(secrets *corev1.SecretList) error {
+	for _, secret := range secrets.Items {
+		u := secret.Annotations[kube.AnnotationURL]
+		if u == "" {
+			continue
+		}
+		u2, err := url.Parse(u)
+		if err != nil || u2.Host == "" {
+			log.Warnf("Ignoring invalid Git service URL %s for pipeline credential %s\n", u, secret.Name)
+			continue
+		}
+		if gits.IsAzureDevOpsHost(u2.Host) {
+			log.Warnf("Cannot rewrite the Azure DevOps URL %s to ssh as its ssh paths differ, configure the remotes of the repositories instead\n", u)
+			continue
+		}
+		key := "url.ssh://git@" + u2.Hostname() + "/.insteadOf"
+		o.RunCommand("git", "config", "--global", "--unset-all", key)
+		for _, scheme := range []string{"https://", "http://"} {
+			err = o.RunCommand("git", "config", "--global", "--add", key, scheme+u2.Host+"/")
+			if err != nil {
+				return errors.Wrapf(err, "failed to rewrite %s git URLs to ssh", u)
+			}
+		}
+		log.Infof("Using ssh for the Git server %s\n", util.ColorInfo(u))
+	}
+	return nil
 }
 
 func (o *StepGitCredentialsOptions) createGitCredentialsFile(fileName string, secrets *corev1.SecretList) error {
@@ -5,6 +5,7 @@ import (
 
 	"github.com/jenkins-x/jx/pkg/gits"
 	"github.com/jenkins-x/jx/pkg/jx/cmd"
+	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/testkube"
 	"github.com/jenkins-x/jx/pkg/tests"
 	"github.com/stretchr/testify/assert"
@@ -55,3 +56,33 @@ func createGitCredentialLine(scheme string, host string, user string, pwd string
 	answer += scheme + user + ":" + pwd + "@" + host + "\n"
 	return answer
 }
+
+func TestGitSigningConfig(t *testing.T) {
+	t.Parallel()
+	gpgConfig := cmd.GitSigningConfig(&kube.GitSigningKey{Format: kube.GitSigningFormatGPG, KeyID: "D3C4A425B5D9DD4E"}, "/home/jenkins/.jx/git-signing")
+	assert.Equal(t, [][]string{
+		{"gpg.format", "openpgp"},
+		{"gpg.program", "/home/jenkins/.jx/git-signing/gpg"},
+		{"user.signingkey", "D3C4A425B5D9DD4E"},
+		{"commit.gpgsign", "true"},
+		{"tag.gpgsign", "true"},
+	}, gpgConfig)
+
+	sshConfig := cmd.GitSigningConfig(&kube.GitSigningKey{Format: kube.GitSigningFormatSSH}, "/home/jenkins/.jx/git-signing")
+	assert.Equal(t, [][]string{
+		{"gpg.format", "ssh"},
+		{"user.signingkey", "/home/jenkins/.jx/git-signing/ssh-signing-key"},
+		{"commit.gpgsign", "true"},
+		{"tag.gpgsign", "true"},
+	}, sshConfig)
+}
+
+func TestGPGFingerprint(t *testing.T) {
+	t.Parallel()
+	output := `sec:u:2048:1:3F7D97C11BBF85DA:1792140000:::u:::scESC:::+:::23::0:
+fpr:::::::::D3C4A425B5D9DD4E5AD0717E3F7D97C11BBF85DA:
+grp:::::::::8B1E2F0C5A0D6E4B8F3A9C2D1E0F4A5B6C7D8E9F:
+uid:u::::1792140000::0123456789ABCDEF::bot <bot@example.com>::::::::::0:`
+	assert.Equal(t, "D3C4A425B5D9DD4E5AD0717E3F7D97C11BBF85DA", cmd.GPGFingerprint(output))
+	assert.Equal(t, "", cmd.GPGFingerprint("gpg: error reading key: No secret key"))
+}
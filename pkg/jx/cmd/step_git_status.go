@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+const defaultCommitStatusContext = "jenkins-x"
+
+var (
+	commitStatusStates = []string{"pending", "success", "failure", "error"}
+
+	stepGitStatusLong = templates.LongDesc(`
+		Reports the state of the pipeline as the build status of the commit on the git provider, so that it is shown on
+		the Pull Request and can be required before merging.
+
+		The commit defaults to the pull request commit of Prow or the HEAD of the repository and the link of the status to
+		the URL of the build.
+`)
+
+	stepGitStatusExample = templates.Examples(`
+		# report that the pipeline of the current commit is running
+		jx step git status --state pending
+
+		# report that the pipeline succeeded
+		jx step git status --state success --description "Build succeeded"
+`)
+)
+
+// StepGitStatusOptions contains the command line flags
+type StepGitStatusOptions struct {
+	StepOptions
+
+	State       string
+	Context     string
+	Description string
+	TargetURL   string
+	SHA         string
+	Dir         string
+}
+
+// NewCmdStepGitStatus creates the command object for the "step git status" command
+func NewCmdStepGitStatus(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepGitStatusOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "status",
+		Short:   "Reports the state of the pipeline as the build status of the commit",
+		Long:    stepGitStatusLong,
+		Example: stepGitStatusExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&options.State, "state", "s", "", fmt.Sprintf("The state of the pipeline: %s", util.ColorInfo(commitStatusStates)))
+	cmd.Flags().StringVarP(&options.Context, "context", "c", defaultCommitStatusContext, "The name which identifies the status of the pipeline on the commit")
+	cmd.Flags().StringVarP(&options.Description, "description", "", "", "The summary of the status")
+	cmd.Flags().StringVarP(&options.TargetURL, "target-url", "", "", "The URL the status links to. Defaults to $BUILD_URL")
+	cmd.Flags().StringVarP(&options.SHA, "sha", "", "", "The commit to report the status on. Defaults to $PULL_PULL_SHA or the HEAD of the repository")
+	cmd.Flags().StringVarP(&options.Dir, "dir", "d", "", "The directory of the repository. Defaults to the current working directory")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepGitStatusOptions) Run() error {
+	if util.StringArrayIndex(commitStatusStates, o.State) < 0 {
+		return util.InvalidOption("state", o.State, commitStatusStates)
+	}
+	gitInfo, err := o.Git().Info(o.Dir)
+	if err != nil {
+		return errors.Wrap(err, "failed to find the git repository")
+	}
+	sha := o.SHA
+	if sha == "" {
+		sha = os.Getenv(PULL_PULL_SHA)
+	}
+	if sha == "" {
+		sha, err = o.getCommandOutput(o.Dir, "git", "rev-parse", "HEAD")
+		if err != nil {
+			return errors.Wrap(err, "failed to find the commit of the repository")
+		}
+	}
+	targetURL := o.TargetURL
+	if targetURL == "" {
+		targetURL = os.Getenv("BUILD_URL")
+	}
+	provider, err := o.gitProviderForURL(gitInfo.URL, "user name to report the build status as")
+	if err != nil {
+		return err
+	}
+	status := &gits.GitRepoStatus{
+		State:       o.State,
+		Context:     o.Context,
+		Description: o.Description,
+		TargetURL:   targetURL,
+	}
+	return updateCommitStatus(provider, gitInfo, sha, status)
+}
+
+// updateCommitStatus sets the build status of the commit of the repository on the git provider
+func updateCommitStatus(provider gits.GitProvider, gitInfo *gits.GitRepositoryInfo, sha string, status *gits.GitRepoStatus) error {
+	_, err := provider.UpdateCommitStatus(gitInfo.Organisation, gitInfo.Name, sha, status)
+	if err != nil {
+		return errors.Wrapf(err, "failed to set the status of commit %s of %s/%s", sha, gitInfo.Organisation, gitInfo.Name)
+	}
+	log.Infof("Set the %s status of commit %s of %s/%s to %s\n", status.Context, util.ColorInfo(sha), gitInfo.Organisation, gitInfo.Name, util.ColorInfo(status.State))
+	return nil
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepGitStatusSetsTheStatusOfTheCommit(t *testing.T) {
+	t.Parallel()
+	commit := &gits.FakeCommit{Commit: &gits.GitCommit{SHA: "abc123"}}
+	repo := &gits.FakeRepository{GitRepo: &gits.GitRepository{Name: "myrepo"}, Commits: []*gits.FakeCommit{commit}}
+	provider := &gits.FakeProvider{
+		Type:         gits.BitbucketServer,
+		Repositories: map[string][]*gits.FakeRepository{"myorg": {repo}},
+	}
+	gitInfo := &gits.GitRepositoryInfo{Organisation: "myorg", Name: "myrepo"}
+
+	for _, state := range []string{"pending", "success", "failure"} {
+		err := updateCommitStatus(provider, gitInfo, "abc123", &gits.GitRepoStatus{State: state, Context: defaultCommitStatusContext})
+		require.NoError(t, err)
+		assert.Equal(t, gits.CommitStatus(state), commit.Status)
+	}
+
+	err := updateCommitStatus(provider, gitInfo, "def456", &gits.GitRepoStatus{State: "success"})
+	assert.Error(t, err, "the commit does not exist")
+}
+
+func TestStepGitStatusRejectsUnknownStates(t *testing.T) {
+	t.Parallel()
+	o := &StepGitStatusOptions{State: "done"}
+	err := o.Run()
+	assert.Error(t, err)
+}
@@ -3,13 +3,19 @@ package cmd
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 
+	"github.com/jenkins-x/jx/pkg/helm"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 // StepHelmApplyOptions contains the command line flags
@@ -27,6 +33,9 @@ var (
 		Applies the helm chart in a given directory.
 
 		This step is usually used to apply any GitOps promotion changes into a Staging or Production cluster.
+
+		Values of the chart such as secret://my-secret/password are replaced with the keys of the Secrets in the
+		development namespace so that no credentials have to be committed to the Git repository of an environment.
 `)
 
 	StepHelmApplyExample = templates.Examples(`
@@ -119,16 +128,78 @@ func (o *StepHelmApplyOptions) Run() error {
 	info := util.ColorInfo
 	log.Infof("Applying helm chart at %s as release name %s to namespace %s\n", info(dir), info(releaseName), info(ns))
 
+	valueFiles, err := o.secretValueFiles(dir)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		for _, f := range valueFiles {
+			os.Remove(f)
+		}
+	}()
+
 	o.Helm().SetCWD(dir)
 
 	if o.Wait {
 		timeout := 600
-		err = o.Helm().UpgradeChart(chartName, releaseName, ns, nil, true, &timeout, o.Force, true, nil, nil)
+		err = o.Helm().UpgradeChart(chartName, releaseName, ns, nil, true, &timeout, o.Force, true, nil, valueFiles)
 	} else {
-		err = o.Helm().UpgradeChart(chartName, releaseName, ns, nil, true, nil, o.Force, false, nil, nil)
+		err = o.Helm().UpgradeChart(chartName, releaseName, ns, nil, true, nil, o.Force, false, nil, valueFiles)
 	}
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// secretValueFiles returns a temporary values file in which the secret URLs of the values of the chart are replaced
+// with the keys of the Secrets they reference or no files if the chart has no secret URLs
+func (o *StepHelmApplyOptions) secretValueFiles(dir string) ([]string, error) {
+	valuesFile := filepath.Join(dir, helm.ValuesFileName)
+	exists, err := util.FileExists(valuesFile)
+	if err != nil || !exists {
+		return nil, err
+	}
+	values, err := helm.LoadValuesFile(valuesFile)
+	if err != nil {
+		return nil, err
+	}
+	var kubeClient kubernetes.Interface
+	var ns string
+	secrets := map[string]*v1.Secret{}
+	resolved, err := helm.ResolveSecretURLs(values, func(secretName string, key string) (string, error) {
+		if kubeClient == nil {
+			kubeClient, ns, err = o.KubeClientAndDevNamespace()
+			if err != nil {
+				return "", err
+			}
+		}
+		secret := secrets[secretName]
+		if secret == nil {
+			secret, err = kubeClient.CoreV1().Secrets(ns).Get(secretName, metav1.GetOptions{})
+			if err != nil {
+				return "", err
+			}
+			secrets[secretName] = secret
+		}
+		value, ok := secret.Data[key]
+		if !ok {
+			return "", fmt.Errorf("the Secret %s in namespace %s has no key %s", secretName, ns, key)
+		}
+		return string(value), nil
+	})
+	if err != nil || !resolved {
+		return nil, err
+	}
+	f, err := ioutil.TempFile("", "secret-values-")
+	if err != nil {
+		return nil, err
+	}
+	f.Close()
+	err = helm.SaveValuesFile(f.Name(), values)
+	if err != nil {
+		os.Remove(f.Name())
+		return nil, err
+	}
+	return []string{f.Name()}, nil
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// StepInstallOptions contains the command line flags
+type StepInstallOptions struct {
+	StepOptions
+}
+
+// NewCmdStepInstall creates the command
+func NewCmdStepInstall(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepInstallOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "install",
+		Short: "install [command]",
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.AddCommand(NewCmdStepInstallCRDs(f, in, out, errOut))
+	return cmd
+}
+
+// Run implements this command
+func (o *StepInstallOptions) Run() error {
+	return o.Cmd.Help()
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// namespaceScopedClusterRole the cluster role which lets the teams of a namespace scoped install read the cluster
+	// scoped resources jx looks up
+	namespaceScopedClusterRole = "jenkins-x-namespace-scoped"
+)
+
+// StepInstallCRDsOptions contains the command line flags
+type StepInstallCRDsOptions struct {
+	StepOptions
+
+	TeamNamespaces []string
+	Users          []string
+}
+
+var (
+	stepInstallCRDsLong = templates.LongDesc(`
+		This pipeline step command registers the Jenkins X custom resource definitions and creates the cluster role
+		which 'jx install --namespace-scoped' needs.
+
+		It is run once by a cluster administrator so that teams which are only admins of their namespaces can install
+		Jenkins X.
+
+`)
+
+	stepInstallCRDsExample = templates.Examples(`
+		# registers the CRDs and lets the builds of the team in namespace jx read them
+		jx step install crds --team-namespace jx
+
+		# also lets a user who is not a cluster administrator run jx install --namespace-scoped
+		jx step install crds --team-namespace jx --user jane@example.com
+
+`)
+)
+
+// NewCmdStepInstallCRDs creates the command
+func NewCmdStepInstallCRDs(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := StepInstallCRDsOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "crds",
+		Short:   "Registers the CRDs and cluster roles needed by a namespace scoped install of Jenkins X",
+		Long:    stepInstallCRDsLong,
+		Example: stepInstallCRDsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringArrayVarP(&options.TeamNamespaces, "team-namespace", "", nil, "The namespace of a team whose build service account can read the cluster scoped resources, can be repeated")
+	cmd.Flags().StringArrayVarP(&options.Users, "user", "", nil, "A user who can read the cluster scoped resources to install Jenkins X, can be repeated")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepInstallCRDsOptions) Run() error {
+	apisClient, err := o.CreateApiExtensionsClient()
+	if err != nil {
+		return errors.Wrap(err, "failed to create the API extensions client")
+	}
+	err = kube.RegisterAllCRDs(apisClient)
+	if err != nil {
+		return errors.Wrap(err, "failed to register the CRDs")
+	}
+	log.Infof("Registered the Jenkins X CRDs\n")
+
+	client, _, err := o.KubeClient()
+	if err != nil {
+		return errors.Wrap(err, "failed to create the kube client")
+	}
+	role := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: namespaceScopedClusterRole,
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{"apiextensions.k8s.io"},
+				Resources: []string{"customresourcedefinitions"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+			{
+				APIGroups: []string{""},
+				Resources: []string{"namespaces"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+		},
+	}
+	roles := client.RbacV1().ClusterRoles()
+	old, err := roles.Get(namespaceScopedClusterRole, metav1.GetOptions{})
+	if err == nil {
+		old.Rules = role.Rules
+		_, err = roles.Update(old)
+	} else {
+		_, err = roles.Create(role)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to save the ClusterRole %s", namespaceScopedClusterRole)
+	}
+	log.Infof("Saved the ClusterRole %s\n", util.ColorInfo(namespaceScopedClusterRole))
+
+	subjects := []rbacv1.Subject{}
+	for _, user := range o.Users {
+		subjects = append(subjects, rbacv1.Subject{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "User",
+			Name:     user,
+		})
+	}
+	for _, ns := range o.TeamNamespaces {
+		subjects = append(subjects, rbacv1.Subject{
+			Kind:      "ServiceAccount",
+			Name:      jxBuilderServiceAccount,
+			Namespace: ns,
+		})
+	}
+	if len(subjects) == 0 {
+		log.Infof("Use --team-namespace or --user to bind the ClusterRole %s\n", namespaceScopedClusterRole)
+		return nil
+	}
+	bindings := client.RbacV1().ClusterRoleBindings()
+	binding, err := bindings.Get(namespaceScopedClusterRole, metav1.GetOptions{})
+	if err == nil {
+		for _, subject := range subjects {
+			if !containsSubject(binding.Subjects, subject) {
+				binding.Subjects = append(binding.Subjects, subject)
+			}
+		}
+		_, err = bindings.Update(binding)
+	} else {
+		binding = &rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: namespaceScopedClusterRole,
+			},
+			Subjects: subjects,
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: "rbac.authorization.k8s.io",
+				Kind:     "ClusterRole",
+				Name:     namespaceScopedClusterRole,
+			},
+		}
+		_, err = bindings.Create(binding)
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to save the ClusterRoleBinding %s: %s", namespaceScopedClusterRole, err)
+	}
+	log.Infof("Saved the ClusterRoleBinding %s\n", util.ColorInfo(namespaceScopedClusterRole))
+	return nil
+}
+
+func containsSubject(subjects []rbacv1.Subject, subject rbacv1.Subject) bool {
+	for _, s := range subjects {
+		if s.Kind == subject.Kind && s.Name == subject.Name && s.Namespace == subject.Namespace {
+			return true
+		}
+	}
+	return false
+}
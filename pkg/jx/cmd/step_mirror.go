@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/binaries"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// StepMirrorOptions contains the command line flags
+type StepMirrorOptions struct {
+	StepOptions
+
+	Dir      string
+	Version  string
+	Provider string
+	Charts   []string
+	OS       string
+	Arch     string
+
+	InstallFlags InstallFlags
+}
+
+var (
+	stepMirrorLong = templates.LongDesc(`
+		This pipeline step command downloads the charts, binaries and the list of images which 'jx install' needs into
+		a bundle which can be loaded into the mirrors of an air-gapped network.
+
+		Run it from a machine which can reach the internet. The binaries are saved under the host and path of their
+		upstream URLs so the directory can be served as the 'binaryDownloadURL' of the --offline-config of 'jx install'.
+
+`)
+
+	stepMirrorExample = templates.Examples(`
+		# downloads the bundle for the platform version of the cloud environments
+		jx step mirror --dir jx-mirror
+
+		# downloads the bundle for linux machines including extra charts
+		jx step mirror --os linux --arch amd64 --chart stable/external-dns
+
+`)
+)
+
+// NewCmdStepMirror creates the command
+func NewCmdStepMirror(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := StepMirrorOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "mirror",
+		Short:   "Downloads the charts, binaries and image list needed to install Jenkins X in an air-gapped network",
+		Long:    stepMirrorLong,
+		Example: stepMirrorExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&options.Dir, "dir", "d", "jx-mirror", "The directory to download the bundle into")
+	cmd.Flags().StringVarP(&options.Version, "version", "", "", "The platform version to mirror. Defaults to the version of the cloud environments")
+	cmd.Flags().StringVarP(&options.Provider, "provider", "", KUBERNETES, "The kubernetes provider whose cloud environment values are used to find the images of the platform")
+	cmd.Flags().StringArrayVarP(&options.Charts, "chart", "c", []string{"stable/nginx-ingress"}, "The additional charts to mirror")
+	cmd.Flags().StringVarP(&options.OS, "os", "", runtime.GOOS, "The operating system of the machines the binaries are downloaded for")
+	cmd.Flags().StringVarP(&options.Arch, "arch", "", runtime.GOARCH, "The architecture of the machines the binaries are downloaded for")
+	options.InstallFlags.addCloudEnvOptions(cmd)
+	return cmd
+}
+
+// Run implements this command
+func (o *StepMirrorOptions) Run() error {
+	chartsDir := filepath.Join(o.Dir, "charts")
+	binariesDir := filepath.Join(o.Dir, "binaries")
+	for _, dir := range []string{chartsDir, binariesDir} {
+		err := os.MkdirAll(dir, DefaultWritePermissions)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create the directory %s", dir)
+		}
+	}
+
+	io := &InstallOptions{}
+	io.CommonOptions = o.CommonOptions
+	io.Flags = o.InstallFlags
+	wrkDir, err := io.cloneJXCloudEnvironmentsRepo()
+	if err != nil {
+		return err
+	}
+	version := o.Version
+	if version == "" {
+		version, err = LoadVersionFromCloudEnvironmentsDir(wrkDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = o.addHelmBinaryRepoIfMissing(DEFAULT_CHARTMUSEUM_URL, "jenkins-x")
+	if err != nil {
+		return err
+	}
+	err = o.Helm().UpdateRepo()
+	if err != nil {
+		return errors.Wrap(err, "failed to update the helm repositories")
+	}
+	helmBinary := o.Helm().HelmBinary()
+	err = o.RunCommand(helmBinary, "fetch", "jenkins-x/jenkins-x-platform", "--version", version, "--destination", chartsDir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to download the platform chart version %s", version)
+	}
+	for _, chart := range o.Charts {
+		err = o.RunCommand(helmBinary, "fetch", chart, "--destination", chartsDir)
+		if err != nil {
+			return errors.Wrapf(err, "failed to download the chart %s", chart)
+		}
+	}
+	log.Infof("Downloaded the charts to %s\n", util.ColorInfo(chartsDir))
+
+	platformChart := filepath.Join(chartsDir, fmt.Sprintf("jenkins-x-platform-%s.tgz", version))
+	args := []string{"template", platformChart}
+	valuesFile := filepath.Join(wrkDir, fmt.Sprintf("env-%s", strings.ToLower(o.Provider)), CloudEnvValuesFile)
+	exists, err := util.FileExists(valuesFile)
+	if err != nil {
+		return err
+	}
+	if exists {
+		args = append(args, "--values", valuesFile)
+	}
+	manifests, err := o.getCommandOutput("", helmBinary, args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to render the platform chart %s", platformChart)
+	}
+	images := helm.ImagesOfManifests(manifests)
+	imagesFile := filepath.Join(o.Dir, "images.txt")
+	err = ioutil.WriteFile(imagesFile, []byte(strings.Join(images, "\n")+"\n"), DefaultWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to write the images file %s", imagesFile)
+	}
+	log.Infof("Listed the %d images of the platform in %s\n", len(images), util.ColorInfo(imagesFile))
+
+	downloadURLs, err := o.binaryDownloadURLs()
+	if err != nil {
+		return err
+	}
+	for _, downloadURL := range downloadURLs {
+		err = o.downloadToMirror(binariesDir, downloadURL)
+		if err != nil {
+			return err
+		}
+	}
+	log.Infof("Downloaded the binaries to %s\n", util.ColorInfo(binariesDir))
+
+	log.Infof("Load the charts into your chart repository mirror, push the images to your docker registry mirror and serve the binaries directory, then pass a file mapping the upstream hosts to the mirrors to %s\n",
+		util.ColorInfo("jx install --offline-config"))
+	return nil
+}
+
+// binaryDownloadURLs returns the download and checksum URLs of the binaries jx installs for the operating system and
+// architecture
+func (o *StepMirrorOptions) binaryDownloadURLs() ([]string, error) {
+	answer := []string{}
+	binaryOptions := []InstallOrUpdateBinaryOptions{
+		eksctlBinaryOptions(binaries.EksctlVersion, false),
+		awsIamAuthenticatorBinaryOptions(binaries.AwsIamAuthenticatorVersion, false),
+		kindBinaryOptions(),
+		k3dBinaryOptions(),
+		doctlBinaryOptions(),
+	}
+	for _, options := range binaryOptions {
+		clientURL, checksumURL, err := options.downloadURLs(o.OS, o.Arch)
+		if err != nil {
+			return nil, err
+		}
+		answer = append(answer, clientURL)
+		if checksumURL != "" {
+			answer = append(answer, checksumURL)
+		}
+	}
+
+	helm3URL := fmt.Sprintf("https://get.helm.sh/helm-v%s-%s-%s.tar.gz", binaries.Helm3Version, o.OS, o.Arch)
+	answer = append(answer, helm3URL, helm3URL+".sha256")
+
+	helmVersion, err := util.GetLatestVersionFromGitHub("kubernetes", "helm")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find the latest helm version")
+	}
+	helmURL := fmt.Sprintf("https://storage.googleapis.com/kubernetes-helm/helm-v%s-%s-%s.tar.gz", helmVersion, o.OS, o.Arch)
+	answer = append(answer, helmURL, helmURL+".sha256")
+
+	kubectlVersion, err := o.getLatestVersionFromKubernetesReleaseUrl()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find the latest kubectl version")
+	}
+	kubectl := "kubectl"
+	if o.OS == "windows" {
+		kubectl = "kubectl.exe"
+	}
+	kubectlURL := fmt.Sprintf("https://storage.googleapis.com/kubernetes-release/release/v%s/bin/%s/%s/%s", kubectlVersion, o.OS, o.Arch, kubectl)
+	answer = append(answer, kubectlURL, kubectlURL+".sha256")
+	return answer, nil
+}
+
+// downloadToMirror downloads the file under the host and path of its URL in the directory
+func (o *StepMirrorOptions) downloadToMirror(dir string, downloadURL string) error {
+	u, err := url.Parse(downloadURL)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse the URL %s", downloadURL)
+	}
+	fileName := filepath.Join(dir, u.Host, filepath.FromSlash(u.Path))
+	err = os.MkdirAll(filepath.Dir(fileName), DefaultWritePermissions)
+	if err != nil {
+		return err
+	}
+	log.Infof("Downloading %s\n", util.ColorInfo(downloadURL))
+	err = util.DownloadFile(fileName, downloadURL)
+	if err != nil {
+		return errors.Wrapf(err, "failed to download %s", downloadURL)
+	}
+	return nil
+}
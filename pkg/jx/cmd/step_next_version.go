@@ -15,8 +15,11 @@ import (
 
 	"github.com/blang/semver"
 	version "github.com/hashicorp/go-version"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/gits"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
 )
@@ -35,7 +38,12 @@ type StepNextVersionOptions struct {
 	Tag           bool
 	UseGitTagOnly bool
 	NewVersion    string
+	SemVer        string
+	PreRelease    string
+	BuildMetadata string
 	StepOptions
+
+	latestTagName string
 }
 
 type Project struct {
@@ -49,6 +57,17 @@ type PackageJSON struct {
 var (
 	StepNextVersionLong = templates.LongDesc(`
 		This pipeline step command works out a semantic version, writes a file ./VERSION and optionally updates a file
+
+		The version bumps the major, minor or patch version of the latest git tag. The 'auto' bump derives it from the
+		conventional commit messages since the latest tag: major for breaking changes, minor for features and patch
+		otherwise. The bump and the pre-release identifier default to those of the release section of jenkins-x.yml:
+
+		release:
+		  semver: auto
+		  preRelease: rc
+
+		The version of a commit which was already released is reused so that running the release again on the same
+		commit does not create another version.
 `)
 
 	StepNextVersionExample = templates.Examples(`
@@ -56,6 +75,9 @@ var (
 		jx step next-version --filename package.json
 		jx step next-version --filename package.json --tag
 		jx step next-version --filename package.json --tag --version 1.2.3
+
+		# bump the minor version and create its next release candidate, e.g. 1.3.0-rc.1 then 1.3.0-rc.2
+		jx step next-version --use-git-tag-only --semver minor --pre-release rc
 `)
 )
 
@@ -78,6 +100,7 @@ func NewCmdStepNextVersion(f Factory, in terminal.FileReader, out terminal.FileW
 	cmd.Flags().StringVarP(&options.Dir, "dir", "d", "", "the directory to look for files that contain a pom.xml or Makefile with the project version to bump")
 	cmd.Flags().BoolVarP(&options.Tag, "tag", "t", false, "tag and push new version")
 	cmd.Flags().BoolVarP(&options.UseGitTagOnly, "use-git-tag-only", "", false, "only use a git tag so work out new semantic version, else specify filename [pom.xml,package.json,Makefile,Chart.yaml]")
+	addSemVerFlags(cmd, &options.SemVer, &options.PreRelease, &options.BuildMetadata)
 
 	options.addCommonFlags(cmd)
 	return cmd
@@ -87,11 +110,12 @@ func (o *StepNextVersionOptions) Run() error {
 
 	var err error
 	if o.NewVersion == "" {
-		o.NewVersion, err = o.getNewVersionFromTag()
+		o.NewVersion, err = o.nextVersion()
 		if err != nil {
 			return err
 		}
 	}
+	o.NewVersion = withBuildMetadata(o.NewVersion, o.BuildMetadata)
 
 	// in declaritive pipelines we sometimes need to write the version to a file rather than pass state
 	err = ioutil.WriteFile("VERSION", []byte(o.NewVersion), 0755)
@@ -123,6 +147,79 @@ func (o *StepNextVersionOptions) Run() error {
 	return nil
 }
 
+// addSemVerFlags adds the flags which choose the next semantic version
+func addSemVerFlags(cmd *cobra.Command, semVer *string, preRelease *string, buildMetadata *string) {
+	cmd.Flags().StringVarP(semVer, "semver", "", "", "The part of the semantic version to bump which is one of: "+strings.Join(util.SemVerBumps, ", ")+". Defaults to the release semver of jenkins-x.yml or patch")
+	cmd.Flags().StringVarP(preRelease, "pre-release", "", "", "The identifier of the pre-release version such as rc which is appended to the version and incremented, e.g. 1.3.0-rc.2. Defaults to the release preRelease of jenkins-x.yml")
+	cmd.Flags().StringVarP(buildMetadata, "build-metadata", "", "", "The build metadata appended to the version, e.g. 1.2.3+<build-metadata>")
+}
+
+// withBuildMetadata appends the build metadata to the version unless it already has build metadata
+func withBuildMetadata(version string, buildMetadata string) string {
+	if buildMetadata == "" || strings.Contains(version, "+") {
+		return version
+	}
+	return version + "+" + buildMetadata
+}
+
+// nextVersion returns the version of the current commit if it was already released or the next version otherwise
+func (o *StepNextVersionOptions) nextVersion() (string, error) {
+	released, err := o.releasedVersion()
+	if err != nil || released != "" {
+		return released, err
+	}
+	return o.getNewVersionFromTag()
+}
+
+// releasedVersion returns the highest version of the tags of the releases of the current commit or an empty string if
+// it was not released
+func (o *StepNextVersionOptions) releasedVersion() (string, error) {
+	tags, err := o.Git().ReleaseTags(o.Dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to find the release tags of the current commit: %s", err)
+	}
+	var answer *semver.Version
+	for _, tag := range tags {
+		v, err := semver.Parse(strings.TrimPrefix(tag, "v"))
+		if err == nil && (answer == nil || v.GT(*answer)) {
+			answer = &v
+		}
+	}
+	if answer == nil {
+		return "", nil
+	}
+	log.Infof("The current commit was already released as %s\n", util.ColorInfo(answer.String()))
+	return answer.String(), nil
+}
+
+// semVerStrategy returns the part of the version to bump and the pre-release identifier of the flags, defaulting to
+// those of the release configuration of the project
+func (o *StepNextVersionOptions) semVerStrategy() (string, string, error) {
+	bump := o.SemVer
+	preRelease := o.PreRelease
+	if bump == "" || preRelease == "" {
+		projectConfig, _, err := config.LoadProjectConfig(o.Dir)
+		if err != nil {
+			return "", "", err
+		}
+		if projectConfig.Release != nil {
+			if bump == "" {
+				bump = projectConfig.Release.SemVer
+			}
+			if preRelease == "" {
+				preRelease = projectConfig.Release.PreRelease
+			}
+		}
+	}
+	if bump == "" {
+		bump = util.SemVerPatch
+	}
+	if util.StringArrayIndex(util.SemVerBumps, bump) < 0 {
+		return "", "", util.InvalidOption("semver", bump, util.SemVerBumps)
+	}
+	return bump, preRelease, nil
+}
+
 // GetVersion gets the version from a source file
 func (o *StepNextVersionOptions) GetVersion() (string, error) {
 	if o.UseGitTagOnly {
@@ -259,10 +356,12 @@ func (o *StepNextVersionOptions) getLatestTag() (string, error) {
 
 	// turn the array into a new collection of versions that we can sort
 	var versions []*version.Version
-	for _, raw := range versionsRaw {
+	tagNames := map[*version.Version]string{}
+	for i, raw := range versionsRaw {
 		v, _ := version.NewVersion(raw)
 		if v != nil {
 			versions = append(versions, v)
+			tagNames[v] = tags[i]
 		}
 	}
 
@@ -282,6 +381,7 @@ func (o *StepNextVersionOptions) getLatestTag() (string, error) {
 	if versions[latest-1] == nil {
 		return "0.0.0", fmt.Errorf("no existing tags found")
 	}
+	o.latestTagName = tagNames[versions[latest-1]]
 	return versions[latest-1].String(), nil
 }
 
@@ -298,9 +398,22 @@ func (o *StepNextVersionOptions) getNewVersionFromTag() (string, error) {
 		return "", err
 	}
 
-	majorVersion := sv.Major
-	minorVersion := sv.Minor
-	patchVersion := sv.Patch + 1
+	bump, preRelease, err := o.semVerStrategy()
+	if err != nil {
+		return "", err
+	}
+	if bump == util.SemVerAuto {
+		messages, err := o.Git().GetCommitMessagesSince(o.Dir, o.latestTagName)
+		if err != nil {
+			return "", fmt.Errorf("failed to get the commit messages since tag %s: %s", o.latestTagName, err)
+		}
+		bump = gits.ConventionalCommitsBump(messages)
+		log.Infof("Bumping the %s version for the %d commits since the latest tag\n", util.ColorInfo(bump), len(messages))
+	}
+	next, err := util.NextSemVer(sv, bump, preRelease)
+	if err != nil {
+		return "", err
+	}
 
 	// check if major or minor version has been changed
 	baseVersion, err := o.GetVersion()
@@ -309,10 +422,6 @@ func (o *StepNextVersionOptions) getNewVersionFromTag() (string, error) {
 	}
 
 	// first use go-version to turn into a proper version, this handles 1.0-SNAPSHOT which semver doesn't
-	baseMajorVersion := uint64(0)
-	baseMinorVersion := uint64(0)
-	basePatchVersion := uint64(0)
-
 	if baseVersion != "" {
 		tmpVersion, err := version.NewVersion(baseVersion)
 		if err != nil {
@@ -322,20 +431,19 @@ func (o *StepNextVersionOptions) getNewVersionFromTag() (string, error) {
 		if err != nil {
 			return "", err
 		}
-		baseMajorVersion = bsv.Major
-		baseMinorVersion = bsv.Minor
-		basePatchVersion = bsv.Patch
-	}
-
-	if baseMajorVersion > majorVersion ||
-		(baseMajorVersion == majorVersion &&
-			(baseMinorVersion > minorVersion) || (baseMinorVersion == minorVersion && basePatchVersion > patchVersion)) {
-		majorVersion = baseMajorVersion
-		minorVersion = baseMinorVersion
-		patchVersion = basePatchVersion
+		base := semver.Version{Major: bsv.Major, Minor: bsv.Minor, Patch: bsv.Patch}
+		if base.GT(semver.Version{Major: next.Major, Minor: next.Minor, Patch: next.Patch}) {
+			next = base
+			if preRelease != "" {
+				id, err := semver.NewPRVersion(preRelease)
+				if err != nil {
+					return "", err
+				}
+				next.Pre = []semver.PRVersion{id, {VersionNum: 1, IsNum: true}}
+			}
+		}
 	}
-
-	return fmt.Sprintf("%d.%d.%d", majorVersion, minorVersion, patchVersion), nil
+	return next.String(), nil
 }
 
 // SetVersion Sets the version...
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepNextVersionSemVerFromProjectConfig(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-step-next-version")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	projectConfig := &config.ProjectConfig{
+		Release: &config.ReleaseConfig{SemVer: "major", PreRelease: "beta"},
+	}
+	err = projectConfig.SaveConfig(filepath.Join(dir, config.ProjectConfigFileName))
+	require.NoError(t, err)
+
+	o := &StepNextVersionOptions{
+		Dir:           dir,
+		UseGitTagOnly: true,
+	}
+	o.GitClient = &gits.GitFake{GitTags: []gits.GitTag{{Name: "1.2.3"}}}
+	version, err := o.nextVersion()
+	require.NoError(t, err)
+	assert.Equal(t, "2.0.0-beta.1", version)
+
+	o.SemVer = "minor"
+	o.PreRelease = "rc"
+	version, err = o.nextVersion()
+	require.NoError(t, err)
+	assert.Equal(t, "1.3.0-rc.1", version, "the flags should override the project configuration")
+
+	o.SemVer = "huge"
+	_, err = o.nextVersion()
+	assert.Error(t, err)
+}
@@ -9,8 +9,10 @@ import (
 	"strconv"
 
 	"fmt"
+	"strings"
 
 	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/log"
 )
 
 // GetOptions is the start of the data required to perform the operation.  As new fields are added, add them here instead of
@@ -26,6 +28,7 @@ type StepPRCommentFlags struct {
 	Owner      string
 	Repository string
 	PR         string
+	Marker     string
 }
 
 // NewCmdStep Steps a command object for the "step" command
@@ -58,6 +61,7 @@ func NewCmdStepPRComment(f Factory, in terminal.FileReader, out terminal.FileWri
 	cmd.Flags().StringVarP(&options.Flags.Owner, "owner", "o", "", "Git organisation / owner")
 	cmd.Flags().StringVarP(&options.Flags.Repository, "repository", "r", "", "Git repository")
 	cmd.Flags().StringVarP(&options.Flags.PR, "pull-request", "p", "", "Git Pull Request number")
+	cmd.Flags().StringVarP(&options.Flags.Marker, "marker", "", "", "A hidden marker which identifies the comment so that it is updated in place instead of adding a new comment")
 
 	options.addCommonFlags(cmd)
 
@@ -109,5 +113,49 @@ func (o *StepPRCommentOptions) Run() error {
 		Number: &prNumber,
 	}
 
+	if o.Flags.Marker != "" {
+		return addOrUpdatePRComment(provider, &pr, o.Flags.Marker, o.Flags.Comment)
+	}
 	return provider.AddPRComment(&pr, o.Flags.Comment)
 }
+
+// commentMarker returns the hidden HTML comment which identifies a Pull Request comment
+func commentMarker(marker string) string {
+	return fmt.Sprintf("<!-- %s -->", marker)
+}
+
+// addOrUpdatePRComment updates the comment on the Pull Request which contains the hidden marker or adds a new comment
+// if there is none
+func addOrUpdatePRComment(provider gits.GitProvider, pr *gits.GitPullRequest, marker string, comment string) error {
+	hiddenMarker := commentMarker(marker)
+	body := comment + "\n\n" + hiddenMarker
+	comments, err := provider.ListPRComments(pr)
+	if err != nil {
+		log.Warnf("Failed to query the comments of the Pull Request so adding a new comment: %s\n", err)
+		return provider.AddPRComment(pr, body)
+	}
+	existing := findMarkedPRComment(comments, hiddenMarker, provider.CurrentUsername())
+	if existing == nil {
+		return provider.AddPRComment(pr, body)
+	}
+	if existing.Body == body {
+		return nil
+	}
+	return provider.EditPRComment(pr, existing, body)
+}
+
+// findMarkedPRComment returns the most recent comment containing the hidden marker written by the user, or by anyone
+// if the user is not known
+func findMarkedPRComment(comments []*gits.GitPRComment, hiddenMarker string, user string) *gits.GitPRComment {
+	var answer *gits.GitPRComment
+	for _, comment := range comments {
+		if comment == nil || !strings.Contains(comment.Body, hiddenMarker) {
+			continue
+		}
+		if user != "" && comment.User.Login != user {
+			continue
+		}
+		answer = comment
+	}
+	return answer
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddOrUpdatePRComment(t *testing.T) {
+	t.Parallel()
+	gitProvider := gits.NewFakeProvider(gits.NewFakeRepository("acme", "myapp"))
+	pr, err := gitProvider.CreatePullRequest(&gits.GitPullRequestArguments{
+		GitRepositoryInfo: &gits.GitRepositoryInfo{Organisation: "acme", Name: "myapp"},
+		Title:             "fix: the thing",
+	})
+	require.NoError(t, err)
+
+	gitProvider.User.Username = "jenkins-x-bot"
+	require.NoError(t, gitProvider.AddPRComment(pr, "/test this"))
+	require.NoError(t, addOrUpdatePRComment(gitProvider, pr, PreviewCommentMarker, "first deploy"))
+	require.NoError(t, addOrUpdatePRComment(gitProvider, pr, PreviewCommentMarker, "second deploy"))
+
+	comments, err := gitProvider.ListPRComments(pr)
+	require.NoError(t, err)
+	require.Len(t, comments, 2)
+	assert.Equal(t, "/test this", comments[0].Body)
+	assert.Equal(t, "second deploy\n\n<!-- jx-preview -->", comments[1].Body)
+
+	// a comment from someone else which contains the marker is not updated
+	gitProvider.User.Username = "mallory"
+	require.NoError(t, addOrUpdatePRComment(gitProvider, pr, PreviewCommentMarker, "third deploy"))
+	comments, err = gitProvider.ListPRComments(pr)
+	require.NoError(t, err)
+	require.Len(t, comments, 3)
+	assert.Equal(t, "second deploy\n\n<!-- jx-preview -->", comments[1].Body)
+}
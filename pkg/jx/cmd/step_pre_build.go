@@ -75,7 +75,7 @@ func (o *StepPreBuildOptions) Run() error {
 
 		log.Infof("Docker registry host: %s app name %s/%s\n", util.ColorInfo(dockerRegistry), util.ColorInfo(orgName), util.ColorInfo(appName))
 
-		if strings.HasSuffix(dockerRegistry, ".amazonaws.com") && strings.Index(dockerRegistry, ".ecr.") > 0 {
+		if amazon.IsECRRegistry(dockerRegistry) {
 			return amazon.LazyCreateRegistry(orgName, appName)
 		}
 	}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// StepReplaceOptions contains the command line flags
+type StepReplaceOptions struct {
+	StepOptions
+}
+
+// NewCmdStepReplace Steps a command object for the "step replace" command
+func NewCmdStepReplace(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepReplaceOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "replace",
+		Short: "replace [command]",
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.AddCommand(NewCmdStepReplaceTLS(f, in, out, errOut))
+	return cmd
+}
+
+// Run implements this command
+func (o *StepReplaceOptions) Run() error {
+	return o.Cmd.Help()
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	"k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// StepReplaceTLSOptions contains the command line flags
+type StepReplaceTLSOptions struct {
+	StepReplaceOptions
+
+	CertFile          string
+	KeyFile           string
+	IngressNamespace  string
+	IngressDeployment string
+}
+
+var (
+	stepReplaceTLSLong = templates.LongDesc(`
+		This pipeline step command replaces the TLS certificate brought by the user for the ingress domain, such as
+		when it is renewed, then rolls the ingress controller so that it serves the new certificate
+
+		The certificate must have been configured via 'jx install --tls-cert-file'.
+
+`)
+
+	stepReplaceTLSExample = templates.Examples(`
+		jx step replace tls --tls-cert-file wildcard.crt --tls-key-file wildcard.key
+
+`)
+)
+
+// NewCmdStepReplaceTLS creates the command
+func NewCmdStepReplaceTLS(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := StepReplaceTLSOptions{
+		StepReplaceOptions: StepReplaceOptions{
+			StepOptions: StepOptions{
+				CommonOptions: CommonOptions{
+					Factory: f,
+					In:      in,
+					Out:     out,
+					Err:     errOut,
+				},
+			},
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "tls",
+		Short:   "Replaces the TLS certificate of the ingress domain",
+		Long:    stepReplaceTLSLong,
+		Example: stepReplaceTLSExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&options.CertFile, "tls-cert-file", "", "", "The PEM encoded wildcard TLS certificate of the domain")
+	cmd.Flags().StringVarP(&options.KeyFile, "tls-key-file", "", "", "The PEM encoded private key of the certificate")
+	cmd.Flags().StringVarP(&options.IngressNamespace, "ingress-namespace", "", "kube-system", "The namespace of the Ingress controller")
+	cmd.Flags().StringVarP(&options.IngressDeployment, "ingress-deployment", "", INGRESS_SERVICE_NAME, "The name of the Ingress controller Deployment")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepReplaceTLSOptions) Run() error {
+	if o.CertFile == "" {
+		return util.MissingOption("tls-cert-file")
+	}
+	if o.KeyFile == "" {
+		return util.MissingOption("tls-key-file")
+	}
+	client, _, err := o.KubeClient()
+	if err != nil {
+		return errors.Wrap(err, "failed to create the kube client")
+	}
+	jxClient, devNs, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return errors.Wrap(err, "failed to create the jx client")
+	}
+	ic, err := kube.GetIngressConfig(client, devNs)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load the ingress config of namespace %s", devNs)
+	}
+	if ic.TLSSecretName == "" {
+		return fmt.Errorf("no TLS certificate was configured for the domain %s, install with 'jx install --tls-cert-file' to use your own certificate", ic.Domain)
+	}
+
+	_, err = o.createTLSSecret(client, devNs, ic.Domain, o.CertFile, o.KeyFile)
+	if err != nil {
+		return err
+	}
+	envMap, _, err := kube.GetEnvironments(jxClient, devNs)
+	if err != nil {
+		return errors.Wrap(err, "failed to load the environments")
+	}
+	for _, env := range envMap {
+		envNs := env.Spec.Namespace
+		if envNs == "" || envNs == devNs {
+			continue
+		}
+		err = kube.CopyTLSSecret(client, devNs, envNs, ic.TLSSecretName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to replace the TLS secret in namespace %s", envNs)
+		}
+		log.Infof("Replaced the TLS secret %s in namespace %s\n", util.ColorInfo(ic.TLSSecretName), util.ColorInfo(envNs))
+	}
+
+	log.Infof("Rolling the ingress controller %s in namespace %s\n", util.ColorInfo(o.IngressDeployment), util.ColorInfo(o.IngressNamespace))
+	err = kube.RestartDeployment(client, o.IngressDeployment, o.IngressNamespace)
+	if err != nil {
+		return errors.Wrapf(err, "failed to roll the ingress controller %s", o.IngressDeployment)
+	}
+	err = kube.WaitForDeploymentToBeReady(client, o.IngressDeployment, o.IngressNamespace, 5*time.Minute)
+	if err != nil {
+		return err
+	}
+	log.Successf("Replaced the TLS certificate of the domain %s", ic.Domain)
+	return nil
+}
+
+// createTLSSecret validates the certificate and key files for the domain and saves them in the TLS secret of the
+// ingress domain
+func (o *CommonOptions) createTLSSecret(client kubernetes.Interface, ns string, domain string, certFile string, keyFile string) (*v1.Secret, error) {
+	cert, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the TLS certificate file %s", certFile)
+	}
+	key, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the TLS key file %s", keyFile)
+	}
+	err = kube.ValidateTLSCertificate(cert, key, domain)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := kube.CreateOrUpdateTLSSecret(client, ns, kube.SecretIngressTLS, cert, key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to save the TLS secret %s in namespace %s", kube.SecretIngressTLS, ns)
+	}
+	log.Infof("Saved the TLS certificate %s in secret %s of namespace %s\n", util.ColorInfo(certFile), util.ColorInfo(kube.SecretIngressTLS), util.ColorInfo(ns))
+	return secret, nil
+}
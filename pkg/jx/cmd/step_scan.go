@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+)
+
+// StepScanOptions contains the command line flags
+type StepScanOptions struct {
+	StepOptions
+}
+
+// NewCmdStepScan creates the command object for the "step scan" command
+func NewCmdStepScan(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepScanOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "scan",
+		Short: "scan step actions",
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+
+	cmd.AddCommand(NewCmdStepScanImage(f, in, out, errOut))
+
+	return cmd
+}
+
+// Run implements this command
+func (o *StepScanOptions) Run() error {
+	return o.Cmd.Help()
+}
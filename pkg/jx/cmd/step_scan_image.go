@@ -0,0 +1,296 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/builds"
+	"github.com/jenkins-x/jx/pkg/cloud/buckets"
+	"github.com/jenkins-x/jx/pkg/cve"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// defaultImageScanFailOn the lowest severity of the vulnerabilities which fail the pipeline if the team has no
+	// imagescanfailon setting
+	defaultImageScanFailOn = "CRITICAL"
+
+	// defaultTrivyIgnoreFile the allow-list of the vulnerabilities of the repository which are not reported
+	defaultTrivyIgnoreFile = ".jx/trivyignore"
+)
+
+var (
+	stepScanImageLong = templates.LongDesc(`
+		Scans an image built by the pipeline for vulnerabilities with Trivy: https://github.com/aquasecurity/trivy
+
+		Trivy is installed into ~/.jx/bin. The pipeline fails if any vulnerability has the severity of the '--fail-on' option
+		or a higher one, which defaults to the imagescanfailon team setting or critical.
+
+		Vulnerabilities which are listed in the .jx/trivyignore file of the repository, one ID per line, are ignored.
+
+		The image is pulled without verifying the TLS certificate of the registry if the dockerregistryinsecure team
+		setting is enabled.
+
+		The Jenkinsfile jx generates for projects which are imported without a build pack scans the image after it is
+		pushed. The build packs do not scan images yet so add this step after 'jx step post build' in the pipelines of
+		projects created from a build pack.
+
+		The report is stored next to the archived build log in the logs storage location of the team and a summary is
+		added to the PipelineActivity of the build as a Scan step.
+`)
+
+	stepScanImageExample = templates.Examples(`
+		# scan the image which was just pushed
+		jx step scan image --image $DOCKER_REGISTRY/$ORG/$APP_NAME:$VERSION
+
+		# fail the pipeline on high and critical vulnerabilities
+		jx step scan image --image $DOCKER_REGISTRY/$ORG/$APP_NAME:$VERSION --fail-on high
+
+		# fail the pipelines of the team on vulnerabilities of medium severity or higher by default
+		jx edit imagescanfailon medium
+`)
+)
+
+// StepScanImageOptions contains the command line flags
+type StepScanImageOptions struct {
+	StepOptions
+
+	Image      string
+	FailOn     string
+	Dir        string
+	IgnoreFile string
+}
+
+// NewCmdStepScanImage creates the command object for the "step scan image" command
+func NewCmdStepScanImage(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &StepScanImageOptions{
+		StepOptions: StepOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "image",
+		Short:   "Scans an image built by the pipeline for vulnerabilities",
+		Long:    stepScanImageLong,
+		Example: stepScanImageExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&options.Image, "image", "i", "", "The full image name to scan including the registry prefix and tag")
+	cmd.Flags().StringVarP(&options.FailOn, "fail-on", "", "", "The lowest severity of the vulnerabilities which fail the pipeline: unknown, low, medium, high or critical. Defaults to the imagescanfailon team setting or critical")
+	cmd.Flags().StringVarP(&options.Dir, "dir", "d", "", "The directory of the repository. Defaults to the current working directory")
+	cmd.Flags().StringVarP(&options.IgnoreFile, "ignore-file", "", defaultTrivyIgnoreFile, "The file of the repository which lists the IDs of the vulnerabilities to ignore")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepScanImageOptions) Run() error {
+	if o.Image == "" {
+		return util.MissingOption("image")
+	}
+	teamSettings, err := o.TeamSettings()
+	if err != nil {
+		return err
+	}
+	failOn, err := o.failOnSeverity(teamSettings)
+	if err != nil {
+		return err
+	}
+	dir := o.Dir
+	if dir == "" {
+		dir, err = os.Getwd()
+		if err != nil {
+			return err
+		}
+	}
+
+	trivy, err := o.installTrivy()
+	if err != nil {
+		return errors.Wrap(err, "failed to install trivy")
+	}
+	tmpDir, err := ioutil.TempDir("", "jx-scan-image-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	reportFile := filepath.Join(tmpDir, "report.json")
+	args, err := o.trivyArgs(teamSettings, dir, reportFile)
+	if err != nil {
+		return err
+	}
+	log.Infof("Scanning image %s for vulnerabilities\n", util.ColorInfo(o.Image))
+	err = o.runCommandVerbose(trivy, args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to scan the image %s", o.Image)
+	}
+	data, err := ioutil.ReadFile(reportFile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load the report of the scan of the image %s", o.Image)
+	}
+	report, err := cve.ParseTrivyReport(data)
+	if err != nil {
+		return err
+	}
+
+	found := report.VulnerabilitiesAtOrAbove(failOn)
+	if len(found) > 0 {
+		o.printVulnerabilities(found)
+	}
+	o.recordScan(teamSettings, dir, failOn, report, data)
+
+	summary := cve.SeveritySummary(report.SeverityCounts())
+	if len(found) > 0 {
+		return fmt.Errorf("found %d vulnerabilities of severity %s or higher in the image %s: %s", len(found), failOn, o.Image, summary)
+	}
+	log.Infof("No vulnerabilities of severity %s or higher in the image %s: %s\n", util.ColorInfo(failOn), util.ColorInfo(o.Image), summary)
+	return nil
+}
+
+// failOnSeverity returns the lowest severity of the vulnerabilities which fail the scan from the --fail-on option,
+// the team settings or the default
+func (o *StepScanImageOptions) failOnSeverity(teamSettings *v1.TeamSettings) (string, error) {
+	failOn := o.FailOn
+	if failOn == "" && teamSettings != nil {
+		failOn = teamSettings.ImageScanFailOn
+	}
+	if failOn == "" {
+		return defaultImageScanFailOn, nil
+	}
+	return cve.ParseSeverity(failOn)
+}
+
+// trivyArgs returns the arguments of trivy which write the JSON report of the image into the report file using the
+// allow-list of the repository if it exists and the registry settings of the team. The exit code is always zero so
+// that the report is recorded before the scan fails
+func (o *StepScanImageOptions) trivyArgs(teamSettings *v1.TeamSettings, dir string, reportFile string) ([]string, error) {
+	args := []string{"image", "--no-progress", "--exit-code", "0", "--format", "json", "--output", reportFile}
+	if teamSettings != nil && teamSettings.DockerRegistryInsecure {
+		args = append(args, "--insecure")
+	}
+	ignoreFile := o.IgnoreFile
+	if ignoreFile != "" {
+		if !filepath.IsAbs(ignoreFile) {
+			ignoreFile = filepath.Join(dir, ignoreFile)
+		}
+		exists, err := util.FileExists(ignoreFile)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			log.Infof("Ignoring the vulnerabilities listed in %s\n", util.ColorInfo(ignoreFile))
+			args = append(args, "--ignorefile", ignoreFile)
+		}
+	}
+	return append(args, o.Image), nil
+}
+
+func (o *StepScanImageOptions) printVulnerabilities(vulnerabilities []cve.TrivyVulnerability) {
+	table := o.CreateTable()
+	table.AddRow("ID", "SEVERITY", "PACKAGE", "INSTALLED", "FIXED", "TITLE")
+	for _, v := range vulnerabilities {
+		table.AddRow(v.VulnerabilityID, v.Severity, v.PkgName, v.InstalledVersion, v.FixedVersion, v.Title)
+	}
+	table.Render()
+}
+
+// recordScan stores the report in the logs storage location and adds the summary of the scan to the PipelineActivity
+// of the build. Failures are only logged as they do not change the result of the scan
+func (o *StepScanImageOptions) recordScan(teamSettings *v1.TeamSettings, dir string, failOn string, report *cve.TrivyReport, data []byte) {
+	gitInfo, err := o.FindGitInfo(dir)
+	if err != nil {
+		log.Warnf("Failed to find the git repository in %s: %s\n", dir, err)
+	}
+	appName := ""
+	if gitInfo != nil {
+		appName = gitInfo.Name
+	}
+	pipeline, build := o.getPipelineName(gitInfo, "", o.getBuildNumber(), appName)
+	if pipeline == "" || build == "" {
+		log.Infof("No pipeline and build number available on $JOB_NAME and $BUILD_NUMBER so cannot record the scan in the PipelineActivity\n")
+		return
+	}
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err == nil {
+		err = o.registerPipelineActivityCRD()
+	}
+	if err != nil {
+		log.Warnf("Failed to record the scan in the PipelineActivity: %s\n", err)
+		return
+	}
+	activities := jxClient.JenkinsV1().PipelineActivities(ns)
+	key := &kube.PipelineActivityKey{
+		Name:     kube.ToValidName(pipeline + "-" + build),
+		Pipeline: pipeline,
+		Build:    build,
+	}
+	activity, _, err := key.GetOrCreate(activities)
+	if err != nil {
+		log.Warnf("Failed to record the scan in the PipelineActivity %s: %s\n", key.Name, err)
+		return
+	}
+	reportURL := o.storeReport(teamSettings, activity, data)
+	err = key.OnScan(activities, o.Image, completeScan(failOn, report, reportURL))
+	if err != nil {
+		log.Warnf("Failed to record the scan in the PipelineActivity %s: %s\n", key.Name, err)
+	}
+}
+
+// storeReport writes the report next to the archived build log of the activity returning its URL or an empty string
+// if the team has no logs storage location
+func (o *StepScanImageOptions) storeReport(teamSettings *v1.TeamSettings, activity *v1.PipelineActivity, data []byte) string {
+	location := teamSettings.StorageLocation(StorageClassifierLogs)
+	reportPath := builds.ArchivedImageScanReportPath(activity)
+	if location == nil || location.BucketURL == "" || reportPath == "" {
+		log.Infof("No logs storage location so the report of the scan is not stored, see: jx install storage\n")
+		return ""
+	}
+	reportURL := strings.TrimSuffix(location.BucketURL, "/") + "/" + reportPath
+	err := buckets.WriteObject(reportURL, data, "", "")
+	if err != nil {
+		log.Warnf("Failed to store the report of the scan: %s\n", err)
+		return ""
+	}
+	log.Infof("Stored the report of the scan at %s\n", util.ColorInfo(reportURL))
+	return reportURL
+}
+
+// completeScan returns the function which records the result of the scan in the Scan step of the PipelineActivity
+func completeScan(failOn string, report *cve.TrivyReport, reportURL string) kube.ScanFn {
+	return func(a *v1.PipelineActivity, s *v1.PipelineActivityStep, scan *v1.ScanActivityStep) error {
+		counts := report.SeverityCounts()
+		scan.FailOn = failOn
+		scan.Vulnerabilities = counts
+		scan.ReportURL = reportURL
+		scan.Description = cve.SeveritySummary(counts)
+		scan.CompletedTimestamp = &metav1.Time{
+			Time: time.Now(),
+		}
+		scan.Status = v1.ActivityStatusTypeSucceeded
+		if len(report.VulnerabilitiesAtOrAbove(failOn)) > 0 {
+			scan.Status = v1.ActivityStatusTypeFailed
+		}
+		return nil
+	}
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/cve"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepScanImageFailOnSeverity(t *testing.T) {
+	t.Parallel()
+	o := &StepScanImageOptions{}
+	failOn, err := o.failOnSeverity(&v1.TeamSettings{})
+	require.NoError(t, err)
+	assert.Equal(t, "CRITICAL", failOn, "the default severity")
+
+	failOn, err = o.failOnSeverity(&v1.TeamSettings{ImageScanFailOn: "medium"})
+	require.NoError(t, err)
+	assert.Equal(t, "MEDIUM", failOn, "the severity of the team settings")
+
+	o.FailOn = "high"
+	failOn, err = o.failOnSeverity(&v1.TeamSettings{ImageScanFailOn: "medium"})
+	require.NoError(t, err)
+	assert.Equal(t, "HIGH", failOn, "the --fail-on option should override the team settings")
+
+	o.FailOn = "severe"
+	_, err = o.failOnSeverity(nil)
+	assert.Error(t, err)
+}
+
+func TestStepScanImageTrivyArgsUseTheIgnoreFileOfTheRepository(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-step-scan-image-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	o := &StepScanImageOptions{
+		Image:      "docker.io/myorg/myapp:1.0.1",
+		IgnoreFile: defaultTrivyIgnoreFile,
+	}
+	args, err := o.trivyArgs(&v1.TeamSettings{}, dir, "report.json")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"image", "--no-progress", "--exit-code", "0", "--format", "json", "--output", "report.json",
+		"docker.io/myorg/myapp:1.0.1"}, args)
+
+	ignoreFile := filepath.Join(dir, ".jx", "trivyignore")
+	require.NoError(t, os.MkdirAll(filepath.Dir(ignoreFile), 0755))
+	require.NoError(t, ioutil.WriteFile(ignoreFile, []byte("CVE-2018-1000001\n"), 0644))
+	args, err = o.trivyArgs(&v1.TeamSettings{}, dir, "report.json")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"image", "--no-progress", "--exit-code", "0", "--format", "json", "--output", "report.json",
+		"--ignorefile", ignoreFile, "docker.io/myorg/myapp:1.0.1"}, args)
+
+	args, err = o.trivyArgs(&v1.TeamSettings{DockerRegistryInsecure: true}, dir, "report.json")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"image", "--no-progress", "--exit-code", "0", "--format", "json", "--output", "report.json",
+		"--insecure", "--ignorefile", ignoreFile, "docker.io/myorg/myapp:1.0.1"}, args)
+}
+
+func TestStepScanImageCompleteScan(t *testing.T) {
+	t.Parallel()
+	report := &cve.TrivyReport{
+		Results: []cve.TrivyResult{
+			{
+				Target: "alpine",
+				Vulnerabilities: []cve.TrivyVulnerability{
+					{VulnerabilityID: "CVE-1", Severity: "HIGH"},
+					{VulnerabilityID: "CVE-2", Severity: "LOW"},
+				},
+			},
+		},
+	}
+	scan := &v1.ScanActivityStep{}
+	err := completeScan("CRITICAL", report, "gs://logs/report.json")(nil, nil, scan)
+	require.NoError(t, err)
+	assert.Equal(t, v1.ActivityStatusTypeSucceeded, scan.Status)
+	assert.Equal(t, "CRITICAL", scan.FailOn)
+	assert.Equal(t, map[string]int{"HIGH": 1, "LOW": 1}, scan.Vulnerabilities)
+	assert.Equal(t, "1 HIGH, 1 LOW", scan.Description)
+	assert.Equal(t, "gs://logs/report.json", scan.ReportURL)
+	assert.NotNil(t, scan.CompletedTimestamp)
+
+	scan = &v1.ScanActivityStep{}
+	err = completeScan("HIGH", report, "")(nil, nil, scan)
+	require.NoError(t, err)
+	assert.Equal(t, v1.ActivityStatusTypeFailed, scan.Status)
+}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -38,6 +37,10 @@ type StepTagFlags struct {
 	VersionFile          string
 	ChartsDir            string
 	ChartValueRepository string
+	Sign                 bool
+	SemVer               string
+	PreRelease           string
+	BuildMetadata        string
 }
 
 var (
@@ -51,12 +54,25 @@ var (
 		git tag -fa v$(VERSION) -m "Release version $(VERSION)"
 		git push origin v$(VERSION)
 
+		The tag is signed with 'git tag -s' instead when the '--sign' option is specified or git is configured to sign
+		tags, e.g. by 'jx step git credentials --sign'.
+
+		Without a version or a VERSION file, or with the '--semver' or '--pre-release' options, the next version is
+		worked out from the git tags like 'jx step next-version --use-git-tag-only'. A commit which was already
+		released is not committed and tagged again, only its tag is pushed.
+
 `)
 
 	stepTagExample = templates.Examples(`
 
 		jx step tag --version 1.0.0
 
+		# create a signed tag
+		jx step tag --version 1.0.0 --sign
+
+		# tag the next minor version of the latest tag with the build number as build metadata
+		jx step tag --semver minor --build-metadata $BUILD_NUMBER
+
 `)
 )
 
@@ -84,17 +100,19 @@ func NewCmdStepTag(f Factory, in terminal.FileReader, out terminal.FileWriter, e
 		},
 	}
 
-	cmd.Flags().StringVarP(&options.Flags.Version, VERSION, "v", "", "version number for the tag [required]")
+	cmd.Flags().StringVarP(&options.Flags.Version, VERSION, "v", "", "version number for the tag. Defaults to the version file or the next version of the git tags")
 	cmd.Flags().StringVarP(&options.Flags.VersionFile, "version-file", "", defaultVersionFile, "The file name used to load the version number from if no '--version' option is specified")
 
 	cmd.Flags().StringVarP(&options.Flags.ChartsDir, "charts-dir", "d", "", "the directory of the chart to update the version")
 	cmd.Flags().StringVarP(&options.Flags.ChartValueRepository, "charts-value-repository", "r", "", "the fully qualified image name without the version tag. e.g. 'dockerregistry/myorg/myapp'")
+	cmd.Flags().BoolVarP(&options.Flags.Sign, "sign", "", false, "signs the tag with the signing key of the git configuration")
+	addSemVerFlags(cmd, &options.Flags.SemVer, &options.Flags.PreRelease, &options.Flags.BuildMetadata)
 
 	return cmd
 }
 
 func (o *StepTagOptions) Run() error {
-	if o.Flags.Version == "" {
+	if o.Flags.Version == "" && o.Flags.SemVer == "" && o.Flags.PreRelease == "" {
 		// lets see if its defined in the VERSION file
 		path := o.Flags.VersionFile
 		if path == "" {
@@ -110,30 +128,33 @@ func (o *StepTagOptions) Run() error {
 		}
 	}
 	if o.Flags.Version == "" {
-		return errors.New("No version flag")
-	}
-	chartsDir := o.Flags.ChartsDir
-	if chartsDir != "" {
-		err := o.updateChart(o.Flags.Version, chartsDir)
-		if err != nil {
-			return err
+		nextVersion := &StepNextVersionOptions{
+			UseGitTagOnly: true,
+			SemVer:        o.Flags.SemVer,
+			PreRelease:    o.Flags.PreRelease,
+			StepOptions:   o.StepOptions,
 		}
-		err = o.updateChartValues(o.Flags.Version, chartsDir)
+		version, err := nextVersion.nextVersion()
 		if err != nil {
 			return err
 		}
+		o.Flags.Version = version
 	}
+	o.Flags.Version = withBuildMetadata(o.Flags.Version, o.Flags.BuildMetadata)
 
 	tag := "v" + o.Flags.Version
 
-	err := o.Git().AddCommmit("", fmt.Sprintf("release %s", o.Flags.Version))
+	releaseTags, err := o.Git().ReleaseTags("")
 	if err != nil {
 		return err
 	}
-
-	err = o.Git().CreateTag("", tag, fmt.Sprintf("release %s", o.Flags.Version))
-	if err != nil {
-		return err
+	if util.StringArrayIndex(releaseTags, tag) >= 0 {
+		log.Infof("The current commit was already released as %s\n", util.ColorInfo(tag))
+	} else {
+		err = o.createTag(tag)
+		if err != nil {
+			return err
+		}
 	}
 
 	err = o.Git().PushTag("", tag)
@@ -145,6 +166,37 @@ func (o *StepTagOptions) Run() error {
 	return nil
 }
 
+// createTag updates the chart, commits the release and creates its tag
+func (o *StepTagOptions) createTag(tag string) error {
+	chartsDir := o.Flags.ChartsDir
+	if chartsDir != "" {
+		err := o.updateChart(o.Flags.Version, chartsDir)
+		if err != nil {
+			return err
+		}
+		err = o.updateChartValues(o.Flags.Version, chartsDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := o.Git().AddCommmit("", fmt.Sprintf("release %s", o.Flags.Version))
+	if err != nil {
+		return err
+	}
+
+	if o.Flags.Sign || o.gitSignsTags() {
+		return o.Git().CreateSignedTag("", tag, fmt.Sprintf("release %s", o.Flags.Version))
+	}
+	return o.Git().CreateTag("", tag, fmt.Sprintf("release %s", o.Flags.Version))
+}
+
+// gitSignsTags returns true if git is configured to sign tags
+func (o *StepTagOptions) gitSignsTags() bool {
+	value, err := o.getCommandOutput("", "git", "config", "--get", "tag.gpgsign")
+	return err == nil && value == "true"
+}
+
 func (o *StepTagOptions) updateChart(version string, chartsDir string) error {
 	chartFile := filepath.Join(chartsDir, "Chart.yaml")
 
@@ -71,3 +71,63 @@ func TestStepTagCharts(t *testing.T) {
 	assert.True(t, foundRepo, "Failed to find tag '%s' in file %s", cmd.ValuesYamlRepositoryPrefix, valuesFile)
 	assert.True(t, foundVersion, "Failed to find tag '%s' in file %s", cmd.ValuesYamlTagPrefix, valuesFile)
 }
+
+func TestStepTagSigned(t *testing.T) {
+	t.Parallel()
+	gitter := &gits.GitFake{}
+	o := cmd.StepTagOptions{}
+	o.Flags.Version = "1.0.0"
+	o.Flags.Sign = true
+	o.GitClient = gitter
+	err := o.Run()
+	assert.NoError(t, err)
+
+	assert.Equal(t, []gits.GitTag{{Name: "v1.0.0", Message: "release 1.0.0", Signed: true}}, gitter.GitTags)
+}
+
+func TestStepTagNextPreRelease(t *testing.T) {
+	t.Parallel()
+	gitter := &gits.GitFake{
+		GitTags: []gits.GitTag{{Name: "v1.2.3"}, {Name: "v1.3.0-rc.1"}, {Name: "not-a-version"}},
+	}
+	o := cmd.StepTagOptions{}
+	o.Flags.SemVer = "minor"
+	o.Flags.PreRelease = "rc"
+	o.Flags.BuildMetadata = "build.42"
+	o.GitClient = gitter
+	err := o.Run()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "v1.3.0-rc.2+build.42", gitter.GitTags[len(gitter.GitTags)-1].Name)
+}
+
+func TestStepTagAutoSemVer(t *testing.T) {
+	t.Parallel()
+	gitter := &gits.GitFake{
+		GitTags: []gits.GitTag{{Name: "v1.2.3"}},
+		Commits: []gits.GitCommit{{Message: "fix: a bug"}, {Message: "feat(ui): a feature"}},
+	}
+	o := cmd.StepTagOptions{}
+	o.Flags.SemVer = "auto"
+	o.GitClient = gitter
+	err := o.Run()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "v1.3.0", gitter.GitTags[len(gitter.GitTags)-1].Name)
+}
+
+func TestStepTagAlreadyReleased(t *testing.T) {
+	t.Parallel()
+	gitter := &gits.GitFake{
+		GitTags:  []gits.GitTag{{Name: "v1.0.0", Message: "release 1.0.0"}},
+		HeadTags: []string{"v1.0.0"},
+	}
+	o := cmd.StepTagOptions{}
+	o.Flags.SemVer = "patch"
+	o.GitClient = gitter
+	err := o.Run()
+	assert.NoError(t, err)
+
+	assert.Equal(t, []gits.GitTag{{Name: "v1.0.0", Message: "release 1.0.0"}}, gitter.GitTags, "the release should not be tagged again")
+	assert.Empty(t, gitter.Commits, "the release should not be committed again")
+}
@@ -6,14 +6,17 @@ import (
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/kballard/go-shellquote"
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
 	"k8s.io/client-go/kubernetes"
 
+	"github.com/jenkins-x/jx/pkg/filesync"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/log"
@@ -35,6 +38,9 @@ type SyncOptions struct {
 	RemoteDir string
 	Reload    bool
 	WatchOnly bool
+	Excludes  []string
+	GitIgnore bool
+	Watch     bool
 
 	stopCh chan struct{}
 }
@@ -43,6 +49,12 @@ var (
 	sync_long = templates.LongDesc(`
 		Synchronises your local files to a DevPod so you an build and test your code easily on the cloud
 
+		Files matching the gitignore patterns in the .jxsyncignore file of the directory are not synchronised. If there is
+		no .jxsyncignore file then the patterns of the .gitignore file and some common build output directories such as
+		node_modules and target are ignored. Use --exclude to ignore more files.
+
+		Use --watch to synchronise only the files which change, without using ksync.
+
 		For more documentation see: [https://jenkins-x.io/developing/devpods/](https://jenkins-x.io/developing/devpods/)
 
 `)
@@ -50,17 +62,10 @@ var (
 	sync_example = templates.Examples(`
 		# Starts synchronizing the current directory files to the users DevPod
 		jx sync 
-`)
 
-	defaultStignoreFile = `.git
-.idea
-.settings
-.vscode
-bin
-build
-target
-node_modules
-`
+		# Synchronises the changed files only without the generated code
+		jx sync --watch --exclude "src/generated/"
+`)
 )
 
 func NewCmdSync(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
@@ -95,6 +100,9 @@ func NewCmdSync(f Factory, in terminal.FileReader, out terminal.FileWriter, errO
 	cmd.Flags().BoolVarP(&options.Daemon, "daemon", "", false, "Runs ksync in a background daemon")
 	cmd.Flags().BoolVarP(&options.NoKsyncInit, "no-init", "", false, "Disables the use of 'ksync init' to ensure we have initialised ksync")
 	cmd.Flags().BoolVarP(&options.SingleMode, "single-mode", "", false, "Terminates eagerly if `ksync watch` fails")
+	cmd.Flags().StringArrayVarP(&options.Excludes, "exclude", "x", []string{}, "The gitignore patterns of more files which are not synchronised")
+	cmd.Flags().BoolVarP(&options.GitIgnore, "gitignore", "", false, "Also ignores the files of the .gitignore file when there is a "+filesync.IgnoreFile+" file")
+	cmd.Flags().BoolVarP(&options.Watch, "watch", "w", false, "Watches the local directory and synchronises the changed files only, without using ksync")
 
 	// deprecated
 	cmd.Flags().BoolVarP(&options.WatchOnly, "watch-only", "", false, "Deprecated this flag is now ignored!")
@@ -106,10 +114,23 @@ func (o *SyncOptions) Run() error {
 	// ksync is installed to the jx/bin dir, so we can add it for the user
 	os.Setenv("PATH", util.PathWithBinary())
 
-	client, _, err := o.KubeClient()
+	client, curNs, err := o.KubeClient()
+	if err != nil {
+		return err
+	}
+	ns, _, err := kube.GetDevNamespace(client, curNs)
+	if err != nil {
+		return err
+	}
+	userName, err := o.getUsername("")
 	if err != nil {
 		return err
 	}
+	go o.recordSyncActivity(client, ns, userName)
+
+	if o.Watch {
+		return o.watchDevPod(client, ns, userName)
+	}
 	_, err = o.installKSync()
 	if err != nil {
 		return err
@@ -137,6 +158,23 @@ func (o *SyncOptions) Run() error {
 	}
 }
 
+// recordSyncActivity periodically records the activity in the DevPods of the user which are synchronised with a local
+// directory so that they are not garbage collected while they are in use
+func (o *SyncOptions) recordSyncActivity(client kubernetes.Interface, ns string, userName string) {
+	for {
+		names, pods, err := kube.GetDevPodNames(client, ns, userName)
+		for _, name := range names {
+			if err == nil && pods[name].Annotations[kube.AnnotationLocalDir] != "" {
+				err = kube.UpdateDevPodLastActivity(client, ns, name, time.Now())
+			}
+		}
+		if err != nil {
+			log.Warnf("Failed to record the activity of the synchronised DevPods: %s\n", err)
+		}
+		time.Sleep(time.Minute)
+	}
+}
+
 func (o *SyncOptions) waitForKsyncWatchToFail() {
 	logged := false
 	for {
@@ -188,17 +226,10 @@ func (o *SyncOptions) CreateKsync(client kubernetes.Interface, ns string, name s
 	info := util.ColorInfo
 	log.Infof("synchronizing directory %s to DevPod %s path %s\n", info(dir), info(name), info(remoteDir))
 
-	ignoreFile := filepath.Join(dir, ".stignore")
-	exists, err := util.FileExists(ignoreFile)
+	err := o.writeStignoreFile(dir)
 	if err != nil {
 		return err
 	}
-	if !exists {
-		err = ioutil.WriteFile(ignoreFile, []byte(defaultStignoreFile), DefaultWritePermissions)
-		if err != nil {
-			return err
-		}
-	}
 	matchLabels := map[string]string{
 		kube.LabelDevPodUsername: username,
 	}
@@ -256,6 +287,156 @@ func (o *SyncOptions) CreateKsync(client kubernetes.Interface, ns string, name s
 	return o.RunCommand("ksync", "create", "--name", name, "-l", "jenkins.io/devpod="+name, reload, "-n", ns, dir, remoteDir)
 }
 
+// writeStignoreFile writes the ignore rules of the directory into the .stignore file used by ksync keeping any rules
+// the user has added to it
+func (o *SyncOptions) writeStignoreFile(dir string) error {
+	ignorer, err := filesync.NewIgnorer(dir, o.Excludes, o.GitIgnore)
+	if err != nil {
+		return err
+	}
+	stignoreFile := filepath.Join(dir, ".stignore")
+	existing := ""
+	exists, err := util.FileExists(stignoreFile)
+	if err != nil {
+		return err
+	}
+	if exists {
+		data, err := ioutil.ReadFile(stignoreFile)
+		if err != nil {
+			return err
+		}
+		existing = string(data)
+	}
+	return ioutil.WriteFile(stignoreFile, []byte(ignorer.MergeStignore(existing)), DefaultWritePermissions)
+}
+
+// watchDevPod synchronises the local directory to the DevPod of the user then watches it synchronising only the files
+// which change
+func (o *SyncOptions) watchDevPod(client kubernetes.Interface, ns string, userName string) error {
+	dir := o.Dir
+	if dir == "" {
+		var err error
+		dir, err = os.Getwd()
+		if err != nil {
+			return err
+		}
+	}
+	names, pods, err := kube.GetDevPodNames(client, ns, userName)
+	if err != nil {
+		return err
+	}
+	name := o.Pod
+	if util.StringArrayIndex(names, name) < 0 {
+		name = ""
+		for _, n := range names {
+			if pods[n].Annotations[kube.AnnotationLocalDir] == dir {
+				name = n
+				break
+			}
+		}
+		if name == "" {
+			if len(names) == 0 {
+				return fmt.Errorf("There are no DevPods for user %s in namespace %s. You can create one via: %s", userName, ns, util.ColorInfo("jx create devpod"))
+			}
+			name, err = util.PickName(names, "Pick DevPod:", o.In, o.Out, o.Err)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	pod := pods[name]
+	remoteDir := o.RemoteDir
+	if remoteDir == "" {
+		remoteDir = pod.Annotations[kube.AnnotationWorkingDir]
+		if remoteDir == "" {
+			remoteDir = "/workspace"
+		}
+	}
+	container := o.Container
+	if container == "" && len(pod.Spec.Containers) > 0 {
+		container = pod.Spec.Containers[0].Name
+	}
+
+	ignorer, err := filesync.NewIgnorer(dir, o.Excludes, o.GitIgnore)
+	if err != nil {
+		return err
+	}
+	info := util.ColorInfo
+	log.Infof("synchronizing directory %s to DevPod %s path %s\n", info(dir), info(name), info(remoteDir))
+	files, size, err := filesync.Files(dir, ignorer)
+	if err != nil {
+		return err
+	}
+	progress := filesync.NewProgress(o.Out, size)
+	err = o.copyToDevPod(ns, name, container, dir, remoteDir, files, progress)
+	if err != nil {
+		return err
+	}
+	progress.Done()
+
+	watcher, err := filesync.NewWatcher(dir, ignorer, filesync.DefaultDebounce)
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+	log.Infof("Watching %s for changes\n", info(dir))
+	return watcher.Run(o.stopCh, func(changes *filesync.Changes) error {
+		if len(changes.Updated) > 0 {
+			err := o.copyToDevPod(ns, name, container, dir, remoteDir, changes.Updated, nil)
+			if err != nil {
+				return err
+			}
+		}
+		if len(changes.Removed) > 0 {
+			args := []string{"rm", "-rf"}
+			for _, rel := range changes.Removed {
+				args = append(args, path.Join(remoteDir, rel))
+			}
+			err := o.execInDevPod(ns, name, container, nil, args...)
+			if err != nil {
+				return err
+			}
+		}
+		log.Infof("Synchronised %s updated and %s removed files\n", info(len(changes.Updated)), info(len(changes.Removed)))
+		err := kube.UpdateDevPodLastActivity(client, ns, name, time.Now())
+		if err != nil {
+			log.Warnf("Failed to record the activity of DevPod %s: %s\n", name, err)
+		}
+		return nil
+	})
+}
+
+// copyToDevPod copies the files of the local directory to the remote directory of the DevPod as a tar archive
+func (o *SyncOptions) copyToDevPod(ns string, name string, container string, dir string, remoteDir string, files []string, progress io.Writer) error {
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(filesync.WriteTar(writer, dir, files, progress))
+	}()
+	defer reader.Close()
+	quotedDir := shellquote.Join(remoteDir)
+	return o.execInDevPod(ns, name, container, reader, "sh", "-c", fmt.Sprintf("mkdir -p %s && tar xf - -C %s", quotedDir, quotedDir))
+}
+
+// execInDevPod runs the command in the container of the DevPod reading its input from the optional reader
+func (o *SyncOptions) execInDevPod(ns string, name string, container string, in io.Reader, command ...string) error {
+	args := []string{"exec", "-n", ns, name}
+	if container != "" {
+		args = append(args, "-c", container)
+	}
+	if in != nil {
+		args = append(args, "-i")
+	}
+	args = append(args, "--")
+	args = append(args, command...)
+	cmd := exec.Command("kubectl", args...)
+	cmd.Stdin = in
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to run %s in DevPod %s: %s: %s", strings.Join(command, " "), name, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
 func (o *SyncOptions) killWatchProcess(cmd *exec.Cmd) {
 	if err := cmd.Process.Kill(); err != nil {
 		log.Warnf("failed to kill 'ksync watch' process: %s\n", err)
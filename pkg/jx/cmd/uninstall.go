@@ -3,11 +3,17 @@ package cmd
 import (
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/pkg/errors"
 
 	"github.com/spf13/cobra"
 
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/cloud/buckets"
+	"github.com/jenkins-x/jx/pkg/cloud/gke"
+	"github.com/jenkins-x/jx/pkg/gits"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/log"
@@ -15,22 +21,43 @@ import (
 	"gopkg.in/AlecAivazis/survey.v1"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 type UninstallOptions struct {
 	CommonOptions
 
-	Namespace        string
-	Confirm          bool
-	KeepEnvironments bool
+	Namespace             string
+	Confirm               bool
+	KeepEnvironments      bool
+	KeepCRDs              bool
+	PurgeEnvironmentRepos bool
+	PurgeCloudResources   bool
+}
+
+// uninstallItem a resource which is removed by the uninstall
+type uninstallItem struct {
+	kind   string
+	name   string
+	remove func() error
 }
 
 var (
 	uninstall_long = templates.LongDesc(`
-		Uninstalls the Jenkins X platform from a Kubernetes cluster`)
+		Uninstalls the Jenkins X platform from a Kubernetes cluster.
+
+		Lists everything which is removed before asking for confirmation then reports whether each item was removed.
+		The environment git repositories and the cloud buckets and service accounts jx created are only deleted
+		when purging them.`)
 	uninstall_example = templates.Examples(`
 		# Uninstall the Jenkins X platform
-		jx uninstall`)
+		jx uninstall
+
+		# Uninstall the Jenkins X platform and delete the environment git repositories and cloud resources
+		jx uninstall --purge-environment-repos --purge-cloud-resources
+
+		# Uninstall a team from a cluster shared with other teams
+		jx uninstall --keep-crds`)
 )
 
 func NewCmdUninstall(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
@@ -59,10 +86,16 @@ func NewCmdUninstall(f Factory, in terminal.FileReader, out terminal.FileWriter,
 	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The team namespace to uninstall. Defaults to the current namespace.")
 	cmd.Flags().BoolVarP(&options.Confirm, "yes", "y", false, "Confirms we should uninstall this installation")
 	cmd.Flags().BoolVarP(&options.KeepEnvironments, "keep-environments", "", false, "Don't delete environments. Uninstall Jenkins X only.")
+	cmd.Flags().BoolVarP(&options.KeepCRDs, "keep-crds", "", false, "Don't delete the Jenkins X custom resource definitions which are shared by all the teams of the cluster")
+	cmd.Flags().BoolVarP(&options.PurgeEnvironmentRepos, "purge-environment-repos", "", false, "Deletes the git repositories of the environments")
+	cmd.Flags().BoolVarP(&options.PurgeCloudResources, "purge-cloud-resources", "", false, "Deletes the cloud buckets and service accounts jx created for the team")
 	return cmd
 }
 
 func (o *UninstallOptions) Run() error {
+	if o.BatchMode && !o.Confirm {
+		return fmt.Errorf("In batch mode you must specify the '-y' flag to confirm")
+	}
 	surveyOpts := survey.WithStdio(o.In, o.Out, o.Err)
 	config, _, err := kube.LoadConfig()
 	if err != nil {
@@ -72,16 +105,26 @@ func (o *UninstallOptions) Run() error {
 	if err != nil {
 		return err
 	}
+	client, _, err := o.KubeClient()
+	if err != nil {
+		return errors.Wrap(err, "failed to get the kube client")
+	}
 	server := kube.CurrentServer(config)
 	namespace := o.Namespace
 	if namespace == "" {
 		namespace = kube.CurrentNamespace(config)
 	}
-	if o.BatchMode {
-		if !o.Confirm {
-			return fmt.Errorf("In batch mode you must specify the '-y' flag to confirm")
-		}
-	} else {
+
+	items := o.inventory(client, jxClient, namespace)
+	log.Infof("Uninstalling Jenkins X from team namespace %s removes:\n", util.ColorInfo(namespace))
+	table := o.CreateTable()
+	table.AddRow("KIND", "NAME")
+	for _, item := range items {
+		table.AddRow(item.kind, item.name)
+	}
+	table.Render()
+
+	if !o.BatchMode {
 		confirm := &survey.Confirm{
 			Message: fmt.Sprintf("Are you sure you wish to remove the Jenkins X platform from the '%s' namespace on cluster '%s'? :", namespace, server),
 			Default: false,
@@ -97,72 +140,359 @@ func (o *UninstallOptions) Run() error {
 	}
 	log.Infof("Removing installation of Jenkins X in team namespace %s\n", util.ColorInfo(namespace))
 
-	err = o.cleanupConfig()
-	if err != nil {
-		return err
+	failed := 0
+	for _, item := range items {
+		err := item.remove()
+		if err != nil {
+			log.Warnf("Failed to remove %s %s: %s\n", item.kind, item.name, err)
+			failed++
+			continue
+		}
+		log.Successf("Removed %s %s", item.kind, item.name)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to remove %d of the %d items of the Jenkins X installation in team namespace %s", failed, len(items), namespace)
 	}
-	envNames, err := kube.GetEnvironmentNames(jxClient, namespace)
+	log.Successf("Jenkins X has been successfully uninstalled from team namespace %s", namespace)
+	return nil
+}
+
+// inventory returns the items to remove in the order they are removed so that the webhooks, repositories and
+// cloud resources are found before the resources recording them are deleted. The git providers are only looked up
+// when the items are removed as they may prompt for credentials
+func (o *UninstallOptions) inventory(client kubernetes.Interface, jxClient versioned.Interface, namespace string) []*uninstallItem {
+	items := []*uninstallItem{}
+	envMap, envNames, err := kube.GetEnvironments(jxClient, namespace)
 	if err != nil {
 		log.Warnf("Failed to find Environments. Probably not installed yet?. Error: %s\n", err)
 	}
+
+	items = append(items, o.webHookItems(client, jxClient, namespace, envMap)...)
+	if o.PurgeEnvironmentRepos && !o.KeepEnvironments {
+		items = append(items, o.environmentRepositoryItems(envMap)...)
+	}
+	if o.PurgeCloudResources {
+		items = append(items, o.cloudResourceItems(client, namespace, envMap)...)
+	}
+
+	releases := []string{}
 	if !o.KeepEnvironments {
 		for _, env := range envNames {
-			release := namespace + "-" + env
-			err := o.Helm().StatusRelease(namespace, release)
-			if err != nil {
-				continue
-			}
-			err = o.Helm().DeleteRelease(namespace, release, true)
-			if err != nil {
-				log.Warnf("Failed to uninstall environment chart %s: %s\n", release, err)
+			releases = append(releases, namespace+"-"+env)
+		}
+	}
+	releases = append(releases, "jx-prow", "jenkins-x")
+	for _, release := range releases {
+		release := release
+		if o.Helm().StatusRelease(namespace, release) != nil {
+			continue
+		}
+		items = append(items, &uninstallItem{
+			kind: "helm release",
+			name: release,
+			remove: func() error {
+				return o.Helm().DeleteRelease(namespace, release, true)
+			},
+		})
+	}
+
+	items = append(items, customResourceItems(jxClient, namespace)...)
+
+	namespaces := []string{namespace}
+	if !o.KeepEnvironments {
+		for _, env := range envNames {
+			namespaces = append(namespaces, namespace+"-"+env)
+		}
+	}
+	for _, ns := range namespaces {
+		ns := ns
+		_, err := client.CoreV1().Namespaces().Get(ns, meta_v1.GetOptions{})
+		if err != nil {
+			continue
+		}
+		items = append(items, &uninstallItem{
+			kind: "namespace",
+			name: ns,
+			remove: func() error {
+				return o.deleteNamespace(ns)
+			},
+		})
+	}
+
+	if !o.KeepCRDs {
+		items = append(items, o.crdItems()...)
+	}
+	items = append(items, o.configItems()...)
+	return items
+}
+
+// customResourceItems returns the custom resources of each kind in the team namespace
+func customResourceItems(jxClient versioned.Interface, namespace string) []*uninstallItem {
+	api := jxClient.JenkinsV1()
+	resources := []struct {
+		name             string
+		count            func() (int, error)
+		deleteCollection func(*meta_v1.DeleteOptions, meta_v1.ListOptions) error
+	}{
+		{
+			name: "environments",
+			count: func() (int, error) {
+				list, err := api.Environments(namespace).List(meta_v1.ListOptions{})
+				if err != nil {
+					return 0, err
+				}
+				return len(list.Items), nil
+			},
+			deleteCollection: api.Environments(namespace).DeleteCollection,
+		},
+		{
+			name: "environmentrolebindings",
+			count: func() (int, error) {
+				list, err := api.EnvironmentRoleBindings(namespace).List(meta_v1.ListOptions{})
+				if err != nil {
+					return 0, err
+				}
+				return len(list.Items), nil
+			},
+			deleteCollection: api.EnvironmentRoleBindings(namespace).DeleteCollection,
+		},
+		{
+			name: "pipelineactivities",
+			count: func() (int, error) {
+				list, err := api.PipelineActivities(namespace).List(meta_v1.ListOptions{})
+				if err != nil {
+					return 0, err
+				}
+				return len(list.Items), nil
+			},
+			deleteCollection: api.PipelineActivities(namespace).DeleteCollection,
+		},
+		{
+			name: "releases",
+			count: func() (int, error) {
+				list, err := api.Releases(namespace).List(meta_v1.ListOptions{})
+				if err != nil {
+					return 0, err
+				}
+				return len(list.Items), nil
+			},
+			deleteCollection: api.Releases(namespace).DeleteCollection,
+		},
+		{
+			name: "gitservices",
+			count: func() (int, error) {
+				list, err := api.GitServices(namespace).List(meta_v1.ListOptions{})
+				if err != nil {
+					return 0, err
+				}
+				return len(list.Items), nil
+			},
+			deleteCollection: api.GitServices(namespace).DeleteCollection,
+		},
+		{
+			name: "teams",
+			count: func() (int, error) {
+				list, err := api.Teams(namespace).List(meta_v1.ListOptions{})
+				if err != nil {
+					return 0, err
+				}
+				return len(list.Items), nil
+			},
+			deleteCollection: api.Teams(namespace).DeleteCollection,
+		},
+		{
+			name: "users",
+			count: func() (int, error) {
+				list, err := api.Users(namespace).List(meta_v1.ListOptions{})
+				if err != nil {
+					return 0, err
+				}
+				return len(list.Items), nil
+			},
+			deleteCollection: api.Users(namespace).DeleteCollection,
+		},
+		{
+			name: "workflows",
+			count: func() (int, error) {
+				list, err := api.Workflows(namespace).List(meta_v1.ListOptions{})
+				if err != nil {
+					return 0, err
+				}
+				return len(list.Items), nil
+			},
+			deleteCollection: api.Workflows(namespace).DeleteCollection,
+		},
+	}
+	items := []*uninstallItem{}
+	for _, resource := range resources {
+		deleteCollection := resource.deleteCollection
+		count, err := resource.count()
+		if err != nil || count == 0 {
+			continue
+		}
+		items = append(items, &uninstallItem{
+			kind: "custom resources",
+			name: fmt.Sprintf("%s.jenkins.io in %s (%d)", resource.name, namespace, count),
+			remove: func() error {
+				return deleteCollection(&meta_v1.DeleteOptions{}, meta_v1.ListOptions{})
+			},
+		})
+	}
+	return items
+}
+
+// webHookItems returns the webhooks of the project and environment repositories which call this installation
+func (o *UninstallOptions) webHookItems(client kubernetes.Interface, jxClient versioned.Interface, namespace string, envMap map[string]*v1.Environment) []*uninstallItem {
+	items := []*uninstallItem{}
+	domain, err := kube.GetCurrentDomain(client, namespace)
+	if err != nil || domain == "" {
+		log.Warnf("Not removing any webhooks as the domain of the team namespace %s could not be found\n", namespace)
+		return items
+	}
+	gitURLs := []string{}
+	for _, env := range envMap {
+		if env.Spec.Source.URL != "" && util.StringArrayIndex(gitURLs, env.Spec.Source.URL) < 0 {
+			gitURLs = append(gitURLs, env.Spec.Source.URL)
+		}
+	}
+	activities, err := jxClient.JenkinsV1().PipelineActivities(namespace).List(meta_v1.ListOptions{})
+	if err == nil {
+		for _, activity := range activities.Items {
+			gitURL := activity.Spec.GitURL
+			if gitURL != "" && util.StringArrayIndex(gitURLs, gitURL) < 0 {
+				gitURLs = append(gitURLs, gitURL)
 			}
 		}
 	}
-	o.Helm().DeleteRelease(namespace, "jx-prow", true)
-	err = o.Helm().DeleteRelease(namespace, "jenkins-x", true)
-	if err != nil {
-		errc := o.cleanupNamesapces(namespace, envNames)
-		if errc != nil {
-			errc = errors.Wrap(errc, "failed to cleanup the jenkins-x platform")
-			return errc
+	for _, gitURL := range gitURLs {
+		gitURL := gitURL
+		gitInfo, err := gits.ParseGitURL(gitURL)
+		if err != nil {
+			log.Warnf("Failed to parse the git URL %s: %s\n", gitURL, err)
+			continue
 		}
-		return errors.Wrap(err, "failed to purge the jenkins-x chart")
+		items = append(items, &uninstallItem{
+			kind: "webhooks",
+			name: fmt.Sprintf("calling %s on %s/%s", domain, gitInfo.Organisation, gitInfo.Name),
+			remove: func() error {
+				provider, err := o.gitProviderForURL(gitURL, "user name to remove the webhooks")
+				if err != nil {
+					return err
+				}
+				return removeWebHooks(provider, gitInfo, domain)
+			},
+		})
 	}
-	err = jxClient.JenkinsV1().Environments(namespace).DeleteCollection(&meta_v1.DeleteOptions{}, meta_v1.ListOptions{})
+	return items
+}
+
+// removeWebHooks deletes the webhooks of the repository which call the domain of the installation
+func removeWebHooks(provider gits.GitProvider, gitInfo *gits.GitRepositoryInfo, domain string) error {
+	webHooks, err := provider.ListWebHooks(gitInfo.Organisation, gitInfo.Name)
 	if err != nil {
-		return err
+		return errors.Wrapf(err, "failed to list the webhooks of %s/%s", gitInfo.Organisation, gitInfo.Name)
 	}
-	err = o.cleanupNamesapces(namespace, envNames)
-	if err != nil {
-		return err
+	for _, webHook := range webHooks {
+		if !strings.Contains(webHook.URL, domain) {
+			continue
+		}
+		err = provider.DeleteWebHook(gitInfo.Organisation, gitInfo.Name, webHook.URL)
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete the webhook %s of %s/%s", webHook.URL, gitInfo.Organisation, gitInfo.Name)
+		}
 	}
-	log.Successf("Jenkins X has been successfully uninstalled from team namespace %s", namespace)
 	return nil
 }
 
-func (o *UninstallOptions) cleanupNamesapces(namespace string, envNames []string) error {
-	client, _, err := o.KubeClient()
+// environmentRepositoryItems returns the git repositories of the permanent environments
+func (o *UninstallOptions) environmentRepositoryItems(envMap map[string]*v1.Environment) []*uninstallItem {
+	items := []*uninstallItem{}
+	for _, env := range envMap {
+		gitURL := env.Spec.Source.URL
+		if gitURL == "" || env.Spec.Kind == v1.EnvironmentKindTypeDevelopment {
+			continue
+		}
+		gitInfo, err := gits.ParseGitURL(gitURL)
+		if err != nil {
+			log.Warnf("Failed to parse the git URL %s: %s\n", gitURL, err)
+			continue
+		}
+		items = append(items, &uninstallItem{
+			kind: "environment repository",
+			name: gitURL,
+			remove: func() error {
+				provider, err := o.gitProviderForURL(gitURL, "user name to delete the environment repository")
+				if err != nil {
+					return err
+				}
+				return provider.DeleteRepository(gitInfo.Organisation, gitInfo.Name)
+			},
+		})
+	}
+	return items
+}
+
+// cloudResourceItems returns the buckets jx created which are recorded in the team settings and the Google service
+// account of the builds
+func (o *UninstallOptions) cloudResourceItems(client kubernetes.Interface, namespace string, envMap map[string]*v1.Environment) []*uninstallItem {
+	items := []*uninstallItem{}
+	devEnv := envMap[kube.LabelValueDevEnvironment]
+	if devEnv != nil {
+		for _, location := range devEnv.Spec.TeamSettings.StorageLocations {
+			bucketURL := location.BucketURL
+			if !location.Managed || bucketURL == "" {
+				continue
+			}
+			items = append(items, &uninstallItem{
+				kind: "bucket",
+				name: bucketURL,
+				remove: func() error {
+					return buckets.DeleteBucket(bucketURL, "", "")
+				},
+			})
+		}
+	}
+	sa, err := client.CoreV1().ServiceAccounts(namespace).Get(jxBuilderServiceAccount, meta_v1.GetOptions{})
+	if err == nil && sa.Annotations[gke.WorkloadIdentityAnnotation] != "" {
+		email := sa.Annotations[gke.WorkloadIdentityAnnotation]
+		items = append(items, &uninstallItem{
+			kind: "service account",
+			name: email,
+			remove: func() error {
+				return gke.DeleteServiceAccount(email)
+			},
+		})
+	}
+	return items
+}
+
+// crdItems returns the Jenkins X custom resource definitions
+func (o *UninstallOptions) crdItems() []*uninstallItem {
+	items := []*uninstallItem{}
+	apisClient, err := o.CreateApiExtensionsClient()
 	if err != nil {
-		return errors.Wrap(err, "failed to get the kube client")
+		log.Warnf("Failed to create the API extensions client: %s\n", err)
+		return items
 	}
-	err = o.deleteNamespace(namespace)
+	crds, err := apisClient.ApiextensionsV1beta1().CustomResourceDefinitions().List(meta_v1.ListOptions{})
 	if err != nil {
-		return errors.Wrap(err, "failed to delete team namespace namespace")
+		log.Warnf("Failed to list the custom resource definitions: %s\n", err)
+		return items
 	}
-	if !o.KeepEnvironments {
-		for _, env := range envNames {
-			envNamespace := namespace + "-" + env
-			_, err := client.CoreV1().Namespaces().Get(envNamespace, meta_v1.GetOptions{})
-			if err != nil {
-				continue
-			}
-			err = o.deleteNamespace(envNamespace)
-			if err != nil {
-				return errors.Wrap(err, "failed to delete environment namespace")
-			}
+	for _, crd := range crds.Items {
+		name := crd.Name
+		if crd.Spec.Group != "jenkins.io" {
+			continue
 		}
+		items = append(items, &uninstallItem{
+			kind: "custom resource definition",
+			name: name,
+			remove: func() error {
+				return apisClient.ApiextensionsV1beta1().CustomResourceDefinitions().Delete(name, &meta_v1.DeleteOptions{})
+			},
+		})
 	}
-	return nil
+	return items
 }
 
 func (o *UninstallOptions) deleteNamespace(namespace string) error {
@@ -177,21 +507,34 @@ func (o *UninstallOptions) deleteNamespace(namespace string) error {
 	return nil
 }
 
-func (o *UninstallOptions) cleanupConfig() error {
+// configItems returns the current Jenkins and chart repository servers of the local jx configuration
+func (o *UninstallOptions) configItems() []*uninstallItem {
+	items := []*uninstallItem{}
 	authConfigSvc, err := o.Factory.CreateAuthConfigService(JenkinsAuthConfigFile)
-	if err != nil {
-		return nil
-	}
-	server := authConfigSvc.Config().CurrentServer
-	err = authConfigSvc.DeleteServer(server)
-	if err != nil {
-		return err
+	if err == nil {
+		server := authConfigSvc.Config().CurrentServer
+		if server != "" {
+			items = append(items, &uninstallItem{
+				kind: "local jenkins server",
+				name: server,
+				remove: func() error {
+					return authConfigSvc.DeleteServer(server)
+				},
+			})
+		}
 	}
-
 	chartConfigSvc, err := o.Factory.CreateChartmuseumAuthConfigService()
-	if err != nil {
-		return err
+	if err == nil {
+		server := chartConfigSvc.Config().CurrentServer
+		if server != "" {
+			items = append(items, &uninstallItem{
+				kind: "local chart repository server",
+				name: server,
+				remove: func() error {
+					return chartConfigSvc.DeleteServer(server)
+				},
+			})
+		}
 	}
-	server = chartConfigSvc.Config().CurrentServer
-	return chartConfigSvc.DeleteServer(server)
+	return items
 }
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	jxfake "github.com/jenkins-x/jx/pkg/client/clientset/versioned/fake"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestUninstallInBatchModeNeedsConfirmation(t *testing.T) {
+	t.Parallel()
+	o := &UninstallOptions{CommonOptions: CommonOptions{BatchMode: true}}
+
+	err := o.Run()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "'-y'")
+}
+
+func TestUninstallWebHooksAreListedWithoutLookingUpTheGitProvider(t *testing.T) {
+	t.Parallel()
+	client := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: kube.ConfigMapIngressConfig, Namespace: "jx"},
+		Data:       map[string]string{"domain": "jx.example.com"},
+	})
+	staging := kube.NewPermanentEnvironment("staging")
+	staging.Spec.Source.URL = "https://github.com/myorg/environment-staging"
+	activity := &v1.PipelineActivity{
+		ObjectMeta: metav1.ObjectMeta{Name: "myorg-myapp-master-1", Namespace: "jx"},
+		Spec:       v1.PipelineActivitySpec{GitURL: "https://github.com/myorg/myapp"},
+	}
+	jxClient := jxfake.NewSimpleClientset(activity)
+	// the options have no factory so looking up a git provider would fail the test
+	o := &UninstallOptions{}
+
+	items := o.webHookItems(client, jxClient, "jx", map[string]*v1.Environment{"staging": staging})
+	names := []string{}
+	for _, item := range items {
+		assert.Equal(t, "webhooks", item.kind)
+		names = append(names, item.name)
+	}
+	assert.ElementsMatch(t, []string{
+		"calling jx.example.com on myorg/environment-staging",
+		"calling jx.example.com on myorg/myapp",
+	}, names)
+}
+
+func TestUninstallRemovesOnlyTheWebHooksOfTheInstallation(t *testing.T) {
+	t.Parallel()
+	repo := &gits.FakeRepository{
+		GitRepo: &gits.GitRepository{Name: "myapp"},
+		WebHooks: []*gits.GitWebHookArguments{
+			{ID: 1, URL: "http://hook.jx.example.com/hook"},
+			{ID: 2, URL: "https://ci.example.org/webhook"},
+		},
+	}
+	provider := &gits.FakeProvider{
+		Type:         gits.GitHub,
+		Repositories: map[string][]*gits.FakeRepository{"myorg": {repo}},
+	}
+	gitInfo, err := gits.ParseGitURL("https://github.com/myorg/myapp")
+	require.NoError(t, err)
+
+	err = removeWebHooks(provider, gitInfo, "jx.example.com")
+	require.NoError(t, err)
+	require.Len(t, repo.WebHooks, 1)
+	assert.Equal(t, "https://ci.example.org/webhook", repo.WebHooks[0].URL)
+}
+
+func TestUninstallListsTheCustomResourcesOfTheTeam(t *testing.T) {
+	t.Parallel()
+	dev := kube.NewPermanentEnvironment("dev")
+	dev.Namespace = "jx"
+	staging := kube.NewPermanentEnvironment("staging")
+	staging.Namespace = "jx"
+	release := &v1.Release{ObjectMeta: metav1.ObjectMeta{Name: "myapp-1.0.0", Namespace: "jx"}}
+	jxClient := jxfake.NewSimpleClientset(dev, staging, release)
+
+	items := customResourceItems(jxClient, "jx")
+	names := []string{}
+	for _, item := range items {
+		assert.Equal(t, "custom resources", item.kind)
+		names = append(names, item.name)
+	}
+	assert.Equal(t, []string{"environments.jenkins.io in jx (2)", "releases.jenkins.io in jx (1)"}, names)
+}
@@ -21,6 +21,7 @@ var (
 	update_resources = `Valid resource types include:
 
 	* cluster
+	* webhooks
 	`
 
 	update_long = templates.LongDesc(`
@@ -54,6 +55,7 @@ func NewCmdUpdate(f Factory, in terminal.FileReader, out terminal.FileWriter, er
 	}
 
 	cmd.AddCommand(NewCmdUpdateCluster(f, in, out, errOut))
+	cmd.AddCommand(NewCmdUpdateWebhooks(f, in, out, errOut))
 
 	return cmd
 }
@@ -0,0 +1,413 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/prow"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	prowHMACSecret     = "hmac-token"
+	prowHMACSecretKey  = "hmac"
+	prowHookDeployment = "hook"
+)
+
+var (
+	updateWebhooksLong = templates.LongDesc(`
+		Checks the webhooks of the repositories of the team, i.e. the environment repositories, the repositories which
+		have been built and the repositories registered with Prow, and repairs those which do not call this installation.
+
+		Webhooks calling the webhook path of this installation on another host, e.g. after the ingress domain changed,
+		are pointed at the current URL. Duplicate webhooks are removed and missing webhooks are created. The secrets of
+		the webhooks are compared if the git provider reveals them.
+
+		With --rotate-secret a new Prow HMAC secret is generated and stored in the cluster Secret, the Prow hook is
+		restarted with it and then it is set on every webhook. If any webhook cannot be updated the webhooks and the
+		Secret are reverted to the previous secret. A ping event is sent to each webhook afterwards if the git provider
+		supports it, checking that the webhook accepted it.
+`)
+
+	updateWebhooksExample = templates.Examples(`
+		# Show which webhooks have drifted without changing them
+		jx update webhooks --dry-run
+
+		# Repair the webhooks of the repositories of an organisation
+		jx update webhooks --org myorg
+
+		# Repair all webhooks and rotate the webhook secret
+		jx update webhooks --rotate-secret
+	`)
+)
+
+// UpdateWebhooksOptions the options for the update webhooks command
+type UpdateWebhooksOptions struct {
+	CommonOptions
+
+	Org          string
+	Repo         string
+	DryRun       bool
+	RotateSecret bool
+
+	azureDevOpsHookURL string
+}
+
+// webHookRepository a repository with the drift of its webhooks
+type webHookRepository struct {
+	gitURL   string
+	gitInfo  *gits.GitRepositoryInfo
+	provider gits.GitProvider
+	expected *gits.GitWebHookArguments
+	drift    *gits.WebHookDrift
+}
+
+// NewCmdUpdateWebhooks creates the command
+func NewCmdUpdateWebhooks(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &UpdateWebhooksOptions{
+		CommonOptions: CommonOptions{
+			Factory: f,
+			In:      in,
+			Out:     out,
+			Err:     errOut,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "webhooks",
+		Short:   "Reports and repairs the webhooks of the repositories of the team",
+		Aliases: []string{"webhook"},
+		Long:    updateWebhooksLong,
+		Example: updateWebhooksExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+	cmd.Flags().StringVarP(&options.Org, "org", "o", "", "Only the repositories of this organisation")
+	cmd.Flags().StringVarP(&options.Repo, "repo", "r", "", "Only the repositories with this name or of the form org/name")
+	cmd.Flags().BoolVarP(&options.DryRun, "dry-run", "", false, "Only reports the webhooks which have drifted")
+	cmd.Flags().BoolVarP(&options.RotateSecret, "rotate-secret", "", false, "Generates a new Prow HMAC secret and sets it on the cluster Secret and every webhook")
+	return cmd
+}
+
+// Run implements this command
+func (o *UpdateWebhooksOptions) Run() error {
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	jxClient, _, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	devEnv, err := kube.GetEnvironment(jxClient, ns, kube.LabelValueDevEnvironment)
+	if err != nil {
+		return errors.Wrapf(err, "failed to find the dev environment in namespace %s", ns)
+	}
+	isProw := devEnv.Spec.TeamSettings.PromotionEngine == v1.PromotionEngineProw
+	if o.RotateSecret && !isProw {
+		return fmt.Errorf("the webhook secret can only be rotated when using Prow")
+	}
+
+	hookURL, secret, err := o.expectedWebHook(kubeClient, ns, isProw)
+	if err != nil {
+		return err
+	}
+	gitURLs, err := o.webHookRepositoryURLs(kubeClient, jxClient, ns, devEnv)
+	if err != nil {
+		return err
+	}
+
+	repos := []*webHookRepository{}
+	providers := map[string]gits.GitProvider{}
+	for _, gitURL := range gitURLs {
+		repo, err := o.detectDrift(gitURL, hookURL, secret, isProw, providers)
+		if err != nil {
+			log.Warnf("Failed to check the webhooks of %s: %s\n", gitURL, err)
+			continue
+		}
+		repos = append(repos, repo)
+	}
+
+	table := o.CreateTable()
+	table.AddRow("REPOSITORY", "STATUS", "SECRET", "DUPLICATES", "STALE URLS")
+	for _, repo := range repos {
+		drift := repo.drift
+		table.AddRow(repo.gitInfo.Organisation+"/"+repo.gitInfo.Name, drift.Status, drift.Secret, strconv.Itoa(drift.Duplicates), strings.Join(drift.StaleURLs(), " "))
+	}
+	table.Render()
+
+	if o.DryRun {
+		return nil
+	}
+	for _, repo := range repos {
+		if !repo.drift.Drifted() {
+			continue
+		}
+		err = gits.RepairWebHooks(repo.provider, repo.drift, repo.expected)
+		if err != nil {
+			return errors.Wrapf(err, "failed to repair the webhooks of %s", repo.gitURL)
+		}
+		log.Infof("Repaired the %s webhooks of %s\n", repo.drift.Status, util.ColorInfo(repo.gitURL))
+	}
+	if o.RotateSecret {
+		return o.rotateSecret(kubeClient, ns, repos, secret)
+	}
+	return nil
+}
+
+// expectedWebHook returns the URL and secret of the webhook which calls this installation. With Jenkins the URL is
+// the base URL of Jenkins as the path depends on the git provider
+func (o *UpdateWebhooksOptions) expectedWebHook(kubeClient kubernetes.Interface, ns string, isProw bool) (string, string, error) {
+	if !isProw {
+		baseURL, err := kube.GetServiceURLFromName(kubeClient, "jenkins", ns)
+		if err != nil {
+			return "", "", errors.Wrap(err, "failed to find the URL of Jenkins")
+		}
+		return baseURL, "", nil
+	}
+	baseURL, err := kube.GetServiceURLFromName(kubeClient, "hook", ns)
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to find the URL of the Prow hook")
+	}
+	hmacSecret, err := kubeClient.CoreV1().Secrets(ns).Get(prowHMACSecret, metav1.GetOptions{})
+	if err != nil {
+		return "", "", errors.Wrapf(err, "failed to find the Prow HMAC token in Secret %s in namespace %s", prowHMACSecret, ns)
+	}
+	return util.UrlJoin(baseURL, "hook"), string(hmacSecret.Data[prowHMACSecretKey]), nil
+}
+
+// webHookRepositoryURLs returns the git URLs of the environment repositories, the repositories which have been built
+// and the repositories registered with Prow which match the filters
+func (o *UpdateWebhooksOptions) webHookRepositoryURLs(kubeClient kubernetes.Interface, jxClient versioned.Interface, ns string, devEnv *v1.Environment) ([]string, error) {
+	gitURLs := []string{}
+	add := func(gitURL string) {
+		if gitURL == "" || util.StringArrayIndex(gitURLs, gitURL) >= 0 {
+			return
+		}
+		gitInfo, err := gits.ParseGitURL(gitURL)
+		if err != nil {
+			log.Warnf("Ignoring the invalid git URL %s: %s\n", gitURL, err)
+			return
+		}
+		if o.matches(gitInfo) {
+			gitURLs = append(gitURLs, gitInfo.HttpsURL())
+		}
+	}
+
+	envMap, _, err := kube.GetEnvironments(jxClient, ns)
+	if err != nil {
+		return nil, err
+	}
+	for _, env := range envMap {
+		add(env.Spec.Source.URL)
+	}
+	activities, err := jxClient.JenkinsV1().PipelineActivities(ns).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, activity := range activities.Items {
+		add(activity.Spec.GitURL)
+	}
+	prowRepos, err := prow.GetRepositories(kubeClient, ns)
+	if err != nil {
+		return nil, err
+	}
+	gitServer := devEnv.Spec.TeamSettings.GitServer
+	if gitServer == "" {
+		gitServer = gits.GitHubURL
+	}
+	for _, repo := range prowRepos {
+		add(util.UrlJoin(gitServer, repo))
+	}
+	return gitURLs, nil
+}
+
+func (o *UpdateWebhooksOptions) matches(gitInfo *gits.GitRepositoryInfo) bool {
+	if o.Org != "" && gitInfo.Organisation != o.Org {
+		return false
+	}
+	if o.Repo != "" && gitInfo.Name != o.Repo && gitInfo.Organisation+"/"+gitInfo.Name != o.Repo {
+		return false
+	}
+	return true
+}
+
+func (o *UpdateWebhooksOptions) detectDrift(gitURL string, hookURL string, secret string, isProw bool, providers map[string]gits.GitProvider) (*webHookRepository, error) {
+	gitInfo, err := gits.ParseGitURL(gitURL)
+	if err != nil {
+		return nil, err
+	}
+	provider := providers[gitInfo.HostURL()]
+	if provider == nil {
+		provider, err = o.gitProviderForURL(gitURL, "user name to update the webhooks")
+		if err != nil {
+			return nil, err
+		}
+		providers[gitInfo.HostURL()] = provider
+	}
+	if !isProw {
+		hookURL = util.UrlJoin(hookURL, provider.JenkinsWebHookPath(gitURL, ""))
+	} else if provider.Kind() == gits.KindAzureDevOps {
+		hookURL, err = o.azureDevOpsWebHookURL()
+		if err != nil {
+			return nil, err
+		}
+	}
+	expected := &gits.GitWebHookArguments{
+		Owner:  gitInfo.Organisation,
+		Repo:   gitInfo,
+		URL:    hookURL,
+		Secret: secret,
+	}
+	hooks, err := provider.ListWebHooks(gitInfo.Organisation, gitInfo.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &webHookRepository{
+		gitURL:   gitURL,
+		gitInfo:  gitInfo,
+		provider: provider,
+		expected: expected,
+		drift:    gits.DetectWebHookDrift(hooks, expected),
+	}, nil
+}
+
+// azureDevOpsWebHookURL returns the URL of the controller which translates the service hooks of Azure DevOps for the
+// Prow hook, deploying the controller unless this is a dry run
+func (o *UpdateWebhooksOptions) azureDevOpsWebHookURL() (string, error) {
+	if o.azureDevOpsHookURL != "" {
+		return o.azureDevOpsHookURL, nil
+	}
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return "", err
+	}
+	if o.DryRun {
+		// the webhooks are reported as missing if the controller has not been deployed yet
+		hookURL, _ := kube.GetServiceURLFromName(kubeClient, azureDevOpsWebHookService, ns)
+		return hookURL, nil
+	}
+	o.azureDevOpsHookURL, err = o.ensureAzureDevOpsWebHook(kubeClient, ns)
+	return o.azureDevOpsHookURL, err
+}
+
+// rotateSecret sets a new secret on the Prow HMAC Secret, restarts the receivers of the webhooks so that they validate
+// the webhooks with it and then sets it on every webhook. If a webhook cannot be updated the webhooks updated so far and
+// the Secret are reverted to the old secret
+func (o *UpdateWebhooksOptions) rotateSecret(kubeClient kubernetes.Interface, ns string, repos []*webHookRepository, oldSecret string) error {
+	newSecret, err := util.RandStringBytesMaskImprSrc(41)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate a new webhook secret")
+	}
+	err = o.switchHMACSecret(kubeClient, ns, newSecret)
+	if err != nil {
+		return err
+	}
+
+	updated := []*gits.GitWebHookArguments{}
+	providers := []gits.GitProvider{}
+	rollback := func(cause error) error {
+		for i, hook := range updated {
+			hook.Secret = oldSecret
+			err := providers[i].UpdateWebHook(hook)
+			if err != nil {
+				log.Warnf("Failed to revert the secret of the webhook %s of %s/%s: %s\n", hook.URL, hook.Owner, hook.Repo.Name, err)
+			}
+		}
+		err := o.switchHMACSecret(kubeClient, ns, oldSecret)
+		if err != nil {
+			log.Warnf("Failed to revert the Prow HMAC token: %s\n", err)
+		}
+		return cause
+	}
+	for _, repo := range repos {
+		hooks, err := repo.provider.ListWebHooks(repo.gitInfo.Organisation, repo.gitInfo.Name)
+		if err != nil {
+			return rollback(errors.Wrapf(err, "failed to list the webhooks of %s", repo.gitURL))
+		}
+		for _, hook := range hooks {
+			if hook.URL != repo.expected.URL {
+				continue
+			}
+			rotated := *repo.expected
+			rotated.ID = hook.ID
+			rotated.Secret = newSecret
+			err = repo.provider.UpdateWebHook(&rotated)
+			if err != nil {
+				return rollback(errors.Wrapf(err, "failed to update the secret of the webhook of %s", repo.gitURL))
+			}
+			updated = append(updated, &rotated)
+			providers = append(providers, repo.provider)
+		}
+	}
+	log.Infof("Rotated the secret of %s webhooks and of Secret %s\n", util.ColorInfo(len(updated)), util.ColorInfo(prowHMACSecret))
+
+	for i, hook := range updated {
+		pinger, ok := providers[i].(gits.WebHookPinger)
+		if !ok {
+			continue
+		}
+		err = pinger.PingWebHook(hook.Owner, hook.Repo.Name, hook.ID)
+		if err != nil {
+			log.Warnf("Failed to ping the webhook of %s/%s: %s\n", hook.Owner, hook.Repo.Name, err)
+		} else {
+			log.Infof("The webhook of %s/%s accepted a ping event\n", hook.Owner, hook.Repo.Name)
+		}
+	}
+	return nil
+}
+
+// switchHMACSecret stores the secret in the Prow HMAC Secret and restarts the receivers of the webhooks which load it
+// on startup, waiting for them to be ready
+func (o *UpdateWebhooksOptions) switchHMACSecret(kubeClient kubernetes.Interface, ns string, secret string) error {
+	secrets := kubeClient.CoreV1().Secrets(ns)
+	hmacSecret, err := secrets.Get(prowHMACSecret, metav1.GetOptions{})
+	if err == nil {
+		if hmacSecret.Data == nil {
+			hmacSecret.Data = map[string][]byte{}
+		}
+		hmacSecret.Data[prowHMACSecretKey] = []byte(secret)
+		_, err = secrets.Update(hmacSecret)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to update the Prow HMAC token in Secret %s in namespace %s", prowHMACSecret, ns)
+	}
+	for _, name := range []string{prowHookDeployment, azureDevOpsWebHookService} {
+		_, err := kubeClient.AppsV1().Deployments(ns).Get(name, metav1.GetOptions{})
+		if err != nil {
+			if name == prowHookDeployment {
+				return errors.Wrapf(err, "failed to find the Prow hook Deployment %s in namespace %s", name, ns)
+			}
+			continue
+		}
+		err = kube.RestartDeployment(kubeClient, name, ns)
+		if err != nil {
+			return errors.Wrapf(err, "failed to restart the Deployment %s", name)
+		}
+		err = kube.WaitForDeploymentRollout(kubeClient, name, ns, 5*time.Minute)
+		if err != nil {
+			return errors.Wrapf(err, "failed waiting for the Deployment %s to restart", name)
+		}
+		log.Infof("Restarted %s with the new webhook secret\n", util.ColorInfo(name))
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// secretCheckingProvider records the Prow HMAC token of the cluster when a webhook is updated
+type secretCheckingProvider struct {
+	*gits.FakeProvider
+	kubeClient    kubernetes.Interface
+	clusterTokens []string
+	fail          bool
+}
+
+func (p *secretCheckingProvider) UpdateWebHook(data *gits.GitWebHookArguments) error {
+	secret, err := p.kubeClient.CoreV1().Secrets("jx").Get(prowHMACSecret, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	p.clusterTokens = append(p.clusterTokens, string(secret.Data[prowHMACSecretKey]))
+	if p.fail && data.Secret != "old-secret" {
+		return fmt.Errorf("forbidden")
+	}
+	return p.FakeProvider.UpdateWebHook(data)
+}
+
+func newRotateSecretFixture(fail bool) (kubernetes.Interface, *secretCheckingProvider, []*webHookRepository) {
+	replicas := int32(1)
+	kubeClient := fake.NewSimpleClientset(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: prowHMACSecret, Namespace: "jx"},
+			Data:       map[string][]byte{prowHMACSecretKey: []byte("old-secret")},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: prowHookDeployment, Namespace: "jx"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     appsv1.DeploymentStatus{Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1},
+		})
+	gitInfo := &gits.GitRepositoryInfo{Organisation: "myorg", Name: "myrepo"}
+	expected := &gits.GitWebHookArguments{Owner: "myorg", Repo: gitInfo, URL: "http://hook.jx.example.com/hook", Secret: "old-secret"}
+	existing := *expected
+	existing.ID = 1
+	repo := &gits.FakeRepository{GitRepo: &gits.GitRepository{Name: "myrepo"}, WebHooks: []*gits.GitWebHookArguments{&existing}}
+	provider := &secretCheckingProvider{
+		FakeProvider: &gits.FakeProvider{Repositories: map[string][]*gits.FakeRepository{"myorg": {repo}}},
+		kubeClient:   kubeClient,
+		fail:         fail,
+	}
+	repos := []*webHookRepository{{gitURL: "https://github.com/myorg/myrepo", gitInfo: gitInfo, provider: provider, expected: expected}}
+	return kubeClient, provider, repos
+}
+
+func TestRotateSecretUpdatesTheClusterSecretBeforeTheWebhooks(t *testing.T) {
+	t.Parallel()
+	kubeClient, provider, repos := newRotateSecretFixture(false)
+	o := &UpdateWebhooksOptions{}
+
+	err := o.rotateSecret(kubeClient, "jx", repos, "old-secret")
+	require.NoError(t, err)
+
+	secret, err := kubeClient.CoreV1().Secrets("jx").Get(prowHMACSecret, metav1.GetOptions{})
+	require.NoError(t, err)
+	newSecret := string(secret.Data[prowHMACSecretKey])
+	assert.NotEqual(t, "old-secret", newSecret)
+	assert.Equal(t, []string{newSecret}, provider.clusterTokens, "the hook should validate with the new secret before the webhook sends it")
+	hooks, err := provider.ListWebHooks("myorg", "myrepo")
+	require.NoError(t, err)
+	assert.Equal(t, newSecret, hooks[0].Secret)
+
+	hook, err := kubeClient.AppsV1().Deployments("jx").Get(prowHookDeployment, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, hook.Spec.Template.Annotations, "the hook should be restarted to load the new secret")
+}
+
+func TestRotateSecretRevertsTheClusterSecretIfAWebhookFails(t *testing.T) {
+	t.Parallel()
+	kubeClient, provider, repos := newRotateSecretFixture(true)
+	o := &UpdateWebhooksOptions{}
+
+	err := o.rotateSecret(kubeClient, "jx", repos, "old-secret")
+	assert.Error(t, err)
+
+	secret, err := kubeClient.CoreV1().Secrets("jx").Get(prowHMACSecret, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "old-secret", string(secret.Data[prowHMACSecretKey]))
+	hooks, err := provider.ListWebHooks("myorg", "myrepo")
+	require.NoError(t, err)
+	assert.Equal(t, "old-secret", hooks[0].Secret)
+}
@@ -27,6 +27,9 @@ var (
 
 		# upgrade extensions
 		jx upgrade extensions 
+
+		# upgrade the apps of the development environment
+		jx upgrade app --all
 	`)
 )
 
@@ -56,6 +59,7 @@ func NewCmdUpgrade(f Factory, in terminal.FileReader, out terminal.FileWriter, e
 	}
 
 	cmd.AddCommand(NewCmdUpgradeAddons(f, in, out, errOut))
+	cmd.AddCommand(NewCmdUpgradeApp(f, in, out, errOut))
 	cmd.AddCommand(NewCmdUpgradeCLI(f, in, out, errOut))
 	cmd.AddCommand(NewCmdUpgradeBinaries(f, in, out, errOut))
 	cmd.AddCommand(NewCmdUpgradeCluster(f, in, out, errOut))
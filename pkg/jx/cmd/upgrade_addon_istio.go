@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	upgradeAddonIstioLong = templates.LongDesc(`
+		Upgrades the Istio addon to the given version with the istioctl of that version
+
+		Istio installed with the helm chart by older versions of jx cannot be upgraded by istioctl so its helm release
+		is deleted and Istio is installed again, which interrupts the traffic of the mesh until the install completes.
+
+		The sidecars of the existing pods keep their version until the pods are restarted.
+`)
+
+	upgradeAddonIstioExample = templates.Examples(`
+		# Upgrades Istio to the default version of this jx
+		jx upgrade addon istio
+
+		# Upgrades Istio to a given version
+		jx upgrade addon istio --version 1.7.3
+	`)
+)
+
+// UpgradeAddonIstioOptions the options for the upgrade addon istio command
+type UpgradeAddonIstioOptions struct {
+	CreateAddonOptions
+
+	DeleteHelmRelease bool
+}
+
+// NewCmdUpgradeAddonIstio defines the command
+func NewCmdUpgradeAddonIstio(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &UpgradeAddonIstioOptions{
+		CreateAddonOptions: CreateAddonOptions{
+			CreateOptions: CreateOptions{
+				CommonOptions: CommonOptions{
+					Factory: f,
+					In:      in,
+					Out:     out,
+					Err:     errOut,
+				},
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "istio",
+		Short:   "Upgrades the Istio addon",
+		Long:    upgradeAddonIstioLong,
+		Example: upgradeAddonIstioExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	options.addCommonFlags(cmd)
+	options.addIstioFlags(cmd)
+	cmd.Flags().BoolVarP(&options.DeleteHelmRelease, "delete-helm-release", "", false, "Deletes the helm release of Istio installed by older versions of jx without prompting so that it can be installed again with istioctl")
+	return cmd
+}
+
+// Run implements the command
+func (o *UpgradeAddonIstioOptions) Run() error {
+	if o.Namespace == "" {
+		return util.MissingOption("namespace")
+	}
+	if o.Version == "" {
+		return util.MissingOption("version")
+	}
+	legacy, err := o.isIstioHelmRelease()
+	if err != nil {
+		return err
+	}
+	if legacy {
+		log.Warnf("Istio was installed with the helm release %s which istioctl cannot upgrade\n", o.ReleaseName)
+		if !o.DeleteHelmRelease {
+			if o.BatchMode {
+				return fmt.Errorf("use --delete-helm-release to delete the helm release %s and install Istio %s with istioctl", o.ReleaseName, o.Version)
+			}
+			message := fmt.Sprintf("Delete the helm release %s and install Istio %s? The traffic of the mesh is interrupted until the install completes", o.ReleaseName, o.Version)
+			if !util.Confirm(message, false, "", o.In, o.Out, o.Err) {
+				return fmt.Errorf("the upgrade of Istio was aborted")
+			}
+		}
+		err = o.Helm().DeleteRelease(o.Namespace, o.ReleaseName, true)
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete the helm release %s", o.ReleaseName)
+		}
+		createOptions := &CreateAddonIstioOptions{
+			CreateAddonOptions: o.CreateAddonOptions,
+			Profile:            defaultIstioProfile,
+		}
+		err = createOptions.Run()
+		if err != nil {
+			return err
+		}
+	} else {
+		istioctl, err := o.installIstioctl(o.Version)
+		if err != nil {
+			return errors.Wrapf(err, "failed to install istioctl %s", o.Version)
+		}
+		args := append([]string{"upgrade", "--skip-confirmation"}, istioSetArgs(o.Namespace, o.SetValues)...)
+		log.Infof("Upgrading Istio in namespace %s to %s\n", util.ColorInfo(o.Namespace), util.ColorInfo(o.Version))
+		err = o.runCommandVerbose(istioctl, args...)
+		if err != nil {
+			return errors.Wrap(err, "istio upgrade failed")
+		}
+		log.Infof("Upgraded Istio to %s\n", util.ColorInfo(o.Version))
+	}
+	return o.logInjectedNamespaces()
+}
+
+// isIstioHelmRelease returns true if Istio was installed with the helm chart by an older version of jx
+func (o *CreateAddonOptions) isIstioHelmRelease() (bool, error) {
+	if o.ReleaseName == "" {
+		return false, nil
+	}
+	err := o.ensureHelm()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to ensure that helm is present")
+	}
+	statusMap, err := o.Helm().StatusReleases(o.Namespace)
+	if err != nil {
+		log.Warnf("Failed to find helm installs: %s\n", err)
+		return false, nil
+	}
+	return statusMap[o.ReleaseName] != "", nil
+}
+
+// logInjectedNamespaces lists the namespaces with sidecar injection whose pods need restarting to upgrade their sidecars
+func (o *UpgradeAddonIstioOptions) logInjectedNamespaces() error {
+	kubeClient, _, err := o.KubeClient()
+	if err != nil {
+		return err
+	}
+	namespaces, err := kubeClient.CoreV1().Namespaces().List(metav1.ListOptions{
+		LabelSelector: kube.LabelIstioInjection + "=" + kube.IstioInjectionEnabled,
+	})
+	if err != nil {
+		return err
+	}
+	names := []string{}
+	for _, ns := range namespaces.Items {
+		names = append(names, ns.Name)
+	}
+	if len(names) > 0 {
+		log.Infof("Restart the pods of the namespaces %s to upgrade their sidecars, e.g. %s\n",
+			util.ColorInfo(strings.Join(names, ", ")), util.ColorInfo("kubectl rollout restart deployment -n "+names[0]))
+	}
+	return nil
+}
@@ -67,6 +67,8 @@ func NewCmdUpgradeAddons(f Factory, in terminal.FileReader, out terminal.FileWri
 	options.addCommonFlags(cmd)
 	options.InstallFlags.addCloudEnvOptions(cmd)
 
+	cmd.AddCommand(NewCmdUpgradeAddonIstio(f, in, out, errOut))
+
 	return cmd
 }
 
@@ -109,6 +111,10 @@ func (o *UpgradeAddonsOptions) Run() error {
 		if name == k {
 			name = "kube-cd"
 		}
+		if status != "" && k == defaultIstioReleaseName {
+			log.Infof("Skipping %s which is upgraded with %s\n", util.ColorInfo(k), util.ColorInfo("jx upgrade addon istio"))
+			continue
+		}
 		if status != "" {
 			log.Infof("Upgrading %s chart %s...\n", util.ColorInfo(name), util.ColorInfo(chart))
 
@@ -0,0 +1,314 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	"k8s.io/client-go/kubernetes"
+)
+
+var (
+	upgradeAppLong = templates.LongDesc(`
+		Upgrades apps added to the development environment with 'jx add app' to a newer version of their chart
+
+		If the development environment uses GitOps the versions in the requirements.yaml of its Git repository are
+		changed via a Pull Request. Otherwise the helm releases are upgraded directly. The values which were supplied
+		when the apps were added are used for the new versions too.
+
+		Use --dry-run to see the version changes and the changes to the values of the apps without upgrading them.
+`)
+
+	upgradeAppExample = templates.Examples(`
+		# upgrades an app to the latest version of its chart
+		jx upgrade app jx-app-jacoco
+
+		# upgrades an app to a specific version
+		jx upgrade app jx-app-jacoco --version 1.2.3
+
+		# shows the changes of upgrading all the apps to their latest versions
+		jx upgrade app --all --dry-run
+	`)
+)
+
+// UpgradeAppOptions the options for the upgrade app command
+type UpgradeAppOptions struct {
+	UpgradeOptions
+
+	Version    string
+	All        bool
+	DryRun     bool
+	HelmUpdate bool
+
+	// allow git to be configured externally before a PR is created
+	ConfigureGitCallback ConfigureGitFolderFn
+}
+
+// NewCmdUpgradeApp defines the command
+func NewCmdUpgradeApp(f Factory, in terminal.FileReader, out terminal.FileWriter, errOut io.Writer) *cobra.Command {
+	options := &UpgradeAppOptions{
+		UpgradeOptions: UpgradeOptions{
+			CommonOptions: CommonOptions{
+				Factory: f,
+				In:      in,
+				Out:     out,
+				Err:     errOut,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "app [name]",
+		Short:   "Upgrades one or more apps of the development environment to a newer version",
+		Aliases: []string{"apps"},
+		Long:    upgradeAppLong,
+		Example: upgradeAppExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&options.Version, "version", "", "", "The version to upgrade the app to. Defaults to the latest version")
+	cmd.Flags().BoolVarP(&options.All, "all", "", false, "Upgrade all the apps of the development environment to their latest versions")
+	cmd.Flags().BoolVarP(&options.DryRun, "dry-run", "", false, "Show the version and values changes without upgrading the apps")
+	cmd.Flags().BoolVarP(&options.HelmUpdate, "helm-update", "", true, "Should we run helm update first to ensure we use the latest version")
+
+	options.addCommonFlags(cmd)
+	return cmd
+}
+
+// Run implements the command
+func (o *UpgradeAppOptions) Run() error {
+	if len(o.Args) == 0 && !o.All {
+		return o.Cmd.Help()
+	}
+	if o.All && len(o.Args) > 0 {
+		return fmt.Errorf("cannot specify both app names and --all")
+	}
+	if o.Version != "" && len(o.Args) != 1 {
+		return fmt.Errorf("--version can only be used to upgrade a single app")
+	}
+
+	kubeClient, ns, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	jxClient, _, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	env, err := kube.GetEnvironment(jxClient, ns, kube.LabelValueDevEnvironment)
+	if err != nil {
+		return err
+	}
+	apps, dir, err := o.devEnvironmentApps(kubeClient, ns, env)
+	if err != nil {
+		return err
+	}
+	apps, err = o.selectApps(apps)
+	if err != nil {
+		return err
+	}
+	err = o.findLatestAppVersions(apps, o.HelmUpdate)
+	if err != nil {
+		return err
+	}
+
+	upgrades := map[string]string{}
+	upgradeApps := []*AppVersion{}
+	for _, app := range apps {
+		version := o.Version
+		if version == "" {
+			version = app.LatestVersion
+		}
+		if version == "" {
+			continue
+		}
+		if version == app.Version {
+			log.Infof("App %s is already at version %s\n", util.ColorInfo(app.Name), util.ColorInfo(version))
+			continue
+		}
+		upgrades[app.Name] = version
+		upgradeApps = append(upgradeApps, app)
+	}
+	if len(upgradeApps) == 0 {
+		log.Infof("There are no apps to upgrade\n")
+		return nil
+	}
+
+	if o.DryRun {
+		return o.dryRun(upgradeApps, upgrades, dir)
+	}
+	if env.Spec.Source.URL == "" {
+		return o.upgradeReleases(kubeClient, ns, upgradeApps, upgrades)
+	}
+	return o.createUpgradePullRequest(env, upgradeApps, upgrades)
+}
+
+// selectApps returns the apps to upgrade
+func (o *UpgradeAppOptions) selectApps(apps []*AppVersion) ([]*AppVersion, error) {
+	if o.All {
+		return apps, nil
+	}
+	answer := []*AppVersion{}
+	for _, name := range o.Args {
+		found := false
+		for _, app := range apps {
+			if app.Name == name {
+				answer = append(answer, app)
+				found = true
+				break
+			}
+		}
+		if !found {
+			names := []string{}
+			for _, app := range apps {
+				names = append(names, app.Name)
+			}
+			return nil, util.InvalidArg(name, names)
+		}
+	}
+	return answer, nil
+}
+
+// dryRun prints the version changes and the changes to the computed values of the apps
+func (o *UpgradeAppOptions) dryRun(apps []*AppVersion, upgrades map[string]string, dir string) error {
+	for _, app := range apps {
+		version := upgrades[app.Name]
+		log.Infof("App %s would be upgraded from version %s to %s\n", util.ColorInfo(app.Name), util.ColorInfo(app.Version), util.ColorInfo(version))
+
+		var values map[string]interface{}
+		var err error
+		if dir != "" {
+			values, err = envAppValues(dir, app.Name)
+		} else {
+			values, err = o.releaseUserValues(app)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to load the values of app %s", app.Name)
+		}
+		err = o.withValuesFile(values, func(valueFiles []string) error {
+			return o.diffAppValues(app, version, valueFiles)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upgradeReleases upgrades the helm releases of the apps of a development environment which does not use GitOps
+// reusing the values supplied when they were installed
+func (o *UpgradeAppOptions) upgradeReleases(kubeClient kubernetes.Interface, ns string, apps []*AppVersion, upgrades map[string]string) error {
+	for _, app := range apps {
+		version := upgrades[app.Name]
+		values, err := o.releaseUserValues(app)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load the values of app %s", app.Name)
+		}
+		log.Infof("Upgrading app %s from version %s to %s\n", util.ColorInfo(app.Name), util.ColorInfo(app.Version), util.ColorInfo(version))
+		err = o.withValuesFile(values, func(valueFiles []string) error {
+			return o.Helm().UpgradeChart(app.repoName+"/"+app.Chart, app.ReleaseName, app.Namespace, &version, false, nil, false, false, nil, valueFiles)
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to upgrade app %s", app.Name)
+		}
+		err = kube.SaveApp(kubeClient, ns, &kube.App{
+			Name:        app.Name,
+			Chart:       app.Chart,
+			Repository:  app.Repository,
+			Version:     version,
+			ReleaseName: app.ReleaseName,
+			Namespace:   app.Namespace,
+		})
+		if err != nil {
+			return err
+		}
+		log.Infof("Upgraded app %s to version %s\n", util.ColorInfo(app.Name), util.ColorInfo(version))
+	}
+	return nil
+}
+
+// createUpgradePullRequest changes the versions of the apps in the requirements of the GitOps development environment
+// via a Pull Request. The values of the apps are left unchanged
+func (o *UpgradeAppOptions) createUpgradePullRequest(env *v1.Environment, apps []*AppVersion, upgrades map[string]string) error {
+	modifyRequirementsFn := func(requirements *helm.Requirements) error {
+		for _, dep := range requirements.Dependencies {
+			if dep == nil {
+				continue
+			}
+			name := dep.Alias
+			if name == "" {
+				name = dep.Name
+			}
+			if version, ok := upgrades[name]; ok {
+				dep.Version = version
+			}
+		}
+		return nil
+	}
+	var confirmFn ConfirmEnvironmentChangesFn
+	if !o.BatchMode {
+		confirmFn = o.confirmEnvironmentChanges
+	}
+	changes := []string{}
+	for _, app := range apps {
+		changes = append(changes, fmt.Sprintf("%s %s", app.Name, upgrades[app.Name]))
+	}
+	branchName := "upgrade-app-" + strings.Join(changes, "-")
+	title := "Upgrade " + strings.Join(changes, ", ")
+	message := "Upgrade apps " + strings.Join(changes, ", ")
+	if len(apps) > 1 {
+		branchName = "upgrade-apps"
+		title = fmt.Sprintf("Upgrade %d apps", len(apps))
+	}
+	_, err := o.createConfirmedEnvironmentPullRequest(env, modifyRequirementsFn, nil, confirmFn, branchName, title, message, nil, o.ConfigureGitCallback)
+	return err
+}
+
+// releaseUserValues returns the values supplied by the user when the helm release of the app was installed
+func (o *UpgradeAppOptions) releaseUserValues(app *AppVersion) (map[string]interface{}, error) {
+	text, err := o.Helm().GetReleaseUserValues(app.Namespace, app.ReleaseName)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]interface{}{}
+	err = yaml.Unmarshal([]byte(text), &values)
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// withValuesFile writes the values to a temporary values file which is passed to the function
+func (o *UpgradeAppOptions) withValuesFile(values map[string]interface{}, fn func(valueFiles []string) error) error {
+	if len(values) == 0 {
+		return fn(nil)
+	}
+	tmpDir, err := ioutil.TempDir("", "upgrade-app-values-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	valuesFile := filepath.Join(tmpDir, helm.ValuesFileName)
+	err = helm.SaveValuesFile(valuesFile, values)
+	if err != nil {
+		return err
+	}
+	return fn([]string{valuesFile})
+}
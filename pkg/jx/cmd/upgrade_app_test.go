@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpgradeAppSelectApps(t *testing.T) {
+	t.Parallel()
+	apps := []*AppVersion{
+		{Name: "jx-app-jacoco", Chart: "jx-app-jacoco", Version: "1.0.0"},
+		{Name: "db", Chart: "postgresql", Version: "2.0.0"},
+	}
+
+	o := &UpgradeAppOptions{}
+	o.Args = []string{"db"}
+	selected, err := o.selectApps(apps)
+	require.NoError(t, err)
+	require.Len(t, selected, 1)
+	assert.Equal(t, "postgresql", selected[0].Chart)
+
+	o.Args = []string{"jx-app-jacocoo"}
+	_, err = o.selectApps(apps)
+	assert.Error(t, err)
+
+	o = &UpgradeAppOptions{All: true}
+	selected, err = o.selectApps(apps)
+	require.NoError(t, err)
+	assert.Len(t, selected, 2)
+}
+
+func TestEnvAppValues(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-upgrade-app")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	envDir := filepath.Join(dir, "env")
+	require.NoError(t, os.MkdirAll(envDir, DefaultWritePermissions))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(envDir, helm.RequirementsFileName), []byte("dependencies:\n- name: postgresql\n  alias: db\n  version: 2.0.0\n"), 0644))
+
+	values, err := envAppValues(dir, "db")
+	require.NoError(t, err)
+	assert.Empty(t, values, "there are no values without a values file")
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(envDir, helm.ValuesFileName), []byte("db:\n  persistence:\n    size: 10Gi\n  password: secret://db-values/password\n"), 0644))
+	values, err = envAppValues(dir, "db")
+	require.NoError(t, err)
+	assert.Equal(t, "secret://db-values/password", values["password"])
+	assert.Equal(t, map[string]interface{}{"size": "10Gi"}, values["persistence"])
+
+	values, err = envAppValues(dir, "jx-app-jacoco")
+	require.NoError(t, err)
+	assert.Empty(t, values)
+}
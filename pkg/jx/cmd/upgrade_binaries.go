@@ -4,6 +4,7 @@ import (
 	"io"
 	"io/ioutil"
 
+	"github.com/jenkins-x/jx/pkg/binaries"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/util"
 	"github.com/spf13/cobra"
@@ -13,6 +14,9 @@ import (
 var (
 	upgradeBinariesLong = templates.LongDesc(`
 		Upgrades the Jenkins X command line binaries (like helm or eksctl) if there is a newer release
+
+		Binaries installed into ~/.jx/bin are refreshed to the versions pinned by jx. Binaries installed elsewhere
+		which are older than the minimum versions jx requires are upgraded into ~/.jx/bin leaving the other copy alone.
 `)
 
 	upgradeBInariesExample = templates.Examples(`
@@ -58,24 +62,44 @@ func (o *UpgradeBinariesOptions) Run() error {
 	if err != nil {
 		return err
 	}
-	binaries, err := ioutil.ReadDir(binDir)
+	files, err := ioutil.ReadDir(binDir)
 	if err != nil {
 		return err
 	}
+	installed := map[string]bool{}
+	for _, file := range files {
+		installed[file.Name()] = true
+	}
 
-	for _, binary := range binaries {
-		if binary.Name() == "eksctl" {
-			err = o.installEksCtl(true)
-			if err != nil {
-				return err
-			}
-		} else if binary.Name() == "heptio-authenticator-aws" {
-			err = o.installHeptioAuthenticatorAws(true)
-			if err != nil {
-				return err
-			}
+	for _, binary := range managedBinaries {
+		if !installed[binaries.BinaryWithExtension(binary.name)] && outdatedBinaryShouldBeUpgraded(binary.name) == "" {
+			continue
+		}
+		err = binary.install(&o.CommonOptions)
+		if err != nil {
+			return err
 		}
 	}
-
 	return nil
 }
+
+type managedBinary struct {
+	name    string
+	install func(o *CommonOptions) error
+}
+
+// managedBinaries the binaries jx installs into ~/.jx/bin at pinned versions
+var managedBinaries = []managedBinary{
+	{
+		name: "eksctl",
+		install: func(o *CommonOptions) error {
+			return o.installEksCtl(true)
+		},
+	},
+	{
+		name: "aws-iam-authenticator",
+		install: func(o *CommonOptions) error {
+			return o.installAwsIamAuthenticator(true)
+		},
+	},
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/jenkins-x/jx/pkg/log"
 
 	jenkinsv1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/gits"
 
 	"github.com/jenkins-x/jx/pkg/util"
 	"github.com/pborman/uuid"
@@ -220,8 +221,7 @@ func (o *UpgradeExtensionsRepositoryOptions) walkRemote(remote string, tag strin
 			}
 			resolvedTag = fmt.Sprintf("v%s", resolvedTag)
 		}
-		definitionsUrl := fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s", org, repo, resolvedTag)
-		definitionsFileUrl := fmt.Sprintf("%s/jenkins-x-extension-definitions.yaml", definitionsUrl)
+		definitionsFileUrl := gits.GitHubRawContentURL(gits.GitHubURL, org, repo, resolvedTag, "jenkins-x-extension-definitions.yaml")
 		extensionDefinitions := jenkinsv1.ExtensionDefinitionList{}
 		extensionDefinitions.LoadFromURL(definitionsFileUrl, remote, resolvedTag)
 		for _, ed := range extensionDefinitions.Extensions {
@@ -255,7 +255,7 @@ func (o *UpgradeExtensionsRepositoryOptions) walkRemote(remote string, tag strin
 					if scriptFile == "" {
 						scriptFile = fmt.Sprintf("%s.sh", strings.ToLower(strcase.SnakeCase(ed.Name)))
 					}
-					script, err = o.LoadAsStringFromURL(fmt.Sprintf("%s/%s", definitionsUrl, scriptFile))
+					script, err = o.LoadAsStringFromURL(gits.GitHubRawContentURL(gits.GitHubURL, org, repo, resolvedTag, scriptFile))
 					if err != nil {
 						return result, err
 					}
@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"io"
+	"io/ioutil"
+	"os"
 	"strings"
 
 	"github.com/jenkins-x/jx/pkg/helm"
@@ -11,16 +13,23 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var (
 	upgrade_platform_long = templates.LongDesc(`
 		Upgrades the Jenkins X platform if there is a newer release
+
+		Use --diff to preview the changes to the computed values and the rendered manifests of the platform chart
+		before confirming the upgrade.
 `)
 
 	upgrade_platform_example = templates.Examples(`
 		# Upgrades the Jenkins X platform 
 		jx upgrade platform
+
+		# Shows the changes of the upgrade before upgrading
+		jx upgrade platform --diff
 	`)
 )
 
@@ -34,6 +43,8 @@ type UpgradePlatformOptions struct {
 	Namespace     string
 	Set           string
 	AlwaysUpgrade bool
+	Diff          bool
+	Confirm       bool
 
 	InstallFlags InstallFlags
 }
@@ -70,6 +81,8 @@ func NewCmdUpgradePlatform(f Factory, in terminal.FileReader, out terminal.FileW
 	cmd.Flags().StringVarP(&options.Version, "version", "v", "", "The specific platform version to upgrade to")
 	cmd.Flags().StringVarP(&options.Set, "set", "s", "", "The helm parameters to pass in while upgrading")
 	cmd.Flags().BoolVarP(&options.AlwaysUpgrade, "always-upgrade", "", false, "If set to true, jx will upgrade platform Helm chart even if requested version is already installed.")
+	cmd.Flags().BoolVarP(&options.Diff, "diff", "", false, "Shows the changes to the values and manifests of the platform chart and asks for confirmation before upgrading")
+	cmd.Flags().BoolVarP(&options.Confirm, "yes", "y", false, "Confirms the upgrade after showing the changes in batch mode")
 
 	options.addCommonFlags(cmd)
 	options.InstallFlags.addCloudEnvOptions(cmd)
@@ -142,10 +155,56 @@ func (o *UpgradePlatformOptions) Run() error {
 	if err != nil {
 		return errors.Wrap(err, "failed to append the myvalues.yaml file")
 	}
+	userValuesFile, err := o.loadInstallUserValues(ns)
+	if err != nil {
+		return err
+	}
+	if userValuesFile != "" {
+		defer os.Remove(userValuesFile)
+		valueFiles = append(valueFiles, userValuesFile)
+	}
 
 	values := []string{}
 	if o.Set != "" {
 		values = append(values, o.Set)
 	}
+	if o.Diff {
+		upgrade, err := o.diffPlatform(ns, targetVersion, values, valueFiles)
+		if err != nil {
+			return err
+		}
+		if !upgrade {
+			return nil
+		}
+	}
 	return o.Helm().UpgradeChart(o.Chart, o.ReleaseName, ns, &targetVersion, false, nil, false, false, values, valueFiles)
 }
+
+// loadInstallUserValues writes the values files passed to 'jx install --values' to a temporary file so the upgrade
+// re-applies them, returning an empty file name if there were none
+func (o *UpgradePlatformOptions) loadInstallUserValues(ns string) (string, error) {
+	client, _, err := o.KubeClient()
+	if err != nil {
+		return "", err
+	}
+	secret, err := client.CoreV1().Secrets(ns).Get(JXInstallConfig, metav1.GetOptions{})
+	if err != nil {
+		log.Warnf("Failed to load the secret %s so any values passed to jx install via --values are not applied: %s\n", JXInstallConfig, err)
+		return "", nil
+	}
+	data := secret.Data[UserValuesFile]
+	if len(data) == 0 {
+		return "", nil
+	}
+	f, err := ioutil.TempFile("", "user-values-")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	_, err = f.Write(data)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to write the user values file")
+	}
+	log.Infof("Applying the values passed to jx install via --values\n")
+	return f.Name(), nil
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/helm"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// diffPlatform prints the changes of the computed values and the rendered manifests between the deployed platform
+// release and the target version of the chart, returning false if the user does not want to upgrade
+func (o *UpgradePlatformOptions) diffPlatform(ns string, version string, values []string, valueFiles []string) (bool, error) {
+	currentValues, err := o.Helm().GetReleaseValues(ns, o.ReleaseName)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get the values of the release %s", o.ReleaseName)
+	}
+	currentValues, err = normalizeValuesYaml(currentValues)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse the values of the release %s", o.ReleaseName)
+	}
+	currentManifests, err := o.Helm().GetReleaseManifest(ns, o.ReleaseName)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get the manifests of the release %s", o.ReleaseName)
+	}
+
+	tmpDir, err := ioutil.TempDir("", "upgrade-platform-")
+	if err != nil {
+		return false, err
+	}
+	defer os.RemoveAll(tmpDir)
+	helmBinary := o.Helm().HelmBinary()
+	err = o.RunCommand(helmBinary, "fetch", o.Chart, "--version", version, "--untar", "--untardir", tmpDir)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to fetch version %s of the chart %s", version, o.Chart)
+	}
+	chartDir := filepath.Join(tmpDir, path.Base(o.Chart))
+
+	targetValues, err := targetPlatformValues(chartDir, values, valueFiles)
+	if err != nil {
+		return false, err
+	}
+	manifestsDir := filepath.Join(tmpDir, "manifests")
+	err = os.MkdirAll(manifestsDir, DefaultWritePermissions)
+	if err != nil {
+		return false, err
+	}
+	cli := helm.NewHelmCLI(helmBinary, helm.BinaryVersion(helmBinary), tmpDir, o.Verbose)
+	err = cli.Template(chartDir, o.ReleaseName, ns, manifestsDir, true, values, valueFiles)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to render version %s of the chart %s", version, o.Chart)
+	}
+	targetManifests, err := helm.LoadManifestsDir(manifestsDir)
+	if err != nil {
+		return false, err
+	}
+
+	valuesDiff, err := util.UnifiedDiff(currentValues, targetValues, "values.yaml (deployed)", fmt.Sprintf("values.yaml (%s)", version))
+	if err != nil {
+		return false, err
+	}
+	manifestsDiff, err := util.UnifiedDiff(helm.SortManifests(currentManifests), helm.SortManifests(targetManifests),
+		"manifests (deployed)", fmt.Sprintf("manifests (%s)", version))
+	if err != nil {
+		return false, err
+	}
+	if valuesDiff == "" && manifestsDiff == "" {
+		log.Infof("There are no changes to the values or the manifests of the release %s\n", util.ColorInfo(o.ReleaseName))
+	} else {
+		fmt.Fprintln(o.Out, util.ColorDiff(valuesDiff+manifestsDiff))
+	}
+
+	if o.BatchMode {
+		if !o.Confirm {
+			return false, fmt.Errorf("In batch mode you must specify the '-y' flag to confirm the upgrade")
+		}
+		return true, nil
+	}
+	return util.Confirm(fmt.Sprintf("Upgrade the release %s to version %s?", o.ReleaseName, version), false,
+		"Upgrades the platform with the changes above", o.In, o.Out, o.Err), nil
+}
+
+// targetPlatformValues computes the values of the chart in the given dir merged with the values files and the
+// --set values the same way helm does
+func targetPlatformValues(chartDir string, values []string, valueFiles []string) (string, error) {
+	answer := map[string]interface{}{}
+	defaultValuesFile := filepath.Join(chartDir, "values.yaml")
+	exists, err := util.FileExists(defaultValuesFile)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		answer, err = helm.LoadValuesFile(defaultValuesFile)
+		if err != nil {
+			return "", err
+		}
+	}
+	for _, valueFile := range valueFiles {
+		overrides, err := helm.LoadValuesFile(valueFile)
+		if err != nil {
+			return "", err
+		}
+		helm.MergeValues(answer, overrides)
+	}
+	for _, value := range values {
+		overrides, err := parseSetValues(value)
+		if err != nil {
+			return "", err
+		}
+		helm.MergeValues(answer, overrides)
+	}
+	data, err := yaml.Marshal(answer)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal the computed values")
+	}
+	return string(data), nil
+}
+
+// parseSetValues parses the comma separated key=value pairs of a helm --set value into nested values
+func parseSetValues(set string) (map[string]interface{}, error) {
+	answer := map[string]interface{}{}
+	for _, pair := range strings.Split(set, ",") {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("the set value %s is not of the form key=value", pair)
+		}
+		var value interface{}
+		err := yaml.Unmarshal([]byte(parts[1]), &value)
+		if err != nil {
+			value = parts[1]
+		}
+		values := answer
+		keys := strings.Split(parts[0], ".")
+		for _, key := range keys[:len(keys)-1] {
+			child, ok := values[key].(map[string]interface{})
+			if !ok {
+				child = map[string]interface{}{}
+				values[key] = child
+			}
+			values = child
+		}
+		values[keys[len(keys)-1]] = value
+	}
+	return answer, nil
+}
+
+// normalizeValuesYaml marshals the values YAML the same way as the computed target values so they can be compared
+func normalizeValuesYaml(text string) (string, error) {
+	values := map[string]interface{}{}
+	err := yaml.Unmarshal([]byte(text), &values)
+	if err != nil {
+		return "", err
+	}
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
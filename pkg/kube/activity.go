@@ -3,6 +3,7 @@ package kube
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -42,6 +43,8 @@ type PromoteStepActivityKey struct {
 
 type PromotePullRequestFn func(*v1.PipelineActivity, *v1.PipelineActivityStep, *v1.PromoteActivityStep, *v1.PromotePullRequestStep) error
 type PromoteUpdateFn func(*v1.PipelineActivity, *v1.PipelineActivityStep, *v1.PromoteActivityStep, *v1.PromoteUpdateStep) error
+type RollbackFn func(*v1.PipelineActivity, *v1.PipelineActivityStep, *v1.RollbackActivityStep) error
+type ScanFn func(*v1.PipelineActivity, *v1.PipelineActivityStep, *v1.ScanActivityStep) error
 
 type PipelineDetails struct {
 	GitOwner      string
@@ -440,6 +443,114 @@ func (k *PromoteStepActivityKey) OnPromoteUpdate(activities typev1.PipelineActiv
 	return err
 }
 
+// GetOrCreateRollback gets the Rollback step for the key which has not completed yet or creates a new one
+func (k *PromoteStepActivityKey) GetOrCreateRollback(activities typev1.PipelineActivityInterface) (*v1.PipelineActivity, *v1.PipelineActivityStep, *v1.RollbackActivityStep, bool, error) {
+	a, _, err := k.GetOrCreate(activities)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	spec := &a.Spec
+	for i := range spec.Steps {
+		step := &spec.Steps[i]
+		if k.matchesRollback(step) {
+			return a, step, step.Rollback, false, nil
+		}
+	}
+	rollback := &v1.RollbackActivityStep{
+		CoreActivityStep: v1.CoreActivityStep{
+			StartedTimestamp: &metav1.Time{
+				Time: time.Now(),
+			},
+		},
+		Environment: k.Environment,
+	}
+	spec.Steps = append(spec.Steps, v1.PipelineActivityStep{
+		Kind:     v1.ActivityStepKindTypeRollback,
+		Rollback: rollback,
+	})
+	return a, &spec.Steps[len(spec.Steps)-1], rollback, true, nil
+}
+
+// OnRollback invokes the function on the Rollback step for the key updating the PipelineActivity if it changes
+func (k *PromoteStepActivityKey) OnRollback(activities typev1.PipelineActivityInterface, fn RollbackFn) error {
+	if !k.IsValid() {
+		return nil
+	}
+	if activities == nil {
+		log.Warn("Warning: no PipelineActivities client available!")
+		return nil
+	}
+	a, s, r, added, err := k.GetOrCreateRollback(activities)
+	if err != nil {
+		return err
+	}
+	r1 := asYaml(a)
+	err = fn(a, s, r)
+	if err != nil {
+		return err
+	}
+	r2 := asYaml(a)
+
+	if added || r1 == "" || r1 != r2 {
+		_, err = activities.Update(a)
+	}
+	return err
+}
+
+// GetOrCreateScan gets the Scan step of the image or creates a new one
+func (k *PipelineActivityKey) GetOrCreateScan(activities typev1.PipelineActivityInterface, image string) (*v1.PipelineActivity, *v1.PipelineActivityStep, *v1.ScanActivityStep, bool, error) {
+	a, _, err := k.GetOrCreate(activities)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	spec := &a.Spec
+	for i := range spec.Steps {
+		step := &spec.Steps[i]
+		if step.Scan != nil && step.Scan.Image == image {
+			return a, step, step.Scan, false, nil
+		}
+	}
+	scan := &v1.ScanActivityStep{
+		CoreActivityStep: v1.CoreActivityStep{
+			StartedTimestamp: &metav1.Time{
+				Time: time.Now(),
+			},
+		},
+		Image: image,
+	}
+	spec.Steps = append(spec.Steps, v1.PipelineActivityStep{
+		Kind: v1.ActivityStepKindTypeScan,
+		Scan: scan,
+	})
+	return a, &spec.Steps[len(spec.Steps)-1], scan, true, nil
+}
+
+// OnScan invokes the function on the Scan step of the image updating the PipelineActivity if it changes
+func (k *PipelineActivityKey) OnScan(activities typev1.PipelineActivityInterface, image string, fn ScanFn) error {
+	if !k.IsValid() {
+		return nil
+	}
+	if activities == nil {
+		log.Warn("Warning: no PipelineActivities client available!")
+		return nil
+	}
+	a, s, scan, added, err := k.GetOrCreateScan(activities, image)
+	if err != nil {
+		return err
+	}
+	r1 := asYaml(a)
+	err = fn(a, s, scan)
+	if err != nil {
+		return err
+	}
+	r2 := asYaml(a)
+
+	if added || r1 == "" || r1 != r2 {
+		_, err = activities.Update(a)
+	}
+	return err
+}
+
 func asYaml(activity *v1.PipelineActivity) string {
 	data, err := yaml.Marshal(activity)
 	if err == nil {
@@ -458,3 +569,29 @@ func (k *PromoteStepActivityKey) matchesPromote(step *v1.PipelineActivityStep) b
 	s := step.Promote
 	return s != nil && s.Environment == k.Environment
 }
+
+func (k *PromoteStepActivityKey) matchesRollback(step *v1.PipelineActivityStep) bool {
+	s := step.Rollback
+	return s != nil && s.Environment == k.Environment && s.CompletedTimestamp == nil
+}
+
+// ActivityStartTime returns the time when the pipeline started or when its activity was created if it has not
+// started yet
+func ActivityStartTime(activity *v1.PipelineActivity) time.Time {
+	if activity.Spec.StartedTimestamp != nil {
+		return activity.Spec.StartedTimestamp.Time
+	}
+	return activity.CreationTimestamp.Time
+}
+
+// SortActivitiesByStartTime sorts the activities by their start time with the latest first
+func SortActivitiesByStartTime(activities []v1.PipelineActivity) {
+	sort.SliceStable(activities, func(i, j int) bool {
+		t1 := ActivityStartTime(&activities[i])
+		t2 := ActivityStartTime(&activities[j])
+		if !t1.Equal(t2) {
+			return t1.After(t2)
+		}
+		return activities[i].Name < activities[j].Name
+	})
+}
@@ -152,3 +152,45 @@ func FailedPromotionUpdate(a *v1.PipelineActivity, s *v1.PipelineActivityStep, p
 	p.Status = v1.ActivityStatusTypeFailed
 	return nil
 }
+
+// StartRollback marks the Rollback step and its PipelineActivity as running
+func StartRollback(a *v1.PipelineActivity, s *v1.PipelineActivityStep, r *v1.RollbackActivityStep) error {
+	if r.StartedTimestamp == nil {
+		r.StartedTimestamp = &metav1.Time{
+			Time: time.Now(),
+		}
+	}
+	if r.Status == v1.ActivityStatusTypeNone {
+		r.Status = v1.ActivityStatusTypeRunning
+	}
+	if a.Spec.Status != v1.ActivityStatusTypeRunning {
+		a.Spec.Status = v1.ActivityStatusTypeRunning
+	}
+	return nil
+}
+
+// CompleteRollback marks the Rollback step as succeeded
+func CompleteRollback(a *v1.PipelineActivity, s *v1.PipelineActivityStep, r *v1.RollbackActivityStep) error {
+	StartRollback(a, s, r)
+	if r.CompletedTimestamp == nil {
+		r.CompletedTimestamp = &metav1.Time{
+			Time: time.Now(),
+		}
+	}
+	r.Status = v1.ActivityStatusTypeSucceeded
+	a.Spec.Status = v1.ActivityStatusTypeSucceeded
+	return nil
+}
+
+// FailedRollback marks the Rollback step as failed
+func FailedRollback(a *v1.PipelineActivity, s *v1.PipelineActivityStep, r *v1.RollbackActivityStep) error {
+	StartRollback(a, s, r)
+	if r.CompletedTimestamp == nil {
+		r.CompletedTimestamp = &metav1.Time{
+			Time: time.Now(),
+		}
+	}
+	r.Status = v1.ActivityStatusTypeFailed
+	a.Spec.Status = v1.ActivityStatusTypeFailed
+	return nil
+}
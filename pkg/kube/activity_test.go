@@ -191,6 +191,83 @@ func TestCreateOrUpdateActivities(t *testing.T) {
 	//tests.Debugf("Has Promote %#v\n", promote)
 }
 
+func TestRollbackActivity(t *testing.T) {
+	t.Parallel()
+	activities := &MockPipelineActivityInterface{
+		Activities: map[string]*v1.PipelineActivity{},
+	}
+	promoteKey := kube.PromoteStepActivityKey{
+		PipelineActivityKey: kube.PipelineActivityKey{
+			Name:     "demo-3",
+			Pipeline: "demo",
+			Build:    "3",
+		},
+		Environment: "production",
+	}
+
+	started := func(a *v1.PipelineActivity, s *v1.PipelineActivityStep, r *v1.RollbackActivityStep) error {
+		kube.StartRollback(a, s, r)
+		r.FromVersion = "1.0.2"
+		r.ToVersion = "1.0.1"
+		return nil
+	}
+	assert.NoError(t, promoteKey.OnRollback(activities, started))
+	assert.NoError(t, promoteKey.OnRollback(activities, kube.CompleteRollback))
+
+	a := activities.Activities["demo-3"]
+	if assert.NotNil(t, a) && assert.Equal(t, 1, len(a.Spec.Steps), "the rollback step is reused until it completes") {
+		step := a.Spec.Steps[0]
+		assert.Equal(t, v1.ActivityStepKindTypeRollback, step.Kind)
+		rollback := step.Rollback
+		if assert.NotNil(t, rollback) {
+			assert.Equal(t, "production", rollback.Environment)
+			assert.Equal(t, "1.0.2", rollback.FromVersion)
+			assert.Equal(t, "1.0.1", rollback.ToVersion)
+			assert.Equal(t, v1.ActivityStatusTypeSucceeded, rollback.Status)
+			assert.NotNil(t, rollback.CompletedTimestamp)
+		}
+	}
+
+	assert.NoError(t, promoteKey.OnRollback(activities, started))
+	assert.Equal(t, 2, len(activities.Activities["demo-3"].Spec.Steps), "a new rollback step is added after the previous one completed")
+}
+
+func TestScanActivity(t *testing.T) {
+	t.Parallel()
+	activities := &MockPipelineActivityInterface{
+		Activities: map[string]*v1.PipelineActivity{},
+	}
+	key := kube.PipelineActivityKey{
+		Name:     "demo-4",
+		Pipeline: "demo",
+		Build:    "4",
+	}
+
+	scanned := func(status v1.ActivityStatusType, critical int) kube.ScanFn {
+		return func(a *v1.PipelineActivity, s *v1.PipelineActivityStep, scan *v1.ScanActivityStep) error {
+			scan.Status = status
+			scan.Vulnerabilities = map[string]int{"CRITICAL": critical}
+			return nil
+		}
+	}
+	assert.NoError(t, key.OnScan(activities, "myorg/demo:0.0.4", scanned(v1.ActivityStatusTypeFailed, 1)))
+	assert.NoError(t, key.OnScan(activities, "myorg/demo:0.0.4", scanned(v1.ActivityStatusTypeSucceeded, 0)))
+	assert.NoError(t, key.OnScan(activities, "myorg/demo-sidecar:0.0.4", scanned(v1.ActivityStatusTypeSucceeded, 0)))
+
+	a := activities.Activities["demo-4"]
+	if assert.NotNil(t, a) && assert.Equal(t, 2, len(a.Spec.Steps), "there is one scan step per image") {
+		step := a.Spec.Steps[0]
+		assert.Equal(t, v1.ActivityStepKindTypeScan, step.Kind)
+		scan := step.Scan
+		if assert.NotNil(t, scan) {
+			assert.Equal(t, "myorg/demo:0.0.4", scan.Image)
+			assert.Equal(t, v1.ActivityStatusTypeSucceeded, scan.Status)
+			assert.Equal(t, map[string]int{"CRITICAL": 0}, scan.Vulnerabilities)
+			assert.NotNil(t, scan.StartedTimestamp)
+		}
+	}
+}
+
 func TestCreatePipelineDetails(t *testing.T) {
 	expectedGitOwner := "jstrachan"
 	expectedGitRepo := "myapp"
@@ -231,3 +308,27 @@ func TestCreatePipelineDetails(t *testing.T) {
 		}
 	}
 }
+
+func TestSortActivitiesByStartTime(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	started := metav1.NewTime(now.Add(-time.Hour))
+	activities := []v1.PipelineActivity{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "oldest", CreationTimestamp: metav1.NewTime(now.Add(-2 * time.Hour))},
+			Spec:       v1.PipelineActivitySpec{StartedTimestamp: &started},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "pending", CreationTimestamp: metav1.NewTime(now)},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "older", CreationTimestamp: metav1.NewTime(now.Add(-time.Minute))},
+		},
+	}
+	kube.SortActivitiesByStartTime(activities)
+	names := []string{}
+	for _, activity := range activities {
+		names = append(names, activity.Name)
+	}
+	assert.Equal(t, []string{"pending", "older", "oldest"}, names)
+}
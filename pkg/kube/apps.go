@@ -0,0 +1,100 @@
+package kube
+
+import (
+	"sort"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	appConfigMapPrefix = "app-"
+
+	appDataName        = "name"
+	appDataChart       = "chart"
+	appDataRepository  = "repository"
+	appDataVersion     = "version"
+	appDataReleaseName = "release"
+	appDataNamespace   = "namespace"
+)
+
+// App an app installed with helm into a development environment which does not use GitOps. Its values are stored
+// in the helm release rather than in the ConfigMap
+type App struct {
+	Name        string
+	Chart       string
+	Repository  string
+	Version     string
+	ReleaseName string
+	Namespace   string
+}
+
+// AppConfigMapName returns the name of the ConfigMap recording the app
+func AppConfigMapName(name string) string {
+	return ToValidName(appConfigMapPrefix + name)
+}
+
+// SaveApp creates or updates the ConfigMap recording the app
+func SaveApp(client kubernetes.Interface, ns string, app *App) error {
+	configMaps := client.CoreV1().ConfigMaps(ns)
+	data := map[string]string{
+		appDataName:        app.Name,
+		appDataChart:       app.Chart,
+		appDataRepository:  app.Repository,
+		appDataVersion:     app.Version,
+		appDataReleaseName: app.ReleaseName,
+		appDataNamespace:   app.Namespace,
+	}
+	name := AppConfigMapName(app.Name)
+	cm, err := configMaps.Get(name, meta_v1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		cm = &v1.ConfigMap{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					LabelKind: ValueKindApp,
+				},
+			},
+			Data: data,
+		}
+		_, err = configMaps.Create(cm)
+		return err
+	}
+	cm.Data = data
+	_, err = configMaps.Update(cm)
+	return err
+}
+
+// GetApps returns the apps recorded in the namespace sorted by name
+func GetApps(client kubernetes.Interface, ns string) ([]*App, error) {
+	list, err := client.CoreV1().ConfigMaps(ns).List(meta_v1.ListOptions{
+		LabelSelector: LabelKind + "=" + ValueKindApp,
+	})
+	if err != nil {
+		return nil, err
+	}
+	answer := []*App{}
+	for _, cm := range list.Items {
+		data := cm.Data
+		if data[appDataName] == "" {
+			continue
+		}
+		answer = append(answer, &App{
+			Name:        data[appDataName],
+			Chart:       data[appDataChart],
+			Repository:  data[appDataRepository],
+			Version:     data[appDataVersion],
+			ReleaseName: data[appDataReleaseName],
+			Namespace:   data[appDataNamespace],
+		})
+	}
+	sort.Slice(answer, func(i, j int) bool {
+		return answer[i].Name < answer[j].Name
+	})
+	return answer, nil
+}
@@ -0,0 +1,54 @@
+package kube_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	kube_mocks "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSaveApps(t *testing.T) {
+	t.Parallel()
+	client := kube_mocks.NewSimpleClientset()
+	ns := "jx"
+
+	apps, err := kube.GetApps(client, ns)
+	require.NoError(t, err)
+	assert.Empty(t, apps)
+
+	for _, name := range []string{"jx-app-jacoco", "db"} {
+		err = kube.SaveApp(client, ns, &kube.App{
+			Name:        name,
+			Chart:       name,
+			Repository:  "https://charts.acme.com",
+			Version:     "1.0.0",
+			ReleaseName: name,
+			Namespace:   ns,
+		})
+		require.NoError(t, err)
+	}
+	err = kube.SaveApp(client, ns, &kube.App{
+		Name:        "db",
+		Chart:       "postgresql",
+		Repository:  "https://charts.acme.com",
+		Version:     "1.1.0",
+		ReleaseName: "db",
+		Namespace:   "data",
+	})
+	require.NoError(t, err)
+
+	apps, err = kube.GetApps(client, ns)
+	require.NoError(t, err)
+	require.Len(t, apps, 2)
+	assert.Equal(t, &kube.App{
+		Name:        "db",
+		Chart:       "postgresql",
+		Repository:  "https://charts.acme.com",
+		Version:     "1.1.0",
+		ReleaseName: "db",
+		Namespace:   "data",
+	}, apps[0])
+	assert.Equal(t, "jx-app-jacoco", apps[1].Name)
+}
@@ -0,0 +1,207 @@
+package kube
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// CanaryAPIVersion the API version of the Flagger canary resources
+	CanaryAPIVersion = "flagger.app/v1alpha3"
+
+	// CanaryKind the kind of the Flagger canary resources
+	CanaryKind = "Canary"
+
+	// CanaryCRDName the name of the CustomResourceDefinition of the Flagger canary resources
+	CanaryCRDName = "canaries.flagger.app"
+
+	// IstioVirtualServiceCRDName the name of the CustomResourceDefinition of the Istio virtual services Flagger shifts
+	// the traffic of the canaries with
+	IstioVirtualServiceCRDName = "virtualservices.networking.istio.io"
+
+	// CanaryPrimarySuffix the suffix of the primary Deployment Flagger creates for the Deployment of a canary
+	CanaryPrimarySuffix = "-primary"
+
+	// CanaryPhaseInitialized the canary has created its primary Deployment
+	CanaryPhaseInitialized = "Initialized"
+
+	// CanaryPhaseProgressing the canary analysis is in progress
+	CanaryPhaseProgressing = "Progressing"
+
+	// CanaryPhaseSucceeded the canary analysis passed and the canary was promoted to the primary
+	CanaryPhaseSucceeded = "Succeeded"
+
+	// CanaryPhaseFailed the canary analysis failed and the canary was rolled back
+	CanaryPhaseFailed = "Failed"
+)
+
+// Canary the Flagger resource which rolls out a new version of a Deployment gradually
+type Canary struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CanarySpec    `json:"spec"`
+	Status *CanaryStatus `json:"status,omitempty"`
+}
+
+// CanarySpec the specification of a canary
+type CanarySpec struct {
+	TargetRef               CanaryTargetRef `json:"targetRef"`
+	ProgressDeadlineSeconds int             `json:"progressDeadlineSeconds,omitempty"`
+	Service                 CanaryService   `json:"service"`
+	CanaryAnalysis          CanaryAnalysis  `json:"canaryAnalysis"`
+}
+
+// CanaryTargetRef the Deployment a canary rolls out
+type CanaryTargetRef struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+}
+
+// CanaryService the port of the Service Flagger creates for a canary
+type CanaryService struct {
+	Port int `json:"port"`
+}
+
+// CanaryAnalysis the steps and checks of the analysis of a canary
+type CanaryAnalysis struct {
+	Interval   string         `json:"interval"`
+	Threshold  int            `json:"threshold"`
+	MaxWeight  int            `json:"maxWeight"`
+	StepWeight int            `json:"stepWeight"`
+	Metrics    []CanaryMetric `json:"metrics,omitempty"`
+}
+
+// CanaryMetric a metric checked at each step of the analysis of a canary
+type CanaryMetric struct {
+	Name      string `json:"name"`
+	Threshold int    `json:"threshold"`
+	Interval  string `json:"interval,omitempty"`
+}
+
+// CanaryStatus the status of a canary
+type CanaryStatus struct {
+	Phase              string      `json:"phase,omitempty"`
+	CanaryWeight       int         `json:"canaryWeight,omitempty"`
+	FailedChecks       int         `json:"failedChecks,omitempty"`
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// IsCanaryEnvironment returns true if promotions to the environment are rolled out as canaries
+func IsCanaryEnvironment(env *v1.Environment) bool {
+	return env != nil && env.Spec.PromotionStrategy == v1.PromotionStrategyTypeCanary
+}
+
+// CanaryFileName returns the name of the file of the canary of the application in the templates of an environment chart
+func CanaryFileName(app string) string {
+	return app + "-canary.yaml"
+}
+
+// NewCanary returns the canary for the application in an environment chart. The Deployment is referenced by the name
+// the application chart gives it inside the release of the environment
+func NewCanary(app string, settings v1.CanarySettings) *Canary {
+	return &Canary{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: CanaryAPIVersion,
+			Kind:       CanaryKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: app,
+		},
+		Spec: CanarySpec{
+			TargetRef: CanaryTargetRef{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       fmt.Sprintf(`{{ printf "%%s-%%s" .Release.Name "%s" | trunc 63 | trimSuffix "-" }}`, app),
+			},
+			ProgressDeadlineSeconds: 60,
+			Service: CanaryService{
+				Port: settings.Port,
+			},
+			CanaryAnalysis: CanaryAnalysis{
+				Interval:   settings.Interval,
+				Threshold:  settings.Threshold,
+				MaxWeight:  settings.MaxWeight,
+				StepWeight: settings.StepWeight,
+				Metrics: []CanaryMetric{
+					{
+						Name:      "request-success-rate",
+						Threshold: settings.RequestSuccessRate,
+						Interval:  settings.Interval,
+					},
+					{
+						Name:      "request-duration",
+						Threshold: settings.RequestDuration,
+						Interval:  settings.Interval,
+					},
+				},
+			},
+		},
+	}
+}
+
+// CanaryYAML returns the YAML of the canary for the application in an environment chart
+func CanaryYAML(app string, settings v1.CanarySettings) ([]byte, error) {
+	return yaml.Marshal(NewCanary(app, settings))
+}
+
+// GetCanary returns the canary with the name in the namespace or nil if there is none
+func GetCanary(kubeClient kubernetes.Interface, ns string, name string) (*Canary, error) {
+	data, err := kubeClient.CoreV1().RESTClient().Get().RequestURI(fmt.Sprintf("/apis/%s/namespaces/%s/canaries", CanaryAPIVersion, ns)).Name(name).DoRaw()
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	canary := &Canary{}
+	err = json.Unmarshal(data, canary)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the canary %s in namespace %s: %s", name, ns, err)
+	}
+	return canary, nil
+}
+
+// CanaryProgress returns a description of the progress of the canary such as 'Progressing 30%'
+func CanaryProgress(canary *Canary) string {
+	if canary == nil || canary.Status == nil || canary.Status.Phase == "" {
+		return ""
+	}
+	status := canary.Status
+	switch status.Phase {
+	case CanaryPhaseProgressing:
+		return fmt.Sprintf("%s %d%%", status.Phase, status.CanaryWeight)
+	case CanaryPhaseFailed:
+		return fmt.Sprintf("%s after %d failed checks", status.Phase, status.FailedChecks)
+	default:
+		return status.Phase
+	}
+}
+
+// CanaryResult returns whether the analysis of the canary which started at the given time has finished and an error if
+// it failed. A status which last changed before the analysis started belongs to a previous rollout
+func CanaryResult(canary *Canary, since time.Time) (bool, error) {
+	if canary == nil || canary.Status == nil {
+		return false, nil
+	}
+	status := canary.Status
+	if status.LastTransitionTime.Time.Before(since) {
+		return false, nil
+	}
+	switch status.Phase {
+	case CanaryPhaseInitialized, CanaryPhaseSucceeded:
+		return true, nil
+	case CanaryPhaseFailed:
+		return true, fmt.Errorf("the canary analysis of %s failed after %d failed checks so it was rolled back", canary.Name, status.FailedChecks)
+	default:
+		return false, nil
+	}
+}
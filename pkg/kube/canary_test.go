@@ -0,0 +1,106 @@
+package kube_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCanaryYAML(t *testing.T) {
+	t.Parallel()
+	teamSettings := v1.TeamSettings{
+		Canary: v1.CanarySettings{
+			StepWeight: 20,
+		},
+	}
+	data, err := kube.CanaryYAML("myapp", teamSettings.GetCanarySettings())
+	require.NoError(t, err)
+	text := string(data)
+
+	assert.Contains(t, text, "apiVersion: "+kube.CanaryAPIVersion)
+	assert.Contains(t, text, "kind: Canary")
+	assert.Contains(t, text, "name: myapp\n")
+	assert.Contains(t, text, `.Release.Name "myapp" | trunc 63`)
+	assert.Contains(t, text, "stepWeight: 20")
+	assert.Contains(t, text, "maxWeight: 50")
+	assert.Contains(t, text, "interval: 1m")
+	assert.Contains(t, text, "port: 8080")
+	assert.Contains(t, text, "name: request-success-rate")
+	assert.False(t, strings.Contains(text, "status:"), "the canary has no status")
+}
+
+func TestCanaryResult(t *testing.T) {
+	t.Parallel()
+	merged := time.Now()
+	before := metav1.Time{Time: merged.Add(-time.Hour)}
+	after := metav1.Time{Time: merged.Add(time.Minute)}
+
+	canary := func(phase string, transition metav1.Time) *kube.Canary {
+		return &kube.Canary{
+			ObjectMeta: metav1.ObjectMeta{Name: "myapp"},
+			Status: &kube.CanaryStatus{
+				Phase:              phase,
+				CanaryWeight:       30,
+				FailedChecks:       5,
+				LastTransitionTime: transition,
+			},
+		}
+	}
+
+	done, err := kube.CanaryResult(nil, merged)
+	assert.False(t, done)
+	assert.NoError(t, err)
+
+	done, err = kube.CanaryResult(canary(kube.CanaryPhaseSucceeded, before), merged)
+	assert.False(t, done, "the status of a previous rollout")
+
+	done, err = kube.CanaryResult(canary(kube.CanaryPhaseProgressing, after), merged)
+	assert.False(t, done)
+	assert.NoError(t, err)
+
+	done, err = kube.CanaryResult(canary(kube.CanaryPhaseSucceeded, after), merged)
+	assert.True(t, done)
+	assert.NoError(t, err)
+
+	done, err = kube.CanaryResult(canary(kube.CanaryPhaseInitialized, after), merged)
+	assert.True(t, done)
+	assert.NoError(t, err)
+
+	done, err = kube.CanaryResult(canary(kube.CanaryPhaseFailed, after), merged)
+	assert.True(t, done)
+	assert.Error(t, err)
+}
+
+func TestCanaryProgress(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "", kube.CanaryProgress(nil))
+	assert.Equal(t, "Progressing 30%", kube.CanaryProgress(&kube.Canary{
+		Status: &kube.CanaryStatus{Phase: kube.CanaryPhaseProgressing, CanaryWeight: 30},
+	}))
+	assert.Equal(t, "Failed after 5 failed checks", kube.CanaryProgress(&kube.Canary{
+		Status: &kube.CanaryStatus{Phase: kube.CanaryPhaseFailed, FailedChecks: 5},
+	}))
+	assert.Equal(t, "Succeeded", kube.CanaryProgress(&kube.Canary{
+		Status: &kube.CanaryStatus{Phase: kube.CanaryPhaseSucceeded},
+	}))
+}
+
+func TestParsePromotionStrategyType(t *testing.T) {
+	t.Parallel()
+	strategy, err := v1.ParsePromotionStrategyType("canary")
+	require.NoError(t, err)
+	assert.Equal(t, v1.PromotionStrategyTypeCanary, strategy)
+
+	strategy, err = v1.ParsePromotionStrategyType("")
+	require.NoError(t, err)
+	assert.Equal(t, v1.PromotionStrategyType(""), strategy)
+
+	_, err = v1.ParsePromotionStrategyType("sometimes")
+	assert.Error(t, err)
+}
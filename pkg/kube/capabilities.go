@@ -0,0 +1,191 @@
+package kube
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// CapabilityResult the result of a cluster capability check
+type CapabilityResult string
+
+const (
+	// CapabilityPass the cluster has the capability
+	CapabilityPass CapabilityResult = "pass"
+	// CapabilityWarn the cluster may lack the capability, which could break some of Jenkins X
+	CapabilityWarn CapabilityResult = "warn"
+	// CapabilityFail the cluster lacks the capability so Jenkins X will not work
+	CapabilityFail CapabilityResult = "fail"
+
+	dockerRuntime = "docker"
+)
+
+// CapabilityCheck the result of checking a capability of the cluster Jenkins X relies on
+type CapabilityCheck struct {
+	Name    string
+	Result  CapabilityResult
+	Message string
+}
+
+// CheckClusterCapabilities checks that the cluster has a default StorageClass, that RBAC is enabled and the user can
+// create ClusterRoleBindings, whether LoadBalancer services get an external address, that the nodes run a container
+// runtime the builders support and whether PodSecurityPolicies could block the Jenkins agents
+func CheckClusterCapabilities(client kubernetes.Interface) []CapabilityCheck {
+	return []CapabilityCheck{
+		CheckDefaultStorageClass(client),
+		CheckClusterRoleBindingAccess(client),
+		CheckLoadBalancers(client),
+		CheckContainerRuntime(client),
+		CheckPodSecurityPolicies(client),
+	}
+}
+
+// CapabilityChecksFailed returns true if any of the checks failed
+func CapabilityChecksFailed(checks []CapabilityCheck) bool {
+	for _, check := range checks {
+		if check.Result == CapabilityFail {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckDefaultStorageClass checks there is a default StorageClass, without which the persistent volume claims of
+// Jenkins, Nexus and ChartMuseum are never bound
+func CheckDefaultStorageClass(client kubernetes.Interface) CapabilityCheck {
+	check := CapabilityCheck{Name: "Default StorageClass"}
+	list, err := client.StorageV1().StorageClasses().List(metav1.ListOptions{})
+	if err != nil {
+		return check.warn("could not list the StorageClasses: %s", err)
+	}
+	for _, sc := range list.Items {
+		if sc.Annotations[AnnotationIsDefaultStorageClass] == "true" || sc.Annotations[AnnotationIsBetaDefaultStorageClass] == "true" {
+			return check.pass("%s", sc.Name)
+		}
+	}
+	return check.fail("there is no default StorageClass so persistent volume claims will not be bound")
+}
+
+// CheckClusterRoleBindingAccess checks RBAC is enabled and the current user can create ClusterRoleBindings
+func CheckClusterRoleBindingAccess(client kubernetes.Interface) CapabilityCheck {
+	check := CapabilityCheck{Name: "RBAC"}
+	_, err := client.RbacV1().ClusterRoles().List(metav1.ListOptions{})
+	if errors.IsNotFound(err) {
+		return check.fail("RBAC is not enabled on the cluster")
+	}
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Verb:     "create",
+				Group:    "rbac.authorization.k8s.io",
+				Resource: "clusterrolebindings",
+			},
+		},
+	}
+	review, err = client.AuthorizationV1().SelfSubjectAccessReviews().Create(review)
+	if err != nil {
+		return check.warn("could not check access to ClusterRoleBindings: %s", err)
+	}
+	if !review.Status.Allowed {
+		return check.fail("the current user cannot create ClusterRoleBindings, please ask a cluster administrator to grant you the cluster-admin role")
+	}
+	return check.pass("the current user can create ClusterRoleBindings")
+}
+
+// CheckLoadBalancers checks whether the existing LoadBalancer services got an external address. If none did the
+// cluster may only support NodePort services
+func CheckLoadBalancers(client kubernetes.Interface) CapabilityCheck {
+	check := CapabilityCheck{Name: "LoadBalancer services"}
+	list, err := client.CoreV1().Services("").List(metav1.ListOptions{})
+	if err != nil {
+		return check.warn("could not list the Services: %s", err)
+	}
+	pending := []string{}
+	for _, svc := range list.Items {
+		if svc.Spec.Type != v1.ServiceTypeLoadBalancer {
+			continue
+		}
+		if len(svc.Status.LoadBalancer.Ingress) > 0 {
+			return check.pass("Service %s/%s has an external address", svc.Namespace, svc.Name)
+		}
+		pending = append(pending, svc.Namespace+"/"+svc.Name)
+	}
+	if len(pending) > 0 {
+		return check.warn("Services %s have no external address so the cluster may only support NodePort services, use --on-premise or --external-ip", strings.Join(pending, ", "))
+	}
+	return check.warn("no LoadBalancer services found, if the cluster only supports NodePort services use --on-premise or --external-ip")
+}
+
+// CheckContainerRuntime checks the nodes run Docker, as the builders mount the Docker socket of the node
+func CheckContainerRuntime(client kubernetes.Interface) CapabilityCheck {
+	check := CapabilityCheck{Name: "Container runtime"}
+	list, err := client.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return check.warn("could not list the Nodes: %s", err)
+	}
+	if len(list.Items) == 0 {
+		return check.fail("the cluster has no nodes")
+	}
+	runtimes := map[string][]string{}
+	for _, node := range list.Items {
+		runtime := strings.SplitN(node.Status.NodeInfo.ContainerRuntimeVersion, "://", 2)[0]
+		runtimes[runtime] = append(runtimes[runtime], node.Name)
+	}
+	others := []string{}
+	for runtime, nodes := range runtimes {
+		if runtime != dockerRuntime {
+			others = append(others, fmt.Sprintf("%s on %s", runtime, strings.Join(nodes, ", ")))
+		}
+	}
+	if len(others) > 0 {
+		sort.Strings(others)
+		return check.warn("builders which use the Docker socket of the node will not work with %s", strings.Join(others, "; "))
+	}
+	return check.pass("all nodes run %s", dockerRuntime)
+}
+
+// CheckPodSecurityPolicies checks whether there are PodSecurityPolicies which could prevent the privileged Jenkins
+// agents which run Docker from starting
+func CheckPodSecurityPolicies(client kubernetes.Interface) CapabilityCheck {
+	check := CapabilityCheck{Name: "PodSecurityPolicies"}
+	list, err := client.PolicyV1beta1().PodSecurityPolicies().List(metav1.ListOptions{})
+	if errors.IsNotFound(err) {
+		return check.pass("PodSecurityPolicies are not supported by the cluster")
+	}
+	if err != nil {
+		return check.warn("could not list the PodSecurityPolicies: %s", err)
+	}
+	if len(list.Items) == 0 {
+		return check.pass("no PodSecurityPolicies")
+	}
+	for _, psp := range list.Items {
+		if psp.Spec.Privileged {
+			return check.pass("PodSecurityPolicy %s allows privileged containers", psp.Name)
+		}
+	}
+	return check.warn("no PodSecurityPolicy allows privileged containers so Jenkins agents which run Docker may be blocked")
+}
+
+func (c CapabilityCheck) pass(format string, a ...interface{}) CapabilityCheck {
+	return c.withResult(CapabilityPass, format, a...)
+}
+
+func (c CapabilityCheck) warn(format string, a ...interface{}) CapabilityCheck {
+	return c.withResult(CapabilityWarn, format, a...)
+}
+
+func (c CapabilityCheck) fail(format string, a ...interface{}) CapabilityCheck {
+	return c.withResult(CapabilityFail, format, a...)
+}
+
+func (c CapabilityCheck) withResult(result CapabilityResult, format string, a ...interface{}) CapabilityCheck {
+	c.Result = result
+	c.Message = fmt.Sprintf(format, a...)
+	return c
+}
@@ -0,0 +1,88 @@
+package kube_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	storagev1 "k8s.io/api/storage/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kube_mocks "k8s.io/client-go/kubernetes/fake"
+	k8s_testing "k8s.io/client-go/testing"
+)
+
+func TestCheckClusterCapabilities(t *testing.T) {
+	t.Parallel()
+
+	client := kube_mocks.NewSimpleClientset(
+		&storagev1.StorageClass{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name:        "standard",
+				Annotations: map[string]string{kube.AnnotationIsDefaultStorageClass: "true"},
+			},
+		},
+		&v1.Service{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "nginx-ingress-controller", Namespace: "kube-system"},
+			Spec:       v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+			Status: v1.ServiceStatus{
+				LoadBalancer: v1.LoadBalancerStatus{Ingress: []v1.LoadBalancerIngress{{IP: "1.2.3.4"}}},
+			},
+		},
+		&v1.Node{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "node-1"},
+			Status:     v1.NodeStatus{NodeInfo: v1.NodeSystemInfo{ContainerRuntimeVersion: "docker://18.9.7"}},
+		},
+	)
+	allowClusterRoleBindings(client, true)
+
+	checks := kube.CheckClusterCapabilities(client)
+	assert.Len(t, checks, 5)
+	for _, check := range checks {
+		assert.Equal(t, kube.CapabilityPass, check.Result, "%s: %s", check.Name, check.Message)
+	}
+	assert.False(t, kube.CapabilityChecksFailed(checks))
+}
+
+func TestCheckClusterCapabilitiesFailures(t *testing.T) {
+	t.Parallel()
+
+	client := kube_mocks.NewSimpleClientset(
+		&storagev1.StorageClass{ObjectMeta: meta_v1.ObjectMeta{Name: "slow"}},
+		&v1.Service{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "nginx-ingress-controller", Namespace: "kube-system"},
+			Spec:       v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+		},
+		&v1.Node{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "node-1"},
+			Status:     v1.NodeStatus{NodeInfo: v1.NodeSystemInfo{ContainerRuntimeVersion: "containerd://1.2.10"}},
+		},
+		&policyv1beta1.PodSecurityPolicy{ObjectMeta: meta_v1.ObjectMeta{Name: "restricted"}},
+	)
+	allowClusterRoleBindings(client, false)
+
+	assert.Equal(t, kube.CapabilityFail, kube.CheckDefaultStorageClass(client).Result)
+	assert.Equal(t, kube.CapabilityFail, kube.CheckClusterRoleBindingAccess(client).Result)
+
+	check := kube.CheckLoadBalancers(client)
+	assert.Equal(t, kube.CapabilityWarn, check.Result)
+	assert.Contains(t, check.Message, "kube-system/nginx-ingress-controller")
+
+	check = kube.CheckContainerRuntime(client)
+	assert.Equal(t, kube.CapabilityWarn, check.Result)
+	assert.Contains(t, check.Message, "containerd on node-1")
+
+	assert.Equal(t, kube.CapabilityWarn, kube.CheckPodSecurityPolicies(client).Result)
+	assert.True(t, kube.CapabilityChecksFailed(kube.CheckClusterCapabilities(client)))
+}
+
+func allowClusterRoleBindings(client *kube_mocks.Clientset, allowed bool) {
+	client.PrependReactor("create", "selfsubjectaccessreviews", func(action k8s_testing.Action) (bool, runtime.Object, error) {
+		review := action.(k8s_testing.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		review.Status.Allowed = allowed
+		return true, review, nil
+	})
+}
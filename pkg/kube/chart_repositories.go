@@ -0,0 +1,98 @@
+package kube
+
+import (
+	"strings"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// SecretDataChartRepositoryUsername the key of the username in the Secret of a private chart repository
+	SecretDataChartRepositoryUsername = "username"
+	// SecretDataChartRepositoryPassword the key of the password in the Secret of a private chart repository
+	SecretDataChartRepositoryPassword = "password"
+)
+
+// ChartRepositoryCredentials the credentials of a private chart repository
+type ChartRepositoryCredentials struct {
+	URL      string
+	Username string
+	Password string
+}
+
+// ChartRepositorySecretName returns the name of the Secret storing the credentials of the chart repository
+func ChartRepositorySecretName(repoURL string) string {
+	name := repoURL
+	idx := strings.Index(name, "://")
+	if idx >= 0 {
+		name = name[idx+3:]
+	}
+	return ToValidName("chart-repo-" + strings.Trim(name, "/"))
+}
+
+// CreateOrUpdateChartRepositorySecret stores the credentials of the chart repository in a Secret so that they are
+// never committed to the git repository of an environment
+func CreateOrUpdateChartRepositorySecret(client kubernetes.Interface, ns string, credentials *ChartRepositoryCredentials) (*v1.Secret, error) {
+	secrets := client.CoreV1().Secrets(ns)
+	name := ChartRepositorySecretName(credentials.URL)
+	data := map[string][]byte{
+		SecretDataChartRepositoryUsername: []byte(credentials.Username),
+		SecretDataChartRepositoryPassword: []byte(credentials.Password),
+	}
+	secret, err := secrets.Get(name, meta_v1.GetOptions{})
+	if errors.IsNotFound(err) {
+		secret = &v1.Secret{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					LabelKind: ValueKindChartRepository,
+				},
+				Annotations: map[string]string{
+					AnnotationChartRepositoryURL: credentials.URL,
+				},
+			},
+			Data: data,
+		}
+		return secrets.Create(secret)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if secret.Labels == nil {
+		secret.Labels = map[string]string{}
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Labels[LabelKind] = ValueKindChartRepository
+	secret.Annotations[AnnotationChartRepositoryURL] = credentials.URL
+	secret.Data = data
+	return secrets.Update(secret)
+}
+
+// GetChartRepositoryCredentials returns the credentials of the private chart repositories indexed by their URL
+// without a trailing slash
+func GetChartRepositoryCredentials(client kubernetes.Interface, ns string) (map[string]*ChartRepositoryCredentials, error) {
+	list, err := client.CoreV1().Secrets(ns).List(meta_v1.ListOptions{
+		LabelSelector: LabelKind + "=" + ValueKindChartRepository,
+	})
+	if err != nil {
+		return nil, err
+	}
+	answer := map[string]*ChartRepositoryCredentials{}
+	for _, secret := range list.Items {
+		repoURL := strings.TrimSuffix(secret.Annotations[AnnotationChartRepositoryURL], "/")
+		if repoURL == "" {
+			continue
+		}
+		answer[repoURL] = &ChartRepositoryCredentials{
+			URL:      repoURL,
+			Username: string(secret.Data[SecretDataChartRepositoryUsername]),
+			Password: string(secret.Data[SecretDataChartRepositoryPassword]),
+		}
+	}
+	return answer, nil
+}
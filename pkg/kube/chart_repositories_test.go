@@ -0,0 +1,40 @@
+package kube_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	kube_mocks "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestChartRepositoryCredentials(t *testing.T) {
+	t.Parallel()
+	client := kube_mocks.NewSimpleClientset()
+	ns := "jx"
+
+	assert.Equal(t, "chart-repo-charts-acme-com-private", kube.ChartRepositorySecretName("https://charts.acme.com/private/"))
+
+	_, err := kube.CreateOrUpdateChartRepositorySecret(client, ns, &kube.ChartRepositoryCredentials{
+		URL:      "https://charts.acme.com/private/",
+		Username: "admin",
+		Password: "old",
+	})
+	require.NoError(t, err)
+	secret, err := kube.CreateOrUpdateChartRepositorySecret(client, ns, &kube.ChartRepositoryCredentials{
+		URL:      "https://charts.acme.com/private/",
+		Username: "admin",
+		Password: "new",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, kube.ValueKindChartRepository, secret.Labels[kube.LabelKind])
+
+	credentials, err := kube.GetChartRepositoryCredentials(client, ns)
+	require.NoError(t, err)
+	require.Len(t, credentials, 1)
+	c := credentials["https://charts.acme.com/private"]
+	require.NotNil(t, c)
+	assert.Equal(t, "admin", c.Username)
+	assert.Equal(t, "new", c.Password)
+}
@@ -268,3 +268,21 @@ func RoleBindings(client kubernetes.Interface, namespace string) (string, error)
 
 	return result, nil
 }
+
+// ReadyNodeCount returns the number of nodes which are Ready and the total number of nodes
+func ReadyNodeCount(client kubernetes.Interface) (int, int, error) {
+	nodes, err := client.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return 0, 0, err
+	}
+	ready := 0
+	for _, node := range nodes.Items {
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == v1.NodeReady && condition.Status == v1.ConditionTrue {
+				ready++
+				break
+			}
+		}
+	}
+	return ready, len(nodes.Items), nil
+}
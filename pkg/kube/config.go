@@ -161,3 +161,60 @@ func AddUserToConfig(user string, token string, config *api.Config) (*api.Config
 
 	return config, nil
 }
+
+// MergeConfigFile merges the cluster and user of the current context of the kube config file into the default kube
+// config as a context of the given name, which becomes the current context. Any certificate files the kube config
+// file refers to are embedded so that the file can be removed
+func MergeConfigFile(fileName string, contextName string) error {
+	other, err := clientcmd.LoadFromFile(fileName)
+	if err != nil {
+		return errors.Wrapf(err, "loading kube config %s", fileName)
+	}
+	err = api.FlattenConfig(other)
+	if err != nil {
+		return errors.Wrapf(err, "embedding the certificates of kube config %s", fileName)
+	}
+	config, po, err := LoadConfig()
+	if err != nil {
+		return errors.Wrap(err, "loading existing config")
+	}
+	err = MergeConfig(config, other, contextName)
+	if err != nil {
+		return errors.Wrapf(err, "merging kube config %s", fileName)
+	}
+	return clientcmd.ModifyConfig(po, *config, false)
+}
+
+// MergeConfig adds the cluster and user of the current context of other to the config as a context of the given
+// name, which becomes the current context
+func MergeConfig(config *api.Config, other *api.Config, contextName string) error {
+	ctx := CurrentContext(other)
+	if ctx == nil {
+		return errors.New("no current context found in config")
+	}
+	cluster := other.Clusters[ctx.Cluster]
+	if cluster == nil {
+		return fmt.Errorf("no cluster %s found in config", ctx.Cluster)
+	}
+	if config.Clusters == nil {
+		config.Clusters = map[string]*api.Cluster{}
+	}
+	if config.AuthInfos == nil {
+		config.AuthInfos = map[string]*api.AuthInfo{}
+	}
+	if config.Contexts == nil {
+		config.Contexts = map[string]*api.Context{}
+	}
+	config.Clusters[contextName] = cluster
+	newContext := &api.Context{
+		Cluster:   contextName,
+		Namespace: ctx.Namespace,
+	}
+	if authInfo := other.AuthInfos[ctx.AuthInfo]; authInfo != nil {
+		config.AuthInfos[contextName] = authInfo
+		newContext.AuthInfo = contextName
+	}
+	config.Contexts[contextName] = newContext
+	config.CurrentContext = contextName
+	return nil
+}
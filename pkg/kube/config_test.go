@@ -0,0 +1,36 @@
+package kube_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestMergeConfig(t *testing.T) {
+	t.Parallel()
+
+	config := api.NewConfig()
+	config.Clusters["default"] = &api.Cluster{Server: "https://existing:6443"}
+	config.Contexts["default"] = &api.Context{Cluster: "default"}
+	config.CurrentContext = "default"
+
+	other := api.NewConfig()
+	other.Clusters["dev"] = &api.Cluster{Server: "https://10.0.0.5:6443"}
+	other.AuthInfos["admin"] = &api.AuthInfo{ClientCertificateData: []byte("cert")}
+	other.Contexts["default"] = &api.Context{Cluster: "dev", AuthInfo: "admin"}
+	other.CurrentContext = "default"
+
+	err := kube.MergeConfig(config, other, "openstack-dev")
+	require.NoError(t, err)
+	assert.Equal(t, "openstack-dev", config.CurrentContext)
+	assert.Equal(t, "https://existing:6443", config.Clusters["default"].Server, "existing clusters are kept")
+	assert.Equal(t, "https://10.0.0.5:6443", config.Clusters["openstack-dev"].Server)
+	assert.Equal(t, []byte("cert"), config.AuthInfos["openstack-dev"].ClientCertificateData)
+	assert.Equal(t, &api.Context{Cluster: "openstack-dev", AuthInfo: "openstack-dev"}, config.Contexts["openstack-dev"])
+
+	err = kube.MergeConfig(config, api.NewConfig(), "empty")
+	assert.Error(t, err)
+}
@@ -16,6 +16,12 @@ const (
 	// ChartAnchore the default chart for the Anchore plugin
 	ChartPipelineEvent = "jenkins-x/pipeline-events-addon"
 
+	// ChartFlagger the default chart for Flagger
+	ChartFlagger = "flagger/flagger"
+
+	// ChartKubePrometheusStack the default chart for the Prometheus addon which includes Grafana
+	ChartKubePrometheusStack = "prometheus-community/kube-prometheus-stack"
+
 	// ChartGitea the default name of the gitea chart
 	ChartGitea = "jenkins-x/gitea"
 
@@ -69,6 +75,9 @@ const (
 	// SecretJenkinsReleaseGPG the GPG secrets for doing releases
 	SecretJenkinsReleaseGPG = "jenkins-release-gpg"
 
+	// SecretGitSigningKey the default Secret with the key the pipelines sign their git commits and tags with
+	SecretGitSigningKey = "jx-git-signing-key"
+
 	// SecretJenkinsPipelineAddonCredentials the chat credentials secret
 	SecretJenkinsPipelineAddonCredentials = "jx-pipeline-addon-"
 
@@ -102,6 +111,9 @@ const (
 	// ConfigMapNameJXInstallConfig is the ConfigMap containing the jx installation's CA and server url. Used by jx login
 	ConfigMapNameJXInstallConfig = "jx-install-config"
 
+	// ConfigMapNameJXInstallState is the ConfigMap recording the completed steps of jx install so it can be resumed
+	ConfigMapNameJXInstallState = "jx-install-state"
+
 	// LocalHelmRepoName is the default name of the local chart repository where CI/CD releases go to
 	LocalHelmRepoName = "releases"
 
@@ -111,6 +123,22 @@ const (
 	// DeploymentProwBuild the name of the Deployment for the Prow webhook engine
 	DeploymentProwBuild = "prow-build"
 
+	// DeploymentChartMuseum the name of the Deployment of the Helm Chart Museum
+	DeploymentChartMuseum = "jenkins-x-chartmuseum"
+
+	// DeploymentNexus the name of the Deployment of the Nexus artifact repository
+	DeploymentNexus = "jenkins-x-nexus"
+
+	// DeploymentDockerRegistry the name of the Deployment of the in-cluster Docker registry
+	DeploymentDockerRegistry = "jenkins-x-docker-registry"
+
+	// DeploymentControllerBuild the name of the Deployment of the controller which updates the PipelineActivity
+	// resources from the build pods
+	DeploymentControllerBuild = "jenkins-x-controllerbuild"
+
+	// DeploymentControllerWorkflow the name of the Deployment of the controller of the promotion workflows
+	DeploymentControllerWorkflow = "jenkins-x-controllerworkflow"
+
 	DefaultEnvironmentGitRepoURL = "https://github.com/jenkins-x/default-environment-charts.git"
 
 	DefaultOrganisationGitRepoURL = "https://github.com/jenkins-x/default-organisation.git"
@@ -130,9 +158,21 @@ const (
 	// ValueKindChat a chat auth secret/credentials
 	ValueKindChat = "chat"
 
+	// ValueKindChartRepository the credentials of a private chart repository
+	ValueKindChartRepository = "chart-repository"
+
+	// ValueKindAppValues the secret values of an app added to an environment
+	ValueKindAppValues = "app-values"
+
+	// ValueKindApp an app installed with helm into an environment which does not use GitOps
+	ValueKindApp = "app"
+
 	// ValueKindGit a git auth secret/credentials
 	ValueKindGit = "git"
 
+	// ValueKindGitSigningKey a git commit and tag signing key
+	ValueKindGitSigningKey = "git-signing-key"
+
 	// ValueKindIssue an issue auth secret/credentials
 	ValueKindIssue = "issue"
 
@@ -196,6 +236,9 @@ const (
 	// AnnotationURL indicates a service/server's URL
 	AnnotationURL = "jenkins.io/url"
 
+	// AnnotationAPIURL indicates the URL of a server's API when it is not derived from the server's URL
+	AnnotationAPIURL = "jenkins.io/api-url"
+
 	// AnnotationExpose used to expose service using exposecontroller
 	AnnotationExpose = "fabric8.io/expose"
 
@@ -212,16 +255,47 @@ const (
 	AnnotationWorkingDir = "jenkins.io/working-dir"
 	// AnnotationLocalDir the local directory that is sync'd to the DevPod
 	AnnotationLocalDir = "jenkins.io/local-dir"
+	// AnnotationDevPodLastActivity the RFC3339 time of the last shell or sync activity in a DevPod
+	AnnotationDevPodLastActivity = "jenkins.io/devpod-last-activity"
+
+	// AnnotationChartRepositoryURL the URL of the chart repository of the credentials of a private chart repository
+	AnnotationChartRepositoryURL = "jenkins.io/chart-repository-url"
+
+	// AnnotationVaultSecret the key in the external Vault of the team which stores the data of a Secret
+	AnnotationVaultSecret = "jenkins.io/vault-secret"
+
+	// AnnotationPreviewKeep pins a preview environment so that it is never garbage collected
+	AnnotationPreviewKeep = "jenkins.io/preview-keep"
+	// AnnotationPreviewLastActivity the RFC3339 time a preview environment was last deployed
+	AnnotationPreviewLastActivity = "jenkins.io/preview-last-activity"
 
 	// AnnotationIsDefaultStorageClass used to indicate a storageclass is default
 	AnnotationIsDefaultStorageClass = "storageclass.kubernetes.io/is-default-class"
 
+	// AnnotationIsBetaDefaultStorageClass used to indicate a storageclass is default on older clusters
+	AnnotationIsBetaDefaultStorageClass = "storageclass.beta.kubernetes.io/is-default-class"
+
+	// AnnotationRestartedAt the pod template annotation changed to roll the pods of a deployment
+	AnnotationRestartedAt = "jenkins-x.io/restartedAt"
+
 	// SecretDataUsername the username in a Secret/Credentials
 	SecretDataUsername = "username"
 
 	// SecretDataPassword the password in a Secret/Credentials
 	SecretDataPassword = "password"
 
+	// SecretDataGitSigningFormat the format of a git signing key, gpg or ssh
+	SecretDataGitSigningFormat = "format"
+
+	// SecretDataGitSigningKeyID the id of a GPG git signing key
+	SecretDataGitSigningKeyID = "key-id"
+
+	// SecretDataGitSigningPrivateKey the private git signing key
+	SecretDataGitSigningPrivateKey = "private-key"
+
+	// SecretDataGitSigningPublicKey the public git signing key
+	SecretDataGitSigningPublicKey = "public-key"
+
 	// SecretBasicAuth the name for the Jenkins X basic auth secret
 	SecretBasicAuth = "jx-basic-auth"
 
@@ -235,10 +309,11 @@ var (
 		"ambassador":                   ChartAmbassador,
 		"anchore":                      ChartAnchore,
 		"cb":                           ChartCloudBees,
+		"flagger":                      ChartFlagger,
 		"gitea":                        ChartGitea,
 		"istio":                        ChartIstio,
 		"kubeless":                     ChartKubeless,
-		"prometheus":                   "stable/prometheus",
+		"prometheus":                   ChartKubePrometheusStack,
 		"grafana":                      "stable/grafana",
 		DefaultProwReleaseName:         ChartProw,
 		DefaultKnativeBuildReleaseName: ChartKnativeBuild,
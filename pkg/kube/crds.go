@@ -9,8 +9,10 @@ import (
 	"github.com/ghodss/yaml"
 	"github.com/jenkins-x/jx/pkg/apis/jenkins.io"
 	"github.com/jenkins-x/jx/pkg/jx/cmd/certmanager"
+	"github.com/jenkins-x/jx/pkg/log"
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -22,6 +24,28 @@ const (
 	CertmanagerIssuerStaging      = "letsencrypt-staging"
 )
 
+// RegisterAllCRDs ensures that all the Jenkins X CRDs are registered
+func RegisterAllCRDs(apiClient apiextensionsclientset.Interface) error {
+	registers := []func(apiextensionsclientset.Interface) error{
+		RegisterEnvironmentCRD,
+		RegisterEnvironmentRoleBindingCRD,
+		RegisterGitServiceCRD,
+		RegisterPipelineActivityCRD,
+		RegisterExtensionCRD,
+		RegisterReleaseCRD,
+		RegisterUserCRD,
+		RegisterTeamCRD,
+		RegisterWorkflowCRD,
+	}
+	for _, register := range registers {
+		err := register(apiClient)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // RegisterEnvironmentCRD ensures that the CRD is registered for Environments
 func RegisterEnvironmentCRD(apiClient apiextensionsclientset.Interface) error {
 	name := "environments." + jenkinsio.GroupName
@@ -282,12 +306,22 @@ func register(apiClient apiextensionsclientset.Interface, name string, crd *v1be
 			if !reflect.DeepEqual(&crd.Spec, old.Spec) {
 				old.Spec = crd.Spec
 				_, err = crdResources.Update(old)
+				if apierrors.IsForbidden(err) {
+					// a namespace scoped install can use the CRD the cluster operator registered
+					log.Warnf("Not allowed to update the CRD %s so using the existing one\n", name)
+					return nil
+				}
 				return err
 			}
 			return nil
 		}
 
 		_, err = crdResources.Create(crd)
+		if apierrors.IsForbidden(err) {
+			return &backoff.PermanentError{
+				Err: fmt.Errorf("not allowed to register the CRD %s, ask a cluster administrator to run 'jx step install crds': %s", name, err),
+			}
+		}
 		return err
 	}
 
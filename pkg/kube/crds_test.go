@@ -8,6 +8,7 @@ import (
 	. "github.com/petergtz/pegomock"
 	"github.com/stretchr/testify/assert"
 	apiextentions_mocks "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestRegisterEnvironmentCRD(t *testing.T) {
@@ -23,3 +24,14 @@ func TestRegisterEnvironmentCRD(t *testing.T) {
 
 	assert.NoError(t, err, "Should not error")
 }
+
+func TestRegisterAllCRDs(t *testing.T) {
+	apiextensionsInterface := apiextentions_mocks.NewSimpleClientset()
+
+	err := kube.RegisterAllCRDs(apiextensionsInterface)
+	assert.NoError(t, err, "Should not error")
+
+	crds, err := apiextensionsInterface.ApiextensionsV1beta1().CustomResourceDefinitions().List(metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, crds.Items, 9)
+}
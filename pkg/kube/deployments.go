@@ -124,6 +124,41 @@ func WaitForDeploymentToBeReady(client kubernetes.Interface, name, namespace str
 	return nil
 }
 
+// WaitForDeploymentRollout waits for all the pods of a deployment to run its latest pod template and be ready
+func WaitForDeploymentRollout(client kubernetes.Interface, name, namespace string, timeout time.Duration) error {
+	err := wait.PollImmediate(2*time.Second, timeout, func() (bool, error) {
+		d, err := client.AppsV1().Deployments(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		replicas := int32(1)
+		if d.Spec.Replicas != nil {
+			replicas = *d.Spec.Replicas
+		}
+		status := d.Status
+		return status.ObservedGeneration >= d.Generation && status.UpdatedReplicas >= replicas &&
+			status.Replicas == status.UpdatedReplicas && status.AvailableReplicas >= replicas, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("deployment %s did not roll out within %s", name, timeout)
+	}
+	return err
+}
+
+// RestartDeployment rolls the pods of the deployment by changing an annotation of its pod template
+func RestartDeployment(client kubernetes.Interface, name, namespace string) error {
+	d, err := client.AppsV1().Deployments(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if d.Spec.Template.Annotations == nil {
+		d.Spec.Template.Annotations = map[string]string{}
+	}
+	d.Spec.Template.Annotations[AnnotationRestartedAt] = time.Now().Format(time.RFC3339)
+	_, err = client.AppsV1().Deployments(namespace).Update(d)
+	return err
+}
+
 func DeploymentPodCount(client kubernetes.Interface, name, namespace string) (int, error) {
 	pods, err := GetDeploymentPods(client, name, namespace)
 	if err == nil {
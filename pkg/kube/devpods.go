@@ -0,0 +1,302 @@
+package kube
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// devPodSuspendedSuffix the suffix of the ConfigMap storing a suspended DevPod
+	devPodSuspendedSuffix = "-suspended"
+	// devPodSuspendedKey the key of the pod in the ConfigMap of a suspended DevPod
+	devPodSuspendedKey = "pod.yaml"
+	// devPodPreferencesSuffix the suffix of the ConfigMap storing the DevPod preferences of a user
+	devPodPreferencesSuffix = "-devpod-preferences"
+)
+
+// DevPodPreferences the default resources of the DevPods a user creates
+type DevPodPreferences struct {
+	CPU    string
+	Memory string
+	GPU    int
+}
+
+// DevPodPreferencesName returns the name of the ConfigMap storing the DevPod preferences of the user
+func DevPodPreferencesName(username string) string {
+	return ToValidName(username + devPodPreferencesSuffix)
+}
+
+// GetDevPodPreferences returns the DevPod preferences of the user which are empty if the user has not saved any yet
+func GetDevPodPreferences(client kubernetes.Interface, ns string, username string) (*DevPodPreferences, error) {
+	preferences := &DevPodPreferences{}
+	name := DevPodPreferencesName(username)
+	cm, err := client.CoreV1().ConfigMaps(ns).Get(name, meta_v1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return preferences, nil
+		}
+		return nil, err
+	}
+	preferences.CPU = cm.Data["cpu"]
+	preferences.Memory = cm.Data["memory"]
+	gpu := cm.Data["gpu"]
+	if gpu != "" {
+		preferences.GPU, err = strconv.Atoi(gpu)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse the GPUs %s in ConfigMap %s: %s", gpu, name, err)
+		}
+	}
+	return preferences, nil
+}
+
+// SaveDevPodPreferences creates or updates the ConfigMap storing the DevPod preferences of the user
+func SaveDevPodPreferences(client kubernetes.Interface, ns string, username string, preferences *DevPodPreferences) error {
+	configMaps := client.CoreV1().ConfigMaps(ns)
+	data := map[string]string{
+		"cpu":    preferences.CPU,
+		"memory": preferences.Memory,
+		"gpu":    strconv.Itoa(preferences.GPU),
+	}
+	name := DevPodPreferencesName(username)
+	cm, err := configMaps.Get(name, meta_v1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		cm = &v1.ConfigMap{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					LabelDevPodUsername: username,
+				},
+			},
+			Data: data,
+		}
+		_, err = configMaps.Create(cm)
+		return err
+	}
+	cm.Data = data
+	_, err = configMaps.Update(cm)
+	return err
+}
+
+// DevPodLastActivity returns the time of the last shell or sync activity in the DevPod falling back to its creation time
+func DevPodLastActivity(pod *v1.Pod) time.Time {
+	if pod.Annotations != nil {
+		text := pod.Annotations[AnnotationDevPodLastActivity]
+		if text != "" {
+			t, err := time.Parse(time.RFC3339, text)
+			if err == nil {
+				return t
+			}
+		}
+	}
+	return pod.CreationTimestamp.Time
+}
+
+// SetDevPodLastActivity records the time of the last activity in the DevPod
+func SetDevPodLastActivity(pod *v1.Pod, t time.Time) {
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[AnnotationDevPodLastActivity] = t.UTC().Format(time.RFC3339)
+}
+
+// DevPodIdleExpiry returns the time the DevPod is garbage collected for the given idle timeout. It returns false if
+// there is no idle timeout
+func DevPodIdleExpiry(pod *v1.Pod, timeout time.Duration) (time.Time, bool) {
+	if timeout <= 0 {
+		return time.Time{}, false
+	}
+	return DevPodLastActivity(pod).Add(timeout), true
+}
+
+// IsDevPodIdle returns true if the DevPod has had no activity for longer than the idle timeout
+func IsDevPodIdle(pod *v1.Pod, timeout time.Duration, now time.Time) bool {
+	expiry, ok := DevPodIdleExpiry(pod, timeout)
+	return ok && !now.Before(expiry)
+}
+
+// UpdateDevPodLastActivity records the time of the last activity on the DevPod with the given name
+func UpdateDevPodLastActivity(client kubernetes.Interface, ns string, name string, t time.Time) error {
+	pods := client.CoreV1().Pods(ns)
+	pod, err := pods.Get(name, meta_v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	SetDevPodLastActivity(pod, t)
+	_, err = pods.Update(pod)
+	return err
+}
+
+// GetDevPods returns the DevPods of all users in the namespace
+func GetDevPods(client kubernetes.Interface, ns string) ([]v1.Pod, error) {
+	list, err := client.CoreV1().Pods(ns).List(meta_v1.ListOptions{
+		LabelSelector: LabelDevPodUsername,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load DevPods %s", err)
+	}
+	return list.Items, nil
+}
+
+// DeleteDevPod deletes the DevPod keeping its workspace PersistentVolumeClaim so that a DevPod created with the same
+// name and --persist uses the workspace again. The other resources of the DevPod are deleted with it
+func DeleteDevPod(client kubernetes.Interface, ns string, pod *v1.Pod) error {
+	err := reownDevPodDependents(client, ns, pod.UID, nil, false)
+	if err != nil {
+		return err
+	}
+	return client.CoreV1().Pods(ns).Delete(pod.Name, &meta_v1.DeleteOptions{})
+}
+
+// SuspendDevPod deletes the DevPod after storing it in a ConfigMap which then owns the workspace and services of the
+// DevPod so that ResumeDevPod can recreate it
+func SuspendDevPod(client kubernetes.Interface, ns string, pod *v1.Pod) (*v1.ConfigMap, error) {
+	saved := pod.DeepCopy()
+	saved.ObjectMeta = meta_v1.ObjectMeta{
+		Name:        pod.Name,
+		Labels:      pod.Labels,
+		Annotations: pod.Annotations,
+	}
+	saved.Spec.NodeName = ""
+	saved.Status = v1.PodStatus{}
+	data, err := yaml.Marshal(saved)
+	if err != nil {
+		return nil, err
+	}
+	cm := &v1.ConfigMap{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name: pod.Name + devPodSuspendedSuffix,
+			Labels: map[string]string{
+				LabelDevPodName:     pod.Name,
+				LabelDevPodUsername: pod.Labels[LabelDevPodUsername],
+				LabelPodTemplate:    pod.Labels[LabelPodTemplate],
+			},
+		},
+		Data: map[string]string{
+			devPodSuspendedKey: string(data),
+		},
+	}
+	cm, err = client.CoreV1().ConfigMaps(ns).Create(cm)
+	if err != nil {
+		return nil, err
+	}
+	owner := devPodOwnerReference("ConfigMap", cm.Name, cm.UID)
+	err = reownDevPodDependents(client, ns, pod.UID, &owner, true)
+	if err != nil {
+		return cm, err
+	}
+	return cm, client.CoreV1().Pods(ns).Delete(pod.Name, &meta_v1.DeleteOptions{})
+}
+
+// GetSuspendedDevPods returns the ConfigMaps of the suspended DevPods of the user with the given pod template label
+func GetSuspendedDevPods(client kubernetes.Interface, ns string, username string, label string) ([]v1.ConfigMap, error) {
+	list, err := client.CoreV1().ConfigMaps(ns).List(meta_v1.ListOptions{
+		LabelSelector: LabelDevPodUsername + "=" + username + "," + LabelPodTemplate + "=" + label,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// SuspendedDevPod returns the DevPod stored in the ConfigMap of a suspended DevPod
+func SuspendedDevPod(cm *v1.ConfigMap) (*v1.Pod, error) {
+	pod := &v1.Pod{}
+	err := yaml.Unmarshal([]byte(cm.Data[devPodSuspendedKey]), pod)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse the suspended DevPod %s: %s", cm.Name, err)
+	}
+	return pod, nil
+}
+
+// ResumeDevPod recreates the suspended DevPod of the ConfigMap, giving it back its workspace and services
+func ResumeDevPod(client kubernetes.Interface, ns string, cm *v1.ConfigMap, t time.Time) (*v1.Pod, error) {
+	pod, err := SuspendedDevPod(cm)
+	if err != nil {
+		return nil, err
+	}
+	SetDevPodLastActivity(pod, t)
+	pod, err = client.CoreV1().Pods(ns).Create(pod)
+	if err != nil {
+		return nil, err
+	}
+	owner := devPodOwnerReference("Pod", pod.Name, pod.UID)
+	err = reownDevPodDependents(client, ns, cm.UID, &owner, true)
+	if err != nil {
+		return pod, err
+	}
+	return pod, client.CoreV1().ConfigMaps(ns).Delete(cm.Name, &meta_v1.DeleteOptions{})
+}
+
+// reownDevPodDependents replaces the owner reference to the given UID of the workspace PersistentVolumeClaims and
+// optionally the services of a DevPod with the new owner or removes it if there is none
+func reownDevPodDependents(client kubernetes.Interface, ns string, uid types.UID, owner *meta_v1.OwnerReference, services bool) error {
+	pvcs, err := client.CoreV1().PersistentVolumeClaims(ns).List(meta_v1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range pvcs.Items {
+		pvc := &pvcs.Items[i]
+		if replaceOwnerReference(&pvc.ObjectMeta, uid, owner) {
+			_, err = client.CoreV1().PersistentVolumeClaims(ns).Update(pvc)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if !services {
+		return nil
+	}
+	svcs, err := client.CoreV1().Services(ns).List(meta_v1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range svcs.Items {
+		svc := &svcs.Items[i]
+		if replaceOwnerReference(&svc.ObjectMeta, uid, owner) {
+			_, err = client.CoreV1().Services(ns).Update(svc)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func replaceOwnerReference(meta *meta_v1.ObjectMeta, uid types.UID, owner *meta_v1.OwnerReference) bool {
+	answer := []meta_v1.OwnerReference{}
+	found := false
+	for _, ref := range meta.OwnerReferences {
+		if ref.UID == uid {
+			found = true
+			if owner != nil {
+				answer = append(answer, *owner)
+			}
+		} else {
+			answer = append(answer, ref)
+		}
+	}
+	meta.OwnerReferences = answer
+	return found
+}
+
+func devPodOwnerReference(kind string, name string, uid types.UID) meta_v1.OwnerReference {
+	controller := true
+	return meta_v1.OwnerReference{
+		APIVersion: "v1",
+		Kind:       kind,
+		Name:       name,
+		UID:        uid,
+		Controller: &controller,
+	}
+}
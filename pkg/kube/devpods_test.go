@@ -0,0 +1,150 @@
+package kube_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testDevPodNs = "jx"
+
+func newTestDevPod(created time.Time) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "alice-maven",
+			Namespace:         testDevPodNs,
+			UID:               "pod-uid",
+			CreationTimestamp: metav1.NewTime(created),
+			Labels: map[string]string{
+				kube.LabelDevPodName:     "alice-maven",
+				kube.LabelDevPodUsername: "alice",
+				kube.LabelPodTemplate:    "maven",
+			},
+		},
+		Spec: v1.PodSpec{
+			NodeName: "node-1",
+			Containers: []v1.Container{
+				{Name: "maven", Image: "jenkinsxio/builder-maven"},
+			},
+		},
+	}
+}
+
+func newTestDevPodDependents(pod *v1.Pod) (*v1.PersistentVolumeClaim, *v1.Service) {
+	controller := true
+	owner := []metav1.OwnerReference{
+		{APIVersion: "v1", Kind: "Pod", Name: pod.Name, UID: pod.UID, Controller: &controller},
+	}
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: pod.Name + "-pvc", Namespace: testDevPodNs, OwnerReferences: owner},
+	}
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: pod.Name + "-theia", Namespace: testDevPodNs, OwnerReferences: owner},
+	}
+	return pvc, svc
+}
+
+func TestDevPodIdle(t *testing.T) {
+	t.Parallel()
+	created := time.Date(2018, 11, 2, 17, 0, 0, 0, time.UTC)
+	timeout := 2 * time.Hour
+	pod := newTestDevPod(created)
+
+	assert.Equal(t, created, kube.DevPodLastActivity(pod))
+	assert.False(t, kube.IsDevPodIdle(pod, timeout, created.Add(time.Hour)))
+	assert.True(t, kube.IsDevPodIdle(pod, timeout, created.Add(2*time.Hour)))
+	assert.False(t, kube.IsDevPodIdle(pod, 0, created.Add(100*time.Hour)))
+
+	active := created.Add(3 * time.Hour)
+	kube.SetDevPodLastActivity(pod, active)
+	assert.Equal(t, active, kube.DevPodLastActivity(pod))
+	assert.False(t, kube.IsDevPodIdle(pod, timeout, created.Add(4*time.Hour)))
+}
+
+func TestDeleteDevPodKeepsWorkspace(t *testing.T) {
+	t.Parallel()
+	pod := newTestDevPod(time.Now())
+	pvc, svc := newTestDevPodDependents(pod)
+	client := fake.NewSimpleClientset(pod, pvc, svc)
+
+	pods, err := kube.GetDevPods(client, testDevPodNs)
+	require.NoError(t, err)
+	require.Len(t, pods, 1)
+
+	err = kube.DeleteDevPod(client, testDevPodNs, pod)
+	require.NoError(t, err)
+
+	_, err = client.CoreV1().Pods(testDevPodNs).Get(pod.Name, metav1.GetOptions{})
+	assert.Error(t, err, "the DevPod should have been deleted")
+	pvc, err = client.CoreV1().PersistentVolumeClaims(testDevPodNs).Get(pvc.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, pvc.OwnerReferences, "the workspace should not be deleted with the DevPod")
+	svc, err = client.CoreV1().Services(testDevPodNs).Get(svc.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Len(t, svc.OwnerReferences, 1, "the services should be deleted with the DevPod")
+}
+
+func TestSuspendAndResumeDevPod(t *testing.T) {
+	t.Parallel()
+	pod := newTestDevPod(time.Now())
+	pvc, svc := newTestDevPodDependents(pod)
+	client := fake.NewSimpleClientset(pod, pvc, svc)
+
+	cm, err := kube.SuspendDevPod(client, testDevPodNs, pod)
+	require.NoError(t, err)
+	_, err = client.CoreV1().Pods(testDevPodNs).Get(pod.Name, metav1.GetOptions{})
+	assert.Error(t, err, "the DevPod should have been deleted")
+	pvc, err = client.CoreV1().PersistentVolumeClaims(testDevPodNs).Get(pvc.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Len(t, pvc.OwnerReferences, 1)
+	assert.Equal(t, "ConfigMap", pvc.OwnerReferences[0].Kind)
+	assert.Equal(t, cm.Name, pvc.OwnerReferences[0].Name)
+
+	suspended, err := kube.GetSuspendedDevPods(client, testDevPodNs, "alice", "maven")
+	require.NoError(t, err)
+	require.Len(t, suspended, 1)
+	other, err := kube.GetSuspendedDevPods(client, testDevPodNs, "bob", "maven")
+	require.NoError(t, err)
+	assert.Empty(t, other)
+
+	resumedAt := time.Date(2018, 11, 5, 9, 0, 0, 0, time.UTC)
+	resumed, err := kube.ResumeDevPod(client, testDevPodNs, &suspended[0], resumedAt)
+	require.NoError(t, err)
+	assert.Equal(t, pod.Name, resumed.Name)
+	assert.Equal(t, "jenkinsxio/builder-maven", resumed.Spec.Containers[0].Image)
+	assert.Equal(t, "", resumed.Spec.NodeName)
+	assert.Equal(t, resumedAt, kube.DevPodLastActivity(resumed))
+
+	svc, err = client.CoreV1().Services(testDevPodNs).Get(svc.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Len(t, svc.OwnerReferences, 1)
+	assert.Equal(t, "Pod", svc.OwnerReferences[0].Kind)
+	assert.Equal(t, resumed.UID, svc.OwnerReferences[0].UID)
+	_, err = client.CoreV1().ConfigMaps(testDevPodNs).Get(cm.Name, metav1.GetOptions{})
+	assert.Error(t, err, "the suspended DevPod should have been removed")
+}
+
+func TestDevPodPreferences(t *testing.T) {
+	t.Parallel()
+	client := fake.NewSimpleClientset()
+
+	preferences, err := kube.GetDevPodPreferences(client, testDevPodNs, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, &kube.DevPodPreferences{}, preferences)
+
+	require.NoError(t, kube.SaveDevPodPreferences(client, testDevPodNs, "alice", &kube.DevPodPreferences{CPU: "2", Memory: "4Gi"}))
+	require.NoError(t, kube.SaveDevPodPreferences(client, testDevPodNs, "alice", &kube.DevPodPreferences{CPU: "4", Memory: "8Gi", GPU: 1}))
+	preferences, err = kube.GetDevPodPreferences(client, testDevPodNs, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, &kube.DevPodPreferences{CPU: "4", Memory: "8Gi", GPU: 1}, preferences)
+
+	preferences, err = kube.GetDevPodPreferences(client, testDevPodNs, "bob")
+	require.NoError(t, err)
+	assert.Equal(t, &kube.DevPodPreferences{}, preferences)
+}
@@ -184,6 +184,7 @@ func CreateEnvironmentSurvey(batchMode bool, authConfigSvc auth.AuthConfigServic
 			string(v1.PromotionStrategyTypeAutomatic),
 			string(v1.PromotionStrategyTypeManual),
 			string(v1.PromotionStrategyTypeNever),
+			string(v1.PromotionStrategyTypeCanary),
 		}
 		defaultValue := string(data.Spec.PromotionStrategy)
 		if defaultValue == "" {
@@ -193,7 +194,7 @@ func CreateEnvironmentSurvey(batchMode bool, authConfigSvc auth.AuthConfigServic
 			Message: "Promotion Strategy:",
 			Options: promoteValues,
 			Default: defaultValue,
-			Help:    "Whether we promote to this Environment automatically, manually or never",
+			Help:    "Whether we promote to this Environment automatically, manually, manually as a canary or never",
 		}
 		textValue := ""
 		err := survey.AskOne(q, &textValue, survey.Required, surveyOpts)
@@ -342,7 +343,11 @@ func createEnvironmentGitRepo(batchMode bool, authConfigSvc auth.AuthConfigServi
 		if err != nil {
 			return "", nil, err
 		}
-		pushGitURL, err := git.CreatePushURL(repo.CloneURL, details.User)
+		cloneURL, err := gits.GitCloneURLForProtocol(repo.CloneURL, gitRepoOptions.Protocol)
+		if err != nil {
+			return "", nil, err
+		}
+		pushGitURL, err := git.CreatePushURL(cloneURL, details.User)
 		if err != nil {
 			return "", nil, err
 		}
@@ -391,7 +396,11 @@ func createEnvironmentGitRepo(batchMode bool, authConfigSvc auth.AuthConfigServi
 				if err != nil {
 					return "", nil, err
 				}
-				err = git.Clone(repo.CloneURL, dir)
+				cloneURL, err := gits.GitCloneURLForProtocol(repo.CloneURL, gitRepoOptions.Protocol)
+				if err != nil {
+					return "", nil, err
+				}
+				err = git.Clone(cloneURL, dir)
 				if err != nil {
 					return "", nil, err
 				}
@@ -415,7 +424,7 @@ func createEnvironmentGitRepo(batchMode bool, authConfigSvc auth.AuthConfigServi
 				if err != nil {
 					return "", nil, err
 				}
-				return repo.CloneURL, provider, nil
+				return cloneURL, provider, nil
 			}
 		}
 
@@ -435,7 +444,11 @@ func createEnvironmentGitRepo(batchMode bool, authConfigSvc auth.AuthConfigServi
 			if err != nil {
 				return "", nil, err
 			}
-			pushGitURL, err := git.CreatePushURL(repo.CloneURL, details.User)
+			cloneURL, err := gits.GitCloneURLForProtocol(repo.CloneURL, gitRepoOptions.Protocol)
+			if err != nil {
+				return "", nil, err
+			}
+			pushGitURL, err := git.CreatePushURL(cloneURL, details.User)
 			if err != nil {
 				return "", nil, err
 			}
@@ -462,7 +475,12 @@ func createEnvironmentGitRepo(batchMode bool, authConfigSvc auth.AuthConfigServi
 			fmt.Fprintf(out, "Pushed Git repository to %s\n\n", util.ColorInfo(repo.HTMLURL))
 		}
 	}
-	return repo.CloneURL, provider, nil
+	// the environment source URL is used to push the promotions so it uses the protocol of the team
+	cloneURL, err := gits.GitCloneURLForProtocol(repo.CloneURL, gitRepoOptions.Protocol)
+	if err != nil {
+		return "", nil, err
+	}
+	return cloneURL, provider, nil
 }
 
 // ModifyNamespace modifies the namespace
@@ -0,0 +1,81 @@
+package kube
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// EnvironmentChartFiles the files of the helm chart every Git repository of an environment needs
+var EnvironmentChartFiles = []string{
+	filepath.Join("env", "Chart.yaml"),
+	filepath.Join("env", "requirements.yaml"),
+	filepath.Join("env", "values.yaml"),
+}
+
+// EnvironmentPipelineFiles the files which define the pipeline of the Git repository of an environment, one of which
+// must exist
+var EnvironmentPipelineFiles = []string{"Jenkinsfile", "jenkins-x.yml"}
+
+// MissingEnvironmentRepositoryFiles returns the files of the layout of an environment which are missing in the Git
+// repository cloned in the directory. The first pipeline file is returned if the repository has no pipeline
+func MissingEnvironmentRepositoryFiles(dir string) ([]string, error) {
+	answer := []string{}
+	for _, name := range EnvironmentChartFiles {
+		exists, err := util.FileExists(filepath.Join(dir, name))
+		if err != nil {
+			return answer, err
+		}
+		if !exists {
+			answer = append(answer, name)
+		}
+	}
+	for _, name := range EnvironmentPipelineFiles {
+		exists, err := util.FileExists(filepath.Join(dir, name))
+		if err != nil {
+			return answer, err
+		}
+		if exists {
+			return answer, nil
+		}
+	}
+	return append(answer, EnvironmentPipelineFiles[0]), nil
+}
+
+// ScaffoldEnvironmentRepository copies the missing files of the layout of an environment into the directory from the
+// clone of the environment template repository in templateDir. The Makefile used by the template Jenkinsfile is copied
+// along with it if the directory has none
+func ScaffoldEnvironmentRepository(dir string, templateDir string, missing []string) error {
+	files := append([]string{}, missing...)
+	if util.StringArrayIndex(missing, EnvironmentPipelineFiles[0]) >= 0 {
+		exists, err := util.FileExists(filepath.Join(dir, "Makefile"))
+		if err != nil {
+			return err
+		}
+		if !exists {
+			files = append(files, "Makefile")
+		}
+	}
+	for _, name := range files {
+		src := filepath.Join(templateDir, name)
+		exists, err := util.FileExists(src)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("the environment template has no %s", name)
+		}
+		dest := filepath.Join(dir, name)
+		err = os.MkdirAll(filepath.Dir(dest), util.DefaultWritePermissions)
+		if err != nil {
+			return err
+		}
+		err = util.CopyFile(src, dest)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
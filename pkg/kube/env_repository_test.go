@@ -0,0 +1,61 @@
+package kube_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestFiles(t *testing.T, dir string, names ...string) {
+	for _, name := range names {
+		file := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0760))
+		require.NoError(t, ioutil.WriteFile(file, []byte(name), 0644))
+	}
+}
+
+func TestScaffoldEnvironmentRepository(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-env-repo-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	templateDir, err := ioutil.TempDir("", "test-env-template-")
+	require.NoError(t, err)
+	defer os.RemoveAll(templateDir)
+
+	writeTestFiles(t, templateDir, "env/Chart.yaml", "env/requirements.yaml", "env/values.yaml", "Jenkinsfile", "Makefile")
+	writeTestFiles(t, dir, "env/values.yaml", "README.md")
+
+	missing, err := kube.MissingEnvironmentRepositoryFiles(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"env/Chart.yaml", "env/requirements.yaml", "Jenkinsfile"}, missing)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "env/values.yaml"), []byte("existing"), 0644))
+	err = kube.ScaffoldEnvironmentRepository(dir, templateDir, missing)
+	require.NoError(t, err)
+
+	missing, err = kube.MissingEnvironmentRepositoryFiles(dir)
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+	assert.FileExists(t, filepath.Join(dir, "Makefile"))
+	data, err := ioutil.ReadFile(filepath.Join(dir, "env/values.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "existing", string(data), "existing files are not overwritten")
+}
+
+func TestMissingEnvironmentRepositoryFilesWithPipeline(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "test-env-repo-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeTestFiles(t, dir, "env/Chart.yaml", "env/requirements.yaml", "env/values.yaml", "jenkins-x.yml")
+	missing, err := kube.MissingEnvironmentRepositoryFiles(dir)
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+}
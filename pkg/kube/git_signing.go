@@ -0,0 +1,93 @@
+package kube
+
+import (
+	"fmt"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// GitSigningFormatGPG signs the git commits and tags with a GPG key
+	GitSigningFormatGPG = "gpg"
+	// GitSigningFormatSSH signs the git commits and tags with an SSH key
+	GitSigningFormatSSH = "ssh"
+)
+
+// GitSigningFormats the formats of the keys git can sign commits and tags with
+var GitSigningFormats = []string{GitSigningFormatGPG, GitSigningFormatSSH}
+
+// GitSigningKey a key the pipelines sign their git commits and tags with
+type GitSigningKey struct {
+	Format string
+	// KeyID the fingerprint of a GPG key
+	KeyID      string
+	PrivateKey []byte
+	PublicKey  []byte
+}
+
+// CreateOrUpdateGitSigningKeySecret stores the git signing key in the Secret with the given name, replacing the key
+// of an existing Secret
+func CreateOrUpdateGitSigningKeySecret(client kubernetes.Interface, ns string, name string, key *GitSigningKey) (*v1.Secret, error) {
+	secrets := client.CoreV1().Secrets(ns)
+	data := map[string][]byte{
+		SecretDataGitSigningFormat:     []byte(key.Format),
+		SecretDataGitSigningPrivateKey: key.PrivateKey,
+		SecretDataGitSigningPublicKey:  key.PublicKey,
+	}
+	if key.KeyID != "" {
+		data[SecretDataGitSigningKeyID] = []byte(key.KeyID)
+	}
+	secret, err := secrets.Get(name, meta_v1.GetOptions{})
+	if errors.IsNotFound(err) {
+		secret = &v1.Secret{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					LabelKind: ValueKindGitSigningKey,
+				},
+			},
+			Data: data,
+		}
+		return secrets.Create(secret)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if secret.Labels == nil {
+		secret.Labels = map[string]string{}
+	}
+	secret.Labels[LabelKind] = ValueKindGitSigningKey
+	secret.Data = data
+	return secrets.Update(secret)
+}
+
+// GetGitSigningKey loads the git signing key from the Secret with the given name
+func GetGitSigningKey(client kubernetes.Interface, ns string, name string) (*GitSigningKey, error) {
+	secret, err := client.CoreV1().Secrets(ns).Get(name, meta_v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	key := &GitSigningKey{
+		Format:     string(secret.Data[SecretDataGitSigningFormat]),
+		KeyID:      string(secret.Data[SecretDataGitSigningKeyID]),
+		PrivateKey: secret.Data[SecretDataGitSigningPrivateKey],
+		PublicKey:  secret.Data[SecretDataGitSigningPublicKey],
+	}
+	if key.Format == "" {
+		key.Format = GitSigningFormatGPG
+	}
+	if util.StringArrayIndex(GitSigningFormats, key.Format) < 0 {
+		return nil, fmt.Errorf("the Secret %s has the unknown git signing key format %s, expected one of %v", name, key.Format, GitSigningFormats)
+	}
+	if len(key.PrivateKey) == 0 {
+		return nil, fmt.Errorf("the Secret %s has no %s", name, SecretDataGitSigningPrivateKey)
+	}
+	if key.Format == GitSigningFormatGPG && key.KeyID == "" {
+		return nil, fmt.Errorf("the Secret %s has no %s of its GPG key", name, SecretDataGitSigningKeyID)
+	}
+	return key, nil
+}
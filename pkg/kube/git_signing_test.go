@@ -0,0 +1,71 @@
+package kube_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kube_mocks "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCreateOrUpdateGitSigningKeySecret(t *testing.T) {
+	t.Parallel()
+	client := kube_mocks.NewSimpleClientset()
+	ns := "jx"
+
+	_, err := kube.CreateOrUpdateGitSigningKeySecret(client, ns, kube.SecretGitSigningKey, &kube.GitSigningKey{
+		Format:     kube.GitSigningFormatGPG,
+		KeyID:      "ABCDEF0123456789",
+		PrivateKey: []byte("private"),
+		PublicKey:  []byte("public"),
+	})
+	require.NoError(t, err)
+
+	key, err := kube.GetGitSigningKey(client, ns, kube.SecretGitSigningKey)
+	require.NoError(t, err)
+	assert.Equal(t, kube.GitSigningFormatGPG, key.Format)
+	assert.Equal(t, "ABCDEF0123456789", key.KeyID)
+	assert.Equal(t, "private", string(key.PrivateKey))
+	assert.Equal(t, "public", string(key.PublicKey))
+
+	secret, err := kube.CreateOrUpdateGitSigningKeySecret(client, ns, kube.SecretGitSigningKey, &kube.GitSigningKey{
+		Format:     kube.GitSigningFormatSSH,
+		PrivateKey: []byte("ssh-private"),
+		PublicKey:  []byte("ssh-ed25519 AAAA"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, kube.ValueKindGitSigningKey, secret.Labels[kube.LabelKind])
+
+	key, err = kube.GetGitSigningKey(client, ns, kube.SecretGitSigningKey)
+	require.NoError(t, err)
+	assert.Equal(t, kube.GitSigningFormatSSH, key.Format)
+	assert.Equal(t, "", key.KeyID)
+	assert.Equal(t, "ssh-private", string(key.PrivateKey))
+}
+
+func TestGetGitSigningKeyValidatesSecret(t *testing.T) {
+	t.Parallel()
+	ns := "jx"
+	client := kube_mocks.NewSimpleClientset(&v1.Secret{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "no-key-id", Namespace: ns},
+		Data: map[string][]byte{
+			kube.SecretDataGitSigningPrivateKey: []byte("private"),
+		},
+	}, &v1.Secret{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "unknown-format", Namespace: ns},
+		Data: map[string][]byte{
+			kube.SecretDataGitSigningFormat:     []byte("x509"),
+			kube.SecretDataGitSigningPrivateKey: []byte("private"),
+		},
+	})
+
+	_, err := kube.GetGitSigningKey(client, ns, "no-key-id")
+	assert.Error(t, err)
+	_, err = kube.GetGitSigningKey(client, ns, "unknown-format")
+	assert.Error(t, err)
+	_, err = kube.GetGitSigningKey(client, ns, "missing")
+	assert.Error(t, err)
+}
@@ -3,10 +3,14 @@ package kube
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"strconv"
 
+	"k8s.io/api/extensions/v1beta1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -17,14 +21,34 @@ const (
 	TLS                    = "tls"
 	Issuer                 = "issuer"
 	Exposer                = "exposer"
+	TLSSecretName          = "tlssecretname"
+	IngressClass           = "ingressclass"
+	IngressKind            = "ingresskind"
+
+	// IngressKindNginx the kind of nginx ingress controllers
+	IngressKindNginx = "nginx"
+	// IngressKindTraefik the kind of traefik ingress controllers which use a different annotation scheme
+	IngressKindTraefik = "traefik"
+
+	// AnnotationIngressClass the annotation which selects the ingress controller of an ingress
+	AnnotationIngressClass = "kubernetes.io/ingress.class"
 )
 
+// IngressKinds the kinds of ingress controllers whose annotations jx knows
+var IngressKinds = []string{IngressKindNginx, IngressKindTraefik}
+
 type IngressConfig struct {
 	Email   string `structs:"email" yaml:"email" json:"email"`
 	Domain  string `structs:"domain" yaml:"domain" json:"domain"`
 	Issuer  string `structs:"issuer" yaml:"issuer" json:"issuer"`
 	Exposer string `structs:"exposer" yaml:"exposer" json:"exposer"`
 	TLS     bool   `structs:"tls" yaml:"tls" json:"tls"`
+	// TLSSecretName the name of the secret of a certificate brought by the user, which is used instead of cert-manager
+	TLSSecretName string `structs:"tlssecretname" yaml:"tlssecretname" json:"tlssecretname"`
+	// IngressClass the class of the ingress controller when reusing an existing one
+	IngressClass string `structs:"ingressclass" yaml:"ingressclass" json:"ingressclass"`
+	// IngressKind the kind of the ingress controller such as nginx or traefik
+	IngressKind string `structs:"ingresskind" yaml:"ingresskind" json:"ingresskind"`
 }
 
 func GetIngress(client kubernetes.Interface, ns, name string) (string, error) {
@@ -69,6 +93,9 @@ func GetIngressConfig(c kubernetes.Interface, ns string) (IngressConfig, error)
 	ic.Email = data[Email]
 	ic.Exposer = data[Exposer]
 	ic.Issuer = data[Issuer]
+	ic.TLSSecretName = data[TLSSecretName]
+	ic.IngressClass = data[IngressClass]
+	ic.IngressKind = data[IngressKind]
 	tls, exists := data[TLS]
 
 	if exists {
@@ -81,3 +108,68 @@ func GetIngressConfig(c kubernetes.Interface, ns string) (IngressConfig, error)
 	}
 	return ic, nil
 }
+
+// BasicAuthIngressAnnotations returns the exposecontroller ingress annotations which protect an ingress with the
+// basic auth secret for the kind of ingress controller
+func BasicAuthIngressAnnotations(ingressKind string, secretName string) string {
+	prefix := "nginx.ingress.kubernetes.io/"
+	if ingressKind == IngressKindTraefik {
+		prefix = "ingress.kubernetes.io/"
+	}
+	return prefix + "auth-type: basic\n" + prefix + "auth-secret: " + secretName
+}
+
+// ProbeIngress creates a temporary ingress of the host routed to the given service then waits for the ingress
+// controller of the ingress class to publish its address, deleting the ingress afterwards
+func ProbeIngress(client kubernetes.Interface, ns string, name string, host string, ingressClass string, serviceName string, servicePort int, timeout time.Duration) error {
+	ingresses := client.ExtensionsV1beta1().Ingresses(ns)
+	ingress := &v1beta1.Ingress{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name: name,
+			Annotations: map[string]string{
+				AnnotationIngressClass: ingressClass,
+			},
+		},
+		Spec: v1beta1.IngressSpec{
+			Rules: []v1beta1.IngressRule{
+				{
+					Host: host,
+					IngressRuleValue: v1beta1.IngressRuleValue{
+						HTTP: &v1beta1.HTTPIngressRuleValue{
+							Paths: []v1beta1.HTTPIngressPath{
+								{
+									Backend: v1beta1.IngressBackend{
+										ServiceName: serviceName,
+										ServicePort: intstr.FromInt(servicePort),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	_, err := ingresses.Create(ingress)
+	if err != nil {
+		return fmt.Errorf("failed to create the probe ingress %s in namespace %s: %v", name, ns, err)
+	}
+	defer ingresses.Delete(name, &meta_v1.DeleteOptions{})
+
+	err = wait.PollImmediate(2*time.Second, timeout, func() (bool, error) {
+		ing, err := ingresses.Get(name, meta_v1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, lb := range ing.Status.LoadBalancer.Ingress {
+			if lb.IP != "" || lb.Hostname != "" {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("no ingress controller with the class %s admitted the probe ingress %s within %s", ingressClass, name, timeout)
+	}
+	return err
+}
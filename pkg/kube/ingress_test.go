@@ -0,0 +1,54 @@
+package kube_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestBasicAuthIngressAnnotations(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "nginx.ingress.kubernetes.io/auth-type: basic\nnginx.ingress.kubernetes.io/auth-secret: jx-basic-auth",
+		kube.BasicAuthIngressAnnotations(kube.IngressKindNginx, kube.SecretBasicAuth))
+	assert.Equal(t, "ingress.kubernetes.io/auth-type: basic\ningress.kubernetes.io/auth-secret: jx-basic-auth",
+		kube.BasicAuthIngressAnnotations(kube.IngressKindTraefik, kube.SecretBasicAuth))
+}
+
+func TestGetIngressConfigReadsIngressClass(t *testing.T) {
+	t.Parallel()
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kube.IngressConfigConfigmap,
+			Namespace: "jx",
+		},
+		Data: map[string]string{
+			kube.Domain:       "example.com",
+			kube.IngressClass: "traefik-internal",
+			kube.IngressKind:  kube.IngressKindTraefik,
+		},
+	}
+	client := fake.NewSimpleClientset(cm)
+
+	ic, err := kube.GetIngressConfig(client, "jx")
+	require.NoError(t, err)
+	assert.Equal(t, "traefik-internal", ic.IngressClass)
+	assert.Equal(t, kube.IngressKindTraefik, ic.IngressKind)
+}
+
+func TestProbeIngressTimesOutAndDeletesIngress(t *testing.T) {
+	t.Parallel()
+	client := fake.NewSimpleClientset()
+
+	err := kube.ProbeIngress(client, "jx", "jx-ingress-probe", "jx-ingress-probe.example.com", "traefik", "jenkins", 8080, time.Millisecond)
+	assert.Error(t, err)
+
+	ingresses, err := client.ExtensionsV1beta1().Ingresses("jx").List(metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, ingresses.Items)
+}
@@ -0,0 +1,115 @@
+package kube
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const installStepPrefix = "step."
+
+// InstallState is the progress of jx install in a namespace, recorded in the jx-install-state ConfigMap so that
+// a failed install can skip the steps which already completed when it is run again
+type InstallState struct {
+	client kubernetes.Interface
+	ns     string
+	data   map[string]string
+}
+
+// LoadInstallState loads the install state of the namespace, which is empty if no install has run in it yet
+func LoadInstallState(client kubernetes.Interface, ns string) (*InstallState, error) {
+	state := &InstallState{
+		client: client,
+		ns:     ns,
+		data:   map[string]string{},
+	}
+	cm, err := client.CoreV1().ConfigMaps(ns).Get(ConfigMapNameJXInstallState, meta_v1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return state, nil
+		}
+		return nil, errors.Wrapf(err, "failed to load the ConfigMap %s in namespace %s", ConfigMapNameJXInstallState, ns)
+	}
+	for k, v := range cm.Data {
+		state.data[k] = v
+	}
+	return state, nil
+}
+
+// IsStepCompleted returns true if the named install step has completed
+func (s *InstallState) IsStepCompleted(step string) bool {
+	_, ok := s.data[installStepPrefix+step]
+	return ok
+}
+
+// CompletedSteps returns the names of the completed install steps
+func (s *InstallState) CompletedSteps() []string {
+	answer := []string{}
+	for k := range s.data {
+		if strings.HasPrefix(k, installStepPrefix) {
+			answer = append(answer, strings.TrimPrefix(k, installStepPrefix))
+		}
+	}
+	return answer
+}
+
+// CompleteStep records that the named install step has completed
+func (s *InstallState) CompleteStep(step string) error {
+	return s.save(installStepPrefix+step, time.Now().UTC().Format(time.RFC3339))
+}
+
+// Value returns a value chosen by a previous install, such as the domain, so that it is not asked for again
+func (s *InstallState) Value(key string) string {
+	return s.data[key]
+}
+
+// SetValue records a value chosen during the install
+func (s *InstallState) SetValue(key string, value string) error {
+	if value == "" || s.data[key] == value {
+		return nil
+	}
+	return s.save(key, value)
+}
+
+// Reset removes the install state so that all the install steps run again
+func (s *InstallState) Reset() error {
+	err := s.client.CoreV1().ConfigMaps(s.ns).Delete(ConfigMapNameJXInstallState, &meta_v1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete the ConfigMap %s in namespace %s", ConfigMapNameJXInstallState, s.ns)
+	}
+	s.data = map[string]string{}
+	return nil
+}
+
+func (s *InstallState) save(key string, value string) error {
+	configMaps := s.client.CoreV1().ConfigMaps(s.ns)
+	cm, err := configMaps.Get(ConfigMapNameJXInstallState, meta_v1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to load the ConfigMap %s in namespace %s", ConfigMapNameJXInstallState, s.ns)
+		}
+		cm = &v1.ConfigMap{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name: ConfigMapNameJXInstallState,
+			},
+			Data: map[string]string{key: value},
+		}
+		_, err = configMaps.Create(cm)
+	} else {
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[key] = value
+		_, err = configMaps.Update(cm)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to save the ConfigMap %s in namespace %s", ConfigMapNameJXInstallState, s.ns)
+	}
+	s.data[key] = value
+	return nil
+}
@@ -0,0 +1,36 @@
+package kube_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	kube_mocks "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestInstallState(t *testing.T) {
+	t.Parallel()
+	client := kube_mocks.NewSimpleClientset()
+	state, err := kube.LoadInstallState(client, "jx")
+	require.NoError(t, err)
+	assert.False(t, state.IsStepCompleted("install-ingress"))
+
+	require.NoError(t, state.CompleteStep("install-ingress"))
+	require.NoError(t, state.SetValue("domain", "jx.example.com"))
+
+	state, err = kube.LoadInstallState(client, "jx")
+	require.NoError(t, err)
+	assert.True(t, state.IsStepCompleted("install-ingress"))
+	assert.False(t, state.IsStepCompleted("install-platform"))
+	assert.Equal(t, []string{"install-ingress"}, state.CompletedSteps())
+	assert.Equal(t, "jx.example.com", state.Value("domain"))
+
+	require.NoError(t, state.Reset())
+	assert.False(t, state.IsStepCompleted("install-ingress"))
+
+	state, err = kube.LoadInstallState(client, "jx")
+	require.NoError(t, err)
+	assert.Empty(t, state.CompletedSteps())
+	assert.Equal(t, "", state.Value("domain"))
+}
@@ -0,0 +1,69 @@
+package kube
+
+import (
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// LabelIstioInjection the label of a namespace which enables or disables the Istio sidecar injection of its pods
+	LabelIstioInjection = "istio-injection"
+
+	// IstioInjectionEnabled the value of the LabelIstioInjection label which enables the sidecar injection
+	IstioInjectionEnabled = "enabled"
+
+	// IstioInjectionDisabled the value of the LabelIstioInjection label which disables the sidecar injection
+	IstioInjectionDisabled = "disabled"
+)
+
+// IstioInjection returns whether the pods of the namespace of the environment get Istio sidecars along with whether
+// the environment has its own setting. Environments without their own setting use the team setting of the dev
+// environment, which may be nil
+func IstioInjection(devEnv *v1.Environment, env *v1.Environment) (bool, bool) {
+	if env.Spec.IstioInjection != nil {
+		return *env.Spec.IstioInjection, true
+	}
+	if devEnv != nil {
+		return devEnv.Spec.TeamSettings.IstioInjection, false
+	}
+	return false, false
+}
+
+// IstioInjectionLabelValue returns the value of the LabelIstioInjection label
+func IstioInjectionLabelValue(enabled bool) string {
+	if enabled {
+		return IstioInjectionEnabled
+	}
+	return IstioInjectionDisabled
+}
+
+// AddIstioInjectionLabel adds the LabelIstioInjection label to the labels of the namespace of the environment. The
+// label is only added to new namespaces unless the environment has its own setting so that the label of an existing
+// namespace is not changed when the team setting changes or when it was added by hand
+func AddIstioInjectionLabel(kubeClient kubernetes.Interface, jxClient versioned.Interface, env *v1.Environment, devNs string, labels map[string]string) error {
+	if env.Spec.Kind == v1.EnvironmentKindTypeDevelopment || env.Spec.Namespace == "" || env.Spec.Namespace == devNs {
+		return nil
+	}
+	devEnv, err := jxClient.JenkinsV1().Environments(devNs).Get(LabelValueDevEnvironment, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		devEnv = nil
+	}
+	enabled, explicit := IstioInjection(devEnv, env)
+	if !explicit {
+		_, err = kubeClient.CoreV1().Namespaces().Get(env.Spec.Namespace, metav1.GetOptions{})
+		if err == nil {
+			return nil
+		}
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	labels[LabelIstioInjection] = IstioInjectionLabelValue(enabled)
+	return nil
+}
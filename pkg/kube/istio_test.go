@@ -0,0 +1,82 @@
+package kube_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	versiond_mocks "github.com/jenkins-x/jx/pkg/client/clientset/versioned/fake"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestIstioInjection(t *testing.T) {
+	t.Parallel()
+	enabled := true
+	disabled := false
+	devEnv := &v1.Environment{}
+	devEnv.Spec.TeamSettings.IstioInjection = true
+	env := &v1.Environment{}
+
+	value, explicit := kube.IstioInjection(nil, env)
+	assert.False(t, value)
+	assert.False(t, explicit)
+
+	value, explicit = kube.IstioInjection(devEnv, env)
+	assert.True(t, value, "the team setting is used by default")
+	assert.False(t, explicit)
+
+	env.Spec.IstioInjection = &disabled
+	value, explicit = kube.IstioInjection(devEnv, env)
+	assert.False(t, value, "the environment overrides the team setting")
+	assert.True(t, explicit)
+
+	env.Spec.IstioInjection = &enabled
+	value, _ = kube.IstioInjection(nil, env)
+	assert.True(t, value)
+}
+
+func TestEnsureEnvironmentNamespaceSetupLabelsIstioInjection(t *testing.T) {
+	t.Parallel()
+	devEnv := kube.NewPermanentEnvironment(kube.LabelValueDevEnvironment)
+	devEnv.Spec.Namespace = "jx"
+	devEnv.Spec.Kind = v1.EnvironmentKindTypeDevelopment
+	devEnv.Spec.TeamSettings.IstioInjection = true
+	jxClient := versiond_mocks.NewSimpleClientset(devEnv)
+	kubeClient := fake.NewSimpleClientset(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "jx-staging",
+			Labels: map[string]string{"istio-injection": "disabled"},
+		},
+	})
+
+	preview := &v1.Environment{
+		ObjectMeta: metav1.ObjectMeta{Name: "jstrachan-demo-pr-1"},
+		Spec: v1.EnvironmentSpec{
+			Namespace: "jx-jstrachan-demo-pr-1",
+			Kind:      v1.EnvironmentKindTypePreview,
+		},
+	}
+	require.NoError(t, kube.EnsureEnvironmentNamespaceSetup(kubeClient, jxClient, preview, "jx"))
+	assert.Equal(t, kube.IstioInjectionEnabled, namespaceLabel(t, kubeClient, "jx-jstrachan-demo-pr-1"), "new namespaces use the team setting")
+	assert.Equal(t, "", namespaceLabel(t, kubeClient, "jx"), "the dev namespace is not labelled")
+
+	staging := kube.NewPermanentEnvironment("staging")
+	staging.Spec.Namespace = "jx-staging"
+	require.NoError(t, kube.EnsureEnvironmentNamespaceSetup(kubeClient, jxClient, staging, "jx"))
+	assert.Equal(t, kube.IstioInjectionDisabled, namespaceLabel(t, kubeClient, "jx-staging"), "existing namespaces are not relabelled by the team setting")
+
+	enabled := true
+	staging.Spec.IstioInjection = &enabled
+	require.NoError(t, kube.EnsureEnvironmentNamespaceSetup(kubeClient, jxClient, staging, "jx"))
+	assert.Equal(t, kube.IstioInjectionEnabled, namespaceLabel(t, kubeClient, "jx-staging"), "the setting of the environment is applied to existing namespaces")
+}
+
+func namespaceLabel(t *testing.T, kubeClient *fake.Clientset, name string) string {
+	ns, err := kubeClient.CoreV1().Namespaces().Get(name, metav1.GetOptions{})
+	require.NoError(t, err)
+	return ns.Labels[kube.LabelIstioInjection]
+}
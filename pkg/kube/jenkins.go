@@ -72,7 +72,7 @@ func ParseXml(xml string) (*etree.Document, string, error) {
 }
 
 func createGitHubConfig(xml string, server *auth.AuthServer, userAuth *auth.UserAuth, credentials string) (string, error) {
-	u := gits.GitHubEnterpriseApiEndpointURL(server.URL)
+	u := gits.GitHubServerAPIURL(server)
 	if strings.TrimSpace(xml) == "" {
 		xml = `<?xml version='1.1' encoding='UTF-8'?>
 		    <org.jenkinsci.plugins.github__branch__source.GitHubConfiguration plugin="github-branch-source@2.3.2"/>`
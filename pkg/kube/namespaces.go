@@ -13,14 +13,18 @@ import (
 func EnsureEnvironmentNamespaceSetup(kubeClient kubernetes.Interface, jxClient versioned.Interface, env *v1.Environment, ns string) error {
 	// lets create the namespace if we are on the same cluster
 	spec := &env.Spec
-	if spec.Cluster == "" && spec.Namespace != "" {
+	if spec.Cluster == "" && spec.RemoteCluster == nil && spec.Namespace != "" {
 		labels := map[string]string{
 			LabelTeam:        ns,
 			LabelEnvironment: env.Name,
 		}
 		annotations := map[string]string{}
 
-		err := EnsureNamespaceCreated(kubeClient, spec.Namespace, labels, annotations)
+		err := AddIstioInjectionLabel(kubeClient, jxClient, env, ns, labels)
+		if err != nil {
+			return err
+		}
+		err = EnsureNamespaceCreated(kubeClient, spec.Namespace, labels, annotations)
 		if err != nil {
 			return err
 		}
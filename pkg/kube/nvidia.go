@@ -0,0 +1,112 @@
+package kube
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// NvidiaDevicePluginName the name of the NVIDIA device plugin DaemonSet
+	NvidiaDevicePluginName = "nvidia-device-plugin-daemonset"
+
+	// NvidiaDevicePluginNamespace the namespace the NVIDIA device plugin is installed into
+	NvidiaDevicePluginNamespace = "kube-system"
+
+	// NvidiaDevicePluginImage the image of the NVIDIA device plugin
+	NvidiaDevicePluginImage = "nvidia/k8s-device-plugin:1.11"
+
+	// NvidiaGPUResource the resource name GPUs are exposed as by the NVIDIA device plugin
+	NvidiaGPUResource = "nvidia.com/gpu"
+)
+
+// NvidiaDevicePluginDaemonSet returns the DaemonSet of the NVIDIA device plugin which exposes the GPUs of the nodes
+// as schedulable resources
+func NvidiaDevicePluginDaemonSet() *appsv1.DaemonSet {
+	labels := map[string]string{"name": "nvidia-device-plugin-ds"}
+	privileged := false
+	hostPathType := v1.HostPathDirectory
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      NvidiaDevicePluginName,
+			Namespace: NvidiaDevicePluginNamespace,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			UpdateStrategy: appsv1.DaemonSetUpdateStrategy{
+				Type: appsv1.RollingUpdateDaemonSetStrategyType,
+			},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+					Annotations: map[string]string{
+						"scheduler.alpha.kubernetes.io/critical-pod": "",
+					},
+				},
+				Spec: v1.PodSpec{
+					Tolerations: []v1.Toleration{
+						{
+							Key:      "CriticalAddonsOnly",
+							Operator: v1.TolerationOpExists,
+						},
+						{
+							Key:      NvidiaGPUResource,
+							Operator: v1.TolerationOpExists,
+							Effect:   v1.TaintEffectNoSchedule,
+						},
+					},
+					Containers: []v1.Container{
+						{
+							Name:  "nvidia-device-plugin-ctr",
+							Image: NvidiaDevicePluginImage,
+							SecurityContext: &v1.SecurityContext{
+								AllowPrivilegeEscalation: &privileged,
+								Capabilities: &v1.Capabilities{
+									Drop: []v1.Capability{"ALL"},
+								},
+							},
+							VolumeMounts: []v1.VolumeMount{
+								{
+									Name:      "device-plugin",
+									MountPath: "/var/lib/kubelet/device-plugins",
+								},
+							},
+						},
+					},
+					Volumes: []v1.Volume{
+						{
+							Name: "device-plugin",
+							VolumeSource: v1.VolumeSource{
+								HostPath: &v1.HostPathVolumeSource{
+									Path: "/var/lib/kubelet/device-plugins",
+									Type: &hostPathType,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// ApplyNvidiaDevicePlugin creates or updates the NVIDIA device plugin DaemonSet
+func ApplyNvidiaDevicePlugin(client kubernetes.Interface) error {
+	daemonSet := NvidiaDevicePluginDaemonSet()
+	daemonSets := client.AppsV1().DaemonSets(daemonSet.Namespace)
+	existing, err := daemonSets.Get(daemonSet.Name, metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		_, err = daemonSets.Create(daemonSet)
+		return err
+	}
+	existing.Spec = daemonSet.Spec
+	_, err = daemonSets.Update(existing)
+	return err
+}
@@ -0,0 +1,25 @@
+package kube_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kube_mocks "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestApplyNvidiaDevicePlugin(t *testing.T) {
+	t.Parallel()
+	client := kube_mocks.NewSimpleClientset()
+
+	// applying twice should update the existing DaemonSet
+	for i := 0; i < 2; i++ {
+		err := kube.ApplyNvidiaDevicePlugin(client)
+		assert.Nil(t, err)
+	}
+
+	daemonSet, err := client.AppsV1().DaemonSets(kube.NvidiaDevicePluginNamespace).Get(kube.NvidiaDevicePluginName, meta_v1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, kube.NvidiaDevicePluginImage, daemonSet.Spec.Template.Spec.Containers[0].Image)
+}
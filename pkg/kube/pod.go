@@ -2,6 +2,7 @@ package kube
 
 import (
 	"fmt"
+	"io"
 	"sort"
 	"strings"
 	"time"
@@ -206,3 +207,18 @@ func GetPodRestarts(pod *v1.Pod) int32 {
 	}
 	return restarts
 }
+
+// StreamContainerLogs copies the log of the container of the pod to the writer, following it until the container
+// terminates if follow is true
+func StreamContainerLogs(client kubernetes.Interface, ns string, pod string, container string, follow bool, out io.Writer) error {
+	stream, err := client.CoreV1().Pods(ns).GetLogs(pod, &v1.PodLogOptions{
+		Container: container,
+		Follow:    follow,
+	}).Stream()
+	if err != nil {
+		return fmt.Errorf("failed to get the log of container %s of pod %s: %s", container, pod, err)
+	}
+	defer stream.Close()
+	_, err = io.Copy(out, stream)
+	return err
+}
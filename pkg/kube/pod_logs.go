@@ -0,0 +1,240 @@
+package kube
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/log"
+	"k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// PodLogsOptions the options of the logs of the containers of the pods which match a label selector
+type PodLogsOptions struct {
+	Namespace string
+	Selector  string
+	// Container the container whose log is shown, defaults to the first container of each pod
+	Container string
+	// AllContainers shows the logs of all the init containers and containers of each pod
+	AllContainers bool
+	// Previous shows the log of the previous instance of each restarted container before its current log
+	Previous bool
+	// Since only shows the lines logged within this duration if it is not zero
+	Since  time.Duration
+	Follow bool
+	// PollPeriod the period between looking for new pods and containers when following the logs
+	PollPeriod time.Duration
+}
+
+// PodLogStreamer interleaves the lines of the logs of the containers of the pods which match a label selector. Each
+// line is written with a single write so that the lines of different containers are never mixed up
+type PodLogStreamer struct {
+	Client  kubernetes.Interface
+	Options PodLogsOptions
+	Out     io.Writer
+
+	// Prefix returns the prefix of the lines of the log of the container of the pod. It is only called from the
+	// goroutine which runs the streamer
+	Prefix func(pod string, container string) string
+
+	// OpenStream opens the log of a container, defaults to the log of the API server
+	OpenStream func(ns string, pod string, options *v1.PodLogOptions) (io.ReadCloser, error)
+
+	lock    sync.Mutex
+	wg      sync.WaitGroup
+	seen    map[string]bool
+	streams map[string]*containerLogStream
+}
+
+type containerLogStream struct {
+	active bool
+	ended  time.Time
+}
+
+// Run streams the logs of the containers of the matching pods. When following the logs it keeps looking for new pods,
+// such as the pods of a rolling deploy, and containers until stop is closed, otherwise it returns once the current logs
+// have been written
+func (s *PodLogStreamer) Run(stop <-chan struct{}) error {
+	s.seen = map[string]bool{}
+	s.streams = map[string]*containerLogStream{}
+	if s.OpenStream == nil {
+		s.OpenStream = func(ns string, pod string, options *v1.PodLogOptions) (io.ReadCloser, error) {
+			return s.Client.CoreV1().Pods(ns).GetLogs(pod, options).Stream()
+		}
+	}
+	period := s.Options.PollPeriod
+	if period <= 0 {
+		period = time.Second
+	}
+	for {
+		err := s.startStreams()
+		if err != nil {
+			return err
+		}
+		if !s.Options.Follow {
+			s.wg.Wait()
+			return nil
+		}
+		select {
+		case <-stop:
+			return nil
+		case <-time.After(period):
+		}
+	}
+}
+
+// startStreams starts streaming the logs of the containers of the matching pods which are not being streamed yet
+func (s *PodLogStreamer) startStreams() error {
+	pods, err := s.Client.CoreV1().Pods(s.Options.Namespace).List(meta_v1.ListOptions{
+		LabelSelector: s.Options.Selector,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list the pods in namespace %s with selector %s: %s", s.Options.Namespace, s.Options.Selector, err)
+	}
+	items := pods.Items
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].CreationTimestamp.Before(&items[j].CreationTimestamp) ||
+			(items[i].CreationTimestamp.Equal(&items[j].CreationTimestamp) && items[i].Name < items[j].Name)
+	})
+	for i := range items {
+		pod := &items[i]
+		for _, container := range s.containerNames(pod) {
+			status := containerStatus(pod, container)
+			if status == nil {
+				continue
+			}
+			containerKey := fmt.Sprintf("%s/%s", pod.UID, container)
+			if !s.seen[containerKey] {
+				s.seen[containerKey] = true
+				if s.Options.Previous && status.LastTerminationState.Terminated != nil {
+					// the log of the previous instance has ended so it is written before the current log
+					err = s.copyLines(pod.Name, &v1.PodLogOptions{
+						Container: container,
+						Previous:  true,
+					}, s.prefix(pod, container))
+					if err != nil {
+						log.Warnf("%s\n", err)
+					}
+				}
+			}
+			if status.State.Running == nil && status.State.Terminated == nil {
+				continue
+			}
+			key := fmt.Sprintf("%s/%d", containerKey, status.RestartCount)
+			options := &v1.PodLogOptions{
+				Container: container,
+				Follow:    s.Options.Follow,
+			}
+			s.lock.Lock()
+			stream := s.streams[key]
+			s.lock.Unlock()
+			if stream != nil {
+				if stream.active || status.State.Running == nil || !s.Options.Follow {
+					continue
+				}
+				// the log of the running container was disconnected so carry on from where it ended
+				since := meta_v1.NewTime(stream.ended)
+				options.SinceTime = &since
+			} else if s.Options.Since > 0 {
+				seconds := int64(math.Ceil(s.Options.Since.Seconds()))
+				options.SinceSeconds = &seconds
+			}
+			s.startStream(pod, container, key, options)
+		}
+	}
+	return nil
+}
+
+// containerNames returns the names of the containers of the pod whose logs are shown
+func (s *PodLogStreamer) containerNames(pod *v1.Pod) []string {
+	answer := []string{}
+	if s.Options.AllContainers {
+		for _, c := range pod.Spec.InitContainers {
+			answer = append(answer, c.Name)
+		}
+		for _, c := range pod.Spec.Containers {
+			answer = append(answer, c.Name)
+		}
+		return answer
+	}
+	for _, c := range pod.Spec.Containers {
+		if s.Options.Container == "" || c.Name == s.Options.Container {
+			return append(answer, c.Name)
+		}
+	}
+	return answer
+}
+
+func containerStatus(pod *v1.Pod, container string) *v1.ContainerStatus {
+	for _, statuses := range [][]v1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for i := range statuses {
+			if statuses[i].Name == container {
+				return &statuses[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (s *PodLogStreamer) prefix(pod *v1.Pod, container string) string {
+	if s.Prefix == nil {
+		return ""
+	}
+	return s.Prefix(pod.Name, container)
+}
+
+func (s *PodLogStreamer) startStream(pod *v1.Pod, container string, key string, options *v1.PodLogOptions) {
+	prefix := s.prefix(pod, container)
+	s.lock.Lock()
+	s.streams[key] = &containerLogStream{active: true}
+	s.lock.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		err := s.copyLines(pod.Name, options, prefix)
+		if err != nil {
+			log.Warnf("%s\n", err)
+		}
+		s.lock.Lock()
+		s.streams[key] = &containerLogStream{ended: time.Now()}
+		s.lock.Unlock()
+	}()
+}
+
+// copyLines writes each line of the log with the prefix in a single write
+func (s *PodLogStreamer) copyLines(pod string, options *v1.PodLogOptions, prefix string) error {
+	stream, err := s.OpenStream(s.Options.Namespace, pod, options)
+	if err != nil {
+		return fmt.Errorf("failed to get the log of container %s of pod %s: %s", options.Container, pod, err)
+	}
+	defer stream.Close()
+	reader := bufio.NewReader(stream)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			if !strings.HasSuffix(line, "\n") {
+				line += "\n"
+			}
+			s.lock.Lock()
+			_, werr := io.WriteString(s.Out, prefix+line)
+			s.lock.Unlock()
+			if werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read the log of container %s of pod %s: %s", options.Container, pod, err)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package kube_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPodLogStreamerInterleavesAllContainers(t *testing.T) {
+	t.Parallel()
+	pod1 := podLogsTestPod("myapp-1", "app", "sidecar")
+	pod1.Status.ContainerStatuses[0].RestartCount = 1
+	pod1.Status.ContainerStatuses[0].LastTerminationState.Terminated = &v1.ContainerStateTerminated{ExitCode: 1}
+	pod2 := podLogsTestPod("myapp-2", "app", "sidecar")
+	other := podLogsTestPod("other", "app")
+	other.Labels["app"] = "other"
+
+	out := &bytes.Buffer{}
+	streamer := &kube.PodLogStreamer{
+		Client: fake.NewSimpleClientset(pod1, pod2, other),
+		Options: kube.PodLogsOptions{
+			Namespace:     "jx-staging",
+			Selector:      "app=myapp",
+			AllContainers: true,
+			Previous:      true,
+			Since:         10 * time.Minute,
+		},
+		Out:        out,
+		OpenStream: podLogsTestStream(t, 600),
+		Prefix: func(pod string, container string) string {
+			return pod + "/" + container + " "
+		},
+	}
+	err := streamer.Run(nil)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	// the previous log is written before the current logs whose lines are interleaved
+	assert.Equal(t, "myapp-1/app previous line", lines[0])
+	sort.Strings(lines)
+	assert.Equal(t, []string{
+		"myapp-1/app line 1",
+		"myapp-1/app line 2",
+		"myapp-1/app previous line",
+		"myapp-1/sidecar line 1",
+		"myapp-1/sidecar line 2",
+		"myapp-2/app line 1",
+		"myapp-2/app line 2",
+		"myapp-2/sidecar line 1",
+		"myapp-2/sidecar line 2",
+	}, lines)
+}
+
+func TestPodLogStreamerFollowsNewPods(t *testing.T) {
+	t.Parallel()
+	pod1 := podLogsTestPod("myapp-1", "app", "sidecar")
+	client := fake.NewSimpleClientset(pod1)
+
+	out := &podLogsTestWriter{}
+	stop := make(chan struct{})
+	streamer := &kube.PodLogStreamer{
+		Client: client,
+		Options: kube.PodLogsOptions{
+			Namespace:  "jx-staging",
+			Selector:   "app=myapp",
+			Follow:     true,
+			PollPeriod: 10 * time.Millisecond,
+		},
+		Out:        out,
+		OpenStream: podLogsTestStream(t, 0),
+		Prefix: func(pod string, container string) string {
+			return pod + "/" + container + " "
+		},
+	}
+	done := make(chan error)
+	go func() {
+		done <- streamer.Run(stop)
+	}()
+
+	waitForPodLogsTestOutput(t, out, "myapp-1/app line 2\n")
+	// the pod is replaced by a rolling deploy
+	err := client.CoreV1().Pods("jx-staging").Delete("myapp-1", nil)
+	require.NoError(t, err)
+	_, err = client.CoreV1().Pods("jx-staging").Create(podLogsTestPod("myapp-2", "app"))
+	require.NoError(t, err)
+	waitForPodLogsTestOutput(t, out, "myapp-2/app line 2\n")
+
+	close(stop)
+	require.NoError(t, <-done)
+	assert.Equal(t, "myapp-1/app line 1\nmyapp-1/app line 2\nmyapp-2/app line 1\nmyapp-2/app line 2\n", out.String(),
+		"only the first container should be shown and each log should only be shown once")
+}
+
+func podLogsTestPod(name string, containers ...string) *v1.Pod {
+	pod := &v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      name,
+			Namespace: "jx-staging",
+			UID:       types.UID(name),
+			Labels:    map[string]string{"app": "myapp"},
+		},
+	}
+	for _, c := range containers {
+		pod.Spec.Containers = append(pod.Spec.Containers, v1.Container{Name: c})
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, v1.ContainerStatus{
+			Name:  c,
+			State: v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+		})
+	}
+	return pod
+}
+
+// podLogsTestStream returns the log of a container whose last line has no line feed, its stream ends as if it was
+// disconnected
+func podLogsTestStream(t *testing.T, sinceSeconds int64) func(string, string, *v1.PodLogOptions) (io.ReadCloser, error) {
+	return func(ns string, pod string, options *v1.PodLogOptions) (io.ReadCloser, error) {
+		if options.Previous {
+			return ioutil.NopCloser(strings.NewReader("previous line\n")), nil
+		}
+		if options.SinceTime != nil {
+			// the log was disconnected so it is resumed from where it ended
+			return ioutil.NopCloser(strings.NewReader("")), nil
+		}
+		if sinceSeconds > 0 {
+			require.NotNil(t, options.SinceSeconds)
+			assert.Equal(t, sinceSeconds, *options.SinceSeconds)
+		}
+		return ioutil.NopCloser(strings.NewReader("line 1\nline 2")), nil
+	}
+}
+
+type podLogsTestWriter struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (w *podLogsTestWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *podLogsTestWriter) String() string {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.buf.String()
+}
+
+func waitForPodLogsTestOutput(t *testing.T, out *podLogsTestWriter, text string) {
+	for i := 0; i < 500; i++ {
+		if strings.Contains(out.String(), text) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.FailNow(t, fmt.Sprintf("timed out waiting for %q in %q", text, out.String()))
+}
@@ -0,0 +1,49 @@
+package kube
+
+import (
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+)
+
+// IsPreviewKept returns true if the preview environment has been pinned so that it is never garbage collected
+func IsPreviewKept(env *v1.Environment) bool {
+	return env.Annotations != nil && env.Annotations[AnnotationPreviewKeep] == "true"
+}
+
+// PreviewLastActivity returns the time the preview environment was last deployed falling back to its creation time
+func PreviewLastActivity(env *v1.Environment) time.Time {
+	if env.Annotations != nil {
+		text := env.Annotations[AnnotationPreviewLastActivity]
+		if text != "" {
+			t, err := time.Parse(time.RFC3339, text)
+			if err == nil {
+				return t
+			}
+		}
+	}
+	return env.CreationTimestamp.Time
+}
+
+// SetPreviewLastActivity records the time the preview environment was last deployed
+func SetPreviewLastActivity(env *v1.Environment, t time.Time) {
+	if env.Annotations == nil {
+		env.Annotations = map[string]string{}
+	}
+	env.Annotations[AnnotationPreviewLastActivity] = t.UTC().Format(time.RFC3339)
+}
+
+// PreviewExpiry returns the time the preview environment expires for the given time to live. It returns false if the
+// preview never expires as it is kept or there is no time to live
+func PreviewExpiry(env *v1.Environment, ttl time.Duration) (time.Time, bool) {
+	if ttl <= 0 || IsPreviewKept(env) {
+		return time.Time{}, false
+	}
+	return PreviewLastActivity(env).Add(ttl), true
+}
+
+// IsPreviewExpired returns true if the preview environment has had no activity for longer than the time to live
+func IsPreviewExpired(env *v1.Environment, ttl time.Duration, now time.Time) bool {
+	expiry, ok := PreviewExpiry(env, ttl)
+	return ok && !now.Before(expiry)
+}
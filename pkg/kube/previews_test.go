@@ -0,0 +1,42 @@
+package kube_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPreviewExpiry(t *testing.T) {
+	t.Parallel()
+	created := time.Date(2018, 11, 1, 9, 0, 0, 0, time.UTC)
+	ttl := 24 * time.Hour
+	env := &v1.Environment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "acme-pr-1",
+			CreationTimestamp: metav1.NewTime(created),
+		},
+	}
+
+	assert.Equal(t, created, kube.PreviewLastActivity(env))
+	assert.False(t, kube.IsPreviewExpired(env, ttl, created.Add(23*time.Hour)))
+	assert.True(t, kube.IsPreviewExpired(env, ttl, created.Add(24*time.Hour)))
+	assert.False(t, kube.IsPreviewExpired(env, 0, created.Add(1000*time.Hour)))
+
+	deployed := created.Add(48 * time.Hour)
+	kube.SetPreviewLastActivity(env, deployed)
+	assert.Equal(t, deployed, kube.PreviewLastActivity(env))
+	expiry, ok := kube.PreviewExpiry(env, ttl)
+	assert.True(t, ok)
+	assert.Equal(t, deployed.Add(ttl), expiry)
+	assert.False(t, kube.IsPreviewExpired(env, ttl, created.Add(50*time.Hour)))
+
+	env.Annotations[kube.AnnotationPreviewKeep] = "true"
+	assert.True(t, kube.IsPreviewKept(env))
+	_, ok = kube.PreviewExpiry(env, ttl)
+	assert.False(t, ok)
+	assert.False(t, kube.IsPreviewExpired(env, ttl, created.Add(1000*time.Hour)))
+}
@@ -0,0 +1,102 @@
+package kube
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	// SecretDataKubeConfig the kubeconfig used to connect to the remote cluster of an environment
+	SecretDataKubeConfig = "kubeconfig"
+
+	// SecretDataToken the bearer token used to connect to the remote cluster of an environment
+	SecretDataToken = "token"
+
+	// SecretDataCA the PEM certificate authority of the API server of the remote cluster of an environment
+	SecretDataCA = "ca.crt"
+)
+
+// IsRemoteEnvironment returns true if the environment runs in a different cluster to the team
+func IsRemoteEnvironment(env *v1.Environment) bool {
+	return env != nil && env.Spec.RemoteCluster != nil
+}
+
+// RemoteClusterConfig returns the REST configuration to connect to the remote cluster of an environment loading any
+// credentials from the Secret in the team namespace
+func RemoteClusterConfig(kubeClient kubernetes.Interface, ns string, cluster *v1.EnvironmentCluster) (*rest.Config, error) {
+	if cluster.SecretName == "" {
+		if cluster.Context == "" {
+			return nil, fmt.Errorf("the remote cluster %s has no secret or kubeconfig context to connect with", cluster.URL)
+		}
+		return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			clientcmd.NewDefaultClientConfigLoadingRules(),
+			&clientcmd.ConfigOverrides{CurrentContext: cluster.Context}).ClientConfig()
+	}
+
+	secret, err := kubeClient.CoreV1().Secrets(ns).Get(cluster.SecretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load the secret %s in namespace %s", cluster.SecretName, ns)
+	}
+	kubeConfig := secret.Data[SecretDataKubeConfig]
+	if len(kubeConfig) > 0 {
+		apiConfig, err := clientcmd.Load(kubeConfig)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the kubeconfig in secret %s", cluster.SecretName)
+		}
+		config, err := clientcmd.NewNonInteractiveClientConfig(*apiConfig, cluster.Context, &clientcmd.ConfigOverrides{}, nil).ClientConfig()
+		if err != nil {
+			return nil, err
+		}
+		if cluster.URL != "" {
+			config.Host = cluster.URL
+		}
+		return config, nil
+	}
+
+	token := string(secret.Data[SecretDataToken])
+	if token == "" {
+		return nil, fmt.Errorf("the secret %s has no %s or %s", cluster.SecretName, SecretDataKubeConfig, SecretDataToken)
+	}
+	if cluster.URL == "" {
+		return nil, fmt.Errorf("the remote cluster has no URL to connect to with the token in secret %s", cluster.SecretName)
+	}
+	caData := secret.Data[SecretDataCA]
+	if len(caData) == 0 && cluster.CAData != "" {
+		caData, err = base64.StdEncoding.DecodeString(cluster.CAData)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode the certificate authority of the remote cluster")
+		}
+	}
+	return &rest.Config{
+		Host:        cluster.URL,
+		BearerToken: token,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData: caData,
+		},
+	}, nil
+}
+
+// RemoteClusterClient returns a kube client for the remote cluster of an environment
+func RemoteClusterClient(kubeClient kubernetes.Interface, ns string, cluster *v1.EnvironmentCluster) (kubernetes.Interface, error) {
+	config, err := RemoteClusterConfig(kubeClient, ns, cluster)
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(config)
+}
+
+// ValidateRemoteCluster checks that the API server of a remote cluster can be reached with the client
+func ValidateRemoteCluster(client kubernetes.Interface) error {
+	_, err := client.Discovery().ServerVersion()
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to the API server of the remote cluster")
+	}
+	return nil
+}
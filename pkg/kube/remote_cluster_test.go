@@ -0,0 +1,88 @@
+package kube_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kube_mocks "k8s.io/client-go/kubernetes/fake"
+)
+
+const testRemoteKubeConfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: production
+  cluster:
+    server: https://prod.example.com
+contexts:
+- name: production
+  context:
+    cluster: production
+    user: deployer
+- name: other
+  context:
+    cluster: production
+    user: other
+current-context: other
+users:
+- name: deployer
+  user:
+    token: deployer-token
+- name: other
+  user:
+    token: other-token
+`
+
+func TestRemoteClusterConfig(t *testing.T) {
+	t.Parallel()
+	ns := "jx"
+	client := kube_mocks.NewSimpleClientset(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "prod-token", Namespace: ns},
+			Data: map[string][]byte{
+				kube.SecretDataToken: []byte("secret-token"),
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "prod-kubeconfig", Namespace: ns},
+			Data: map[string][]byte{
+				kube.SecretDataKubeConfig: []byte(testRemoteKubeConfig),
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "empty", Namespace: ns},
+		},
+	)
+
+	config, err := kube.RemoteClusterConfig(client, ns, &v1.EnvironmentCluster{
+		URL:        "https://prod.example.com",
+		CAData:     base64.StdEncoding.EncodeToString([]byte("my-ca")),
+		SecretName: "prod-token",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://prod.example.com", config.Host)
+	assert.Equal(t, "secret-token", config.BearerToken)
+	assert.Equal(t, []byte("my-ca"), config.TLSClientConfig.CAData)
+
+	config, err = kube.RemoteClusterConfig(client, ns, &v1.EnvironmentCluster{
+		SecretName: "prod-kubeconfig",
+		Context:    "production",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://prod.example.com", config.Host)
+	assert.Equal(t, "deployer-token", config.BearerToken)
+
+	_, err = kube.RemoteClusterConfig(client, ns, &v1.EnvironmentCluster{SecretName: "prod-token"})
+	assert.Error(t, err, "a token needs the URL of the cluster")
+	_, err = kube.RemoteClusterConfig(client, ns, &v1.EnvironmentCluster{URL: "https://prod.example.com", SecretName: "empty"})
+	assert.Error(t, err)
+	_, err = kube.RemoteClusterConfig(client, ns, &v1.EnvironmentCluster{URL: "https://prod.example.com", SecretName: "missing"})
+	assert.Error(t, err)
+	_, err = kube.RemoteClusterConfig(client, ns, &v1.EnvironmentCluster{URL: "https://prod.example.com"})
+	assert.Error(t, err)
+}
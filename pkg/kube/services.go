@@ -30,6 +30,15 @@ type ServiceURL struct {
 	URL  string
 }
 
+// ExposedURL the URL of a service exposed by the exposecontroller or by an ingress
+type ExposedURL struct {
+	Environment string `json:"environment,omitempty"`
+	Namespace   string `json:"namespace"`
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	TLS         bool   `json:"tls"`
+}
+
 func GetServices(client kubernetes.Interface, ns string) (map[string]*v1.Service, error) {
 	answer := map[string]*v1.Service{}
 	list, err := client.CoreV1().Services(ns).List(meta_v1.ListOptions{})
@@ -167,6 +176,59 @@ func FindServiceURLs(client kubernetes.Interface, namespace string) ([]ServiceUR
 	return urls, nil
 }
 
+// FindExposedURLs returns the URLs of the services of the namespace which are exposed by the exposecontroller or by an
+// ingress sorted by name. The URL annotation of a service is used rather than an ingress of the same name
+func FindExposedURLs(client kubernetes.Interface, namespace string) ([]ExposedURL, error) {
+	answer := []ExposedURL{}
+	names := map[string]bool{}
+	svcs, err := client.CoreV1().Services(namespace).List(meta_v1.ListOptions{})
+	if err != nil {
+		return answer, fmt.Errorf("failed to load Services %s", err)
+	}
+	for _, svc := range svcs.Items {
+		url := GetServiceURL(&svc)
+		if url != "" {
+			names[svc.Name] = true
+			answer = append(answer, ExposedURL{
+				Namespace: namespace,
+				Name:      svc.Name,
+				URL:       url,
+				TLS:       strings.HasPrefix(url, "https://"),
+			})
+		}
+	}
+	ingresses, err := client.ExtensionsV1beta1().Ingresses(namespace).List(meta_v1.ListOptions{})
+	if err != nil {
+		return answer, fmt.Errorf("failed to load Ingresses %s", err)
+	}
+	for _, ing := range ingresses.Items {
+		if names[ing.Name] || len(ing.Spec.Rules) == 0 || ing.Spec.Rules[0].Host == "" {
+			continue
+		}
+		host := ing.Spec.Rules[0].Host
+		tls := false
+		for _, t := range ing.Spec.TLS {
+			if util.StringArrayIndex(t.Hosts, host) >= 0 {
+				tls = true
+			}
+		}
+		url := "http://" + host
+		if tls {
+			url = "https://" + host
+		}
+		answer = append(answer, ExposedURL{
+			Namespace: namespace,
+			Name:      ing.Name,
+			URL:       url,
+			TLS:       tls,
+		})
+	}
+	sort.Slice(answer, func(i, j int) bool {
+		return answer[i].Name < answer[j].Name
+	})
+	return answer, nil
+}
+
 // waits for the pods of a deployment to become ready
 func WaitForExternalIP(client kubernetes.Interface, name, namespace string, timeout time.Duration) error {
 
@@ -256,6 +318,25 @@ func IsServicePresent(c kubernetes.Interface, name, ns string) (bool, error) {
 	return true, nil
 }
 
+// AnnotateService adds the given annotations to the service, replacing the values of existing annotations
+func AnnotateService(c kubernetes.Interface, ns, name string, annotations map[string]string) error {
+	svc, err := c.CoreV1().Services(ns).Get(name, meta_v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if svc.Annotations == nil {
+		svc.Annotations = map[string]string{}
+	}
+	for k, v := range annotations {
+		svc.Annotations[k] = v
+	}
+	_, err = c.CoreV1().Services(ns).Update(svc)
+	if err != nil {
+		return fmt.Errorf("failed to annotate and update service %s in namespace %s: %v", name, ns, err)
+	}
+	return nil
+}
+
 func AnnotateNamespaceServicesWithCertManager(c kubernetes.Interface, ns, issuer string, services ...string) error {
 	svcList, err := GetServices(c, ns)
 	if err != nil {
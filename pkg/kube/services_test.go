@@ -0,0 +1,52 @@
+package kube_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestFindExposedURLs(t *testing.T) {
+	t.Parallel()
+	exposed := &v1.Service{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:        "myapp",
+			Namespace:   "jx-staging",
+			Annotations: map[string]string{kube.ExposeURLAnnotation: "https://myapp.jx-staging.example.com"},
+		},
+	}
+	internal := &v1.Service{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "internal", Namespace: "jx-staging"},
+	}
+	client := fake.NewSimpleClientset(exposed, internal,
+		exposedURLsTestIngress("myapp", "myapp.example.com", true),
+		exposedURLsTestIngress("docs", "docs.example.com", false),
+		exposedURLsTestIngress("api", "api.example.com", true))
+
+	urls, err := kube.FindExposedURLs(client, "jx-staging")
+	require.NoError(t, err)
+	assert.Equal(t, []kube.ExposedURL{
+		{Namespace: "jx-staging", Name: "api", URL: "https://api.example.com", TLS: true},
+		{Namespace: "jx-staging", Name: "docs", URL: "http://docs.example.com", TLS: false},
+		{Namespace: "jx-staging", Name: "myapp", URL: "https://myapp.jx-staging.example.com", TLS: true},
+	}, urls)
+}
+
+func exposedURLsTestIngress(name string, host string, tls bool) *v1beta1.Ingress {
+	ing := &v1beta1.Ingress{
+		ObjectMeta: meta_v1.ObjectMeta{Name: name, Namespace: "jx-staging"},
+		Spec: v1beta1.IngressSpec{
+			Rules: []v1beta1.IngressRule{{Host: host}},
+		},
+	}
+	if tls {
+		ing.Spec.TLS = []v1beta1.IngressTLS{{Hosts: []string{host}}}
+	}
+	return ing
+}
@@ -0,0 +1,79 @@
+package kube
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// SecretIngressTLS the name of the TLS secret of a certificate brought by the user for the ingress domain
+	SecretIngressTLS = "jx-ingress-tls"
+)
+
+// ValidateTLSCertificate checks the PEM encoded certificate matches the key, has not expired and, if a domain is
+// given, covers the hosts of the domain which exposecontroller generates such as jenkins.jx.example.com
+func ValidateTLSCertificate(certPEM []byte, keyPEM []byte, domain string) error {
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return fmt.Errorf("invalid TLS certificate and key: %s", err)
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("failed to parse the TLS certificate: %s", err)
+	}
+	if time.Now().After(cert.NotAfter) {
+		return fmt.Errorf("the TLS certificate %s expired on %s", cert.Subject.CommonName, cert.NotAfter.Format(time.RFC3339))
+	}
+	if domain != "" {
+		host := "jenkins." + strings.TrimSuffix(domain, ".")
+		err = cert.VerifyHostname(host)
+		if err != nil {
+			return fmt.Errorf("the TLS certificate does not cover the domain %s: %s", domain, err)
+		}
+	}
+	return nil
+}
+
+// CreateOrUpdateTLSSecret creates the TLS secret with the PEM encoded certificate and key or updates the secret
+// if it already exists
+func CreateOrUpdateTLSSecret(client kubernetes.Interface, ns string, name string, certPEM []byte, keyPEM []byte) (*v1.Secret, error) {
+	secrets := client.CoreV1().Secrets(ns)
+	data := map[string][]byte{
+		v1.TLSCertKey:       certPEM,
+		v1.TLSPrivateKeyKey: keyPEM,
+	}
+	secret, err := secrets.Get(name, meta_v1.GetOptions{})
+	if errors.IsNotFound(err) {
+		secret = &v1.Secret{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name: name,
+			},
+			Type: v1.SecretTypeTLS,
+			Data: data,
+		}
+		return secrets.Create(secret)
+	}
+	if err != nil {
+		return nil, err
+	}
+	secret.Data = data
+	return secrets.Update(secret)
+}
+
+// CopyTLSSecret copies the TLS secret into the target namespace, replacing the data of an existing copy
+func CopyTLSSecret(client kubernetes.Interface, ns string, targetNamespace string, name string) error {
+	secret, err := client.CoreV1().Secrets(ns).Get(name, meta_v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	_, err = CreateOrUpdateTLSSecret(client, targetNamespace, name, secret.Data[v1.TLSCertKey], secret.Data[v1.TLSPrivateKeyKey])
+	return err
+}
@@ -0,0 +1,65 @@
+package kube_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kube_mocks "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestValidateTLSCertificate(t *testing.T) {
+	t.Parallel()
+	cert, key := generateCertificate(t, "*.jx.example.com", time.Now().Add(24*time.Hour))
+	assert.NoError(t, kube.ValidateTLSCertificate(cert, key, "jx.example.com"))
+	assert.Error(t, kube.ValidateTLSCertificate(cert, key, "other.example.com"))
+
+	_, otherKey := generateCertificate(t, "*.jx.example.com", time.Now().Add(24*time.Hour))
+	assert.Error(t, kube.ValidateTLSCertificate(cert, otherKey, "jx.example.com"))
+
+	expired, expiredKey := generateCertificate(t, "*.jx.example.com", time.Now().Add(-time.Hour))
+	assert.Error(t, kube.ValidateTLSCertificate(expired, expiredKey, "jx.example.com"))
+}
+
+func TestCreateOrUpdateTLSSecret(t *testing.T) {
+	t.Parallel()
+	client := kube_mocks.NewSimpleClientset()
+	_, err := kube.CreateOrUpdateTLSSecret(client, "jx", kube.SecretIngressTLS, []byte("cert"), []byte("key"))
+	require.NoError(t, err)
+	_, err = kube.CreateOrUpdateTLSSecret(client, "jx", kube.SecretIngressTLS, []byte("renewed"), []byte("key"))
+	require.NoError(t, err)
+
+	err = kube.CopyTLSSecret(client, "jx", "jx-staging", kube.SecretIngressTLS)
+	require.NoError(t, err)
+	secret, err := client.CoreV1().Secrets("jx-staging").Get(kube.SecretIngressTLS, meta_v1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, v1.SecretTypeTLS, secret.Type)
+	assert.Equal(t, "renewed", string(secret.Data[v1.TLSCertKey]))
+}
+
+func generateCertificate(t *testing.T, host string, notAfter time.Time) ([]byte, []byte) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: host},
+		DNSNames:     []string{host},
+		NotBefore:    notAfter.Add(-48 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return certPEM, keyPEM
+}
@@ -0,0 +1,186 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// DashboardLabel the label of the ConfigMaps which the Grafana sidecar loads dashboards from
+	DashboardLabel = "grafana_dashboard"
+
+	// DashboardPipelines the name of the dashboard of the pipelines
+	DashboardPipelines = "jenkins-x-pipelines"
+	// DashboardPreviewEnvironments the name of the dashboard of the preview environments
+	DashboardPreviewEnvironments = "jenkins-x-preview-environments"
+
+	// the build and workflow controllers may both record the same pipelines so take the maximum of the series of the
+	// controllers and their instances
+	dedupe = "max without (instance, pod, job, controller)"
+)
+
+type dashboard struct {
+	UID           string     `json:"uid"`
+	Title         string     `json:"title"`
+	Tags          []string   `json:"tags"`
+	SchemaVersion int        `json:"schemaVersion"`
+	Refresh       string     `json:"refresh"`
+	Time          timeRange  `json:"time"`
+	Templating    templating `json:"templating"`
+	Panels        []panel    `json:"panels"`
+}
+
+type timeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type templating struct {
+	List []variable `json:"list"`
+}
+
+type variable struct {
+	Name       string `json:"name"`
+	Label      string `json:"label"`
+	Type       string `json:"type"`
+	Query      string `json:"query"`
+	Refresh    int    `json:"refresh"`
+	IncludeAll bool   `json:"includeAll"`
+	Multi      bool   `json:"multi"`
+	AllValue   string `json:"allValue,omitempty"`
+}
+
+type panel struct {
+	ID      int      `json:"id"`
+	Title   string   `json:"title"`
+	Type    string   `json:"type"`
+	GridPos gridPos  `json:"gridPos"`
+	Targets []target `json:"targets"`
+	Format  string   `json:"format,omitempty"`
+}
+
+type gridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type target struct {
+	Expr         string `json:"expr"`
+	LegendFormat string `json:"legendFormat,omitempty"`
+	RefID        string `json:"refId"`
+}
+
+// Dashboards returns the JSON of the Grafana dashboards of the metrics of the controllers indexed by their name
+func Dashboards() (map[string]string, error) {
+	answer := map[string]string{}
+	for name, d := range map[string]*dashboard{
+		DashboardPipelines:           pipelinesDashboard(),
+		DashboardPreviewEnvironments: previewEnvironmentsDashboard(),
+	} {
+		data, err := json.MarshalIndent(d, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal dashboard %s: %s", name, err)
+		}
+		answer[name] = string(data)
+	}
+	return answer, nil
+}
+
+func newDashboard(uid string, title string, panels ...panel) *dashboard {
+	for i := range panels {
+		panels[i].ID = i + 1
+		for j := range panels[i].Targets {
+			panels[i].Targets[j].RefID = string(rune('A' + j))
+		}
+	}
+	return &dashboard{
+		UID:           uid,
+		Title:         title,
+		Tags:          []string{"jenkins-x"},
+		SchemaVersion: 16,
+		Refresh:       "1m",
+		Time:          timeRange{From: "now-24h", To: "now"},
+		Templating:    templating{List: []variable{}},
+		Panels:        panels,
+	}
+}
+
+func pipelinesDashboard() *dashboard {
+	selector := fmt.Sprintf(`{%s=~"$repository"}`, LabelRepository)
+	increase := func(metric string) string {
+		return fmt.Sprintf("sum by (%s) (%s (increase(%s%s[$__interval])))", LabelRepository, dedupe, metric, selector)
+	}
+	quantile := func(q string) string {
+		return fmt.Sprintf("histogram_quantile(%s, sum by (le, %s) (%s (rate(%s_bucket%s[1h]))))", q, LabelRepository, dedupe, MetricPipelineDuration, selector)
+	}
+	d := newDashboard(DashboardPipelines, "Jenkins X Pipelines",
+		panel{
+			Title:   "Pipelines started",
+			Type:    "graph",
+			GridPos: gridPos{H: 8, W: 12, X: 0, Y: 0},
+			Targets: []target{{Expr: increase(MetricPipelinesStarted), LegendFormat: "{{" + LabelRepository + "}}"}},
+		},
+		panel{
+			Title:   "Pipelines succeeded and failed",
+			Type:    "graph",
+			GridPos: gridPos{H: 8, W: 12, X: 12, Y: 0},
+			Targets: []target{
+				{Expr: increase(MetricPipelinesSucceeded), LegendFormat: "{{" + LabelRepository + "}} succeeded"},
+				{Expr: increase(MetricPipelinesFailed), LegendFormat: "{{" + LabelRepository + "}} failed"},
+			},
+		},
+		panel{
+			Title:   "Pipeline success rate",
+			Type:    "singlestat",
+			Format:  "percentunit",
+			GridPos: gridPos{H: 8, W: 6, X: 0, Y: 8},
+			Targets: []target{{
+				Expr: fmt.Sprintf("sum(%s (increase(%s%s[24h]))) / (sum(%s (increase(%s%s[24h]))) + sum(%s (increase(%s%s[24h]))))",
+					dedupe, MetricPipelinesSucceeded, selector,
+					dedupe, MetricPipelinesSucceeded, selector,
+					dedupe, MetricPipelinesFailed, selector),
+			}},
+		},
+		panel{
+			Title:   "Pipeline duration",
+			Type:    "graph",
+			Format:  "s",
+			GridPos: gridPos{H: 8, W: 18, X: 6, Y: 8},
+			Targets: []target{
+				{Expr: quantile("0.5"), LegendFormat: "{{" + LabelRepository + "}} p50"},
+				{Expr: quantile("0.95"), LegendFormat: "{{" + LabelRepository + "}} p95"},
+			},
+		},
+	)
+	d.Templating.List = append(d.Templating.List, variable{
+		Name:       LabelRepository,
+		Label:      "Repository",
+		Type:       "query",
+		Query:      fmt.Sprintf("label_values(%s, %s)", MetricPipelinesStarted, LabelRepository),
+		Refresh:    2,
+		IncludeAll: true,
+		Multi:      true,
+		AllValue:   ".*",
+	})
+	return d
+}
+
+func previewEnvironmentsDashboard() *dashboard {
+	expr := fmt.Sprintf("max(%s)", MetricPreviewEnvironmentsActive)
+	return newDashboard(DashboardPreviewEnvironments, "Jenkins X Preview Environments",
+		panel{
+			Title:   "Active preview environments",
+			Type:    "singlestat",
+			GridPos: gridPos{H: 8, W: 6, X: 0, Y: 0},
+			Targets: []target{{Expr: expr}},
+		},
+		panel{
+			Title:   "Preview environments over time",
+			Type:    "graph",
+			GridPos: gridPos{H: 8, W: 18, X: 6, Y: 0},
+			Targets: []target{{Expr: expr, LegendFormat: "preview environments"}},
+		},
+	)
+}
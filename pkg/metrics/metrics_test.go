@@ -0,0 +1,147 @@
+package metrics_test
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestMetricNames the dashboards and any alerts users have written depend on these names so they must not change
+func TestMetricNames(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, []string{
+		"jx_pipelines_started_total",
+		"jx_pipelines_succeeded_total",
+		"jx_pipelines_failed_total",
+		"jx_pipeline_duration_seconds",
+		"jx_preview_environments_active",
+	}, metrics.MetricNames())
+
+	registry := prometheus.NewRegistry()
+	m, err := metrics.NewPipelineMetrics(registry, "workflow")
+	require.NoError(t, err)
+	m.OnPipelineActivity(activity("jstrachan-demo-master-1", v1.ActivityStatusTypeRunning, 0))
+	m.OnPipelineActivity(activity("jstrachan-demo-master-1", v1.ActivityStatusTypeSucceeded, time.Minute))
+	m.SetPreviewEnvironments(1)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	names := []string{}
+	for _, family := range families {
+		names = append(names, family.GetName())
+	}
+	expected := metrics.MetricNames()
+	expected = append(expected[:2], expected[3:]...)
+	sort.Strings(expected)
+	assert.Equal(t, expected, names, "no pipeline has failed yet")
+}
+
+func TestPipelineMetrics(t *testing.T) {
+	t.Parallel()
+	registry := prometheus.NewRegistry()
+	m, err := metrics.NewPipelineMetrics(registry, "workflow")
+	require.NoError(t, err)
+
+	// already completed when the controller starts
+	m.OnPipelineActivity(activity("jstrachan-demo-master-1", v1.ActivityStatusTypeSucceeded, time.Minute))
+
+	m.OnPipelineActivity(activity("jstrachan-demo-master-2", v1.ActivityStatusTypePending, 0))
+	m.OnPipelineActivity(activity("jstrachan-demo-master-2", v1.ActivityStatusTypeRunning, 0))
+	m.OnPipelineActivity(activity("jstrachan-demo-master-2", v1.ActivityStatusTypeSucceeded, 90*time.Second))
+	m.OnPipelineActivity(activity("jstrachan-demo-master-2", v1.ActivityStatusTypeSucceeded, 90*time.Second))
+
+	m.OnPipelineActivity(activity("jstrachan-demo-master-3", v1.ActivityStatusTypeRunning, 0))
+	m.OnPipelineActivity(activity("jstrachan-demo-master-3", v1.ActivityStatusTypeAborted, 10*time.Second))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	values := map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.Metric {
+			labels := []string{}
+			for _, label := range metric.Label {
+				labels = append(labels, label.GetName()+"="+label.GetValue())
+			}
+			key := family.GetName() + "{" + strings.Join(labels, ",") + "}"
+			if metric.Counter != nil {
+				values[key] = metric.Counter.GetValue()
+			}
+			if metric.Histogram != nil {
+				values[key] = float64(metric.Histogram.GetSampleCount())
+				values[key+"_sum"] = metric.Histogram.GetSampleSum()
+			}
+		}
+	}
+	labels := "{branch=master,controller=workflow,repository=jstrachan/demo}"
+	assert.Equal(t, map[string]float64{
+		"jx_pipelines_started_total" + labels:            2,
+		"jx_pipelines_succeeded_total" + labels:          1,
+		"jx_pipelines_failed_total" + labels:             1,
+		"jx_pipeline_duration_seconds" + labels:          2,
+		"jx_pipeline_duration_seconds" + labels + "_sum": 100,
+	}, values)
+}
+
+func TestDashboardsUseExportedMetrics(t *testing.T) {
+	t.Parallel()
+	dashboards, err := metrics.Dashboards()
+	require.NoError(t, err)
+	assert.Len(t, dashboards, 2)
+
+	metricPattern := regexp.MustCompile(`jx_[a-z_]+`)
+	used := map[string]bool{}
+	for name, text := range dashboards {
+		dashboard := struct {
+			Panels []struct {
+				Targets []struct {
+					Expr string `json:"expr"`
+				} `json:"targets"`
+			} `json:"panels"`
+		}{}
+		require.NoError(t, json.Unmarshal([]byte(text), &dashboard), "dashboard %s", name)
+		for _, panel := range dashboard.Panels {
+			require.NotEmpty(t, panel.Targets)
+			for _, target := range panel.Targets {
+				for _, metric := range metricPattern.FindAllString(target.Expr, -1) {
+					metric = strings.TrimSuffix(metric, "_bucket")
+					assert.Contains(t, metrics.MetricNames(), metric, "dashboard %s uses an unknown metric", name)
+					used[metric] = true
+				}
+			}
+		}
+	}
+	for _, metric := range metrics.MetricNames() {
+		assert.True(t, used[metric], "metric %s is not used by any dashboard", metric)
+	}
+	assert.Contains(t, dashboards[metrics.DashboardPipelines], "max without (instance, pod, job, controller)",
+		"the pipelines recorded by both the build and workflow controllers are counted once")
+}
+
+func activity(name string, status v1.ActivityStatusType, duration time.Duration) *v1.PipelineActivity {
+	started := metav1.NewTime(time.Date(2018, 10, 1, 12, 0, 0, 0, time.UTC))
+	a := &v1.PipelineActivity{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: v1.PipelineActivitySpec{
+			Pipeline:         "jstrachan/demo/master",
+			Status:           status,
+			StartedTimestamp: &started,
+		},
+	}
+	if status.IsTerminated() {
+		completed := metav1.NewTime(started.Add(duration))
+		a.Spec.CompletedTimestamp = &completed
+	}
+	return a
+}
@@ -0,0 +1,174 @@
+package metrics
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricPipelinesStarted the number of pipelines which have started
+	MetricPipelinesStarted = "jx_pipelines_started_total"
+	// MetricPipelinesSucceeded the number of pipelines which have succeeded
+	MetricPipelinesSucceeded = "jx_pipelines_succeeded_total"
+	// MetricPipelinesFailed the number of pipelines which have failed, errored or been aborted
+	MetricPipelinesFailed = "jx_pipelines_failed_total"
+	// MetricPipelineDuration the duration of the completed pipelines in seconds
+	MetricPipelineDuration = "jx_pipeline_duration_seconds"
+	// MetricPreviewEnvironmentsActive the number of preview environments
+	MetricPreviewEnvironmentsActive = "jx_preview_environments_active"
+
+	// LabelRepository the label of the owner/name of the git repository of a pipeline
+	LabelRepository = "repository"
+	// LabelBranch the label of the branch of a pipeline
+	LabelBranch = "branch"
+	// LabelController the label of the name of the controller which recorded the metrics
+	LabelController = "controller"
+)
+
+// MetricNames returns the names of all the metrics exported by the controllers which the dashboards depend on
+func MetricNames() []string {
+	return []string{
+		MetricPipelinesStarted,
+		MetricPipelinesSucceeded,
+		MetricPipelinesFailed,
+		MetricPipelineDuration,
+		MetricPreviewEnvironmentsActive,
+	}
+}
+
+// PipelineMetrics records the metrics of the pipelines from the changes of their PipelineActivity resources. A nil
+// PipelineMetrics records nothing so that the metrics can be disabled
+type PipelineMetrics struct {
+	started   *prometheus.CounterVec
+	succeeded *prometheus.CounterVec
+	failed    *prometheus.CounterVec
+	duration  *prometheus.HistogramVec
+	previews  prometheus.Gauge
+
+	lock     sync.Mutex
+	statuses map[string]v1.ActivityStatusType
+}
+
+// NewPipelineMetrics creates the pipeline metrics of the named controller registering them with the given registerer
+func NewPipelineMetrics(registerer prometheus.Registerer, controller string) (*PipelineMetrics, error) {
+	labels := []string{LabelRepository, LabelBranch}
+	constLabels := prometheus.Labels{LabelController: controller}
+	m := &PipelineMetrics{
+		started: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        MetricPipelinesStarted,
+			Help:        "The number of pipelines which have started",
+			ConstLabels: constLabels,
+		}, labels),
+		succeeded: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        MetricPipelinesSucceeded,
+			Help:        "The number of pipelines which have succeeded",
+			ConstLabels: constLabels,
+		}, labels),
+		failed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        MetricPipelinesFailed,
+			Help:        "The number of pipelines which have failed, errored or been aborted",
+			ConstLabels: constLabels,
+		}, labels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: MetricPipelineDuration,
+			Help: "The duration of the completed pipelines in seconds",
+			// from 30 seconds to about 2 hours
+			Buckets:     prometheus.ExponentialBuckets(30, 2, 9),
+			ConstLabels: constLabels,
+		}, labels),
+		previews: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        MetricPreviewEnvironmentsActive,
+			Help:        "The number of preview environments",
+			ConstLabels: constLabels,
+		}),
+		statuses: map[string]v1.ActivityStatusType{},
+	}
+	for _, c := range []prometheus.Collector{m.started, m.succeeded, m.failed, m.duration, m.previews} {
+		err := registerer.Register(c)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// OnPipelineActivity records the status changes of the pipeline. A pipeline is counted as started when it is first
+// seen before it has terminated and as succeeded or failed when it then terminates. Pipelines which have already
+// terminated when they are first seen, such as the existing ones when a controller starts, are not counted
+func (m *PipelineMetrics) OnPipelineActivity(activity *v1.PipelineActivity) {
+	if m == nil {
+		return
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	status := activity.Spec.Status
+	previous, seen := m.statuses[activity.Name]
+	m.statuses[activity.Name] = status
+	if seen && (previous == status || previous.IsTerminated()) {
+		return
+	}
+	repository, branch := pipelineLabels(activity)
+	if !seen {
+		if status.IsTerminated() {
+			return
+		}
+		m.started.WithLabelValues(repository, branch).Inc()
+	}
+	if !status.IsTerminated() {
+		return
+	}
+	if status == v1.ActivityStatusTypeSucceeded {
+		m.succeeded.WithLabelValues(repository, branch).Inc()
+	} else {
+		m.failed.WithLabelValues(repository, branch).Inc()
+	}
+	spec := &activity.Spec
+	if spec.StartedTimestamp != nil && spec.CompletedTimestamp != nil {
+		duration := spec.CompletedTimestamp.Sub(spec.StartedTimestamp.Time)
+		if duration >= 0 {
+			m.duration.WithLabelValues(repository, branch).Observe(duration.Seconds())
+		}
+	}
+}
+
+// OnPipelineActivityDeleted forgets the status of the deleted pipeline
+func (m *PipelineMetrics) OnPipelineActivityDeleted(activity *v1.PipelineActivity) {
+	if m == nil {
+		return
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.statuses, activity.Name)
+}
+
+// SetPreviewEnvironments records the number of preview environments
+func (m *PipelineMetrics) SetPreviewEnvironments(count int) {
+	if m == nil {
+		return
+	}
+	m.previews.Set(float64(count))
+}
+
+// pipelineLabels returns the owner/name of the repository and the branch of the pipeline
+func pipelineLabels(activity *v1.PipelineActivity) (string, string) {
+	// RepositoryName() defaults the repository of the spec so use a copy to avoid modifying the cached resource
+	a := *activity
+	branch := a.BranchName()
+	repository := a.RepositoryName()
+	owner := a.Spec.GitOwner
+	if owner == "" {
+		paths := strings.Split(a.Spec.Pipeline, "/")
+		if len(paths) > 2 {
+			owner = paths[len(paths)-3]
+		}
+	}
+	if owner != "" && repository != "" {
+		repository = owner + "/" + repository
+	}
+	return repository, branch
+}
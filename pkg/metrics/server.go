@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// Path the HTTP path the metrics are served on
+	Path = "/metrics"
+
+	// DefaultPort the default port the controllers serve their metrics on
+	DefaultPort = 8080
+)
+
+// Serve serves the metrics of the default prometheus registry on the port in the background
+func Serve(port int) {
+	mux := http.NewServeMux()
+	mux.Handle(Path, prometheus.Handler())
+	log.Infof("Serving metrics on port %d at %s\n", port, Path)
+	go func() {
+		err := http.ListenAndServe(fmt.Sprintf(":%d", port), mux)
+		if err != nil {
+			log.Warnf("Failed to serve the metrics on port %d: %s\n", port, err)
+		}
+	}()
+}
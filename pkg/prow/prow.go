@@ -2,12 +2,14 @@ package prow
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/ghodss/yaml"
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
 	build "github.com/knative/build/pkg/apis/build/v1alpha1"
 	"k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/test-infra/prow/config"
@@ -21,6 +23,9 @@ const (
 
 	Application Kind = "APPLICATION"
 	Environment Kind = "ENVIRONMENT"
+
+	gitSSHKeyVolume    = "git-ssh-key"
+	gitSSHKeyMountPath = "/home/jenkins/.ssh-git"
 )
 
 type Kind string
@@ -33,9 +38,11 @@ type Options struct {
 	Kind                 Kind
 	DraftPack            string
 	EnvironmentNamespace string
+	// GitSSHKeySecret the Secret with the SSH deploy key the builds use for ssh git remotes, if any
+	GitSSHKeySecret string
 }
 
-func add(kubeClient kubernetes.Interface, repos []string, ns string, kind Kind, draftPack, environmentNamespace string) error {
+func add(kubeClient kubernetes.Interface, repos []string, ns string, kind Kind, draftPack, environmentNamespace string, gitSSHKeySecret string) error {
 
 	if len(repos) == 0 {
 		return fmt.Errorf("no repo defined")
@@ -47,6 +54,7 @@ func add(kubeClient kubernetes.Interface, repos []string, ns string, kind Kind,
 		Kind:                 kind,
 		DraftPack:            draftPack,
 		EnvironmentNamespace: environmentNamespace,
+		GitSSHKeySecret:      gitSSHKeySecret,
 	}
 
 	err := o.AddProwConfig()
@@ -57,12 +65,80 @@ func add(kubeClient kubernetes.Interface, repos []string, ns string, kind Kind,
 	return o.AddProwPlugins()
 }
 
-func AddEnvironment(kubeClient kubernetes.Interface, repos []string, ns, environmentNamespace string) error {
-	return add(kubeClient, repos, ns, Environment, "", environmentNamespace)
+func AddEnvironment(kubeClient kubernetes.Interface, repos []string, ns, environmentNamespace string, gitSSHKeySecret string) error {
+	return add(kubeClient, repos, ns, Environment, "", environmentNamespace, gitSSHKeySecret)
+}
+
+func AddApplication(kubeClient kubernetes.Interface, repos []string, ns, draftPack string, gitSSHKeySecret string) error {
+	return add(kubeClient, repos, ns, Application, draftPack, "", gitSSHKeySecret)
+}
+
+// addGitSSHKey mounts the SSH deploy key Secret into the steps of the build and makes git use it for ssh remotes.
+// The Secret contains the private key in ssh-privatekey and the keys of the git servers in known_hosts
+func (o *Options) addGitSSHKey(spec *build.BuildSpec) {
+	if o.GitSSHKeySecret == "" {
+		return
+	}
+	mode := int32(0400)
+	spec.Volumes = append(spec.Volumes, v1.Volume{
+		Name: gitSSHKeyVolume,
+		VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{
+			SecretName:  o.GitSSHKeySecret,
+			DefaultMode: &mode,
+		}},
+	})
+	sshCommand := fmt.Sprintf("ssh -i %s/ssh-privatekey -o UserKnownHostsFile=%s/known_hosts", gitSSHKeyMountPath, gitSSHKeyMountPath)
+	for i := range spec.Steps {
+		step := &spec.Steps[i]
+		step.VolumeMounts = append(step.VolumeMounts, v1.VolumeMount{Name: gitSSHKeyVolume, MountPath: gitSSHKeyMountPath, ReadOnly: true})
+		step.Env = append(step.Env, v1.EnvVar{Name: "GIT_SSH_COMMAND", Value: sshCommand})
+	}
+}
+
+// IsRepositoryRegistered returns true if the prow configuration in the namespace has jobs for the repository of the
+// form org/name
+func IsRepositoryRegistered(kubeClient kubernetes.Interface, ns string, repo string) (bool, error) {
+	cm, err := kubeClient.CoreV1().ConfigMaps(ns).Get("config", metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	prowConfig := &config.Config{}
+	err = yaml.Unmarshal([]byte(cm.Data["config.yaml"]), prowConfig)
+	if err != nil {
+		return false, err
+	}
+	return len(prowConfig.Presubmits[repo]) > 0 || len(prowConfig.Postsubmits[repo]) > 0, nil
 }
 
-func AddApplication(kubeClient kubernetes.Interface, repos []string, ns, draftPack string) error {
-	return add(kubeClient, repos, ns, Application, draftPack, "")
+// GetRepositories returns the repositories of the form org/name which have jobs in the prow configuration in the
+// namespace
+func GetRepositories(kubeClient kubernetes.Interface, ns string) ([]string, error) {
+	answer := []string{}
+	cm, err := kubeClient.CoreV1().ConfigMaps(ns).Get("config", metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return answer, nil
+		}
+		return answer, err
+	}
+	prowConfig := &config.Config{}
+	err = yaml.Unmarshal([]byte(cm.Data["config.yaml"]), prowConfig)
+	if err != nil {
+		return answer, err
+	}
+	for repo := range prowConfig.Presubmits {
+		answer = append(answer, repo)
+	}
+	for repo := range prowConfig.Postsubmits {
+		if _, ok := prowConfig.Presubmits[repo]; !ok {
+			answer = append(answer, repo)
+		}
+	}
+	sort.Strings(answer)
+	return answer, nil
 }
 
 // create Git repo?
@@ -99,6 +175,7 @@ func (o *Options) createPreSubmitEnvironment() config.Presubmit {
 		ServiceAccountName: "jenkins",
 	}
 
+	o.addGitSSHKey(spec)
 	ps.BuildSpec = spec
 	ps.RerunCommand = "/test this"
 	ps.Trigger = "(?m)^/test( all| this),?(\\s+|$)"
@@ -131,6 +208,7 @@ func (o *Options) createPostSubmitEnvironment() config.Postsubmit {
 		},
 		ServiceAccountName: "jenkins",
 	}
+	o.addGitSSHKey(spec)
 	ps.BuildSpec = spec
 	return ps
 }
@@ -182,6 +260,7 @@ func (o *Options) createPostSubmitApplication() config.Postsubmit {
 		},
 	}
 
+	o.addGitSSHKey(spec)
 	ps.BuildSpec = spec
 	return ps
 }
@@ -233,6 +312,7 @@ func (o *Options) createPreSubmitApplication() config.Presubmit {
 		},
 	}
 
+	o.addGitSSHKey(spec)
 	ps.BuildSpec = spec
 	ps.RerunCommand = "/test this"
 	ps.Trigger = "(?m)^/test( all| this),?(\\s+|$)"
@@ -269,3 +269,70 @@ func TestReplaceProwConfig(t *testing.T) {
 	assert.Equal(t, 3, len(prowConfig.Tide.Queries[0].Repos))
 	assert.Equal(t, 2, len(prowConfig.Tide.Queries[1].Repos))
 }
+
+func TestIsRepositoryRegistered(t *testing.T) {
+	t.Parallel()
+	o := TestOptions{}
+	o.Setup()
+	o.Kind = prow.Application
+
+	registered, err := prow.IsRepositoryRegistered(o.KubeClient, o.NS, "test/repo")
+	assert.NoError(t, err)
+	assert.False(t, registered, "no prow config yet")
+
+	err = o.AddProwConfig()
+	assert.NoError(t, err)
+
+	registered, err = prow.IsRepositoryRegistered(o.KubeClient, o.NS, "test/repo")
+	assert.NoError(t, err)
+	assert.True(t, registered)
+
+	registered, err = prow.IsRepositoryRegistered(o.KubeClient, o.NS, "test/other")
+	assert.NoError(t, err)
+	assert.False(t, registered)
+}
+
+func TestGetRepositories(t *testing.T) {
+	t.Parallel()
+	o := TestOptions{}
+	o.Setup()
+	o.Kind = prow.Application
+
+	repos, err := prow.GetRepositories(o.KubeClient, o.NS)
+	assert.NoError(t, err)
+	assert.Empty(t, repos, "no prow config yet")
+
+	err = o.AddProwConfig()
+	assert.NoError(t, err)
+	o.Repos = []string{"test/another"}
+	err = o.AddProwConfig()
+	assert.NoError(t, err)
+
+	repos, err = prow.GetRepositories(o.KubeClient, o.NS)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"test/another", "test/repo"}, repos)
+}
+
+func TestProwConfigGitSSHKey(t *testing.T) {
+	t.Parallel()
+	o := TestOptions{}
+	o.Setup()
+	o.Kind = prow.Application
+	o.GitSSHKeySecret = "git-deploy-key"
+
+	err := o.AddProwConfig()
+	assert.NoError(t, err)
+
+	cm, err := o.KubeClient.CoreV1().ConfigMaps(o.NS).Get("config", metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	prowConfig := &config.Config{}
+	yaml.Unmarshal([]byte(cm.Data["config.yaml"]), &prowConfig)
+
+	spec := prowConfig.Postsubmits["test/repo"][0].BuildSpec
+	assert.NotNil(t, spec)
+	assert.Equal(t, "git-deploy-key", spec.Volumes[len(spec.Volumes)-1].Secret.SecretName)
+	for _, step := range spec.Steps {
+		assert.Contains(t, step.Env, v1.EnvVar{Name: "GIT_SSH_COMMAND", Value: "ssh -i /home/jenkins/.ssh-git/ssh-privatekey -o UserKnownHostsFile=/home/jenkins/.ssh-git/known_hosts"})
+	}
+}
@@ -0,0 +1,88 @@
+package quickstarts
+
+import (
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/util"
+	"gopkg.in/yaml.v2"
+)
+
+// invalidCacheFileCharacters matches the characters which are replaced in the names of the catalog cache files
+var invalidCacheFileCharacters = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// Catalog the repositories of an owner which were listed at a point in time
+type Catalog struct {
+	Created      time.Time             `yaml:"created"`
+	Repositories []*gits.GitRepository `yaml:"repositories"`
+}
+
+// CatalogFileName returns the name of the file caching the catalog of the owner on the Git server
+func CatalogFileName(serverURL string, owner string) string {
+	host := serverURL
+	u, err := url.Parse(serverURL)
+	if err == nil && u.Host != "" {
+		host = u.Host
+	}
+	return invalidCacheFileCharacters.ReplaceAllString(host+"-"+owner, "-") + ".yml"
+}
+
+// LoadCatalog loads the catalog from the file returning nil if it does not exist or is older than the ttl
+func LoadCatalog(fileName string, ttl time.Duration) (*Catalog, error) {
+	exists, err := util.FileExists(fileName)
+	if err != nil || !exists {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	catalog := &Catalog{}
+	err = yaml.Unmarshal(data, catalog)
+	if err != nil {
+		return nil, err
+	}
+	if time.Since(catalog.Created) > ttl {
+		return nil, nil
+	}
+	return catalog, nil
+}
+
+// SaveCatalog saves the catalog to the file
+func SaveCatalog(fileName string, catalog *Catalog) error {
+	data, err := yaml.Marshal(catalog)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fileName, data, util.DefaultWritePermissions)
+}
+
+// LoadCachedGitQuickstarts loads the quickstarts of the owner from the catalog cached in the directory if it is newer
+// than the ttl, otherwise the repositories are listed using the provider and cached. A ttl of zero disables the cache
+func (model *QuickstartModel) LoadCachedGitQuickstarts(provider gits.GitProvider, owner string, includes []string, excludes []string, cacheDir string, ttl time.Duration) error {
+	if cacheDir == "" || ttl <= 0 {
+		return model.LoadGithubQuickstarts(provider, owner, includes, excludes)
+	}
+	fileName := filepath.Join(cacheDir, CatalogFileName(provider.ServerURL(), owner))
+	catalog, err := LoadCatalog(fileName, ttl)
+	if err != nil || catalog == nil {
+		repos, err := provider.ListRepositories(owner)
+		if err != nil {
+			return err
+		}
+		catalog = &Catalog{
+			Created:      time.Now(),
+			Repositories: repos,
+		}
+		err = SaveCatalog(fileName, catalog)
+		if err != nil {
+			return err
+		}
+	}
+	model.addQuickstarts(provider, owner, catalog.Repositories, includes, excludes)
+	return nil
+}
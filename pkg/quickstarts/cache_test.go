@@ -0,0 +1,66 @@
+package quickstarts_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/auth"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/quickstarts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogFileName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "github.example.com-starters.yml", quickstarts.CatalogFileName("https://github.example.com", "starters"))
+	assert.Equal(t, "gitlab.com-group-subgroup.yml", quickstarts.CatalogFileName("https://gitlab.com/", "group/subgroup"))
+}
+
+func TestLoadCachedGitQuickstarts(t *testing.T) {
+	t.Parallel()
+	cacheDir, err := ioutil.TempDir("", "test-quickstarts-cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(cacheDir)
+
+	provider := gits.NewFakeProvider(&gits.FakeRepository{
+		Owner:   "starters",
+		GitRepo: &gits.GitRepository{Name: "node-http", CloneURL: "https://github.example.com/starters/node-http.git", Language: "JavaScript"},
+	}, &gits.FakeRepository{
+		Owner:   "starters",
+		GitRepo: &gits.GitRepository{Name: "WIP-golang", CloneURL: "https://github.example.com/starters/WIP-golang.git"},
+	})
+	provider.Server = auth.AuthServer{URL: "https://github.example.com"}
+
+	model := quickstarts.NewQuickstartModel()
+	err = model.LoadCachedGitQuickstarts(provider, "starters", []string{"*"}, []string{"WIP-*"}, cacheDir, time.Hour)
+	require.NoError(t, err)
+	require.Len(t, model.Quickstarts, 1)
+	q := model.Quickstarts["starters/node-http"]
+	require.NotNil(t, q)
+	assert.Equal(t, "https://github.example.com/starters/node-http.git", q.GitURL)
+	assert.Equal(t, "JavaScript", q.Language)
+
+	fileName := filepath.Join(cacheDir, "github.example.com-starters.yml")
+	catalog, err := quickstarts.LoadCatalog(fileName, time.Hour)
+	require.NoError(t, err)
+	require.NotNil(t, catalog)
+	assert.Len(t, catalog.Repositories, 2)
+
+	// the cached catalog is used while it is fresh
+	provider.Repositories["starters"] = nil
+	model = quickstarts.NewQuickstartModel()
+	err = model.LoadCachedGitQuickstarts(provider, "starters", []string{"*"}, []string{"WIP-*"}, cacheDir, time.Hour)
+	require.NoError(t, err)
+	assert.Len(t, model.Quickstarts, 1)
+
+	catalog.Created = time.Now().Add(-2 * time.Hour)
+	require.NoError(t, quickstarts.SaveCatalog(fileName, catalog))
+	model = quickstarts.NewQuickstartModel()
+	err = model.LoadCachedGitQuickstarts(provider, "starters", []string{"*"}, []string{"WIP-*"}, cacheDir, time.Hour)
+	require.NoError(t, err)
+	assert.Empty(t, model.Quickstarts, "the expired catalog is listed again")
+}
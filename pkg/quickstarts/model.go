@@ -37,7 +37,9 @@ func toGitHubQuickstart(provider gits.GitProvider, owner string, repo *gits.GitR
 	// TODO find this from GitHub???
 	framework := ""
 	tags := []string{}
-	return GitQuickstart(provider, owner, repo.Name, language, framework, tags...)
+	answer := GitQuickstart(provider, owner, repo.Name, language, framework, tags...)
+	answer.GitURL = repo.CloneURL
+	return answer
 }
 
 // LoadGithubQuickstarts Loads quickstarts from github
@@ -46,13 +48,17 @@ func (model *QuickstartModel) LoadGithubQuickstarts(provider gits.GitProvider, o
 	if err != nil {
 		return err
 	}
+	model.addQuickstarts(provider, owner, repos, includes, excludes)
+	return nil
+}
+
+func (model *QuickstartModel) addQuickstarts(provider gits.GitProvider, owner string, repos []*gits.GitRepository, includes []string, excludes []string) {
 	for _, repo := range repos {
 		name := repo.Name
 		if util.StringMatchesAny(name, includes, excludes) {
 			model.Add(toGitHubQuickstart(provider, owner, repo))
 		}
 	}
-	return nil
 }
 
 // NewQuickstartModel creates a new quickstart model
@@ -62,11 +68,17 @@ func NewQuickstartModel() *QuickstartModel {
 	}
 }
 
-// Add adds the given quickstart to this mode. Returns true if it was added
+// Add adds the given quickstart to this mode. Returns true if it was added.
+// A quickstart of the same name loaded from an earlier location is replaced so that the most specific location wins
 func (model *QuickstartModel) Add(q *Quickstart) bool {
 	if q != nil {
 		id := q.ID
 		if id != "" {
+			for key, existing := range model.Quickstarts {
+				if key != id && existing.Name == q.Name {
+					delete(model.Quickstarts, key)
+				}
+			}
 			model.Quickstarts[id] = q
 			return true
 		}
@@ -91,11 +103,25 @@ func (model *QuickstartModel) CreateSurvey(filter *QuickstartFilter, batchMode b
 			}
 		}
 	}
+	framework := filter.Framework
+	if framework != "" {
+		frameworks := model.Frameworks()
+		if len(frameworks) == 0 {
+			// lets ignore this filter as there are none available
+			filter.Framework = ""
+		} else {
+			lower := strings.ToLower(framework)
+			lowerFrameworks := util.StringArrayToLower(frameworks)
+			if util.StringArrayIndex(lowerFrameworks, lower) < 0 {
+				return nil, util.InvalidOption("framework", framework, frameworks)
+			}
+		}
+	}
 	quickstarts := model.Filter(filter)
 	names := []string{}
 	m := map[string]*Quickstart{}
 	for _, q := range quickstarts {
-		name := q.SurveyName()
+		name := q.SurveyOption()
 		m[name] = q
 		names = append(names, name)
 	}
@@ -109,8 +135,10 @@ func (model *QuickstartModel) CreateSurvey(filter *QuickstartFilter, batchMode b
 		answer = names[0]
 	} else {
 		prompt := &survey.Select{
-			Message: "select the quickstart you wish to create",
-			Options: names,
+			Message:  "select the quickstart you wish to create",
+			Options:  names,
+			PageSize: 15,
+			Help:     "Type to filter the quickstarts by any part of their name, language or framework",
 		}
 		err := survey.AskOne(prompt, &answer, survey.Required, surveyOpts)
 		if err != nil {
@@ -160,6 +188,27 @@ func (model *QuickstartModel) Filter(filter *QuickstartFilter) []*Quickstart {
 	return answer
 }
 
+// SortedQuickstarts returns the quickstarts matching the filter sorted by their survey name
+func (model *QuickstartModel) SortedQuickstarts(filter *QuickstartFilter) []*Quickstart {
+	answer := model.Filter(filter)
+	sort.Slice(answer, func(i, j int) bool {
+		return answer[i].SurveyName() < answer[j].SurveyName()
+	})
+	return answer
+}
+
+// Frameworks returns all the frameworks in the quickstarts sorted
+func (model *QuickstartModel) Frameworks() []string {
+	m := map[string]string{}
+	for _, q := range model.Quickstarts {
+		f := q.Framework
+		if f != "" {
+			m[f] = f
+		}
+	}
+	return util.SortedMapKeys(m)
+}
+
 // Languages returns all the languages in the quickstarts sorted
 func (model *QuickstartModel) Languages() []string {
 	m := map[string]string{}
@@ -112,3 +112,72 @@ func TestQuickstartModelFilterTextMatchesOneExactly(t *testing.T) {
 	assert.Equal(t, 1, len(results))
 	assert.Contains(t, results, quickstart1)
 }
+
+func TestQuickstartModelFilterMetadata(t *testing.T) {
+	t.Parallel()
+
+	goHTTP := &quickstarts.Quickstart{
+		ID:        "jenkins-x-quickstarts/golang-http",
+		Owner:     "jenkins-x-quickstarts",
+		Name:      "golang-http",
+		Language:  "Go",
+		Framework: "http",
+		Tags:      []string{"web", "Example"},
+	}
+	goCli := &quickstarts.Quickstart{
+		ID:       "jenkins-x-quickstarts/golang-cli",
+		Owner:    "jenkins-x-quickstarts",
+		Name:     "golang-cli",
+		Language: "Go",
+		Tags:     []string{"cli"},
+	}
+	node := &quickstarts.Quickstart{
+		ID:        "myorg/node-http",
+		Owner:     "myorg",
+		Name:      "node-http",
+		Language:  "JavaScript",
+		Framework: "express",
+		Tags:      []string{"web"},
+	}
+	model := quickstarts.NewQuickstartModel()
+	model.Add(goHTTP)
+	model.Add(goCli)
+	model.Add(node)
+
+	assert.Equal(t, []*quickstarts.Quickstart{goCli, goHTTP}, model.SortedQuickstarts(&quickstarts.QuickstartFilter{Language: "go"}))
+	assert.Equal(t, []*quickstarts.Quickstart{goHTTP}, model.SortedQuickstarts(&quickstarts.QuickstartFilter{Framework: "HTTP"}))
+	assert.Equal(t, []*quickstarts.Quickstart{goHTTP, node}, model.SortedQuickstarts(&quickstarts.QuickstartFilter{Tags: []string{"web"}}))
+	assert.Equal(t, []*quickstarts.Quickstart{goHTTP}, model.SortedQuickstarts(&quickstarts.QuickstartFilter{Tags: []string{"web", "example"}}))
+	assert.Equal(t, []*quickstarts.Quickstart{node}, model.SortedQuickstarts(&quickstarts.QuickstartFilter{Owner: "myorg"}))
+	assert.Equal(t, []string{"express", "http"}, model.Frameworks())
+
+	assert.Equal(t, "golang-http (go, http)", goHTTP.SurveyOption())
+	assert.Equal(t, "myorg/node-http (javascript, express)", node.SurveyOption())
+	assert.Equal(t, "golang-cli (go)", goCli.SurveyOption())
+}
+
+func TestQuickstartModelAddReplacesQuickstartOfSameName(t *testing.T) {
+	t.Parallel()
+
+	team := &quickstarts.Quickstart{
+		ID:   "jenkins-x-quickstarts/node-http",
+		Name: "node-http",
+	}
+	other := &quickstarts.Quickstart{
+		ID:   "jenkins-x-quickstarts/ruby",
+		Name: "ruby",
+	}
+	mine := &quickstarts.Quickstart{
+		ID:   "myorg/node-http",
+		Name: "node-http",
+	}
+	model := quickstarts.NewQuickstartModel()
+	assert.True(t, model.Add(team))
+	assert.True(t, model.Add(other))
+	assert.True(t, model.Add(mine))
+
+	assert.Equal(t, map[string]*quickstarts.Quickstart{
+		"jenkins-x-quickstarts/ruby": other,
+		"myorg/node-http":            mine,
+	}, model.Quickstarts)
+}
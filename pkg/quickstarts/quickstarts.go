@@ -1,7 +1,10 @@
 package quickstarts
 
 import (
+	"fmt"
 	"strings"
+
+	"github.com/jenkins-x/jx/pkg/util"
 )
 
 func (q *Quickstart) SurveyName() string {
@@ -32,5 +35,25 @@ func (f *QuickstartFilter) Matches(q *Quickstart) bool {
 	if framework != "" && strings.ToLower(q.Framework) != framework {
 		return false
 	}
+	for _, tag := range f.Tags {
+		if tag != "" && util.StringArrayIndex(util.StringArrayToLower(q.Tags), strings.ToLower(tag)) < 0 {
+			return false
+		}
+	}
 	return true
 }
+
+// SurveyOption returns the text of the quickstart in the survey including its language and framework so that typing
+// to filter the survey also matches them
+func (q *Quickstart) SurveyOption() string {
+	details := []string{}
+	for _, text := range []string{q.Language, q.Framework} {
+		if text != "" {
+			details = append(details, strings.ToLower(text))
+		}
+	}
+	if len(details) == 0 {
+		return q.SurveyName()
+	}
+	return fmt.Sprintf("%s (%s)", q.SurveyName(), strings.Join(details, ", "))
+}
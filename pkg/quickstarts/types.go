@@ -10,7 +10,8 @@ type Quickstart struct {
 	Framework      string
 	Tags           []string
 	DownloadZipURL string
-	GitProvider    gits.GitProvider
+	GitURL         string
+	GitProvider    gits.GitProvider `json:"-" yaml:"-"`
 }
 
 type QuickstartModel struct {
@@ -26,12 +26,21 @@ const (
 	OptionDependency     = "dep"
 	OptionDependencyKind = "kind"
 	OptionType           = "type"
+	OptionBuild          = "build"
+
+	// BuildMaven builds the generated project with maven
+	BuildMaven = "maven"
+	// BuildGradle builds the generated project with gradle
+	BuildGradle = "gradle"
 
 	startSpringURL = "http://start.spring.io"
 )
 
 var (
 	DefaultDependencyKinds = []string{"Core", "Web", "Template Engines", "SQL", "I/O", "Ops", "Spring Cloud GCP", "Azure", "Cloud Contract", "Cloud AWS", "Cloud Messaging", "Cloud Tracing"}
+
+	// BuildTools the build tools which can be used to build the generated project
+	BuildTools = []string{BuildMaven, BuildGradle}
 )
 
 type SpringValue struct {
@@ -90,6 +99,7 @@ type SpringBootForm struct {
 	Dependencies    []string
 	DependencyKinds []string
 	Type            string
+	BuildTool       string
 }
 
 func LoadSpringBoot(cacheDir string) (*SpringBootModel, error) {
@@ -125,17 +135,17 @@ func LoadSpringBoot(cacheDir string) (*SpringBootModel, error) {
 	}
 	// default the build tool
 	if model.Type.Default == "" {
-		model.Type.Default = "maven"
+		model.Type.Default = BuildMaven + "-project"
 	}
 	if len(model.Type.Values) == 0 {
 		model.Type.Values = []SpringOption{
 			{
-				ID:          "gradle",
+				ID:          BuildGradle + "-project",
 				Name:        "Gradle",
 				Description: "Build with the gradle build tool",
 			},
 			{
-				ID:          "maven",
+				ID:          BuildMaven + "-project",
 				Name:        "Maven",
 				Description: "Build with the maven build tool",
 			},
@@ -161,13 +171,22 @@ func (model *SpringBootModel) CreateSurvey(data *SpringBootForm, advanced bool,
 	if err != nil {
 		return err
 	}
-	err = model.ValidateTreeInput(OptionDependency, &model.Dependencies, data.Dependencies)
+	err = model.ValidateBuildTool(data)
+	if err != nil {
+		return err
+	}
+	err = model.ValidateInput(OptionType, &model.Type, data.Type)
+	if err != nil {
+		return err
+	}
+	err = model.ValidateDependencies(data.Dependencies)
 	if err != nil {
 		return err
 	}
 
 	var qs = []*survey.Question{}
 	if batchMode {
+		model.DefaultValues(data)
 		return nil
 	}
 	if data.Language == "" {
@@ -257,6 +276,75 @@ func (model *SpringBootModel) ValidateTreeInput(name string, options *SpringTree
 	return nil
 }
 
+// ValidateDependencies returns an error for the first dependency which is not in the metadata listing the
+// dependencies with a similar id or name
+func (model *SpringBootModel) ValidateDependencies(values []string) error {
+	ids := model.Dependencies.StringArray()
+	for _, value := range values {
+		if value != "" && util.StringArrayIndex(ids, value) < 0 {
+			similar := model.Dependencies.SimilarIDs(value)
+			if len(similar) == 0 {
+				return fmt.Errorf("unknown dependency %s, see %s for the available dependencies", value, startSpringURL)
+			}
+			return fmt.Errorf("unknown dependency %s, similar: %s", value, strings.Join(similar, ", "))
+		}
+	}
+	return nil
+}
+
+// SimilarIDs returns the sorted ids of the options whose id or name contains the value or whose id is close to it
+func (options *SpringTreeSelect) SimilarIDs(value string) []string {
+	ids := options.StringArray()
+	answer := util.SuggestionsFor(value, ids, util.DefaultSuggestionsMinimumDistance)
+	lower := strings.ToLower(value)
+	for _, g := range options.Values {
+		for _, o := range g.Values {
+			if o.ID == "" || util.StringArrayIndex(answer, o.ID) >= 0 {
+				continue
+			}
+			if strings.Contains(o.ID, lower) || strings.Contains(strings.ToLower(o.Name), lower) {
+				answer = append(answer, o.ID)
+			}
+		}
+	}
+	sort.Strings(answer)
+	return answer
+}
+
+// ValidateBuildTool validates the build tool and converts it into the project type of the form, failing if the form
+// already has a project type of a different build tool
+func (model *SpringBootModel) ValidateBuildTool(data *SpringBootForm) error {
+	buildTool := data.BuildTool
+	if buildTool == "" {
+		return nil
+	}
+	if util.StringArrayIndex(BuildTools, buildTool) < 0 {
+		return util.InvalidOption(OptionBuild, buildTool, BuildTools)
+	}
+	if data.Type != "" {
+		if !strings.HasPrefix(data.Type, buildTool+"-") {
+			return fmt.Errorf("the --%s %s does not match the --%s %s", OptionBuild, buildTool, OptionType, data.Type)
+		}
+		return nil
+	}
+	data.Type = buildTool + "-project"
+	return nil
+}
+
+// DefaultValues populates the values of the form which have not been specified from the defaults of the metadata so
+// that no questions need to be asked
+func (model *SpringBootModel) DefaultValues(data *SpringBootForm) {
+	if data.Language == "" {
+		data.Language = model.Language.Default
+	}
+	if data.GroupId == "" {
+		data.GroupId = model.GroupId.Default
+	}
+	if data.ArtifactId == "" {
+		data.ArtifactId = model.ArtifactId.Default
+	}
+}
+
 func CreateValueSelect(message string, name string, options *SpringOptions, data *SpringBootForm) *survey.Question {
 	values := options.StringArray()
 	return &survey.Question{
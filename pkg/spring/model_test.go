@@ -0,0 +1,100 @@
+package spring_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/spring"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSpringBootModel() *spring.SpringBootModel {
+	return &spring.SpringBootModel{
+		Language: spring.SpringOptions{
+			Default: "java",
+			Values:  []spring.SpringOption{{ID: "java"}, {ID: "kotlin"}},
+		},
+		JavaVersion: spring.SpringOptions{
+			Default: "1.8",
+			Values:  []spring.SpringOption{{ID: "1.8"}, {ID: "11"}},
+		},
+		Type: spring.SpringOptions{
+			Default: "maven-project",
+			Values:  []spring.SpringOption{{ID: "maven-project"}, {ID: "gradle-project"}},
+		},
+		GroupId:    spring.SpringValue{Default: "com.example"},
+		ArtifactId: spring.SpringValue{Default: "demo"},
+		Dependencies: spring.SpringTreeSelect{
+			Values: []spring.SpringTreeGroup{
+				{
+					Name: "Web",
+					Values: []spring.SpringOption{
+						{ID: "web", Name: "Web"},
+						{ID: "webflux", Name: "Reactive Web"},
+					},
+				},
+				{
+					Name: "SQL",
+					Values: []spring.SpringOption{
+						{ID: "data-jpa", Name: "JPA"},
+						{ID: "jdbc", Name: "JDBC"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestValidateDependencies(t *testing.T) {
+	t.Parallel()
+	model := testSpringBootModel()
+
+	assert.NoError(t, model.ValidateDependencies([]string{"web", "data-jpa"}))
+
+	err := model.ValidateDependencies([]string{"web", "jpa"})
+	require.Error(t, err)
+	assert.Equal(t, "unknown dependency jpa, similar: data-jpa", err.Error())
+
+	err = model.ValidateDependencies([]string{"wbe"})
+	require.Error(t, err)
+	assert.Equal(t, "unknown dependency wbe, similar: web", err.Error())
+
+	err = model.ValidateDependencies([]string{"kafka"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown dependency kafka, see")
+}
+
+func TestValidateBuildTool(t *testing.T) {
+	t.Parallel()
+	model := testSpringBootModel()
+
+	form := &spring.SpringBootForm{BuildTool: "gradle"}
+	require.NoError(t, model.ValidateBuildTool(form))
+	assert.Equal(t, "gradle-project", form.Type)
+
+	form = &spring.SpringBootForm{BuildTool: "gradle", Type: "gradle-build"}
+	require.NoError(t, model.ValidateBuildTool(form))
+	assert.Equal(t, "gradle-build", form.Type)
+
+	assert.Error(t, model.ValidateBuildTool(&spring.SpringBootForm{BuildTool: "ant"}))
+	assert.Error(t, model.ValidateBuildTool(&spring.SpringBootForm{BuildTool: "maven", Type: "gradle-project"}))
+}
+
+func TestCreateSurveyBatchMode(t *testing.T) {
+	t.Parallel()
+	model := testSpringBootModel()
+
+	form := &spring.SpringBootForm{
+		JavaVersion:  "11",
+		BuildTool:    "gradle",
+		Dependencies: []string{"webflux"},
+	}
+	require.NoError(t, model.CreateSurvey(form, true, true))
+	assert.Equal(t, "java", form.Language)
+	assert.Equal(t, "com.example", form.GroupId)
+	assert.Equal(t, "demo", form.ArtifactId)
+	assert.Equal(t, "gradle-project", form.Type)
+
+	assert.Error(t, model.CreateSurvey(&spring.SpringBootForm{JavaVersion: "7"}, false, true))
+	assert.Error(t, model.CreateSurvey(&spring.SpringBootForm{Dependencies: []string{"jpa"}}, false, true))
+}
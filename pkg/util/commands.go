@@ -22,6 +22,7 @@ type Command struct {
 	Timeout            time.Duration
 	Out                io.Writer
 	Err                io.Writer
+	In                 io.Reader
 	Env                map[string]string
 }
 
@@ -154,6 +155,10 @@ func (c *Command) run() (string, error) {
 		e.Stderr = c.Err
 	}
 
+	if c.In != nil {
+		e.Stdin = c.In
+	}
+
 	var text string
 	var err error
 
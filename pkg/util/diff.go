@@ -0,0 +1,36 @@
+package util
+
+import (
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// UnifiedDiff returns the unified diff between the from and to text or an empty string if they are the same
+func UnifiedDiff(from string, to string, fromName string, toName string) (string, error) {
+	diff := difflib.UnifiedDiff{
+		A:        difflib.SplitLines(from),
+		B:        difflib.SplitLines(to),
+		FromFile: fromName,
+		ToFile:   toName,
+		Context:  3,
+	}
+	return difflib.GetUnifiedDiffString(diff)
+}
+
+// ColorDiff colors the added lines of a unified diff as info, the removed lines as errors and the hunk headers as
+// status
+func ColorDiff(diff string) string {
+	lines := strings.Split(diff, "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+++") || strings.HasPrefix(line, "---") || strings.HasPrefix(line, "@@"):
+			lines[i] = ColorStatus(line)
+		case strings.HasPrefix(line, "+"):
+			lines[i] = ColorInfo(line)
+		case strings.HasPrefix(line, "-"):
+			lines[i] = ColorError(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,22 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	t.Parallel()
+	from := "nexus:\n  persistence:\n    size: 8Gi\n"
+	to := "nexus:\n  persistence:\n    size: 2Gi\n"
+
+	diff, err := util.UnifiedDiff(from, to, "current", "target")
+	assert.NoError(t, err)
+	assert.Equal(t, "--- current\n+++ target\n@@ -1,4 +1,4 @@\n nexus:\n   persistence:\n-    size: 8Gi\n+    size: 2Gi\n \n", diff)
+
+	diff, err = util.UnifiedDiff(from, from, "current", "target")
+	assert.NoError(t, err)
+	assert.Equal(t, "", diff)
+}
@@ -57,6 +57,20 @@ func CacheDir() (string, error) {
 	return path, nil
 }
 
+// QuickstartsDir returns the directory caching the catalogs of quickstarts
+func QuickstartsDir() (string, error) {
+	h, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(h, "quickstarts")
+	err = os.MkdirAll(path, DefaultWritePermissions)
+	if err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
 func EnvironmentsDir() (string, error) {
 	h, err := ConfigDir()
 	if err != nil {
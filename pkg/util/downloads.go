@@ -6,7 +6,6 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"path"
 	"path/filepath"
@@ -29,7 +28,11 @@ func DownloadFile(filepath string, url string) (err error) {
 	defer out.Close()
 
 	// Get the data
-	resp, err := http.Get(url)
+	client, err := DownloadClient()
+	if err != nil {
+		return err
+	}
+	resp, err := client.Get(url)
 	if err != nil {
 		return err
 	}
@@ -63,12 +66,15 @@ func GetLatestVersionFromGitHub(githubOwner, githubRepo string) (semver.Version,
 func GetLatestVersionStringFromGitHub(githubOwner, githubRepo string) (string, error) {
 	if githubClient == nil {
 		token := os.Getenv("GH_TOKEN")
-		var tc *http.Client
+		tc, err := DownloadClient()
+		if err != nil {
+			return "", err
+		}
 		if len(token) > 0 {
 			ts := oauth2.StaticTokenSource(
 				&oauth2.Token{AccessToken: token},
 			)
-			tc = oauth2.NewClient(oauth2.NoContext, ts)
+			tc = oauth2.NewClient(context.WithValue(oauth2.NoContext, oauth2.HTTPClient, tc), ts)
 		}
 		githubClient = github.NewClient(tc)
 	}
@@ -0,0 +1,95 @@
+package util
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	// DownloadCAFileEnvVar the environment variable of the PEM bundle trusted when downloading dependencies
+	DownloadCAFileEnvVar = "JX_DOWNLOAD_CA_FILE"
+)
+
+var (
+	// proxyEnvVars the proxy environment variables passed explicitly to child processes
+	proxyEnvVars = []string{"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY"}
+
+	downloadCAFile     string
+	downloadClient     *http.Client
+	downloadClientLock sync.Mutex
+)
+
+// SetDownloadCAFile sets the PEM bundle trusted in addition to the system roots when downloading dependencies.
+// If it is empty the bundle in the JX_DOWNLOAD_CA_FILE environment variable is used
+func SetDownloadCAFile(fileName string) {
+	downloadClientLock.Lock()
+	defer downloadClientLock.Unlock()
+	downloadCAFile = fileName
+	downloadClient = nil
+}
+
+// DownloadClient returns the HTTP client used to download dependencies and find their latest versions. It uses the
+// proxy configured by HTTP_PROXY, HTTPS_PROXY and NO_PROXY and trusts the certificates of the download CA file
+func DownloadClient() (*http.Client, error) {
+	downloadClientLock.Lock()
+	defer downloadClientLock.Unlock()
+	if downloadClient != nil {
+		return downloadClient, nil
+	}
+	caFile := downloadCAFile
+	if caFile == "" {
+		caFile = os.Getenv(DownloadCAFileEnvVar)
+	}
+	transport, err := NewDownloadTransport(caFile)
+	if err != nil {
+		return nil, err
+	}
+	downloadClient = &http.Client{Transport: transport}
+	return downloadClient, nil
+}
+
+// NewDownloadTransport creates a transport which uses the proxy from the environment and trusts the certificates of
+// the given PEM bundle as well as the system roots
+func NewDownloadTransport(caFile string) (*http.Transport, error) {
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+	}
+	if caFile == "" {
+		return transport, nil
+	}
+	data, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read the download CA file %s due to %s", caFile, err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM certificates found in the download CA file %s", caFile)
+	}
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	return transport, nil
+}
+
+// ProxyEnv returns the proxy environment variables set in either upper or lower case, in both cases, so that they
+// can be passed explicitly to child processes
+func ProxyEnv() []string {
+	answer := []string{}
+	for _, name := range proxyEnvVars {
+		value := os.Getenv(name)
+		if value == "" {
+			value = os.Getenv(strings.ToLower(name))
+		}
+		if value != "" {
+			answer = append(answer, name+"="+value, strings.ToLower(name)+"="+value)
+		}
+	}
+	return answer
+}
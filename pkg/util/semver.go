@@ -0,0 +1,69 @@
+package util
+
+import (
+	"fmt"
+
+	"github.com/blang/semver"
+)
+
+const (
+	// SemVerMajor bumps the major version
+	SemVerMajor = "major"
+	// SemVerMinor bumps the minor version
+	SemVerMinor = "minor"
+	// SemVerPatch bumps the patch version
+	SemVerPatch = "patch"
+	// SemVerAuto bumps the part of the version derived from the conventional commit messages since the last release,
+	// see gits.ConventionalCommitsBump
+	SemVerAuto = "auto"
+)
+
+// SemVerBumps the parts of a semantic version which can be bumped
+var SemVerBumps = []string{SemVerMajor, SemVerMinor, SemVerPatch, SemVerAuto}
+
+// NextSemVer returns the version which follows the latest version by bumping its major, minor or patch version.
+// A pre-release of the latest version is released if it is already bumped enough, e.g. 1.3.0-rc.2 becomes 1.3.0 for a
+// minor bump. With a pre-release identifier such as rc the next version is its first pre-release, e.g. 1.3.0-rc.1,
+// unless the latest version is already one of its pre-releases in which case the pre-release number is incremented
+func NextSemVer(latest semver.Version, bump string, preRelease string) (semver.Version, error) {
+	release := semver.Version{Major: latest.Major, Minor: latest.Minor, Patch: latest.Patch}
+	next := release
+	if len(latest.Pre) == 0 || !bumpedEnough(release, bump) {
+		switch bump {
+		case SemVerMajor:
+			next = semver.Version{Major: release.Major + 1}
+		case SemVerMinor:
+			next = semver.Version{Major: release.Major, Minor: release.Minor + 1}
+		case SemVerPatch:
+			next = semver.Version{Major: release.Major, Minor: release.Minor, Patch: release.Patch + 1}
+		default:
+			return next, InvalidOption("semver", bump, []string{SemVerMajor, SemVerMinor, SemVerPatch})
+		}
+	}
+	if preRelease == "" {
+		return next, nil
+	}
+	number := uint64(1)
+	if next.Equals(release) && len(latest.Pre) > 0 && latest.Pre[0].VersionStr == preRelease {
+		if len(latest.Pre) > 1 && latest.Pre[1].IsNum {
+			number = latest.Pre[1].VersionNum + 1
+		}
+	}
+	id, err := semver.NewPRVersion(preRelease)
+	if err != nil {
+		return next, fmt.Errorf("invalid pre-release identifier %s: %s", preRelease, err)
+	}
+	next.Pre = []semver.PRVersion{id, {VersionNum: number, IsNum: true}}
+	return next, nil
+}
+
+// bumpedEnough returns true if the release of a pre-release version already bumps the given part of the version
+func bumpedEnough(release semver.Version, bump string) bool {
+	switch bump {
+	case SemVerMajor:
+		return release.Minor == 0 && release.Patch == 0
+	case SemVerMinor:
+		return release.Patch == 0
+	}
+	return true
+}
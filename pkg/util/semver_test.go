@@ -0,0 +1,43 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextSemVer(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		latest     string
+		bump       string
+		preRelease string
+		expected   string
+	}{
+		{"1.2.3", util.SemVerPatch, "", "1.2.4"},
+		{"1.2.3", util.SemVerMinor, "", "1.3.0"},
+		{"1.2.3", util.SemVerMajor, "", "2.0.0"},
+		{"1.2.3+build.7", util.SemVerPatch, "", "1.2.4"},
+		{"1.2.3", util.SemVerMinor, "rc", "1.3.0-rc.1"},
+		{"1.3.0-rc.1", util.SemVerMinor, "rc", "1.3.0-rc.2"},
+		{"1.3.0-rc.2", util.SemVerPatch, "rc", "1.3.0-rc.3"},
+		{"1.3.0-beta.4", util.SemVerMinor, "rc", "1.3.0-rc.1"},
+		{"1.3.0-rc.2", util.SemVerMinor, "", "1.3.0"},
+		{"1.3.0-rc.2", util.SemVerMajor, "", "2.0.0"},
+		{"1.2.4-rc.1", util.SemVerMinor, "rc", "1.3.0-rc.1"},
+		{"2.0.0-rc", util.SemVerMajor, "rc", "2.0.0-rc.1"},
+	}
+	for _, tc := range testCases {
+		latest, err := semver.Parse(tc.latest)
+		require.NoError(t, err)
+		next, err := util.NextSemVer(latest, tc.bump, tc.preRelease)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, next.String(), "next %s %s version of %s", tc.bump, tc.preRelease, tc.latest)
+	}
+
+	_, err := util.NextSemVer(semver.MustParse("1.2.3"), "huge", "")
+	assert.Error(t, err)
+}
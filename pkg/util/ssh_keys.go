@@ -0,0 +1,131 @@
+package util
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// SSHPrivateKeyFileName the file name of generated SSH private keys
+	SSHPrivateKeyFileName = "id_ed25519"
+
+	// SSHPublicKeyFileName the file name of generated SSH public keys
+	SSHPublicKeyFileName = SSHPrivateKeyFileName + ".pub"
+)
+
+// DefaultSSHPublicKey returns the path of the SSH public key used by default by tools like eksctl
+func DefaultSSHPublicKey() string {
+	return filepath.Join(HomeDir(), ".ssh", "id_rsa.pub")
+}
+
+// SSHKeysDir returns the directory used to store the generated SSH keys of the given name
+func SSHKeysDir(name string) (string, error) {
+	h, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(h, "ssh", name)
+	err = os.MkdirAll(path, DefaultWritePermissions)
+	if err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// GenerateSSHKeyPair generates an ed25519 SSH key pair in the directory returning the path of the public key.
+// If the key pair already exists it is reused
+func GenerateSSHKeyPair(dir string, comment string) (string, error) {
+	privateKeyPath := filepath.Join(dir, SSHPrivateKeyFileName)
+	publicKeyPath := filepath.Join(dir, SSHPublicKeyFileName)
+	exists, err := FileExists(publicKeyPath)
+	if err != nil || exists {
+		return publicKeyPath, err
+	}
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", err
+	}
+	sshPublicKey, err := ssh.NewPublicKey(publicKey)
+	if err != nil {
+		return "", err
+	}
+	privateKeyData, err := marshalED25519PrivateKey(publicKey, privateKey, comment)
+	if err != nil {
+		return "", err
+	}
+	err = ioutil.WriteFile(privateKeyPath, privateKeyData, 0600)
+	if err != nil {
+		return "", err
+	}
+	publicKeyData := ssh.MarshalAuthorizedKey(sshPublicKey)
+	if comment != "" {
+		publicKeyData = append(publicKeyData[:len(publicKeyData)-1], []byte(" "+comment+"\n")...)
+	}
+	err = ioutil.WriteFile(publicKeyPath, publicKeyData, 0644)
+	if err != nil {
+		return "", err
+	}
+	return publicKeyPath, nil
+}
+
+// marshalED25519PrivateKey encodes the private key in the unencrypted openssh-key-v1 format used by ssh-keygen
+func marshalED25519PrivateKey(publicKey ed25519.PublicKey, privateKey ed25519.PrivateKey, comment string) ([]byte, error) {
+	checkBytes := make([]byte, 4)
+	_, err := rand.Read(checkBytes)
+	if err != nil {
+		return nil, err
+	}
+	check := binary.BigEndian.Uint32(checkBytes)
+
+	sshPublicKey, err := ssh.NewPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	privateKeyBlock := struct {
+		Check1  uint32
+		Check2  uint32
+		Keytype string
+		Pub     []byte
+		Priv    []byte
+		Comment string
+		Pad     []byte `ssh:"rest"`
+	}{
+		Check1:  check,
+		Check2:  check,
+		Keytype: ssh.KeyAlgoED25519,
+		Pub:     publicKey,
+		Priv:    privateKey,
+		Comment: comment,
+	}
+	// the private key block is padded to the cipher block size of 8
+	blockLength := len(ssh.Marshal(privateKeyBlock))
+	for i := 0; blockLength%8 != 0; i++ {
+		privateKeyBlock.Pad = append(privateKeyBlock.Pad, byte(i+1))
+		blockLength++
+	}
+
+	key := struct {
+		CipherName   string
+		KdfName      string
+		KdfOpts      string
+		NumKeys      uint32
+		PubKey       []byte
+		PrivKeyBlock []byte
+	}{
+		CipherName:   "none",
+		KdfName:      "none",
+		NumKeys:      1,
+		PubKey:       sshPublicKey.Marshal(),
+		PrivKeyBlock: ssh.Marshal(privateKeyBlock),
+	}
+	data := append([]byte("openssh-key-v1\x00"), ssh.Marshal(key)...)
+	return pem.EncodeToMemory(&pem.Block{Type: "OPENSSH PRIVATE KEY", Bytes: data}), nil
+}
@@ -0,0 +1,45 @@
+package util_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestGenerateSSHKeyPair(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "jx-ssh-keys-")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	publicKeyPath, err := util.GenerateSSHKeyPair(dir, "mycluster")
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(dir, util.SSHPublicKeyFileName), publicKeyPath)
+
+	publicKeyData, err := ioutil.ReadFile(publicKeyPath)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasSuffix(string(publicKeyData), " mycluster\n"))
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey(publicKeyData)
+	assert.Nil(t, err)
+	assert.Equal(t, ssh.KeyAlgoED25519, publicKey.Type())
+
+	privateKeyData, err := ioutil.ReadFile(filepath.Join(dir, util.SSHPrivateKeyFileName))
+	assert.Nil(t, err)
+	signer, err := ssh.ParsePrivateKey(privateKeyData)
+	assert.Nil(t, err)
+	assert.Equal(t, publicKey.Marshal(), signer.PublicKey().Marshal())
+
+	// an existing key pair is reused
+	again, err := util.GenerateSSHKeyPair(dir, "mycluster")
+	assert.Nil(t, err)
+	assert.Equal(t, publicKeyPath, again)
+	data, err := ioutil.ReadFile(publicKeyPath)
+	assert.Nil(t, err)
+	assert.Equal(t, publicKeyData, data)
+}
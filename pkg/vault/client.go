@@ -0,0 +1,238 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultAuthPath the default mount path of the Kubernetes auth method
+	DefaultAuthPath = "kubernetes"
+	// DefaultSecretPrefix the default path under which the secrets of a team are stored
+	DefaultSecretPrefix = "secret/jenkins-x"
+
+	// renewBefore how long before the lease of the token expires it is renewed
+	renewBefore = time.Minute
+)
+
+// Client reads and writes the secrets of a team in a Vault using the Kubernetes auth method. The token is renewed
+// before its lease expires and the client logs in again if the Vault rejects it
+type Client struct {
+	URL          string
+	AuthPath     string
+	Role         string
+	SecretPrefix string
+	HTTPClient   *http.Client
+
+	jwt         string
+	lock        sync.Mutex
+	token       string
+	renewable   bool
+	leaseExpiry time.Time
+}
+
+type loginRequest struct {
+	Role string `json:"role"`
+	JWT  string `json:"jwt"`
+}
+
+type authResponse struct {
+	Auth struct {
+		ClientToken   string `json:"client_token"`
+		LeaseDuration int    `json:"lease_duration"`
+		Renewable     bool   `json:"renewable"`
+	} `json:"auth"`
+}
+
+type secretResponse struct {
+	Data map[string]interface{} `json:"data"`
+}
+
+type errorResponse struct {
+	Errors []string `json:"errors"`
+}
+
+// NewClient creates a client of the Vault at the URL which logs in with the JWT of a Kubernetes service account
+func NewClient(url string, authPath string, role string, secretPrefix string, jwt string) *Client {
+	if authPath == "" {
+		authPath = DefaultAuthPath
+	}
+	if secretPrefix == "" {
+		secretPrefix = DefaultSecretPrefix
+	}
+	return &Client{
+		URL:          strings.TrimSuffix(url, "/"),
+		AuthPath:     strings.Trim(authPath, "/"),
+		Role:         role,
+		SecretPrefix: strings.Trim(secretPrefix, "/"),
+		HTTPClient:   http.DefaultClient,
+		jwt:          jwt,
+	}
+}
+
+// Login logs in with the Kubernetes auth method
+func (c *Client) Login() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.login()
+}
+
+func (c *Client) login() error {
+	var auth authResponse
+	body := loginRequest{Role: c.Role, JWT: c.jwt}
+	err := c.do(http.MethodPost, "auth/"+c.AuthPath+"/login", "", body, &auth)
+	if err != nil {
+		return fmt.Errorf("failed to log in to the Vault %s with the role %s: %v", c.URL, c.Role, err)
+	}
+	c.setToken(auth)
+	return nil
+}
+
+func (c *Client) renew() error {
+	var auth authResponse
+	err := c.do(http.MethodPost, "auth/token/renew-self", c.token, nil, &auth)
+	if err != nil {
+		return err
+	}
+	c.setToken(auth)
+	return nil
+}
+
+func (c *Client) setToken(auth authResponse) {
+	c.token = auth.Auth.ClientToken
+	c.renewable = auth.Auth.Renewable
+	c.leaseExpiry = time.Time{}
+	if auth.Auth.LeaseDuration > 0 {
+		c.leaseExpiry = time.Now().Add(time.Duration(auth.Auth.LeaseDuration) * time.Second)
+	}
+}
+
+// ensureToken logs in if there is no token and renews the token when its lease is about to expire, logging in again
+// if it can not be renewed
+func (c *Client) ensureToken() (string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.token == "" {
+		err := c.login()
+		return c.token, err
+	}
+	if c.leaseExpiry.IsZero() || time.Now().Add(renewBefore).Before(c.leaseExpiry) {
+		return c.token, nil
+	}
+	if c.renewable && c.renew() == nil {
+		return c.token, nil
+	}
+	err := c.login()
+	return c.token, err
+}
+
+// Read returns the data of the secret with the key under the secret prefix
+func (c *Client) Read(key string) (map[string]interface{}, error) {
+	var secret secretResponse
+	err := c.doWithToken(http.MethodGet, c.secretPath(key), nil, &secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the secret %s from the Vault %s: %v", c.secretPath(key), c.URL, err)
+	}
+	return secret.Data, nil
+}
+
+// Write saves the data of the secret with the key under the secret prefix
+func (c *Client) Write(key string, data map[string]interface{}) error {
+	err := c.doWithToken(http.MethodPut, c.secretPath(key), data, nil)
+	if err != nil {
+		return fmt.Errorf("failed to write the secret %s to the Vault %s: %v", c.secretPath(key), c.URL, err)
+	}
+	return nil
+}
+
+func (c *Client) secretPath(key string) string {
+	return c.SecretPrefix + "/" + strings.TrimPrefix(key, "/")
+}
+
+// doWithToken invokes the Vault API with the token, logging in again once if the Vault rejects the token
+func (c *Client) doWithToken(method string, path string, body interface{}, result interface{}) error {
+	token, err := c.ensureToken()
+	if err != nil {
+		return err
+	}
+	err = c.do(method, path, token, body, result)
+	if _, forbidden := err.(*forbiddenError); !forbidden {
+		return err
+	}
+	c.lock.Lock()
+	err = c.login()
+	token = c.token
+	c.lock.Unlock()
+	if err != nil {
+		return err
+	}
+	return c.do(method, path, token, body, result)
+}
+
+type forbiddenError struct {
+	message string
+}
+
+func (e *forbiddenError) Error() string {
+	return e.message
+}
+
+func (c *Client) do(method string, path string, token string, body interface{}, result interface{}) error {
+	reader := bytes.NewReader(nil)
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, c.URL+"/v1/"+path, reader)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		message := fmt.Sprintf("status %s", resp.Status)
+		var errors errorResponse
+		if json.Unmarshal(data, &errors) == nil && len(errors.Errors) > 0 {
+			message = fmt.Sprintf("status %s: %s", resp.Status, strings.Join(errors.Errors, ", "))
+		}
+		if resp.StatusCode == http.StatusForbidden {
+			return &forbiddenError{message: message}
+		}
+		return fmt.Errorf("%s", message)
+	}
+	if result == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, result)
+}
+
+// LoginScript returns a shell script which logs in to the Vault with the token of the service account of the pod
+// it runs in, so that a probe pod can check the Kubernetes auth role from inside the cluster
+func LoginScript(url string, authPath string, role string) string {
+	if authPath == "" {
+		authPath = DefaultAuthPath
+	}
+	loginURL := strings.TrimSuffix(url, "/") + "/v1/auth/" + strings.Trim(authPath, "/") + "/login"
+	return fmt.Sprintf(`JWT=$(cat /var/run/secrets/kubernetes.io/serviceaccount/token) && curl -sSf -X POST -d "{\"role\":\"%s\",\"jwt\":\"$JWT\"}" %s > /dev/null`,
+		role, loginURL)
+}
@@ -0,0 +1,127 @@
+package vault_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x/jx/pkg/vault"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVault records the requests of a client and serves the Kubernetes auth login, token renewal and KV endpoints
+type fakeVault struct {
+	logins        int
+	renewals      int
+	leaseDuration int
+	validToken    string
+	secrets       map[string]map[string]interface{}
+}
+
+func (f *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/v1/auth/kubernetes/login":
+		body := map[string]string{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["role"] != "jenkins-x" || body["jwt"] != "my-jwt" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errors":["invalid role"]}`))
+			return
+		}
+		f.logins++
+		f.validToken = "token-" + string(rune('0'+f.logins))
+		f.writeAuth(w)
+	case "/v1/auth/token/renew-self":
+		f.renewals++
+		f.writeAuth(w)
+	default:
+		if r.Header.Get("X-Vault-Token") != f.validToken {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		key := r.URL.Path[len("/v1/"):]
+		if r.Method == http.MethodPut {
+			data := map[string]interface{}{}
+			json.NewDecoder(r.Body).Decode(&data)
+			f.secrets[key] = data
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": f.secrets[key]})
+	}
+}
+
+func (f *fakeVault) writeAuth(w http.ResponseWriter) {
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"auth": map[string]interface{}{
+			"client_token":   f.validToken,
+			"lease_duration": f.leaseDuration,
+			"renewable":      true,
+		},
+	})
+}
+
+func TestClientWritesAndReadsSecretsUnderThePrefix(t *testing.T) {
+	t.Parallel()
+	fake := &fakeVault{leaseDuration: 3600, secrets: map[string]map[string]interface{}{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client := vault.NewClient(server.URL, "", "jenkins-x", "secret/teams/jx/", "my-jwt")
+	err := client.Write("adminSecrets", map[string]interface{}{"password": "secret"})
+	require.NoError(t, err)
+
+	assert.Contains(t, fake.secrets, "secret/teams/jx/adminSecrets")
+	data, err := client.Read("adminSecrets")
+	require.NoError(t, err)
+	assert.Equal(t, "secret", data["password"])
+	assert.Equal(t, 1, fake.logins)
+	assert.Equal(t, 0, fake.renewals)
+}
+
+func TestClientRenewsTokenBeforeTheLeaseExpires(t *testing.T) {
+	t.Parallel()
+	fake := &fakeVault{leaseDuration: 10, secrets: map[string]map[string]interface{}{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client := vault.NewClient(server.URL, "kubernetes", "jenkins-x", "", "my-jwt")
+	err := client.Write("a", map[string]interface{}{"x": "1"})
+	require.NoError(t, err)
+	_, err = client.Read("a")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, fake.logins)
+	assert.Equal(t, 1, fake.renewals)
+}
+
+func TestClientLogsInAgainWhenTheTokenIsRejected(t *testing.T) {
+	t.Parallel()
+	fake := &fakeVault{leaseDuration: 3600, secrets: map[string]map[string]interface{}{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client := vault.NewClient(server.URL, "", "jenkins-x", "", "my-jwt")
+	err := client.Login()
+	require.NoError(t, err)
+	fake.validToken = "revoked"
+
+	err = client.Write("a", map[string]interface{}{"x": "1"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, fake.logins)
+}
+
+func TestClientLoginFailsWithTheWrongRole(t *testing.T) {
+	t.Parallel()
+	fake := &fakeVault{secrets: map[string]map[string]interface{}{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client := vault.NewClient(server.URL, "", "other", "", "my-jwt")
+	err := client.Login()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid role")
+}